@@ -167,6 +167,12 @@ const (
 
 	MgoStatsEnabled = "MGO_STATS_ENABLED"
 
+	// APISRVName holds the DNS name under which the controller
+	// publishes SRV records for its API servers. When it is set,
+	// the agent looks the records up whenever it connects, in
+	// addition to trying the API addresses it has recorded.
+	APISRVName = "API_SRV_NAME"
+
 	// LoggingOverride will set the logging for this agent to the value
 	// specified. Model configuration will be ignored and this value takes
 	// precidence for the agent.
@@ -176,6 +182,12 @@ const (
 	LogSinkDBLoggerFlushInterval = "LOGSINK_DBLOGGER_FLUSH_INTERVAL"
 	LogSinkRateLimitBurst        = "LOGSINK_RATELIMIT_BURST"
 	LogSinkRateLimitRefill       = "LOGSINK_RATELIMIT_REFILL"
+
+	// RelationCacheMaxEntries and RelationCacheTTL override the
+	// bounds of a unit agent's relation settings caches; zero leaves
+	// them unbounded.
+	RelationCacheMaxEntries = "RELATION_CACHE_MAX_ENTRIES"
+	RelationCacheTTL        = "RELATION_CACHE_TTL"
 )
 
 // The Config interface is the sole way that the agent gets access to the
@@ -792,6 +804,7 @@ func (c *configInternal) APIInfo() (*api.Info, bool) {
 	}
 	return &api.Info{
 		Addrs:    addrs,
+		SRVName:  c.values[APISRVName],
 		Password: c.apiDetails.password,
 		CACert:   c.caCert,
 		Tag:      c.tag,
@@ -505,6 +505,16 @@ func (*suite) TestAPIInfoDoesntAddLocalhostWhenNoServingInfo(c *gc.C) {
 	c.Assert(apiinfo.Addrs, gc.DeepEquals, attrParams.APIAddresses)
 }
 
+func (*suite) TestAPIInfoIncludesSRVName(c *gc.C) {
+	attrParams := attributeParams
+	attrParams.Values = map[string]string{agent.APISRVName: "juju.example.com"}
+	conf, err := agent.NewAgentConfig(attrParams)
+	c.Assert(err, jc.ErrorIsNil)
+	apiinfo, ok := conf.APIInfo()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(apiinfo.SRVName, gc.Equals, "juju.example.com")
+}
+
 func (*suite) TestSetPassword(c *gc.C) {
 	attrParams := attributeParams
 	servingInfo := stateServingInfo()
@@ -0,0 +1,55 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/utils/bindiff"
+)
+
+// DeltaContentType is the content type with which a tools delta is
+// served in place of a tools tarball.
+const DeltaContentType = "application/x-juju-tools-delta"
+
+// WriteToolsDelta writes to w a delta that makes the gzipped tar
+// archive target from the archive base, which holds baseSize bytes.
+//
+// The delta makes the target archive itself, rather than the files in
+// it, so that an agent applying it can check the archive it makes
+// against the hash of the tools it asked for.
+func WriteToolsDelta(w io.Writer, base io.ReaderAt, baseSize int64, target io.Reader) error {
+	return errors.Trace(bindiff.Diff(w, base, baseSize, target))
+}
+
+// UnpackToolsDelta reads a tools delta, as written by WriteToolsDelta,
+// and applies it to the archive kept with the tools with version base
+// in dataDir. The archive made is unpacked by UnpackTools, which
+// checks it against the given tools' hash.
+func UnpackToolsDelta(dataDir string, base version.Binary, tools *coretools.Tools, r io.Reader) error {
+	baseArchive, err := os.Open(SharedToolsArchive(dataDir, base))
+	if err != nil {
+		return errors.Annotatef(err, "opening base tools %v", base)
+	}
+	defer baseArchive.Close()
+	f, err := ioutil.TempFile(os.TempDir(), "tools-delta")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if err := bindiff.Patch(f, baseArchive, r); err != nil {
+		return errors.Annotate(err, "applying tools delta")
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(UnpackTools(dataDir, tools, f))
+}
@@ -0,0 +1,120 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tools_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	agenttools "github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/testing"
+	coretest "github.com/juju/juju/tools"
+)
+
+// randomString returns n random bytes, which compress no better in
+// a tools archive than a real jujud binary does.
+func randomString(seed int64, n int) string {
+	data := make([]byte, n)
+	rand.New(rand.NewSource(seed)).Read(data)
+	return string(data)
+}
+
+var (
+	deltaJujud     = randomString(1, 64*1024)
+	deltaBaseFiles = []*testing.TarFile{
+		testing.NewTarFile("jujud", agenttools.DirPerm, deltaJujud),
+		testing.NewTarFile("old", agenttools.DirPerm, "old contents"),
+	}
+	deltaTargetFiles = []*testing.TarFile{
+		testing.NewTarFile("jujud", agenttools.DirPerm, deltaJujud+"and 1.2.4"),
+		testing.NewTarFile("new", agenttools.DirPerm, "new contents"),
+	}
+)
+
+// unpackDeltaBase unpacks the base tools for a delta into the data
+// directory, and returns the base tarball, and the target tarball and
+// its checksum.
+func (t *ToolsSuite) unpackDeltaBase(c *gc.C) (base, target []byte, targetSHA256 string) {
+	base, checksum := testing.TarGz(deltaBaseFiles...)
+	err := agenttools.UnpackTools(t.dataDir, &coretest.Tools{
+		Version: version.MustParseBinary("1.2.3-quantal-amd64"),
+		Size:    int64(len(base)),
+		SHA256:  checksum,
+	}, bytes.NewReader(base))
+	c.Assert(err, jc.ErrorIsNil)
+	target, targetSHA256 = testing.TarGz(deltaTargetFiles...)
+	return base, target, targetSHA256
+}
+
+func writeToolsDelta(c *gc.C, base, target []byte) *bytes.Buffer {
+	var delta bytes.Buffer
+	err := agenttools.WriteToolsDelta(&delta, bytes.NewReader(base), int64(len(base)), bytes.NewReader(target))
+	c.Assert(err, jc.ErrorIsNil)
+	return &delta
+}
+
+func (t *ToolsSuite) TestUnpackToolsDelta(c *gc.C) {
+	base, target, targetSHA256 := t.unpackDeltaBase(c)
+	delta := writeToolsDelta(c, base, target)
+	c.Assert(delta.Len() < len(target), jc.IsTrue)
+
+	testTools := &coretest.Tools{
+		URL:     "http://foo/bar",
+		Version: version.MustParseBinary("1.2.4-quantal-amd64"),
+		Size:    int64(len(target)),
+		SHA256:  targetSHA256,
+	}
+	err := agenttools.UnpackToolsDelta(t.dataDir, version.MustParseBinary("1.2.3-quantal-amd64"), testTools, delta)
+	c.Assert(err, jc.ErrorIsNil)
+	assertDirNames(c, t.toolsDir(), []string{"1.2.3-quantal-amd64", "1.2.4-quantal-amd64"})
+	t.assertToolsContents(c, testTools, deltaTargetFiles)
+	archive, err := ioutil.ReadFile(agenttools.SharedToolsArchive(t.dataDir, testTools.Version))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bytes.Equal(archive, target), jc.IsTrue)
+}
+
+func (t *ToolsSuite) TestUnpackToolsDeltaBadChecksum(c *gc.C) {
+	// The archive made from a delta is checked against the tools'
+	// hash, not just the one recorded in the delta.
+	base, target, _ := t.unpackDeltaBase(c)
+	delta := writeToolsDelta(c, base, target)
+
+	testTools := &coretest.Tools{
+		Version: version.MustParseBinary("1.2.4-quantal-amd64"),
+		Size:    int64(len(target)),
+		SHA256:  "1234",
+	}
+	err := agenttools.UnpackToolsDelta(t.dataDir, version.MustParseBinary("1.2.3-quantal-amd64"), testTools, delta)
+	c.Assert(err, gc.ErrorMatches, "tarball sha256 mismatch, expected 1234, got .*")
+	assertDirNames(c, t.toolsDir(), []string{"1.2.3-quantal-amd64"})
+}
+
+func (t *ToolsSuite) TestUnpackToolsDeltaBaseChanged(c *gc.C) {
+	base, target, targetSHA256 := t.unpackDeltaBase(c)
+	delta := writeToolsDelta(c, base, target)
+
+	otherBase, _ := testing.TarGz(testing.NewTarFile("jujud", agenttools.DirPerm, randomString(2, 64*1024)))
+	archive := agenttools.SharedToolsArchive(t.dataDir, version.MustParseBinary("1.2.3-quantal-amd64"))
+	err := ioutil.WriteFile(archive, otherBase, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	testTools := &coretest.Tools{
+		Version: version.MustParseBinary("1.2.4-quantal-amd64"),
+		SHA256:  targetSHA256,
+	}
+	err = agenttools.UnpackToolsDelta(t.dataDir, version.MustParseBinary("1.2.3-quantal-amd64"), testTools, delta)
+	c.Assert(err, gc.ErrorMatches, "applying tools delta: .*")
+	assertDirNames(c, t.toolsDir(), []string{"1.2.3-quantal-amd64"})
+}
+
+func (t *ToolsSuite) TestUnpackToolsDeltaNoBase(c *gc.C) {
+	testTools := &coretest.Tools{Version: version.MustParseBinary("1.2.4-quantal-amd64")}
+	err := agenttools.UnpackToolsDelta(t.dataDir, version.MustParseBinary("1.2.3-quantal-amd64"), testTools, &bytes.Buffer{})
+	c.Assert(err, gc.ErrorMatches, "opening base tools 1.2.3-quantal-amd64: .*")
+}
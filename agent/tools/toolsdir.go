@@ -23,9 +23,10 @@ import (
 )
 
 const (
-	dirPerm        = 0755
-	guiArchiveFile = "downloaded-gui.txt"
-	toolsFile      = "downloaded-tools.txt"
+	dirPerm          = 0755
+	guiArchiveFile   = "downloaded-gui.txt"
+	toolsFile        = "downloaded-tools.txt"
+	toolsArchiveFile = "tools.tar.gz"
 )
 
 // SharedToolsDir returns the directory that is used to
@@ -35,6 +36,14 @@ func SharedToolsDir(dataDir string, vers version.Binary) string {
 	return path.Join(dataDir, "tools", vers.String())
 }
 
+// SharedToolsArchive returns the path of the gzipped tar archive
+// kept with the given version of the juju tools within the dataDir
+// directory, from which later versions may be made by applying a
+// delta. Tools unpacked before archives were kept have none.
+func SharedToolsArchive(dataDir string, vers version.Binary) string {
+	return path.Join(SharedToolsDir(dataDir, vers), toolsArchiveFile)
+}
+
 // SharedGUIDir returns the directory that is used to store release archives
 // of the Juju GUI within the dataDir directory.
 func SharedGUIDir(dataDir string) string {
@@ -54,34 +63,85 @@ func ToolsDir(dataDir, agentName string) string {
 
 // UnpackTools reads a set of juju tools in gzipped tar-archive
 // format and unpacks them into the appropriate tools directory
-// within dataDir. If a valid tools directory already exists,
-// UnpackTools returns without error.
+// within dataDir. The archive is kept in the tools directory too.
+// If a valid tools directory already exists, UnpackTools returns
+// without error.
 func UnpackTools(dataDir string, tools *coretools.Tools, r io.Reader) (err error) {
-	// Unpack the gzip file and compute the checksum.
-	sha256hash := sha256.New()
-	zr, err := gzip.NewReader(io.TeeReader(r, sha256hash))
-	if err != nil {
-		return err
-	}
-	defer zr.Close()
+	// Save the archive and compute the checksum.
 	f, err := ioutil.TempFile(os.TempDir(), "tools-tar")
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(f, zr)
+	defer os.Remove(f.Name())
+	defer f.Close()
+	sha256hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, sha256hash), r)
 	if err != nil {
 		return err
 	}
-	defer os.Remove(f.Name())
 	gzipSHA256 := fmt.Sprintf("%x", sha256hash.Sum(nil))
 	if tools.SHA256 != gzipSHA256 {
 		return fmt.Errorf("tarball sha256 mismatch, expected %s, got %s", tools.SHA256, gzipSHA256)
 	}
 
+	// Checksum matches, now reset the file and untar it.
+	_, err = f.Seek(0, 0)
+	if err != nil {
+		return err
+	}
+	return installTools(dataDir, tools, func(dir string) error {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		tr := tar.NewReader(zr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if err := checkArchiveHeader(hdr); err != nil {
+				return err
+			}
+			name := path.Join(dir, hdr.Name)
+			if err := writeFile(name, os.FileMode(hdr.Mode&0777), tr); err != nil {
+				return errors.Annotatef(err, "tar extract %q failed", name)
+			}
+		}
+		// Keep the archive, so that the next upgrade can be
+		// fetched as a delta from it.
+		if _, err := f.Seek(0, 0); err != nil {
+			return err
+		}
+		return writeFile(path.Join(dir, toolsArchiveFile), 0644, f)
+	})
+}
+
+// checkArchiveHeader returns an error if the given header is not that
+// of a regular file at the top level of a tools archive.
+func checkArchiveHeader(hdr *tar.Header) error {
+	if strings.ContainsAny(hdr.Name, "/\\") {
+		return fmt.Errorf("bad name %q in tools archive", hdr.Name)
+	}
+	if hdr.Typeflag != tar.TypeReg {
+		return fmt.Errorf("bad file type %c in file %q in tools archive", hdr.Typeflag, hdr.Name)
+	}
+	return nil
+}
+
+// installTools makes the tools directory for the given tools within
+// dataDir, calling extract to write the tools' files into a temporary
+// directory that then replaces it. If a valid tools directory already
+// exists, installTools returns without error.
+func installTools(dataDir string, tools *coretools.Tools, extract func(dir string) error) error {
 	// Make a temporary directory in the tools directory,
 	// first ensuring that the tools directory exists.
 	toolsDir := path.Join(dataDir, "tools")
-	err = os.MkdirAll(toolsDir, dirPerm)
+	err := os.MkdirAll(toolsDir, dirPerm)
 	if err != nil {
 		return err
 	}
@@ -91,31 +151,9 @@ func UnpackTools(dataDir string, tools *coretools.Tools, r io.Reader) (err error
 	}
 	defer removeAll(dir)
 
-	// Checksum matches, now reset the file and untar it.
-	_, err = f.Seek(0, 0)
-	if err != nil {
+	if err := extract(dir); err != nil {
 		return err
 	}
-	tr := tar.NewReader(f)
-	for {
-		hdr, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
-		}
-		if strings.ContainsAny(hdr.Name, "/\\") {
-			return fmt.Errorf("bad name %q in tools archive", hdr.Name)
-		}
-		if hdr.Typeflag != tar.TypeReg {
-			return fmt.Errorf("bad file type %c in file %q in tools archive", hdr.Typeflag, hdr.Name)
-		}
-		name := path.Join(dir, hdr.Name)
-		if err := writeFile(name, os.FileMode(hdr.Mode&0777), tr); err != nil {
-			return errors.Annotatef(err, "tar extract %q failed", name)
-		}
-	}
 	toolsMetadataData, err := json.Marshal(tools)
 	if err != nil {
 		return err
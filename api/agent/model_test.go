@@ -4,11 +4,15 @@
 package agent_test
 
 import (
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api/agent"
 	apitesting "github.com/juju/juju/api/testing"
+	"github.com/juju/juju/cloud"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/watcher/watchertest"
 )
 
 type modelSuite struct {
@@ -30,3 +34,26 @@ func (s *modelSuite) SetUpTest(c *gc.C) {
 		agentAPI, s.BackingState,
 	)
 }
+
+func (s *modelSuite) TestWatchCloudSpecChanges(c *gc.C) {
+	stateAPI, _ := s.OpenAPIAsNewMachine(c, state.JobManageModel)
+	agentAPI := agent.NewState(stateAPI)
+
+	w, err := agentAPI.WatchCloudSpecChanges(s.State.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	wc := watchertest.NewNotifyWatcherC(c, w, s.BackingState.StartSync)
+	defer wc.AssertStops()
+	// Initial event.
+	wc.AssertOneChange()
+
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	tag, ok := model.CloudCredential()
+	c.Assert(ok, jc.IsTrue)
+	err = s.State.UpdateCloudCredential(tag, cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"username": "fred",
+		"password": "secret",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -89,6 +89,32 @@ func (c *State) WatchCredential(tag names.CloudCredentialTag) (watcher.NotifyWat
 	return apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
 }
 
+// WatchCloudSpecChanges returns a watcher which reports when the cloud
+// spec, and in particular the credential, of the model with the given
+// tag has changed. It returns an error satisfying
+// params.IsCodeNotFound if the model has no credential.
+func (st *State) WatchCloudSpecChanges(tag names.ModelTag) (watcher.NotifyWatcher, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("watching cloud spec changes")
+	}
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	err := st.facade.FacadeCall("WatchCloudSpecsChanges", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(st.facade.RawAPICaller(), result), nil
+}
+
 type Entity struct {
 	st  *State
 	tag names.Tag
@@ -14,6 +14,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -554,7 +555,7 @@ type dialOpts struct {
 //
 // It also returns the TLS configuration that it has derived from the Info.
 func dialAPI(ctx context.Context, info *Info, opts0 DialOpts) (*dialResult, error) {
-	if len(info.Addrs) == 0 {
+	if len(info.Addrs) == 0 && info.SRVName == "" {
 		return nil, errors.New("no API addresses to connect to")
 	}
 	opts := dialOpts{
@@ -576,6 +577,9 @@ func dialAPI(ctx context.Context, info *Info, opts0 DialOpts) (*dialResult, erro
 	if opts.IPAddrResolver == nil {
 		opts.IPAddrResolver = net.DefaultResolver
 	}
+	if opts.SRVResolver == nil {
+		opts.SRVResolver = net.DefaultResolver
+	}
 	if opts.Clock == nil {
 		opts.Clock = clock.WallClock
 	}
@@ -591,7 +595,18 @@ func dialAPI(ctx context.Context, info *Info, opts0 DialOpts) (*dialResult, erro
 		defer cancel()
 		ctx = ctx1
 	}
-	dialInfo, err := dialWebsocketMulti(ctx, info.Addrs, path, opts)
+	addrs := info.Addrs
+	if info.SRVName != "" {
+		srvAddrs, err := lookupSRV(ctx, info.SRVName, opts.SRVResolver)
+		if err != nil {
+			if len(addrs) == 0 {
+				return nil, errors.Annotatef(err, "cannot discover API addresses for %q", info.SRVName)
+			}
+			logger.Warningf("cannot discover API addresses for %q: %v", info.SRVName, err)
+		}
+		addrs = mergeAddrs(srvAddrs, addrs)
+	}
+	dialInfo, err := dialWebsocketMulti(ctx, addrs, path, opts)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -740,6 +755,45 @@ func lookupIPAddr(ctx context.Context, host string, resolver IPAddrResolver) ([]
 	return ips, nil
 }
 
+// lookupSRV returns the host:port addresses of the API servers
+// published as SRV records under the given name, in the order the
+// resolver returns them (by priority, then randomised by weight).
+func lookupSRV(ctx context.Context, name string, resolver SRVResolver) ([]string, error) {
+	_, srvs, err := resolver.LookupSRV(ctx, network.APISRVService, network.APISRVProto, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	addrs := make([]string, 0, len(srvs))
+	for _, srv := range srvs {
+		target := strings.TrimSuffix(srv.Target, ".")
+		if target == "" {
+			// A target of "." means the service is
+			// decidedly not available at this name.
+			continue
+		}
+		addrs = append(addrs, net.JoinHostPort(target, strconv.Itoa(int(srv.Port))))
+	}
+	if len(addrs) == 0 {
+		return nil, errors.NotFoundf("SRV records for %q", name)
+	}
+	logger.Debugf("discovered API addresses %v for %q", addrs, name)
+	return addrs, nil
+}
+
+// mergeAddrs returns the addresses in a followed by those in b,
+// without duplicates.
+func mergeAddrs(a, b []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, addr := range append(append([]string{}, a...), b...) {
+		if !seen[addr] {
+			seen[addr] = true
+			merged = append(merged, addr)
+		}
+	}
+	return merged
+}
+
 // recordTryError starts a try that just returns the given error.
 // This is so that we can use the usual Try error combination
 // logic even for errors that happen before we start a try.
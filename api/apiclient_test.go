@@ -672,6 +672,60 @@ func (s *apiclientSuite) TestDNSCacheUsed(c *gc.C) {
 	c.Assert(conn.IPAddr(), gc.Equals, "0.1.1.1:1234")
 }
 
+func (s *apiclientSuite) TestOpenDiscoversAddressesWithSRV(c *gc.C) {
+	var dialed string
+	fakeDialer := func(ctx context.Context, urlStr string, tlsConfig *tls.Config, ipAddr string) (jsoncodec.JSONConn, error) {
+		dialed = ipAddr
+		return fakeConn{}, nil
+	}
+	conn, err := api.Open(&api.Info{
+		SRVName:   "juju.example",
+		SkipLogin: true,
+		CACert:    jtesting.CACert,
+	}, api.DialOpts{
+		DialWebsocket: fakeDialer,
+		SRVResolver: apitesting.SRVResolverMap{
+			"_juju-api._tcp.juju.example": {{Target: "api-0.juju.example.", Port: 17070}},
+		},
+		IPAddrResolver: apitesting.IPAddrResolverMap{
+			"api-0.juju.example": {"0.1.1.1"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn, gc.NotNil)
+	c.Assert(dialed, gc.Equals, "0.1.1.1:17070")
+	c.Assert(conn.Addr(), gc.Equals, "api-0.juju.example:17070")
+}
+
+func (s *apiclientSuite) TestOpenFallsBackWhenSRVLookupFails(c *gc.C) {
+	fakeDialer := func(ctx context.Context, urlStr string, tlsConfig *tls.Config, ipAddr string) (jsoncodec.JSONConn, error) {
+		return fakeConn{}, nil
+	}
+	conn, err := api.Open(&api.Info{
+		Addrs:     []string{"0.1.2.3:1234"},
+		SRVName:   "juju.example",
+		SkipLogin: true,
+		CACert:    jtesting.CACert,
+	}, api.DialOpts{
+		DialWebsocket:  fakeDialer,
+		SRVResolver:    apitesting.SRVResolverMap{},
+		IPAddrResolver: apitesting.IPAddrResolverMap{},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.Addr(), gc.Equals, "0.1.2.3:1234")
+}
+
+func (s *apiclientSuite) TestOpenFailsWhenOnlySRVLookupFails(c *gc.C) {
+	_, err := api.Open(&api.Info{
+		SRVName:   "juju.example",
+		SkipLogin: true,
+		CACert:    jtesting.CACert,
+	}, api.DialOpts{
+		SRVResolver: apitesting.SRVResolverMap{},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot discover API addresses for "juju.example": mock resolver cannot resolve "_juju-api._tcp.juju.example"`)
+}
+
 func (s *apiclientSuite) TestNumericAddressIsNotAddedToCache(c *gc.C) {
 	fakeDialer := func(ctx context.Context, urlStr string, tlsConfig *tls.Config, ipAddr string) (jsoncodec.JSONConn, error) {
 		return fakeConn{}, nil
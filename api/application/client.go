@@ -8,6 +8,8 @@
 package application
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/charm.v6-unstable"
@@ -408,6 +410,19 @@ func (c *Client) Expose(application string) error {
 	return c.facade.FacadeCall("Expose", params, nil)
 }
 
+// SetTrust sets whether an application is trusted with the model's
+// cloud credential. Only model admins may change it.
+func (c *Client) SetTrust(application string, trusted bool) error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotSupportedf("trusting applications")
+	}
+	p := params.ApplicationTrust{
+		ApplicationName: application,
+		Trusted:         trusted,
+	}
+	return c.facade.FacadeCall("SetTrust", p, nil)
+}
+
 // Unexpose changes the juju-managed firewall to unexpose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) Unexpose(application string) error {
@@ -432,6 +447,28 @@ func (c *Client) Set(application string, options map[string]string) error {
 	return c.facade.FacadeCall("Set", p, nil)
 }
 
+// SetApplicationsConfig sets configuration options on several
+// applications, keyed by application name. Either all of the
+// applications are updated, or none of them are.
+func (c *Client) SetApplicationsConfig(options map[string]map[string]string) error {
+	if c.BestAPIVersion() < 6 {
+		return errors.NotSupportedf("setting config on multiple applications atomically")
+	}
+	appNames := make([]string, 0, len(options))
+	for name := range options {
+		appNames = append(appNames, name)
+	}
+	sort.Strings(appNames)
+	var p params.ApplicationsSet
+	for _, name := range appNames {
+		p.Applications = append(p.Applications, params.ApplicationSet{
+			ApplicationName: name,
+			Options:         options[name],
+		})
+	}
+	return c.facade.FacadeCall("SetApplicationsConfig", p, nil)
+}
+
 // Unset resets configuration options on an application.
 func (c *Client) Unset(application string, options []string) error {
 	p := params.ApplicationUnset{
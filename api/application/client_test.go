@@ -4,6 +4,7 @@
 package application_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -142,6 +143,54 @@ func (s *applicationSuite) TestDeployAttachStorageV4(c *gc.C) {
 	c.Assert(called, jc.IsFalse)
 }
 
+func (s *applicationSuite) TestSetApplicationsConfig(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Check(objType, gc.Equals, "Application")
+				c.Check(request, gc.Equals, "SetApplicationsConfig")
+				c.Check(a, jc.DeepEquals, params.ApplicationsSet{
+					Applications: []params.ApplicationSet{{
+						ApplicationName: "mysql",
+						Options:         map[string]string{"tuning": "fast"},
+					}, {
+						ApplicationName: "wordpress",
+						Options:         map[string]string{"blog-title": "hello"},
+					}},
+				})
+				return nil
+			},
+		),
+		BestVersion: 6,
+	})
+	err := client.SetApplicationsConfig(map[string]map[string]string{
+		"wordpress": {"blog-title": "hello"},
+		"mysql":     {"tuning": "fast"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestSetApplicationsConfigV5(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				return nil
+			},
+		),
+		BestVersion: 5,
+	})
+	err := client.SetApplicationsConfig(map[string]map[string]string{
+		"mysql": {"tuning": "fast"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(called, jc.IsFalse)
+}
+
 func (s *applicationSuite) TestDeployAttachStorageMultipleUnits(c *gc.C) {
 	var called bool
 	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
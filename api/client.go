@@ -97,13 +97,29 @@ func (c *Client) StatusHistory(kind status.HistoryKind, tag names.Tag, filter st
 			Err:  h.Err,
 		}
 		// TODO(perrito666) https://launchpad.net/bugs/1577589
-		if !history[i].Kind.Valid() {
+		if !history[i].Kind.Valid() && history[i].Kind != status.KindAnnotation {
 			logger.Errorf("history returned an unknown status kind %q", h.Kind)
 		}
 	}
 	return history, nil
 }
 
+// AddStatusAnnotation adds a note to the status history of the unit
+// or machine with the given tag, attributed to the current user.
+func (c *Client) AddStatusAnnotation(tag names.Tag, message string) error {
+	if c.BestAPIVersion() < 2 {
+		return errors.NotSupportedf("status annotations on this controller")
+	}
+	args := params.StatusAnnotations{
+		Annotations: []params.StatusAnnotation{{Tag: tag.String(), Message: message}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddStatusAnnotations", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // Resolved clears errors on a unit.
 func (c *Client) Resolved(unit string, retry bool) error {
 	p := params.Resolved{
@@ -113,6 +129,38 @@ func (c *Client) Resolved(unit string, retry bool) error {
 	return c.facade.FacadeCall("Resolved", p, nil)
 }
 
+// SuspendUnits stops the agents of the given units from running hooks
+// until the units are resumed.
+func (c *Client) SuspendUnits(units ...names.UnitTag) ([]params.ErrorResult, error) {
+	return c.setUnitsSuspended("SuspendUnits", units)
+}
+
+// ResumeUnits allows the agents of the given suspended units to run
+// hooks again.
+func (c *Client) ResumeUnits(units ...names.UnitTag) ([]params.ErrorResult, error) {
+	return c.setUnitsSuspended("ResumeUnits", units)
+}
+
+func (c *Client) setUnitsSuspended(method string, units []names.UnitTag) ([]params.ErrorResult, error) {
+	if c.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("suspending units on this controller")
+	}
+	p := params.Entities{
+		Entities: make([]params.Entity, len(units)),
+	}
+	for i, unit := range units {
+		p.Entities[i].Tag = unit.String()
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(method, p, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(units) {
+		return nil, errors.Errorf("expected %d results, got %d", len(units), len(results.Results))
+	}
+	return results.Results, nil
+}
+
 // RetryProvisioning updates the provisioning status of a machine allowing the
 // provisioner to retry.
 func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.ErrorResult, error) {
@@ -455,6 +503,22 @@ func (c *Client) OpenURI(uri string, query url.Values) (io.ReadCloser, error) {
 	return blob, nil
 }
 
+// APIServerMetrics returns the Prometheus metrics of the API server
+// the client is connected to, in the text exposition format. Only
+// controller superusers and users with read access to the controller
+// model may read them.
+func (c *Client) APIServerMetrics() (io.ReadCloser, error) {
+	httpClient, err := c.st.RootHTTPClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	blob, err := openBlob(httpClient, "/introspection/metrics", nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return blob, nil
+}
+
 // NewCharmDownloader returns a new charm downloader that wraps the
 // provided API client.
 func NewCharmDownloader(client *Client) *downloader.Downloader {
@@ -537,3 +601,9 @@ var websocketDial = func(dialer *websocket.Dialer, urlStr string, requestHeader
 func (c *Client) WatchDebugLog(args common.DebugLogParams) (<-chan common.LogMessage, error) {
 	return common.StreamDebugLog(c.st, args)
 }
+
+// OpenDebugLog returns a stream of structured log messages, like
+// WatchDebugLog, whose filters may be changed while it is open.
+func (c *Client) OpenDebugLog(args common.DebugLogParams) (*common.DebugLogStream, error) {
+	return common.OpenDebugLog(c.st, args)
+}
@@ -5,6 +5,8 @@ package api_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -441,12 +443,19 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 		IncludeModule: []string{"c", "d"},
 		ExcludeEntity: []string{"e", "f"},
 		ExcludeModule: []string{"g", "h"},
+		IncludeModel:  []string{"i"},
+		ExcludeModel:  []string{"j"},
 		Limit:         100,
 		Backlog:       200,
 		Level:         loggo.ERROR,
 		Replay:        true,
 		NoTail:        true,
 		StartTime:     time.Date(2016, 11, 30, 11, 48, 0, 100, time.UTC),
+		EndTime:       time.Date(2016, 11, 30, 12, 48, 0, 0, time.UTC),
+		RateLimit:     50,
+		Overflow:      "drop",
+		Trailer:       true,
+		Compress:      true,
 	}
 
 	client := s.APIState.Client()
@@ -462,15 +471,147 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 		"includeModule": params.IncludeModule,
 		"excludeEntity": params.ExcludeEntity,
 		"excludeModule": params.ExcludeModule,
+		"includeModel":  params.IncludeModel,
+		"excludeModel":  params.ExcludeModel,
 		"maxLines":      {"100"},
 		"backlog":       {"200"},
 		"level":         {"ERROR"},
 		"replay":        {"true"},
 		"noTail":        {"true"},
 		"startTime":     {"2016-11-30T11:48:00.0000001Z"},
+		"endTime":       {"2016-11-30T12:48:00Z"},
+		"ratelimit":     {"50"},
+		"overflow":      {"drop"},
+		"trailer":       {"true"},
+		"compress":      {"gzip"},
 	})
 }
 
+func (s *clientSuite) TestOpenDebugLogSetFilters(c *gc.C) {
+	stream := &jsonWriterStream{
+		fakeStreamReader: fakeStreamReader{strings.NewReader("null\n")},
+	}
+	s.PatchValue(api.WebsocketDial, func(_ *websocket.Dialer, _ string, _ http.Header) (base.Stream, error) {
+		return stream, nil
+	})
+
+	client := s.APIState.Client()
+	logStream, err := client.OpenDebugLog(common.DebugLogParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = logStream.SetFilters(common.DebugLogFilters{
+		IncludeEntity: []string{"unit-mysql-*"},
+		ExcludeModule: []string{"juju.worker"},
+		Level:         loggo.WARNING,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = logStream.SetFilters(common.DebugLogFilters{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stream.written, jc.DeepEquals, []interface{}{
+		params.DebugLogFilters{
+			IncludeEntity: []string{"unit-mysql-*"},
+			ExcludeModule: []string{"juju.worker"},
+			Level:         "WARNING",
+		},
+		params.DebugLogFilters{},
+	})
+}
+
+func (s *clientSuite) TestOpenDebugLogTrailer(c *gc.C) {
+	stream := &jsonReaderStream{
+		fakeStreamReader: fakeStreamReader{strings.NewReader("null\n")},
+		messages: []string{
+			`{"tag":"machine-0","sev":"INFO","mod":"juju.worker","msg":"hello"}`,
+			`{"trailer":{"lines-matched":5,"lines-sent":1,"bytes-sent":70,"truncated":true}}`,
+		},
+	}
+	s.PatchValue(api.WebsocketDial, func(_ *websocket.Dialer, _ string, _ http.Header) (base.Stream, error) {
+		return stream, nil
+	})
+
+	client := s.APIState.Client()
+	logStream, err := client.OpenDebugLog(common.DebugLogParams{Trailer: true})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var messages []common.LogMessage
+	for msg := range logStream.Messages() {
+		messages = append(messages, msg)
+	}
+	c.Assert(messages, jc.DeepEquals, []common.LogMessage{{
+		Entity:   "machine-0",
+		Severity: "INFO",
+		Module:   "juju.worker",
+		Message:  "hello",
+	}})
+	c.Assert(logStream.Trailer(), jc.DeepEquals, &common.DebugLogTrailer{
+		LinesMatched: 5,
+		LinesSent:    1,
+		BytesSent:    70,
+		Truncated:    true,
+	})
+}
+
+func (s *clientSuite) TestOpenDebugLogCompressed(c *gc.C) {
+	// Each record is sent as a chunk of a single gzip stream.
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	var messages [][]byte
+	for _, record := range []string{
+		`{"tag":"machine-0","sev":"INFO","mod":"juju.worker","msg":"hello"}` + "\n",
+		`{"tag":"machine-0","sev":"INFO","mod":"juju.worker","msg":"goodbye"}` + "\n",
+	} {
+		_, err := zw.Write([]byte(record))
+		c.Assert(err, jc.ErrorIsNil)
+		err = zw.Flush()
+		c.Assert(err, jc.ErrorIsNil)
+		messages = append(messages, append([]byte(nil), buf.Bytes()...))
+		buf.Reset()
+	}
+	stream := &messageStream{
+		fakeStreamReader: fakeStreamReader{strings.NewReader("null\n")},
+		messageType:      websocket.BinaryMessage,
+		messages:         messages,
+	}
+	s.PatchValue(api.WebsocketDial, func(_ *websocket.Dialer, _ string, _ http.Header) (base.Stream, error) {
+		return stream, nil
+	})
+
+	client := s.APIState.Client()
+	logStream, err := client.OpenDebugLog(common.DebugLogParams{Compress: true})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var received []string
+	for msg := range logStream.Messages() {
+		received = append(received, msg.Message)
+	}
+	c.Assert(received, jc.DeepEquals, []string{"hello", "goodbye"})
+}
+
+func (s *clientSuite) TestOpenDebugLogCompressedFallback(c *gc.C) {
+	// A server that doesn't support compression sends text messages.
+	stream := &messageStream{
+		fakeStreamReader: fakeStreamReader{strings.NewReader("null\n")},
+		messageType:      websocket.TextMessage,
+		messages: [][]byte{
+			[]byte(`{"tag":"machine-0","sev":"INFO","mod":"juju.worker","msg":"hello"}` + "\n"),
+			[]byte(`{"tag":"machine-0","sev":"INFO","mod":"juju.worker","msg":"goodbye"}` + "\n"),
+		},
+	}
+	s.PatchValue(api.WebsocketDial, func(_ *websocket.Dialer, _ string, _ http.Header) (base.Stream, error) {
+		return stream, nil
+	})
+
+	client := s.APIState.Client()
+	logStream, err := client.OpenDebugLog(common.DebugLogParams{Compress: true})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var received []string
+	for msg := range logStream.Messages() {
+		received = append(received, msg.Message)
+	}
+	c.Assert(received, jc.DeepEquals, []string{"hello", "goodbye"})
+}
+
 func (s *clientSuite) TestConnectStreamAtUUIDPath(c *gc.C) {
 	catcher := urlCatcher{}
 	s.PatchValue(api.WebsocketDial, catcher.recordLocation)
@@ -602,3 +743,62 @@ func (s fakeStreamReader) ReadJSON(v interface{}) error {
 func (s fakeStreamReader) WriteJSON(v interface{}) error {
 	return errors.NotImplementedf("WriteJSON")
 }
+
+// jsonWriterStream is a fakeStreamReader that records the values
+// written to it with WriteJSON.
+type jsonWriterStream struct {
+	fakeStreamReader
+	written []interface{}
+}
+
+func (s *jsonWriterStream) WriteJSON(v interface{}) error {
+	s.written = append(s.written, v)
+	return nil
+}
+
+// jsonReaderStream is a fakeStreamReader that returns each of its
+// messages in turn from ReadJSON.
+type jsonReaderStream struct {
+	fakeStreamReader
+	messages []string
+}
+
+func (s *jsonReaderStream) ReadJSON(v interface{}) error {
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	return json.Unmarshal([]byte(msg), v)
+}
+
+// messageStream is a fakeStreamReader that, once the initial response
+// has been read, returns each of its messages in turn from NextReader,
+// as messages of the given type.
+type messageStream struct {
+	fakeStreamReader
+	messageType int
+	initialRead bool
+	messages    [][]byte
+}
+
+func (s *messageStream) NextReader() (messageType int, r io.Reader, err error) {
+	if !s.initialRead {
+		s.initialRead = true
+		return s.fakeStreamReader.NextReader()
+	}
+	if len(s.messages) == 0 {
+		return 0, nil, io.EOF
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	return s.messageType, bytes.NewReader(msg), nil
+}
+
+func (s *messageStream) ReadJSON(v interface{}) error {
+	_, r, err := s.NextReader()
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(r).Decode(v)
+}
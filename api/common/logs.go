@@ -4,10 +4,14 @@
 package common
 
 import (
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
@@ -34,6 +38,16 @@ type DebugLogParams struct {
 	// ExcludeModule lists logging modules to exclude from the resposne. If a
 	// module is specified, all the submodules are also excluded.
 	ExcludeModule []string
+	// IncludeModel lists the models, by UUID or name, whose logs to
+	// include in the response, in place of those of the controller
+	// model. It is only valid when streaming the controller model's
+	// logs, and requires superuser access to the controller.
+	IncludeModel []string
+	// ExcludeModel lists the models, by UUID or name, whose logs to
+	// exclude from the response. As with IncludeModel, it is only valid
+	// for the controller model. If IncludeModel is not set, the logs of
+	// all other models are included.
+	ExcludeModel []string
 	// Limit defines the maximum number of lines to return. Once this many
 	// have been sent, the socket is closed.  If zero, all filtered lines are
 	// sent down the connection until the client closes the connection.
@@ -48,11 +62,34 @@ type DebugLogParams struct {
 	// than the end. If replay is true, backlog is ignored.
 	Replay bool
 	// NoTail tells the server to only return the logs it has now, and not
-	// to wait for new logs to arrive.
+	// to wait for new logs to arrive. The server ends the stream, and
+	// the channel returned by StreamDebugLog is closed, once they have
+	// all been sent.
 	NoTail bool
 	// StartTime should be a time in the past - only records with a
 	// log time on or after StartTime will be returned.
 	StartTime time.Time
+	// EndTime, if set, limits the records returned to those with a
+	// log time on or before EndTime. Once EndTime has passed, the
+	// server closes the connection after the last matching record.
+	EndTime time.Time
+	// RateLimit, if non-zero, limits the number of lines the server
+	// sends per second.
+	RateLimit uint
+	// Overflow specifies what the server does when the client reads
+	// lines so slowly that its queue of lines to send fills up: one of
+	// "block" (the default), "drop" or "disconnect".
+	Overflow string
+	// Trailer asks the server to end a stream that finishes normally,
+	// because NoTail or EndTime were set or Limit lines were sent,
+	// with a summary of the lines sent, which is available from the
+	// stream's Trailer method once its messages channel is closed.
+	Trailer bool
+	// Compress asks the server to compress the log records it sends
+	// with gzip, which reduces the bandwidth used when streaming a
+	// large volume of logs over a slow link. Servers that do not
+	// support compression send the records uncompressed.
+	Compress bool
 }
 
 func (args DebugLogParams) URLQuery() url.Values {
@@ -61,6 +98,8 @@ func (args DebugLogParams) URLQuery() url.Values {
 		"includeModule": args.IncludeModule,
 		"excludeEntity": args.ExcludeEntity,
 		"excludeModule": args.ExcludeModule,
+		"includeModel":  args.IncludeModel,
+		"excludeModel":  args.ExcludeModel,
 	}
 	if args.Replay {
 		attrs.Set("replay", fmt.Sprint(args.Replay))
@@ -80,6 +119,21 @@ func (args DebugLogParams) URLQuery() url.Values {
 	if !args.StartTime.IsZero() {
 		attrs.Set("startTime", args.StartTime.Format(time.RFC3339Nano))
 	}
+	if !args.EndTime.IsZero() {
+		attrs.Set("endTime", args.EndTime.Format(time.RFC3339Nano))
+	}
+	if args.RateLimit > 0 {
+		attrs.Set("ratelimit", fmt.Sprint(args.RateLimit))
+	}
+	if args.Overflow != "" {
+		attrs.Set("overflow", args.Overflow)
+	}
+	if args.Trailer {
+		attrs.Set("trailer", fmt.Sprint(args.Trailer))
+	}
+	if args.Compress {
+		attrs.Set("compress", "gzip")
+	}
 	return attrs
 }
 
@@ -93,9 +147,86 @@ type LogMessage struct {
 	Message   string
 }
 
+// DebugLogFilters holds the filters of an open debug log stream that
+// may be changed without reconnecting. The fields have the same
+// meaning as those of DebugLogParams.
+type DebugLogFilters struct {
+	IncludeEntity []string
+	IncludeModule []string
+	ExcludeEntity []string
+	ExcludeModule []string
+	Level         loggo.Level
+}
+
+// DebugLogTrailer summarises a debug log stream that has ended.
+type DebugLogTrailer struct {
+	// LinesMatched is the number of lines that the server read
+	// which matched the filters.
+	LinesMatched uint64
+	// LinesSent is the number of lines that the server sent.
+	LinesSent uint64
+	// BytesSent is the size of the messages that the server sent.
+	BytesSent uint64
+	// Truncated reports whether lines that matched the filters were
+	// not sent, because they were dropped or because Limit lines had
+	// already been sent.
+	Truncated bool
+}
+
+// DebugLogStream is an open stream of debug log records.
+type DebugLogStream struct {
+	connection base.Stream
+	messages   chan LogMessage
+
+	// trailer is set, if the server sent one, before messages is
+	// closed.
+	trailer *DebugLogTrailer
+}
+
+// Messages returns a channel of the messages received from the server.
+// It is closed when the stream ends.
+func (s *DebugLogStream) Messages() <-chan LogMessage {
+	return s.messages
+}
+
+// Trailer returns the summary of the stream sent by the server, or nil
+// if none was sent. The stream must have been opened with Trailer set,
+// and its messages channel must have been closed.
+func (s *DebugLogStream) Trailer() *DebugLogTrailer {
+	return s.trailer
+}
+
+// SetFilters replaces the filters of the stream. Records sent after the
+// server has applied the new filters match them; if no records have
+// been sent yet, the backlog requested when the stream was opened is
+// sent again with the new filters.
+func (s *DebugLogStream) SetFilters(filters DebugLogFilters) error {
+	msg := params.DebugLogFilters{
+		IncludeEntity: filters.IncludeEntity,
+		IncludeModule: filters.IncludeModule,
+		ExcludeEntity: filters.ExcludeEntity,
+		ExcludeModule: filters.ExcludeModule,
+	}
+	if filters.Level != loggo.UNSPECIFIED {
+		msg.Level = fmt.Sprint(filters.Level)
+	}
+	return errors.Trace(s.connection.WriteJSON(msg))
+}
+
 // StreamDebugLog requests the specified debug log records from the
 // server and returns a channel of the messages that come back.
 func StreamDebugLog(source base.StreamConnector, args DebugLogParams) (<-chan LogMessage, error) {
+	stream, err := OpenDebugLog(source, args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return stream.Messages(), nil
+}
+
+// OpenDebugLog requests the specified debug log records from the
+// server and returns a stream of the messages that come back, whose
+// filters may be changed while it is open.
+func OpenDebugLog(source base.StreamConnector, args DebugLogParams) (*DebugLogStream, error) {
 	// TODO(babbageclunk): this isn't cancellable - if the caller stops
 	// reading from the channel (because it has an error, for example),
 	// the goroutine will be leaked. This is OK when used from the command
@@ -111,17 +242,36 @@ func StreamDebugLog(source base.StreamConnector, args DebugLogParams) (<-chan Lo
 		return nil, errors.Trace(err)
 	}
 
-	messages := make(chan LogMessage)
+	stream := &DebugLogStream{
+		connection: connection,
+		messages:   make(chan LogMessage),
+	}
+	readJSON := connection.ReadJSON
+	if args.Compress {
+		readJSON = (&debugLogReader{stream: connection}).ReadJSON
+	}
 	go func() {
-		defer close(messages)
+		defer close(stream.messages)
 
 		for {
-			var msg params.LogMessage
-			err := connection.ReadJSON(&msg)
+			var msg struct {
+				params.LogMessage
+				Trailer *params.DebugLogTrailer `json:"trailer"`
+			}
+			err := readJSON(&msg)
 			if err != nil {
 				return
 			}
-			messages <- LogMessage{
+			if msg.Trailer != nil {
+				stream.trailer = &DebugLogTrailer{
+					LinesMatched: msg.Trailer.LinesMatched,
+					LinesSent:    msg.Trailer.LinesSent,
+					BytesSent:    msg.Trailer.BytesSent,
+					Truncated:    msg.Trailer.Truncated,
+				}
+				continue
+			}
+			stream.messages <- LogMessage{
 				Entity:    msg.Entity,
 				Timestamp: msg.Timestamp,
 				Severity:  msg.Severity,
@@ -132,5 +282,75 @@ func StreamDebugLog(source base.StreamConnector, args DebugLogParams) (<-chan Lo
 		}
 	}()
 
-	return messages, nil
+	return stream, nil
+}
+
+// debugLogReader reads successive JSON values from a debug log stream
+// on which compression was requested. A server that supports it sends
+// each flushed chunk of a single gzip stream as a binary message; one
+// that predates compression ignores the request and sends each value
+// as a text message. The kind of the first message received decides
+// how the stream is read.
+type debugLogReader struct {
+	stream base.Stream
+	decode func(v interface{}) error
+}
+
+// ReadJSON reads the next JSON value from the stream into v.
+func (r *debugLogReader) ReadJSON(v interface{}) error {
+	if r.decode != nil {
+		return r.decode(v)
+	}
+	messageType, reader, err := r.stream.NextReader()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch messageType {
+	case websocket.TextMessage:
+		r.decode = r.stream.ReadJSON
+		return json.NewDecoder(reader).Decode(v)
+	case websocket.BinaryMessage:
+		zr, err := gzip.NewReader(&binaryMessageReader{
+			stream:  r.stream,
+			current: reader,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		r.decode = json.NewDecoder(zr).Decode
+		return r.decode(v)
+	}
+	return errors.Errorf("unexpected message type %v", messageType)
+}
+
+// binaryMessageReader is an io.Reader that reads the payloads of the
+// binary messages received on a stream, one after another.
+type binaryMessageReader struct {
+	stream  base.Stream
+	current io.Reader
+}
+
+// Read is part of the io.Reader interface.
+func (r *binaryMessageReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			messageType, reader, err := r.stream.NextReader()
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			if messageType != websocket.BinaryMessage {
+				return 0, errors.Errorf("unexpected message type %v", messageType)
+			}
+			r.current = reader
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
 }
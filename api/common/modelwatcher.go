@@ -81,3 +81,12 @@ func (e *ModelWatcher) UpdateStatusHookInterval() (time.Duration, error) {
 	}
 	return modelConfig.UpdateStatusHookInterval(), nil
 }
+
+// MaintenanceWindows returns the model's current maintenance windows.
+func (e *ModelWatcher) MaintenanceWindows() (config.MaintenanceWindows, error) {
+	modelConfig, err := e.ModelConfig()
+	if err != nil {
+		return nil, err
+	}
+	return modelConfig.MaintenanceWindows(), nil
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 
 	"github.com/juju/errors"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
 
@@ -154,6 +155,34 @@ func (c *Client) DestroyController(args DestroyControllerParams) error {
 	}, nil)
 }
 
+// ConfigSet changes the controller config. Only the attributes in
+// controller.AllowedUpdateConfigAttributes may be changed.
+func (c *Client) ConfigSet(values map[string]interface{}) error {
+	if c.BestAPIVersion() < 5 {
+		return errors.New("this controller version doesn't support updating controller config")
+	}
+	return errors.Trace(c.facade.FacadeCall("ConfigSet", params.ControllerConfigSet{
+		Config: values,
+	}, nil))
+}
+
+// UpgradeDryRun returns the upgrade steps that the controller would run
+// when upgraded to the given version, in order, with the result of
+// checking the preconditions of each.
+func (c *Client) UpgradeDryRun(to version.Number) ([]params.UpgradeStepCheck, error) {
+	if c.BestAPIVersion() < 6 {
+		return nil, errors.NotSupportedf("upgrade dry run")
+	}
+	var result params.UpgradeDryRunResult
+	err := c.facade.FacadeCall("UpgradeDryRun", params.UpgradeDryRunArgs{
+		TargetVersion: to,
+	}, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Steps, nil
+}
+
 // ListBlockedModels returns a list of all models within the controller
 // which have at least one block in place.
 func (c *Client) ListBlockedModels() ([]params.ModelBlockInfo, error) {
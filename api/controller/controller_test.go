@@ -10,6 +10,7 @@ import (
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
@@ -77,6 +78,68 @@ func (s *Suite) TestDestroyControllerError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "nope")
 }
 
+func (s *Suite) TestConfigSet(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 5,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			return stub.NextErr()
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	err := client.ConfigSet(map[string]interface{}{"features": "new-schema"})
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.ConfigSet", []interface{}{params.ControllerConfigSet{
+			Config: map[string]interface{}{"features": "new-schema"},
+		}}},
+	})
+}
+
+func (s *Suite) TestConfigSetAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 4}
+	client := controller.NewClient(apiCaller)
+	err := client.ConfigSet(map[string]interface{}{"features": "new-schema"})
+	c.Assert(err, gc.ErrorMatches, "this controller version doesn't support updating controller config")
+}
+
+func (s *Suite) TestUpgradeDryRun(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 6,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.UpgradeDryRunResult)) = params.UpgradeDryRunResult{
+				Steps: []params.UpgradeStepCheck{{
+					Version:     "2.2.3",
+					Description: "add max-action-age and max-action-size config settings",
+				}},
+			}
+			return stub.NextErr()
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	steps, err := client.UpgradeDryRun(version.MustParse("2.2.3"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(steps, jc.DeepEquals, []params.UpgradeStepCheck{{
+		Version:     "2.2.3",
+		Description: "add max-action-age and max-action-size config settings",
+	}})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.UpgradeDryRun", []interface{}{params.UpgradeDryRunArgs{
+			TargetVersion: version.MustParse("2.2.3"),
+		}}},
+	})
+}
+
+func (s *Suite) TestUpgradeDryRunAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 5}
+	client := controller.NewClient(apiCaller)
+	_, err := client.UpgradeDryRun(version.MustParse("2.2.3"))
+	c.Assert(err, gc.ErrorMatches, "upgrade dry run not supported")
+}
+
 func (s *Suite) TestInitiateMigration(c *gc.C) {
 	s.checkInitiateMigration(c, makeSpec())
 }
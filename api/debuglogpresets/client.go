@@ -0,0 +1,68 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package debuglogpresets provides access to the named debug-log
+// filter presets stored on the controller.
+package debuglogpresets
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the DebugLogPresets API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the DebugLogPresets API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "DebugLogPresets")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// List returns all of the debug-log presets stored on the controller.
+func (c *Client) List() ([]params.DebugLogPreset, error) {
+	var result params.DebugLogPresets
+	if err := c.facade.FacadeCall("List", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Presets, nil
+}
+
+// Get returns the debug-log preset with the given name.
+func (c *Client) Get(name string) (params.DebugLogPreset, error) {
+	presets, err := c.List()
+	if err != nil {
+		return params.DebugLogPreset{}, errors.Trace(err)
+	}
+	for _, p := range presets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return params.DebugLogPreset{}, errors.NotFoundf("debug-log preset %q", name)
+}
+
+// Set creates or replaces the given debug-log preset.
+func (c *Client) Set(preset params.DebugLogPreset) error {
+	args := params.DebugLogPresets{Presets: []params.DebugLogPreset{preset}}
+	var result params.ErrorResults
+	if err := c.facade.FacadeCall("Set", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// Remove removes the named debug-log preset.
+func (c *Client) Remove(name string) error {
+	args := params.DebugLogPresetNames{Names: []string{name}}
+	var result params.ErrorResults
+	if err := c.facade.FacadeCall("Remove", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
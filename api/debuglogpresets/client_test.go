@@ -0,0 +1,82 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package debuglogpresets_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/debuglogpresets"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestGet(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "DebugLogPresets")
+		c.Check(request, gc.Equals, "List")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.DebugLogPresets)) = params.DebugLogPresets{
+			Presets: []params.DebugLogPreset{{
+				Name:  "other",
+				Level: "ERROR",
+			}, {
+				Name:          "noisy-neutron",
+				IncludeEntity: []string{"unit-neutron-*"},
+			}},
+		}
+		return nil
+	})
+	client := debuglogpresets.NewClient(apiCaller)
+	preset, err := client.Get("noisy-neutron")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(preset, jc.DeepEquals, params.DebugLogPreset{
+		Name:          "noisy-neutron",
+		IncludeEntity: []string{"unit-neutron-*"},
+	})
+
+	_, err = client.Get("missing")
+	c.Assert(err, gc.ErrorMatches, `debug-log preset "missing" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientSuite) TestSet(c *gc.C) {
+	preset := params.DebugLogPreset{
+		Name:   "uniter",
+		Format: "json",
+	}
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "Set")
+		c.Check(arg, jc.DeepEquals, params.DebugLogPresets{
+			Presets: []params.DebugLogPreset{preset},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	err := debuglogpresets.NewClient(apiCaller).Set(preset)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *clientSuite) TestRemove(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "Remove")
+		c.Check(arg, jc.DeepEquals, params.DebugLogPresetNames{Names: []string{"uniter"}})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	})
+	err := debuglogpresets.NewClient(apiCaller).Remove("uniter")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -4,6 +4,7 @@
 package diskmanager
 
 import (
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/base"
@@ -43,3 +44,47 @@ func (st *State) SetMachineBlockDevices(devices []storage.BlockDevice) error {
 	}
 	return results.OneError()
 }
+
+// MachineFilesystemAttachments returns the provisioned filesystem
+// attachments of the machine identified by the authenticated machine
+// tag.
+func (st *State) MachineFilesystemAttachments() ([]params.FilesystemAttachment, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("filesystem usage reporting")
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: st.tag.String()}},
+	}
+	var results params.FilesystemAttachmentsResults
+	err := st.facade.FacadeCall("MachineFilesystemAttachments", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Result, nil
+}
+
+// SetMachineFilesystemUsage records the usage of the filesystems
+// attached to the machine identified by the authenticated machine tag.
+func (st *State) SetMachineFilesystemUsage(usage []params.FilesystemAttachmentUsage) error {
+	if st.facade.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("filesystem usage reporting")
+	}
+	args := params.SetMachineFilesystemUsage{
+		MachineFilesystemUsage: []params.MachineFilesystemUsage{{
+			Machine:     st.tag.String(),
+			Filesystems: usage,
+		}},
+	}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetMachineFilesystemUsage", args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
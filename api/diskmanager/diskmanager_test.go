@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 
+	jujuerrors "github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
@@ -124,3 +125,79 @@ func (s *DiskManagerSuite) TestSetMachineBlockDevicesResultCountInvalid(c *gc.C)
 		c.Check(err, gc.ErrorMatches, fmt.Sprintf("expected 1 result, got %d", n))
 	}
 }
+
+func (s *DiskManagerSuite) TestMachineFilesystemAttachments(c *gc.C) {
+	attachments := []params.FilesystemAttachment{{
+		FilesystemTag: "filesystem-0",
+		MachineTag:    "machine-123",
+		Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv/data"},
+	}}
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "DiskManager")
+			c.Check(request, gc.Equals, "MachineFilesystemAttachments")
+			c.Check(arg, gc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-123"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.FilesystemAttachmentsResults{})
+			*(result.(*params.FilesystemAttachmentsResults)) = params.FilesystemAttachmentsResults{
+				Results: []params.FilesystemAttachmentsResult{{Result: attachments}},
+			}
+			callCount++
+			return nil
+		},
+		BestVersion: 3,
+	}
+	st := diskmanager.NewState(apiCaller, names.NewMachineTag("123"))
+	result, err := st.MachineFilesystemAttachments()
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result, jc.DeepEquals, attachments)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *DiskManagerSuite) TestSetMachineFilesystemUsage(c *gc.C) {
+	usage := []params.FilesystemAttachmentUsage{{
+		FilesystemTag: "filesystem-0",
+		Usage:         params.FilesystemUsage{UsedBytes: 1024, AvailableBytes: 2048},
+	}}
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "DiskManager")
+			c.Check(request, gc.Equals, "SetMachineFilesystemUsage")
+			c.Check(arg, jc.DeepEquals, params.SetMachineFilesystemUsage{
+				MachineFilesystemUsage: []params.MachineFilesystemUsage{{
+					Machine:     "machine-123",
+					Filesystems: usage,
+				}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: nil}},
+			}
+			callCount++
+			return nil
+		},
+		BestVersion: 3,
+	}
+	st := diskmanager.NewState(apiCaller, names.NewMachineTag("123"))
+	err := st.SetMachineFilesystemUsage(usage)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *DiskManagerSuite) TestFilesystemUsageNotSupported(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		},
+		BestVersion: 2,
+	}
+	st := diskmanager.NewState(apiCaller, names.NewMachineTag("123"))
+	_, err := st.MachineFilesystemAttachments()
+	c.Check(err, jc.Satisfies, jujuerrors.IsNotSupported)
+	err = st.SetMachineFilesystemUsage(nil)
+	c.Check(err, jc.Satisfies, jujuerrors.IsNotSupported)
+}
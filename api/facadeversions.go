@@ -13,12 +13,12 @@ package api
 var facadeVersions = map[string]int{
 	"Action":                       2,
 	"ActionPruner":                 1,
-	"Agent":                        2,
+	"Agent":                        3,
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  5,
+	"Application":                  6,
 	"ApplicationOffers":            1,
 	"ApplicationScaler":            1,
 	"Backups":                      1,
@@ -27,15 +27,17 @@ var facadeVersions = map[string]int{
 	"CharmRevisionUpdater":         2,
 	"Charms":                       2,
 	"Cleaner":                      2,
-	"Client":                       1,
+	"Client":                       2,
 	"Cloud":                        2,
-	"Controller":                   4,
+	"Controller":                   6,
 	"CrossModelRelations":          1,
+	"DebugLogPresets":              1,
 	"Deployer":                     1,
-	"DiskManager":                  2,
+	"DiskManager":                  3,
 	"EntityWatcher":                2,
 	"FilesystemAttachmentsWatcher": 2,
-	"Firewaller":                   4,
+	"Firewaller":                   5,
+	"FirewallRules":                1,
 	"HighAvailability":             2,
 	"HostKeyReporter":              1,
 	"ImageManager":                 2,
@@ -47,6 +49,7 @@ var facadeVersions = map[string]int{
 	"LeadershipService":            2,
 	"LifeFlag":                     1,
 	"LogForwarding":                1,
+	"LogLevels":                    1,
 	"Logger":                       1,
 	"MachineActions":               1,
 	"MachineManager":               3,
@@ -62,9 +65,12 @@ var facadeVersions = map[string]int{
 	"MigrationStatusWatcher":       1,
 	"MigrationTarget":              1,
 	"ModelConfig":                  1,
+	"ModelGeneration":              1,
+	"ModelHistory":                 1,
 	"ModelManager":                 4,
 	"ModelUpgrader":                1,
 	"NotifyWatcher":                1,
+	"Operations":                   1,
 	"Payloads":                     1,
 	"PayloadsHookContext":          1,
 	"Pinger":                       1,
@@ -88,7 +94,8 @@ var facadeVersions = map[string]int{
 	"Subnets":                      2,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       6,
+	"Uniter":                       7,
+	"UpgradeSteps":                 1,
 	"Upgrader":                     1,
 	"UserManager":                  1,
 	"VolumeAttachmentsWatcher":     2,
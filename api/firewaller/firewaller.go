@@ -23,6 +23,7 @@ const firewallerFacade = "Firewaller"
 type Client struct {
 	facade base.FacadeCaller
 	*common.ModelWatcher
+	*common.ControllerConfigAPI
 	*cloudspec.CloudSpecAPI
 }
 
@@ -30,9 +31,10 @@ type Client struct {
 func NewClient(caller base.APICaller) *Client {
 	facadeCaller := base.NewFacadeCaller(caller, firewallerFacade)
 	return &Client{
-		facade:       facadeCaller,
-		ModelWatcher: common.NewModelWatcher(facadeCaller),
-		CloudSpecAPI: cloudspec.NewCloudSpecAPI(facadeCaller),
+		facade:              facadeCaller,
+		ModelWatcher:        common.NewModelWatcher(facadeCaller),
+		ControllerConfigAPI: common.NewControllerConfig(facadeCaller),
+		CloudSpecAPI:        cloudspec.NewCloudSpecAPI(facadeCaller),
 	}
 }
 
@@ -218,3 +220,31 @@ func (c *Client) MacaroonForRelation(relationKey string) (*macaroon.Macaroon, er
 	}
 	return result.Result, nil
 }
+
+// WatchFirewallRules returns a NotifyWatcher that notifies of changes
+// to the controller's firewall rules.
+func (c *Client) WatchFirewallRules() (watcher.NotifyWatcher, error) {
+	if c.BestAPIVersion() < 5 {
+		return nil, errors.NotImplementedf("WatchFirewallRules() (need V5+)")
+	}
+	var result params.NotifyWatchResult
+	if err := c.facade.FacadeCall("WatchFirewallRules", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(c.facade.RawAPICaller(), result), nil
+}
+
+// FirewallRules returns the controller's firewall rules.
+func (c *Client) FirewallRules() ([]params.FirewallRule, error) {
+	if c.BestAPIVersion() < 5 {
+		return nil, errors.NotImplementedf("FirewallRules() (need V5+)")
+	}
+	var result params.ListFirewallRulesResults
+	if err := c.facade.FacadeCall("FirewallRules", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Rules, nil
+}
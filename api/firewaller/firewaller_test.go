@@ -181,3 +181,43 @@ func (s *firewallerSuite) TestMacaroonForRelation(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "FAIL")
 	c.Check(callCount, gc.Equals, 1)
 }
+
+func (s *firewallerSuite) TestFirewallRules(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Firewaller")
+		c.Check(version, gc.Equals, 5)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "FirewallRules")
+		c.Assert(arg, gc.IsNil)
+		c.Assert(result, gc.FitsTypeOf, &params.ListFirewallRulesResults{})
+		*(result.(*params.ListFirewallRulesResults)) = params.ListFirewallRulesResults{
+			Rules: []params.FirewallRule{{
+				KnownService:   "ssh",
+				WhitelistCIDRS: []string{"192.168.1.0/24"},
+			}},
+		}
+		callCount++
+		return nil
+	})
+	client := firewaller.NewClient(testing.BestVersionCaller{apiCaller, 5})
+	rules, err := client.FirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rules, jc.DeepEquals, []params.FirewallRule{{
+		KnownService:   "ssh",
+		WhitelistCIDRS: []string{"192.168.1.0/24"},
+	}})
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *firewallerSuite) TestFirewallRulesNotImplemented(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	client := firewaller.NewClient(testing.BestVersionCaller{apiCaller, 4})
+	_, err := client.FirewallRules()
+	c.Check(err, gc.ErrorMatches, `FirewallRules\(\) \(need V5\+\) not implemented`)
+	_, err = client.WatchFirewallRules()
+	c.Check(err, gc.ErrorMatches, `WatchFirewallRules\(\) \(need V5\+\) not implemented`)
+}
@@ -0,0 +1,51 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package firewallrules provides access to the firewall rules that
+// restrict access to controller-level services.
+package firewallrules
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the FirewallRules API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the FirewallRules API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "FirewallRules")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// SetFirewallRule sets the CIDRs allowed to reach the given well
+// known service.
+func (c *Client) SetFirewallRule(service string, whitelistCIDRs []string) error {
+	args := params.FirewallRuleArgs{
+		Args: []params.FirewallRule{{
+			KnownService:   service,
+			WhitelistCIDRS: whitelistCIDRs,
+		}},
+	}
+	var result params.ErrorResults
+	if err := c.facade.FacadeCall("SetFirewallRules", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// ListFirewallRules returns all of the firewall rules stored on the
+// controller.
+func (c *Client) ListFirewallRules() ([]params.FirewallRule, error) {
+	var result params.ListFirewallRulesResults
+	if err := c.facade.FacadeCall("ListFirewallRules", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Rules, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewallrules_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestSetFirewallRule(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "FirewallRules")
+		c.Check(request, gc.Equals, "SetFirewallRules")
+		c.Check(arg, jc.DeepEquals, params.FirewallRuleArgs{
+			Args: []params.FirewallRule{{
+				KnownService:   "ssh",
+				WhitelistCIDRS: []string{"10.0.0.0/8"},
+			}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	err := firewallrules.NewClient(apiCaller).SetFirewallRule("ssh", []string{"10.0.0.0/8"})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *clientSuite) TestListFirewallRules(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "FirewallRules")
+		c.Check(request, gc.Equals, "ListFirewallRules")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.ListFirewallRulesResults)) = params.ListFirewallRulesResults{
+			Rules: []params.FirewallRule{{
+				KnownService:   "juju-controller",
+				WhitelistCIDRS: []string{"192.168.1.0/24"},
+			}},
+		}
+		return nil
+	})
+	rules, err := firewallrules.NewClient(apiCaller).ListFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []params.FirewallRule{{
+		KnownService:   "juju-controller",
+		WhitelistCIDRS: []string{"192.168.1.0/24"},
+	}})
+}
@@ -40,6 +40,13 @@ type Info struct {
 	// Addrs holds the addresses of the controllers.
 	Addrs []string
 
+	// SRVName optionally holds the DNS name under which the
+	// controller publishes SRV records for its API servers. If it
+	// is set, the records are looked up when dialing and the
+	// addresses they name are tried before those in Addrs, which
+	// may then be empty.
+	SRVName string `yaml:",omitempty"`
+
 	// SNIHostName optionally holds the host name to use for
 	// server name indication (SNI) when connecting
 	// to the addresses in Addrs above. If CACert is non-empty,
@@ -97,7 +104,7 @@ func (info *Info) Ports() []int {
 
 // Validate validates the API info.
 func (info *Info) Validate() error {
-	if len(info.Addrs) == 0 {
+	if len(info.Addrs) == 0 && info.SRVName == "" {
 		return errors.NotValidf("missing addresses")
 	}
 	if _, err := network.ParseHostPorts(info.Addrs...); err != nil {
@@ -165,6 +172,10 @@ type DialOpts struct {
 	// If it is nil, net.DefaultResolver will be used.
 	IPAddrResolver IPAddrResolver
 
+	// SRVResolver is used to look up the SRV records named by
+	// Info.SRVName. If it is nil, net.DefaultResolver will be used.
+	SRVResolver SRVResolver
+
 	// DNSCache is consulted to find and store cached DNS lookups.
 	// If it is nil, no cache will be used or updated.
 	DNSCache DNSCache
@@ -181,6 +192,12 @@ type IPAddrResolver interface {
 	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
 }
 
+// SRVResolver implements a lookup of DNS SRV records. It is notably
+// implemented by net.Resolver.
+type SRVResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (cname string, addrs []*net.SRV, err error)
+}
+
 // DNSCache implements a cache of DNS lookup results.
 type DNSCache interface {
 	// Lookup returns the IP addresses associated
@@ -0,0 +1,78 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package loglevels provides access to the log levels of the loggo
+// modules on the controller and on individual agents.
+package loglevels
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the LogLevels API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the LogLevels API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "LogLevels")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ControllerLoggingConfig returns the current log levels of the loggo
+// modules in the controller's API server.
+func (c *Client) ControllerLoggingConfig() (string, error) {
+	var result params.StringResult
+	if err := c.facade.FacadeCall("ControllerLoggingConfig", nil, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// SetControllerLoggingConfig changes the log levels of the loggo
+// modules in the controller's API server, until it is restarted.
+func (c *Client) SetControllerLoggingConfig(config string) error {
+	args := params.LoggingConfig{Config: config}
+	return errors.Trace(c.facade.FacadeCall("SetControllerLoggingConfig", args, nil))
+}
+
+// AgentLoggingConfig returns the logging configuration that the given
+// agent applies on top of the model's logging-config.
+func (c *Client) AgentLoggingConfig(agent names.Tag) (string, error) {
+	args := params.Entities{Entities: []params.Entity{{Tag: agent.String()}}}
+	var results params.StringResults
+	if err := c.facade.FacadeCall("AgentLoggingConfig", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return "", err
+	}
+	return results.Results[0].Result, nil
+}
+
+// SetAgentLoggingConfig sets the logging configuration that the given
+// agent applies on top of the model's logging-config. The agent applies
+// the change without restarting; an empty configuration returns it to
+// the model's logging-config.
+func (c *Client) SetAgentLoggingConfig(agent names.Tag, config string) error {
+	args := params.AgentLoggingConfigs{
+		Configs: []params.AgentLoggingConfig{{Tag: agent.String(), Config: config}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetAgentLoggingConfig", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
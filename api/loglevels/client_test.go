@@ -0,0 +1,75 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loglevels_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/loglevels"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestControllerLoggingConfig(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "LogLevels")
+		c.Check(request, gc.Equals, "ControllerLoggingConfig")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.StringResult)) = params.StringResult{Result: "<root>=WARNING"}
+		return nil
+	})
+	config, err := loglevels.NewClient(apiCaller).ControllerLoggingConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.Equals, "<root>=WARNING")
+}
+
+func (s *clientSuite) TestSetControllerLoggingConfig(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "SetControllerLoggingConfig")
+		c.Check(arg, jc.DeepEquals, params.LoggingConfig{Config: "juju.apiserver=TRACE"})
+		return nil
+	})
+	err := loglevels.NewClient(apiCaller).SetControllerLoggingConfig("juju.apiserver=TRACE")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestAgentLoggingConfig(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "AgentLoggingConfig")
+		c.Check(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "unit-mysql-0"}},
+		})
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{Result: "unit=TRACE"}},
+		}
+		return nil
+	})
+	config, err := loglevels.NewClient(apiCaller).AgentLoggingConfig(names.NewUnitTag("mysql/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.Equals, "unit=TRACE")
+}
+
+func (s *clientSuite) TestSetAgentLoggingConfig(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "SetAgentLoggingConfig")
+		c.Check(arg, jc.DeepEquals, params.AgentLoggingConfigs{
+			Configs: []params.AgentLoggingConfig{{Tag: "machine-0", Config: "juju=DEBUG"}},
+		})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	err := loglevels.NewClient(apiCaller).SetAgentLoggingConfig(names.NewMachineTag("0"), "juju=DEBUG")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
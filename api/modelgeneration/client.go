@@ -0,0 +1,88 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelgeneration provides access to the branches of a model,
+// against which charm config changes are staged for a subset of units.
+package modelgeneration
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the ModelGeneration API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the ModelGeneration
+// API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ModelGeneration")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddBranch creates a new, empty branch of the model.
+func (c *Client) AddBranch(branchName string) error {
+	args := params.BranchArg{BranchName: branchName}
+	return errors.Trace(c.facade.FacadeCall("AddBranch", args, nil))
+}
+
+// TrackBranch makes the given units, or all units of the given
+// applications, track the branch.
+func (c *Client) TrackBranch(branchName string, entities []names.Tag) error {
+	args := params.BranchTrackArg{
+		BranchName: branchName,
+		Entities:   make([]params.Entity, len(entities)),
+	}
+	for i, tag := range entities {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("TrackBranch", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.Combine()
+}
+
+// SetBranchConfig stages changes to the application's charm config on
+// the branch. The options are parsed according to the charm's config;
+// the unset settings are reset to the charm defaults when the branch
+// is committed.
+func (c *Client) SetBranchConfig(branchName, appName string, options map[string]string, unset []string) error {
+	args := params.BranchConfigArg{
+		BranchName:      branchName,
+		ApplicationName: appName,
+		Options:         options,
+		Unset:           unset,
+	}
+	return errors.Trace(c.facade.FacadeCall("SetBranchConfig", args, nil))
+}
+
+// CommitBranch applies the config changes staged on the branch to all
+// units, and completes the branch.
+func (c *Client) CommitBranch(branchName string) error {
+	args := params.BranchArg{BranchName: branchName}
+	return errors.Trace(c.facade.FacadeCall("CommitBranch", args, nil))
+}
+
+// AbortBranch completes the branch without applying the config changes
+// staged on it.
+func (c *Client) AbortBranch(branchName string) error {
+	args := params.BranchArg{BranchName: branchName}
+	return errors.Trace(c.facade.FacadeCall("AbortBranch", args, nil))
+}
+
+// Branches returns the branches of the model that are in progress,
+// ordered by name.
+func (c *Client) Branches() ([]params.Branch, error) {
+	var result params.BranchResults
+	if err := c.facade.FacadeCall("Branches", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Branches, nil
+}
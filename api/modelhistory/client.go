@@ -0,0 +1,44 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelhistory provides access to the history of significant
+// events in a model.
+package modelhistory
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the ModelHistory API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the ModelHistory API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ModelHistory")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// History returns the significant events in the model's history since
+// the given time, oldest first. If limit is positive, only the most
+// recent limit events are returned; if kinds is not empty, only events
+// of those kinds are returned.
+func (c *Client) History(since time.Time, limit int, kinds []string) (params.ModelEvents, error) {
+	args := params.ModelHistoryArgs{
+		Since: since,
+		Limit: limit,
+		Kinds: kinds,
+	}
+	var result params.ModelEvents
+	if err := c.facade.FacadeCall("History", args, &result); err != nil {
+		return params.ModelEvents{}, errors.Trace(err)
+	}
+	return result, nil
+}
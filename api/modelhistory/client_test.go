@@ -0,0 +1,64 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelhistory_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/modelhistory"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestHistory(c *gc.C) {
+	since := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := []params.ModelEvent{{
+		Timestamp: since.Add(time.Minute),
+		Kind:      "deploy",
+		Entity:    testing.ModelTag.String(),
+		Origin:    "user-bob",
+		Summary:   "Application.Deploy",
+	}}
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ModelHistory")
+		c.Check(request, gc.Equals, "History")
+		c.Check(arg, jc.DeepEquals, params.ModelHistoryArgs{
+			Since: since,
+			Limit: 10,
+			Kinds: []string{"deploy"},
+		})
+		*(result.(*params.ModelEvents)) = params.ModelEvents{
+			Events:           events,
+			AuditingDisabled: true,
+		}
+		return nil
+	})
+	client := modelhistory.NewClient(apiCaller)
+	result, err := client.History(since, 10, []string{"deploy"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ModelEvents{
+		Events:           events,
+		AuditingDisabled: true,
+	})
+}
+
+func (s *clientSuite) TestHistoryError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	client := modelhistory.NewClient(apiCaller)
+	_, err := client.History(time.Now(), 0, nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
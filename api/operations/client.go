@@ -0,0 +1,85 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package operations provides access to operations: groups of tasks,
+// such as the commands enqueued by a single juju run, that may be
+// listed, inspected and cancelled together.
+package operations
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the Operations API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the Operations API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Operations")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Run enqueues an operation running the given commands on the
+// specified machines, units and units of applications.
+func (c *Client) Run(run params.RunParams) (params.OperationResult, error) {
+	var result params.OperationResult
+	err := c.facade.FacadeCall("Run", run, &result)
+	return result, errors.Trace(err)
+}
+
+// RunOnAllMachines enqueues an operation running the given commands on
+// all of the model's machines.
+func (c *Client) RunOnAllMachines(commands string, timeout time.Duration) (params.OperationResult, error) {
+	var result params.OperationResult
+	args := params.RunParams{Commands: commands, Timeout: timeout}
+	err := c.facade.FacadeCall("RunOnAllMachines", args, &result)
+	return result, errors.Trace(err)
+}
+
+// ListOperations returns the operations with tasks matching the query,
+// along with the matching tasks.
+func (c *Client) ListOperations(args params.OperationQueryArgs) ([]params.OperationResult, error) {
+	var results params.OperationResults
+	if err := c.facade.FacadeCall("ListOperations", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// Operations returns the operations with the given ids.
+func (c *Client) Operations(ids []string) ([]params.OperationResult, error) {
+	return c.operationsCall("Operations", ids)
+}
+
+// CancelOperations cancels the pending tasks of the operations with
+// the given ids.
+func (c *Client) CancelOperations(ids []string) ([]params.OperationResult, error) {
+	return c.operationsCall("CancelOperations", ids)
+}
+
+func (c *Client) operationsCall(method string, ids []string) ([]params.OperationResult, error) {
+	var results params.OperationResults
+	args := params.OperationIDs{IDs: ids}
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d results, got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// Tasks returns the tasks with the given action tags.
+func (c *Client) Tasks(arg params.Entities) (params.ActionResults, error) {
+	var results params.ActionResults
+	err := c.facade.FacadeCall("Tasks", arg, &results)
+	return results, errors.Trace(err)
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operations_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/operations"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestRunOnAllMachines(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Operations")
+		c.Check(request, gc.Equals, "RunOnAllMachines")
+		c.Check(arg, jc.DeepEquals, params.RunParams{
+			Commands: "hostname",
+			Timeout:  time.Minute,
+		})
+		*(result.(*params.OperationResult)) = params.OperationResult{
+			OperationID: "1",
+			Summary:     "run hostname",
+		}
+		return nil
+	})
+	client := operations.NewClient(apiCaller)
+	result, err := client.RunOnAllMachines("hostname", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.OperationResult{
+		OperationID: "1",
+		Summary:     "run hostname",
+	})
+}
+
+func (s *clientSuite) TestListOperations(c *gc.C) {
+	query := params.OperationQueryArgs{
+		Units:  []string{"mysql/0"},
+		Status: []string{"running"},
+	}
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "ListOperations")
+		c.Check(arg, jc.DeepEquals, query)
+		*(result.(*params.OperationResults)) = params.OperationResults{
+			Results: []params.OperationResult{{OperationID: "2"}},
+		}
+		return nil
+	})
+	client := operations.NewClient(apiCaller)
+	results, err := client.ListOperations(query)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.OperationResult{{OperationID: "2"}})
+}
+
+func (s *clientSuite) TestCancelOperations(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "CancelOperations")
+		c.Check(arg, jc.DeepEquals, params.OperationIDs{IDs: []string{"1", "2"}})
+		*(result.(*params.OperationResults)) = params.OperationResults{
+			Results: []params.OperationResult{{OperationID: "1"}, {OperationID: "2"}},
+		}
+		return nil
+	})
+	client := operations.NewClient(apiCaller)
+	results, err := client.CancelOperations([]string{"1", "2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+}
+
+func (s *clientSuite) TestOperationsResultCountMismatch(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "Operations")
+		return nil
+	})
+	client := operations.NewClient(apiCaller)
+	_, err := client.Operations([]string{"1"})
+	c.Assert(err, gc.ErrorMatches, "expected 1 results, got 0")
+}
@@ -39,3 +39,19 @@ func (r IPAddrResolverMap) LookupIPAddr(ctx context.Context, host string) ([]net
 	}
 	return ipAddrs, nil
 }
+
+var _ api.SRVResolver = SRVResolverMap(nil)
+
+// SRVResolverMap implements SRVResolver by looking up the records in
+// the map, which maps "_service._proto.name" to the SRV records
+// published there.
+type SRVResolverMap map[string][]*net.SRV
+
+func (r SRVResolverMap) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	cname := "_" + service + "._" + proto + "." + name
+	srvs := r[cname]
+	if len(srvs) == 0 {
+		return "", nil, errors.Errorf("mock resolver cannot resolve %q", cname)
+	}
+	return cname, srvs, nil
+}
@@ -38,4 +38,7 @@ func CreateUnit(st *State, tag names.UnitTag) *Unit {
 	return &Unit{st, tag, params.Alive}
 }
 
-var NewStateV4 = newStateForVersionFn(4)
+var (
+	NewStateV4 = newStateForVersionFn(4)
+	NewStateV6 = newStateForVersionFn(6)
+)
@@ -0,0 +1,57 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// readCache holds the results of conditional reads, along with their
+// versions, so that the controller need not send results the client
+// already holds. Cached values must not be modified; callers return
+// copies of them.
+type readCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedRead
+}
+
+type cachedRead struct {
+	version string
+	value   interface{}
+}
+
+func newReadCache() *readCache {
+	return &readCache{entries: make(map[string]cachedRead)}
+}
+
+// fetchFunc performs a conditional read for a caller holding the given
+// version, which is empty if the caller holds none. It returns the
+// value read and its version, or whether the caller's version is
+// current, in which case no value is returned.
+type fetchFunc func(version string) (value interface{}, newVersion string, notModified bool, err error)
+
+// read returns the value cached under the given key, refreshed by
+// fetch if it has changed, and its version.
+func (c *readCache) read(key string, fetch fetchFunc) (interface{}, string, error) {
+	c.mu.Lock()
+	cached, ok := c.entries[key]
+	c.mu.Unlock()
+
+	value, version, notModified, err := fetch(cached.version)
+	if err != nil {
+		return nil, "", err
+	}
+	if notModified {
+		if !ok || version != cached.version {
+			return nil, "", errors.Errorf("unexpected not modified result for %s", key)
+		}
+		return cached.value, cached.version, nil
+	}
+	c.mu.Lock()
+	c.entries[key] = cachedRead{version: version, value: value}
+	c.mu.Unlock()
+	return value, version, nil
+}
@@ -107,6 +107,29 @@ func (ru *RelationUnit) LeaveScope() error {
 // Settings returns a Settings which allows access to the unit's settings
 // within the relation.
 func (ru *RelationUnit) Settings() (*Settings, error) {
+	if ru.st.BestAPIVersion() < 7 {
+		return ru.settings()
+	}
+	relationTag, unitTag := ru.relation.tag.String(), ru.unit.tag.String()
+	settings, err := ru.st.readRelationSettings("settings "+relationTag+" "+unitTag, func(version string) (params.SettingsResults, error) {
+		var results params.SettingsResults
+		args := params.VersionedRelationUnits{
+			RelationUnits: []params.VersionedRelationUnit{{
+				Relation: relationTag,
+				Unit:     unitTag,
+				Version:  version,
+			}},
+		}
+		err := ru.st.facade.FacadeCall("ConditionalReadSettings", args, &results)
+		return results, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newSettings(ru.st, relationTag, unitTag, settings), nil
+}
+
+func (ru *RelationUnit) settings() (*Settings, error) {
 	var results params.SettingsResults
 	args := params.RelationUnits{
 		RelationUnits: []params.RelationUnit{{
@@ -140,6 +163,23 @@ func (ru *RelationUnit) ReadSettings(uname string) (params.Settings, error) {
 		return nil, errors.Errorf("%q is not a valid unit", uname)
 	}
 	tag := names.NewUnitTag(uname)
+	if ru.st.BestAPIVersion() >= 7 {
+		relationTag, unitTag := ru.relation.tag.String(), ru.unit.tag.String()
+		key := "remote-settings " + relationTag + " " + unitTag + " " + tag.String()
+		return ru.st.readRelationSettings(key, func(version string) (params.SettingsResults, error) {
+			var results params.SettingsResults
+			args := params.VersionedRelationUnitPairs{
+				RelationUnitPairs: []params.VersionedRelationUnitPair{{
+					Relation:   relationTag,
+					LocalUnit:  unitTag,
+					RemoteUnit: tag.String(),
+					Version:    version,
+				}},
+			}
+			err := ru.st.facade.FacadeCall("ConditionalReadRemoteSettings", args, &results)
+			return results, err
+		})
+	}
 	var results params.SettingsResults
 	args := params.RelationUnitPairs{
 		RelationUnitPairs: []params.RelationUnitPair{{
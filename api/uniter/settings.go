@@ -4,6 +4,8 @@
 package uniter
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/params"
 )
 
@@ -74,19 +76,9 @@ func (s *Settings) Delete(key string) {
 // to make sure we update the address (and other settings) correctly,
 // without overwritting.
 func (s *Settings) Write() error {
-	// First make a copy of the map, including deleted keys.
-	settingsCopy := make(params.Settings)
-	for k, v := range s.settings {
-		settingsCopy[k] = v
-	}
-
 	var result params.ErrorResults
 	args := params.RelationUnitsSettings{
-		RelationUnits: []params.RelationUnitSettings{{
-			Relation: s.relationTag,
-			Unit:     s.unitTag,
-			Settings: settingsCopy,
-		}},
+		RelationUnits: []params.RelationUnitSettings{s.changes()},
 	}
 	err := s.st.facade.FacadeCall("UpdateSettings", args, &result)
 	if err != nil {
@@ -94,3 +86,51 @@ func (s *Settings) Write() error {
 	}
 	return result.OneError()
 }
+
+// changes returns the changes to be written back onto s's node.
+func (s *Settings) changes() params.RelationUnitSettings {
+	// Make a copy of the map, including deleted keys.
+	settingsCopy := make(params.Settings)
+	for k, v := range s.settings {
+		settingsCopy[k] = v
+	}
+	return params.RelationUnitSettings{
+		Relation: s.relationTag,
+		Unit:     s.unitTag,
+		Settings: settingsCopy,
+	}
+}
+
+// WriteSettings writes the changes made to each of the given settings
+// in a single API call. If the controller supports it, the changes are
+// written in a single transaction, so that either all of them or none
+// of them are written; otherwise each settings is written in turn, and
+// the first error encountered is returned.
+func (st *State) WriteSettings(settings ...*Settings) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	args := params.RelationUnitsSettings{
+		RelationUnits: make([]params.RelationUnitSettings, len(settings)),
+	}
+	for i, s := range settings {
+		args.RelationUnits[i] = s.changes()
+	}
+	method := "WriteRelationSettings"
+	if st.BestAPIVersion() < 7 {
+		method = "UpdateSettings"
+	}
+	var results params.ErrorResults
+	if err := st.facade.FacadeCall(method, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != len(settings) {
+		return errors.Errorf("expected %d results, got %d", len(settings), len(results.Results))
+	}
+	for i, result := range results.Results {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "relation %q", settings[i].relationTag)
+		}
+	}
+	return nil
+}
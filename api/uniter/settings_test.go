@@ -4,12 +4,15 @@
 package uniter_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type settingsSuite struct {
@@ -133,3 +136,79 @@ func (s *settingsSuite) TestWrite(c *gc.C) {
 		"other": "days",
 	})
 }
+
+type writeSettingsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&writeSettingsSuite{})
+
+func (s *writeSettingsSuite) apiCaller(c *gc.C, expectMethod string, results params.ErrorResults, calls *int) basetesting.APICallerFunc {
+	return basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*calls++
+		c.Check(objType, gc.Equals, "Uniter")
+		c.Check(request, gc.Equals, expectMethod)
+		c.Check(arg, jc.DeepEquals, params.RelationUnitsSettings{
+			RelationUnits: []params.RelationUnitSettings{{
+				Relation: "relation-0",
+				Unit:     "unit-wordpress-0",
+				Settings: params.Settings{"foo": "bar", "gone": ""},
+			}, {
+				Relation: "relation-1",
+				Unit:     "unit-wordpress-0",
+				Settings: params.Settings{"baz": "qux"},
+			}},
+		})
+		*(result.(*params.ErrorResults)) = results
+		return nil
+	})
+}
+
+func (s *writeSettingsSuite) settings(st *uniter.State) []*uniter.Settings {
+	settings0 := uniter.NewSettings(st, "relation-0", "unit-wordpress-0", params.Settings{"gone": "soon"})
+	settings0.Set("foo", "bar")
+	settings0.Delete("gone")
+	settings1 := uniter.NewSettings(st, "relation-1", "unit-wordpress-0", nil)
+	settings1.Set("baz", "qux")
+	return []*uniter.Settings{settings0, settings1}
+}
+
+func (s *writeSettingsSuite) TestWriteSettings(c *gc.C) {
+	var calls int
+	results := params.ErrorResults{Results: []params.ErrorResult{{}, {}}}
+	apiCaller := s.apiCaller(c, "WriteRelationSettings", results, &calls)
+	st := uniter.NewState(apiCaller, names.NewUnitTag("wordpress/0"))
+	err := st.WriteSettings(s.settings(st)...)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 1)
+}
+
+func (s *writeSettingsSuite) TestWriteSettingsError(c *gc.C) {
+	var calls int
+	results := params.ErrorResults{Results: []params.ErrorResult{
+		{}, {Error: &params.Error{Message: "boom"}},
+	}}
+	apiCaller := s.apiCaller(c, "WriteRelationSettings", results, &calls)
+	st := uniter.NewState(apiCaller, names.NewUnitTag("wordpress/0"))
+	err := st.WriteSettings(s.settings(st)...)
+	c.Assert(err, gc.ErrorMatches, `relation "relation-1": boom`)
+}
+
+func (s *writeSettingsSuite) TestWriteSettingsOldFacadeVersion(c *gc.C) {
+	var calls int
+	results := params.ErrorResults{Results: []params.ErrorResult{{}, {}}}
+	apiCaller := s.apiCaller(c, "UpdateSettings", results, &calls)
+	st := uniter.NewStateV6(apiCaller, names.NewUnitTag("wordpress/0"))
+	err := st.WriteSettings(s.settings(st)...)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 1)
+}
+
+func (s *writeSettingsSuite) TestWriteSettingsNone(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("unexpected call")
+	})
+	st := uniter.NewState(apiCaller, names.NewUnitTag("wordpress/0"))
+	err := st.WriteSettings()
+	c.Assert(err, jc.ErrorIsNil)
+}
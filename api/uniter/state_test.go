@@ -37,6 +37,21 @@ func (s *stateSuite) TestProviderType(c *gc.C) {
 	c.Assert(providerType, gc.DeepEquals, cfg.Type())
 }
 
+func (s *stateSuite) TestModelConfigCached(c *gc.C) {
+	cfg, err := s.uniter.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.LoggingConfig(), gc.Not(gc.Equals), "<root>=DEBUG")
+	cfg, err = s.uniter.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.LoggingConfig(), gc.Not(gc.Equals), "<root>=DEBUG")
+
+	err = s.State.UpdateModelConfig(map[string]interface{}{"logging-config": "<root>=DEBUG"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = s.uniter.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.LoggingConfig(), gc.Equals, "<root>=DEBUG")
+}
+
 func (s *stateSuite) TestAllMachinePorts(c *gc.C) {
 	// Verify no ports are opened yet on the machine or unit.
 	machinePorts, err := s.wordpressMachine.AllPorts()
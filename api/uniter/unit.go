@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/api/common"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/watcher"
 )
@@ -94,6 +95,81 @@ func (u *Unit) UnitStatus() (params.StatusResult, error) {
 	return result, nil
 }
 
+// UnitStatusHistory returns the latest size entries of the unit's
+// workload and agent status history, oldest first.
+func (u *Unit) UnitStatusHistory(size int) ([]params.DetailedStatus, error) {
+	if u.st.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("unit status history")
+	}
+	var results params.StatusHistoryResults
+	args := params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:    u.tag.String(),
+			Kind:   status.KindUnit.String(),
+			Filter: params.StatusHistoryFilter{Size: size},
+		}},
+	}
+	err := u.st.facade.FacadeCall("UnitStatusHistory", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.History.Statuses, nil
+}
+
+// CompletedApplicationHooks returns the application hooks of the unit's
+// application that have completed, keyed on hook name, with the URL of
+// the charm each last completed for.
+func (u *Unit) CompletedApplicationHooks() (map[string]string, error) {
+	if u.st.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("application hooks")
+	}
+	var results params.SettingsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("CompletedApplicationHooks", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Settings, nil
+}
+
+// SetApplicationHookCompleted records that the unit, as the leader of
+// its application, has completed the named application hook for the
+// charm with the given URL. It fails if the unit is not the leader.
+func (u *Unit) SetApplicationHookCompleted(name, charmURL string) error {
+	if u.st.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("application hooks")
+	}
+	var results params.ErrorResults
+	args := params.ApplicationHookCompletions{
+		Completions: []params.ApplicationHookCompletion{{
+			Tag:      u.tag.String(),
+			Hook:     name,
+			CharmURL: charmURL,
+		}},
+	}
+	err := u.st.facade.FacadeCall("SetApplicationHooksCompleted", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // SetAgentStatus sets the status of the unit agent.
 func (u *Unit) SetAgentStatus(agentStatus status.Status, info string, data map[string]interface{}) error {
 	var result params.ErrorResults
@@ -215,6 +291,48 @@ func (u *Unit) Application() (*Application, error) {
 // value for the associated option, and may thus be nil when no default is
 // specified.
 func (u *Unit) ConfigSettings() (charm.Settings, error) {
+	settings, _, err := u.ConfigSettingsWithVersion()
+	return settings, err
+}
+
+// ConfigSettingsWithVersion returns the unit's config settings, as
+// ConfigSettings does, along with a version reported by the controller
+// that changes when, and only when, the settings do. The version is
+// empty if the controller does not report one.
+func (u *Unit) ConfigSettingsWithVersion() (charm.Settings, string, error) {
+	if u.st.BestAPIVersion() < 7 {
+		settings, err := u.configSettings()
+		return settings, "", err
+	}
+	value, version, err := u.st.cache.read("config "+u.tag.String(), func(version string) (interface{}, string, bool, error) {
+		var results params.ConfigSettingsResults
+		args := params.VersionedEntities{
+			Entities: []params.VersionedEntity{{Tag: u.tag.String(), Version: version}},
+		}
+		err := u.st.facade.FacadeCall("ConditionalConfigSettings", args, &results)
+		if err != nil {
+			return nil, "", false, err
+		}
+		if len(results.Results) != 1 {
+			return nil, "", false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+		}
+		result := results.Results[0]
+		if result.Error != nil {
+			return nil, "", false, result.Error
+		}
+		return result.Settings, result.Version, result.NotModified, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	settings := make(charm.Settings)
+	for k, v := range value.(params.ConfigSettings) {
+		settings[k] = v
+	}
+	return settings, version, nil
+}
+
+func (u *Unit) configSettings() (charm.Settings, error) {
 	var results params.ConfigSettingsResults
 	args := params.Entities{
 		Entities: []params.Entity{{Tag: u.tag.String()}},
@@ -300,6 +418,72 @@ func (u *Unit) Resolved() (params.ResolvedMode, error) {
 	return result.Mode, nil
 }
 
+// Suspended returns whether the unit has been suspended by an operator.
+// Controllers that predate unit suspension never report a unit as
+// suspended.
+func (u *Unit) Suspended() (bool, error) {
+	if u.st.BestAPIVersion() < 7 {
+		return false, nil
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("Suspended", args, &results)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return false, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
+
+// Secrets returns the secrets stored by the unit's charm.
+func (u *Unit) Secrets() (map[string]string, error) {
+	if u.st.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("unit secrets")
+	}
+	var results params.SettingsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("Secrets", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Settings, nil
+}
+
+// SetSecrets stores the supplied secrets on behalf of the unit's
+// charm, leaving its other secrets untouched. Secrets with empty
+// values are removed.
+func (u *Unit) SetSecrets(secrets map[string]string) error {
+	if u.st.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("unit secrets")
+	}
+	var results params.ErrorResults
+	args := params.UnitsSecrets{
+		Units: []params.UnitSecrets{{Tag: u.tag.String(), Secrets: secrets}},
+	}
+	err := u.st.facade.FacadeCall("SetSecrets", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // AssignedMachine returns the unit's assigned machine tag or an error
 // satisfying params.IsCodeNotAssigned when the unit has no assigned
 // machine..
@@ -489,6 +673,31 @@ func (u *Unit) ClosePorts(protocol string, fromPort, toPort int) error {
 	return result.OneError()
 }
 
+// OpenClosePortRanges closes and then opens the given port ranges for
+// the unit in a single operation: either all of the changes are made,
+// or none are.
+func (u *Unit) OpenClosePortRanges(openRanges, closeRanges []network.PortRange) error {
+	if u.st.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("opening and closing port ranges together")
+	}
+	change := params.EntityPortRangeChanges{Tag: u.tag.String()}
+	for _, pr := range openRanges {
+		change.OpenRanges = append(change.OpenRanges, params.FromNetworkPortRange(pr))
+	}
+	for _, pr := range closeRanges {
+		change.CloseRanges = append(change.CloseRanges, params.FromNetworkPortRange(pr))
+	}
+	var result params.ErrorResults
+	args := params.EntitiesPortRangeChanges{
+		Changes: []params.EntityPortRangeChanges{change},
+	}
+	err := u.st.facade.FacadeCall("OpenClosePortRanges", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 var ErrNoCharmURLSet = errors.New("unit has no charm url set")
 
 // CharmURL returns the charm URL this unit is currently using.
@@ -782,3 +991,27 @@ func (u *Unit) NetworkInfo(bindings []string) (map[string]params.NetworkInfoResu
 
 	return results.Results, nil
 }
+
+// GoalState returns the units and relations that the model intends to
+// exist for the unit's application, along with their current status.
+func (u *Unit) GoalState() (params.GoalState, error) {
+	if u.st.BestAPIVersion() < 7 {
+		return params.GoalState{}, errors.NotSupportedf("goal state")
+	}
+	var results params.GoalStateResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("GoalStates", args, &results)
+	if err != nil {
+		return params.GoalState{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.GoalState{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.GoalState{}, result.Error
+	}
+	return *result.Result, nil
+}
@@ -15,6 +15,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
+	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -322,6 +323,37 @@ func (s *unitSuite) TestResolve(c *gc.C) {
 	c.Assert(mode, gc.Equals, params.ResolvedNone)
 }
 
+func (s *unitSuite) TestSuspended(c *gc.C) {
+	suspended, err := s.apiUnit.Suspended()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(suspended, jc.IsFalse)
+
+	err = s.wordpressUnit.SetSuspended(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	suspended, err = s.apiUnit.Suspended()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(suspended, jc.IsTrue)
+}
+
+func (s *unitSuite) TestSecrets(c *gc.C) {
+	secrets, err := s.apiUnit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 0)
+
+	err = s.apiUnit.SetSecrets(map[string]string{"password": "hunter2", "token": "abc"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.SetSecrets(map[string]string{"token": ""})
+	c.Assert(err, jc.ErrorIsNil)
+
+	secrets, err = s.apiUnit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, jc.DeepEquals, map[string]string{"password": "hunter2"})
+	stateSecrets, err := s.wordpressUnit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateSecrets, jc.DeepEquals, secrets)
+}
+
 func (s *unitSuite) TestAssignedMachine(c *gc.C) {
 	machineTag, err := s.apiUnit.AssignedMachine()
 	c.Assert(err, jc.ErrorIsNil)
@@ -517,6 +549,29 @@ func (s *unitSuite) TestConfigSettings(c *gc.C) {
 	})
 }
 
+func (s *unitSuite) TestConfigSettingsWithVersion(c *gc.C) {
+	err := s.apiUnit.SetCharmURL(s.wordpressCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, version, err := s.apiUnit.ConfigSettingsWithVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Not(gc.Equals), "")
+
+	// The version is unchanged while the settings are.
+	_, again, err := s.apiUnit.ConfigSettingsWithVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, gc.Equals, version)
+
+	err = s.wordpressApplication.UpdateConfigSettings(charm.Settings{
+		"blog-title": "superhero paparazzi",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, changed, err := s.apiUnit.ConfigSettingsWithVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["blog-title"], gc.Equals, "superhero paparazzi")
+	c.Assert(changed, gc.Not(gc.Equals), version)
+}
+
 func (s *unitSuite) TestWatchConfigSettings(c *gc.C) {
 	// Make sure WatchConfigSettings returns an error when
 	// no charm URL is set, as its state counterpart does.
@@ -944,3 +999,160 @@ func (s *unitMetricBatchesSuite) TestSendMetricBatch(c *gc.C) {
 	c.Assert(batches[0].Metrics()[0].Key, gc.Equals, "pings")
 	c.Assert(batches[0].Metrics()[0].Value, gc.Equals, "5")
 }
+
+func (s *unitSuite) TestGoalState(c *gc.C) {
+	now := time.Now()
+	err := s.wordpressUnit.SetStatus(status.StatusInfo{Status: status.Active, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+
+	goalState, err := s.apiUnit.GoalState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(goalState.Units, gc.HasLen, 1)
+	c.Assert(goalState.Units["wordpress/0"].Status, gc.Equals, "active")
+	c.Assert(goalState.Units["wordpress/0"].Since.Equal(now), jc.IsTrue)
+	c.Assert(goalState.Relations, gc.HasLen, 0)
+}
+
+func (s *unitSuite) TestGoalStateOldFacadeVersion(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %s", request)
+		return nil
+	})
+	st := uniter.NewStateV6(apiCaller, names.NewUnitTag("wordpress/0"))
+	unit := uniter.CreateUnit(st, names.NewUnitTag("wordpress/0"))
+	_, err := unit.GoalState()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *unitSuite) TestUnitStatusHistory(c *gc.C) {
+	since := time.Now().Add(time.Minute)
+	err := s.wordpressUnit.SetStatus(status.StatusInfo{Status: status.Active, Message: "ready", Since: &since})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.apiUnit.UnitStatusHistory(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Kind, gc.Equals, "workload")
+	c.Assert(history[0].Status, gc.Equals, "active")
+	c.Assert(history[0].Info, gc.Equals, "ready")
+	c.Assert(history[0].Since.Equal(since), jc.IsTrue)
+}
+
+func (s *unitSuite) TestUnitStatusHistoryOldFacadeVersion(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %s", request)
+		return nil
+	})
+	st := uniter.NewStateV6(apiCaller, names.NewUnitTag("wordpress/0"))
+	unit := uniter.CreateUnit(st, names.NewUnitTag("wordpress/0"))
+	_, err := unit.UnitStatusHistory(10)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *unitSuite) TestApplicationHookCompleted(c *gc.C) {
+	err := s.apiUnit.SetApplicationHookCompleted("migrate", "cs:quantal/wordpress-3")
+	c.Assert(err, gc.ErrorMatches, `cannot record completion of application hook "migrate": .*`)
+
+	err = s.State.LeadershipClaimer().ClaimLeadership("wordpress", "wordpress/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.SetApplicationHookCompleted("migrate", "cs:quantal/wordpress-3")
+	c.Assert(err, jc.ErrorIsNil)
+
+	hooks, err := s.apiUnit.CompletedApplicationHooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hooks, jc.DeepEquals, map[string]string{"migrate": "cs:quantal/wordpress-3"})
+}
+
+func (s *unitSuite) TestApplicationHooksOldFacadeVersion(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %s", request)
+		return nil
+	})
+	st := uniter.NewStateV6(apiCaller, names.NewUnitTag("wordpress/0"))
+	unit := uniter.CreateUnit(st, names.NewUnitTag("wordpress/0"))
+	_, err := unit.CompletedApplicationHooks()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = unit.SetApplicationHookCompleted("migrate", "cs:quantal/wordpress-3")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *unitSuite) TestOpenClosePortRangesTogether(c *gc.C) {
+	err := s.wordpressUnit.OpenPorts("udp", 4321, 5000)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.apiUnit.OpenClosePortRanges(
+		[]network.PortRange{{Protocol: "tcp", FromPort: 1234, ToPort: 1400}},
+		[]network.PortRange{{Protocol: "udp", FromPort: 4321, ToPort: 5000}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ports, err := s.wordpressUnit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, gc.DeepEquals, []network.PortRange{
+		{Protocol: "tcp", FromPort: 1234, ToPort: 1400},
+	})
+}
+
+func (s *unitSuite) TestOpenClosePortRangesOldFacadeVersion(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected API call %s", request)
+		return nil
+	})
+	st := uniter.NewStateV6(apiCaller, names.NewUnitTag("wordpress/0"))
+	unit := uniter.CreateUnit(st, names.NewUnitTag("wordpress/0"))
+	err := unit.OpenClosePortRanges([]network.PortRange{{Protocol: "tcp", FromPort: 80, ToPort: 80}}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *unitSuite) TestConfigSettingsConditional(c *gc.C) {
+	var versions []string
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Uniter")
+		c.Check(request, gc.Equals, "ConditionalConfigSettings")
+		args := arg.(params.VersionedEntities)
+		c.Assert(args.Entities, gc.HasLen, 1)
+		c.Check(args.Entities[0].Tag, gc.Equals, "unit-wordpress-0")
+		versions = append(versions, args.Entities[0].Version)
+		r := result.(*params.ConfigSettingsResults)
+		if args.Entities[0].Version == "v1" {
+			r.Results = []params.ConfigSettingsResult{{Version: "v1", NotModified: true}}
+		} else {
+			r.Results = []params.ConfigSettingsResult{{
+				Settings: params.ConfigSettings{"blog-title": "My Title"},
+				Version:  "v1",
+			}}
+		}
+		return nil
+	})
+	st := uniter.NewState(apiCaller, names.NewUnitTag("wordpress/0"))
+	unit := uniter.CreateUnit(st, names.NewUnitTag("wordpress/0"))
+
+	settings, err := unit.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
+	// Changes to the returned settings do not affect the cache.
+	settings["blog-title"] = "changed"
+
+	settings, err = unit.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
+	c.Assert(versions, jc.DeepEquals, []string{"", "v1"})
+}
+
+func (s *unitSuite) TestConfigSettingsOldFacadeVersion(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(request, gc.Equals, "ConfigSettings")
+		c.Check(arg, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "unit-wordpress-0"}}})
+		*(result.(*params.ConfigSettingsResults)) = params.ConfigSettingsResults{
+			Results: []params.ConfigSettingsResult{{
+				Settings: params.ConfigSettings{"blog-title": "My Title"},
+			}},
+		}
+		return nil
+	})
+	st := uniter.NewStateV6(apiCaller, names.NewUnitTag("wordpress/0"))
+	unit := uniter.CreateUnit(st, names.NewUnitTag("wordpress/0"))
+	settings, err := unit.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/api/common"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/watcher"
 )
@@ -30,6 +31,8 @@ type State struct {
 	facade             base.FacadeCaller
 	// unitTag contains the authenticated unit's tag.
 	unitTag names.UnitTag
+	// cache holds the results of conditional reads.
+	cache *readCache
 }
 
 // newStateForVersion creates a new client-side Uniter facade for the
@@ -50,6 +53,7 @@ func newStateForVersion(
 		StorageAccessor: NewStorageAccessor(facadeCaller),
 		facade:          facadeCaller,
 		unitTag:         authTag,
+		cache:           newReadCache(),
 	}
 
 	newWatcher := func(result params.NotifyWatchResult) watcher.NotifyWatcher {
@@ -69,12 +73,12 @@ func newStateForVersionFn(version int) func(base.APICaller, names.UnitTag) *Stat
 	}
 }
 
-// newStateV6 creates a new client-side Uniter facade, version 6
-var newStateV6 = newStateForVersionFn(6)
+// newStateV7 creates a new client-side Uniter facade, version 7
+var newStateV7 = newStateForVersionFn(7)
 
 // NewState creates a new client-side Uniter facade.
 // Defined like this to allow patching during tests.
-var NewState = newStateV6
+var NewState = newStateV7
 
 // BestAPIVersion returns the API version that we were able to
 // determine is supported by both the client and the API Server.
@@ -87,6 +91,58 @@ func (st *State) Facade() base.FacadeCaller {
 	return st.facade
 }
 
+// ModelConfig returns the current model configuration. Unless the
+// configuration has changed since it was last read, the controller
+// does not send it again.
+func (st *State) ModelConfig() (*config.Config, error) {
+	if st.BestAPIVersion() < 7 {
+		return st.ModelWatcher.ModelConfig()
+	}
+	value, _, err := st.cache.read("model-config", func(version string) (interface{}, string, bool, error) {
+		var result params.ModelConfigResult
+		args := params.ModelConfigVersion{Version: version}
+		if err := st.facade.FacadeCall("ConditionalModelConfig", args, &result); err != nil {
+			return nil, "", false, err
+		}
+		return result.Config, result.Version, result.NotModified, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	attrs := make(map[string]interface{})
+	for k, v := range value.(params.ModelConfig) {
+		attrs[k] = v
+	}
+	return config.New(config.NoDefaults, attrs)
+}
+
+// readRelationSettings returns a copy of the relation settings cached
+// under the given key, refreshed by a conditional read made by call.
+func (st *State) readRelationSettings(key string, call func(version string) (params.SettingsResults, error)) (params.Settings, error) {
+	value, _, err := st.cache.read(key, func(version string) (interface{}, string, bool, error) {
+		results, err := call(version)
+		if err != nil {
+			return nil, "", false, err
+		}
+		if len(results.Results) != 1 {
+			return nil, "", false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+		}
+		result := results.Results[0]
+		if result.Error != nil {
+			return nil, "", false, result.Error
+		}
+		return result.Settings, result.Version, result.NotModified, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	settings := make(params.Settings)
+	for k, v := range value.(params.Settings) {
+		settings[k] = v
+	}
+	return settings, nil
+}
+
 // life requests the lifecycle of the given entity from the server.
 func (st *State) life(tag names.Tag) (params.Life, error) {
 	return common.Life(st.facade, tag)
@@ -276,6 +332,69 @@ func (st *State) ActionFinish(tag names.ActionTag, status string, results map[st
 	return nil
 }
 
+// ActionProgress records an intermediate progress message and
+// completion percentage for a running action.
+func (st *State) ActionProgress(tag names.ActionTag, message string, percent int) error {
+	if st.BestAPIVersion() < 7 {
+		return errors.NotImplementedf("ActionProgress() (need V7+)")
+	}
+	var outcome params.ErrorResults
+
+	args := params.ActionsProgress{
+		Progress: []params.ActionProgress{
+			{
+				ActionTag: tag.String(),
+				Message:   message,
+				Percent:   percent,
+			},
+		},
+	}
+
+	err := st.facade.FacadeCall("SetActionsProgress", args, &outcome)
+	if err != nil {
+		return err
+	}
+	if len(outcome.Results) != 1 {
+		return fmt.Errorf("expected 1 result, got %d", len(outcome.Results))
+	}
+	result := outcome.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// ActionLog appends a timestamped message to the logs of a running
+// action.
+func (st *State) ActionLog(tag names.ActionTag, message string) error {
+	if st.BestAPIVersion() < 7 {
+		return errors.NotImplementedf("ActionLog() (need V7+)")
+	}
+	var outcome params.ErrorResults
+
+	args := params.ActionsMessages{
+		Messages: []params.ActionMessageParams{
+			{
+				ActionTag: tag.String(),
+				Message:   message,
+			},
+		},
+	}
+
+	err := st.facade.FacadeCall("LogActionsMessages", args, &outcome)
+	if err != nil {
+		return err
+	}
+	if len(outcome.Results) != 1 {
+		return fmt.Errorf("expected 1 result, got %d", len(outcome.Results))
+	}
+	result := outcome.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 // RelationById returns the existing relation with the given id.
 func (st *State) RelationById(id int) (*Relation, error) {
 	var results params.RelationResults
@@ -408,3 +527,20 @@ func (st *State) SLALevel() (string, error) {
 	}
 	return result.Result, nil
 }
+
+// CloudSpec returns the cloud that the model is running in. The cloud
+// credential is only included if the unit's application is trusted.
+func (st *State) CloudSpec() (*params.CloudSpec, error) {
+	if st.BestAPIVersion() < 7 {
+		return nil, errors.NotSupportedf("cloud spec")
+	}
+	var result params.CloudSpecResult
+	err := st.facade.FacadeCall("CloudSpec", nil, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := result.Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Result, nil
+}
@@ -120,6 +120,15 @@ func (s *uniterSuite) assertInScope(c *gc.C, relUnit *state.RelationUnit, inScop
 	c.Assert(ok, gc.Equals, inScope)
 }
 
+func (s *uniterSuite) TestCloudSpec(c *gc.C) {
+	spec, err := s.uniter.CloudSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec.Name, gc.Equals, "dummy")
+	c.Assert(spec.Region, gc.Equals, "dummy-region")
+	// The wordpress application is not trusted.
+	c.Assert(spec.Credential, gc.IsNil)
+}
+
 func (s *uniterSuite) TestSLALevel(c *gc.C) {
 	err := s.State.SetSLA("essential", "bob", []byte("creds"))
 	c.Assert(err, jc.ErrorIsNil)
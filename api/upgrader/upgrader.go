@@ -46,6 +46,15 @@ func (st *State) SetVersion(tag string, v version.Binary) error {
 }
 
 func (st *State) DesiredVersion(tag string) (version.Number, error) {
+	v, _, err := st.DesiredVersionHeld(tag)
+	return v, err
+}
+
+// DesiredVersionHeld returns the version the agent with the given tag
+// should be running, and whether it is being held at its current
+// version, in which case the desired version should be checked again
+// later.
+func (st *State) DesiredVersionHeld(tag string) (version.Number, bool, error) {
 	var results params.VersionResults
 	args := params.Entities{
 		Entities: []params.Entity{{Tag: tag}},
@@ -53,21 +62,21 @@ func (st *State) DesiredVersion(tag string) (version.Number, error) {
 	err := st.facade.FacadeCall("DesiredVersion", args, &results)
 	if err != nil {
 		// TODO: Not directly tested
-		return version.Number{}, err
+		return version.Number{}, false, err
 	}
 	if len(results.Results) != 1 {
 		// TODO: Not directly tested
-		return version.Number{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+		return version.Number{}, false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
 	}
 	result := results.Results[0]
 	if err := result.Error; err != nil {
-		return version.Number{}, err
+		return version.Number{}, false, err
 	}
 	if result.Version == nil {
 		// TODO: Not directly tested
-		return version.Number{}, fmt.Errorf("received no error, but got a nil Version")
+		return version.Number{}, false, fmt.Errorf("received no error, but got a nil Version")
 	}
-	return *result.Version, nil
+	return *result.Version, result.Held, nil
 }
 
 // Tools returns the agent tools that should run on the given entity,
@@ -142,3 +142,11 @@ func (s *machineUpgraderSuite) TestDesiredVersion(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(stateVersion, gc.Equals, current.Number)
 }
+
+func (s *machineUpgraderSuite) TestDesiredVersionHeld(c *gc.C) {
+	s.rawMachine.SetAgentVersion(current)
+	stateVersion, held, err := s.st.DesiredVersionHeld(s.rawMachine.Tag().String())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stateVersion, gc.Equals, current.Number)
+	c.Assert(held, jc.IsFalse)
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package upgradesteps implements the client-side API facade used by
+// agents to record the upgrade steps they have completed.
+package upgradesteps
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Facade provides access to the UpgradeSteps API facade.
+type Facade struct {
+	caller base.FacadeCaller
+}
+
+// NewFacade creates a new client-side UpgradeSteps facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{
+		caller: base.NewFacadeCaller(caller, "UpgradeSteps"),
+	}
+}
+
+// UpgradeStepCompleted reports whether the agent with the given tag
+// has recorded the upgrade step as completed.
+func (f *Facade) UpgradeStepCompleted(agentTag string, vers version.Number, description string) (bool, error) {
+	var results params.BoolResults
+	err := f.caller.FacadeCall("UpgradeStepsCompleted", upgradeSteps(agentTag, vers, description), &results)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return false, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return false, err
+	}
+	return results.Results[0].Result, nil
+}
+
+// SetUpgradeStepCompleted records the upgrade step as completed by the
+// agent with the given tag.
+func (f *Facade) SetUpgradeStepCompleted(agentTag string, vers version.Number, description string) error {
+	var results params.ErrorResults
+	err := f.caller.FacadeCall("SetUpgradeStepsCompleted", upgradeSteps(agentTag, vers, description), &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+func upgradeSteps(agentTag string, vers version.Number, description string) params.UpgradeSteps {
+	return params.UpgradeSteps{Steps: []params.UpgradeStep{{
+		Tag:         agentTag,
+		Version:     vers,
+		Description: description,
+	}}}
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradesteps_test
+
+import (
+	"errors"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/upgradesteps"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type facadeSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) TestUpgradeStepCompleted(c *gc.C) {
+	stub := new(testing.Stub)
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "UpgradeSteps")
+		c.Check(id, gc.Equals, "")
+		stub.AddCall(request, args)
+		*response.(*params.BoolResults) = params.BoolResults{
+			Results: []params.BoolResult{{Result: true}},
+		}
+		return nil
+	})
+	facade := upgradesteps.NewFacade(apiCaller)
+
+	done, err := facade.UpgradeStepCompleted("machine-42", version.MustParse("2.3.0"), "step")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsTrue)
+
+	stub.CheckCalls(c, []testing.StubCall{{
+		"UpgradeStepsCompleted", []interface{}{params.UpgradeSteps{
+			Steps: []params.UpgradeStep{{
+				Tag:         "machine-42",
+				Version:     version.MustParse("2.3.0"),
+				Description: "step",
+			}},
+		}},
+	}})
+}
+
+func (s *facadeSuite) TestSetUpgradeStepCompleted(c *gc.C) {
+	stub := new(testing.Stub)
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		c.Check(objType, gc.Equals, "UpgradeSteps")
+		stub.AddCall(request, args)
+		*response.(*params.ErrorResults) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "nope"}}},
+		}
+		return nil
+	})
+	facade := upgradesteps.NewFacade(apiCaller)
+
+	err := facade.SetUpgradeStepCompleted("machine-42", version.MustParse("2.3.0"), "step")
+	c.Assert(err, gc.ErrorMatches, "nope")
+	stub.CheckCallNames(c, "SetUpgradeStepsCompleted")
+}
+
+func (s *facadeSuite) TestCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(
+		objType string, version int,
+		id, request string,
+		args, response interface{},
+	) error {
+		return errors.New("blam")
+	})
+	facade := upgradesteps.NewFacade(apiCaller)
+
+	_, err := facade.UpgradeStepCompleted("machine-42", version.MustParse("2.3.0"), "step")
+	c.Assert(err, gc.ErrorMatches, "blam")
+	err = facade.SetUpgradeStepCompleted("machine-42", version.MustParse("2.3.0"), "step")
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
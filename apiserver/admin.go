@@ -118,6 +118,9 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 	if err != nil {
 		return fail, errors.Trace(err)
 	}
+	if port := a.srv.agentPort(); port != 0 && a.root.agentRequest {
+		hostPorts = common.ReplaceHostPortsPort(hostPorts, port)
+	}
 
 	model, err := a.root.state.Model()
 	if err != nil {
@@ -218,6 +221,9 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := a.srv.checkListenerAccess(a.root.agentRequest, a.root.entity); err != nil {
+		return nil, errors.Trace(err)
+	}
 	a.loggedIn = true
 
 	// TODO(wallyworld) - we can't yet observe anonymous logins as entity must be non-nil
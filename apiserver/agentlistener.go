@@ -0,0 +1,85 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+var (
+	// errAgentsOnly is returned when anything other than an agent
+	// logs in through the agent listener.
+	errAgentsOnly = errors.New("only agents may log in on the agent API port")
+
+	// errUseAgentPort is returned when an agent other than a
+	// controller machine agent logs in through the main listener
+	// while there is an agent listener.
+	errUseAgentPort = errors.New("agents must log in on the agent API port")
+)
+
+// agentRequestKey is the context key that marks requests received
+// through the agent listener.
+type agentRequestKey struct{}
+
+// markAgentRequests returns a handler that marks each request as
+// having been received through the agent listener before passing it
+// to the given handler.
+func markAgentRequests(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), agentRequestKey{}, true)
+		handler.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// isAgentRequest reports whether the request was received through the
+// agent listener.
+func isAgentRequest(req *http.Request) bool {
+	agent, _ := req.Context().Value(agentRequestKey{}).(bool)
+	return agent
+}
+
+// agentPort returns the port of the server's agent listener, or 0 if
+// there is none.
+func (srv *Server) agentPort() int {
+	if srv.agentLis == nil {
+		return 0
+	}
+	if addr, ok := srv.agentLis.Addr().(*net.TCPAddr); ok {
+		return addr.Port
+	}
+	return 0
+}
+
+// checkListenerAccess returns an error if the given entity, which is
+// nil for an anonymous login, may not log in through the listener that
+// received its connection. If the server has no agent listener, all
+// entities may log in through the main listener.
+func (srv *Server) checkListenerAccess(agentRequest bool, entity state.Entity) error {
+	if srv.agentLis == nil {
+		return nil
+	}
+	isAgent := entity != nil && entity.Tag().Kind() != names.UserTagKind
+	if agentRequest {
+		if !isAgent {
+			return errAgentsOnly
+		}
+		return nil
+	}
+	if !isAgent {
+		return nil
+	}
+	// Controller machine agents connect to the API server on
+	// their own machine, which is not subject to network policy.
+	if machine, ok := entity.(*state.Machine); ok && machine.IsManager() {
+		return nil
+	}
+	return errUseAgentPort
+}
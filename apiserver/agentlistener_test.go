@@ -0,0 +1,113 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"fmt"
+	"net"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type agentListenerSuite struct {
+	apiserverBaseSuite
+	srv       *apiserver.Server
+	agentPort int
+}
+
+var _ = gc.Suite(&agentListenerSuite{})
+
+func (s *agentListenerSuite) SetUpTest(c *gc.C) {
+	s.apiserverBaseSuite.SetUpTest(c)
+	agentListener, err := net.Listen("tcp", ":0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.agentPort = agentListener.Addr().(*net.TCPAddr).Port
+	config := s.sampleConfig(c)
+	config.AgentListener = agentListener
+	s.srv = s.newServer(c, config)
+}
+
+func (s *agentListenerSuite) open(c *gc.C, port int, tag names.Tag, password, nonce string) (api.Connection, error) {
+	conn, err := api.Open(&api.Info{
+		Addrs:    []string{fmt.Sprintf("localhost:%d", port)},
+		CACert:   coretesting.CACert,
+		ModelTag: s.State.ModelTag(),
+		Tag:      tag,
+		Password: password,
+		Nonce:    nonce,
+	}, api.DialOpts{})
+	if err == nil {
+		s.AddCleanup(func(*gc.C) { conn.Close() })
+	}
+	return conn, err
+}
+
+func (s *agentListenerSuite) addMachine(c *gc.C, job state.MachineJob) (names.Tag, string) {
+	machine, err := s.State.AddMachine("quantal", job)
+	c.Assert(err, jc.ErrorIsNil)
+	password, err := utils.RandomPassword()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetPassword(password)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProvisioned("foo", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return machine.Tag(), password
+}
+
+func (s *agentListenerSuite) TestAgentLoginOnAgentPort(c *gc.C) {
+	tag, password := s.addMachine(c, state.JobHostUnits)
+	_, err := s.open(c, s.agentPort, tag, password, "fake_nonce")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *agentListenerSuite) TestUserLoginOnAgentPort(c *gc.C) {
+	_, err := s.open(c, s.agentPort, s.Owner, ownerPassword, "")
+	c.Assert(err, gc.ErrorMatches, "only agents may log in on the agent API port")
+}
+
+func (s *agentListenerSuite) TestAgentLoginOnAPIPort(c *gc.C) {
+	tag, password := s.addMachine(c, state.JobHostUnits)
+	_, err := s.open(c, s.srv.Addr().Port, tag, password, "fake_nonce")
+	c.Assert(err, gc.ErrorMatches, "agents must log in on the agent API port")
+}
+
+func (s *agentListenerSuite) TestUserLoginOnAPIPort(c *gc.C) {
+	_, err := s.open(c, s.srv.Addr().Port, s.Owner, ownerPassword, "")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *agentListenerSuite) TestControllerLoginOnBothPorts(c *gc.C) {
+	tag, password := s.addMachine(c, state.JobManageModel)
+	for _, port := range []int{s.agentPort, s.srv.Addr().Port} {
+		_, err := s.open(c, port, tag, password, "fake_nonce")
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *agentListenerSuite) TestAgentLoginServersUseAgentPort(c *gc.C) {
+	hostPorts := network.NewHostPorts(17070, "10.0.0.1")
+	err := s.State.SetAPIHostPorts([][]network.HostPort{hostPorts})
+	c.Assert(err, jc.ErrorIsNil)
+
+	tag, password := s.addMachine(c, state.JobHostUnits)
+	conn, err := s.open(c, s.agentPort, tag, password, "fake_nonce")
+	c.Assert(err, jc.ErrorIsNil)
+	var found bool
+	for _, server := range conn.APIHostPorts() {
+		for _, hp := range server {
+			c.Check(hp.Port, gc.Equals, s.agentPort)
+			found = found || hp.Value == "10.0.0.1"
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/unitassigner"
 	"github.com/juju/juju/apiserver/facades/agent/uniter"
 	"github.com/juju/juju/apiserver/facades/agent/upgrader"
+	"github.com/juju/juju/apiserver/facades/agent/upgradesteps"
 	"github.com/juju/juju/apiserver/facades/client/action"
 	"github.com/juju/juju/apiserver/facades/client/annotations" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/application" // ModelUser Write
@@ -46,14 +47,20 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/client"           // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/cloud"            // ModelUser Read
 	"github.com/juju/juju/apiserver/facades/client/controller"       // ModelUser Admin (although some methods check for read only)
+	"github.com/juju/juju/apiserver/facades/client/debuglogpresets"  // ModelUser Read (changes require controller superuser)
+	"github.com/juju/juju/apiserver/facades/client/firewallrules"    // ModelUser Read (changes require controller superuser)
 	"github.com/juju/juju/apiserver/facades/client/highavailability" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/imagemanager"     // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/imagemetadatamanager"
-	"github.com/juju/juju/apiserver/facades/client/keymanager"     // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/machinemanager" // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/metricsdebug"   // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/modelconfig"    // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/modelmanager"   // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/keymanager"      // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/loglevels"       // ModelUser Admin (controller levels require superuser)
+	"github.com/juju/juju/apiserver/facades/client/machinemanager"  // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/metricsdebug"    // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/modelconfig"     // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/modelgeneration" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/modelhistory"    // ModelUser Read
+	"github.com/juju/juju/apiserver/facades/client/modelmanager"    // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/operations"      // ModelUser Read (running commands requires admin)
 	"github.com/juju/juju/apiserver/facades/client/payloads"
 	"github.com/juju/juju/apiserver/facades/client/resources"
 	"github.com/juju/juju/apiserver/facades/client/spaces"    // ModelUser Write
@@ -117,6 +124,7 @@ func AllFacades() *facade.Registry {
 	reg("Action", 2, action.NewActionAPI)
 	reg("ActionPruner", 1, actionpruner.NewAPI)
 	reg("Agent", 2, agent.NewAgentAPIV2)
+	reg("Agent", 3, agent.NewAgentAPIV3)
 	reg("AgentTools", 1, agenttools.NewFacade)
 	reg("Annotations", 2, annotations.NewAPI)
 
@@ -125,6 +133,7 @@ func AllFacades() *facade.Registry {
 	reg("Application", 3, application.NewFacade)
 	reg("Application", 4, application.NewFacade)
 	reg("Application", 5, application.NewFacade) // adds AttachStorage
+	reg("Application", 6, application.NewFacade) // adds SetApplicationsConfig, SetTrust
 
 	reg("ApplicationScaler", 1, applicationscaler.NewAPI)
 	reg("Backups", 1, backups.NewFacade)
@@ -133,7 +142,8 @@ func AllFacades() *facade.Registry {
 	reg("CharmRevisionUpdater", 2, charmrevisionupdater.NewCharmRevisionUpdaterAPI)
 	reg("Charms", 2, charms.NewFacade)
 	reg("Cleaner", 2, cleaner.NewCleanerAPI)
-	reg("Client", 1, client.NewFacade)
+	reg("Client", 1, client.NewFacadeV1)
+	reg("Client", 2, client.NewFacade) // adds SuspendUnits, ResumeUnits and AddStatusAnnotations
 	reg("Cloud", 1, cloud.NewFacade)
 	if featureflag.Enabled(feature.CAAS) {
 		reg("Cloud", 2, cloud.NewFacadeV2)
@@ -141,9 +151,14 @@ func AllFacades() *facade.Registry {
 
 	reg("Controller", 3, controller.NewControllerAPIv3)
 	reg("Controller", 4, controller.NewControllerAPIv4)
+	reg("Controller", 5, controller.NewControllerAPIv5)
+	reg("Controller", 6, controller.NewControllerAPIv6) // adds UpgradeDryRun
+
+	reg("DebugLogPresets", 1, debuglogpresets.NewFacade)
 
 	reg("Deployer", 1, deployer.NewDeployerAPI)
-	reg("DiskManager", 2, diskmanager.NewDiskManagerAPI)
+	reg("DiskManager", 2, diskmanager.NewDiskManagerAPIV2)
+	reg("DiskManager", 3, diskmanager.NewDiskManagerAPI)
 	reg("Firewaller", 3, firewaller.NewStateFirewallerAPIV3)
 	reg("HighAvailability", 2, highavailability.NewHighAvailabilityAPI)
 	reg("HostKeyReporter", 1, hostkeyreporter.NewFacade)
@@ -161,6 +176,7 @@ func AllFacades() *facade.Registry {
 	reg("LifeFlag", 1, lifeflag.NewExternalFacade)
 	reg("Logger", 1, loggerapi.NewLoggerAPI)
 	reg("LogForwarding", 1, logfwd.NewFacade)
+	reg("LogLevels", 1, loglevels.NewFacade)
 	reg("MachineActions", 1, machineactions.NewExternalFacade)
 
 	reg("MachineManager", 2, machinemanager.NewMachineManagerAPI)
@@ -180,11 +196,15 @@ func AllFacades() *facade.Registry {
 	reg("MigrationTarget", 1, migrationtarget.NewFacade)
 
 	reg("ModelConfig", 1, modelconfig.NewFacade)
+	reg("ModelGeneration", 1, modelgeneration.NewFacade)
+	reg("ModelHistory", 1, modelhistory.NewFacade)
 	reg("ModelManager", 2, modelmanager.NewFacadeV2)
 	reg("ModelManager", 3, modelmanager.NewFacadeV3)
 	reg("ModelManager", 4, modelmanager.NewFacadeV4)
 	reg("ModelUpgrader", 1, modelupgrader.NewStateFacade)
 
+	reg("Operations", 1, operations.NewAPI)
+
 	reg("Payloads", 1, payloads.NewFacade)
 	regHookContext(
 		"PayloadsHookContext", 1,
@@ -227,8 +247,10 @@ func AllFacades() *facade.Registry {
 
 	reg("Uniter", 4, uniter.NewUniterAPIV4)
 	reg("Uniter", 5, uniter.NewUniterAPIV5)
-	reg("Uniter", 6, uniter.NewUniterAPI)
+	reg("Uniter", 6, uniter.NewUniterAPIV6)
+	reg("Uniter", 7, uniter.NewUniterAPI) // adds WriteRelationSettings, GoalStates and more; see UniterAPIV6
 
+	reg("UpgradeSteps", 1, upgradesteps.NewFacade)
 	reg("Upgrader", 1, upgrader.NewUpgraderFacade)
 	reg("UserManager", 1, usermanager.NewUserManagerAPI)
 
@@ -237,6 +259,8 @@ func AllFacades() *facade.Registry {
 		reg("RemoteRelations", 1, remoterelations.NewStateRemoteRelationsAPI)
 		reg("CrossModelRelations", 1, crossmodelrelations.NewStateCrossModelRelationsAPI)
 		reg("Firewaller", 4, firewaller.NewStateFirewallerAPIV4)
+		reg("Firewaller", 5, firewaller.NewStateFirewallerAPIV5) // adds firewall rules
+		reg("FirewallRules", 1, firewallrules.NewFacade)
 	}
 
 	regRaw("AllWatcher", 1, NewAllWatcher, reflect.TypeOf((*SrvAllWatcher)(nil)))
@@ -74,6 +74,7 @@ type Server struct {
 	wg                     sync.WaitGroup
 	statePool              *state.StatePool
 	lis                    net.Listener
+	agentLis               net.Listener
 	tag                    names.Tag
 	dataDir                string
 	logDir                 string
@@ -93,6 +94,7 @@ type Server struct {
 	certChanged            <-chan params.StateServingInfo
 	tlsConfig              *tls.Config
 	allowModelAccess       bool
+	cors                   *corsPolicy
 	logSinkWriter          io.WriteCloser
 	logsinkRateLimitConfig logsink.RateLimitConfig
 	dbloggers              dbloggers
@@ -153,6 +155,12 @@ type ServerConfig struct {
 	// they don't have access to the controller.
 	AllowModelAccess bool
 
+	// CORSAllowedOrigins holds the browser origins, other than the
+	// controller's own, that may make cross-origin requests to the
+	// API server's HTTP endpoints. An origin of "*" allows all
+	// origins, but without credentials.
+	CORSAllowedOrigins []string
+
 	// NewObserver is a function which will return an observer. This
 	// is used per-connection to instantiate a new observer to be
 	// notified of key events during API requests.
@@ -175,6 +183,13 @@ type ServerConfig struct {
 
 	// PrometheusRegisterer registers Prometheus collectors.
 	PrometheusRegisterer prometheus.Registerer
+
+	// AgentListener, if non-nil, is a listener on which the server
+	// accepts connections from agents only. While there is an agent
+	// listener, only users and controller machine agents may log in
+	// through the main listener. The server closes the listener
+	// when it exits.
+	AgentListener net.Listener
 }
 
 // Validate validates the API server configuration.
@@ -196,6 +211,9 @@ func (c ServerConfig) Validate() error {
 			return errors.Annotate(err, "validating logsink configuration")
 		}
 	}
+	if _, err := newCORSPolicy(c.CORSAllowedOrigins); err != nil {
+		return errors.Annotate(err, "validating CORS allowed origins")
+	}
 	return nil
 }
 
@@ -337,6 +355,9 @@ func NewServer(stPool *state.StatePool, lis net.Listener, cfg ServerConfig) (*Se
 	if err != nil {
 		// There is no running server around to close the listener.
 		lis.Close()
+		if cfg.AgentListener != nil {
+			cfg.AgentListener.Close()
+		}
 		return nil, errors.Trace(err)
 	}
 	return srv, nil
@@ -376,9 +397,18 @@ func newServer(stPool *state.StatePool, lis net.Listener, cfg ServerConfig) (_ *
 		},
 	}
 
+	srv.cors, err = newCORSPolicy(cfg.CORSAllowedOrigins)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	srv.tlsConfig = srv.newTLSConfig(cfg)
 	srv.lis = newThrottlingListener(
 		tls.NewListener(lis, srv.tlsConfig), cfg.RateLimitConfig, clock.WallClock)
+	if cfg.AgentListener != nil {
+		srv.agentLis = newThrottlingListener(
+			tls.NewListener(cfg.AgentListener, srv.tlsConfig), cfg.RateLimitConfig, clock.WallClock)
+	}
 
 	// The auth context for authenticating logins.
 	srv.loginAuthCtxt, err = newAuthContext(stPool.SystemState())
@@ -434,6 +464,10 @@ func (a *metricAdaptor) ConnectionPauseTime() time.Duration {
 	return a.srv.lis.(*throttlingListener).pauseTime()
 }
 
+func (a *metricAdaptor) AllWatcherResyncs() int64 {
+	return state.MultiwatcherResyncCount()
+}
+
 func (srv *Server) newTLSConfig(cfg ServerConfig) *tls.Config {
 	tlsConfig := utils.SecureTLSConfig()
 	if cfg.AutocertDNSName == "" {
@@ -532,6 +566,11 @@ func (srv *Server) run() {
 		addr := srv.lis.Addr().String() // Addr not valid after close
 		err := srv.lis.Close()
 		logger.Infof("closed listening socket %q with final error: %v", addr, err)
+		if srv.agentLis != nil {
+			addr := srv.agentLis.Addr().String()
+			err := srv.agentLis.Close()
+			logger.Infof("closed agent listening socket %q with final error: %v", addr, err)
+		}
 
 		// Break deadlocks caused by leadership BlockUntil... calls.
 		srv.statePool.KillWorkers()
@@ -575,30 +614,41 @@ func (srv *Server) run() {
 		registerEndpoint(endpoint, mux)
 	}
 
-	go func() {
-		logger.Debugf("Starting API http server on address %q", srv.lis.Addr())
-		httpSrv := &http.Server{
-			Handler:   mux,
-			TLSConfig: srv.tlsConfig,
-			ErrorLog: log.New(&loggoWrapper{
-				level:  loggo.WARNING,
-				logger: logger,
-			}, "", 0), // no prefix and no flags so log.Logger doesn't add extra prefixes
-		}
-		err := httpSrv.Serve(srv.lis)
-		// Normally logging an error at debug level would be grounds for a beating,
-		// however in this case the error is *expected* to be non nil, and does not
-		// affect the operation of the apiserver, but for completeness log it anyway.
-		logger.Debugf("API http server exited, final error was: %v", err)
-	}()
+	go srv.serveHTTP(srv.lis, mux)
+	if srv.agentLis != nil {
+		logger.Infof("listening for agents on %q", srv.agentLis.Addr())
+		go srv.serveHTTP(srv.agentLis, markAgentRequests(mux))
+	}
 
 	<-srv.tomb.Dying()
 }
 
+// serveHTTP serves the API's HTTP endpoints on the given listener
+// until it is closed.
+func (srv *Server) serveHTTP(lis net.Listener, handler http.Handler) {
+	logger.Debugf("Starting API http server on address %q", lis.Addr())
+	httpSrv := &http.Server{
+		Handler:   handler,
+		TLSConfig: srv.tlsConfig,
+		ErrorLog: log.New(&loggoWrapper{
+			level:  loggo.WARNING,
+			logger: logger,
+		}, "", 0), // no prefix and no flags so log.Logger doesn't add extra prefixes
+	}
+	err := httpSrv.Serve(lis)
+	// Normally logging an error at debug level would be grounds for a beating,
+	// however in this case the error is *expected* to be non nil, and does not
+	// affect the operation of the apiserver, but for completeness log it anyway.
+	logger.Debugf("API http server exited, final error was: %v", err)
+}
+
 func (srv *Server) endpoints() []apihttp.Endpoint {
 	var endpoints []apihttp.Endpoint
 
 	add := func(pattern string, handler http.Handler) {
+		// Let browser clients served from allowed origins read
+		// the responses.
+		handler = srv.cors.wrap(handler)
 		// TODO: We can switch from all methods to specific ones for entries
 		// where we only want to support specific request methods. However, our
 		// tests currently assert that errors come back as application/json and
@@ -632,6 +682,9 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 	add("/model/:modeluuid/pubsub", pubsubHandler)
 	add("/model/:modeluuid/logstream", logStreamHandler)
 	add("/model/:modeluuid/log", debugLogHandler)
+	add("/model/:modeluuid/log-archive", srv.trackRequests(&logArchiveHandler{
+		ctxt: httpCtxt,
+	}))
 
 	logSinkHandler := logsink.NewHTTPHandler(
 		newAgentLogWriteCloserFunc(httpCtxt, srv.logSinkWriter, &srv.dbloggers),
@@ -730,9 +783,11 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 			stateAuthFunc: httpCtxt.stateForMigrationImporting,
 		},
 	)
+	toolsDeltas := newToolsDeltaCache()
 	add("/model/:modeluuid/tools/:version",
 		&toolsDownloadHandler{
-			ctxt: httpCtxt,
+			ctxt:   httpCtxt,
+			deltas: toolsDeltas,
 		},
 	)
 	add("/model/:modeluuid/backups",
@@ -763,7 +818,8 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 	)
 	add("/tools/:version",
 		&toolsDownloadHandler{
-			ctxt: httpCtxt,
+			ctxt:   httpCtxt,
+			deltas: toolsDeltas,
 		},
 	)
 	add("/register",
@@ -900,13 +956,13 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 	websocket.Serve(w, req, func(conn *websocket.Conn) {
 		modelUUID := req.URL.Query().Get(":modeluuid")
 		logger.Tracef("got a request for model %q", modelUUID)
-		if err := srv.serveConn(conn, modelUUID, apiObserver, req.Host); err != nil {
+		if err := srv.serveConn(conn, modelUUID, apiObserver, req.Host, isAgentRequest(req)); err != nil {
 			logger.Errorf("error serving RPCs: %v", err)
 		}
 	})
 }
 
-func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserver observer.Observer, host string) error {
+func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserver observer.Observer, host string, agentRequest bool) error {
 	codec := jsoncodec.NewWebsocket(wsConn.Conn)
 	conn := rpc.NewConn(codec, apiObserver)
 
@@ -930,6 +986,9 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserv
 		defer releaser()
 		h, err = newAPIHandler(srv, st, conn, modelUUID, host)
 	}
+	if err == nil {
+		h.agentRequest = agentRequest
+	}
 
 	if err != nil {
 		conn.ServeRoot(&errRoot{errors.Trace(err)}, serverError)
@@ -19,6 +19,7 @@ type ServerMetricsSource interface {
 	ConnectionCount() int64
 	ConcurrentLoginAttempts() int64
 	ConnectionPauseTime() time.Duration
+	AllWatcherResyncs() int64
 }
 
 // Collector is a prometheus.Collector that collects metrics based
@@ -30,6 +31,7 @@ type Collector struct {
 	connectionCountGauge     prometheus.Gauge
 	connectionPauseTimeGauge prometheus.Gauge
 	concurrentLoginsGauge    prometheus.Gauge
+	allWatcherResyncCounter  prometheus.Counter
 }
 
 // NewMetricsCollector returns a new Collector.
@@ -56,6 +58,11 @@ func NewMetricsCollector(src ServerMetricsSource) *Collector {
 			Name:      "active_login_attempts",
 			Help:      "Current number of active agent login attempts",
 		}),
+		allWatcherResyncCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: apiserverMetricsNamespace,
+			Name:      "allwatcher_resyncs_total",
+			Help:      "Total number of all-watchers dropped because their clients fell behind",
+		}),
 	}
 }
 
@@ -65,6 +72,7 @@ func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	c.connectionCountGauge.Describe(ch)
 	c.connectionPauseTimeGauge.Describe(ch)
 	c.concurrentLoginsGauge.Describe(ch)
+	c.allWatcherResyncCounter.Describe(ch)
 }
 
 // Collect is part of the prometheus.Collector interface.
@@ -81,4 +89,9 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	c.connectionCountGauge.Collect(ch)
 	c.connectionPauseTimeGauge.Collect(ch)
 	c.concurrentLoginsGauge.Collect(ch)
+	ch <- prometheus.MustNewConstMetric(
+		c.allWatcherResyncCounter.Desc(),
+		prometheus.CounterValue,
+		float64(c.src.AllWatcherResyncs()),
+	)
 }
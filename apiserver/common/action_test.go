@@ -185,6 +185,52 @@ func (s *actionsSuite) TestFinishActions(c *gc.C) {
 	})
 }
 
+func (s *actionsSuite) TestSetActionsProgress(c *gc.C) {
+	args := params.ActionsProgress{
+		[]params.ActionProgress{
+			{ActionTag: "success", Message: "halfway", Percent: 50},
+			{ActionTag: "notfound"},
+			{ActionTag: "progressFail", Message: "late", Percent: 90},
+		},
+	}
+	expectErr := errors.New("explosivo")
+	actionFn := makeGetActionByTagString(map[string]state.Action{
+		"success":      fakeAction{},
+		"progressFail": fakeAction{progressErr: expectErr},
+	})
+	results := common.SetActionsProgress(args, actionFn)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		[]params.ErrorResult{
+			{},
+			{common.ServerError(actionNotFoundErr)},
+			{common.ServerError(expectErr)},
+		},
+	})
+}
+
+func (s *actionsSuite) TestLogActionsMessages(c *gc.C) {
+	args := params.ActionsMessages{
+		[]params.ActionMessageParams{
+			{ActionTag: "success", Message: "copying files"},
+			{ActionTag: "notfound"},
+			{ActionTag: "logFail", Message: "too late"},
+		},
+	}
+	expectErr := errors.New("explosivo")
+	actionFn := makeGetActionByTagString(map[string]state.Action{
+		"success": fakeAction{},
+		"logFail": fakeAction{logErr: expectErr},
+	})
+	results := common.LogActionsMessages(args, actionFn)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		[]params.ErrorResult{
+			{},
+			{common.ServerError(actionNotFoundErr)},
+			{common.ServerError(expectErr)},
+		},
+	})
+}
+
 func (s *actionsSuite) TestWatchActionNotifications(c *gc.C) {
 	args := entities("invalid-actionreceiver", "machine-1", "machine-2", "machine-3")
 	canAccess := makeCanAccess(map[names.Tag]bool{
@@ -330,11 +376,13 @@ type fakeEntity struct {
 
 type fakeAction struct {
 	state.Action
-	receiver  string
-	name      string
-	beginErr  error
-	finishErr error
-	status    state.ActionStatus
+	receiver    string
+	name        string
+	beginErr    error
+	finishErr   error
+	progressErr error
+	logErr      error
+	status      state.ActionStatus
 }
 
 func (mock fakeAction) Status() state.ActionStatus {
@@ -345,6 +393,14 @@ func (mock fakeAction) Begin() (state.Action, error) {
 	return nil, mock.beginErr
 }
 
+func (mock fakeAction) SetProgress(string, int) error {
+	return mock.progressErr
+}
+
+func (mock fakeAction) Log(string) error {
+	return mock.logErr
+}
+
 func (mock fakeAction) Receiver() string {
 	return mock.receiver
 }
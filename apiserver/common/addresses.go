@@ -4,8 +4,11 @@
 package common
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
@@ -36,6 +39,60 @@ func NewAPIAddresser(getter AddressAndCertGetter, resources facade.Resources) *A
 	}
 }
 
+// AgentAddressAndCertGetter is an AddressAndCertGetter that can also
+// return the controller config, which holds the port on which agents
+// connect to the API server.
+type AgentAddressAndCertGetter interface {
+	AddressAndCertGetter
+	ControllerConfig() (controller.Config, error)
+}
+
+// NewAgentAPIAddresser returns a new APIAddresser for use by agents.
+// If the controller config sets an agent API port, the addresses
+// returned have that port in place of the API port.
+func NewAgentAPIAddresser(getter AgentAddressAndCertGetter, resources facade.Resources) *APIAddresser {
+	return &APIAddresser{
+		getter:    agentAddressGetter{getter},
+		resources: resources,
+	}
+}
+
+// agentAddressGetter returns the API addresses on which agents
+// connect to the API server.
+type agentAddressGetter struct {
+	AgentAddressAndCertGetter
+}
+
+// APIHostPorts is part of the AddressAndCertGetter interface.
+func (g agentAddressGetter) APIHostPorts() ([][]network.HostPort, error) {
+	servers, err := g.AgentAddressAndCertGetter.APIHostPorts()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err := g.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if port := cfg.AgentAPIPort(); port != 0 {
+		servers = ReplaceHostPortsPort(servers, port)
+	}
+	return servers, nil
+}
+
+// ReplaceHostPortsPort returns a copy of the given API server
+// addresses with each port replaced by the given port.
+func ReplaceHostPortsPort(servers [][]network.HostPort, port int) [][]network.HostPort {
+	result := make([][]network.HostPort, len(servers))
+	for i, hostPorts := range servers {
+		result[i] = make([]network.HostPort, len(hostPorts))
+		for j, hp := range hostPorts {
+			hp.Port = port
+			result[i][j] = hp
+		}
+	}
+	return result
+}
+
 // APIHostPorts returns the API server addresses.
 func (api *APIAddresser) APIHostPorts() (params.APIHostPortsResult, error) {
 	servers, err := api.getter.APIHostPorts()
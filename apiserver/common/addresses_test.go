@@ -8,6 +8,8 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 )
@@ -88,6 +90,37 @@ func (s *apiAddresserSuite) TestAPIAddressesPrivateFirst(c *gc.C) {
 	})
 }
 
+func (s *apiAddresserSuite) TestAgentAPIAddresses(c *gc.C) {
+	ctlr, err := network.ParseHostPorts("52.7.1.1:17070", "10.0.2.1:17070")
+	c.Assert(err, jc.ErrorIsNil)
+	fake := &fakeAgentAddresses{
+		fakeAddresses: fakeAddresses{hostPorts: [][]network.HostPort{ctlr}},
+		config:        controller.Config{controller.AgentAPIPort: 17071},
+	}
+	addresser := common.NewAgentAPIAddresser(fake, common.NewResources())
+
+	result, err := addresser.APIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Result, gc.DeepEquals, []string{"10.0.2.1:17071", "52.7.1.1:17071"})
+
+	hostPorts, err := addresser.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	servers := params.NetworkHostsPorts(hostPorts.Servers)
+	c.Check(servers[0][0].Port, gc.Equals, 17071)
+	c.Check(servers[0][1].Port, gc.Equals, 17071)
+
+	// The addresses held in state are unchanged.
+	c.Check(fake.hostPorts[0][0].Port, gc.Equals, 17070)
+}
+
+func (s *apiAddresserSuite) TestAgentAPIAddressesNoAgentPort(c *gc.C) {
+	fake := &fakeAgentAddresses{fakeAddresses: *s.fake}
+	addresser := common.NewAgentAPIAddresser(fake, common.NewResources())
+	result, err := addresser.APIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Result, gc.DeepEquals, []string{"apiaddresses:1", "apiaddresses:2"})
+}
+
 func (s *apiAddresserSuite) TestCACert(c *gc.C) {
 	result := s.addresser.CACert()
 	c.Assert(string(result.Result), gc.Equals, "a cert")
@@ -123,3 +156,15 @@ func (f fakeAddresses) APIHostPorts() ([][]network.HostPort, error) {
 func (fakeAddresses) WatchAPIHostPorts() state.NotifyWatcher {
 	panic("should never be called")
 }
+
+// Verify that AgentAddressAndCertGetter is satisfied by *state.State.
+var _ common.AgentAddressAndCertGetter = (*state.State)(nil)
+
+type fakeAgentAddresses struct {
+	fakeAddresses
+	config controller.Config
+}
+
+func (f *fakeAgentAddresses) ControllerConfig() (controller.Config, error) {
+	return f.config, nil
+}
@@ -63,6 +63,11 @@ var errorTransformTests = []struct {
 	code:       params.CodeDead,
 	status:     http.StatusInternalServerError,
 	helperFunc: params.IsCodeDead,
+}, {
+	err:        state.ErrResyncRequired,
+	code:       params.CodeWatcherResyncRequired,
+	status:     http.StatusInternalServerError,
+	helperFunc: params.IsCodeWatcherResyncRequired,
 }, {
 	err:        txn.ErrExcessiveContention,
 	code:       params.CodeExcessiveContention,
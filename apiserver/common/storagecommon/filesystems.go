@@ -151,6 +151,17 @@ func FilesystemAttachmentInfoFromState(info state.FilesystemAttachmentInfo) para
 	}
 }
 
+// FilesystemUsageFromState converts a state.FilesystemUsage to
+// params.FilesystemUsage.
+func FilesystemUsageFromState(usage state.FilesystemUsage) *params.FilesystemUsage {
+	updated := usage.Updated
+	return &params.FilesystemUsage{
+		UsedBytes:      usage.UsedBytes,
+		AvailableBytes: usage.AvailableBytes,
+		Updated:        &updated,
+	}
+}
+
 // ParseFilesystemAttachmentIds parses the strings, returning machine storage IDs.
 func ParseFilesystemAttachmentIds(stringIds []string) ([]params.MachineStorageId, error) {
 	ids := make([]params.MachineStorageId, len(stringIds))
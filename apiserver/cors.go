@@ -0,0 +1,107 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/controller"
+)
+
+// corsMaxAge is how long browsers may cache the result of a CORS
+// preflight request.
+const corsMaxAge = 10 * time.Minute
+
+// corsPolicy decides which browser origins, other than the
+// controller's own, may make cross-origin requests to the API server,
+// and adds the CORS headers that allow them to do so.
+//
+// Websocket connections are not subject to CORS, so the policy does
+// not prevent other origins from connecting to the API; callers must
+// still authenticate as usual.
+type corsPolicy struct {
+	allowAll bool
+	origins  set.Strings
+}
+
+// newCORSPolicy returns a corsPolicy allowing the specified origins.
+// An origin of "*" allows all origins, but only the origins listed
+// explicitly may make requests with credentials.
+func newCORSPolicy(origins []string) (*corsPolicy, error) {
+	policy := &corsPolicy{origins: set.NewStrings()}
+	for _, origin := range origins {
+		if origin == "*" {
+			policy.allowAll = true
+			continue
+		}
+		normalized, err := controller.NormalizeCORSOrigin(origin)
+		if err != nil {
+			return nil, errors.Annotatef(err, "CORS origin %q", origin)
+		}
+		policy.origins.Add(normalized)
+	}
+	return policy, nil
+}
+
+// listed reports whether the given origin is explicitly allowed.
+func (p *corsPolicy) listed(origin string) bool {
+	normalized, err := controller.NormalizeCORSOrigin(origin)
+	if err != nil {
+		return false
+	}
+	return p.origins.Contains(normalized)
+}
+
+// enabled reports whether any cross-origin requests are allowed.
+func (p *corsPolicy) enabled() bool {
+	return p.allowAll || !p.origins.IsEmpty()
+}
+
+// wrap returns a handler that adds CORS headers to the responses to
+// requests from allowed origins, and answers their preflight requests,
+// before passing requests on to the given handler.
+func (p *corsPolicy) wrap(handler http.Handler) http.Handler {
+	if !p.enabled() {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		origin := req.Header.Get("Origin")
+		if origin == "" {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		switch {
+		case p.listed(origin):
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		case p.allowAll:
+			// Credentials (e.g. macaroon cookies) are never
+			// sent on behalf of arbitrary origins.
+			header.Set("Access-Control-Allow-Origin", "*")
+		default:
+			handler.ServeHTTP(w, req)
+			return
+		}
+		requestMethod := req.Header.Get("Access-Control-Request-Method")
+		if req.Method != "OPTIONS" || requestMethod == "" {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		// This is a preflight request, which we answer ourselves.
+		header.Set("Access-Control-Allow-Methods", strings.Join(defaultHTTPMethods, ", "))
+		if requestHeaders := req.Header.Get("Access-Control-Request-Headers"); requestHeaders != "" {
+			header.Set("Access-Control-Allow-Headers", requestHeaders)
+		}
+		header.Set("Access-Control-Max-Age", strconv.Itoa(int(corsMaxAge/time.Second)))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
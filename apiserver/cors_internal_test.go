@@ -0,0 +1,124 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+)
+
+type corsSuite struct {
+	coretesting.BaseSuite
+	served []string
+}
+
+var _ = gc.Suite(&corsSuite{})
+
+func (s *corsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.served = nil
+}
+
+func (s *corsSuite) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.served = append(s.served, req.Method)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func (s *corsSuite) serve(c *gc.C, origins []string, req *http.Request) *httptest.ResponseRecorder {
+	policy, err := newCORSPolicy(origins)
+	c.Assert(err, jc.ErrorIsNil)
+	w := httptest.NewRecorder()
+	policy.wrap(s.handler()).ServeHTTP(w, req)
+	return w
+}
+
+func newCORSRequest(method, origin string) *http.Request {
+	req := httptest.NewRequest(method, "https://controller.invalid:17070/model/uuid/charms", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	return req
+}
+
+func (s *corsSuite) TestInvalidOrigins(c *gc.C) {
+	for _, origin := range []string{
+		"dashboard.example.com",
+		"ftp://dashboard.example.com",
+		"https://dashboard.example.com/path",
+		"https://user@dashboard.example.com",
+	} {
+		_, err := newCORSPolicy([]string{origin})
+		c.Check(err, gc.ErrorMatches, `CORS origin ".*": expected .*`)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *corsSuite) TestNoOrigins(c *gc.C) {
+	w := s.serve(c, nil, newCORSRequest("GET", "https://dashboard.example.com"))
+	c.Assert(w.Code, gc.Equals, http.StatusOK)
+	c.Assert(w.Header().Get("Access-Control-Allow-Origin"), gc.Equals, "")
+	c.Assert(s.served, jc.DeepEquals, []string{"GET"})
+}
+
+func (s *corsSuite) TestNoOriginHeader(c *gc.C) {
+	w := s.serve(c, []string{"https://dashboard.example.com"}, newCORSRequest("GET", ""))
+	c.Assert(w.Code, gc.Equals, http.StatusOK)
+	c.Assert(w.Header().Get("Access-Control-Allow-Origin"), gc.Equals, "")
+	c.Assert(s.served, jc.DeepEquals, []string{"GET"})
+}
+
+func (s *corsSuite) TestAllowedOrigin(c *gc.C) {
+	w := s.serve(c, []string{"https://Dashboard.example.com/"}, newCORSRequest("GET", "https://dashboard.example.com"))
+	c.Assert(w.Code, gc.Equals, http.StatusOK)
+	c.Assert(w.Header().Get("Access-Control-Allow-Origin"), gc.Equals, "https://dashboard.example.com")
+	c.Assert(w.Header().Get("Access-Control-Allow-Credentials"), gc.Equals, "true")
+	c.Assert(w.Header().Get("Vary"), gc.Equals, "Origin")
+	c.Assert(s.served, jc.DeepEquals, []string{"GET"})
+}
+
+func (s *corsSuite) TestDisallowedOrigin(c *gc.C) {
+	w := s.serve(c, []string{"https://dashboard.example.com"}, newCORSRequest("GET", "https://evil.example.com"))
+	c.Assert(w.Code, gc.Equals, http.StatusOK)
+	c.Assert(w.Header().Get("Access-Control-Allow-Origin"), gc.Equals, "")
+	c.Assert(w.Header().Get("Access-Control-Allow-Credentials"), gc.Equals, "")
+	c.Assert(s.served, jc.DeepEquals, []string{"GET"})
+}
+
+func (s *corsSuite) TestAnyOriginWithoutCredentials(c *gc.C) {
+	w := s.serve(c, []string{"*"}, newCORSRequest("GET", "https://dashboard.example.com"))
+	c.Assert(w.Code, gc.Equals, http.StatusOK)
+	c.Assert(w.Header().Get("Access-Control-Allow-Origin"), gc.Equals, "*")
+	c.Assert(w.Header().Get("Access-Control-Allow-Credentials"), gc.Equals, "")
+	c.Assert(s.served, jc.DeepEquals, []string{"GET"})
+}
+
+func (s *corsSuite) TestPreflight(c *gc.C) {
+	req := newCORSRequest("OPTIONS", "https://dashboard.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type, Authorization")
+	w := s.serve(c, []string{"https://dashboard.example.com"}, req)
+	c.Assert(w.Code, gc.Equals, http.StatusNoContent)
+	c.Assert(w.Header().Get("Access-Control-Allow-Origin"), gc.Equals, "https://dashboard.example.com")
+	c.Assert(w.Header().Get("Access-Control-Allow-Methods"), gc.Equals, "GET, POST, HEAD, PUT, DELETE, OPTIONS")
+	c.Assert(w.Header().Get("Access-Control-Allow-Headers"), gc.Equals, "Content-Type, Authorization")
+	c.Assert(w.Header().Get("Access-Control-Max-Age"), gc.Equals, "600")
+	c.Assert(s.served, gc.HasLen, 0)
+}
+
+func (s *corsSuite) TestPreflightDisallowedOrigin(c *gc.C) {
+	req := newCORSRequest("OPTIONS", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w := s.serve(c, []string{"https://dashboard.example.com"}, req)
+	c.Assert(w.Header().Get("Access-Control-Allow-Origin"), gc.Equals, "")
+	c.Assert(w.Header().Get("Access-Control-Allow-Methods"), gc.Equals, "")
+	c.Assert(s.served, jc.DeepEquals, []string{"OPTIONS"})
+}
@@ -4,6 +4,9 @@
 package apiserver
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"net"
 	"net/http"
 	"net/url"
@@ -12,26 +15,33 @@ import (
 	"syscall"
 	"time"
 
+	gorillaws "github.com/gorilla/websocket"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/websocket"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
 // debugLogHandler takes requests to watch the debug log.
 //
-// It provides the underlying framework for the 2 debug-log
-// variants. The supplied handle func allows for varied handling of
-// requests.
+// It provides the underlying framework for streaming debug-log
+// records to the client. Records are read from the centralized logs
+// collection in state (see debuglog_db.go), so that output from all
+// controller machines and remote models is included. The supplied
+// handle func allows for varied handling of requests.
 type debugLogHandler struct {
 	ctxt   httpContext
 	handle debugLogHandlerFunc
 }
 
 type debugLogHandlerFunc func(
+	clock.Clock,
 	state.LogTailerState,
 	debugLogParams,
 	debugLogSocket,
@@ -60,20 +70,59 @@ func newDebugLogHandler(
 //   excludeEntity -> []string - lists entity tags to exclude from the response
 //      - as with include, it may finish with a '*'
 //   excludeModule -> []string - lists logging modules to exclude from the response
+//   includeModel -> []string - lists models, by UUID or name, whose logs to include
+//      - only valid when tailing the controller model's logs, for users
+//        with superuser access to the controller
+//      - if set, the logs of the listed models are sent in place of the
+//        controller model's logs
+//   excludeModel -> []string - lists models, by UUID or name, whose logs to exclude
+//      - as with include; if include is not set, the logs of all models
+//        other than those listed are sent
 //   limit -> uint - show *at most* this many lines
 //   backlog -> uint
 //      - go back this many lines from the end before starting to filter
 //      - has no meaning if 'replay' is true
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
-//   replay -> string - one of [true, false], if true, start the file from the start
+//   replay -> string - one of [true, false], if true, start from the oldest log record
 //   noTail -> string - one of [true, false], if true, existing logs are sent back,
-//      - but the command does not wait for new ones.
+//      - but the command does not wait for new ones: once the server has
+//        caught up with the end of the log it closes the websocket with a
+//        normal closure status.
+//   startTime -> string - RFC3339 time, only send records logged at or after this time
+//   endTime -> string - RFC3339 time, only send records logged at or before this time
+//      - once the end time has passed, the stream ends after the last matching record
+//   format -> string - one of [text, json], defaults to text
+//      - if json, each record is sent as a single line JSON object with the
+//        fields timestamp, entity, module, level, message and location
+//   ratelimit -> uint - send at most this many lines per second
+//      - if zero or not set, lines are sent as fast as the client reads them
+//   overflow -> string - one of [block, drop, disconnect], defaults to block
+//      - determines what happens when the client reads so slowly that the
+//        queue of lines waiting to be sent fills up: block stops reading
+//        new lines until there is room, drop discards new lines (and tells
+//        the client how many were dropped), and disconnect ends the stream
+//   trailer -> string - one of [true, false], if true, a stream that ends
+//      - because noTail was set, the end time passed or limit lines were
+//        sent, is ended with a params.DebugLogTrailerMessage JSON message
+//        saying how many lines matched, how many were sent, the number of
+//        bytes sent and whether any matching lines were not sent
+//   compress -> string - if gzip, the records and trailer are compressed
+//      - they are written to a single gzip stream, flushed after each
+//        message, and each flushed chunk is sent as a binary message;
+//        the initial error response is not compressed
+//
+// Once the stream is open, the client may send a params.DebugLogFilters
+// JSON message to replace the level, includeEntity, excludeEntity,
+// includeModule and excludeModule filters. The new filters apply to all
+// lines after the last one sent before the message was received; if no
+// lines have been sent, the request starts again with the new filters.
+// The model filters may not be changed.
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	handler := func(conn *websocket.Conn) {
-		socket := &debugLogSocketImpl{conn}
+		socket := &debugLogSocketImpl{conn: conn}
 		defer conn.Close()
 
-		st, releaser, _, err := h.ctxt.stateForRequestAuthenticatedTag(req, names.MachineTagKind, names.UserTagKind)
+		st, releaser, entity, err := h.ctxt.stateForRequestAuthenticatedTag(req, names.MachineTagKind, names.UserTagKind)
 		if err != nil {
 			socket.sendError(err)
 			return
@@ -85,8 +134,18 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			socket.sendError(err)
 			return
 		}
+		if params.compress == debugLogCompressGzip {
+			socket.gzip = gzip.NewWriter(&socket.gzipBuf)
+		}
+		if len(params.includeModel) > 0 || len(params.excludeModel) > 0 {
+			params.modelUUIDs, err = resolveDebugLogModels(st, entity.Tag(), params.includeModel, params.excludeModel)
+			if err != nil {
+				socket.sendError(err)
+				return
+			}
+		}
 
-		if err := h.handle(st, params, socket, h.ctxt.stop()); err != nil {
+		if err := h.handle(h.ctxt.srv.clock, st, params, socket, h.ctxt.stop()); err != nil {
 			if isBrokenPipe(err) {
 				logger.Tracef("debug-log handler stopped (client disconnected)")
 			} else {
@@ -117,8 +176,25 @@ type debugLogSocket interface {
 	// sendError sends a JSON-encoded error response.
 	sendError(err error)
 
-	// sendLogRecord sends record JSON encoded.
-	sendLogRecord(record *params.LogMessage) error
+	// sendLogRecord sends record JSON encoded, and returns the
+	// size of the message sent.
+	sendLogRecord(record *params.LogMessage) (int, error)
+
+	// sendDebugLogRecord sends record JSON encoded, for requests
+	// using the json format, and returns the size of the message
+	// sent.
+	sendDebugLogRecord(record *params.DebugLogRecord) (int, error)
+
+	// sendTrailer sends trailer JSON encoded.
+	sendTrailer(trailer *params.DebugLogTrailerMessage) error
+
+	// sendEnd tells the client that all the records it asked for
+	// have been sent.
+	sendEnd() error
+
+	// readFilters reads a JSON-encoded filter change sent by the
+	// client.
+	readFilters(filters *params.DebugLogFilters) error
 }
 
 // debugLogSocketImpl implements the debugLogSocket interface. It
@@ -126,6 +202,11 @@ type debugLogSocket interface {
 // methods.
 type debugLogSocketImpl struct {
 	conn *websocket.Conn
+
+	// gzip, if set, compresses the messages sent into gzipBuf, from
+	// which they are sent as binary messages.
+	gzip    *gzip.Writer
+	gzipBuf bytes.Buffer
 }
 
 // sendOk implements debugLogSocket.
@@ -142,13 +223,81 @@ func (s *debugLogSocketImpl) sendError(err error) {
 	}
 }
 
-func (s *debugLogSocketImpl) sendLogRecord(record *params.LogMessage) error {
-	return s.conn.WriteJSON(record)
+func (s *debugLogSocketImpl) sendLogRecord(record *params.LogMessage) (int, error) {
+	return s.writeJSON(record)
+}
+
+func (s *debugLogSocketImpl) sendDebugLogRecord(record *params.DebugLogRecord) (int, error) {
+	return s.writeJSON(record)
+}
+
+func (s *debugLogSocketImpl) sendTrailer(trailer *params.DebugLogTrailerMessage) error {
+	_, err := s.writeJSON(trailer)
+	return err
+}
+
+// writeJSON sends v JSON encoded, as the websocket's WriteJSON does,
+// and returns the size of the message sent. If the socket compresses
+// messages, the size is that of the compressed message.
+func (s *debugLogSocketImpl) writeJSON(v interface{}) (int, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	data = append(data, '\n')
+	if s.gzip == nil {
+		return len(data), s.conn.WriteMessage(gorillaws.TextMessage, data)
+	}
+	s.gzipBuf.Reset()
+	if _, err := s.gzip.Write(data); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if err := s.gzip.Flush(); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return s.gzipBuf.Len(), s.conn.WriteMessage(gorillaws.BinaryMessage, s.gzipBuf.Bytes())
+}
+
+func (s *debugLogSocketImpl) sendEnd() error {
+	return s.conn.SendCloseNormal()
 }
 
+func (s *debugLogSocketImpl) readFilters(filters *params.DebugLogFilters) error {
+	return s.conn.ReadJSON(filters)
+}
+
+const (
+	// debugLogFormatText is the default debug-log format, where
+	// records are sent as params.LogMessage for the client to render.
+	debugLogFormatText = "text"
+
+	// debugLogFormatJSON sends records as params.DebugLogRecord.
+	debugLogFormatJSON = "json"
+)
+
+const (
+	// debugLogOverflowBlock is the default debug-log overflow policy,
+	// where no more records are read while the queue of records
+	// waiting to be sent to the client is full.
+	debugLogOverflowBlock = "block"
+
+	// debugLogOverflowDrop discards records read while the queue is
+	// full, and tells the client how many were discarded.
+	debugLogOverflowDrop = "drop"
+
+	// debugLogOverflowDisconnect ends the request when the queue is
+	// full.
+	debugLogOverflowDisconnect = "disconnect"
+)
+
+// debugLogCompressGzip is the only supported value of the compress
+// parameter, which compresses the records sent with gzip.
+const debugLogCompressGzip = "gzip"
+
 // debugLogParams contains the parsed debuglog API request parameters.
 type debugLogParams struct {
 	startTime     time.Time
+	endTime       time.Time
 	maxLines      uint
 	fromTheStart  bool
 	noTail        bool
@@ -158,6 +307,14 @@ type debugLogParams struct {
 	excludeEntity []string
 	includeModule []string
 	excludeModule []string
+	includeModel  []string
+	excludeModel  []string
+	modelUUIDs    []string
+	format        string
+	rateLimit     uint
+	overflow      string
+	trailer       bool
+	compress      string
 }
 
 func readDebugLogParams(queryMap url.Values) (debugLogParams, error) {
@@ -196,11 +353,9 @@ func readDebugLogParams(queryMap url.Values) (debugLogParams, error) {
 	}
 
 	if value := queryMap.Get("level"); value != "" {
-		var ok bool
-		level, ok := loggo.ParseLevel(value)
-		if !ok || level < loggo.TRACE || level > loggo.ERROR {
-			return params, errors.Errorf("level value %q is not one of %q, %q, %q, %q, %q",
-				value, loggo.TRACE, loggo.DEBUG, loggo.INFO, loggo.WARNING, loggo.ERROR)
+		level, err := parseDebugLogLevel(value)
+		if err != nil {
+			return params, errors.Trace(err)
 		}
 		params.filterLevel = level
 	}
@@ -213,10 +368,162 @@ func readDebugLogParams(queryMap url.Values) (debugLogParams, error) {
 		params.startTime = startTime
 	}
 
+	if value := queryMap.Get("endTime"); value != "" {
+		endTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return params, errors.Errorf("end time %q is not a valid time in RFC3339 format", value)
+		}
+		if endTime.Before(params.startTime) {
+			return params, errors.Errorf("end time %q is before start time", value)
+		}
+		params.endTime = endTime
+	}
+
+	params.format = debugLogFormatText
+	if value := queryMap.Get("format"); value != "" {
+		if value != debugLogFormatText && value != debugLogFormatJSON {
+			return params, errors.Errorf("format value %q is not one of %q, %q",
+				value, debugLogFormatText, debugLogFormatJSON)
+		}
+		params.format = value
+	}
+
+	if value := queryMap.Get("ratelimit"); value != "" {
+		num, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return params, errors.Errorf("ratelimit value %q is not a valid unsigned number", value)
+		}
+		params.rateLimit = uint(num)
+	}
+
+	params.overflow = debugLogOverflowBlock
+	if value := queryMap.Get("overflow"); value != "" {
+		switch value {
+		case debugLogOverflowBlock, debugLogOverflowDrop, debugLogOverflowDisconnect:
+		default:
+			return params, errors.Errorf("overflow value %q is not one of %q, %q, %q",
+				value, debugLogOverflowBlock, debugLogOverflowDrop, debugLogOverflowDisconnect)
+		}
+		params.overflow = value
+	}
+
+	if value := queryMap.Get("trailer"); value != "" {
+		trailer, err := strconv.ParseBool(value)
+		if err != nil {
+			return params, errors.Errorf("trailer value %q is not a valid boolean", value)
+		}
+		params.trailer = trailer
+	}
+
+	if value := queryMap.Get("compress"); value != "" {
+		if value != debugLogCompressGzip {
+			return params, errors.Errorf("compress value %q is not %q", value, debugLogCompressGzip)
+		}
+		params.compress = value
+	}
+
 	params.includeEntity = queryMap["includeEntity"]
 	params.excludeEntity = queryMap["excludeEntity"]
 	params.includeModule = queryMap["includeModule"]
 	params.excludeModule = queryMap["excludeModule"]
+	params.includeModel = queryMap["includeModel"]
+	params.excludeModel = queryMap["excludeModel"]
 
 	return params, nil
 }
+
+// resolveDebugLogModels returns the UUIDs of the models selected by
+// the model filters of a request made by the given entity.
+func resolveDebugLogModels(st *state.State, tag names.Tag, include, exclude []string) ([]string, error) {
+	if !st.IsController() {
+		return nil, errors.New("model filters are only valid for the controller model")
+	}
+	if _, ok := tag.(names.UserTag); !ok {
+		return nil, common.ErrPerm
+	}
+	isSuperuser, err := common.HasPermission(st.UserPermission, tag, permission.SuperuserAccess, st.ControllerTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isSuperuser {
+		return nil, common.ErrPerm
+	}
+	uuids, err := st.AllModelUUIDs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	models := make([]debugLogModel, len(uuids))
+	for i, uuid := range uuids {
+		model, err := st.GetModel(names.NewModelTag(uuid))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		models[i] = debugLogModel{uuid: uuid, name: model.Name()}
+	}
+	selected := selectDebugLogModels(models, include, exclude)
+	if len(selected) == 0 {
+		return nil, errors.New("model filters match no models")
+	}
+	return selected, nil
+}
+
+// debugLogModel identifies a model that may be selected by the model
+// filters of a debug-log request.
+type debugLogModel struct {
+	uuid string
+	name string
+}
+
+// selectDebugLogModels returns the UUIDs of the models that match any
+// of the include filters, or all models if there are none, and none of
+// the exclude filters. A filter matches a model's UUID or name.
+func selectDebugLogModels(models []debugLogModel, include, exclude []string) []string {
+	matches := func(model debugLogModel, filters []string) bool {
+		for _, filter := range filters {
+			if filter == model.uuid || filter == model.name {
+				return true
+			}
+		}
+		return false
+	}
+	var selected []string
+	for _, model := range models {
+		if len(include) > 0 && !matches(model, include) {
+			continue
+		}
+		if matches(model, exclude) {
+			continue
+		}
+		selected = append(selected, model.uuid)
+	}
+	return selected
+}
+
+// parseDebugLogLevel returns the minimum level named by a debug-log
+// level filter.
+func parseDebugLogLevel(value string) (loggo.Level, error) {
+	level, ok := loggo.ParseLevel(value)
+	if !ok || level < loggo.TRACE || level > loggo.ERROR {
+		return loggo.UNSPECIFIED, errors.Errorf("level value %q is not one of %q, %q, %q, %q, %q",
+			value, loggo.TRACE, loggo.DEBUG, loggo.INFO, loggo.WARNING, loggo.ERROR)
+	}
+	return level, nil
+}
+
+// withFilters returns a copy of the request parameters with the
+// filters replaced by those sent by the client.
+func (p debugLogParams) withFilters(filters params.DebugLogFilters) (debugLogParams, error) {
+	p.filterLevel = loggo.UNSPECIFIED
+	if filters.Level != "" {
+		level, err := parseDebugLogLevel(filters.Level)
+		if err != nil {
+			return debugLogParams{}, errors.Trace(err)
+		}
+		p.filterLevel = level
+	}
+	p.includeEntity = filters.IncludeEntity
+	p.excludeEntity = filters.ExcludeEntity
+	p.includeModule = filters.IncludeModule
+	p.excludeModule = filters.ExcludeModule
+	return p, nil
+}
@@ -4,9 +4,16 @@
 package apiserver
 
 import (
+	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/ratelimit"
+	"github.com/juju/utils/clock"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
@@ -16,42 +23,400 @@ func newDebugLogDBHandler(ctxt httpContext) http.Handler {
 	return newDebugLogHandler(ctxt, handleDebugLogDBRequest)
 }
 
+// debugLogQueueSize is the maximum number of records waiting to be
+// sent to a debug-log client.
+var debugLogQueueSize = 1000 // For replacing in tests
+
 func handleDebugLogDBRequest(
+	clock clock.Clock,
 	st state.LogTailerState,
 	reqParams debugLogParams,
 	socket debugLogSocket,
 	stop <-chan struct{},
 ) error {
-	params := makeLogTailerParams(reqParams)
-	tailer, err := newLogTailer(st, params)
+	tailer, err := newLogTailer(st, makeLogTailerParams(reqParams))
 	if err != nil {
 		return errors.Trace(err)
 	}
-	defer tailer.Stop()
+	// The tailer is replaced when the client changes the filters.
+	defer func() {
+		if tailer != nil {
+			tailer.Stop()
+		}
+	}()
 
 	// Indicate that all is well.
 	socket.sendOk()
 
-	var lineCount uint
+	// Records are sent to the client by a separate goroutine, so
+	// that a slow client is dealt with according to the requested
+	// overflow policy rather than always stalling the tailer.
+	sender := newDebugLogSender(clock, reqParams, socket)
+	abort := make(chan struct{})
+	defer close(abort)
+	senderDone := make(chan error, 1)
+	go func() {
+		senderDone <- sender.loop(abort)
+	}()
+	filterChanges := make(chan params.DebugLogFilters)
+	go readDebugLogFilters(socket, filterChanges, abort)
+
+	// When the tailer is replaced, it is started from the time of the
+	// last record sent, so records up to and including that one are
+	// skipped.
+	var resuming bool
+	var resumeAfterID int64
 	for {
 		select {
 		case <-stop:
 			return nil
+		case err := <-senderDone:
+			return endDebugLogRequest(socket, sender, err)
+		case filters := <-filterChanges:
+			newParams, err := reqParams.withFilters(filters)
+			if err != nil {
+				// The client is told, whatever the overflow policy.
+				notice := debugLogItem{
+					notice: fmt.Sprintf("debug-log filters not changed: %v", err),
+				}
+				select {
+				case <-stop:
+					return nil
+				case err := <-senderDone:
+					return errors.Trace(err)
+				case sender.queue <- notice:
+				}
+				continue
+			}
+			tailerParams := makeLogTailerParams(newParams)
+			if last := sender.changeFilters(); last != nil {
+				tailerParams.StartTime = last.Time
+				tailerParams.InitialLines = 0
+				resuming, resumeAfterID = true, last.ID
+			}
+			tailer.Stop()
+			tailer = nil
+			newTailer, err := newLogTailer(st, tailerParams)
+			if err != nil {
+				return errors.Annotate(err, "restarting log tailer")
+			}
+			tailer = newTailer
+			reqParams = newParams
 		case rec, ok := <-tailer.Logs():
 			if !ok {
-				return errors.Annotate(tailer.Err(), "tailer stopped")
+				if err := tailer.Err(); err != nil {
+					return errors.Annotate(err, "tailer stopped")
+				}
+				// The tailer has caught up with the end of the
+				// log (or passed the end time); wait for the
+				// queued records to be sent.
+				close(sender.queue)
+				select {
+				case <-stop:
+					return nil
+				case err := <-senderDone:
+					return endDebugLogRequest(socket, sender, err)
+				}
+			}
+			if resuming && rec.ID <= resumeAfterID {
+				continue
+			}
+			resuming = false
+			item := sender.newItem(rec)
+			select {
+			case sender.queue <- item:
+				continue
+			default:
 			}
+			switch reqParams.overflow {
+			case debugLogOverflowDrop:
+				sender.drop()
+			case debugLogOverflowDisconnect:
+				return errors.New("client too slow: debug-log queue full")
+			default:
+				select {
+				case <-stop:
+					return nil
+				case err := <-senderDone:
+					return errors.Trace(err)
+				case sender.queue <- item:
+				}
+			}
+		}
+	}
+}
+
+// readDebugLogFilters passes each filter change sent by the client to
+// changes, until the client stops sending or abort is closed.
+func readDebugLogFilters(socket debugLogSocket, changes chan<- params.DebugLogFilters, abort <-chan struct{}) {
+	for {
+		var filters params.DebugLogFilters
+		if err := socket.readFilters(&filters); err != nil {
+			logger.Tracef("debug-log client stopped sending filters: %v", err)
+			return
+		}
+		select {
+		case <-abort:
+			return
+		case changes <- filters:
+		}
+	}
+}
+
+// endDebugLogRequest tells the client that the stream is complete,
+// sending the trailer first if the client asked for it, unless the
+// sender that finished the request failed.
+func endDebugLogRequest(socket debugLogSocket, sender *debugLogSender, senderErr error) error {
+	if senderErr != nil {
+		return errors.Trace(senderErr)
+	}
+	if sender.reqParams.trailer {
+		trailer := &params.DebugLogTrailerMessage{Trailer: sender.trailer()}
+		if err := socket.sendTrailer(trailer); err != nil {
+			return errors.Annotate(err, "sending trailer")
+		}
+	}
+	return errors.Annotate(socket.sendEnd(), "sending end of stream")
+}
+
+// debugLogItem is a record, or a notice for the client, queued to be
+// sent by a debugLogSender.
+type debugLogItem struct {
+	// record is the record to send, if the notice is empty.
+	record *state.LogRecord
+
+	// generation is the generation of the filters that the record
+	// was read with.
+	generation uint64
+
+	// notice is a warning to send to the client.
+	notice string
+}
+
+// debugLogSender sends the records queued for a debug-log client,
+// no faster than the requested rate limit.
+type debugLogSender struct {
+	// dropped is the number of records discarded since the client
+	// was last told about dropped records, and totalDropped the
+	// number discarded altogether. They must be accessed atomically.
+	dropped      uint64
+	totalDropped uint64
+
+	clock     clock.Clock
+	reqParams debugLogParams
+	socket    debugLogSocket
+	queue     chan debugLogItem
+	bucket    *ratelimit.Bucket
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// generation is incremented each time the client changes the
+	// filters. Records read with earlier filters are not sent.
+	generation uint64
+
+	// lastSent is the last record sent, or being sent, to the
+	// client.
+	lastSent *state.LogRecord
+
+	// linesSent and bytesSent count the records, and the bytes of
+	// all messages, sent to the client. They are only accessed by
+	// the loop goroutine until it has finished.
+	linesSent uint64
+	bytesSent uint64
+}
 
-			if err := socket.sendLogRecord(formatLogRecord(rec)); err != nil {
-				return errors.Annotate(err, "sending failed")
+func newDebugLogSender(clock clock.Clock, reqParams debugLogParams, socket debugLogSocket) *debugLogSender {
+	sender := &debugLogSender{
+		clock:     clock,
+		reqParams: reqParams,
+		socket:    socket,
+		queue:     make(chan debugLogItem, debugLogQueueSize),
+	}
+	if reqParams.rateLimit > 0 {
+		interval := time.Second / time.Duration(reqParams.rateLimit)
+		if interval <= 0 {
+			interval = 1
+		}
+		sender.bucket = ratelimit.NewBucketWithClock(
+			interval,
+			int64(reqParams.rateLimit),
+			ratelimitClock{clock},
+		)
+	}
+	return sender
+}
+
+// newItem returns an item that queues rec to be sent with the current
+// filters. It must be called by the goroutine that calls
+// changeFilters.
+func (s *debugLogSender) newItem(rec *state.LogRecord) debugLogItem {
+	return debugLogItem{record: rec, generation: s.generation}
+}
+
+// drop records that a record read with the current filters was
+// discarded rather than queued.
+func (s *debugLogSender) drop() {
+	atomic.AddUint64(&s.dropped, 1)
+	atomic.AddUint64(&s.totalDropped, 1)
+}
+
+// trailer returns the summary of the records sent, discarding any
+// that remain queued. It must only be called once loop has finished.
+func (s *debugLogSender) trailer() params.DebugLogTrailer {
+	var pending uint64
+	for done := false; !done; {
+		select {
+		case item, ok := <-s.queue:
+			if !ok {
+				done = true
+			} else if item.notice == "" && s.isCurrent(item) {
+				pending++
 			}
+		default:
+			done = true
+		}
+	}
+	dropped := atomic.LoadUint64(&s.totalDropped)
+	return params.DebugLogTrailer{
+		LinesMatched: s.linesSent + dropped + pending,
+		LinesSent:    s.linesSent,
+		BytesSent:    s.bytesSent,
+		Truncated:    dropped+pending > 0,
+	}
+}
 
-			lineCount++
-			if reqParams.maxLines > 0 && lineCount == reqParams.maxLines {
-				return nil
+// changeFilters stops the sender sending records read with the
+// current filters, and returns the last record it sent, or is
+// sending, if any.
+func (s *debugLogSender) changeFilters() *state.LogRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	return s.lastSent
+}
+
+// loop sends queued records until the queue is closed, the
+// requested maximum number of lines has been sent, or abort is
+// closed.
+func (s *debugLogSender) loop(abort <-chan struct{}) error {
+	var lineCount uint
+	for {
+		var item debugLogItem
+		select {
+		case <-abort:
+			return nil
+		case i, ok := <-s.queue:
+			if !ok {
+				return errors.Trace(s.sendDroppedNotice())
+			}
+			item = i
+		}
+		if item.notice != "" {
+			if err := s.sendNotice(item.notice); err != nil {
+				return errors.Trace(err)
 			}
+			continue
 		}
+		if !s.isCurrent(item) {
+			continue
+		}
+		if s.bucket != nil {
+			if d := s.bucket.Take(1); d > 0 {
+				select {
+				case <-abort:
+					return nil
+				case <-s.clock.After(d):
+				}
+			}
+		}
+		if err := s.sendDroppedNotice(); err != nil {
+			return errors.Trace(err)
+		}
+		sent, err := s.sendRecord(item)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !sent {
+			continue
+		}
+
+		lineCount++
+		if s.reqParams.maxLines > 0 && lineCount == s.reqParams.maxLines {
+			return nil
+		}
+	}
+}
+
+// isCurrent reports whether the record queued in item was read with
+// the current filters.
+func (s *debugLogSender) isCurrent(item debugLogItem) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return item.generation == s.generation
+}
+
+// sendRecord sends the record queued in item, unless it was read with
+// filters that the client has since changed, and reports whether it
+// was sent.
+func (s *debugLogSender) sendRecord(item debugLogItem) (bool, error) {
+	s.mu.Lock()
+	if item.generation != s.generation {
+		s.mu.Unlock()
+		return false, nil
+	}
+	// The record is recorded as sent before it is, so that the
+	// filters may change while a slow client is reading it.
+	s.lastSent = item.record
+	s.mu.Unlock()
+
+	var n int
+	var err error
+	if s.reqParams.format == debugLogFormatJSON {
+		n, err = s.socket.sendDebugLogRecord(formatDebugLogRecord(item.record))
+	} else {
+		n, err = s.socket.sendLogRecord(formatLogRecord(item.record))
+	}
+	if err != nil {
+		return false, errors.Annotate(err, "sending failed")
+	}
+	s.linesSent++
+	s.bytesSent += uint64(n)
+	return true, nil
+}
+
+// sendDroppedNotice tells the client how many records have been
+// dropped since it was last told, if any.
+func (s *debugLogSender) sendDroppedNotice() error {
+	dropped := atomic.SwapUint64(&s.dropped, 0)
+	if dropped == 0 {
+		return nil
+	}
+	return s.sendNotice(fmt.Sprintf("%d log records dropped (client too slow)", dropped))
+}
+
+// sendNotice sends a warning to the client as a log record.
+func (s *debugLogSender) sendNotice(message string) error {
+	const module = "juju.apiserver.debuglog"
+	var n int
+	var err error
+	if s.reqParams.format == debugLogFormatJSON {
+		n, err = s.socket.sendDebugLogRecord(&params.DebugLogRecord{
+			Timestamp: s.clock.Now(),
+			Module:    module,
+			Level:     loggo.WARNING.String(),
+			Message:   message,
+		})
+	} else {
+		n, err = s.socket.sendLogRecord(&params.LogMessage{
+			Timestamp: s.clock.Now(),
+			Severity:  loggo.WARNING.String(),
+			Module:    module,
+			Message:   message,
+		})
 	}
+	s.bytesSent += uint64(n)
+	return errors.Annotate(err, "sending failed")
 }
 
 func makeLogTailerParams(reqParams debugLogParams) state.LogTailerParams {
@@ -59,11 +424,13 @@ func makeLogTailerParams(reqParams debugLogParams) state.LogTailerParams {
 		MinLevel:      reqParams.filterLevel,
 		NoTail:        reqParams.noTail,
 		StartTime:     reqParams.startTime,
+		EndTime:       reqParams.endTime,
 		InitialLines:  int(reqParams.backlog),
 		IncludeEntity: reqParams.includeEntity,
 		ExcludeEntity: reqParams.excludeEntity,
 		IncludeModule: reqParams.includeModule,
 		ExcludeModule: reqParams.excludeModule,
+		ModelUUIDs:    reqParams.modelUUIDs,
 	}
 	if reqParams.fromTheStart {
 		params.InitialLines = 0
@@ -82,8 +449,29 @@ func formatLogRecord(r *state.LogRecord) *params.LogMessage {
 	}
 }
 
+func formatDebugLogRecord(r *state.LogRecord) *params.DebugLogRecord {
+	return &params.DebugLogRecord{
+		Timestamp: r.Time,
+		Entity:    r.Entity.String(),
+		Module:    r.Module,
+		Level:     r.Level.String(),
+		Message:   r.Message,
+		Location:  r.Location,
+	}
+}
+
 var newLogTailer = _newLogTailer // For replacing in tests
 
 func _newLogTailer(st state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
 	return state.NewLogTailer(st, params)
 }
+
+// ratelimitClock adapts clock.Clock to ratelimit.Clock.
+type ratelimitClock struct {
+	clock.Clock
+}
+
+// Sleep is defined by the ratelimit.Clock interface.
+func (c ratelimitClock) Sleep(d time.Duration) {
+	<-c.Clock.After(d)
+}
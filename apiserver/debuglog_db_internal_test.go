@@ -4,10 +4,16 @@
 package apiserver
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/juju/loggo"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
@@ -19,37 +25,41 @@ import (
 
 type debugLogDBIntSuite struct {
 	coretesting.BaseSuite
-	sock *fakeDebugLogSocket
+	clock *testing.Clock
+	sock  *fakeDebugLogSocket
 }
 
 var _ = gc.Suite(&debugLogDBIntSuite{})
 
 func (s *debugLogDBIntSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Time{})
 	s.sock = newFakeDebugLogSocket()
 }
 
 func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 	t1 := time.Date(2016, 11, 30, 10, 51, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
 	reqParams := debugLogParams{
 		fromTheStart:  false,
 		noTail:        true,
 		backlog:       11,
 		startTime:     t1,
+		endTime:       t2,
 		filterLevel:   loggo.INFO,
 		includeEntity: []string{"foo"},
 		includeModule: []string{"bar"},
 		excludeEntity: []string{"baz"},
 		excludeModule: []string{"qux"},
+		modelUUIDs:    []string{"deadbeef"},
 	}
 
 	called := false
 	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
 		called = true
 
-		// Start time will be used once the client is extended to send
-		// time range arguments.
 		c.Assert(params.StartTime, gc.Equals, t1)
+		c.Assert(params.EndTime, gc.Equals, t2)
 		c.Assert(params.NoTail, jc.IsTrue)
 		c.Assert(params.MinLevel, gc.Equals, loggo.INFO)
 		c.Assert(params.InitialLines, gc.Equals, 11)
@@ -57,13 +67,14 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 		c.Assert(params.IncludeModule, jc.DeepEquals, []string{"bar"})
 		c.Assert(params.ExcludeEntity, jc.DeepEquals, []string{"baz"})
 		c.Assert(params.ExcludeModule, jc.DeepEquals, []string{"qux"})
+		c.Assert(params.ModelUUIDs, jc.DeepEquals, []string{"deadbeef"})
 
 		return newFakeLogTailer(), nil
 	})
 
 	stop := make(chan struct{})
 	close(stop) // Stop the request immediately.
-	err := handleDebugLogDBRequest(nil, reqParams, s.sock, stop)
+	err := handleDebugLogDBRequest(s.clock, nil, reqParams, s.sock, stop)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
@@ -86,11 +97,54 @@ func (s *debugLogDBIntSuite) TestParamConversionReplay(c *gc.C) {
 
 	stop := make(chan struct{})
 	close(stop) // Stop the request immediately.
-	err := handleDebugLogDBRequest(nil, reqParams, s.sock, stop)
+	err := handleDebugLogDBRequest(s.clock, nil, reqParams, s.sock, stop)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *debugLogDBIntSuite) TestReadModelFilters(c *gc.C) {
+	reqParams, err := readDebugLogParams(url.Values{
+		"includeModel": {"default", "deadbeef"},
+		"excludeModel": {"controller"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(reqParams.includeModel, jc.DeepEquals, []string{"default", "deadbeef"})
+	c.Check(reqParams.excludeModel, jc.DeepEquals, []string{"controller"})
+}
+
+func (s *debugLogDBIntSuite) TestSelectModels(c *gc.C) {
+	models := []debugLogModel{
+		{uuid: "uuid-controller", name: "controller"},
+		{uuid: "uuid-default", name: "default"},
+		{uuid: "uuid-other", name: "other"},
+		{uuid: "uuid-default-too", name: "default"},
+	}
+	for i, test := range []struct {
+		include  []string
+		exclude  []string
+		expected []string
+	}{{
+		include:  []string{"default"},
+		expected: []string{"uuid-default", "uuid-default-too"},
+	}, {
+		include:  []string{"uuid-other", "controller"},
+		expected: []string{"uuid-controller", "uuid-other"},
+	}, {
+		exclude:  []string{"controller", "uuid-default-too"},
+		expected: []string{"uuid-default", "uuid-other"},
+	}, {
+		include:  []string{"default"},
+		exclude:  []string{"uuid-default"},
+		expected: []string{"uuid-default-too"},
+	}, {
+		include: []string{"nonexistent"},
+	}} {
+		c.Logf("test %d: include %v exclude %v", i, test.include, test.exclude)
+		selected := selectDebugLogModels(models, test.include, test.exclude)
+		c.Check(selected, jc.DeepEquals, test.expected)
+	}
+}
+
 func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	// Set up a fake log tailer with a 2 log records ready to send.
 	tailer := newFakeLogTailer()
@@ -128,6 +182,33 @@ func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	s.assertStops(c, done, tailer)
 }
 
+func (s *debugLogDBIntSuite) TestJSONFormat(c *gc.C) {
+	tailer := newFakeLogTailer()
+	tailer.logsCh <- &state.LogRecord{
+		Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+		Entity:   names.NewMachineTag("99"),
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "stuff happened",
+	}
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		return tailer, nil
+	})
+
+	stop := make(chan struct{})
+	done := s.runRequest(debugLogParams{format: debugLogFormatJSON}, stop)
+
+	s.assertOutput(c, []string{
+		"ok",
+		`{"timestamp":"2015-06-19T15:34:37Z","entity":"machine-99","module":"some.where",` +
+			`"level":"INFO","message":"stuff happened","location":"code.go:42"}`,
+	})
+
+	close(stop)
+	s.assertStops(c, done, tailer)
+}
+
 func (s *debugLogDBIntSuite) TestRequestStopsWhenTailerStops(c *gc.C) {
 	tailer := newFakeLogTailer()
 	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
@@ -135,9 +216,26 @@ func (s *debugLogDBIntSuite) TestRequestStopsWhenTailerStops(c *gc.C) {
 		return tailer, nil
 	})
 
-	err := handleDebugLogDBRequest(nil, debugLogParams{}, s.sock, nil)
+	err := handleDebugLogDBRequest(s.clock, nil, debugLogParams{}, s.sock, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(tailer.stopped, jc.IsTrue)
+	s.assertOutput(c, []string{"ok", "end"})
+}
+
+func (s *debugLogDBIntSuite) TestNoTailEndsStreamWhenCaughtUp(c *gc.C) {
+	// A noTail tailer closes its channel once it has sent the
+	// existing records.
+	tailer := s.patchTailer(c, 2)
+	close(tailer.logsCh)
+
+	done := s.runRequest(debugLogParams{noTail: true}, nil)
+	s.assertOutput(c, []string{
+		"ok",
+		"machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 line 0\n",
+		"machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 line 1\n",
+		"end",
+	})
+	s.assertStops(c, done, tailer)
 }
 
 func (s *debugLogDBIntSuite) TestMaxLines(c *gc.C) {
@@ -168,12 +266,320 @@ func (s *debugLogDBIntSuite) TestMaxLines(c *gc.C) {
 
 	// The tailer should now stop by itself after the line limit was reached.
 	s.assertStops(c, done, tailer)
+	s.assertOutput(c, []string{"end"})
+}
+
+func (s *debugLogDBIntSuite) TestTrailerNoTail(c *gc.C) {
+	tailer := s.patchTailer(c, 2)
+	close(tailer.logsCh)
+
+	done := s.runRequest(debugLogParams{noTail: true, trailer: true}, nil)
+	line0 := "machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 line 0\n"
+	line1 := "machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 line 1\n"
+	s.assertOutput(c, []string{
+		"ok",
+		line0,
+		line1,
+		fmt.Sprintf(`{"trailer":{"lines-matched":2,"lines-sent":2,"bytes-sent":%d,"truncated":false}}`,
+			len(line0)+len(line1)),
+		"end",
+	})
+	s.assertStops(c, done, tailer)
+}
+
+func (s *debugLogDBIntSuite) TestTrailerMaxLines(c *gc.C) {
+	s.sock.blockSends()
+	tailer := s.patchTailer(c, 5)
+
+	done := s.runRequest(debugLogParams{maxLines: 3, trailer: true}, nil)
+	s.assertOutput(c, []string{"ok"})
+
+	// Wait for all the records to be queued before any are sent.
+	for a := coretesting.LongAttempt.Start(); len(tailer.logsCh) > 0; {
+		if !a.Next() {
+			c.Fatalf("timed out waiting for records to be read")
+		}
+	}
+	s.sock.unblockSends()
+
+	var bytes int
+	for i := 0; i < 3; i++ {
+		line := fmt.Sprintf("machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 line %d\n", i)
+		s.assertOutput(c, []string{line})
+		bytes += len(line)
+	}
+	s.assertStops(c, done, tailer)
+	s.assertOutput(c, []string{
+		fmt.Sprintf(`{"trailer":{"lines-matched":5,"lines-sent":3,"bytes-sent":%d,"truncated":true}}`, bytes),
+		"end",
+	})
+}
+
+func (s *debugLogDBIntSuite) TestRateLimit(c *gc.C) {
+	tailer := newFakeLogTailer()
+	for i := 0; i < 3; i++ {
+		tailer.logsCh <- &state.LogRecord{
+			Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+			Entity:   names.NewMachineTag("99"),
+			Module:   "some.where",
+			Location: "code.go:42",
+			Level:    loggo.INFO,
+			Message:  fmt.Sprintf("line %d", i),
+		}
+	}
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		return tailer, nil
+	})
+
+	stop := make(chan struct{})
+	done := s.runRequest(debugLogParams{rateLimit: 2}, stop)
+
+	// The first second's worth of lines are sent immediately.
+	s.assertOutput(c, []string{
+		"ok",
+		"machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 line 0\n",
+		"machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 line 1\n",
+	})
+	select {
+	case write := <-s.sock.writes:
+		c.Fatalf("unexpected write %q", write)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	err := s.clock.WaitAdvance(time.Second, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertOutput(c, []string{
+		"machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 line 2\n",
+	})
+
+	close(stop)
+	s.assertStops(c, done, tailer)
+}
+
+func (s *debugLogDBIntSuite) TestOverflowDrop(c *gc.C) {
+	s.PatchValue(&debugLogQueueSize, 1)
+	s.sock.blockSends()
+	tailer := s.patchTailer(c, 5)
+	close(tailer.logsCh)
+
+	done := s.runRequest(debugLogParams{overflow: debugLogOverflowDrop}, nil)
+	s.assertOutput(c, []string{"ok"})
+
+	// Wait for the records to be read while the client is blocked.
+	for a := coretesting.LongAttempt.Start(); len(tailer.logsCh) > 0; {
+		if !a.Next() {
+			c.Fatalf("timed out waiting for records to be read")
+		}
+	}
+	s.sock.unblockSends()
+
+	// Each record is either sent or counted in a dropped notice.
+	droppedRE := regexp.MustCompile(`WARNING juju.apiserver.debuglog  (\d+) log records dropped \(client too slow\)`)
+	var sent, dropped int
+	timeout := time.After(coretesting.LongWait)
+	for sent+dropped < 5 {
+		select {
+		case write := <-s.sock.writes:
+			if m := droppedRE.FindStringSubmatch(write); m != nil {
+				n, err := strconv.Atoi(m[1])
+				c.Assert(err, jc.ErrorIsNil)
+				dropped += n
+			} else {
+				c.Assert(write, gc.Matches, `machine-99: .* line \d\n`)
+				sent++
+			}
+		case <-timeout:
+			c.Fatalf("timed out waiting for socket write")
+		}
+	}
+	c.Assert(sent+dropped, gc.Equals, 5)
+	c.Assert(dropped >= 2, jc.IsTrue)
+	s.assertStops(c, done, tailer)
+}
+
+func (s *debugLogDBIntSuite) TestOverflowDisconnect(c *gc.C) {
+	s.PatchValue(&debugLogQueueSize, 1)
+	s.sock.blockSends()
+	defer s.sock.unblockSends()
+	tailer := s.patchTailer(c, 3)
+
+	err := handleDebugLogDBRequest(s.clock, &fakeState{}, debugLogParams{overflow: debugLogOverflowDisconnect}, s.sock, nil)
+	c.Assert(err, gc.ErrorMatches, "client too slow: debug-log queue full")
+	c.Assert(tailer.stopped, jc.IsTrue)
+}
+
+func (s *debugLogDBIntSuite) TestChangeFilters(c *gc.C) {
+	t0 := time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC)
+	first := newFakeLogTailer()
+	first.logsCh <- testLogRecord(t0, "machine-99", "first")
+	second := newFakeLogTailer()
+	// The second tailer starts at the time of the last record sent,
+	// so it reads that record again.
+	second.logsCh <- testLogRecord(t0, "machine-99", "first")
+	second.logsCh <- testLogRecord(t0.Add(time.Second), "unit-foo-2", "second")
+	tailerParams := s.patchTailers(first, second)
+
+	stop := make(chan struct{})
+	done := s.runRequest(debugLogParams{backlog: 10, filterLevel: loggo.INFO}, stop)
+	s.assertOutput(c, []string{
+		"ok",
+		"machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 first\n",
+	})
+	c.Assert(<-tailerParams, jc.DeepEquals, state.LogTailerParams{
+		MinLevel:     loggo.INFO,
+		InitialLines: 10,
+	})
+
+	s.sock.filters <- params.DebugLogFilters{
+		Level:         "WARNING",
+		IncludeEntity: []string{"unit-foo-*"},
+		ExcludeModule: []string{"juju.worker"},
+	}
+	s.assertOutput(c, []string{
+		"unit-foo-2: 2015-06-19 15:34:38 INFO some.where code.go:42 second\n",
+	})
+	c.Assert(<-tailerParams, jc.DeepEquals, state.LogTailerParams{
+		MinLevel:      loggo.WARNING,
+		StartTime:     t0,
+		IncludeEntity: []string{"unit-foo-*"},
+		ExcludeModule: []string{"juju.worker"},
+	})
+	c.Assert(first.stopped, jc.IsTrue)
+
+	close(stop)
+	s.assertStops(c, done, second)
+}
+
+func (s *debugLogDBIntSuite) TestChangeFiltersBeforeFirstRecord(c *gc.C) {
+	first := newFakeLogTailer()
+	second := newFakeLogTailer()
+	tailerParams := s.patchTailers(first, second)
+
+	stop := make(chan struct{})
+	done := s.runRequest(debugLogParams{backlog: 10}, stop)
+	s.assertOutput(c, []string{"ok"})
+	<-tailerParams
+
+	// Nothing has been sent, so the request starts again.
+	s.sock.filters <- params.DebugLogFilters{Level: "ERROR"}
+	c.Assert(<-tailerParams, jc.DeepEquals, state.LogTailerParams{
+		MinLevel:     loggo.ERROR,
+		InitialLines: 10,
+	})
+
+	close(stop)
+	s.assertStops(c, done, second)
+}
+
+func (s *debugLogDBIntSuite) TestChangeFiltersDiscardsQueuedRecords(c *gc.C) {
+	t0 := time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC)
+	first := newFakeLogTailer()
+	for i := 0; i < 3; i++ {
+		first.logsCh <- testLogRecord(t0.Add(time.Duration(i)*time.Second), "machine-99", fmt.Sprintf("old %d", i))
+	}
+	second := newFakeLogTailer()
+	second.logsCh <- testLogRecord(t0.Add(5*time.Second), "machine-99", "new")
+	tailerParams := s.patchTailers(first, second)
+
+	s.sock.blockSends()
+	stop := make(chan struct{})
+	done := s.runRequest(debugLogParams{}, stop)
+	s.assertOutput(c, []string{"ok"})
+	<-tailerParams
+	for a := coretesting.LongAttempt.Start(); len(first.logsCh) > 0; {
+		if !a.Next() {
+			c.Fatalf("timed out waiting for records to be queued")
+		}
+	}
+
+	// The record being sent when the filters change is sent; those
+	// still queued are not.
+	s.sock.filters <- params.DebugLogFilters{}
+	c.Assert((<-tailerParams).StartTime, gc.Equals, t0)
+	s.sock.unblockSends()
+	s.assertOutput(c, []string{
+		"machine-99: 2015-06-19 15:34:37 INFO some.where code.go:42 old 0\n",
+		"machine-99: 2015-06-19 15:34:42 INFO some.where code.go:42 new\n",
+	})
+
+	close(stop)
+	s.assertStops(c, done, second)
+}
+
+func (s *debugLogDBIntSuite) TestChangeFiltersInvalid(c *gc.C) {
+	tailer := s.patchTailer(c, 0)
+
+	stop := make(chan struct{})
+	done := s.runRequest(debugLogParams{}, stop)
+	s.assertOutput(c, []string{"ok"})
+
+	s.sock.filters <- params.DebugLogFilters{Level: "BOGUS"}
+	select {
+	case write := <-s.sock.writes:
+		c.Assert(write, gc.Matches, `: .* WARNING juju.apiserver.debuglog  debug-log filters not changed: level value "BOGUS" is not one of .*\n`)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for socket write")
+	}
+
+	close(stop)
+	s.assertStops(c, done, tailer)
+}
+
+// patchTailers patches newLogTailer to return each of the given
+// tailers in turn, and returns a channel on which the params used
+// to create each are sent.
+func (s *debugLogDBIntSuite) patchTailers(tailers ...*fakeLogTailer) <-chan state.LogTailerParams {
+	tailerParams := make(chan state.LogTailerParams, len(tailers))
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		tailer := tailers[0]
+		tailers = tailers[1:]
+		tailerParams <- params
+		return tailer, nil
+	})
+	return tailerParams
+}
+
+// testLogRecord returns a log record logged at t by the named entity.
+func testLogRecord(t time.Time, entity, message string) *state.LogRecord {
+	tag, err := names.ParseTag(entity)
+	if err != nil {
+		panic(err)
+	}
+	return &state.LogRecord{
+		ID:       t.UnixNano(),
+		Time:     t,
+		Entity:   tag,
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  message,
+	}
+}
+
+// patchTailer patches newLogTailer to return a fake tailer with n
+// records ready to send, and returns it.
+func (s *debugLogDBIntSuite) patchTailer(c *gc.C, n int) *fakeLogTailer {
+	tailer := newFakeLogTailer()
+	for i := 0; i < n; i++ {
+		tailer.logsCh <- &state.LogRecord{
+			Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+			Entity:   names.NewMachineTag("99"),
+			Module:   "some.where",
+			Location: "code.go:42",
+			Level:    loggo.INFO,
+			Message:  fmt.Sprintf("line %d", i),
+		}
+	}
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		return tailer, nil
+	})
+	return tailer
 }
 
 func (s *debugLogDBIntSuite) runRequest(params debugLogParams, stop chan struct{}) chan error {
 	done := make(chan error)
 	go func() {
-		done <- handleDebugLogDBRequest(&fakeState{}, params, s.sock, stop)
+		done <- handleDebugLogDBRequest(s.clock, &fakeState{}, params, s.sock, stop)
 	}()
 	return done
 }
@@ -231,12 +637,31 @@ func (t *fakeLogTailer) Err() error {
 
 func newFakeDebugLogSocket() *fakeDebugLogSocket {
 	return &fakeDebugLogSocket{
-		writes: make(chan string, 10),
+		writes:  make(chan string, 10),
+		filters: make(chan params.DebugLogFilters),
 	}
 }
 
 type fakeDebugLogSocket struct {
-	writes chan string
+	writes  chan string
+	filters chan params.DebugLogFilters
+	unblock chan struct{}
+}
+
+// blockSends causes the socket's sends to block until unblockSends
+// is called, as if the client had stopped reading.
+func (s *fakeDebugLogSocket) blockSends() {
+	s.unblock = make(chan struct{})
+}
+
+func (s *fakeDebugLogSocket) unblockSends() {
+	close(s.unblock)
+}
+
+func (s *fakeDebugLogSocket) wait() {
+	if s.unblock != nil {
+		<-s.unblock
+	}
 }
 
 func (s *fakeDebugLogSocket) sendOk() {
@@ -247,14 +672,49 @@ func (s *fakeDebugLogSocket) sendError(err error) {
 	s.writes <- fmt.Sprintf("err: %v", err)
 }
 
-func (s *fakeDebugLogSocket) sendLogRecord(r *params.LogMessage) error {
-	s.writes <- fmt.Sprintf("%s: %s %s %s %s %s\n",
+func (s *fakeDebugLogSocket) sendLogRecord(r *params.LogMessage) (int, error) {
+	s.wait()
+	write := fmt.Sprintf("%s: %s %s %s %s %s\n",
 		r.Entity,
 		s.formatTime(r.Timestamp),
 		r.Severity,
 		r.Module,
 		r.Location,
 		r.Message)
+	s.writes <- write
+	return len(write), nil
+}
+
+func (s *fakeDebugLogSocket) sendDebugLogRecord(r *params.DebugLogRecord) (int, error) {
+	s.wait()
+	data, err := json.Marshal(r)
+	if err != nil {
+		return 0, err
+	}
+	s.writes <- string(data)
+	return len(data), nil
+}
+
+func (s *fakeDebugLogSocket) sendTrailer(t *params.DebugLogTrailerMessage) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	s.writes <- string(data)
+	return nil
+}
+
+func (s *fakeDebugLogSocket) sendEnd() error {
+	s.writes <- "end"
+	return nil
+}
+
+func (s *fakeDebugLogSocket) readFilters(filters *params.DebugLogFilters) error {
+	f, ok := <-s.filters
+	if !ok {
+		return io.EOF
+	}
+	*filters = f
 	return nil
 }
 
@@ -4,16 +4,25 @@
 package apiserver_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/websocket/websockettest"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/version"
 )
 
 type debugLogDBSuite struct {
@@ -31,6 +40,107 @@ func (s *debugLogDBSuite) TestBadParams(c *gc.C) {
 	websockettest.AssertWebsocketClosed(c, reader)
 }
 
+func (s *debugLogDBSuite) TestBadEndTime(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"endTime": {"yesterday"}})
+	websockettest.AssertJSONError(c, reader, `end time "yesterday" is not a valid time in RFC3339 format`)
+	websockettest.AssertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestEndTimeBeforeStartTime(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{
+		"startTime": {"2017-06-01T10:00:00Z"},
+		"endTime":   {"2017-06-01T09:00:00Z"},
+	})
+	websockettest.AssertJSONError(c, reader, `end time "2017-06-01T09:00:00Z" is before start time`)
+	websockettest.AssertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestBadFormat(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"format": {"yaml"}})
+	websockettest.AssertJSONError(c, reader, `format value "yaml" is not one of "text", "json"`)
+	websockettest.AssertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestBadRateLimit(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"ratelimit": {"-1"}})
+	websockettest.AssertJSONError(c, reader, `ratelimit value "-1" is not a valid unsigned number`)
+	websockettest.AssertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestBadOverflow(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"overflow": {"explode"}})
+	websockettest.AssertJSONError(c, reader, `overflow value "explode" is not one of "block", "drop", "disconnect"`)
+	websockettest.AssertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestBadTrailer(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"trailer": {"sometimes"}})
+	websockettest.AssertJSONError(c, reader, `trailer value "sometimes" is not a valid boolean`)
+	websockettest.AssertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestBadCompress(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"compress": {"zip"}})
+	websockettest.AssertJSONError(c, reader, `compress value "zip" is not "gzip"`)
+	websockettest.AssertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestCompressed(c *gc.C) {
+	logger := state.NewDbLogger(s.State)
+	defer logger.Close()
+	t0 := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+	err := logger.Log([]state.LogRecord{{
+		Time:     t0,
+		Entity:   names.NewMachineTag("0"),
+		Version:  version.Current,
+		Module:   "juju.worker",
+		Location: "worker.go:1",
+		Level:    loggo.INFO,
+		Message:  "starting",
+	}, {
+		Time:     t0.Add(time.Minute),
+		Entity:   names.NewMachineTag("0"),
+		Version:  version.Current,
+		Module:   "juju.worker",
+		Location: "worker.go:2",
+		Level:    loggo.INFO,
+		Message:  "stopping",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	conn := s.openWebsocket(c, url.Values{
+		"replay":   {"true"},
+		"noTail":   {"true"},
+		"compress": {"gzip"},
+	})
+	result := websockettest.ReadJSONErrorLine(c, conn)
+	c.Assert(result.Error, gc.IsNil)
+
+	// The records arrive as successive chunks of a gzip stream,
+	// each of which may be decompressed as soon as it is received.
+	var compressed bytes.Buffer
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			c.Assert(websocket.IsCloseError(err, websocket.CloseNormalClosure), jc.IsTrue)
+			break
+		}
+		c.Assert(messageType, gc.Equals, websocket.BinaryMessage)
+		compressed.Write(data)
+	}
+	zr, err := gzip.NewReader(&compressed)
+	c.Assert(err, jc.ErrorIsNil)
+	decoder := json.NewDecoder(zr)
+	var messages []string
+	for i := 0; i < 2; i++ {
+		var record params.LogMessage
+		err := decoder.Decode(&record)
+		c.Assert(err, jc.ErrorIsNil)
+		messages = append(messages, record.Message)
+	}
+	c.Assert(messages, jc.DeepEquals, []string{"starting", "stopping"})
+}
+
 func (s *debugLogDBSuite) TestWithHTTP(c *gc.C) {
 	uri := s.logURL(c, "http", nil).String()
 	s.sendRequest(c, httpRequestParams{
@@ -92,6 +202,38 @@ func (s *debugLogDBSuite) TestMachineLoginsAccepted(c *gc.C) {
 	c.Assert(result.Error, gc.IsNil)
 }
 
+func (s *debugLogDBSuite) TestModelFilterUnknownModel(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"includeModel": {"nonexistent"}})
+	websockettest.AssertJSONError(c, reader, "model filters match no models")
+	websockettest.AssertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogDBSuite) TestModelFilterRequiresSuperuser(c *gc.C) {
+	u := s.Factory.MakeUser(c, &factory.UserParams{
+		Name:     "oryx",
+		Password: "gardener",
+	})
+	header := utils.BasicAuthHeader(u.Tag().String(), "gardener")
+	values := url.Values{"includeModel": {s.State.ModelUUID()}}
+	conn := s.dialWebsocketInternal(c, values, header)
+	defer conn.Close()
+
+	websockettest.AssertJSONError(c, conn, "permission denied")
+	websockettest.AssertWebsocketClosed(c, conn)
+}
+
+func (s *debugLogDBSuite) TestModelFilterAccepted(c *gc.C) {
+	values := url.Values{
+		"includeModel": {s.State.ModelUUID()},
+		"maxLines":     {"0"},
+		"noTail":       {"true"},
+	}
+	conn := s.openWebsocket(c, values)
+
+	result := websockettest.ReadJSONErrorLine(c, conn)
+	c.Assert(result.Error, gc.IsNil)
+}
+
 func (s *debugLogDBSuite) openWebsocket(c *gc.C, values url.Values) *websocket.Conn {
 	conn := s.dialWebsocket(c, values)
 	s.AddCleanup(func(_ *gc.C) { conn.Close() })
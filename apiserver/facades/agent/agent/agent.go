@@ -21,6 +21,12 @@ import (
 	"github.com/juju/juju/state/watcher"
 )
 
+// AgentAPIV3 implements the version 3 of the API provided to an agent,
+// which adds WatchCloudSpecsChanges.
+type AgentAPIV3 struct {
+	*AgentAPIV2
+}
+
 // AgentAPIV2 implements the version 2 of the API provided to an agent.
 type AgentAPIV2 struct {
 	*common.PasswordChanger
@@ -57,6 +63,16 @@ func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Auth
 	}, nil
 }
 
+// NewAgentAPIV3 returns an object implementing version 3 of the Agent API
+// with the given authorizer representing the currently logged in client.
+func NewAgentAPIV3(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV3, error) {
+	v2, err := NewAgentAPIV2(st, resources, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &AgentAPIV3{v2}, nil
+}
+
 func (api *AgentAPIV2) GetEntities(args params.Entities) params.AgentGetEntitiesResults {
 	results := params.AgentGetEntitiesResults{
 		Entities: make([]params.AgentGetEntitiesResult, len(args.Entities)),
@@ -169,16 +185,58 @@ func (api *AgentAPIV2) WatchCredentials(args params.Entities) (params.NotifyWatc
 			results.Results[i].Error = common.ServerError(err)
 			continue
 		}
-		watch := api.st.WatchCredential(credentialTag)
-		// Consume the initial event. Technically, API calls to Watch
-		// 'transmit' the initial event in the Watch response. But
-		// NotifyWatchers have no state to transmit.
-		if _, ok := <-watch.Changes(); ok {
-			results.Results[i].NotifyWatcherId = api.resources.Register(watch)
-		} else {
-			err = watcher.EnsureErr(watch)
+		results.Results[i] = api.watchCredential(credentialTag)
+	}
+	return results, nil
+}
+
+// WatchCloudSpecsChanges watches for changes to the credentials of the
+// specified models, so that their environs can be given the new
+// credentials.
+func (api *AgentAPIV3) WatchCloudSpecsChanges(args params.Entities) (params.NotifyWatchResults, error) {
+	if !api.auth.AuthController() {
+		return params.NotifyWatchResults{}, common.ErrPerm
+	}
+
+	results := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		modelTag, err := names.ParseModelTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if modelTag != api.st.ModelTag() {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		model, err := api.st.Model()
+		if err != nil {
 			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		credentialTag, ok := model.CloudCredential()
+		if !ok {
+			results.Results[i].Error = common.ServerError(errors.NotFoundf("credential for %s", names.ReadableString(modelTag)))
+			continue
 		}
+		results.Results[i] = api.watchCredential(credentialTag)
 	}
 	return results, nil
 }
+
+func (api *AgentAPIV2) watchCredential(tag names.CloudCredentialTag) params.NotifyWatchResult {
+	var result params.NotifyWatchResult
+	watch := api.st.WatchCredential(tag)
+	// Consume the initial event. Technically, API calls to Watch
+	// 'transmit' the initial event in the Watch response. But
+	// NotifyWatchers have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = api.resources.Register(watch)
+	} else {
+		err := watcher.EnsureErr(watch)
+		result.Error = common.ServerError(err)
+	}
+	return result
+}
@@ -265,6 +265,50 @@ func (s *agentSuite) TestWatchCredentials(c *gc.C) {
 	wc.AssertOneChange()
 }
 
+func (s *agentSuite) TestWatchCloudSpecsChanges(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag:        names.NewMachineTag("0"),
+		Controller: true,
+	}
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.WatchCloudSpecsChanges(params.Entities{Entities: []params.Entity{
+		{Tag: s.State.ModelTag().String()},
+		{Tag: names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d").String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResults{Results: []params.NotifyWatchResult{
+		{NotifyWatcherId: "1"},
+		{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+	}})
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+
+	w := s.resources.Get("1")
+	defer statetesting.AssertStop(c, w)
+
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	model, err := s.State.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	tag, ok := model.CloudCredential()
+	c.Assert(ok, jc.IsTrue)
+	err = s.State.UpdateCloudCredential(tag, cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"username": "fred",
+		"password": "secret",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *agentSuite) TestWatchCloudSpecsChangesAuthError(c *gc.C) {
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.WatchCloudSpecsChanges(params.Entities{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+}
+
 func (s *agentSuite) TestWatchAuthError(c *gc.C) {
 	authorizer := apiservertesting.FakeAuthorizer{
 		Tag:        names.NewMachineTag("1"),
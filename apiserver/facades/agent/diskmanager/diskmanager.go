@@ -4,9 +4,11 @@
 package diskmanager
 
 import (
+	"github.com/juju/errors"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
@@ -20,6 +22,12 @@ type DiskManagerAPI struct {
 	getAuthFunc common.GetAuthFunc
 }
 
+// DiskManagerAPIV2 provides access to version 2 of the DiskManager API
+// facade, which cannot report filesystem usage.
+type DiskManagerAPIV2 struct {
+	DiskManagerAPI
+}
+
 var getState = func(st *state.State) stateInterface {
 	return stateShim{st}
 }
@@ -50,6 +58,20 @@ func NewDiskManagerAPI(
 	}, nil
 }
 
+// NewDiskManagerAPIV2 creates a new server-side DiskManager API
+// facade, version 2.
+func NewDiskManagerAPIV2(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*DiskManagerAPIV2, error) {
+	api, err := NewDiskManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &DiskManagerAPIV2{*api}, nil
+}
+
 func (d *DiskManagerAPI) SetMachineBlockDevices(args params.SetMachineBlockDevices) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.MachineBlockDevices)),
@@ -105,3 +127,88 @@ func stateBlockDeviceInfo(devices []storage.BlockDevice) []state.BlockDeviceInfo
 	}
 	return result
 }
+
+// MachineFilesystemAttachments returns the provisioned filesystem
+// attachments of each of the specified machines, so that their agents
+// can report the usage of the filesystems.
+func (d *DiskManagerAPI) MachineFilesystemAttachments(args params.Entities) (params.FilesystemAttachmentsResults, error) {
+	result := params.FilesystemAttachmentsResults{
+		Results: make([]params.FilesystemAttachmentsResult, len(args.Entities)),
+	}
+	canAccess, err := d.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	one := func(arg params.Entity) ([]params.FilesystemAttachment, error) {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return nil, common.ErrPerm
+		}
+		attachments, err := d.st.MachineFilesystemAttachments(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var out []params.FilesystemAttachment
+		for _, attachment := range attachments {
+			if attachment.Life() != state.Alive {
+				continue
+			}
+			p, err := storagecommon.FilesystemAttachmentFromState(attachment)
+			if errors.IsNotProvisioned(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			out = append(out, p)
+		}
+		return out, nil
+	}
+	for i, arg := range args.Entities {
+		attachments, err := one(arg)
+		result.Results[i].Result = attachments
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetMachineFilesystemUsage records the usage of the filesystems
+// attached to each of the specified machines.
+func (d *DiskManagerAPI) SetMachineFilesystemUsage(args params.SetMachineFilesystemUsage) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.MachineFilesystemUsage)),
+	}
+	canAccess, err := d.getAuthFunc()
+	if err != nil {
+		return result, err
+	}
+	one := func(arg params.MachineFilesystemUsage) error {
+		tag, err := names.ParseMachineTag(arg.Machine)
+		if err != nil || !canAccess(tag) {
+			return common.ErrPerm
+		}
+		usage := make(map[names.FilesystemTag]state.FilesystemUsage, len(arg.Filesystems))
+		for _, fs := range arg.Filesystems {
+			filesystemTag, err := names.ParseFilesystemTag(fs.FilesystemTag)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			usage[filesystemTag] = state.FilesystemUsage{
+				UsedBytes:      fs.Usage.UsedBytes,
+				AvailableBytes: fs.Usage.AvailableBytes,
+			}
+		}
+		return d.st.SetMachineFilesystemUsage(tag.Id(), usage)
+	}
+	for i, arg := range args.MachineFilesystemUsage {
+		result.Results[i].Error = common.ServerError(one(arg))
+	}
+	return result, nil
+}
+
+// MachineFilesystemAttachments is not available in version 2 of the
+// facade.
+func (*DiskManagerAPIV2) MachineFilesystemAttachments(_, _ struct{}) {}
+
+// SetMachineFilesystemUsage is not available in version 2 of the
+// facade.
+func (*DiskManagerAPIV2) SetMachineFilesystemUsage(_, _ struct{}) {}
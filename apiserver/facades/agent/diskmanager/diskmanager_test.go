@@ -4,8 +4,7 @@
 package diskmanager_test
 
 import (
-	"errors"
-
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
@@ -107,10 +106,83 @@ func (s *DiskManagerSuite) TestSetMachineBlockDevicesStateError(c *gc.C) {
 	})
 }
 
+func (s *DiskManagerSuite) TestMachineFilesystemAttachments(c *gc.C) {
+	s.st.attachments = []state.FilesystemAttachment{
+		&mockFilesystemAttachment{
+			filesystem: names.NewFilesystemTag("0"),
+			machine:    names.NewMachineTag("0"),
+			life:       state.Alive,
+			info:       &state.FilesystemAttachmentInfo{MountPoint: "/srv/data"},
+		},
+		&mockFilesystemAttachment{
+			filesystem: names.NewFilesystemTag("1"),
+			machine:    names.NewMachineTag("0"),
+			life:       state.Alive,
+		},
+		&mockFilesystemAttachment{
+			filesystem: names.NewFilesystemTag("2"),
+			machine:    names.NewMachineTag("0"),
+			life:       state.Dying,
+			info:       &state.FilesystemAttachmentInfo{MountPoint: "/srv/old"},
+		},
+	}
+	results, err := s.api.MachineFilesystemAttachments(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.FilesystemAttachmentsResults{
+		Results: []params.FilesystemAttachmentsResult{{
+			Result: []params.FilesystemAttachment{{
+				FilesystemTag: "filesystem-0",
+				MachineTag:    "machine-0",
+				Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv/data"},
+			}},
+		}, {
+			Error: &params.Error{Message: "permission denied", Code: "unauthorized access"},
+		}},
+	})
+}
+
+func (s *DiskManagerSuite) TestSetMachineFilesystemUsage(c *gc.C) {
+	results, err := s.api.SetMachineFilesystemUsage(params.SetMachineFilesystemUsage{
+		MachineFilesystemUsage: []params.MachineFilesystemUsage{{
+			Machine: "machine-0",
+			Filesystems: []params.FilesystemAttachmentUsage{{
+				FilesystemTag: "filesystem-0",
+				Usage:         params.FilesystemUsage{UsedBytes: 1024, AvailableBytes: 2048},
+			}},
+		}, {
+			Machine: "machine-1",
+		}, {
+			Machine: "machine-0",
+			Filesystems: []params.FilesystemAttachmentUsage{{
+				FilesystemTag: "volume-0",
+			}},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: nil,
+		}, {
+			Error: &params.Error{Message: "permission denied", Code: "unauthorized access"},
+		}, {
+			Error: &params.Error{Message: `"volume-0" is not a valid filesystem tag`},
+		}},
+	})
+	c.Assert(s.st.usage, jc.DeepEquals, map[string]map[names.FilesystemTag]state.FilesystemUsage{
+		"0": {
+			names.NewFilesystemTag("0"): {UsedBytes: 1024, AvailableBytes: 2048},
+		},
+	})
+}
+
 type mockState struct {
-	calls   int
-	devices map[string][]state.BlockDeviceInfo
-	err     error
+	calls       int
+	devices     map[string][]state.BlockDeviceInfo
+	attachments []state.FilesystemAttachment
+	usage       map[string]map[names.FilesystemTag]state.FilesystemUsage
+	err         error
 }
 
 func (st *mockState) SetMachineBlockDevices(machineId string, devices []state.BlockDeviceInfo) error {
@@ -121,3 +193,42 @@ func (st *mockState) SetMachineBlockDevices(machineId string, devices []state.Bl
 	st.devices[machineId] = devices
 	return st.err
 }
+
+func (st *mockState) MachineFilesystemAttachments(machineId string) ([]state.FilesystemAttachment, error) {
+	return st.attachments, st.err
+}
+
+func (st *mockState) SetMachineFilesystemUsage(machineId string, usage map[names.FilesystemTag]state.FilesystemUsage) error {
+	if st.usage == nil {
+		st.usage = make(map[string]map[names.FilesystemTag]state.FilesystemUsage)
+	}
+	st.usage[machineId] = usage
+	return st.err
+}
+
+type mockFilesystemAttachment struct {
+	state.FilesystemAttachment
+	filesystem names.FilesystemTag
+	machine    names.MachineTag
+	life       state.Life
+	info       *state.FilesystemAttachmentInfo
+}
+
+func (a *mockFilesystemAttachment) Filesystem() names.FilesystemTag {
+	return a.filesystem
+}
+
+func (a *mockFilesystemAttachment) Machine() names.MachineTag {
+	return a.machine
+}
+
+func (a *mockFilesystemAttachment) Life() state.Life {
+	return a.life
+}
+
+func (a *mockFilesystemAttachment) Info() (state.FilesystemAttachmentInfo, error) {
+	if a.info == nil {
+		return state.FilesystemAttachmentInfo{}, errors.NotProvisionedf("filesystem attachment")
+	}
+	return *a.info, nil
+}
@@ -3,10 +3,17 @@
 
 package diskmanager
 
-import "github.com/juju/juju/state"
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
 
 type stateInterface interface {
 	SetMachineBlockDevices(machineId string, devices []state.BlockDeviceInfo) error
+	MachineFilesystemAttachments(machineId string) ([]state.FilesystemAttachment, error)
+	SetMachineFilesystemUsage(machineId string, usage map[names.FilesystemTag]state.FilesystemUsage) error
 }
 
 type stateShim struct {
@@ -20,3 +27,19 @@ func (s stateShim) SetMachineBlockDevices(machineId string, devices []state.Bloc
 	}
 	return m.SetMachineBlockDevices(devices...)
 }
+
+func (s stateShim) MachineFilesystemAttachments(machineId string) ([]state.FilesystemAttachment, error) {
+	im, err := s.State.IAASModel()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return im.MachineFilesystemAttachments(names.NewMachineTag(machineId))
+}
+
+func (s stateShim) SetMachineFilesystemUsage(machineId string, usage map[names.FilesystemTag]state.FilesystemUsage) error {
+	im, err := s.State.IAASModel()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return im.SetMachineFilesystemUsage(names.NewMachineTag(machineId), usage)
+}
@@ -47,7 +47,8 @@ func NewLoggerAPI(
 // WatchLoggingConfig starts a watcher to track changes to the logging config
 // for the agents specified..  Unfortunately the current infrastruture makes
 // watching parts of the config non-trivial, so currently any change to the
-// config will cause the watcher to notify the client.
+// config will cause the watcher to notify the client. Changes to the
+// agent's logging override also cause the watcher to notify.
 func (api *LoggerAPI) WatchLoggingConfig(arg params.Entities) params.NotifyWatchResults {
 	result := make([]params.NotifyWatchResult, len(arg.Entities))
 	for i, entity := range arg.Entities {
@@ -58,7 +59,10 @@ func (api *LoggerAPI) WatchLoggingConfig(arg params.Entities) params.NotifyWatch
 		}
 		err = common.ErrPerm
 		if api.authorizer.AuthOwner(tag) {
-			watch := api.state.WatchForModelConfigChanges()
+			watch := common.NewMultiNotifyWatcher(
+				api.state.WatchForModelConfigChanges(),
+				api.state.WatchAgentLoggingOverride(tag),
+			)
 			// Consume the initial event. Technically, API calls to Watch
 			// 'transmit' the initial event in the Watch response. But
 			// NotifyWatchers have no state to transmit.
@@ -74,7 +78,9 @@ func (api *LoggerAPI) WatchLoggingConfig(arg params.Entities) params.NotifyWatch
 	return params.NotifyWatchResults{Results: result}
 }
 
-// LoggingConfig reports the logging configuration for the agents specified.
+// LoggingConfig reports the logging configuration for the agents specified:
+// the model's logging-config, followed by any override set for the agent
+// so that the override's module levels take precedence.
 func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
 	if len(arg.Entities) == 0 {
 		return params.StringResults{}
@@ -90,8 +96,9 @@ func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
 		err = common.ErrPerm
 		if api.authorizer.AuthOwner(tag) {
 			if configErr == nil {
-				results[i].Result = config.LoggingConfig()
-				err = nil
+				var override string
+				override, err = api.state.AgentLoggingOverride(tag)
+				results[i].Result = combineLoggingConfig(config.LoggingConfig(), override)
 			} else {
 				err = configErr
 			}
@@ -100,3 +107,15 @@ func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
 	}
 	return params.StringResults{Results: results}
 }
+
+// combineLoggingConfig returns a loggo configuration string in which
+// the module levels in override replace those in base.
+func combineLoggingConfig(base, override string) string {
+	switch {
+	case override == "":
+		return base
+	case base == "":
+		return override
+	}
+	return base + ";" + override
+}
@@ -144,3 +144,45 @@ func (s *loggerSuite) TestLoggingConfigForAgent(c *gc.C) {
 	c.Assert(result.Error, gc.IsNil)
 	c.Assert(result.Result, gc.Equals, newLoggingConfig)
 }
+
+func (s *loggerSuite) TestLoggingConfigWithOverride(c *gc.C) {
+	s.setLoggingConfig(c, "<root>=WARN;juju.worker=INFO")
+	err := s.State.SetAgentLoggingOverride(s.rawMachine.Tag(), "juju.worker=DEBUG")
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results := s.logger.LoggingConfig(args)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result, gc.Equals, "<root>=WARN;juju.worker=INFO;juju.worker=DEBUG")
+}
+
+func (s *loggerSuite) TestWatchLoggingConfigOverride(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results := s.logger.WatchLoggingConfig(args)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	resource := s.resources.Get(results.Results[0].NotifyWatcherId)
+	c.Assert(resource, gc.NotNil)
+
+	w := resource.(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	err := s.State.SetAgentLoggingOverride(s.rawMachine.Tag(), "juju.worker=DEBUG")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Overrides for other agents are not reported.
+	err = s.State.SetAgentLoggingOverride(names.NewMachineTag("42"), "juju.worker=DEBUG")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
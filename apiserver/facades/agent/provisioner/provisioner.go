@@ -119,7 +119,7 @@ func NewProvisionerAPI(st *state.State, resources facade.Resources, authorizer f
 		PasswordChanger:         common.NewPasswordChanger(st, getAuthFunc),
 		LifeGetter:              common.NewLifeGetter(st, getAuthFunc),
 		StateAddresser:          common.NewStateAddresser(st),
-		APIAddresser:            common.NewAPIAddresser(st, resources),
+		APIAddresser:            common.NewAgentAPIAddresser(st, resources),
 		ModelWatcher:            common.NewModelWatcher(st, resources, authorizer),
 		ModelMachinesWatcher:    common.NewModelMachinesWatcher(st, resources, authorizer),
 		ControllerConfigAPI:     common.NewStateControllerConfig(st),
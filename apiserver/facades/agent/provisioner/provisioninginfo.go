@@ -76,7 +76,7 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Envi
 		return nil, errors.Trace(err)
 	}
 
-	subnetsToZones, err := p.machineSubnetsAndZones(m)
+	subnetsToZones, err := p.machineSubnetsAndZones(m, env)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot match subnets to zones")
 	}
@@ -240,8 +240,10 @@ func (p *ProvisionerAPI) machineTags(m *state.Machine, jobs []multiwatcher.Machi
 // machineSubnetsAndZones returns a map of subnet provider-specific id
 // to list of availability zone names for that subnet. The result can
 // be empty if there are no spaces constraints specified for the
-// machine, or there's an error fetching them.
-func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]string, error) {
+// machine, or there's an error fetching them. An error satisfying
+// errors.IsNotSupported is returned if spaces constraints are specified
+// but the environ does not support spaces.
+func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine, env environs.Environ) (map[string][]string, error) {
 	mcons, err := m.Constraints()
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot get machine constraints")
@@ -251,6 +253,9 @@ func (p *ProvisionerAPI) machineSubnetsAndZones(m *state.Machine) (map[string][]
 		// Nothing to do.
 		return nil, nil
 	}
+	if !environs.SupportsSpaces(env) {
+		return nil, errors.NotSupportedf("spaces constraints")
+	}
 	// TODO(dimitern): For the network model MVP we only use the first
 	// included space and ignore the rest.
 	//
@@ -24,3 +24,5 @@ func NewStorageAPI(
 ) (*StorageAPI, error) {
 	return newStorageAPI(storageStateInterface(st), resources, accessUnit)
 }
+
+var NewPacedRelationUnitsWatcher = newPacedRelationUnitsWatcher
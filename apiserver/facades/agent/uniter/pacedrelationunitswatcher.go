@@ -0,0 +1,209 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// pacedRelationUnitsWatcher wraps a RelationUnitsWatcher so that units
+// entering the relation are reported at most batchSize at a time, with
+// at least interval between batches. When an application scales out,
+// each unit of the related application then runs relation-joined hooks
+// for the new units a few at a time, rather than all at once.
+//
+// The units in the relation when the watcher starts are reported at
+// once, in the initial event, as the unit watching may already know of
+// them. Settings changes for units that have been reported, and
+// departures of those units, are passed on immediately. A unit that
+// departs before it has been reported is never reported at all.
+type pacedRelationUnitsWatcher struct {
+	catacomb  catacomb.Catacomb
+	source    state.RelationUnitsWatcher
+	clock     clock.Clock
+	batchSize int
+	interval  time.Duration
+	out       chan params.RelationUnitsChange
+}
+
+// newPacedRelationUnitsWatcher returns a watcher that reports the
+// changes from source, releasing joining units in batches of at most
+// batchSize, at least interval apart. The returned watcher takes
+// responsibility for stopping source.
+func newPacedRelationUnitsWatcher(
+	source state.RelationUnitsWatcher,
+	clock clock.Clock,
+	batchSize int,
+	interval time.Duration,
+) (state.RelationUnitsWatcher, error) {
+	w := &pacedRelationUnitsWatcher{
+		source:    source,
+		clock:     clock,
+		batchSize: batchSize,
+		interval:  interval,
+		out:       make(chan params.RelationUnitsChange),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+		Init: []worker.Worker{source},
+	})
+	return w, errors.Trace(err)
+}
+
+func (w *pacedRelationUnitsWatcher) loop() error {
+	defer close(w.out)
+	var (
+		receivedInitial bool
+		sentInitial     bool
+		changes         params.RelationUnitsChange
+		out             chan<- params.RelationUnitsChange
+
+		// joined holds the units that have been reported.
+		joined = set.NewStrings()
+
+		// waiting holds the units that have entered the relation
+		// but have not yet been reported, in the order they
+		// entered, and waitingSettings their latest settings.
+		waiting         []string
+		waitingSettings = make(map[string]params.UnitSettings)
+
+		// release delivers a value when the next batch of waiting
+		// units may be reported, which is not before nextRelease.
+		release     <-chan time.Time
+		nextRelease time.Time
+	)
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case c, ok := <-w.source.Changes():
+			if !ok {
+				return w.catacomb.ErrDying()
+			}
+			// Units entering together are queued in name order.
+			names := make([]string, 0, len(c.Changed))
+			for name := range c.Changed {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				settings := c.Changed[name]
+				if !receivedInitial {
+					joined.Add(name)
+				}
+				if joined.Contains(name) {
+					setRelationUnitSettings(&changes, name, settings)
+					continue
+				}
+				if _, found := waitingSettings[name]; !found {
+					waiting = append(waiting, name)
+				}
+				waitingSettings[name] = settings
+			}
+			for _, name := range c.Departed {
+				if joined.Contains(name) {
+					joined.Remove(name)
+					delete(changes.Changed, name)
+					changes.Departed = append(changes.Departed, name)
+				} else if _, found := waitingSettings[name]; found {
+					delete(waitingSettings, name)
+					waiting = removeOrdered(waiting, name)
+				}
+			}
+			receivedInitial = true
+		case <-release:
+			release = nil
+		case out <- changes:
+			sentInitial = true
+			changes = params.RelationUnitsChange{}
+		}
+
+		if len(waiting) > 0 && release == nil {
+			now := w.clock.Now()
+			if !now.Before(nextRelease) {
+				n := len(waiting)
+				if n > w.batchSize {
+					n = w.batchSize
+				}
+				for _, name := range waiting[:n] {
+					joined.Add(name)
+					changes.Departed = removeOrdered(changes.Departed, name)
+					setRelationUnitSettings(&changes, name, waitingSettings[name])
+					delete(waitingSettings, name)
+				}
+				waiting = waiting[n:]
+				nextRelease = now.Add(w.interval)
+				if len(waiting) > 0 {
+					logger.Debugf("%d units waiting to be reported as joining relation", len(waiting))
+				}
+			}
+			if len(waiting) > 0 {
+				release = w.clock.After(nextRelease.Sub(now))
+			}
+		}
+
+		if !receivedInitial {
+			out = nil
+		} else if !sentInitial || len(changes.Changed) > 0 || len(changes.Departed) > 0 {
+			out = w.out
+		} else {
+			out = nil
+		}
+	}
+}
+
+func setRelationUnitSettings(changes *params.RelationUnitsChange, name string, settings params.UnitSettings) {
+	if changes.Changed == nil {
+		changes.Changed = make(map[string]params.UnitSettings)
+	}
+	changes.Changed[name] = settings
+}
+
+// removeOrdered removes s from strs, preserving the order of the
+// remaining elements, and returns the modified slice.
+func removeOrdered(strs []string, s string) []string {
+	for i, v := range strs {
+		if v == s {
+			return append(strs[:i], strs[i+1:]...)
+		}
+	}
+	return strs
+}
+
+// Changes implements state.RelationUnitsWatcher.
+func (w *pacedRelationUnitsWatcher) Changes() <-chan params.RelationUnitsChange {
+	return w.out
+}
+
+// Err implements state.RelationUnitsWatcher.
+func (w *pacedRelationUnitsWatcher) Err() error {
+	return w.catacomb.Err()
+}
+
+// Kill implements state.RelationUnitsWatcher.
+func (w *pacedRelationUnitsWatcher) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Stop implements state.RelationUnitsWatcher.
+func (w *pacedRelationUnitsWatcher) Stop() error {
+	w.Kill()
+	return w.Wait()
+}
+
+// Wait implements state.RelationUnitsWatcher.
+func (w *pacedRelationUnitsWatcher) Wait() error {
+	return w.catacomb.Wait()
+}
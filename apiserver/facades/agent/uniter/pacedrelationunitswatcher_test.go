@@ -0,0 +1,192 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"sync"
+	"time"
+
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/agent/uniter"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type pacedRelationUnitsWatcherSuite struct {
+	coretesting.BaseSuite
+	clock  *jujutesting.Clock
+	source *fakeRelationUnitsWatcher
+}
+
+var _ = gc.Suite(&pacedRelationUnitsWatcherSuite{})
+
+func (s *pacedRelationUnitsWatcherSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = jujutesting.NewClock(time.Now())
+	s.source = newFakeRelationUnitsWatcher()
+}
+
+func (s *pacedRelationUnitsWatcherSuite) startWatcher(c *gc.C, batchSize int) state.RelationUnitsWatcher {
+	w, err := uniter.NewPacedRelationUnitsWatcher(s.source, s.clock, batchSize, 10*time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) {
+		c.Check(w.Stop(), jc.ErrorIsNil)
+	})
+	return w
+}
+
+func (s *pacedRelationUnitsWatcherSuite) sendSource(c *gc.C, change params.RelationUnitsChange) {
+	select {
+	case s.source.changes <- change:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending change")
+	}
+}
+
+func assertRelationUnitsChange(c *gc.C, w state.RelationUnitsWatcher, expect params.RelationUnitsChange) {
+	select {
+	case change, ok := <-w.Changes():
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(change, jc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for change")
+	}
+}
+
+func assertNoRelationUnitsChange(c *gc.C, w state.RelationUnitsWatcher) {
+	select {
+	case change := <-w.Changes():
+		c.Fatalf("unexpected change %#v", change)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *pacedRelationUnitsWatcherSuite) TestInitialEventUnpaced(c *gc.C) {
+	w := s.startWatcher(c, 1)
+	initial := params.RelationUnitsChange{
+		Changed: map[string]params.UnitSettings{
+			"mysql/0": {Version: 1},
+			"mysql/1": {Version: 1},
+			"mysql/2": {Version: 1},
+		},
+	}
+	s.sendSource(c, initial)
+	assertRelationUnitsChange(c, w, initial)
+	assertNoRelationUnitsChange(c, w)
+}
+
+func (s *pacedRelationUnitsWatcherSuite) TestJoiningUnitsBatched(c *gc.C) {
+	w := s.startWatcher(c, 2)
+	s.sendSource(c, params.RelationUnitsChange{})
+	assertRelationUnitsChange(c, w, params.RelationUnitsChange{})
+	s.sendSource(c, params.RelationUnitsChange{
+		Changed: map[string]params.UnitSettings{
+			"mysql/2": {Version: 1},
+			"mysql/0": {Version: 1},
+			"mysql/1": {Version: 1},
+		},
+	})
+	assertRelationUnitsChange(c, w, params.RelationUnitsChange{
+		Changed: map[string]params.UnitSettings{
+			"mysql/0": {Version: 1},
+			"mysql/1": {Version: 1},
+		},
+	})
+
+	// Units already reported are not held back.
+	s.sendSource(c, params.RelationUnitsChange{
+		Changed: map[string]params.UnitSettings{
+			"mysql/1": {Version: 2},
+			"mysql/2": {Version: 2},
+		},
+	})
+	assertRelationUnitsChange(c, w, params.RelationUnitsChange{
+		Changed: map[string]params.UnitSettings{"mysql/1": {Version: 2}},
+	})
+	s.sendSource(c, params.RelationUnitsChange{Departed: []string{"mysql/0"}})
+	assertRelationUnitsChange(c, w, params.RelationUnitsChange{Departed: []string{"mysql/0"}})
+	assertNoRelationUnitsChange(c, w)
+
+	// The next batch has the latest settings of the waiting unit.
+	s.clock.WaitAdvance(10*time.Second, coretesting.LongWait, 1)
+	assertRelationUnitsChange(c, w, params.RelationUnitsChange{
+		Changed: map[string]params.UnitSettings{"mysql/2": {Version: 2}},
+	})
+}
+
+func (s *pacedRelationUnitsWatcherSuite) TestWaitingUnitDeparts(c *gc.C) {
+	w := s.startWatcher(c, 1)
+	s.sendSource(c, params.RelationUnitsChange{})
+	assertRelationUnitsChange(c, w, params.RelationUnitsChange{})
+	s.sendSource(c, params.RelationUnitsChange{
+		Changed: map[string]params.UnitSettings{
+			"mysql/0": {Version: 1},
+			"mysql/1": {Version: 1},
+		},
+	})
+	assertRelationUnitsChange(c, w, params.RelationUnitsChange{
+		Changed: map[string]params.UnitSettings{"mysql/0": {Version: 1}},
+	})
+	s.sendSource(c, params.RelationUnitsChange{Departed: []string{"mysql/1"}})
+	s.clock.WaitAdvance(10*time.Second, coretesting.LongWait, 1)
+	assertNoRelationUnitsChange(c, w)
+}
+
+func (s *pacedRelationUnitsWatcherSuite) TestEmptyInitialEvent(c *gc.C) {
+	w := s.startWatcher(c, 1)
+	s.sendSource(c, params.RelationUnitsChange{})
+	assertRelationUnitsChange(c, w, params.RelationUnitsChange{})
+}
+
+func (s *pacedRelationUnitsWatcherSuite) TestStopStopsSource(c *gc.C) {
+	w, err := uniter.NewPacedRelationUnitsWatcher(s.source, s.clock, 1, time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Stop(), jc.ErrorIsNil)
+	select {
+	case <-s.source.dead:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("source watcher not stopped")
+	}
+	_, ok := <-w.Changes()
+	c.Assert(ok, jc.IsFalse)
+}
+
+type fakeRelationUnitsWatcher struct {
+	changes chan params.RelationUnitsChange
+	dead    chan struct{}
+	once    sync.Once
+}
+
+func newFakeRelationUnitsWatcher() *fakeRelationUnitsWatcher {
+	return &fakeRelationUnitsWatcher{
+		changes: make(chan params.RelationUnitsChange),
+		dead:    make(chan struct{}),
+	}
+}
+
+func (w *fakeRelationUnitsWatcher) Changes() <-chan params.RelationUnitsChange {
+	return w.changes
+}
+
+func (w *fakeRelationUnitsWatcher) Kill() {
+	w.once.Do(func() { close(w.dead) })
+}
+
+func (w *fakeRelationUnitsWatcher) Wait() error {
+	<-w.dead
+	return nil
+}
+
+func (w *fakeRelationUnitsWatcher) Stop() error {
+	w.Kill()
+	return w.Wait()
+}
+
+func (w *fakeRelationUnitsWatcher) Err() error {
+	return nil
+}
@@ -185,16 +185,41 @@ func (s *StorageAPI) fromStateStorageAttachment(stateStorageAttachment state.Sto
 	if owner, ok := stateStorageInstance.Owner(); ok {
 		ownerTag = owner.String()
 	}
+	var usage *params.FilesystemUsage
+	if stateStorageInstance.Kind() == state.StorageKindFilesystem {
+		usage, err = s.filesystemAttachmentUsage(stateStorageInstance.StorageTag(), machineTag)
+		if err != nil {
+			return params.StorageAttachment{}, err
+		}
+	}
 	return params.StorageAttachment{
-		stateStorageAttachment.StorageInstance().String(),
-		ownerTag,
-		stateStorageAttachment.Unit().String(),
-		params.StorageKind(stateStorageInstance.Kind()),
-		info.Location,
-		params.Life(stateStorageAttachment.Life().String()),
+		StorageTag: stateStorageAttachment.StorageInstance().String(),
+		OwnerTag:   ownerTag,
+		UnitTag:    stateStorageAttachment.Unit().String(),
+		Kind:       params.StorageKind(stateStorageInstance.Kind()),
+		Location:   info.Location,
+		Life:       params.Life(stateStorageAttachment.Life().String()),
+		Usage:      usage,
 	}, nil
 }
 
+// filesystemAttachmentUsage returns the most recently reported usage
+// of the filesystem backing the specified storage instance on the
+// specified machine, or nil if none has been reported.
+func (s *StorageAPI) filesystemAttachmentUsage(storageTag names.StorageTag, machineTag names.MachineTag) (*params.FilesystemUsage, error) {
+	filesystem, err := s.st.StorageInstanceFilesystem(storageTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	usage, err := s.st.FilesystemAttachmentUsage(machineTag, filesystem.FilesystemTag())
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return storagecommon.FilesystemUsageFromState(usage), nil
+}
+
 // WatchUnitStorageAttachments creates watchers for a collection of units,
 // each of which can be used to watch for lifecycle changes to the corresponding
 // unit's storage attachments.
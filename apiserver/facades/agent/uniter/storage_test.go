@@ -5,6 +5,7 @@ package uniter_test
 
 import (
 	"errors"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -219,6 +220,73 @@ func (s *storageSuite) TestWatchStorageAttachmentFilesystem(c *gc.C) {
 	})
 }
 
+func (s *storageSuite) TestStorageAttachmentsFilesystemUsage(c *gc.C) {
+	resources := common.NewResources()
+	getCanAccess := func() (common.AuthFunc, error) {
+		return func(names.Tag) bool {
+			return true
+		}, nil
+	}
+	unitTag := names.NewUnitTag("mysql/0")
+	storageTag := names.NewStorageTag("data/0")
+	machineTag := names.NewMachineTag("66")
+	filesystemTag := names.NewFilesystemTag("104")
+	updated := time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC)
+	state := &mockStorageState{
+		storageAttachment: func(s names.StorageTag, u names.UnitTag) (state.StorageAttachment, error) {
+			return &mockStorageAttachment{storage: s, unit: u}, nil
+		},
+		storageInstance: func(s names.StorageTag) (state.StorageInstance, error) {
+			return &mockStorageInstance{tag: s, kind: state.StorageKindFilesystem}, nil
+		},
+		storageInstanceFilesystem: func(s names.StorageTag) (state.Filesystem, error) {
+			c.Assert(s, gc.DeepEquals, storageTag)
+			return &mockFilesystem{tag: filesystemTag}, nil
+		},
+		unitAssignedMachine: func(u names.UnitTag) (names.MachineTag, error) {
+			return machineTag, nil
+		},
+		filesystemAttachment: func(m names.MachineTag, f names.FilesystemTag) (state.FilesystemAttachment, error) {
+			return &mockFilesystemAttachment{info: state.FilesystemAttachmentInfo{MountPoint: "/srv"}}, nil
+		},
+		filesystemAttachmentUsage: func(m names.MachineTag, f names.FilesystemTag) (state.FilesystemUsage, error) {
+			c.Assert(m, gc.DeepEquals, machineTag)
+			c.Assert(f, gc.DeepEquals, filesystemTag)
+			return state.FilesystemUsage{
+				UsedBytes:      1024,
+				AvailableBytes: 2048,
+				Updated:        updated,
+			}, nil
+		},
+	}
+
+	storage, err := uniter.NewStorageAPI(state, resources, getCanAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := storage.StorageAttachments(params.StorageAttachmentIds{
+		Ids: []params.StorageAttachmentId{{
+			StorageTag: storageTag.String(),
+			UnitTag:    unitTag.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StorageAttachmentResults{
+		Results: []params.StorageAttachmentResult{{
+			Result: params.StorageAttachment{
+				StorageTag: storageTag.String(),
+				UnitTag:    unitTag.String(),
+				Kind:       params.StorageKindFilesystem,
+				Location:   "/srv",
+				Life:       params.Alive,
+				Usage: &params.FilesystemUsage{
+					UsedBytes:      1024,
+					AvailableBytes: 2048,
+					Updated:        &updated,
+				},
+			},
+		}},
+	})
+}
+
 func (s *storageSuite) TestDestroyUnitStorageAttachments(c *gc.C) {
 	resources := common.NewResources()
 	getCanAccess := func() (common.AuthFunc, error) {
@@ -479,6 +547,9 @@ type mockStorageState struct {
 	remove                        func(names.StorageTag, names.UnitTag) error
 	storageInstance               func(names.StorageTag) (state.StorageInstance, error)
 	storageInstanceFilesystem     func(names.StorageTag) (state.Filesystem, error)
+	storageAttachment             func(names.StorageTag, names.UnitTag) (state.StorageAttachment, error)
+	filesystemAttachment          func(names.MachineTag, names.FilesystemTag) (state.FilesystemAttachment, error)
+	filesystemAttachmentUsage     func(names.MachineTag, names.FilesystemTag) (state.FilesystemUsage, error)
 	storageInstanceVolume         func(names.StorageTag) (state.Volume, error)
 	unitAssignedMachine           func(names.UnitTag) (names.MachineTag, error)
 	watchStorageAttachments       func(names.UnitTag) state.StringsWatcher
@@ -506,6 +577,18 @@ func (m *mockStorageState) StorageInstanceFilesystem(s names.StorageTag) (state.
 	return m.storageInstanceFilesystem(s)
 }
 
+func (m *mockStorageState) StorageAttachment(s names.StorageTag, u names.UnitTag) (state.StorageAttachment, error) {
+	return m.storageAttachment(s, u)
+}
+
+func (m *mockStorageState) FilesystemAttachment(mtag names.MachineTag, f names.FilesystemTag) (state.FilesystemAttachment, error) {
+	return m.filesystemAttachment(mtag, f)
+}
+
+func (m *mockStorageState) FilesystemAttachmentUsage(mtag names.MachineTag, f names.FilesystemTag) (state.FilesystemUsage, error) {
+	return m.filesystemAttachmentUsage(mtag, f)
+}
+
 func (m *mockStorageState) StorageInstanceVolume(s names.StorageTag) (state.Volume, error) {
 	return m.storageInstanceVolume(s)
 }
@@ -595,11 +678,47 @@ func (m *mockFilesystem) FilesystemTag() names.FilesystemTag {
 	return m.tag
 }
 
+type mockFilesystemAttachment struct {
+	state.FilesystemAttachment
+	info state.FilesystemAttachmentInfo
+}
+
+func (m *mockFilesystemAttachment) Info() (state.FilesystemAttachmentInfo, error) {
+	return m.info, nil
+}
+
+type mockStorageAttachment struct {
+	state.StorageAttachment
+	storage names.StorageTag
+	unit    names.UnitTag
+}
+
+func (m *mockStorageAttachment) StorageInstance() names.StorageTag {
+	return m.storage
+}
+
+func (m *mockStorageAttachment) Unit() names.UnitTag {
+	return m.unit
+}
+
+func (m *mockStorageAttachment) Life() state.Life {
+	return state.Alive
+}
+
 type mockStorageInstance struct {
 	state.StorageInstance
+	tag  names.StorageTag
 	kind state.StorageKind
 }
 
+func (m *mockStorageInstance) StorageTag() names.StorageTag {
+	return m.tag
+}
+
 func (m *mockStorageInstance) Kind() state.StorageKind {
 	return m.kind
 }
+
+func (m *mockStorageInstance) Owner() (names.Tag, bool) {
+	return nil, false
+}
@@ -6,14 +6,20 @@
 package uniter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/cloudspec"
 	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/facade"
 	leadershipapiserver "github.com/juju/juju/apiserver/facades/agent/leadership"
@@ -23,13 +29,15 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/status"
 	"github.com/juju/utils/set"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
-// UniterAPI implements the latest version (v6) of the Uniter API.
+// UniterAPI implements the latest version (v7) of the Uniter API.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -48,14 +56,24 @@ type UniterAPI struct {
 	accessApplication common.GetAuthFunc
 	unit              *state.Unit
 	accessMachine     common.GetAuthFunc
+	cloudSpec         cloudspec.CloudSpecAPI
 	StorageAPI
 }
 
+// UniterAPIV6 doesn't have the WriteRelationSettings, GoalStates,
+// SetActionsProgress, CloudSpec, Suspended, Secrets, SetSecrets,
+// SetApplicationWorkloadVersion, UnitStatusHistory,
+// OpenClosePortRanges, Conditional*, LogActionsMessages,
+// CompletedApplicationHooks or SetApplicationHooksCompleted methods.
+type UniterAPIV6 struct {
+	UniterAPI
+}
+
 // UniterAPIV5 returns a RelationResultsV5 instead of RelationResults
 // from Relation and RelationById - elements don't have an
 // OtherApplication field.
 type UniterAPIV5 struct {
-	UniterAPI
+	UniterAPIV6
 }
 
 // UniterAPIV4 has old WatchApplicationRelations and NetworkConfig
@@ -136,7 +154,7 @@ func NewUniterAPI(st *state.State, resources facade.Resources, authorizer facade
 		LifeGetter:                 common.NewLifeGetter(st, accessUnitOrApplication),
 		DeadEnsurer:                common.NewDeadEnsurer(st, accessUnit),
 		AgentEntityWatcher:         common.NewAgentEntityWatcher(st, resources, accessUnitOrApplication),
-		APIAddresser:               common.NewAPIAddresser(st, resources),
+		APIAddresser:               common.NewAgentAPIAddresser(st, resources),
 		ModelWatcher:               common.NewModelWatcher(st, resources, authorizer),
 		RebootRequester:            common.NewRebootRequester(st, accessMachine),
 		LeadershipSettingsAccessor: leadershipSettingsAccessorFactory(st, resources, authorizer),
@@ -151,19 +169,31 @@ func NewUniterAPI(st *state.State, resources facade.Resources, authorizer facade
 		accessUnit:        accessUnit,
 		accessApplication: accessApplication,
 		accessMachine:     accessMachine,
+		cloudSpec:         cloudspec.NewCloudSpec(stateenvirons.EnvironConfigGetter{st}.CloudSpec, common.AuthFuncForTag(st.ModelTag())),
 		unit:              unit,
 		StorageAPI:        *storageAPI,
 	}, nil
 }
 
+// NewUniterAPIV6 creates an instance of the V6 uniter API.
+func NewUniterAPIV6(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV6, error) {
+	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV6{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV5 creates an instance of the V5 uniter API.
 func NewUniterAPIV5(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV5, error) {
-	uniterAPI, err := NewUniterAPI(st, resources, authorizer)
+	uniterAPI, err := NewUniterAPIV6(st, resources, authorizer)
 	if err != nil {
 		return nil, err
 	}
 	return &UniterAPIV5{
-		UniterAPI: *uniterAPI,
+		UniterAPIV6: *uniterAPI,
 	}, nil
 }
 
@@ -417,6 +447,98 @@ func (u *UniterAPI) Resolved(args params.Entities) (params.ResolvedModeResults,
 	return result, nil
 }
 
+// Suspended returns whether each given unit has been suspended by an
+// operator, and so should not run any hooks until it is resumed.
+func (u *UniterAPI) Suspended(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.BoolResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				result.Results[i].Result = unit.Suspended()
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// Secrets returns the secrets stored by the charm of each given unit.
+// A unit may only read its own secrets.
+func (u *UniterAPI) Secrets(args params.Entities) (params.SettingsResults, error) {
+	result := params.SettingsResults{
+		Results: make([]params.SettingsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SettingsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				var secrets map[string]string
+				secrets, err = unit.Secrets()
+				if err == nil {
+					result.Results[i].Settings = secrets
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetSecrets stores secrets on behalf of the charm of each given unit,
+// leaving its other secrets untouched. Secrets with empty values are
+// removed.
+func (u *UniterAPI) SetSecrets(args params.UnitsSecrets) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Units)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.Units {
+		tag, err := names.ParseUnitTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.UpdateSecrets(arg.Secrets)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // ClearResolved removes any resolved setting from each given unit.
 func (u *UniterAPI) ClearResolved(args params.Entities) (params.ErrorResults, error) {
 	result := params.ErrorResults{
@@ -752,6 +874,225 @@ func (u *UniterAPI) SetWorkloadVersion(args params.EntityWorkloadVersions) (para
 	return result, nil
 }
 
+// SetApplicationWorkloadVersion sets the workload version of the
+// application of each given unit. An error will be returned for any
+// unit that is not the leader of its application.
+func (u *UniterAPI) SetApplicationWorkloadVersion(args params.EntityWorkloadVersions) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, entity := range args.Entities {
+		resultItem := &result.Results[i]
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(tag) {
+			resultItem.Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		application, err := unit.Application()
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		token := u.st.LeadershipChecker().LeadershipCheck(application.Name(), unit.Name())
+		err = application.SetWorkloadVersion(token, entity.WorkloadVersion)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// CompletedApplicationHooks returns, for the application of each given
+// unit, the application hooks that have completed, keyed on hook name,
+// with the URL of the charm each last completed for.
+func (u *UniterAPI) CompletedApplicationHooks(args params.Entities) (params.SettingsResults, error) {
+	result := params.SettingsResults{
+		Results: make([]params.SettingsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SettingsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		resultItem := &result.Results[i]
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(tag) {
+			resultItem.Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		application, err := unit.Application()
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		hooks, err := application.CompletedApplicationHooks()
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		resultItem.Settings = hooks
+	}
+	return result, nil
+}
+
+// SetApplicationHooksCompleted records the completion of application
+// hooks by the given units. An error will be returned for any unit that
+// is not the leader of its application, so that a deposed leader cannot
+// record a hook as completed.
+func (u *UniterAPI) SetApplicationHooksCompleted(args params.ApplicationHookCompletions) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Completions)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, completion := range args.Completions {
+		resultItem := &result.Results[i]
+		tag, err := names.ParseUnitTag(completion.Tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		if !canAccess(tag) {
+			resultItem.Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		application, err := unit.Application()
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		token := u.st.LeadershipChecker().LeadershipCheck(application.Name(), unit.Name())
+		err = application.SetApplicationHookCompleted(token, completion.Hook, completion.CharmURL)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// UnitStatusHistory returns the most recent workload and agent status
+// history entries of each given unit, oldest first. Each request must
+// have a size, and may have a kind of "workload" or "juju-unit" to
+// select only those entries.
+func (u *UniterAPI) UnitStatusHistory(args params.StatusHistoryRequests) (params.StatusHistoryResults, error) {
+	result := params.StatusHistoryResults{
+		Results: make([]params.StatusHistoryResult, len(args.Requests)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StatusHistoryResults{}, err
+	}
+	for i, request := range args.Requests {
+		resultItem := &result.Results[i]
+		tag, err := names.ParseUnitTag(request.Tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			resultItem.Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		statuses, err := oneUnitStatusHistory(unit, request)
+		if err != nil {
+			resultItem.Error = common.ServerError(err)
+			continue
+		}
+		resultItem.History.Statuses = statuses
+	}
+	return result, nil
+}
+
+// oneUnitStatusHistory returns the status history of the unit requested.
+func oneUnitStatusHistory(unit *state.Unit, request params.StatusHistoryRequest) ([]params.DetailedStatus, error) {
+	filter := status.StatusHistoryFilter{Size: request.Filter.Size}
+	if filter.Size <= 0 {
+		return nil, errors.NotValidf("status history size %d", filter.Size)
+	}
+	kind := status.HistoryKind(request.Kind)
+	switch kind {
+	case "":
+		kind = status.KindUnit
+	case status.KindUnit, status.KindWorkload, status.KindUnitAgent:
+	default:
+		return nil, errors.NotValidf("status history kind %q", kind)
+	}
+	var statuses []params.DetailedStatus
+	if kind == status.KindUnit || kind == status.KindWorkload {
+		workload, err := unit.StatusHistory(filter)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		statuses = append(statuses, detailedStatuses(workload, status.KindWorkload)...)
+	}
+	if kind == status.KindUnit || kind == status.KindUnitAgent {
+		agent, err := unit.AgentHistory().StatusHistory(filter)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		statuses = append(statuses, detailedStatuses(agent, status.KindUnitAgent)...)
+	}
+	// Each history holds at most filter.Size entries, so keep only
+	// the latest of the merged entries.
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Since.Before(*statuses[j].Since)
+	})
+	if len(statuses) > filter.Size {
+		statuses = statuses[len(statuses)-filter.Size:]
+	}
+	return statuses, nil
+}
+
+// detailedStatuses converts status history entries of the given kind
+// to their API representation.
+func detailedStatuses(infos []status.StatusInfo, kind status.HistoryKind) []params.DetailedStatus {
+	statuses := make([]params.DetailedStatus, len(infos))
+	for i, info := range infos {
+		statuses[i] = params.DetailedStatus{
+			Status: string(info.Status),
+			Info:   info.Message,
+			Data:   info.Data,
+			Since:  info.Since,
+			Kind:   string(kind),
+		}
+	}
+	return statuses
+}
+
 // OpenPorts sets the policy of the port range with protocol to be
 // opened, for all given units.
 func (u *UniterAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResults, error) {
@@ -810,6 +1151,43 @@ func (u *UniterAPI) ClosePorts(args params.EntitiesPortRanges) (params.ErrorResu
 	return result, nil
 }
 
+// OpenClosePortRanges closes and then opens the given port ranges for
+// each given unit. The changes for each unit are made in a single
+// transaction: either all of them are made, or none are.
+func (u *UniterAPI) OpenClosePortRanges(args params.EntitiesPortRangeChanges) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	networkRanges := func(portRanges []params.PortRange) []network.PortRange {
+		ranges := make([]network.PortRange, len(portRanges))
+		for i, pr := range portRanges {
+			ranges[i] = pr.NetworkPortRange()
+		}
+		return ranges
+	}
+	for i, change := range args.Changes {
+		tag, err := names.ParseUnitTag(change.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				err = unit.OpenClosePorts(networkRanges(change.OpenRanges), networkRanges(change.CloseRanges))
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // WatchConfigSettings returns a NotifyWatcher for observing changes
 // to each unit's application configuration settings. See also
 // state/watcher.go:Unit.WatchConfigSettings().
@@ -863,21 +1241,34 @@ func (u *UniterAPI) ConfigSettings(args params.Entities) (params.ConfigSettingsR
 		return params.ConfigSettingsResults{}, err
 	}
 	for i, entity := range args.Entities {
-		tag, err := names.ParseUnitTag(entity.Tag)
-		if err != nil {
-			result.Results[i].Error = common.ServerError(common.ErrPerm)
-			continue
-		}
-		err = common.ErrPerm
-		if canAccess(tag) {
-			var unit *state.Unit
-			unit, err = u.getUnit(tag)
-			if err == nil {
-				var settings charm.Settings
-				settings, err = unit.ConfigSettings()
-				if err == nil {
-					result.Results[i].Settings = params.ConfigSettings(settings)
-				}
+		settings, err := u.unitConfigSettings(canAccess, entity.Tag)
+		if err == nil {
+			result.Results[i].Settings = params.ConfigSettings(settings)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// ConditionalConfigSettings returns the complete set of application
+// charm config settings available to each given unit, along with their
+// version. If the caller already holds the current version of a unit's
+// settings, they are not returned again.
+func (u *UniterAPI) ConditionalConfigSettings(args params.VersionedEntities) (params.ConfigSettingsResults, error) {
+	result := params.ConfigSettingsResults{
+		Results: make([]params.ConfigSettingsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ConfigSettingsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		settings, err := u.unitConfigSettings(canAccess, entity.Tag)
+		if err == nil {
+			r := &result.Results[i]
+			r.Version, r.NotModified, err = settingsVersion(settings, entity.Version)
+			if err == nil && !r.NotModified {
+				r.Settings = params.ConfigSettings(settings)
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -885,6 +1276,18 @@ func (u *UniterAPI) ConfigSettings(args params.Entities) (params.ConfigSettingsR
 	return result, nil
 }
 
+func (u *UniterAPI) unitConfigSettings(canAccess common.AuthFunc, unitTag string) (charm.Settings, error) {
+	tag, err := names.ParseUnitTag(unitTag)
+	if err != nil || !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return nil, err
+	}
+	return unit.ConfigSettings()
+}
+
 // CharmArchiveSha256 returns the SHA256 digest of the charm archive
 // (bundle) data for each charm url in the given parameters.
 func (u *UniterAPI) CharmArchiveSha256(args params.CharmURLs) (params.StringResults, error) {
@@ -964,6 +1367,31 @@ func (u *UniterAPI) FinishActions(args params.ActionExecutionResults) (params.Er
 	return common.FinishActions(args, actionFn), nil
 }
 
+// SetActionsProgress records intermediate progress reported by running
+// Actions.
+func (u *UniterAPI) SetActionsProgress(args params.ActionsProgress) (params.ErrorResults, error) {
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+
+	actionFn := common.AuthAndActionFromTagFn(canAccess, u.st.ActionByTag)
+	return common.SetActionsProgress(args, actionFn), nil
+}
+
+// LogActionsMessages appends timestamped messages to the logs of
+// running actions, so they may be observed before the actions
+// complete.
+func (u *UniterAPI) LogActionsMessages(args params.ActionsMessages) (params.ErrorResults, error) {
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+
+	actionFn := common.AuthAndActionFromTagFn(canAccess, u.st.ActionByTag)
+	return common.LogActionsMessages(args, actionFn), nil
+}
+
 // RelationById returns information about all given relations,
 // specified by their ids, including their key and the local
 // endpoint.
@@ -1154,6 +1582,37 @@ func (u *UniterAPI) ReadSettings(args params.RelationUnits) (params.SettingsResu
 	return result, nil
 }
 
+// ConditionalReadSettings returns the local settings of each given set
+// of relation/unit, along with their version. If the caller already
+// holds the current version of the settings, they are not returned
+// again.
+func (u *UniterAPI) ConditionalReadSettings(args params.VersionedRelationUnits) (params.SettingsResults, error) {
+	result := params.SettingsResults{
+		Results: make([]params.SettingsResult, len(args.RelationUnits)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SettingsResults{}, err
+	}
+	for i, arg := range args.RelationUnits {
+		unit, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		relUnit, err := u.getRelationUnit(canAccess, arg.Relation, unit)
+		if err == nil {
+			var settings *state.Settings
+			settings, err = relUnit.Settings()
+			if err == nil {
+				result.Results[i], err = conditionalRelationSettings(settings.Map(), arg.Version)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // ReadRemoteSettings returns the remote settings of each given set of
 // relation/local unit/remote unit.
 func (u *UniterAPI) ReadRemoteSettings(args params.RelationUnitPairs) (params.SettingsResults, error) {
@@ -1188,6 +1647,93 @@ func (u *UniterAPI) ReadRemoteSettings(args params.RelationUnitPairs) (params.Se
 	return result, nil
 }
 
+// ConditionalReadRemoteSettings returns the remote settings of each
+// given set of relation/local unit/remote unit, along with their
+// version. If the caller already holds the current version of the
+// settings, they are not returned again.
+func (u *UniterAPI) ConditionalReadRemoteSettings(args params.VersionedRelationUnitPairs) (params.SettingsResults, error) {
+	result := params.SettingsResults{
+		Results: make([]params.SettingsResult, len(args.RelationUnitPairs)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SettingsResults{}, err
+	}
+	for i, arg := range args.RelationUnitPairs {
+		unit, err := names.ParseUnitTag(arg.LocalUnit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		relUnit, err := u.getRelationUnit(canAccess, arg.Relation, unit)
+		if err == nil {
+			var remoteUnit string
+			remoteUnit, err = u.checkRemoteUnit(relUnit, arg.RemoteUnit)
+			if err == nil {
+				var settings map[string]interface{}
+				settings, err = relUnit.ReadSettings(remoteUnit)
+				if err == nil {
+					result.Results[i], err = conditionalRelationSettings(settings, arg.Version)
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// conditionalRelationSettings returns the result of a conditional read
+// of the given relation settings by a caller holding the given version.
+func conditionalRelationSettings(settings map[string]interface{}, version string) (params.SettingsResult, error) {
+	converted, err := convertRelationSettings(settings)
+	if err != nil {
+		return params.SettingsResult{}, err
+	}
+	var result params.SettingsResult
+	result.Version, result.NotModified, err = settingsVersion(converted, version)
+	if err != nil {
+		return params.SettingsResult{}, err
+	}
+	if !result.NotModified {
+		result.Settings = converted
+	}
+	return result, nil
+}
+
+// ConditionalModelConfig returns the current model configuration, along
+// with its version. If the caller already holds the current version,
+// the configuration is not returned again.
+func (u *UniterAPI) ConditionalModelConfig(args params.ModelConfigVersion) (params.ModelConfigResult, error) {
+	cfg, err := u.st.ModelConfig()
+	if err != nil {
+		return params.ModelConfigResult{}, err
+	}
+	attrs := cfg.AllAttrs()
+	var result params.ModelConfigResult
+	result.Version, result.NotModified, err = settingsVersion(attrs, args.Version)
+	if err != nil {
+		return params.ModelConfigResult{}, err
+	}
+	if !result.NotModified {
+		result.Config = attrs
+	}
+	return result, nil
+}
+
+// settingsVersion returns the version of the given settings, and
+// whether it matches the given version held by the caller. The version
+// is a digest of the settings' JSON encoding, which orders map keys, so
+// it changes when, and only when, the settings do.
+func settingsVersion(settings interface{}, callerVersion string) (string, bool, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", false, errors.Annotate(err, "cannot compute settings version")
+	}
+	sum := sha256.Sum256(data)
+	version := hex.EncodeToString(sum[:])
+	return version, callerVersion == version, nil
+}
+
 // UpdateSettings persists all changes made to the local settings of
 // all given pairs of relation and unit. Keys with empty values are
 // considered a signal to delete these values.
@@ -1225,6 +1771,71 @@ func (u *UniterAPI) UpdateSettings(args params.RelationUnitsSettings) (params.Er
 	return result, nil
 }
 
+// WriteRelationSettings persists all changes made to the local
+// settings of all given pairs of relation and unit in a single
+// transaction, so that either all of the changes are written or none
+// of them are. Keys with empty values are considered a signal to
+// delete these values.
+//
+// If any pair cannot be written, the reason is reported against that
+// pair and nothing is written.
+func (u *UniterAPI) WriteRelationSettings(args params.RelationUnitsSettings) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.RelationUnits)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	var (
+		failed   bool
+		all      []*state.Settings
+		byRelKey = make(map[string]*state.Settings)
+	)
+	for i, arg := range args.RelationUnits {
+		unit, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			failed = true
+			continue
+		}
+		// Changes to the same pair made in separate entries are
+		// applied in order to the same settings.
+		relKey := arg.Relation + "#" + arg.Unit
+		settings, ok := byRelKey[relKey]
+		if !ok {
+			var relUnit *state.RelationUnit
+			relUnit, err = u.getRelationUnit(canAccess, arg.Relation, unit)
+			if err == nil {
+				settings, err = relUnit.Settings()
+			}
+			if err != nil {
+				result.Results[i].Error = common.ServerError(err)
+				failed = true
+				continue
+			}
+			byRelKey[relKey] = settings
+			all = append(all, settings)
+		}
+		for k, v := range arg.Settings {
+			if v == "" {
+				settings.Delete(k)
+			} else {
+				settings.Set(k, v)
+			}
+		}
+	}
+	if failed {
+		return result, nil
+	}
+	if err := u.st.WriteAllSettings(all...); err != nil {
+		for i := range result.Results {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
 // WatchRelationUnits returns a RelationUnitsWatcher for observing
 // changes to every unit in the supplied relation that is visible to
 // the supplied unit. See also state/watcher.go:RelationUnit.Watch().
@@ -1442,7 +2053,27 @@ func (u *UniterAPI) watchOneUnitAddresses(tag names.UnitTag) (string, error) {
 }
 
 func (u *UniterAPI) watchOneRelationUnit(relUnit *state.RelationUnit) (params.RelationUnitsWatchResult, error) {
-	watch := relUnit.Watch()
+	cfg, err := u.st.ModelConfig()
+	if err != nil {
+		return params.RelationUnitsWatchResult{}, errors.Trace(err)
+	}
+	endpoints := relUnit.Relation().Endpoints()
+	endpointNames := make([]string, len(endpoints))
+	for i, ep := range endpoints {
+		endpointNames[i] = ep.String()
+	}
+	var watch state.RelationUnitsWatcher = relUnit.Watch()
+	if batch := cfg.RelationJoinedBatch(endpointNames...); batch.Size > 0 {
+		// Report joining units a few at a time, so a large
+		// scale-out of the related application doesn't run
+		// all of its relation-joined hooks at once.
+		watch, err = newPacedRelationUnitsWatcher(
+			watch, clock.WallClock, batch.Size, batch.Interval,
+		)
+		if err != nil {
+			return params.RelationUnitsWatchResult{}, errors.Trace(err)
+		}
+	}
 	// Consume the initial event and forward it to the result.
 	if changes, ok := <-watch.Changes(); ok {
 		return params.RelationUnitsWatchResult{
@@ -1924,6 +2555,164 @@ func (u *UniterAPIV4) watchOneApplicationRelations(tag names.ApplicationTag) (pa
 	return nothing, watcher.EnsureErr(watch)
 }
 
+// GoalStates returns the goal state of each given unit: the units of
+// its application, and the applications and units it is related to,
+// that the model intends to exist, along with their current status.
+func (u *UniterAPI) GoalStates(args params.Entities) (params.GoalStateResults, error) {
+	result := params.GoalStateResults{
+		Results: make([]params.GoalStateResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.GoalStateResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		unit, err := u.getUnit(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		goalState, err := u.oneGoalState(unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = goalState
+	}
+	return result, nil
+}
+
+// CloudSpec returns the type, name, region and endpoints of the cloud
+// that the model is running in. The model's cloud credential is only
+// included if a model admin has trusted the unit's application (see
+// state.Application.IsTrusted).
+func (u *UniterAPI) CloudSpec() (params.CloudSpecResult, error) {
+	result := u.cloudSpec.GetCloudSpec(u.st.ModelTag())
+	if result.Error != nil {
+		return result, nil
+	}
+	trusted, err := u.applicationTrusted()
+	if err != nil {
+		return params.CloudSpecResult{Error: common.ServerError(err)}, nil
+	}
+	if !trusted {
+		result.Result.Credential = nil
+	}
+	return result, nil
+}
+
+// applicationTrusted reports whether a model admin has explicitly
+// trusted the authorized unit's application. The flag is kept out of
+// charm config, which users without admin access may change.
+func (u *UniterAPI) applicationTrusted() (bool, error) {
+	app, err := u.unit.Application()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return app.IsTrusted(), nil
+}
+
+func (u *UniterAPI) oneGoalState(unit *state.Unit) (*params.GoalState, error) {
+	app, err := unit.Application()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := u.goalStateUnits(app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relations, err := app.Relations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	goalState := &params.GoalState{
+		Units:     units,
+		Relations: make(map[string]params.UnitsGoalState),
+	}
+	for _, rel := range relations {
+		endpoint, err := rel.Endpoint(app.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		related, err := rel.RelatedEndpoints(app.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		relGoalState, ok := goalState.Relations[endpoint.Name]
+		if !ok {
+			relGoalState = make(params.UnitsGoalState)
+			goalState.Relations[endpoint.Name] = relGoalState
+		}
+		for _, ep := range related {
+			if ep.ApplicationName != app.Name() {
+				relGoalState[ep.ApplicationName] = params.GoalStateStatus{
+					Status: goalStateRelationStatus(rel.Life()),
+				}
+			}
+			relatedApp, err := u.st.Application(ep.ApplicationName)
+			if errors.IsNotFound(err) {
+				// The units of remote applications are not
+				// known to this model.
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			relatedUnits, err := u.goalStateUnits(relatedApp)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			for name, status := range relatedUnits {
+				relGoalState[name] = status
+			}
+		}
+	}
+	return goalState, nil
+}
+
+// goalStateUnits returns the goal state of the units of the given
+// application. Units that are not alive report their life rather than
+// their workload status.
+func (u *UniterAPI) goalStateUnits(app *state.Application) (params.UnitsGoalState, error) {
+	units, err := app.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(params.UnitsGoalState, len(units))
+	for _, unit := range units {
+		statusInfo, err := unit.Status()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unitStatus := string(statusInfo.Status)
+		if life := unit.Life(); life != state.Alive {
+			unitStatus = life.String()
+		}
+		result[unit.Name()] = params.GoalStateStatus{
+			Status: unitStatus,
+			Since:  statusInfo.Since,
+		}
+	}
+	return result, nil
+}
+
+// goalStateRelationStatus returns the goal state status of an
+// application at the other end of a relation with the given life.
+func goalStateRelationStatus(life state.Life) string {
+	if life == state.Alive {
+		return "joined"
+	}
+	return life.String()
+}
+
 // Mask the new methods from the V4 API. The API reflection code in
 // rpc/rpcreflect/type.go:newMethod skips 2-argument methods, so this
 // removes the method as far as the RPC machinery is concerned.
@@ -1936,3 +2725,56 @@ func (u *UniterAPIV4) NetworkInfo(_, _ struct{}) {}
 
 // WatchUnitRelations isn't on the V4 API.
 func (u *UniterAPIV4) WatchUnitRelations(_, _ struct{}) {}
+
+// Mask the new methods from the V6 API.
+
+// WriteRelationSettings isn't on the V6 API.
+func (u *UniterAPIV6) WriteRelationSettings(_, _ struct{}) {}
+
+// GoalStates isn't on the V6 API.
+func (u *UniterAPIV6) GoalStates(_, _ struct{}) {}
+
+// SetActionsProgress isn't on the V6 API.
+func (u *UniterAPIV6) SetActionsProgress(_, _ struct{}) {}
+
+// CloudSpec isn't on the V6 API.
+func (u *UniterAPIV6) CloudSpec(_, _ struct{}) {}
+
+// Suspended isn't on the V6 API.
+func (u *UniterAPIV6) Suspended(_, _ struct{}) {}
+
+// Secrets isn't on the V6 API.
+func (u *UniterAPIV6) Secrets(_, _ struct{}) {}
+
+// SetSecrets isn't on the V6 API.
+func (u *UniterAPIV6) SetSecrets(_, _ struct{}) {}
+
+// SetApplicationWorkloadVersion isn't on the V6 API.
+func (u *UniterAPIV6) SetApplicationWorkloadVersion(_, _ struct{}) {}
+
+// UnitStatusHistory isn't on the V6 API.
+func (u *UniterAPIV6) UnitStatusHistory(_, _ struct{}) {}
+
+// OpenClosePortRanges isn't on the V6 API.
+func (u *UniterAPIV6) OpenClosePortRanges(_, _ struct{}) {}
+
+// ConditionalConfigSettings isn't on the V6 API.
+func (u *UniterAPIV6) ConditionalConfigSettings(_, _ struct{}) {}
+
+// ConditionalReadSettings isn't on the V6 API.
+func (u *UniterAPIV6) ConditionalReadSettings(_, _ struct{}) {}
+
+// ConditionalReadRemoteSettings isn't on the V6 API.
+func (u *UniterAPIV6) ConditionalReadRemoteSettings(_, _ struct{}) {}
+
+// ConditionalModelConfig isn't on the V6 API.
+func (u *UniterAPIV6) ConditionalModelConfig(_, _ struct{}) {}
+
+// LogActionsMessages isn't on the V6 API.
+func (u *UniterAPIV6) LogActionsMessages(_, _ struct{}) {}
+
+// CompletedApplicationHooks isn't on the V6 API.
+func (u *UniterAPIV6) CompletedApplicationHooks(_, _ struct{}) {}
+
+// SetApplicationHooksCompleted isn't on the V6 API.
+func (u *UniterAPIV6) SetApplicationHooksCompleted(_, _ struct{}) {}
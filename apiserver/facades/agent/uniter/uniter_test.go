@@ -267,6 +267,53 @@ func (s *uniterSuite) TestSetUnitStatus(c *gc.C) {
 	c.Assert(statusInfo.Message, gc.Equals, "foobar")
 }
 
+func (s *uniterSuite) TestUnitStatusHistory(c *gc.C) {
+	now := time.Now()
+	setStatus := func(set func(status.StatusInfo) error, st status.Status, message string, offset time.Duration) {
+		since := now.Add(offset)
+		err := set(status.StatusInfo{Status: st, Message: message, Since: &since})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	setStatus(s.wordpressUnit.SetStatus, status.Maintenance, "installing", time.Second)
+	setStatus(s.wordpressUnit.SetAgentStatus, status.Executing, "running install hook", 2*time.Second)
+	setStatus(s.wordpressUnit.SetStatus, status.Active, "ready", 3*time.Second)
+	setStatus(s.wordpressUnit.SetAgentStatus, status.Idle, "", 4*time.Second)
+
+	args := params.StatusHistoryRequests{Requests: []params.StatusHistoryRequest{
+		{Tag: "unit-mysql-0", Filter: params.StatusHistoryFilter{Size: 3}},
+		{Tag: "unit-wordpress-0", Filter: params.StatusHistoryFilter{Size: 3}},
+		{Tag: "unit-wordpress-0", Kind: "workload", Filter: params.StatusHistoryFilter{Size: 1}},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-wordpress-0", Kind: "juju-machine", Filter: params.StatusHistoryFilter{Size: 1}},
+		{Tag: "unit-foo-42", Filter: params.StatusHistoryFilter{Size: 3}},
+	}}
+	result, err := s.uniter.UnitStatusHistory(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 6)
+	c.Assert(result.Results[0].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	summarize := func(statuses []params.DetailedStatus) []string {
+		var summaries []string
+		for _, st := range statuses {
+			summaries = append(summaries, fmt.Sprintf("%s %s %q", st.Kind, st.Status, st.Info))
+		}
+		return summaries
+	}
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Check(summarize(result.Results[1].History.Statuses), jc.DeepEquals, []string{
+		`juju-unit executing "running install hook"`,
+		`workload active "ready"`,
+		`juju-unit idle ""`,
+	})
+	c.Assert(result.Results[2].Error, gc.IsNil)
+	c.Check(summarize(result.Results[2].History.Statuses), jc.DeepEquals, []string{
+		`workload active "ready"`,
+	})
+	c.Check(result.Results[3].Error, gc.ErrorMatches, "status history size 0 not valid")
+	c.Check(result.Results[4].Error, gc.ErrorMatches, `status history kind "juju-machine" not valid`)
+	c.Check(result.Results[5].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+}
+
 func (s *uniterSuite) TestLife(c *gc.C) {
 	// Add a relation wordpress-mysql.
 	rel := s.addRelation(c, "wordpress", "mysql")
@@ -578,6 +625,74 @@ func (s *uniterSuite) TestResolved(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestSuspended(c *gc.C) {
+	err := s.wordpressUnit.SetSuspended(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.Suspended(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestSecrets(c *gc.C) {
+	err := s.wordpressUnit.UpdateSecrets(map[string]string{"password": "hunter2"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.Secrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SettingsResults{
+		Results: []params.SettingsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Settings: params.Settings{"password": "hunter2"}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterSuite) TestSetSecrets(c *gc.C) {
+	args := params.UnitsSecrets{Units: []params.UnitSecrets{
+		{Tag: "unit-mysql-0", Secrets: map[string]string{"password": "nope"}},
+		{Tag: "unit-wordpress-0", Secrets: map[string]string{"password": "hunter2"}},
+		{Tag: "unit-wordpress-0", Secrets: map[string]string{"bad.name": "x"}},
+		{Tag: "unit-foo-42", Secrets: map[string]string{"password": "nope"}},
+	}}
+	result, err := s.uniter.SetSecrets(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: nil},
+			{Error: &params.Error{
+				Message: `cannot update secrets for unit "wordpress/0": secret name "bad.name" not valid`,
+			}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	secrets, err := s.wordpressUnit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, jc.DeepEquals, map[string]string{"password": "hunter2"})
+	secrets, err = s.mysqlUnit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 0)
+}
+
 func (s *uniterSuite) TestClearResolved(c *gc.C) {
 	err := s.wordpressUnit.SetResolved(state.ResolvedRetryHooks)
 	c.Assert(err, jc.ErrorIsNil)
@@ -870,6 +985,62 @@ func (s *uniterSuite) TestSetWorkloadVersion(c *gc.C) {
 	c.Assert(newVersion, gc.Equals, "shiro")
 }
 
+func (s *uniterSuite) TestSetApplicationWorkloadVersion(c *gc.C) {
+	args := params.EntityWorkloadVersions{Entities: []params.EntityWorkloadVersion{
+		{Tag: "unit-mysql-0", WorkloadVersion: "allura"},
+		{Tag: "unit-wordpress-0", WorkloadVersion: "shiro"},
+		{Tag: "unit-foo-42", WorkloadVersion: "pidge"},
+	}}
+	result, err := s.uniter.SetApplicationWorkloadVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `cannot set workload version for application "wordpress": .*`)
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	err = s.State.LeadershipClaimer().ClaimLeadership("wordpress", "wordpress/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.SetApplicationWorkloadVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+
+	version, err := s.wordpress.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "shiro")
+}
+
+func (s *uniterSuite) TestSetApplicationHooksCompleted(c *gc.C) {
+	args := params.ApplicationHookCompletions{Completions: []params.ApplicationHookCompletion{
+		{Tag: "unit-mysql-0", Hook: "migrate", CharmURL: "cs:quantal/mysql-1"},
+		{Tag: "unit-wordpress-0", Hook: "migrate", CharmURL: "cs:quantal/wordpress-3"},
+		{Tag: "unit-foo-42", Hook: "migrate", CharmURL: "cs:quantal/foo-1"},
+	}}
+	result, err := s.uniter.SetApplicationHooksCompleted(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `cannot record completion of application hook "migrate": .*`)
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	err = s.State.LeadershipClaimer().ClaimLeadership("wordpress", "wordpress/0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.SetApplicationHooksCompleted(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+
+	hooks, err := s.uniter.CompletedApplicationHooks(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hooks, jc.DeepEquals, params.SettingsResults{
+		Results: []params.SettingsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Settings: params.Settings{"migrate": "cs:quantal/wordpress-3"}},
+		},
+	})
+}
+
 func (s *uniterSuite) TestCharmModifiedVersion(c *gc.C) {
 	args := params.Entities{Entities: []params.Entity{
 		{Tag: "application-mysql"},
@@ -948,6 +1119,54 @@ func (s *uniterSuite) TestClosePorts(c *gc.C) {
 	c.Assert(openedPorts, gc.HasLen, 0)
 }
 
+func (s *uniterSuite) TestOpenClosePortRanges(c *gc.C) {
+	err := s.wordpressUnit.OpenPorts("udp", 4321, 5000)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.EntitiesPortRangeChanges{Changes: []params.EntityPortRangeChanges{{
+		Tag:        "unit-mysql-0",
+		OpenRanges: []params.PortRange{{FromPort: 1234, ToPort: 1400, Protocol: "tcp"}},
+	}, {
+		Tag:         "unit-wordpress-0",
+		OpenRanges:  []params.PortRange{{FromPort: 80, ToPort: 80, Protocol: "tcp"}, {FromPort: 4321, ToPort: 4400, Protocol: "udp"}},
+		CloseRanges: []params.PortRange{{FromPort: 4321, ToPort: 5000, Protocol: "udp"}},
+	}, {
+		Tag:        "unit-foo-42",
+		OpenRanges: []params.PortRange{{FromPort: 42, ToPort: 42, Protocol: "tcp"}},
+	}}}
+	result, err := s.uniter.OpenClosePortRanges(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	openedPorts, err := s.wordpressUnit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(openedPorts, gc.DeepEquals, []network.PortRange{
+		{Protocol: "tcp", FromPort: 80, ToPort: 80},
+		{Protocol: "udp", FromPort: 4321, ToPort: 4400},
+	})
+}
+
+func (s *uniterSuite) TestOpenClosePortRangesAllOrNothing(c *gc.C) {
+	args := params.EntitiesPortRangeChanges{Changes: []params.EntityPortRangeChanges{{
+		Tag:        "unit-wordpress-0",
+		OpenRanges: []params.PortRange{{FromPort: 80, ToPort: 90, Protocol: "tcp"}, {FromPort: 85, ToPort: 85, Protocol: "tcp"}},
+	}}}
+	result, err := s.uniter.OpenClosePortRanges(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, `.*cannot open ports 85-85/tcp .* conflict`)
+
+	openedPorts, err := s.wordpressUnit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(openedPorts, gc.HasLen, 0)
+}
+
 func (s *uniterSuite) TestWatchConfigSettings(c *gc.C) {
 	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
 	c.Assert(err, jc.ErrorIsNil)
@@ -1131,6 +1350,52 @@ func (s *uniterSuite) TestConfigSettings(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestConditionalConfigSettings(c *gc.C) {
+	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.VersionedEntities{Entities: []params.VersionedEntity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.ConditionalConfigSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	version := result.Results[1].Version
+	c.Assert(version, gc.Not(gc.Equals), "")
+	c.Assert(result, gc.DeepEquals, params.ConfigSettingsResults{
+		Results: []params.ConfigSettingsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Settings: params.ConfigSettings{"blog-title": "My Title"}, Version: version},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Reading the same version again returns no settings.
+	args = params.VersionedEntities{Entities: []params.VersionedEntity{
+		{Tag: "unit-wordpress-0", Version: version},
+	}}
+	result, err = s.uniter.ConditionalConfigSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ConfigSettingsResults{
+		Results: []params.ConfigSettingsResult{
+			{Version: version, NotModified: true},
+		},
+	})
+
+	// Once the settings change, they are returned with a new version.
+	err = s.wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "Another Title"})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.ConditionalConfigSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].NotModified, jc.IsFalse)
+	c.Assert(result.Results[0].Version, gc.Not(gc.Equals), version)
+	c.Assert(result.Results[0].Settings, gc.DeepEquals, params.ConfigSettings{"blog-title": "Another Title"})
+}
+
 func (s *uniterSuite) TestWatchUnitRelations(c *gc.C) {
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 
@@ -1656,6 +1921,73 @@ func (s *uniterSuite) TestBeginActions(c *gc.C) {
 	c.Assert(started.After(enqueued) || started.Equal(enqueued), jc.IsTrue, gc.Commentf("started should be after or equal to enqueued time"))
 }
 
+func (s *uniterSuite) TestSetActionsProgress(c *gc.C) {
+	good, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = good.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	bad, err := s.mysqlUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.ActionsProgress{Progress: []params.ActionProgress{{
+		ActionTag: good.ActionTag().String(),
+		Message:   "halfway there",
+		Percent:   50,
+	}, {
+		ActionTag: bad.ActionTag().String(),
+		Message:   "not mine",
+		Percent:   10,
+	}}}
+	res, err := s.uniter.SetActionsProgress(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res, gc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{
+		{Error: nil},
+		{Error: apiservertesting.ErrUnauthorized},
+	}})
+
+	action, err := s.State.ActionByTag(good.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+	message, percent := action.Progress()
+	c.Assert(message, gc.Equals, "halfway there")
+	c.Assert(percent, gc.Equals, 50)
+}
+
+func (s *uniterSuite) TestLogActionsMessages(c *gc.C) {
+	good, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = good.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	bad, err := s.mysqlUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.ActionsMessages{Messages: []params.ActionMessageParams{{
+		ActionTag: good.ActionTag().String(),
+		Message:   "copying files",
+	}, {
+		ActionTag: good.ActionTag().String(),
+		Message:   "restarting",
+	}, {
+		ActionTag: bad.ActionTag().String(),
+		Message:   "not mine",
+	}}}
+	res, err := s.uniter.LogActionsMessages(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res, gc.DeepEquals, params.ErrorResults{Results: []params.ErrorResult{
+		{Error: nil},
+		{Error: nil},
+		{Error: apiservertesting.ErrUnauthorized},
+	}})
+
+	action, err := s.State.ActionByTag(good.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+	messages := action.Messages()
+	c.Assert(messages, gc.HasLen, 2)
+	c.Check(messages[0].Message, gc.Equals, "copying files")
+	c.Check(messages[1].Message, gc.Equals, "restarting")
+}
+
 func (s *uniterSuite) TestRelation(c *gc.C) {
 	rel := s.addRelation(c, "wordpress", "mysql")
 	wpEp, err := rel.Endpoint("wordpress")
@@ -2004,6 +2336,40 @@ func (s *uniterSuite) TestReadSettings(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestConditionalReadSettings(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.wordpressUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(map[string]interface{}{"some": "settings"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.VersionedRelationUnits{RelationUnits: []params.VersionedRelationUnit{
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0"},
+		{Relation: rel.Tag().String(), Unit: "unit-mysql-0"},
+	}}
+	result, err := s.uniter.ConditionalReadSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	version := result.Results[0].Version
+	c.Assert(version, gc.Not(gc.Equals), "")
+	c.Assert(result, gc.DeepEquals, params.SettingsResults{
+		Results: []params.SettingsResult{
+			{Settings: params.Settings{"some": "settings"}, Version: version},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	args.RelationUnits = args.RelationUnits[:1]
+	args.RelationUnits[0].Version = version
+	result, err = s.uniter.ConditionalReadSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.SettingsResults{
+		Results: []params.SettingsResult{
+			{Version: version, NotModified: true},
+		},
+	})
+}
+
 func (s *uniterSuite) TestReadSettingsWithNonStringValuesFails(c *gc.C) {
 	rel := s.addRelation(c, "wordpress", "mysql")
 	relUnit, err := rel.Unit(s.wordpressUnit)
@@ -2029,6 +2395,50 @@ func (s *uniterSuite) TestReadSettingsWithNonStringValuesFails(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestConditionalReadRemoteSettings(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(map[string]interface{}{"other": "things"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.VersionedRelationUnitPairs{RelationUnitPairs: []params.VersionedRelationUnitPair{{
+		Relation:   rel.Tag().String(),
+		LocalUnit:  "unit-wordpress-0",
+		RemoteUnit: "unit-mysql-0",
+	}}}
+	result, err := s.uniter.ConditionalReadRemoteSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	version := result.Results[0].Version
+	c.Assert(version, gc.Not(gc.Equals), "")
+	c.Assert(result.Results[0], gc.DeepEquals, params.SettingsResult{
+		Settings: params.Settings{"other": "things"},
+		Version:  version,
+	})
+
+	args.RelationUnitPairs[0].Version = version
+	result, err = s.uniter.ConditionalReadRemoteSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.SettingsResults{
+		Results: []params.SettingsResult{
+			{Version: version, NotModified: true},
+		},
+	})
+
+	// A stale version gets the current settings.
+	settings, err := relUnit.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("other", "stuff")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.ConditionalReadRemoteSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].NotModified, jc.IsFalse)
+	c.Assert(result.Results[0].Settings, gc.DeepEquals, params.Settings{"other": "stuff"})
+}
+
 func (s *uniterSuite) TestReadRemoteSettings(c *gc.C) {
 	rel := s.addRelation(c, "wordpress", "mysql")
 	relUnit, err := rel.Unit(s.wordpressUnit)
@@ -2199,6 +2609,178 @@ func (s *uniterSuite) TestUpdateSettings(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestWriteRelationSettings(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.wordpressUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(map[string]interface{}{
+		"some":  "settings",
+		"other": "stuff",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.RelationUnitsSettings{RelationUnits: []params.RelationUnitSettings{
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Settings: params.Settings{
+			"some":  "different",
+			"other": "",
+		}},
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Settings: params.Settings{
+			"more": "things",
+		}},
+	}}
+	result, err := s.uniter.WriteRelationSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{nil}, {nil}},
+	})
+
+	readSettings, err := relUnit.ReadSettings(s.wordpressUnit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readSettings, gc.DeepEquals, map[string]interface{}{
+		"some": "different",
+		"more": "things",
+	})
+}
+
+func (s *uniterSuite) TestWriteRelationSettingsNothingWrittenOnError(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.wordpressUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(map[string]interface{}{"some": "settings"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.RelationUnitsSettings{RelationUnits: []params.RelationUnitSettings{
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Settings: params.Settings{
+			"some": "different",
+		}},
+		{Relation: rel.Tag().String(), Unit: "unit-mysql-0", Settings: nil},
+		{Relation: "foo", Unit: "bar", Settings: nil},
+	}}
+	result, err := s.uniter.WriteRelationSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	readSettings, err := relUnit.ReadSettings(s.wordpressUnit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readSettings, gc.DeepEquals, map[string]interface{}{
+		"some": "settings",
+	})
+}
+
+func (s *uniterSuite) TestGoalStates(c *gc.C) {
+	s.addRelation(c, "wordpress", "mysql")
+	now := time.Now()
+	err := s.wordpressUnit.SetStatus(status.StatusInfo{Status: status.Maintenance, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysqlUnit.SetStatus(status.StatusInfo{Status: status.Active, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-mysql-0"},
+		{Tag: "application-wordpress"},
+	}}
+	result, err := s.uniter.GoalStates(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[2].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+
+	goalState := result.Results[0].Result
+	c.Assert(goalState, gc.NotNil)
+	c.Assert(goalState.Units, gc.HasLen, 1)
+	c.Assert(goalState.Units["wordpress/0"].Status, gc.Equals, "maintenance")
+	c.Assert(goalState.Units["wordpress/0"].Since.Equal(now), jc.IsTrue)
+	c.Assert(goalState.Relations, gc.HasLen, 1)
+	db := goalState.Relations["db"]
+	c.Assert(db, gc.HasLen, 2)
+	c.Assert(db["mysql"], jc.DeepEquals, params.GoalStateStatus{Status: "joined"})
+	c.Assert(db["mysql/0"].Status, gc.Equals, "active")
+	c.Assert(db["mysql/0"].Since.Equal(now), jc.IsTrue)
+}
+
+func (s *uniterSuite) TestCloudSpecUntrusted(c *gc.C) {
+	result, err := s.uniter.CloudSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result, jc.DeepEquals, &params.CloudSpec{
+		Type:             "dummy",
+		Name:             "dummy",
+		Region:           "dummy-region",
+		Endpoint:         "dummy-endpoint",
+		IdentityEndpoint: "dummy-identity-endpoint",
+		StorageEndpoint:  "dummy-storage-endpoint",
+	})
+}
+
+func (s *uniterSuite) TestCloudSpecTrusted(c *gc.C) {
+	app := s.Factory.MakeApplication(c, &jujufactory.ApplicationParams{Name: "trusted"})
+	err := app.SetTrusted(true)
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.Factory.MakeUnit(c, &jujufactory.UnitParams{Application: app})
+
+	authorizer := s.authorizer
+	authorizer.Tag = unit.Tag()
+	api, err := uniter.NewUniterAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := api.CloudSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result.Name, gc.Equals, "dummy")
+	c.Assert(result.Result.Credential, jc.DeepEquals, &params.CloudCredential{
+		AuthType: "userpass",
+		Attributes: map[string]string{
+			"username": "dummy",
+			"passeord": "secret",
+		},
+	})
+}
+
+func (s *uniterSuite) TestCloudSpecTrustConfigIgnored(c *gc.C) {
+	// A charm config option named "trust", which users without
+	// admin access may set, does not trust the application.
+	app := s.Factory.MakeApplication(c, &jujufactory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &jujufactory.CharmParams{Name: "trusted"}),
+	})
+	err := app.UpdateConfigSettings(charm.Settings{"trust": true})
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.Factory.MakeUnit(c, &jujufactory.UnitParams{Application: app})
+
+	authorizer := s.authorizer
+	authorizer.Tag = unit.Tag()
+	api, err := uniter.NewUniterAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := api.CloudSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result.Credential, gc.IsNil)
+}
+
+func (s *uniterSuite) TestGoalStatesDyingUnit(c *gc.C) {
+	s.addRelation(c, "wordpress", "mysql")
+	// An idle unit isn't removed immediately when destroyed.
+	err := s.mysqlUnit.SetAgentStatus(status.StatusInfo{Status: status.Idle})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysqlUnit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: "unit-wordpress-0"}}}
+	result, err := s.uniter.GoalStates(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.Relations["db"]["mysql/0"].Status, gc.Equals, "dying")
+}
+
 func (s *uniterSuite) TestWatchRelationUnits(c *gc.C) {
 	// Add a relation between wordpress and mysql and enter scope with
 	// mysqlUnit.
@@ -2276,6 +2858,32 @@ func (s *uniterSuite) TestWatchRelationUnits(c *gc.C) {
 	wc.AssertChange(nil, []string{"mysql/0"})
 }
 
+func (s *uniterSuite) TestConditionalModelConfig(c *gc.C) {
+	result, err := s.uniter.ConditionalModelConfig(params.ModelConfigVersion{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.NotModified, jc.IsFalse)
+	c.Assert(result.Version, gc.Not(gc.Equals), "")
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Config, jc.DeepEquals, params.ModelConfig(cfg.AllAttrs()))
+	version := result.Version
+
+	result, err = s.uniter.ConditionalModelConfig(params.ModelConfigVersion{Version: version})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ModelConfigResult{
+		Version:     version,
+		NotModified: true,
+	})
+
+	err = s.State.UpdateModelConfig(map[string]interface{}{"logging-config": "<root>=DEBUG"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.ConditionalModelConfig(params.ModelConfigVersion{Version: version})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.NotModified, jc.IsFalse)
+	c.Assert(result.Version, gc.Not(gc.Equals), version)
+	c.Assert(result.Config["logging-config"], gc.Equals, "<root>=DEBUG")
+}
+
 func (s *uniterSuite) TestAPIAddresses(c *gc.C) {
 	hostPorts := [][]network.HostPort{
 		network.NewHostPorts(1234, "0.1.2.3"),
@@ -4,8 +4,12 @@
 package upgrader
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
@@ -16,6 +20,8 @@ import (
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/status"
+	coretools "github.com/juju/juju/tools"
 	jujuversion "github.com/juju/juju/version"
 )
 
@@ -63,6 +69,7 @@ type UpgraderAPI struct {
 	st         *state.State
 	resources  facade.Resources
 	authorizer facade.Authorizer
+	clock      clock.Clock
 }
 
 // NewUpgraderAPI creates a new server-side UpgraderAPI facade.
@@ -89,6 +96,7 @@ func NewUpgraderAPI(
 		st:          st,
 		resources:   resources,
 		authorizer:  authorizer,
+		clock:       clock.WallClock,
 	}, nil
 }
 
@@ -157,7 +165,7 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	if len(args.Entities) == 0 {
 		return params.VersionResults{}, nil
 	}
-	agentVersion, _, err := u.getGlobalAgentVersion()
+	agentVersion, cfg, err := u.getGlobalAgentVersion()
 	if err != nil {
 		return params.VersionResults{}, common.ServerError(err)
 	}
@@ -181,11 +189,19 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
-			if !isNewerVersion || u.entityIsManager(tag) {
-				results[i].Version = &agentVersion
-			} else {
+			//
+			// Other agents are further held at their current version
+			// outside the model's maintenance windows, and until any
+			// upgrade canaries have proven the new version.
+			if isNewerVersion && !u.entityIsManager(tag) {
 				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, jujuversion.Current)
 				results[i].Version = &jujuversion.Current
+			} else if held, current, reason := u.upgradeHeld(tag, agentVersion, cfg); held {
+				logger.Debugf("desired version is %s, but upgrade of %s is held: %s", agentVersion, names.ReadableString(tag), reason)
+				results[i].Version = &current
+				results[i].Held = true
+			} else {
+				results[i].Version = &agentVersion
 			}
 			err = nil
 		}
@@ -193,3 +209,99 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	}
 	return params.VersionResults{Results: results}, nil
 }
+
+type hasAgentTools interface {
+	AgentTools() (*coretools.Tools, error)
+}
+
+// currentVersion returns the version the entity's agent last reported
+// running, or this API server's version if it has not reported one.
+func (u *UpgraderAPI) currentVersion(tag names.Tag) version.Number {
+	entity, err := u.st.FindEntity(tag)
+	if err != nil {
+		return jujuversion.Current
+	}
+	if e, ok := entity.(hasAgentTools); ok {
+		if tools, err := e.AgentTools(); err == nil {
+			return tools.Version.Number
+		}
+	}
+	return jujuversion.Current
+}
+
+// upgradeHeld reports whether the agent with the supplied tag must not
+// yet upgrade to agentVersion, and if so the version it is running and
+// why. Controller agents and
+// agents already running agentVersion are never held; other machine
+// agents are held outside the model's maintenance windows, and, if
+// upgrade canaries are configured, until every canary has run
+// agentVersion for the soak period without it, or its units, reporting
+// an error.
+func (u *UpgraderAPI) upgradeHeld(tag names.Tag, agentVersion version.Number, cfg *config.Config) (bool, version.Number, string) {
+	if u.entityIsManager(tag) {
+		return false, version.Number{}, ""
+	}
+	current := u.currentVersion(tag)
+	if current == agentVersion {
+		return false, version.Number{}, ""
+	}
+	now := u.clock.Now()
+	if !cfg.MaintenanceWindows().Allows(now) {
+		return true, current, "outside maintenance windows"
+	}
+	canaries := cfg.UpgradeCanaries()
+	for _, id := range canaries {
+		if tag == names.NewMachineTag(id) {
+			return false, version.Number{}, ""
+		}
+	}
+	for _, id := range canaries {
+		if reason := u.canaryPending(id, agentVersion, now.Add(-cfg.UpgradeCanarySoak())); reason != "" {
+			return true, current, reason
+		}
+	}
+	return false, version.Number{}, ""
+}
+
+// canaryPending returns why the canary machine with the supplied id
+// has not yet proven agentVersion, having run it since soakedBefore
+// without errors; or an empty string if it has, or if the machine no
+// longer exists.
+func (u *UpgraderAPI) canaryPending(id string, agentVersion version.Number, soakedBefore time.Time) string {
+	machine, err := u.st.Machine(id)
+	if errors.IsNotFound(err) {
+		return ""
+	} else if err != nil {
+		return fmt.Sprintf("cannot get canary machine %s: %v", id, err)
+	}
+	tools, err := machine.AgentTools()
+	if err != nil || tools.Version.Number != agentVersion {
+		return fmt.Sprintf("canary machine %s not yet upgraded", id)
+	}
+	agentStatus, err := machine.Status()
+	if err != nil {
+		return fmt.Sprintf("cannot get status of canary machine %s: %v", id, err)
+	}
+	if agentStatus.Status == status.Error {
+		logger.Warningf("agent upgrade halted: canary machine %s reports error: %s", id, agentStatus.Message)
+		return fmt.Sprintf("canary machine %s reports error", id)
+	}
+	if agentStatus.Since == nil || agentStatus.Since.After(soakedBefore) {
+		return fmt.Sprintf("canary machine %s still soaking", id)
+	}
+	units, err := machine.Units()
+	if err != nil {
+		return fmt.Sprintf("cannot get units of canary machine %s: %v", id, err)
+	}
+	for _, unit := range units {
+		unitStatus, err := unit.AgentStatus()
+		if err != nil {
+			return fmt.Sprintf("cannot get status of unit %s: %v", unit.Name(), err)
+		}
+		if unitStatus.Status == status.Error {
+			logger.Warningf("agent upgrade halted: unit %s on canary machine %s reports error: %s", unit.Name(), id, unitStatus.Message)
+			return fmt.Sprintf("unit %s on canary machine %s reports error", unit.Name(), id)
+		}
+	}
+	return ""
+}
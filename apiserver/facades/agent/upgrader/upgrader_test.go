@@ -5,6 +5,7 @@ package upgrader_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -21,7 +22,9 @@ import (
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/status"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	jujuversion "github.com/juju/juju/version"
 )
 
@@ -341,3 +344,109 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, jujuversion.Current)
 }
+
+// setUpCanary adds a canary machine, configures it as the model's only
+// upgrade canary, and requests an upgrade. It returns the canary, the
+// version the machines are running, and the version requested.
+func (s *upgraderSuite) setUpCanary(c *gc.C, attrs map[string]interface{}) (*state.Machine, version.Number, version.Number) {
+	canary, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	current := version.Binary{
+		Number: jujuversion.Current,
+		Arch:   arch.HostArch(),
+		Series: series.MustHostSeries(),
+	}
+	err = canary.SetAgentVersion(current)
+	c.Assert(err, jc.ErrorIsNil)
+	err = canary.SetStatus(status.StatusInfo{Status: status.Started})
+	c.Assert(err, jc.ErrorIsNil)
+
+	updateAttrs := map[string]interface{}{
+		"upgrade-canaries":    canary.Id(),
+		"upgrade-canary-soak": "0s",
+	}
+	for k, v := range attrs {
+		updateAttrs[k] = v
+	}
+	err = s.State.UpdateModelConfig(updateAttrs, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	newVersion := s.bumpDesiredAgentVersion(c)
+	// The API server has already upgraded.
+	s.PatchValue(&jujuversion.Current, newVersion)
+	return canary, current.Number, newVersion
+}
+
+func (s *upgraderSuite) upgradeCanary(c *gc.C, canary *state.Machine, newVersion version.Number) {
+	err := canary.SetAgentVersion(version.Binary{
+		Number: newVersion,
+		Arch:   arch.HostArch(),
+		Series: series.MustHostSeries(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *upgraderSuite) checkDesiredVersion(c *gc.C, machine *state.Machine, expect version.Number, held bool) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: machine.Tag()}
+	upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{{Tag: machine.Tag().String()}}}
+	results, err := upgraderAPI.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	c.Check(*results.Results[0].Version, gc.Equals, expect)
+	c.Check(results.Results[0].Held, gc.Equals, held)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldUntilCanariesUpgrade(c *gc.C) {
+	canary, oldVersion, newVersion := s.setUpCanary(c, nil)
+	s.checkDesiredVersion(c, canary, newVersion, false)
+	s.checkDesiredVersion(c, s.rawMachine, oldVersion, true)
+
+	s.upgradeCanary(c, canary, newVersion)
+	s.checkDesiredVersion(c, s.rawMachine, newVersion, false)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldWhileCanariesSoak(c *gc.C) {
+	canary, oldVersion, newVersion := s.setUpCanary(c, map[string]interface{}{
+		"upgrade-canary-soak": "1h",
+	})
+	s.upgradeCanary(c, canary, newVersion)
+	s.checkDesiredVersion(c, s.rawMachine, oldVersion, true)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHaltedByCanaryError(c *gc.C) {
+	canary, oldVersion, newVersion := s.setUpCanary(c, nil)
+	s.upgradeCanary(c, canary, newVersion)
+	err := canary.SetStatus(status.StatusInfo{
+		Status:  status.Error,
+		Message: "cannot start workers",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkDesiredVersion(c, s.rawMachine, oldVersion, true)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHaltedByCanaryUnitError(c *gc.C) {
+	canary, oldVersion, newVersion := s.setUpCanary(c, nil)
+	s.upgradeCanary(c, canary, newVersion)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Machine: canary})
+	err := unit.SetAgentStatus(status.StatusInfo{
+		Status:  status.Error,
+		Message: "hook failed: \"config-changed\"",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkDesiredVersion(c, s.rawMachine, oldVersion, true)
+}
+
+func (s *upgraderSuite) TestDesiredVersionHeldOutsideMaintenanceWindows(c *gc.C) {
+	start := time.Now().UTC().Add(2 * time.Hour)
+	window := fmt.Sprintf("%s-%s", start.Format("15:04"), start.Add(time.Hour).Format("15:04"))
+	canary, oldVersion, _ := s.setUpCanary(c, map[string]interface{}{
+		"maintenance-windows": window,
+	})
+	s.checkDesiredVersion(c, canary, oldVersion, true)
+	s.checkDesiredVersion(c, s.rawMachine, oldVersion, true)
+	s.checkDesiredVersion(c, s.apiMachine, jujuversion.Current, false)
+}
@@ -0,0 +1,86 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package upgradesteps implements the API facade used by agents to
+// record the upgrade steps they have completed.
+package upgradesteps
+
+import (
+	"github.com/juju/version"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Backend defines the State API used by the upgradesteps facade.
+type Backend interface {
+	UpgradeStepCompleted(agentTag string, vers version.Number, description string) (bool, error)
+	SetUpgradeStepCompleted(agentTag string, vers version.Number, description string) error
+}
+
+// Facade implements the API used by agents to record the upgrade
+// steps they have completed, so that steps are not run again when an
+// upgrade is retried.
+type Facade struct {
+	backend   Backend
+	canAccess common.AuthFunc
+}
+
+// New returns a new API facade for recording completed upgrade steps.
+// Agents may only check and record their own steps.
+func New(backend Backend, _ facade.Resources, authorizer facade.Authorizer) (*Facade, error) {
+	if !authorizer.AuthMachineAgent() && !authorizer.AuthUnitAgent() {
+		return nil, common.ErrPerm
+	}
+	return &Facade{
+		backend:   backend,
+		canAccess: authorizer.AuthOwner,
+	}, nil
+}
+
+// UpgradeStepsCompleted reports whether each of the given upgrade steps
+// has been recorded as completed by its agent.
+func (f *Facade) UpgradeStepsCompleted(args params.UpgradeSteps) (params.BoolResults, error) {
+	results := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Steps)),
+	}
+	for i, step := range args.Steps {
+		tag, err := f.agentTag(step.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		done, err := f.backend.UpgradeStepCompleted(tag.String(), step.Version, step.Description)
+		results.Results[i].Result = done
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// SetUpgradeStepsCompleted records each of the given upgrade steps as
+// completed by its agent.
+func (f *Facade) SetUpgradeStepsCompleted(args params.UpgradeSteps) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Steps)),
+	}
+	for i, step := range args.Steps {
+		tag, err := f.agentTag(step.Tag)
+		if err == nil {
+			err = f.backend.SetUpgradeStepCompleted(tag.String(), step.Version, step.Description)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// agentTag parses the given agent tag, and checks that it is the
+// tag of the authenticated agent.
+func (f *Facade) agentTag(tagString string) (names.Tag, error) {
+	tag, err := names.ParseTag(tagString)
+	if err != nil || !f.canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return tag, nil
+}
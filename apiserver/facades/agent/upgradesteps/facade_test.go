@@ -0,0 +1,108 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradesteps_test
+
+import (
+	"fmt"
+
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/agent/upgradesteps"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	testing.BaseSuite
+	backend    *mockBackend
+	authorizer *apiservertesting.FakeAuthorizer
+	facade     *upgradesteps.Facade
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{}
+	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("1")}
+	facade, err := upgradesteps.New(s.backend, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.facade = facade
+}
+
+func (s *facadeSuite) TestNewRequiresAgent(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("bob")
+	_, err := upgradesteps.New(s.backend, nil, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *facadeSuite) TestSetUpgradeStepsCompleted(c *gc.C) {
+	vers := version.MustParse("2.3.0")
+	result, err := s.facade.SetUpgradeStepsCompleted(params.UpgradeSteps{
+		Steps: []params.UpgradeStep{
+			{Tag: "machine-0", Version: vers, Description: "step"},
+			{Tag: "machine-1", Version: vers, Description: "step"},
+			{Tag: "invalid", Version: vers, Description: "step"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	s.backend.CheckCalls(c, []jujutesting.StubCall{
+		{"SetUpgradeStepCompleted", []interface{}{"machine-1", vers, "step"}},
+	})
+}
+
+func (s *facadeSuite) TestUpgradeStepsCompleted(c *gc.C) {
+	vers := version.MustParse("2.3.0")
+	s.backend.completed = []string{"machine-1 2.3.0 done"}
+	result, err := s.facade.UpgradeStepsCompleted(params.UpgradeSteps{
+		Steps: []params.UpgradeStep{
+			{Tag: "machine-1", Version: vers, Description: "done"},
+			{Tag: "machine-1", Version: vers, Description: "not done"},
+			{Tag: "machine-0", Version: vers, Description: "done"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: true},
+			{Result: false},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	s.backend.CheckCallNames(c, "UpgradeStepCompleted", "UpgradeStepCompleted")
+}
+
+type mockBackend struct {
+	jujutesting.Stub
+	completed []string
+}
+
+func (b *mockBackend) UpgradeStepCompleted(agentTag string, vers version.Number, description string) (bool, error) {
+	b.MethodCall(b, "UpgradeStepCompleted", agentTag, vers, description)
+	key := fmt.Sprintf("%s %s %s", agentTag, vers, description)
+	for _, done := range b.completed {
+		if done == key {
+			return true, b.NextErr()
+		}
+	}
+	return false, b.NextErr()
+}
+
+func (b *mockBackend) SetUpgradeStepCompleted(agentTag string, vers version.Number, description string) error {
+	b.MethodCall(b, "SetUpgradeStepCompleted", agentTag, vers, description)
+	return b.NextErr()
+}
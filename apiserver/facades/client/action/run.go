@@ -43,6 +43,9 @@ func getAllUnitNames(st *state.State, units, services []string) (result []names.
 
 // Run the commands specified on the machines identified through the
 // list of machines, units and services.
+//
+// Deprecated: clients should use the Operations facade, which groups
+// the actions enqueued into an operation.
 func (a *ActionAPI) Run(run params.RunParams) (results params.ActionResults, err error) {
 	if err := a.checkCanAdmin(); err != nil {
 		return results, err
@@ -70,6 +73,8 @@ func (a *ActionAPI) Run(run params.RunParams) (results params.ActionResults, err
 }
 
 // RunOnAllMachines attempts to run the specified command on all the machines.
+//
+// Deprecated: clients should use the Operations facade.
 func (a *ActionAPI) RunOnAllMachines(run params.RunParams) (results params.ActionResults, err error) {
 	if err := a.checkCanAdmin(); err != nil {
 		return results, err
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/set"
+	"github.com/juju/version"
 	"gopkg.in/juju/charm.v6-unstable"
 	csparams "gopkg.in/juju/charmrepo.v2-unstable/csclient/params"
 	"gopkg.in/juju/names.v2"
@@ -111,6 +112,10 @@ func (api *API) checkCanWrite() error {
 	return api.checkPermission(api.backend.ModelTag(), permission.WriteAccess)
 }
 
+func (api *API) checkCanAdmin() error {
+	return api.checkPermission(api.backend.ModelTag(), permission.AdminAccess)
+}
+
 // SetMetricCredentials sets credentials on the application.
 func (api *API) SetMetricCredentials(args params.ApplicationMetricCredentials) (params.ErrorResults, error) {
 	if err := api.checkCanWrite(); err != nil {
@@ -155,6 +160,30 @@ func (api *API) Deploy(args params.ApplicationsDeploy) (params.ErrorResults, err
 	return result, nil
 }
 
+// checkSpacesSupported returns an error satisfying errors.IsNotSupported
+// if the deployment refers to network spaces, via endpoint bindings or
+// constraints, and the model's environ does not support spaces.
+func checkSpacesSupported(backend Backend, args params.ApplicationDeploy) error {
+	usesSpaces := args.Constraints.Spaces != nil && len(*args.Constraints.Spaces) > 0
+	for _, space := range args.EndpointBindings {
+		if space != environs.DefaultSpaceName {
+			usesSpaces = true
+			break
+		}
+	}
+	if !usesSpaces {
+		return nil
+	}
+	supported, err := backend.SupportsSpaces()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !supported {
+		return errors.NotSupportedf("deploying %q to network spaces", args.ApplicationName)
+	}
+	return nil
+}
+
 // deployApplication fetches the charm from the charm store and deploys it.
 // The logic has been factored out into a common function which is called by
 // both the legacy API on the client facade, as well as the new application facade.
@@ -189,7 +218,11 @@ func deployApplication(
 		return errors.Trace(err)
 	}
 
-	if err := checkMinVersion(ch); err != nil {
+	if err := checkCharmMinVersion(backend, ch); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := checkSpacesSupported(backend, args); err != nil {
 		return errors.Trace(err)
 	}
 
@@ -234,6 +267,29 @@ func deployApplication(
 	return errors.Trace(err)
 }
 
+// checkCharmMinVersion ensures that the charm's declared minimum juju
+// version is satisfied by both the controller and the agents running
+// in the model, which may not yet have been upgraded to the controller's
+// version.
+func checkCharmMinVersion(backend Backend, ch Charm) error {
+	minver := ch.Meta().MinJujuVersion
+	if minver == version.Zero {
+		return nil
+	}
+	if err := checkMinVersion(ch); err != nil {
+		return errors.Trace(err)
+	}
+	cfg, err := backend.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	agentver, ok := cfg.AgentVersion()
+	if ok && minver.Compare(agentver) > 0 {
+		return minVersionError(minver, agentver)
+	}
+	return nil
+}
+
 // ApplicationSetSettingsStrings updates the settings for the given application,
 // taking the configuration from a map of strings.
 func ApplicationSetSettingsStrings(application Application, settings map[string]string) error {
@@ -392,6 +448,9 @@ func (api *API) applicationSetCharm(
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err := checkCharmMinVersion(api.backend, sch); err != nil {
+		return errors.Trace(err)
+	}
 	var settings charm.Settings
 	if configSettingsYAML != "" {
 		settings, err = sch.Config().ParseSettingsYAML([]byte(configSettingsYAML), appName)
@@ -526,6 +585,38 @@ func (api *API) Set(p params.ApplicationSet) error {
 
 }
 
+// SetApplicationsConfig sets config on several applications in a single
+// transaction, so that either all of the applications are updated or none
+// of them are. As with Set, values set to an empty string are not unset.
+func (api *API) SetApplicationsConfig(args params.ApplicationsSet) error {
+	if err := api.checkCanWrite(); err != nil {
+		return err
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	changes := make(map[string]charm.Settings)
+	for _, arg := range args.Applications {
+		if _, ok := changes[arg.ApplicationName]; ok {
+			return errors.Errorf("application %q specified more than once", arg.ApplicationName)
+		}
+		app, err := api.backend.Application(arg.ApplicationName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ch, _, err := app.Charm()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		settings, err := ch.Config().ParseSettingsStrings(arg.Options)
+		if err != nil {
+			return errors.Annotatef(err, "application %q", arg.ApplicationName)
+		}
+		changes[arg.ApplicationName] = settings
+	}
+	return api.backend.UpdateApplicationsConfigSettings(changes)
+}
+
 // Unset implements the server side of Client.Unset.
 func (api *API) Unset(p params.ApplicationUnset) error {
 	if err := api.checkCanWrite(); err != nil {
@@ -599,6 +690,23 @@ func (api *API) Unexpose(args params.ApplicationUnexpose) error {
 	return app.ClearExposed()
 }
 
+// SetTrust sets whether an application is trusted with the model's
+// cloud credential. Since trusting an application hands its charm the
+// credential, only model admins may change it.
+func (api *API) SetTrust(args params.ApplicationTrust) error {
+	if err := api.checkCanAdmin(); err != nil {
+		return err
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	app, err := api.backend.Application(args.ApplicationName)
+	if err != nil {
+		return err
+	}
+	return app.SetTrusted(args.Trusted)
+}
+
 // AddUnits adds a given number of units to an application.
 func (api *API) AddUnits(args params.AddApplicationUnits) (params.AddApplicationUnitsResults, error) {
 	if err := api.checkCanWrite(); err != nil {
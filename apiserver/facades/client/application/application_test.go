@@ -1489,6 +1489,64 @@ func (s *applicationSuite) TestApplicationSet(c *gc.C) {
 	})
 }
 
+func (s *applicationSuite) TestSetApplicationsConfig(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	dummy1 := s.AddTestingApplication(c, "dummy1", ch)
+	dummy2 := s.AddTestingApplication(c, "dummy2", ch)
+
+	err := s.applicationAPI.SetApplicationsConfig(params.ApplicationsSet{
+		Applications: []params.ApplicationSet{{
+			ApplicationName: "dummy1",
+			Options:         map[string]string{"title": "foo"},
+		}, {
+			ApplicationName: "dummy2",
+			Options:         map[string]string{"title": "bar", "skill-level": "9"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := dummy1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"title": "foo"})
+	settings, err = dummy2.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"title": "bar", "skill-level": int64(9)})
+}
+
+func (s *applicationSuite) TestSetApplicationsConfigAllOrNothing(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	dummy1 := s.AddTestingApplication(c, "dummy1", ch)
+	s.AddTestingApplication(c, "dummy2", ch)
+
+	err := s.applicationAPI.SetApplicationsConfig(params.ApplicationsSet{
+		Applications: []params.ApplicationSet{{
+			ApplicationName: "dummy1",
+			Options:         map[string]string{"title": "foo"},
+		}, {
+			ApplicationName: "dummy2",
+			Options:         map[string]string{"skill-level": "lots"},
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, `application "dummy2": option "skill-level" expected int, got "lots"`)
+	settings, err := dummy1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{})
+}
+
+func (s *applicationSuite) TestSetApplicationsConfigDuplicateApplication(c *gc.C) {
+	s.AddTestingApplication(c, "dummy", s.AddTestingCharm(c, "dummy"))
+
+	err := s.applicationAPI.SetApplicationsConfig(params.ApplicationsSet{
+		Applications: []params.ApplicationSet{{
+			ApplicationName: "dummy",
+			Options:         map[string]string{"title": "foo"},
+		}, {
+			ApplicationName: "dummy",
+			Options:         map[string]string{"title": "bar"},
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, `application "dummy" specified more than once`)
+}
+
 func (s *applicationSuite) assertApplicationSetBlocked(c *gc.C, dummy *state.Application, msg string) {
 	err := s.applicationAPI.Set(params.ApplicationSet{
 		ApplicationName: "dummy",
@@ -1850,6 +1908,42 @@ func (s *applicationSuite) TestApplicationExpose(c *gc.C) {
 	}
 }
 
+func (s *applicationSuite) TestApplicationSetTrust(c *gc.C) {
+	err := s.applicationAPI.SetTrust(params.ApplicationTrust{
+		ApplicationName: s.application.Name(),
+		Trusted:         true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.application.IsTrusted(), jc.IsTrue)
+
+	err = s.applicationAPI.SetTrust(params.ApplicationTrust{
+		ApplicationName: s.application.Name(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.application.IsTrusted(), jc.IsFalse)
+}
+
+func (s *applicationSuite) TestApplicationSetTrustRequiresAdmin(c *gc.C) {
+	// Users who may change charm config may not trust applications.
+	s.authorizer = &apiservertesting.FakeAuthorizer{
+		Tag:         names.NewUserTag("fred"),
+		HasWriteTag: names.NewUserTag("fred"),
+	}
+	api := s.makeAPI(c)
+	err := api.SetTrust(params.ApplicationTrust{
+		ApplicationName: s.application.Name(),
+		Trusted:         true,
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = s.application.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.application.IsTrusted(), jc.IsFalse)
+}
+
 func (s *applicationSuite) setupApplicationExpose(c *gc.C) {
 	charm := s.AddTestingCharm(c, "dummy")
 	applicationNames := []string{"dummy-application", "exposed-application"}
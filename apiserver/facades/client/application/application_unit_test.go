@@ -4,10 +4,13 @@
 package application_test
 
 import (
+	"regexp"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
@@ -199,6 +202,73 @@ postgresql:
 	})
 }
 
+func (s *ApplicationSuite) TestSetCharmMinJujuVersionNotMet(c *gc.C) {
+	s.backend.charm.meta = &charm.Meta{MinJujuVersion: version.MustParse("2.1.0")}
+	s.backend.modelConfig = coretesting.CustomModelConfig(c, coretesting.Attrs{
+		"agent-version": "2.0.0",
+	})
+	err := s.api.SetCharm(params.ApplicationSetCharm{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql",
+	})
+	c.Assert(err, gc.ErrorMatches, regexp.QuoteMeta(
+		"charm's min version (2.1.0) is higher than this juju environment's version (2.0.0)",
+	))
+	c.Assert(params.IsCodeMinJujuVersionNotMet(err), jc.IsTrue)
+	s.backend.CheckCallNames(c, "ModelTag", "Application", "Charm", "ModelConfig")
+	app := s.backend.applications["postgresql"].(*mockApplication)
+	app.CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestSetCharmMinJujuVersionMet(c *gc.C) {
+	s.backend.charm.meta = &charm.Meta{MinJujuVersion: version.MustParse("2.0.0")}
+	s.backend.modelConfig = coretesting.CustomModelConfig(c, coretesting.Attrs{
+		"agent-version": "2.0.0",
+	})
+	err := s.api.SetCharm(params.ApplicationSetCharm{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "ModelTag", "Application", "Charm", "ModelConfig")
+	app := s.backend.applications["postgresql"].(*mockApplication)
+	app.CheckCallNames(c, "SetCharm")
+}
+
+func (s *ApplicationSuite) TestDeployMinJujuVersionNotMet(c *gc.C) {
+	s.backend.charm.meta = &charm.Meta{MinJujuVersion: version.MustParse("2.1.0")}
+	s.backend.modelConfig = coretesting.CustomModelConfig(c, coretesting.Attrs{
+		"agent-version": "2.0.0",
+	})
+	results, err := s.api.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "foo",
+			CharmURL:        "local:foo-0",
+			NumUnits:        1,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.NotNil)
+	c.Assert(results.Results[0].Error.Code, gc.Equals, params.CodeMinJujuVersionNotMet)
+}
+
+func (s *ApplicationSuite) TestDeploySpacesNotSupported(c *gc.C) {
+	results, err := s.api.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName:  "foo",
+			CharmURL:         "local:foo-0",
+			NumUnits:         1,
+			EndpointBindings: map[string]string{"db": "internal"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `deploying "foo" to network spaces not supported`)
+	c.Assert(results.Results[0].Error.Code, gc.Equals, params.CodeNotSupported)
+	s.backend.CheckCallNames(c, "ModelTag", "Charm", "SupportsSpaces")
+}
+
 func (s *ApplicationSuite) TestDestroyRelation(c *gc.C) {
 	err := s.api.DestroyRelation(params.DestroyRelation{Endpoints: []string{"a", "b"}})
 	c.Assert(err, jc.ErrorIsNil)
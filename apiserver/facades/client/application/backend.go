@@ -12,9 +12,12 @@ import (
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/crossmodel"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
 // Backend defines the state functionality required by the application
@@ -34,10 +37,16 @@ type Backend interface {
 	EndpointsRelation(...state.Endpoint) (Relation, error)
 	InferEndpoints(...string) ([]state.Endpoint, error)
 	Machine(string) (Machine, error)
+	ModelConfig() (*config.Config, error)
 	ModelTag() names.ModelTag
 	Unit(string) (Unit, error)
+	UpdateApplicationsConfigSettings(map[string]charm.Settings) error
 	SaveController(info crossmodel.ControllerInfo, modelUUID string) (ExternalController, error)
 	ControllerTag() names.ControllerTag
+
+	// SupportsSpaces reports whether the model's environ supports
+	// network spaces.
+	SupportsSpaces() (bool, error)
 }
 
 // BlockChecker defines the block-checking functionality required by
@@ -70,6 +79,7 @@ type Application interface {
 	SetExposed() error
 	SetMetricCredentials([]byte) error
 	SetMinUnits(int) error
+	SetTrusted(bool) error
 	UpdateConfigSettings(charm.Settings) error
 }
 
@@ -134,6 +144,14 @@ func (s stateShim) SaveController(controllerInfo crossmodel.ControllerInfo, mode
 	return api.Save(controllerInfo, modelUUID)
 }
 
+func (s stateShim) SupportsSpaces() (bool, error) {
+	env, err := stateenvirons.GetNewEnvironFunc(environs.New)(s.State)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return environs.SupportsSpaces(env), nil
+}
+
 // NewStateBackend converts a state.State into a Backend.
 func NewStateBackend(st *state.State, pool *state.StatePool) (Backend, error) {
 	im, err := st.IAASModel()
@@ -175,6 +175,12 @@ type minJujuVersionErr struct {
 	*errors.Err
 }
 
+// ErrorCode implements params.ErrorCoder, so that clients can
+// distinguish version incompatibilities from other deploy failures.
+func (minJujuVersionErr) ErrorCode() string {
+	return params.CodeMinJujuVersionNotMet
+}
+
 func minVersionError(minver, jujuver version.Number) error {
 	err := errors.NewErr("charm's min version (%s) is higher than this juju environment's version (%s)",
 		minver, jujuver)
@@ -205,14 +211,13 @@ type CharmArchive struct {
 }
 
 // StoreCharmArchive stores a charm archive in environment storage.
+// Archives with a known SHA256 hash are stored in the controller's
+// blob store, so that a charm deployed in many models is stored once.
 func StoreCharmArchive(st *state.State, archive CharmArchive) error {
 	storage := newStateStorage(st.ModelUUID(), st.MongoSession())
-	storagePath, err := charmArchiveStoragePath(archive.ID)
+	storagePath, err := putCharmArchive(storage, archive)
 	if err != nil {
-		return errors.Annotate(err, "cannot generate charm archive name")
-	}
-	if err := storage.Put(storagePath, archive.Data, archive.Size); err != nil {
-		return errors.Annotate(err, "cannot add charm to storage")
+		return errors.Trace(err)
 	}
 
 	info := state.CharmInfo{
@@ -246,6 +251,22 @@ func StoreCharmArchive(st *state.State, archive CharmArchive) error {
 	return nil
 }
 
+// putCharmArchive stores the data of a charm archive, returning the
+// path from which it may be read from the model's storage.
+func putCharmArchive(stor storage.Storage, archive CharmArchive) (string, error) {
+	storagePath, err := charmArchiveStoragePath(archive.ID)
+	if err != nil {
+		return "", errors.Annotate(err, "cannot generate charm archive name")
+	}
+	if archive.SHA256 != "" {
+		storagePath = storage.BlobPath(storage.SHA256BlobID(archive.SHA256), storagePath)
+	}
+	if err := stor.Put(storagePath, archive.Data, archive.Size); err != nil {
+		return "", errors.Annotate(err, "cannot add charm to storage")
+	}
+	return storagePath, nil
+}
+
 // charmArchiveStoragePath returns a string that is suitable as a
 // storage path, using a random UUID to avoid colliding with concurrent
 // uploads.
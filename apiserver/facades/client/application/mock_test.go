@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/application"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -245,6 +246,8 @@ type mockBackend struct {
 	storageInstances           map[string]*mockStorage
 	storageInstanceFilesystems map[string]*mockFilesystem
 	controllers                map[string]crossmodel.ControllerInfo
+	modelConfig                *config.Config
+	supportsSpaces             bool
 }
 
 func (m *mockBackend) ControllerTag() names.ControllerTag {
@@ -445,6 +448,22 @@ func (m *mockBackend) ModelUUID() string {
 	return m.modelUUID
 }
 
+func (m *mockBackend) SupportsSpaces() (bool, error) {
+	m.MethodCall(m, "SupportsSpaces")
+	if err := m.NextErr(); err != nil {
+		return false, err
+	}
+	return m.supportsSpaces, nil
+}
+
+func (m *mockBackend) ModelConfig() (*config.Config, error) {
+	m.MethodCall(m, "ModelConfig")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return m.modelConfig, nil
+}
+
 func (m *mockBackend) ModelTag() names.ModelTag {
 	m.MethodCall(m, "ModelTag")
 	m.PopNoErr()
@@ -28,7 +28,10 @@ type Unit interface {
 	PublicAddress() (network.Address, error)
 	PrivateAddress() (network.Address, error)
 	Resolve(retryHooks bool) error
+	SetSuspended(suspended bool) error
 	AgentHistory() status.StatusHistoryGetter
+	AddStatusAnnotation(author, message string) error
+	StatusAnnotations(filter status.StatusHistoryFilter) ([]status.StatusInfo, error)
 }
 
 // Backend contains the state.State methods used in this package,
@@ -67,6 +67,12 @@ type Client struct {
 	check      *common.BlockChecker
 }
 
+// ClientV1 doesn't have the SuspendUnits, ResumeUnits or
+// AddStatusAnnotations methods.
+type ClientV1 struct {
+	*Client
+}
+
 func (c *Client) checkCanRead() error {
 	isAdmin, err := c.api.auth.HasPermission(permission.SuperuserAccess, c.api.stateAccessor.ControllerTag())
 	if err != nil {
@@ -147,6 +153,26 @@ func NewFacade(ctx facade.Context) (*Client, error) {
 	)
 }
 
+// NewFacadeV1 creates a version 1 Client facade to handle API requests.
+func NewFacadeV1(ctx facade.Context) (*ClientV1, error) {
+	client, err := NewFacade(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientV1{client}, nil
+}
+
+// Mask the new methods from the V1 API.
+
+// SuspendUnits isn't on the V1 API.
+func (c *ClientV1) SuspendUnits(_, _ struct{}) {}
+
+// ResumeUnits isn't on the V1 API.
+func (c *ClientV1) ResumeUnits(_, _ struct{}) {}
+
+// AddStatusAnnotations isn't on the V1 API.
+func (c *ClientV1) AddStatusAnnotations(_, _ struct{}) {}
+
 // NewClient creates a new instance of the Client Facade.
 func NewClient(
 	st Backend,
@@ -218,6 +244,43 @@ func (c *Client) Resolved(p params.Resolved) error {
 	return unit.Resolve(p.Retry)
 }
 
+// SuspendUnits stops the agents of the given units from running any
+// further hooks until the units are resumed.
+func (c *Client) SuspendUnits(args params.Entities) (params.ErrorResults, error) {
+	return c.setUnitsSuspended(args, true)
+}
+
+// ResumeUnits allows the agents of the given suspended units to run
+// hooks again.
+func (c *Client) ResumeUnits(args params.Entities) (params.ErrorResults, error) {
+	return c.setUnitsSuspended(args, false)
+}
+
+func (c *Client) setUnitsSuspended(args params.Entities, suspended bool) (params.ErrorResults, error) {
+	if err := c.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		unit, err := c.api.stateAccessor.Unit(tag.Id())
+		if err == nil {
+			err = unit.SetSuspended(suspended)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // PublicAddress implements the server side of Client.PublicAddress.
 func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAddressResults, err error) {
 	if err := c.checkCanRead(); err != nil {
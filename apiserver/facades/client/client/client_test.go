@@ -574,6 +574,62 @@ func (s *clientSuite) assertResolvedBlocked(c *gc.C, u *state.Unit, msg string)
 	s.AssertBlocked(c, err, msg)
 }
 
+func (s *clientSuite) TestClientSuspendResumeUnits(c *gc.C) {
+	s.setUpScenario(c)
+	results, err := s.APIState.Client().SuspendUnits(
+		names.NewUnitTag("wordpress/0"),
+		names.NewUnitTag("wordpress/99"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, `unit "wordpress/99" not found`)
+
+	u, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Suspended(), jc.IsTrue)
+
+	results, err = s.APIState.Client().ResumeUnits(names.NewUnitTag("wordpress/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{}})
+	err = u.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Suspended(), jc.IsFalse)
+}
+
+func (s *clientSuite) TestBlockChangeSuspendUnits(c *gc.C) {
+	s.setUpScenario(c)
+	s.BlockAllChanges(c, "TestBlockChangeSuspendUnits")
+	_, err := s.APIState.Client().SuspendUnits(names.NewUnitTag("wordpress/0"))
+	s.AssertBlocked(c, err, "TestBlockChangeSuspendUnits")
+}
+
+func (s *clientSuite) TestClientAddStatusAnnotation(c *gc.C) {
+	s.setUpScenario(c)
+	err := s.APIState.Client().AddStatusAnnotation(names.NewUnitTag("wordpress/0"), "disk replaced")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().AddStatusAnnotation(names.NewMachineTag("0"), "rebooted")
+	c.Assert(err, jc.ErrorIsNil)
+
+	u, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	annotations, err := u.StatusAnnotations(status.StatusHistoryFilter{Size: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 1)
+	c.Assert(annotations[0].Message, gc.Equals, "disk replaced")
+	c.Assert(annotations[0].Data, jc.DeepEquals, map[string]interface{}{"author": "admin"})
+
+	m, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	annotations, err = m.StatusAnnotations(status.StatusHistoryFilter{Size: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 1)
+	c.Assert(annotations[0].Message, gc.Equals, "rebooted")
+
+	err = s.APIState.Client().AddStatusAnnotation(names.NewApplicationTag("wordpress"), "hello")
+	c.Assert(err, gc.ErrorMatches, `status annotation for application wordpress not valid`)
+}
+
 func (s *serverSuite) TestCACert(c *gc.C) {
 	r, err := s.APIState.Client().CACert()
 	c.Assert(err, jc.ErrorIsNil)
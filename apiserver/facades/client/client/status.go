@@ -73,15 +73,23 @@ func (c *Client) unitStatusHistory(unitTag names.UnitTag, filter status.StatusHi
 		}
 		statuses = append(statuses, agentStatusFromStatusInfo(agentStatuses, status.KindUnitAgent)...)
 	}
+	annotations, err := unit.StatusAnnotations(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	statuses = append(statuses, agentStatusFromStatusInfo(annotations, status.KindAnnotation)...)
 
 	sort.Sort(byTime(statuses))
-	if kind == status.KindUnit && filter.Size > 0 {
-		if len(statuses) > filter.Size {
-			statuses = statuses[len(statuses)-filter.Size:]
-		}
-	}
+	return limitHistory(statuses, filter), nil
+}
 
-	return statuses, nil
+// limitHistory returns the latest filter.Size entries of a history,
+// sorted by time, that was merged from several sources.
+func limitHistory(statuses []params.DetailedStatus, filter status.StatusHistoryFilter) []params.DetailedStatus {
+	if filter.Size > 0 && len(statuses) > filter.Size {
+		return statuses[len(statuses)-filter.Size:]
+	}
+	return statuses
 }
 
 // machineStatusHistory returns status history for the given machine.
@@ -99,7 +107,50 @@ func (c *Client) machineStatusHistory(machineTag names.MachineTag, filter status
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return agentStatusFromStatusInfo(sInfo, kind), nil
+	annotations, err := machine.StatusAnnotations(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	statuses := agentStatusFromStatusInfo(sInfo, kind)
+	statuses = append(statuses, agentStatusFromStatusInfo(annotations, status.KindAnnotation)...)
+	sort.Sort(byTime(statuses))
+	return limitHistory(statuses, filter), nil
+}
+
+// AddStatusAnnotations adds notes, attributed to the authenticated
+// user, to the status history of units and machines. They are shown
+// alongside the entities' statuses by show-status-log.
+func (c *Client) AddStatusAnnotations(args params.StatusAnnotations) (params.ErrorResults, error) {
+	if err := c.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, err
+	}
+	author := c.api.auth.GetAuthTag().Id()
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Annotations)),
+	}
+	for i, arg := range args.Annotations {
+		tag, err := names.ParseTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		switch tag := tag.(type) {
+		case names.UnitTag:
+			var unit Unit
+			if unit, err = c.api.stateAccessor.Unit(tag.Id()); err == nil {
+				err = unit.AddStatusAnnotation(author, arg.Message)
+			}
+		case names.MachineTag:
+			var machine *state.Machine
+			if machine, err = c.api.stateAccessor.Machine(tag.Id()); err == nil {
+				err = machine.AddStatusAnnotation(author, arg.Message)
+			}
+		default:
+			err = errors.NotValidf("status annotation for %s", names.ReadableString(tag))
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
 }
 
 // StatusHistory returns a slice of past statuses for several entities.
@@ -939,6 +990,16 @@ func (context *statusContext) processApplication(application *state.Application)
 		sort.Sort(bySinceDescending(versions))
 		processedStatus.WorkloadVersion = versions[0].Message
 	}
+	// A version recorded by the leader for the application as a
+	// whole takes precedence over those reported by its units.
+	applicationVersion, err := application.WorkloadVersion()
+	if err != nil {
+		processedStatus.Err = common.ServerError(err)
+		return processedStatus
+	}
+	if applicationVersion != "" {
+		processedStatus.WorkloadVersion = applicationVersion
+	}
 
 	return processedStatus
 }
@@ -323,6 +323,21 @@ func (s *statusUnitTestSuite) TestWorkloadVersionOkWithUnset(c *gc.C) {
 	checkUnitVersion(c, appStatus, unit, "")
 }
 
+func (s *statusUnitTestSuite) TestWorkloadVersionApplicationWins(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit1 := addUnitWithVersion(c, application, "voltron")
+	err := s.State.LeadershipClaimer().ClaimLeadership(application.Name(), unit1.Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	token := s.State.LeadershipChecker().LeadershipCheck(application.Name(), unit1.Name())
+	err = application.SetWorkloadVersion(token, "lion force")
+	c.Assert(err, jc.ErrorIsNil)
+	unit2 := addUnitWithVersion(c, application, "zarkon")
+
+	appStatus := s.checkAppVersion(c, application, "lion force")
+	checkUnitVersion(c, appStatus, unit1, "voltron")
+	checkUnitVersion(c, appStatus, unit2, "zarkon")
+}
+
 func (s *statusUnitTestSuite) TestMigrationInProgress(c *gc.C) {
 
 	// Create a host model because controller models can't be migrated.
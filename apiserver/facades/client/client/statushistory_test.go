@@ -219,10 +219,48 @@ func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 	checkStatusInfo(c, h.Results[0].History.Statuses, expected)
 }
 
+func (s *statusHistoryTestSuite) TestStatusHistoryIncludesAnnotations(c *gc.C) {
+	s.st.unitHistory = statusInfoWithDates([]status.StatusInfo{
+		{
+			Status:  status.Active,
+			Message: "running",
+		},
+		{
+			Status:  status.Maintenance,
+			Message: "working",
+		},
+	})
+	s.st.unitAnnotations = []status.StatusInfo{{
+		Message: "disk replaced, ticket OPS-42",
+		Data:    map[string]interface{}{"author": "admin"},
+		Since:   s.st.unitHistory[0].Since,
+	}}
+	h := s.api.StatusHistory(params.StatusHistoryRequests{
+		Requests: []params.StatusHistoryRequest{{
+			Tag:    "unit-unit-0",
+			Kind:   status.KindWorkload.String(),
+			Filter: params.StatusHistoryFilter{Size: 10},
+		}}})
+	c.Assert(h.Results, gc.HasLen, 1)
+	c.Assert(h.Results[0].Error, gc.IsNil)
+	statuses := h.Results[0].History.Statuses
+	c.Assert(statuses, gc.HasLen, 3)
+	var found bool
+	for _, st := range statuses {
+		if st.Kind == status.KindAnnotation.String() {
+			found = true
+			c.Check(st.Info, gc.Equals, "disk replaced, ticket OPS-42")
+			c.Check(st.Data["author"], gc.Equals, "admin")
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+}
+
 type mockState struct {
 	client.Backend
-	unitHistory  []status.StatusInfo
-	agentHistory []status.StatusInfo
+	unitHistory     []status.StatusInfo
+	agentHistory    []status.StatusInfo
+	unitAnnotations []status.StatusInfo
 }
 
 func (m *mockState) ModelUUID() string {
@@ -242,14 +280,16 @@ func (m *mockState) Unit(name string) (client.Unit, error) {
 		return nil, errors.NotFoundf("%v", name)
 	}
 	return &mockUnit{
-		status: m.unitHistory,
-		agent:  &mockUnitAgent{m.agentHistory},
+		status:      m.unitHistory,
+		agent:       &mockUnitAgent{m.agentHistory},
+		annotations: m.unitAnnotations,
 	}, nil
 }
 
 type mockUnit struct {
-	status statuses
-	agent  *mockUnitAgent
+	status      statuses
+	agent       *mockUnitAgent
+	annotations statuses
 	client.Unit
 }
 
@@ -257,6 +297,10 @@ func (m *mockUnit) StatusHistory(filter status.StatusHistoryFilter) ([]status.St
 	return m.status.StatusHistory(filter)
 }
 
+func (m *mockUnit) StatusAnnotations(filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	return m.annotations.StatusHistory(filter)
+}
+
 func (m *mockUnit) AgentHistory() status.StatusHistoryGetter {
 	return m.agent
 }
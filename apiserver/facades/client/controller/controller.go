@@ -26,10 +26,21 @@ import (
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
+	"github.com/juju/juju/upgrades"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.controller")
 
+// ControllerAPIv6 provides the v6 Controller API.
+type ControllerAPIv6 struct {
+	*ControllerAPIv5
+}
+
+// ControllerAPIv5 provides the v5 Controller API.
+type ControllerAPIv5 struct {
+	*ControllerAPIv4
+}
+
 // ControllerAPIv4 provides the v4 Controller API.
 type ControllerAPIv4 struct {
 	*ControllerAPIv3
@@ -48,6 +59,24 @@ type ControllerAPIv3 struct {
 	resources  facade.Resources
 }
 
+// NewControllerAPIv6 creates a new ControllerAPIv6.
+func NewControllerAPIv6(ctx facade.Context) (*ControllerAPIv6, error) {
+	v5, err := NewControllerAPIv5(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv6{v5}, nil
+}
+
+// NewControllerAPIv5 creates a new ControllerAPIv5.
+func NewControllerAPIv5(ctx facade.Context) (*ControllerAPIv5, error) {
+	v4, err := NewControllerAPIv4(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv5{v4}, nil
+}
+
 // NewControllerAPIv4 creates a new ControllerAPIv4.
 func NewControllerAPIv4(ctx facade.Context) (*ControllerAPIv4, error) {
 	v3, err := NewControllerAPIv3(ctx)
@@ -97,6 +126,57 @@ func (s *ControllerAPIv3) checkHasAdmin() error {
 	return nil
 }
 
+// ConfigSet changes the controller config. Only controller
+// administrators may change it, and only the attributes in
+// controller.AllowedUpdateConfigAttributes may be changed.
+func (s *ControllerAPIv5) ConfigSet(args params.ControllerConfigSet) error {
+	if err := s.checkHasAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := s.state.UpdateControllerConfig(args.Config, nil); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// UpgradeDryRun returns the upgrade steps that the controller would run
+// when upgraded to the given version, and whether the preconditions of
+// each are met. No steps are run. Only controller administrators may
+// check an upgrade.
+func (s *ControllerAPIv6) UpgradeDryRun(args params.UpgradeDryRunArgs) (params.UpgradeDryRunResult, error) {
+	result := params.UpgradeDryRunResult{}
+	if err := s.checkHasAdmin(); err != nil {
+		return result, errors.Trace(err)
+	}
+	st := s.statePool.SystemState()
+	cfg, err := st.ModelConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	from, ok := cfg.AgentVersion()
+	if !ok {
+		return result, errors.New("controller model has no agent version")
+	}
+	if args.TargetVersion.Compare(from) < 0 {
+		return result, errors.Errorf("cannot upgrade from %s to %s", from, args.TargetVersion)
+	}
+	context := upgrades.NewContext(nil, nil, upgrades.NewStateBackend(st, s.statePool))
+	checks, err := upgrades.DryRunStateSteps(from, args.TargetVersion, context)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Steps = make([]params.UpgradeStepCheck, len(checks))
+	for i, check := range checks {
+		result.Steps[i] = params.UpgradeStepCheck{
+			Version:     check.TargetVersion.String(),
+			Description: check.Description,
+			Feature:     check.Feature,
+			Error:       common.ServerError(check.Err),
+		}
+	}
+	return result, nil
+}
+
 // AllModels allows controller administrators to get the list of all the
 // models in the controller.
 func (s *ControllerAPIv3) AllModels() (params.UserModelList, error) {
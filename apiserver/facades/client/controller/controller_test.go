@@ -12,6 +12,7 @@ import (
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon.v1"
@@ -305,6 +306,100 @@ func (s *controllerSuite) TestControllerConfigFromNonController(c *gc.C) {
 	c.Assert(cfg.Config["api-port"], gc.Equals, cfgFromDB.APIPort())
 }
 
+func (s *controllerSuite) newControllerAPIv5(c *gc.C, authorizer apiservertesting.FakeAuthorizer) *controller.ControllerAPIv5 {
+	api, err := controller.NewControllerAPIv5(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.statePool,
+			Resources_: s.resources,
+			Auth_:      authorizer,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *controllerSuite) TestConfigSet(c *gc.C) {
+	api := s.newControllerAPIv5(c, s.authorizer)
+	err := api.ConfigSet(params.ControllerConfigSet{Config: map[string]interface{}{
+		"features": "new-schema",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Features().Values(), jc.DeepEquals, []string{"new-schema"})
+}
+
+func (s *controllerSuite) TestConfigSetRejectsBootstrapOnlyAttributes(c *gc.C) {
+	api := s.newControllerAPIv5(c, s.authorizer)
+	err := api.ConfigSet(params.ControllerConfigSet{Config: map[string]interface{}{
+		"api-port": 1234,
+	}})
+	c.Assert(err, gc.ErrorMatches, `can't change "api-port" after bootstrap`)
+}
+
+func (s *controllerSuite) TestConfigSetRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	api := s.newControllerAPIv5(c, apiservertesting.FakeAuthorizer{Tag: user.UserTag()})
+	err := api.ConfigSet(params.ControllerConfigSet{Config: map[string]interface{}{
+		"features": "new-schema",
+	}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) newControllerAPIv6(c *gc.C, authorizer apiservertesting.FakeAuthorizer) *controller.ControllerAPIv6 {
+	api, err := controller.NewControllerAPIv6(
+		facadetest.Context{
+			State_:     s.State,
+			StatePool_: s.statePool,
+			Resources_: s.resources,
+			Auth_:      authorizer,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *controllerSuite) TestUpgradeDryRun(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{"agent-version": "2.2.1"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	api := s.newControllerAPIv6(c, s.authorizer)
+	result, err := api.UpgradeDryRun(params.UpgradeDryRunArgs{
+		TargetVersion: version.MustParse("2.2.3"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Steps, jc.DeepEquals, []params.UpgradeStepCheck{{
+		Version:     "2.2.2",
+		Description: "add environ-version to model docs",
+	}, {
+		Version:     "2.2.3",
+		Description: "add max-action-age and max-action-size config settings",
+	}})
+
+	// Nothing was changed.
+	cfg, err := s.State.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	agentVersion, _ := cfg.AgentVersion()
+	c.Assert(agentVersion, gc.Equals, version.MustParse("2.2.1"))
+}
+
+func (s *controllerSuite) TestUpgradeDryRunRejectsDowngrade(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{"agent-version": "2.2.1"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	api := s.newControllerAPIv6(c, s.authorizer)
+	_, err = api.UpgradeDryRun(params.UpgradeDryRunArgs{
+		TargetVersion: version.MustParse("2.1.0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot upgrade from 2.2.1 to 2.1.0")
+}
+
+func (s *controllerSuite) TestUpgradeDryRunRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	api := s.newControllerAPIv6(c, apiservertesting.FakeAuthorizer{Tag: user.UserTag()})
+	_, err := api.UpgradeDryRun(params.UpgradeDryRunArgs{
+		TargetVersion: version.MustParse("2.2.3"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *controllerSuite) TestRemoveBlocks(c *gc.C) {
 	st := s.Factory.MakeModel(c, &factory.ModelParams{
 		Name: "test"})
@@ -0,0 +1,136 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package debuglogpresets provides the API for managing named
+// debug-log filter presets stored on the controller.
+package debuglogpresets
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// DebugLogPresets facade.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	DebugLogPresets() ([]state.DebugLogPreset, error)
+	SetDebugLogPreset(state.DebugLogPreset) error
+	RemoveDebugLogPreset(name string) error
+}
+
+// API implements the DebugLogPresets facade. Any user may list the
+// presets; only controller superusers may change them.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade creates a new DebugLogPresets facade.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Auth())
+}
+
+// NewAPI returns a new DebugLogPresets facade using the given backend.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkCanWrite() error {
+	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// List returns all of the debug-log presets stored on the controller.
+func (api *API) List() (params.DebugLogPresets, error) {
+	presets, err := api.backend.DebugLogPresets()
+	if err != nil {
+		return params.DebugLogPresets{}, common.ServerError(err)
+	}
+	result := params.DebugLogPresets{
+		Presets: make([]params.DebugLogPreset, len(presets)),
+	}
+	for i, p := range presets {
+		result.Presets[i] = params.DebugLogPreset{
+			Name:          p.Name,
+			IncludeEntity: p.IncludeEntity,
+			ExcludeEntity: p.ExcludeEntity,
+			IncludeModule: p.IncludeModule,
+			ExcludeModule: p.ExcludeModule,
+			Level:         p.Level,
+			Format:        p.Format,
+		}
+	}
+	return result, nil
+}
+
+// Set creates or replaces the given debug-log presets.
+func (api *API) Set(args params.DebugLogPresets) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Presets)),
+	}
+	for i, p := range args.Presets {
+		err := api.setPreset(p)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) setPreset(p params.DebugLogPreset) error {
+	if p.Level != "" {
+		if level, ok := loggo.ParseLevel(p.Level); !ok || level < loggo.TRACE || level > loggo.ERROR {
+			return errors.NotValidf("level %q", p.Level)
+		}
+	}
+	switch p.Format {
+	case "", "text", "json":
+	default:
+		return errors.NotValidf("format %q", p.Format)
+	}
+	return api.backend.SetDebugLogPreset(state.DebugLogPreset{
+		Name:          p.Name,
+		IncludeEntity: p.IncludeEntity,
+		ExcludeEntity: p.ExcludeEntity,
+		IncludeModule: p.IncludeModule,
+		ExcludeModule: p.ExcludeModule,
+		Level:         p.Level,
+		Format:        p.Format,
+	})
+}
+
+// Remove removes the named debug-log presets.
+func (api *API) Remove(args params.DebugLogPresetNames) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	for i, name := range args.Names {
+		err := api.backend.RemoveDebugLogPreset(name)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package debuglogpresets_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/debuglogpresets"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type debugLogPresetsSuite struct {
+	testing.IsolationSuite
+	backend *mockBackend
+}
+
+var _ = gc.Suite(&debugLogPresetsSuite{})
+
+func (s *debugLogPresetsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		presets: []state.DebugLogPreset{{
+			Name:          "noisy-neutron",
+			IncludeEntity: []string{"unit-neutron-*"},
+			Level:         "WARNING",
+		}},
+	}
+}
+
+func (s *debugLogPresetsSuite) newAPI(c *gc.C, user string) *debuglogpresets.API {
+	api, err := debuglogpresets.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *debugLogPresetsSuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := debuglogpresets.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *debugLogPresetsSuite) TestList(c *gc.C) {
+	result, err := s.newAPI(c, "read").List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.DebugLogPresets{
+		Presets: []params.DebugLogPreset{{
+			Name:          "noisy-neutron",
+			IncludeEntity: []string{"unit-neutron-*"},
+			Level:         "WARNING",
+		}},
+	})
+	s.backend.CheckCallNames(c, "DebugLogPresets")
+}
+
+func (s *debugLogPresetsSuite) TestSet(c *gc.C) {
+	result, err := s.newAPI(c, "superuser-bob").Set(params.DebugLogPresets{
+		Presets: []params.DebugLogPreset{{
+			Name:          "uniter",
+			IncludeModule: []string{"juju.worker.uniter"},
+			Format:        "json",
+		}, {
+			Name:  "bad-level",
+			Level: "LOUD",
+		}, {
+			Name:   "bad-format",
+			Format: "yaml",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `level "LOUD" not valid`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `format "yaml" not valid`)
+	s.backend.CheckCallNames(c, "ControllerTag", "SetDebugLogPreset")
+	s.backend.CheckCall(c, 1, "SetDebugLogPreset", state.DebugLogPreset{
+		Name:          "uniter",
+		IncludeModule: []string{"juju.worker.uniter"},
+		Format:        "json",
+	})
+}
+
+func (s *debugLogPresetsSuite) TestSetPermissionDenied(c *gc.C) {
+	_, err := s.newAPI(c, "write").Set(params.DebugLogPresets{
+		Presets: []params.DebugLogPreset{{Name: "uniter"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag")
+}
+
+func (s *debugLogPresetsSuite) TestRemove(c *gc.C) {
+	result, err := s.newAPI(c, "superuser-bob").Remove(params.DebugLogPresetNames{
+		Names: []string{"noisy-neutron"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "ControllerTag", "RemoveDebugLogPreset")
+	s.backend.CheckCall(c, 1, "RemoveDebugLogPreset", "noisy-neutron")
+}
+
+type mockBackend struct {
+	testing.Stub
+	presets []state.DebugLogPreset
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) DebugLogPresets() ([]state.DebugLogPreset, error) {
+	b.MethodCall(b, "DebugLogPresets")
+	return b.presets, b.NextErr()
+}
+
+func (b *mockBackend) SetDebugLogPreset(preset state.DebugLogPreset) error {
+	b.MethodCall(b, "SetDebugLogPreset", preset)
+	return b.NextErr()
+}
+
+func (b *mockBackend) RemoveDebugLogPreset(name string) error {
+	b.MethodCall(b, "RemoveDebugLogPreset", name)
+	return b.NextErr()
+}
@@ -0,0 +1,96 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package firewallrules provides the API for managing the firewall
+// rules that restrict access to controller-level services.
+package firewallrules
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the state functionality required by the
+// FirewallRules facade.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	FirewallRules() ([]state.FirewallRule, error)
+	SaveFirewallRule(state.FirewallRule) error
+}
+
+// API implements the FirewallRules facade. Any user may list the
+// rules; only controller superusers may change them.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade creates a new FirewallRules facade.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Auth())
+}
+
+// NewAPI returns a new FirewallRules facade using the given backend.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkCanWrite() error {
+	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// SetFirewallRules creates or replaces the given firewall rules.
+func (api *API) SetFirewallRules(args params.FirewallRuleArgs) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := api.backend.SaveFirewallRule(state.FirewallRule{
+			WellKnownService: state.WellKnownServiceType(arg.KnownService),
+			WhitelistCIDRs:   arg.WhitelistCIDRS,
+		})
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// ListFirewallRules returns all of the firewall rules stored on the
+// controller.
+func (api *API) ListFirewallRules() (params.ListFirewallRulesResults, error) {
+	rules, err := api.backend.FirewallRules()
+	if err != nil {
+		return params.ListFirewallRulesResults{}, common.ServerError(err)
+	}
+	result := params.ListFirewallRulesResults{
+		Rules: make([]params.FirewallRule, len(rules)),
+	}
+	for i, rule := range rules {
+		result.Rules[i] = params.FirewallRule{
+			KnownService:   string(rule.WellKnownService),
+			WhitelistCIDRS: rule.WhitelistCIDRs,
+		}
+	}
+	return result, nil
+}
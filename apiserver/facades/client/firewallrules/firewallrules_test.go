@@ -0,0 +1,111 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewallrules_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/firewallrules"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type firewallRulesSuite struct {
+	testing.IsolationSuite
+	backend *mockBackend
+}
+
+var _ = gc.Suite(&firewallRulesSuite{})
+
+func (s *firewallRulesSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		rules: []state.FirewallRule{{
+			WellKnownService: state.SSHRule,
+			WhitelistCIDRs:   []string{"192.168.1.0/24"},
+		}},
+	}
+}
+
+func (s *firewallRulesSuite) newAPI(c *gc.C, user string) *firewallrules.API {
+	api, err := firewallrules.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *firewallRulesSuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := firewallrules.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *firewallRulesSuite) TestListFirewallRules(c *gc.C) {
+	result, err := s.newAPI(c, "read").ListFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ListFirewallRulesResults{
+		Rules: []params.FirewallRule{{
+			KnownService:   "ssh",
+			WhitelistCIDRS: []string{"192.168.1.0/24"},
+		}},
+	})
+	s.backend.CheckCallNames(c, "FirewallRules")
+}
+
+func (s *firewallRulesSuite) TestSetFirewallRules(c *gc.C) {
+	s.backend.SetErrors(nil, errors.NotValidf("well known service type %q", "telnet"))
+	result, err := s.newAPI(c, "superuser-bob").SetFirewallRules(params.FirewallRuleArgs{
+		Args: []params.FirewallRule{{
+			KnownService:   "juju-controller",
+			WhitelistCIDRS: []string{"10.0.0.0/8"},
+		}, {
+			KnownService: "telnet",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 2)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `well known service type "telnet" not valid`)
+	s.backend.CheckCallNames(c, "ControllerTag", "SaveFirewallRule", "SaveFirewallRule")
+	s.backend.CheckCall(c, 1, "SaveFirewallRule", state.FirewallRule{
+		WellKnownService: state.JujuControllerRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+}
+
+func (s *firewallRulesSuite) TestSetFirewallRulesPermissionDenied(c *gc.C) {
+	_, err := s.newAPI(c, "write").SetFirewallRules(params.FirewallRuleArgs{
+		Args: []params.FirewallRule{{KnownService: "ssh"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag")
+}
+
+type mockBackend struct {
+	testing.Stub
+	rules []state.FirewallRule
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) FirewallRules() ([]state.FirewallRule, error) {
+	b.MethodCall(b, "FirewallRules")
+	return b.rules, b.NextErr()
+}
+
+func (b *mockBackend) SaveFirewallRule(rule state.FirewallRule) error {
+	b.MethodCall(b, "SaveFirewallRule", rule)
+	return b.NextErr()
+}
@@ -0,0 +1,200 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package loglevels provides the API for inspecting and changing the
+// log levels of loggo modules at runtime, on the controller and on
+// individual agents.
+package loglevels
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.loglevels")
+
+// Backend defines the state functionality required by the LogLevels
+// facade.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	ModelTag() names.ModelTag
+	FindEntity(names.Tag) (state.Entity, error)
+	AgentLoggingOverride(names.Tag) (string, error)
+	SetAgentLoggingOverride(names.Tag, string) error
+}
+
+// Loggers provides access to the log levels of the loggo modules in
+// the API server's process.
+type Loggers interface {
+	LoggerInfo() string
+	ConfigureLoggers(string) error
+}
+
+type defaultLoggers struct{}
+
+// LoggerInfo is part of the Loggers interface.
+func (defaultLoggers) LoggerInfo() string {
+	return loggo.LoggerInfo()
+}
+
+// ConfigureLoggers is part of the Loggers interface.
+func (defaultLoggers) ConfigureLoggers(config string) error {
+	return loggo.ConfigureLoggers(config)
+}
+
+// API implements the LogLevels facade. Controller superusers may read
+// and change the controller's log levels; model administrators may
+// read and change those of the model's agents.
+type API struct {
+	backend    Backend
+	loggers    Loggers
+	authorizer facade.Authorizer
+}
+
+// NewFacade creates a new LogLevels facade.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), defaultLoggers{}, ctx.Auth())
+}
+
+// NewAPI returns a new LogLevels facade using the given backend, and
+// reading and changing the log levels of the given loggers.
+func NewAPI(backend Backend, loggers Loggers, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		loggers:    loggers,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkPermission(access permission.Access, target names.Tag) error {
+	ok, err := api.authorizer.HasPermission(access, target)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+func (api *API) checkControllerAdmin() error {
+	return api.checkPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+}
+
+func (api *API) checkModelAdmin() error {
+	err := api.checkPermission(permission.AdminAccess, api.backend.ModelTag())
+	if err == common.ErrPerm {
+		// Controller superusers may administer any model.
+		return api.checkControllerAdmin()
+	}
+	return err
+}
+
+// ControllerLoggingConfig returns the current log levels of the loggo
+// modules in the API server handling the request.
+func (api *API) ControllerLoggingConfig() (params.StringResult, error) {
+	if err := api.checkControllerAdmin(); err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	return params.StringResult{Result: api.loggers.LoggerInfo()}, nil
+}
+
+// SetControllerLoggingConfig changes the log levels of the loggo
+// modules in the API server handling the request. Modules not named in
+// the configuration keep their current levels.
+//
+// The change is not persisted: it lasts until the controller agent is
+// restarted or next applies the model's logging-config. In an HA
+// controller only the API server handling the request is affected.
+func (api *API) SetControllerLoggingConfig(args params.LoggingConfig) error {
+	if err := api.checkControllerAdmin(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := loggo.ParseConfigString(args.Config); err != nil {
+		return errors.NewNotValid(err, "logging config")
+	}
+	logger.Infof("changing controller logging config to %q", args.Config)
+	return errors.Trace(api.loggers.ConfigureLoggers(args.Config))
+}
+
+// AgentLoggingConfig returns the logging configuration that each of
+// the given agents applies on top of the model's logging-config.
+func (api *API) AgentLoggingConfig(args params.Entities) (params.StringResults, error) {
+	if err := api.checkModelAdmin(); err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		config, err := api.agentLoggingConfig(entity.Tag)
+		results.Results[i].Result = config
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) agentLoggingConfig(tagString string) (string, error) {
+	tag, err := api.agentTag(tagString)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return api.backend.AgentLoggingOverride(tag)
+}
+
+// SetAgentLoggingConfig sets the logging configuration that each of
+// the given agents applies on top of the model's logging-config. The
+// agents apply the change as soon as they are notified of it, without
+// restarting; an empty configuration returns an agent to the model's
+// logging-config.
+func (api *API) SetAgentLoggingConfig(args params.AgentLoggingConfigs) (params.ErrorResults, error) {
+	if err := api.checkModelAdmin(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Configs)),
+	}
+	for i, arg := range args.Configs {
+		err := api.setAgentLoggingConfig(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) setAgentLoggingConfig(arg params.AgentLoggingConfig) error {
+	tag, err := api.agentTag(arg.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := loggo.ParseConfigString(arg.Config); err != nil {
+		return errors.NewNotValid(err, "logging config")
+	}
+	if _, err := api.backend.FindEntity(tag); err != nil {
+		return errors.Trace(err)
+	}
+	return api.backend.SetAgentLoggingOverride(tag, arg.Config)
+}
+
+// agentTag parses the given tag, which must be that of a machine or
+// unit agent.
+func (api *API) agentTag(tagString string) (names.Tag, error) {
+	tag, err := names.ParseTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch tag.(type) {
+	case names.MachineTag, names.UnitTag:
+		return tag, nil
+	}
+	return nil, errors.NotValidf("agent tag %q", tagString)
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package loglevels_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/loglevels"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type logLevelsSuite struct {
+	testing.IsolationSuite
+	backend *mockBackend
+	loggers *mockLoggers
+}
+
+var _ = gc.Suite(&logLevelsSuite{})
+
+func (s *logLevelsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		overrides: map[string]string{
+			"unit-mysql-0": "juju.worker.uniter=DEBUG",
+		},
+	}
+	s.loggers = &mockLoggers{info: "<root>=WARNING"}
+}
+
+func (s *logLevelsSuite) newAPI(c *gc.C, user string) *loglevels.API {
+	api, err := loglevels.NewAPI(s.backend, s.loggers, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func modelAdmin() string {
+	return "admin-" + coretesting.ModelTag.String()
+}
+
+func (s *logLevelsSuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := loglevels.NewAPI(s.backend, s.loggers, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *logLevelsSuite) TestControllerLoggingConfig(c *gc.C) {
+	result, err := s.newAPI(c, "superuser-bob").ControllerLoggingConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringResult{Result: "<root>=WARNING"})
+}
+
+func (s *logLevelsSuite) TestControllerLoggingConfigPermissionDenied(c *gc.C) {
+	_, err := s.newAPI(c, modelAdmin()).ControllerLoggingConfig()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *logLevelsSuite) TestSetControllerLoggingConfig(c *gc.C) {
+	err := s.newAPI(c, "superuser-bob").SetControllerLoggingConfig(params.LoggingConfig{
+		Config: "juju.apiserver=TRACE",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.loggers.CheckCall(c, 0, "ConfigureLoggers", "juju.apiserver=TRACE")
+}
+
+func (s *logLevelsSuite) TestSetControllerLoggingConfigInvalid(c *gc.C) {
+	err := s.newAPI(c, "superuser-bob").SetControllerLoggingConfig(params.LoggingConfig{
+		Config: "juju.apiserver=LOUD",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	s.loggers.CheckNoCalls(c)
+}
+
+func (s *logLevelsSuite) TestSetControllerLoggingConfigPermissionDenied(c *gc.C) {
+	err := s.newAPI(c, "write").SetControllerLoggingConfig(params.LoggingConfig{
+		Config: "juju.apiserver=TRACE",
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.loggers.CheckNoCalls(c)
+}
+
+func (s *logLevelsSuite) TestAgentLoggingConfig(c *gc.C) {
+	results, err := s.newAPI(c, modelAdmin()).AgentLoggingConfig(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "unit-mysql-0"},
+			{Tag: "machine-0"},
+			{Tag: "user-bob"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0], jc.DeepEquals, params.StringResult{Result: "juju.worker.uniter=DEBUG"})
+	c.Assert(results.Results[1], jc.DeepEquals, params.StringResult{})
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `agent tag "user-bob" not valid`)
+}
+
+func (s *logLevelsSuite) TestSetAgentLoggingConfig(c *gc.C) {
+	s.backend.SetErrors(nil, nil, errors.NotFoundf("machine 42"))
+	results, err := s.newAPI(c, "superuser-bob").SetAgentLoggingConfig(params.AgentLoggingConfigs{
+		Configs: []params.AgentLoggingConfig{
+			{Tag: "unit-mysql-0", Config: "unit=TRACE"},
+			{Tag: "machine-42", Config: "juju=DEBUG"},
+			{Tag: "machine-0", Config: "juju=LOUD"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "machine 42 not found")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `logging config: .*`)
+	s.backend.CheckCallNames(c, "ModelTag", "ControllerTag", "FindEntity", "SetAgentLoggingOverride", "FindEntity")
+	s.backend.CheckCall(c, 3, "SetAgentLoggingOverride", names.NewUnitTag("mysql/0"), "unit=TRACE")
+}
+
+func (s *logLevelsSuite) TestSetAgentLoggingConfigPermissionDenied(c *gc.C) {
+	_, err := s.newAPI(c, "read").SetAgentLoggingConfig(params.AgentLoggingConfigs{
+		Configs: []params.AgentLoggingConfig{{Tag: "machine-0", Config: "juju=DEBUG"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type mockBackend struct {
+	testing.Stub
+	overrides map[string]string
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ModelTag() names.ModelTag {
+	b.MethodCall(b, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (b *mockBackend) FindEntity(tag names.Tag) (state.Entity, error) {
+	b.MethodCall(b, "FindEntity", tag)
+	return nil, b.NextErr()
+}
+
+func (b *mockBackend) AgentLoggingOverride(tag names.Tag) (string, error) {
+	b.MethodCall(b, "AgentLoggingOverride", tag)
+	return b.overrides[tag.String()], b.NextErr()
+}
+
+func (b *mockBackend) SetAgentLoggingOverride(tag names.Tag, config string) error {
+	b.MethodCall(b, "SetAgentLoggingOverride", tag, config)
+	return b.NextErr()
+}
+
+type mockLoggers struct {
+	testing.Stub
+	info string
+}
+
+func (l *mockLoggers) LoggerInfo() string {
+	l.MethodCall(l, "LoggerInfo")
+	return l.info
+}
+
+func (l *mockLoggers) ConfigureLoggers(config string) error {
+	l.MethodCall(l, "ConfigureLoggers", config)
+	return l.NextErr()
+}
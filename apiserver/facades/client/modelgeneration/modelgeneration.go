@@ -0,0 +1,217 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelgeneration provides the API server facade for managing
+// the branches of a model, against which charm config changes are
+// staged for a subset of units before being committed to all of them.
+package modelgeneration
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
+)
+
+// Backend defines the state functionality required by the
+// ModelGeneration facade.
+type Backend interface {
+	ModelTag() names.ModelTag
+	AddBranch(name, createdBy string) error
+	Branch(name string) (Generation, error)
+	Branches() ([]Generation, error)
+	CharmConfig(appName string) (*charm.Config, error)
+}
+
+// Generation defines the branch functionality required by the
+// ModelGeneration facade.
+type Generation interface {
+	Name() string
+	CreatedBy() string
+	AssignedUnits() map[string][]string
+	Config() map[string]charm.Settings
+	AssignUnit(unitName string) error
+	AssignAllUnits(appName string) error
+	UpdateCharmConfig(appName string, changes charm.Settings) error
+	Commit(userName string) error
+	Abort(userName string) error
+}
+
+// BlockChecker checks whether changes to the model are blocked.
+type BlockChecker interface {
+	ChangeAllowed() error
+}
+
+// API implements the ModelGeneration facade. Model readers may list
+// the branches; model writers may change them.
+type API struct {
+	backend    Backend
+	check      BlockChecker
+	authorizer facade.Authorizer
+}
+
+// NewFacade creates a new ModelGeneration facade.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, common.NewBlockChecker(ctx.State()), ctx.Auth())
+}
+
+// NewAPI returns a new ModelGeneration facade using the given backend.
+func NewAPI(backend Backend, check BlockChecker, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		check:      check,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *API) checkPermission(access permission.Access) error {
+	ok, err := api.authorizer.HasPermission(access, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+func (api *API) checkCanWrite() error {
+	if err := api.checkPermission(permission.WriteAccess); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(api.check.ChangeAllowed())
+}
+
+func (api *API) userName() string {
+	return api.authorizer.GetAuthTag().Id()
+}
+
+// AddBranch creates a new, empty branch of the model.
+func (api *API) AddBranch(arg params.BranchArg) error {
+	if err := api.checkCanWrite(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(api.backend.AddBranch(arg.BranchName, api.userName()))
+}
+
+// TrackBranch makes the given units, or all units of the given
+// applications, track the branch. A unit may track only one branch at
+// a time.
+func (api *API) TrackBranch(arg params.BranchTrackArg) (params.ErrorResults, error) {
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	branch, err := api.backend.Branch(arg.BranchName)
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(arg.Entities)),
+	}
+	for i, entity := range arg.Entities {
+		results.Results[i].Error = common.ServerError(trackBranch(branch, entity.Tag))
+	}
+	return results, nil
+}
+
+func trackBranch(branch Generation, tagString string) error {
+	tag, err := names.ParseTag(tagString)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch tag := tag.(type) {
+	case names.UnitTag:
+		return branch.AssignUnit(tag.Id())
+	case names.ApplicationTag:
+		return branch.AssignAllUnits(tag.Id())
+	}
+	return errors.NotValidf("tracking entity %q", tagString)
+}
+
+// SetBranchConfig stages changes to an application's charm config on
+// the branch, in addition to any already staged.
+func (api *API) SetBranchConfig(arg params.BranchConfigArg) error {
+	if err := api.checkCanWrite(); err != nil {
+		return errors.Trace(err)
+	}
+	branch, err := api.backend.Branch(arg.BranchName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	config, err := api.backend.CharmConfig(arg.ApplicationName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	changes, err := config.ParseSettingsStrings(arg.Options)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range arg.Unset {
+		if _, ok := config.Options[name]; !ok {
+			return errors.NotValidf("unknown option %q", name)
+		}
+		changes[name] = nil
+	}
+	return errors.Trace(branch.UpdateCharmConfig(arg.ApplicationName, changes))
+}
+
+// CommitBranch applies the config changes staged on the branch to all
+// units, and completes the branch.
+func (api *API) CommitBranch(arg params.BranchArg) error {
+	if err := api.checkCanWrite(); err != nil {
+		return errors.Trace(err)
+	}
+	branch, err := api.backend.Branch(arg.BranchName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(branch.Commit(api.userName()))
+}
+
+// AbortBranch completes the branch without applying the config changes
+// staged on it.
+func (api *API) AbortBranch(arg params.BranchArg) error {
+	if err := api.checkCanWrite(); err != nil {
+		return errors.Trace(err)
+	}
+	branch, err := api.backend.Branch(arg.BranchName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(branch.Abort(api.userName()))
+}
+
+// Branches returns the branches of the model that are in progress,
+// ordered by name.
+func (api *API) Branches() (params.BranchResults, error) {
+	if err := api.checkPermission(permission.ReadAccess); err != nil {
+		return params.BranchResults{}, errors.Trace(err)
+	}
+	branches, err := api.backend.Branches()
+	if err != nil {
+		return params.BranchResults{}, errors.Trace(err)
+	}
+	results := params.BranchResults{
+		Branches: make([]params.Branch, len(branches)),
+	}
+	for i, branch := range branches {
+		config := make(map[string]map[string]interface{})
+		for appName, settings := range branch.Config() {
+			config[appName] = settings
+		}
+		results.Branches[i] = params.Branch{
+			BranchName:    branch.Name(),
+			CreatedBy:     branch.CreatedBy(),
+			AssignedUnits: branch.AssignedUnits(),
+			Config:        config,
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelgeneration_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/modelgeneration"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type modelGenerationSuite struct {
+	testing.IsolationSuite
+	backend *mockBackend
+	branch  *mockGeneration
+	check   *mockBlockChecker
+}
+
+var _ = gc.Suite(&modelGenerationSuite{})
+
+func (s *modelGenerationSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.branch = &mockGeneration{
+		name:      "canary",
+		createdBy: "bob",
+		units:     map[string][]string{"wordpress": {"wordpress/0"}},
+		config: map[string]charm.Settings{
+			"wordpress": {"blog-title": "canary"},
+		},
+	}
+	s.backend = &mockBackend{branch: s.branch}
+	s.check = &mockBlockChecker{}
+}
+
+func (s *modelGenerationSuite) newAPI(c *gc.C, user string) *modelgeneration.API {
+	api, err := modelgeneration.NewAPI(s.backend, s.check, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *modelGenerationSuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := modelgeneration.NewAPI(s.backend, s.check, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *modelGenerationSuite) TestAddBranch(c *gc.C) {
+	err := s.newAPI(c, "write").AddBranch(params.BranchArg{BranchName: "beta"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCall(c, 1, "AddBranch", "beta", "write")
+}
+
+func (s *modelGenerationSuite) TestAddBranchPermissionDenied(c *gc.C) {
+	err := s.newAPI(c, "read").AddBranch(params.BranchArg{BranchName: "beta"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+func (s *modelGenerationSuite) TestAddBranchBlocked(c *gc.C) {
+	s.check.SetErrors(errors.New("change blocked"))
+	err := s.newAPI(c, "write").AddBranch(params.BranchArg{BranchName: "beta"})
+	c.Assert(err, gc.ErrorMatches, "change blocked")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+func (s *modelGenerationSuite) TestTrackBranch(c *gc.C) {
+	s.branch.SetErrors(nil, errors.New("unit is tracking branch \"beta\""))
+	results, err := s.newAPI(c, "write").TrackBranch(params.BranchTrackArg{
+		BranchName: "canary",
+		Entities: []params.Entity{
+			{Tag: "unit-wordpress-1"},
+			{Tag: "unit-wordpress-2"},
+			{Tag: "application-mysql"},
+			{Tag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `unit is tracking branch "beta"`)
+	c.Assert(results.Results[2].Error, gc.IsNil)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `tracking entity "machine-0" not valid`)
+	s.backend.CheckCall(c, 1, "Branch", "canary")
+	s.branch.CheckCalls(c, []testing.StubCall{
+		{"AssignUnit", []interface{}{"wordpress/1"}},
+		{"AssignUnit", []interface{}{"wordpress/2"}},
+		{"AssignAllUnits", []interface{}{"mysql"}},
+	})
+}
+
+func (s *modelGenerationSuite) TestSetBranchConfig(c *gc.C) {
+	err := s.newAPI(c, "write").SetBranchConfig(params.BranchConfigArg{
+		BranchName:      "canary",
+		ApplicationName: "wordpress",
+		Options:         map[string]string{"blog-title": "new"},
+		Unset:           []string{"skill-level"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCall(c, 2, "CharmConfig", "wordpress")
+	s.branch.CheckCall(c, 0, "UpdateCharmConfig", "wordpress", charm.Settings{
+		"blog-title":  "new",
+		"skill-level": nil,
+	})
+}
+
+func (s *modelGenerationSuite) TestSetBranchConfigUnknownOption(c *gc.C) {
+	err := s.newAPI(c, "write").SetBranchConfig(params.BranchConfigArg{
+		BranchName:      "canary",
+		ApplicationName: "wordpress",
+		Unset:           []string{"colour"},
+	})
+	c.Assert(err, gc.ErrorMatches, `unknown option "colour" not valid`)
+	s.branch.CheckNoCalls(c)
+}
+
+func (s *modelGenerationSuite) TestCommitBranch(c *gc.C) {
+	err := s.newAPI(c, "write").CommitBranch(params.BranchArg{BranchName: "canary"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.branch.CheckCall(c, 0, "Commit", "write")
+}
+
+func (s *modelGenerationSuite) TestAbortBranch(c *gc.C) {
+	err := s.newAPI(c, "write").AbortBranch(params.BranchArg{BranchName: "canary"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.branch.CheckCall(c, 0, "Abort", "write")
+}
+
+func (s *modelGenerationSuite) TestAbortBranchNotFound(c *gc.C) {
+	s.backend.SetErrors(errors.NotFoundf("branch %q", "beta"))
+	err := s.newAPI(c, "write").AbortBranch(params.BranchArg{BranchName: "beta"})
+	c.Assert(err, gc.ErrorMatches, `branch "beta" not found`)
+	s.branch.CheckNoCalls(c)
+}
+
+func (s *modelGenerationSuite) TestBranches(c *gc.C) {
+	results, err := s.newAPI(c, "read").Branches()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.BranchResults{
+		Branches: []params.Branch{{
+			BranchName:    "canary",
+			CreatedBy:     "bob",
+			AssignedUnits: map[string][]string{"wordpress": {"wordpress/0"}},
+			Config: map[string]map[string]interface{}{
+				"wordpress": {"blog-title": "canary"},
+			},
+		}},
+	})
+}
+
+func (s *modelGenerationSuite) TestBranchesPermissionDenied(c *gc.C) {
+	_, err := s.newAPI(c, "nobody").Branches()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type mockBackend struct {
+	testing.Stub
+	branch *mockGeneration
+}
+
+func (b *mockBackend) ModelTag() names.ModelTag {
+	b.MethodCall(b, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (b *mockBackend) AddBranch(name, createdBy string) error {
+	b.MethodCall(b, "AddBranch", name, createdBy)
+	return b.NextErr()
+}
+
+func (b *mockBackend) Branch(name string) (modelgeneration.Generation, error) {
+	b.MethodCall(b, "Branch", name)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.branch, nil
+}
+
+func (b *mockBackend) Branches() ([]modelgeneration.Generation, error) {
+	b.MethodCall(b, "Branches")
+	return []modelgeneration.Generation{b.branch}, b.NextErr()
+}
+
+const wordpressConfig = `
+options:
+  blog-title:
+    type: string
+    default: My Title
+  skill-level:
+    type: int
+`
+
+func (b *mockBackend) CharmConfig(appName string) (*charm.Config, error) {
+	b.MethodCall(b, "CharmConfig", appName)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return charm.ReadConfig(strings.NewReader(wordpressConfig))
+}
+
+type mockGeneration struct {
+	testing.Stub
+	name      string
+	createdBy string
+	units     map[string][]string
+	config    map[string]charm.Settings
+}
+
+func (g *mockGeneration) Name() string {
+	return g.name
+}
+
+func (g *mockGeneration) CreatedBy() string {
+	return g.createdBy
+}
+
+func (g *mockGeneration) AssignedUnits() map[string][]string {
+	return g.units
+}
+
+func (g *mockGeneration) Config() map[string]charm.Settings {
+	return g.config
+}
+
+func (g *mockGeneration) AssignUnit(unitName string) error {
+	g.MethodCall(g, "AssignUnit", unitName)
+	return g.NextErr()
+}
+
+func (g *mockGeneration) AssignAllUnits(appName string) error {
+	g.MethodCall(g, "AssignAllUnits", appName)
+	return g.NextErr()
+}
+
+func (g *mockGeneration) UpdateCharmConfig(appName string, changes charm.Settings) error {
+	g.MethodCall(g, "UpdateCharmConfig", appName, changes)
+	return g.NextErr()
+}
+
+func (g *mockGeneration) Commit(userName string) error {
+	g.MethodCall(g, "Commit", userName)
+	return g.NextErr()
+}
+
+func (g *mockGeneration) Abort(userName string) error {
+	g.MethodCall(g, "Abort", userName)
+	return g.NextErr()
+}
+
+type mockBlockChecker struct {
+	testing.Stub
+}
+
+func (m *mockBlockChecker) ChangeAllowed() error {
+	m.MethodCall(m, "ChangeAllowed")
+	return m.NextErr()
+}
@@ -0,0 +1,50 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelgeneration
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/state"
+)
+
+type stateShim struct {
+	*state.State
+}
+
+// Branch is part of the Backend interface.
+func (s stateShim) Branch(name string) (Generation, error) {
+	branch, err := s.State.Branch(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return branch, nil
+}
+
+// Branches is part of the Backend interface.
+func (s stateShim) Branches() ([]Generation, error) {
+	branches, err := s.State.Branches()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Generation, len(branches))
+	for i, branch := range branches {
+		result[i] = branch
+	}
+	return result, nil
+}
+
+// CharmConfig is part of the Backend interface.
+func (s stateShim) CharmConfig(appName string) (*charm.Config, error) {
+	app, err := s.Application(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ch, _, err := app.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ch.Config(), nil
+}
@@ -0,0 +1,260 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelhistory provides the API server facade for the history
+// of significant events in a model, merged from the model's audit
+// records and status history.
+package modelhistory
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+// The kinds of event reported in a model's history.
+const (
+	KindDeploy      = "deploy"
+	KindConfig      = "config"
+	KindUpgrade     = "upgrade"
+	KindHookFailure = "hook-failure"
+	KindMigration   = "migration"
+)
+
+var allKinds = set.NewStrings(
+	KindDeploy,
+	KindConfig,
+	KindUpgrade,
+	KindHookFailure,
+	KindMigration,
+)
+
+// auditKinds maps the API calls recorded in the audit log that are
+// significant enough to appear in the history to their kinds.
+var auditKinds = map[string]string{
+	"Application.Deploy":                KindDeploy,
+	"Application.AddUnits":              KindDeploy,
+	"Application.Set":                   KindConfig,
+	"Application.Unset":                 KindConfig,
+	"Application.SetApplicationsConfig": KindConfig,
+	"ModelConfig.ModelSet":              KindConfig,
+	"ModelConfig.ModelUnset":            KindConfig,
+	"Application.SetCharm":              KindUpgrade,
+	"Client.SetModelAgentVersion":       KindUpgrade,
+}
+
+// migrationPrefix starts the messages of the model statuses set
+// while the model is being migrated.
+const migrationPrefix = "migrating: "
+
+// Backend defines the state functionality required by the
+// ModelHistory facade.
+type Backend interface {
+	ModelTag() names.ModelTag
+	ControllerConfig() (controller.Config, error)
+	AuditEntries(since time.Time, operationPattern string, limit int) ([]audit.AuditEntry, error)
+	ModelStatusHistory(filter status.StatusHistoryFilter) ([]status.StatusInfo, error)
+	UnitAgentStatusHistory(value status.Status, since time.Time, limit int) ([]state.UnitStatusInfo, error)
+}
+
+// API implements the ModelHistory facade.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade creates a new ModelHistory facade.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth())
+}
+
+// NewAPI returns a new ModelHistory facade using the given backend.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+// History returns the significant events in the model's history since
+// the given time, oldest first.
+func (api *API) History(args params.ModelHistoryArgs) (params.ModelEvents, error) {
+	modelTag := api.backend.ModelTag()
+	ok, err := api.authorizer.HasPermission(permission.ReadAccess, modelTag)
+	if err != nil {
+		return params.ModelEvents{}, errors.Trace(err)
+	}
+	if !ok {
+		return params.ModelEvents{}, common.ErrPerm
+	}
+	if args.Since.IsZero() {
+		return params.ModelEvents{}, errors.NotValidf("zero since time")
+	}
+	kinds := allKinds
+	if len(args.Kinds) > 0 {
+		kinds = set.NewStrings(args.Kinds...)
+		if unknown := kinds.Difference(allKinds); !unknown.IsEmpty() {
+			return params.ModelEvents{}, errors.NotValidf("event kinds %s", strings.Join(unknown.SortedValues(), ", "))
+		}
+	}
+
+	var result params.ModelEvents
+	var events []params.ModelEvent
+	if kinds.Contains(KindDeploy) || kinds.Contains(KindConfig) || kinds.Contains(KindUpgrade) {
+		config, err := api.backend.ControllerConfig()
+		if err != nil {
+			return params.ModelEvents{}, errors.Trace(err)
+		}
+		if config.AuditingEnabled() {
+			auditEvents, err := api.auditEvents(modelTag, args.Since, args.Limit, kinds)
+			if err != nil {
+				return params.ModelEvents{}, errors.Trace(err)
+			}
+			events = append(events, auditEvents...)
+		} else {
+			result.AuditingDisabled = true
+		}
+	}
+	filter := status.StatusHistoryFilter{FromDate: &args.Since}
+	if kinds.Contains(KindMigration) {
+		migrationEvents, err := api.migrationEvents(modelTag, filter)
+		if err != nil {
+			return params.ModelEvents{}, errors.Trace(err)
+		}
+		events = append(events, migrationEvents...)
+	}
+	if kinds.Contains(KindHookFailure) {
+		hookEvents, err := api.hookFailureEvents(args.Since, args.Limit)
+		if err != nil {
+			return params.ModelEvents{}, errors.Trace(err)
+		}
+		events = append(events, hookEvents...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	if args.Limit > 0 && len(events) > args.Limit {
+		events = events[len(events)-args.Limit:]
+	}
+	result.Events = events
+	return result, nil
+}
+
+func (api *API) auditEvents(modelTag names.ModelTag, since time.Time, limit int, kinds set.Strings) ([]params.ModelEvent, error) {
+	entries, err := api.backend.AuditEntries(since, auditOperationPattern(kinds), limit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var events []params.ModelEvent
+	for _, entry := range entries {
+		call := auditCall(entry.Operation)
+		kind, ok := auditKinds[call]
+		if !ok || !kinds.Contains(kind) {
+			continue
+		}
+		events = append(events, params.ModelEvent{
+			Timestamp: entry.Timestamp,
+			Kind:      kind,
+			Entity:    modelTag.String(),
+			Origin:    entry.OriginName,
+			Summary:   call,
+		})
+	}
+	return events, nil
+}
+
+// auditCall returns the facade and method, as "Facade.Method", of the
+// API call recorded in an audit entry's operation, which has the form
+// "Facade:vN - Method".
+func auditCall(operation string) string {
+	parts := strings.SplitN(operation, " - ", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	facadeName := strings.SplitN(parts[0], ":", 2)[0]
+	return facadeName + "." + parts[1]
+}
+
+// auditOperationPattern returns a regular expression matching the
+// operations of the audit entries recording the API calls of the
+// given kinds.
+func auditOperationPattern(kinds set.Strings) string {
+	methods := make(map[string][]string)
+	for call, kind := range auditKinds {
+		if !kinds.Contains(kind) {
+			continue
+		}
+		parts := strings.SplitN(call, ".", 2)
+		methods[parts[0]] = append(methods[parts[0]], regexp.QuoteMeta(parts[1]))
+	}
+	facadeNames := make([]string, 0, len(methods))
+	for facadeName := range methods {
+		facadeNames = append(facadeNames, facadeName)
+	}
+	sort.Strings(facadeNames)
+	alternatives := make([]string, len(facadeNames))
+	for i, facadeName := range facadeNames {
+		sort.Strings(methods[facadeName])
+		alternatives[i] = fmt.Sprintf("%s:v[0-9]+ - (%s)",
+			regexp.QuoteMeta(facadeName), strings.Join(methods[facadeName], "|"))
+	}
+	return "^(" + strings.Join(alternatives, "|") + ")$"
+}
+
+func (api *API) migrationEvents(modelTag names.ModelTag, filter status.StatusHistoryFilter) ([]params.ModelEvent, error) {
+	history, err := api.backend.ModelStatusHistory(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var events []params.ModelEvent
+	for _, info := range history {
+		if !strings.HasPrefix(info.Message, migrationPrefix) {
+			continue
+		}
+		events = append(events, statusEvent(KindMigration, modelTag, info))
+	}
+	return events, nil
+}
+
+func (api *API) hookFailureEvents(since time.Time, limit int) ([]params.ModelEvent, error) {
+	history, err := api.backend.UnitAgentStatusHistory(status.Error, since, limit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	events := make([]params.ModelEvent, len(history))
+	for i, info := range history {
+		events[i] = statusEvent(KindHookFailure, names.NewUnitTag(info.UnitName), info.StatusInfo)
+	}
+	return events, nil
+}
+
+func statusEvent(kind string, entity names.Tag, info status.StatusInfo) params.ModelEvent {
+	event := params.ModelEvent{
+		Kind:    kind,
+		Entity:  entity.String(),
+		Summary: info.Message,
+	}
+	if info.Since != nil {
+		event.Timestamp = *info.Since
+	}
+	return event
+}
@@ -0,0 +1,254 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelhistory_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/facades/client/modelhistory"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var since = time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func at(minutes int) time.Time {
+	return since.Add(time.Duration(minutes) * time.Minute)
+}
+
+func atPtr(minutes int) *time.Time {
+	t := at(minutes)
+	return &t
+}
+
+type modelHistorySuite struct {
+	testing.IsolationSuite
+	backend *mockBackend
+}
+
+var _ = gc.Suite(&modelHistorySuite{})
+
+func (s *modelHistorySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		controllerConfig: controller.Config{
+			controller.AuditingEnabled: true,
+		},
+		audit: []audit.AuditEntry{{
+			Timestamp:  at(1),
+			OriginName: "user-bob",
+			Operation:  "Application:v6 - Deploy",
+		}, {
+			Timestamp:  at(2),
+			OriginName: "user-bob",
+			Operation:  "Client:v1 - FullStatus",
+		}, {
+			Timestamp:  at(4),
+			OriginName: "user-mary",
+			Operation:  "ModelConfig:v1 - ModelSet",
+		}, {
+			Timestamp:  at(7),
+			OriginName: "user-mary",
+			Operation:  "Application:v6 - SetCharm",
+		}},
+		model: []status.StatusInfo{{
+			Status:  status.Available,
+			Message: "",
+			Since:   atPtr(0),
+		}, {
+			Status:  status.Busy,
+			Message: "migrating: importing",
+			Since:   atPtr(8),
+		}},
+		unitErrors: []state.UnitStatusInfo{{
+			UnitName: "mysql/0",
+			StatusInfo: status.StatusInfo{
+				Status:  status.Error,
+				Message: `hook failed: "install"`,
+				Since:   atPtr(5),
+			},
+		}},
+	}
+}
+
+func (s *modelHistorySuite) newAPI(c *gc.C, user string) *modelhistory.API {
+	api, err := modelhistory.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *modelHistorySuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := modelhistory.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *modelHistorySuite) TestHistory(c *gc.C) {
+	result, err := s.newAPI(c, "read").History(params.ModelHistoryArgs{Since: since})
+	c.Assert(err, jc.ErrorIsNil)
+	modelTag := coretesting.ModelTag.String()
+	c.Assert(result, jc.DeepEquals, params.ModelEvents{
+		Events: []params.ModelEvent{{
+			Timestamp: at(1),
+			Kind:      modelhistory.KindDeploy,
+			Entity:    modelTag,
+			Origin:    "user-bob",
+			Summary:   "Application.Deploy",
+		}, {
+			Timestamp: at(4),
+			Kind:      modelhistory.KindConfig,
+			Entity:    modelTag,
+			Origin:    "user-mary",
+			Summary:   "ModelConfig.ModelSet",
+		}, {
+			Timestamp: at(5),
+			Kind:      modelhistory.KindHookFailure,
+			Entity:    "unit-mysql-0",
+			Summary:   `hook failed: "install"`,
+		}, {
+			Timestamp: at(7),
+			Kind:      modelhistory.KindUpgrade,
+			Entity:    modelTag,
+			Origin:    "user-mary",
+			Summary:   "Application.SetCharm",
+		}, {
+			Timestamp: at(8),
+			Kind:      modelhistory.KindMigration,
+			Entity:    modelTag,
+			Summary:   "migrating: importing",
+		}},
+	})
+
+	s.backend.CheckCallNames(c, "ModelTag", "ControllerConfig", "AuditEntries", "ModelStatusHistory", "UnitAgentStatusHistory")
+	s.backend.CheckCall(c, 2, "AuditEntries", since,
+		`^(Application:v[0-9]+ - (AddUnits|Deploy|Set|SetApplicationsConfig|SetCharm|Unset)`+
+			`|Client:v[0-9]+ - (SetModelAgentVersion)`+
+			`|ModelConfig:v[0-9]+ - (ModelSet|ModelUnset))$`, 0)
+	s.backend.CheckCall(c, 3, "ModelStatusHistory", status.StatusHistoryFilter{FromDate: &since})
+	s.backend.CheckCall(c, 4, "UnitAgentStatusHistory", status.Error, since, 0)
+}
+
+func (s *modelHistorySuite) TestHistoryLimit(c *gc.C) {
+	result, err := s.newAPI(c, "read").History(params.ModelHistoryArgs{
+		Since: since,
+		Limit: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Events, gc.HasLen, 2)
+	c.Check(result.Events[0].Kind, gc.Equals, modelhistory.KindUpgrade)
+	c.Check(result.Events[1].Kind, gc.Equals, modelhistory.KindMigration)
+	s.backend.CheckCall(c, 2, "AuditEntries", since,
+		`^(Application:v[0-9]+ - (AddUnits|Deploy|Set|SetApplicationsConfig|SetCharm|Unset)`+
+			`|Client:v[0-9]+ - (SetModelAgentVersion)`+
+			`|ModelConfig:v[0-9]+ - (ModelSet|ModelUnset))$`, 2)
+	s.backend.CheckCall(c, 4, "UnitAgentStatusHistory", status.Error, since, 2)
+}
+
+func (s *modelHistorySuite) TestHistoryKinds(c *gc.C) {
+	result, err := s.newAPI(c, "read").History(params.ModelHistoryArgs{
+		Since: since,
+		Kinds: []string{modelhistory.KindHookFailure},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Events, gc.HasLen, 1)
+	c.Check(result.Events[0].Entity, gc.Equals, "unit-mysql-0")
+	s.backend.CheckCallNames(c, "ModelTag", "UnitAgentStatusHistory")
+}
+
+func (s *modelHistorySuite) TestHistoryAuditKinds(c *gc.C) {
+	result, err := s.newAPI(c, "read").History(params.ModelHistoryArgs{
+		Since: since,
+		Kinds: []string{modelhistory.KindConfig},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Events, gc.HasLen, 1)
+	c.Check(result.Events[0].Summary, gc.Equals, "ModelConfig.ModelSet")
+	s.backend.CheckCallNames(c, "ModelTag", "ControllerConfig", "AuditEntries")
+	s.backend.CheckCall(c, 2, "AuditEntries", since,
+		`^(Application:v[0-9]+ - (Set|SetApplicationsConfig|Unset)`+
+			`|ModelConfig:v[0-9]+ - (ModelSet|ModelUnset))$`, 0)
+}
+
+func (s *modelHistorySuite) TestHistoryAuditingDisabled(c *gc.C) {
+	s.backend.controllerConfig = controller.Config{}
+	result, err := s.newAPI(c, "read").History(params.ModelHistoryArgs{Since: since})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.AuditingDisabled, jc.IsTrue)
+	c.Assert(result.Events, gc.HasLen, 2)
+	c.Check(result.Events[0].Kind, gc.Equals, modelhistory.KindHookFailure)
+	c.Check(result.Events[1].Kind, gc.Equals, modelhistory.KindMigration)
+	s.backend.CheckCallNames(c, "ModelTag", "ControllerConfig", "ModelStatusHistory", "UnitAgentStatusHistory")
+}
+
+func (s *modelHistorySuite) TestHistoryUnknownKind(c *gc.C) {
+	_, err := s.newAPI(c, "read").History(params.ModelHistoryArgs{
+		Since: since,
+		Kinds: []string{"deploy", "reboot"},
+	})
+	c.Assert(err, gc.ErrorMatches, "event kinds reboot not valid")
+}
+
+func (s *modelHistorySuite) TestHistoryRequiresSince(c *gc.C) {
+	_, err := s.newAPI(c, "read").History(params.ModelHistoryArgs{})
+	c.Assert(err, gc.ErrorMatches, "zero since time not valid")
+}
+
+func (s *modelHistorySuite) TestHistoryPermissionDenied(c *gc.C) {
+	_, err := s.newAPI(c, "nobody").History(params.ModelHistoryArgs{Since: since})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ModelTag")
+}
+
+func (s *modelHistorySuite) TestHistoryBackendError(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom"))
+	_, err := s.newAPI(c, "read").History(params.ModelHistoryArgs{Since: since})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockBackend struct {
+	testing.Stub
+	controllerConfig controller.Config
+	audit            []audit.AuditEntry
+	model            []status.StatusInfo
+	unitErrors       []state.UnitStatusInfo
+}
+
+func (b *mockBackend) ModelTag() names.ModelTag {
+	b.MethodCall(b, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (b *mockBackend) ControllerConfig() (controller.Config, error) {
+	b.MethodCall(b, "ControllerConfig")
+	return b.controllerConfig, b.NextErr()
+}
+
+func (b *mockBackend) AuditEntries(since time.Time, operationPattern string, limit int) ([]audit.AuditEntry, error) {
+	b.MethodCall(b, "AuditEntries", since, operationPattern, limit)
+	return b.audit, b.NextErr()
+}
+
+func (b *mockBackend) ModelStatusHistory(filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	b.MethodCall(b, "ModelStatusHistory", filter)
+	return b.model, b.NextErr()
+}
+
+func (b *mockBackend) UnitAgentStatusHistory(value status.Status, since time.Time, limit int) ([]state.UnitStatusInfo, error) {
+	b.MethodCall(b, "UnitAgentStatusHistory", value, since, limit)
+	return b.unitErrors, b.NextErr()
+}
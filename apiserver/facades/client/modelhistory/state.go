@@ -0,0 +1,24 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelhistory
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+)
+
+type stateShim struct {
+	*state.State
+}
+
+// ModelStatusHistory is part of the Backend interface.
+func (s stateShim) ModelStatusHistory(filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	model, err := s.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return model.StatusHistory(filter)
+}
@@ -0,0 +1,333 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package operations provides the API server facade for operations:
+// groups of tasks, such as the commands enqueued by a single juju run,
+// that may be listed, inspected and cancelled together.
+package operations
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/actions"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+// API implements the Operations facade.
+type API struct {
+	state      *state.State
+	authorizer facade.Authorizer
+	check      *common.BlockChecker
+}
+
+// NewAPI returns a new Operations facade.
+func NewAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		state:      st,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+	}, nil
+}
+
+func (api *API) checkAccess(access permission.Access) error {
+	ok, err := api.authorizer.HasPermission(access, api.state.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// Run enqueues an operation that runs the given commands on the
+// machines, units and units of the applications specified.
+func (api *API) Run(run params.RunParams) (params.OperationResult, error) {
+	if err := api.checkAccess(permission.AdminAccess); err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	receivers, err := unitTags(api.state, run.Units, run.Applications)
+	if err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	for _, id := range run.Machines {
+		if !names.IsValidMachine(id) {
+			return params.OperationResult{}, errors.Errorf("invalid machine id %q", id)
+		}
+		receivers = append(receivers, names.NewMachineTag(id))
+	}
+	return api.enqueueRun(receivers, run.Commands, run.Timeout)
+}
+
+// RunOnAllMachines enqueues an operation that runs the given commands
+// on all of the model's machines.
+func (api *API) RunOnAllMachines(run params.RunParams) (params.OperationResult, error) {
+	if err := api.checkAccess(permission.AdminAccess); err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	machines, err := api.state.AllMachines()
+	if err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	receivers := make([]names.Tag, len(machines))
+	for i, machine := range machines {
+		receivers[i] = machine.Tag()
+	}
+	return api.enqueueRun(receivers, run.Commands, run.Timeout)
+}
+
+// unitTags returns the tags of the given units and of the units of the
+// given applications, sorted by name.
+func unitTags(st *state.State, units, applications []string) ([]names.Tag, error) {
+	unitNames := set.NewStrings(units...)
+	for _, name := range applications {
+		app, err := st.Application(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		appUnits, err := app.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, unit := range appUnits {
+			unitNames.Add(unit.Name())
+		}
+	}
+	var tags []names.Tag
+	for _, name := range unitNames.SortedValues() {
+		if !names.IsValidUnit(name) {
+			return nil, errors.Errorf("invalid unit name %q", name)
+		}
+		tags = append(tags, names.NewUnitTag(name))
+	}
+	return tags, nil
+}
+
+// enqueueRun adds an operation with a juju-run task for each of the
+// receivers. Errors adding individual tasks are reported in the task
+// results.
+func (api *API) enqueueRun(receivers []names.Tag, commands string, timeout time.Duration) (params.OperationResult, error) {
+	id, err := api.state.EnqueueOperation(fmt.Sprintf("run %s", commands))
+	if err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	payload := map[string]interface{}{
+		"command": commands,
+		"timeout": timeout.Nanoseconds(),
+	}
+	tagToActionReceiver := common.TagToActionReceiverFn(api.state.FindEntity)
+	tasks := make([]state.Action, 0, len(receivers))
+	result := params.OperationResult{
+		OperationID: id,
+		Tasks:       make([]params.ActionResult, len(receivers)),
+	}
+	for i, tag := range receivers {
+		taskResult := &result.Tasks[i]
+		receiver, err := tagToActionReceiver(tag.String())
+		if err != nil {
+			taskResult.Error = common.ServerError(err)
+			continue
+		}
+		task, err := receiver.AddTask(id, actions.JujuRunActionName, payload)
+		if err != nil {
+			taskResult.Error = common.ServerError(err)
+			continue
+		}
+		tasks = append(tasks, task)
+		result.Tasks[i] = common.MakeActionResult(receiver.Tag(), task)
+	}
+	op, err := api.state.Operation(id)
+	if err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	result.Summary = op.Summary()
+	result.Enqueued = op.Enqueued()
+	result.Status = string(state.OperationStatus(tasks))
+	return result, nil
+}
+
+// ListOperations returns the operations with tasks that match the
+// query, along with the matching tasks.
+func (api *API) ListOperations(args params.OperationQueryArgs) (params.OperationResults, error) {
+	if err := api.checkAccess(permission.ReadAccess); err != nil {
+		return params.OperationResults{}, errors.Trace(err)
+	}
+	query := state.OperationQuery{
+		ActionNames: args.ActionNames,
+	}
+	if len(args.Units) > 0 || len(args.Applications) > 0 || len(args.Machines) > 0 {
+		units, err := unitTags(api.state, args.Units, args.Applications)
+		if err != nil {
+			return params.OperationResults{}, errors.Trace(err)
+		}
+		for _, tag := range units {
+			query.Receivers = append(query.Receivers, tag.Id())
+		}
+		for _, id := range args.Machines {
+			if !names.IsValidMachine(id) {
+				return params.OperationResults{}, errors.Errorf("invalid machine id %q", id)
+			}
+			query.Receivers = append(query.Receivers, id)
+		}
+		if len(query.Receivers) == 0 {
+			// The applications have no units.
+			return params.OperationResults{}, nil
+		}
+	}
+	for _, status := range args.Status {
+		query.Status = append(query.Status, state.ActionStatus(status))
+	}
+	infos, err := api.state.ListOperations(query)
+	if err != nil {
+		return params.OperationResults{}, errors.Trace(err)
+	}
+	results := params.OperationResults{
+		Results: make([]params.OperationResult, len(infos)),
+	}
+	for i, info := range infos {
+		// The operation's status depends on all of its tasks, not
+		// just those matching the query.
+		allTasks, err := info.Operation.Tasks()
+		if err != nil {
+			results.Results[i].OperationID = info.Operation.Id()
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = makeOperationResult(info.Operation, info.Tasks, state.OperationStatus(allTasks))
+	}
+	return results, nil
+}
+
+// Operations returns the operations with the given ids, with all of
+// their tasks.
+func (api *API) Operations(args params.OperationIDs) (params.OperationResults, error) {
+	if err := api.checkAccess(permission.ReadAccess); err != nil {
+		return params.OperationResults{}, errors.Trace(err)
+	}
+	results := params.OperationResults{
+		Results: make([]params.OperationResult, len(args.IDs)),
+	}
+	for i, id := range args.IDs {
+		op, err := api.state.Operation(id)
+		if err != nil {
+			results.Results[i].OperationID = id
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		tasks, err := op.Tasks()
+		if err != nil {
+			results.Results[i].OperationID = id
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = makeOperationResult(op, tasks, state.OperationStatus(tasks))
+	}
+	return results, nil
+}
+
+// Tasks returns the tasks with the given action tags.
+func (api *API) Tasks(args params.Entities) (params.ActionResults, error) {
+	if err := api.checkAccess(permission.ReadAccess); err != nil {
+		return params.ActionResults{}, errors.Trace(err)
+	}
+	results := params.ActionResults{
+		Results: make([]params.ActionResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseActionTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		task, err := api.state.ActionByTag(tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = makeTaskResult(task)
+	}
+	return results, nil
+}
+
+// CancelOperations cancels the pending tasks of the operations with
+// the given ids, and returns the operations with the tasks cancelled.
+// Tasks that are already running are left to complete. As with Run,
+// cancelling operations requires admin access to the model.
+func (api *API) CancelOperations(args params.OperationIDs) (params.OperationResults, error) {
+	if err := api.checkAccess(permission.AdminAccess); err != nil {
+		return params.OperationResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.OperationResults{}, errors.Trace(err)
+	}
+	results := params.OperationResults{
+		Results: make([]params.OperationResult, len(args.IDs)),
+	}
+	for i, id := range args.IDs {
+		op, err := api.state.Operation(id)
+		if err != nil {
+			results.Results[i].OperationID = id
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		cancelled, err := op.Cancel()
+		if err != nil {
+			results.Results[i] = makeOperationResult(op, cancelled, state.OperationStatus(cancelled))
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		tasks, err := op.Tasks()
+		if err != nil {
+			results.Results[i] = makeOperationResult(op, cancelled, state.OperationStatus(cancelled))
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = makeOperationResult(op, cancelled, state.OperationStatus(tasks))
+	}
+	return results, nil
+}
+
+// makeOperationResult returns the result for an operation with the
+// given status, reporting the given tasks of it.
+func makeOperationResult(op *state.Operation, tasks []state.Action, status state.ActionStatus) params.OperationResult {
+	result := params.OperationResult{
+		OperationID: op.Id(),
+		Summary:     op.Summary(),
+		Enqueued:    op.Enqueued(),
+		Status:      string(status),
+		Tasks:       make([]params.ActionResult, len(tasks)),
+	}
+	for i, task := range tasks {
+		result.Tasks[i] = makeTaskResult(task)
+	}
+	return result
+}
+
+func makeTaskResult(task state.Action) params.ActionResult {
+	receiverTag, err := names.ActionReceiverTag(task.Receiver())
+	if err != nil {
+		return params.ActionResult{
+			Action: &params.Action{Tag: task.ActionTag().String()},
+			Error:  common.ServerError(err),
+		}
+	}
+	return common.MakeActionResult(receiverTag, task)
+}
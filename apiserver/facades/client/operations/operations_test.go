@@ -0,0 +1,208 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operations_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/facades/client/operations"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type operationsSuite struct {
+	jujutesting.JujuConnSuite
+	commontesting.BlockHelper
+
+	api *operations.API
+}
+
+var _ = gc.Suite(&operationsSuite{})
+
+func (s *operationsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+
+	var err error
+	s.api, err = operations.NewAPI(s.State, nil, apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	magic, err := s.State.AddApplication(state.AddApplicationArgs{
+		Name:  "magic",
+		Charm: s.AddTestingCharm(c, "dummy"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 2; i++ {
+		unit, err := magic.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToNewMachine()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *operationsSuite) run(c *gc.C, args params.RunParams) params.OperationResult {
+	result, err := s.api.Run(args)
+	c.Assert(err, jc.ErrorIsNil)
+	return result
+}
+
+func (s *operationsSuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := operations.NewAPI(s.State, nil, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *operationsSuite) TestRun(c *gc.C) {
+	result := s.run(c, params.RunParams{
+		Commands:     "hostname",
+		Machines:     []string{"0"},
+		Applications: []string{"magic"},
+	})
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.OperationID, gc.Not(gc.Equals), "")
+	c.Assert(result.Summary, gc.Equals, "run hostname")
+	c.Assert(result.Status, gc.Equals, "pending")
+	c.Assert(result.Tasks, gc.HasLen, 3)
+
+	var receivers []string
+	for _, task := range result.Tasks {
+		c.Assert(task.Error, gc.IsNil)
+		c.Assert(task.Operation, gc.Equals, result.OperationID)
+		c.Assert(task.Action.Name, gc.Equals, "juju-run")
+		receivers = append(receivers, task.Action.Receiver)
+	}
+	c.Assert(receivers, jc.DeepEquals, []string{"unit-magic-0", "unit-magic-1", "machine-0"})
+}
+
+func (s *operationsSuite) TestRunOnAllMachines(c *gc.C) {
+	result, err := s.api.RunOnAllMachines(params.RunParams{Commands: "hostname"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Tasks, gc.HasLen, 3)
+	for _, task := range result.Tasks {
+		c.Assert(task.Error, gc.IsNil)
+		c.Assert(task.Action.Receiver, gc.Matches, "machine-.*")
+	}
+}
+
+func (s *operationsSuite) TestRunBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestRunBlocked")
+	_, err := s.api.Run(params.RunParams{
+		Commands: "hostname",
+		Machines: []string{"0"},
+	})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+}
+
+func (s *operationsSuite) TestRunRequiresAdmin(c *gc.C) {
+	api, err := operations.NewAPI(s.State, nil, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("read"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Run(params.RunParams{Commands: "hostname", Machines: []string{"0"}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *operationsSuite) TestListOperations(c *gc.C) {
+	first := s.run(c, params.RunParams{Commands: "hostname", Machines: []string{"0"}})
+	second := s.run(c, params.RunParams{Commands: "uptime", Applications: []string{"magic"}})
+
+	results, err := s.api.ListOperations(params.OperationQueryArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].OperationID, gc.Equals, first.OperationID)
+	c.Assert(results.Results[1].OperationID, gc.Equals, second.OperationID)
+
+	results, err = s.api.ListOperations(params.OperationQueryArgs{
+		Units:  []string{"magic/1"},
+		Status: []string{"pending"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].OperationID, gc.Equals, second.OperationID)
+	c.Assert(results.Results[0].Tasks, gc.HasLen, 1)
+	c.Assert(results.Results[0].Tasks[0].Action.Receiver, gc.Equals, "unit-magic-1")
+
+	results, err = s.api.ListOperations(params.OperationQueryArgs{
+		Status: []string{"running"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+}
+
+func (s *operationsSuite) TestOperationsAndTasks(c *gc.C) {
+	run := s.run(c, params.RunParams{Commands: "hostname", Machines: []string{"0"}})
+
+	results, err := s.api.Operations(params.OperationIDs{IDs: []string{run.OperationID, "42"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Tasks, gc.HasLen, 1)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `operation "42" not found`)
+
+	tasks, err := s.api.Tasks(params.Entities{Entities: []params.Entity{
+		{Tag: run.Tasks[0].Action.Tag},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tasks.Results, gc.HasLen, 2)
+	c.Assert(tasks.Results[0].Error, gc.IsNil)
+	c.Assert(tasks.Results[0].Operation, gc.Equals, run.OperationID)
+	c.Assert(tasks.Results[0].Status, gc.Equals, "pending")
+	c.Assert(tasks.Results[1].Error, gc.ErrorMatches, "id not found")
+}
+
+func (s *operationsSuite) TestCancelOperations(c *gc.C) {
+	run := s.run(c, params.RunParams{Commands: "hostname", Applications: []string{"magic"}})
+
+	results, err := s.api.CancelOperations(params.OperationIDs{IDs: []string{run.OperationID}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Status, gc.Equals, "cancelled")
+	c.Assert(results.Results[0].Tasks, gc.HasLen, 2)
+	for _, task := range results.Results[0].Tasks {
+		c.Assert(task.Status, gc.Equals, "cancelled")
+	}
+}
+
+func (s *operationsSuite) TestListOperationsStatusFromAllTasks(c *gc.C) {
+	run := s.run(c, params.RunParams{Commands: "hostname", Applications: []string{"magic"}})
+	tag, err := names.ParseActionTag(run.Tasks[0].Action.Tag)
+	c.Assert(err, jc.ErrorIsNil)
+	task, err := s.State.ActionByTag(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = task.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the pending task matches, but the operation is running.
+	results, err := s.api.ListOperations(params.OperationQueryArgs{
+		Status: []string{"pending"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Tasks, gc.HasLen, 1)
+	c.Assert(results.Results[0].Tasks[0].Status, gc.Equals, "pending")
+	c.Assert(results.Results[0].Status, gc.Equals, "running")
+}
+
+func (s *operationsSuite) TestCancelOperationsRequiresAdmin(c *gc.C) {
+	run := s.run(c, params.RunParams{Commands: "hostname", Machines: []string{"0"}})
+	api, err := operations.NewAPI(s.State, nil, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("write"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.CancelOperations(params.OperationIDs{IDs: []string{run.OperationID}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -4,9 +4,12 @@
 package storage_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
@@ -156,3 +159,56 @@ func (s *filesystemSuite) TestListFilesystemsVolumeBacked(c *gc.C) {
 	c.Assert(found.Results[0].Result, gc.HasLen, 1)
 	c.Assert(found.Results[0].Result[0], jc.DeepEquals, expected)
 }
+
+func (s *filesystemSuite) TestListFilesystemsAttachmentUsage(c *gc.C) {
+	updated := time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.state.filesystemAttachmentUsage = func(m names.MachineTag, f names.FilesystemTag) (state.FilesystemUsage, error) {
+		return state.FilesystemUsage{
+			UsedBytes:      1024,
+			AvailableBytes: 2048,
+			Updated:        updated,
+		}, nil
+	}
+	usage := &params.FilesystemUsage{
+		UsedBytes:      1024,
+		AvailableBytes: 2048,
+		Updated:        &updated,
+	}
+	expected := s.expectedFilesystemDetails()
+	expected.MachineAttachments[s.machineTag.String()] = params.FilesystemAttachmentDetails{
+		Life:  "dead",
+		Usage: usage,
+	}
+	expectedStorageAttachmentDetails := expected.Storage.Attachments["unit-mysql-0"]
+	expectedStorageAttachmentDetails.Usage = usage
+	expected.Storage.Attachments["unit-mysql-0"] = expectedStorageAttachmentDetails
+	found, err := s.api.ListFilesystems(params.FilesystemFilters{
+		[]params.FilesystemFilter{{}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Result, gc.HasLen, 1)
+	c.Assert(found.Results[0].Result[0], jc.DeepEquals, expected)
+}
+
+func (s *filesystemSuite) TestListFilesystemsAttachmentUsageError(c *gc.C) {
+	s.state.filesystemAttachmentUsage = func(m names.MachineTag, f names.FilesystemTag) (state.FilesystemUsage, error) {
+		return state.FilesystemUsage{}, errors.New("kaboom")
+	}
+	expected := s.expectedFilesystemDetails()
+	expected.MachineAttachments[s.machineTag.String()] = params.FilesystemAttachmentDetails{
+		Life:       "dead",
+		UsageError: &params.Error{Message: "kaboom"},
+	}
+	expectedStorageAttachmentDetails := expected.Storage.Attachments["unit-mysql-0"]
+	expectedStorageAttachmentDetails.UsageError = &params.Error{Message: "kaboom"}
+	expected.Storage.Attachments["unit-mysql-0"] = expectedStorageAttachmentDetails
+	found, err := s.api.ListFilesystems(params.FilesystemFilters{
+		[]params.FilesystemFilter{{}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Error, gc.IsNil)
+	c.Assert(found.Results[0].Result, gc.HasLen, 1)
+	c.Assert(found.Results[0].Result[0], jc.DeepEquals, expected)
+}
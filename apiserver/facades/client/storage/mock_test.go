@@ -46,6 +46,7 @@ type mockState struct {
 	volumeAttachment                    func(names.MachineTag, names.VolumeTag) (state.VolumeAttachment, error)
 	storageInstanceFilesystem           func(names.StorageTag) (state.Filesystem, error)
 	storageInstanceFilesystemAttachment func(m names.MachineTag, f names.FilesystemTag) (state.FilesystemAttachment, error)
+	filesystemAttachmentUsage           func(m names.MachineTag, f names.FilesystemTag) (state.FilesystemUsage, error)
 	watchStorageAttachment              func(names.StorageTag, names.UnitTag) state.NotifyWatcher
 	watchFilesystemAttachment           func(names.MachineTag, names.FilesystemTag) state.NotifyWatcher
 	watchVolumeAttachment               func(names.MachineTag, names.VolumeTag) state.NotifyWatcher
@@ -90,6 +91,13 @@ func (st *mockState) FilesystemAttachment(m names.MachineTag, f names.Filesystem
 	return st.storageInstanceFilesystemAttachment(m, f)
 }
 
+func (st *mockState) FilesystemAttachmentUsage(m names.MachineTag, f names.FilesystemTag) (state.FilesystemUsage, error) {
+	if st.filesystemAttachmentUsage == nil {
+		return state.FilesystemUsage{}, errors.NotFoundf("usage of filesystem %q", f.Id())
+	}
+	return st.filesystemAttachmentUsage(m, f)
+}
+
 func (st *mockState) StorageInstanceFilesystem(s names.StorageTag) (state.Filesystem, error) {
 	return st.storageInstanceFilesystem(s)
 }
@@ -75,6 +75,9 @@ type storageAccess interface {
 	// FilesystemAttachment is required for storage functionality.
 	FilesystemAttachment(names.MachineTag, names.FilesystemTag) (state.FilesystemAttachment, error)
 
+	// FilesystemAttachmentUsage is required for storage functionality.
+	FilesystemAttachmentUsage(names.MachineTag, names.FilesystemTag) (state.FilesystemUsage, error)
+
 	// StorageInstanceFilesystem is required for storage functionality.
 	StorageInstanceFilesystem(names.StorageTag) (state.Filesystem, error)
 
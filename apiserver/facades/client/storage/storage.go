@@ -168,11 +168,13 @@ func createStorageDetails(st storageAccess, si state.StorageInstance) (*params.S
 	// Get information from underlying volume or filesystem.
 	var persistent bool
 	var statusEntity status.StatusGetter
+	var filesystem state.Filesystem
 	if si.Kind() != state.StorageKindBlock {
 		// TODO(axw) when we support persistent filesystems,
 		// e.g. CephFS, we'll need to do set "persistent"
 		// here too.
-		filesystem, err := st.StorageInstanceFilesystem(si.StorageTag())
+		var err error
+		filesystem, err = st.StorageInstanceFilesystem(si.StorageTag())
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -212,6 +214,11 @@ func createStorageDetails(st storageAccess, si state.StorageInstance) (*params.S
 				Location:   location,
 				Life:       params.Life(a.Life().String()),
 			}
+			if filesystem != nil && machineTag != (names.MachineTag{}) {
+				usage, err := filesystemAttachmentUsage(st, machineTag, filesystem.FilesystemTag())
+				details.Usage = usage
+				details.UsageError = common.ServerError(err)
+			}
 			storageAttachmentDetails[a.Unit().String()] = details
 		}
 	}
@@ -248,6 +255,20 @@ func storageAttachmentInfo(st storageAccess, a state.StorageAttachment) (_ names
 	return machineTag, info.Location, nil
 }
 
+// filesystemAttachmentUsage returns the most recently reported usage
+// of the specified filesystem attachment, or nil if none has been
+// reported. Errors are reported against the attachment by the caller,
+// rather than failing the whole listing.
+func filesystemAttachmentUsage(st storageAccess, m names.MachineTag, f names.FilesystemTag) (*params.FilesystemUsage, error) {
+	usage, err := st.FilesystemAttachmentUsage(m, f)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return storagecommon.FilesystemUsageFromState(usage), nil
+}
+
 // ListPools returns a list of pools.
 // If filter is provided, returned list only contains pools that match
 // the filter.
@@ -673,6 +694,9 @@ func createFilesystemDetails(
 					stateInfo,
 				)
 			}
+			usage, err := filesystemAttachmentUsage(st, attachment.Machine(), attachment.Filesystem())
+			attDetails.Usage = usage
+			attDetails.UsageError = common.ServerError(err)
 			details.MachineAttachments[attachment.Machine().String()] = attDetails
 		}
 	}
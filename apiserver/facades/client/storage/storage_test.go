@@ -5,6 +5,7 @@ package storage_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -66,6 +67,53 @@ func (s *storageSuite) TestStorageListFilesystem(c *gc.C) {
 	c.Assert(found.Results[0].Result[0], jc.DeepEquals, wantedDetails)
 }
 
+func (s *storageSuite) TestStorageListFilesystemUsage(c *gc.C) {
+	updated := time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.state.filesystemAttachmentUsage = func(m names.MachineTag, f names.FilesystemTag) (state.FilesystemUsage, error) {
+		c.Assert(m, gc.Equals, s.machineTag)
+		c.Assert(f, gc.Equals, s.filesystemTag)
+		return state.FilesystemUsage{
+			UsedBytes:      1024,
+			AvailableBytes: 2048,
+			Updated:        updated,
+		}, nil
+	}
+	found, err := s.api.ListStorageDetails(
+		params.StorageFilters{[]params.StorageFilter{{}}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Error, gc.IsNil)
+	c.Assert(found.Results[0].Result, gc.HasLen, 1)
+	wantedDetails := s.createTestStorageDetails()
+	attachmentDetails := wantedDetails.Attachments[s.unitTag.String()]
+	attachmentDetails.Usage = &params.FilesystemUsage{
+		UsedBytes:      1024,
+		AvailableBytes: 2048,
+		Updated:        &updated,
+	}
+	wantedDetails.Attachments[s.unitTag.String()] = attachmentDetails
+	c.Assert(found.Results[0].Result[0], jc.DeepEquals, wantedDetails)
+}
+
+func (s *storageSuite) TestStorageListFilesystemUsageError(c *gc.C) {
+	s.state.filesystemAttachmentUsage = func(names.MachineTag, names.FilesystemTag) (state.FilesystemUsage, error) {
+		return state.FilesystemUsage{}, errors.New("kaboom")
+	}
+	found, err := s.api.ListStorageDetails(
+		params.StorageFilters{[]params.StorageFilter{{}}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Error, gc.IsNil)
+	c.Assert(found.Results[0].Result, gc.HasLen, 1)
+	wantedDetails := s.createTestStorageDetails()
+	attachmentDetails := wantedDetails.Attachments[s.unitTag.String()]
+	attachmentDetails.UsageError = &params.Error{Message: "kaboom"}
+	wantedDetails.Attachments[s.unitTag.String()] = attachmentDetails
+	c.Assert(found.Results[0].Result[0], jc.DeepEquals, wantedDetails)
+}
+
 func (s *storageSuite) TestStorageListVolume(c *gc.C) {
 	s.storageInstance.kind = state.StorageKindBlock
 	found, err := s.api.ListStorageDetails(
@@ -247,11 +295,10 @@ func (s *storageSuite) createTestStorageDetails() params.StorageDetails {
 		},
 		Attachments: map[string]params.StorageAttachmentDetails{
 			s.unitTag.String(): params.StorageAttachmentDetails{
-				s.storageTag.String(),
-				s.unitTag.String(),
-				s.machineTag.String(),
-				"", // location
-				"alive",
+				StorageTag: s.storageTag.String(),
+				UnitTag:    s.unitTag.String(),
+				MachineTag: s.machineTag.String(),
+				Life:       "alive",
 			},
 		},
 	}
@@ -305,11 +352,10 @@ func (s *storageSuite) TestShowStorage(c *gc.C) {
 		},
 		Attachments: map[string]params.StorageAttachmentDetails{
 			s.unitTag.String(): params.StorageAttachmentDetails{
-				s.storageTag.String(),
-				s.unitTag.String(),
-				s.machineTag.String(),
-				"",
-				"alive",
+				StorageTag: s.storageTag.String(),
+				UnitTag:    s.unitTag.String(),
+				MachineTag: s.machineTag.String(),
+				Life:       "alive",
 			},
 		},
 	}
@@ -46,6 +46,11 @@ type FirewallerAPIV4 struct {
 	*common.ControllerConfigAPI
 }
 
+// FirewallerAPIV5 provides access to the Firewaller v5 API facade.
+type FirewallerAPIV5 struct {
+	*FirewallerAPIV4
+}
+
 // NewStateFirewallerAPIv3 creates a new server-side FirewallerAPIV3 facade.
 func NewStateFirewallerAPIV3(context facade.Context) (*FirewallerAPIV3, error) {
 	st := context.State()
@@ -66,6 +71,17 @@ func NewStateFirewallerAPIV4(context facade.Context) (*FirewallerAPIV4, error) {
 	}, nil
 }
 
+// NewStateFirewallerAPIV5 creates a new server-side FirewallerAPIV5 facade.
+func NewStateFirewallerAPIV5(context facade.Context) (*FirewallerAPIV5, error) {
+	facadev4, err := NewStateFirewallerAPIV4(context)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV5{
+		FirewallerAPIV4: facadev4,
+	}, nil
+}
+
 // NewFirewallerAPI creates a new server-side FirewallerAPIV3 facade.
 func NewFirewallerAPI(
 	st State,
@@ -440,3 +456,33 @@ func (f *FirewallerAPIV4) MacaroonForRelations(args params.Entities) (params.Mac
 	}
 	return result, nil
 }
+
+// WatchFirewallRules returns a NotifyWatcher that triggers whenever
+// the controller's firewall rules change.
+func (f *FirewallerAPIV5) WatchFirewallRules() (params.NotifyWatchResult, error) {
+	w := f.st.WatchFirewallRules()
+	if _, ok := <-w.Changes(); !ok {
+		return params.NotifyWatchResult{}, common.ServerError(watcher.EnsureErr(w))
+	}
+	return params.NotifyWatchResult{
+		NotifyWatcherId: f.resources.Register(w),
+	}, nil
+}
+
+// FirewallRules returns the controller's firewall rules.
+func (f *FirewallerAPIV5) FirewallRules() (params.ListFirewallRulesResults, error) {
+	rules, err := f.st.FirewallRules()
+	if err != nil {
+		return params.ListFirewallRulesResults{}, common.ServerError(err)
+	}
+	result := params.ListFirewallRulesResults{
+		Rules: make([]params.FirewallRule, len(rules)),
+	}
+	for i, rule := range rules {
+		result.Rules[i] = params.FirewallRule{
+			KnownService:   string(rule.WellKnownService),
+			WhitelistCIDRS: rule.WhitelistCIDRs,
+		}
+	}
+	return result, nil
+}
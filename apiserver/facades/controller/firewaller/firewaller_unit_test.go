@@ -4,6 +4,8 @@
 package firewaller_test
 
 import (
+	"reflect"
+
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -27,7 +29,7 @@ type RemoteFirewallerSuite struct {
 	resources  *common.Resources
 	authorizer *apiservertesting.FakeAuthorizer
 	st         *mockState
-	api        *firewaller.FirewallerAPIV4
+	api        *firewaller.FirewallerAPIV5
 }
 
 func (s *RemoteFirewallerSuite) SetUpTest(c *gc.C) {
@@ -44,7 +46,9 @@ func (s *RemoteFirewallerSuite) SetUpTest(c *gc.C) {
 	s.st = newMockState(coretesting.ModelTag.Id())
 	api, err := firewaller.NewFirewallerAPI(s.st, s.resources, s.authorizer, &mockCloudSpecAPI{})
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &firewaller.FirewallerAPIV4{FirewallerAPIV3: api, ControllerConfigAPI: common.NewControllerConfig(s.st)}
+	s.api = &firewaller.FirewallerAPIV5{
+		FirewallerAPIV4: &firewaller.FirewallerAPIV4{FirewallerAPIV3: api, ControllerConfigAPI: common.NewControllerConfig(s.st)},
+	}
 }
 
 func (s *RemoteFirewallerSuite) TestWatchIngressAddressesForRelations(c *gc.C) {
@@ -104,3 +108,38 @@ func (s *RemoteFirewallerSuite) TestMacaroonForRelations(c *gc.C) {
 	c.Assert(result.Results[0].Error, gc.IsNil)
 	c.Assert(result.Results[0].Result, jc.DeepEquals, mac)
 }
+
+func (s *RemoteFirewallerSuite) TestFirewallRulesNotInV4(c *gc.C) {
+	v4 := reflect.TypeOf(&firewaller.FirewallerAPIV4{})
+	for _, method := range []string{"FirewallRules", "WatchFirewallRules"} {
+		_, ok := v4.MethodByName(method)
+		c.Check(ok, jc.IsFalse, gc.Commentf("method %s", method))
+	}
+}
+
+func (s *RemoteFirewallerSuite) TestWatchFirewallRules(c *gc.C) {
+	result, err := s.api.WatchFirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Equals, "1")
+
+	resource := s.resources.Get("1")
+	c.Assert(resource, gc.NotNil)
+	c.Assert(resource, gc.Implements, new(state.NotifyWatcher))
+	s.st.CheckCallNames(c, "WatchFirewallRules")
+}
+
+func (s *RemoteFirewallerSuite) TestFirewallRules(c *gc.C) {
+	s.st.firewallRules = []state.FirewallRule{{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.1.0/24"},
+	}}
+	result, err := s.api.FirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ListFirewallRulesResults{
+		Rules: []params.FirewallRule{{
+			KnownService:   "ssh",
+			WhitelistCIDRS: []string{"192.168.1.0/24"},
+		}},
+	})
+}
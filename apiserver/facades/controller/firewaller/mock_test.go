@@ -41,6 +41,8 @@ type mockState struct {
 	subnetsWatcher *mockStringsWatcher
 	modelWatcher   *mockNotifyWatcher
 	configAttrs    map[string]interface{}
+	firewallRules  []state.FirewallRule
+	rulesWatcher   *mockNotifyWatcher
 }
 
 func newMockState(modelUUID string) *mockState {
@@ -53,6 +55,7 @@ func newMockState(modelUUID string) *mockState {
 		subnetsWatcher: newMockStringsWatcher(),
 		modelWatcher:   newMockNotifyWatcher(),
 		configAttrs:    coretesting.FakeConfig(),
+		rulesWatcher:   newMockNotifyWatcher(),
 	}
 }
 
@@ -115,6 +118,16 @@ func (st *mockState) GetModel(tag names.ModelTag) (*state.Model, error) {
 	return nil, errors.NotImplementedf("GetModel")
 }
 
+func (st *mockState) FirewallRules() ([]state.FirewallRule, error) {
+	st.MethodCall(st, "FirewallRules")
+	return st.firewallRules, st.NextErr()
+}
+
+func (st *mockState) WatchFirewallRules() state.NotifyWatcher {
+	st.MethodCall(st, "WatchFirewallRules")
+	return st.rulesWatcher
+}
+
 type mockWatcher struct {
 	testing.Stub
 	tomb.Tomb
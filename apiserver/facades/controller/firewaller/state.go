@@ -23,6 +23,10 @@ type State interface {
 	WatchOpenedPorts() state.StringsWatcher
 
 	FindEntity(tag names.Tag) (state.Entity, error)
+
+	FirewallRules() ([]state.FirewallRule, error)
+
+	WatchFirewallRules() state.NotifyWatcher
 }
 
 // TODO(wallyworld) - for tests, remove when remaining firewaller tests become unit tests.
@@ -51,3 +55,11 @@ func (st stateShim) FindEntity(tag names.Tag) (state.Entity, error) {
 func (st stateShim) WatchOpenedPorts() state.StringsWatcher {
 	return st.st.WatchOpenedPorts()
 }
+
+func (st stateShim) FirewallRules() ([]state.FirewallRule, error) {
+	return st.st.FirewallRules()
+}
+
+func (st stateShim) WatchFirewallRules() state.NotifyWatcher {
+	return st.st.WatchFirewallRules()
+}
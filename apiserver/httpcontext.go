@@ -84,16 +84,18 @@ func (ctxt *httpContext) stateForRequestAuthenticated(r *http.Request) (
 		// Handle the special case of a worker on a controller machine
 		// acting on behalf of a hosted model.
 		if isMachineTag(req.AuthTag) {
-			entity, err := checkControllerMachineCreds(ctxt.srv.statePool.SystemState(), req, authenticator)
+			entity, err = checkControllerMachineCreds(ctxt.srv.statePool.SystemState(), req, authenticator)
 			if err != nil {
 				return nil, nil, nil, errors.NewUnauthorized(err, "")
 			}
-			return st, releaser, entity, nil
+		} else {
+			// Any other error at this point should be treated as
+			// "unauthorized".
+			return nil, nil, nil, errors.Trace(errors.NewUnauthorized(err, ""))
 		}
-
-		// Any other error at this point should be treated as
-		// "unauthorized".
-		return nil, nil, nil, errors.Trace(errors.NewUnauthorized(err, ""))
+	}
+	if err := ctxt.srv.checkListenerAccess(isAgentRequest(r), entity); err != nil {
+		return nil, nil, nil, errors.NewUnauthorized(err, "")
 	}
 	return st, releaser, entity, nil
 }
@@ -0,0 +1,157 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+// logArchiveHandler serves historical log records from the logs
+// collection as a gzip-compressed download, for analysis away from the
+// controller. Unlike debug-log, the request does not follow the log:
+// the response ends with the last matching record.
+type logArchiveHandler struct {
+	ctxt httpContext
+}
+
+// ServeHTTP implements http.Handler.
+//
+// Args for the HTTP GET request are as follows:
+//   entity -> []string - lists entity tags whose records are included
+//      - tags may finish with a '*' to match a prefix e.g.: unit-mysql-*
+//      - if none are set, the records of all entities are included
+//   start -> string - RFC3339 time, only include records logged at or
+//      after this time
+//   end -> string - RFC3339 time, only include records logged at or
+//      before this time
+//   level -> string - one of [TRACE, DEBUG, INFO, WARNING, ERROR], only
+//      include records of at least this severity
+//
+// Records are written one per line, in the same format as the
+// controller's logsink.log files.
+func (h *logArchiveHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "GET" {
+		if err := sendError(w, errors.MethodNotAllowedf("unsupported method: %q", req.Method)); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	if err := h.serveGet(w, req); err != nil {
+		if err := sendError(w, errors.Trace(err)); err != nil {
+			logger.Errorf("%v", err)
+		}
+	}
+}
+
+// serveGet writes the requested log records to w. Errors are only
+// returned before the response has been started; once records are
+// being sent, an error truncates the response, which the client will
+// see as an incomplete gzip stream.
+func (h *logArchiveHandler) serveGet(w http.ResponseWriter, req *http.Request) error {
+	st, releaser, _, err := h.ctxt.stateForRequestAuthenticatedTag(req, names.MachineTagKind, names.UserTagKind)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer releaser()
+
+	params, err := readLogArchiveParams(req.URL.Query())
+	if err != nil {
+		return errors.NewBadRequest(err, "")
+	}
+	tailer, err := newLogTailer(st, params)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer tailer.Stop()
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", st.ModelUUID()+".log.gz"))
+	w.WriteHeader(http.StatusOK)
+	gz := gzip.NewWriter(w)
+	stop := h.ctxt.stop()
+	for {
+		select {
+		case <-stop:
+			logger.Debugf("log archive request abandoned: server stopping")
+			return nil
+		case rec, ok := <-tailer.Logs():
+			if !ok {
+				if err := tailer.Err(); err != nil {
+					logger.Errorf("log archive request failed: %v", err)
+					return nil
+				}
+				if err := gz.Close(); err != nil {
+					logger.Debugf("log archive request failed: %v", err)
+				}
+				return nil
+			}
+			if _, err := gz.Write([]byte(formatLogArchiveLine(rec))); err != nil {
+				if isBrokenPipe(err) {
+					logger.Tracef("log archive request abandoned (client disconnected)")
+				} else {
+					logger.Errorf("log archive request failed: %v", err)
+				}
+				return nil
+			}
+		}
+	}
+}
+
+// readLogArchiveParams returns the parameters of the log tailer that
+// will read the records requested by the given query.
+func readLogArchiveParams(query url.Values) (state.LogTailerParams, error) {
+	params := state.LogTailerParams{
+		NoTail:        true,
+		IncludeEntity: query["entity"],
+	}
+	if value := query.Get("start"); value != "" {
+		start, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return params, errors.Errorf("start time %q is not a valid time in RFC3339 format", value)
+		}
+		params.StartTime = start
+	}
+	if value := query.Get("end"); value != "" {
+		end, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return params, errors.Errorf("end time %q is not a valid time in RFC3339 format", value)
+		}
+		if end.Before(params.StartTime) {
+			return params, errors.Errorf("end time %q is before start time", value)
+		}
+		params.EndTime = end
+	}
+	if value := query.Get("level"); value != "" {
+		level, ok := loggo.ParseLevel(value)
+		if !ok || level < loggo.TRACE || level > loggo.ERROR {
+			return params, errors.Errorf("level value %q is not one of %q, %q, %q, %q, %q",
+				value, loggo.TRACE, loggo.DEBUG, loggo.INFO, loggo.WARNING, loggo.ERROR)
+		}
+		params.MinLevel = level
+	}
+	return params, nil
+}
+
+// formatLogArchiveLine formats a log record as logToFile does.
+func formatLogArchiveLine(r *state.LogRecord) string {
+	return strings.Join([]string{
+		r.Entity.String(),
+		r.Time.In(time.UTC).Format("2006-01-02 15:04:05"),
+		r.Level.String(),
+		r.Module,
+		r.Location,
+		r.Message,
+	}, " ") + "\n"
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+type logArchiveSuite struct {
+	authHTTPSuite
+}
+
+var _ = gc.Suite(&logArchiveSuite{})
+
+func (s *logArchiveSuite) SetUpTest(c *gc.C) {
+	s.authHTTPSuite.SetUpTest(c)
+	t0 := time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)
+	logger := state.NewDbLogger(s.State)
+	defer logger.Close()
+	err := logger.Log([]state.LogRecord{{
+		Time:     t0,
+		Entity:   names.NewMachineTag("0"),
+		Version:  version.Current,
+		Module:   "juju.worker",
+		Location: "worker.go:1",
+		Level:    loggo.INFO,
+		Message:  "starting",
+	}, {
+		Time:     t0.Add(time.Minute),
+		Entity:   names.NewUnitTag("mysql/0"),
+		Version:  version.Current,
+		Module:   "juju.worker.uniter",
+		Location: "uniter.go:2",
+		Level:    loggo.ERROR,
+		Message:  "hook failed",
+	}, {
+		Time:     t0.Add(2 * time.Minute),
+		Entity:   names.NewMachineTag("0"),
+		Version:  version.Current,
+		Module:   "juju.worker",
+		Location: "worker.go:3",
+		Level:    loggo.DEBUG,
+		Message:  "stopping",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *logArchiveSuite) archiveURL(c *gc.C, query url.Values) string {
+	return s.makeURL(c, "https", "/model/"+s.modelUUID+"/log-archive", query).String()
+}
+
+func (s *logArchiveSuite) readArchive(c *gc.C, query url.Values) string {
+	resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.archiveURL(c, query)})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "application/gzip")
+	c.Assert(resp.Header.Get("Content-Disposition"), gc.Equals, fmt.Sprintf("attachment; filename=%q", s.modelUUID+".log.gz"))
+	r, err := gzip.NewReader(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *logArchiveSuite) assertError(c *gc.C, resp *http.Response, statusCode int, msg string) {
+	body := assertResponse(c, resp, statusCode, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, msg)
+}
+
+func (s *logArchiveSuite) TestRequiresAuth(c *gc.C) {
+	resp := s.sendRequest(c, httpRequestParams{method: "GET", url: s.archiveURL(c, nil)})
+	s.assertError(c, resp, http.StatusUnauthorized, "no credentials provided")
+}
+
+func (s *logArchiveSuite) TestRequiresGET(c *gc.C) {
+	resp := s.authRequest(c, httpRequestParams{method: "POST", url: s.archiveURL(c, nil)})
+	s.assertError(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *logArchiveSuite) TestBadParams(c *gc.C) {
+	for i, test := range []struct {
+		query url.Values
+		err   string
+	}{{
+		query: url.Values{"start": {"yesterday"}},
+		err:   `start time "yesterday" is not a valid time in RFC3339 format`,
+	}, {
+		query: url.Values{"start": {"2017-06-01T10:00:00Z"}, "end": {"2017-06-01T09:00:00Z"}},
+		err:   `end time "2017-06-01T09:00:00Z" is before start time`,
+	}, {
+		query: url.Values{"level": {"LOUD"}},
+		err:   `level value "LOUD" is not one of .*`,
+	}} {
+		c.Logf("test %d: %v", i, test.query)
+		resp := s.authRequest(c, httpRequestParams{method: "GET", url: s.archiveURL(c, test.query)})
+		s.assertError(c, resp, http.StatusBadRequest, test.err)
+	}
+}
+
+func (s *logArchiveSuite) TestAllRecords(c *gc.C) {
+	c.Assert(s.readArchive(c, nil), gc.Equals, `
+machine-0 2017-06-01 10:00:00 INFO juju.worker worker.go:1 starting
+unit-mysql-0 2017-06-01 10:01:00 ERROR juju.worker.uniter uniter.go:2 hook failed
+machine-0 2017-06-01 10:02:00 DEBUG juju.worker worker.go:3 stopping
+`[1:])
+}
+
+func (s *logArchiveSuite) TestFilters(c *gc.C) {
+	c.Assert(s.readArchive(c, url.Values{"entity": {"machine-0"}}), gc.Equals, `
+machine-0 2017-06-01 10:00:00 INFO juju.worker worker.go:1 starting
+machine-0 2017-06-01 10:02:00 DEBUG juju.worker worker.go:3 stopping
+`[1:])
+	c.Assert(s.readArchive(c, url.Values{
+		"start": {"2017-06-01T10:00:30Z"},
+		"end":   {"2017-06-01T10:01:30Z"},
+	}), gc.Equals, `
+unit-mysql-0 2017-06-01 10:01:00 ERROR juju.worker.uniter uniter.go:2 hook failed
+`[1:])
+	c.Assert(s.readArchive(c, url.Values{"level": {"INFO"}}), gc.Equals, `
+machine-0 2017-06-01 10:00:00 INFO juju.worker worker.go:1 starting
+unit-mysql-0 2017-06-01 10:01:00 ERROR juju.worker.uniter uniter.go:2 hook failed
+`[1:])
+}
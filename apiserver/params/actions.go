@@ -55,6 +55,17 @@ type ActionResult struct {
 	Message   string                 `json:"message,omitempty"`
 	Output    map[string]interface{} `json:"output,omitempty"`
 	Error     *Error                 `json:"error,omitempty"`
+
+	ProgressMessage string `json:"progress-message,omitempty"`
+	ProgressPercent int    `json:"progress-percent,omitempty"`
+
+	// Log holds the messages logged by the action while it was
+	// running, in the order they were logged.
+	Log []ActionMessage `json:"log,omitempty"`
+
+	// Operation is the id of the operation the action is a task of,
+	// if any.
+	Operation string `json:"operation,omitempty"`
 }
 
 // ActionsByReceivers wrap a slice of Actions for API calls.
@@ -116,6 +127,39 @@ type ActionExecutionResult struct {
 	Message   string                 `json:"message,omitempty"`
 }
 
+// ActionsProgress holds a slice of ActionProgress for a bulk
+// action API call.
+type ActionsProgress struct {
+	Progress []ActionProgress `json:"progress,omitempty"`
+}
+
+// ActionProgress holds the action tag, message and completion
+// percentage used when reporting the progress of a running action.
+type ActionProgress struct {
+	ActionTag string `json:"action-tag"`
+	Message   string `json:"message"`
+	Percent   int    `json:"percent"`
+}
+
+// ActionMessage is a timestamped message logged by a running action.
+type ActionMessage struct {
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+// ActionMessageParams holds the action tag and message used when
+// logging a message against a running action.
+type ActionMessageParams struct {
+	ActionTag string `json:"action-tag"`
+	Message   string `json:"message"`
+}
+
+// ActionsMessages holds a slice of ActionMessageParams for a bulk
+// action API call.
+type ActionsMessages struct {
+	Messages []ActionMessageParams `json:"messages,omitempty"`
+}
+
 // ApplicationsCharmActionsResults holds a slice of ApplicationCharmActionsResult for
 // a bulk result of charm Actions for Applications.
 type ApplicationsCharmActionsResults struct {
@@ -143,3 +187,34 @@ type ActionPruneArgs struct {
 	MaxHistoryTime time.Duration `json:"max-history-time"`
 	MaxHistoryMB   int           `json:"max-history-mb"`
 }
+
+// OperationQueryArgs holds the filters used to list operations. Only
+// the tasks that match all of the non-empty filters are listed.
+type OperationQueryArgs struct {
+	Applications []string `json:"applications,omitempty"`
+	Units        []string `json:"units,omitempty"`
+	Machines     []string `json:"machines,omitempty"`
+	ActionNames  []string `json:"actions,omitempty"`
+	Status       []string `json:"status,omitempty"`
+}
+
+// OperationIDs holds the ids of operations for bulk requests.
+type OperationIDs struct {
+	IDs []string `json:"ids"`
+}
+
+// OperationResult describes an operation, a group of actions, its
+// tasks, that were enqueued together.
+type OperationResult struct {
+	OperationID string         `json:"operation"`
+	Summary     string         `json:"summary,omitempty"`
+	Enqueued    time.Time      `json:"enqueued,omitempty"`
+	Status      string         `json:"status,omitempty"`
+	Tasks       []ActionResult `json:"tasks,omitempty"`
+	Error       *Error         `json:"error,omitempty"`
+}
+
+// OperationResults is a slice of OperationResult for bulk requests.
+type OperationResults struct {
+	Results []OperationResult `json:"results,omitempty"`
+}
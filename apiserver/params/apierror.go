@@ -96,6 +96,8 @@ const (
 	CodeDischargeRequired         = "macaroon discharge required"
 	CodeRedirect                  = "redirection required"
 	CodeRetry                     = "retry"
+	CodeMinJujuVersionNotMet      = "min juju version not met"
+	CodeWatcherResyncRequired     = "watcher resync required"
 )
 
 // ErrCode returns the error code associated with
@@ -238,6 +240,14 @@ func IsCodeNotSupported(err error) bool {
 	return ErrCode(err) == CodeNotSupported
 }
 
+func IsCodeMinJujuVersionNotMet(err error) bool {
+	return ErrCode(err) == CodeMinJujuVersionNotMet
+}
+
+func IsCodeWatcherResyncRequired(err error) bool {
+	return ErrCode(err) == CodeWatcherResyncRequired
+}
+
 func IsBadRequest(err error) bool {
 	return ErrCode(err) == CodeBadRequest
 }
@@ -3,6 +3,8 @@
 
 package params
 
+import "github.com/juju/version"
+
 // DestroyControllerArgs holds the arguments for destroying a controller.
 type DestroyControllerArgs struct {
 	// DestroyModels specifies whether or not the hosted models
@@ -97,3 +99,33 @@ const (
 	GrantControllerAccess  ControllerAction = "grant"
 	RevokeControllerAccess ControllerAction = "revoke"
 )
+
+// UpgradeDryRunArgs holds the version of Juju to check an upgrade of
+// the controller to.
+type UpgradeDryRunArgs struct {
+	TargetVersion version.Number `json:"target-version"`
+}
+
+// UpgradeStepCheck holds the result of checking the preconditions of
+// an upgrade step that the controller would run.
+type UpgradeStepCheck struct {
+	// Version is the version of Juju that the step belongs to.
+	Version string `json:"version"`
+
+	// Description is the step's human readable description.
+	Description string `json:"description"`
+
+	// Feature holds the controller feature that enables the step,
+	// if it is feature-gated.
+	Feature string `json:"feature,omitempty"`
+
+	// Error holds the reason that the step cannot be run, if its
+	// preconditions are not met.
+	Error *Error `json:"error,omitempty"`
+}
+
+// UpgradeDryRunResult holds the upgrade steps that the controller
+// would run, in order.
+type UpgradeDryRunResult struct {
+	Steps []UpgradeStepCheck `json:"steps"`
+}
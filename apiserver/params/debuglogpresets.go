@@ -0,0 +1,26 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// DebugLogPreset is a named set of debug-log filters stored on the
+// controller.
+type DebugLogPreset struct {
+	Name          string   `json:"name"`
+	IncludeEntity []string `json:"include-entity,omitempty"`
+	ExcludeEntity []string `json:"exclude-entity,omitempty"`
+	IncludeModule []string `json:"include-module,omitempty"`
+	ExcludeModule []string `json:"exclude-module,omitempty"`
+	Level         string   `json:"level,omitempty"`
+	Format        string   `json:"format,omitempty"`
+}
+
+// DebugLogPresets holds a collection of debug-log presets.
+type DebugLogPresets struct {
+	Presets []DebugLogPreset `json:"presets"`
+}
+
+// DebugLogPresetNames holds the names of debug-log presets.
+type DebugLogPresetNames struct {
+	Names []string `json:"names"`
+}
@@ -0,0 +1,23 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// FirewallRule holds the source CIDRs allowed to reach a well known
+// controller-level service.
+type FirewallRule struct {
+	KnownService   string   `json:"known-service"`
+	WhitelistCIDRS []string `json:"whitelist-cidrs,omitempty"`
+}
+
+// FirewallRuleArgs holds the parameters for updating one or more
+// firewall rules.
+type FirewallRuleArgs struct {
+	Args []FirewallRule `json:"args"`
+}
+
+// ListFirewallRulesResults holds the results of listing firewall
+// rules.
+type ListFirewallRulesResults struct {
+	Rules []FirewallRule `json:"rules"`
+}
@@ -211,6 +211,12 @@ type Settings map[string]string
 type SettingsResult struct {
 	Error    *Error   `json:"error,omitempty"`
 	Settings Settings `json:"settings"`
+
+	// Version is a token identifying the settings, returned by the
+	// conditional reads. Settings are omitted and NotModified is set
+	// if the caller already holds that version.
+	Version     string `json:"version,omitempty"`
+	NotModified bool   `json:"not-modified,omitempty"`
 }
 
 // SettingsResults holds the result of an API calls that
@@ -219,6 +225,18 @@ type SettingsResults struct {
 	Results []SettingsResult `json:"results"`
 }
 
+// UnitSecrets holds secrets to be stored on behalf of a unit's charm.
+// Secrets with empty values are removed.
+type UnitSecrets struct {
+	Tag     string            `json:"tag"`
+	Secrets map[string]string `json:"secrets"`
+}
+
+// UnitsSecrets holds the arguments for a SetSecrets API call.
+type UnitsSecrets struct {
+	Units []UnitSecrets `json:"units"`
+}
+
 // ConfigSettings holds unit, application or cham configuration settings
 // with string keys and arbitrary values.
 type ConfigSettings map[string]interface{}
@@ -227,6 +245,12 @@ type ConfigSettings map[string]interface{}
 type ConfigSettingsResult struct {
 	Error    *Error         `json:"error,omitempty"`
 	Settings ConfigSettings `json:"settings"`
+
+	// Version is a token identifying the settings, returned by the
+	// conditional reads. Settings are omitted and NotModified is set
+	// if the caller already holds that version.
+	Version     string `json:"version,omitempty"`
+	NotModified bool   `json:"not-modified,omitempty"`
 }
 
 // ConfigSettingsResults holds multiple configuration maps or errors.
@@ -243,6 +267,18 @@ type ControllerConfig map[string]interface{}
 // ModelConfigResult holds model configuration.
 type ModelConfigResult struct {
 	Config ModelConfig `json:"config"`
+
+	// Version is a token identifying the configuration, returned by
+	// the conditional reads. Config is omitted and NotModified is set
+	// if the caller already holds that version.
+	Version     string `json:"version,omitempty"`
+	NotModified bool   `json:"not-modified,omitempty"`
+}
+
+// ModelConfigVersion holds the version of the model configuration
+// already held by the caller, if any.
+type ModelConfigVersion struct {
+	Version string `json:"version,omitempty"`
 }
 
 // ControllerConfigResult holds controller configuration.
@@ -250,6 +286,11 @@ type ControllerConfigResult struct {
 	Config ControllerConfig `json:"config"`
 }
 
+// ControllerConfigSet holds new controller configuration values.
+type ControllerConfigSet struct {
+	Config ControllerConfig `json:"config"`
+}
+
 // ControllerAPIInfoResult holds controller api address details.
 type ControllerAPIInfoResult struct {
 	Addresses []string `json:"addresses"`
@@ -292,6 +333,50 @@ type RelationUnitPairs struct {
 	RelationUnitPairs []RelationUnitPair `json:"relation-unit-pairs"`
 }
 
+// VersionedEntity identifies an entity and the version of the entity's
+// settings already held by the caller, if any.
+type VersionedEntity struct {
+	Tag     string `json:"tag"`
+	Version string `json:"version,omitempty"`
+}
+
+// VersionedEntities holds the parameters for conditional reads of the
+// settings of multiple entities.
+type VersionedEntities struct {
+	Entities []VersionedEntity `json:"entities"`
+}
+
+// VersionedRelationUnit holds a relation tag, a unit tag and the
+// version of the unit's settings in the relation already held by the
+// caller, if any.
+type VersionedRelationUnit struct {
+	Relation string `json:"relation"`
+	Unit     string `json:"unit"`
+	Version  string `json:"version,omitempty"`
+}
+
+// VersionedRelationUnits holds the parameters for conditional reads of
+// the settings of multiple units in their relations.
+type VersionedRelationUnits struct {
+	RelationUnits []VersionedRelationUnit `json:"relation-units"`
+}
+
+// VersionedRelationUnitPair holds a relation tag, a local and remote
+// unit tags and the version of the remote unit's settings already held
+// by the caller, if any.
+type VersionedRelationUnitPair struct {
+	Relation   string `json:"relation"`
+	LocalUnit  string `json:"local-unit"`
+	RemoteUnit string `json:"remote-unit"`
+	Version    string `json:"version,omitempty"`
+}
+
+// VersionedRelationUnitPairs holds the parameters for conditional reads
+// of the settings of multiple remote units.
+type VersionedRelationUnitPairs struct {
+	RelationUnitPairs []VersionedRelationUnitPair `json:"relation-unit-pairs"`
+}
+
 // RelationUnitSettings holds a relation tag, a unit tag and local
 // unit settings.
 type RelationUnitSettings struct {
@@ -306,6 +391,36 @@ type RelationUnitsSettings struct {
 	RelationUnits []RelationUnitSettings `json:"relation-units"`
 }
 
+// GoalStateStatus holds the status of a unit or application in the
+// goal state of a unit, and when it was last changed.
+type GoalStateStatus struct {
+	Status string     `json:"status"`
+	Since  *time.Time `json:"since,omitempty"`
+}
+
+// UnitsGoalState holds the goal state statuses of units, and of the
+// applications in a relation, keyed on name.
+type UnitsGoalState map[string]GoalStateStatus
+
+// GoalState holds the units and relations that the model intends to
+// exist for a unit's application, keyed on unit name and relation
+// endpoint name respectively.
+type GoalState struct {
+	Units     UnitsGoalState            `json:"units"`
+	Relations map[string]UnitsGoalState `json:"relations"`
+}
+
+// GoalStateResult holds the goal state of a single unit, or an error.
+type GoalStateResult struct {
+	Result *GoalState `json:"result"`
+	Error  *Error     `json:"error,omitempty"`
+}
+
+// GoalStateResults holds the results of a GoalStates API call.
+type GoalStateResults struct {
+	Results []GoalStateResult `json:"results"`
+}
+
 // RelationResults holds the result of an API call that returns
 // information about multiple relations.
 type RelationResults struct {
@@ -363,6 +478,20 @@ type EntityWorkloadVersions struct {
 	Entities []EntityWorkloadVersion `json:"entities"`
 }
 
+// ApplicationHookCompletion records that the leader unit identified by
+// Tag has completed the named application hook for a charm.
+type ApplicationHookCompletion struct {
+	Tag      string `json:"tag"`
+	Hook     string `json:"hook"`
+	CharmURL string `json:"charm-url"`
+}
+
+// ApplicationHookCompletions holds the parameters for recording the
+// completion of application hooks.
+type ApplicationHookCompletions struct {
+	Completions []ApplicationHookCompletion `json:"completions"`
+}
+
 // BytesResult holds the result of an API call that returns a slice
 // of bytes.
 type BytesResult struct {
@@ -453,7 +582,14 @@ type AgentGetEntitiesResult struct {
 // DesiredVersion() API call.
 type VersionResult struct {
 	Version *version.Number `json:"version,omitempty"`
-	Error   *Error          `json:"error,omitempty"`
+
+	// Held is true if the agent is being held at its current
+	// version, until a maintenance window opens or upgrade canaries
+	// have proven the desired version, so the desired version
+	// should be checked again later.
+	Held bool `json:"held,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
 }
 
 // VersionResults is a list of versions for the requested entities.
@@ -770,6 +906,56 @@ type LogMessage struct {
 	Message   string    `json:"msg"`
 }
 
+// DebugLogRecord is a log record as sent by the debug-log API when the
+// json format is requested. Field names are chosen to be descriptive
+// so that the stream can be consumed directly by external tooling.
+type DebugLogRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	Entity    string    `json:"entity"`
+	Module    string    `json:"module"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	Location  string    `json:"location"`
+}
+
+// DebugLogTrailer summarises a debug-log stream. When the client asks
+// for it, it is sent as the last message of a stream that ends
+// normally, wrapped in a DebugLogTrailerMessage.
+type DebugLogTrailer struct {
+	// LinesMatched is the number of records read that matched the
+	// filters, whether or not they were sent.
+	LinesMatched uint64 `json:"lines-matched"`
+
+	// LinesSent is the number of records sent.
+	LinesSent uint64 `json:"lines-sent"`
+
+	// BytesSent is the size of the messages sent before the trailer.
+	BytesSent uint64 `json:"bytes-sent"`
+
+	// Truncated reports whether records that matched the filters
+	// were not sent, because they were dropped or because the
+	// requested maximum number of lines was reached.
+	Truncated bool `json:"truncated"`
+}
+
+// DebugLogTrailerMessage wraps a DebugLogTrailer so that clients can
+// tell it from a log record.
+type DebugLogTrailerMessage struct {
+	Trailer DebugLogTrailer `json:"trailer"`
+}
+
+// DebugLogFilters holds the filters that a debug-log client may send
+// on an open stream to replace those it requested, without
+// reconnecting. Each message replaces all of the filters; a filter
+// that is not set no longer applies.
+type DebugLogFilters struct {
+	Level         string   `json:"level,omitempty"`
+	IncludeEntity []string `json:"includeEntity,omitempty"`
+	ExcludeEntity []string `json:"excludeEntity,omitempty"`
+	IncludeModule []string `json:"includeModule,omitempty"`
+	ExcludeModule []string `json:"excludeModule,omitempty"`
+}
+
 // ResourceUploadResult is used to return some details about an
 // uploaded resource.
 type ResourceUploadResult struct {
@@ -782,3 +968,22 @@ type ResourceUploadResult struct {
 	// Timestamp indicates when the resource was added to the model.
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// UpgradeStep identifies an upgrade step run by an agent.
+type UpgradeStep struct {
+	// Tag is the tag of the agent that runs the step.
+	Tag string `json:"tag"`
+
+	// Version is the version of the upgrade that the step belongs to.
+	Version version.Number `json:"version"`
+
+	// Description is the step's description, which identifies it
+	// within its upgrade.
+	Description string `json:"description"`
+}
+
+// UpgradeSteps holds the arguments for recording or checking the
+// completion of upgrade steps.
+type UpgradeSteps struct {
+	Steps []UpgradeStep `json:"steps"`
+}
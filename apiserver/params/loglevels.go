@@ -0,0 +1,23 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// LoggingConfig holds a loggo configuration string, such as
+// "<root>=WARNING;juju.worker.uniter=DEBUG".
+type LoggingConfig struct {
+	Config string `json:"config"`
+}
+
+// AgentLoggingConfig holds the logging configuration applied by an
+// agent on top of its model's logging-config.
+type AgentLoggingConfig struct {
+	Tag    string `json:"tag"`
+	Config string `json:"config"`
+}
+
+// AgentLoggingConfigs holds the logging configuration for a number
+// of agents.
+type AgentLoggingConfigs struct {
+	Configs []AgentLoggingConfig `json:"configs"`
+}
@@ -0,0 +1,52 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// BranchArg identifies a branch of a model.
+type BranchArg struct {
+	BranchName string `json:"branch"`
+}
+
+// BranchTrackArg identifies the units that are to track a branch.
+// Entities may be unit tags, or application tags to make all of the
+// application's units track the branch.
+type BranchTrackArg struct {
+	BranchName string   `json:"branch"`
+	Entities   []Entity `json:"entities"`
+}
+
+// BranchConfigArg holds changes to an application's charm config to
+// be staged on a branch.
+type BranchConfigArg struct {
+	BranchName      string `json:"branch"`
+	ApplicationName string `json:"application"`
+
+	// Options holds the settings to change, as strings to be parsed
+	// according to the charm's config.
+	Options map[string]string `json:"options,omitempty"`
+
+	// Unset holds the names of the settings to reset to the charm
+	// defaults.
+	Unset []string `json:"unset,omitempty"`
+}
+
+// Branch describes a branch of a model that is in progress.
+type Branch struct {
+	BranchName string `json:"branch"`
+	CreatedBy  string `json:"created-by"`
+
+	// AssignedUnits holds the names of the units tracking the
+	// branch, keyed on application name.
+	AssignedUnits map[string][]string `json:"assigned-units"`
+
+	// Config holds the charm config changes staged on the branch,
+	// keyed on application name. A nil value resets the setting to
+	// the charm default.
+	Config map[string]map[string]interface{} `json:"config"`
+}
+
+// BranchResults holds the branches of a model that are in progress.
+type BranchResults struct {
+	Branches []Branch `json:"branches"`
+}
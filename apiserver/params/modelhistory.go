@@ -0,0 +1,41 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// ModelHistoryArgs holds the parameters for querying the history of
+// significant events in a model.
+type ModelHistoryArgs struct {
+	// Since is the earliest time from which events are returned.
+	Since time.Time `json:"since"`
+
+	// Limit, if positive, is the maximum number of events to
+	// return; the most recent events are returned.
+	Limit int `json:"limit,omitempty"`
+
+	// Kinds, if not empty, restricts the events returned to those
+	// of the given kinds.
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// ModelEvent describes a significant event in the history of a model.
+type ModelEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	Entity    string    `json:"entity"`
+	Origin    string    `json:"origin,omitempty"`
+	Summary   string    `json:"summary"`
+}
+
+// ModelEvents holds the events in the history of a model, oldest
+// first.
+type ModelEvents struct {
+	Events []ModelEvent `json:"events"`
+
+	// AuditingDisabled is true if events recorded only by the
+	// controller's audit log were requested, but auditing is not
+	// enabled on the controller, so no such events are returned.
+	AuditingDisabled bool `json:"auditing-disabled,omitempty"`
+}
@@ -274,6 +274,20 @@ type EntitiesPortRanges struct {
 	Entities []EntityPortRange `json:"entities"`
 }
 
+// EntityPortRangeChanges holds the port ranges to open and close for
+// an entity in a single operation.
+type EntityPortRangeChanges struct {
+	Tag         string      `json:"tag"`
+	OpenRanges  []PortRange `json:"open-ranges,omitempty"`
+	CloseRanges []PortRange `json:"close-ranges,omitempty"`
+}
+
+// EntitiesPortRangeChanges holds the parameters for making an
+// OpenClosePortRanges call on some entities.
+type EntitiesPortRangeChanges struct {
+	Changes []EntityPortRangeChanges `json:"changes"`
+}
+
 // Address represents the location of a machine, including metadata
 // about what kind of location the address describes. It's used in
 // the API requests/responses. See also network.Address, from/to
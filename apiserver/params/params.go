@@ -284,6 +284,13 @@ type ApplicationExpose struct {
 	ApplicationName string `json:"application"`
 }
 
+// ApplicationTrust holds the parameters for making the application
+// SetTrust call.
+type ApplicationTrust struct {
+	ApplicationName string `json:"application"`
+	Trusted         bool   `json:"trusted"`
+}
+
 // ApplicationSet holds the parameters for an application Set
 // command. Options contains the configuration data.
 type ApplicationSet struct {
@@ -291,6 +298,13 @@ type ApplicationSet struct {
 	Options         map[string]string `json:"options"`
 }
 
+// ApplicationsSet holds the parameters for setting config on several
+// applications at once. Either all of the changes are made, or none
+// of them are.
+type ApplicationsSet struct {
+	Applications []ApplicationSet `json:"applications"`
+}
+
 // ApplicationUnset holds the parameters for an application Unset
 // command. Options contains the option attribute names
 // to unset.
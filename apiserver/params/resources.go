@@ -96,6 +96,10 @@ type Resource struct {
 
 	// Timestamp indicates when the resource was added to the model.
 	Timestamp time.Time `json:"timestamp"`
+
+	// PinnedFingerprint is the SHA-384 checksum the resource is
+	// pinned to, if any.
+	PinnedFingerprint []byte `json:"pinned-fingerprint,omitempty"`
 }
 
 // CharmResource contains the definition for a resource.
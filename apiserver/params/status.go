@@ -245,6 +245,19 @@ type StatusHistoryResults struct {
 	Results []StatusHistoryResult `json:"results"`
 }
 
+// StatusAnnotation holds a note to be added to the status history of
+// the unit or machine with the given tag.
+type StatusAnnotation struct {
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+// StatusAnnotations holds the arguments for an AddStatusAnnotations
+// API call.
+type StatusAnnotations struct {
+	Annotations []StatusAnnotation `json:"annotations"`
+}
+
 // StatusHistoryPruneArgs holds arguments for status history
 // prunning process.
 type StatusHistoryPruneArgs struct {
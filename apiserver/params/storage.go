@@ -3,7 +3,11 @@
 
 package params
 
-import "github.com/juju/juju/storage"
+import (
+	"time"
+
+	"github.com/juju/juju/storage"
+)
 
 // MachineBlockDevices holds a machine tag and the block devices present
 // on that machine.
@@ -18,6 +22,40 @@ type SetMachineBlockDevices struct {
 	MachineBlockDevices []MachineBlockDevices `json:"machine-block-devices"`
 }
 
+// FilesystemUsage describes the space used on a mounted filesystem.
+type FilesystemUsage struct {
+	// UsedBytes is the number of bytes used on the filesystem.
+	UsedBytes uint64 `json:"used-bytes"`
+
+	// AvailableBytes is the number of bytes available to
+	// unprivileged users of the filesystem.
+	AvailableBytes uint64 `json:"available-bytes"`
+
+	// Updated is the time at which the usage was recorded. It is
+	// not set when reporting usage.
+	Updated *time.Time `json:"updated,omitempty"`
+}
+
+// FilesystemAttachmentUsage holds the usage of a filesystem attached
+// to a machine.
+type FilesystemAttachmentUsage struct {
+	FilesystemTag string          `json:"filesystem-tag"`
+	Usage         FilesystemUsage `json:"usage"`
+}
+
+// MachineFilesystemUsage holds a machine tag and the usage of the
+// filesystems attached to that machine.
+type MachineFilesystemUsage struct {
+	Machine     string                      `json:"machine"`
+	Filesystems []FilesystemAttachmentUsage `json:"filesystems,omitempty"`
+}
+
+// SetMachineFilesystemUsage holds the arguments for recording the
+// usage of the filesystems attached to a set of machines.
+type SetMachineFilesystemUsage struct {
+	MachineFilesystemUsage []MachineFilesystemUsage `json:"machine-filesystem-usage"`
+}
+
 // BlockDeviceResult holds the result of an API call to retrieve details
 // of a block device.
 type BlockDeviceResult struct {
@@ -94,6 +132,14 @@ type StorageAttachment struct {
 	Kind     StorageKind `json:"kind"`
 	Location string      `json:"location"`
 	Life     Life        `json:"life"`
+
+	// Usage holds the most recently reported usage of a
+	// filesystem-kind storage attachment, if any.
+	Usage *FilesystemUsage `json:"usage,omitempty"`
+
+	// UsageError holds the error, if any, encountered while
+	// fetching Usage.
+	UsageError *Error `json:"usage-error,omitempty"`
 }
 
 // StorageAttachmentId identifies a storage attachment by the tags of the
@@ -405,6 +451,18 @@ type FilesystemAttachmentResults struct {
 	Results []FilesystemAttachmentResult `json:"results,omitempty"`
 }
 
+// FilesystemAttachmentsResult holds the details of a set of filesystem
+// attachments, or an error.
+type FilesystemAttachmentsResult struct {
+	Result []FilesystemAttachment `json:"result,omitempty"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// FilesystemAttachmentsResults holds a set of FilesystemAttachmentsResults.
+type FilesystemAttachmentsResults struct {
+	Results []FilesystemAttachmentsResult `json:"results,omitempty"`
+}
+
 // FilesystemResult holds information about a filesystem.
 type FilesystemResult struct {
 	Result Filesystem `json:"result"`
@@ -535,6 +593,14 @@ type StorageAttachmentDetails struct {
 	// Juju controllers older than 2.2 do not populate this
 	// field, so it may be omitted.
 	Life Life `json:"life,omitempty"`
+
+	// Usage holds the most recently reported usage of a
+	// filesystem-kind storage attachment, if any.
+	Usage *FilesystemUsage `json:"usage,omitempty"`
+
+	// UsageError holds the error, if any, encountered while
+	// fetching Usage.
+	UsageError *Error `json:"usage-error,omitempty"`
 }
 
 // StoragePool holds data for a pool instance.
@@ -728,6 +794,14 @@ type FilesystemAttachmentDetails struct {
 	// Juju controllers older than 2.2 do not populate this
 	// field, so it may be omitted.
 	Life Life `json:"life,omitempty"`
+
+	// Usage holds the most recently reported usage of the
+	// filesystem on the machine, if any.
+	Usage *FilesystemUsage `json:"usage,omitempty"`
+
+	// UsageError holds the error, if any, encountered while
+	// fetching Usage.
+	UsageError *Error `json:"usage-error,omitempty"`
 }
 
 // FilesystemDetailsResult contains details about a filesystem, its attachments or
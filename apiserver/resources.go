@@ -37,6 +37,15 @@ type ResourcesBackend interface {
 
 	// UpdatePendingResource adds the resource to blob storage and updates the metadata.
 	UpdatePendingResource(applicationID, pendingID, userID string, res charmresource.Resource, r io.Reader) (resource.Resource, error)
+
+	// UpdatePinnedPendingResource adds the resource to blob storage and
+	// updates the metadata, pinning the resource to the given
+	// fingerprint, which the content must have.
+	UpdatePinnedPendingResource(applicationID, pendingID, userID string, res charmresource.Resource, pin charmresource.Fingerprint, r io.Reader) (resource.Resource, error)
+
+	// SetUnpinnedResource is like SetResource, but replaces the
+	// resource even if it is pinned, leaving it unpinned.
+	SetUnpinnedResource(applicationID, userID string, res charmresource.Resource, r io.Reader) (resource.Resource, error)
 }
 
 // ResourcesHandler is the HTTP handler for client downloads and
@@ -101,11 +110,21 @@ func (h *ResourcesHandler) upload(backend ResourcesBackend, req *http.Request, u
 	}
 
 	var stored resource.Resource
-	if uploaded.PendingID != "" {
+	if !uploaded.PinnedFingerprint.IsZero() {
+		stored, err = backend.UpdatePinnedPendingResource(uploaded.Service, uploaded.PendingID, username, uploaded.Resource, uploaded.PinnedFingerprint, uploaded.Data)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else if uploaded.PendingID != "" {
 		stored, err = backend.UpdatePendingResource(uploaded.Service, uploaded.PendingID, username, uploaded.Resource, uploaded.Data)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+	} else if uploaded.Unpin {
+		stored, err = backend.SetUnpinnedResource(uploaded.Service, username, uploaded.Resource, uploaded.Data)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	} else {
 		stored, err = backend.SetResource(uploaded.Service, username, uploaded.Resource, uploaded.Data)
 		if err != nil {
@@ -131,6 +150,14 @@ type uploadedResource struct {
 	// Resource is the information about the resource.
 	Resource charmresource.Resource
 
+	// PinnedFingerprint is the fingerprint the resource is to be
+	// pinned to, if any.
+	PinnedFingerprint charmresource.Fingerprint
+
+	// Unpin indicates whether the upload may replace a pinned
+	// resource.
+	Unpin bool
+
 	// Data holds the resource blob.
 	Data io.ReadCloser
 }
@@ -141,6 +168,20 @@ func (h *ResourcesHandler) readResource(backend ResourcesBackend, req *http.Requ
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if !uReq.PinnedFingerprint.IsZero() {
+		if uReq.PendingID == "" {
+			return nil, errors.BadRequestf("cannot pin resource %q: resource is not pending", uReq.Name)
+		}
+		if uReq.PinnedFingerprint.String() != uReq.Fingerprint.String() {
+			return nil, errors.BadRequestf(
+				"cannot pin resource %q: uploaded content fingerprint %s does not match pinned fingerprint %s",
+				uReq.Name, uReq.Fingerprint, uReq.PinnedFingerprint,
+			)
+		}
+	}
+	if uReq.Unpin && uReq.PendingID != "" {
+		return nil, errors.BadRequestf("cannot unpin resource %q: resource is pending", uReq.Name)
+	}
 	var res resource.Resource
 	if uReq.PendingID != "" {
 		res, err = backend.GetPendingResource(uReq.Service, uReq.Name, uReq.PendingID)
@@ -164,10 +205,12 @@ func (h *ResourcesHandler) readResource(backend ResourcesBackend, req *http.Requ
 		return nil, errors.Trace(err)
 	}
 	return &uploadedResource{
-		Service:   uReq.Service,
-		PendingID: uReq.PendingID,
-		Resource:  chRes,
-		Data:      req.Body,
+		Service:           uReq.Service,
+		PendingID:         uReq.PendingID,
+		Resource:          chRes,
+		PinnedFingerprint: uReq.PinnedFingerprint,
+		Unpin:             uReq.Unpin,
+		Data:              req.Body,
 	}, nil
 }
 
@@ -208,6 +251,22 @@ func extractUploadRequest(req *http.Request) (api.UploadRequest, error) {
 		return ur, errors.Annotate(err, "invalid fingerprint")
 	}
 
+	var pinned charmresource.Fingerprint
+	if pin := req.Header.Get(api.HeaderPinnedSha384); pin != "" {
+		pinned, err = charmresource.ParseFingerprint(pin)
+		if err != nil {
+			return ur, errors.Annotate(err, "invalid pinned fingerprint")
+		}
+	}
+
+	var unpin bool
+	if raw := req.Header.Get(api.HeaderUnpin); raw != "" {
+		unpin, err = strconv.ParseBool(raw)
+		if err != nil {
+			return ur, errors.Annotate(err, "invalid unpin")
+		}
+	}
+
 	filename, err := extractFilename(req)
 	if err != nil {
 		return ur, errors.Trace(err)
@@ -219,12 +278,14 @@ func extractUploadRequest(req *http.Request) (api.UploadRequest, error) {
 	}
 
 	ur = api.UploadRequest{
-		Service:     service,
-		Name:        name,
-		Filename:    filename,
-		Size:        size,
-		Fingerprint: fp,
-		PendingID:   pendingID,
+		Service:           service,
+		Name:              name,
+		Filename:          filename,
+		Size:              size,
+		Fingerprint:       fp,
+		PendingID:         pendingID,
+		PinnedFingerprint: pinned,
+		Unpin:             unpin,
 	}
 	return ur, nil
 }
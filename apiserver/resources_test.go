@@ -148,6 +148,82 @@ func (s *ResourcesHandlerSuite) TestPutWithPending(c *gc.C) {
 	s.checkResp(c, http.StatusOK, "application/json", string(expected))
 }
 
+func (s *ResourcesHandlerSuite) TestPutPinned(c *gc.C) {
+	uploadContent := "<some data>"
+	res, _ := newResource(c, "spam", "a-user", uploadContent)
+	res.PendingID = "some-unique-id"
+	res.PinnedFingerprint = res.Fingerprint
+	stored, _ := newResource(c, "spam", "", "")
+	stored.PendingID = "some-unique-id"
+	s.backend.ReturnGetPendingResource = stored
+	s.backend.ReturnUpdatePinnedPendingResource = res
+
+	req, _ := newUploadRequest(c, "spam", "a-application", uploadContent)
+	req.URL.RawQuery += "&pendingid=some-unique-id"
+	req.Header.Set("Juju-Pinned-Sha384", req.Header.Get("Content-SHA384"))
+	s.handler.ServeHTTP(s.recorder, req)
+
+	expected := mustMarshalJSON(&params.UploadResult{
+		Resource: api.Resource2API(res),
+	})
+	s.checkResp(c, http.StatusOK, "application/json", string(expected))
+}
+
+func (s *ResourcesHandlerSuite) TestPutPinnedMismatch(c *gc.C) {
+	stored, _ := newResource(c, "spam", "", "")
+	stored.PendingID = "some-unique-id"
+	s.backend.ReturnGetPendingResource = stored
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader("<other data>"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	req, _ := newUploadRequest(c, "spam", "a-application", "<some data>")
+	req.URL.RawQuery += "&pendingid=some-unique-id"
+	req.Header.Set("Juju-Pinned-Sha384", fp.String())
+	s.handler.ServeHTTP(s.recorder, req)
+
+	c.Check(s.recorder.Code, gc.Equals, http.StatusBadRequest)
+	c.Check(s.recorder.Body.String(), jc.Contains, `cannot pin resource \"spam\": uploaded content fingerprint`)
+}
+
+func (s *ResourcesHandlerSuite) TestPutPinnedNotPending(c *gc.C) {
+	stored, _ := newResource(c, "spam", "", "")
+	s.backend.ReturnGetResource = stored
+
+	req, _ := newUploadRequest(c, "spam", "a-application", "<some data>")
+	req.Header.Set("Juju-Pinned-Sha384", req.Header.Get("Content-SHA384"))
+	s.handler.ServeHTTP(s.recorder, req)
+
+	c.Check(s.recorder.Code, gc.Equals, http.StatusBadRequest)
+	c.Check(s.recorder.Body.String(), jc.Contains, `cannot pin resource \"spam\": resource is not pending`)
+}
+
+func (s *ResourcesHandlerSuite) TestPutUnpin(c *gc.C) {
+	uploadContent := "<some data>"
+	res, _ := newResource(c, "spam", "a-user", uploadContent)
+	stored, _ := newResource(c, "spam", "", "")
+	s.backend.ReturnGetResource = stored
+	s.backend.ReturnSetUnpinnedResource = res
+
+	req, _ := newUploadRequest(c, "spam", "a-application", uploadContent)
+	req.Header.Set("Juju-Unpin", "true")
+	s.handler.ServeHTTP(s.recorder, req)
+
+	expected := mustMarshalJSON(&params.UploadResult{
+		Resource: api.Resource2API(res),
+	})
+	s.checkResp(c, http.StatusOK, "application/json", string(expected))
+}
+
+func (s *ResourcesHandlerSuite) TestPutUnpinPending(c *gc.C) {
+	req, _ := newUploadRequest(c, "spam", "a-application", "<some data>")
+	req.URL.RawQuery += "&pendingid=some-unique-id"
+	req.Header.Set("Juju-Unpin", "true")
+	s.handler.ServeHTTP(s.recorder, req)
+
+	c.Check(s.recorder.Code, gc.Equals, http.StatusBadRequest)
+	c.Check(s.recorder.Body.String(), jc.Contains, `cannot unpin resource \"spam\": resource is pending`)
+}
+
 func (s *ResourcesHandlerSuite) TestPutSetResourceFailure(c *gc.C) {
 	content := "<some data>"
 	stored, _ := newResource(c, "spam", "", "")
@@ -181,6 +257,9 @@ type fakeBackend struct {
 	ReturnSetResource           resource.Resource
 	SetResourceErr              error
 	ReturnUpdatePendingResource resource.Resource
+
+	ReturnUpdatePinnedPendingResource resource.Resource
+	ReturnSetUnpinnedResource         resource.Resource
 }
 
 const resourceBody = "body"
@@ -211,6 +290,14 @@ func (s *fakeBackend) UpdatePendingResource(applicationID, pendingID, userID str
 	return s.ReturnUpdatePendingResource, nil
 }
 
+func (s *fakeBackend) UpdatePinnedPendingResource(applicationID, pendingID, userID string, res charmresource.Resource, pin charmresource.Fingerprint, r io.Reader) (resource.Resource, error) {
+	return s.ReturnUpdatePinnedPendingResource, nil
+}
+
+func (s *fakeBackend) SetUnpinnedResource(applicationID, userID string, res charmresource.Resource, r io.Reader) (resource.Resource, error) {
+	return s.ReturnSetUnpinnedResource, nil
+}
+
 func newResource(c *gc.C, name, username, data string) (resource.Resource, params.Resource) {
 	opened := resourcetesting.NewResource(c, nil, name, "a-application", data)
 	res := opened.Resource
@@ -18,6 +18,7 @@ var controllerFacadeNames = set.NewStrings(
 	"ApplicationOffers",
 	"Cloud",
 	"Controller",
+	"FirewallRules",
 	"MigrationTarget",
 	"ModelManager",
 	"UserManager",
@@ -36,6 +36,7 @@ func (s *restrictControllerSuite) TestAllowed(c *gc.C) {
 	s.assertMethod(c, "Bundle", 1, "GetChanges")
 	s.assertMethod(c, "HighAvailability", 2, "EnableHA")
 	s.assertMethod(c, "ApplicationOffers", 1, "ApplicationOffers")
+	s.assertMethod(c, "FirewallRules", 1, "ListFirewallRules")
 }
 
 func (s *restrictControllerSuite) TestNotAllowed(c *gc.C) {
@@ -31,6 +31,15 @@ func (s *restrictModelSuite) TestAllowed(c *gc.C) {
 	s.assertMethod(c, "HighAvailability", 2, "EnableHA")
 }
 
+func (s *restrictModelSuite) TestClientV1MasksNewMethods(c *gc.C) {
+	for _, method := range []string{"SuspendUnits", "ResumeUnits", "AddStatusAnnotations"} {
+		s.assertMethod(c, "Client", 2, method)
+		caller, err := s.root.FindMethod("Client", 1, method)
+		c.Check(err, gc.ErrorMatches, `no such request - method Client\(1\)\.`+method+` is not implemented`)
+		c.Check(caller, gc.IsNil)
+	}
+}
+
 func (s *restrictModelSuite) TestBlocked(c *gc.C) {
 	caller, err := s.root.FindMethod("ModelManager", 2, "ListModels")
 	c.Assert(err, gc.ErrorMatches, `facade "ModelManager" not supported for model API connection`)
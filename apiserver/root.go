@@ -59,6 +59,10 @@ type apiHandler struct {
 	// serverHost is the host:port of the API server that the client
 	// connected to.
 	serverHost string
+
+	// agentRequest is true if the client connected through the
+	// server's agent listener.
+	agentRequest bool
 }
 
 var _ = (*apiHandler)(nil)
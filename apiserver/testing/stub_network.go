@@ -589,6 +589,11 @@ func (se *StubEnviron) GoString() string {
 	return "&StubEnviron{}"
 }
 
+// Capabilities implements environs.Environ.
+func (se *StubEnviron) Capabilities() (environs.Capabilities, error) {
+	return environs.Capabilities{}, nil
+}
+
 // StubZonedEnviron is used in tests where providercommon.ZonedEnviron
 // is needed.
 type StubZonedEnviron struct {
@@ -612,6 +617,11 @@ func (se *StubZonedEnviron) GoString() string {
 	return "&StubZonedEnviron{}"
 }
 
+// Capabilities implements environs.Environ.
+func (se *StubZonedEnviron) Capabilities() (environs.Capabilities, error) {
+	return environs.Capabilities{AvailabilityZones: true}, nil
+}
+
 // StubNetworkingEnviron is used in tests where
 // environs.NetworkingEnviron is needed.
 type StubNetworkingEnviron struct {
@@ -638,6 +648,11 @@ func (se *StubNetworkingEnviron) SupportsSpaces() (bool, error) {
 	return true, nil
 }
 
+// Capabilities implements environs.Environ.
+func (se *StubNetworkingEnviron) Capabilities() (environs.Capabilities, error) {
+	return environs.Capabilities{}, nil
+}
+
 // GoString implements fmt.GoStringer.
 func (se *StubNetworkingEnviron) GoString() string {
 	return "&StubNetworkingEnviron{}"
@@ -667,6 +682,11 @@ func (se *StubZonedNetworkingEnviron) SupportsSpaces() (bool, error) {
 	return true, nil
 }
 
+// Capabilities implements environs.Environ.
+func (se *StubZonedNetworkingEnviron) Capabilities() (environs.Capabilities, error) {
+	return environs.Capabilities{AvailabilityZones: true}, nil
+}
+
 func (se *StubZonedNetworkingEnviron) Subnets(instId instance.Id, subIds []network.Id) ([]network.SubnetInfo, error) {
 	se.MethodCall(se, "Subnets", instId, subIds)
 	if err := se.NextErr(); err != nil {
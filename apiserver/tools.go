@@ -35,7 +35,8 @@ type toolsUploadHandler struct {
 
 // toolsHandler handles tool download through HTTPS in the API server.
 type toolsDownloadHandler struct {
-	ctxt httpContext
+	ctxt   httpContext
+	deltas *toolsDeltaCache
 }
 
 func (h *toolsDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -50,6 +51,18 @@ func (h *toolsDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	switch r.Method {
 	case "GET":
+		if r.URL.Query().Get("delta-from") != "" {
+			delta, err := h.processGetDelta(r, st)
+			if err == nil {
+				if err := h.sendToolsDelta(w, delta); err != nil {
+					logger.Errorf("%v", err)
+				}
+				return
+			}
+			// The agent asking for a delta will accept the
+			// full tarball instead.
+			logger.Infof("GET(%s) sending full tools: %v", r.URL, err)
+		}
 		tarball, err := h.processGet(r, st)
 		if err != nil {
 			logger.Errorf("GET(%s) failed: %v", r.URL, err)
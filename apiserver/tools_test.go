@@ -4,14 +4,17 @@
 package apiserver_test
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/errors"
@@ -24,6 +27,7 @@ import (
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
 
+	agenttools "github.com/juju/juju/agent/tools"
 	apiauthentication "github.com/juju/juju/api/authentication"
 	apitesting "github.com/juju/juju/api/testing"
 	commontesting "github.com/juju/juju/apiserver/common/testing"
@@ -535,6 +539,65 @@ func (s *toolsSuite) testDownload(c *gc.C, tools *coretools.Tools, uuid string)
 	return data
 }
 
+// storeDeltaTools stores tools tarballs for two versions whose jujud
+// binaries differ only slightly, and returns the tools and tarballs.
+func (s *toolsSuite) storeDeltaTools(c *gc.C) (base, target *coretools.Tools, baseData, targetData []byte) {
+	jujud := make([]byte, 64*1024)
+	rand.New(rand.NewSource(1)).Read(jujud)
+	baseData, baseSHA256 := testing.TarGz(testing.NewTarFile("jujud", 0755, string(jujud)))
+	targetData, targetSHA256 := testing.TarGz(testing.NewTarFile("jujud", 0755, string(jujud)+"more"))
+	base = s.storeFakeTools(c, s.State, string(baseData), binarystorage.Metadata{
+		Version: "2.2.0-trusty-amd64",
+		Size:    int64(len(baseData)),
+		SHA256:  baseSHA256,
+	})
+	target = s.storeFakeTools(c, s.State, string(targetData), binarystorage.Metadata{
+		Version: "2.2.1-trusty-amd64",
+		Size:    int64(len(targetData)),
+		SHA256:  targetSHA256,
+	})
+	return base, target, baseData, targetData
+}
+
+func (s *toolsSuite) deltaRequest(c *gc.C, target, base version.Binary) *http.Response {
+	url := s.toolsURL(c, "delta-from="+base.String())
+	url.Path = fmt.Sprintf("/model/%s/tools/%s", s.State.ModelUUID(), target)
+	return s.sendRequest(c, httpRequestParams{method: "GET", url: url.String()})
+}
+
+func (s *toolsSuite) TestDownloadDelta(c *gc.C) {
+	base, target, baseData, targetData := s.storeDeltaTools(c)
+	resp := s.deltaRequest(c, target.Version, base.Version)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, agenttools.DeltaContentType)
+	delta, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(len(delta) < len(targetData), jc.IsTrue)
+
+	// The delta makes the target tools from the base tools.
+	dataDir := c.MkDir()
+	err = agenttools.UnpackTools(dataDir, base, bytes.NewReader(baseData))
+	c.Assert(err, jc.ErrorIsNil)
+	err = agenttools.UnpackToolsDelta(dataDir, base.Version, target, bytes.NewReader(delta))
+	c.Assert(err, jc.ErrorIsNil)
+	jujud, err := ioutil.ReadFile(filepath.Join(agenttools.SharedToolsDir(dataDir, target.Version), "jujud"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.HasSuffix(string(jujud), "more"), jc.IsTrue)
+}
+
+func (s *toolsSuite) TestDownloadDeltaUnknownBase(c *gc.C) {
+	_, target, _, _ := s.storeDeltaTools(c)
+	resp := s.deltaRequest(c, target.Version, version.MustParseBinary("2.1.0-trusty-amd64"))
+	defer resp.Body.Close()
+	// The full tarball is sent instead.
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "application/x-tar-gz")
+	data, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.HasLen, int(target.Size))
+}
+
 func (s *toolsSuite) TestDownloadRejectsWrongModelUUIDPath(c *gc.C) {
 	current := version.Binary{
 		Number: jujuversion.Current,
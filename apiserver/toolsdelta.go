@@ -0,0 +1,179 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	agenttools "github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/state"
+)
+
+// maxCachedToolsDeltas is the number of tools deltas kept in memory by
+// a toolsDeltaCache.
+const maxCachedToolsDeltas = 4
+
+// toolsDeltaCache holds recently computed tools deltas. When many
+// agents upgrade at once they all ask for the same delta, which is
+// then computed only once.
+type toolsDeltaCache struct {
+	mu      sync.Mutex
+	entries map[string]*toolsDeltaEntry
+	order   []string
+}
+
+type toolsDeltaEntry struct {
+	ready chan struct{}
+	delta []byte
+	err   error
+}
+
+func newToolsDeltaCache() *toolsDeltaCache {
+	return &toolsDeltaCache{entries: make(map[string]*toolsDeltaEntry)}
+}
+
+// get returns the delta with the given key, calling compute to make it
+// if it is not cached. Concurrent calls for the same key wait for the
+// first to compute the delta.
+func (c *toolsDeltaCache) get(key string, compute func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		<-e.ready
+		return e.delta, e.err
+	}
+	e := &toolsDeltaEntry{ready: make(chan struct{})}
+	c.entries[key] = e
+	c.order = append(c.order, key)
+	if len(c.order) > maxCachedToolsDeltas {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.mu.Unlock()
+
+	e.delta, e.err = compute()
+	close(e.ready)
+	if e.err != nil {
+		// Don't keep failures; the next request may succeed.
+		c.mu.Lock()
+		if c.entries[key] == e {
+			delete(c.entries, key)
+			for i, k := range c.order {
+				if k == key {
+					c.order = append(c.order[:i], c.order[i+1:]...)
+					break
+				}
+			}
+		}
+		c.mu.Unlock()
+	}
+	return e.delta, e.err
+}
+
+// processGetDelta handles a tools GET request for a delta from the
+// tools with the version in the request's delta-from parameter, which
+// the requesting agent has installed. Both the requested tools and the
+// base tools must be in tools storage.
+func (h *toolsDownloadHandler) processGetDelta(r *http.Request, st *state.State) ([]byte, error) {
+	query := r.URL.Query()
+	targetVersion, err := version.ParseBinary(query.Get(":version"))
+	if err != nil {
+		return nil, errors.Annotate(err, "error parsing version")
+	}
+	baseVersion, err := version.ParseBinary(query.Get("delta-from"))
+	if err != nil {
+		return nil, errors.Annotate(err, "error parsing delta-from version")
+	}
+	storage, err := st.ToolsStorage()
+	if err != nil {
+		return nil, errors.Annotate(err, "error getting tools storage")
+	}
+	defer storage.Close()
+	baseMetadata, base, err := storage.Open(baseVersion.String())
+	if err != nil {
+		return nil, errors.Annotatef(err, "error opening %v tools", baseVersion)
+	}
+	defer base.Close()
+	targetMetadata, target, err := storage.Open(targetVersion.String())
+	if err != nil {
+		return nil, errors.Annotatef(err, "error opening %v tools", targetVersion)
+	}
+	defer target.Close()
+
+	key := baseMetadata.SHA256 + "-" + targetMetadata.SHA256
+	delta, err := h.deltas.get(key, func() ([]byte, error) {
+		logger.Infof("computing tools delta from %v to %v", baseVersion, targetVersion)
+		return computeToolsDelta(base, target, targetMetadata.Size)
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "error computing tools delta")
+	}
+	if delta == nil {
+		return nil, errors.Errorf("tools delta is no smaller than the tools")
+	}
+	return delta, nil
+}
+
+// computeToolsDelta returns a delta that makes the tools tarball
+// target, which holds targetSize bytes, from the tarball base. Neither
+// tarball is held in memory: the base, which the delta is computed
+// against at random, is spooled to disk, and the target is read as the
+// delta is written. computeToolsDelta returns nil if the delta would
+// be no smaller than the target.
+func computeToolsDelta(base, target io.Reader, targetSize int64) ([]byte, error) {
+	baseFile, err := ioutil.TempFile("", "tools-delta-base")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer os.Remove(baseFile.Name())
+	defer baseFile.Close()
+	baseSize, err := io.Copy(baseFile, base)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading base tools")
+	}
+	delta := &limitedBuffer{limit: targetSize}
+	err = agenttools.WriteToolsDelta(delta, baseFile, baseSize, target)
+	if errors.Cause(err) == errBufferFull {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return delta.Bytes(), nil
+}
+
+var errBufferFull = errors.New("buffer full")
+
+// limitedBuffer is a bytes.Buffer that refuses to hold limit or more
+// bytes.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int64
+}
+
+// Write is part of the io.Writer interface.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if int64(b.Len()+len(p)) >= b.limit {
+		return 0, errBufferFull
+	}
+	return b.Buffer.Write(p)
+}
+
+// sendToolsDelta sends a tools delta to the client.
+func (h *toolsDownloadHandler) sendToolsDelta(w http.ResponseWriter, delta []byte) error {
+	w.Header().Set("Content-Type", agenttools.DeltaContentType)
+	w.Header().Set("Content-Length", fmt.Sprint(len(delta)))
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(delta)
+	return errors.Annotate(err, "failed to write tools delta")
+}
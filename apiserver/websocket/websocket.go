@@ -97,3 +97,13 @@ func (conn *Conn) SendInitialErrorV0(err error) error {
 
 	return errors.Trace(err)
 }
+
+// SendCloseNormal tells the other end that the stream is complete,
+// by sending a close message with a normal closure status. This lets
+// clients tell a stream that has finished from one whose connection
+// was lost.
+func (conn *Conn) SendCloseNormal() error {
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(WriteWait))
+	return errors.Trace(err)
+}
@@ -64,6 +64,11 @@ type InstanceConfig struct {
 	// or be empty when starting a controller.
 	APIInfo *api.Info
 
+	// ControllerAPIPorts holds the ports on which the controller's API
+	// servers listen, for instances that are not controllers. See
+	// APIPorts.
+	ControllerAPIPorts []int
+
 	// ControllerTag identifies the controller.
 	ControllerTag names.ControllerTag
 
@@ -388,7 +393,14 @@ func (cfg *InstanceConfig) ToolsDir(renderer shell.Renderer) string {
 }
 
 func (cfg *InstanceConfig) InitService(renderer shell.Renderer) (service.Service, error) {
-	conf := service.AgentConf(cfg.agentInfo(), renderer)
+	// A controller's agent serves the API from its database, so it
+	// starts after the database's service.
+	var conf common.Conf
+	if multiwatcher.AnyJobNeedsState(cfg.Jobs...) {
+		conf = service.ControllerAgentConf(cfg.agentInfo(), renderer, mongo.ServiceName)
+	} else {
+		conf = service.AgentConf(cfg.agentInfo(), renderer)
+	}
 
 	name := cfg.MachineAgentServiceName
 	svc, err := newService(name, conf, cfg.Series)
@@ -492,6 +504,26 @@ func (cfg *InstanceConfig) APIHosts() []string {
 	return hosts
 }
 
+// APIPorts returns the ports on which the controller's API servers
+// listen, which providers open in the firewall rules shared by the
+// model's machines. They are the controller's API and agent API ports
+// if the instance is a controller, and otherwise ControllerAPIPorts or,
+// failing that, the port of the instance's API addresses.
+func (cfg *InstanceConfig) APIPorts() []int {
+	if cfg.Controller != nil {
+		return cfg.Controller.Config.APIPorts()
+	}
+	if len(cfg.ControllerAPIPorts) > 0 {
+		return cfg.ControllerAPIPorts
+	}
+	// All ports are the same so pick the first.
+	ports := cfg.APIInfo.Ports()
+	if len(ports) > 1 {
+		ports = ports[:1]
+	}
+	return ports
+}
+
 // AgentVersion returns the version of the Juju agent that will be configured
 // on the instance. The zero value will be returned if there are no tools set.
 func (cfg *InstanceConfig) AgentVersion() version.Binary {
@@ -8,7 +8,9 @@ import (
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/testing"
@@ -53,6 +55,21 @@ func testInstanceTags(c *gc.C, cfg *config.Config, jobs []multiwatcher.MachineJo
 	c.Assert(tags, jc.DeepEquals, expectTags)
 }
 
+func (*instancecfgSuite) TestAPIPorts(c *gc.C) {
+	icfg := &instancecfg.InstanceConfig{
+		APIInfo: &api.Info{Addrs: []string{"10.0.0.1:17071", "10.0.0.2:17071"}},
+	}
+	c.Assert(icfg.APIPorts(), jc.DeepEquals, []int{17071})
+
+	icfg.ControllerAPIPorts = []int{17070, 17071}
+	c.Assert(icfg.APIPorts(), jc.DeepEquals, []int{17070, 17071})
+
+	icfg.Controller = &instancecfg.ControllerConfig{
+		Config: controller.Config{"api-port": 17777, "agent-api-port": 17778},
+	}
+	c.Assert(icfg.APIPorts(), jc.DeepEquals, []int{17777, 17778})
+}
+
 func (*instancecfgSuite) TestAgentVersionZero(c *gc.C) {
 	var icfg instancecfg.InstanceConfig
 	c.Assert(icfg.AgentVersion(), gc.Equals, version.Binary{})
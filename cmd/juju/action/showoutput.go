@@ -38,6 +38,10 @@ to wait indefinitely.  If units are left off, seconds are assumed.
 The default behavior without --wait is to immediately check and return; if
 the results are "pending" then only the available information will be
 displayed.  This is also the behavior when any negative time is given.
+
+Messages logged by the action with "juju-log --action" are shown as they
+are logged, so the log of a running action may be followed by showing its
+output again.
 `
 
 // Set up the output.
@@ -204,6 +208,13 @@ func FormatActionResult(result params.ActionResult) map[string]interface{} {
 	if len(result.Output) != 0 {
 		response["results"] = result.Output
 	}
+	if len(result.Log) != 0 {
+		logs := make([]string, len(result.Log))
+		for i, m := range result.Log {
+			logs[i] = m.Timestamp.String() + " " + m.Message
+		}
+		response["log"] = logs
+	}
 
 	if result.Enqueued.IsZero() && result.Started.IsZero() && result.Completed.IsZero() {
 		return response
@@ -265,6 +265,32 @@ status: completed
 timing:
   completed: 2015-02-14 08:15:30 +0000 UTC
   enqueued: 2015-02-14 08:13:00 +0000 UTC
+`[1:],
+	}, {
+		should:            "show messages logged by a running action",
+		withClientQueryID: validActionId,
+		withAPITimeout:    10 * time.Second,
+		withTags:          tagsForIdPrefix(validActionId, validActionTagString),
+		withAPIResponse: []params.ActionResult{{
+			Status: "running",
+			Log: []params.ActionMessage{{
+				Timestamp: time.Date(2015, time.February, 14, 8, 15, 0, 0, time.UTC),
+				Message:   "copying files",
+			}, {
+				Timestamp: time.Date(2015, time.February, 14, 8, 15, 10, 0, time.UTC),
+				Message:   "restarting",
+			}},
+			Enqueued: time.Date(2015, time.February, 14, 8, 13, 0, 0, time.UTC),
+			Started:  time.Date(2015, time.February, 14, 8, 14, 0, 0, time.UTC),
+		}},
+		expectedOutput: `
+log:
+- 2015-02-14 08:15:00 +0000 UTC copying files
+- 2015-02-14 08:15:10 +0000 UTC restarting
+status: running
+timing:
+  enqueued: 2015-02-14 08:13:00 +0000 UTC
+  started: 2015-02-14 08:14:00 +0000 UTC
 `[1:],
 	}}
 
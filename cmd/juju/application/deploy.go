@@ -287,6 +287,10 @@ type DeployCommand struct {
 	// Resources is a map of resource name to filename to be uploaded on deploy.
 	Resources map[string]string
 
+	// PinResources indicates whether uploaded resources are pinned to
+	// the checksums of their files.
+	PinResources bool
+
 	Bindings map[string]string
 	Steps    []DeployStep
 
@@ -354,6 +358,9 @@ than one resource.
   juju deploy foo --resource bar=/some/file.tgz --resource baz=./docs/cfg.xml
 
 Where 'bar' and 'baz' are resources named in the metadata for the 'foo' charm.
+With '--pin-resources', each uploaded resource is pinned to the checksum of its
+file: units refuse to use the resource if its content does not match, and
+` + "`juju attach-resource`" + ` only replaces it when given '--unpin'.
 
 When using a placement directive to deploy to an existing machine or container
 ('--to' option), the ` + "`juju status`" + ` command should be used for guidance. A few
@@ -463,6 +470,7 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Force, "force", false, "Allow a charm to be deployed to a machine running an unsupported series")
 	f.Var(storageFlag{&c.Storage, &c.BundleStorage}, "storage", "Charm storage constraints")
 	f.Var(stringMap{&c.Resources}, "resource", "Resource to be uploaded to the controller")
+	f.BoolVar(&c.PinResources, "pin-resources", false, "Pin uploaded resources to the checksums of their files")
 	f.StringVar(&c.BindToSpaces, "bind", "", "Configure application endpoint bindings to spaces")
 
 	for _, step := range c.Steps {
@@ -612,7 +620,11 @@ func (c *DeployCommand) deployCharm(
 			strings.Join(charmInfo.Meta.Terms, " "))
 	}
 
-	ids, err := resourceadapters.DeployResources(
+	deployResources := resourceadapters.DeployResources
+	if c.PinResources {
+		deployResources = resourceadapters.DeployPinnedResources
+	}
+	ids, err := deployResources(
 		serviceName,
 		id,
 		csMac,
@@ -5,6 +5,7 @@ package application
 
 import (
 	"github.com/juju/cmd"
+	"github.com/juju/utils/clock"
 	"gopkg.in/juju/charmrepo.v2-unstable/csclient"
 	"gopkg.in/macaroon-bakery.v1/httpbakery"
 
@@ -46,6 +47,33 @@ func NewAddUnitCommandForTest(api serviceAddUnitAPI) cmd.Command {
 	})
 }
 
+// NewRebalanceCommandForTest returns a RebalanceCommand with the api and
+// clock provided as specified.
+func NewRebalanceCommandForTest(api rebalanceAPI, clock clock.Clock) cmd.Command {
+	return modelcmd.Wrap(&rebalanceCommand{
+		api:   api,
+		clock: clock,
+	})
+}
+
+// NewSuspendUnitCommandForTest returns a SuspendUnitCommand with the api
+// provided as specified.
+func NewSuspendUnitCommandForTest(api suspendUnitAPI) cmd.Command {
+	return modelcmd.Wrap(&suspendUnitCommand{suspend: true, api: api})
+}
+
+// NewResumeUnitCommandForTest returns a ResumeUnitCommand with the api
+// provided as specified.
+func NewResumeUnitCommandForTest(api suspendUnitAPI) cmd.Command {
+	return modelcmd.Wrap(&suspendUnitCommand{suspend: false, api: api})
+}
+
+// NewTrustCommandForTest returns a TrustCommand with the api provided
+// as specified.
+func NewTrustCommandForTest(api trustAPI) cmd.Command {
+	return modelcmd.Wrap(&trustCommand{api: api})
+}
+
 // NewAddRelationCommandForTest returns an AddRelationCommand with the api provided as specified.
 func NewAddRelationCommandForTest(addAPI applicationAddRelationAPI, consumeAPI applicationConsumeDetailsAPI) modelcmd.ModelCommand {
 	cmd := &addRelationCommand{addRelationAPI: addAPI, consumeDetailsAPI: consumeAPI}
@@ -0,0 +1,499 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/status"
+)
+
+var usageRebalanceSummary = `
+Redistributes an application's units across availability zones.`[1:]
+
+var usageRebalanceDetails = `
+The rebalance command computes an even distribution of the units of an
+application across the availability zones in use by the model, and then
+moves units from over-populated zones to under-populated ones.
+
+Units are moved one at a time. Each move adds a replacement unit in the
+target zone, waits for it to become ready, and only then removes the
+original unit. Before each move, the application and every application
+related to it must be free of units in an error state; otherwise the
+rebalance stops, leaving the remaining units untouched. The leader unit
+is always moved last, to minimise leadership churn.
+
+The set of availability zones is taken from the zones reported by the
+provider for the model's machines. Use --zones to supply the zones
+explicitly, for example to spread units onto a zone that is not yet in
+use.
+
+Examples:
+
+Show what would be moved, without making any changes:
+    juju rebalance --application mysql --dry-run
+
+Rebalance the mysql units across all known zones:
+    juju rebalance --application mysql
+
+Rebalance across an explicit set of zones:
+    juju rebalance --application mysql --zones us-east-1a,us-east-1b,us-east-1c
+
+See also:
+    add-unit
+    remove-unit`[1:]
+
+const (
+	defaultRebalanceTimeout = 30 * time.Minute
+	rebalancePollInterval   = 5 * time.Second
+)
+
+// NewRebalanceCommand returns a command which redistributes the units
+// of an application across availability zones.
+func NewRebalanceCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&rebalanceCommand{clock: clock.WallClock})
+}
+
+// rebalanceAPI defines the methods on the client API that the
+// rebalance command calls.
+type rebalanceAPI interface {
+	Close() error
+	ModelUUID() string
+	Status(patterns []string) (*params.FullStatus, error)
+	AddUnits(application.AddUnitsParams) ([]string, error)
+	DestroyUnits(unitNames ...string) ([]params.DestroyUnitResult, error)
+}
+
+// rebalanceCommand is responsible for redistributing the units of an
+// application across availability zones.
+type rebalanceCommand struct {
+	modelcmd.ModelCommandBase
+
+	ApplicationName string
+	Zones           []string
+	DryRun          bool
+	Timeout         time.Duration
+
+	zonesSpec string
+	api       rebalanceAPI
+	clock     clock.Clock
+}
+
+func (c *rebalanceCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "rebalance",
+		Purpose: usageRebalanceSummary,
+		Doc:     usageRebalanceDetails,
+	}
+}
+
+func (c *rebalanceCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.ApplicationName, "application", "", "The application whose units should be rebalanced")
+	f.StringVar(&c.zonesSpec, "zones", "", "Comma separated list of availability zones to spread units across")
+	f.BoolVar(&c.DryRun, "dry-run", false, "Show the planned moves without making any changes")
+	f.DurationVar(&c.Timeout, "timeout", defaultRebalanceTimeout, "How long to wait for each replacement unit to become ready")
+}
+
+func (c *rebalanceCommand) Init(args []string) error {
+	if c.ApplicationName == "" {
+		return errors.New("no application specified")
+	}
+	if !names.IsValidApplication(c.ApplicationName) {
+		return errors.NotValidf("application name %q", c.ApplicationName)
+	}
+	if c.zonesSpec != "" {
+		for _, zone := range strings.Split(c.zonesSpec, ",") {
+			zone = strings.TrimSpace(zone)
+			if zone == "" {
+				return errors.Errorf("invalid --zones value %q", c.zonesSpec)
+			}
+			c.Zones = append(c.Zones, zone)
+		}
+	}
+	if c.Timeout <= 0 {
+		return errors.New("--timeout must be positive")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *rebalanceCommand) getAPI() (rebalanceAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &rebalanceAPIAdaptor{
+		Client:     application.NewClient(root),
+		statusFunc: root.Client().Status,
+	}, nil
+}
+
+// rebalanceAPIAdaptor combines the application and client facades
+// into the single rebalanceAPI used by the command.
+type rebalanceAPIAdaptor struct {
+	*application.Client
+	statusFunc func([]string) (*params.FullStatus, error)
+}
+
+func (a *rebalanceAPIAdaptor) Status(patterns []string) (*params.FullStatus, error) {
+	return a.statusFunc(patterns)
+}
+
+// Run connects to the model specified on the command line, computes
+// a rebalancing plan for the application, and carries it out.
+func (c *rebalanceCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer apiclient.Close()
+
+	fullStatus, err := apiclient.Status(nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	placements, err := unitPlacements(fullStatus, c.ApplicationName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	zones := c.Zones
+	if len(zones) == 0 {
+		zones = modelZones(fullStatus)
+	}
+	if len(zones) < 2 {
+		return errors.New("at least two availability zones are required to rebalance; use --zones to specify them")
+	}
+	moves, err := planRebalance(placements, zones)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(moves) == 0 {
+		ctx.Infof("application %q is already balanced", c.ApplicationName)
+		return nil
+	}
+	for _, move := range moves {
+		ctx.Infof("%s: %s -> %s", move.Unit, move.FromZone, move.ToZone)
+	}
+	if c.DryRun {
+		return nil
+	}
+	for _, move := range moves {
+		if err := c.moveUnit(ctx, apiclient, move); err != nil {
+			return errors.Annotatef(err, "moving unit %s to zone %s", move.Unit, move.ToZone)
+		}
+	}
+	return nil
+}
+
+// moveUnit replaces a single unit with a new one in the target zone.
+func (c *rebalanceCommand) moveUnit(ctx *cmd.Context, apiclient rebalanceAPI, move rebalanceMove) error {
+	fullStatus, err := apiclient.Status(nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := checkRelatedHealthy(fullStatus, c.ApplicationName); err != nil {
+		return errors.Trace(err)
+	}
+	added, err := apiclient.AddUnits(application.AddUnitsParams{
+		ApplicationName: c.ApplicationName,
+		NumUnits:        1,
+		Placement: []*instance.Placement{{
+			Scope:     apiclient.ModelUUID(),
+			Directive: "zone=" + move.ToZone,
+		}},
+	})
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if len(added) != 1 {
+		return errors.Errorf("expected 1 unit to be added, got %d", len(added))
+	}
+	newUnit := added[0]
+	ctx.Infof("added unit %s, waiting for it to become ready", newUnit)
+	if err := c.waitForUnitReady(apiclient, newUnit); err != nil {
+		return errors.Trace(err)
+	}
+	results, err := apiclient.DestroyUnits(move.Unit)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	if len(results) == 1 && results[0].Error != nil {
+		return errors.Annotatef(results[0].Error, "removing unit %s", move.Unit)
+	}
+	ctx.Infof("removing unit %s", move.Unit)
+	return nil
+}
+
+// waitForUnitReady polls the model status until the named unit's agent
+// is idle and its workload is settled, or the timeout expires. Charms
+// need not set a workload status, and may legitimately report blocked
+// or waiting, so any workload status other than error or maintenance
+// is considered settled.
+func (c *rebalanceCommand) waitForUnitReady(apiclient rebalanceAPI, unitName string) error {
+	timeout := c.clock.After(c.Timeout)
+	for {
+		fullStatus, err := apiclient.Status(nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		unit, ok := fullStatus.Applications[c.ApplicationName].Units[unitName]
+		if ok {
+			if unit.WorkloadStatus.Status == status.Error.String() || unit.AgentStatus.Status == status.Error.String() {
+				return errors.Errorf("unit %s is in error state", unitName)
+			}
+			if unit.AgentStatus.Status == status.Idle.String() && unit.WorkloadStatus.Status != status.Maintenance.String() {
+				return nil
+			}
+		}
+		select {
+		case <-timeout:
+			return errors.Errorf("timed out waiting for unit %s to become ready", unitName)
+		case <-c.clock.After(rebalancePollInterval):
+		}
+	}
+}
+
+// unitPlacement records the availability zone of a unit's machine.
+type unitPlacement struct {
+	Unit   string
+	Zone   string
+	Leader bool
+}
+
+// rebalanceMove describes moving a single unit between zones.
+type rebalanceMove struct {
+	Unit     string
+	FromZone string
+	ToZone   string
+}
+
+// unitPlacements returns the zone placement of each unit of the
+// named application.
+func unitPlacements(fullStatus *params.FullStatus, appName string) ([]unitPlacement, error) {
+	app, ok := fullStatus.Applications[appName]
+	if !ok {
+		return nil, errors.NotFoundf("application %q", appName)
+	}
+	if len(app.SubordinateTo) > 0 {
+		return nil, errors.Errorf("cannot rebalance subordinate application %q", appName)
+	}
+	var result []unitPlacement
+	for unitName, unit := range app.Units {
+		machine, ok := findMachine(fullStatus.Machines, unit.Machine)
+		if !ok {
+			return nil, errors.Errorf("unit %s is not assigned to a machine", unitName)
+		}
+		zone := hardwareZone(machine.Hardware)
+		if zone == "" {
+			return nil, errors.Errorf("availability zone of machine %s hosting unit %s is not known", unit.Machine, unitName)
+		}
+		result = append(result, unitPlacement{
+			Unit:   unitName,
+			Zone:   zone,
+			Leader: unit.Leader,
+		})
+	}
+	sort.Sort(placementsByUnit(result))
+	return result, nil
+}
+
+// findMachine finds the status of the machine or container with the
+// given id.
+func findMachine(machines map[string]params.MachineStatus, id string) (params.MachineStatus, bool) {
+	for machineId, machine := range machines {
+		if machineId == id {
+			return machine, true
+		}
+		if m, ok := findMachine(machine.Containers, id); ok {
+			// Containers share the availability zone of their host.
+			if m.Hardware == "" {
+				m.Hardware = machine.Hardware
+			}
+			return m, true
+		}
+	}
+	return params.MachineStatus{}, false
+}
+
+// modelZones returns the sorted set of availability zones reported for
+// the machines in the model.
+func modelZones(fullStatus *params.FullStatus) []string {
+	zones := set.NewStrings()
+	for _, machine := range fullStatus.Machines {
+		if zone := hardwareZone(machine.Hardware); zone != "" {
+			zones.Add(zone)
+		}
+	}
+	return zones.SortedValues()
+}
+
+// hardwareZone extracts the availability zone from a machine's
+// hardware characteristics string.
+func hardwareZone(hardware string) string {
+	for _, field := range strings.Fields(hardware) {
+		if strings.HasPrefix(field, "availability-zone=") {
+			return strings.TrimPrefix(field, "availability-zone=")
+		}
+	}
+	return ""
+}
+
+// planRebalance computes the unit moves required to distribute units
+// evenly across the given zones, such that no zone holds more than one
+// unit more than any other. Non-leader units are moved first.
+func planRebalance(placements []unitPlacement, zones []string) ([]rebalanceMove, error) {
+	if len(zones) == 0 {
+		return nil, errors.New("no availability zones specified")
+	}
+	byZone := make(map[string][]unitPlacement)
+	for _, zone := range zones {
+		byZone[zone] = nil
+	}
+	for _, p := range placements {
+		byZone[p.Zone] = append(byZone[p.Zone], p)
+	}
+	targets := set.NewStrings(zones...)
+	// Units in zones outside the requested set are moved first,
+	// regardless of balance.
+	var moves []rebalanceMove
+	pickZone := func() string {
+		var best string
+		for _, zone := range zones {
+			if best == "" || len(byZone[zone]) < len(byZone[best]) {
+				best = zone
+			}
+		}
+		return best
+	}
+	for _, zone := range sortedZones(byZone) {
+		if targets.Contains(zone) {
+			continue
+		}
+		for _, p := range leadersLast(byZone[zone]) {
+			to := pickZone()
+			moves = append(moves, rebalanceMove{Unit: p.Unit, FromZone: zone, ToZone: to})
+			byZone[to] = append(byZone[to], p)
+		}
+		delete(byZone, zone)
+	}
+	for {
+		var most, least string
+		for _, zone := range zones {
+			if most == "" || len(byZone[zone]) > len(byZone[most]) {
+				most = zone
+			}
+			if least == "" || len(byZone[zone]) < len(byZone[least]) {
+				least = zone
+			}
+		}
+		if len(byZone[most])-len(byZone[least]) <= 1 {
+			break
+		}
+		candidates := leadersLast(byZone[most])
+		p := candidates[0]
+		moves = append(moves, rebalanceMove{Unit: p.Unit, FromZone: most, ToZone: least})
+		byZone[most] = candidates[1:]
+		byZone[least] = append(byZone[least], p)
+	}
+	// Moving the leader triggers a leadership election, so do it last.
+	leaders := set.NewStrings()
+	for _, p := range placements {
+		if p.Leader {
+			leaders.Add(p.Unit)
+		}
+	}
+	sort.Stable(movesLeadersLast{moves, leaders})
+	return moves, nil
+}
+
+func sortedZones(byZone map[string][]unitPlacement) []string {
+	zones := make([]string, 0, len(byZone))
+	for zone := range byZone {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// leadersLast returns the placements sorted by unit name, with the
+// leader unit at the end.
+func leadersLast(placements []unitPlacement) []unitPlacement {
+	result := make([]unitPlacement, len(placements))
+	copy(result, placements)
+	sort.Sort(placementsLeadersLast(result))
+	return result
+}
+
+type placementsByUnit []unitPlacement
+
+func (p placementsByUnit) Len() int           { return len(p) }
+func (p placementsByUnit) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p placementsByUnit) Less(i, j int) bool { return p[i].Unit < p[j].Unit }
+
+type placementsLeadersLast []unitPlacement
+
+func (p placementsLeadersLast) Len() int      { return len(p) }
+func (p placementsLeadersLast) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p placementsLeadersLast) Less(i, j int) bool {
+	if p[i].Leader != p[j].Leader {
+		return !p[i].Leader
+	}
+	return p[i].Unit < p[j].Unit
+}
+
+type movesLeadersLast struct {
+	moves   []rebalanceMove
+	leaders set.Strings
+}
+
+func (m movesLeadersLast) Len() int      { return len(m.moves) }
+func (m movesLeadersLast) Swap(i, j int) { m.moves[i], m.moves[j] = m.moves[j], m.moves[i] }
+func (m movesLeadersLast) Less(i, j int) bool {
+	return !m.leaders.Contains(m.moves[i].Unit) && m.leaders.Contains(m.moves[j].Unit)
+}
+
+// checkRelatedHealthy returns an error if any unit of the application,
+// or of the applications related to it, is in an error state. Moving
+// units while the relation graph is unhealthy risks compounding the
+// failure.
+func checkRelatedHealthy(fullStatus *params.FullStatus, appName string) error {
+	app, ok := fullStatus.Applications[appName]
+	if !ok {
+		return errors.NotFoundf("application %q", appName)
+	}
+	check := set.NewStrings(appName)
+	for _, related := range app.Relations {
+		check = check.Union(set.NewStrings(related...))
+	}
+	var failing []string
+	for _, name := range check.SortedValues() {
+		for unitName, unit := range fullStatus.Applications[name].Units {
+			if unit.WorkloadStatus.Status == status.Error.String() || unit.AgentStatus.Status == status.Error.String() {
+				failing = append(failing, unitName)
+			}
+		}
+	}
+	if len(failing) > 0 {
+		sort.Strings(failing)
+		return errors.Errorf("units in error state: %s", strings.Join(failing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	apiapplication "github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/testing"
+)
+
+type RebalanceSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake *fakeRebalanceAPI
+}
+
+var _ = gc.Suite(&RebalanceSuite{})
+
+type fakeRebalanceAPI struct {
+	jujutesting.Stub
+	status *params.FullStatus
+	added  int
+
+	// addedWorkload, if set, is the workload status of added units.
+	addedWorkload string
+}
+
+func (f *fakeRebalanceAPI) Close() error {
+	return nil
+}
+
+func (f *fakeRebalanceAPI) ModelUUID() string {
+	return "fake-uuid"
+}
+
+func (f *fakeRebalanceAPI) Status(patterns []string) (*params.FullStatus, error) {
+	f.MethodCall(f, "Status", patterns)
+	return f.status, f.NextErr()
+}
+
+func (f *fakeRebalanceAPI) AddUnits(args apiapplication.AddUnitsParams) ([]string, error) {
+	f.MethodCall(f, "AddUnits", args)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	f.added++
+	name := fmt.Sprintf("%s/%d", args.ApplicationName, 100+f.added)
+	unit := readyUnit("")
+	if f.addedWorkload != "" {
+		unit.WorkloadStatus.Status = f.addedWorkload
+	}
+	f.status.Applications[args.ApplicationName].Units[name] = unit
+	return []string{name}, nil
+}
+
+func (f *fakeRebalanceAPI) DestroyUnits(unitNames ...string) ([]params.DestroyUnitResult, error) {
+	f.MethodCall(f, "DestroyUnits", unitNames)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return make([]params.DestroyUnitResult, len(unitNames)), nil
+}
+
+func readyUnit(machine string) params.UnitStatus {
+	return params.UnitStatus{
+		Machine:        machine,
+		AgentStatus:    params.DetailedStatus{Status: "idle"},
+		WorkloadStatus: params.DetailedStatus{Status: "active"},
+	}
+}
+
+func (s *RebalanceSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	leader := readyUnit("0")
+	leader.Leader = true
+	s.fake = &fakeRebalanceAPI{
+		status: &params.FullStatus{
+			Machines: map[string]params.MachineStatus{
+				"0": {Id: "0", Hardware: "arch=amd64 availability-zone=zone-a"},
+				"1": {Id: "1", Hardware: "arch=amd64 availability-zone=zone-a"},
+				"2": {Id: "2", Hardware: "arch=amd64 availability-zone=zone-a"},
+				"3": {Id: "3", Hardware: "arch=amd64 availability-zone=zone-b"},
+				"4": {Id: "4", Hardware: "arch=amd64 availability-zone=zone-c"},
+			},
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {
+					Relations: map[string][]string{"db": {"wordpress"}},
+					Units: map[string]params.UnitStatus{
+						"mysql/0": leader,
+						"mysql/1": readyUnit("1"),
+						"mysql/2": readyUnit("2"),
+					},
+				},
+				"wordpress": {
+					Units: map[string]params.UnitStatus{
+						"wordpress/0": readyUnit("3"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *RebalanceSuite) runRebalance(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, application.NewRebalanceCommandForTest(s.fake, clock.WallClock), args...)
+}
+
+func (s *RebalanceSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  "no application specified",
+	}, {
+		args: []string{"--application", "no/way"},
+		err:  `application name "no/way" not valid`,
+	}, {
+		args: []string{"--application", "mysql", "--zones", "a,,b"},
+		err:  `invalid --zones value "a,,b"`,
+	}, {
+		args: []string{"--application", "mysql", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d", i)
+		err := cmdtesting.InitCommand(application.NewRebalanceCommandForTest(s.fake, clock.WallClock), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *RebalanceSuite) TestDryRun(c *gc.C) {
+	ctx, err := s.runRebalance(c, "--application", "mysql", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"mysql/1: zone-a -> zone-b\n"+
+		"mysql/2: zone-a -> zone-c\n")
+	s.fake.CheckCallNames(c, "Status")
+}
+
+func (s *RebalanceSuite) TestRebalanceMovesUnits(c *gc.C) {
+	_, err := s.runRebalance(c, "--application", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c,
+		"Status",
+		"Status", "AddUnits", "Status", "DestroyUnits",
+		"Status", "AddUnits", "Status", "DestroyUnits",
+	)
+	s.fake.CheckCall(c, 2, "AddUnits", apiapplication.AddUnitsParams{
+		ApplicationName: "mysql",
+		NumUnits:        1,
+		Placement:       []*instance.Placement{{Scope: "fake-uuid", Directive: "zone=zone-b"}},
+	})
+	s.fake.CheckCall(c, 4, "DestroyUnits", []string{"mysql/1"})
+	s.fake.CheckCall(c, 6, "AddUnits", apiapplication.AddUnitsParams{
+		ApplicationName: "mysql",
+		NumUnits:        1,
+		Placement:       []*instance.Placement{{Scope: "fake-uuid", Directive: "zone=zone-c"}},
+	})
+	s.fake.CheckCall(c, 8, "DestroyUnits", []string{"mysql/2"})
+}
+
+func (s *RebalanceSuite) TestRebalanceLeaderMovedLast(c *gc.C) {
+	ctx, err := s.runRebalance(c, "--application", "mysql", "--zones", "zone-b,zone-c", "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"mysql/1: zone-a -> zone-b\n"+
+		"mysql/2: zone-a -> zone-c\n"+
+		"mysql/0: zone-a -> zone-b\n")
+}
+
+func (s *RebalanceSuite) TestAlreadyBalanced(c *gc.C) {
+	ctx, err := s.runRebalance(c, "--application", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "application \"wordpress\" is already balanced\n")
+}
+
+func (s *RebalanceSuite) TestRelatedUnitInError(c *gc.C) {
+	wordpress := s.fake.status.Applications["wordpress"]
+	unit := wordpress.Units["wordpress/0"]
+	unit.WorkloadStatus.Status = "error"
+	wordpress.Units["wordpress/0"] = unit
+	_, err := s.runRebalance(c, "--application", "mysql")
+	c.Assert(err, gc.ErrorMatches, "moving unit mysql/1 to zone zone-b: units in error state: wordpress/0")
+	s.fake.CheckCallNames(c, "Status", "Status")
+}
+
+func (s *RebalanceSuite) assertWorkloadSettled(c *gc.C, workload string) {
+	s.fake.addedWorkload = workload
+	_, err := s.runRebalance(c, "--application", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c,
+		"Status",
+		"Status", "AddUnits", "Status", "DestroyUnits",
+		"Status", "AddUnits", "Status", "DestroyUnits",
+	)
+}
+
+func (s *RebalanceSuite) TestRebalanceWorkloadUnknown(c *gc.C) {
+	s.assertWorkloadSettled(c, "unknown")
+}
+
+func (s *RebalanceSuite) TestRebalanceWorkloadBlocked(c *gc.C) {
+	s.assertWorkloadSettled(c, "blocked")
+}
+
+func (s *RebalanceSuite) TestRebalanceWorkloadWaiting(c *gc.C) {
+	s.assertWorkloadSettled(c, "waiting")
+}
+
+func (s *RebalanceSuite) TestRebalanceWorkloadMaintenanceTimesOut(c *gc.C) {
+	s.fake.addedWorkload = "maintenance"
+	_, err := s.runRebalance(c, "--application", "mysql", "--timeout", "1ms")
+	c.Assert(err, gc.ErrorMatches, "moving unit mysql/1 to zone zone-b: timed out waiting for unit mysql/101 to become ready")
+	s.fake.CheckCallNames(c, "Status", "Status", "AddUnits", "Status")
+}
+
+func (s *RebalanceSuite) TestNotEnoughZones(c *gc.C) {
+	_, err := s.runRebalance(c, "--application", "mysql", "--zones", "zone-a")
+	c.Assert(err, gc.ErrorMatches, "at least two availability zones are required to rebalance; use --zones to specify them")
+}
@@ -0,0 +1,150 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const suspendUnitDoc = `
+Suspend application units, so that their agents stop running hooks.
+
+A suspended unit's agent finishes whatever hook or action it is running,
+reports the "suspended" agent status, and then runs nothing further until
+the unit is resumed. Events that arrive in the meantime are not lost; they
+are handled once the unit is resumed. This makes it possible to inspect or
+debug a unit without racing its agent.
+
+Removing a suspended unit resumes it so that it can be torn down.
+
+Examples:
+
+    juju suspend-unit wordpress/2 wordpress/3
+
+See also:
+    resume-unit
+    debug-hooks
+`
+
+const resumeUnitDoc = `
+Resume suspended application units.
+
+The agents of the units run any hooks that were held back while the units
+were suspended.
+
+Examples:
+
+    juju resume-unit wordpress/2 wordpress/3
+
+See also:
+    suspend-unit
+`
+
+// NewSuspendUnitCommand returns a command which suspends application units.
+func NewSuspendUnitCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&suspendUnitCommand{suspend: true})
+}
+
+// NewResumeUnitCommand returns a command which resumes suspended
+// application units.
+func NewResumeUnitCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&suspendUnitCommand{suspend: false})
+}
+
+// suspendUnitAPI defines the methods on the client API that the
+// suspend-unit and resume-unit commands call.
+type suspendUnitAPI interface {
+	Close() error
+	SuspendUnits(...names.UnitTag) ([]params.ErrorResult, error)
+	ResumeUnits(...names.UnitTag) ([]params.ErrorResult, error)
+}
+
+// suspendUnitCommand is responsible for suspending and resuming
+// application units.
+type suspendUnitCommand struct {
+	modelcmd.ModelCommandBase
+	UnitNames []string
+
+	suspend bool
+	api     suspendUnitAPI
+}
+
+func (c *suspendUnitCommand) Info() *cmd.Info {
+	if c.suspend {
+		return &cmd.Info{
+			Name:    "suspend-unit",
+			Args:    "<unit> [...]",
+			Purpose: "Stop application units from running hooks.",
+			Doc:     suspendUnitDoc,
+		}
+	}
+	return &cmd.Info{
+		Name:    "resume-unit",
+		Args:    "<unit> [...]",
+		Purpose: "Allow suspended application units to run hooks again.",
+		Doc:     resumeUnitDoc,
+	}
+}
+
+func (c *suspendUnitCommand) Init(args []string) error {
+	c.UnitNames = args
+	if len(c.UnitNames) == 0 {
+		return errors.Errorf("no units specified")
+	}
+	for _, name := range c.UnitNames {
+		if !names.IsValidUnit(name) {
+			return errors.Errorf("invalid unit name %q", name)
+		}
+	}
+	return nil
+}
+
+func (c *suspendUnitCommand) getAPI() (suspendUnitAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run connects to the model specified on the command line and suspends
+// or resumes the units therein.
+func (c *suspendUnitCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	tags := make([]names.UnitTag, len(c.UnitNames))
+	for i, name := range c.UnitNames {
+		tags[i] = names.NewUnitTag(name)
+	}
+	call, verb := client.ResumeUnits, "resuming"
+	if c.suspend {
+		call, verb = client.SuspendUnits, "suspending"
+	}
+	results, err := call(tags...)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	anyFailed := false
+	for i, name := range c.UnitNames {
+		if err := results[i].Error; err != nil {
+			anyFailed = true
+			ctx.Infof("%s unit %s failed: %s", verb, name, err)
+			continue
+		}
+		ctx.Infof("%s unit %s", verb, name)
+	}
+	if anyFailed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
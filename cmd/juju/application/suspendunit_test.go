@@ -0,0 +1,102 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/testing"
+)
+
+type SuspendUnitSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake *fakeSuspendUnitAPI
+}
+
+var _ = gc.Suite(&SuspendUnitSuite{})
+
+type fakeSuspendUnitAPI struct {
+	jujutesting.Stub
+	results []params.ErrorResult
+}
+
+func (f *fakeSuspendUnitAPI) Close() error {
+	return nil
+}
+
+func (f *fakeSuspendUnitAPI) SuspendUnits(units ...names.UnitTag) ([]params.ErrorResult, error) {
+	f.MethodCall(f, "SuspendUnits", units)
+	return f.results, f.NextErr()
+}
+
+func (f *fakeSuspendUnitAPI) ResumeUnits(units ...names.UnitTag) ([]params.ErrorResult, error) {
+	f.MethodCall(f, "ResumeUnits", units)
+	return f.results, f.NextErr()
+}
+
+func (s *SuspendUnitSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeSuspendUnitAPI{}
+}
+
+func (s *SuspendUnitSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no units specified",
+	}, {
+		args: []string{"mysql"},
+		err:  `invalid unit name "mysql"`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := cmdtesting.RunCommand(c, application.NewSuspendUnitCommandForTest(s.fake), t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+		_, err = cmdtesting.RunCommand(c, application.NewResumeUnitCommandForTest(s.fake), t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *SuspendUnitSuite) TestSuspend(c *gc.C) {
+	s.fake.results = []params.ErrorResult{{}, {
+		Error: common.ServerError(errors.NotFoundf(`unit "mysql/7"`)),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, application.NewSuspendUnitCommandForTest(s.fake), "mysql/0", "mysql/7")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+suspending unit mysql/0
+suspending unit mysql/7 failed: unit "mysql/7" not found
+`[1:])
+	s.fake.CheckCalls(c, []jujutesting.StubCall{{
+		"SuspendUnits", []interface{}{[]names.UnitTag{
+			names.NewUnitTag("mysql/0"),
+			names.NewUnitTag("mysql/7"),
+		}},
+	}})
+}
+
+func (s *SuspendUnitSuite) TestResume(c *gc.C) {
+	s.fake.results = []params.ErrorResult{{}}
+	ctx, err := cmdtesting.RunCommand(c, application.NewResumeUnitCommandForTest(s.fake), "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "resuming unit mysql/0\n")
+	s.fake.CheckCalls(c, []jujutesting.StubCall{{
+		"ResumeUnits", []interface{}{[]names.UnitTag{names.NewUnitTag("mysql/0")}},
+	}})
+}
+
+func (s *SuspendUnitSuite) TestSuspendBlocked(c *gc.C) {
+	s.fake.SetErrors(common.OperationBlockedError("TestSuspendBlocked"))
+	_, err := cmdtesting.RunCommand(c, application.NewSuspendUnitCommandForTest(s.fake), "mysql/0")
+	testing.AssertOperationWasBlocked(c, err, ".*TestSuspendBlocked.*")
+}
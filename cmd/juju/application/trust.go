@@ -0,0 +1,101 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const trustDoc = `
+Trust an application with the model's cloud credential.
+
+The units of a trusted application can read the credential with the
+credential-get hook tool, so that their charm can manage cloud resources
+on the operator's behalf. Only model admins may trust an application.
+
+Examples:
+
+    juju trust aws-integrator
+    juju trust aws-integrator --remove
+
+See also:
+    config
+`
+
+// NewTrustCommand returns a command which trusts applications with the
+// model's cloud credential.
+func NewTrustCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&trustCommand{})
+}
+
+// trustAPI defines the methods on the application API that the trust
+// command calls.
+type trustAPI interface {
+	Close() error
+	SetTrust(application string, trusted bool) error
+}
+
+// trustCommand is responsible for trusting applications with the
+// model's cloud credential, and for removing that trust.
+type trustCommand struct {
+	modelcmd.ModelCommandBase
+	ApplicationName string
+	Remove          bool
+
+	api trustAPI
+}
+
+func (c *trustCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "trust",
+		Args:    "<application name>",
+		Purpose: "Trust an application with the model's cloud credential.",
+		Doc:     trustDoc,
+	}
+}
+
+func (c *trustCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.Remove, "remove", false, "Stop trusting the application")
+}
+
+func (c *trustCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application name specified")
+	}
+	if !names.IsValidApplication(args[0]) {
+		return errors.NotValidf("application name %q", args[0])
+	}
+	c.ApplicationName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *trustCommand) getAPI() (trustAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+// Run sets or clears the application's trusted flag.
+func (c *trustCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.SetTrust(c.ApplicationName, !c.Remove)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
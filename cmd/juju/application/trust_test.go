@@ -0,0 +1,77 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/testing"
+)
+
+type TrustSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake *fakeTrustAPI
+}
+
+var _ = gc.Suite(&TrustSuite{})
+
+type fakeTrustAPI struct {
+	jujutesting.Stub
+}
+
+func (f *fakeTrustAPI) Close() error {
+	return nil
+}
+
+func (f *fakeTrustAPI) SetTrust(application string, trusted bool) error {
+	f.MethodCall(f, "SetTrust", application, trusted)
+	return f.NextErr()
+}
+
+func (s *TrustSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeTrustAPI{}
+}
+
+func (s *TrustSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no application name specified",
+	}, {
+		args: []string{"mysql/0"},
+		err:  `application name "mysql/0" not valid`,
+	}, {
+		args: []string{"mysql", "wordpress"},
+		err:  `unrecognized args: \["wordpress"\]`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := cmdtesting.RunCommand(c, application.NewTrustCommandForTest(s.fake), t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *TrustSuite) TestTrust(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, application.NewTrustCommandForTest(s.fake), "aws-integrator")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCall(c, 0, "SetTrust", "aws-integrator", true)
+}
+
+func (s *TrustSuite) TestRemoveTrust(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, application.NewTrustCommandForTest(s.fake), "aws-integrator", "--remove")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCall(c, 0, "SetTrust", "aws-integrator", false)
+}
+
+func (s *TrustSuite) TestTrustError(c *gc.C) {
+	s.fake.SetErrors(errors.New("permission denied"))
+	_, err := cmdtesting.RunCommand(c, application.NewTrustCommandForTest(s.fake), "aws-integrator")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -6,9 +6,12 @@ package commands
 import (
 	"bufio"
 	"fmt"
+	"net"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -20,6 +23,7 @@ import (
 	"gopkg.in/juju/charm.v6-unstable"
 
 	jujucloud "github.com/juju/juju/cloud"
+	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/constraints"
@@ -75,6 +79,15 @@ algorithm by assigning a "placement directive" via the '--to' option. This
 dictates what machine to use for the controller. This would typically be
 used with the MAAS provider ('--to <host>.maas').
 
+A controller instance left behind by a failed bootstrap, or by a client
+that has lost its record of the controller, may be reused rather than
+provisioning a new one by specifying its instance id with
+'--adopt-instance'. The instance is only adopted if its controller API
+cannot be reached, and after confirmation (skipped with '--yes'). The
+instance is reconfigured from scratch: its Juju agents and data are
+removed, and the new controller's agents are given new credentials. Not
+all clouds support adopting instances.
+
 Available keys for use with --config can be found here:
     https://jujucharms.com/docs/stable/controllers-config
     https://jujucharms.com/docs/stable/models-config
@@ -107,6 +120,7 @@ Examples:
     juju bootstrap --config=~/config-rs.yaml rackspace joe-syd
     juju bootstrap --config agent-version=1.25.3 aws joe-us-east-1
     juju bootstrap --config bootstrap-timeout=1200 azure joe-eastus
+    juju bootstrap --adopt-instance juju-0a1b2c-0 localhost
 
 See also:
     add-credentials
@@ -116,6 +130,13 @@ See also:
     set-constraints
     show-cloud`
 
+var adoptInstanceMsg = `
+WARNING! This command will adopt instance %q, which was started for
+controller %s. All Juju agents and data on the instance will be removed.
+Make sure that no other bootstrap of that controller is in progress.
+
+Continue? (y/N):`[1:]
+
 // defaultHostedModelName is the name of the hosted model created in each
 // controller for deploying workloads to, in addition to the "controller" model.
 const defaultHostedModelName = "default"
@@ -141,6 +162,8 @@ type bootstrapCommand struct {
 	BuildAgent              bool
 	MetadataSource          string
 	Placement               string
+	AdoptInstance           string
+	assumeYes               bool
 	KeepBrokenEnvironment   bool
 	AutoUpgrade             bool
 	AgentVersionParam       string
@@ -180,6 +203,9 @@ func (c *bootstrapCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.BuildAgent, "build-agent", false, "Build local version of agent binary before bootstrapping")
 	f.StringVar(&c.MetadataSource, "metadata-source", "", "Local path to use as tools and/or metadata source")
 	f.StringVar(&c.Placement, "to", "", "Placement directive indicating an instance to bootstrap")
+	f.StringVar(&c.AdoptInstance, "adopt-instance", "", "Reuse the orphaned controller instance with the specified id")
+	f.BoolVar(&c.assumeYes, "y", false, "Do not ask for confirmation when adopting an instance")
+	f.BoolVar(&c.assumeYes, "yes", false, "")
 	f.BoolVar(&c.KeepBrokenEnvironment, "keep-broken", false, "Do not destroy the model if bootstrap fails")
 	f.BoolVar(&c.AutoUpgrade, "auto-upgrade", false, "Upgrade to the latest patch release tools on first bootstrap")
 	f.StringVar(&c.AgentVersionParam, "agent-version", "", "Version of tools to use for Juju agents")
@@ -220,6 +246,9 @@ func (c *bootstrapCommand) Init(args []string) (err error) {
 			return errors.Errorf("unsupported bootstrap placement directive %q", c.Placement)
 		}
 	}
+	if c.AdoptInstance != "" && c.Placement != "" {
+		return errors.New("--adopt-instance and --to can't be used together")
+	}
 	if !c.AutoUpgrade {
 		// With no auto upgrade chosen, we default to the version matching the bootstrap client.
 		vers := jujuversion.Current
@@ -299,6 +328,9 @@ var getBootstrapFuncs = func() BootstrapInterface {
 }
 
 var (
+	dialControllerAPI = func(addr string) (net.Conn, error) {
+		return net.DialTimeout("tcp", addr, 10*time.Second)
+	}
 	bootstrapPrepare           = bootstrap.Prepare
 	environsDestroy            = environs.Destroy
 	waitForAgentInitialisation = common.WaitForAgentInitialisation
@@ -399,10 +431,38 @@ func (c *bootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 		c.controllerName = defaultControllerName(cloud.Name, region.Name)
 	}
 
-	config, err := c.bootstrapConfigs(ctx, cloud, provider)
+	cloudSpec := environs.CloudSpec{
+		Type:             cloud.Type,
+		Name:             cloud.Name,
+		Region:           region.Name,
+		Endpoint:         region.Endpoint,
+		IdentityEndpoint: region.IdentityEndpoint,
+		StorageEndpoint:  region.StorageEndpoint,
+		Credential:       credentials.credential,
+	}
+	var orphan *environs.OrphanedController
+	if c.AdoptInstance != "" {
+		if orphan, err = c.orphanedController(provider, cloudSpec); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	config, err := c.bootstrapConfigs(ctx, cloud, provider, orphan)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if orphan != nil {
+		if err := checkControllerUnreachable(orphan, config.controller.APIPort()); err != nil {
+			return errors.Trace(err)
+		}
+		if !c.assumeYes {
+			fmt.Fprintf(ctx.Stdout, adoptInstanceMsg, orphan.InstanceId, orphan.ControllerUUID)
+			if err := jujucmd.UserConfirmYes(ctx); err != nil {
+				return errors.Annotate(err, "adopting instance")
+			}
+		}
+		ctx.Infof("Adopting orphaned controller instance %s", orphan.InstanceId)
+	}
 
 	// Read existing current controller so we can clean up on error.
 	var oldCurrentController string
@@ -440,17 +500,9 @@ func (c *bootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 			ModelConfig:      config.bootstrapModel,
 			ControllerConfig: config.controller,
 			ControllerName:   c.controllerName,
-			Cloud: environs.CloudSpec{
-				Type:             cloud.Type,
-				Name:             cloud.Name,
-				Region:           region.Name,
-				Endpoint:         region.Endpoint,
-				IdentityEndpoint: region.IdentityEndpoint,
-				StorageEndpoint:  region.StorageEndpoint,
-				Credential:       credentials.credential,
-			},
-			CredentialName: credentials.name,
-			AdminSecret:    config.bootstrap.AdminSecret,
+			Cloud:            cloudSpec,
+			CredentialName:   credentials.name,
+			AdminSecret:      config.bootstrap.AdminSecret,
 		},
 	)
 	if err != nil {
@@ -559,6 +611,11 @@ See `[1:] + "`juju kill-controller`" + `.`)
 		credentials.name = credentials.detectedName
 	}
 
+	var adoptInstance instance.Id
+	if orphan != nil {
+		adoptInstance = orphan.InstanceId
+	}
+
 	bootstrapFuncs := getBootstrapFuncs()
 	err = bootstrapFuncs.Bootstrap(modelcmd.BootstrapContext(ctx), environ, bootstrap.BootstrapParams{
 		ModelConstraints:          c.Constraints,
@@ -566,6 +623,7 @@ See `[1:] + "`juju kill-controller`" + `.`)
 		BootstrapSeries:           c.BootstrapSeries,
 		BootstrapImage:            c.BootstrapImage,
 		Placement:                 c.Placement,
+		AdoptInstance:             adoptInstance,
 		BuildAgent:                c.BuildAgent,
 		BuildAgentTarball:         sync.BuildAgentTarball,
 		AgentVersion:              c.AgentVersion,
@@ -816,6 +874,71 @@ func (c *bootstrapCommand) credentialsAndRegionName(
 	return creds, regionName, nil
 }
 
+// orphanedController returns the orphaned controller whose instance is
+// to be adopted, as specified by --adopt-instance. Controllers known to
+// the client store are never orphaned.
+func (c *bootstrapCommand) orphanedController(
+	provider environs.EnvironProvider,
+	spec environs.CloudSpec,
+) (*environs.OrphanedController, error) {
+	finder, ok := provider.(environs.OrphanedControllerFinder)
+	if !ok {
+		return nil, errors.NotSupportedf("adopting instances on %q clouds", spec.Type)
+	}
+	controllers, err := c.ClientStore().AllControllers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	known := make([]string, 0, len(controllers))
+	for _, details := range controllers {
+		known = append(known, details.ControllerUUID)
+	}
+	orphans, err := finder.OrphanedControllers(spec, known)
+	if err != nil {
+		return nil, errors.Annotate(err, "finding orphaned controllers")
+	}
+	for i, orphan := range orphans {
+		if string(orphan.InstanceId) == c.AdoptInstance {
+			return &orphans[i], nil
+		}
+	}
+	return nil, errors.NotFoundf("orphaned controller instance %q", c.AdoptInstance)
+}
+
+// checkControllerUnreachable returns an error if the API server of the
+// orphaned controller can be reached on any of its instance's addresses,
+// at either the given API port or the default one. A controller that
+// serves its API is alive, even if this client does not know it, and
+// must not be adopted.
+func checkControllerUnreachable(orphan *environs.OrphanedController, apiPort int) error {
+	if len(orphan.Addresses) == 0 {
+		return errors.Errorf(
+			"cannot verify that controller %s is not running: instance %q has no addresses",
+			orphan.ControllerUUID, orphan.InstanceId,
+		)
+	}
+	ports := []int{apiPort}
+	if apiPort != controller.DefaultAPIPort {
+		ports = append(ports, controller.DefaultAPIPort)
+	}
+	for _, addr := range orphan.Addresses {
+		for _, port := range ports {
+			hostPort := net.JoinHostPort(addr.Value, strconv.Itoa(port))
+			conn, err := dialControllerAPI(hostPort)
+			if err != nil {
+				logger.Debugf("controller API at %s is unreachable: %v", hostPort, err)
+				continue
+			}
+			conn.Close()
+			return errors.Errorf(
+				"controller %s is serving its API at %s; it is not orphaned and cannot be adopted",
+				orphan.ControllerUUID, hostPort,
+			)
+		}
+	}
+	return nil
+}
+
 type bootstrapConfigs struct {
 	bootstrapModel           map[string]interface{}
 	controller               controller.Config
@@ -828,6 +951,7 @@ func (c *bootstrapCommand) bootstrapConfigs(
 	ctx *cmd.Context,
 	cloud jujucloud.Cloud,
 	provider environs.EnvironProvider,
+	orphan *environs.OrphanedController,
 ) (
 	bootstrapConfigs,
 	error,
@@ -841,6 +965,17 @@ func (c *bootstrapCommand) bootstrapConfigs(
 	if err != nil {
 		return bootstrapConfigs{}, errors.Trace(err)
 	}
+	if orphan != nil {
+		// The adopted instance is recognised by the provider as
+		// belonging to the controller and model it was started for,
+		// so the new controller must take over their UUIDs.
+		if controllerModelUUID, err = utils.UUIDFromString(orphan.ModelUUID); err != nil {
+			return bootstrapConfigs{}, errors.Annotate(err, "orphaned controller model UUID")
+		}
+		if controllerUUID, err = utils.UUIDFromString(orphan.ControllerUUID); err != nil {
+			return bootstrapConfigs{}, errors.Annotate(err, "orphaned controller UUID")
+		}
+	}
 
 	// Create a model config, and split out any controller
 	// and bootstrap config attributes.
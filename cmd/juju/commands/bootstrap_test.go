@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -400,6 +401,14 @@ var bootstrapTests = []bootstrapTest{{
 	info:      "placement",
 	args:      []string{"--to", "something"},
 	placement: "something",
+}, {
+	info: "--adopt-instance with --to",
+	args: []string{"--adopt-instance", "juju-0a1b2c-0", "--to", "something"},
+	err:  `--adopt-instance and --to can't be used together`,
+}, {
+	info: "--adopt-instance unsupported",
+	args: []string{"--adopt-instance", "juju-0a1b2c-0"},
+	err:  `adopting instances on "dummy" clouds not supported`,
 }, {
 	info:       "keep broken",
 	args:       []string{"--keep-broken"},
@@ -645,7 +654,7 @@ func checkConfigs(
 	ctx *cmd.Context, cloud *cloud.Cloud, provider environs.EnvironProvider,
 	expect map[string]map[string]interface{}) {
 
-	configs, err := bootstrapCmd.bootstrapConfigs(ctx, *cloud, provider)
+	configs, err := bootstrapCmd.bootstrapConfigs(ctx, *cloud, provider, nil)
 
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -1742,6 +1751,43 @@ func (s *BootstrapSuite) TestBootstrapPrintCloudRegionsNoSuchCloud(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cloud foo not found")
 }
 
+func (s *BootstrapSuite) TestCheckControllerUnreachable(c *gc.C) {
+	var dialed []string
+	s.PatchValue(&dialControllerAPI, func(addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return nil, errors.New("connection refused")
+	})
+	err := checkControllerUnreachable(&environs.OrphanedController{
+		InstanceId:     "juju-0a1b2c-0",
+		ControllerUUID: "orphaned-uuid",
+		Addresses:      network.NewAddresses("10.0.0.1"),
+	}, 17777)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dialed, jc.DeepEquals, []string{"10.0.0.1:17777", "10.0.0.1:17070"})
+}
+
+func (s *BootstrapSuite) TestCheckControllerUnreachableServingAPI(c *gc.C) {
+	s.PatchValue(&dialControllerAPI, func(addr string) (net.Conn, error) {
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	})
+	err := checkControllerUnreachable(&environs.OrphanedController{
+		InstanceId:     "juju-0a1b2c-0",
+		ControllerUUID: "orphaned-uuid",
+		Addresses:      network.NewAddresses("10.0.0.1"),
+	}, 17070)
+	c.Assert(err, gc.ErrorMatches, `controller orphaned-uuid is serving its API at 10.0.0.1:17070; it is not orphaned and cannot be adopted`)
+}
+
+func (s *BootstrapSuite) TestCheckControllerUnreachableNoAddresses(c *gc.C) {
+	err := checkControllerUnreachable(&environs.OrphanedController{
+		InstanceId:     "juju-0a1b2c-0",
+		ControllerUUID: "orphaned-uuid",
+	}, 17070)
+	c.Assert(err, gc.ErrorMatches, `cannot verify that controller orphaned-uuid is not running: instance "juju-0a1b2c-0" has no addresses`)
+}
+
 func (s *BootstrapSuite) TestBootstrapSetsControllerOnBase(c *gc.C) {
 	// This test ensures that the controller name is correctly set on
 	// on the bootstrap commands embedded ModelCommandBase. Without
@@ -0,0 +1,457 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/juju/osenv"
+)
+
+const completionDoc = `
+Generates a shell completion script for juju.
+
+The script completes command names, the flags of each command, and
+model, application, unit and machine names for the current model. The
+names of applications, units and machines are fetched from the controller
+and cached locally for a short time, so that completion stays responsive.
+
+Supported shells are bash, zsh and fish.
+
+Examples:
+
+Enable completion for the current bash session:
+    source <(juju completion bash)
+
+Install completion for zsh:
+    juju completion zsh > "${fpath[1]}/_juju"
+
+Install completion for fish:
+    juju completion fish > ~/.config/fish/completions/juju.fish
+`
+
+// completionCacheTTL is how long the results of a dynamic completion
+// query are reused before the controller is asked again.
+const completionCacheTTL = time.Minute
+
+var completionShells = []string{"bash", "fish", "zsh"}
+
+// completionListKinds are the kinds of names that may be requested with
+// the --list flag. Models are read from the local client store; the
+// remainder are fetched from the controller.
+var completionListKinds = []string{"applications", "machines", "models", "units"}
+
+// completionStatusAPI defines the methods on the client API that the
+// completion command calls.
+type completionStatusAPI interface {
+	Close() error
+	Status(patterns []string) (*params.FullStatus, error)
+}
+
+// newCompletionCommand returns a command that generates shell completion
+// scripts for the given commands.
+func newCompletionCommand() *completionCommand {
+	return &completionCommand{clock: clock.WallClock}
+}
+
+// completionCommand generates shell completion scripts from the
+// metadata of the registered commands, and answers the dynamic
+// completion queries made by those scripts.
+type completionCommand struct {
+	modelcmd.ModelCommandBase
+
+	shell string
+	list  string
+
+	commands  []cmd.Command
+	clock     clock.Clock
+	statusAPI completionStatusAPI
+}
+
+// add records a command to be included in the generated scripts.
+func (c *completionCommand) add(command cmd.Command) {
+	c.commands = append(c.commands, command)
+}
+
+// Info implements cmd.Command.
+func (c *completionCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "completion",
+		Args:    "bash|zsh|fish",
+		Purpose: "Generates a shell completion script.",
+		Doc:     completionDoc,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *completionCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.list, "list", "", "Print the names of the given kind of entity, for use by completion scripts")
+}
+
+// Init implements cmd.Command.
+func (c *completionCommand) Init(args []string) error {
+	if c.list != "" {
+		if !set.NewStrings(completionListKinds...).Contains(c.list) {
+			return errors.NotValidf("--list value %q", c.list)
+		}
+		return cmd.CheckEmpty(args)
+	}
+	if len(args) == 0 {
+		return errors.New("no shell specified")
+	}
+	c.shell, args = args[0], args[1:]
+	if !set.NewStrings(completionShells...).Contains(c.shell) {
+		return errors.NotSupportedf("shell %q", c.shell)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *completionCommand) Run(ctx *cmd.Context) error {
+	if c.list != "" {
+		names, err := c.listNames()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, name := range names {
+			fmt.Fprintln(ctx.Stdout, name)
+		}
+		return nil
+	}
+	tmpl := completionTemplates[c.shell]
+	return errors.Trace(tmpl.Execute(ctx.Stdout, completionData{
+		Commands: completionCommands(c.commands),
+	}))
+}
+
+// listNames returns the names of the requested kind of entity.
+func (c *completionCommand) listNames() ([]string, error) {
+	if c.list == "models" {
+		return c.modelNames()
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelName, err := c.ModelName()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cachePath := completionCachePath(controllerName, modelName)
+	entry, err := readCompletionCache(cachePath)
+	if err != nil || c.clock.Now().Sub(entry.Updated) > completionCacheTTL {
+		entry, err = c.fetchNames()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := writeCompletionCache(cachePath, entry); err != nil {
+			logger.Debugf("cannot cache completion data: %v", err)
+		}
+	}
+	switch c.list {
+	case "applications":
+		return entry.Applications, nil
+	case "units":
+		return entry.Units, nil
+	default:
+		return entry.Machines, nil
+	}
+}
+
+func (c *completionCommand) modelNames() ([]string, error) {
+	store := c.ClientStore()
+	controllerName, err := store.CurrentController()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	models, err := store.AllModels(controllerName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	names := make([]string, 0, len(models))
+	for name := range models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (c *completionCommand) getStatusAPI() (completionStatusAPI, error) {
+	if c.statusAPI != nil {
+		return c.statusAPI, nil
+	}
+	return c.NewAPIClient()
+}
+
+// fetchNames queries the controller for the names of the entities in
+// the model.
+func (c *completionCommand) fetchNames() (completionCacheEntry, error) {
+	client, err := c.getStatusAPI()
+	if err != nil {
+		return completionCacheEntry{}, errors.Trace(err)
+	}
+	defer client.Close()
+	status, err := client.Status(nil)
+	if err != nil {
+		return completionCacheEntry{}, errors.Trace(err)
+	}
+	applications := set.NewStrings()
+	units := set.NewStrings()
+	for appName, app := range status.Applications {
+		applications.Add(appName)
+		for unitName, unit := range app.Units {
+			units.Add(unitName)
+			for subName := range unit.Subordinates {
+				units.Add(subName)
+			}
+		}
+	}
+	machines := set.NewStrings()
+	var addMachines func(map[string]params.MachineStatus)
+	addMachines = func(ms map[string]params.MachineStatus) {
+		for id, m := range ms {
+			machines.Add(id)
+			addMachines(m.Containers)
+		}
+	}
+	addMachines(status.Machines)
+	return completionCacheEntry{
+		Updated:      c.clock.Now(),
+		Applications: applications.SortedValues(),
+		Units:        units.SortedValues(),
+		Machines:     machines.SortedValues(),
+	}, nil
+}
+
+// completionCacheEntry holds the cached results of the dynamic
+// completion queries for a single model.
+type completionCacheEntry struct {
+	Updated      time.Time `json:"updated"`
+	Applications []string  `json:"applications"`
+	Units        []string  `json:"units"`
+	Machines     []string  `json:"machines"`
+}
+
+func completionCachePath(controllerName, modelName string) string {
+	name := strings.Replace(controllerName+"_"+modelName, string(filepath.Separator), "_", -1)
+	return osenv.JujuXDGDataHomePath("completion", name+".json")
+}
+
+func readCompletionCache(path string) (completionCacheEntry, error) {
+	var entry completionCacheEntry
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return entry, errors.Trace(err)
+	}
+	return entry, errors.Trace(json.Unmarshal(data, &entry))
+}
+
+func writeCompletionCache(path string, entry completionCacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(path, data, 0600))
+}
+
+// completionData is the data passed to the completion script templates.
+type completionData struct {
+	Commands []completionCommandInfo
+}
+
+// completionCommandInfo describes a single command for completion.
+type completionCommandInfo struct {
+	Name    string
+	Purpose string
+	Flags   []completionFlag
+}
+
+// completionFlag describes a single flag of a command.
+type completionFlag struct {
+	Name  string
+	Usage string
+	// List is the kind of entity whose names complete the flag's
+	// value, if any.
+	List string
+}
+
+// Option returns the flag as it is written on the command line.
+func (f completionFlag) Option() string {
+	if len(f.Name) == 1 {
+		return "-" + f.Name
+	}
+	return "--" + f.Name
+}
+
+// completionFlagLists maps flag names to the kind of entity whose
+// names complete the flag's value.
+var completionFlagLists = map[string]string{
+	"m":           "models",
+	"model":       "models",
+	"application": "applications",
+	"unit":        "units",
+	"to":          "machines",
+}
+
+// completionCommands extracts the completion metadata from the given
+// commands, sorted by name. Aliases are included as commands in their
+// own right.
+func completionCommands(commands []cmd.Command) []completionCommandInfo {
+	var result []completionCommandInfo
+	for _, command := range commands {
+		info := command.Info()
+		f := gnuflag.NewFlagSet(info.Name, gnuflag.ContinueOnError)
+		command.SetFlags(f)
+		var flags []completionFlag
+		f.VisitAll(func(flag *gnuflag.Flag) {
+			flags = append(flags, completionFlag{
+				Name:  flag.Name,
+				Usage: flag.Usage,
+				List:  completionFlagLists[flag.Name],
+			})
+		})
+		purpose := strings.TrimSpace(info.Purpose)
+		for _, name := range append([]string{info.Name}, info.Aliases...) {
+			result = append(result, completionCommandInfo{
+				Name:    name,
+				Purpose: purpose,
+				Flags:   flags,
+			})
+		}
+	}
+	sort.Sort(completionCommandsByName(result))
+	return result
+}
+
+type completionCommandsByName []completionCommandInfo
+
+func (c completionCommandsByName) Len() int           { return len(c) }
+func (c completionCommandsByName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c completionCommandsByName) Less(i, j int) bool { return c[i].Name < c[j].Name }
+
+// completionRegistry records every command registered through it with
+// the completion command, before passing it on.
+type completionRegistry struct {
+	commandRegistry
+	completion *completionCommand
+}
+
+// Register implements commandRegistry.
+func (r completionRegistry) Register(c cmd.Command) {
+	r.completion.add(c)
+	r.commandRegistry.Register(c)
+}
+
+// RegisterDeprecated implements commandRegistry.
+func (r completionRegistry) RegisterDeprecated(c cmd.Command, check cmd.DeprecationCheck) {
+	if !check.Obsolete() {
+		r.completion.add(c)
+	}
+	r.commandRegistry.RegisterDeprecated(c, check)
+}
+
+var completionFuncs = template.FuncMap{
+	"quote": func(s string) string {
+		return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+	},
+	"summary": func(s string) string {
+		s = strings.TrimSuffix(strings.TrimSpace(s), ".")
+		if i := strings.Index(s, "\n"); i >= 0 {
+			s = s[:i]
+		}
+		return s
+	},
+}
+
+var completionTemplates = map[string]*template.Template{
+	"bash": template.Must(template.New("bash").Funcs(completionFuncs).Parse(bashCompletionTemplate)),
+	"zsh":  template.Must(template.New("zsh").Funcs(completionFuncs).Parse(zshCompletionTemplate)),
+	"fish": template.Must(template.New("fish").Funcs(completionFuncs).Parse(fishCompletionTemplate)),
+}
+
+const bashCompletionTemplate = `# bash completion for juju, generated by "juju completion bash".
+
+_juju_list() {
+    juju completion --list "$1" 2>/dev/null
+}
+
+_juju_complete() {
+    local cur prev command flags
+    COMPREPLY=()
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    if [ "$COMP_CWORD" -eq 1 ]; then
+        COMPREPLY=($(compgen -W "{{range .Commands}}{{.Name}} {{end}}" -- "$cur"))
+        return 0
+    fi
+    command="${COMP_WORDS[1]}"
+    case "$command" in
+{{- range .Commands}}
+    {{.Name}})
+        flags="{{range .Flags}}{{.Option}} {{end}}"
+        case "$prev" in
+{{- range .Flags}}{{if .List}}
+        {{.Option}})
+            COMPREPLY=($(compgen -W "$(_juju_list {{.List}})" -- "$cur"))
+            return 0
+            ;;
+{{- end}}{{end}}
+        esac
+        ;;
+{{- end}}
+    esac
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+        return 0
+    fi
+    COMPREPLY=($(compgen -W "$(_juju_list applications) $(_juju_list units)" -- "$cur"))
+    return 0
+}
+
+complete -F _juju_complete juju
+`
+
+const zshCompletionTemplate = `#compdef juju
+# zsh completion for juju, generated by "juju completion zsh".
+
+autoload -U +X bashcompinit && bashcompinit
+
+` + bashCompletionTemplate
+
+const fishCompletionTemplate = `# fish completion for juju, generated by "juju completion fish".
+
+function __juju_list
+    juju completion --list $argv[1] 2>/dev/null
+end
+
+complete -c juju -f
+{{- range .Commands}}
+complete -c juju -n '__fish_use_subcommand' -a {{quote .Name}} -d {{quote (summary .Purpose)}}
+{{- $command := .Name}}
+{{- range .Flags}}
+complete -c juju -n '__fish_seen_subcommand_from {{$command}}' {{if eq (len .Name) 1}}-s{{else}}-l{{end}} {{.Name}}{{if .List}} -x -a '(__juju_list {{.List}})'{{end}} -d {{quote (summary .Usage)}}
+{{- end}}
+{{- end}}
+complete -c juju -n 'not __fish_use_subcommand' -a '(__juju_list applications) (__juju_list units)'
+`
@@ -0,0 +1,176 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/gnuflag"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CompletionSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	store *jujuclient.MemStore
+	api   *fakeCompletionStatusAPI
+	clock *testing.Clock
+}
+
+var _ = gc.Suite(&CompletionSuite{})
+
+type fakeCompletionStatusAPI struct {
+	testing.Stub
+	status *params.FullStatus
+}
+
+func (f *fakeCompletionStatusAPI) Close() error {
+	return nil
+}
+
+func (f *fakeCompletionStatusAPI) Status(patterns []string) (*params.FullStatus, error) {
+	f.MethodCall(f, "Status", patterns)
+	return f.status, f.NextErr()
+}
+
+type completionTestCommand struct {
+	cmd.CommandBase
+	name    string
+	aliases []string
+	flag    string
+}
+
+func (c *completionTestCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    c.name,
+		Aliases: c.aliases,
+		Purpose: "Does " + c.name + " things.",
+	}
+}
+
+func (c *completionTestCommand) SetFlags(f *gnuflag.FlagSet) {
+	var value string
+	f.StringVar(&value, c.flag, "", "The "+c.flag+" to use")
+}
+
+func (s *CompletionSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	s.store.Controllers["ctrl"] = jujuclient.ControllerDetails{}
+	s.store.CurrentControllerName = "ctrl"
+	s.store.Models["ctrl"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"admin/default": {"default-uuid"},
+			"admin/other":   {"other-uuid"},
+		},
+		CurrentModel: "admin/default",
+	}
+	s.api = &fakeCompletionStatusAPI{
+		status: &params.FullStatus{
+			Machines: map[string]params.MachineStatus{
+				"0": {Containers: map[string]params.MachineStatus{"0/lxd/0": {}}},
+			},
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {Units: map[string]params.UnitStatus{
+					"mysql/0": {Subordinates: map[string]params.UnitStatus{"ntp/0": {}}},
+				}},
+				"ntp": {},
+			},
+		},
+	}
+	s.clock = testing.NewClock(time.Now())
+}
+
+func (s *CompletionSuite) newCommand() cmd.Command {
+	completion := &completionCommand{
+		clock:     s.clock,
+		statusAPI: s.api,
+	}
+	completion.add(&completionTestCommand{name: "deploy", aliases: []string{"install"}, flag: "to"})
+	completion.add(&completionTestCommand{name: "add-unit", flag: "num-units"})
+	completion.SetClientStore(s.store)
+	return modelcmd.Wrap(completion)
+}
+
+func (s *CompletionSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no shell specified",
+	}, {
+		args: []string{"tcsh"},
+		err:  `shell "tcsh" not supported`,
+	}, {
+		args: []string{"bash", "zsh"},
+		err:  `unrecognized args: \["zsh"\]`,
+	}, {
+		args: []string{"--list", "relations"},
+		err:  `--list value "relations" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := cmdtesting.InitCommand(s.newCommand(), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *CompletionSuite) TestBash(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "bash")
+	c.Assert(err, jc.ErrorIsNil)
+	out := cmdtesting.Stdout(ctx)
+	c.Check(out, jc.Contains, `COMPREPLY=($(compgen -W "add-unit deploy install " -- "$cur"))`)
+	c.Check(out, jc.Contains, `flags="--num-units "`)
+	c.Check(out, jc.Contains, `
+        --to)
+            COMPREPLY=($(compgen -W "$(_juju_list machines)" -- "$cur"))`)
+	c.Check(out, jc.Contains, "complete -F _juju_complete juju\n")
+}
+
+func (s *CompletionSuite) TestZsh(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "zsh")
+	c.Assert(err, jc.ErrorIsNil)
+	out := cmdtesting.Stdout(ctx)
+	c.Check(out, gc.Matches, "(?s)#compdef juju\n.*bashcompinit.*complete -F _juju_complete juju\n")
+}
+
+func (s *CompletionSuite) TestFish(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "fish")
+	c.Assert(err, jc.ErrorIsNil)
+	out := cmdtesting.Stdout(ctx)
+	c.Check(out, jc.Contains, "complete -c juju -n '__fish_use_subcommand' -a 'add-unit' -d 'Does add-unit things'\n")
+	c.Check(out, jc.Contains, "complete -c juju -n '__fish_seen_subcommand_from deploy' -l to -x -a '(__juju_list machines)' -d 'The to to use'\n")
+}
+
+func (s *CompletionSuite) TestListModels(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--list", "models")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "admin/default\nadmin/other\n")
+	s.api.CheckNoCalls(c)
+}
+
+func (s *CompletionSuite) TestListCached(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--list", "applications")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "mysql\nntp\n")
+
+	ctx, err = cmdtesting.RunCommand(c, s.newCommand(), "--list", "units")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "mysql/0\nntp/0\n")
+	s.api.CheckCallNames(c, "Status")
+
+	s.clock.Advance(2 * completionCacheTTL)
+	ctx, err = cmdtesting.RunCommand(c, s.newCommand(), "--list", "machines")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "0\n0/lxd/0\n")
+	s.api.CheckCallNames(c, "Status", "Status")
+}
@@ -34,6 +34,9 @@ type debugHooksCommand struct {
 const debugHooksDoc = `
 Interactively debug a hook remotely on an application unit.
 
+Within a debugging session, the hook tool hook-env prints the environment
+variables Juju gives to the hook being debugged.
+
 See the "juju help ssh" for information about SSH related options
 accepted by the debug-hooks command.
 `
@@ -4,6 +4,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -19,7 +20,10 @@ import (
 	"github.com/mattn/go-isatty"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/api/debuglogpresets"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 )
 
@@ -89,11 +93,55 @@ machine-3 or machine-4, and then stop:
         --exclude machine-3 \
         --exclude machine-4 
 
+When showing the log of the controller model, users with superuser access
+to the controller may instead show the messages of other models, selected
+by UUID or name with --include-model and --exclude-model. With only
+--exclude-model, the messages of all other models are shown.
+
+Show all messages from the default and staging models:
+
+    juju debug-log -m controller --replay \
+        --include-model default \
+        --include-model staging
+
 To see all WARNING and ERROR messages and then continue showing any
 new WARNING and ERROR messages as they are logged:
 
     juju debug-log --replay --level WARNING
 
+The '--compress' option asks the controller to compress the log messages
+it sends, which saves bandwidth when following a busy model over a slow
+link. Controllers that do not support compression send the messages
+uncompressed.
+
+The '--format' option selects the output format. With '--format json', each
+log message is written as a single line JSON object with the fields
+timestamp, entity, module, level, message and location.
+
+When the output stops because --limit lines have been shown, or because
+the controller discarded messages while the command was reading them too
+slowly, a note saying how many matching messages were shown is written to
+stderr.
+
+Filters may be saved on the controller as a named preset, so that they can
+be shared with other users of the controller. Saving or removing a preset
+requires controller superuser access. When a preset is used, any --include,
+--exclude, --include-module and --exclude-module options are added to those
+of the preset, and --level and --format take precedence over the preset.
+
+Save the filters as a preset called noisy-neutron:
+
+    juju debug-log --save-preset noisy-neutron \
+        --include neutron-gateway --level WARNING
+
+Show messages using the noisy-neutron preset:
+
+    juju debug-log --preset noisy-neutron
+
+Remove the noisy-neutron preset:
+
+    juju debug-log --remove-preset noisy-neutron
+
 See also: 
     status
     ssh`
@@ -131,8 +179,18 @@ type debugLogCommand struct {
 
 	format string
 	tz     *time.Location
+
+	output       string
+	preset       string
+	savePreset   string
+	removePreset string
 }
 
+const (
+	debugLogFormatText = "text"
+	debugLogFormatJSON = "json"
+)
+
 func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeEntity), "i", "Only show log messages for these entities")
@@ -141,6 +199,8 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeEntity), "exclude", "Do not show log messages for these entities")
 	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeModule), "include-module", "Only show log messages for these logging modules")
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeModule), "exclude-module", "Do not show log messages for these logging modules")
+	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeModel), "include-model", "Only show log messages for these models (controller model only)")
+	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeModel), "exclude-model", "Do not show log messages for these models (controller model only)")
 
 	f.StringVar(&c.level, "l", "", "Log level to show, one of [TRACE, DEBUG, INFO, WARNING, ERROR]")
 	f.StringVar(&c.level, "level", "", "")
@@ -158,6 +218,12 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.location, "location", false, "Show filename and line numbers")
 	f.BoolVar(&c.date, "date", false, "Show dates as well as times")
 	f.BoolVar(&c.ms, "ms", false, "Show times to millisecond precision")
+
+	f.StringVar(&c.output, "format", "", "Output format, one of [text, json]")
+	f.BoolVar(&c.params.Compress, "compress", false, "Ask the controller to compress log messages, to save bandwidth over slow links")
+	f.StringVar(&c.preset, "preset", "", "Use the filters saved in the named preset")
+	f.StringVar(&c.savePreset, "save-preset", "", "Save the specified filters as the named preset, and exit")
+	f.StringVar(&c.removePreset, "remove-preset", "", "Remove the named preset, and exit")
 }
 
 func (c *debugLogCommand) Init(args []string) error {
@@ -172,6 +238,15 @@ func (c *debugLogCommand) Init(args []string) error {
 	if c.tail && c.notail {
 		return errors.NotValidf("setting --tail and --no-tail")
 	}
+	switch c.output {
+	case "", debugLogFormatText, debugLogFormatJSON:
+	default:
+		return errors.Errorf("format value %q is not one of %q, %q",
+			c.output, debugLogFormatText, debugLogFormatJSON)
+	}
+	if c.savePreset != "" && c.removePreset != "" {
+		return errors.NotValidf("setting --save-preset and --remove-preset")
+	}
 	if c.utc {
 		c.tz = time.UTC
 	}
@@ -221,12 +296,58 @@ func (c *debugLogCommand) processEntities(entities []string) []string {
 }
 
 type DebugLogAPI interface {
-	WatchDebugLog(params common.DebugLogParams) (<-chan common.LogMessage, error)
+	OpenDebugLog(params common.DebugLogParams) (DebugLogStream, error)
 	Close() error
 }
 
+// DebugLogStream is an open debug log stream.
+type DebugLogStream interface {
+	// Messages returns the channel of messages received, which is
+	// closed when the stream ends.
+	Messages() <-chan common.LogMessage
+
+	// Trailer returns the summary of the stream sent by the
+	// controller, if any, once the messages channel is closed.
+	Trailer() *common.DebugLogTrailer
+}
+
 var getDebugLogAPI = func(c *debugLogCommand) (DebugLogAPI, error) {
-	return c.NewAPIClient()
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return debugLogClient{client}, nil
+}
+
+// debugLogClient adapts *api.Client to DebugLogAPI.
+type debugLogClient struct {
+	*api.Client
+}
+
+// OpenDebugLog is part of the DebugLogAPI interface.
+func (c debugLogClient) OpenDebugLog(params common.DebugLogParams) (DebugLogStream, error) {
+	stream, err := c.Client.OpenDebugLog(params)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// DebugLogPresetsAPI provides access to the debug-log presets
+// stored on the controller.
+type DebugLogPresetsAPI interface {
+	Get(name string) (params.DebugLogPreset, error)
+	Set(preset params.DebugLogPreset) error
+	Remove(name string) error
+	Close() error
+}
+
+var getDebugLogPresetsAPI = func(c *debugLogCommand) (DebugLogPresetsAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return debuglogpresets.NewClient(root), nil
 }
 
 func isTerminal(out io.Writer) bool {
@@ -239,6 +360,11 @@ func isTerminal(out io.Writer) bool {
 
 // Run retrieves the debug log via the API.
 func (c *debugLogCommand) Run(ctx *cmd.Context) (err error) {
+	if c.preset != "" || c.savePreset != "" || c.removePreset != "" {
+		if done, err := c.handlePresets(ctx); err != nil || done {
+			return err
+		}
+	}
 	if c.tail {
 		c.params.NoTail = false
 	} else if c.notail {
@@ -248,16 +374,18 @@ func (c *debugLogCommand) Run(ctx *cmd.Context) (err error) {
 		// using a terminal.
 		c.params.NoTail = !isTerminal(ctx.Stdout)
 	}
+	c.params.Trailer = true
 
 	client, err := getDebugLogAPI(c)
 	if err != nil {
 		return err
 	}
 	defer client.Close()
-	messages, err := client.WatchDebugLog(c.params)
+	stream, err := client.OpenDebugLog(c.params)
 	if err != nil {
 		return err
 	}
+	messages := stream.Messages()
 	writer := ansiterm.NewWriter(ctx.Stdout)
 	if c.color {
 		writer.SetColorCapable(true)
@@ -267,12 +395,100 @@ func (c *debugLogCommand) Run(ctx *cmd.Context) (err error) {
 		if !ok {
 			break
 		}
+		if c.output == debugLogFormatJSON {
+			if err := c.writeJSONLogRecord(ctx.Stdout, msg); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
 		c.writeLogRecord(writer, msg)
 	}
 
+	if trailer := stream.Trailer(); trailer != nil && trailer.Truncated {
+		fmt.Fprintf(ctx.Stderr,
+			"debug-log output truncated: %d of %d matching lines shown\n",
+			trailer.LinesSent, trailer.LinesMatched)
+	}
 	return nil
 }
 
+// handlePresets applies, saves or removes debug-log presets as
+// requested. It returns true if there is nothing more to do.
+func (c *debugLogCommand) handlePresets(ctx *cmd.Context) (bool, error) {
+	client, err := getDebugLogPresetsAPI(c)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+
+	if c.removePreset != "" {
+		if err := client.Remove(c.removePreset); err != nil {
+			return false, errors.Annotatef(err, "removing preset %q", c.removePreset)
+		}
+		ctx.Infof("Removed debug-log preset %q", c.removePreset)
+		return true, nil
+	}
+	if c.preset != "" {
+		preset, err := client.Get(c.preset)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if err := c.applyPreset(preset); err != nil {
+			return false, errors.Annotatef(err, "preset %q", c.preset)
+		}
+	}
+	if c.savePreset != "" {
+		preset := params.DebugLogPreset{
+			Name:          c.savePreset,
+			IncludeEntity: c.params.IncludeEntity,
+			ExcludeEntity: c.params.ExcludeEntity,
+			IncludeModule: c.params.IncludeModule,
+			ExcludeModule: c.params.ExcludeModule,
+			Format:        c.output,
+		}
+		if c.params.Level != loggo.UNSPECIFIED {
+			preset.Level = c.params.Level.String()
+		}
+		if err := client.Set(preset); err != nil {
+			return false, errors.Annotatef(err, "saving preset %q", c.savePreset)
+		}
+		ctx.Infof("Saved debug-log preset %q", c.savePreset)
+		return true, nil
+	}
+	return false, nil
+}
+
+// applyPreset adds the filters from the preset to those specified on
+// the command line. Options given on the command line take precedence.
+func (c *debugLogCommand) applyPreset(preset params.DebugLogPreset) error {
+	c.params.IncludeEntity = append(c.params.IncludeEntity, preset.IncludeEntity...)
+	c.params.ExcludeEntity = append(c.params.ExcludeEntity, preset.ExcludeEntity...)
+	c.params.IncludeModule = append(c.params.IncludeModule, preset.IncludeModule...)
+	c.params.ExcludeModule = append(c.params.ExcludeModule, preset.ExcludeModule...)
+	if c.params.Level == loggo.UNSPECIFIED && preset.Level != "" {
+		level, ok := loggo.ParseLevel(preset.Level)
+		if !ok {
+			return errors.NotValidf("level %q", preset.Level)
+		}
+		c.params.Level = level
+	}
+	if c.output == "" {
+		c.output = preset.Format
+	}
+	return nil
+}
+
+func (c *debugLogCommand) writeJSONLogRecord(w io.Writer, r common.LogMessage) error {
+	return json.NewEncoder(w).Encode(params.DebugLogRecord{
+		Timestamp: r.Timestamp.In(c.tz),
+		Entity:    r.Entity,
+		Module:    r.Module,
+		Level:     r.Severity,
+		Message:   r.Message,
+		Location:  r.Location,
+	})
+}
+
 var SeverityColor = map[string]*ansiterm.Context{
 	"TRACE":   ansiterm.Foreground(ansiterm.Default),
 	"DEBUG":   ansiterm.Foreground(ansiterm.Green),
@@ -7,11 +7,14 @@ import (
 	"time"
 
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/testing"
 )
@@ -88,6 +91,13 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				ExcludeModule: []string{"juju.foo", "unit"},
 				Backlog:       10,
 			},
+		}, {
+			args: []string{"--include-model", "default", "--exclude-model", "staging"},
+			expected: common.DebugLogParams{
+				IncludeModel: []string{"default"},
+				ExcludeModel: []string{"staging"},
+				Backlog:      10,
+			},
 		}, {
 			args: []string{"--replay"},
 			expected: common.DebugLogParams{
@@ -103,6 +113,18 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--compress"},
+			expected: common.DebugLogParams{
+				Backlog:  10,
+				Compress: true,
+			},
+		}, {
+			args:     []string{"--format", "yaml"},
+			errMatch: `format value "yaml" is not one of "text", "json"`,
+		}, {
+			args:     []string{"--save-preset", "a", "--remove-preset", "b"},
+			errMatch: `setting --save-preset and --remove-preset not valid`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -137,6 +159,7 @@ func (s *DebugLogSuite) TestParamsPassed(c *gc.C) {
 		Backlog:       500,
 		Level:         loggo.WARNING,
 		NoTail:        true,
+		Trailer:       true,
 	})
 }
 
@@ -182,13 +205,156 @@ func (s *DebugLogSuite) TestLogOutput(c *gc.C) {
 		"machine-0: 14:15:23 INFO test.module somefile.go:123 this is the log output\n")
 }
 
+func (s *DebugLogSuite) TestJSONOutput(c *gc.C) {
+	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
+		return &fakeDebugLogAPI{log: []common.LogMessage{{
+			Entity:    "machine-0",
+			Timestamp: time.Date(2016, 10, 9, 8, 15, 23, 0, time.UTC),
+			Severity:  "INFO",
+			Module:    "test.module",
+			Location:  "somefile.go:123",
+			Message:   "this is the log output",
+		}}}, nil
+	})
+	ctx, err := cmdtesting.RunCommand(c, newDebugLogCommandTZ(time.UTC), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals,
+		`{"timestamp":"2016-10-09T08:15:23Z","entity":"machine-0","module":"test.module",`+
+			`"level":"INFO","message":"this is the log output","location":"somefile.go:123"}`+"\n")
+}
+
+func (s *DebugLogSuite) TestTruncatedOutputReported(c *gc.C) {
+	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
+		return &fakeDebugLogAPI{
+			log: []common.LogMessage{{
+				Entity:    "machine-0",
+				Timestamp: time.Date(2016, 10, 9, 8, 15, 23, 0, time.UTC),
+				Severity:  "INFO",
+				Module:    "test.module",
+				Location:  "somefile.go:123",
+				Message:   "this is the log output",
+			}},
+			trailer: &common.DebugLogTrailer{
+				LinesMatched: 12,
+				LinesSent:    1,
+				BytesSent:    120,
+				Truncated:    true,
+			},
+		}, nil
+	})
+	ctx, err := cmdtesting.RunCommand(c, newDebugLogCommandTZ(time.UTC), "--limit", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals,
+		"machine-0: 08:15:23 INFO test.module this is the log output\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "debug-log output truncated: 1 of 12 matching lines shown\n")
+}
+
+func (s *DebugLogSuite) TestPresetApplied(c *gc.C) {
+	fake := &fakeDebugLogAPI{}
+	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
+		return fake, nil
+	})
+	presets := &fakeDebugLogPresetsAPI{
+		preset: params.DebugLogPreset{
+			Name:          "noisy-neutron",
+			IncludeEntity: []string{"unit-neutron-gateway-*"},
+			ExcludeModule: []string{"juju.worker.uniter"},
+			Level:         "WARNING",
+			Format:        "json",
+		},
+	}
+	s.PatchValue(&getDebugLogPresetsAPI, func(_ *debugLogCommand) (DebugLogPresetsAPI, error) {
+		return presets, nil
+	})
+	_, err := cmdtesting.RunCommand(c, newDebugLogCommand(),
+		"--preset", "noisy-neutron",
+		"-i", "machine-1",
+		"--level", "ERROR",
+		"--no-tail",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	presets.CheckCallNames(c, "Get", "Close")
+	presets.CheckCall(c, 0, "Get", "noisy-neutron")
+	c.Assert(fake.params, gc.DeepEquals, common.DebugLogParams{
+		IncludeEntity: []string{"machine-1", "unit-neutron-gateway-*"},
+		ExcludeModule: []string{"juju.worker.uniter"},
+		Backlog:       10,
+		Level:         loggo.ERROR,
+		NoTail:        true,
+		Trailer:       true,
+	})
+}
+
+func (s *DebugLogSuite) TestSavePreset(c *gc.C) {
+	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
+		c.Fatalf("debug log should not be requested")
+		return nil, nil
+	})
+	presets := &fakeDebugLogPresetsAPI{}
+	s.PatchValue(&getDebugLogPresetsAPI, func(_ *debugLogCommand) (DebugLogPresetsAPI, error) {
+		return presets, nil
+	})
+	ctx, err := cmdtesting.RunCommand(c, newDebugLogCommand(),
+		"--save-preset", "noisy-neutron",
+		"-i", "neutron-gateway",
+		"--level", "WARNING",
+		"--format", "json",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Saved debug-log preset \"noisy-neutron\"\n")
+	presets.CheckCallNames(c, "Set", "Close")
+	presets.CheckCall(c, 0, "Set", params.DebugLogPreset{
+		Name:          "noisy-neutron",
+		IncludeEntity: []string{"unit-neutron-gateway-*"},
+		Level:         "WARNING",
+		Format:        "json",
+	})
+}
+
+func (s *DebugLogSuite) TestRemovePreset(c *gc.C) {
+	presets := &fakeDebugLogPresetsAPI{}
+	presets.SetErrors(errors.New("permission denied"))
+	s.PatchValue(&getDebugLogPresetsAPI, func(_ *debugLogCommand) (DebugLogPresetsAPI, error) {
+		return presets, nil
+	})
+	_, err := cmdtesting.RunCommand(c, newDebugLogCommand(), "--remove-preset", "noisy-neutron")
+	c.Assert(err, gc.ErrorMatches, `removing preset "noisy-neutron": permission denied`)
+	presets.CheckCallNames(c, "Remove", "Close")
+}
+
+type fakeDebugLogPresetsAPI struct {
+	jujutesting.Stub
+	preset params.DebugLogPreset
+}
+
+func (f *fakeDebugLogPresetsAPI) Get(name string) (params.DebugLogPreset, error) {
+	f.MethodCall(f, "Get", name)
+	return f.preset, f.NextErr()
+}
+
+func (f *fakeDebugLogPresetsAPI) Set(preset params.DebugLogPreset) error {
+	f.MethodCall(f, "Set", preset)
+	return f.NextErr()
+}
+
+func (f *fakeDebugLogPresetsAPI) Remove(name string) error {
+	f.MethodCall(f, "Remove", name)
+	return f.NextErr()
+}
+
+func (f *fakeDebugLogPresetsAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
 type fakeDebugLogAPI struct {
-	log    []common.LogMessage
-	params common.DebugLogParams
-	err    error
+	log     []common.LogMessage
+	trailer *common.DebugLogTrailer
+	params  common.DebugLogParams
+	err     error
 }
 
-func (fake *fakeDebugLogAPI) WatchDebugLog(params common.DebugLogParams) (<-chan common.LogMessage, error) {
+func (fake *fakeDebugLogAPI) OpenDebugLog(params common.DebugLogParams) (DebugLogStream, error) {
 	if fake.err != nil {
 		return nil, fake.err
 	}
@@ -200,9 +366,22 @@ func (fake *fakeDebugLogAPI) WatchDebugLog(params common.DebugLogParams) (<-chan
 			response <- msg
 		}
 	}()
-	return response, nil
+	return &fakeDebugLogStream{response, fake.trailer}, nil
 }
 
 func (fake *fakeDebugLogAPI) Close() error {
 	return nil
 }
+
+type fakeDebugLogStream struct {
+	messages <-chan common.LogMessage
+	trailer  *common.DebugLogTrailer
+}
+
+func (s *fakeDebugLogStream) Messages() <-chan common.LogMessage {
+	return s.messages
+}
+
+func (s *fakeDebugLogStream) Trailer() *common.DebugLogTrailer {
+	return s.trailer
+}
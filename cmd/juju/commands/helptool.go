@@ -54,6 +54,9 @@ func (dummyHookContext) HookRelation() (jujuc.ContextRelation, error) {
 func (dummyHookContext) RemoteUnitName() (string, error) {
 	return "", errors.NotFoundf("RemoteUnitName")
 }
+func (dummyHookContext) DepartingUnit() (string, error) {
+	return "", errors.NotFoundf("DepartingUnit")
+}
 func (dummyHookContext) Relation(id int) (jujuc.ContextRelation, error) {
 	return nil, errors.NotFoundf("Relation")
 }
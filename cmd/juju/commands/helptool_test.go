@@ -31,11 +31,15 @@ Show help on a Juju charm tool.
 var expectedCommands = []string{
 	"action-fail",
 	"action-get",
+	"action-progress",
 	"action-set",
 	"add-metric",
 	"application-version-set",
 	"close-port",
 	"config-get",
+	"credential-get",
+	"goal-state",
+	"hook-env",
 	"is-leader",
 	"juju-log",
 	"juju-reboot",
@@ -52,7 +56,10 @@ var expectedCommands = []string{
 	"relation-list",
 	"relation-set",
 	"resource-get",
+	"secret-get",
+	"secret-set",
 	"status-get",
+	"status-history",
 	"status-set",
 	"storage-add",
 	"storage-get",
@@ -33,10 +33,12 @@ import (
 	"github.com/juju/juju/cmd/juju/cloud"
 	"github.com/juju/juju/cmd/juju/controller"
 	"github.com/juju/juju/cmd/juju/crossmodel"
+	"github.com/juju/juju/cmd/juju/firewall"
 	"github.com/juju/juju/cmd/juju/gui"
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/cmd/juju/metricsdebug"
 	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/cmd/juju/operations"
 	"github.com/juju/juju/cmd/juju/resource"
 	rcmd "github.com/juju/juju/cmd/juju/romulus/commands"
 	"github.com/juju/juju/cmd/juju/setmeterstatus"
@@ -269,6 +271,12 @@ type commandRegistry interface {
 
 // registerCommands registers commands in the specified registry.
 func registerCommands(r commandRegistry, ctx *cmd.Context) {
+	// Shell completion is generated from the metadata of every
+	// command registered after it.
+	completion := newCompletionCommand()
+	r = completionRegistry{commandRegistry: r, completion: completion}
+	r.Register(modelcmd.Wrap(completion))
+
 	// Creation commands.
 	r.Register(newBootstrapCommand())
 	r.Register(application.NewAddRelationCommand())
@@ -290,12 +298,16 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(status.NewStatusCommand())
 	r.Register(newSwitchCommand())
 	r.Register(status.NewStatusHistoryCommand())
+	r.Register(status.NewAddStatusAnnotationCommand())
+	r.Register(status.NewTopCommand())
 
 	// Error resolution and debugging commands.
 	r.Register(newDefaultRunCommand())
 	r.Register(newSCPCommand(nil))
 	r.Register(newSSHCommand(nil))
 	r.Register(newResolvedCommand())
+	r.Register(application.NewSuspendUnitCommand())
+	r.Register(application.NewResumeUnitCommand())
 	r.Register(newDebugLogCommand())
 	r.Register(newDebugHooksCommand(nil))
 
@@ -357,6 +369,13 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(model.NewGrantCommand())
 	r.Register(model.NewRevokeCommand())
 	r.Register(model.NewShowCommand())
+	r.Register(model.NewHistoryCommand())
+	r.Register(model.NewAddBranchCommand())
+	r.Register(model.NewTrackCommand())
+	r.Register(model.NewBranchConfigCommand())
+	r.Register(model.NewCommitBranchCommand())
+	r.Register(model.NewAbortBranchCommand())
+	r.Register(model.NewBranchesCommand())
 
 	r.Register(newMigrateCommand())
 	if featureflag.Enabled(feature.DeveloperMode) {
@@ -371,6 +390,11 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(action.NewListCommand())
 	r.Register(action.NewCancelCommand())
 
+	// Manage operations, such as those started by juju run
+	r.Register(operations.NewListOperationsCommand())
+	r.Register(operations.NewShowTaskCommand())
+	r.Register(operations.NewCancelOperationCommand())
+
 	// Manage controller availability
 	r.Register(newEnableHACommand())
 
@@ -380,6 +404,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(application.NewDeployCommand())
 	r.Register(application.NewExposeCommand())
 	r.Register(application.NewUnexposeCommand())
+	r.Register(application.NewTrustCommand())
+	r.Register(application.NewRebalanceCommand())
 	r.Register(application.NewServiceGetConstraintsCommand())
 	r.Register(application.NewServiceSetConstraintsCommand())
 
@@ -429,6 +455,12 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(controller.NewShowControllerCommand())
 	r.Register(controller.NewGetConfigCommand())
 
+	// Manage controller firewall rules
+	if featureflag.Enabled(feature.CrossModelRelations) {
+		r.Register(firewall.NewSetFirewallRuleCommand())
+		r.Register(firewall.NewListFirewallRulesCommand())
+	}
+
 	// Debug Metrics
 	r.Register(metricsdebug.New())
 	r.Register(metricsdebug.NewCollectMetricsCommand())
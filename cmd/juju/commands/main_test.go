@@ -387,7 +387,9 @@ func assertNoArgs(c *gc.C, argChan <-chan []string) {
 }
 
 var commandNames = []string{
+	"abort-branch",
 	"actions",
+	"add-branch",
 	"add-cloud",
 	"add-credential",
 	"add-machine",
@@ -395,6 +397,7 @@ var commandNames = []string{
 	"add-relation",
 	"add-space",
 	"add-ssh-key",
+	"add-status-annotation",
 	"add-storage",
 	"add-subnet",
 	"add-unit",
@@ -407,10 +410,15 @@ var commandNames = []string{
 	"autoload-credentials",
 	"backups",
 	"bootstrap",
+	"branch-config",
+	"branches",
 	"budget",
 	"cached-images",
 	"cancel-action",
+	"cancel-operation",
 	"change-user-password",
+	"commit-branch",
+	"completion",
 	"charm",
 	"charm-resources",
 	"clouds",
@@ -443,12 +451,14 @@ var commandNames = []string{
 	"gui",
 	"help",
 	"help-tool",
+	"history",
 	"import-filesystem",
 	"import-ssh-key",
 	"kill-controller",
 	"list-actions",
 	"list-agreements",
 	"list-backups",
+	"list-branches",
 	"list-cached-images",
 	"list-charm-resources",
 	"list-clouds",
@@ -457,6 +467,7 @@ var commandNames = []string{
 	"list-disabled-commands",
 	"list-machines",
 	"list-models",
+	"list-operations",
 	"list-payloads",
 	"list-plans",
 	"list-regions",
@@ -477,8 +488,10 @@ var commandNames = []string{
 	"model-default",
 	"model-defaults",
 	"models",
+	"operations",
 	"payloads",
 	"plans",
+	"rebalance",
 	"regions",
 	"register",
 	"relate", //alias for add-relation
@@ -497,6 +510,7 @@ var commandNames = []string{
 	"resolved",
 	"resources",
 	"restore-backup",
+	"resume-unit",
 	"retry-provisioning",
 	"revoke",
 	"run",
@@ -519,6 +533,7 @@ var commandNames = []string{
 	"show-status",
 	"show-status-log",
 	"show-storage",
+	"show-task",
 	"show-user",
 	"show-wallet",
 	"sla",
@@ -529,8 +544,12 @@ var commandNames = []string{
 	"storage",
 	"storage-pools",
 	"subnets",
+	"suspend-unit",
 	"switch",
 	"sync-tools",
+	"top",
+	"track",
+	"trust",
 	"unexpose",
 	"unregister",
 	"update-clouds",
@@ -554,9 +573,12 @@ var devFeatures = []string{
 var commandNamesBehindFlags = set.NewStrings(
 	"consume",
 	"find-endpoints",
+	"firewall-rules",
+	"list-firewall-rules",
 	"list-offers",
 	"offer",
 	"offers",
+	"set-firewall-rule",
 	"show-endpoints",
 )
 
@@ -17,8 +17,8 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	actionapi "github.com/juju/juju/api/action"
+	"github.com/juju/juju/api/operations"
 	"github.com/juju/juju/apiserver/params"
-	"github.com/juju/juju/cmd/juju/action"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
 )
@@ -73,8 +73,10 @@ the unit.
 in the model.  If you specify --all you cannot provide additional
 targets.
 
-Since juju run creates actions, you can query for the status of commands
-started with juju run by calling "juju show-action-status --name juju-run".
+Each juju run enqueues an operation, with a task for each target. You can
+query for the status of commands started with juju run by calling
+"juju operations", show a single task with "juju show-task", and cancel the
+tasks that have not yet started with "juju cancel-operation".
 
 If you need to pass flags to the command being run, you must precede the
 command and its arguments with "--", to tell "juju run" to stop processing
@@ -217,9 +219,9 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	var runResults []params.ActionResult
+	var operation params.OperationResult
 	if c.all {
-		runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
+		operation, err = client.RunOnAllMachines(c.commands, c.timeout)
 	} else {
 		params := params.RunParams{
 			Commands:     c.commands,
@@ -228,7 +230,7 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 			Applications: c.services,
 			Units:        c.units,
 		}
-		runResults, err = client.Run(params)
+		operation, err = client.Run(params)
 	}
 
 	if err != nil {
@@ -236,7 +238,7 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 	}
 
 	actionsToQuery := []actionQuery{}
-	for _, result := range runResults {
+	for _, result := range operation.Tasks {
 		if result.Error != nil {
 			fmt.Fprintf(ctx.GetStderr(), "couldn't queue one action: %v", result.Error)
 			continue
@@ -276,7 +278,7 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 	timeout := c.timeAfter(c.timeout)
 	values := []interface{}{}
 	for len(actionsToQuery) > 0 {
-		actionResults, err := client.Actions(entities(actionsToQuery))
+		actionResults, err := client.Tasks(entities(actionsToQuery))
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -302,9 +304,8 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 				timedOut = true
 			case <-c.timeAfter(1 * time.Second):
 				// TODO(axw) 2017-02-07 #1662451
-				// use a watcher instead of polling.
-				// this should be easier once we implement
-				// action grouping
+				// use a watcher on the operation instead
+				// of polling.
 			}
 			if timedOut {
 				break
@@ -371,9 +372,10 @@ type actionQuery struct {
 
 // RunClient exposes the capabilities required by the CLI
 type RunClient interface {
-	action.APIClient
-	RunOnAllMachines(commands string, timeout time.Duration) ([]params.ActionResult, error)
-	Run(params.RunParams) ([]params.ActionResult, error)
+	Close() error
+	RunOnAllMachines(commands string, timeout time.Duration) (params.OperationResult, error)
+	Run(params.RunParams) (params.OperationResult, error)
+	Tasks(params.Entities) (params.ActionResults, error)
 }
 
 // In order to be able to easily mock out the API side for testing,
@@ -383,12 +385,38 @@ var getRunAPIClient = func(c *runCommand) (RunClient, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return actionapi.NewClient(root), errors.Trace(err)
+	if root.BestFacadeVersion("Operations") == 0 {
+		// Older controllers have no Operations facade; run the
+		// commands as ungrouped actions instead.
+		return runActionAdapter{actionapi.NewClient(root)}, nil
+	}
+	return operations.NewClient(root), nil
+}
+
+// runActionAdapter adapts the Action facade's run methods, used by
+// controllers without the Operations facade, to a RunClient.
+type runActionAdapter struct {
+	*actionapi.Client
+}
+
+func (a runActionAdapter) Run(run params.RunParams) (params.OperationResult, error) {
+	results, err := a.Client.Run(run)
+	if err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	return params.OperationResult{Tasks: results}, nil
+}
+
+func (a runActionAdapter) RunOnAllMachines(commands string, timeout time.Duration) (params.OperationResult, error) {
+	results, err := a.Client.RunOnAllMachines(commands, timeout)
+	if err != nil {
+		return params.OperationResult{}, errors.Trace(err)
+	}
+	return params.OperationResult{Tasks: results}, nil
 }
 
-// getActionResult abstracts over the action CLI function that we use here to fetch results
-var getActionResult = func(c RunClient, actionId string, wait *time.Timer) (params.ActionResult, error) {
-	return action.GetActionResult(c, actionId, wait)
+func (a runActionAdapter) Tasks(arg params.Entities) (params.ActionResults, error) {
+	return a.Client.Actions(arg)
 }
 
 // entities is a convenience constructor for params.Entities.
@@ -19,9 +19,10 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 
+	actionapi "github.com/juju/juju/api/action"
+	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
-	"github.com/juju/juju/cmd/juju/action"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/testing"
 )
@@ -491,6 +492,49 @@ func (s *RunSuite) TestSingleResponse(c *gc.C) {
 	}
 }
 
+func (s *RunSuite) TestRunActionAdapter(c *gc.C) {
+	var calls []string
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Action")
+			calls = append(calls, request)
+			switch request {
+			case "Run", "RunOnAllMachines":
+				*(result.(*params.ActionResults)) = params.ActionResults{
+					Results: []params.ActionResult{{
+						Action: &params.Action{Tag: "action-1", Receiver: "machine-0"},
+					}},
+				}
+			case "Actions":
+				c.Check(arg, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "action-1"}},
+				})
+				*(result.(*params.ActionResults)) = params.ActionResults{
+					Results: []params.ActionResult{{Status: params.ActionCompleted}},
+				}
+			}
+			return nil
+		},
+	)
+	client := runActionAdapter{actionapi.NewClient(apiCaller)}
+
+	operation, err := client.Run(params.RunParams{Commands: "hostname", Machines: []string{"0"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(operation.OperationID, gc.Equals, "")
+	c.Check(operation.Tasks, jc.DeepEquals, []params.ActionResult{{
+		Action: &params.Action{Tag: "action-1", Receiver: "machine-0"},
+	}})
+
+	operation, err = client.RunOnAllMachines("hostname", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(operation.Tasks, gc.HasLen, 1)
+
+	tasks, err := client.Tasks(params.Entities{Entities: []params.Entity{{Tag: "action-1"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(tasks.Results, jc.DeepEquals, []params.ActionResult{{Status: params.ActionCompleted}})
+	c.Check(calls, jc.DeepEquals, []string{"Run", "RunOnAllMachines", "Actions"})
+}
+
 func (s *RunSuite) setupMockAPI() *mockRunAPI {
 	mock := &mockRunAPI{}
 	s.PatchValue(&getRunAPIClient, func(_ *runCommand) (RunClient, error) {
@@ -500,7 +544,6 @@ func (s *RunSuite) setupMockAPI() *mockRunAPI {
 }
 
 type mockRunAPI struct {
-	action.APIClient
 	stdout string
 	stderr string
 	code   int
@@ -586,8 +629,8 @@ func (*mockRunAPI) Close() error {
 	return nil
 }
 
-func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) ([]params.ActionResult, error) {
-	var result []params.ActionResult
+func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) (params.OperationResult, error) {
+	var result params.OperationResult
 
 	if m.block {
 		return result, common.OperationBlockedError("the operation has been blocked")
@@ -609,14 +652,14 @@ func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) ([
 				Message: exec.ErrCancelled.Error(),
 			}
 		}
-		result = append(result, response)
+		result.Tasks = append(result.Tasks, response)
 	}
 
 	return result, nil
 }
 
-func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.ActionResult, error) {
-	var result []params.ActionResult
+func (m *mockRunAPI) Run(runParams params.RunParams) (params.OperationResult, error) {
+	var result params.OperationResult
 
 	if m.block {
 		return result, common.OperationBlockedError("the operation has been blocked")
@@ -625,21 +668,21 @@ func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.ActionResult, err
 	for _, id := range runParams.Machines {
 		response, found := m.runResponses[id]
 		if found {
-			result = append(result, response)
+			result.Tasks = append(result.Tasks, response)
 		}
 	}
 	// mock ignores services
 	for _, id := range runParams.Units {
 		response, found := m.runResponses[id]
 		if found {
-			result = append(result, response)
+			result.Tasks = append(result.Tasks, response)
 		}
 	}
 
 	return result, nil
 }
 
-func (m *mockRunAPI) Actions(actionTags params.Entities) (params.ActionResults, error) {
+func (m *mockRunAPI) Tasks(actionTags params.Entities) (params.ActionResults, error) {
 	results := params.ActionResults{Results: make([]params.ActionResult, len(actionTags.Entities))}
 
 	for i, entity := range actionTags.Entities {
@@ -52,6 +52,8 @@ a previous upgrade was not fully completed (e.g.: if one of the
 controllers in a high availability model failed to upgrade).
 If a failed upgrade has been resolved, '--reset-previous-upgrade' can be
 used to allow the upgrade to proceed.
+When upgrading the controller model, '--dry-run' also lists the upgrade
+steps that the controller would run, and any that it could not.
 Backups are recommended prior to upgrading.
 
 Examples:
@@ -181,6 +183,7 @@ type modelConfigAPI interface {
 
 type controllerAPI interface {
 	ModelConfig() (map[string]interface{}, error)
+	UpgradeDryRun(to version.Number) ([]params.UpgradeStepCheck, error)
 	Close() error
 }
 
@@ -350,6 +353,11 @@ func (c *upgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	}
 	if c.DryRun {
 		fmt.Fprintf(ctx.Stderr, "upgrade to this version by running\n    juju upgrade-juju --agent-version=\"%s\"\n", context.chosen)
+		if isControllerModel {
+			if err := reportUpgradeSteps(ctx, controllerClient, context.chosen); err != nil {
+				return err
+			}
+		}
 	} else {
 		if c.ResetPrevious {
 			if ok, err := c.confirmResetPreviousUpgrade(ctx); !ok || err != nil {
@@ -379,6 +387,35 @@ func (c *upgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	return nil
 }
 
+// reportUpgradeSteps writes the upgrade steps that the controller would
+// run when upgraded to the given version, noting any whose
+// preconditions are not met.
+func reportUpgradeSteps(ctx *cmd.Context, client controllerAPI, to version.Number) error {
+	steps, err := client.UpgradeDryRun(to)
+	if errors.IsNotSupported(err) {
+		ctx.Infof("this controller cannot report the upgrade steps it would run")
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot check upgrade steps")
+	}
+	if len(steps) == 0 {
+		fmt.Fprintln(ctx.Stdout, "the controller would run no upgrade steps")
+		return nil
+	}
+	fmt.Fprintln(ctx.Stdout, "the controller would run these upgrade steps:")
+	for _, step := range steps {
+		fmt.Fprintf(ctx.Stdout, "    %s: %s", step.Version, step.Description)
+		if step.Feature != "" {
+			fmt.Fprintf(ctx.Stdout, " (feature %q)", step.Feature)
+		}
+		fmt.Fprintln(ctx.Stdout)
+		if step.Error != nil {
+			fmt.Fprintf(ctx.Stdout, "        cannot be run: %s\n", step.Error.Message)
+		}
+	}
+	return nil
+}
+
 func tryImplicitUpload(agentVersion version.Number) bool {
 	newerAgent := jujuversion.Current.Compare(agentVersion) > 0
 	return newerAgent || agentVersion.Build > 0 || jujuversion.Current.Build > 0
@@ -790,6 +790,34 @@ func (s *UpgradeJujuSuite) TestUpgradeUnknownSeriesInStreams(c *gc.C) {
 	c.Assert(fakeAPI.tools, gc.DeepEquals, []string{"2.1.0-weird-amd64", fakeAPI.nextVersion.String()})
 }
 
+func (s *UpgradeJujuSuite) TestUpgradeDryRunReportsSteps(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.upgradeSteps = []params.UpgradeStepCheck{{
+		Version:     "2.2.0",
+		Description: "add status history pruning config settings",
+	}, {
+		Version:     "2.2.3",
+		Description: "add max-action-age and max-action-size config settings",
+		Error:       &params.Error{Message: "cannot read model config"},
+	}}
+	fakeAPI.patch(s)
+	cmd := &upgradeJujuCommand{}
+	err := cmdtesting.InitCommand(modelcmd.Wrap(cmd), []string{"--dry-run"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := cmdtesting.Context(c)
+	err = modelcmd.Wrap(cmd).Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.upgradeDryRunCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+the controller would run these upgrade steps:
+    2.2.0: add status history pruning config settings
+    2.2.3: add max-action-age and max-action-size config settings
+        cannot be run: cannot read model config
+`[1:])
+}
+
 func (s *UpgradeJujuSuite) TestUpgradeInProgress(c *gc.C) {
 	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
 	fakeAPI.setVersionErr = &params.Error{
@@ -905,6 +933,8 @@ type fakeUpgradeJujuAPI struct {
 	setVersionCalledWith      version.Number
 	tools                     []string
 	findToolsCalled           bool
+	upgradeSteps              []params.UpgradeStepCheck
+	upgradeDryRunCalledWith   version.Number
 }
 
 func (a *fakeUpgradeJujuAPI) reset() {
@@ -933,6 +963,11 @@ func (a *fakeUpgradeJujuAPI) ModelConfig() (map[string]interface{}, error) {
 	}, nil
 }
 
+func (a *fakeUpgradeJujuAPI) UpgradeDryRun(to version.Number) ([]params.UpgradeStepCheck, error) {
+	a.upgradeDryRunCalledWith = to
+	return a.upgradeSteps, nil
+}
+
 func (a *fakeUpgradeJujuAPI) addTools(tools ...string) {
 	for _, tool := range tools {
 		a.tools = append(a.tools, tool)
@@ -24,12 +24,14 @@ func NewGetConfigCommand() cmd.Command {
 }
 
 // getConfigCommand is able to output either the entire environment or
-// the requested value in a format of the user's choosing.
+// the requested value in a format of the user's choosing, or to set
+// the values of the attributes that may be changed after bootstrap.
 type getConfigCommand struct {
 	modelcmd.ControllerCommandBase
-	api controllerAPI
-	key string
-	out cmd.Output
+	api    controllerAPI
+	key    string
+	values map[string]interface{}
+	out    cmd.Output
 }
 
 const getControllerHelpDoc = `
@@ -40,11 +42,16 @@ The controller configuration is set during bootstrap, available keys
 and values can be found here:
   https://jujucharms.com/docs/stable/controllers-config
 
+Some attributes, such as "features", may also be changed afterwards by
+specifying key=value pairs. Upgrade steps gated on newly enabled
+features are run by the next upgrade.
+
 Examples:
 
     juju controller-config
     juju controller-config api-port
     juju controller-config -c mycontroller
+    juju controller-config features=new-schema
 
 See also:
     controllers
@@ -55,8 +62,8 @@ See also:
 func (c *getConfigCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "controller-config",
-		Args:    "[<attribute key>]",
-		Purpose: "Displays configuration settings for a controller.",
+		Args:    "[<attribute key> | <attribute key>=<value> ...]",
+		Purpose: "Displays or sets configuration settings for a controller.",
 		Doc:     strings.TrimSpace(getControllerHelpDoc),
 	}
 }
@@ -71,6 +78,17 @@ func (c *getConfigCommand) SetFlags(f *gnuflag.FlagSet) {
 }
 
 func (c *getConfigCommand) Init(args []string) (err error) {
+	if len(args) > 0 && strings.Contains(args[0], "=") {
+		c.values = make(map[string]interface{})
+		for _, arg := range args {
+			parts := strings.SplitN(arg, "=", 2)
+			if len(parts) != 2 {
+				return errors.Errorf("expected <attribute key>=<value>, got %q", arg)
+			}
+			c.values[parts[0]] = parts[1]
+		}
+		return nil
+	}
 	c.key, err = cmd.ZeroOrOneArgs(args)
 	return
 }
@@ -78,6 +96,7 @@ func (c *getConfigCommand) Init(args []string) (err error) {
 type controllerAPI interface {
 	Close() error
 	ControllerConfig() (controller.Config, error)
+	ConfigSet(values map[string]interface{}) error
 }
 
 func (c *getConfigCommand) getAPI() (controllerAPI, error) {
@@ -102,6 +121,10 @@ func (c *getConfigCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if c.values != nil {
+		return errors.Trace(client.ConfigSet(c.values))
+	}
+
 	attrs, err := client.ControllerConfig()
 	if err != nil {
 		return err
@@ -41,6 +41,11 @@ func (s *GetConfigSuite) TestInit(c *gc.C) {
 	// More than one is not allowed.
 	err = cmdtesting.InitCommand(controller.NewGetConfigCommandForTest(&fakeControllerAPI{}, s.store), []string{"one", "two"})
 	c.Check(err, gc.ErrorMatches, `unrecognized args: \["two"\]`)
+	// Any number of key=value pairs is fine.
+	err = cmdtesting.InitCommand(controller.NewGetConfigCommandForTest(&fakeControllerAPI{}, s.store), []string{"one=1", "two=2"})
+	c.Check(err, jc.ErrorIsNil)
+	err = cmdtesting.InitCommand(controller.NewGetConfigCommandForTest(&fakeControllerAPI{}, s.store), []string{"one=1", "two"})
+	c.Check(err, gc.ErrorMatches, `expected <attribute key>=<value>, got "two"`)
 }
 
 func (s *GetConfigSuite) TestSingleValue(c *gc.C) {
@@ -89,8 +94,25 @@ func (s *GetConfigSuite) TestError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "error")
 }
 
+func (s *GetConfigSuite) TestSetValues(c *gc.C) {
+	api := &fakeControllerAPI{}
+	command := controller.NewGetConfigCommandForTest(api, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "features=new-schema,other")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api.values, jc.DeepEquals, map[string]interface{}{
+		"features": "new-schema,other",
+	})
+}
+
+func (s *GetConfigSuite) TestSetValuesError(c *gc.C) {
+	command := controller.NewGetConfigCommandForTest(&fakeControllerAPI{err: errors.New("error")}, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "api-port=1234")
+	c.Assert(err, gc.ErrorMatches, "error")
+}
+
 type fakeControllerAPI struct {
-	err error
+	err    error
+	values map[string]interface{}
 }
 
 func (f *fakeControllerAPI) Close() error {
@@ -107,3 +129,8 @@ func (f *fakeControllerAPI) ControllerConfig() (jujucontroller.Config, error) {
 		"ca-cert":         "multi\nline",
 	}, nil
 }
+
+func (f *fakeControllerAPI) ConfigSet(values map[string]interface{}) error {
+	f.values = values
+	return f.err
+}
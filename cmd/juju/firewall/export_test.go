@@ -0,0 +1,27 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+// NewSetFirewallRuleCommandForTest returns a set-firewall-rule command
+// that uses the given API and client store.
+func NewSetFirewallRuleCommandForTest(api SetFirewallRuleAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &setFirewallRuleCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+// NewListFirewallRulesCommandForTest returns a firewall-rules command
+// that uses the given API and client store.
+func NewListFirewallRulesCommandForTest(api ListFirewallRulesAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &listFirewallRulesCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall
+
+import (
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+var listRulesHelpSummary = `
+Prints the firewall rules.`[1:]
+
+var listRulesHelpDetails = `
+Lists the firewall rules which control ingress to the well known
+services used by Juju. Services without a rule may be reached from
+anywhere.
+
+Examples:
+
+    juju firewall-rules
+
+See also:
+    set-firewall-rule
+`[1:]
+
+// NewListFirewallRulesCommand returns a command to list firewall rules.
+func NewListFirewallRulesCommand() cmd.Command {
+	return modelcmd.WrapController(&listFirewallRulesCommand{})
+}
+
+type listFirewallRulesCommand struct {
+	modelcmd.ControllerCommandBase
+	api ListFirewallRulesAPI
+	out cmd.Output
+}
+
+// ListFirewallRulesAPI defines the API methods that the list firewall
+// rules command uses.
+type ListFirewallRulesAPI interface {
+	Close() error
+	ListFirewallRules() ([]params.FirewallRule, error)
+}
+
+// firewallRule is the output representation of a firewall rule.
+type firewallRule struct {
+	KnownService   string `yaml:"known-service" json:"known-service"`
+	WhitelistCIDRS string `yaml:"whitelist-subnets" json:"whitelist-subnets"`
+}
+
+// Info implements cmd.Command.
+func (c *listFirewallRulesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "firewall-rules",
+		Purpose: listRulesHelpSummary,
+		Doc:     listRulesHelpDetails,
+		Aliases: []string{"list-firewall-rules"},
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *listFirewallRulesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatListTabular,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *listFirewallRulesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *listFirewallRulesCommand) getAPI() (ListFirewallRulesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return firewallrules.NewClient(root), nil
+}
+
+// Run implements cmd.Command.
+func (c *listFirewallRulesCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	rules, err := client.ListFirewallRules()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := make([]firewallRule, len(rules))
+	for i, rule := range rules {
+		result[i] = firewallRule{
+			KnownService:   rule.KnownService,
+			WhitelistCIDRS: strings.Join(rule.WhitelistCIDRS, ","),
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+func formatListTabular(writer io.Writer, value interface{}) error {
+	rules, ok := value.([]firewallRule)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", rules, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Service", "Whitelist subnets")
+	for _, rule := range rules {
+		w.Println(rule.KnownService, rule.WhitelistCIDRS)
+	}
+	return tw.Flush()
+}
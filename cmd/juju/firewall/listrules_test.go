@@ -0,0 +1,82 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/jujuclient"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ListRulesSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	api   *mockListRulesAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&ListRulesSuite{})
+
+func (s *ListRulesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &mockListRulesAPI{
+		rules: []params.FirewallRule{{
+			KnownService:   "juju-controller",
+			WhitelistCIDRS: []string{"203.0.113.7/32"},
+		}, {
+			KnownService:   "ssh",
+			WhitelistCIDRS: []string{"192.168.1.0/24", "10.0.0.0/8"},
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{}
+}
+
+func (s *ListRulesSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, firewall.NewListFirewallRulesCommandForTest(s.api, s.store), args...)
+}
+
+func (s *ListRulesSuite) TestListTabular(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Service          Whitelist subnets
+juju-controller  203.0.113.7/32
+ssh              192.168.1.0/24,10.0.0.0/8
+`[1:])
+}
+
+func (s *ListRulesSuite) TestListYAML(c *gc.C) {
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- known-service: juju-controller
+  whitelist-subnets: 203.0.113.7/32
+- known-service: ssh
+  whitelist-subnets: 192.168.1.0/24,10.0.0.0/8
+`[1:])
+}
+
+func (s *ListRulesSuite) TestListUnrecognizedArg(c *gc.C) {
+	_, err := s.run(c, "ssh")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["ssh"\]`)
+}
+
+type mockListRulesAPI struct {
+	rules []params.FirewallRule
+}
+
+func (a *mockListRulesAPI) Close() error {
+	return nil
+}
+
+func (a *mockListRulesAPI) ListFirewallRules() ([]params.FirewallRule, error) {
+	return a.rules, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package firewall provides the commands for managing the firewall
+// rules that restrict access to controller-level services.
+package firewall
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// knownServices holds the names of the services whose ingress may be
+// restricted with a firewall rule.
+var knownServices = []string{
+	"juju-application-offer",
+	"juju-controller",
+	"ssh",
+}
+
+var setRuleHelpSummary = `
+Sets a firewall rule.`[1:]
+
+var setRuleHelpDetails = `
+Firewall rules control ingress to the well known services used by
+Juju, such as SSH and the controller API. The rules are applied to all
+models on the controller, on clouds where Juju manages security groups.
+
+The whitelist is a comma separated list of CIDRs from which the service
+may be reached. An empty whitelist allows access from anywhere.
+
+Known services are:
+    ` + strings.Join(knownServices, "\n    ") + `
+
+Examples:
+
+    juju set-firewall-rule ssh --whitelist 192.168.1.0/24,10.0.0.0/8
+    juju set-firewall-rule juju-controller --whitelist 203.0.113.7/32
+
+See also:
+    firewall-rules
+`[1:]
+
+// NewSetFirewallRuleCommand returns a command to set a firewall rule.
+func NewSetFirewallRuleCommand() cmd.Command {
+	return modelcmd.WrapController(&setFirewallRuleCommand{})
+}
+
+type setFirewallRuleCommand struct {
+	modelcmd.ControllerCommandBase
+	api SetFirewallRuleAPI
+
+	service   string
+	whitelist string
+	cidrs     []string
+}
+
+// SetFirewallRuleAPI defines the API methods that the set firewall
+// rule command uses.
+type SetFirewallRuleAPI interface {
+	Close() error
+	SetFirewallRule(service string, whitelistCIDRs []string) error
+}
+
+// Info implements cmd.Command.
+func (c *setFirewallRuleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-firewall-rule",
+		Args:    "<service-name> --whitelist <cidr>[,<cidr>...]",
+		Purpose: setRuleHelpSummary,
+		Doc:     setRuleHelpDetails,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *setFirewallRuleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.whitelist, "whitelist", "", "list of subnets to whitelist")
+}
+
+// Init implements cmd.Command.
+func (c *setFirewallRuleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no well known service specified")
+	}
+	service, err := cmd.ZeroOrOneArgs(args)
+	if err != nil {
+		return err
+	}
+	if !isKnownService(service) {
+		return errors.NotValidf("well known service type %q", service)
+	}
+	c.service = service
+
+	for _, cidr := range strings.Split(c.whitelist, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("CIDR %q", cidr)
+		}
+		c.cidrs = append(c.cidrs, cidr)
+	}
+	return nil
+}
+
+func isKnownService(service string) bool {
+	for _, known := range knownServices {
+		if service == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *setFirewallRuleCommand) getAPI() (SetFirewallRuleAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return firewallrules.NewClient(root), nil
+}
+
+// Run implements cmd.Command.
+func (c *setFirewallRuleCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return errors.Trace(client.SetFirewallRule(c.service, c.cidrs))
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewall_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/firewall"
+	"github.com/juju/juju/jujuclient"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SetRuleSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	api   *mockSetRuleAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&SetRuleSuite{})
+
+func (s *SetRuleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &mockSetRuleAPI{}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{}
+}
+
+func (s *SetRuleSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, firewall.NewSetFirewallRuleCommandForTest(s.api, s.store), args...)
+}
+
+func (s *SetRuleSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no well known service specified",
+	}, {
+		args: []string{"ssh", "http"},
+		err:  `unrecognized args: \["http"\]`,
+	}, {
+		args: []string{"telnet"},
+		err:  `well known service type "telnet" not valid`,
+	}, {
+		args: []string{"ssh", "--whitelist", "10.0.0.0/8,10.1.1.1"},
+		err:  `CIDR "10.1.1.1" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := cmdtesting.InitCommand(firewall.NewSetFirewallRuleCommandForTest(s.api, s.store), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *SetRuleSuite) TestSetRule(c *gc.C) {
+	_, err := s.run(c, "ssh", "--whitelist", "192.168.1.0/24, 10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "SetFirewallRule", "Close")
+	s.api.CheckCall(c, 0, "SetFirewallRule", "ssh", []string{"192.168.1.0/24", "10.0.0.0/8"})
+}
+
+func (s *SetRuleSuite) TestSetRuleEmptyWhitelist(c *gc.C) {
+	_, err := s.run(c, "juju-controller")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "SetFirewallRule", "juju-controller", []string(nil))
+}
+
+func (s *SetRuleSuite) TestSetRuleError(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	_, err := s.run(c, "ssh", "--whitelist", "10.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type mockSetRuleAPI struct {
+	testing.Stub
+}
+
+func (a *mockSetRuleAPI) Close() error {
+	a.MethodCall(a, "Close")
+	return nil
+}
+
+func (a *mockSetRuleAPI) SetFirewallRule(service string, whitelistCIDRs []string) error {
+	a.MethodCall(a, "SetFirewallRule", service, whitelistCIDRs)
+	return a.NextErr()
+}
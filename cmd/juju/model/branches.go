@@ -0,0 +1,457 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/keyvalues"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/modelgeneration"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// BranchAPI defines the API methods used by the branch commands.
+type BranchAPI interface {
+	Close() error
+	AddBranch(branchName string) error
+	TrackBranch(branchName string, entities []names.Tag) error
+	SetBranchConfig(branchName, appName string, options map[string]string, unset []string) error
+	CommitBranch(branchName string) error
+	AbortBranch(branchName string) error
+	Branches() ([]params.Branch, error)
+}
+
+// branchCommandBase is the base type for the commands that manage the
+// branches of a model.
+type branchCommandBase struct {
+	modelcmd.ModelCommandBase
+	api BranchAPI
+}
+
+func (c *branchCommandBase) getAPI() (BranchAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelgeneration.NewClient(root), nil
+}
+
+// initBranchName checks that the first of the args names a branch, and
+// returns the remaining args.
+func initBranchName(args []string, branchName *string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no branch specified")
+	}
+	*branchName = args[0]
+	return args[1:], nil
+}
+
+const branchesSeeAlso = `
+See also:
+    add-branch
+    track
+    branch-config
+    commit-branch
+    abort-branch
+    branches
+`
+
+// NewAddBranchCommand returns a command that adds a branch to a model.
+func NewAddBranchCommand() cmd.Command {
+	return modelcmd.Wrap(&addBranchCommand{})
+}
+
+type addBranchCommand struct {
+	branchCommandBase
+	branchName string
+}
+
+const addBranchDoc = `
+Adds a new, empty branch to the model. A branch stages changes to the
+charm config of applications, which are seen only by the units tracking
+the branch until it is committed, when all units see them.
+
+Branch names may contain lowercase letters, digits and hyphens.
+
+Examples:
+
+    juju add-branch canary
+` + branchesSeeAlso
+
+// Info implements Command.
+func (c *addBranchCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-branch",
+		Args:    "<branch name>",
+		Purpose: "Adds a branch to a model.",
+		Doc:     addBranchDoc,
+	}
+}
+
+// Init implements Command.
+func (c *addBranchCommand) Init(args []string) error {
+	args, err := initBranchName(args, &c.branchName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.
+func (c *addBranchCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	return errors.Trace(client.AddBranch(c.branchName))
+}
+
+// NewTrackCommand returns a command that makes units track a branch.
+func NewTrackCommand() cmd.Command {
+	return modelcmd.Wrap(&trackCommand{})
+}
+
+type trackCommand struct {
+	branchCommandBase
+	branchName string
+	entities   []names.Tag
+}
+
+const trackDoc = `
+Makes the given units, or all units of the given applications, track
+a branch of the model, so that they see the charm config changes staged
+on the branch. A unit may track only one branch at a time, and tracks
+it until the branch is committed or aborted.
+
+Examples:
+
+    juju track canary wordpress/0
+    juju track canary mysql
+` + branchesSeeAlso
+
+// Info implements Command.
+func (c *trackCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "track",
+		Args:    "<branch name> <unit or application name> [...]",
+		Purpose: "Makes units track a branch of a model.",
+		Doc:     trackDoc,
+	}
+}
+
+// Init implements Command.
+func (c *trackCommand) Init(args []string) error {
+	args, err := initBranchName(args, &c.branchName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) == 0 {
+		return errors.New("no units or applications specified")
+	}
+	for _, arg := range args {
+		switch {
+		case names.IsValidUnit(arg):
+			c.entities = append(c.entities, names.NewUnitTag(arg))
+		case names.IsValidApplication(arg):
+			c.entities = append(c.entities, names.NewApplicationTag(arg))
+		default:
+			return errors.Errorf("invalid unit or application name %q", arg)
+		}
+	}
+	return nil
+}
+
+// Run implements Command.
+func (c *trackCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	return errors.Trace(client.TrackBranch(c.branchName, c.entities))
+}
+
+// NewBranchConfigCommand returns a command that stages charm config
+// changes on a branch.
+func NewBranchConfigCommand() cmd.Command {
+	return modelcmd.Wrap(&branchConfigCommand{})
+}
+
+type branchConfigCommand struct {
+	branchCommandBase
+	branchName string
+	appName    string
+	options    map[string]string
+	reset      string
+	unset      []string
+}
+
+const branchConfigDoc = `
+Stages changes to an application's charm config on a branch of the
+model. The changes are seen only by the units tracking the branch until
+the branch is committed.
+
+Settings given as key=value are changed to the value; those given with
+--reset are reset to the charm defaults.
+
+Examples:
+
+    juju branch-config canary wordpress blog-title="New title"
+    juju branch-config canary wordpress --reset blog-title,skill-level
+` + branchesSeeAlso
+
+// Info implements Command.
+func (c *branchConfigCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "branch-config",
+		Args:    "<branch name> <application name> [<key>=<value> ...]",
+		Purpose: "Stages charm config changes on a branch of a model.",
+		Doc:     branchConfigDoc,
+	}
+}
+
+// SetFlags implements Command.
+func (c *branchConfigCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.reset, "reset", "", "Reset the comma-separated settings to the charm defaults")
+}
+
+// Init implements Command.
+func (c *branchConfigCommand) Init(args []string) error {
+	args, err := initBranchName(args, &c.branchName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) == 0 {
+		return errors.New("no application specified")
+	}
+	c.appName, args = args[0], args[1:]
+	if !names.IsValidApplication(c.appName) {
+		return errors.Errorf("invalid application name %q", c.appName)
+	}
+	if c.reset != "" {
+		for _, name := range strings.Split(c.reset, ",") {
+			c.unset = append(c.unset, strings.TrimSpace(name))
+		}
+	}
+	if len(args) == 0 && len(c.unset) == 0 {
+		return errors.New("no config changes specified")
+	}
+	c.options, err = keyvalues.Parse(args, true)
+	return errors.Trace(err)
+}
+
+// Run implements Command.
+func (c *branchConfigCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	return errors.Trace(client.SetBranchConfig(c.branchName, c.appName, c.options, c.unset))
+}
+
+// NewCommitBranchCommand returns a command that commits a branch.
+func NewCommitBranchCommand() cmd.Command {
+	return modelcmd.Wrap(&completeBranchCommand{commit: true})
+}
+
+// NewAbortBranchCommand returns a command that aborts a branch.
+func NewAbortBranchCommand() cmd.Command {
+	return modelcmd.Wrap(&completeBranchCommand{})
+}
+
+// completeBranchCommand commits or aborts a branch.
+type completeBranchCommand struct {
+	branchCommandBase
+	branchName string
+	commit     bool
+}
+
+const commitBranchDoc = `
+Commits a branch of the model, applying the charm config changes staged
+on it to all units at once. Either all of the changes are made or none
+of them are.
+
+Examples:
+
+    juju commit-branch canary
+` + branchesSeeAlso
+
+const abortBranchDoc = `
+Aborts a branch of the model, discarding the charm config changes staged
+on it. Units tracking the branch revert to the model's current config.
+
+Examples:
+
+    juju abort-branch canary
+` + branchesSeeAlso
+
+// Info implements Command.
+func (c *completeBranchCommand) Info() *cmd.Info {
+	if c.commit {
+		return &cmd.Info{
+			Name:    "commit-branch",
+			Args:    "<branch name>",
+			Purpose: "Applies the changes staged on a branch to all units.",
+			Doc:     commitBranchDoc,
+		}
+	}
+	return &cmd.Info{
+		Name:    "abort-branch",
+		Args:    "<branch name>",
+		Purpose: "Discards the changes staged on a branch.",
+		Doc:     abortBranchDoc,
+	}
+}
+
+// Init implements Command.
+func (c *completeBranchCommand) Init(args []string) error {
+	args, err := initBranchName(args, &c.branchName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.
+func (c *completeBranchCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if c.commit {
+		return errors.Trace(client.CommitBranch(c.branchName))
+	}
+	return errors.Trace(client.AbortBranch(c.branchName))
+}
+
+// NewBranchesCommand returns a command that lists the branches of a
+// model.
+func NewBranchesCommand() cmd.Command {
+	return modelcmd.Wrap(&branchesCommand{})
+}
+
+type branchesCommand struct {
+	branchCommandBase
+	out cmd.Output
+}
+
+const branchesDoc = `
+Lists the branches of the model that have been neither committed nor
+aborted, with the units tracking them and the charm config changes
+staged on them.
+
+Examples:
+
+    juju branches
+    juju branches --format yaml
+` + branchesSeeAlso
+
+// Info implements Command.
+func (c *branchesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "branches",
+		Purpose: "Lists the branches of a model.",
+		Doc:     branchesDoc,
+		Aliases: []string{"list-branches"},
+	}
+}
+
+// SetFlags implements Command.
+func (c *branchesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatBranchesTabular,
+	})
+}
+
+// Init implements Command.
+func (c *branchesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// branchInfo is the serialisation of a branch.
+type branchInfo struct {
+	CreatedBy string                            `yaml:"created-by" json:"created-by"`
+	Units     map[string][]string               `yaml:"units,omitempty" json:"units,omitempty"`
+	Config    map[string]map[string]interface{} `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// Run implements Command.
+func (c *branchesCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	branches, err := client.Branches()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(branches) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("The model has no branches.")
+		return nil
+	}
+	results := make(map[string]branchInfo, len(branches))
+	for _, branch := range branches {
+		results[branch.BranchName] = branchInfo{
+			CreatedBy: branch.CreatedBy,
+			Units:     branch.AssignedUnits,
+			Config:    branch.Config,
+		}
+	}
+	return c.out.Write(ctx, results)
+}
+
+func formatBranchesTabular(writer io.Writer, value interface{}) error {
+	branches, ok := value.(map[string]branchInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", branches, value)
+	}
+	branchNames := make([]string, 0, len(branches))
+	for name := range branches {
+		branchNames = append(branchNames, name)
+	}
+	sort.Strings(branchNames)
+
+	tw := output.TabWriter(writer)
+	fmt.Fprintln(tw, "Branch\tCreated by\tUnits\tChanged applications")
+	for _, name := range branchNames {
+		branch := branches[name]
+		var units, apps []string
+		for _, appUnits := range branch.Units {
+			units = append(units, appUnits...)
+		}
+		for app := range branch.Config {
+			apps = append(apps, app)
+		}
+		sort.Strings(units)
+		sort.Strings(apps)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			name,
+			branch.CreatedBy,
+			strings.Join(units, ","),
+			strings.Join(apps, ","),
+		)
+	}
+	return tw.Flush()
+}
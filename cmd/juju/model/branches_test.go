@@ -0,0 +1,225 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type BranchCommandsSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api   *fakeBranchAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&BranchCommandsSuite{})
+
+type fakeBranchAPI struct {
+	gitjujutesting.Stub
+	branches []params.Branch
+}
+
+func (f *fakeBranchAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeBranchAPI) AddBranch(branchName string) error {
+	f.MethodCall(f, "AddBranch", branchName)
+	return f.NextErr()
+}
+
+func (f *fakeBranchAPI) TrackBranch(branchName string, entities []names.Tag) error {
+	f.MethodCall(f, "TrackBranch", branchName, entities)
+	return f.NextErr()
+}
+
+func (f *fakeBranchAPI) SetBranchConfig(branchName, appName string, options map[string]string, unset []string) error {
+	f.MethodCall(f, "SetBranchConfig", branchName, appName, options, unset)
+	return f.NextErr()
+}
+
+func (f *fakeBranchAPI) CommitBranch(branchName string) error {
+	f.MethodCall(f, "CommitBranch", branchName)
+	return f.NextErr()
+}
+
+func (f *fakeBranchAPI) AbortBranch(branchName string) error {
+	f.MethodCall(f, "AbortBranch", branchName)
+	return f.NextErr()
+}
+
+func (f *fakeBranchAPI) Branches() ([]params.Branch, error) {
+	f.MethodCall(f, "Branches")
+	return f.branches, f.NextErr()
+}
+
+func (s *BranchCommandsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeBranchAPI{
+		branches: []params.Branch{{
+			BranchName: "canary",
+			CreatedBy:  "admin",
+			AssignedUnits: map[string][]string{
+				"wordpress": {"wordpress/1", "wordpress/0"},
+				"mysql":     {"mysql/0"},
+			},
+			Config: map[string]map[string]interface{}{
+				"wordpress": {"blog-title": "canary"},
+			},
+		}, {
+			BranchName: "beta",
+			CreatedBy:  "bob",
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *BranchCommandsSuite) run(c *gc.C, command cmd.Command, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+func (s *BranchCommandsSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		command cmd.Command
+		args    []string
+		err     string
+	}{{
+		command: model.NewAddBranchCommandForTest(s.api, s.store),
+		err:     "no branch specified",
+	}, {
+		command: model.NewAddBranchCommandForTest(s.api, s.store),
+		args:    []string{"canary", "extra"},
+		err:     `unrecognized args: \["extra"\]`,
+	}, {
+		command: model.NewTrackCommandForTest(s.api, s.store),
+		args:    []string{"canary"},
+		err:     "no units or applications specified",
+	}, {
+		command: model.NewTrackCommandForTest(s.api, s.store),
+		args:    []string{"canary", "Wordpress"},
+		err:     `invalid unit or application name "Wordpress"`,
+	}, {
+		command: model.NewBranchConfigCommandForTest(s.api, s.store),
+		args:    []string{"canary"},
+		err:     "no application specified",
+	}, {
+		command: model.NewBranchConfigCommandForTest(s.api, s.store),
+		args:    []string{"canary", "wordpress"},
+		err:     "no config changes specified",
+	}, {
+		command: model.NewBranchConfigCommandForTest(s.api, s.store),
+		args:    []string{"canary", "wordpress", "blog-title"},
+		err:     `expected "key=value", got "blog-title"`,
+	}, {
+		command: model.NewCompleteBranchCommandForTest(s.api, true, s.store),
+		err:     "no branch specified",
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.run(c, test.command, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	s.api.CheckNoCalls(c)
+}
+
+func (s *BranchCommandsSuite) TestAddBranch(c *gc.C) {
+	_, err := s.run(c, model.NewAddBranchCommandForTest(s.api, s.store), "canary")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{
+		{"AddBranch", []interface{}{"canary"}},
+		{"Close", nil},
+	})
+}
+
+func (s *BranchCommandsSuite) TestTrack(c *gc.C) {
+	_, err := s.run(c, model.NewTrackCommandForTest(s.api, s.store), "canary", "wordpress/0", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "TrackBranch", "canary", []names.Tag{
+		names.NewUnitTag("wordpress/0"),
+		names.NewApplicationTag("mysql"),
+	})
+}
+
+func (s *BranchCommandsSuite) TestBranchConfig(c *gc.C) {
+	_, err := s.run(c, model.NewBranchConfigCommandForTest(s.api, s.store),
+		"canary", "wordpress", "blog-title=New title", "--reset", "skill-level, outlook")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "SetBranchConfig", "canary", "wordpress",
+		map[string]string{"blog-title": "New title"},
+		[]string{"skill-level", "outlook"},
+	)
+}
+
+func (s *BranchCommandsSuite) TestCommitBranch(c *gc.C) {
+	_, err := s.run(c, model.NewCompleteBranchCommandForTest(s.api, true, s.store), "canary")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "CommitBranch", "Close")
+	s.api.CheckCall(c, 0, "CommitBranch", "canary")
+}
+
+func (s *BranchCommandsSuite) TestAbortBranch(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	_, err := s.run(c, model.NewCompleteBranchCommandForTest(s.api, false, s.store), "canary")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.api.CheckCallNames(c, "AbortBranch", "Close")
+}
+
+func (s *BranchCommandsSuite) TestBranchesTabular(c *gc.C) {
+	ctx, err := s.run(c, model.NewBranchesCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Branch  Created by  Units                            Changed applications
+beta    bob                                          
+canary  admin       mysql/0,wordpress/0,wordpress/1  wordpress
+`[1:])
+}
+
+func (s *BranchCommandsSuite) TestBranchesYAML(c *gc.C) {
+	ctx, err := s.run(c, model.NewBranchesCommandForTest(s.api, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+beta:
+  created-by: bob
+canary:
+  created-by: admin
+  units:
+    mysql:
+    - mysql/0
+    wordpress:
+    - wordpress/1
+    - wordpress/0
+  config:
+    wordpress:
+      blog-title: canary
+`[1:])
+}
+
+func (s *BranchCommandsSuite) TestBranchesEmpty(c *gc.C) {
+	s.api.branches = nil
+	ctx, err := s.run(c, model.NewBranchesCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "The model has no branches.\n")
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/utils/clock"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/base"
@@ -50,6 +51,55 @@ func NewShowCommandForTest(api ShowModelAPI, refreshFunc func(jujuclient.ClientS
 	return modelcmd.Wrap(cmd, modelcmd.WrapSkipModelFlags)
 }
 
+// NewHistoryCommandForTest returns a HistoryCommand with the api and
+// clock provided as specified.
+func NewHistoryCommandForTest(api HistoryAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	cmd := &historyCommand{api: api, clock: clock}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewAddBranchCommandForTest returns an AddBranchCommand with the api
+// provided as specified.
+func NewAddBranchCommandForTest(api BranchAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &addBranchCommand{branchCommandBase: branchCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewTrackCommandForTest returns a TrackCommand with the api provided
+// as specified.
+func NewTrackCommandForTest(api BranchAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &trackCommand{branchCommandBase: branchCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewBranchConfigCommandForTest returns a BranchConfigCommand with the
+// api provided as specified.
+func NewBranchConfigCommandForTest(api BranchAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &branchConfigCommand{branchCommandBase: branchCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewCompleteBranchCommandForTest returns a CommitBranchCommand, or an
+// AbortBranchCommand if commit is false, with the api provided as
+// specified.
+func NewCompleteBranchCommandForTest(api BranchAPI, commit bool, store jujuclient.ClientStore) cmd.Command {
+	cmd := &completeBranchCommand{branchCommandBase: branchCommandBase{api: api}, commit: commit}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewBranchesCommandForTest returns a BranchesCommand with the api
+// provided as specified.
+func NewBranchesCommandForTest(api BranchAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &branchesCommand{branchCommandBase: branchCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 // NewDumpCommandForTest returns a DumpCommand with the api provided as specified.
 func NewDumpCommandForTest(api DumpModelAPI, store jujuclient.ClientStore) cmd.Command {
 	cmd := &dumpCommand{api: api}
@@ -0,0 +1,220 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/modelhistory"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// NewHistoryCommand returns a command that shows the history of
+// significant events in a model.
+func NewHistoryCommand() cmd.Command {
+	return modelcmd.Wrap(&historyCommand{clock: clock.WallClock})
+}
+
+// HistoryAPI defines the API methods used by the history command.
+type HistoryAPI interface {
+	Close() error
+	History(since time.Time, limit int, kinds []string) (params.ModelEvents, error)
+}
+
+type historyCommand struct {
+	modelcmd.ModelCommandBase
+	out   cmd.Output
+	api   HistoryAPI
+	clock clock.Clock
+
+	since   string
+	limit   int
+	kinds   string
+	isoTime bool
+
+	sinceDuration time.Duration
+	sinceDate     time.Time
+}
+
+const historyDoc = `
+Shows a merged, time-ordered history of the significant events in a
+model: applications deployed and units added, changes to model and
+application config, charm and agent upgrades, hook failures and
+migrations.
+
+By default the events of the past day are shown. Use --since with a
+duration, such as 2h or 30m, or with a date in the format YYYY-MM-DD
+to show events since then, and -n to show only the most recent events.
+
+Events may be restricted to those of the given kinds with --kind, which
+accepts a comma-separated list of:
+    deploy, config, upgrade, hook-failure, migration
+
+Deploy, config and upgrade events are taken from the controller's audit
+log, so they are shown only if the controller was bootstrapped with
+auditing-enabled=true.
+
+Examples:
+
+    juju history
+    juju history --since 2h --kind deploy,upgrade
+    juju history --since 2017-06-01 -n 20
+
+See also:
+    show-status-log
+    status
+`
+
+// Info implements Command.
+func (c *historyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "history",
+		Purpose: "Shows the history of significant events in a model.",
+		Doc:     historyDoc,
+	}
+}
+
+// SetFlags implements Command.
+func (c *historyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+	})
+	f.StringVar(&c.since, "since", "24h", "Show events since this long ago, or since this date (YYYY-MM-DD)")
+	f.IntVar(&c.limit, "n", 0, "Show only the last N events")
+	f.StringVar(&c.kinds, "kind", "", "Show only events of these comma-separated kinds")
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+}
+
+// Init implements Command.
+func (c *historyCommand) Init(args []string) error {
+	if c.limit < 0 {
+		return errors.Errorf("invalid number of events %d", c.limit)
+	}
+	if d, err := time.ParseDuration(c.since); err == nil {
+		if d <= 0 {
+			return errors.Errorf("invalid --since duration %q: must be positive", c.since)
+		}
+		c.sinceDuration = d
+	} else if t, err := time.Parse("2006-01-02", c.since); err == nil {
+		c.sinceDate = t
+	} else {
+		return errors.Errorf("invalid --since %q: expected a duration or a date (YYYY-MM-DD)", c.since)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *historyCommand) getAPI() (HistoryAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelhistory.NewClient(root), nil
+}
+
+// historyEvent is the serialisation of a model event.
+type historyEvent struct {
+	Time    time.Time `yaml:"time" json:"time"`
+	Kind    string    `yaml:"kind" json:"kind"`
+	Entity  string    `yaml:"entity" json:"entity"`
+	Origin  string    `yaml:"origin,omitempty" json:"origin,omitempty"`
+	Summary string    `yaml:"summary" json:"summary"`
+}
+
+// Run implements Command.
+func (c *historyCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	since := c.sinceDate
+	if c.sinceDuration > 0 {
+		since = c.clock.Now().Add(-c.sinceDuration)
+	}
+	var kinds []string
+	if c.kinds != "" {
+		for _, kind := range strings.Split(c.kinds, ",") {
+			kinds = append(kinds, strings.TrimSpace(kind))
+		}
+	}
+	result, err := client.History(since, c.limit, kinds)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if result.AuditingDisabled {
+		ctx.Infof("Deploy, config and upgrade events are not shown because auditing is not enabled on the controller.")
+	}
+	events := result.Events
+	if len(events) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No events in the model's history since %s.", common.FormatTime(&since, c.isoTime))
+		return nil
+	}
+
+	results := make([]historyEvent, len(events))
+	for i, event := range events {
+		results[i] = historyEvent{
+			Time:    event.Timestamp,
+			Kind:    event.Kind,
+			Entity:  entityName(event.Entity),
+			Origin:  entityName(event.Origin),
+			Summary: event.Summary,
+		}
+	}
+	return c.out.Write(ctx, results)
+}
+
+// entityName returns a friendlier name for the entity with the given
+// tag.
+func entityName(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	t, err := names.ParseTag(tag)
+	if err != nil {
+		return tag
+	}
+	if t.Kind() == names.ModelTagKind {
+		return "model"
+	}
+	return t.Id()
+}
+
+func (c *historyCommand) formatTabular(writer io.Writer, value interface{}) error {
+	events, ok := value.([]historyEvent)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", events, value)
+	}
+	tw := output.TabWriter(writer)
+	fmt.Fprintln(tw, "Time\tKind\tEntity\tOrigin\tSummary")
+	for _, event := range events {
+		t := event.Time
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			common.FormatTime(&t, c.isoTime),
+			event.Kind,
+			event.Entity,
+			event.Origin,
+			event.Summary,
+		)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+var historyNow = time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+
+type HistoryCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api   *fakeHistoryAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&HistoryCommandSuite{})
+
+type fakeHistoryAPI struct {
+	gitjujutesting.Stub
+	events           []params.ModelEvent
+	auditingDisabled bool
+}
+
+func (f *fakeHistoryAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeHistoryAPI) History(since time.Time, limit int, kinds []string) (params.ModelEvents, error) {
+	f.MethodCall(f, "History", since, limit, kinds)
+	return params.ModelEvents{
+		Events:           f.events,
+		AuditingDisabled: f.auditingDisabled,
+	}, f.NextErr()
+}
+
+func (s *HistoryCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeHistoryAPI{
+		events: []params.ModelEvent{{
+			Timestamp: historyNow.Add(-time.Hour),
+			Kind:      "deploy",
+			Entity:    testing.ModelTag.String(),
+			Origin:    "user-bob",
+			Summary:   "Application.Deploy",
+		}, {
+			Timestamp: historyNow.Add(-time.Minute),
+			Kind:      "hook-failure",
+			Entity:    "unit-mysql-0",
+			Summary:   `hook failed: "install"`,
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *HistoryCommandSuite) run(c *gc.C, args ...string) (string, error) {
+	command := model.NewHistoryCommandForTest(s.api, gitjujutesting.NewClock(historyNow), s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, args...)
+	if err != nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), nil
+}
+
+func (s *HistoryCommandSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--since", "yesterday"},
+		err:  `invalid --since "yesterday": expected a duration or a date \(YYYY-MM-DD\)`,
+	}, {
+		args: []string{"--since", "-1h"},
+		err:  `invalid --since duration "-1h": must be positive`,
+	}, {
+		args: []string{"-n", "-1"},
+		err:  `invalid number of events -1`,
+	}, {
+		args: []string{"extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *HistoryCommandSuite) TestHistoryTabular(c *gc.C) {
+	out, err := s.run(c, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+Time                  Kind          Entity   Origin  Summary
+2017-06-01 11:00:00Z  deploy        model    bob     Application.Deploy
+2017-06-01 11:59:00Z  hook-failure  mysql/0          hook failed: "install"
+`[1:])
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{
+		{"History", []interface{}{historyNow.Add(-24 * time.Hour), 0, []string(nil)}},
+		{"Close", nil},
+	})
+}
+
+func (s *HistoryCommandSuite) TestHistoryYAML(c *gc.C) {
+	out, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+- time: 2017-06-01T11:00:00Z
+  kind: deploy
+  entity: model
+  origin: bob
+  summary: Application.Deploy
+- time: 2017-06-01T11:59:00Z
+  kind: hook-failure
+  entity: mysql/0
+  summary: 'hook failed: "install"'
+`[1:])
+}
+
+func (s *HistoryCommandSuite) TestHistoryArgs(c *gc.C) {
+	_, err := s.run(c, "--since", "2h", "-n", "5", "--kind", "deploy, upgrade")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "History", historyNow.Add(-2*time.Hour), 5, []string{"deploy", "upgrade"})
+
+	s.api.ResetCalls()
+	_, err = s.run(c, "--since", "2017-05-30")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "History", time.Date(2017, 5, 30, 0, 0, 0, 0, time.UTC), 0, []string(nil))
+}
+
+func (s *HistoryCommandSuite) TestHistoryEmpty(c *gc.C) {
+	s.api.events = nil
+	command := model.NewHistoryCommandForTest(s.api, gitjujutesting.NewClock(historyNow), s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No events in the model's history since 2017-05-31 12:00:00Z.\n")
+}
+
+func (s *HistoryCommandSuite) TestHistoryAuditingDisabled(c *gc.C) {
+	s.api.auditingDisabled = true
+	command := model.NewHistoryCommandForTest(s.api, gitjujutesting.NewClock(historyNow), s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Time                  Kind          Entity   Origin  Summary
+2017-06-01 11:00:00Z  deploy        model    bob     Application.Deploy
+2017-06-01 11:59:00Z  hook-failure  mysql/0          hook failed: "install"
+`[1:])
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals,
+		"Deploy, config and upgrade events are not shown because auditing is not enabled on the controller.\n")
+}
+
+func (s *HistoryCommandSuite) TestHistoryError(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+	s.api.CheckCallNames(c, "History", "Close")
+}
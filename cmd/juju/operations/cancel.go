@@ -0,0 +1,102 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+var cancelOperationHelpSummary = `
+Cancels the pending tasks of operations.`[1:]
+
+var cancelOperationHelpDetails = `
+Cancels those tasks of the given operations that have not yet started.
+Tasks that are already running are left to complete.
+
+Examples:
+
+    juju cancel-operation 3
+    juju cancel-operation 3 4
+
+See also:
+    operations
+    run
+`[1:]
+
+// NewCancelOperationCommand returns a command to cancel operations.
+func NewCancelOperationCommand() cmd.Command {
+	return modelcmd.Wrap(&cancelOperationCommand{})
+}
+
+type cancelOperationCommand struct {
+	operationsCommandBase
+	out cmd.Output
+	ids []string
+}
+
+// Info implements cmd.Command.
+func (c *cancelOperationCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cancel-operation",
+		Args:    "<operation id> ...",
+		Purpose: cancelOperationHelpSummary,
+		Doc:     cancelOperationHelpDetails,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *cancelOperationCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
+}
+
+// Init implements cmd.Command.
+func (c *cancelOperationCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no operations specified")
+	}
+	c.ids = args
+	return nil
+}
+
+// Run implements cmd.Command.
+func (c *cancelOperationCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.CancelOperations(c.ids)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	var ops []operation
+	var failed []string
+	for _, result := range results {
+		if result.Error != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", result.OperationID, result.Error))
+			continue
+		}
+		ops = append(ops, makeOperation(result))
+	}
+	if len(ops) > 0 {
+		if err := c.out.Write(ctx, ops); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("cannot cancel operations:\n  %s", strings.Join(failed, "\n  "))
+	}
+	return nil
+}
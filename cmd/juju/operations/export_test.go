@@ -0,0 +1,38 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operations
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+// NewListOperationsCommandForTest returns an operations command that
+// uses the given API and client store.
+func NewListOperationsCommandForTest(api OperationsAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &listOperationsCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
+// NewShowTaskCommandForTest returns a show-task command that uses the
+// given API and client store.
+func NewShowTaskCommandForTest(api OperationsAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &showTaskCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
+// NewCancelOperationCommandForTest returns a cancel-operation command
+// that uses the given API and client store.
+func NewCancelOperationCommandForTest(api OperationsAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &cancelOperationCommand{}
+	c.api = api
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
@@ -0,0 +1,168 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operations
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+var listOperationsHelpSummary = `
+Lists operations and their tasks.`[1:]
+
+var listOperationsHelpDetails = `
+Lists the operations in the model, such as those started by juju run,
+with the number of tasks each has. The operations listed may be limited
+to those with tasks of the given statuses, actions, or receivers, in
+which case only the matching tasks are counted.
+
+Valid statuses are pending, running, completed, failed and cancelled.
+
+Examples:
+
+    juju operations
+    juju operations --status running,pending
+    juju operations --applications mysql --machines 0
+
+See also:
+    run
+    show-task
+    cancel-operation
+`[1:]
+
+var validTaskStatus = []string{
+	params.ActionPending,
+	params.ActionRunning,
+	params.ActionCompleted,
+	params.ActionFailed,
+	params.ActionCancelled,
+}
+
+// NewListOperationsCommand returns a command to list operations.
+func NewListOperationsCommand() cmd.Command {
+	return modelcmd.Wrap(&listOperationsCommand{})
+}
+
+type listOperationsCommand struct {
+	operationsCommandBase
+	out          cmd.Output
+	status       []string
+	actions      []string
+	applications []string
+	units        []string
+	machines     []string
+}
+
+// Info implements cmd.Command.
+func (c *listOperationsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "operations",
+		Purpose: listOperationsHelpSummary,
+		Doc:     listOperationsHelpDetails,
+		Aliases: []string{"list-operations"},
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *listOperationsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatListTabular,
+	})
+	f.Var(cmd.NewStringsValue(nil, &c.status), "status", "Comma separated list of task statuses to include")
+	f.Var(cmd.NewStringsValue(nil, &c.actions), "actions", "Comma separated list of action names to include")
+	f.Var(cmd.NewStringsValue(nil, &c.applications), "applications", "Comma separated list of applications whose units' tasks are included")
+	f.Var(cmd.NewStringsValue(nil, &c.units), "units", "Comma separated list of units whose tasks are included")
+	f.Var(cmd.NewStringsValue(nil, &c.machines), "machines", "Comma separated list of machines whose tasks are included")
+}
+
+// Init implements cmd.Command.
+func (c *listOperationsCommand) Init(args []string) error {
+	for _, status := range c.status {
+		if !isValidTaskStatus(status) {
+			return errors.Errorf("%q is not a valid task status, want one of %s",
+				status, strings.Join(validTaskStatus, ", "))
+		}
+	}
+	for _, name := range c.applications {
+		if !names.IsValidApplication(name) {
+			return errors.NotValidf("application name %q", name)
+		}
+	}
+	for _, name := range c.units {
+		if !names.IsValidUnit(name) {
+			return errors.NotValidf("unit name %q", name)
+		}
+	}
+	for _, id := range c.machines {
+		if !names.IsValidMachine(id) {
+			return errors.NotValidf("machine id %q", id)
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func isValidTaskStatus(status string) bool {
+	for _, valid := range validTaskStatus {
+		if status == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Run implements cmd.Command.
+func (c *listOperationsCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.ListOperations(params.OperationQueryArgs{
+		Applications: c.applications,
+		Units:        c.units,
+		Machines:     c.machines,
+		ActionNames:  c.actions,
+		Status:       c.status,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No operations to display.")
+		return nil
+	}
+	ops := make([]operation, len(results))
+	for i, result := range results {
+		ops[i] = makeOperation(result)
+	}
+	return c.out.Write(ctx, ops)
+}
+
+func formatListTabular(writer io.Writer, value interface{}) error {
+	ops, ok := value.([]operation)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", ops, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Id", "Status", "Enqueued", "Tasks", "Summary")
+	for _, op := range ops {
+		w.Println(op.ID, op.Status, op.Enqueued, fmt.Sprint(len(op.Tasks)), op.Summary)
+	}
+	return tw.Flush()
+}
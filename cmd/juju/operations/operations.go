@@ -0,0 +1,122 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package operations provides the commands that list, show and cancel
+// operations and their tasks.
+package operations
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/operations"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// OperationsAPI defines the API methods used by the operations
+// commands.
+type OperationsAPI interface {
+	Close() error
+	ListOperations(params.OperationQueryArgs) ([]params.OperationResult, error)
+	CancelOperations(ids []string) ([]params.OperationResult, error)
+	Tasks(params.Entities) (params.ActionResults, error)
+}
+
+// operationsCommandBase is the base type for the operations commands.
+type operationsCommandBase struct {
+	modelcmd.ModelCommandBase
+	api OperationsAPI
+}
+
+func (c *operationsCommandBase) getAPI() (OperationsAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return operations.NewClient(root), nil
+}
+
+// task is the output representation of a task.
+type task struct {
+	ID        string                 `yaml:"id" json:"id"`
+	Operation string                 `yaml:"operation,omitempty" json:"operation,omitempty"`
+	Action    string                 `yaml:"action,omitempty" json:"action,omitempty"`
+	Receiver  string                 `yaml:"receiver,omitempty" json:"receiver,omitempty"`
+	Status    string                 `yaml:"status,omitempty" json:"status,omitempty"`
+	Message   string                 `yaml:"message,omitempty" json:"message,omitempty"`
+	Enqueued  string                 `yaml:"enqueued,omitempty" json:"enqueued,omitempty"`
+	Started   string                 `yaml:"started,omitempty" json:"started,omitempty"`
+	Completed string                 `yaml:"completed,omitempty" json:"completed,omitempty"`
+	Output    map[string]interface{} `yaml:"output,omitempty" json:"output,omitempty"`
+	Error     string                 `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// operation is the output representation of an operation.
+type operation struct {
+	ID       string `yaml:"id" json:"id"`
+	Summary  string `yaml:"summary" json:"summary"`
+	Status   string `yaml:"status" json:"status"`
+	Enqueued string `yaml:"enqueued,omitempty" json:"enqueued,omitempty"`
+	Tasks    []task `yaml:"tasks,omitempty" json:"tasks,omitempty"`
+	Error    string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+func makeTask(result params.ActionResult) task {
+	t := task{
+		Operation: result.Operation,
+		Status:    result.Status,
+		Message:   result.Message,
+		Enqueued:  formatTime(result.Enqueued),
+		Started:   formatTime(result.Started),
+		Completed: formatTime(result.Completed),
+		Output:    result.Output,
+	}
+	if result.Error != nil {
+		t.Error = result.Error.Error()
+	}
+	if result.Action != nil {
+		t.Action = result.Action.Name
+		t.ID = result.Action.Tag
+		if tag, err := names.ParseActionTag(result.Action.Tag); err == nil {
+			t.ID = tag.Id()
+		}
+		t.Receiver = result.Action.Receiver
+		if tag, err := names.ActionReceiverFromTag(result.Action.Receiver); err == nil {
+			t.Receiver = tag.Id()
+		}
+	}
+	return t
+}
+
+func makeOperation(result params.OperationResult) operation {
+	op := operation{
+		ID:       result.OperationID,
+		Summary:  result.Summary,
+		Status:   result.Status,
+		Enqueued: formatTime(result.Enqueued),
+		Tasks:    make([]task, len(result.Tasks)),
+	}
+	if result.Error != nil {
+		op.Error = result.Error.Error()
+	}
+	for i, t := range result.Tasks {
+		op.Tasks[i] = makeTask(t)
+		// The operation is implied.
+		op.Tasks[i].Operation = ""
+		op.Tasks[i].Output = nil
+	}
+	return op
+}
@@ -0,0 +1,199 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operations_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/operations"
+	"github.com/juju/juju/jujuclient"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const taskID = "01234567-89ab-cdef-0123-456789abcdef"
+
+type OperationsSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	api   *mockOperationsAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&OperationsSuite{})
+
+func (s *OperationsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	enqueued := time.Date(2017, 3, 1, 10, 0, 0, 0, time.UTC)
+	s.api = &mockOperationsAPI{
+		operations: []params.OperationResult{{
+			OperationID: "1",
+			Summary:     "run hostname",
+			Enqueued:    enqueued,
+			Status:      "completed",
+			Tasks: []params.ActionResult{{
+				Action: &params.Action{
+					Tag:      "action-" + taskID,
+					Receiver: "unit-mysql-0",
+					Name:     "juju-run",
+				},
+				Enqueued:  enqueued,
+				Completed: enqueued.Add(time.Second),
+				Status:    "completed",
+				Operation: "1",
+				Output:    map[string]interface{}{"Stdout": "box\n"},
+			}},
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "ctrl"
+	s.store.Controllers["ctrl"] = jujuclient.ControllerDetails{}
+	s.store.Models["ctrl"] = &jujuclient.ControllerModels{
+		CurrentModel: "admin/default",
+		Models: map[string]jujuclient.ModelDetails{
+			"admin/default": {"default-uuid"},
+		},
+	}
+	s.store.Accounts["ctrl"] = jujuclient.AccountDetails{User: "admin"}
+}
+
+func (s *OperationsSuite) TestListTabular(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, operations.NewListOperationsCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Id  Status     Enqueued             Tasks  Summary
+1   completed  2017-03-01 10:00:00  1      run hostname
+`[1:])
+	s.api.CheckCall(c, 0, "ListOperations", params.OperationQueryArgs{})
+}
+
+func (s *OperationsSuite) TestListFilters(c *gc.C) {
+	s.api.operations = nil
+	ctx, err := cmdtesting.RunCommand(c, operations.NewListOperationsCommandForTest(s.api, s.store),
+		"--status", "running,pending", "--units", "mysql/0", "--machines", "0", "--applications", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No operations to display.\n")
+	s.api.CheckCall(c, 0, "ListOperations", params.OperationQueryArgs{
+		Applications: []string{"wordpress"},
+		Units:        []string{"mysql/0"},
+		Machines:     []string{"0"},
+		Status:       []string{"running", "pending"},
+	})
+}
+
+func (s *OperationsSuite) TestListInvalidStatus(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, operations.NewListOperationsCommandForTest(s.api, s.store),
+		"--status", "done")
+	c.Assert(err, gc.ErrorMatches, `"done" is not a valid task status, want one of pending, running, completed, failed, cancelled`)
+}
+
+func (s *OperationsSuite) TestListYAML(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, operations.NewListOperationsCommandForTest(s.api, s.store),
+		"--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- id: "1"
+  summary: run hostname
+  status: completed
+  enqueued: 2017-03-01 10:00:00
+  tasks:
+  - id: 01234567-89ab-cdef-0123-456789abcdef
+    action: juju-run
+    receiver: mysql/0
+    status: completed
+    enqueued: 2017-03-01 10:00:00
+    completed: 2017-03-01 10:00:01
+`[1:])
+}
+
+func (s *OperationsSuite) TestShowTask(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, operations.NewShowTaskCommandForTest(s.api, s.store), taskID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+id: 01234567-89ab-cdef-0123-456789abcdef
+operation: "1"
+action: juju-run
+receiver: mysql/0
+status: completed
+enqueued: 2017-03-01 10:00:00
+completed: 2017-03-01 10:00:01
+output:
+  Stdout: |
+    box
+`[1:])
+	s.api.CheckCall(c, 0, "Tasks", params.Entities{
+		Entities: []params.Entity{{Tag: "action-" + taskID}},
+	})
+}
+
+func (s *OperationsSuite) TestShowTaskInvalidID(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, operations.NewShowTaskCommandForTest(s.api, s.store), "3")
+	c.Assert(err, gc.ErrorMatches, `task id "3" not valid`)
+}
+
+func (s *OperationsSuite) TestCancelOperation(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, operations.NewCancelOperationCommandForTest(s.api, s.store), "1", "2")
+	c.Assert(err, gc.ErrorMatches, "cannot cancel operations:\n  2: operation \"2\" not found")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- id: "1"
+  summary: run hostname
+  status: completed
+  enqueued: 2017-03-01 10:00:00
+  tasks:
+  - id: 01234567-89ab-cdef-0123-456789abcdef
+    action: juju-run
+    receiver: mysql/0
+    status: completed
+    enqueued: 2017-03-01 10:00:00
+    completed: 2017-03-01 10:00:01
+`[1:])
+	s.api.CheckCall(c, 0, "CancelOperations", []string{"1", "2"})
+}
+
+func (s *OperationsSuite) TestCancelOperationNoArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, operations.NewCancelOperationCommandForTest(s.api, s.store))
+	c.Assert(err, gc.ErrorMatches, "no operations specified")
+}
+
+type mockOperationsAPI struct {
+	testing.Stub
+	operations []params.OperationResult
+}
+
+func (m *mockOperationsAPI) Close() error {
+	return nil
+}
+
+func (m *mockOperationsAPI) ListOperations(args params.OperationQueryArgs) ([]params.OperationResult, error) {
+	m.MethodCall(m, "ListOperations", args)
+	return m.operations, m.NextErr()
+}
+
+func (m *mockOperationsAPI) CancelOperations(ids []string) ([]params.OperationResult, error) {
+	m.MethodCall(m, "CancelOperations", ids)
+	results := make([]params.OperationResult, len(ids))
+	for i, id := range ids {
+		results[i] = params.OperationResult{
+			OperationID: id,
+			Error:       &params.Error{Message: `operation "` + id + `" not found`},
+		}
+		for _, op := range m.operations {
+			if op.OperationID == id {
+				results[i] = op
+			}
+		}
+	}
+	return results, m.NextErr()
+}
+
+func (m *mockOperationsAPI) Tasks(args params.Entities) (params.ActionResults, error) {
+	m.MethodCall(m, "Tasks", args)
+	return params.ActionResults{
+		Results: m.operations[0].Tasks,
+	}, m.NextErr()
+}
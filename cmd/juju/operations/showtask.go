@@ -0,0 +1,94 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operations
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+var showTaskHelpSummary = `
+Shows a task and its output.`[1:]
+
+var showTaskHelpDetails = `
+Shows the status and output of the task with the given id, as listed
+by juju operations --format yaml.
+
+Examples:
+
+    juju show-task 01234567-89ab-cdef-0123-456789abcdef
+
+See also:
+    operations
+    run
+`[1:]
+
+// NewShowTaskCommand returns a command to show a task.
+func NewShowTaskCommand() cmd.Command {
+	return modelcmd.Wrap(&showTaskCommand{})
+}
+
+type showTaskCommand struct {
+	operationsCommandBase
+	out cmd.Output
+	tag names.ActionTag
+}
+
+// Info implements cmd.Command.
+func (c *showTaskCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-task",
+		Args:    "<task id>",
+		Purpose: showTaskHelpSummary,
+		Doc:     showTaskHelpDetails,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *showTaskCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
+}
+
+// Init implements cmd.Command.
+func (c *showTaskCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no task id specified")
+	}
+	if !names.IsValidAction(args[0]) {
+		return errors.NotValidf("task id %q", args[0])
+	}
+	c.tag = names.NewActionTag(args[0])
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *showTaskCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	results, err := client.Tasks(params.Entities{
+		Entities: []params.Entity{{Tag: c.tag.String()}},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return errors.Trace(result.Error)
+	}
+	return c.out.Write(ctx, makeTask(result))
+}
@@ -23,6 +23,10 @@ type DeployClient interface {
 
 	// AddPendingResource uploads data and metadata for a pending resource for the given application.
 	AddPendingResource(applicationID string, resource charmresource.Resource, filename string, r io.ReadSeeker) (id string, err error)
+
+	// AddPinnedPendingResource uploads data and metadata for a pending resource for the given
+	// application, pinning the resource to the fingerprint of the data.
+	AddPinnedPendingResource(applicationID string, resource charmresource.Resource, filename string, r io.ReadSeeker) (id string, err error)
 }
 
 // DeployResourcesArgs holds the arguments to DeployResources().
@@ -49,6 +53,10 @@ type DeployResourcesArgs struct {
 	// that should be added/updated on the controller.
 	ResourcesMeta map[string]charmresource.Meta
 
+	// PinUploads indicates whether uploaded resources are pinned to
+	// the checksums of their files.
+	PinUploads bool
+
 	// Client is the resources API client to use during deploy.
 	Client DeployClient
 }
@@ -63,6 +71,7 @@ func DeployResources(args DeployResourcesArgs) (ids map[string]string, err error
 		csMac:         args.CharmStoreMacaroon,
 		client:        args.Client,
 		resources:     args.ResourcesMeta,
+		pinUploads:    args.PinUploads,
 		osOpen:        func(s string) (ReadSeekCloser, error) { return os.Open(s) },
 		osStat:        func(s string) error { _, err := os.Stat(s); return err },
 	}
@@ -80,6 +89,7 @@ type deployUploader struct {
 	csMac         *macaroon.Macaroon
 	resources     map[string]charmresource.Meta
 	client        DeployClient
+	pinUploads    bool
 	osOpen        func(path string) (ReadSeekCloser, error)
 	osStat        func(path string) error
 }
@@ -188,7 +198,24 @@ func (d deployUploader) uploadFile(resourcename, filename string) (id string, er
 		Origin: charmresource.OriginUpload,
 	}
 
-	id, err = d.client.AddPendingResource(d.applicationID, res, filename, f)
+	if !d.pinUploads {
+		id, err = d.client.AddPendingResource(d.applicationID, res, filename, f)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return id, err
+	}
+
+	// Pin the resource to the file's current content, so that units
+	// never run with anything else.
+	res.Fingerprint, err = charmresource.GenerateFingerprint(f)
+	if err != nil {
+		return "", errors.Annotatef(err, "can't read file for resource %q", resourcename)
+	}
+	if _, err := f.Seek(0, os.SEEK_SET); err != nil {
+		return "", errors.Trace(err)
+	}
+	id, err = d.client.AddPinnedPendingResource(d.applicationID, res, filename, f)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -213,8 +214,9 @@ func (s DeploySuite) TestUploadFilesAndRevisions(c *gc.C) {
 				Path: "store",
 			},
 		},
-		osOpen: deps.Open,
-		osStat: deps.Stat,
+		pinUploads: true,
+		osOpen:     deps.Open,
+		osStat:     deps.Stat,
 	}
 
 	files := map[string]string{
@@ -230,7 +232,7 @@ func (s DeploySuite) TestUploadFilesAndRevisions(c *gc.C) {
 		"store":  "id-store",
 	})
 
-	s.stub.CheckCallNames(c, "Stat", "AddPendingResources", "Open", "AddPendingResource")
+	s.stub.CheckCallNames(c, "Stat", "AddPendingResources", "Open", "AddPinnedPendingResource")
 	expectedStore := []charmresource.Resource{
 		{
 			Meta:     du.resources["store"],
@@ -241,11 +243,14 @@ func (s DeploySuite) TestUploadFilesAndRevisions(c *gc.C) {
 	s.stub.CheckCall(c, 1, "AddPendingResources", "mysql", chID, csMac, expectedStore)
 	s.stub.CheckCall(c, 2, "Open", "foobar.txt")
 
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader(""))
+	c.Assert(err, jc.ErrorIsNil)
 	expectedUpload := charmresource.Resource{
-		Meta:   du.resources["upload"],
-		Origin: charmresource.OriginUpload,
+		Meta:        du.resources["upload"],
+		Origin:      charmresource.OriginUpload,
+		Fingerprint: fp,
 	}
-	s.stub.CheckCall(c, 3, "AddPendingResource", "mysql", expectedUpload, "foobar.txt", deps.ReadSeekCloser)
+	s.stub.CheckCall(c, 3, "AddPinnedPendingResource", "mysql", expectedUpload, "foobar.txt", deps.ReadSeekCloser)
 }
 
 func (s DeploySuite) TestUploadUnexpectedResourceFile(c *gc.C) {
@@ -348,6 +353,14 @@ func (s uploadDeps) AddPendingResource(applicationID string, resource charmresou
 	return "id-" + resource.Name, nil
 }
 
+func (s uploadDeps) AddPinnedPendingResource(applicationID string, resource charmresource.Resource, filename string, r io.ReadSeeker) (id string, err error) {
+	s.stub.AddCall("AddPinnedPendingResource", applicationID, resource, filename, r)
+	if err := s.stub.NextErr(); err != nil {
+		return "", err
+	}
+	return "id-" + resource.Name, nil
+}
+
 func (s uploadDeps) Open(name string) (ReadSeekCloser, error) {
 	s.stub.AddCall("Open", name)
 	if err := s.stub.NextErr(); err != nil {
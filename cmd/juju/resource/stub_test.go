@@ -41,6 +41,15 @@ func (s *stubAPIClient) Upload(service, name, filename string, resource io.ReadS
 	return nil
 }
 
+func (s *stubAPIClient) UploadUnpinned(service, name, filename string, resource io.ReadSeeker) error {
+	s.stub.AddCall("UploadUnpinned", service, name, filename, resource)
+	if err := s.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
 func (s *stubAPIClient) Close() error {
 	s.stub.AddCall("Close")
 	if err := s.stub.NextErr(); err != nil {
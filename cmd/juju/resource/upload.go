@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 
 	"github.com/juju/juju/cmd/modelcmd"
 )
@@ -17,6 +18,10 @@ type UploadClient interface {
 	// Upload sends the resource to Juju.
 	Upload(service, name, filename string, resource io.ReadSeeker) error
 
+	// UploadUnpinned sends the resource to Juju, replacing it even
+	// if it is pinned.
+	UploadUnpinned(service, name, filename string, resource io.ReadSeeker) error
+
 	// Close closes the client.
 	Close() error
 }
@@ -43,6 +48,7 @@ type UploadCommand struct {
 	modelcmd.ModelCommandBase
 	service      string
 	resourceFile resourceFile
+	unpin        bool
 }
 
 // NewUploadCommand returns a new command that lists resources defined
@@ -60,11 +66,20 @@ func (c *UploadCommand) Info() *cmd.Info {
 		Doc: `
 This command uploads a file from your local disk to the juju controller to be
 used as a resource for an application.
+
+A resource that was pinned to the checksum of its file when the application
+was deployed is only replaced if --unpin is specified.
 `,
 		Aliases: []string{"attach"},
 	}
 }
 
+// SetFlags implements cmd.Command.SetFlags.
+func (c *UploadCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.unpin, "unpin", false, "Replace the resource even if it is pinned")
+}
+
 // Init implements cmd.Command.Init. It will return an error satisfying
 // errors.BadRequest if you give it an incorrect number of arguments.
 func (c *UploadCommand) Init(args []string) error {
@@ -129,6 +144,10 @@ func (c *UploadCommand) upload(rf resourceFile, client UploadClient) error {
 		return errors.Trace(err)
 	}
 	defer f.Close()
-	err = client.Upload(rf.service, rf.name, rf.filename, f)
+	upload := client.Upload
+	if c.unpin {
+		upload = client.UploadUnpinned
+	}
+	err = upload(rf.service, rf.name, rf.filename, f)
 	return errors.Trace(err)
 }
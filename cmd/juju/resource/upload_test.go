@@ -5,6 +5,7 @@ package resource_test
 
 import (
 	jujucmd "github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -96,6 +97,9 @@ func (s *UploadSuite) TestInfo(c *gc.C) {
 		Doc: `
 This command uploads a file from your local disk to the juju controller to be
 used as a resource for an application.
+
+A resource that was pinned to the checksum of its file when the application
+was deployed is only replaced if --unpin is specified.
 `,
 		Aliases: []string{"attach"},
 	})
@@ -126,6 +130,30 @@ func (s *UploadSuite) TestRun(c *gc.C) {
 	s.stub.CheckCall(c, 2, "Upload", "svc", "foo", "bar", file)
 }
 
+func (s *UploadSuite) TestRunUnpin(c *gc.C) {
+	file := &stubFile{stub: s.stub}
+	s.stubDeps.file = file
+	u := resourcecmd.NewUploadCommand(resourcecmd.UploadDeps{
+		NewClient:    s.stubDeps.NewClient,
+		OpenResource: s.stubDeps.OpenResource,
+	},
+	)
+	err := cmdtesting.InitCommand(u, []string{"--unpin", "svc", "foo=bar"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = u.Run(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c,
+		"NewClient",
+		"OpenResource",
+		"UploadUnpinned",
+		"FileClose",
+		"Close",
+	)
+	s.stub.CheckCall(c, 2, "UploadUnpinned", "svc", "foo", "bar", file)
+}
+
 type stubUploadDeps struct {
 	stub   *testing.Stub
 	file   resourcecmd.ReadSeekCloser
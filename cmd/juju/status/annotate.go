@@ -0,0 +1,101 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const addStatusAnnotationDoc = `
+Add a note to the status history of a unit or machine.
+
+Annotations record operator activity, such as maintenance performed or
+the ticket that tracks it, alongside the statuses reported by Juju.
+They are attributed to the current user and shown by show-status-log.
+
+Examples:
+
+    juju add-status-annotation mysql/0 "replaced failed disk, ticket OPS-42"
+    juju add-status-annotation 3 "rebooted for kernel update"
+
+See also:
+    show-status-log
+`
+
+// NewAddStatusAnnotationCommand returns a command that adds a note to
+// the status history of a unit or machine.
+func NewAddStatusAnnotationCommand() cmd.Command {
+	return modelcmd.Wrap(&addStatusAnnotationCommand{})
+}
+
+// addStatusAnnotationAPI defines the methods on the client API that the
+// add-status-annotation command calls.
+type addStatusAnnotationAPI interface {
+	Close() error
+	AddStatusAnnotation(tag names.Tag, message string) error
+}
+
+type addStatusAnnotationCommand struct {
+	modelcmd.ModelCommandBase
+	tag     names.Tag
+	message string
+
+	api addStatusAnnotationAPI
+}
+
+func (c *addStatusAnnotationCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add-status-annotation",
+		Args:    "<unit | machine> <message>",
+		Purpose: "Add a note to the status history of a unit or machine.",
+		Doc:     addStatusAnnotationDoc,
+	}
+}
+
+func (c *addStatusAnnotationCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.Errorf("no unit or machine specified")
+	case 1:
+		return errors.Errorf("no message specified")
+	case 2:
+	default:
+		return cmd.CheckEmpty(args[2:])
+	}
+	switch entity := args[0]; {
+	case names.IsValidUnit(entity):
+		c.tag = names.NewUnitTag(entity)
+	case names.IsValidMachine(entity):
+		c.tag = names.NewMachineTag(entity)
+	default:
+		return errors.Errorf("%q is not a valid unit or machine name", entity)
+	}
+	c.message = strings.TrimSpace(args[1])
+	if c.message == "" {
+		return errors.Errorf("no message specified")
+	}
+	return nil
+}
+
+func (c *addStatusAnnotationCommand) getAPI() (addStatusAnnotationAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+func (c *addStatusAnnotationCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	return errors.Trace(client.AddStatusAnnotation(c.tag, c.message))
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/testing"
+)
+
+type AddStatusAnnotationSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake *fakeAddStatusAnnotationAPI
+}
+
+var _ = gc.Suite(&AddStatusAnnotationSuite{})
+
+type fakeAddStatusAnnotationAPI struct {
+	jujutesting.Stub
+}
+
+func (f *fakeAddStatusAnnotationAPI) Close() error {
+	return nil
+}
+
+func (f *fakeAddStatusAnnotationAPI) AddStatusAnnotation(tag names.Tag, message string) error {
+	f.MethodCall(f, "AddStatusAnnotation", tag, message)
+	return f.NextErr()
+}
+
+func (s *AddStatusAnnotationSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = &fakeAddStatusAnnotationAPI{}
+}
+
+func (s *AddStatusAnnotationSuite) runCommand(c *gc.C, args ...string) error {
+	command := modelcmd.Wrap(&addStatusAnnotationCommand{api: s.fake})
+	_, err := cmdtesting.RunCommand(c, command, args...)
+	return err
+}
+
+func (s *AddStatusAnnotationSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no unit or machine specified",
+	}, {
+		args: []string{"mysql/0"},
+		err:  "no message specified",
+	}, {
+		args: []string{"mysql/0", " "},
+		err:  "no message specified",
+	}, {
+		args: []string{"mysql", "hello"},
+		err:  `"mysql" is not a valid unit or machine name`,
+	}, {
+		args: []string{"mysql/0", "hello", "world"},
+		err:  `unrecognized args: \["world"\]`,
+	}} {
+		c.Logf("test %d", i)
+		err := s.runCommand(c, t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+	s.fake.CheckNoCalls(c)
+}
+
+func (s *AddStatusAnnotationSuite) TestAnnotateUnit(c *gc.C) {
+	err := s.runCommand(c, "mysql/0", "replaced disk, ticket OPS-42")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []jujutesting.StubCall{{
+		"AddStatusAnnotation", []interface{}{names.NewUnitTag("mysql/0"), "replaced disk, ticket OPS-42"},
+	}})
+}
+
+func (s *AddStatusAnnotationSuite) TestAnnotateMachine(c *gc.C) {
+	err := s.runCommand(c, "0/lxd/1", "rebooted")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []jujutesting.StubCall{{
+		"AddStatusAnnotation", []interface{}{names.NewMachineTag("0/lxd/1"), "rebooted"},
+	}})
+}
+
+func (s *AddStatusAnnotationSuite) TestAnnotateError(c *gc.C) {
+	s.fake.SetErrors(errors.New("boom"))
+	err := s.runCommand(c, "mysql/0", "hello")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -56,6 +56,9 @@ The statuses are available for the following types.
     container: will show statuses for containers.
  and sorted by time of occurrence.
  The default is unit.
+
+Notes added to the history of a unit or machine with add-status-annotation
+are shown with the type "annotation", followed by the user who added them.
 `
 
 func (c *statusHistoryCommand) Info() *cmd.Info {
@@ -182,6 +185,11 @@ func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
 	statuses = statuses.SquashLogs(3)
 	for _, v := range statuses {
 		fields := []string{common.FormatTime(v.Since, c.isoTime), string(v.Kind), string(v.Status), v.Info}
+		if v.Kind == status.KindAnnotation {
+			if author, ok := v.Data[status.AnnotationAuthorKey].(string); ok {
+				fields[3] = fmt.Sprintf("%s (%s)", v.Info, author)
+			}
+		}
 		for k, v := range fields {
 			if len(v) > lengths[k] {
 				lengths[k] = len(v)
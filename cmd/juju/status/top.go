@@ -0,0 +1,604 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/status"
+)
+
+const topDoc = `
+Show a live overview of the activity in a model.
+
+The display is refreshed every interval, and shows the hooks that unit
+agents are running, a summary of the states of machine and unit agents,
+the rate at which the model is changing, and the most recent errors
+reported by machines and units.
+
+The rate of calls to the controller's API server, their mean latency
+and the busiest API methods are read from the controller's metrics,
+which only controller superusers and users with read access to the
+controller model may see. The rates are shown from the second refresh.
+
+Press Ctrl-C to exit.
+
+Examples:
+
+    juju top
+    juju top --interval 5s
+    juju top --once
+
+See also:
+    show-status
+    show-status-log
+    debug-log
+`
+
+const (
+	// topActivityWindow is the period over which the rate of change
+	// of the model is measured.
+	topActivityWindow = time.Minute
+
+	// topMaxErrors is the number of recent errors displayed.
+	topMaxErrors = 10
+
+	// topMaxAPIMethods is the number of busiest API methods
+	// displayed.
+	topMaxAPIMethods = 5
+)
+
+// NewTopCommand returns a command that shows a live overview of the
+// activity in a model.
+func NewTopCommand() cmd.Command {
+	return modelcmd.Wrap(&topCommand{})
+}
+
+// topAPI defines the API methods used by the top command.
+type topAPI interface {
+	Close() error
+	WatchAll() (allWatcher, error)
+	APIServerMetrics() (io.ReadCloser, error)
+}
+
+// allWatcher reports the changes to the entities in a model.
+type allWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
+type topCommand struct {
+	modelcmd.ModelCommandBase
+	interval time.Duration
+	once     bool
+
+	api   topAPI
+	clock clock.Clock
+}
+
+func (c *topCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "top",
+		Purpose: "Show a live overview of the activity in a model.",
+		Doc:     topDoc,
+	}
+}
+
+func (c *topCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.DurationVar(&c.interval, "interval", 2*time.Second, "Time between refreshes of the display")
+	f.BoolVar(&c.once, "once", false, "Show the current state of the model once, and exit")
+}
+
+func (c *topCommand) Init(args []string) error {
+	if c.interval <= 0 {
+		return errors.Errorf("interval must be positive")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// clientTopAPI adapts an api.Client to the topAPI interface.
+type clientTopAPI struct {
+	*api.Client
+}
+
+func (c clientTopAPI) WatchAll() (allWatcher, error) {
+	return c.Client.WatchAll()
+}
+
+func (c *topCommand) getAPI() (topAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return clientTopAPI{client}, nil
+}
+
+func (c *topCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	clk := c.clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+
+	w, err := client.WatchAll()
+	if err != nil {
+		return errors.Annotate(err, "cannot watch model")
+	}
+	defer w.Stop()
+
+	// The first batch of deltas describes the whole model.
+	deltas, err := w.Next()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	model := newTopModel()
+	model.update(deltas, clk.Now())
+	// Loading the model is not activity within it.
+	model.changes = nil
+	if c.once {
+		model.sampleAPI(client, clk.Now())
+		model.render(ctx.Stdout, clk.Now())
+		return nil
+	}
+
+	type nextResult struct {
+		deltas []multiwatcher.Delta
+		err    error
+	}
+	next := make(chan nextResult)
+	go func() {
+		for {
+			deltas, err := w.Next()
+			next <- nextResult{deltas, err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+
+	for {
+		model.sampleAPI(client, clk.Now())
+		fmt.Fprint(ctx.Stdout, clearScreen)
+		model.render(ctx.Stdout, clk.Now())
+		refresh := clk.After(c.interval)
+	wait:
+		for {
+			select {
+			case <-interrupted:
+				return nil
+			case result := <-next:
+				if result.err != nil {
+					return errors.Trace(result.err)
+				}
+				model.update(result.deltas, clk.Now())
+			case <-refresh:
+				break wait
+			}
+		}
+	}
+}
+
+// clearScreen moves the cursor to the top left of a terminal, and
+// clears it.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// topError records an error reported by an entity.
+type topError struct {
+	time    time.Time
+	entity  string
+	message string
+}
+
+// topModel holds what the top command knows of a model.
+type topModel struct {
+	machines map[string]*multiwatcher.MachineInfo
+	units    map[string]*multiwatcher.UnitInfo
+
+	// changes holds the times at which changes to the model were
+	// reported within the activity window.
+	changes []time.Time
+
+	// errors holds the most recent errors, oldest first, and
+	// lastError the last error reported by each entity.
+	errors    []topError
+	lastError map[string]string
+
+	// lastAPI and prevAPI hold the last two samples of the API
+	// server's metrics, and apiErr the error that prevented the last
+	// sample being taken.
+	lastAPI *apiSample
+	prevAPI *apiSample
+	apiErr  error
+}
+
+func newTopModel() *topModel {
+	return &topModel{
+		machines:  make(map[string]*multiwatcher.MachineInfo),
+		units:     make(map[string]*multiwatcher.UnitInfo),
+		lastError: make(map[string]string),
+	}
+}
+
+// update applies the deltas reported at the given time to the model.
+func (m *topModel) update(deltas []multiwatcher.Delta, now time.Time) {
+	for _, delta := range deltas {
+		switch info := delta.Entity.(type) {
+		case *multiwatcher.MachineInfo:
+			name := "machine " + info.Id
+			if delta.Removed {
+				delete(m.machines, info.Id)
+				delete(m.lastError, name)
+				break
+			}
+			m.machines[info.Id] = info
+			m.noteErrors(name, now, info.AgentStatus, info.InstanceStatus)
+		case *multiwatcher.UnitInfo:
+			name := "unit " + info.Name
+			if delta.Removed {
+				delete(m.units, info.Name)
+				delete(m.lastError, name)
+				break
+			}
+			m.units[info.Name] = info
+			m.noteErrors(name, now, info.AgentStatus, info.WorkloadStatus)
+		default:
+			// Other entities count towards the activity
+			// of the model, but are not displayed.
+		}
+		m.changes = append(m.changes, now)
+	}
+}
+
+// noteErrors records any error in the given statuses of an entity that
+// differs from the last one it reported.
+func (m *topModel) noteErrors(entity string, now time.Time, statuses ...multiwatcher.StatusInfo) {
+	for _, s := range statuses {
+		if s.Current != status.Error && s.Current != status.ProvisioningError {
+			continue
+		}
+		if m.lastError[entity] == s.Message {
+			return
+		}
+		m.lastError[entity] = s.Message
+		m.errors = append(m.errors, topError{now, entity, s.Message})
+		if len(m.errors) > topMaxErrors {
+			m.errors = m.errors[len(m.errors)-topMaxErrors:]
+		}
+		return
+	}
+	delete(m.lastError, entity)
+}
+
+// activity returns the number of changes to the model reported within
+// the activity window before now.
+func (m *topModel) activity(now time.Time) int {
+	cutoff := now.Add(-topActivityWindow)
+	i := 0
+	for i < len(m.changes) && m.changes[i].Before(cutoff) {
+		i++
+	}
+	m.changes = m.changes[i:]
+	return len(m.changes)
+}
+
+// sampleAPI samples the metrics of the API server through the given
+// API.
+func (m *topModel) sampleAPI(client topAPI, now time.Time) {
+	sample, err := readAPISample(client, now)
+	if err != nil {
+		m.apiErr = err
+		return
+	}
+	m.apiErr = nil
+	m.prevAPI, m.lastAPI = m.lastAPI, sample
+}
+
+func readAPISample(client topAPI, now time.Time) (*apiSample, error) {
+	r, err := client.APIServerMetrics()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer r.Close()
+	return parseAPISample(r, now)
+}
+
+// render writes the model's current state to w.
+func (m *topModel) render(w io.Writer, now time.Time) {
+	tw := output.TabWriter(w)
+	p := output.Wrapper{tw}
+
+	changes := m.activity(now)
+	fmt.Fprintf(tw, "Activity: %d changes in the last %s (%.1f/s)\n",
+		changes, topActivityWindow, float64(changes)/topActivityWindow.Seconds())
+	fmt.Fprintf(tw, "Machines: %s\n", summariseStatuses(m.machineAgentStatuses()))
+	fmt.Fprintf(tw, "Units:    %s\n", summariseStatuses(m.unitAgentStatuses()))
+	fmt.Fprintf(tw, "API:      %s\n", m.summariseAPI())
+	p.Println()
+
+	var running []*multiwatcher.UnitInfo
+	for _, u := range m.units {
+		if u.AgentStatus.Current == status.Executing {
+			running = append(running, u)
+		}
+	}
+	sort.Slice(running, func(i, j int) bool { return running[i].Name < running[j].Name })
+	p.Println("Unit", "Activity", "Since")
+	for _, u := range running {
+		p.Println(u.Name, u.AgentStatus.Message, formatSince(u.AgentStatus.Since, now))
+	}
+	if len(running) == 0 {
+		p.Println("-", "idle", "")
+	}
+	p.Println()
+
+	if busiest := m.busiestAPIMethods(); len(busiest) > 0 {
+		p.Println("API method", "Calls/s")
+		for _, method := range busiest {
+			p.Println(method.name, fmt.Sprintf("%.1f", method.rate))
+		}
+		p.Println()
+	}
+
+	p.Println("Time", "Entity", "Error")
+	for i := len(m.errors) - 1; i >= 0; i-- {
+		e := m.errors[i]
+		p.Println(e.time.Format("15:04:05"), e.entity, e.message)
+	}
+	if len(m.errors) == 0 {
+		p.Println("-", "-", "none")
+	}
+	tw.Flush()
+}
+
+// summariseAPI returns a summary of the calls to the API server since
+// the previous sample, or in total if there is no previous sample.
+func (m *topModel) summariseAPI() string {
+	if m.apiErr != nil {
+		return fmt.Sprintf("unavailable (%v)", m.apiErr)
+	}
+	last, prev := m.lastAPI, m.prevAPI
+	if last == nil {
+		return "unavailable"
+	}
+	if prev == nil {
+		return fmt.Sprintf("%.0f calls served, %.0f failed", last.count, last.errors)
+	}
+	seconds := last.time.Sub(prev.time).Seconds()
+	calls := last.count - prev.count
+	if seconds <= 0 || calls < 0 {
+		// The API server restarted, or the connection moved to
+		// another controller, between the samples.
+		return "measuring"
+	}
+	summary := fmt.Sprintf("%.1f calls/s, %.1f failed/s",
+		calls/seconds, (last.errors-prev.errors)/seconds)
+	if calls > 0 {
+		latency := (last.seconds - prev.seconds) / calls
+		summary += fmt.Sprintf(", mean latency %.0fms", latency*1000)
+	}
+	return summary
+}
+
+// apiMethodRate holds the rate of calls to an API method.
+type apiMethodRate struct {
+	name string
+	rate float64
+}
+
+// busiestAPIMethods returns the API methods called most often between
+// the last two samples, busiest first.
+func (m *topModel) busiestAPIMethods() []apiMethodRate {
+	last, prev := m.lastAPI, m.prevAPI
+	if m.apiErr != nil || last == nil || prev == nil {
+		return nil
+	}
+	seconds := last.time.Sub(prev.time).Seconds()
+	if seconds <= 0 {
+		return nil
+	}
+	var rates []apiMethodRate
+	for name, calls := range last.calls {
+		if delta := calls - prev.calls[name]; delta > 0 {
+			rates = append(rates, apiMethodRate{name, delta / seconds})
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].rate != rates[j].rate {
+			return rates[i].rate > rates[j].rate
+		}
+		return rates[i].name < rates[j].name
+	})
+	if len(rates) > topMaxAPIMethods {
+		rates = rates[:topMaxAPIMethods]
+	}
+	return rates
+}
+
+func (m *topModel) machineAgentStatuses() []status.Status {
+	var result []status.Status
+	for _, info := range m.machines {
+		result = append(result, info.AgentStatus.Current)
+	}
+	return result
+}
+
+func (m *topModel) unitAgentStatuses() []status.Status {
+	var result []status.Status
+	for _, info := range m.units {
+		result = append(result, info.AgentStatus.Current)
+	}
+	return result
+}
+
+// summariseStatuses returns the number of agents with each status,
+// e.g. "3 (idle 2, executing 1)".
+func summariseStatuses(statuses []status.Status) string {
+	counts := make(map[status.Status]int)
+	var seen []string
+	for _, s := range statuses {
+		if counts[s] == 0 {
+			seen = append(seen, string(s))
+		}
+		counts[s]++
+	}
+	if len(seen) == 0 {
+		return "0"
+	}
+	sort.Strings(seen)
+	summary := fmt.Sprintf("%d (", len(statuses))
+	for i, s := range seen {
+		if i > 0 {
+			summary += ", "
+		}
+		summary += fmt.Sprintf("%s %d", s, counts[status.Status(s)])
+	}
+	return summary + ")"
+}
+
+// formatSince returns how long before now the given time was, to the
+// nearest second.
+func formatSince(since *time.Time, now time.Time) string {
+	if since == nil {
+		return ""
+	}
+	return (now.Sub(*since) / time.Second * time.Second).String()
+}
+
+// apiSample holds the API server's request metrics at a point in time.
+// The metrics are counted from when the API server started.
+type apiSample struct {
+	time time.Time
+
+	// calls holds the number of calls to each API method, keyed on
+	// "Facade.Method", and count the total.
+	calls map[string]float64
+	count float64
+
+	// errors holds the number of calls that failed with an error
+	// code, and seconds the total time spent serving calls.
+	errors  float64
+	seconds float64
+}
+
+// parseAPISample parses the API request metrics from the Prometheus
+// text exposition format.
+func parseAPISample(r io.Reader, now time.Time) (*apiSample, error) {
+	sample := &apiSample{
+		time:  now,
+		calls: make(map[string]float64),
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name, labels, value, ok := parseMetric(scanner.Text())
+		if !ok {
+			continue
+		}
+		switch name {
+		case "juju_api_requests_total":
+			sample.calls[labels["facade"]+"."+labels["method"]] += value
+			sample.count += value
+			if labels["error_code"] != "" {
+				sample.errors += value
+			}
+		case "juju_api_request_duration_seconds_sum":
+			sample.seconds += value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Annotate(err, "cannot read API server metrics")
+	}
+	return sample, nil
+}
+
+// parseMetric parses a sample line of the Prometheus text exposition
+// format, such as `juju_api_requests_total{facade="Client"} 42`. It
+// returns false if the line is not a sample.
+func parseMetric(line string) (name string, labels map[string]string, value float64, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil, 0, false
+	}
+	end := strings.IndexAny(line, "{ ")
+	if end <= 0 {
+		return "", nil, 0, false
+	}
+	name, rest := line[:end], line[end:]
+	labels = make(map[string]string)
+	if strings.HasPrefix(rest, "{") {
+		rest = rest[1:]
+		for !strings.HasPrefix(rest, "}") {
+			eq := strings.Index(rest, "=\"")
+			if eq <= 0 {
+				return "", nil, 0, false
+			}
+			label := strings.TrimSpace(rest[:eq])
+			labelValue, remainder, ok := parseLabelValue(rest[eq+2:])
+			if !ok {
+				return "", nil, 0, false
+			}
+			labels[label] = labelValue
+			rest = strings.TrimPrefix(remainder, ",")
+		}
+		rest = rest[1:]
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, false
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, 0, false
+	}
+	return name, labels, value, true
+}
+
+// parseLabelValue parses a label value, whose opening quote has been
+// consumed, returning the value and the remainder of the line after
+// its closing quote.
+func parseLabelValue(s string) (value, rest string, ok bool) {
+	var buf []byte
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return string(buf), s[i+1:], true
+		case '\\':
+			if i++; i == len(s) {
+				return "", "", false
+			}
+			if s[i] == 'n' {
+				buf = append(buf, '\n')
+				continue
+			}
+		}
+		buf = append(buf, s[i])
+	}
+	return "", "", false
+}
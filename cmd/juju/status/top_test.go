@@ -0,0 +1,262 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/testing"
+)
+
+type TopSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	clock   *jujutesting.Clock
+	watcher *fakeAllWatcher
+	api     *fakeTopAPI
+}
+
+var _ = gc.Suite(&TopSuite{})
+
+func (s *TopSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.clock = jujutesting.NewClock(time.Date(2017, 1, 2, 10, 0, 0, 0, time.UTC))
+	since := s.clock.Now().Add(-5 * time.Second)
+	s.watcher = &fakeAllWatcher{
+		deltas: [][]multiwatcher.Delta{{{
+			Entity: &multiwatcher.MachineInfo{
+				Id:          "0",
+				AgentStatus: multiwatcher.StatusInfo{Current: status.Started},
+			},
+		}, {
+			Entity: &multiwatcher.ApplicationInfo{Name: "mysql"},
+		}, {
+			Entity: &multiwatcher.UnitInfo{
+				Name: "mysql/0",
+				AgentStatus: multiwatcher.StatusInfo{
+					Current: status.Executing,
+					Message: "running config-changed hook",
+					Since:   &since,
+				},
+			},
+		}, {
+			Entity: &multiwatcher.UnitInfo{
+				Name:        "wordpress/0",
+				AgentStatus: multiwatcher.StatusInfo{Current: status.Idle},
+				WorkloadStatus: multiwatcher.StatusInfo{
+					Current: status.Error,
+					Message: `hook failed: "install"`,
+				},
+			},
+		}}},
+	}
+	s.api = &fakeTopAPI{
+		watcher: s.watcher,
+		metrics: []string{apiMetrics},
+	}
+}
+
+const apiMetrics = `
+# HELP juju_api_request_duration_seconds Latency of Juju API requests in seconds.
+# TYPE juju_api_request_duration_seconds summary
+juju_api_request_duration_seconds{error_code="",facade="Client",method="FullStatus",version="1",quantile="0.5"} 0.05
+juju_api_request_duration_seconds_sum{error_code="",facade="Client",method="FullStatus",version="1"} 2
+juju_api_request_duration_seconds_count{error_code="",facade="Client",method="FullStatus",version="1"} 40
+# HELP juju_api_requests_total Number of Juju API requests served.
+# TYPE juju_api_requests_total counter
+juju_api_requests_total{error_code="",facade="Client",method="FullStatus",version="1"} 40
+juju_api_requests_total{error_code="not found",facade="Application",method="Get",version="4"} 2
+`
+
+const apiMetricsLater = `
+juju_api_request_duration_seconds_sum{error_code="",facade="Client",method="FullStatus",version="1"} 2.3
+juju_api_requests_total{error_code="",facade="Client",method="FullStatus",version="1"} 50
+juju_api_requests_total{error_code="not found",facade="Application",method="Get",version="4"} 3
+juju_api_requests_total{error_code="",facade="Uniter",method="Watch",version="7"} 4
+`
+
+func (s *TopSuite) runTop(c *gc.C, args ...string) (string, error) {
+	command := modelcmd.Wrap(&topCommand{
+		api:   s.api,
+		clock: s.clock,
+	})
+	ctx, err := cmdtesting.RunCommand(c, command, args...)
+	return cmdtesting.Stdout(ctx), err
+}
+
+func (s *TopSuite) TestInitErrors(c *gc.C) {
+	_, err := s.runTop(c, "--interval", "0s")
+	c.Assert(err, gc.ErrorMatches, "interval must be positive")
+	_, err = s.runTop(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["mysql"\]`)
+}
+
+func (s *TopSuite) TestOnce(c *gc.C) {
+	out, err := s.runTop(c, "--once")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+Activity: 0 changes in the last 1m0s (0.0/s)
+Machines: 1 (started 1)
+Units:    2 (executing 1, idle 1)
+API:      42 calls served, 2 failed
+
+Unit     Activity                     Since
+mysql/0  running config-changed hook  5s
+
+Time      Entity            Error
+10:00:00  unit wordpress/0  hook failed: "install"
+`[1:])
+	c.Assert(s.watcher.stopped, jc.IsTrue)
+}
+
+func (s *TopSuite) TestWatcherError(c *gc.C) {
+	s.watcher.deltas = nil
+	_, err := s.runTop(c)
+	c.Assert(err, gc.ErrorMatches, "watcher stopped")
+}
+
+func (s *TopSuite) TestModelActivityAndErrors(c *gc.C) {
+	model := newTopModel()
+	now := s.clock.Now()
+	failing := func(message string) []multiwatcher.Delta {
+		return []multiwatcher.Delta{{
+			Entity: &multiwatcher.MachineInfo{
+				Id:             "1",
+				InstanceStatus: multiwatcher.StatusInfo{Current: status.ProvisioningError, Message: message},
+			},
+		}}
+	}
+	model.update(failing("no capacity"), now)
+	model.update(failing("no capacity"), now.Add(30*time.Second))
+	model.update(failing("quota exceeded"), now.Add(45*time.Second))
+	c.Assert(model.activity(now.Add(80*time.Second)), gc.Equals, 2)
+	c.Assert(model.errors, jc.DeepEquals, []topError{
+		{now, "machine 1", "no capacity"},
+		{now.Add(45 * time.Second), "machine 1", "quota exceeded"},
+	})
+
+	model.update([]multiwatcher.Delta{{
+		Removed: true,
+		Entity:  &multiwatcher.MachineInfo{Id: "1"},
+	}}, now.Add(90*time.Second))
+	var buf bytes.Buffer
+	model.render(&buf, now.Add(90*time.Second))
+	c.Assert(buf.String(), jc.Contains, "Machines: 0\n")
+}
+
+func (s *TopSuite) TestAPIRates(c *gc.C) {
+	s.api.metrics = append(s.api.metrics, apiMetricsLater)
+	model := newTopModel()
+	now := s.clock.Now()
+	model.sampleAPI(s.api, now)
+	model.sampleAPI(s.api, now.Add(10*time.Second))
+
+	var buf bytes.Buffer
+	model.render(&buf, now.Add(10*time.Second))
+	c.Assert(buf.String(), jc.Contains, "API:      1.5 calls/s, 0.1 failed/s, mean latency 20ms\n")
+	c.Assert(buf.String(), jc.Contains, `
+API method         Calls/s
+Client.FullStatus  1.0
+Uniter.Watch       0.4
+Application.Get    0.1
+`)
+}
+
+func (s *TopSuite) TestAPIMetricsError(c *gc.C) {
+	s.api.metrics = nil
+	model := newTopModel()
+	model.sampleAPI(s.api, s.clock.Now())
+
+	var buf bytes.Buffer
+	model.render(&buf, s.clock.Now())
+	c.Assert(buf.String(), jc.Contains, "API:      unavailable (access denied)\n")
+	c.Assert(buf.String(), gc.Not(jc.Contains), "API method")
+}
+
+func (s *TopSuite) TestParseMetric(c *gc.C) {
+	name, labels, value, ok := parseMetric(`juju_api_requests_total{facade="Cl\"i,ent}",method="Full\\Status"} 42 1500000000`)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(name, gc.Equals, "juju_api_requests_total")
+	c.Assert(labels, jc.DeepEquals, map[string]string{
+		"facade": `Cl"i,ent}`,
+		"method": `Full\Status`,
+	})
+	c.Assert(value, gc.Equals, 42.0)
+
+	name, labels, value, ok = parseMetric("go_goroutines 12")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(name, gc.Equals, "go_goroutines")
+	c.Assert(labels, gc.HasLen, 0)
+	c.Assert(value, gc.Equals, 12.0)
+
+	for _, line := range []string{
+		"",
+		"# TYPE go_goroutines gauge",
+		`juju_api_requests_total{facade="Client} 42`,
+		"go_goroutines many",
+	} {
+		_, _, _, ok := parseMetric(line)
+		c.Check(ok, jc.IsFalse, gc.Commentf("%q", line))
+	}
+}
+
+func (s *TopSuite) TestSummariseStatuses(c *gc.C) {
+	c.Assert(summariseStatuses(nil), gc.Equals, "0")
+	c.Assert(summariseStatuses([]status.Status{
+		status.Idle, status.Error, status.Idle,
+	}), gc.Equals, "3 (error 1, idle 2)")
+}
+
+type fakeTopAPI struct {
+	watcher *fakeAllWatcher
+	metrics []string
+}
+
+func (f *fakeTopAPI) Close() error {
+	return nil
+}
+
+func (f *fakeTopAPI) WatchAll() (allWatcher, error) {
+	return f.watcher, nil
+}
+
+func (f *fakeTopAPI) APIServerMetrics() (io.ReadCloser, error) {
+	if len(f.metrics) == 0 {
+		return nil, errors.New("access denied")
+	}
+	metrics := f.metrics[0]
+	f.metrics = f.metrics[1:]
+	return ioutil.NopCloser(strings.NewReader(metrics)), nil
+}
+
+type fakeAllWatcher struct {
+	deltas  [][]multiwatcher.Delta
+	stopped bool
+}
+
+func (w *fakeAllWatcher) Next() ([]multiwatcher.Delta, error) {
+	if len(w.deltas) == 0 {
+		return nil, errors.New("watcher stopped")
+	}
+	deltas := w.deltas[0]
+	w.deltas = w.deltas[1:]
+	return deltas, nil
+}
+
+func (w *fakeAllWatcher) Stop() error {
+	w.stopped = true
+	return nil
+}
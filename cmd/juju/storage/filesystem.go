@@ -53,9 +53,11 @@ type FilesystemAttachments struct {
 }
 
 type MachineFilesystemAttachment struct {
-	MountPoint string `yaml:"mount-point" json:"mount-point"`
-	ReadOnly   bool   `yaml:"read-only" json:"read-only"`
-	Life       string `yaml:"life,omitempty" json:"life,omitempty"`
+	MountPoint string           `yaml:"mount-point" json:"mount-point"`
+	ReadOnly   bool             `yaml:"read-only" json:"read-only"`
+	Life       string           `yaml:"life,omitempty" json:"life,omitempty"`
+	Usage      *FilesystemUsage `yaml:"usage,omitempty" json:"usage,omitempty"`
+	UsageError string           `yaml:"usage-error,omitempty" json:"usage-error,omitempty"`
 }
 
 // generateListFilesystemOutput returns a map filesystem IDs to filesystem info
@@ -129,9 +131,11 @@ func createFilesystemInfo(details params.FilesystemDetails) (names.FilesystemTag
 				return names.FilesystemTag{}, FilesystemInfo{}, errors.Trace(err)
 			}
 			machineAttachments[machineId] = MachineFilesystemAttachment{
-				attachment.MountPoint,
-				attachment.ReadOnly,
-				string(attachment.Life),
+				MountPoint: attachment.MountPoint,
+				ReadOnly:   attachment.ReadOnly,
+				Life:       string(attachment.Life),
+				Usage:      createFilesystemUsage(attachment.Usage),
+				UsageError: usageErrorString(attachment.UsageError),
 			}
 		}
 		info.Attachments = &FilesystemAttachments{
@@ -114,6 +114,10 @@ storage:
       units:
         transcode/0:
           location: there
+          usage:
+            used-bytes: 1024
+            available-bytes: 2048
+            updated: .*
         transcode/1:
           location: here
 filesystems:
@@ -380,6 +384,11 @@ func (s *mockListAPI) ListStorageDetails() ([]params.StorageDetails, error) {
 		Attachments: map[string]params.StorageAttachmentDetails{
 			"unit-transcode-0": params.StorageAttachmentDetails{
 				Location: "there",
+				Usage: &params.FilesystemUsage{
+					UsedBytes:      1024,
+					AvailableBytes: 2048,
+					Updated:        &epoch,
+				},
 			},
 			"unit-transcode-1": params.StorageAttachmentDetails{
 				Location: "here",
@@ -62,9 +62,46 @@ type UnitStorageAttachment struct {
 	// Life is the lifecycle state of the storage attachment.
 	Life string `yaml:"life,omitempty" json:"life,omitempty"`
 
+	// Usage is the most recently reported usage of a filesystem
+	// storage attachment, if any.
+	Usage *FilesystemUsage `yaml:"usage,omitempty" json:"usage,omitempty"`
+
+	// UsageError is the error, if any, encountered while fetching
+	// Usage.
+	UsageError string `yaml:"usage-error,omitempty" json:"usage-error,omitempty"`
+
 	// TODO(axw) per-unit status when we have it in state.
 }
 
+// FilesystemUsage holds the space used on a filesystem, as last
+// reported by the machine it is attached to.
+type FilesystemUsage struct {
+	UsedBytes      uint64 `yaml:"used-bytes" json:"used-bytes"`
+	AvailableBytes uint64 `yaml:"available-bytes" json:"available-bytes"`
+	Updated        string `yaml:"updated,omitempty" json:"updated,omitempty"`
+}
+
+func createFilesystemUsage(usage *params.FilesystemUsage) *FilesystemUsage {
+	if usage == nil {
+		return nil
+	}
+	result := &FilesystemUsage{
+		UsedBytes:      usage.UsedBytes,
+		AvailableBytes: usage.AvailableBytes,
+	}
+	if usage.Updated != nil {
+		result.Updated = common.FormatTime(usage.Updated, false)
+	}
+	return result
+}
+
+func usageErrorString(err *params.Error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
 // formatStorageDetails takes a set of StorageDetail and
 // creates a mapping from storage ID to storage details.
 func formatStorageDetails(storages []params.StorageDetails) (map[string]StorageInfo, error) {
@@ -116,9 +153,11 @@ func createStorageInfo(details params.StorageDetails) (names.StorageTag, Storage
 				machineId = machineTag.Id()
 			}
 			unitStorageAttachments[unitTag.Id()] = UnitStorageAttachment{
-				machineId,
-				attachmentDetails.Location,
-				string(attachmentDetails.Life),
+				MachineId:  machineId,
+				Location:   attachmentDetails.Location,
+				Life:       string(attachmentDetails.Life),
+				Usage:      createFilesystemUsage(attachmentDetails.Usage),
+				UsageError: usageErrorString(attachmentDetails.UsageError),
 			}
 		}
 		info.Attachments = &StorageAttachments{unitStorageAttachments}
@@ -7,6 +7,7 @@ agent contains jujud's machine agent.
 package agent
 
 import (
+	"path/filepath"
 	"sync"
 
 	"github.com/juju/cmd"
@@ -16,8 +17,29 @@ import (
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/worker/logsender"
 )
 
+// logDiskBufferSize is the maximum size of the logs an agent buffers
+// on disk while it cannot send them to the controller.
+const logDiskBufferSize = 200 * 1024 * 1024
+
+// logDiskBufferMemoryLen is the number of logs an agent holds in memory
+// before buffering further logs on disk, so that few are lost if the
+// agent restarts while it cannot send them.
+const logDiskBufferMemoryLen = 1000
+
+// enableLogDiskBuffer makes the agent buffer logs it cannot send to the
+// controller in its data directory, so that they are not lost while the
+// controller is unreachable.
+func enableLogDiskBuffer(bufferedLogger *logsender.BufferedLogWriter, agentConfig agent.Config) {
+	dir := filepath.Join(agentConfig.DataDir(), "logbuffer", agentConfig.Tag().String())
+	if err := bufferedLogger.EnableDiskBuffer(dir, logDiskBufferSize, logDiskBufferMemoryLen); err != nil {
+		// This isn't fatal; logs are still buffered in memory.
+		logger.Errorf("%v", err)
+	}
+}
+
 // AgentConf is a terribly confused interface.
 //
 // Parts of it are a mixin for cmd.Command implementations; others are a mixin
@@ -27,6 +27,7 @@ import (
 	"github.com/juju/utils/featureflag"
 	"github.com/juju/utils/series"
 	"github.com/juju/utils/set"
+	"github.com/juju/utils/shell"
 	"github.com/juju/utils/symlink"
 	"github.com/juju/utils/voyeur"
 	"github.com/juju/version"
@@ -61,8 +62,10 @@ import (
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/simplestreams"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
 	jujunames "github.com/juju/juju/juju/names"
+	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/mongo/mongometrics"
@@ -525,12 +528,17 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 
 	agentConfig := a.CurrentConfig()
 	a.upgradeComplete = upgradesteps.NewLock(agentConfig)
+	enableLogDiskBuffer(a.bufferedLogger, agentConfig)
 
 	createEngine := a.makeEngineCreator(agentConfig.UpgradedToVersion())
 	charmrepo.CacheDir = filepath.Join(agentConfig.DataDir(), "charmcache")
 	if err := a.createJujudSymlinks(agentConfig.DataDir()); err != nil {
 		return err
 	}
+	if err := a.verifyAgentService(agentConfig); err != nil {
+		// This isn't fatal; the agent is already running.
+		logger.Errorf("failed to verify agent service: %v", err)
+	}
 	a.runner.StartWorker("engine", createEngine)
 
 	// At this point, all workers will have been configured to start
@@ -582,7 +590,7 @@ func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) fu
 			StartAPIWorkers:      a.startAPIWorkers,
 			PreUpgradeSteps:      a.preUpgradeSteps,
 			LogSource:            a.bufferedLogger.Logs(),
-			NewDeployContext:     newDeployContext,
+			NewDeployContext:     a.newDeployContext,
 			Clock:                clock.WallClock,
 			ValidateMigration:    a.validateMigration,
 			PrometheusRegisterer: a.prometheusRegistry,
@@ -1132,7 +1140,8 @@ func (a *MachineAgent) startStateWorkers(
 					return nil, errors.Annotate(err, "getting environ from state")
 				}
 				supportsSpaces := environs.SupportsSpaces(env)
-				w, err := peergrouperNew(st, clock.WallClock, supportsSpaces, a.centralHub)
+				zoneDir := filepath.Join(agentConfig.DataDir(), "dns")
+				w, err := peergrouperNew(st, clock.WallClock, supportsSpaces, a.centralHub, zoneDir)
 				if err != nil {
 					return nil, errors.Annotate(err, "cannot start peergrouper worker")
 				}
@@ -1361,6 +1370,16 @@ func (a *MachineAgent) newAPIserverWorker(
 		return nil, errors.Annotate(err, "getting log sink config")
 	}
 
+	var agentListener net.Listener
+	if port := controllerConfig.AgentAPIPort(); port != 0 {
+		agentEndpoint := net.JoinHostPort("", strconv.Itoa(port))
+		agentListener, err = net.Listen("tcp", agentEndpoint)
+		if err != nil {
+			listener.Close()
+			return nil, errors.Annotate(err, "cannot listen for agents")
+		}
+	}
+
 	server, err := apiserver.NewServer(statePool, listener, apiserver.ServerConfig{
 		Clock:                         clock.WallClock,
 		Cert:                          cert,
@@ -1374,11 +1393,13 @@ func (a *MachineAgent) newAPIserverWorker(
 		AutocertURL:                   controllerConfig.AutocertURL(),
 		AutocertDNSName:               controllerConfig.AutocertDNSName(),
 		AllowModelAccess:              controllerConfig.AllowModelAccess(),
+		CORSAllowedOrigins:            controllerConfig.CORSAllowedOrigins(),
 		NewObserver:                   newObserver,
 		RegisterIntrospectionHandlers: registerIntrospectionHandlers,
 		RateLimitConfig:               rateLimitConfig,
 		LogSinkConfig:                 &logSinkConfig,
 		PrometheusRegisterer:          a.prometheusRegistry,
+		AgentListener:                 agentListener,
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot start api server worker")
@@ -1822,6 +1843,59 @@ func (a *MachineAgent) createJujudSymlinks(dataDir string) error {
 	return nil
 }
 
+// verifyAgentService compares the installed definition of the agent's
+// own service with the one that would be rendered for it, and repairs
+// the definition if it has been edited or corrupted. The repaired
+// definition takes effect the next time the agent is restarted.
+func (a *MachineAgent) verifyAgentService(agentConfig agent.Config) error {
+	name := agentConfig.Value(agent.AgentServiceName)
+	if name == "" {
+		return nil
+	}
+	renderer, err := shell.NewRenderer("")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info := service.NewMachineAgentInfo(
+		a.Tag().Id(),
+		agentConfig.DataDir(),
+		agentConfig.LogDir(),
+	)
+	var conf common.Conf
+	if containerType := os.Getenv(osenv.JujuContainerTypeEnvKey); containerType != "" {
+		conf = service.ContainerAgentConf(info, renderer, containerType)
+	} else if multiwatcher.AnyJobNeedsState(agentConfig.Jobs()...) {
+		conf = service.ControllerAgentConf(info, renderer, mongo.ServiceName)
+	} else {
+		conf = service.AgentConf(info, renderer)
+	}
+	svc, err := service.NewService(name, conf, series.HostSeries())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	verifiable, ok := svc.(service.VerifiableService)
+	if !ok {
+		return nil
+	}
+	if installed, err := svc.Installed(); err != nil {
+		return errors.Trace(err)
+	} else if !installed {
+		// The agent is not being run by the init system, so
+		// there is nothing to repair.
+		logger.Debugf("service %q is not installed", name)
+		return nil
+	}
+	drift, err := verifiable.Verify()
+	if err != nil {
+		return errors.Annotatef(err, "verifying service %q", name)
+	}
+	if len(drift) == 0 {
+		return nil
+	}
+	logger.Warningf("service %q definition has drifted, repairing:\n%s", name, strings.Join(drift, "\n"))
+	return errors.Annotatef(verifiable.Repair(), "repairing service %q", name)
+}
+
 func (a *MachineAgent) createSymlink(target, link string) error {
 	fullLink := utils.EnsureBaseDir(a.rootDir, link)
 
@@ -1945,6 +2019,16 @@ var newDeployContext = func(st *apideployer.State, agentConfig agent.Config) dep
 	return deployer.NewSimpleContext(agentConfig, st)
 }
 
+// newDeployContext returns the deployer.Context used to deploy the
+// machine's units. Unless units are to be run inside the machine
+// agent process, it defers to the package-level newDeployContext.
+func (a *MachineAgent) newDeployContext(st *apideployer.State, agentConfig agent.Config) (deployer.Context, error) {
+	if featureflag.Enabled(feature.ConsolidatedUnitAgents) {
+		return deployer.NewNestedContext(agentConfig, st, a.newNestedUnitWorker)
+	}
+	return newDeployContext(st, agentConfig), nil
+}
+
 func newStateMetricsWorker(statePool *state.StatePool, registry *prometheus.Registry) worker.Worker {
 	return jworker.NewSimpleWorker(func(stop <-chan struct{}) error {
 		collector := statemetrics.New(statemetrics.NewStatePool(statePool))
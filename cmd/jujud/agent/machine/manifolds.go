@@ -35,6 +35,7 @@ import (
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/filesystemusage"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/gate"
 	"github.com/juju/juju/worker/hostkeyreporter"
@@ -126,7 +127,7 @@ type ManifoldsConfig struct {
 	// running the tests and (2) get access to the *State used internally, so that
 	// tests can be run without waiting for the 5s watcher refresh time to which we would
 	// otherwise be restricted.
-	NewDeployContext func(st *apideployer.State, agentConfig coreagent.Config) deployer.Context
+	NewDeployContext func(st *apideployer.State, agentConfig coreagent.Config) (deployer.Context, error)
 
 	// Clock supplies timekeeping services to various workers.
 	Clock clock.Clock
@@ -410,6 +411,15 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 		})),
 
+		// The filesystem usage reporter periodically records the space
+		// used on the filesystems attached to the machine it runs on.
+		// This worker will be run on all Juju-managed machines (one per
+		// machine agent).
+		filesystemUsageName: ifNotMigrating(filesystemusage.Manifold(filesystemusage.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+		})),
+
 		// The proxy config updater is a leaf worker that sets http/https/apt/etc
 		// proxy settings.
 		proxyConfigUpdater: ifNotMigrating(proxyupdater.Manifold(proxyupdater.ManifoldConfig{
@@ -551,6 +561,7 @@ const (
 	rebootName               = "reboot-executor"
 	loggingConfigUpdaterName = "logging-config-updater"
 	diskManagerName          = "disk-manager"
+	filesystemUsageName      = "filesystem-usage-reporter"
 	proxyConfigUpdater       = "proxy-config-updater"
 	apiAddressUpdaterName    = "api-address-updater"
 	machinerName             = "machiner"
@@ -0,0 +1,140 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/voyeur"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/jujud/agent/unit"
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// nestedUnitAgent is the agent.Agent for a unit whose workers run
+// inside the machine agent process.
+type nestedUnitAgent struct {
+	AgentConf
+	tag              names.UnitTag
+	configChangedVal *voyeur.Value
+}
+
+// Tag returns the unit's tag.
+func (a *nestedUnitAgent) Tag() names.Tag {
+	return a.tag
+}
+
+// ChangeConfig is part of the agent.Agent interface.
+func (a *nestedUnitAgent) ChangeConfig(mutate agent.ConfigMutator) error {
+	err := a.AgentConf.ChangeConfig(mutate)
+	a.configChangedVal.Set(true)
+	return errors.Trace(err)
+}
+
+// newNestedUnitWorker returns a dependency.Engine running the
+// responsibilities of the named unit's agent, for use by a
+// deployer.NestedContext. The unit's agent configuration must already
+// have been written to the machine agent's data directory.
+func (a *MachineAgent) newNestedUnitWorker(unitName string) (worker.Worker, error) {
+	tag := names.NewUnitTag(unitName)
+	unitAgent := &nestedUnitAgent{
+		AgentConf:        NewAgentConf(a.CurrentConfig().DataDir()),
+		tag:              tag,
+		configChangedVal: voyeur.NewValue(true),
+	}
+	if err := unitAgent.ReadConfig(tag.String()); err != nil {
+		return nil, errors.Annotatef(err, "cannot read agent configuration for unit %q", unitName)
+	}
+	agentConfig := unitAgent.CurrentConfig()
+
+	// The machine agent's log sender already forwards every record
+	// logged in the process, so the unit's workers are not given a
+	// log source of their own. They register their metrics with a
+	// registry of their own so that the collectors of different units
+	// do not collide.
+	manifolds := unit.NestedManifolds(unit.ManifoldsConfig{
+		Agent:               agent.APIHostPortsSetter{unitAgent},
+		LeadershipGuarantee: 30 * time.Second,
+		AgentConfigChanged:  unitAgent.configChangedVal,
+		ValidateMigration: func(apiCaller base.APICaller) error {
+			return validateUnitMigration(apiCaller, tag, agentConfig.Model().Id())
+		},
+		PrometheusRegisterer: prometheus.NewRegistry(),
+	})
+
+	config := dependency.EngineConfig{
+		IsFatal:     cmdutil.IsFatal,
+		WorstError:  cmdutil.MoreImportantError,
+		ErrorDelay:  3 * time.Second,
+		BounceDelay: 10 * time.Millisecond,
+	}
+	engine, err := dependency.NewEngine(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := dependency.Install(engine, manifolds); err != nil {
+		if err := worker.Stop(engine); err != nil {
+			logger.Errorf("while stopping engine with bad manifolds: %v", err)
+		}
+		return nil, errors.Trace(err)
+	}
+
+	// Output from the unit's hooks continues to be written to the
+	// unit's own log file, as it would be by a standalone unit agent.
+	writerName := "nested-" + tag.String()
+	logFile := &lumberjack.Logger{
+		Filename:   agent.LogFilename(agentConfig),
+		MaxSize:    300, // megabytes
+		MaxBackups: 2,
+		Compress:   true,
+	}
+	loggo.RemoveWriter(writerName)
+	if err := loggo.RegisterWriter(writerName, newUnitLogWriter(unitName, logFile)); err != nil {
+		worker.Stop(engine)
+		return nil, errors.Annotatef(err, "cannot log output for unit %q", unitName)
+	}
+	go func() {
+		engine.Wait()
+		loggo.RemoveWriter(writerName)
+		logFile.Close()
+	}()
+	return engine, nil
+}
+
+// unitLogWriter is a loggo.Writer that writes the records logged by
+// a single unit's hooks to the given target.
+type unitLogWriter struct {
+	prefix string
+	target io.Writer
+}
+
+func newUnitLogWriter(unitName string, target io.Writer) *unitLogWriter {
+	return &unitLogWriter{
+		prefix: "unit." + unitName + ".",
+		target: target,
+	}
+}
+
+// Write is part of the loggo.Writer interface.
+func (w *unitLogWriter) Write(entry loggo.Entry) {
+	if !strings.HasPrefix(entry.Module, w.prefix) {
+		return
+	}
+	ts := entry.Timestamp.In(time.UTC).Format("2006-01-02 15:04:05")
+	// Just show the last element of the module.
+	module := entry.Module[strings.LastIndex(entry.Module, ".")+1:]
+	fmt.Fprintf(w.target, "%s %s %s %s\n", ts, entry.Level, module, entry.Message)
+}
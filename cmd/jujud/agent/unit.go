@@ -5,6 +5,7 @@ package agent
 
 import (
 	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/juju/cmd"
@@ -29,6 +30,7 @@ import (
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/introspection"
 	"github.com/juju/juju/worker/logsender"
+	workeruniter "github.com/juju/juju/worker/uniter"
 )
 
 var (
@@ -149,6 +151,7 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 		return err
 	}
 	agentLogger.Infof("unit agent %v start (%s [%s])", a.Tag().String(), jujuversion.Current, runtime.Compiler)
+	enableLogDiskBuffer(a.bufferedLogger, a.CurrentConfig())
 	if flags := featureflag.String(); flags != "" {
 		logger.Warningf("developer feature flags enabled: %s", flags)
 	}
@@ -161,13 +164,19 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 
 // APIWorkers returns a dependency.Engine running the unit agent's responsibilities.
 func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
+	relationCacheMaxEntries, relationCacheTTL, err := getRelationCacheBounds(a.CurrentConfig())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	manifolds := unitManifolds(unit.ManifoldsConfig{
-		Agent:                agent.APIHostPortsSetter{a},
-		LogSource:            a.bufferedLogger.Logs(),
-		LeadershipGuarantee:  30 * time.Second,
-		AgentConfigChanged:   a.configChangedVal,
-		ValidateMigration:    a.validateMigration,
-		PrometheusRegisterer: a.prometheusRegistry,
+		Agent:                   agent.APIHostPortsSetter{a},
+		LogSource:               a.bufferedLogger.Logs(),
+		LeadershipGuarantee:     30 * time.Second,
+		AgentConfigChanged:      a.configChangedVal,
+		ValidateMigration:       a.validateMigration,
+		PrometheusRegisterer:    a.prometheusRegistry,
+		RelationCacheMaxEntries: relationCacheMaxEntries,
+		RelationCacheTTL:        relationCacheTTL,
 	})
 
 	config := dependency.EngineConfig{
@@ -202,6 +211,28 @@ func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
 	return engine, nil
 }
 
+// getRelationCacheBounds returns the bounds of the uniter's relation
+// settings caches, which may be overridden in the agent config.
+func getRelationCacheBounds(cfg agent.Config) (maxEntries int, ttl time.Duration, err error) {
+	maxEntries = workeruniter.DefaultRelationCacheMaxEntries
+	ttl = workeruniter.DefaultRelationCacheTTL
+	if v := cfg.Value(agent.RelationCacheMaxEntries); v != "" {
+		if maxEntries, err = strconv.Atoi(v); err != nil {
+			return 0, 0, errors.Annotatef(err, "parsing %s", agent.RelationCacheMaxEntries)
+		} else if maxEntries < 0 {
+			return 0, 0, errors.NotValidf("negative %s", agent.RelationCacheMaxEntries)
+		}
+	}
+	if v := cfg.Value(agent.RelationCacheTTL); v != "" {
+		if ttl, err = time.ParseDuration(v); err != nil {
+			return 0, 0, errors.Annotatef(err, "parsing %s", agent.RelationCacheTTL)
+		} else if ttl < 0 {
+			return 0, 0, errors.NotValidf("negative %s", agent.RelationCacheTTL)
+		}
+	}
+	return maxEntries, ttl, nil
+}
+
 func (a *UnitAgent) Tag() names.Tag {
 	return names.NewUnitTag(a.UnitName)
 }
@@ -215,8 +246,14 @@ func (a *UnitAgent) ChangeConfig(mutate agent.ConfigMutator) error {
 // validateMigration is called by the migrationminion to help check
 // that the agent will be ok when connected to a new controller.
 func (a *UnitAgent) validateMigration(apiCaller base.APICaller) error {
+	modelUUID := a.CurrentConfig().Model().Id()
+	return validateUnitMigration(apiCaller, names.NewUnitTag(a.UnitName), modelUUID)
+}
+
+// validateUnitMigration checks that the unit is known to the
+// controller at the other end of apiCaller, in the expected model.
+func validateUnitMigration(apiCaller base.APICaller, unitTag names.UnitTag, curModelUUID string) error {
 	// TODO(mjs) - more extensive checks to come.
-	unitTag := names.NewUnitTag(a.UnitName)
 	facade := uniter.NewState(apiCaller, unitTag)
 	_, err := facade.Unit(unitTag)
 	if err != nil {
@@ -226,7 +263,6 @@ func (a *UnitAgent) validateMigration(apiCaller base.APICaller) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	curModelUUID := a.CurrentConfig().Model().Id()
 	newModelUUID := model.UUID()
 	if newModelUUID != curModelUUID {
 		return errors.Errorf("model mismatch when validating: got %q, expected %q",
@@ -64,6 +64,11 @@ type ManifoldsConfig struct {
 	// PrometheusRegisterer is a prometheus.Registerer that may be used
 	// by workers to register Prometheus metric collectors.
 	PrometheusRegisterer prometheus.Registerer
+
+	// RelationCacheMaxEntries and RelationCacheTTL bound the uniter's
+	// relation settings caches; zero values leave them unbounded.
+	RelationCacheMaxEntries int
+	RelationCacheTTL        time.Duration
 }
 
 // Manifolds returns a set of co-configured manifolds covering the various
@@ -229,6 +234,10 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			CharmDirName:          charmDirName,
 			HookRetryStrategyName: hookRetryStrategyName,
 			TranslateResolverErr:  uniter.TranslateFortressErrors,
+
+			RelationCacheMaxEntries: config.RelationCacheMaxEntries,
+			RelationCacheTTL:        config.RelationCacheTTL,
+			PrometheusRegisterer:    config.PrometheusRegisterer,
 		})),
 
 		// TODO (mattyw) should be added to machine agent.
@@ -266,6 +275,22 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 	}
 }
 
+// NestedManifolds returns the manifolds needed to run a unit agent
+// inside the machine agent process. It omits the workers that act on
+// behalf of the whole process, which the machine agent already runs.
+func NestedManifolds(config ManifoldsConfig) dependency.Manifolds {
+	manifolds := Manifolds(config)
+	for _, name := range []string{
+		logSenderName,
+		upgraderName,
+		loggingConfigUpdaterName,
+		proxyConfigUpdaterName,
+	} {
+		delete(manifolds, name)
+	}
+	return manifolds
+}
+
 var ifNotMigrating = engine.Housing{
 	Flags: []string{
 		migrationInactiveFlagName,
@@ -61,6 +61,27 @@ func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
 	c.Assert(expectedKeys, jc.SameContents, keys)
 }
 
+func (s *ManifoldsSuite) TestNestedManifoldNames(c *gc.C) {
+	manifolds := unit.NestedManifolds(unit.ManifoldsConfig{})
+	for _, name := range []string{
+		"log-sender",
+		"upgrader",
+		"logging-config-updater",
+		"proxy-config-updater",
+	} {
+		c.Check(manifolds[name].Start, gc.IsNil, gc.Commentf("%q", name))
+	}
+	for _, name := range []string{
+		"agent",
+		"api-caller",
+		"api-address-updater",
+		"leadership-tracker",
+		"uniter",
+	} {
+		c.Check(manifolds[name].Start, gc.NotNil, gc.Commentf("%q", name))
+	}
+}
+
 func (*ManifoldsSuite) TestMigrationGuards(c *gc.C) {
 	exempt := set.NewStrings(
 		"agent",
@@ -454,3 +454,38 @@ func (s *UnitSuite) TestWorkers(c *gc.C) {
 		append(alwaysUnitWorkers, notMigratingUnitWorkers...))
 	WaitMatch(c, matcher.Check, coretesting.LongWait, s.BackingState.StartSync)
 }
+
+type RelationCacheBoundsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&RelationCacheBoundsSuite{})
+
+func (s *RelationCacheBoundsSuite) TestDefaults(c *gc.C) {
+	maxEntries, ttl, err := getRelationCacheBounds(FakeConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(maxEntries, gc.Equals, 1000)
+	c.Assert(ttl, gc.Equals, time.Hour)
+}
+
+func (s *RelationCacheBoundsSuite) TestOverridden(c *gc.C) {
+	maxEntries, ttl, err := getRelationCacheBounds(FakeConfig{values: map[string]string{
+		agent.RelationCacheMaxEntries: "50000",
+		agent.RelationCacheTTL:        "0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(maxEntries, gc.Equals, 50000)
+	c.Assert(ttl, gc.Equals, time.Duration(0))
+}
+
+func (s *RelationCacheBoundsSuite) TestInvalid(c *gc.C) {
+	for _, values := range []map[string]string{
+		{agent.RelationCacheMaxEntries: "lots"},
+		{agent.RelationCacheMaxEntries: "-1"},
+		{agent.RelationCacheTTL: "forever"},
+		{agent.RelationCacheTTL: "-1h"},
+	} {
+		_, _, err := getRelationCacheBounds(FakeConfig{values: values})
+		c.Check(err, gc.ErrorMatches, `(parsing|negative) RELATION_CACHE_.*`)
+	}
+}
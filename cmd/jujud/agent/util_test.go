@@ -92,7 +92,7 @@ func (s *commonMachineSuite) SetUpTest(c *gc.C) {
 
 	s.singularRecord = newSingularRunnerRecord()
 	s.PatchValue(&newSingularRunner, s.singularRecord.newSingularRunner)
-	s.PatchValue(&peergrouperNew, func(*state.State, clock.Clock, bool, peergrouper.Hub) (worker.Worker, error) {
+	s.PatchValue(&peergrouperNew, func(*state.State, clock.Clock, bool, peergrouper.Hub, string) (worker.Worker, error) {
 		return newDummyWorker(), nil
 	})
 
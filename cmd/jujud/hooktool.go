@@ -0,0 +1,192 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/exec"
+
+	"github.com/juju/juju/juju/sockets"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+const (
+	// hookToolTimeoutEnvKey names the environment variable that
+	// overrides how long a hook tool waits for the unit agent to run
+	// it. A value of 0 means wait forever.
+	hookToolTimeoutEnvKey = "JUJU_HOOK_TOOL_TIMEOUT"
+
+	// hookToolAttemptsEnvKey names the environment variable that
+	// overrides how many times a hook tool tries to reach the unit
+	// agent when the connection fails transiently.
+	hookToolAttemptsEnvKey = "JUJU_HOOK_TOOL_ATTEMPTS"
+
+	defaultHookToolTimeout  = 10 * time.Minute
+	defaultHookToolAttempts = 5
+	defaultHookToolDelay    = 250 * time.Millisecond
+)
+
+// hookToolConfig holds the timeout and retry settings used when a
+// hook tool asks the unit agent to run it.
+type hookToolConfig struct {
+	// Timeout is how long to wait for each call to the unit agent
+	// to complete. Zero means wait forever.
+	Timeout time.Duration
+
+	// Attempts is the maximum number of calls made to the unit
+	// agent, when earlier calls fail transiently.
+	Attempts int
+
+	// Delay is the time to wait before the first retry; the delay
+	// doubles with each subsequent retry.
+	Delay time.Duration
+
+	// Clock is used for timeouts and retry delays.
+	Clock clock.Clock
+}
+
+// hookToolConfigFromEnv returns the default hookToolConfig, with any
+// overrides given in the environment applied.
+func hookToolConfigFromEnv() (hookToolConfig, error) {
+	config := hookToolConfig{
+		Timeout:  defaultHookToolTimeout,
+		Attempts: defaultHookToolAttempts,
+		Delay:    defaultHookToolDelay,
+		Clock:    clock.WallClock,
+	}
+	if value := os.Getenv(hookToolTimeoutEnvKey); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if value == "0" {
+			timeout, err = 0, nil
+		}
+		if err != nil || timeout < 0 {
+			return hookToolConfig{}, errors.NotValidf("%s %q", hookToolTimeoutEnvKey, value)
+		}
+		config.Timeout = timeout
+	}
+	if value := os.Getenv(hookToolAttemptsEnvKey); value != "" {
+		attempts, err := strconv.Atoi(value)
+		if err != nil || attempts < 1 {
+			return hookToolConfig{}, errors.NotValidf("%s %q", hookToolAttemptsEnvKey, value)
+		}
+		config.Attempts = attempts
+	}
+	return config, nil
+}
+
+// dialJujuc is used to connect to the unit agent's jujuc server. It
+// is a variable so that it can be replaced in tests.
+var dialJujuc = sockets.Dial
+
+// callJujuc asks the unit agent listening on socketPath to run the
+// requested hook tool, and returns the tool's response.
+//
+// Connections that fail, or are dropped before a response is
+// received, are retried: changes made by hook tools are recorded in
+// the hook context and only committed when the hook completes, so a
+// request may safely be run again. A call that times out is not
+// retried, as the agent is then likely to be wedged rather than
+// merely busy.
+func callJujuc(socketPath string, req jujuc.Request, config hookToolConfig) (*exec.ExecResponse, error) {
+	var resp *exec.ExecResponse
+	err := retry.Call(retry.CallArgs{
+		Func: func() error {
+			var err error
+			resp, err = callJujucOnce(socketPath, &req, config)
+			return err
+		},
+		IsFatalError: func(err error) bool {
+			return !isTransientJujucError(err)
+		},
+		Attempts:    config.Attempts,
+		Delay:       config.Delay,
+		BackoffFunc: retry.DoubleDelay,
+		Clock:       config.Clock,
+	})
+	if err != nil {
+		return nil, retry.LastError(err)
+	}
+	return resp, nil
+}
+
+// callJujucOnce makes a single connection to the unit agent to run
+// the requested hook tool. If the tool needs its standard input, it
+// is read and added to the request, which is then sent again.
+func callJujucOnce(socketPath string, req *jujuc.Request, config hookToolConfig) (*exec.ExecResponse, error) {
+	client, err := dialJujuc(socketPath)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	resp, err := callJujucMain(client, *req, config)
+	if err != nil && err.Error() == jujuc.ErrNoStdin.Error() {
+		req.Stdin, err = ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read stdin")
+		}
+		req.StdinSet = true
+		resp, err = callJujucMain(client, *req, config)
+	}
+	return resp, err
+}
+
+func callJujucMain(client *rpc.Client, req jujuc.Request, config hookToolConfig) (*exec.ExecResponse, error) {
+	var resp exec.ExecResponse
+	call := client.Go("Jujuc.Main", req, &resp, nil)
+	var timeout <-chan time.Time
+	if config.Timeout > 0 {
+		timeout = config.Clock.After(config.Timeout)
+	}
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			return nil, call.Error
+		}
+		return &resp, nil
+	case <-timeout:
+		return nil, errors.Errorf(
+			"timed out after %v waiting for the unit agent to run %q (see %s)",
+			config.Timeout, req.CommandName, hookToolTimeoutEnvKey,
+		)
+	}
+}
+
+// isTransientJujucError returns true if the given error indicates
+// that the connection to the unit agent failed in a way that may not
+// recur.
+func isTransientJujucError(err error) bool {
+	switch errors.Cause(err) {
+	case io.EOF, io.ErrUnexpectedEOF, rpc.ErrShutdown:
+		return true
+	}
+	opErr, ok := errors.Cause(err).(*net.OpError)
+	if !ok {
+		return false
+	}
+	errno, ok := opErr.Err.(syscall.Errno)
+	if !ok {
+		if sysErr, isSysErr := opErr.Err.(*os.SyscallError); isSysErr {
+			errno, ok = sysErr.Err.(syscall.Errno)
+		}
+	}
+	if !ok {
+		return false
+	}
+	switch errno {
+	case syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.EPIPE, syscall.EAGAIN:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/rpc"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type HookToolSuite struct {
+	testing.IsolationSuite
+	sockPath string
+	server   *jujuc.Server
+	release  chan struct{}
+	dials    int
+}
+
+var _ = gc.Suite(&HookToolSuite{})
+
+// blockingCommand is a hook tool that does not complete until the
+// test releases it.
+type blockingCommand struct {
+	cmd.CommandBase
+	release <-chan struct{}
+}
+
+func (c *blockingCommand) Info() *cmd.Info {
+	return &cmd.Info{Name: "block"}
+}
+
+func (c *blockingCommand) Run(ctx *cmd.Context) error {
+	<-c.release
+	return nil
+}
+
+func (s *HookToolSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	if runtime.GOOS == "windows" {
+		c.Skip("hook tool sockets are named pipes on windows")
+	}
+	s.release = make(chan struct{})
+	s.dials = 0
+	factory := func(contextId, cmdName string) (cmd.Command, error) {
+		if cmdName == "block" {
+			return &blockingCommand{release: s.release}, nil
+		}
+		return &RemoteCommand{}, nil
+	}
+	s.sockPath = osDependentSockPath(c)
+	srv, err := jujuc.NewServer(factory, s.sockPath)
+	c.Assert(err, jc.ErrorIsNil)
+	s.server = srv
+	go s.server.Run()
+	s.AddCleanup(func(*gc.C) {
+		close(s.release)
+		s.server.Close()
+	})
+}
+
+func (s *HookToolSuite) patchDial(errs ...error) {
+	s.PatchValue(&dialJujuc, func(socketPath string) (*rpc.Client, error) {
+		s.dials++
+		if len(errs) > 0 {
+			err := errs[0]
+			errs = errs[1:]
+			if err != nil {
+				return nil, err
+			}
+		}
+		return rpc.Dial("unix", socketPath)
+	})
+}
+
+func (s *HookToolSuite) config() hookToolConfig {
+	return hookToolConfig{
+		Timeout:  time.Minute,
+		Attempts: 3,
+		Delay:    time.Millisecond,
+		Clock:    clock.WallClock,
+	}
+}
+
+func dialError(errno syscall.Errno) error {
+	return &net.OpError{
+		Op:  "dial",
+		Net: "unix",
+		Err: &os.SyscallError{Syscall: "connect", Err: errno},
+	}
+}
+
+func request(c *gc.C, command string) jujuc.Request {
+	return jujuc.Request{
+		ContextId:   "ctx",
+		Dir:         c.MkDir(),
+		CommandName: command,
+		// Don't let the command read the test's own stdin.
+		StdinSet: true,
+	}
+}
+
+func (s *HookToolSuite) TestCall(c *gc.C) {
+	s.patchDial()
+	resp, err := callJujuc(s.sockPath, request(c, "remote"), s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Code, gc.Equals, 0)
+	c.Assert(string(resp.Stdout), gc.Equals, "success!\n")
+	c.Assert(s.dials, gc.Equals, 1)
+}
+
+func (s *HookToolSuite) TestRetriesTransientErrors(c *gc.C) {
+	s.patchDial(dialError(syscall.ECONNREFUSED), dialError(syscall.EAGAIN))
+	resp, err := callJujuc(s.sockPath, request(c, "remote"), s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(resp.Stdout), gc.Equals, "success!\n")
+	c.Assert(s.dials, gc.Equals, 3)
+}
+
+func (s *HookToolSuite) TestRetriesDroppedConnection(c *gc.C) {
+	s.patchDial(errors.Trace(rpc.ErrShutdown))
+	_, err := callJujuc(s.sockPath, request(c, "remote"), s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.dials, gc.Equals, 2)
+}
+
+func (s *HookToolSuite) TestAttemptsExhausted(c *gc.C) {
+	refused := dialError(syscall.ECONNREFUSED)
+	s.patchDial(refused, refused, refused)
+	_, err := callJujuc(s.sockPath, request(c, "remote"), s.config())
+	c.Assert(err, gc.Equals, refused)
+	c.Assert(s.dials, gc.Equals, 3)
+}
+
+func (s *HookToolSuite) TestNoRetryPermanentError(c *gc.C) {
+	s.patchDial(dialError(syscall.ENOENT))
+	_, err := callJujuc(s.sockPath, request(c, "remote"), s.config())
+	c.Assert(err, gc.ErrorMatches, "dial unix: connect: no such file or directory")
+	c.Assert(s.dials, gc.Equals, 1)
+}
+
+func (s *HookToolSuite) TestTimeout(c *gc.C) {
+	s.patchDial()
+	config := s.config()
+	config.Timeout = 50 * time.Millisecond
+	_, err := callJujuc(s.sockPath, request(c, "block"), config)
+	c.Assert(err, gc.ErrorMatches, `timed out after 50ms waiting for the unit agent to run "block" \(see JUJU_HOOK_TOOL_TIMEOUT\)`)
+	// Timeouts are not retried.
+	c.Assert(s.dials, gc.Equals, 1)
+}
+
+func (s *HookToolSuite) TestConfigFromEnv(c *gc.C) {
+	config, err := hookToolConfigFromEnv()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Timeout, gc.Equals, defaultHookToolTimeout)
+	c.Assert(config.Attempts, gc.Equals, defaultHookToolAttempts)
+
+	s.PatchEnvironment(hookToolTimeoutEnvKey, "90s")
+	s.PatchEnvironment(hookToolAttemptsEnvKey, "2")
+	config, err = hookToolConfigFromEnv()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Timeout, gc.Equals, 90*time.Second)
+	c.Assert(config.Attempts, gc.Equals, 2)
+
+	s.PatchEnvironment(hookToolTimeoutEnvKey, "0")
+	config, err = hookToolConfigFromEnv()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config.Timeout, gc.Equals, time.Duration(0))
+}
+
+func (s *HookToolSuite) TestConfigFromEnvInvalid(c *gc.C) {
+	for key, value := range map[string]string{
+		hookToolTimeoutEnvKey:  "soon",
+		hookToolAttemptsEnvKey: "0",
+	} {
+		s.PatchEnvironment(key, value)
+		_, err := hookToolConfigFromEnv()
+		c.Check(err, gc.ErrorMatches, fmt.Sprintf("%s %q not valid", key, value))
+		s.PatchEnvironment(key, "")
+	}
+}
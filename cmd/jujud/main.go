@@ -6,7 +6,6 @@ package main
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -16,7 +15,6 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
-	"github.com/juju/utils/exec"
 	proxyutils "github.com/juju/utils/proxy"
 
 	"github.com/juju/juju/agent"
@@ -26,7 +24,6 @@ import (
 	"github.com/juju/juju/cmd/jujud/introspect"
 	components "github.com/juju/juju/component/all"
 	"github.com/juju/juju/juju/names"
-	"github.com/juju/juju/juju/sockets"
 	// Import the providers.
 	_ "github.com/juju/juju/provider/all"
 	"github.com/juju/juju/upgrades"
@@ -53,7 +50,10 @@ https://jujucharms.com/
 The jujud command can also forward invocations over RPC for execution by the
 juju unit agent. When used in this way, it expects to be called via a symlink
 named for the desired remote command, and expects JUJU_AGENT_SOCKET and
-JUJU_CONTEXT_ID be set in its model.
+JUJU_CONTEXT_ID be set in its model. Calls that fail because the connection
+to the unit agent was refused or dropped are retried up to
+JUJU_HOOK_TOOL_ATTEMPTS times (default 5), and each call waits for at most
+JUJU_HOOK_TOOL_TIMEOUT (default 10m; 0 waits forever).
 `
 
 const (
@@ -106,22 +106,11 @@ func jujuCMain(commandName string, ctx *cmd.Context, args []string) (code int, e
 	if err != nil {
 		return
 	}
-	client, err := sockets.Dial(socketPath)
+	config, err := hookToolConfigFromEnv()
 	if err != nil {
 		return
 	}
-	defer client.Close()
-	var resp exec.ExecResponse
-	err = client.Call("Jujuc.Main", req, &resp)
-	if err != nil && err.Error() == jujuc.ErrNoStdin.Error() {
-		req.Stdin, err = ioutil.ReadAll(os.Stdin)
-		if err != nil {
-			err = errors.Annotate(err, "cannot read stdin")
-			return
-		}
-		req.StdinSet = true
-		err = client.Call("Jujuc.Main", req, &resp)
-	}
+	resp, err := callJujuc(socketPath, req, config)
 	if err != nil {
 		return
 	}
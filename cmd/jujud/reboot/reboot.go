@@ -98,11 +98,12 @@ func (r *Reboot) stopDeployedUnits() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	services, err := service.ListServices()
+	services, err := service.ListServicesWithInfo()
 	if err != nil {
 		return err
 	}
-	for _, svcName := range services {
+	for _, info := range services {
+		svcName := info.Name
 		if strings.HasPrefix(svcName, `jujud-unit-`) {
 			svc, err := service.NewService(svcName, common.Conf{}, osVersion)
 			if err != nil {
@@ -98,7 +98,7 @@ func (s *RebootSuite) SetUpTest(c *gc.C) {
 		s.addService(fake)
 	}
 	testing.PatchValue(&service.NewService, s.newService)
-	testing.PatchValue(&service.ListServices, s.listServices)
+	testing.PatchValue(&service.ListServicesWithInfo, s.listServices)
 }
 
 func (s *RebootSuite) addService(name string) {
@@ -107,8 +107,12 @@ func (s *RebootSuite) addService(name string) {
 	svc.Start()
 }
 
-func (s *RebootSuite) listServices() ([]string, error) {
-	return s.serviceData.InstalledNames(), nil
+func (s *RebootSuite) listServices() ([]common.ServiceInfo, error) {
+	var infos []common.ServiceInfo
+	for _, name := range s.serviceData.InstalledNames() {
+		infos = append(infos, common.ServiceInfo{Name: name})
+	}
+	return infos, nil
 }
 
 func (s *RebootSuite) newService(name string, conf common.Conf, series string) (service.Service, error) {
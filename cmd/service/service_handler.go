@@ -7,7 +7,12 @@
 package service
 
 import (
+	"os"
+
+	"github.com/juju/errors"
 	"golang.org/x/sys/windows/svc"
+
+	"github.com/juju/juju/juju/osenv"
 )
 
 // SystemService type that is responsible for managing the life-cycle of the service
@@ -51,7 +56,13 @@ func (s *SystemService) Execute(args []string, changeReq <-chan svc.ChangeReques
 	}
 }
 
-// Run runs the service
+// Run runs the service, in the working directory given by its conf,
+// if any.
 func (s *SystemService) Run() error {
+	if dir := os.Getenv(osenv.JujuServiceWorkingDirEnvKey); dir != "" {
+		if err := os.Chdir(dir); err != nil {
+			return errors.Annotate(err, "changing to service working directory")
+		}
+	}
 	return svc.Run(s.Name, s)
 }
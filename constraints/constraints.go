@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -23,15 +24,20 @@ const (
 	Arch      = "arch"
 	Container = "container"
 	// cpuCores is an alias for Cores.
-	cpuCores     = "cpu-cores"
-	Cores        = "cores"
-	CpuPower     = "cpu-power"
-	Mem          = "mem"
-	RootDisk     = "root-disk"
-	Tags         = "tags"
-	InstanceType = "instance-type"
-	Spaces       = "spaces"
-	VirtType     = "virt-type"
+	cpuCores       = "cpu-cores"
+	Cores          = "cores"
+	CpuPower       = "cpu-power"
+	Mem            = "mem"
+	RootDisk       = "root-disk"
+	Tags           = "tags"
+	InstanceType   = "instance-type"
+	Spaces         = "spaces"
+	VirtType       = "virt-type"
+	ImageId        = "image-id"
+	Gpu            = "gpu"
+	UsbDevice      = "usb-device"
+	RootDiskSource = "root-disk-source"
+	ResourcePool   = "resource-pool"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -85,6 +91,34 @@ type Value struct {
 	// VirtType, if not nil or empty, indicates that a machine must run the named
 	// virtual type. Only valid for clouds with multi-hypervisor support.
 	VirtType *string `json:"virt-type,omitempty" yaml:"virt-type,omitempty"`
+
+	// ImageId, if not nil or empty, indicates that a machine must be
+	// started from the specified cloud image, rather than one chosen
+	// from the image metadata. Only valid for clouds which select
+	// images from image metadata.
+	ImageId *string `json:"image-id,omitempty" yaml:"image-id,omitempty"`
+
+	// Gpus, if not nil, indicates that a machine must have at least
+	// that number of GPUs passed through to it. Only valid for
+	// providers that support device passthrough.
+	Gpus *uint64 `json:"gpu,omitempty" yaml:"gpu,omitempty"`
+
+	// UsbDevices, if not nil, holds a list of USB devices, each
+	// identified as "<vendor id>:<product id>" in hexadecimal, that
+	// must be passed through to a machine. Only valid for providers
+	// that support device passthrough.
+	UsbDevices *[]string `json:"usb-device,omitempty" yaml:"usb-device,omitempty"`
+
+	// RootDiskSource, if not nil or empty, names the storage location,
+	// such as a vSphere datastore, on which a machine's root disk must
+	// be created.
+	RootDiskSource *string `json:"root-disk-source,omitempty" yaml:"root-disk-source,omitempty"`
+
+	// ResourcePool, if not nil or empty, holds the path of the
+	// resource pool, relative to the availability zone's root pool,
+	// in which a machine must be created. Only valid for clouds with
+	// resource pools, such as vSphere.
+	ResourcePool *string `json:"resource-pool,omitempty" yaml:"resource-pool,omitempty"`
 }
 
 var rawAliases = map[string]string{
@@ -180,6 +214,35 @@ func (v *Value) HasVirtType() bool {
 	return v.VirtType != nil && *v.VirtType != ""
 }
 
+// HasImageId returns true if the constraints.Value specifies an image id.
+func (v *Value) HasImageId() bool {
+	return v.ImageId != nil && *v.ImageId != ""
+}
+
+// HasGpus returns true if the constraints.Value specifies a minimum
+// number of GPUs.
+func (v *Value) HasGpus() bool {
+	return v.Gpus != nil && *v.Gpus > 0
+}
+
+// HasUsbDevices returns true if the constraints.Value specifies any
+// USB devices.
+func (v *Value) HasUsbDevices() bool {
+	return v.UsbDevices != nil && len(*v.UsbDevices) > 0
+}
+
+// HasRootDiskSource returns true if the constraints.Value specifies a
+// source for the root disk.
+func (v *Value) HasRootDiskSource() bool {
+	return v.RootDiskSource != nil && *v.RootDiskSource != ""
+}
+
+// HasResourcePool returns true if the constraints.Value specifies a
+// resource pool.
+func (v *Value) HasResourcePool() bool {
+	return v.ResourcePool != nil && *v.ResourcePool != ""
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -223,6 +286,22 @@ func (v Value) String() string {
 	if v.VirtType != nil {
 		strs = append(strs, "virt-type="+string(*v.VirtType))
 	}
+	if v.ImageId != nil {
+		strs = append(strs, "image-id="+*v.ImageId)
+	}
+	if v.Gpus != nil {
+		strs = append(strs, "gpu="+uintStr(*v.Gpus))
+	}
+	if v.UsbDevices != nil {
+		s := strings.Join(*v.UsbDevices, ",")
+		strs = append(strs, "usb-device="+s)
+	}
+	if v.RootDiskSource != nil {
+		strs = append(strs, "root-disk-source="+*v.RootDiskSource)
+	}
+	if v.ResourcePool != nil {
+		strs = append(strs, "resource-pool="+*v.ResourcePool)
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -264,6 +343,23 @@ func (v Value) GoString() string {
 	if v.VirtType != nil {
 		values = append(values, fmt.Sprintf("VirtType: %q", *v.VirtType))
 	}
+	if v.ImageId != nil {
+		values = append(values, fmt.Sprintf("ImageId: %q", *v.ImageId))
+	}
+	if v.Gpus != nil {
+		values = append(values, fmt.Sprintf("Gpus: %v", *v.Gpus))
+	}
+	if v.UsbDevices != nil && *v.UsbDevices != nil {
+		values = append(values, fmt.Sprintf("UsbDevices: %q", *v.UsbDevices))
+	} else if v.UsbDevices != nil {
+		values = append(values, "UsbDevices: (*[]string)(nil)")
+	}
+	if v.RootDiskSource != nil {
+		values = append(values, fmt.Sprintf("RootDiskSource: %q", *v.RootDiskSource))
+	}
+	if v.ResourcePool != nil {
+		values = append(values, fmt.Sprintf("ResourcePool: %q", *v.ResourcePool))
+	}
 	return fmt.Sprintf("{%s}", strings.Join(values, ", "))
 }
 
@@ -420,6 +516,16 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setSpaces(str)
 	case VirtType:
 		err = v.setVirtType(str)
+	case ImageId:
+		err = v.setImageId(str)
+	case Gpu:
+		err = v.setGpus(str)
+	case UsbDevice:
+		err = v.setUsbDevices(str)
+	case RootDiskSource:
+		err = v.setRootDiskSource(str)
+	case ResourcePool:
+		err = v.setResourcePool(str)
 	default:
 		return errors.Errorf("unknown constraint %q", name)
 	}
@@ -483,6 +589,24 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			}
 		case VirtType:
 			v.VirtType = &vstr
+		case ImageId:
+			v.ImageId = &vstr
+		case Gpu:
+			v.Gpus, err = parseUint64(vstr)
+		case UsbDevice:
+			var devices *[]string
+			devices, err = parseYamlStrings("usb-device", val)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = validateUsbDevices(devices)
+			if err == nil {
+				v.UsbDevices = devices
+			}
+		case RootDiskSource:
+			v.RootDiskSource = &vstr
+		case ResourcePool:
+			v.ResourcePool = &vstr
 		default:
 			return errors.Errorf("unknown constraint value: %v", k)
 		}
@@ -607,6 +731,74 @@ func (v *Value) setVirtType(str string) error {
 	return nil
 }
 
+func (v *Value) setImageId(str string) error {
+	if v.ImageId != nil {
+		return errors.Errorf("already set")
+	}
+	v.ImageId = &str
+	return nil
+}
+
+func (v *Value) setGpus(str string) (err error) {
+	if v.Gpus != nil {
+		return errors.Errorf("already set")
+	}
+	v.Gpus, err = parseUint64(str)
+	return
+}
+
+func (v *Value) setUsbDevices(str string) error {
+	if v.UsbDevices != nil {
+		return errors.Errorf("already set")
+	}
+	devices := parseCommaDelimited(str)
+	if err := validateUsbDevices(devices); err != nil {
+		return err
+	}
+	v.UsbDevices = devices
+	return nil
+}
+
+func (v *Value) setRootDiskSource(str string) error {
+	if v.RootDiskSource != nil {
+		return errors.Errorf("already set")
+	}
+	v.RootDiskSource = &str
+	return nil
+}
+
+func (v *Value) setResourcePool(str string) error {
+	if v.ResourcePool != nil {
+		return errors.Errorf("already set")
+	}
+	v.ResourcePool = &str
+	return nil
+}
+
+var validUsbDevice = regexp.MustCompile(`^[0-9a-fA-F]{4}:[0-9a-fA-F]{4}$`)
+
+func validateUsbDevices(devices *[]string) error {
+	if devices == nil {
+		return nil
+	}
+	for _, device := range *devices {
+		if !validUsbDevice.MatchString(device) {
+			return errors.Errorf("%q is not a valid USB device, expected <vendor id>:<product id>", device)
+		}
+	}
+	return nil
+}
+
+// ParseUsbDevice returns the vendor and product ids, in lower case,
+// of a USB device given as it appears in the usb-device constraint.
+func ParseUsbDevice(device string) (vendorId, productId string, err error) {
+	if !validUsbDevice.MatchString(device) {
+		return "", "", errors.NotValidf("USB device %q", device)
+	}
+	device = strings.ToLower(device)
+	return device[:4], device[5:], nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
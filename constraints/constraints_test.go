@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	goyaml "gopkg.in/yaml.v2"
@@ -313,6 +314,86 @@ var parseConstraintsTests = []struct {
 		err:     `bad "virt-type" constraint: already set`,
 	},
 
+	// "image-id" in detail.
+	{
+		summary: "set image-id empty",
+		args:    []string{"image-id="},
+	}, {
+		summary: "set image-id",
+		args:    []string{"image-id=ami-0123456789abcdef0"},
+	}, {
+		summary: "double set image-id together",
+		args:    []string{"image-id=ami-1 image-id=ami-1"},
+		err:     `bad "image-id" constraint: already set`,
+	}, {
+		summary: "double set image-id separately",
+		args:    []string{"image-id=ami-1", "image-id="},
+		err:     `bad "image-id" constraint: already set`,
+	},
+
+	// "gpu" in detail.
+	{
+		summary: "set gpu empty",
+		args:    []string{"gpu="},
+	}, {
+		summary: "set gpu",
+		args:    []string{"gpu=2"},
+	}, {
+		summary: "set nonsense gpu",
+		args:    []string{"gpu=all"},
+		err:     `bad "gpu" constraint: must be a non-negative integer`,
+	}, {
+		summary: "double set gpu separately",
+		args:    []string{"gpu=1", "gpu="},
+		err:     `bad "gpu" constraint: already set`,
+	},
+
+	// "usb-device" in detail.
+	{
+		summary: "set usb-device empty",
+		args:    []string{"usb-device="},
+	}, {
+		summary: "set usb-device",
+		args:    []string{"usb-device=046d:c52b"},
+	}, {
+		summary: "set multiple usb-devices",
+		args:    []string{"usb-device=046d:c52b,10C4:EA60"},
+	}, {
+		summary: "set bad usb-device",
+		args:    []string{"usb-device=logitech"},
+		err:     `bad "usb-device" constraint: "logitech" is not a valid USB device, expected <vendor id>:<product id>`,
+	}, {
+		summary: "double set usb-device together",
+		args:    []string{"usb-device=046d:c52b usb-device=046d:c52b"},
+		err:     `bad "usb-device" constraint: already set`,
+	},
+
+	// "root-disk-source" in detail.
+	{
+		summary: "set root-disk-source empty",
+		args:    []string{"root-disk-source="},
+	}, {
+		summary: "set root-disk-source",
+		args:    []string{"root-disk-source=datastore1"},
+	}, {
+		summary: "double set root-disk-source separately",
+		args:    []string{"root-disk-source=datastore1", "root-disk-source="},
+		err:     `bad "root-disk-source" constraint: already set`,
+	},
+
+	// "resource-pool" in detail.
+	{
+		summary: "set resource-pool empty",
+		args:    []string{"resource-pool="},
+	}, {
+		summary: "set resource-pool",
+		args:    []string{"resource-pool=juju/fast"},
+	}, {
+		summary: "double set resource-pool together",
+		args:    []string{"resource-pool=juju resource-pool=juju"},
+		err:     `bad "resource-pool" constraint: already set`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -510,6 +591,18 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Spaces3", constraints.Value{Spaces: &[]string{"space1", "^space2"}}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
+	{"ImageId1", constraints.Value{ImageId: strp("")}},
+	{"ImageId2", constraints.Value{ImageId: strp("ami-0123456789abcdef0")}},
+	{"Gpus1", constraints.Value{Gpus: nil}},
+	{"Gpus2", constraints.Value{Gpus: uint64p(0)}},
+	{"Gpus3", constraints.Value{Gpus: uint64p(2)}},
+	{"UsbDevices1", constraints.Value{UsbDevices: nil}},
+	{"UsbDevices2", constraints.Value{UsbDevices: &[]string{}}},
+	{"UsbDevices3", constraints.Value{UsbDevices: &[]string{"046d:c52b", "10c4:ea60"}}},
+	{"RootDiskSource1", constraints.Value{RootDiskSource: strp("")}},
+	{"RootDiskSource2", constraints.Value{RootDiskSource: strp("datastore1")}},
+	{"ResourcePool1", constraints.Value{ResourcePool: strp("")}},
+	{"ResourcePool2", constraints.Value{ResourcePool: strp("juju/fast")}},
 	{"All", constraints.Value{
 		Arch:         strp("i386"),
 		Container:    ctypep("lxd"),
@@ -523,6 +616,16 @@ var constraintsRoundtripTests = []roundTrip{
 	}},
 }
 
+func (s *ConstraintsSuite) TestParseUsbDevice(c *gc.C) {
+	vendor, product, err := constraints.ParseUsbDevice("10C4:EA60")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vendor, gc.Equals, "10c4")
+	c.Assert(product, gc.Equals, "ea60")
+
+	_, _, err = constraints.ParseUsbDevice("10c4")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
 func (s *ConstraintsSuite) TestRoundtripGnuflagValue(c *gc.C) {
 	for _, t := range constraintsRoundtripTests {
 		c.Logf("test %s", t.Name)
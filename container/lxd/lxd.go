@@ -98,7 +98,13 @@ func (manager *containerManager) CreateContainer(
 
 	defer func() {
 		if err != nil {
-			callback(status.ProvisioningError, fmt.Sprintf("Creating container: %v", err), nil)
+			// If the container failed to start, report the tails
+			// of its start logs along with the error.
+			var data map[string]interface{}
+			if startErr, ok := errors.Cause(err).(*lxdclient.StartError); ok {
+				data = startErr.StatusData()
+			}
+			callback(status.ProvisioningError, fmt.Sprintf("Creating container: %v", err), data)
 		}
 	}()
 
@@ -6,12 +6,15 @@ package controller
 import (
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
 	utilscert "github.com/juju/utils/cert"
+	"github.com/juju/utils/set"
 	"gopkg.in/macaroon-bakery.v1/bakery"
 
 	"github.com/juju/juju/cert"
@@ -28,6 +31,15 @@ const (
 	// APIPort is the port used for api connections.
 	APIPort = "api-port"
 
+	// AgentAPIPort sets the port on which agents connect to the API
+	// server. When it is set, only agents may log in on this port,
+	// and only users and controller machine agents may log in on
+	// the API port, so that network policy can keep workload
+	// machines from reaching the port used by operators. Providers
+	// open the port in the controller's firewall alongside the API
+	// port.
+	AgentAPIPort = "agent-api-port"
+
 	// AuditingEnabled determines whether the controller will record
 	// auditing information.
 	AuditingEnabled = "auditing-enabled"
@@ -63,11 +75,32 @@ const (
 	// "https://acme-staging.api.letsencrypt.org/directory".
 	AutocertURLKey = "autocert-url"
 
+	// APISRVNameKey sets the DNS name under which the controller
+	// publishes SRV records (_juju-api._tcp.<name>) for its API
+	// endpoints. When it is set, each controller machine writes a
+	// zone file fragment holding the records, and newly provisioned
+	// agents discover the API servers by looking them up.
+	APISRVNameKey = "api-srv-name"
+
 	// AllowModelAccessKey sets whether the controller will allow users to
 	// connect to models they have been authorized for even when
 	// they don't have any access rights to the controller itself.
 	AllowModelAccessKey = "allow-model-access"
 
+	// CORSAllowedOrigins sets the comma-separated list of
+	// browser origins, such as "https://dashboard.example.com", that
+	// may make cross-origin requests to the controller's API and log
+	// endpoints. An origin of "*" allows any origin to make requests
+	// without credentials.
+	CORSAllowedOrigins = "api-cors-allowed-origins"
+
+	// Features sets the comma-separated list of experimental features,
+	// such as upgrade steps that are not yet run by default, that are
+	// enabled on the controller. It may be changed after bootstrap;
+	// upgrade steps gated on newly enabled features are run by the
+	// next upgrade.
+	Features = "features"
+
 	// MongoMemoryProfile sets whether mongo uses the least possible memory or the
 	// detault
 	MongoMemoryProfile = "mongo-memory-profile"
@@ -115,12 +148,16 @@ const (
 // ControllerOnlyConfigAttributes are attributes which are only relevant
 // for a controller, never a model.
 var ControllerOnlyConfigAttributes = []string{
+	AgentAPIPort,
 	AllowModelAccessKey,
 	APIPort,
+	APISRVNameKey,
 	AutocertDNSNameKey,
 	AutocertURLKey,
 	CACertKey,
 	ControllerUUIDKey,
+	CORSAllowedOrigins,
+	Features,
 	IdentityPublicKey,
 	IdentityURL,
 	SetNUMAControlPolicyKey,
@@ -131,6 +168,12 @@ var ControllerOnlyConfigAttributes = []string{
 	MaxTxnLogSize,
 }
 
+// AllowedUpdateConfigAttributes contains the attributes that may be
+// changed after the controller has been bootstrapped.
+var AllowedUpdateConfigAttributes = set.NewStrings(
+	Features,
+)
+
 // ControllerOnlyAttribute returns true if the specified attribute name
 // is only relevant for a controller.
 func ControllerOnlyAttribute(attr string) bool {
@@ -210,6 +253,28 @@ func (c Config) APIPort() int {
 	return c.mustInt(APIPort)
 }
 
+// AgentAPIPort returns the port on which agents connect to the API
+// server, or 0 if agents connect on the API port. See AgentAPIPort
+// for more details.
+func (c Config) AgentAPIPort() int {
+	// Values obtained over the api are encoded as float64.
+	if value, ok := c[AgentAPIPort].(float64); ok {
+		return int(value)
+	}
+	value, _ := c[AgentAPIPort].(int)
+	return value
+}
+
+// APIPorts returns the ports on which the API server listens: the API
+// port and, if it is set, the agent API port.
+func (c Config) APIPorts() []int {
+	ports := []int{c.APIPort()}
+	if port := c.AgentAPIPort(); port != 0 {
+		ports = append(ports, port)
+	}
+	return ports
+}
+
 // AuditingEnabled returns whether or not auditing has been enabled
 // for the environment. The default is false.
 func (c Config) AuditingEnabled() bool {
@@ -251,6 +316,13 @@ func (c Config) AutocertDNSName() string {
 	return c.asString(AutocertDNSNameKey)
 }
 
+// APISRVName returns the DNS name under which the controller's API
+// endpoints are published as SRV records, or "" if they are not.
+// See APISRVNameKey for more details.
+func (c Config) APISRVName() string {
+	return c.asString(APISRVNameKey)
+}
+
 // IdentityPublicKey returns the public key of the identity manager.
 func (c Config) IdentityPublicKey() *bakery.PublicKey {
 	key := c.asString(IdentityPublicKey)
@@ -291,6 +363,54 @@ func (c Config) AllowModelAccess() bool {
 	return value
 }
 
+// CORSAllowedOrigins returns the browser origins that may make
+// cross-origin requests to the API server. See CORSAllowedOrigins
+// for more details.
+func (c Config) CORSAllowedOrigins() []string {
+	return splitCommaList(c.asString(CORSAllowedOrigins))
+}
+
+// Features returns the experimental features enabled on the
+// controller. See Features for more details.
+func (c Config) Features() set.Strings {
+	return set.NewStrings(splitCommaList(c.asString(Features))...)
+}
+
+// splitCommaList returns the non-empty elements of a comma-separated
+// list, with surrounding space removed.
+func splitCommaList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validDNSName matches a fully qualified DNS name without the
+// trailing dot, such as "juju.example.com".
+var validDNSName = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,63}$`)
+
+// NormalizeCORSOrigin returns the given CORS origin in the form sent by
+// browsers in the Origin header, or an error satisfying
+// errors.IsNotValid if it is not of the form scheme://host[:port],
+// with an http or https scheme. The wildcard origin "*" is not
+// accepted; callers must handle it themselves.
+func NormalizeCORSOrigin(origin string) (string, error) {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return "", errors.NewNotValid(err, "")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.NewNotValid(nil, fmt.Sprintf("expected http or https scheme, got %q", u.Scheme))
+	}
+	if u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", errors.NewNotValid(nil, "expected scheme://host[:port]")
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
+
 // MaxLogsAge is the maximum age of log entries before they are pruned.
 func (c Config) MaxLogsAge() time.Duration {
 	// Value has already been validated.
@@ -354,6 +474,33 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[CORSAllowedOrigins].(string); ok {
+		for _, origin := range splitCommaList(v) {
+			if origin == "*" {
+				continue
+			}
+			if _, err := NormalizeCORSOrigin(origin); err != nil {
+				return errors.Annotatef(err, "invalid %s origin %q", CORSAllowedOrigins, origin)
+			}
+		}
+	}
+
+	if _, ok := c[AgentAPIPort]; ok {
+		port := c.AgentAPIPort()
+		if port <= 0 || port > 65535 {
+			return errors.Errorf("%s: expected port number, got %d", AgentAPIPort, port)
+		}
+		for _, other := range []string{APIPort, StatePort} {
+			if v, ok := c[other].(int); ok && v == port {
+				return errors.Errorf("%s: port %d already used by %s", AgentAPIPort, port, other)
+			}
+		}
+	}
+
+	if v, ok := c[APISRVNameKey].(string); ok && !validDNSName.MatchString(v) {
+		return errors.Errorf("%s: expected DNS name, got %q", APISRVNameKey, v)
+	}
+
 	if v, ok := c[MaxLogsAge].(string); ok {
 		if _, err := time.ParseDuration(v); err != nil {
 			return errors.Annotate(err, "invalid logs prune interval in configuration")
@@ -384,19 +531,24 @@ func GenerateControllerCertAndKey(caCert, caKey string, hostAddresses []string)
 var configChecker = schema.FieldMap(schema.Fields{
 	AuditingEnabled:         schema.Bool(),
 	APIPort:                 schema.ForceInt(),
+	AgentAPIPort:            schema.ForceInt(),
 	StatePort:               schema.ForceInt(),
 	IdentityURL:             schema.String(),
 	IdentityPublicKey:       schema.String(),
 	SetNUMAControlPolicyKey: schema.Bool(),
 	AutocertURLKey:          schema.String(),
 	AutocertDNSNameKey:      schema.String(),
+	APISRVNameKey:           schema.String(),
 	AllowModelAccessKey:     schema.Bool(),
+	CORSAllowedOrigins:      schema.String(),
+	Features:                schema.String(),
 	MongoMemoryProfile:      schema.String(),
 	MaxLogsAge:              schema.String(),
 	MaxLogsSize:             schema.String(),
 	MaxTxnLogSize:           schema.String(),
 }, schema.Defaults{
 	APIPort:                 DefaultAPIPort,
+	AgentAPIPort:            schema.Omit,
 	AuditingEnabled:         DefaultAuditingEnabled,
 	StatePort:               DefaultStatePort,
 	IdentityURL:             schema.Omit,
@@ -404,7 +556,10 @@ var configChecker = schema.FieldMap(schema.Fields{
 	SetNUMAControlPolicyKey: DefaultNUMAControlPolicy,
 	AutocertURLKey:          schema.Omit,
 	AutocertDNSNameKey:      schema.Omit,
+	APISRVNameKey:           schema.Omit,
 	AllowModelAccessKey:     schema.Omit,
+	CORSAllowedOrigins:      schema.Omit,
+	Features:                schema.Omit,
 	MongoMemoryProfile:      schema.Omit,
 	MaxLogsAge:              fmt.Sprintf("%vh", DefaultMaxLogsAgeDays*24),
 	MaxLogsSize:             fmt.Sprintf("%vM", DefaultMaxLogCollectionMB),
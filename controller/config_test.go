@@ -7,6 +7,7 @@ import (
 	stdtesting "testing"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -121,6 +122,61 @@ var validateTests = []struct {
 		controller.CACertKey:         testing.CACert,
 	},
 	expectError: `invalid identity public key: wrong length for base64 key, got 3 want 32`,
+}, {
+	about: "CORS allowed origins OK",
+	config: controller.Config{
+		controller.CORSAllowedOrigins: "https://dashboard.example.com, http://10.0.0.1:8080,*",
+		controller.CACertKey:          testing.CACert,
+	},
+}, {
+	about: "CORS allowed origin without scheme",
+	config: controller.Config{
+		controller.CORSAllowedOrigins: "https://dashboard.example.com,dashboard.example.com",
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `invalid api-cors-allowed-origins origin "dashboard.example.com": expected http or https scheme, got ""`,
+}, {
+	about: "CORS allowed origin with path",
+	config: controller.Config{
+		controller.CORSAllowedOrigins: "https://dashboard.example.com/juju",
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `invalid api-cors-allowed-origins origin "https://dashboard.example.com/juju": expected scheme://host\[:port\]`,
+}, {
+	about: "API SRV name OK",
+	config: controller.Config{
+		controller.APISRVNameKey: "juju.example.com",
+		controller.CACertKey:     testing.CACert,
+	},
+}, {
+	about: "invalid API SRV name",
+	config: controller.Config{
+		controller.APISRVNameKey: "_juju-api._tcp.example.com.",
+		controller.CACertKey:     testing.CACert,
+	},
+	expectError: `api-srv-name: expected DNS name, got "_juju-api._tcp.example.com."`,
+}, {
+	about: "agent API port OK",
+	config: controller.Config{
+		controller.AgentAPIPort: 17071,
+		controller.APIPort:      17070,
+		controller.CACertKey:    testing.CACert,
+	},
+}, {
+	about: "agent API port out of range",
+	config: controller.Config{
+		controller.AgentAPIPort: 70000,
+		controller.CACertKey:    testing.CACert,
+	},
+	expectError: `agent-api-port: expected port number, got 70000`,
+}, {
+	about: "agent API port same as API port",
+	config: controller.Config{
+		controller.AgentAPIPort: 17070,
+		controller.APIPort:      17070,
+		controller.CACertKey:    testing.CACert,
+	},
+	expectError: `agent-api-port: port 17070 already used by api-port`,
 }}
 
 func (s *ConfigSuite) TestValidate(c *gc.C) {
@@ -173,3 +229,77 @@ func (s *ConfigSuite) TestTxnLogConfigValue(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg.MaxTxnLogSizeMB(), gc.Equals, 8192)
 }
+
+func (s *ConfigSuite) TestCORSAllowedOrigins(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.CORSAllowedOrigins(), gc.HasLen, 0)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"api-cors-allowed-origins": " https://dashboard.example.com,,http://10.0.0.1:8080 ",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.CORSAllowedOrigins(), jc.DeepEquals, []string{
+		"https://dashboard.example.com",
+		"http://10.0.0.1:8080",
+	})
+}
+
+func (s *ConfigSuite) TestNormalizeCORSOrigin(c *gc.C) {
+	origin, err := controller.NormalizeCORSOrigin("HTTPS://Dashboard.Example.com:8443/")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(origin, gc.Equals, "https://dashboard.example.com:8443")
+
+	_, err = controller.NormalizeCORSOrigin("*")
+	c.Assert(err, gc.ErrorMatches, `expected http or https scheme, got ""`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ConfigSuite) TestAgentAPIPort(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AgentAPIPort(), gc.Equals, 0)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"agent-api-port": "17071",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AgentAPIPort(), gc.Equals, 17071)
+
+	// Values obtained over the API are float64.
+	cfg[controller.AgentAPIPort] = float64(17072)
+	c.Assert(cfg.AgentAPIPort(), gc.Equals, 17072)
+}
+
+func (s *ConfigSuite) TestAPIPorts(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.APIPorts(), jc.DeepEquals, []int{controller.DefaultAPIPort})
+
+	cfg[controller.AgentAPIPort] = 17071
+	c.Assert(cfg.APIPorts(), jc.DeepEquals, []int{controller.DefaultAPIPort, 17071})
+}
+
+func (s *ConfigSuite) TestFeatures(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Features().Values(), gc.HasLen, 0)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"features": "new-schema, ,other-thing",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Features().SortedValues(), jc.DeepEquals, []string{"new-schema", "other-thing"})
+}
@@ -57,7 +57,7 @@ than *use*, the core logic, we're basically completely screwed.
 
 The one place we address these concerns is in the core/lease.Token interface,
 which includes functionality for communicating with the implementation of
-lease.Client currently in play; where the state code which is responsible for
+lease.Store currently in play; where the state code which is responsible for
 creating a mongo-based client is not entirely unjustified in making use of the
 trapdoor to extract mgo.txn operations from lease.Token~s passed back in.
 
@@ -89,6 +89,12 @@ type Tracker interface {
 	// is guaranteed valid.
 	ClaimDuration() time.Duration
 
+	// LeaseExpiry returns the time at which the tracker's current leadership
+	// lease expires, or the zero time if the tracker does not hold the lease.
+	// Clients caching a successful claim should not rely on it once the
+	// expiry is less than ClaimDuration away.
+	LeaseExpiry() time.Time
+
 	// ClaimLeader will return a Ticket which, when Wait()ed for, will return
 	// true if leadership is guaranteed for at least the tracker's duration from
 	// the time the ticket was issued. Leadership claims should be resolved
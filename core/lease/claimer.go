@@ -47,9 +47,9 @@ type Token interface {
 	// the token continues to represent a true fact.
 	//
 	// If the token represents a true fact and trapdoorKey is *not* nil, it will
-	// be passed through layers for the attention of the underlying lease.Client
+	// be passed through layers for the attention of the underlying lease.Store
 	// implementation. If you need to do this, consult the documentation for the
-	// particular Client you're using to determine what key should be passed and
+	// particular Store you're using to determine what key should be passed and
 	// what errors that might induce.
 	Check(trapdoorKey interface{}) error
 }
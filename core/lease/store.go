@@ -10,10 +10,10 @@ import (
 	"github.com/juju/errors"
 )
 
-// Client manipulates leases directly, and is most likely to be seen set on a
+// Store manipulates leases directly, and is most likely to be seen set on a
 // worker/lease.ManagerConfig struct (and used by the Manager). Implementations
-// of Client are not expected to be goroutine-safe.
-type Client interface {
+// of Store are not expected to be goroutine-safe.
+type Store interface {
 
 	// ClaimLease records the supplied holder's claim to the supplied lease. If
 	// it succeeds, the claim is guaranteed until at least the supplied duration
@@ -36,7 +36,7 @@ type Client interface {
 	// expressed according to the Clock the client was configured with.
 	Leases() map[string]Info
 
-	// Refresh reads all lease state from the database.
+	// Refresh reads all lease state from the store's substrate.
 	Refresh() error
 }
 
@@ -51,7 +51,7 @@ type Info struct {
 	// be valid. Attempting to expire the lease before this time will fail.
 	Expiry time.Time
 
-	// Trapdoor exposes the originating Client's persistence substrate, if the
+	// Trapdoor exposes the originating Store's persistence substrate, if the
 	// substrate exposes any such capability. It's useful specifically for
 	// integrating mgo/txn-based components: which thus get a mechanism for
 	// extracting assertion operations they can use to gate other substrate
@@ -60,7 +60,7 @@ type Info struct {
 }
 
 // Trapdoor allows a client to use pre-agreed special knowledge to communicate
-// with a Client substrate by passing a key with suitable properties.
+// with a Store substrate by passing a key with suitable properties.
 type Trapdoor func(key interface{}) error
 
 // LockedTrapdoor is a Trapdoor suitable for use by substrates that don't want
@@ -94,7 +94,7 @@ func (request Request) Validate() error {
 }
 
 // ValidateString returns an error if the string is empty, or if it contains
-// whitespace, or if it contains any character in `.#$`. Client implementations
+// whitespace, or if it contains any character in `.#$`. Store implementations
 // are expected to always reject invalid strings, and never to produce them.
 func ValidateString(s string) error {
 	if s == "" {
@@ -106,9 +106,9 @@ func ValidateString(s string) error {
 	return nil
 }
 
-// ErrInvalid indicates that a Client operation failed because latest state
+// ErrInvalid indicates that a Store operation failed because latest state
 // indicates that it's a logical impossibility. It's a short-range signal to
 // calling code only; that code should never pass it on, but should inspect
-// the Client's updated Leases() and either attempt a new operation or return
+// the Store's updated Leases() and either attempt a new operation or return
 // a new error at a suitable level of abstraction.
 var ErrInvalid = errors.New("invalid lease operation")
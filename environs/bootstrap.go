@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/tools"
 )
 
@@ -60,6 +61,12 @@ type BootstrapParams struct {
 	// that rely on it for selecting images. This will be empty for
 	// providers that do not implements simplestreams.HasRegion.
 	ImageMetadata []*imagemetadata.ImageMetadata
+
+	// AdoptInstance, if non-empty, is the id of an existing instance,
+	// left behind by an earlier bootstrap, to configure as the
+	// controller instead of starting a new one. The instance's agent
+	// credentials are replaced.
+	AdoptInstance instance.Id
 }
 
 // BootstrapFinalizer is a function returned from Environ.Bootstrap.
@@ -38,6 +38,7 @@ import (
 	"github.com/juju/juju/environs/storage"
 	"github.com/juju/juju/environs/sync"
 	"github.com/juju/juju/environs/tools"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
 	coretools "github.com/juju/juju/tools"
 	jujuversion "github.com/juju/juju/version"
@@ -109,6 +110,12 @@ type BootstrapParams struct {
 	// directive used to choose the initial instance.
 	Placement string
 
+	// AdoptInstance, if non-empty, is the id of an orphaned controller
+	// instance to configure as the controller instead of starting a
+	// new instance. The controller UUID and controller model UUID must
+	// be those the instance was started with.
+	AdoptInstance instance.Id
+
 	// BuildAgent reports whether we should build and upload the local agent
 	// binary and override the environment's specified agent-version.
 	// It is an error to specify BuildAgent with a nil BuildAgentTarball.
@@ -354,7 +361,11 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 		return err
 	}
 
-	ctx.Verbosef("Starting new instance for initial controller")
+	if args.AdoptInstance != "" {
+		ctx.Verbosef("Adopting instance %s for initial controller", args.AdoptInstance)
+	} else {
+		ctx.Verbosef("Starting new instance for initial controller")
+	}
 
 	result, err := environ.Bootstrap(ctx, environs.BootstrapParams{
 		CloudName:            args.Cloud.Name,
@@ -366,6 +377,7 @@ func Bootstrap(ctx environs.BootstrapContext, environ environs.Environ, args Boo
 		Placement:            args.Placement,
 		AvailableTools:       availableTools,
 		ImageMetadata:        imageMetadata,
+		AdoptInstance:        args.AdoptInstance,
 	})
 	if err != nil {
 		return err
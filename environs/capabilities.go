@@ -0,0 +1,93 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+// AllFirewallModes holds every firewall mode known to Juju. It may be
+// used by providers that support all of them.
+var AllFirewallModes = []string{config.FwInstance, config.FwGlobal, config.FwNone}
+
+// Capabilities describes the optional features supported by an Environ.
+// Consumers should consult Capabilities rather than checking whether
+// an Environ implements optional interfaces.
+//
+// Network spaces support is not recorded here; it is reported by
+// NetworkingEnviron.SupportsSpaces and checked with SupportsSpaces.
+type Capabilities struct {
+	// StorageKinds holds the kinds of storage that may be provisioned
+	// by the Environ's storage providers.
+	StorageKinds []storage.StorageKind
+
+	// InstanceResize reports whether the Environ can change the
+	// hardware characteristics of a running instance.
+	InstanceResize bool
+
+	// AvailabilityZones reports whether the Environ distributes
+	// instances across availability zones.
+	AvailabilityZones bool
+
+	// FirewallModes holds the firewall modes supported by the Environ.
+	FirewallModes []string
+}
+
+// SupportsStorageKind reports whether storage of the given kind
+// can be provisioned.
+func (c Capabilities) SupportsStorageKind(kind storage.StorageKind) bool {
+	for _, k := range c.StorageKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsFirewallMode reports whether the given firewall mode is
+// supported.
+func (c Capabilities) SupportsFirewallMode(mode string) bool {
+	for _, m := range c.FirewallModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// StorageKinds returns the kinds of storage supported by the storage
+// providers in the given registry. Providers that support block storage
+// can also be used for filesystem storage, as Juju will manage the
+// filesystem itself.
+func StorageKinds(registry storage.ProviderRegistry) ([]storage.StorageKind, error) {
+	types, err := registry.StorageProviderTypes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var block, filesystem bool
+	for _, t := range types {
+		p, err := registry.StorageProvider(t)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if p.Supports(storage.StorageKindBlock) {
+			block = true
+			filesystem = true
+		}
+		if p.Supports(storage.StorageKindFilesystem) {
+			filesystem = true
+		}
+	}
+	var kinds []storage.StorageKind
+	if block {
+		kinds = append(kinds, storage.StorageKindBlock)
+	}
+	if filesystem {
+		kinds = append(kinds, storage.StorageKindFilesystem)
+	}
+	return kinds, nil
+}
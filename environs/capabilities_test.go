@@ -0,0 +1,71 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+	dummystorage "github.com/juju/juju/storage/provider/dummy"
+)
+
+type capabilitiesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&capabilitiesSuite{})
+
+func (s *capabilitiesSuite) TestSupportsFirewallMode(c *gc.C) {
+	caps := environs.Capabilities{
+		FirewallModes: []string{config.FwInstance, config.FwNone},
+	}
+	c.Assert(caps.SupportsFirewallMode(config.FwInstance), jc.IsTrue)
+	c.Assert(caps.SupportsFirewallMode(config.FwGlobal), jc.IsFalse)
+}
+
+func (s *capabilitiesSuite) TestStorageKinds(c *gc.C) {
+	onlyKind := func(kind storage.StorageKind) func(storage.StorageKind) bool {
+		return func(k storage.StorageKind) bool {
+			return k == kind
+		}
+	}
+	for i, t := range []struct {
+		supports func(storage.StorageKind) bool
+		expected []storage.StorageKind
+	}{{
+		supports: nil,
+		expected: []storage.StorageKind{storage.StorageKindBlock, storage.StorageKindFilesystem},
+	}, {
+		supports: onlyKind(storage.StorageKindBlock),
+		expected: []storage.StorageKind{storage.StorageKindBlock, storage.StorageKindFilesystem},
+	}, {
+		supports: onlyKind(storage.StorageKindFilesystem),
+		expected: []storage.StorageKind{storage.StorageKindFilesystem},
+	}} {
+		c.Logf("test %d", i)
+		registry := storage.StaticProviderRegistry{
+			Providers: map[storage.ProviderType]storage.Provider{
+				"dummy": &dummystorage.StorageProvider{SupportsFunc: t.supports},
+			},
+		}
+		kinds, err := environs.StorageKinds(registry)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(kinds, jc.DeepEquals, t.expected)
+
+		caps := environs.Capabilities{StorageKinds: kinds}
+		for _, kind := range t.expected {
+			c.Assert(caps.SupportsStorageKind(kind), jc.IsTrue)
+		}
+	}
+}
+
+func (s *capabilitiesSuite) TestStorageKindsEmptyRegistry(c *gc.C) {
+	kinds, err := environs.StorageKinds(storage.StaticProviderRegistry{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(kinds, gc.HasLen, 0)
+}
@@ -165,6 +165,46 @@ const (
 	// originates if the model is deployed such that NAT or similar is in use.
 	EgressCidrs = "egress-cidrs"
 
+	// MaintenanceWindowsKey holds the times, in UTC, during which
+	// automatic operations that may disrupt workloads are preferably
+	// performed.
+	MaintenanceWindowsKey = "maintenance-windows"
+
+	// RelationJoinedBatchSize is the maximum number of units joining a
+	// relation that a unit's relation watcher reports at once; the rest
+	// are reported in later batches, so that scaling out an application
+	// does not run a flood of relation-joined hooks on its counterpart.
+	// Zero means no limit.
+	RelationJoinedBatchSize = "relation-joined-batch-size"
+
+	// RelationJoinedBatchInterval is the minimum time between batches
+	// of joining units reported by a relation watcher, eg "10s".
+	RelationJoinedBatchInterval = "relation-joined-batch-interval"
+
+	// RelationJoinedBatchOverrides overrides the batch size and
+	// interval for particular relations, eg
+	// "wordpress:db,mysql:server=5/30s mysql:cluster=0".
+	RelationJoinedBatchOverrides = "relation-joined-batch-overrides"
+
+	// HookTimeout is the maximum time that a hook may run before it is
+	// killed, eg "30m". Empty or zero means that hooks may run for as
+	// long as they like.
+	HookTimeout = "hook-timeout"
+
+	// HookTimeoutOverrides overrides the hook timeout for particular
+	// kinds of hook, eg "install=1h update-status=1m".
+	HookTimeoutOverrides = "hook-timeout-overrides"
+
+	// UpgradeCanariesKey holds the comma-separated ids of the machines
+	// whose agents are upgraded first; other machine agents are held at
+	// their current version until the canaries have run the new version
+	// for the soak period without reporting errors.
+	UpgradeCanariesKey = "upgrade-canaries"
+
+	// UpgradeCanarySoakKey is how long the canary machines must run a
+	// new agent version before the other machines upgrade, eg "1h".
+	UpgradeCanarySoakKey = "upgrade-canary-soak"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -327,6 +367,14 @@ const (
 	DefaultActionResultsAge = "336h" // 2 weeks
 
 	DefaultActionResultsSize = "5G"
+
+	// DefaultRelationJoinedBatchInterval is the default value for
+	// RelationJoinedBatchInterval.
+	DefaultRelationJoinedBatchInterval = "10s"
+
+	// DefaultUpgradeCanarySoak is the default value for
+	// UpgradeCanarySoakKey.
+	DefaultUpgradeCanarySoak = "1h"
 )
 
 var defaultConfigValues = map[string]interface{}{
@@ -549,6 +597,36 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[RelationJoinedBatchSize].(int); ok && v < 0 {
+		return errors.Errorf("relation joined batch size %d cannot be negative", v)
+	}
+
+	if v, ok := cfg.defined[RelationJoinedBatchInterval].(string); ok && v != "" {
+		if d, err := time.ParseDuration(v); err != nil {
+			return errors.Annotate(err, "invalid relation joined batch interval in model configuration")
+		} else if d <= 0 {
+			return errors.Errorf("relation joined batch interval %v must be positive", d)
+		}
+	}
+
+	if v, ok := cfg.defined[RelationJoinedBatchOverrides].(string); ok && v != "" {
+		if _, err := ParseRelationJoinedBatchOverrides(v, time.Second); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if v, ok := cfg.defined[HookTimeout].(string); ok && v != "" {
+		if _, err := parseHookTimeout(v); err != nil {
+			return errors.Annotate(err, "invalid hook timeout in model configuration")
+		}
+	}
+
+	if v, ok := cfg.defined[HookTimeoutOverrides].(string); ok && v != "" {
+		if _, err := ParseHookTimeoutOverrides(v); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	if v, ok := cfg.defined[EgressCidrs].(string); ok && v != "" {
 		addresses := strings.Split(v, ",")
 		for _, addr := range addresses {
@@ -558,6 +636,28 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[MaintenanceWindowsKey].(string); ok && v != "" {
+		if _, err := ParseMaintenanceWindows(v); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if v, ok := cfg.defined[UpgradeCanariesKey].(string); ok && v != "" {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" && !names.IsValidMachine(id) {
+				return errors.NotValidf("upgrade canary machine id %q", id)
+			}
+		}
+	}
+
+	if v, ok := cfg.defined[UpgradeCanarySoakKey].(string); ok && v != "" {
+		if d, err := time.ParseDuration(v); err != nil {
+			return errors.Annotate(err, "invalid upgrade canary soak in model configuration")
+		} else if d < 0 {
+			return errors.Errorf("upgrade canary soak %v cannot be negative", d)
+		}
+	}
+
 	// Check the immutable config values.  These can't change
 	if old != nil {
 		for _, attr := range immutableAttributes {
@@ -1039,6 +1139,90 @@ func (c *Config) EgressCidrs() []string {
 	return result
 }
 
+// RelationJoinedBatchSize returns the maximum number of joining units
+// a relation watcher reports at once, or zero if there is no limit.
+func (c *Config) RelationJoinedBatchSize() int {
+	value, _ := c.defined[RelationJoinedBatchSize].(int)
+	return value
+}
+
+// RelationJoinedBatchInterval returns the minimum time between batches
+// of joining units reported by a relation watcher.
+func (c *Config) RelationJoinedBatchInterval() time.Duration {
+	raw := c.asString(RelationJoinedBatchInterval)
+	if raw == "" {
+		raw = DefaultRelationJoinedBatchInterval
+	}
+	// Value has already been validated.
+	val, _ := time.ParseDuration(raw)
+	return val
+}
+
+// RelationJoinedBatch returns the batching of units joining the
+// relation with the given endpoints, each of the form
+// "application:endpoint": the relation's override if it has one, or
+// else the model's batch size and interval.
+func (c *Config) RelationJoinedBatch(endpoints ...string) RelationJoinedBatch {
+	defaultBatch := RelationJoinedBatch{
+		Size:     c.RelationJoinedBatchSize(),
+		Interval: c.RelationJoinedBatchInterval(),
+	}
+	// Value has already been validated.
+	overrides, _ := ParseRelationJoinedBatchOverrides(
+		c.asString(RelationJoinedBatchOverrides), defaultBatch.Interval,
+	)
+	if batch, ok := overrides.Get(endpoints...); ok {
+		return batch
+	}
+	return defaultBatch
+}
+
+// HookTimeout returns the maximum time that a hook of the given kind,
+// such as "install", may run before it is killed: the kind's override
+// if it has one, or else the model's hook timeout. Zero means that the
+// hook may run for as long as it likes.
+func (c *Config) HookTimeout(kind string) time.Duration {
+	// Values have already been validated.
+	overrides, _ := ParseHookTimeoutOverrides(c.asString(HookTimeoutOverrides))
+	if timeout, ok := overrides[kind]; ok {
+		return timeout
+	}
+	timeout, _ := parseHookTimeout(c.asString(HookTimeout))
+	return timeout
+}
+
+// MaintenanceWindows returns the maintenance windows configured for the
+// model. If there are none, automatic operations may run at any time.
+func (c *Config) MaintenanceWindows() MaintenanceWindows {
+	// Value has already been validated.
+	windows, _ := ParseMaintenanceWindows(c.asString(MaintenanceWindowsKey))
+	return windows
+}
+
+// UpgradeCanaries returns the ids of the machines whose agents are
+// upgraded before the rest of the model's.
+func (c *Config) UpgradeCanaries() []string {
+	var ids []string
+	for _, id := range strings.Split(c.asString(UpgradeCanariesKey), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// UpgradeCanarySoak returns how long the canary machines must run a new
+// agent version before the rest of the model's agents upgrade.
+func (c *Config) UpgradeCanarySoak() time.Duration {
+	raw := c.asString(UpgradeCanarySoakKey)
+	if raw == "" {
+		raw = DefaultUpgradeCanarySoak
+	}
+	// Value has already been validated.
+	val, _ := time.ParseDuration(raw)
+	return val
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1150,6 +1334,14 @@ var alwaysOptional = schema.Defaults{
 	MaxActionResultsSize:         schema.Omit,
 	UpdateStatusHookInterval:     schema.Omit,
 	EgressCidrs:                  schema.Omit,
+	MaintenanceWindowsKey:        schema.Omit,
+	RelationJoinedBatchSize:      schema.Omit,
+	RelationJoinedBatchInterval:  schema.Omit,
+	RelationJoinedBatchOverrides: schema.Omit,
+	HookTimeout:                  schema.Omit,
+	HookTimeoutOverrides:         schema.Omit,
+	UpgradeCanariesKey:           schema.Omit,
+	UpgradeCanarySoakKey:         schema.Omit,
 }
 
 func allowEmpty(attr string) bool {
@@ -1551,4 +1743,44 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	MaintenanceWindowsKey: {
+		Description: `Comma-separated UTC times during which automatic operations may disrupt workloads, e.g. "Sat 02:00-04:00, 23:30-00:30" (default: any time)`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	RelationJoinedBatchSize: {
+		Description: "The maximum number of units joining a relation that are reported to each related unit at once (default 0, no limit)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	RelationJoinedBatchInterval: {
+		Description: "The minimum time between batches of units joining a relation, in human-readable time format (default 10s)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	RelationJoinedBatchOverrides: {
+		Description: `Space-separated batch sizes and intervals for particular relations, each of the form <endpoints>=<size>[/<interval>], where endpoints are the relation's comma-separated endpoints, eg "wordpress:db,mysql:server=5/30s"`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	HookTimeout: {
+		Description: "The maximum time that a hook may run before it is killed, in human-readable time format (default 0, no limit)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	HookTimeoutOverrides: {
+		Description: `Space-separated hook timeouts for particular kinds of hook, each of the form <hook kind>=<timeout>, eg "install=1h update-status=1m"`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	UpgradeCanariesKey: {
+		Description: "Comma-separated ids of machines whose agents are upgraded, and must run without errors for upgrade-canary-soak, before the rest of the model's",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	UpgradeCanarySoakKey: {
+		Description: "How long upgrade canary machines must run a new agent version before the rest of the model upgrades, in human-readable time format (default 1h)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 }
@@ -1131,6 +1131,171 @@ func (s *ConfigSuite) TestEgressCidrs(c *gc.C) {
 	c.Assert(cfg.EgressCidrs(), gc.DeepEquals, []string{"10.0.0.1/32", "192.168.1.1/16"})
 }
 
+func (s *ConfigSuite) TestMaintenanceWindows(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"maintenance-windows": "sat 02:00-04:00, 23:30-00:30",
+	})
+	c.Assert(cfg.MaintenanceWindows(), jc.DeepEquals, config.MaintenanceWindows{{
+		Weekday: time.Saturday,
+		Start:   2 * time.Hour,
+		End:     4 * time.Hour,
+	}, {
+		Daily: true,
+		Start: 23*time.Hour + 30*time.Minute,
+		End:   30 * time.Minute,
+	}})
+}
+
+func (s *ConfigSuite) TestMaintenanceWindowsDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.MaintenanceWindows(), gc.HasLen, 0)
+	c.Assert(cfg.MaintenanceWindows().Allows(time.Now()), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestMaintenanceWindowsInvalid(c *gc.C) {
+	_, err := config.New(config.UseDefaults, sampleConfig.Merge(testing.Attrs{
+		"maintenance-windows": "Someday 02:00-04:00",
+	}))
+	c.Assert(err, gc.ErrorMatches, `invalid maintenance window "Someday 02:00-04:00": unknown day "Someday"`)
+}
+
+func (s *ConfigSuite) TestRelationJoinedBatchDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.RelationJoinedBatchSize(), gc.Equals, 0)
+	c.Assert(cfg.RelationJoinedBatchInterval(), gc.Equals, 10*time.Second)
+}
+
+func (s *ConfigSuite) TestRelationJoinedBatchValues(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"relation-joined-batch-size":     5,
+		"relation-joined-batch-interval": "1m",
+	})
+	c.Assert(cfg.RelationJoinedBatchSize(), gc.Equals, 5)
+	c.Assert(cfg.RelationJoinedBatchInterval(), gc.Equals, time.Minute)
+}
+
+func (s *ConfigSuite) TestRelationJoinedBatchInvalid(c *gc.C) {
+	_, err := config.New(config.UseDefaults, sampleConfig.Merge(testing.Attrs{
+		"relation-joined-batch-size": -1,
+	}))
+	c.Assert(err, gc.ErrorMatches, "relation joined batch size -1 cannot be negative")
+	_, err = config.New(config.UseDefaults, sampleConfig.Merge(testing.Attrs{
+		"relation-joined-batch-interval": "0s",
+	}))
+	c.Assert(err, gc.ErrorMatches, "relation joined batch interval 0s must be positive")
+}
+
+func (s *ConfigSuite) TestUpgradeCanariesDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.UpgradeCanaries(), gc.HasLen, 0)
+	c.Assert(cfg.UpgradeCanarySoak(), gc.Equals, time.Hour)
+}
+
+func (s *ConfigSuite) TestUpgradeCanaries(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"upgrade-canaries":    "3, 0/lxd/1,,",
+		"upgrade-canary-soak": "30m",
+	})
+	c.Assert(cfg.UpgradeCanaries(), jc.DeepEquals, []string{"3", "0/lxd/1"})
+	c.Assert(cfg.UpgradeCanarySoak(), gc.Equals, 30*time.Minute)
+}
+
+func (s *ConfigSuite) TestUpgradeCanariesInvalid(c *gc.C) {
+	_, err := config.New(config.UseDefaults, sampleConfig.Merge(testing.Attrs{
+		"upgrade-canaries": "3, mysql/0",
+	}))
+	c.Assert(err, gc.ErrorMatches, `upgrade canary machine id "mysql/0" not valid`)
+	_, err = config.New(config.UseDefaults, sampleConfig.Merge(testing.Attrs{
+		"upgrade-canary-soak": "-1m",
+	}))
+	c.Assert(err, gc.ErrorMatches, "upgrade canary soak -1m0s cannot be negative")
+}
+
+func (s *ConfigSuite) TestRelationJoinedBatchOverrides(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"relation-joined-batch-size":      5,
+		"relation-joined-batch-overrides": "wordpress:db,mysql:server=2/1m mysql:cluster=0",
+	})
+	c.Assert(cfg.RelationJoinedBatch("mysql:server", "wordpress:db"), jc.DeepEquals, config.RelationJoinedBatch{
+		Size:     2,
+		Interval: time.Minute,
+	})
+	c.Assert(cfg.RelationJoinedBatch("mysql:cluster"), jc.DeepEquals, config.RelationJoinedBatch{
+		Size:     0,
+		Interval: 10 * time.Second,
+	})
+	c.Assert(cfg.RelationJoinedBatch("wordpress:db", "postgresql:db"), jc.DeepEquals, config.RelationJoinedBatch{
+		Size:     5,
+		Interval: 10 * time.Second,
+	})
+}
+
+func (s *ConfigSuite) TestRelationJoinedBatchOverridesInvalid(c *gc.C) {
+	for _, test := range []struct {
+		value string
+		err   string
+	}{{
+		value: "mysql:server",
+		err:   `invalid relation joined batch override "mysql:server": expected <endpoints>=<size>\[/<interval>\]`,
+	}, {
+		value: "mysql=2",
+		err:   `invalid relation joined batch override "mysql=2": endpoint "mysql" is not of the form <application>:<endpoint>`,
+	}, {
+		value: "a:b,c:d,e:f=2",
+		err:   `invalid relation joined batch override "a:b,c:d,e:f=2": a relation has at most two endpoints`,
+	}, {
+		value: "mysql:cluster=-1",
+		err:   `invalid relation joined batch override "mysql:cluster=-1": batch size "-1" is not a non-negative integer`,
+	}, {
+		value: "mysql:cluster=1/0s",
+		err:   `invalid relation joined batch override "mysql:cluster=1/0s": batch interval 0s must be positive`,
+	}} {
+		c.Logf("%s", test.value)
+		_, err := config.New(config.UseDefaults, sampleConfig.Merge(testing.Attrs{
+			"relation-joined-batch-overrides": test.value,
+		}))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestHookTimeoutDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.HookTimeout("install"), gc.Equals, time.Duration(0))
+}
+
+func (s *ConfigSuite) TestHookTimeout(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"hook-timeout":           "30m",
+		"hook-timeout-overrides": "install=1h update-status=0s",
+	})
+	c.Assert(cfg.HookTimeout("install"), gc.Equals, time.Hour)
+	c.Assert(cfg.HookTimeout("update-status"), gc.Equals, time.Duration(0))
+	c.Assert(cfg.HookTimeout("relation-joined"), gc.Equals, 30*time.Minute)
+}
+
+func (s *ConfigSuite) TestHookTimeoutInvalid(c *gc.C) {
+	for _, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"hook-timeout": "-1m"},
+		err:   "invalid hook timeout in model configuration: hook timeout -1m0s cannot be negative",
+	}, {
+		attrs: testing.Attrs{"hook-timeout-overrides": "install"},
+		err:   `invalid hook timeout override "install": expected <hook kind>=<timeout>`,
+	}, {
+		attrs: testing.Attrs{"hook-timeout-overrides": "Install=1m"},
+		err:   `invalid hook timeout override "Install=1m": "Install" is not a hook kind`,
+	}, {
+		attrs: testing.Attrs{"hook-timeout-overrides": "install=soon"},
+		err:   `invalid hook timeout override "install=soon": time: invalid duration .*`,
+	}} {
+		c.Logf("%v", test.attrs)
+		_, err := config.New(config.UseDefaults, sampleConfig.Merge(test.attrs))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 func (s *ConfigSuite) TestSchemaNoExtra(c *gc.C) {
 	schema, err := config.Schema(nil)
 	c.Assert(err, gc.IsNil)
@@ -0,0 +1,50 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// validHookKind matches the kinds of hooks, such as "install" and
+// "relation-joined".
+var validHookKind = regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+
+// ParseHookTimeoutOverrides parses a space-separated list of per-kind
+// hook timeouts, each of the form "<hook kind>=<timeout>", such as
+// "install=30m update-status=1m". A zero timeout means that hooks of
+// that kind may run for as long as they like.
+func ParseHookTimeoutOverrides(value string) (map[string]time.Duration, error) {
+	overrides := make(map[string]time.Duration)
+	for _, field := range strings.Fields(value) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid hook timeout override %q: expected <hook kind>=<timeout>", field)
+		}
+		if !validHookKind.MatchString(parts[0]) {
+			return nil, errors.Errorf("invalid hook timeout override %q: %q is not a hook kind", field, parts[0])
+		}
+		timeout, err := parseHookTimeout(parts[1])
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid hook timeout override %q", field)
+		}
+		overrides[parts[0]] = timeout
+	}
+	return overrides, nil
+}
+
+func parseHookTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if timeout < 0 {
+		return 0, errors.Errorf("hook timeout %v cannot be negative", timeout)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	dayLength  = 24 * time.Hour
+	weekLength = 7 * dayLength
+)
+
+// MaintenanceWindow is a recurring period, in UTC, during which noisy
+// automatic operations are preferably performed.
+type MaintenanceWindow struct {
+	// Weekday is the day on which the window starts. It is ignored
+	// if Daily is true.
+	Weekday time.Weekday
+
+	// Daily is true if the window recurs every day rather than once
+	// a week.
+	Daily bool
+
+	// Start and End are the offsets from midnight at which the
+	// window starts and ends. If End is not after Start, the window
+	// ends on the following day.
+	Start, End time.Duration
+}
+
+// length returns the duration of the window.
+func (w MaintenanceWindow) length() time.Duration {
+	if w.End > w.Start {
+		return w.End - w.Start
+	}
+	return w.End + dayLength - w.Start
+}
+
+// Contains reports whether the given time falls within the window.
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	sinceMidnight := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+	start, now, period := w.Start, sinceMidnight, dayLength
+	if !w.Daily {
+		start += time.Duration(w.Weekday) * dayLength
+		now += time.Duration(t.Weekday()) * dayLength
+		period = weekLength
+	}
+	offset := (now - start) % period
+	if offset < 0 {
+		offset += period
+	}
+	return offset < w.length()
+}
+
+// String returns the window in the form accepted by
+// ParseMaintenanceWindows.
+func (w MaintenanceWindow) String() string {
+	span := fmt.Sprintf("%s-%s", formatTimeOfDay(w.Start), formatTimeOfDay(w.End))
+	if w.Daily {
+		return span
+	}
+	return w.Weekday.String()[:3] + " " + span
+}
+
+// MaintenanceWindows holds the maintenance windows configured for a
+// model. If there are none, automatic operations are not restricted.
+type MaintenanceWindows []MaintenanceWindow
+
+// Allows reports whether noisy automatic operations may be performed
+// at the given time; that is, whether no windows are configured or
+// the time falls within one of them.
+func (ws MaintenanceWindows) Allows(t time.Time) bool {
+	if len(ws) == 0 {
+		return true
+	}
+	for _, w := range ws {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the windows in the form accepted by
+// ParseMaintenanceWindows.
+func (ws MaintenanceWindows) String() string {
+	parts := make([]string, len(ws))
+	for i, w := range ws {
+		parts[i] = w.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseMaintenanceWindows parses a comma-separated list of maintenance
+// windows, each of the form "[day ]HH:MM-HH:MM", where day is a three
+// letter day name such as "Sat". Windows without a day recur daily.
+// All times are in UTC.
+func ParseMaintenanceWindows(value string) (MaintenanceWindows, error) {
+	var windows MaintenanceWindows
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		w, err := parseMaintenanceWindow(part)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid maintenance window %q", part)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+func parseMaintenanceWindow(value string) (MaintenanceWindow, error) {
+	w := MaintenanceWindow{Daily: true}
+	fields := strings.Fields(value)
+	switch len(fields) {
+	case 1:
+	case 2:
+		weekday, err := parseWeekday(fields[0])
+		if err != nil {
+			return MaintenanceWindow{}, errors.Trace(err)
+		}
+		w.Weekday = weekday
+		w.Daily = false
+		fields = fields[1:]
+	default:
+		return MaintenanceWindow{}, errors.New(`expected "[day ]HH:MM-HH:MM"`)
+	}
+	span := strings.Split(fields[0], "-")
+	if len(span) != 2 {
+		return MaintenanceWindow{}, errors.New(`expected "[day ]HH:MM-HH:MM"`)
+	}
+	var err error
+	if w.Start, err = parseTimeOfDay(span[0]); err != nil {
+		return MaintenanceWindow{}, errors.Trace(err)
+	}
+	if w.End, err = parseTimeOfDay(span[1]); err != nil {
+		return MaintenanceWindow{}, errors.Trace(err)
+	}
+	if w.Start == w.End {
+		return MaintenanceWindow{}, errors.New("start and end times are the same")
+	}
+	return w, nil
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(value, d.String()[:3]) {
+			return d, nil
+		}
+	}
+	return 0, errors.Errorf("unknown day %q", value)
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.Errorf("time %q is not of the form HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", d/time.Hour, (d%time.Hour)/time.Minute)
+}
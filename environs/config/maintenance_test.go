@@ -0,0 +1,95 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+)
+
+type MaintenanceWindowsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&MaintenanceWindowsSuite{})
+
+func (s *MaintenanceWindowsSuite) TestParse(c *gc.C) {
+	windows, err := config.ParseMaintenanceWindows(" Sat 02:00-04:00,, 23:30-00:30 ")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(windows, jc.DeepEquals, config.MaintenanceWindows{{
+		Weekday: time.Saturday,
+		Start:   2 * time.Hour,
+		End:     4 * time.Hour,
+	}, {
+		Daily: true,
+		Start: 23*time.Hour + 30*time.Minute,
+		End:   30 * time.Minute,
+	}})
+	c.Assert(windows.String(), gc.Equals, "Sat 02:00-04:00,23:30-00:30")
+}
+
+func (s *MaintenanceWindowsSuite) TestParseEmpty(c *gc.C) {
+	windows, err := config.ParseMaintenanceWindows("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(windows, gc.HasLen, 0)
+}
+
+func (s *MaintenanceWindowsSuite) TestParseErrors(c *gc.C) {
+	for i, test := range []struct {
+		value string
+		err   string
+	}{{
+		value: "02:00",
+		err:   `invalid maintenance window "02:00": expected "\[day \]HH:MM-HH:MM"`,
+	}, {
+		value: "Sat Sun 02:00-04:00",
+		err:   `invalid maintenance window "Sat Sun 02:00-04:00": expected "\[day \]HH:MM-HH:MM"`,
+	}, {
+		value: "Caturday 02:00-04:00",
+		err:   `invalid maintenance window "Caturday 02:00-04:00": unknown day "Caturday"`,
+	}, {
+		value: "02:00-25:00",
+		err:   `invalid maintenance window "02:00-25:00": time "25:00" is not of the form HH:MM`,
+	}, {
+		value: "Mon 02:00-02:00",
+		err:   `invalid maintenance window "Mon 02:00-02:00": start and end times are the same`,
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		_, err := config.ParseMaintenanceWindows(test.value)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *MaintenanceWindowsSuite) TestAllows(c *gc.C) {
+	windows, err := config.ParseMaintenanceWindows("Sun 23:00-01:00, 12:00-12:30")
+	c.Assert(err, jc.ErrorIsNil)
+	// 2017-10-01 is a Sunday.
+	for i, test := range []struct {
+		time   time.Time
+		allows bool
+	}{
+		{time.Date(2017, 10, 1, 22, 59, 0, 0, time.UTC), false},
+		{time.Date(2017, 10, 1, 23, 0, 0, 0, time.UTC), true},
+		{time.Date(2017, 10, 2, 0, 59, 0, 0, time.UTC), true},
+		{time.Date(2017, 10, 2, 1, 0, 0, 0, time.UTC), false},
+		{time.Date(2017, 10, 2, 23, 30, 0, 0, time.UTC), false},
+		{time.Date(2017, 10, 4, 12, 15, 0, 0, time.UTC), true},
+		{time.Date(2017, 10, 4, 12, 30, 0, 0, time.UTC), false},
+		// Times are compared in UTC.
+		{time.Date(2017, 10, 4, 14, 15, 0, 0, time.FixedZone("CEST", 2*60*60)), true},
+	} {
+		c.Logf("test %d: %v", i, test.time)
+		c.Check(windows.Allows(test.time), gc.Equals, test.allows)
+	}
+}
+
+func (s *MaintenanceWindowsSuite) TestAllowsNoWindows(c *gc.C) {
+	var windows config.MaintenanceWindows
+	c.Assert(windows.Allows(time.Date(2017, 10, 4, 12, 0, 0, 0, time.UTC)), jc.IsTrue)
+}
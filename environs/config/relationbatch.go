@@ -0,0 +1,96 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// RelationJoinedBatch describes how the units joining a relation are
+// reported to the units of the related application.
+type RelationJoinedBatch struct {
+	// Size is the maximum number of joining units reported at once,
+	// or zero if there is no limit.
+	Size int
+
+	// Interval is the minimum time between batches.
+	Interval time.Duration
+}
+
+// RelationJoinedBatches holds the batching of particular
+// relations, keyed by the relations' sorted, comma-separated endpoints.
+type RelationJoinedBatches map[string]RelationJoinedBatch
+
+// Get returns the batching of the relation with the given endpoints,
+// each of the form "application:endpoint", and whether it is
+// overridden.
+func (o RelationJoinedBatches) Get(endpoints ...string) (RelationJoinedBatch, bool) {
+	batch, ok := o[relationBatchKey(endpoints)]
+	return batch, ok
+}
+
+// relationBatchKey returns the key of the relation with the given
+// endpoints, which is the same whatever their order.
+func relationBatchKey(endpoints []string) string {
+	sorted := append([]string(nil), endpoints...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// ParseRelationJoinedBatchOverrides parses a space-separated list of
+// per-relation batching overrides, each of the form
+// "<endpoints>=<size>[/<interval>]". The endpoints name a relation by
+// its comma-separated endpoints, in any order, such as
+// "wordpress:db,mysql:server", or a peer relation by its one endpoint.
+// Overrides without an interval use defaultInterval.
+func ParseRelationJoinedBatchOverrides(value string, defaultInterval time.Duration) (RelationJoinedBatches, error) {
+	overrides := make(RelationJoinedBatches)
+	for _, field := range strings.Fields(value) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid relation joined batch override %q: expected <endpoints>=<size>[/<interval>]", field)
+		}
+		endpoints := strings.Split(parts[0], ",")
+		if len(endpoints) > 2 {
+			return nil, errors.Errorf("invalid relation joined batch override %q: a relation has at most two endpoints", field)
+		}
+		for _, endpoint := range endpoints {
+			if i := strings.Index(endpoint, ":"); i <= 0 || i == len(endpoint)-1 {
+				return nil, errors.Errorf("invalid relation joined batch override %q: endpoint %q is not of the form <application>:<endpoint>", field, endpoint)
+			}
+		}
+		batch, err := parseRelationJoinedBatch(parts[1], defaultInterval)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid relation joined batch override %q", field)
+		}
+		overrides[relationBatchKey(endpoints)] = batch
+	}
+	return overrides, nil
+}
+
+func parseRelationJoinedBatch(value string, defaultInterval time.Duration) (RelationJoinedBatch, error) {
+	batch := RelationJoinedBatch{Interval: defaultInterval}
+	parts := strings.SplitN(value, "/", 2)
+	size, err := strconv.Atoi(parts[0])
+	if err != nil || size < 0 {
+		return RelationJoinedBatch{}, errors.Errorf("batch size %q is not a non-negative integer", parts[0])
+	}
+	batch.Size = size
+	if len(parts) == 2 {
+		interval, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return RelationJoinedBatch{}, errors.Annotate(err, "batch interval")
+		}
+		if interval <= 0 {
+			return RelationJoinedBatch{}, errors.Errorf("batch interval %v must be positive", interval)
+		}
+		batch.Interval = interval
+	}
+	return batch, nil
+}
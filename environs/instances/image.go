@@ -67,6 +67,14 @@ func FindInstanceSpec(possibleImages []Image, ic *InstanceConstraint, allInstanc
 			ic.Series, ic.Region, ic.Arches)
 	}
 
+	if ic.Constraints.HasImageId() {
+		possibleImages = filterImagesById(possibleImages, *ic.Constraints.ImageId)
+		if len(possibleImages) == 0 {
+			return nil, fmt.Errorf("image %q not found in %q images in %s with arches %s",
+				*ic.Constraints.ImageId, ic.Series, ic.Region, ic.Arches)
+		}
+	}
+
 	logger.Debugf("matching constraints %v against possible image metadata %+v", ic, possibleImages)
 	matchingTypes, err := MatchingInstanceTypes(allInstanceTypes, ic.Region, ic.Constraints)
 	if err != nil {
@@ -114,6 +122,17 @@ func FindInstanceSpec(possibleImages []Image, ic *InstanceConstraint, allInstanc
 	return nil, fmt.Errorf("no %q images in %s matching instance types %v", ic.Series, ic.Region, names)
 }
 
+// filterImagesById returns the images with the given id.
+func filterImagesById(images []Image, id string) []Image {
+	var result []Image
+	for _, image := range images {
+		if image.Id == id {
+			result = append(result, image)
+		}
+	}
+	return result
+}
+
 // byArch sorts InstanceSpecs first by descending word-size, then
 // alphabetically by name, and choose the first spec in the sequence.
 type byArch []*InstanceSpec
@@ -326,6 +326,18 @@ var findInstanceSpecTests = []instanceSpecTestParams{
 		},
 		err: `no instance types in test matching constraints "instance-type=it-10"`,
 	},
+	{
+		desc:        "use image id constraint",
+		region:      "test",
+		constraints: "image-id=ami-00000034",
+		imageId:     "ami-00000034",
+	},
+	{
+		desc:        "image id constraint, no matching image",
+		region:      "test",
+		constraints: "image-id=ami-golden",
+		err:         `image "ami-golden" not found in "precise" images in test with arches \[amd64 armhf\]`,
+	},
 	{
 		desc:   "no image exists in metadata",
 		region: "invalid-region",
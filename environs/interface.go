@@ -200,6 +200,36 @@ type CloudRegionDetector interface {
 	DetectRegions() ([]cloud.Region, error)
 }
 
+// OrphanedController describes a controller instance that is not
+// known to the client, such as one left behind by a failed bootstrap.
+type OrphanedController struct {
+	// InstanceId is the id of the controller's instance.
+	InstanceId instance.Id
+
+	// ControllerUUID is the UUID of the controller the instance was
+	// started for.
+	ControllerUUID string
+
+	// ModelUUID is the UUID of the controller model the instance was
+	// started in.
+	ModelUUID string
+
+	// Addresses are the instance's addresses, through which it can
+	// be checked that the controller is no longer serving its API.
+	Addresses []network.Address
+}
+
+// OrphanedControllerFinder is an interface that an EnvironProvider may
+// implement in order to find orphaned controller instances, which may
+// be adopted by a subsequent bootstrap rather than starting a new
+// instance.
+type OrphanedControllerFinder interface {
+	// OrphanedControllers returns the controller instances in the
+	// cloud that are alive and belong to none of the controllers
+	// with the given UUIDs.
+	OrphanedControllers(spec CloudSpec, knownControllerUUIDs []string) ([]OrphanedController, error)
+}
+
 // ModelConfigUpgrader is an interface that an EnvironProvider may
 // implement in order to modify environment configuration on agent upgrade.
 type ModelConfigUpgrader interface {
@@ -347,6 +377,10 @@ type Environ interface {
 	// InstanceTypesFetcher represents an environment that can return
 	// information about the available instance types.
 	InstanceTypesFetcher
+
+	// Capabilities returns the optional features supported by
+	// the environment.
+	Capabilities() (Capabilities, error)
 }
 
 // InstancePrechecker provides a means of "prechecking" instance
@@ -410,6 +444,17 @@ type Firewaller interface {
 	IngressRules() ([]network.IngressRule, error)
 }
 
+// ServiceFirewaller is an interface that may be implemented by an
+// Environ whose firewall guards the well known services used by Juju,
+// such as SSH and the controller API, so that the addresses allowed to
+// reach them can be restricted.
+type ServiceFirewaller interface {
+	// SetServiceIngressRules replaces the source CIDRs allowed to
+	// reach the port range of each of the given rules. Port ranges
+	// not mentioned in the rules are left unchanged.
+	SetServiceIngressRules(rules []network.IngressRule) error
+}
+
 // InstanceTagger is an interface that can be used for tagging instances.
 type InstanceTagger interface {
 	// TagInstance tags the given instance with the specified tags.
@@ -419,6 +464,14 @@ type InstanceTagger interface {
 	TagInstance(id instance.Id, tags map[string]string) error
 }
 
+// CloudSpecSetter is an interface that may be implemented by an Environ
+// whose cloud spec, and in particular its credential, can be replaced
+// without reopening it.
+type CloudSpecSetter interface {
+	// SetCloudSpec replaces the environ's cloud spec.
+	SetCloudSpec(spec CloudSpec) error
+}
+
 // InstanceTypesFetcher is an interface that allows for instance information from
 // a provider to be obtained.
 type InstanceTypesFetcher interface {
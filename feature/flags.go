@@ -43,3 +43,13 @@ const StrictMigration = "strict-migration"
 
 // CAAS enables creating models on CAAS infrastructure (k8s, etc)
 const CAAS = "caas"
+
+// ConsolidatedUnitAgents causes the machine agent to run the agents of
+// newly deployed units inside its own process, rather than installing
+// an init service for each of them.
+const ConsolidatedUnitAgents = "consolidated-unit-agents"
+
+// ForwardHookToolAudit causes unit agents to forward the audit records
+// of hook tool invocations to the controller, in addition to recording
+// them in the unit's local audit log.
+const ForwardHookToolAudit = "forward-hook-tool-audit"
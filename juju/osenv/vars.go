@@ -43,6 +43,12 @@ const (
 	// timestamps to be written in RFC3339 format.
 	JujuStatusIsoTimeEnvKey = "JUJU_STATUS_ISO_TIME"
 
+	// JujuServiceWorkingDirEnvKey is set in the environment of a
+	// Windows service whose conf gives a working directory, since the
+	// service control manager cannot start services in one. The jujud
+	// service handler changes to the directory before running.
+	JujuServiceWorkingDirEnvKey = "JUJU_SERVICE_WORKING_DIR"
+
 	// XDGDataHome is a path where data for the running user
 	// should be stored according to the xdg standard.
 	XDGDataHome = "XDG_DATA_HOME"
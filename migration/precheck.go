@@ -34,6 +34,9 @@ type PrecheckBackend interface {
 	ControllerBackend() (PrecheckBackendCloser, error)
 	CloudCredential(tag names.CloudCredentialTag) (cloud.Credential, error)
 	ListPendingResources(string) ([]resource.Resource, error)
+	ListResources(string) (resource.ServiceResources, error)
+	HasOperations() (bool, error)
+	HasBranches() (bool, error)
 }
 
 // PrecheckBackendCloser adds the Close method to the standard
@@ -86,6 +89,7 @@ type PrecheckUnit interface {
 	AgentStatus() (status.StatusInfo, error)
 	Status() (status.StatusInfo, error)
 	AgentPresence() (bool, error)
+	Secrets() (map[string]string, error)
 }
 
 // SourcePrecheck checks the state of the source controller to make
@@ -110,6 +114,23 @@ func SourcePrecheck(backend PrecheckBackend) error {
 		return errors.New("cleanup needed")
 	}
 
+	// Operations are not part of the model description, so they
+	// would be lost by the migration.
+	if hasOperations, err := backend.HasOperations(); err != nil {
+		return errors.Annotate(err, "checking operations")
+	} else if hasOperations {
+		return errors.New("model has operations, which cannot be migrated")
+	}
+
+	// Nor are branches; once a branch is committed or aborted its
+	// changes are in the model's config, so only those in progress
+	// would be lost.
+	if hasBranches, err := backend.HasBranches(); err != nil {
+		return errors.Annotate(err, "checking branches")
+	} else if hasBranches {
+		return errors.New("model has branches in progress, which cannot be migrated; commit or abort them first")
+	}
+
 	// Check the source controller.
 	controllerBackend, err := backend.ControllerBackend()
 	if err != nil {
@@ -308,6 +329,18 @@ func checkApplications(backend PrecheckBackend) error {
 			resName := resources[0].Name
 			return errors.Errorf("resource %q is pending for application %s", resName, app.Name())
 		}
+
+		// Resource pins are not part of the model description, so
+		// would be lost if the model was migrated.
+		appResources, err := backend.ListResources(app.Name())
+		if err != nil {
+			return errors.Annotate(err, "checking resources")
+		}
+		for _, res := range appResources.Resources {
+			if res.IsPinned() {
+				return errors.Errorf("resource %q is pinned for application %s", res.Name, app.Name())
+			}
+		}
 	}
 	return nil
 }
@@ -340,6 +373,14 @@ func checkUnits(app PrecheckApplication, modelVersion version.Number) error {
 		if appCharmURL.String() != unitCharmURL.String() {
 			return errors.Errorf("unit %s is upgrading", unit.Name())
 		}
+
+		// Unit secrets are not part of the model description, so
+		// would be lost if the model was migrated.
+		if secrets, err := unit.Secrets(); err != nil {
+			return errors.Annotatef(err, "retrieving unit %s secrets", unit.Name())
+		} else if len(secrets) > 0 {
+			return errors.Errorf("unit %s has stored secrets, which cannot be migrated", unit.Name())
+		}
 	}
 	return nil
 }
@@ -116,6 +116,15 @@ func (s *precheckShim) ListPendingResources(app string) ([]resource.Resource, er
 	return resources, nil
 }
 
+// ListResources implements PrecheckBackend.
+func (s *precheckShim) ListResources(app string) (resource.ServiceResources, error) {
+	resources, err := s.resourcesSt.ListResources(app)
+	if err != nil {
+		return resource.ServiceResources{}, errors.Trace(err)
+	}
+	return resources, nil
+}
+
 // ControllerBackend implements PrecheckBackend.
 func (s *precheckShim) ControllerBackend() (PrecheckBackendCloser, error) {
 	st, err := s.State.ForModel(s.State.ControllerModelTag())
@@ -87,6 +87,15 @@ func (*SourcePrecheckSuite) TestPendingResourcesError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `checking resources: blam`)
 }
 
+func (*SourcePrecheckSuite) TestPinnedResources(c *gc.C) {
+	backend := newHappyBackend()
+	res := resourcetesting.NewResource(c, nil, "blob", "foo", "body").Resource
+	res.PinnedFingerprint = res.Fingerprint
+	backend.resources = []resource.Resource{res}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, `resource "blob" is pinned for application foo`)
+}
+
 func (*SourcePrecheckSuite) TestImportingModel(c *gc.C) {
 	backend := newFakeBackend()
 	backend.model.migrationMode = state.MigrationModeImporting
@@ -108,6 +117,34 @@ func (*SourcePrecheckSuite) TestCleanupsNeeded(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cleanup needed")
 }
 
+func (*SourcePrecheckSuite) TestOperationsError(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasOperationsErr = errors.New("boom")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "checking operations: boom")
+}
+
+func (*SourcePrecheckSuite) TestOperations(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasOperations = true
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "model has operations, which cannot be migrated")
+}
+
+func (*SourcePrecheckSuite) TestBranchesError(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasBranchesErr = errors.New("boom")
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "checking branches: boom")
+}
+
+func (*SourcePrecheckSuite) TestBranches(c *gc.C) {
+	backend := newFakeBackend()
+	backend.hasBranches = true
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err, gc.ErrorMatches, "model has branches in progress, which cannot be migrated; commit or abort them first")
+}
+
 func (s *SourcePrecheckSuite) TestIsUpgradingError(c *gc.C) {
 	backend := newFakeBackend()
 	backend.controllerBackend.isUpgradingErr = errors.New("boom")
@@ -248,6 +285,21 @@ func (s *SourcePrecheckSuite) TestUnitNotIdle(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "unit foo/0 not idle or executing (failed)")
 }
 
+func (s *SourcePrecheckSuite) TestUnitWithSecrets(c *gc.C) {
+	backend := &fakeBackend{
+		apps: []migration.PrecheckApplication{
+			&fakeApp{
+				name: "foo",
+				units: []migration.PrecheckUnit{
+					&fakeUnit{name: "foo/0", secrets: map[string]string{"password": "hunter2"}},
+				},
+			},
+		},
+	}
+	err := migration.SourcePrecheck(backend)
+	c.Assert(err.Error(), gc.Equals, "unit foo/0 has stored secrets, which cannot be migrated")
+}
+
 func (s *SourcePrecheckSuite) TestUnitLost(c *gc.C) {
 	backend := &fakeBackend{
 		apps: []migration.PrecheckApplication{
@@ -653,8 +705,15 @@ type fakeBackend struct {
 	credentialsErr error
 
 	pendingResources    []resource.Resource
+	resources           []resource.Resource
 	pendingResourcesErr error
 
+	hasOperations    bool
+	hasOperationsErr error
+
+	hasBranches    bool
+	hasBranchesErr error
+
 	controllerBackend *fakeBackend
 }
 
@@ -703,6 +762,18 @@ func (b *fakeBackend) ListPendingResources(app string) ([]resource.Resource, err
 	return b.pendingResources, b.pendingResourcesErr
 }
 
+func (b *fakeBackend) ListResources(app string) (resource.ServiceResources, error) {
+	return resource.ServiceResources{Resources: b.resources}, nil
+}
+
+func (b *fakeBackend) HasOperations() (bool, error) {
+	return b.hasOperations, b.hasOperationsErr
+}
+
+func (b *fakeBackend) HasBranches() (bool, error) {
+	return b.hasBranches, b.hasBranchesErr
+}
+
 func (b *fakeBackend) ControllerBackend() (migration.PrecheckBackendCloser, error) {
 	if b.controllerBackend == nil {
 		return b, nil
@@ -844,6 +915,7 @@ type fakeUnit struct {
 	charmURL    string
 	agentStatus status.Status
 	lost        bool
+	secrets     map[string]string
 }
 
 func (u *fakeUnit) Name() string {
@@ -874,6 +946,10 @@ func (u *fakeUnit) CharmURL() (*charm.URL, bool) {
 	return charm.MustParseURL(url), false
 }
 
+func (u *fakeUnit) Secrets() (map[string]string, error) {
+	return u.secrets, nil
+}
+
 func (u *fakeUnit) AgentStatus() (status.StatusInfo, error) {
 	s := u.agentStatus
 	if s == "" {
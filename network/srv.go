@@ -0,0 +1,98 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+const (
+	// APISRVService and APISRVProto name the SRV records under which
+	// controller API endpoints are published: for a controller
+	// configured with the name "juju.example.com", clients look up
+	// _juju-api._tcp.juju.example.com.
+	APISRVService = "juju-api"
+	APISRVProto   = "tcp"
+
+	// apiSRVTTL is the time to live, in seconds, of published
+	// records. It is kept short so that clients notice controllers
+	// joining and leaving the HA set promptly.
+	apiSRVTTL = 60
+)
+
+// APISRVZone renders, in RFC 1035 zone file syntax, the SRV records
+// that publish the given API servers under name, along with the
+// address records their targets refer to. Each element of apiServers
+// holds the addresses of one controller, as published to agents.
+//
+// The result is intended to be included by an authoritative DNS server
+// for name. Machine-local and link-local addresses are omitted, as
+// they are useless to clients elsewhere.
+func APISRVZone(name string, apiServers [][]HostPort) string {
+	var buf bytes.Buffer
+	srvName := fmt.Sprintf("_%s._%s.%s.", APISRVService, APISRVProto, name)
+	fmt.Fprintf(&buf, "; Juju controller API endpoints, published under %s\n", srvName)
+	for i, hostPorts := range apiServers {
+		// All of a controller's IP addresses are grouped behind a
+		// single target name; host names are targets in their own
+		// right.
+		target := fmt.Sprintf("api-%d.%s.", i, name)
+		var targetAddrs []Address
+		ports := make(map[string]map[int]bool)
+		addPort := func(target string, port int) {
+			if ports[target] == nil {
+				ports[target] = make(map[int]bool)
+			}
+			ports[target][port] = true
+		}
+		for _, hp := range hostPorts {
+			if hp.Scope == ScopeMachineLocal || hp.Scope == ScopeLinkLocal {
+				continue
+			}
+			switch hp.Type {
+			case HostName:
+				addPort(hp.Value+".", hp.Port)
+			case IPv4Address, IPv6Address:
+				if !containsAddress(targetAddrs, hp.Address) {
+					targetAddrs = append(targetAddrs, hp.Address)
+				}
+				addPort(target, hp.Port)
+			}
+		}
+		for _, addr := range targetAddrs {
+			rrType := "A"
+			if addr.Type == IPv6Address {
+				rrType = "AAAA"
+			}
+			fmt.Fprintf(&buf, "%s %d IN %s %s\n", target, apiSRVTTL, rrType, addr.Value)
+		}
+		targets := make([]string, 0, len(ports))
+		for t := range ports {
+			targets = append(targets, t)
+		}
+		sort.Strings(targets)
+		for _, t := range targets {
+			portList := make([]int, 0, len(ports[t]))
+			for port := range ports[t] {
+				portList = append(portList, port)
+			}
+			sort.Ints(portList)
+			for _, port := range portList {
+				fmt.Fprintf(&buf, "%s %d IN SRV 0 0 %d %s\n", srvName, apiSRVTTL, port, t)
+			}
+		}
+	}
+	return buf.String()
+}
+
+func containsAddress(addrs []Address, addr Address) bool {
+	for _, a := range addrs {
+		if a.Value == addr.Value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SRVSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&SRVSuite{})
+
+func (s *SRVSuite) TestAPISRVZone(c *gc.C) {
+	zone := network.APISRVZone("juju.example.com", [][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1", "127.0.0.1", "2001:db8::1", "fe80::1"),
+		network.NewHostPorts(17070, "10.0.0.2", "api.example.com"),
+	})
+	c.Assert(zone, gc.Equals, `
+; Juju controller API endpoints, published under _juju-api._tcp.juju.example.com.
+api-0.juju.example.com. 60 IN A 10.0.0.1
+api-0.juju.example.com. 60 IN AAAA 2001:db8::1
+_juju-api._tcp.juju.example.com. 60 IN SRV 0 0 17070 api-0.juju.example.com.
+api-1.juju.example.com. 60 IN A 10.0.0.2
+_juju-api._tcp.juju.example.com. 60 IN SRV 0 0 17070 api-1.juju.example.com.
+_juju-api._tcp.juju.example.com. 60 IN SRV 0 0 17070 api.example.com.
+`[1:])
+}
+
+func (s *SRVSuite) TestAPISRVZoneNoServers(c *gc.C) {
+	zone := network.APISRVZone("juju.example.com", nil)
+	c.Assert(zone, gc.Equals, "; Juju controller API endpoints, published under _juju-api._tcp.juju.example.com.\n")
+}
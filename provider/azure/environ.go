@@ -280,9 +280,8 @@ func (env *azureEnviron) createCommonResourceDeployment(
 	storageAccountType string,
 	rules []network.SecurityRule,
 ) error {
-	const apiPort = -1
 	commonResources := networkTemplateResources(
-		env.location, tags, apiPort, rules,
+		env.location, tags, nil, rules,
 	)
 	commonResources = append(commonResources, storageAccountTemplateResource(
 		env.location, tags,
@@ -353,6 +352,18 @@ func (env *azureEnviron) SetConfig(cfg *config.Config) error {
 	return nil
 }
 
+// Capabilities is specified on the Environ interface.
+func (env *azureEnviron) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(env)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:  storageKinds,
+		FirewallModes: []string{config.FwInstance, config.FwNone},
+	}, nil
+}
+
 // ConstraintsValidator is defined on the Environs interface.
 func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	instanceTypes, err := env.getInstanceTypes()
@@ -370,6 +381,10 @@ func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 		constraints.CpuPower,
 		constraints.Tags,
 		constraints.VirtType,
+		constraints.Gpu,
+		constraints.UsbDevice,
+		constraints.RootDiskSource,
+		constraints.ResourcePool,
 	})
 	validator.RegisterVocabulary(
 		constraints.Arch,
@@ -561,16 +576,9 @@ func (env *azureEnviron) createVirtualMachine(
 
 	deploymentsClient := resources.DeploymentsClient{env.resources}
 
-	var apiPort int
-	if instanceConfig.Controller != nil {
-		apiPortValue := instanceConfig.Controller.Config.APIPort()
-		apiPort = apiPortValue
-	} else {
-		apiPorts := instanceConfig.APIInfo.Ports()
-		if len(apiPorts) != 1 {
-			return errors.Errorf("expected one API port, found %v", apiPorts)
-		}
-		apiPort = apiPorts[0]
+	apiPorts := instanceConfig.APIPorts()
+	if len(apiPorts) == 0 {
+		return errors.New("expected an API port, found none")
 	}
 
 	var nicDependsOn, vmDependsOn []string
@@ -579,7 +587,7 @@ func (env *azureEnviron) createVirtualMachine(
 	if createCommonResources {
 		// We're starting the bootstrap machine, so we will create the
 		// common resources in the same deployment.
-		commonResources := networkTemplateResources(env.location, envTags, apiPort, nil)
+		commonResources := networkTemplateResources(env.location, envTags, apiPorts, nil)
 		commonResources = append(commonResources, storageAccountTemplateResource(
 			env.location, envTags,
 			env.storageAccountName, storageAccountType,
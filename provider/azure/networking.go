@@ -71,7 +71,8 @@ const (
 
 	// securityRuleInternalAPIInbound is the priority of the
 	// security rule that allows inbound Juju API access to
-	// controller machines
+	// controller machines. The rules for any further API ports,
+	// such as the agent API port, take the priorities that follow.
 	securityRuleInternalAPIInbound
 )
 
@@ -110,22 +111,27 @@ var (
 // networkTemplateResources returns resource definitions for creating network
 // resources shared by all machines in a model.
 //
-// If apiPort is -1, then there should be no controller subnet created, and
-// no network security rule allowing Juju API traffic.
+// If apiPorts is empty, then there should be no controller subnet created,
+// and no network security rule allowing Juju API traffic.
 func networkTemplateResources(
 	location string,
 	envTags map[string]string,
-	apiPort int,
+	apiPorts []int,
 	extraRules []network.SecurityRule,
 ) []armtemplates.Resource {
 	// Create a network security group for the environment. There is only
 	// one NSG per environment (there's a limit of 100 per subscription),
 	// in which we manage rules for each exposed machine.
 	securityRules := []network.SecurityRule{sshSecurityRule}
-	if apiPort != -1 {
+	for i, apiPort := range apiPorts {
 		apiSecurityRule := apiSecurityRule
+		if i > 0 {
+			// Each rule needs its own name and priority.
+			apiSecurityRule.Name = to.StringPtr(fmt.Sprintf("%s%d", to.String(apiSecurityRule.Name), apiPort))
+		}
 		properties := *apiSecurityRule.Properties
 		properties.DestinationPortRange = to.StringPtr(fmt.Sprint(apiPort))
+		properties.Priority = to.Int32Ptr(securityRuleInternalAPIInbound + int32(i))
 		apiSecurityRule.Properties = &properties
 		securityRules = append(securityRules, apiSecurityRule)
 	}
@@ -144,7 +150,7 @@ func networkTemplateResources(
 			},
 		},
 	}}
-	if apiPort != -1 {
+	if len(apiPorts) > 0 {
 		subnets = append(subnets, network.Subnet{
 			Name: to.StringPtr(controllerSubnetName),
 			Properties: &network.SubnetPropertiesFormat{
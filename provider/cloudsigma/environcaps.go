@@ -4,7 +4,11 @@
 package cloudsigma
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 )
 
 var unsupportedConstraints = []string{
@@ -12,6 +16,23 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.ImageId,
+	constraints.Gpu,
+	constraints.UsbDevice,
+	constraints.RootDiskSource,
+	constraints.ResourcePool,
+}
+
+// Capabilities is specified on the Environ interface.
+func (env *environ) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(env)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:  storageKinds,
+		FirewallModes: []string{config.FwGlobal, config.FwNone},
+	}, nil
 }
 
 // ConstraintsValidator returns a Validator instance which
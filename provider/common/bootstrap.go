@@ -136,6 +136,14 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 	}
 	maybeSetBridge(instanceConfig)
 
+	cloudRegion := args.CloudName
+	if args.CloudRegion != "" {
+		cloudRegion += "/" + args.CloudRegion
+	}
+	if args.AdoptInstance != "" {
+		return adoptBootstrapInstance(ctx, env, args, client, instanceConfig, selectedSeries, cloudRegion, maybeSetBridge)
+	}
+
 	// We're creating a new instance; inject host keys so that we can then
 	// make an SSH connection with known keys.
 	initialSSHHostKeys, err := generateSSHHostKeys()
@@ -144,10 +152,6 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 	}
 	instanceConfig.Bootstrap.InitialSSHHostKeys = initialSSHHostKeys
 
-	cloudRegion := args.CloudName
-	if args.CloudRegion != "" {
-		cloudRegion += "/" + args.CloudRegion
-	}
 	fmt.Fprintf(ctx.GetStderr(), "Launching controller instance(s) on %s...\n", cloudRegion)
 	// Print instance status reports status changes during provisioning.
 	// Note the carriage returns, meaning subsequent prints are to the same
@@ -214,6 +218,62 @@ func BootstrapInstance(ctx environs.BootstrapContext, env environs.Environ, args
 	return result, selectedSeries, finalize, nil
 }
 
+// adoptBootstrapInstance returns the existing instance named by
+// args.AdoptInstance in place of a newly started one, along with a
+// function that finalizes the bootstrap by reconfiguring the instance
+// from scratch. The instance is assumed to have been started for the
+// controller and model being bootstrapped, so that it is recognised as
+// theirs by the provider.
+func adoptBootstrapInstance(
+	ctx environs.BootstrapContext,
+	env environs.Environ,
+	args environs.BootstrapParams,
+	client ssh.Client,
+	instanceConfig *instancecfg.InstanceConfig,
+	selectedSeries, cloudRegion string,
+	maybeSetBridge func(*instancecfg.InstanceConfig),
+) (*environs.StartInstanceResult, string, environs.BootstrapFinalizer, error) {
+	fmt.Fprintf(ctx.GetStderr(), "Adopting controller instance %s on %s...\n", args.AdoptInstance, cloudRegion)
+	insts, err := env.Instances([]instance.Id{args.AdoptInstance})
+	if err == environs.ErrNoInstances {
+		return nil, "", nil, errors.NotFoundf("instance %q", args.AdoptInstance)
+	} else if err != nil {
+		return nil, "", nil, errors.Annotatef(err, "cannot get instance %q", args.AdoptInstance)
+	}
+	inst := insts[0]
+
+	// The architecture of an existing instance cannot be discovered
+	// through the Environ, so it must be implied by the constraints
+	// or by the tools available.
+	var instArch string
+	if args.BootstrapConstraints.HasArch() {
+		instArch = *args.BootstrapConstraints.Arch
+	} else if arches := args.AvailableTools.Arches(); len(arches) == 1 {
+		instArch = arches[0]
+	} else {
+		return nil, "", nil, errors.Errorf(
+			"cannot determine the architecture of instance %q; specify it with an arch constraint",
+			args.AdoptInstance,
+		)
+	}
+	result := &environs.StartInstanceResult{
+		Instance: inst,
+		Hardware: &instance.HardwareCharacteristics{Arch: &instArch},
+	}
+	fmt.Fprintf(ctx.GetStderr(), " - %s (%s)\n", inst.Id(), formatHardware(result.Hardware))
+
+	finalize := func(ctx environs.BootstrapContext, icfg *instancecfg.InstanceConfig, opts environs.BootstrapDialOpts) error {
+		icfg.Bootstrap.BootstrapMachineInstanceId = inst.Id()
+		icfg.Bootstrap.BootstrapMachineHardwareCharacteristics = result.Hardware
+		if err := instancecfg.FinishInstanceConfig(icfg, env.Config()); err != nil {
+			return err
+		}
+		maybeSetBridge(icfg)
+		return FinishAdoption(ctx, client, env, inst, icfg, opts)
+	}
+	return result, selectedSeries, finalize, nil
+}
+
 func formatHardware(hw *instance.HardwareCharacteristics) string {
 	if hw == nil {
 		return ""
@@ -278,6 +338,80 @@ var FinishBootstrap = func(
 	return ConfigureMachine(ctx, client, addr, instanceConfig, sshOptions)
 }
 
+// FinishAdoption completes the bootstrap process for an adopted
+// instance by connecting to it via SSH, removing the Juju agents and
+// data left on it, and carrying out the cloud-config. The agents are
+// reinstalled with new credentials.
+//
+// Note: FinishAdoption is exposed so it can be replaced for testing.
+var FinishAdoption = func(
+	ctx environs.BootstrapContext,
+	client ssh.Client,
+	env environs.Environ,
+	inst instance.Instance,
+	instanceConfig *instancecfg.InstanceConfig,
+	opts environs.BootstrapDialOpts,
+) error {
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+
+	// The instance's host keys were injected by the bootstrap that
+	// started it, and are not known to us, so we can only fall back
+	// to the user's SSH configuration to check them.
+	addr, err := WaitSSH(
+		ctx.GetStderr(),
+		interrupted,
+		client,
+		checkCloudInitFinishedCommand,
+		&RefreshableInstance{inst, env},
+		opts,
+		DefaultHostSSHOptions,
+	)
+	if err != nil {
+		return err
+	}
+	return configureMachine(ctx, client, addr, instanceConfig, nil, GetResetMachineScript(instanceConfig))
+}
+
+// checkCloudInitFinishedCommand blocks configuring an adopted instance
+// until cloud-init has completed. There is no nonce to check, as the
+// nonce of the instance's original configuration is not known.
+const checkCloudInitFinishedCommand = `
+if [ ! -e /var/lib/cloud/instance/boot-finished ]; then
+	echo "cloud-init has not finished" >&2
+	exit 1
+fi
+`
+
+// GetResetMachineScript returns a script that stops and removes the
+// Juju agents and database services on a machine, and removes the
+// data they left behind, so that the machine may be configured as a
+// new controller. Services managed by systemd and by upstart are
+// removed; the script fails on machines running any other init system.
+func GetResetMachineScript(instanceConfig *instancecfg.InstanceConfig) string {
+	return fmt.Sprintf(`
+if [ -d /run/systemd/system ]; then
+	for unit in $(systemctl list-unit-files --no-legend 'jujud-*' 'juju-db*' | awk '{print $1}'); do
+		systemctl stop "$unit" || true
+		systemctl disable "$unit" || true
+		rm -f "/etc/systemd/system/$unit" "/lib/systemd/system/$unit"
+	done
+	systemctl daemon-reload || true
+elif [ -x /sbin/initctl ]; then
+	for conf in /etc/init/jujud-*.conf /etc/init/juju-db*.conf; do
+		[ -e "$conf" ] || continue
+		initctl stop "$(basename "$conf" .conf)" || true
+		rm -f "$conf"
+	done
+else
+	echo "cannot reset machine: unsupported init system" >&2
+	exit 1
+fi
+rm -rf %s %s
+`, utils.ShQuote(instanceConfig.DataDir), utils.ShQuote(instanceConfig.LogDir))
+}
+
 func GetCheckNonceCommand(instanceConfig *instancecfg.InstanceConfig) string {
 	// Each attempt to connect to an address must verify the machine is the
 	// bootstrap machine by checking its nonce file exists and contains the
@@ -306,6 +440,19 @@ func ConfigureMachine(
 	host string,
 	instanceConfig *instancecfg.InstanceConfig,
 	sshOptions *ssh.Options,
+) error {
+	return configureMachine(ctx, client, host, instanceConfig, sshOptions, "")
+}
+
+// configureMachine configures the host as described by the instance
+// config, after running the given preamble script.
+func configureMachine(
+	ctx environs.BootstrapContext,
+	client ssh.Client,
+	host string,
+	instanceConfig *instancecfg.InstanceConfig,
+	sshOptions *ssh.Options,
+	preamble string,
 ) error {
 	// Bootstrap is synchronous, and will spawn a subprocess
 	// to complete the procedure. If the user hits Ctrl-C,
@@ -333,7 +480,7 @@ func ConfigureMachine(
 	if err != nil {
 		return err
 	}
-	script := shell.DumpFileOnErrorScript(instanceConfig.CloudInitOutputLog) + configScript
+	script := shell.DumpFileOnErrorScript(instanceConfig.CloudInitOutputLog) + preamble + configScript
 	return sshinit.RunConfigureScript(script, sshinit.ConfigureParams{
 		Host:           "ubuntu@" + host,
 		Client:         client,
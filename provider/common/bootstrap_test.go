@@ -308,6 +308,121 @@ func (s *BootstrapSuite) TestSuccess(c *gc.C) {
 	)
 }
 
+func (s *BootstrapSuite) TestAdoptInstance(c *gc.C) {
+	s.PatchValue(&jujuversion.Current, coretesting.FakeVersionNumber)
+	inst := &mockInstance{id: "i-orphan"}
+	env := &mockEnviron{
+		storage: newStorage(s, c),
+		config:  configGetter(c),
+		startInstance: func(string, constraints.Value, []string, tools.List, *instancecfg.InstanceConfig) (
+			instance.Instance, *instance.HardwareCharacteristics, []network.InterfaceInfo, error,
+		) {
+			c.Fatalf("unexpected call to StartInstance")
+			return nil, nil, nil, nil
+		},
+		instances: func(ids []instance.Id) ([]instance.Instance, error) {
+			c.Assert(ids, jc.DeepEquals, []instance.Id{"i-orphan"})
+			return []instance.Instance{inst}, nil
+		},
+	}
+	var adopted instance.Instance
+	var adoptedConfig *instancecfg.InstanceConfig
+	s.PatchValue(&common.FinishAdoption, func(
+		_ environs.BootstrapContext,
+		_ ssh.Client,
+		_ environs.Environ,
+		inst instance.Instance,
+		icfg *instancecfg.InstanceConfig,
+		_ environs.BootstrapDialOpts,
+	) error {
+		adopted = inst
+		adoptedConfig = icfg
+		return nil
+	})
+
+	ctx := envtesting.BootstrapContext(c)
+	result, err := common.Bootstrap(ctx, env, environs.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdoptInstance:    "i-orphan",
+		AvailableTools: tools.List{
+			&tools.Tools{
+				Version: version.Binary{
+					Number: jujuversion.Current,
+					Arch:   arch.HostArch(),
+					Series: series.MustHostSeries(),
+				},
+			},
+		}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Arch, gc.Equals, arch.HostArch()) // based on the available tools
+
+	icfg, err := instancecfg.NewBootstrapInstanceConfig(
+		coretesting.FakeControllerConfig(), constraints.Value{}, constraints.Value{}, result.Series, "",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = result.Finalize(ctx, icfg, environs.BootstrapDialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(adopted, gc.Equals, inst)
+	c.Assert(adoptedConfig.Bootstrap.BootstrapMachineInstanceId, gc.Equals, instance.Id("i-orphan"))
+	c.Assert(adoptedConfig.Bootstrap.InitialSSHHostKeys.RSA, gc.IsNil)
+}
+
+func (s *BootstrapSuite) TestAdoptInstanceNotFound(c *gc.C) {
+	env := &mockEnviron{
+		storage: newStorage(s, c),
+		config:  configGetter(c),
+		instances: func(ids []instance.Id) ([]instance.Instance, error) {
+			return nil, environs.ErrNoInstances
+		},
+	}
+	ctx := envtesting.BootstrapContext(c)
+	_, err := common.Bootstrap(ctx, env, environs.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdoptInstance:    "i-missing",
+		AvailableTools: tools.List{
+			&tools.Tools{
+				Version: version.Binary{
+					Number: jujuversion.Current,
+					Arch:   arch.HostArch(),
+					Series: series.MustHostSeries(),
+				},
+			},
+		}})
+	c.Assert(err, gc.ErrorMatches, `instance "i-missing" not found`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *BootstrapSuite) TestAdoptInstanceAmbiguousArch(c *gc.C) {
+	env := &mockEnviron{
+		storage: newStorage(s, c),
+		config:  configGetter(c),
+		instances: func(ids []instance.Id) ([]instance.Instance, error) {
+			return []instance.Instance{&mockInstance{id: "i-orphan"}}, nil
+		},
+	}
+	hostSeries := series.MustHostSeries()
+	ctx := envtesting.BootstrapContext(c)
+	_, err := common.Bootstrap(ctx, env, environs.BootstrapParams{
+		ControllerConfig: coretesting.FakeControllerConfig(),
+		AdoptInstance:    "i-orphan",
+		AvailableTools: tools.List{
+			&tools.Tools{Version: version.MustParseBinary("2.0.0-" + hostSeries + "-amd64")},
+			&tools.Tools{Version: version.MustParseBinary("2.0.0-" + hostSeries + "-arm64")},
+		}})
+	c.Assert(err, gc.ErrorMatches, `cannot determine the architecture of instance "i-orphan"; specify it with an arch constraint`)
+}
+
+func (s *BootstrapSuite) TestGetResetMachineScript(c *gc.C) {
+	script := common.GetResetMachineScript(&instancecfg.InstanceConfig{
+		DataDir: "/var/lib/juju",
+		LogDir:  "/var/log/juju",
+	})
+	c.Assert(script, jc.Contains, "systemctl stop \"$unit\"")
+	c.Assert(script, jc.Contains, "initctl stop \"$(basename \"$conf\" .conf)\"")
+	c.Assert(script, jc.Contains, "unsupported init system")
+	c.Assert(script, jc.Contains, "rm -rf '/var/lib/juju' '/var/log/juju'")
+}
+
 type neverRefreshes struct {
 }
 
@@ -74,17 +74,19 @@ func (c *sshInstanceConfigurator) DropAllPorts(exceptPorts []int, addr string) e
 }
 
 // ConfigureExternalIpAddressCommands returns the commands to run to configure
-// the external IP address
-func ConfigureExternalIpAddressCommands(apiPort int) []string {
+// the external IP address, accepting connections on the given API ports.
+func ConfigureExternalIpAddressCommands(apiPorts ...int) []string {
 	commands := []string{
 		`printf 'auto eth1\niface eth1 inet dhcp' | sudo tee -a /etc/network/interfaces.d/eth1.cfg`,
 		"sudo ifup eth1",
 		"sudo iptables -i eth1 -I INPUT -m state --state NEW -j DROP",
 	}
-	if apiPort > 0 {
-		commands = append(commands, fmt.Sprintf(
-			"sudo iptables -I INPUT -p tcp --dport %d -j ACCEPT", apiPort,
-		))
+	for _, apiPort := range apiPorts {
+		if apiPort > 0 {
+			commands = append(commands, fmt.Sprintf(
+				"sudo iptables -I INPUT -p tcp --dport %d -j ACCEPT", apiPort,
+			))
+		}
 	}
 	return commands
 }
@@ -1173,6 +1173,22 @@ func (e *environ) Instances(ids []instance.Id) (insts []instance.Instance, err e
 	return
 }
 
+// Capabilities is specified on the Environ interface.
+func (env *environ) Capabilities() (environs.Capabilities, error) {
+	if err := env.checkBroken("Capabilities"); err != nil {
+		return environs.Capabilities{}, err
+	}
+	storageKinds, err := environs.StorageKinds(env)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:      storageKinds,
+		AvailabilityZones: true,
+		FirewallModes:     environs.AllFirewallModes,
+	}, nil
+}
+
 // SupportsSpaces is specified on environs.Networking.
 func (env *environ) SupportsSpaces() (bool, error) {
 	dummy.mu.Lock()
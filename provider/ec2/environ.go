@@ -136,6 +136,19 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 	return common.Bootstrap(ctx, e, args)
 }
 
+// Capabilities is specified on the Environ interface.
+func (e *environ) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(e)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:      storageKinds,
+		AvailabilityZones: true,
+		FirewallModes:     environs.AllFirewallModes,
+	}, nil
+}
+
 // SupportsSpaces is specified on environs.Networking.
 func (e *environ) SupportsSpaces() (bool, error) {
 	return true, nil
@@ -156,6 +169,10 @@ var unsupportedConstraints = []string{
 	// TODO(anastasiamac 2016-03-16) LP#1557874
 	// use virt-type in StartInstances
 	constraints.VirtType,
+	constraints.Gpu,
+	constraints.UsbDevice,
+	constraints.RootDiskSource,
+	constraints.ResourcePool,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -490,14 +507,8 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (_ *environs.
 		return nil, errors.Annotate(err, "cannot make user data")
 	}
 	logger.Debugf("ec2 user data; %d bytes", len(userData))
-	var apiPort int
-	if args.InstanceConfig.Controller != nil {
-		apiPort = args.InstanceConfig.Controller.Config.APIPort()
-	} else {
-		apiPort = args.InstanceConfig.APIInfo.Ports()[0]
-	}
 	callback(status.Allocating, "Setting up groups", nil)
-	groups, err := e.setUpGroups(args.ControllerUUID, args.InstanceConfig.MachineId, apiPort)
+	groups, err := e.setUpGroups(args.ControllerUUID, args.InstanceConfig.MachineId, args.InstanceConfig.APIPorts())
 
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot set up groups")
@@ -1405,6 +1416,68 @@ func (e *environ) IngressRules() ([]network.IngressRule, error) {
 	return e.ingressRulesInGroup(e.globalGroupName())
 }
 
+var _ environs.ServiceFirewaller = (*environ)(nil)
+
+// SetServiceIngressRules is part of the environs.ServiceFirewaller
+// interface. The rules are applied to the model's juju group, which
+// holds the SSH and API port permissions for every instance.
+func (e *environ) SetServiceIngressRules(rules []network.IngressRule) error {
+	name := e.jujuGroupName()
+	existing, err := e.ingressRulesInGroup(name)
+	if isNotFoundError(err) {
+		// The group is created along with the model's first
+		// instance, which will pick up the rules then.
+		logger.Debugf("juju group %q not found, not setting service ingress rules", name)
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, rule := range rules {
+		want := set.NewStrings(rule.SourceCIDRs...)
+		if want.IsEmpty() {
+			want.Add(defaultRouteCIDRBlock)
+		}
+		have := set.NewStrings(sourceCIDRsForPortRange(existing, rule.PortRange)...)
+		// Authorize the new addresses before revoking the old ones,
+		// so that there is no window in which the service cannot be
+		// reached from anywhere.
+		if err := e.setSourceCIDRsInGroup(name, rule.PortRange, want.Difference(have), e.openPortsInGroup); err != nil {
+			return errors.Trace(err)
+		}
+		if err := e.setSourceCIDRsInGroup(name, rule.PortRange, have.Difference(want), e.closePortsInGroup); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	logger.Infof("set service ingress rules in juju group: %v", rules)
+	return nil
+}
+
+func (e *environ) setSourceCIDRsInGroup(
+	name string, portRange network.PortRange, cidrs set.Strings,
+	apply func(string, []network.IngressRule) error,
+) error {
+	if cidrs.IsEmpty() {
+		return nil
+	}
+	rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, cidrs.SortedValues()...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return apply(name, []network.IngressRule{rule})
+}
+
+// sourceCIDRsForPortRange returns the source CIDRs of the rules that
+// apply exactly to the given port range.
+func sourceCIDRsForPortRange(rules []network.IngressRule, portRange network.PortRange) []string {
+	var cidrs []string
+	for _, rule := range rules {
+		if rule.PortRange == portRange {
+			cidrs = append(cidrs, rule.SourceCIDRs...)
+		}
+	}
+	return cidrs
+}
+
 func (*environ) Provider() environs.EnvironProvider {
 	return &providerInstance
 }
@@ -1641,21 +1714,40 @@ func (e *environ) jujuGroupName() string {
 // other instances that might be running on the same EC2 account.  In
 // addition, a specific machine security group is created for each
 // machine, so that its firewall rules can be configured per machine.
-func (e *environ) setUpGroups(controllerUUID, machineId string, apiPort int) ([]ec2.SecurityGroup, error) {
+func (e *environ) setUpGroups(controllerUUID, machineId string, apiPorts []int) ([]ec2.SecurityGroup, error) {
 
-	// Ensure there's a global group for Juju-related traffic.
-	jujuGroup, err := e.ensureGroup(controllerUUID, e.jujuGroupName(),
-		[]ec2.IPPerm{{
-			Protocol:  "tcp",
-			FromPort:  22,
-			ToPort:    22,
-			SourceIPs: []string{"0.0.0.0/0"},
-		}, {
+	// Keep any restrictions on access to SSH and the API that have
+	// been made with SetServiceIngressRules, rather than reopening
+	// them to the world.
+	existing, err := e.ingressRulesInGroup(e.jujuGroupName())
+	if err != nil && !isNotFoundError(err) {
+		return nil, errors.Trace(err)
+	}
+	sourceCIDRs := func(port int) []string {
+		portRange := network.PortRange{FromPort: port, ToPort: port, Protocol: "tcp"}
+		if cidrs := sourceCIDRsForPortRange(existing, portRange); len(cidrs) > 0 {
+			return cidrs
+		}
+		return []string{defaultRouteCIDRBlock}
+	}
+	perms := []ec2.IPPerm{{
+		Protocol:  "tcp",
+		FromPort:  22,
+		ToPort:    22,
+		SourceIPs: sourceCIDRs(22),
+	}}
+	for _, apiPort := range apiPorts {
+		perms = append(perms, ec2.IPPerm{
 			Protocol:  "tcp",
 			FromPort:  apiPort,
 			ToPort:    apiPort,
-			SourceIPs: []string{"0.0.0.0/0"},
-		}, {
+			SourceIPs: sourceCIDRs(apiPort),
+		})
+	}
+
+	// Ensure there's a global group for Juju-related traffic.
+	jujuGroup, err := e.ensureGroup(controllerUUID, e.jujuGroupName(),
+		append(perms, []ec2.IPPerm{{
 			Protocol: "tcp",
 			FromPort: 0,
 			ToPort:   65535,
@@ -1667,7 +1759,7 @@ func (e *environ) setUpGroups(controllerUUID, machineId string, apiPort int) ([]
 			Protocol: "icmp",
 			FromPort: -1,
 			ToPort:   -1,
-		}},
+		}}...),
 	)
 	if err != nil {
 		return nil, err
@@ -568,6 +568,41 @@ func (t *localServerSuite) TestGetTerminatedInstances(c *gc.C) {
 	c.Assert(terminated[0].Id(), jc.DeepEquals, inst1.Id())
 }
 
+func (t *localServerSuite) TestSetServiceIngressRules(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	sshSourceIPs := func() []string {
+		resp, err := ec2.EnvironEC2(env).SecurityGroups(amzec2.SecurityGroupNames(ec2.JujuGroupName(env)), nil)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(resp.Groups, gc.HasLen, 1)
+		var sourceIPs []string
+		for _, perm := range resp.Groups[0].IPPerms {
+			if perm.Protocol == "tcp" && perm.FromPort == 22 && perm.ToPort == 22 {
+				sourceIPs = append(sourceIPs, perm.SourceIPs...)
+			}
+		}
+		return sourceIPs
+	}
+	c.Assert(sshSourceIPs(), jc.SameContents, []string{"0.0.0.0/0"})
+
+	err := env.(environs.ServiceFirewaller).SetServiceIngressRules([]network.IngressRule{
+		network.MustNewIngressRule("tcp", 22, 22, "10.0.0.0/8", "192.168.0.0/16"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sshSourceIPs(), jc.SameContents, []string{"10.0.0.0/8", "192.168.0.0/16"})
+
+	// Starting another instance does not reopen SSH to the world.
+	inst, _ := testing.AssertStartInstance(c, env, t.ControllerUUID, "1")
+	defer env.StopInstances(inst.Id())
+	c.Assert(sshSourceIPs(), jc.SameContents, []string{"10.0.0.0/8", "192.168.0.0/16"})
+
+	// An empty whitelist allows access from anywhere.
+	err = env.(environs.ServiceFirewaller).SetServiceIngressRules([]network.IngressRule{
+		network.MustNewIngressRule("tcp", 22, 22),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sshSourceIPs(), jc.SameContents, []string{"0.0.0.0/0"})
+}
+
 func (t *localServerSuite) TestInstanceSecurityGroupsWitheInstanceStatusFilter(c *gc.C) {
 	env := t.prepareAndBootstrap(c)
 
@@ -1256,10 +1291,10 @@ func (t *localServerSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 	env := t.Prepare(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 tags=foo virt-type=kvm")
+	cons := constraints.MustParse("arch=amd64 tags=foo virt-type=kvm gpu=1 usb-device=10c4:ea60")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"tags", "virt-type"})
+	c.Assert(unsupported, jc.SameContents, []string{"tags", "virt-type", "gpu", "usb-device"})
 }
 
 func (t *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -198,15 +198,14 @@ func (env *environ) Create(environs.CreateParams) error {
 // that must be called to finalize the bootstrap process by transferring
 // the tools and installing the initial juju controller.
 func (env *environ) Bootstrap(ctx environs.BootstrapContext, params environs.BootstrapParams) (*environs.BootstrapResult, error) {
-	// Ensure the API server port is open (globally for all instances
+	// Ensure the API server ports are open (globally for all instances
 	// on the network, not just for the specific node of the state
 	// server). See LP bug #1436191 for details.
-	rule := network.NewOpenIngressRule(
-		"tcp",
-		params.ControllerConfig.APIPort(),
-		params.ControllerConfig.APIPort(),
-	)
-	if err := env.gce.OpenPorts(env.globalFirewallName(), rule); err != nil {
+	var rules []network.IngressRule
+	for _, port := range params.ControllerConfig.APIPorts() {
+		rules = append(rules, network.NewOpenIngressRule("tcp", port, port))
+	}
+	if err := env.gce.OpenPorts(env.globalFirewallName(), rules...); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return bootstrap(ctx, env, params)
@@ -33,6 +33,10 @@ func (env *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Gpu,
+	constraints.UsbDevice,
+	constraints.RootDiskSource,
+	constraints.ResourcePool,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -45,6 +49,19 @@ var instanceTypeConstraints = []string{
 	constraints.Container, // VirtType
 }
 
+// Capabilities is specified on the Environ interface.
+func (env *environ) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(env)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:      storageKinds,
+		AvailabilityZones: true,
+		FirewallModes:     environs.AllFirewallModes,
+	}, nil
+}
+
 // ConstraintsValidator returns a Validator value which is used to
 // validate and merge constraints.
 func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
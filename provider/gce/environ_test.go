@@ -106,6 +106,30 @@ func (s *environSuite) TestBootstrapOpensAPIPort(c *gc.C) {
 	c.Check(calls[0].Rules, jc.DeepEquals, expectRules)
 }
 
+func (s *environSuite) TestBootstrapOpensAgentAPIPort(c *gc.C) {
+	s.FakeCommon.BSFinalizer = func(environs.BootstrapContext, *instancecfg.InstanceConfig, environs.BootstrapDialOpts) error {
+		return nil
+	}
+
+	ctx := envtesting.BootstrapContext(c)
+	params := environs.BootstrapParams{
+		ControllerConfig: testing.FakeControllerConfig(),
+	}
+	params.ControllerConfig["agent-api-port"] = 17071
+	_, err := s.Env.Bootstrap(ctx, params)
+	c.Assert(err, jc.ErrorIsNil)
+	apiPort := params.ControllerConfig.APIPort()
+
+	called, calls := s.FakeConn.WasCalled("OpenPorts")
+	c.Check(called, gc.Equals, true)
+	c.Check(calls, gc.HasLen, 1)
+	expectRules := []network.IngressRule{
+		network.MustNewIngressRule("tcp", apiPort, apiPort),
+		network.MustNewIngressRule("tcp", 17071, 17071),
+	}
+	c.Check(calls[0].Rules, jc.DeepEquals, expectRules)
+}
+
 func (s *environSuite) TestBootstrapCommon(c *gc.C) {
 	ctx := envtesting.BootstrapContext(c)
 	params := environs.BootstrapParams{
@@ -48,6 +48,22 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.Gpu,
+	constraints.UsbDevice,
+	constraints.RootDiskSource,
+	constraints.ResourcePool,
+}
+
+// Capabilities is specified on the Environ interface.
+func (env *joyentEnviron) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(env)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:  storageKinds,
+		FirewallModes: environs.AllFirewallModes,
+	}, nil
 }
 
 // ConstraintsValidator is defined on the Environs interface.
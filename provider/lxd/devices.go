@@ -0,0 +1,53 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build go1.3
+
+package lxd
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/tools/lxdclient"
+)
+
+// constraintDevices returns the LXD devices that pass through to a new
+// container the GPUs and USB devices requested by the given
+// constraints, having checked with hostResources that the LXD host
+// has them.
+func constraintDevices(cons constraints.Value, hostResources func() (lxdclient.HostResources, error)) (lxdclient.Devices, error) {
+	if !cons.HasGpus() && !cons.HasUsbDevices() {
+		return nil, nil
+	}
+	host, err := hostResources()
+	if err != nil {
+		return nil, errors.Annotate(err, "reading host resources")
+	}
+	devices := make(lxdclient.Devices)
+	if cons.HasGpus() {
+		want := *cons.Gpus
+		if have := uint64(len(host.GPUs)); have < want {
+			return nil, errors.Errorf("cannot pass through %d GPUs: host has %d", want, have)
+		}
+		for i, id := range host.GPUs[:want] {
+			devices[fmt.Sprintf("gpu%d", i)] = lxdclient.GPUDevice(id)
+		}
+	}
+	if cons.HasUsbDevices() {
+		for i, device := range *cons.UsbDevices {
+			vendor, product, err := constraints.ParseUsbDevice(device)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			id := lxdclient.USBDeviceID{VendorID: vendor, ProductID: product}
+			if !host.HasUSBDevice(id) {
+				return nil, errors.Errorf("cannot pass through USB device %s: not attached to host", device)
+			}
+			devices[fmt.Sprintf("usb%d", i)] = lxdclient.USBDevice(id)
+		}
+	}
+	return devices, nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/tools/lxdclient"
 )
 
 const bootstrapMessage = `To configure your system to better support LXD containers, please see: https://github.com/lxc/lxd/blob/master/doc/production-setup.md`
@@ -100,7 +101,11 @@ func (env *environ) initProfile() error {
 		return nil
 	}
 
-	return env.raw.CreateProfile(env.profileName(), defaultProfileConfig)
+	return env.raw.CreateProfile(lxdclient.Profile{
+		Name:        env.profileName(),
+		Description: "Juju profile for model " + env.ecfg.Name(),
+		Config:      defaultProfileConfig,
+	})
 }
 
 func (env *environ) profileName() string {
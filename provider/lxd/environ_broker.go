@@ -140,6 +140,13 @@ func (env *environ) newRawInstance(
 
 	// TODO: support args.Constraints.Arch, we'll want to map from
 
+	// Check that the host has the devices to pass through before
+	// going to the trouble of fetching an image.
+	devices, err := constraintDevices(args.Constraints, env.raw.HostResources)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Keep track of StatusCallback output so we may clean up later.
 	// This is implemented here, close to where the StatusCallback calls
 	// are made, instead of at a higher level in the package, so as not to
@@ -201,6 +208,7 @@ func (env *environ) newRawInstance(
 			env.profileName(),
 		},
 		// Network is omitted (left empty).
+		Devices: devices,
 	}
 
 	logger.Infof("starting instance %q (image %q)...", instSpec.Name, instSpec.Image)
@@ -6,12 +6,15 @@
 package lxd_test
 
 import (
+	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/provider/lxd"
+	"github.com/juju/juju/tools/lxdclient"
 )
 
 type environBrokerSuite struct {
@@ -40,6 +43,57 @@ func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
 	s.Stub.CheckCall(c, 0, "EnsureImageExists", "trusty", "arm64")
 }
 
+func (s *environBrokerSuite) TestStartInstanceWithDevices(c *gc.C) {
+	s.Client.Inst = s.RawInstance
+	s.PatchValue(&arch.HostArch, func() string { return arch.ARM64 })
+	s.Client.HostDevices = lxdclient.HostResources{
+		GPUs: []string{"0", "1", "2"},
+		USBDevices: []lxdclient.USBDeviceID{
+			{VendorID: "10c4", ProductID: "ea60"},
+		},
+	}
+	s.StartInstArgs.Constraints = constraints.MustParse("gpu=2 usb-device=10C4:EA60")
+
+	_, err := s.Env.StartInstance(s.StartInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCallNames(c, "HostResources", "EnsureImageExists", "AddInstance")
+	spec := s.Stub.Calls()[2].Args[0].(lxdclient.InstanceSpec)
+	c.Assert(spec.Devices, jc.DeepEquals, lxdclient.Devices{
+		"gpu0": {"type": "gpu", "id": "0"},
+		"gpu1": {"type": "gpu", "id": "1"},
+		"usb0": {"type": "usb", "vendorid": "10c4", "productid": "ea60"},
+	})
+}
+
+func (s *environBrokerSuite) TestStartInstanceMissingDevices(c *gc.C) {
+	s.Client.Inst = s.RawInstance
+	s.PatchValue(&arch.HostArch, func() string { return arch.ARM64 })
+	s.Client.HostDevices = lxdclient.HostResources{GPUs: []string{"0"}}
+
+	s.StartInstArgs.Constraints = constraints.MustParse("gpu=2")
+	_, err := s.Env.StartInstance(s.StartInstArgs)
+	c.Assert(err, gc.ErrorMatches, "cannot pass through 2 GPUs: host has 1")
+
+	s.StartInstArgs.Constraints = constraints.MustParse("usb-device=046d:c52b")
+	_, err = s.Env.StartInstance(s.StartInstArgs)
+	c.Assert(err, gc.ErrorMatches, "cannot pass through USB device 046d:c52b: not attached to host")
+
+	// No instance is started without its devices.
+	s.Stub.CheckCallNames(c, "HostResources", "HostResources")
+}
+
+func (s *environBrokerSuite) TestStartInstanceHostResourcesError(c *gc.C) {
+	s.Client.Inst = s.RawInstance
+	s.PatchValue(&arch.HostArch, func() string { return arch.ARM64 })
+	s.Stub.SetErrors(errors.NotSupportedf("reading the devices of a remote LXD host without the resources API"))
+
+	s.StartInstArgs.Constraints = constraints.MustParse("gpu=1")
+	_, err := s.Env.StartInstance(s.StartInstArgs)
+	c.Assert(err, gc.ErrorMatches, "reading host resources: reading the devices of a remote LXD host without the resources API not supported")
+	s.Stub.CheckCallNames(c, "HostResources")
+}
+
 func (s *environBrokerSuite) TestStartInstanceNoTools(c *gc.C) {
 	s.Client.Inst = s.RawInstance
 
@@ -34,6 +34,21 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.ImageId,
+	constraints.RootDiskSource,
+	constraints.ResourcePool,
+}
+
+// Capabilities is specified on the Environ interface.
+func (env *environ) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(env)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:  storageKinds,
+		FirewallModes: environs.AllFirewallModes,
+	}, nil
 }
 
 // ConstraintsValidator returns a Validator value which is used to
@@ -98,6 +98,8 @@ func (s *environPolSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 		"cores=2",
 		"cpu-power=250",
 		"virt-type=kvm",
+		"image-id=golden",
+		"root-disk-source=default",
 	}, " "))
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
@@ -108,6 +110,8 @@ func (s *environPolSuite) TestConstraintsValidatorUnsupported(c *gc.C) {
 		"cores",
 		"cpu-power",
 		"virt-type",
+		"image-id",
+		"root-disk-source",
 	}
 	c.Check(unsupported, jc.SameContents, expected)
 }
@@ -45,13 +45,18 @@ type lxdInstances interface {
 	AddInstance(lxdclient.InstanceSpec) (*lxdclient.Instance, error)
 	RemoveInstances(string, ...string) error
 	Addresses(string) ([]network.Address, error)
-	AttachDisk(string, string, lxdclient.DiskDevice) error
+	AttachDevice(string, string, lxdclient.InstanceDevice) error
+	DetachDevice(string, string) error
+	ProxySupported() bool
+	AddProxyDevice(string, string, lxdclient.ProxyDevice) error
+	ProxyDevices(string) (map[string]lxdclient.ProxyDevice, error)
 	RemoveDevice(string, string) error
+	HostResources() (lxdclient.HostResources, error)
 }
 
 type lxdProfiles interface {
 	DefaultProfileBridgeName() string
-	CreateProfile(string, map[string]string) error
+	CreateProfile(lxdclient.Profile) error
 	HasProfile(string) (bool, error)
 }
 
@@ -8,8 +8,9 @@ package lxd
 import "github.com/juju/juju/tools/lxdclient"
 
 var (
-	GlobalFirewallName = (*environ).globalFirewallName
-	NewInstance        = newInstance
+	GlobalFirewallName  = (*environ).globalFirewallName
+	NewInstance         = newInstance
+	OrphanedControllers = orphanedControllers
 )
 
 func ExposeInstRaw(inst *environInstance) *lxdclient.Instance {
@@ -28,6 +29,10 @@ func ExposeEnvClient(env *environ) lxdInstances {
 	return env.raw.lxdInstances
 }
 
+func SetCloudEndpoint(env *environ, endpoint string) {
+	env.cloud.Endpoint = endpoint
+}
+
 func GetImageSources(env *environ) ([]lxdclient.Remote, error) {
 	return env.getImageSources()
 }
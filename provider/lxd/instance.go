@@ -62,19 +62,40 @@ func (inst *environInstance) Addresses() ([]network.Address, error) {
 // firewall stuff
 
 // OpenPorts opens the given ports on the instance, which
-// should have been started with the given machine id.
+// should have been started with the given machine id. Each port is
+// opened by adding an LXD proxy device that forwards connections from
+// the LXD host's endpoint address to the container.
 func (inst *environInstance) OpenPorts(machineID string, rules []network.IngressRule) error {
-	// TODO(ericsnow) Make sure machineId matches inst.Id()?
+	if !inst.env.raw.ProxySupported() {
+		return errors.NotSupportedf("opening ports without the LXD container_proxy API extension")
+	}
+	host, err := proxyListenHost(inst.env.cloud.Endpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	name, err := inst.env.namespace.Hostname(machineID)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	err = inst.env.raw.OpenPorts(name, rules...)
-	if errors.IsNotImplemented(err) {
-		// TODO(ericsnow) for now...
-		return nil
+	existing, err := inst.env.raw.ProxyDevices(name)
+	if err != nil {
+		return errors.Trace(err)
 	}
-	return errors.Trace(err)
+	for _, rule := range rules {
+		devices, err := proxyDevicesForRule(rule, host)
+		if err != nil {
+			return errors.Annotatef(err, "opening %s", rule)
+		}
+		for deviceName, device := range devices {
+			if _, ok := existing[deviceName]; ok {
+				continue
+			}
+			if err := inst.env.raw.AddProxyDevice(name, deviceName, device); err != nil {
+				return errors.Annotatef(err, "opening %s", rule)
+			}
+		}
+	}
+	return nil
 }
 
 // ClosePorts closes the given ports on the instance, which
@@ -84,12 +105,22 @@ func (inst *environInstance) ClosePorts(machineID string, rules []network.Ingres
 	if err != nil {
 		return errors.Trace(err)
 	}
-	err = inst.env.raw.ClosePorts(name, rules...)
-	if errors.IsNotImplemented(err) {
-		// TODO(ericsnow) for now...
-		return nil
+	existing, err := inst.env.raw.ProxyDevices(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, rule := range rules {
+		for port := rule.FromPort; port <= rule.ToPort; port++ {
+			deviceName := proxyDeviceName(rule.Protocol, port)
+			if _, ok := existing[deviceName]; !ok {
+				continue
+			}
+			if err := inst.env.raw.RemoveDevice(name, deviceName); err != nil {
+				return errors.Annotatef(err, "closing %s", rule)
+			}
+		}
 	}
-	return errors.Trace(err)
+	return nil
 }
 
 // IngressRules returns the set of rules applied to the instance, which
@@ -100,10 +131,9 @@ func (inst *environInstance) IngressRules(machineID string) ([]network.IngressRu
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	ports, err := inst.env.raw.IngressRules(name)
-	if errors.IsNotImplemented(err) {
-		// TODO(ericsnow) for now...
-		return nil, nil
+	devices, err := inst.env.raw.ProxyDevices(name)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
-	return ports, errors.Trace(err)
+	return ingressRulesForProxyDevices(devices), nil
 }
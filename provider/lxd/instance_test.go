@@ -6,11 +6,13 @@
 package lxd_test
 
 import (
+	"github.com/juju/errors"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/lxd"
 	"github.com/juju/juju/tools/lxdclient"
 )
@@ -55,34 +57,138 @@ func (s *instanceSuite) TestOpenPortsAPI(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	s.Stub.CheckCalls(c, []gitjujutesting.StubCall{{
-		FuncName: "OpenPorts",
+		FuncName: "ProxySupported",
+	}, {
+		FuncName: "ProxyDevices",
+		Args:     []interface{}{s.InstName},
+	}, {
+		FuncName: "AddProxyDevice",
 		Args: []interface{}{
 			s.InstName,
-			s.Rules,
+			"juju-proxy-tcp-80",
+			lxdclient.ProxyDevice{
+				Listen:  "tcp:10.0.8.1:80",
+				Connect: "tcp:127.0.0.1:80",
+			},
 		},
 	}})
 }
 
+func (s *instanceSuite) TestOpenPortsRange(c *gc.C) {
+	rules := []network.IngressRule{network.MustNewIngressRule("udp", 53, 54)}
+	err := s.Instance.OpenPorts("42", rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCallNames(c, "ProxySupported", "ProxyDevices", "AddProxyDevice", "AddProxyDevice")
+	s.Stub.CheckCall(c, 2, "AddProxyDevice", s.InstName, "juju-proxy-udp-53", lxdclient.ProxyDevice{
+		Listen:  "udp:10.0.8.1:53",
+		Connect: "udp:127.0.0.1:53",
+	})
+	s.Stub.CheckCall(c, 3, "AddProxyDevice", s.InstName, "juju-proxy-udp-54", lxdclient.ProxyDevice{
+		Listen:  "udp:10.0.8.1:54",
+		Connect: "udp:127.0.0.1:54",
+	})
+}
+
+func (s *instanceSuite) TestOpenPortsRangeTooLarge(c *gc.C) {
+	rules := []network.IngressRule{network.MustNewIngressRule("tcp", 1000, 1100)}
+	err := s.Instance.OpenPorts("42", rules)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `opening 1000-1100/tcp: 101 ports \(more than 100\) in one rule not supported`)
+
+	s.Stub.CheckCallNames(c, "ProxySupported", "ProxyDevices")
+}
+
+func (s *instanceSuite) TestOpenPortsIPv6Endpoint(c *gc.C) {
+	lxd.SetCloudEndpoint(s.Env, "https://[fd00::1]:8443")
+	err := s.Instance.OpenPorts("42", s.Rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCall(c, 2, "AddProxyDevice", s.InstName, "juju-proxy-tcp-80", lxdclient.ProxyDevice{
+		Listen:  "tcp:[fd00::1]:80",
+		Connect: "tcp:127.0.0.1:80",
+	})
+}
+
+func (s *instanceSuite) TestOpenPortsEndpointNotAnAddress(c *gc.C) {
+	lxd.SetCloudEndpoint(s.Env, "lxd.example.com:8443")
+	err := s.Instance.OpenPorts("42", s.Rules)
+	c.Assert(err, gc.ErrorMatches, `cannot determine proxy listen address: LXD endpoint host "lxd.example.com" is not an IP address`)
+
+	s.Stub.CheckCallNames(c, "ProxySupported")
+}
+
+func (s *instanceSuite) TestOpenPortsProxyNotSupported(c *gc.C) {
+	s.Client.ProxyIsSupported = false
+	err := s.Instance.OpenPorts("42", s.Rules)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+
+	s.Stub.CheckCallNames(c, "ProxySupported")
+}
+
+func (s *instanceSuite) TestOpenPortsAlreadyOpen(c *gc.C) {
+	s.Client.Proxies = map[string]lxdclient.ProxyDevice{
+		"juju-proxy-tcp-80": {Listen: "tcp:10.0.8.1:80", Connect: "tcp:127.0.0.1:80"},
+	}
+	err := s.Instance.OpenPorts("42", s.Rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCallNames(c, "ProxySupported", "ProxyDevices")
+}
+
+func (s *instanceSuite) TestOpenPortsIgnoresICMP(c *gc.C) {
+	rules := []network.IngressRule{network.MustNewIngressRule("icmp", -1, -1)}
+	err := s.Instance.OpenPorts("42", rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCallNames(c, "ProxySupported", "ProxyDevices")
+}
+
+func (s *instanceSuite) TestOpenPortsError(c *gc.C) {
+	s.Stub.SetErrors(nil, errors.New("boom"))
+	err := s.Instance.OpenPorts("42", s.Rules)
+	c.Assert(err, gc.ErrorMatches, "opening 80/tcp: boom")
+}
+
 func (s *instanceSuite) TestClosePortsAPI(c *gc.C) {
+	s.Client.Proxies = map[string]lxdclient.ProxyDevice{
+		"juju-proxy-tcp-80": {Listen: "tcp:10.0.8.1:80", Connect: "tcp:127.0.0.1:80"},
+	}
 	err := s.Instance.ClosePorts("42", s.Rules)
 	c.Assert(err, jc.ErrorIsNil)
 
 	s.Stub.CheckCalls(c, []gitjujutesting.StubCall{{
-		FuncName: "ClosePorts",
-		Args: []interface{}{
-			s.InstName,
-			s.Rules,
-		},
+		FuncName: "ProxyDevices",
+		Args:     []interface{}{s.InstName},
+	}, {
+		FuncName: "RemoveDevice",
+		Args:     []interface{}{s.InstName, "juju-proxy-tcp-80"},
 	}})
 }
 
+func (s *instanceSuite) TestClosePortsNotOpen(c *gc.C) {
+	err := s.Instance.ClosePorts("42", s.Rules)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCallNames(c, "ProxyDevices")
+}
+
 func (s *instanceSuite) TestPortsOkay(c *gc.C) {
-	s.Firewaller.PortRanges = s.Rules
+	s.Client.Proxies = map[string]lxdclient.ProxyDevice{
+		"juju-proxy-tcp-80":  {Listen: "tcp:0.0.0.0:80", Connect: "tcp:127.0.0.1:80"},
+		"juju-proxy-udp-53":  {Listen: "udp:0.0.0.0:53", Connect: "udp:127.0.0.1:53"},
+		"juju-proxy-tcp-443": {Listen: "tcp:[fd00::1]:443", Connect: "tcp:127.0.0.1:443"},
+		"not-ours":           {Listen: "tcp:0.0.0.0:8080", Connect: "tcp:127.0.0.1:8080"},
+	}
 
 	ports, err := s.Instance.IngressRules("42")
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(ports, jc.DeepEquals, s.Rules)
+	c.Check(ports, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80),
+		network.MustNewIngressRule("tcp", 443, 443),
+		network.MustNewIngressRule("udp", 53, 53),
+	})
 }
 
 func (s *instanceSuite) TestPortsAPI(c *gc.C) {
@@ -90,7 +196,7 @@ func (s *instanceSuite) TestPortsAPI(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	s.Stub.CheckCalls(c, []gitjujutesting.StubCall{{
-		FuncName: "Ports",
+		FuncName: "ProxyDevices",
 		Args: []interface{}{
 			s.InstName,
 		},
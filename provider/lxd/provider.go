@@ -13,12 +13,15 @@ import (
 	"github.com/juju/jsonschema"
 	"github.com/juju/schema"
 	"github.com/juju/utils"
+	"github.com/juju/utils/set"
 	"github.com/lxc/lxd/shared"
 	"gopkg.in/juju/environschema.v1"
 
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/lxd/lxdnames"
 	"github.com/juju/juju/tools/lxdclient"
 )
@@ -62,6 +65,57 @@ func (p *environProvider) Open(args environs.OpenParams) (environs.Environ, erro
 	return env, errors.Trace(err)
 }
 
+// OrphanedControllers is part of the environs.OrphanedControllerFinder
+// interface.
+func (p *environProvider) OrphanedControllers(
+	spec environs.CloudSpec, knownControllerUUIDs []string,
+) ([]environs.OrphanedController, error) {
+	local, err := p.validateCloudSpec(spec)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	raw, err := newRawProvider(spec, local)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	instances, err := raw.Instances("juju-", lxdclient.AliveStatuses...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	orphans := orphanedControllers(instances, knownControllerUUIDs)
+	for i, orphan := range orphans {
+		addrs, err := raw.Addresses(string(orphan.InstanceId))
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting addresses of instance %q", orphan.InstanceId)
+		}
+		orphans[i].Addresses = addrs
+	}
+	return orphans, nil
+}
+
+// orphanedControllers returns the controller instances among those
+// given that belong to none of the known controllers.
+func orphanedControllers(instances []lxdclient.Instance, knownControllerUUIDs []string) []environs.OrphanedController {
+	known := set.NewStrings(knownControllerUUIDs...)
+	var results []environs.OrphanedController
+	for _, inst := range instances {
+		metadata := inst.Metadata()
+		if metadata[tags.JujuIsController] != "true" {
+			continue
+		}
+		controllerUUID := metadata[tags.JujuController]
+		if controllerUUID == "" || known.Contains(controllerUUID) {
+			continue
+		}
+		results = append(results, environs.OrphanedController{
+			InstanceId:     instance.Id(inst.Name),
+			ControllerUUID: controllerUUID,
+			ModelUUID:      metadata[tags.JujuModel],
+		})
+	}
+	return results
+}
+
 // CloudSchema returns the schema used to validate input for add-cloud.  Since
 // this provider does not support custom clouds, this always returns nil.
 func (p *environProvider) CloudSchema() *jsonschema.Schema {
@@ -153,12 +207,14 @@ func (p *environProvider) getLocalHostAddress(ctx environs.FinalizeCloudContext)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
+	// Only listen on the bridge, rather than exposing LXD on every
+	// interface; clients on the host can still connect through it.
+	if err := lxdclient.EnableHTTPSListenerOn(raw, []string{hostAddress}); err != nil {
+		return "", errors.Annotate(err, "enabling HTTPS listener")
+	}
 	// LXD itself reports the host:ports that is listens on.
 	// Cross-check the address we have with the values
 	// reported by LXD.
-	if err := lxdclient.EnableHTTPSListener(raw); err != nil {
-		return "", errors.Annotate(err, "enabling HTTPS listener")
-	}
 	serverAddresses, err := raw.ServerAddresses()
 	if err != nil {
 		return "", errors.Trace(err)
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/provider/lxd"
 	"github.com/juju/juju/provider/lxd/lxdnames"
 	"github.com/juju/juju/tools/lxdclient"
@@ -124,7 +125,7 @@ func (s *providerSuite) TestFinalizeCloud(c *gc.C) {
 		{"DefaultProfileBridgeName", nil},
 		{"InterfaceAddress", []interface{}{"test-bridge"}},
 		{"ServerStatus", nil},
-		{"SetServerConfig", []interface{}{"core.https_address", "[::]"}},
+		{"SetServerConfig", []interface{}{"core.https_address", "1.2.3.4"}},
 		{"ServerAddresses", nil},
 	})
 }
@@ -162,7 +163,7 @@ func (s *providerSuite) TestFinalizeCloudAlreadyListeningHTTPS(c *gc.C) {
 		{"DefaultProfileBridgeName", nil},
 		{"InterfaceAddress", []interface{}{"test-bridge"}},
 		{"ServerStatus", nil},
-		{"SetServerConfig", []interface{}{"core.https_address", "[::]"}},
+		{"SetServerConfig", []interface{}{"core.https_address", "1.2.3.4"}},
 		{"ServerAddresses", nil},
 	})
 }
@@ -181,6 +182,38 @@ func (s *providerSuite) TestValidate(c *gc.C) {
 	c.Check(s.Config.AllAttrs(), gc.DeepEquals, validAttrs)
 }
 
+func (s *providerSuite) TestOrphanedControllers(c *gc.C) {
+	newInstance := func(name string, metadata map[string]string) lxdclient.Instance {
+		return *lxdclient.NewInstance(lxdclient.InstanceSummary{
+			Name:     name,
+			Status:   lxdclient.StatusRunning,
+			Metadata: metadata,
+		}, nil)
+	}
+	instances := []lxdclient.Instance{
+		newInstance("juju-aaaaaa-0", map[string]string{
+			tags.JujuController:   "known-uuid",
+			tags.JujuModel:        "model-aaaaaa",
+			tags.JujuIsController: "true",
+		}),
+		newInstance("juju-bbbbbb-0", map[string]string{
+			tags.JujuController:   "orphaned-uuid",
+			tags.JujuModel:        "model-bbbbbb",
+			tags.JujuIsController: "true",
+		}),
+		newInstance("juju-bbbbbb-1", map[string]string{
+			tags.JujuController: "orphaned-uuid",
+			tags.JujuModel:      "model-bbbbbb",
+		}),
+	}
+	orphans := lxd.OrphanedControllers(instances, []string{"known-uuid"})
+	c.Assert(orphans, jc.DeepEquals, []environs.OrphanedController{{
+		InstanceId:     "juju-bbbbbb-0",
+		ControllerUUID: "orphaned-uuid",
+		ModelUUID:      "model-bbbbbb",
+	}})
+}
+
 type ProviderFunctionalSuite struct {
 	lxd.BaseSuite
 
@@ -0,0 +1,110 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build go1.3
+
+package lxd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/tools/lxdclient"
+)
+
+// proxyDevicePrefix is the prefix of the names of the proxy devices
+// managed by Juju. Devices without this prefix are left untouched.
+const proxyDevicePrefix = "juju-proxy-"
+
+// proxyDeviceName returns the name of the proxy device that forwards
+// the given host port to the container.
+func proxyDeviceName(protocol string, port int) string {
+	return fmt.Sprintf("%s%s-%d", proxyDevicePrefix, protocol, port)
+}
+
+// maxProxyPortRange is the largest number of ports that may be opened
+// by a single rule. Each port needs its own proxy device, so larger
+// ranges are rejected rather than flooding the container with devices.
+const maxProxyPortRange = 100
+
+// proxyListenHost returns the host address on which proxy devices
+// listen, derived from the LXD endpoint. Listening on the one address
+// rather than on all of the host's addresses keeps the proxies from
+// colliding with services bound to the host's other addresses.
+func proxyListenHost(endpoint string) (string, error) {
+	if endpoint == "" {
+		return "", errors.New("cannot determine proxy listen address: no LXD endpoint")
+	}
+	remoteURL, err := endpointURL(endpoint)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	host := remoteURL.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", errors.Errorf("cannot determine proxy listen address: LXD endpoint host %q is not an IP address", host)
+	}
+	if ip.To4() == nil {
+		return "[" + ip.String() + "]", nil
+	}
+	return ip.String(), nil
+}
+
+// proxyDevicesForRule returns the proxy devices, keyed by device name,
+// required to forward each port in the rule's range from the given
+// host address to the same port in the container. Proxy devices
+// cannot filter by source address, nor forward ICMP, so the rule's
+// source CIDRs are ignored and ICMP rules yield no devices.
+func proxyDevicesForRule(rule network.IngressRule, host string) (map[string]lxdclient.ProxyDevice, error) {
+	devices := make(map[string]lxdclient.ProxyDevice)
+	if rule.Protocol != "tcp" && rule.Protocol != "udp" {
+		return devices, nil
+	}
+	if n := rule.ToPort - rule.FromPort + 1; n > maxProxyPortRange {
+		return nil, errors.NotSupportedf("%d ports (more than %d) in one rule", n, maxProxyPortRange)
+	}
+	for port := rule.FromPort; port <= rule.ToPort; port++ {
+		devices[proxyDeviceName(rule.Protocol, port)] = lxdclient.ProxyDevice{
+			Listen:  fmt.Sprintf("%s:%s:%d", rule.Protocol, host, port),
+			Connect: fmt.Sprintf("%s:127.0.0.1:%d", rule.Protocol, port),
+		}
+	}
+	return devices, nil
+}
+
+// ingressRulesForProxyDevices returns the ingress rules implemented by
+// the Juju-managed proxy devices amongst those supplied. The rules are
+// returned as sorted by SortIngressRules.
+func ingressRulesForProxyDevices(devices map[string]lxdclient.ProxyDevice) []network.IngressRule {
+	var rules []network.IngressRule
+	for name, device := range devices {
+		if !strings.HasPrefix(name, proxyDevicePrefix) {
+			continue
+		}
+		// The listen address is "<protocol>:<host>:<port>", where
+		// the host may be a bracketed IPv6 address.
+		first := strings.Index(device.Listen, ":")
+		last := strings.LastIndex(device.Listen, ":")
+		if first <= 0 || first == last {
+			logger.Warningf("ignoring proxy device %q with unexpected listen address %q", name, device.Listen)
+			continue
+		}
+		port, err := strconv.Atoi(device.Listen[last+1:])
+		if err != nil {
+			logger.Warningf("ignoring proxy device %q with unexpected listen address %q", name, device.Listen)
+			continue
+		}
+		rules = append(rules, network.NewOpenIngressRule(device.Listen[:first], port, port))
+	}
+	network.SortIngressRules(rules)
+	return rules
+}
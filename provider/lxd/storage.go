@@ -72,6 +72,7 @@ var lxdStorageConfigFields = schema.Fields{
 		schema.Const("dir"),
 		schema.Const("btrfs"),
 		schema.Const("lvm"),
+		schema.Const("ceph"),
 	),
 	attrLXDStoragePool: schema.String(),
 }
@@ -466,7 +467,7 @@ func (s *lxdFilesystemSource) attachFilesystem(
 			Pool:     poolName,
 			ReadOnly: arg.ReadOnly,
 		}
-		if err := s.env.raw.AttachDisk(inst.raw.Name, deviceName, disk); err != nil {
+		if err := s.env.raw.AttachDevice(inst.raw.Name, deviceName, disk); err != nil {
 			return nil, errors.Trace(err)
 		}
 	}
@@ -532,7 +533,7 @@ func (s *lxdFilesystemSource) detachFilesystem(
 	if _, ok := devices[deviceName]; !ok {
 		return nil
 	}
-	return s.env.raw.RemoveDevice(inst.raw.Name, deviceName)
+	return s.env.raw.DetachDevice(inst.raw.Name, deviceName)
 }
 
 // ImportFilesystem is part of the storage.FilesystemImporter interface.
@@ -315,7 +315,7 @@ func (s *storageSuite) TestAttachFilesystems(c *gc.C) {
 		"Instances",
 		[]interface{}{"juju-f75cba-", []string{"Starting", "Started", "Running", "Stopping", "Stopped"}},
 	}, {
-		"AttachDisk",
+		"AttachDevice",
 		[]interface{}{"inst-0", "filesystem-0", lxdclient.DiskDevice{
 			Path:     "/mnt/path",
 			Source:   "filesystem-0",
@@ -374,7 +374,7 @@ func (s *storageSuite) TestDetachFilesystems(c *gc.C) {
 		"Instances",
 		[]interface{}{"juju-f75cba-", []string{"Starting", "Started", "Running", "Stopping", "Stopped"}},
 	}, {
-		"RemoveDevice", []interface{}{"inst-0", "filesystem-0"},
+		"DetachDevice", []interface{}{"inst-0", "filesystem-0"},
 	}})
 }
 
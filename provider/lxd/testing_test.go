@@ -157,6 +157,7 @@ func (s *BaseSuiteUnpatched) initEnv(c *gc.C) {
 		cloud: environs.CloudSpec{
 			Name:       "localhost",
 			Type:       "lxd",
+			Endpoint:   "10.0.8.1",
 			Credential: &certCred,
 		},
 		provider: s.Provider,
@@ -330,6 +331,7 @@ func (s *BaseSuite) SetUpTest(c *gc.C) {
 	s.Client = &StubClient{
 		Stub:               s.Stub,
 		StorageIsSupported: true,
+		ProxyIsSupported:   true,
 		Server: &api.Server{
 			ServerPut: api.ServerPut{
 				Config: map[string]interface{}{},
@@ -476,6 +478,9 @@ type StubClient struct {
 	Server             *api.Server
 	StorageIsSupported bool
 	Volumes            map[string][]api.StorageVolume
+	ProxyIsSupported   bool
+	Proxies            map[string]lxdclient.ProxyDevice
+	HostDevices        lxdclient.HostResources
 }
 
 func (conn *StubClient) Instances(prefix string, statuses ...string) ([]lxdclient.Instance, error) {
@@ -577,8 +582,8 @@ func (conn *StubClient) DefaultProfileBridgeName() string {
 	return "test-bridge"
 }
 
-func (conn *StubClient) CreateProfile(name string, attrs map[string]string) error {
-	conn.AddCall("CreateProfile", name, attrs)
+func (conn *StubClient) CreateProfile(profile lxdclient.Profile) error {
+	conn.AddCall("CreateProfile", profile)
 	return conn.NextErr()
 }
 
@@ -587,16 +592,47 @@ func (conn *StubClient) HasProfile(name string) (bool, error) {
 	return false, conn.NextErr()
 }
 
-func (conn *StubClient) AttachDisk(container, device string, disk lxdclient.DiskDevice) error {
-	conn.AddCall("AttachDisk", container, device, disk)
+func (conn *StubClient) AttachDevice(container, device string, dev lxdclient.InstanceDevice) error {
+	conn.AddCall("AttachDevice", container, device, dev)
 	return conn.NextErr()
 }
 
+func (conn *StubClient) DetachDevice(container, device string) error {
+	conn.AddCall("DetachDevice", container, device)
+	return conn.NextErr()
+}
+
+func (conn *StubClient) ProxySupported() bool {
+	conn.AddCall("ProxySupported")
+	return conn.ProxyIsSupported
+}
+
+func (conn *StubClient) AddProxyDevice(container, device string, proxy lxdclient.ProxyDevice) error {
+	conn.AddCall("AddProxyDevice", container, device, proxy)
+	return conn.NextErr()
+}
+
+func (conn *StubClient) ProxyDevices(container string) (map[string]lxdclient.ProxyDevice, error) {
+	conn.AddCall("ProxyDevices", container)
+	if err := conn.NextErr(); err != nil {
+		return nil, err
+	}
+	return conn.Proxies, nil
+}
+
 func (conn *StubClient) RemoveDevice(container, device string) error {
 	conn.AddCall("RemoveDevice", container, device)
 	return conn.NextErr()
 }
 
+func (conn *StubClient) HostResources() (lxdclient.HostResources, error) {
+	conn.AddCall("HostResources")
+	if err := conn.NextErr(); err != nil {
+		return lxdclient.HostResources{}, errors.Trace(err)
+	}
+	return conn.HostDevices, nil
+}
+
 func (conn *StubClient) StorageSupported() bool {
 	conn.AddCall("StorageSupported")
 	return conn.StorageIsSupported
@@ -20,6 +20,11 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.VirtType,
+	constraints.ImageId,
+	constraints.Gpu,
+	constraints.UsbDevice,
+	constraints.RootDiskSource,
+	constraints.ResourcePool,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -316,6 +316,19 @@ func (env *maasEnviron) getSupportedArchitectures() ([]string, error) {
 	return fetchArchitectures()
 }
 
+// Capabilities is specified on the Environ interface.
+func (env *maasEnviron) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(env)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:      storageKinds,
+		AvailabilityZones: true,
+		FirewallModes:     environs.AllFirewallModes,
+	}, nil
+}
+
 // SupportsSpaces is specified on environs.Networking.
 func (env *maasEnviron) SupportsSpaces() (bool, error) {
 	return true, nil
@@ -323,6 +323,23 @@ var unsupportedConstraints = []string{
 	constraints.InstanceType,
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.ImageId,
+	constraints.Gpu,
+	constraints.UsbDevice,
+	constraints.RootDiskSource,
+	constraints.ResourcePool,
+}
+
+// Capabilities is specified on the Environ interface.
+func (e *manualEnviron) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(e)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:  storageKinds,
+		FirewallModes: environs.AllFirewallModes,
+	}, nil
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -27,8 +27,18 @@ const (
 	CredAttrUserDomainName    = "user-domain-name"
 	CredAttrAccessKey         = "access-key"
 	CredAttrSecretKey         = "secret-key"
+	CredAttrTrustID           = "trust-id"
+
+	CredAttrApplicationCredentialID     = "application-credential-id"
+	CredAttrApplicationCredentialName   = "application-credential-name"
+	CredAttrApplicationCredentialSecret = "application-credential-secret"
 )
 
+// ApplicationCredentialAuthType is the auth-type of credentials that
+// authenticate with a Keystone v3 application credential, rather than
+// a user's password.
+const ApplicationCredentialAuthType cloud.AuthType = "application-credential"
+
 type OpenstackCredentials struct{}
 
 // CredentialSchemas is part of the environs.ProviderCredentials interface.
@@ -59,6 +69,39 @@ func (OpenstackCredentials) CredentialSchemas() map[cloud.AuthType]cloud.Credent
 					Description: "The OpenStack user domain name.",
 					Optional:    true,
 				},
+			}, {
+				CredAttrTrustID, cloud.CredentialAttr{
+					Description: "The ID of a Keystone trust delegating roles in the trustor's project to the user.",
+					Optional:    true,
+				},
+			},
+		},
+		ApplicationCredentialAuthType: {
+			{
+				CredAttrApplicationCredentialID, cloud.CredentialAttr{
+					Description: "The ID of the application credential.",
+					Optional:    true,
+				},
+			}, {
+				CredAttrApplicationCredentialName, cloud.CredentialAttr{
+					Description: "The name of the application credential, if its ID is not specified.",
+					Optional:    true,
+				},
+			}, {
+				CredAttrApplicationCredentialSecret, cloud.CredentialAttr{
+					Description: "The secret of the application credential.",
+					Hidden:      true,
+				},
+			}, {
+				CredAttrUserName, cloud.CredentialAttr{
+					Description: "The user owning the application credential, if its ID is not specified.",
+					Optional:    true,
+				},
+			}, {
+				CredAttrUserDomainName, cloud.CredentialAttr{
+					Description: "The OpenStack domain of the user owning the application credential.",
+					Optional:    true,
+				},
 			},
 		},
 		cloud.AccessKeyAuthType: {
@@ -116,6 +159,9 @@ func (c OpenstackCredentials) DetectCredentials() (*cloud.CloudCredential, error
 
 func (c OpenstackCredentials) detectCredential() (*cloud.Credential, string, string, error) {
 	creds := identity.CredentialsFromEnv()
+	if os.Getenv("OS_APPLICATION_CREDENTIAL_SECRET") != "" {
+		return c.detectApplicationCredential(creds)
+	}
 	if creds.TenantName == "" {
 		return nil, "", "", errors.NewNotFound(nil, "OS_TENANT_NAME environment variable not set")
 	}
@@ -144,6 +190,7 @@ func (c OpenstackCredentials) detectCredential() (*cloud.Credential, string, str
 				CredAttrUserDomainName:    creds.UserDomain,
 				CredAttrProjectDomainName: creds.ProjectDomain,
 				CredAttrDomainName:        creds.Domain,
+				CredAttrTrustID:           os.Getenv("OS_TRUST_ID"),
 			},
 		)
 	} else {
@@ -164,6 +211,37 @@ func (c OpenstackCredentials) detectCredential() (*cloud.Credential, string, str
 	return &credential, user, creds.Region, nil
 }
 
+// detectApplicationCredential returns an application credential using
+// the OS_APPLICATION_CREDENTIAL_* environment variables, as used by the
+// OpenStack command line clients.
+func (c OpenstackCredentials) detectApplicationCredential(creds *identity.Credentials) (*cloud.Credential, string, string, error) {
+	id := os.Getenv("OS_APPLICATION_CREDENTIAL_ID")
+	name := os.Getenv("OS_APPLICATION_CREDENTIAL_NAME")
+	if id == "" && (name == "" || creds.User == "") {
+		return nil, "", "", errors.NewNotFound(nil, "neither OS_APPLICATION_CREDENTIAL_ID nor OS_APPLICATION_CREDENTIAL_NAME and OS_USERNAME environment variables set")
+	}
+	credential := cloud.NewCredential(
+		ApplicationCredentialAuthType,
+		map[string]string{
+			CredAttrApplicationCredentialID:     id,
+			CredAttrApplicationCredentialName:   name,
+			CredAttrApplicationCredentialSecret: os.Getenv("OS_APPLICATION_CREDENTIAL_SECRET"),
+			CredAttrUserName:                    creds.User,
+			CredAttrUserDomainName:              creds.UserDomain,
+		},
+	)
+	label := id
+	if label == "" {
+		label = name
+	}
+	region := creds.Region
+	if region == "" {
+		region = "<unspecified>"
+	}
+	credential.Label = fmt.Sprintf("openstack region %q application credential %q", region, label)
+	return &credential, label, creds.Region, nil
+}
+
 // FinalizeCredential is part of the environs.ProviderCredentials interface.
 func (OpenstackCredentials) FinalizeCredential(_ environs.FinalizeCredentialContext, args environs.FinalizeCredentialParams) (*cloud.Credential, error) {
 	return &args.Credential, nil
@@ -35,7 +35,7 @@ func (s *credentialsSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *credentialsSuite) TestCredentialSchemas(c *gc.C) {
-	envtesting.AssertProviderAuthTypes(c, s.provider, "access-key", "userpass")
+	envtesting.AssertProviderAuthTypes(c, s.provider, "access-key", "userpass", "application-credential")
 }
 
 func (s *credentialsSuite) TestAccessKeyCredentialsValid(c *gc.C) {
@@ -62,6 +62,26 @@ func (s *credentialsSuite) TestUserPassHiddenAttributes(c *gc.C) {
 	envtesting.AssertProviderCredentialsAttributesHidden(c, s.provider, "userpass", "password")
 }
 
+func (s *credentialsSuite) TestUserPassTrustCredentialsValid(c *gc.C) {
+	envtesting.AssertProviderCredentialsValid(c, s.provider, "userpass", map[string]string{
+		"username":    "bob",
+		"password":    "dobbs",
+		"tenant-name": "gary",
+		"trust-id":    "0123456789abcdef",
+	})
+}
+
+func (s *credentialsSuite) TestApplicationCredentialCredentialsValid(c *gc.C) {
+	envtesting.AssertProviderCredentialsValid(c, s.provider, "application-credential", map[string]string{
+		"application-credential-id":     "app-cred-id",
+		"application-credential-secret": "secret",
+	})
+}
+
+func (s *credentialsSuite) TestApplicationCredentialHiddenAttributes(c *gc.C) {
+	envtesting.AssertProviderCredentialsAttributesHidden(c, s.provider, "application-credential", "application-credential-secret")
+}
+
 func (s *credentialsSuite) TestDetectCredentialsNotFound(c *gc.C) {
 	// No environment variables set, so no credentials should be found.
 	_, err := s.provider.DetectCredentials()
@@ -108,6 +128,7 @@ func (s *credentialsSuite) TestDetectCredentialsUserPassEnvironmentVariables(c *
 			"domain-name":         "",
 			"project-domain-name": "",
 			"user-domain-name":    "user-domain",
+			"trust-id":            "",
 		},
 	)
 	expected.Label = `openstack region "west" project "gary" user "bob"`
@@ -133,12 +154,63 @@ func (s *credentialsSuite) TestDetectCredentialsUserPassDefaultDomain(c *gc.C) {
 			"domain-name":         "",
 			"project-domain-name": "default-domain",
 			"user-domain-name":    "default-domain",
+			"trust-id":            "",
 		},
 	)
 	expected.Label = `openstack region "west" project "gary" user "bob"`
 	c.Assert(credentials.AuthCredentials["bob"], jc.DeepEquals, expected)
 }
 
+func (s *credentialsSuite) TestDetectCredentialsUserPassTrust(c *gc.C) {
+	s.PatchEnvironment("USER", "fred")
+	s.PatchEnvironment("OS_PROJECT_NAME", "gary")
+	s.PatchEnvironment("OS_USERNAME", "bob")
+	s.PatchEnvironment("OS_PASSWORD", "dobbs")
+	s.PatchEnvironment("OS_REGION_NAME", "west")
+	s.PatchEnvironment("OS_TRUST_ID", "0123456789abcdef")
+
+	credentials, err := s.provider.DetectCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credentials.AuthCredentials["bob"].Attributes()["trust-id"], gc.Equals, "0123456789abcdef")
+}
+
+func (s *credentialsSuite) TestDetectCredentialsApplicationCredentialEnvironmentVariables(c *gc.C) {
+	s.PatchEnvironment("USER", "fred")
+	s.PatchEnvironment("OS_APPLICATION_CREDENTIAL_ID", "app-cred-id")
+	s.PatchEnvironment("OS_APPLICATION_CREDENTIAL_SECRET", "app-cred-secret")
+	s.PatchEnvironment("OS_REGION_NAME", "west")
+
+	credentials, err := s.provider.DetectCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credentials.DefaultRegion, gc.Equals, "west")
+	expected := cloud.NewCredential(
+		"application-credential", map[string]string{
+			"application-credential-id":     "app-cred-id",
+			"application-credential-name":   "",
+			"application-credential-secret": "app-cred-secret",
+			"username":                      "",
+			"user-domain-name":              "",
+		},
+	)
+	expected.Label = `openstack region "west" application credential "app-cred-id"`
+	c.Assert(credentials.AuthCredentials["app-cred-id"], jc.DeepEquals, expected)
+}
+
+func (s *credentialsSuite) TestDetectCredentialsApplicationCredentialByName(c *gc.C) {
+	s.PatchEnvironment("OS_APPLICATION_CREDENTIAL_NAME", "juju")
+	s.PatchEnvironment("OS_APPLICATION_CREDENTIAL_SECRET", "app-cred-secret")
+
+	_, err := s.provider.DetectCredentials()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	s.PatchEnvironment("OS_USERNAME", "bob")
+	credentials, err := s.provider.DetectCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	attrs := credentials.AuthCredentials["juju"].Attributes()
+	c.Assert(attrs["application-credential-name"], gc.Equals, "juju")
+	c.Assert(attrs["username"], gc.Equals, "bob")
+}
+
 func (s *credentialsSuite) TestDetectCredentialsNovarc(c *gc.C) {
 	if runtime.GOOS != "linux" {
 		c.Skip("not running linux")
@@ -174,6 +246,7 @@ OS_PROJECT_DOMAIN_NAME=project-domain
 			"domain-name":         "",
 			"project-domain-name": "project-domain",
 			"user-domain-name":    "",
+			"trust-id":            "",
 		},
 	)
 	expected.Label = `openstack region "region" project "gary" user "bob"`
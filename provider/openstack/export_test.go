@@ -454,7 +454,7 @@ func SetUpGlobalGroup(e environs.Environ, name string, apiPort int) (neutron.Sec
 	if err := switching.initFirewaller(); err != nil {
 		return neutron.SecurityGroupV2{}, err
 	}
-	return switching.fw.(*neutronFirewaller).setUpGlobalGroup(name, apiPort)
+	return switching.fw.(*neutronFirewaller).setUpGlobalGroup(name, []int{apiPort})
 }
 
 func EnsureGroup(e environs.Environ, name string, rules []neutron.RuleInfoV2) (neutron.SecurityGroupV2, error) {
@@ -70,8 +70,8 @@ type Firewaller interface {
 	GetSecurityGroups(ids ...instance.Id) ([]string, error)
 
 	// SetUpGroups sets up initial security groups, if any, and returns
-	// their names.
-	SetUpGroups(controllerUUID, machineId string, apiPort int) ([]string, error)
+	// their names. The given API ports are opened in the global group.
+	SetUpGroups(controllerUUID, machineId string, apiPorts []int) ([]string, error)
 
 	// OpenInstancePorts opens the given port ranges for the specified  instance.
 	OpenInstancePorts(inst instance.Instance, machineId string, rules []network.IngressRule) error
@@ -177,11 +177,11 @@ func (f *switchingFirewaller) GetSecurityGroups(ids ...instance.Id) ([]string, e
 	return f.fw.GetSecurityGroups(ids...)
 }
 
-func (f *switchingFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort int) ([]string, error) {
+func (f *switchingFirewaller) SetUpGroups(controllerUUID, machineId string, apiPorts []int) ([]string, error) {
 	if err := f.initFirewaller(); err != nil {
 		return nil, errors.Trace(err)
 	}
-	return f.fw.SetUpGroups(controllerUUID, machineId, apiPort)
+	return f.fw.SetUpGroups(controllerUUID, machineId, apiPorts)
 }
 
 func (f *switchingFirewaller) OpenInstancePorts(inst instance.Instance, machineId string, rules []network.IngressRule) error {
@@ -441,8 +441,8 @@ type neutronFirewaller struct {
 // Note: ideally we'd have a better way to determine group membership so that 2
 // people that happen to share an openstack account and name their environment
 // "openstack" don't end up destroying each other's machines.
-func (c *neutronFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort int) ([]string, error) {
-	jujuGroup, err := c.setUpGlobalGroup(c.jujuGroupName(controllerUUID), apiPort)
+func (c *neutronFirewaller) SetUpGroups(controllerUUID, machineId string, apiPorts []int) ([]string, error) {
+	jujuGroup, err := c.setUpGlobalGroup(c.jujuGroupName(controllerUUID), apiPorts)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -463,39 +463,29 @@ func (c *neutronFirewaller) SetUpGroups(controllerUUID, machineId string, apiPor
 	return groups, nil
 }
 
-func (c *neutronFirewaller) setUpGlobalGroup(groupName string, apiPort int) (neutron.SecurityGroupV2, error) {
-	return c.ensureGroup(groupName,
-		[]neutron.RuleInfoV2{
-			{
-				Direction:      "ingress",
-				IPProtocol:     "tcp",
-				PortRangeMax:   22,
-				PortRangeMin:   22,
-				RemoteIPPrefix: "::/0",
-				EthernetType:   "IPv6",
-			},
-			{
+func (c *neutronFirewaller) setUpGlobalGroup(groupName string, apiPorts []int) (neutron.SecurityGroupV2, error) {
+	var rules []neutron.RuleInfoV2
+	for _, port := range append([]int{22}, apiPorts...) {
+		rules = append(rules,
+			neutron.RuleInfoV2{
 				Direction:      "ingress",
 				IPProtocol:     "tcp",
-				PortRangeMax:   22,
-				PortRangeMin:   22,
-				RemoteIPPrefix: "0.0.0.0/0",
-			},
-			{
-				Direction:      "ingress",
-				IPProtocol:     "tcp",
-				PortRangeMax:   apiPort,
-				PortRangeMin:   apiPort,
+				PortRangeMax:   port,
+				PortRangeMin:   port,
 				RemoteIPPrefix: "::/0",
 				EthernetType:   "IPv6",
 			},
-			{
+			neutron.RuleInfoV2{
 				Direction:      "ingress",
 				IPProtocol:     "tcp",
-				PortRangeMax:   apiPort,
-				PortRangeMin:   apiPort,
+				PortRangeMax:   port,
+				PortRangeMin:   port,
 				RemoteIPPrefix: "0.0.0.0/0",
 			},
+		)
+	}
+	return c.ensureGroup(groupName, append(rules,
+		[]neutron.RuleInfoV2{
 			{
 				Direction:    "ingress",
 				IPProtocol:   "tcp",
@@ -531,7 +521,7 @@ func (c *neutronFirewaller) setUpGlobalGroup(groupName string, apiPort int) (neu
 				Direction:  "ingress",
 				IPProtocol: "icmp",
 			},
-		})
+		}...))
 }
 
 // zeroGroup holds the zero security group.
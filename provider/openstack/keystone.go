@@ -0,0 +1,259 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/goose.v2/identity"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+)
+
+// keystoneV3Auth is an identity.Authenticator for the Keystone v3
+// authentication methods that goose does not implement itself:
+// application credentials, and password authentication scoped to a
+// trust. Neither requires the user's password to be given to juju
+// with the authority to act across all of the user's projects.
+type keystoneV3Auth struct {
+	client *http.Client
+
+	// Exactly one of appCred and trustID is set.
+	appCred *applicationCredential
+	trustID string
+}
+
+// applicationCredential holds the details of a Keystone application
+// credential. A credential is identified either by its ID alone, or
+// by its name together with the name of the user that owns it.
+type applicationCredential struct {
+	ID         string
+	Name       string
+	Secret     string
+	User       string
+	UserDomain string
+}
+
+// newKeystoneV3Auth returns the authenticator to use for the given
+// credential, or nil if goose's own authenticators suffice.
+func newKeystoneV3Auth(spec environs.CloudSpec, sslHostnameVerification bool) *keystoneV3Auth {
+	httpClient := utils.GetValidatingHTTPClient()
+	if !sslHostnameVerification {
+		httpClient = utils.GetNonValidatingHTTPClient()
+	}
+	credAttrs := spec.Credential.Attributes()
+	switch spec.Credential.AuthType() {
+	case ApplicationCredentialAuthType:
+		return &keystoneV3Auth{
+			client: httpClient,
+			appCred: &applicationCredential{
+				ID:         credAttrs[CredAttrApplicationCredentialID],
+				Name:       credAttrs[CredAttrApplicationCredentialName],
+				Secret:     credAttrs[CredAttrApplicationCredentialSecret],
+				User:       credAttrs[CredAttrUserName],
+				UserDomain: credAttrs[CredAttrUserDomainName],
+			},
+		}
+	case cloud.UserPassAuthType:
+		if trustID := credAttrs[CredAttrTrustID]; trustID != "" {
+			return &keystoneV3Auth{
+				client:  httpClient,
+				trustID: trustID,
+			}
+		}
+	}
+	return nil
+}
+
+type v3Name struct {
+	Name string `json:"name"`
+}
+
+type v3User struct {
+	ID       string  `json:"id,omitempty"`
+	Name     string  `json:"name,omitempty"`
+	Domain   *v3Name `json:"domain,omitempty"`
+	Password string  `json:"password,omitempty"`
+}
+
+type v3ApplicationCredential struct {
+	ID     string  `json:"id,omitempty"`
+	Name   string  `json:"name,omitempty"`
+	Secret string  `json:"secret"`
+	User   *v3User `json:"user,omitempty"`
+}
+
+type v3Password struct {
+	User v3User `json:"user"`
+}
+
+type v3TrustScope struct {
+	Trust struct {
+		ID string `json:"id"`
+	} `json:"OS-TRUST:trust"`
+}
+
+type v3AuthRequest struct {
+	Auth struct {
+		Identity struct {
+			Methods               []string                 `json:"methods"`
+			Password              *v3Password              `json:"password,omitempty"`
+			ApplicationCredential *v3ApplicationCredential `json:"application_credential,omitempty"`
+		} `json:"identity"`
+		Scope *v3TrustScope `json:"scope,omitempty"`
+	} `json:"auth"`
+}
+
+type v3Endpoint struct {
+	Interface string `json:"interface"`
+	Region    string `json:"region"`
+	RegionID  string `json:"region_id"`
+	URL       string `json:"url"`
+}
+
+type v3AuthResponse struct {
+	Token struct {
+		User struct {
+			ID     string `json:"id"`
+			Domain v3Name `json:"domain"`
+		} `json:"user"`
+		Project struct {
+			ID string `json:"id"`
+		} `json:"project"`
+		Catalog []struct {
+			Type      string       `json:"type"`
+			Endpoints []v3Endpoint `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+// authRequest returns the body of the token request for the given
+// credentials.
+func (a *keystoneV3Auth) authRequest(creds *identity.Credentials) (*v3AuthRequest, error) {
+	var req v3AuthRequest
+	if a.appCred != nil {
+		appCred := &v3ApplicationCredential{Secret: a.appCred.Secret}
+		if a.appCred.ID != "" {
+			appCred.ID = a.appCred.ID
+		} else {
+			if a.appCred.Name == "" || a.appCred.User == "" {
+				return nil, errors.NotValidf("application credential without ID, name or user")
+			}
+			appCred.Name = a.appCred.Name
+			appCred.User = &v3User{Name: a.appCred.User}
+			if a.appCred.UserDomain != "" {
+				appCred.User.Domain = &v3Name{a.appCred.UserDomain}
+			}
+		}
+		req.Auth.Identity.Methods = []string{"application_credential"}
+		req.Auth.Identity.ApplicationCredential = appCred
+		return &req, nil
+	}
+
+	user := v3User{Name: creds.User, Password: creds.Secrets}
+	if domain := creds.UserDomain; domain != "" {
+		user.Domain = &v3Name{domain}
+	} else if creds.Domain != "" {
+		user.Domain = &v3Name{creds.Domain}
+	}
+	req.Auth.Identity.Methods = []string{"password"}
+	req.Auth.Identity.Password = &v3Password{User: user}
+	// A trust-scoped token acts in the trustor's project, with
+	// the roles delegated by the trust; the credential's tenant
+	// is not used.
+	req.Auth.Scope = &v3TrustScope{}
+	req.Auth.Scope.Trust.ID = a.trustID
+	return &req, nil
+}
+
+// tokensURL returns the URL of the Keystone v3 token API, given the
+// identity endpoint in the credentials.
+func tokensURL(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if strings.HasSuffix(endpoint, "/auth/tokens") {
+		return endpoint
+	}
+	if !strings.HasSuffix(endpoint, "/v3") {
+		endpoint += "/v3"
+	}
+	return endpoint + "/auth/tokens"
+}
+
+// Auth is part of the identity.Authenticator interface.
+func (a *keystoneV3Auth) Auth(creds *identity.Credentials) (*identity.AuthDetails, error) {
+	authReq, err := a.authRequest(creds)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	body, err := json.Marshal(authReq)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", tokensURL(creds.URL), bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "requesting token")
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading token response")
+	}
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusOK:
+	case http.StatusUnauthorized:
+		// Application credentials are commonly rotated by deleting
+		// the old one once its replacement is in place, and trusts
+		// may expire or be revoked by the trustor.
+		return nil, errors.Unauthorizedf("keystone rejected credential (it may have expired, been rotated or been revoked)")
+	default:
+		return nil, errors.Errorf("requesting token: %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+
+	token := resp.Header.Get("X-Subject-Token")
+	if token == "" {
+		return nil, errors.New("keystone response did not include a token")
+	}
+	var authResp v3AuthResponse
+	if err := json.Unmarshal(respBody, &authResp); err != nil {
+		return nil, errors.Annotate(err, "decoding token response")
+	}
+	details := &identity.AuthDetails{
+		Token:             token,
+		TenantId:          authResp.Token.Project.ID,
+		UserId:            authResp.Token.User.ID,
+		Domain:            authResp.Token.User.Domain.Name,
+		RegionServiceURLs: make(map[string]identity.ServiceURLs),
+	}
+	for _, service := range authResp.Token.Catalog {
+		for _, ep := range service.Endpoints {
+			if ep.Interface != "public" {
+				continue
+			}
+			region := ep.RegionID
+			if region == "" {
+				region = ep.Region
+			}
+			serviceURLs, ok := details.RegionServiceURLs[region]
+			if !ok {
+				serviceURLs = make(identity.ServiceURLs)
+				details.RegionServiceURLs[region] = serviceURLs
+			}
+			serviceURLs[service.Type] = ep.URL
+		}
+	}
+	return details, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/goose.v2/identity"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+)
+
+type keystoneSuite struct {
+	testing.IsolationSuite
+
+	server   *httptest.Server
+	requests []map[string]interface{}
+	status   int
+}
+
+var _ = gc.Suite(&keystoneSuite{})
+
+const tokenResponse = `{
+  "token": {
+    "user": {"id": "user-id", "domain": {"name": "Default"}},
+    "project": {"id": "project-id"},
+    "catalog": [{
+      "type": "compute",
+      "endpoints": [
+        {"interface": "public", "region_id": "east", "url": "https://nova.east.invalid"},
+        {"interface": "internal", "region_id": "east", "url": "https://nova.internal.invalid"}
+      ]
+    }]
+  }
+}`
+
+func (s *keystoneSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.requests = nil
+	s.status = http.StatusCreated
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.URL.Path, gc.Equals, "/v3/auth/tokens")
+		body, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		var decoded map[string]interface{}
+		c.Check(json.Unmarshal(body, &decoded), jc.ErrorIsNil)
+		s.requests = append(s.requests, decoded)
+		w.Header().Set("X-Subject-Token", "the-token")
+		w.WriteHeader(s.status)
+		w.Write([]byte(tokenResponse))
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+}
+
+func (s *keystoneSuite) auth(c *gc.C, authType cloud.AuthType, attrs map[string]string) *keystoneV3Auth {
+	cred := cloud.NewCredential(authType, attrs)
+	auth := newKeystoneV3Auth(environs.CloudSpec{Credential: &cred}, true)
+	c.Assert(auth, gc.NotNil)
+	return auth
+}
+
+func (s *keystoneSuite) TestNoAuthenticatorForPlainUserPass(c *gc.C) {
+	cred := cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		CredAttrUserName: "bob",
+		CredAttrPassword: "dobbs",
+	})
+	auth := newKeystoneV3Auth(environs.CloudSpec{Credential: &cred}, true)
+	c.Assert(auth, gc.IsNil)
+}
+
+func (s *keystoneSuite) TestApplicationCredentialByID(c *gc.C) {
+	auth := s.auth(c, ApplicationCredentialAuthType, map[string]string{
+		CredAttrApplicationCredentialID:     "app-cred-id",
+		CredAttrApplicationCredentialSecret: "secret",
+	})
+	details, err := auth.Auth(&identity.Credentials{URL: s.server.URL + "/v3/"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details, jc.DeepEquals, &identity.AuthDetails{
+		Token:    "the-token",
+		TenantId: "project-id",
+		UserId:   "user-id",
+		Domain:   "Default",
+		RegionServiceURLs: map[string]identity.ServiceURLs{
+			"east": {"compute": "https://nova.east.invalid"},
+		},
+	})
+	c.Assert(s.requests, jc.DeepEquals, []map[string]interface{}{{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []interface{}{"application_credential"},
+				"application_credential": map[string]interface{}{
+					"id":     "app-cred-id",
+					"secret": "secret",
+				},
+			},
+		},
+	}})
+}
+
+func (s *keystoneSuite) TestApplicationCredentialByName(c *gc.C) {
+	auth := s.auth(c, ApplicationCredentialAuthType, map[string]string{
+		CredAttrApplicationCredentialName:   "juju",
+		CredAttrApplicationCredentialSecret: "secret",
+		CredAttrUserName:                    "bob",
+		CredAttrUserDomainName:              "Default",
+	})
+	_, err := auth.Auth(&identity.Credentials{URL: s.server.URL})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 1)
+	identityReq := s.requests[0]["auth"].(map[string]interface{})["identity"].(map[string]interface{})
+	c.Assert(identityReq["application_credential"], jc.DeepEquals, map[string]interface{}{
+		"name":   "juju",
+		"secret": "secret",
+		"user": map[string]interface{}{
+			"name":   "bob",
+			"domain": map[string]interface{}{"name": "Default"},
+		},
+	})
+}
+
+func (s *keystoneSuite) TestTrust(c *gc.C) {
+	auth := s.auth(c, cloud.UserPassAuthType, map[string]string{
+		CredAttrUserName: "bob",
+		CredAttrPassword: "dobbs",
+		CredAttrTrustID:  "trust-id",
+	})
+	_, err := auth.Auth(&identity.Credentials{
+		URL:        s.server.URL + "/v3",
+		User:       "bob",
+		Secrets:    "dobbs",
+		UserDomain: "Default",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, jc.DeepEquals, []map[string]interface{}{{
+		"auth": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"methods": []interface{}{"password"},
+				"password": map[string]interface{}{
+					"user": map[string]interface{}{
+						"name":     "bob",
+						"password": "dobbs",
+						"domain":   map[string]interface{}{"name": "Default"},
+					},
+				},
+			},
+			"scope": map[string]interface{}{
+				"OS-TRUST:trust": map[string]interface{}{"id": "trust-id"},
+			},
+		},
+	}})
+}
+
+func (s *keystoneSuite) TestRotatedCredentialUnauthorized(c *gc.C) {
+	s.status = http.StatusUnauthorized
+	auth := s.auth(c, ApplicationCredentialAuthType, map[string]string{
+		CredAttrApplicationCredentialID:     "app-cred-id",
+		CredAttrApplicationCredentialSecret: "secret",
+	})
+	_, err := auth.Auth(&identity.Credentials{URL: s.server.URL})
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *keystoneSuite) TestTokensURL(c *gc.C) {
+	for _, endpoint := range []string{
+		"https://keystone.invalid",
+		"https://keystone.invalid/",
+		"https://keystone.invalid/v3",
+		"https://keystone.invalid/v3/",
+		"https://keystone.invalid/v3/auth/tokens",
+	} {
+		c.Check(tokensURL(endpoint), gc.Equals, "https://keystone.invalid/v3/auth/tokens")
+	}
+}
+
+func (s *keystoneSuite) TestValidateCloudSpecApplicationCredential(c *gc.C) {
+	cred := cloud.NewCredential(ApplicationCredentialAuthType, map[string]string{
+		CredAttrApplicationCredentialName:   "juju",
+		CredAttrApplicationCredentialSecret: "secret",
+	})
+	spec := environs.CloudSpec{
+		Type:       "openstack",
+		Name:       "openstack",
+		Endpoint:   "https://keystone.invalid/v3",
+		Credential: &cred,
+	}
+	err := validateCloudSpec(spec)
+	c.Assert(err, gc.ErrorMatches, "application credential without application-credential-id, or application-credential-name and username not valid")
+
+	cred = cloud.NewCredential(ApplicationCredentialAuthType, map[string]string{
+		CredAttrApplicationCredentialID:     "app-cred-id",
+		CredAttrApplicationCredentialSecret: "secret",
+	})
+	err = validateCloudSpec(spec)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -29,8 +29,8 @@ type legacyNovaFirewaller struct {
 // other instances that might be running on the same OpenStack account.
 // In addition, a specific machine security group is created for each
 // machine, so that its firewall rules can be configured per machine.
-func (c *legacyNovaFirewaller) SetUpGroups(controllerUUID, machineId string, apiPort int) ([]string, error) {
-	jujuGroup, err := c.setUpGlobalGroup(c.jujuGroupName(controllerUUID), apiPort)
+func (c *legacyNovaFirewaller) SetUpGroups(controllerUUID, machineId string, apiPorts []int) ([]string, error) {
+	jujuGroup, err := c.setUpGlobalGroup(c.jujuGroupName(controllerUUID), apiPorts)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -51,21 +51,18 @@ func (c *legacyNovaFirewaller) SetUpGroups(controllerUUID, machineId string, api
 	return groupNames, nil
 }
 
-func (c *legacyNovaFirewaller) setUpGlobalGroup(groupName string, apiPort int) (nova.SecurityGroup, error) {
-	return c.ensureGroup(groupName,
+func (c *legacyNovaFirewaller) setUpGlobalGroup(groupName string, apiPorts []int) (nova.SecurityGroup, error) {
+	var rules []nova.RuleInfo
+	for _, port := range append([]int{22}, apiPorts...) {
+		rules = append(rules, nova.RuleInfo{
+			IPProtocol: "tcp",
+			ToPort:     port,
+			FromPort:   port,
+			Cidr:       "0.0.0.0/0",
+		})
+	}
+	return c.ensureGroup(groupName, append(rules,
 		[]nova.RuleInfo{
-			{
-				IPProtocol: "tcp",
-				ToPort:     22,
-				FromPort:   22,
-				Cidr:       "0.0.0.0/0",
-			},
-			{
-				IPProtocol: "tcp",
-				ToPort:     apiPort,
-				FromPort:   apiPort,
-				Cidr:       "0.0.0.0/0",
-			},
 			{
 				IPProtocol: "tcp",
 				FromPort:   1,
@@ -81,7 +78,7 @@ func (c *legacyNovaFirewaller) setUpGlobalGroup(groupName string, apiPort int) (
 				FromPort:   -1,
 				ToPort:     -1,
 			},
-		})
+		}...))
 }
 
 // legacyZeroGroup holds the zero security group.
@@ -97,6 +97,7 @@ var cloudSchema = &jsonschema.Schema{
 					Enum: []interface{}{
 						string(cloud.AccessKeyAuthType),
 						string(cloud.UserPassAuthType),
+						string(ApplicationCredentialAuthType),
 					},
 				}},
 			},
@@ -299,6 +300,7 @@ var _ environs.Environ = (*Environ)(nil)
 var _ simplestreams.HasRegion = (*Environ)(nil)
 var _ instance.Distributor = (*Environ)(nil)
 var _ environs.InstanceTagger = (*Environ)(nil)
+var _ environs.CloudSpecSetter = (*Environ)(nil)
 
 type openstackInstance struct {
 	e        *Environ
@@ -493,6 +495,23 @@ func (e *Environ) neutron() *neutron.Client {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.Gpu,
+	constraints.UsbDevice,
+	constraints.RootDiskSource,
+	constraints.ResourcePool,
+}
+
+// Capabilities is specified on the Environ interface.
+func (e *Environ) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(e)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:      storageKinds,
+		AvailabilityZones: true,
+		FirewallModes:     environs.AllFirewallModes,
+	}, nil
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -721,6 +740,13 @@ func newCredentials(spec environs.CloudSpec) (identity.Credentials, identity.Aut
 		cred.User = credAttrs[CredAttrAccessKey]
 		cred.Secrets = credAttrs[CredAttrSecretKey]
 		authMode = identity.AuthKeyPair
+	case ApplicationCredentialAuthType:
+		// Application credentials are scoped to the project they
+		// were created in, and are authenticated by keystoneV3Auth.
+		cred.TenantName = ""
+		cred.User = credAttrs[CredAttrUserName]
+		cred.UserDomain = credAttrs[CredAttrUserDomainName]
+		authMode = identity.AuthUserPassV3
 	}
 	return cred, authMode
 }
@@ -756,6 +782,19 @@ func authClient(spec environs.CloudSpec, ecfg *environConfig) (client.Authentica
 	cred, authMode := newCredentials(spec)
 
 	gooseLogger := gooselogging.LoggoLogger{loggo.GetLogger("goose")}
+	if auth := newKeystoneV3Auth(spec, ecfg.SSLHostnameVerification()); auth != nil {
+		// Application credentials and trusts are only supported by
+		// Keystone v3, so there's no need to determine the best
+		// client.
+		newClient := client.NewClientWithAuthenticator
+		if ecfg.SSLHostnameVerification() == false {
+			newClient = client.NewNonValidatingClientWithAuthenticator
+		}
+		client := newClient(&cred, auth, gooseLogger)
+		client.SetRequiredServiceTypes([]string{"compute"})
+		return client, nil
+	}
+
 	newClient := client.NewClient
 	if ecfg.SSLHostnameVerification() == false {
 		newClient = client.NewNonValidatingClient
@@ -820,6 +859,28 @@ func (e *Environ) SetConfig(cfg *config.Config) error {
 	return nil
 }
 
+// SetCloudSpec replaces the cloud spec used by the environ, so that
+// rotated credentials take effect without reopening the environ.
+// Application credentials and trusts are typically rotated by creating
+// a replacement and deleting the original, after which any client
+// using the original can no longer authenticate.
+func (e *Environ) SetCloudSpec(spec environs.CloudSpec) error {
+	if err := validateCloudSpec(spec); err != nil {
+		return errors.Annotate(err, "validating cloud spec")
+	}
+	e.ecfgMutex.Lock()
+	defer e.ecfgMutex.Unlock()
+	client, err := authClient(spec, e.ecfgUnlocked)
+	if err != nil {
+		return errors.Annotate(err, "cannot set cloud spec")
+	}
+	e.cloud = spec
+	e.clientUnlocked = client
+	e.novaUnlocked = nova.New(e.clientUnlocked)
+	e.neutronUnlocked = neutron.New(e.clientUnlocked)
+	return nil
+}
+
 func identityClientVersion(authURL string) (int, error) {
 	url, err := url.Parse(authURL)
 	if err != nil {
@@ -1012,14 +1073,7 @@ func (e *Environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 
 	var novaGroupNames = []nova.SecurityGroupName{}
 	if createSecurityGroups {
-		var apiPort int
-		if args.InstanceConfig.Controller != nil {
-			apiPort = args.InstanceConfig.Controller.Config.APIPort()
-		} else {
-			// All ports are the same so pick the first.
-			apiPort = args.InstanceConfig.APIInfo.Ports()[0]
-		}
-		groupNames, err := e.firewaller.SetUpGroups(args.ControllerUUID, args.InstanceConfig.MachineId, apiPort)
+		groupNames, err := e.firewaller.SetUpGroups(args.ControllerUUID, args.InstanceConfig.MachineId, args.InstanceConfig.APIPorts())
 		if err != nil {
 			return nil, errors.Annotate(err, "cannot set up groups")
 		}
@@ -1727,9 +1781,20 @@ func validateCloudSpec(spec environs.CloudSpec) error {
 	if spec.Credential == nil {
 		return errors.NotValidf("missing credential")
 	}
+	credAttrs := spec.Credential.Attributes()
 	switch authType := spec.Credential.AuthType(); authType {
 	case cloud.UserPassAuthType:
 	case cloud.AccessKeyAuthType:
+	case ApplicationCredentialAuthType:
+		if credAttrs[CredAttrApplicationCredentialID] == "" {
+			if credAttrs[CredAttrApplicationCredentialName] == "" || credAttrs[CredAttrUserName] == "" {
+				return errors.NotValidf("application credential without %s, or %s and %s",
+					CredAttrApplicationCredentialID,
+					CredAttrApplicationCredentialName,
+					CredAttrUserName,
+				)
+			}
+		}
 	default:
 		return errors.NotSupportedf("%q auth-type", authType)
 	}
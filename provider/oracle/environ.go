@@ -395,20 +395,16 @@ func (o *OracleEnviron) StartInstance(args environs.StartInstanceParams) (*envir
 	}
 	tags = append(tags, machineName)
 
-	var apiPort int
 	var desiredStatus ociCommon.InstanceState
 	if args.InstanceConfig.Controller != nil {
-		apiPort = args.InstanceConfig.Controller.Config.APIPort()
 		desiredStatus = ociCommon.StateRunning
 	} else {
-		// All ports are the same so pick the first.
-		apiPort = args.InstanceConfig.APIInfo.Ports()[0]
 		desiredStatus = ociCommon.StateStarting
 	}
 
 	// create a new seclists
 	secLists, err := o.CreateMachineSecLists(
-		args.InstanceConfig.MachineId, apiPort)
+		args.InstanceConfig.MachineId, args.InstanceConfig.APIPorts())
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -651,6 +647,24 @@ func (o *OracleEnviron) Config() *config.Config {
 	return o.cfg
 }
 
+// Capabilities is part of the environs.Environ interface.
+func (o *OracleEnviron) Capabilities() (environs.Capabilities, error) {
+	spaces, err := o.SupportsSpaces()
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	storageKinds, err := environs.StorageKinds(o)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		Spaces:            spaces,
+		StorageKinds:      storageKinds,
+		AvailabilityZones: true,
+		FirewallModes:     environs.AllFirewallModes,
+	}, nil
+}
+
 // ConstraintsValidator is part of the environs.Environ interface.
 func (o *OracleEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	// list of unsupported oracle provider constraints
@@ -659,6 +673,10 @@ func (o *OracleEnviron) ConstraintsValidator() (constraints.Validator, error) {
 		constraints.CpuPower,
 		constraints.RootDisk,
 		constraints.VirtType,
+		constraints.Gpu,
+		constraints.UsbDevice,
+		constraints.RootDiskSource,
+		constraints.ResourcePool,
 	}
 
 	// we choose to use the default validator implementation
@@ -45,7 +45,7 @@ type Firewaller interface {
 	// sec list is also present, and has the appropriate default rules.
 	// The port parameter is the API port for the state machine, for which we need
 	// to create rules.
-	CreateMachineSecLists(id string, port int) ([]string, error)
+	CreateMachineSecLists(id string, apiPorts []int) ([]string, error)
 
 	// DeleteMachineSecList will delete the security list on the given machine
 	// id
@@ -160,10 +160,10 @@ func (f Firewall) ClosePortsOnInstance(machineId string, rules []network.Ingress
 // CreateMachineSecLists creates a security list for the given instance.
 // It's worth noting that this function also ensures that the default environment
 // sec list is also present, and has the appropriate default rules.
-// The port parameter is the API port for the state machine, for which we need
-// to create rules.
-func (f Firewall) CreateMachineSecLists(machineId string, apiPort int) ([]string, error) {
-	defaultSecList, err := f.createDefaultGroupAndRules(apiPort)
+// The apiPorts parameter holds the API ports for the state machine, for which
+// we need to create rules.
+func (f Firewall) CreateMachineSecLists(machineId string, apiPorts []int) ([]string, error) {
+	defaultSecList, err := f.createDefaultGroupAndRules(apiPorts)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -305,50 +305,24 @@ func (f Firewall) GlobalIngressRules() ([]network.IngressRule, error) {
 	return f.getIngressRules(f.client.ComposeName(seclist))
 }
 
-// getDefaultIngressRules will create the default ingressRules given an api port
-func (f Firewall) getDefaultIngressRules(apiPort int) []network.IngressRule {
-	return []network.IngressRule{
-		network.IngressRule{
+// getDefaultIngressRules will create the default ingressRules given the api ports
+func (f Firewall) getDefaultIngressRules(apiPorts []int) []network.IngressRule {
+	ports := append([]int{22, 3389}, apiPorts...)
+	ports = append(ports, controller.DefaultStatePort)
+	rules := make([]network.IngressRule, len(ports))
+	for i, port := range ports {
+		rules[i] = network.IngressRule{
 			PortRange: network.PortRange{
-				FromPort: 22,
-				ToPort:   22,
+				FromPort: port,
+				ToPort:   port,
 				Protocol: "tcp",
 			},
 			SourceCIDRs: []string{
 				"0.0.0.0/0",
 			},
-		},
-		network.IngressRule{
-			PortRange: network.PortRange{
-				FromPort: 3389,
-				ToPort:   3389,
-				Protocol: "tcp",
-			},
-			SourceCIDRs: []string{
-				"0.0.0.0/0",
-			},
-		},
-		network.IngressRule{
-			PortRange: network.PortRange{
-				FromPort: apiPort,
-				ToPort:   apiPort,
-				Protocol: "tcp",
-			},
-			SourceCIDRs: []string{
-				"0.0.0.0/0",
-			},
-		},
-		network.IngressRule{
-			PortRange: network.PortRange{
-				FromPort: controller.DefaultStatePort,
-				ToPort:   controller.DefaultStatePort,
-				Protocol: "tcp",
-			},
-			SourceCIDRs: []string{
-				"0.0.0.0/0",
-			},
-		},
+		}
 	}
+	return rules
 }
 
 type stubSecurityRule struct {
@@ -359,8 +333,8 @@ type stubSecurityRule struct {
 	SrcIpAddressPrefixSets []string
 }
 
-func (f Firewall) createDefaultGroupAndRules(apiPort int) (response.SecList, error) {
-	rules := f.getDefaultIngressRules(apiPort)
+func (f Firewall) createDefaultGroupAndRules(apiPorts []int) (response.SecList, error) {
+	rules := f.getDefaultIngressRules(apiPorts)
 	var details response.SecList
 	var err error
 	globalGroupName := f.globalGroupName()
@@ -762,7 +762,7 @@ func (f *firewallSuite) TestCreateMachineSecLists(c *gc.C) {
 	firewall := network.NewFirewall(cfg, providertest.DefaultFakeFirewallAPI, &advancingClock)
 	c.Assert(firewall, gc.NotNil)
 
-	lists, err := firewall.CreateMachineSecLists("0", 7070)
+	lists, err := firewall.CreateMachineSecLists("0", []int{7070})
 	c.Assert(err, gc.IsNil)
 	c.Assert(lists, gc.NotNil)
 }
@@ -820,7 +820,7 @@ func (f *firewallSuite) TestCreateMachineSecListsWithErrors(c *gc.C) {
 		firewall := network.NewFirewall(cfg, fake, &advancingClock)
 		c.Assert(firewall, gc.NotNil)
 
-		_, err := firewall.CreateMachineSecLists("0", 7070)
+		_, err := firewall.CreateMachineSecLists("0", []int{7070})
 		c.Assert(err, gc.NotNil)
 	}
 }
@@ -67,11 +67,11 @@ func (e environ) StartInstance(args environs.StartInstanceParams) (*environs.Sta
 			return nil, errors.Trace(err)
 		}
 		client := newInstanceConfigurator(addr)
-		apiPort := 0
+		exceptPorts := []int{22}
 		if args.InstanceConfig.Controller != nil {
-			apiPort = args.InstanceConfig.Controller.Config.APIPort()
+			exceptPorts = append(exceptPorts, args.InstanceConfig.APIPorts()...)
 		}
-		err = client.DropAllPorts([]int{apiPort, 22}, addr)
+		err = client.DropAllPorts(exceptPorts, addr)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
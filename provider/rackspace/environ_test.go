@@ -233,6 +233,10 @@ func (e *fakeEnviron) InstanceTypes(constraints.Value) (instances.InstanceTypesW
 	return instances.InstanceTypesWithCostMetadata{}, nil
 }
 
+func (e *fakeEnviron) Capabilities() (environs.Capabilities, error) {
+	return environs.Capabilities{}, nil
+}
+
 type fakeConfigurator struct {
 	methodCalls []methodCall
 }
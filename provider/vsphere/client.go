@@ -22,11 +22,13 @@ type Client interface {
 	Close(context.Context) error
 	ComputeResources(context.Context) ([]*mo.ComputeResource, error)
 	CreateVirtualMachine(context.Context, vsphereclient.CreateVirtualMachineParams) (*mo.VirtualMachine, error)
+	Datastores(context.Context, *mo.ComputeResource) ([]*mo.Datastore, error)
 	DestroyVMFolder(context.Context, string) error
 	EnsureVMFolder(context.Context, string) error
 	MoveVMFolderInto(context.Context, string, string) error
 	MoveVMsInto(context.Context, string, ...types.ManagedObjectReference) error
 	RemoveVirtualMachines(context.Context, string) error
+	ResourcePools(context.Context, *mo.ComputeResource) (map[string]types.ManagedObjectReference, error)
 	UpdateVirtualMachineExtraConfig(context.Context, *mo.VirtualMachine, map[string]string) error
 	VirtualMachines(context.Context, string) ([]*mo.VirtualMachine, error)
 }
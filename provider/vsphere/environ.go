@@ -205,12 +205,14 @@ func (env *sessionEnviron) DestroyController(controllerUUID string) error {
 		return errors.Trace(err)
 	}
 	controllerFolderName := controllerFolderName(controllerUUID)
-	if err := env.client.RemoveVirtualMachines(env.ctx, path.Join(
-		controllerFolderName,
-		modelFolderName("*", "*"),
-		"*",
-	)); err != nil {
-		return errors.Annotate(err, "removing VMs")
+	modelFoldersPath := path.Join(controllerFolderName, modelFolderName("*", "*"))
+	for _, vmPath := range []string{
+		path.Join(modelFoldersPath, "*"),
+		path.Join(modelFoldersPath, "*", "*"),
+	} {
+		if err := env.client.RemoveVirtualMachines(env.ctx, vmPath); err != nil {
+			return errors.Annotate(err, "removing VMs")
+		}
 	}
 	return env.client.DestroyVMFolder(env.ctx, controllerFolderName)
 }
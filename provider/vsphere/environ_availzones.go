@@ -107,19 +107,17 @@ func (env *sessionEnviron) availZone(name string) (common.AvailabilityZone, erro
 }
 
 // parseAvailabilityZones returns the availability zones that should be
-// tried for the given instance spec. If a placement argument was
-// provided then only that one is returned. Otherwise the environment is
+// tried for the given instance spec. If the placement selects a zone
+// then only that one is returned. Otherwise the environment is
 // queried for available zones. In that case, the resulting list is
 // roughly ordered such that the environment's instances are spread
 // evenly across the region.
-func (env *sessionEnviron) parseAvailabilityZones(args environs.StartInstanceParams) ([]string, error) {
-	if args.Placement != "" {
-		// args.Placement will always be a zone name or empty.
-		placement, err := env.parsePlacement(args.Placement)
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
-		return []string{placement.Name()}, nil
+func (env *sessionEnviron) parseAvailabilityZones(
+	args environs.StartInstanceParams,
+	placement *vmwarePlacement,
+) ([]string, error) {
+	if placement.zone != nil {
+		return []string{placement.zone.Name()}, nil
 	}
 
 	// If no availability zone is specified, then automatically spread across
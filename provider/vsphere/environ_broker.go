@@ -133,11 +133,11 @@ func (env *sessionEnviron) newRawInstance(
 	// necessary to configure it.
 	externalNetwork := env.ecfg.externalNetwork()
 	if externalNetwork != "" {
-		apiPort := 0
+		var apiPorts []int
 		if args.InstanceConfig.Controller != nil {
-			apiPort = args.InstanceConfig.Controller.Config.APIPort()
+			apiPorts = args.InstanceConfig.APIPorts()
 		}
-		commands := common.ConfigureExternalIpAddressCommands(apiPort)
+		commands := common.ConfigureExternalIpAddressCommands(apiPorts...)
 		cloudcfg.AddBootCmd(commands...)
 	}
 
@@ -156,7 +156,12 @@ func (env *sessionEnviron) newRawInstance(
 
 	// Identify which zones may be used, taking into
 	// account placement directives.
-	zones, err := env.parseAvailabilityZones(args)
+	placement, err := env.parsePlacement(args.Placement)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	placement.applyConstraints(cons)
+	zones, err := env.parseAvailabilityZones(args, placement)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
@@ -176,12 +181,24 @@ func (env *sessionEnviron) newRawInstance(
 	}
 	defer ovaCleanup()
 
+	folder := path.Join(
+		controllerFolderName(args.ControllerUUID),
+		env.modelFolderName(),
+	)
+	if placement.folder != "" {
+		folder = path.Join(folder, placement.folder)
+		if err := env.client.EnsureVMFolder(env.ctx, folder); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	datastore := env.ecfg.datastore()
+	if placement.datastore != "" {
+		datastore = placement.datastore
+	}
+
 	createVMArgs := vsphereclient.CreateVirtualMachineParams{
-		Name: vmName,
-		Folder: path.Join(
-			controllerFolderName(args.ControllerUUID),
-			env.modelFolderName(),
-		),
+		Name:                   vmName,
+		Folder:                 folder,
 		OVADir:                 ovaDir,
 		OVF:                    string(ovf),
 		UserData:               string(userData),
@@ -189,7 +206,7 @@ func (env *sessionEnviron) newRawInstance(
 		Constraints:            cons,
 		PrimaryNetwork:         env.ecfg.primaryNetwork(),
 		ExternalNetwork:        externalNetwork,
-		Datastore:              env.ecfg.datastore(),
+		Datastore:              datastore,
 		UpdateProgress:         updateProgress,
 		UpdateProgressInterval: updateProgressInterval,
 		Clock: clock.WallClock,
@@ -207,6 +224,14 @@ func (env *sessionEnviron) newRawInstance(
 			continue
 		}
 		createVMArgs.ComputeResource = &availZone.(*vmwareAvailZone).r
+		createVMArgs.ResourcePool, err = env.placementResourcePool(
+			availZone.(*vmwareAvailZone), placement,
+		)
+		if err != nil {
+			logger.Warningf("cannot place instance in availability zone %s: %s", zone, err)
+			lastError = err
+			continue
+		}
 
 		vm, err = env.client.CreateVirtualMachine(env.ctx, createVMArgs)
 		if err != nil {
@@ -296,22 +321,21 @@ func (env *environ) AllInstances() (instances []instance.Instance, err error) {
 
 // AllInstances implements environs.InstanceBroker.
 func (env *sessionEnviron) AllInstances() ([]instance.Instance, error) {
-	modelFolderPath := path.Join(
-		controllerFolderName("*"),
-		env.modelFolderName(),
-	)
-	vms, err := env.client.VirtualMachines(env.ctx, modelFolderPath+"/*")
-	if err != nil {
-		return nil, errors.Trace(err)
+	var vms []*mo.VirtualMachine
+	for _, vmPath := range env.modelVMPaths("*") {
+		found, err := env.client.VirtualMachines(env.ctx, vmPath)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		vms = append(vms, found...)
 	}
 
-	// Turn mo.VirtualMachine values into *environInstance values,
-	// whether or not we got an error.
+	// Turn mo.VirtualMachine values into *environInstance values.
 	results := make([]instance.Instance, len(vms))
 	for i, vm := range vms {
 		results[i] = newInstance(vm, env.environ)
 	}
-	return results, err
+	return results, nil
 }
 
 // StopInstances implements environs.InstanceBroker.
@@ -323,20 +347,18 @@ func (env *environ) StopInstances(ids ...instance.Id) error {
 
 // StopInstances implements environs.InstanceBroker.
 func (env *sessionEnviron) StopInstances(ids ...instance.Id) error {
-	modelFolderPath := path.Join(
-		controllerFolderName("*"),
-		env.modelFolderName(),
-	)
 	results := make([]error, len(ids))
 	var wg sync.WaitGroup
 	for i, id := range ids {
 		wg.Add(1)
 		go func(i int, id instance.Id) {
 			defer wg.Done()
-			results[i] = env.client.RemoveVirtualMachines(
-				env.ctx,
-				path.Join(modelFolderPath, string(id)),
-			)
+			for _, vmPath := range env.modelVMPaths(string(id)) {
+				if err := env.client.RemoveVirtualMachines(env.ctx, vmPath); err != nil {
+					results[i] = err
+					return
+				}
+			}
 		}(i, id)
 	}
 	wg.Wait()
@@ -361,3 +383,17 @@ func (env *sessionEnviron) StopInstances(ids ...instance.Id) error {
 		)
 	}
 }
+
+// modelVMPaths returns the paths of the model's VMs with the given
+// name, which may be a pattern: those in the model's VM folder, and
+// those in the subfolders created for folder placement directives.
+func (env *sessionEnviron) modelVMPaths(name string) []string {
+	modelFolderPath := path.Join(
+		controllerFolderName("*"),
+		env.modelFolderName(),
+	)
+	return []string{
+		path.Join(modelFolderPath, name),
+		path.Join(modelFolderPath, "*", name),
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/utils/arch"
 	"github.com/juju/version"
 	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
@@ -355,6 +356,102 @@ func (s *environBrokerSuite) TestStartInstanceSelectZone(c *gc.C) {
 	c.Assert(createVMArgs.ComputeResource, jc.DeepEquals, s.client.computeResources[1])
 }
 
+func (s *environBrokerSuite) TestStartInstancePlacementDatastoreResourcePoolFolder(c *gc.C) {
+	s.client.datastores = []*mo.Datastore{
+		newDatastore("ds0", true),
+		newDatastore("ds1", true),
+	}
+	pool := types.ManagedObjectReference{Type: "ResourcePool", Value: "rp-child"}
+	s.client.resourcePools = map[string]types.ManagedObjectReference{
+		"parent/child": pool,
+	}
+	startInstArgs := s.createStartInstanceArgs(c)
+	startInstArgs.Placement = "zone=z2,datastore=ds1,resource-pool=parent/child,folder=web"
+	_, err := s.env.StartInstance(startInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.client.CheckCallNames(c,
+		"ComputeResources", "EnsureVMFolder", "Datastores",
+		"ResourcePools", "CreateVirtualMachine", "Close",
+	)
+	folder := path.Join(
+		"Juju Controller ("+startInstArgs.ControllerUUID+")",
+		`Model "testenv" (2d02eeac-9dbb-11e4-89d3-123b93f75cba)`,
+		"web",
+	)
+	c.Assert(s.client.Calls()[1].Args[1], gc.Equals, folder)
+	c.Assert(s.client.Calls()[2].Args[1], gc.Equals, s.client.computeResources[1])
+
+	createVMArgs := s.client.Calls()[4].Args[1].(vsphereclient.CreateVirtualMachineParams)
+	c.Assert(createVMArgs.ComputeResource, jc.DeepEquals, s.client.computeResources[1])
+	c.Assert(createVMArgs.Datastore, gc.Equals, "ds1")
+	c.Assert(createVMArgs.ResourcePool, jc.DeepEquals, &pool)
+	c.Assert(createVMArgs.Folder, gc.Equals, folder)
+}
+
+func (s *environBrokerSuite) TestStartInstancePlacementDatastoreTriesAllZones(c *gc.C) {
+	s.client.SetErrors(nil, errors.New("datastore listing failed"))
+	s.client.datastores = []*mo.Datastore{newDatastore("ds0", true)}
+	startInstArgs := s.createStartInstanceArgs(c)
+	startInstArgs.Placement = "datastore=ds0"
+	_, err := s.env.StartInstance(startInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.client.CheckCallNames(c,
+		"ComputeResources", "Datastores", "Datastores",
+		"CreateVirtualMachine", "Close",
+	)
+	createVMArgs := s.client.Calls()[3].Args[1].(vsphereclient.CreateVirtualMachineParams)
+	c.Assert(createVMArgs.ComputeResource, jc.DeepEquals, s.client.computeResources[1])
+	c.Assert(createVMArgs.Datastore, gc.Equals, "ds0")
+	c.Assert(createVMArgs.ResourcePool, gc.IsNil)
+}
+
+func (s *environBrokerSuite) TestStartInstancePlacementDatastoreNotFound(c *gc.C) {
+	s.client.datastores = []*mo.Datastore{newDatastore("ds0", false)}
+	startInstArgs := s.createStartInstanceArgs(c)
+	startInstArgs.Placement = "zone=z1,datastore=ds0"
+	_, err := s.env.StartInstance(startInstArgs)
+	c.Assert(err, gc.ErrorMatches, `failed to create instance in any availability zone: accessible datastore "ds0" in availability zone "z1" not found`)
+	s.client.CheckCallNames(c, "ComputeResources", "Datastores", "Close")
+}
+
+func (s *environBrokerSuite) TestStartInstanceRootDiskSourceResourcePoolConstraints(c *gc.C) {
+	s.client.datastores = []*mo.Datastore{newDatastore("ds1", true)}
+	pool := types.ManagedObjectReference{Type: "ResourcePool", Value: "rp-child"}
+	s.client.resourcePools = map[string]types.ManagedObjectReference{
+		"parent/child": pool,
+	}
+	startInstArgs := s.createStartInstanceArgs(c)
+	startInstArgs.Constraints = constraints.MustParse("root-disk-source=ds1 resource-pool=/parent/child")
+	_, err := s.env.StartInstance(startInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.client.CheckCallNames(c,
+		"ComputeResources", "Datastores", "ResourcePools",
+		"CreateVirtualMachine", "Close",
+	)
+	createVMArgs := s.client.Calls()[3].Args[1].(vsphereclient.CreateVirtualMachineParams)
+	c.Assert(createVMArgs.Datastore, gc.Equals, "ds1")
+	c.Assert(createVMArgs.ResourcePool, jc.DeepEquals, &pool)
+}
+
+func (s *environBrokerSuite) TestStartInstancePlacementOverridesRootDiskSource(c *gc.C) {
+	s.client.datastores = []*mo.Datastore{
+		newDatastore("ds0", true),
+		newDatastore("ds1", true),
+	}
+	startInstArgs := s.createStartInstanceArgs(c)
+	startInstArgs.Placement = "datastore=ds1"
+	startInstArgs.Constraints = constraints.MustParse("root-disk-source=ds0")
+	_, err := s.env.StartInstance(startInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.client.CheckCallNames(c, "ComputeResources", "Datastores", "CreateVirtualMachine", "Close")
+	createVMArgs := s.client.Calls()[2].Args[1].(vsphereclient.CreateVirtualMachineParams)
+	c.Assert(createVMArgs.Datastore, gc.Equals, "ds1")
+}
+
 func (s *environBrokerSuite) TestStartInstanceCallsAvailabilityZoneAllocations(c *gc.C) {
 	startInstArgs := s.createStartInstanceArgs(c)
 	startInstArgs.DistributionGroup = func() ([]instance.Id, error) {
@@ -368,8 +465,8 @@ func (s *environBrokerSuite) TestStartInstanceCallsAvailabilityZoneAllocations(c
 	_, err := s.env.StartInstance(startInstArgs)
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.client.CheckCallNames(c, "ComputeResources", "VirtualMachines", "CreateVirtualMachine", "Close")
-	call := s.client.Calls()[2]
+	s.client.CheckCallNames(c, "ComputeResources", "VirtualMachines", "VirtualMachines", "CreateVirtualMachine", "Close")
+	call := s.client.Calls()[3]
 	createVMArgs := call.Args[1].(vsphereclient.CreateVirtualMachineParams)
 
 	// Because the old VM is allocated to the first compute resource,
@@ -414,8 +511,12 @@ func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	var paths []string
-	s.client.CheckCallNames(c, "RemoveVirtualMachines", "RemoveVirtualMachines", "Close")
-	for i := 0; i < 2; i++ {
+	s.client.CheckCallNames(c,
+		"RemoveVirtualMachines", "RemoveVirtualMachines",
+		"RemoveVirtualMachines", "RemoveVirtualMachines",
+		"Close",
+	)
+	for i := 0; i < 4; i++ {
 		args := s.client.Calls()[i].Args
 		paths = append(paths, args[1].(string))
 	}
@@ -424,7 +525,9 @@ func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
 	// we run the RemoveVirtualMachines calls concurrently.
 	c.Assert(paths, jc.SameContents, []string{
 		`Juju Controller (*)/Model "testenv" (2d02eeac-9dbb-11e4-89d3-123b93f75cba)/vm-0`,
+		`Juju Controller (*)/Model "testenv" (2d02eeac-9dbb-11e4-89d3-123b93f75cba)/*/vm-0`,
 		`Juju Controller (*)/Model "testenv" (2d02eeac-9dbb-11e4-89d3-123b93f75cba)/vm-1`,
+		`Juju Controller (*)/Model "testenv" (2d02eeac-9dbb-11e4-89d3-123b93f75cba)/*/vm-1`,
 	})
 }
 
@@ -432,7 +535,9 @@ func (s *environBrokerSuite) TestStopInstancesOneFailure(c *gc.C) {
 	s.client.SetErrors(errors.New("bah"))
 	err := s.env.StopInstances("vm-0", "vm-1")
 
-	s.client.CheckCallNames(c, "RemoveVirtualMachines", "RemoveVirtualMachines", "Close")
+	// The instance whose removal failed is not looked for in the
+	// model's subfolders.
+	s.client.CheckCallNames(c, "RemoveVirtualMachines", "RemoveVirtualMachines", "RemoveVirtualMachines", "Close")
 	vmName := path.Base(s.client.Calls()[0].Args[1].(string))
 	c.Assert(err, gc.ErrorMatches, fmt.Sprintf("failed to stop instance %s: bah", vmName))
 }
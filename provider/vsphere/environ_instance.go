@@ -7,10 +7,13 @@ import (
 	"strings"
 
 	"github.com/juju/errors"
+	"github.com/vmware/govmomi/vim25/types"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
 )
 
 // Instances is part of the environs.Environ interface.
@@ -98,28 +101,159 @@ func (env *sessionEnviron) ControllerInstances(controllerUUID string) ([]instanc
 	return results, nil
 }
 
-// parsePlacement extracts the availability zone from the placement
-// string and returns it. If no zone is found there then an error is
-// returned.
-func (env *sessionEnviron) parsePlacement(placement string) (*vmwareAvailZone, error) {
+// vmwarePlacement holds the placement directives for an instance.
+type vmwarePlacement struct {
+	// zone is the availability zone in which to create the instance.
+	// If this is nil, any zone may be used.
+	zone *vmwareAvailZone
+
+	// datastore is the name of the datastore in which to create the
+	// instance, overriding the model's "datastore" config.
+	datastore string
+
+	// resourcePool is the path of the resource pool in which to create
+	// the instance, relative to the zone's root resource pool.
+	resourcePool string
+
+	// folder is the name of the folder, within the model's VM folder,
+	// in which to create the instance.
+	folder string
+}
+
+// parsePlacement parses the placement string, which holds one or more
+// comma-separated directives, such as "zone=z1,datastore=ds1". The
+// directives "zone", "datastore", "resource-pool" and "folder" are
+// recognised, and the availability zone is checked to exist. The
+// datastore and resource pool are checked by checkPlacement.
+func (env *sessionEnviron) parsePlacement(placement string) (*vmwarePlacement, error) {
+	var result vmwarePlacement
 	if placement == "" {
-		return nil, nil
+		return &result, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, directive := range strings.Split(placement, ",") {
+		pos := strings.IndexRune(directive, '=')
+		if pos == -1 {
+			return nil, errors.Errorf("unknown placement directive: %v", directive)
+		}
+		key, value := directive[:pos], directive[pos+1:]
+		if seen[key] {
+			return nil, errors.Errorf("duplicate placement directive: %v", key)
+		}
+		seen[key] = true
+		if value == "" {
+			return nil, errors.Errorf("empty placement directive: %v", key)
+		}
+
+		switch key {
+		case "zone":
+			zone, err := env.availZone(value)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			result.zone = zone.(*vmwareAvailZone)
+		case "datastore":
+			result.datastore = value
+		case "resource-pool":
+			result.resourcePool = strings.Trim(value, "/")
+		case "folder":
+			if value == "." || value == ".." || strings.ContainsAny(value, `/\%`) {
+				return nil, errors.NotValidf("folder %q", value)
+			}
+			result.folder = value
+		default:
+			return nil, errors.Errorf("unknown placement directive: %v", directive)
+		}
+	}
+	return &result, nil
+}
+
+// applyConstraints fills in the datastore and resource pool from the
+// "root-disk-source" and "resource-pool" constraints, where they are
+// not already selected by placement directives.
+func (p *vmwarePlacement) applyConstraints(cons constraints.Value) {
+	if p.datastore == "" && cons.HasRootDiskSource() {
+		p.datastore = *cons.RootDiskSource
+	}
+	if p.resourcePool == "" && cons.HasResourcePool() {
+		p.resourcePool = strings.Trim(*cons.ResourcePool, "/")
 	}
+}
 
-	pos := strings.IndexRune(placement, '=')
-	if pos == -1 {
-		return nil, errors.Errorf("unknown placement directive: %v", placement)
+// checkPlacement checks that the datastore and resource pool selected
+// by the placement exist in its availability zone or, if it does not
+// select one, in at least one of the model's availability zones.
+func (env *sessionEnviron) checkPlacement(placement *vmwarePlacement) error {
+	if placement.datastore == "" && placement.resourcePool == "" {
+		return nil
+	}
+	var zones []common.AvailabilityZone
+	if placement.zone != nil {
+		zones = []common.AvailabilityZone{placement.zone}
+	} else {
+		var err error
+		zones, err = env.AvailabilityZones()
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
+	lastErr := errors.NotFoundf("availability zones")
+	for _, zone := range zones {
+		_, err := env.placementResourcePool(zone.(*vmwareAvailZone), placement)
+		if err == nil {
+			return nil
+		}
+		if !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		lastErr = err
+	}
+	return errors.Trace(lastErr)
+}
 
-	switch key, value := placement[:pos], placement[pos+1:]; key {
-	case "zone":
-		zone, err := env.availZone(value)
+// placementResourcePool checks that the datastore and resource pool
+// selected by the placement exist in the given availability zone, and
+// returns the resource pool, or nil if the placement does not select
+// one.
+func (env *sessionEnviron) placementResourcePool(
+	zone *vmwareAvailZone,
+	placement *vmwarePlacement,
+) (*types.ManagedObjectReference, error) {
+	if placement.datastore != "" {
+		datastores, err := env.client.Datastores(env.ctx, &zone.r)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		return zone.(*vmwareAvailZone), nil
+		var found bool
+		for _, ds := range datastores {
+			if ds.Name == placement.datastore && ds.Summary.Accessible {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.NotFoundf(
+				"accessible datastore %q in availability zone %q",
+				placement.datastore, zone.Name(),
+			)
+		}
+	}
+	if placement.resourcePool == "" {
+		return nil, nil
+	}
+	pools, err := env.client.ResourcePools(env.ctx, &zone.r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pool, ok := pools[placement.resourcePool]
+	if !ok {
+		return nil, errors.NotFoundf(
+			"resource pool %q in availability zone %q",
+			placement.resourcePool, zone.Name(),
+		)
 	}
-	return nil, errors.Errorf("unknown placement directive: %v", placement)
+	return &pool, nil
 }
 
 func (env *sessionEnviron) modelFolderName() string {
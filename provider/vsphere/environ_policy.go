@@ -4,15 +4,18 @@
 package vsphere
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/utils/arch"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 )
 
 // PrecheckInstance is part of the environs.Environ interface.
 func (env *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error {
-	if args.Placement == "" {
+	cons := args.Constraints
+	if args.Placement == "" && !cons.HasRootDiskSource() && !cons.HasResourcePool() {
 		return nil
 	}
 	return env.withSession(func(env *sessionEnviron) error {
@@ -22,13 +25,33 @@ func (env *environ) PrecheckInstance(args environs.PrecheckInstanceParams) error
 
 // PrecheckInstance is part of the environs.Environ interface.
 func (env *sessionEnviron) PrecheckInstance(args environs.PrecheckInstanceParams) error {
-	_, err := env.parsePlacement(args.Placement)
-	return err
+	placement, err := env.parsePlacement(args.Placement)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	placement.applyConstraints(args.Constraints)
+	return env.checkPlacement(placement)
 }
 
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	constraints.ImageId,
+	constraints.Gpu,
+	constraints.UsbDevice,
+}
+
+// Capabilities is specified on the Environ interface.
+func (env *environ) Capabilities() (environs.Capabilities, error) {
+	storageKinds, err := environs.StorageKinds(env)
+	if err != nil {
+		return environs.Capabilities{}, errors.Trace(err)
+	}
+	return environs.Capabilities{
+		StorageKinds:      storageKinds,
+		AvailabilityZones: true,
+		FirewallModes:     []string{config.FwInstance, config.FwNone},
+	}, nil
 }
 
 // ConstraintsValidator returns a Validator value which is used to
@@ -5,9 +5,12 @@ package vsphere_test
 
 import (
 	jc "github.com/juju/testing/checkers"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
 )
 
 type environPolSuite struct {
@@ -16,6 +19,102 @@ type environPolSuite struct {
 
 var _ = gc.Suite(&environPolSuite{})
 
+func (s *environPolSuite) SetUpTest(c *gc.C) {
+	s.EnvironFixture.SetUpTest(c)
+	s.client.computeResources = []*mo.ComputeResource{
+		newComputeResource("z1"),
+		newComputeResource("z2"),
+	}
+}
+
+func (s *environPolSuite) TestPrecheckInstanceNoPlacement(c *gc.C) {
+	err := s.env.PrecheckInstance(environs.PrecheckInstanceParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckNoCalls(c)
+}
+
+func (s *environPolSuite) TestPrecheckInstancePlacement(c *gc.C) {
+	s.client.datastores = []*mo.Datastore{newDatastore("ds0", true)}
+	s.client.resourcePools = map[string]types.ManagedObjectReference{
+		"parent/child": {Type: "ResourcePool", Value: "rp-child"},
+	}
+	err := s.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Placement: "zone=z2,datastore=ds0,resource-pool=/parent/child/,folder=web",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCallNames(c, "ComputeResources", "Datastores", "ResourcePools", "Close")
+	c.Assert(s.client.Calls()[1].Args[1], gc.Equals, s.client.computeResources[1])
+}
+
+func (s *environPolSuite) TestPrecheckInstancePlacementAnyZone(c *gc.C) {
+	s.client.resourcePools = map[string]types.ManagedObjectReference{
+		"pool": {Type: "ResourcePool", Value: "rp-pool"},
+	}
+	err := s.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Placement: "resource-pool=pool",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCallNames(c, "ComputeResources", "ResourcePools", "Close")
+}
+
+func (s *environPolSuite) TestPrecheckInstancePlacementResourcePoolNotFound(c *gc.C) {
+	err := s.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Placement: "resource-pool=nope",
+	})
+	c.Assert(err, gc.ErrorMatches, `resource pool "nope" in availability zone "z2" not found`)
+	s.client.CheckCallNames(c, "ComputeResources", "ResourcePools", "ResourcePools", "Close")
+}
+
+func (s *environPolSuite) TestPrecheckInstanceConstraints(c *gc.C) {
+	s.client.datastores = []*mo.Datastore{newDatastore("ds0", true)}
+	s.client.resourcePools = map[string]types.ManagedObjectReference{
+		"pool": {Type: "ResourcePool", Value: "rp-pool"},
+	}
+	err := s.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Constraints: constraints.MustParse("root-disk-source=ds0 resource-pool=pool"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCallNames(c, "ComputeResources", "Datastores", "ResourcePools", "Close")
+}
+
+func (s *environPolSuite) TestPrecheckInstanceRootDiskSourceNotFound(c *gc.C) {
+	s.client.datastores = []*mo.Datastore{newDatastore("ds0", true)}
+	err := s.env.PrecheckInstance(environs.PrecheckInstanceParams{
+		Placement:   "zone=z1",
+		Constraints: constraints.MustParse("root-disk-source=nope"),
+	})
+	c.Assert(err, gc.ErrorMatches, `accessible datastore "nope" in availability zone "z1" not found`)
+	s.client.CheckCallNames(c, "ComputeResources", "Datastores", "Close")
+}
+
+func (s *environPolSuite) TestPrecheckInstancePlacementInvalid(c *gc.C) {
+	for _, test := range []struct {
+		placement string
+		err       string
+	}{{
+		placement: "zone=z1,zone=z2",
+		err:       "duplicate placement directive: zone",
+	}, {
+		placement: "datastore=",
+		err:       "empty placement directive: datastore",
+	}, {
+		placement: "folder=a/b",
+		err:       `folder "a/b" not valid`,
+	}, {
+		placement: "zone=z1,host=h1",
+		err:       "unknown placement directive: host=h1",
+	}, {
+		placement: "zone=z3",
+		err:       `availability zone "z3" not found`,
+	}} {
+		c.Logf("placement %q", test.placement)
+		err := s.env.PrecheckInstance(environs.PrecheckInstanceParams{
+			Placement: test.placement,
+		})
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 func (s *environPolSuite) TestConstraintsValidator(c *gc.C) {
 	validator, err := s.env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
@@ -79,7 +79,7 @@ func (s *environSuite) TestDestroyController(c *gc.C) {
 	c.Assert(destroyCalled, jc.IsTrue)
 
 	s.dialStub.CheckCallNames(c, "Dial")
-	s.client.CheckCallNames(c, "DestroyVMFolder", "RemoveVirtualMachines", "DestroyVMFolder", "Close")
+	s.client.CheckCallNames(c, "DestroyVMFolder", "RemoveVirtualMachines", "RemoveVirtualMachines", "DestroyVMFolder", "Close")
 
 	destroyModelVMFolderCall := s.client.Calls()[0]
 	c.Assert(destroyModelVMFolderCall.Args, gc.HasLen, 2)
@@ -95,7 +95,14 @@ func (s *environSuite) TestDestroyController(c *gc.C) {
 		`Juju Controller (foo)/Model "*" (*)/*`,
 	)
 
-	destroyControllerVMFolderCall := s.client.Calls()[2]
+	removeFolderVirtualMachinesCall := s.client.Calls()[2]
+	c.Assert(removeFolderVirtualMachinesCall.Args, gc.HasLen, 2)
+	c.Assert(removeFolderVirtualMachinesCall.Args[0], gc.Implements, new(context.Context))
+	c.Assert(removeFolderVirtualMachinesCall.Args[1], gc.Equals,
+		`Juju Controller (foo)/Model "*" (*)/*/*`,
+	)
+
+	destroyControllerVMFolderCall := s.client.Calls()[3]
 	c.Assert(destroyControllerVMFolderCall.Args, gc.HasLen, 2)
 	c.Assert(destroyControllerVMFolderCall.Args[0], gc.Implements, new(context.Context))
 	c.Assert(destroyControllerVMFolderCall.Args[1], gc.Equals, `Juju Controller (foo)`)
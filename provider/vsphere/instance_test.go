@@ -35,6 +35,28 @@ func (s *InstanceSuite) TestInstances(c *gc.C) {
 	c.Assert(instances[1].Id(), gc.Equals, instance.Id("inst-1"))
 }
 
+func (s *InstanceSuite) TestInstancesInFolders(c *gc.C) {
+	s.client.virtualMachines = []*mo.VirtualMachine{
+		buildVM("inst-0").vm(),
+	}
+	s.client.folderVirtualMachines = []*mo.VirtualMachine{
+		buildVM("inst-1").vm(),
+	}
+	instances, err := s.env.Instances([]instance.Id{"inst-0", "inst-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instances, gc.HasLen, 2)
+	c.Assert(instances[0].Id(), gc.Equals, instance.Id("inst-0"))
+	c.Assert(instances[1].Id(), gc.Equals, instance.Id("inst-1"))
+
+	s.client.CheckCallNames(c, "VirtualMachines", "VirtualMachines", "Close")
+	c.Assert(s.client.Calls()[0].Args[1], gc.Equals,
+		`Juju Controller (*)/Model "testenv" (2d02eeac-9dbb-11e4-89d3-123b93f75cba)/*`,
+	)
+	c.Assert(s.client.Calls()[1].Args[1], gc.Equals,
+		`Juju Controller (*)/Model "testenv" (2d02eeac-9dbb-11e4-89d3-123b93f75cba)/*/*`,
+	)
+}
+
 func (s *InstanceSuite) TestInstancesNoInstances(c *gc.C) {
 	_, err := s.env.Instances([]instance.Id{"inst-0"})
 	c.Assert(err, gc.Equals, environs.ErrNoInstances)
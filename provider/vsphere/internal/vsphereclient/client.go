@@ -183,6 +183,62 @@ func (c *Client) ComputeResources(ctx context.Context) ([]*mo.ComputeResource, e
 	return cprs, nil
 }
 
+// Datastores returns the datastores accessible to the given compute
+// resource.
+func (c *Client) Datastores(ctx context.Context, cr *mo.ComputeResource) ([]*mo.Datastore, error) {
+	if len(cr.Datastore) == 0 {
+		return nil, nil
+	}
+	var datastores []mo.Datastore
+	if err := c.client.Retrieve(ctx, cr.Datastore, nil, &datastores); err != nil {
+		return nil, errors.Annotate(err, "retrieving datastore details")
+	}
+	result := make([]*mo.Datastore, len(datastores))
+	for i := range datastores {
+		result[i] = &datastores[i]
+	}
+	return result, nil
+}
+
+// ResourcePools returns the resource pools of the given compute
+// resource, keyed by their paths relative to the compute resource's
+// root resource pool, such as "parent/child". The root resource pool
+// itself is not included.
+func (c *Client) ResourcePools(
+	ctx context.Context,
+	cr *mo.ComputeResource,
+) (map[string]types.ManagedObjectReference, error) {
+	pools := make(map[string]types.ManagedObjectReference)
+	var addChildren func(parentPath string, parent types.ManagedObjectReference) error
+	addChildren = func(parentPath string, parent types.ManagedObjectReference) error {
+		var rp mo.ResourcePool
+		if err := c.client.RetrieveOne(ctx, parent, []string{"resourcePool"}, &rp); err != nil {
+			return errors.Annotate(err, "retrieving resource pool details")
+		}
+		if len(rp.ResourcePool) == 0 {
+			return nil
+		}
+		var children []mo.ResourcePool
+		if err := c.client.Retrieve(ctx, rp.ResourcePool, []string{"name"}, &children); err != nil {
+			return errors.Annotate(err, "retrieving resource pool details")
+		}
+		for _, child := range children {
+			childPath := path.Join(parentPath, child.Name)
+			pools[childPath] = child.Reference()
+			if err := addChildren(childPath, child.Reference()); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return nil
+	}
+	if cr.ResourcePool != nil {
+		if err := addChildren("", *cr.ResourcePool); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return pools, nil
+}
+
 // EnsureVMFolder creates the a VM folder with the given path if it doesn't
 // already exist.
 func (c *Client) EnsureVMFolder(ctx context.Context, folderPath string) error {
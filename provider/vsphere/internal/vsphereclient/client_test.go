@@ -203,6 +203,41 @@ func (s *clientSuite) SetUpTest(c *gc.C) {
 				},
 			},
 		}},
+		"FakeResourcePool1": []types.ObjectContent{{
+			Obj: types.ManagedObjectReference{
+				Type:  "ResourcePool",
+				Value: "FakeResourcePool1",
+			},
+			PropSet: []types.DynamicProperty{
+				{Name: "name", Val: "Resources"},
+				{Name: "resourcePool", Val: []types.ManagedObjectReference{{
+					Type:  "ResourcePool",
+					Value: "FakeResourcePool3",
+				}}},
+			},
+		}},
+		"FakeResourcePool3": []types.ObjectContent{{
+			Obj: types.ManagedObjectReference{
+				Type:  "ResourcePool",
+				Value: "FakeResourcePool3",
+			},
+			PropSet: []types.DynamicProperty{
+				{Name: "name", Val: "pool"},
+				{Name: "resourcePool", Val: []types.ManagedObjectReference{{
+					Type:  "ResourcePool",
+					Value: "FakeResourcePool4",
+				}}},
+			},
+		}},
+		"FakeResourcePool4": []types.ObjectContent{{
+			Obj: types.ManagedObjectReference{
+				Type:  "ResourcePool",
+				Value: "FakeResourcePool4",
+			},
+			PropSet: []types.DynamicProperty{
+				{Name: "name", Val: "child"},
+			},
+		}},
 		"FakeDatastore1": []types.ObjectContent{{
 			Obj: types.ManagedObjectReference{
 				Type:  "Datastore",
@@ -360,6 +395,60 @@ func (s *clientSuite) TestComputeResources(c *gc.C) {
 	c.Assert(result[1].Name, gc.Equals, "z1")
 }
 
+func (s *clientSuite) TestDatastores(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	result, err := client.Datastores(context.Background(), &mo.ComputeResource{
+		Datastore: []types.ManagedObjectReference{{
+			Type:  "Datastore",
+			Value: "FakeDatastore1",
+		}, {
+			Type:  "Datastore",
+			Value: "FakeDatastore2",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.roundTripper.CheckCalls(c, []testing.StubCall{
+		retrievePropertiesStubCall("FakeDatastore1", "FakeDatastore2"),
+	})
+
+	c.Assert(result, gc.HasLen, 2)
+	c.Assert(result[0].Name, gc.Equals, "datastore1")
+	c.Assert(result[0].Summary.Accessible, jc.IsFalse)
+	c.Assert(result[1].Name, gc.Equals, "datastore2")
+	c.Assert(result[1].Summary.Accessible, jc.IsTrue)
+}
+
+func (s *clientSuite) TestResourcePools(c *gc.C) {
+	client := s.newFakeClient(&s.roundTripper, "dc0")
+	result, err := client.ResourcePools(context.Background(), &mo.ComputeResource{
+		ResourcePool: &types.ManagedObjectReference{
+			Type:  "ResourcePool",
+			Value: "FakeResourcePool1",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.roundTripper.CheckCalls(c, []testing.StubCall{
+		retrievePropertiesStubCall("FakeResourcePool1"),
+		retrievePropertiesStubCall("FakeResourcePool3"),
+		retrievePropertiesStubCall("FakeResourcePool3"),
+		retrievePropertiesStubCall("FakeResourcePool4"),
+		retrievePropertiesStubCall("FakeResourcePool4"),
+	})
+
+	c.Assert(result, jc.DeepEquals, map[string]types.ManagedObjectReference{
+		"pool": {
+			Type:  "ResourcePool",
+			Value: "FakeResourcePool3",
+		},
+		"pool/child": {
+			Type:  "ResourcePool",
+			Value: "FakeResourcePool4",
+		},
+	})
+}
+
 func (s *clientSuite) TestDestroyVMFolder(c *gc.C) {
 	client := s.newFakeClient(&s.roundTripper, "dc0")
 	err := client.DestroyVMFolder(context.Background(), "foo")
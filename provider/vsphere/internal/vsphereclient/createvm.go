@@ -53,6 +53,11 @@ type CreateVirtualMachineParams struct {
 	// to create the VM.
 	ComputeResource *mo.ComputeResource
 
+	// ResourcePool is the resource pool, belonging to ComputeResource,
+	// in which to create the VM. If this is nil, the compute resource's
+	// root resource pool will be used.
+	ResourcePool *types.ManagedObjectReference
+
 	// Datastore is the name of the datastore in which to create the VM.
 	// If this is empty, any accessible datastore will be used.
 	Datastore string
@@ -136,7 +141,7 @@ func (c *Client) CreateVirtualMachine(
 	// Import the VApp.
 	args.UpdateProgress(fmt.Sprintf("creating VM %q", args.Name))
 	c.logger.Debugf("creating VM in folder %s", vmFolder)
-	rp := object.NewResourcePool(c.client.Client, *args.ComputeResource.ResourcePool)
+	rp := object.NewResourcePool(c.client.Client, resourcePoolRef(args))
 	lease, err := rp.ImportVApp(ctx, spec.ImportSpec, vmFolder, nil)
 	if err != nil {
 		return nil, errors.Annotatef(err, "failed to import vapp")
@@ -253,7 +258,7 @@ func (c *Client) createImportSpec(
 	}
 
 	ovfManager := object.NewOvfManager(c.client.Client)
-	resourcePool := object.NewReference(c.client.Client, *args.ComputeResource.ResourcePool)
+	resourcePool := object.NewReference(c.client.Client, resourcePoolRef(args))
 	datastore, err := c.selectDatastore(ctx, args)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -327,6 +332,15 @@ func (c *Client) createImportSpec(
 	return spec, nil
 }
 
+// resourcePoolRef returns a reference to the resource pool in which
+// the VM described by args should be created.
+func resourcePoolRef(args CreateVirtualMachineParams) types.ManagedObjectReference {
+	if args.ResourcePool != nil {
+		return *args.ResourcePool
+	}
+	return *args.ComputeResource.ResourcePool
+}
+
 func (c *Client) selectDatastore(
 	ctx context.Context,
 	args CreateVirtualMachineParams,
@@ -5,6 +5,7 @@ package vsphere_test
 
 import (
 	"net/url"
+	"strings"
 	"sync"
 
 	"github.com/juju/testing"
@@ -34,7 +35,10 @@ type mockClient struct {
 
 	computeResources      []*mo.ComputeResource
 	createdVirtualMachine *mo.VirtualMachine
+	datastores            []*mo.Datastore
+	resourcePools         map[string]types.ManagedObjectReference
 	virtualMachines       []*mo.VirtualMachine
+	folderVirtualMachines []*mo.VirtualMachine
 }
 
 func (c *mockClient) Close(ctx context.Context) error {
@@ -58,6 +62,13 @@ func (c *mockClient) CreateVirtualMachine(ctx context.Context, args vsphereclien
 	return c.createdVirtualMachine, c.NextErr()
 }
 
+func (c *mockClient) Datastores(ctx context.Context, cr *mo.ComputeResource) ([]*mo.Datastore, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Datastores", ctx, cr)
+	return c.datastores, c.NextErr()
+}
+
 func (c *mockClient) DestroyVMFolder(ctx context.Context, path string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -93,6 +104,13 @@ func (c *mockClient) RemoveVirtualMachines(ctx context.Context, path string) err
 	return c.NextErr()
 }
 
+func (c *mockClient) ResourcePools(ctx context.Context, cr *mo.ComputeResource) (map[string]types.ManagedObjectReference, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "ResourcePools", ctx, cr)
+	return c.resourcePools, c.NextErr()
+}
+
 func (c *mockClient) UpdateVirtualMachineExtraConfig(ctx context.Context, vm *mo.VirtualMachine, attrs map[string]string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -104,6 +122,10 @@ func (c *mockClient) VirtualMachines(ctx context.Context, path string) ([]*mo.Vi
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.MethodCall(c, "VirtualMachines", ctx, path)
+	if strings.HasSuffix(path, "/*/*") {
+		// VMs in the subfolders of the model's VM folder.
+		return c.folderVirtualMachines, c.NextErr()
+	}
 	return c.virtualMachines, c.NextErr()
 }
 
@@ -168,3 +190,10 @@ func newComputeResource(name string) *mo.ComputeResource {
 	}
 	return cr
 }
+
+func newDatastore(name string, accessible bool) *mo.Datastore {
+	ds := new(mo.Datastore)
+	ds.Name = name
+	ds.Summary.Accessible = accessible
+	return ds
+}
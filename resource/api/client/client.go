@@ -105,12 +105,24 @@ func newListResourcesArgs(services []string) (params.ListResourcesArgs, error) {
 	return args, nil
 }
 
-// Upload sends the provided resource blob up to Juju.
+// Upload sends the provided resource blob up to Juju. A pinned
+// resource is not replaced.
 func (c Client) Upload(service, name, filename string, reader io.ReadSeeker) error {
+	return c.upload(service, name, filename, reader, false)
+}
+
+// UploadUnpinned is like Upload, but replaces the resource even if
+// it is pinned, leaving it unpinned.
+func (c Client) UploadUnpinned(service, name, filename string, reader io.ReadSeeker) error {
+	return c.upload(service, name, filename, reader, true)
+}
+
+func (c Client) upload(service, name, filename string, reader io.ReadSeeker, unpin bool) error {
 	uReq, err := api.NewUploadRequest(service, name, filename, reader)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	uReq.Unpin = unpin
 	req, err := uReq.HTTPRequest()
 	if err != nil {
 		return errors.Trace(err)
@@ -203,6 +215,23 @@ func newAddPendingResourcesArgs(applicationID string, chID charmstore.CharmID, c
 // without making it available yet. For example, AddPendingResource()
 // is used before the application is deployed.
 func (c Client) AddPendingResource(applicationID string, res charmresource.Resource, filename string, reader io.ReadSeeker) (pendingID string, err error) {
+	return c.addPendingResource(applicationID, res, filename, reader, false)
+}
+
+// AddPinnedPendingResource is like AddPendingResource, but the blob
+// must match the resource's fingerprint, and the resource is pinned to
+// that fingerprint so that units refuse any other content.
+func (c Client) AddPinnedPendingResource(applicationID string, res charmresource.Resource, filename string, reader io.ReadSeeker) (pendingID string, err error) {
+	if reader == nil {
+		return "", errors.NotValidf("pinning resource %q without content", res.Name)
+	}
+	if res.Fingerprint.IsZero() {
+		return "", errors.NotValidf("pinning resource %q without fingerprint", res.Name)
+	}
+	return c.addPendingResource(applicationID, res, filename, reader, true)
+}
+
+func (c Client) addPendingResource(applicationID string, res charmresource.Resource, filename string, reader io.ReadSeeker, pinned bool) (pendingID string, err error) {
 	ids, err := c.AddPendingResources(AddPendingResourcesArgs{
 		ApplicationID: applicationID,
 		Resources:     []charmresource.Resource{res},
@@ -218,6 +247,9 @@ func (c Client) AddPendingResource(applicationID string, res charmresource.Resou
 			return "", errors.Trace(err)
 		}
 		uReq.PendingID = pendingID
+		if pinned {
+			uReq.PinnedFingerprint = res.Fingerprint
+		}
 		req, err := uReq.HTTPRequest()
 		if err != nil {
 			return "", errors.Trace(err)
@@ -52,6 +52,32 @@ func (s *UploadSuite) TestOkay(c *gc.C) {
 	s.stub.CheckCall(c, 3, "Do", req, reader, s.response)
 }
 
+func (s *UploadSuite) TestUnpinned(c *gc.C) {
+	data := "<data>"
+	reader := &stubFile{stub: s.stub}
+	reader.returnRead = strings.NewReader(data)
+	cl := client.NewClient(s.facade, s, s.facade)
+
+	_, s.response.Resource = newResource(c, "spam", "a-user", data)
+
+	err := cl.UploadUnpinned("a-application", "spam", "foo.zip", reader)
+	c.Assert(err, jc.ErrorIsNil)
+
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	req, err := http.NewRequest("PUT", "/applications/a-application/resources/spam", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-SHA384", fp.String())
+	req.Header.Set("Juju-Unpin", "true")
+	req.Header.Set("Content-Length", fmt.Sprint(len(data)))
+	req.Header.Set("Content-Disposition", "form-data; filename=foo.zip")
+	req.ContentLength = int64(len(data))
+
+	s.stub.CheckCallNames(c, "Read", "Read", "Seek", "Do")
+	s.stub.CheckCall(c, 3, "Do", req, reader, s.response)
+}
+
 func (s *UploadSuite) TestBadService(c *gc.C) {
 	cl := client.NewClient(s.facade, s, s.facade)
 
@@ -149,6 +175,54 @@ func (s *UploadSuite) TestPendingResourceOkay(c *gc.C) {
 	c.Check(uploadID, gc.Equals, expected)
 }
 
+func (s *UploadSuite) TestPinnedPendingResourceOkay(c *gc.C) {
+	res, apiResult := newResourceResult(c, "a-application", "spam")
+	s.response.Resource = apiResult.Resources[0]
+	data := "<data>"
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader(data))
+	c.Assert(err, jc.ErrorIsNil)
+	chRes := res[0].Resource
+	chRes.Fingerprint = fp
+	reader := &stubFile{stub: s.stub}
+	reader.returnRead = strings.NewReader(data)
+	s.facade.pendingIDs = []string{"some-unique-id"}
+	cl := client.NewClient(s.facade, s, s.facade)
+
+	uploadID, err := cl.AddPinnedPendingResource("a-application", chRes, "file.zip", reader)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c,
+		"FacadeCall",
+		"Read",
+		"Read",
+		"Seek",
+		"Do",
+	)
+
+	req, err := http.NewRequest("PUT", "/applications/a-application/resources/spam", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-SHA384", fp.String())
+	req.Header.Set("Juju-Pinned-Sha384", fp.String())
+	req.Header.Set("Content-Length", fmt.Sprint(len(data)))
+	req.ContentLength = int64(len(data))
+	req.URL.RawQuery = "pendingid=some-unique-id"
+	req.Header.Set("Content-Disposition", "form-data; filename=file.zip")
+
+	s.stub.CheckCall(c, 4, "Do", req, reader, s.response)
+	c.Check(uploadID, gc.Equals, "some-unique-id")
+}
+
+func (s *UploadSuite) TestPinnedPendingResourceNoFile(c *gc.C) {
+	res, _ := newResourceResult(c, "a-application", "spam")
+	cl := client.NewClient(s.facade, s, s.facade)
+
+	_, err := cl.AddPinnedPendingResource("a-application", res[0].Resource, "file.zip", nil)
+
+	c.Check(err, gc.ErrorMatches, `pinning resource "spam" without content not valid`)
+	s.stub.CheckNoCalls(c)
+}
+
 func (s *UploadSuite) TestPendingResourceNoFile(c *gc.C) {
 	res, apiResult := newResourceResult(c, "a-application", "spam")
 	uuid, err := utils.NewUUID()
@@ -16,7 +16,7 @@ import (
 // Resource2API converts a resource.Resource into
 // a Resource struct.
 func Resource2API(res resource.Resource) params.Resource {
-	return params.Resource{
+	apiRes := params.Resource{
 		CharmResource: CharmResource2API(res.Resource),
 		ID:            res.ID,
 		PendingID:     res.PendingID,
@@ -24,6 +24,10 @@ func Resource2API(res resource.Resource) params.Resource {
 		Username:      res.Username,
 		Timestamp:     res.Timestamp,
 	}
+	if res.IsPinned() {
+		apiRes.PinnedFingerprint = res.PinnedFingerprint.Bytes()
+	}
+	return apiRes
 }
 
 // APIResult2ServiceResources converts a ResourcesResult into a resource.ServiceResources.
@@ -126,13 +130,19 @@ func API2Resource(apiRes params.Resource) (resource.Resource, error) {
 		return res, errors.Trace(err)
 	}
 
+	pinned, err := resource.DeserializeFingerprint(apiRes.PinnedFingerprint)
+	if err != nil {
+		return res, errors.Trace(err)
+	}
+
 	res = resource.Resource{
-		Resource:      charmRes,
-		ID:            apiRes.ID,
-		PendingID:     apiRes.PendingID,
-		ApplicationID: apiRes.ApplicationID,
-		Username:      apiRes.Username,
-		Timestamp:     apiRes.Timestamp,
+		Resource:          charmRes,
+		ID:                apiRes.ID,
+		PendingID:         apiRes.PendingID,
+		ApplicationID:     apiRes.ApplicationID,
+		Username:          apiRes.Username,
+		Timestamp:         apiRes.Timestamp,
+		PinnedFingerprint: pinned,
 	}
 
 	if err := res.Validate(); err != nil {
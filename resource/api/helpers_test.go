@@ -442,6 +442,33 @@ func (HelpersSuite) TestAPI2Resource(c *gc.C) {
 	c.Check(res, jc.DeepEquals, expected)
 }
 
+func (HelpersSuite) TestPinnedResourceRoundTrip(c *gc.C) {
+	fp, err := charmresource.NewFingerprint([]byte(fingerprint))
+	c.Assert(err, jc.ErrorIsNil)
+	res := resource.Resource{
+		Resource: charmresource.Resource{
+			Meta: charmresource.Meta{
+				Name: "spam",
+				Type: charmresource.TypeFile,
+				Path: "spam.tgz",
+			},
+			Origin:      charmresource.OriginUpload,
+			Fingerprint: fp,
+			Size:        10,
+		},
+		ID:                "a-application/spam",
+		ApplicationID:     "a-application",
+		PinnedFingerprint: fp,
+	}
+
+	apiRes := api.Resource2API(res)
+	c.Check(apiRes.PinnedFingerprint, jc.DeepEquals, []byte(fingerprint))
+
+	converted, err := api.API2Resource(apiRes)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(converted, jc.DeepEquals, res)
+}
+
 func (HelpersSuite) TestCharmResource2API(c *gc.C) {
 	fp, err := charmresource.NewFingerprint([]byte(fingerprint))
 	c.Assert(err, jc.ErrorIsNil)
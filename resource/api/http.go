@@ -39,6 +39,12 @@ const (
 	HeaderContentType = "Content-Type"
 	// HeaderContentSha384 is the header name for the sha hash of a file upload.
 	HeaderContentSha384 = "Content-Sha384"
+	// HeaderPinnedSha384 is the header name for the sha hash a file
+	// upload is expected to have, and the resource is to be pinned to.
+	HeaderPinnedSha384 = "Juju-Pinned-Sha384"
+	// HeaderUnpin is the header name for whether a file upload may
+	// replace a pinned resource, leaving it unpinned.
+	HeaderUnpin = "Juju-Unpin"
 	// HeaderContentLength is the header name for the length of a file upload.
 	HeaderContentLength = "Content-Length"
 	// HeaderContentDisposition is the header name for value that holds the filename.
@@ -35,6 +35,16 @@ type UploadRequest struct {
 
 	// PendingID is the pending ID to associate with this upload, if any.
 	PendingID string
+
+	// PinnedFingerprint is the fingerprint the uploaded data must
+	// have, and the resource is to be pinned to, if any. Only pending
+	// resources may be pinned.
+	PinnedFingerprint charmresource.Fingerprint
+
+	// Unpin indicates whether the upload may replace a pinned
+	// resource, leaving it unpinned. Only non-pending resources
+	// may be unpinned.
+	Unpin bool
 }
 
 // NewUploadRequest generates a new upload request for the given resource.
@@ -92,6 +102,12 @@ func (ur UploadRequest) HTTPRequest() (*http.Request, error) {
 
 	req.Header.Set(HeaderContentType, ContentTypeRaw)
 	req.Header.Set(HeaderContentSha384, ur.Fingerprint.String())
+	if !ur.PinnedFingerprint.IsZero() {
+		req.Header.Set(HeaderPinnedSha384, ur.PinnedFingerprint.String())
+	}
+	if ur.Unpin {
+		req.Header.Set(HeaderUnpin, "true")
+	}
 	req.Header.Set(HeaderContentLength, fmt.Sprint(ur.Size))
 	setFilename(ur.Filename, req)
 
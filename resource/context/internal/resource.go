@@ -29,11 +29,19 @@ type OpenedResource struct {
 }
 
 // OpenResource opens the identified resource using the provided client.
+// A resource pinned to a fingerprint other than its own is refused,
+// so that its content never reaches the unit.
 func OpenResource(name string, client OpenedResourceClient) (*OpenedResource, error) {
 	info, reader, err := client.GetResource(name)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := info.CheckPinned(); err != nil {
+		if reader != nil {
+			reader.Close()
+		}
+		return nil, errors.Trace(err)
+	}
 	or := &OpenedResource{
 		Resource:   info,
 		ReadCloser: reader,
@@ -41,12 +49,18 @@ func OpenResource(name string, client OpenedResourceClient) (*OpenedResource, er
 	return or, nil
 }
 
-// Content returns the "content" for the opened resource.
+// Content returns the "content" for the opened resource. The content
+// of a pinned resource is verified against the pinned fingerprint as
+// it is written.
 func (or OpenedResource) Content() Content {
+	fp := or.Fingerprint
+	if or.IsPinned() {
+		fp = or.PinnedFingerprint
+	}
 	return Content{
 		Data:        or.ReadCloser,
 		Size:        or.Size,
-		Fingerprint: or.Fingerprint,
+		Fingerprint: fp,
 	}
 }
 
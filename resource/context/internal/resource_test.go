@@ -4,9 +4,13 @@
 package internal_test
 
 import (
+	"strings"
+
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
 
 	"github.com/juju/juju/resource/context/internal"
 )
@@ -40,6 +44,34 @@ func (s *OpenedResourceSuite) TestOpenResource(c *gc.C) {
 	})
 }
 
+func (s *OpenedResourceSuite) TestOpenResourcePinned(c *gc.C) {
+	info, reader := newResource(c, s.stub.Stub, "spam", "some data")
+	info.PinnedFingerprint = info.Fingerprint
+	s.stub.ReturnGetResourceInfo = info
+	s.stub.ReturnGetResourceData = reader
+
+	opened, err := internal.OpenResource("spam", s.stub)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "GetResource")
+	c.Check(opened.Resource, jc.DeepEquals, info)
+}
+
+func (s *OpenedResourceSuite) TestOpenResourcePinMismatch(c *gc.C) {
+	info, reader := newResource(c, s.stub.Stub, "spam", "some data")
+	fp, err := charmresource.GenerateFingerprint(strings.NewReader("other data"))
+	c.Assert(err, jc.ErrorIsNil)
+	info.PinnedFingerprint = fp
+	s.stub.ReturnGetResourceInfo = info
+	s.stub.ReturnGetResourceData = reader
+
+	_, err = internal.OpenResource("spam", s.stub)
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `resource "spam" fingerprint .* does not match pinned fingerprint .*`)
+	s.stub.CheckCallNames(c, "GetResource", "Close")
+}
+
 func (s *OpenedResourceSuite) TestContent(c *gc.C) {
 	info, reader := newResource(c, s.stub.Stub, "spam", "some data")
 	opened := internal.OpenedResource{
@@ -57,6 +89,21 @@ func (s *OpenedResourceSuite) TestContent(c *gc.C) {
 	})
 }
 
+func (s *OpenedResourceSuite) TestContentPinned(c *gc.C) {
+	info, reader := newResource(c, s.stub.Stub, "spam", "some data")
+	pin, err := charmresource.GenerateFingerprint(strings.NewReader("other data"))
+	c.Assert(err, jc.ErrorIsNil)
+	info.PinnedFingerprint = pin
+	opened := internal.OpenedResource{
+		Resource:   info,
+		ReadCloser: reader,
+	}
+
+	content := opened.Content()
+
+	c.Check(content.Fingerprint, jc.DeepEquals, pin)
+}
+
 func (s *OpenedResourceSuite) TestInfo(c *gc.C) {
 	expected, reader := newResource(c, s.stub.Stub, "spam", "some data")
 	opened := internal.OpenedResource{
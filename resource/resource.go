@@ -65,6 +65,12 @@ type Resource struct {
 
 	// Timestamp indicates when the resource was added to the model.
 	Timestamp time.Time
+
+	// PinnedFingerprint is the fingerprint of the content uploaded
+	// for the resource when it was deployed, if the resource was
+	// pinned to it. Units refuse to use the content of a pinned
+	// resource unless it matches.
+	PinnedFingerprint resource.Fingerprint
 }
 
 // Validate ensures that the spec is valid.
@@ -91,6 +97,27 @@ func (res Resource) Validate() error {
 	return nil
 }
 
+// IsPinned indicates whether or not the resource is pinned to the
+// fingerprint of the content uploaded for it.
+func (res Resource) IsPinned() bool {
+	return !res.PinnedFingerprint.IsZero()
+}
+
+// CheckPinned returns an error satisfying errors.IsNotValid if the
+// resource is pinned to a fingerprint other than that of its content.
+func (res Resource) CheckPinned() error {
+	if !res.IsPinned() {
+		return nil
+	}
+	if res.Fingerprint.String() != res.PinnedFingerprint.String() {
+		return errors.NewNotValid(nil, fmt.Sprintf(
+			"resource %q fingerprint %s does not match pinned fingerprint %s",
+			res.Name, res.Fingerprint, res.PinnedFingerprint,
+		))
+	}
+	return nil
+}
+
 // IsPlaceholder indicates whether or not the resource is a
 // "placeholder" (partially populated pending an upload).
 func (res Resource) IsPlaceholder() bool {
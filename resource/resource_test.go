@@ -221,3 +221,33 @@ func (s *ResourceSuite) TestAsMap(c *gc.C) {
 		"eggs": eggs,
 	})
 }
+
+func (ResourceSuite) TestCheckPinnedNotPinned(c *gc.C) {
+	res := resource.Resource{
+		Resource: newFullCharmResource(c, "spam"),
+	}
+
+	c.Check(res.IsPinned(), jc.IsFalse)
+	c.Check(res.CheckPinned(), jc.ErrorIsNil)
+}
+
+func (ResourceSuite) TestCheckPinnedMatches(c *gc.C) {
+	res := resource.Resource{
+		Resource:          newFullCharmResource(c, "spam"),
+		PinnedFingerprint: newFingerprint(c, "spam"),
+	}
+
+	c.Check(res.IsPinned(), jc.IsTrue)
+	c.Check(res.CheckPinned(), jc.ErrorIsNil)
+}
+
+func (ResourceSuite) TestCheckPinnedMismatch(c *gc.C) {
+	res := resource.Resource{
+		Resource:          newFullCharmResource(c, "spam"),
+		PinnedFingerprint: newFingerprint(c, "eggs"),
+	}
+
+	err := res.CheckPinned()
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `resource "spam" fingerprint .* does not match pinned fingerprint .*`)
+}
@@ -37,6 +37,32 @@ func DeployResources(
 	resources map[string]charmresource.Meta,
 	conn base.APICallCloser,
 ) (ids map[string]string, err error) {
+	return deployResources(applicationID, chID, csMac, filesAndRevisions, resources, conn, false)
+}
+
+// DeployPinnedResources is like DeployResources, but pins each
+// uploaded resource to the checksum of its file, so that units refuse
+// any other content.
+func DeployPinnedResources(
+	applicationID string,
+	chID charmstore.CharmID,
+	csMac *macaroon.Macaroon,
+	filesAndRevisions map[string]string,
+	resources map[string]charmresource.Meta,
+	conn base.APICallCloser,
+) (ids map[string]string, err error) {
+	return deployResources(applicationID, chID, csMac, filesAndRevisions, resources, conn, true)
+}
+
+func deployResources(
+	applicationID string,
+	chID charmstore.CharmID,
+	csMac *macaroon.Macaroon,
+	filesAndRevisions map[string]string,
+	resources map[string]charmresource.Meta,
+	conn base.APICallCloser,
+	pinUploads bool,
+) (ids map[string]string, err error) {
 
 	if len(filesAndRevisions)+len(resources) == 0 {
 		// Nothing to upload.
@@ -66,6 +92,7 @@ func DeployResources(
 		Filenames:          filenames,
 		Revisions:          revisions,
 		ResourcesMeta:      resources,
+		PinUploads:         pinUploads,
 		Client:             &deployClient{client},
 	})
 	if err != nil {
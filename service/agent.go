@@ -44,6 +44,15 @@ func AgentConf(info AgentInfo, renderer shell.Renderer) common.Conf {
 	return conf
 }
 
+// ControllerAgentConf returns the data that defines an init service
+// config for the identified machine agent of a controller, which starts
+// after the named database service that it serves the API from.
+func ControllerAgentConf(info AgentInfo, renderer shell.Renderer, dbServiceName string) common.Conf {
+	conf := AgentConf(info, renderer)
+	conf.After = append(conf.After, dbServiceName)
+	return conf
+}
+
 // TODO(ericsnow) Eliminate ContainerAgentConf once it is no longer
 // used in worker/deployer/simple.go.
 
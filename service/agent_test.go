@@ -111,6 +111,17 @@ func (*agentSuite) TestAgentConfMachineUbuntu(c *gc.C) {
 	})
 }
 
+func (*agentSuite) TestControllerAgentConf(c *gc.C) {
+	info := service.NewMachineAgentInfo("0", "/var/lib/juju", "/var/log/juju")
+	renderer, err := shell.NewRenderer("ubuntu")
+	c.Assert(err, jc.ErrorIsNil)
+	conf := service.ControllerAgentConf(info, renderer, "juju-db")
+
+	expected := service.AgentConf(info, renderer)
+	expected.After = []string{"juju-db"}
+	c.Check(conf, jc.DeepEquals, expected)
+}
+
 func (*agentSuite) TestAgentConfMachineWindows(c *gc.C) {
 	dataDir := `C:\Juju\lib\juju`
 	logDir := `C:\Juju\logs\juju`
@@ -4,6 +4,7 @@
 package common
 
 import (
+	"encoding/json"
 	"reflect"
 	"strings"
 
@@ -24,11 +25,28 @@ type Conf struct {
 	// service will not start until after the other stops.
 	AfterStopped string
 
+	// Before holds the names of other services that will not start
+	// until this service has started.
+	Before []string
+
+	// After holds the names of other services. This service will not
+	// start until after they have started, but does not otherwise
+	// depend on them.
+	After []string
+
+	// Requires holds the names of other services that this service
+	// needs. This service will not start until after they have
+	// started, and will not run without them.
+	Requires []string
+
 	// Env holds the environment variables that will be set when the
 	// command runs.
-	// Currently not used on Windows.
 	Env map[string]string
 
+	// WorkingDirectory, if set, is the absolute path of the directory
+	// that the command runs in.
+	WorkingDirectory string
+
 	// TODO(ericsnow) Add a Limit type, since the possible keys are known.
 
 	// Limit holds the ulimit values that will be set when the command
@@ -46,6 +64,20 @@ type Conf struct {
 	// The command will be restarted if it exits with a non-zero exit code.
 	ExecStart string
 
+	// RestartSec is how many seconds to wait before restarting the
+	// command after it exits with a non-zero exit code. Values less
+	// than or equal to 0 (the default) leave the delay to the init
+	// system implementation.
+	// Currently only used on Windows.
+	RestartSec int
+
+	// RestartResetSec is how many seconds the command must run without
+	// failing before its count of failures is reset. Values less than
+	// or equal to 0 (the default) leave the period to the init system
+	// implementation.
+	// Currently only used on Windows.
+	RestartResetSec int
+
 	// ExecStopPost is the command that will be run after the service stops.
 	// The path to the executable must be absolute.
 	ExecStopPost string
@@ -66,6 +98,60 @@ type Conf struct {
 	ServiceArgs []string
 }
 
+// confDoc is the serialized form of a Conf, used when persisting
+// service definitions (e.g. in a service manifest). Its fields must
+// match those of Conf, so that each can be converted to the other.
+type confDoc struct {
+	Desc             string            `yaml:"description" json:"description"`
+	Transient        bool              `yaml:"transient,omitempty" json:"transient,omitempty"`
+	AfterStopped     string            `yaml:"after-stopped,omitempty" json:"after-stopped,omitempty"`
+	Before           []string          `yaml:"before,omitempty" json:"before,omitempty"`
+	After            []string          `yaml:"after,omitempty" json:"after,omitempty"`
+	Requires         []string          `yaml:"requires,omitempty" json:"requires,omitempty"`
+	Env              map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+	WorkingDirectory string            `yaml:"working-directory,omitempty" json:"working-directory,omitempty"`
+	Limit            map[string]int    `yaml:"limit,omitempty" json:"limit,omitempty"`
+	Timeout          int               `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	ExecStart        string            `yaml:"exec-start" json:"exec-start"`
+	RestartSec       int               `yaml:"restart-sec,omitempty" json:"restart-sec,omitempty"`
+	RestartResetSec  int               `yaml:"restart-reset-sec,omitempty" json:"restart-reset-sec,omitempty"`
+	ExecStopPost     string            `yaml:"exec-stop-post,omitempty" json:"exec-stop-post,omitempty"`
+	Logfile          string            `yaml:"logfile,omitempty" json:"logfile,omitempty"`
+	ExtraScript      string            `yaml:"extra-script,omitempty" json:"extra-script,omitempty"`
+	ServiceBinary    string            `yaml:"service-binary,omitempty" json:"service-binary,omitempty"`
+	ServiceArgs      []string          `yaml:"service-args,omitempty" json:"service-args,omitempty"`
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (c Conf) MarshalYAML() (interface{}, error) {
+	return confDoc(c), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (c *Conf) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var doc confDoc
+	if err := unmarshal(&doc); err != nil {
+		return errors.Trace(err)
+	}
+	*c = Conf(doc)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c Conf) MarshalJSON() ([]byte, error) {
+	return json.Marshal(confDoc(c))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Conf) UnmarshalJSON(data []byte) error {
+	var doc confDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return errors.Trace(err)
+	}
+	*c = Conf(doc)
+	return nil
+}
+
 // IsZero determines whether or not the conf is a zero value.
 func (c Conf) IsZero() bool {
 	return reflect.DeepEqual(c, Conf{})
@@ -77,6 +163,22 @@ func (c Conf) Validate(renderer shell.Renderer) error {
 		return errors.New("missing Desc")
 	}
 
+	if c.WorkingDirectory != "" && !renderer.IsAbs(c.WorkingDirectory) {
+		return errors.NotValidf("relative path in WorkingDirectory (%s)", c.WorkingDirectory)
+	}
+
+	for field, names := range map[string][]string{
+		"Before":   c.Before,
+		"After":    c.After,
+		"Requires": c.Requires,
+	} {
+		for _, name := range names {
+			if name == "" || strings.ContainsAny(name, " \t\n") {
+				return errors.NotValidf("service name %q in %s", name, field)
+			}
+		}
+	}
+
 	// Check the Exec* fields.
 	if c.ExecStart == "" {
 		return errors.New("missing ExecStart")
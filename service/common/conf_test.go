@@ -4,10 +4,13 @@
 package common_test
 
 import (
+	"encoding/json"
+
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/shell"
 	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/service/common"
 )
@@ -115,3 +118,105 @@ func (*confSuite) TestValidateRelativeExecStopPost(c *gc.C) {
 
 	c.Check(err, gc.ErrorMatches, `.*relative path in ExecStopPost \(.*`)
 }
+
+func (*confSuite) TestValidateRelativeWorkingDirectory(c *gc.C) {
+	conf := common.Conf{
+		Desc:             "some service",
+		ExecStart:        "/path/to/some-command a b c",
+		WorkingDirectory: "var/lib/some-service",
+	}
+	err := conf.Validate(renderer)
+
+	c.Check(err, gc.ErrorMatches, `.*relative path in WorkingDirectory \(.*`)
+}
+
+func (*confSuite) TestValidateDependencyNames(c *gc.C) {
+	conf := common.Conf{
+		Desc:      "some service",
+		ExecStart: "/path/to/some-command a b c",
+		After:     []string{"network-service", ""},
+	}
+	err := conf.Validate(renderer)
+	c.Check(err, gc.ErrorMatches, `service name "" in After not valid`)
+
+	conf.After = nil
+	conf.Requires = []string{"two services"}
+	err = conf.Validate(renderer)
+	c.Check(err, gc.ErrorMatches, `service name "two services" in Requires not valid`)
+}
+
+func (*confSuite) TestMarshalRoundTrip(c *gc.C) {
+	conf := common.Conf{
+		Desc:             "some service",
+		AfterStopped:     "other-service",
+		Before:           []string{"later-service"},
+		After:            []string{"network-service"},
+		Requires:         []string{"database-service"},
+		Env:              map[string]string{"JUJU_FOO": "bar"},
+		WorkingDirectory: "/var/lib/some-service",
+		Limit:            map[string]int{"nofile": 20000},
+		Timeout:          30,
+		ExecStart:        "/path/to/some-command a b c",
+		RestartSec:       10,
+		RestartResetSec:  300,
+		ExecStopPost:     "/path/to/cleanup",
+		Logfile:          "/var/log/some-service.log",
+		ServiceArgs:      []string{"a", "b", "c"},
+	}
+
+	data, err := goyaml.Marshal(conf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, `
+description: some service
+after-stopped: other-service
+before:
+- later-service
+after:
+- network-service
+requires:
+- database-service
+env:
+  JUJU_FOO: bar
+working-directory: /var/lib/some-service
+limit:
+  nofile: 20000
+timeout: 30
+exec-start: /path/to/some-command a b c
+restart-sec: 10
+restart-reset-sec: 300
+exec-stop-post: /path/to/cleanup
+logfile: /var/log/some-service.log
+service-args:
+- a
+- b
+- c
+`[1:])
+	var fromYAML common.Conf
+	err = goyaml.Unmarshal(data, &fromYAML)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fromYAML, jc.DeepEquals, conf)
+
+	data, err = json.Marshal(conf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), jc.JSONEquals, map[string]interface{}{
+		"description":       "some service",
+		"after-stopped":     "other-service",
+		"before":            []interface{}{"later-service"},
+		"after":             []interface{}{"network-service"},
+		"requires":          []interface{}{"database-service"},
+		"env":               map[string]interface{}{"JUJU_FOO": "bar"},
+		"working-directory": "/var/lib/some-service",
+		"limit":             map[string]interface{}{"nofile": 20000},
+		"timeout":           30,
+		"exec-start":        "/path/to/some-command a b c",
+		"restart-sec":       10,
+		"restart-reset-sec": 300,
+		"exec-stop-post":    "/path/to/cleanup",
+		"logfile":           "/var/log/some-service.log",
+		"service-args":      []interface{}{"a", "b", "c"},
+	})
+	var fromJSON common.Conf
+	err = json.Unmarshal(data, &fromJSON)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fromJSON, jc.DeepEquals, conf)
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Drift describes how an installed service definition differs from
+// the expected one, one line at a time. Lines that are only expected
+// are prefixed with "-" and lines that are only installed with "+".
+// Identical definitions have no drift.
+func Drift(expected, installed []byte) []string {
+	expectedLines := splitLines(expected)
+	installedLines := splitLines(installed)
+	var drift []string
+	for i := 0; i < len(expectedLines) || i < len(installedLines); i++ {
+		var want, got string
+		if i < len(expectedLines) {
+			want = expectedLines[i]
+		}
+		if i < len(installedLines) {
+			got = installedLines[i]
+		}
+		if want == got {
+			continue
+		}
+		if i < len(expectedLines) {
+			drift = append(drift, fmt.Sprintf("line %d: -%s", i+1, want))
+		}
+		if i < len(installedLines) {
+			drift = append(drift, fmt.Sprintf("line %d: +%s", i+1, got))
+		}
+	}
+	return drift
+}
+
+func splitLines(data []byte) []string {
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,37 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"github.com/juju/testing"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/service/common"
+)
+
+type driftSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&driftSuite{})
+
+func (driftSuite) TestSame(c *gc.C) {
+	data := []byte("a\nb\nc\n")
+	c.Check(common.Drift(data, data), gc.HasLen, 0)
+}
+
+func (driftSuite) TestChangedLine(c *gc.C) {
+	drift := common.Drift([]byte("a\nb\nc\n"), []byte("a\nB\nc\n"))
+	c.Check(drift, gc.DeepEquals, []string{"line 2: -b", "line 2: +B"})
+}
+
+func (driftSuite) TestMissingLines(c *gc.C) {
+	drift := common.Drift([]byte("a\nb\nc\n"), []byte("a\n"))
+	c.Check(drift, gc.DeepEquals, []string{"line 2: -b", "line 3: -c"})
+}
+
+func (driftSuite) TestExtraLines(c *gc.C) {
+	drift := common.Drift([]byte("a\n"), []byte("a\nb\n"))
+	c.Check(drift, gc.DeepEquals, []string{"line 2: +b"})
+}
@@ -0,0 +1,26 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+// ServiceInfo describes the state of a service installed in an init
+// system.
+type ServiceInfo struct {
+	// Name is the name of the service.
+	Name string
+
+	// Running reports whether the service is currently running.
+	Running bool
+
+	// Enabled reports whether the init system starts the service
+	// when the host boots.
+	Enabled bool
+
+	// Pid holds the process id of the service's main process, or 0
+	// if it is not running or the init system does not track it.
+	Pid int
+
+	// InitSystem is the name of the init system managing the
+	// service.
+	InitSystem string
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/openrc"
 	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/service/windows"
@@ -36,6 +37,23 @@ func DiscoverService(name string, conf common.Conf) (Service, error) {
 	return service, nil
 }
 
+// DiscoverUserService returns an interface to a service, for the
+// current system, that is installed for and managed by the current
+// user. See NewUserService.
+func DiscoverUserService(name string, conf common.Conf) (Service, error) {
+	hostSeries := series.MustHostSeries()
+	initName, err := discoverInitSystem(hostSeries)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	service, err := newUserService(name, conf, initName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return service, nil
+}
+
 func discoverInitSystem(hostSeries string) (string, error) {
 	initName, err := discoverLocalInitSystem()
 	if errors.IsNotFound(err) {
@@ -101,6 +119,7 @@ type discoveryCheck struct {
 var discoveryFuncs = []discoveryCheck{
 	{InitSystemUpstart, upstart.IsRunning},
 	{InitSystemSystemd, systemd.IsRunning},
+	{InitSystemOpenRC, openrc.IsRunning},
 	{InitSystemWindows, windows.IsRunning},
 }
 
@@ -121,12 +140,18 @@ func discoverLocalInitSystem() (string, error) {
 
 const discoverInitSystemScript = `
 # Use guaranteed discovery mechanisms for known init systems.
-if [ -d /run/systemd/system ]; then
+if [ -d /run/systemd/system ] || [ -S /run/systemd/private ]; then
+    echo -n systemd
+    exit 0
+elif [ "$(cat /proc/1/comm 2>/dev/null)" = systemd ]; then
     echo -n systemd
     exit 0
 elif [ -f /sbin/initctl ] && /sbin/initctl --system list 2>&1 > /dev/null; then
     echo -n upstart
     exit 0
+elif [ -d /run/openrc ]; then
+    echo -n openrc
+    exit 0
 fi
 
 # uh-oh
@@ -4,6 +4,7 @@
 package service
 
 import (
+	"github.com/juju/juju/service/openrc"
 	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/service/windows"
@@ -12,3 +13,4 @@ import (
 var _ Service = (*upstart.Service)(nil)
 var _ Service = (*windows.Service)(nil)
 var _ Service = (*systemd.Service)(nil)
+var _ Service = (*openrc.Service)(nil)
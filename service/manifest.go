@@ -0,0 +1,92 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/service/common"
+)
+
+// Manifest holds a complete service definition, so that the service
+// may be recreated, e.g. after a machine is re-provisioned.
+type Manifest struct {
+	// Name is the name of the service.
+	Name string `yaml:"name" json:"name"`
+
+	// Conf is the service's configuration.
+	Conf common.Conf `yaml:"conf" json:"conf"`
+}
+
+// Validate checks that the manifest describes a service that may be
+// installed.
+func (m Manifest) Validate() error {
+	if m.Name == "" {
+		return errors.NotValidf("manifest with missing name")
+	}
+	return nil
+}
+
+// WriteManifest writes the manifest, in YAML format, to the given path.
+func WriteManifest(path string, m Manifest) error {
+	if err := m.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	data, err := goyaml.Marshal(m)
+	if err != nil {
+		return errors.Annotatef(err, "cannot marshal manifest for service %q", m.Name)
+	}
+	if err := utils.AtomicWriteFile(path, data, 0644); err != nil {
+		return errors.Annotate(err, "cannot write service manifest")
+	}
+	return nil
+}
+
+// ReadManifest reads a manifest, in YAML or JSON format, from the
+// given path.
+func ReadManifest(path string) (Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Manifest{}, errors.Annotate(err, "cannot read service manifest")
+	}
+	var m Manifest
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		err = json.Unmarshal(data, &m)
+	} else {
+		err = goyaml.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return Manifest{}, errors.Annotatef(err, "cannot parse service manifest %q", path)
+	}
+	if err := m.Validate(); err != nil {
+		return Manifest{}, errors.Annotatef(err, "service manifest %q", path)
+	}
+	return m, nil
+}
+
+// newManifestService is patched out during some tests.
+var newManifestService = DiscoverService
+
+// InstallFromManifest reads the manifest at the given path, then installs
+// and starts the service it describes using the host's init system.
+func InstallFromManifest(path string) (Service, error) {
+	m, err := ReadManifest(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	svc, err := newManifestService(m.Name, m.Conf)
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to find service %q", m.Name)
+	}
+	if err := InstallAndStart(svc); err != nil {
+		return nil, errors.Annotatef(err, "failed to install service %q", m.Name)
+	}
+	return svc, nil
+}
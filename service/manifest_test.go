@@ -0,0 +1,115 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+)
+
+type manifestSuite struct {
+	service.BaseSuite
+}
+
+var _ = gc.Suite(&manifestSuite{})
+
+func (s *manifestSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.Patched.Service = s.Service
+	s.PatchAttempts(5)
+}
+
+func (s *manifestSuite) manifest() service.Manifest {
+	conf := s.Conf
+	conf.Env = map[string]string{"JUJU_FOO": "bar"}
+	conf.Limit = map[string]int{"nofile": 20000}
+	conf.AfterStopped = "juju-db"
+	return service.Manifest{Name: s.Name, Conf: conf}
+}
+
+func (s *manifestSuite) TestWriteReadRoundTrip(c *gc.C) {
+	path := filepath.Join(s.Dirname, "manifest.yaml")
+	err := service.WriteManifest(path, s.manifest())
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := service.ReadManifest(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m, jc.DeepEquals, s.manifest())
+}
+
+func (s *manifestSuite) TestReadJSON(c *gc.C) {
+	path := filepath.Join(s.Dirname, "manifest.json")
+	err := ioutil.WriteFile(path, []byte(`{
+	"name": "juju-agent-machine-0",
+	"conf": {
+		"description": "some service",
+		"exec-start": "/bin/jujud machine 0",
+		"limit": {"nofile": 20000}
+	}
+}`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := service.ReadManifest(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m, jc.DeepEquals, service.Manifest{
+		Name: "juju-agent-machine-0",
+		Conf: common.Conf{
+			Desc:      "some service",
+			ExecStart: "/bin/jujud machine 0",
+			Limit:     map[string]int{"nofile": 20000},
+		},
+	})
+}
+
+func (s *manifestSuite) TestReadMissingName(c *gc.C) {
+	path := filepath.Join(s.Dirname, "manifest.yaml")
+	err := ioutil.WriteFile(path, []byte("conf:\n  description: some service\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = service.ReadManifest(path)
+	c.Assert(err, gc.ErrorMatches, `service manifest ".*": manifest with missing name not valid`)
+}
+
+func (s *manifestSuite) TestWriteMissingName(c *gc.C) {
+	err := service.WriteManifest(filepath.Join(s.Dirname, "manifest.yaml"), service.Manifest{})
+	c.Assert(err, gc.ErrorMatches, `manifest with missing name not valid`)
+}
+
+func (s *manifestSuite) TestInstallFromManifest(c *gc.C) {
+	path := filepath.Join(s.Dirname, "manifest.yaml")
+	err := service.WriteManifest(path, s.manifest())
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc, err := service.InstallFromManifest(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc, gc.Equals, s.Service)
+
+	s.Stub.CheckCallNames(c, "DiscoverService", "Install", "Stop", "Start")
+	s.Stub.CheckCall(c, 0, "DiscoverService", s.Name, s.manifest().Conf)
+}
+
+func (s *manifestSuite) TestInstallFromManifestFailDiscovery(c *gc.C) {
+	path := filepath.Join(s.Dirname, "manifest.yaml")
+	err := service.WriteManifest(path, s.manifest())
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.SetErrors(s.Failure)
+
+	_, err = service.InstallFromManifest(path)
+
+	s.CheckFailure(c, err)
+	s.Stub.CheckCallNames(c, "DiscoverService")
+}
+
+func (s *manifestSuite) TestInstallFromManifestMissingFile(c *gc.C) {
+	_, err := service.InstallFromManifest(filepath.Join(s.Dirname, "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, "cannot read service manifest: .*")
+	s.Stub.CheckNoCalls(c)
+}
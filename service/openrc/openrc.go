@@ -0,0 +1,463 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openrc
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"runtime"
+	"sort"
+	"text/template"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/shell"
+
+	"github.com/juju/juju/service/common"
+)
+
+var (
+	InitDir = "/etc/init.d" // the default init directory name.
+
+	logger       = loggo.GetLogger("juju.service.openrc")
+	runDir       = "/run/openrc"
+	runlevelsDir = "/etc/runlevels"
+	servicesRe   = regexp.MustCompile("^([a-zA-Z0-9-_:]+)$")
+	renderer     = &shell.BashRenderer{}
+)
+
+// runlevel is the OpenRC runlevel that juju services are added to.
+const runlevel = "default"
+
+// IsRunning returns whether or not OpenRC is the local init system.
+// OpenRC records its state under /run/openrc once it has booted the
+// host, so the presence of that directory is sufficient.
+func IsRunning() (bool, error) {
+	if runtime.GOOS == "windows" {
+		return false, nil
+	}
+
+	info, err := os.Stat(runDir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return info.IsDir(), nil
+}
+
+// ListServices returns the name of all installed services on the
+// local host.
+func ListServices() ([]string, error) {
+	fis, err := ioutil.ReadDir(InitDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var services []string
+	for _, fi := range fis {
+		if fi.IsDir() {
+			continue
+		}
+		if groups := servicesRe.FindStringSubmatch(fi.Name()); len(groups) > 0 {
+			services = append(services, groups[1])
+		}
+	}
+	return services, nil
+}
+
+// ListServicesWithInfo returns the state of all the services on the
+// local host. OpenRC records the services it has started under
+// /run/openrc/started, and those it starts at boot under
+// /etc/runlevels/default. It does not track process ids, so Pid is
+// always 0.
+func ListServicesWithInfo() ([]common.ServiceInfo, error) {
+	names, err := ListServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	infos := make([]common.ServiceInfo, len(names))
+	for i, name := range names {
+		running, err := exists(path.Join(runDir, "started", name))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		enabled, err := exists(path.Join(runlevelsDir, runlevel, name))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		infos[i] = common.ServiceInfo{
+			Name:       name,
+			Running:    running,
+			Enabled:    enabled,
+			InitSystem: "openrc",
+		}
+	}
+	return infos, nil
+}
+
+func exists(filename string) (bool, error) {
+	_, err := os.Lstat(filename)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// ListCommand returns a command that will list the services on a host.
+func ListCommand() string {
+	return `rc-service --list | sort | uniq`
+}
+
+// Service provides visibility into and control over an OpenRC service.
+type Service struct {
+	common.Service
+}
+
+// NewService returns a new OpenRC service with the given name and
+// configuration.
+func NewService(name string, conf common.Conf) *Service {
+	return &Service{
+		Service: common.Service{
+			Name: name,
+			Conf: conf,
+		},
+	}
+}
+
+// Name implements service.Service.
+func (s Service) Name() string {
+	return s.Service.Name
+}
+
+// Conf implements service.Service.
+func (s Service) Conf() common.Conf {
+	return s.Service.Conf
+}
+
+// confPath returns the path to the service's init script.
+func (s *Service) confPath() string {
+	return path.Join(InitDir, s.Service.Name)
+}
+
+// Validate returns an error if the service is not adequately defined.
+func (s *Service) Validate() error {
+	if err := s.Service.Validate(renderer); err != nil {
+		return errors.Trace(err)
+	}
+
+	if s.Service.Conf.Transient {
+		return errors.NotSupportedf("Conf.Transient")
+	}
+	if s.Service.Conf.AfterStopped != "" {
+		return errors.NotSupportedf("Conf.AfterStopped")
+	}
+	if s.Service.Conf.ExecStopPost != "" {
+		return errors.NotSupportedf("Conf.ExecStopPost")
+	}
+	return nil
+}
+
+// render returns the OpenRC init script for the service as a slice
+// of bytes.
+func (s *Service) render() ([]byte, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return Serialize(s.Name(), s.Conf())
+}
+
+// Installed returns whether the service's init script exists in the
+// init directory.
+func (s *Service) Installed() (bool, error) {
+	_, err := os.Stat(s.confPath())
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// Exists returns whether the service's init script exists in the
+// init directory with the same content that this Service would have
+// if installed.
+func (s *Service) Exists() (bool, error) {
+	_, same, _, err := s.existsAndSame()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return same, nil
+}
+
+func (s *Service) existsAndSame() (exists, same bool, conf []byte, err error) {
+	expected, err := s.render()
+	if err != nil {
+		return false, false, nil, errors.Trace(err)
+	}
+	current, err := ioutil.ReadFile(s.confPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			// no existing script
+			return false, false, expected, nil
+		}
+		return false, false, nil, errors.Trace(err)
+	}
+	return true, bytes.Equal(current, expected), expected, nil
+}
+
+// Running returns true if the Service appears to be running.
+func (s *Service) Running() (bool, error) {
+	out, err := exec.Command("rc-service", s.Service.Name, "status").CombinedOutput()
+	logger.Tracef("Running \"rc-service %s status\": %q", s.Service.Name, out)
+	if err == nil {
+		return true, nil
+	}
+	if _, ok := err.(*exec.ExitError); ok {
+		// rc-service exits non-zero for stopped, crashed and
+		// unknown services alike.
+		return false, nil
+	}
+	return false, errors.Trace(err)
+}
+
+// Start starts the service.
+func (s *Service) Start() error {
+	running, err := s.Running()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if running {
+		return nil
+	}
+	err = runCommand("rc-service", s.Service.Name, "start")
+	if err != nil {
+		// Double check to see if we were started before our command ran.
+		// If this fails then we simply trust it's okay.
+		if running, _ := s.Running(); running {
+			return nil
+		}
+	}
+	return err
+}
+
+func runCommand(args ...string) error {
+	out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	out = bytes.TrimSpace(out)
+	if len(out) > 0 {
+		return fmt.Errorf("exec %q: %v (%s)", args, err, out)
+	}
+	return fmt.Errorf("exec %q: %v", args, err)
+}
+
+// Stop stops the service.
+func (s *Service) Stop() error {
+	running, err := s.Running()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !running {
+		return nil
+	}
+	return runCommand("rc-service", s.Service.Name, "stop")
+}
+
+// Restart restarts the service.
+func (s *Service) Restart() error {
+	return runCommand("rc-service", s.Service.Name, "restart")
+}
+
+// Remove removes the service from the default runlevel and deletes
+// its init script.
+func (s *Service) Remove() error {
+	installed, err := s.Installed()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !installed {
+		return nil
+	}
+	if err := runCommand("rc-update", "del", s.Service.Name, runlevel); err != nil {
+		// The service may never have been added to the runlevel.
+		logger.Debugf("openrc: %v", err)
+	}
+	return os.Remove(s.confPath())
+}
+
+// Install writes the service's init script and adds it to the
+// default runlevel.
+func (s *Service) Install() error {
+	exists, same, conf, err := s.existsAndSame()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if same {
+		return nil
+	}
+	if exists {
+		if err := s.Stop(); err != nil {
+			return errors.Annotate(err, "openrc: could not stop installed service")
+		}
+		if err := s.Remove(); err != nil {
+			return errors.Annotate(err, "openrc: could not remove installed service")
+		}
+	}
+	if err := ioutil.WriteFile(s.confPath(), conf, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	if err := runCommand("rc-update", "add", s.Service.Name, runlevel); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// Verify implements service.VerifiableService.
+func (s *Service) Verify() ([]string, error) {
+	expected, err := s.render()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	installed, err := ioutil.ReadFile(s.confPath())
+	if os.IsNotExist(err) {
+		return []string{fmt.Sprintf("%s is missing", s.confPath())}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.Drift(expected, installed), nil
+}
+
+// Repair implements service.VerifiableService. OpenRC reads the init
+// script whenever it is used, so it only needs to be rewritten.
+func (s *Service) Repair() error {
+	conf, err := s.render()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(s.confPath(), conf, 0755))
+}
+
+// InstallCommands returns shell commands to install the service.
+func (s *Service) InstallCommands() ([]string, error) {
+	conf, err := s.render()
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		fmt.Sprintf("cat > %s << 'EOF'\n%sEOF\n", s.confPath(), conf),
+		"chmod 0755 " + s.confPath(),
+		fmt.Sprintf("rc-update add %s %s", s.Service.Name, runlevel),
+	}, nil
+}
+
+// StartCommands returns shell commands to start the service.
+func (s *Service) StartCommands() ([]string, error) {
+	return []string{fmt.Sprintf("rc-service %s start", s.Service.Name)}, nil
+}
+
+type scriptData struct {
+	Name        string
+	Desc        string
+	Env         []string
+	Limit       []string
+	ExtraScript string
+	Logfile     string
+	CommandArgs string
+	Before      []string
+	After       []string
+	Requires    []string
+}
+
+// Serialize renders the conf as an openrc-run init script.
+func Serialize(name string, conf common.Conf) ([]byte, error) {
+	data := scriptData{
+		Name:        name,
+		Desc:        conf.Desc,
+		ExtraScript: conf.ExtraScript,
+		Logfile:     conf.Logfile,
+		// openrc-run evals command_args, so the command is quoted
+		// once for the assignment and once for the eval.
+		CommandArgs: renderer.Quote("-c " + renderer.Quote("exec "+conf.ExecStart)),
+		Before:      conf.Before,
+		After:       conf.After,
+		Requires:    conf.Requires,
+	}
+	for k, v := range conf.Env {
+		data.Env = append(data.Env, fmt.Sprintf("export %s=%s", k, renderer.Quote(v)))
+	}
+	sort.Strings(data.Env)
+	for k, v := range conf.Limit {
+		flag, ok := ulimitFlags[k]
+		if !ok {
+			return nil, errors.NotSupportedf("limit %q", k)
+		}
+		data.Limit = append(data.Limit, fmt.Sprintf("ulimit -%s %d", flag, v))
+	}
+	sort.Strings(data.Limit)
+
+	var buf bytes.Buffer
+	if err := scriptT.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ulimitFlags maps the limit names used in common.Conf to the
+// corresponding ulimit flags.
+var ulimitFlags = map[string]string{
+	"core":    "c",
+	"data":    "d",
+	"fsize":   "f",
+	"memlock": "l",
+	"nofile":  "n",
+	"nproc":   "u",
+	"rss":     "m",
+	"stack":   "s",
+	"cpu":     "t",
+	"as":      "v",
+}
+
+// The service is run under supervise-daemon so that it is restarted
+// if it exits, matching the respawn behaviour of the other init
+// systems.
+var scriptT = template.Must(template.New("").Parse(`
+#!/sbin/openrc-run
+# Generated by juju.
+
+description="{{.Desc}}"
+supervisor="supervise-daemon"
+command="/bin/sh"
+command_args={{.CommandArgs}}
+pidfile="/run/{{.Name}}.pid"
+{{if .Logfile}}output_log="{{.Logfile}}"
+error_log="{{.Logfile}}"
+{{end}}{{range .Env}}{{.}}
+{{end}}
+depend() {
+	need net{{range .Requires}} {{.}}{{end}}
+	after firewall{{range .After}} {{.}}{{end}}
+{{if .Before}}	before{{range .Before}} {{.}}{{end}}
+{{end}}}
+
+start_pre() {
+{{range .Limit}}	{{.}}
+{{end}}{{if .ExtraScript}}{{.ExtraScript}}
+{{end}}{{if .Logfile}}	# Ensure log files are properly protected
+	touch {{.Logfile}}
+	chmod 0600 {{.Logfile}}
+{{end}}	return 0
+}
+`[1:]))
@@ -0,0 +1,307 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openrc_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/openrc"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func Test(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping openrc tests on windows")
+	}
+	gc.TestingT(t)
+}
+
+type OpenRCSuite struct {
+	coretesting.BaseSuite
+	testPath string
+	service  *openrc.Service
+	initDir  string
+}
+
+var _ = gc.Suite(&OpenRCSuite{})
+
+func (s *OpenRCSuite) SetUpTest(c *gc.C) {
+	s.testPath = c.MkDir()
+	s.initDir = c.MkDir()
+	s.PatchEnvPathPrepend(s.testPath)
+	s.PatchValue(&openrc.InitDir, s.initDir)
+	s.service = openrc.NewService(
+		"some-application",
+		common.Conf{
+			Desc:      "some service",
+			ExecStart: "/path/to/some-command",
+		},
+	)
+}
+
+// MakeTool writes a fake command which records its arguments in
+// <name>.args before running the given script.
+func (s *OpenRCSuite) MakeTool(c *gc.C, name, script string) {
+	path := filepath.Join(s.testPath, name)
+	argsPath := filepath.Join(s.testPath, name+".args")
+	content := "#!/bin/bash --norc\necho \"$@\" >> " + argsPath + "\n" + script
+	err := ioutil.WriteFile(path, []byte(content), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *OpenRCSuite) toolArgs(c *gc.C, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(s.testPath, name+".args"))
+	if os.IsNotExist(err) {
+		return ""
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *OpenRCSuite) TestIsRunning(c *gc.C) {
+	dir := c.MkDir()
+	s.PatchValue(openrc.RunDir, dir)
+	running, err := openrc.IsRunning()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsTrue)
+
+	s.PatchValue(openrc.RunDir, filepath.Join(dir, "missing"))
+	running, err = openrc.IsRunning()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsFalse)
+}
+
+func (s *OpenRCSuite) TestListServices(c *gc.C) {
+	for _, name := range []string{"jujud-machine-0", "sshd"} {
+		err := ioutil.WriteFile(filepath.Join(s.initDir, name), nil, 0755)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := os.Mkdir(filepath.Join(s.initDir, "subdir"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	services, err := openrc.ListServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(services, jc.SameContents, []string{"jujud-machine-0", "sshd"})
+}
+
+func (s *OpenRCSuite) TestListServicesWithInfo(c *gc.C) {
+	for _, name := range []string{"jujud-machine-0", "sshd", "cron"} {
+		err := ioutil.WriteFile(filepath.Join(s.initDir, name), nil, 0755)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	dir := c.MkDir()
+	s.PatchValue(openrc.RunDir, filepath.Join(dir, "run"))
+	s.PatchValue(openrc.RunlevelsDir, filepath.Join(dir, "runlevels"))
+	for _, link := range []string{
+		"run/started/jujud-machine-0",
+		"runlevels/default/jujud-machine-0",
+		"runlevels/default/cron",
+	} {
+		target := filepath.Join(dir, link)
+		err := os.MkdirAll(filepath.Dir(target), 0755)
+		c.Assert(err, jc.ErrorIsNil)
+		err = os.Symlink(filepath.Join(s.initDir, filepath.Base(link)), target)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	infos, err := openrc.ListServicesWithInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(infos, jc.SameContents, []common.ServiceInfo{{
+		Name:       "jujud-machine-0",
+		Running:    true,
+		Enabled:    true,
+		InitSystem: "openrc",
+	}, {
+		Name:       "sshd",
+		InitSystem: "openrc",
+	}, {
+		Name:       "cron",
+		Enabled:    true,
+		InitSystem: "openrc",
+	}})
+}
+
+func (s *OpenRCSuite) TestInstall(c *gc.C) {
+	s.MakeTool(c, "rc-update", "exit 0")
+
+	err := s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := os.Stat(filepath.Join(s.initDir, "some-application"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0755))
+	c.Check(s.toolArgs(c, "rc-update"), gc.Equals, "add some-application default\n")
+
+	exists, err := s.service.Exists()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(exists, jc.IsTrue)
+
+	// Installing again is a no-op.
+	err = s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.toolArgs(c, "rc-update"), gc.Equals, "add some-application default\n")
+}
+
+func (s *OpenRCSuite) TestVerifyMissing(c *gc.C) {
+	drift, err := s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, jc.DeepEquals, []string{
+		filepath.Join(s.initDir, "some-application") + " is missing",
+	})
+}
+
+func (s *OpenRCSuite) TestVerifyAndRepair(c *gc.C) {
+	s.MakeTool(c, "rc-update", "exit 0")
+	err := s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+	drift, err := s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, gc.HasLen, 0)
+
+	confPath := filepath.Join(s.initDir, "some-application")
+	err = ioutil.WriteFile(confPath, []byte("#!/bin/sh\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	drift, err = s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, jc.Contains, "line 1: +#!/bin/sh")
+
+	err = s.service.Repair()
+	c.Assert(err, jc.ErrorIsNil)
+	drift, err = s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, gc.HasLen, 0)
+	info, err := os.Stat(confPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0755))
+}
+
+func (s *OpenRCSuite) TestRemove(c *gc.C) {
+	s.MakeTool(c, "rc-update", "exit 0")
+	err := s.service.Install()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	installed, err := s.service.Installed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(installed, jc.IsFalse)
+	c.Check(s.toolArgs(c, "rc-update"), gc.Equals,
+		"add some-application default\ndel some-application default\n")
+}
+
+func (s *OpenRCSuite) TestRunning(c *gc.C) {
+	s.MakeTool(c, "rc-service", "exit 3")
+	running, err := s.service.Running()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsFalse)
+
+	s.MakeTool(c, "rc-service", "exit 0")
+	running, err = s.service.Running()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsTrue)
+}
+
+func (s *OpenRCSuite) TestStart(c *gc.C) {
+	s.MakeTool(c, "rc-service", `if [ "$2" = status ]; then exit 3; fi`)
+
+	err := s.service.Start()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.toolArgs(c, "rc-service"), gc.Equals,
+		"some-application status\nsome-application start\n")
+}
+
+func (s *OpenRCSuite) TestStopNotRunning(c *gc.C) {
+	s.MakeTool(c, "rc-service", "exit 3")
+
+	err := s.service.Stop()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.toolArgs(c, "rc-service"), gc.Equals, "some-application status\n")
+}
+
+func (s *OpenRCSuite) TestStop(c *gc.C) {
+	s.MakeTool(c, "rc-service", "exit 0")
+
+	err := s.service.Stop()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.toolArgs(c, "rc-service"), gc.Equals,
+		"some-application status\nsome-application stop\n")
+}
+
+func (s *OpenRCSuite) TestValidateTransient(c *gc.C) {
+	s.service.Service.Conf.Transient = true
+	err := s.service.Validate()
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *OpenRCSuite) TestInstallCommands(c *gc.C) {
+	s.service.Service.Conf.Env = map[string]string{"JUJU_FOO": "bar baz"}
+	s.service.Service.Conf.Limit = map[string]int{"nofile": 65000}
+	s.service.Service.Conf.Logfile = "/var/log/juju/some-application.log"
+
+	commands, err := s.service.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	confPath := filepath.Join(s.initDir, "some-application")
+	c.Assert(commands, jc.DeepEquals, []string{
+		"cat > " + confPath + " << 'EOF'\n" + `#!/sbin/openrc-run
+# Generated by juju.
+
+description="some service"
+supervisor="supervise-daemon"
+command="/bin/sh"
+command_args='-c '"'"'exec /path/to/some-command'"'"''
+pidfile="/run/some-application.pid"
+output_log="/var/log/juju/some-application.log"
+error_log="/var/log/juju/some-application.log"
+export JUJU_FOO='bar baz'
+
+depend() {
+	need net
+	after firewall
+}
+
+start_pre() {
+	ulimit -n 65000
+	# Ensure log files are properly protected
+	touch /var/log/juju/some-application.log
+	chmod 0600 /var/log/juju/some-application.log
+	return 0
+}
+EOF
+`,
+		"chmod 0755 " + confPath,
+		"rc-update add some-application default",
+	})
+}
+
+func (s *OpenRCSuite) TestInstallCommandsDependencies(c *gc.C) {
+	s.service.Service.Conf.Before = []string{"later-application"}
+	s.service.Service.Conf.After = []string{"network-application"}
+	s.service.Service.Conf.Requires = []string{"juju-db", "other-db"}
+
+	commands, err := s.service.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(commands, gc.HasLen, 3)
+	c.Check(commands[0], jc.Contains, `
+depend() {
+	need net juju-db other-db
+	after firewall network-application
+	before later-application
+}
+`)
+}
+
+func (s *OpenRCSuite) TestStartCommands(c *gc.C) {
+	commands, err := s.service.StartCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(commands, jc.DeepEquals, []string{"rc-service some-application start"})
+}
@@ -4,6 +4,8 @@
 package service
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/service/openrc"
 	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/service/windows"
@@ -27,6 +30,7 @@ var (
 const (
 	InitSystemSystemd = "systemd"
 	InitSystemUpstart = "upstart"
+	InitSystemOpenRC  = "openrc"
 	InitSystemWindows = "windows"
 )
 
@@ -35,6 +39,7 @@ const (
 var linuxInitSystems = []string{
 	InitSystemSystemd,
 	InitSystemUpstart,
+	InitSystemOpenRC,
 }
 
 // ServiceActions represents the actions that may be requested for
@@ -93,6 +98,21 @@ type RestartableService interface {
 	Restart() error
 }
 
+// VerifiableService is a service whose installed definition can be
+// checked against, and repaired to match, what its conf would render.
+type VerifiableService interface {
+	// Verify describes how the service definition installed in the
+	// init system differs from the one that the service's conf would
+	// render. No drift means that the installed definition is as
+	// expected.
+	Verify() ([]string, error)
+
+	// Repair replaces the installed service definition with the one
+	// that the service's conf renders. It does not stop or restart
+	// the service.
+	Repair() error
+}
+
 // TODO(ericsnow) bug #1426458
 // Eliminate the need to pass an empty conf for most service methods
 // and several helper functions.
@@ -121,6 +141,8 @@ func newService(name string, conf common.Conf, initSystem, series string) (Servi
 		return svc, nil
 	case InitSystemUpstart:
 		return upstart.NewService(name, conf), nil
+	case InitSystemOpenRC:
+		return openrc.NewService(name, conf), nil
 	case InitSystemSystemd:
 		dataDir, err := paths.DataDir(series)
 		if err != nil {
@@ -137,6 +159,67 @@ func newService(name string, conf common.Conf, initSystem, series string) (Servi
 	}
 }
 
+// NewUserService returns a new Service based on the provided info,
+// which is installed for and managed by the current user rather than
+// the system, so that it may be managed without root privileges.
+// Only the systemd and windows init systems support user services.
+//
+// On windows, per-user services are templates registered with the
+// service control manager, so installing or removing one still
+// requires administrator rights; only its instances run as each user
+// that logs on. Only systemd user services may be installed without
+// elevated privileges.
+var NewUserService = func(name string, conf common.Conf, series string) (Service, error) {
+	if name == "" {
+		return nil, errors.New("missing name")
+	}
+
+	initSystem, err := versionInitSystem(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newUserService(name, conf, initSystem)
+}
+
+func newUserService(name string, conf common.Conf, initSystem string) (Service, error) {
+	switch initSystem {
+	case InitSystemWindows:
+		svc, err := windows.NewUserService(name, conf)
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to wrap user service %q", name)
+		}
+		return svc, nil
+	case InitSystemSystemd:
+		dataDir, err := userDataDir()
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to find juju data dir for user service %q", name)
+		}
+		svc, err := systemd.NewUserService(name, conf, dataDir)
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to wrap user service %q", name)
+		}
+		return svc, nil
+	case InitSystemUpstart, InitSystemOpenRC:
+		return nil, errors.NotSupportedf("user services with init system %q", initSystem)
+	default:
+		return nil, errors.NotFoundf("init system %q", initSystem)
+	}
+}
+
+// userDataDir returns the directory that holds the files of the
+// current user's juju services, following the XDG base directory
+// specification. It is a variable so that it may be patched in tests.
+var userDataDir = func() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "juju"), nil
+	}
+	home := utils.Home()
+	if home == "" {
+		return "", errors.New("cannot determine home directory")
+	}
+	return filepath.Join(home, ".local", "share", "juju"), nil
+}
+
 // ListServices lists all installed services on the running system
 var ListServices = func() ([]string, error) {
 	hostSeries, err := series.HostSeries()
@@ -167,13 +250,78 @@ var ListServices = func() ([]string, error) {
 			return nil, errors.Annotatef(err, "failed to list %s services", initName)
 		}
 		return services, nil
+	case InitSystemOpenRC:
+		services, err := openrc.ListServices()
+		if err != nil {
+			return nil, errors.Annotatef(err, "failed to list %s services", initName)
+		}
+		return services, nil
+	default:
+		return nil, errors.NotFoundf("init system %q", initName)
+	}
+}
+
+// ListServicesWithInfo returns the state of all the services installed
+// on the running system, as reported by its init system. This saves
+// callers interested in a service's state from having to construct a
+// Service for it. Unlike ListServices, the init system is discovered
+// from the running host rather than inferred from its series.
+var ListServicesWithInfo = func() ([]common.ServiceInfo, error) {
+	initName, err := discoverLocalInitSystem()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var infos []common.ServiceInfo
+	switch initName {
+	case InitSystemWindows:
+		infos, err = windows.ListServicesWithInfo()
+	case InitSystemUpstart:
+		infos, err = upstart.ListServicesWithInfo()
+	case InitSystemSystemd:
+		infos, err = systemd.ListServicesWithInfo()
+	case InitSystemOpenRC:
+		infos, err = openrc.ListServicesWithInfo()
 	default:
 		return nil, errors.NotFoundf("init system %q", initName)
 	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "failed to list %s services", initName)
+	}
+	return infos, nil
+}
+
+// LocalServiceInfo returns the state of the named service installed on
+// the running system, or a NotFound error if there is no such service.
+// Where the init system allows, only that service is queried.
+var LocalServiceInfo = func(name string) (common.ServiceInfo, error) {
+	initName, err := discoverLocalInitSystem()
+	if err != nil {
+		return common.ServiceInfo{}, errors.Trace(err)
+	}
+
+	switch initName {
+	case InitSystemSystemd:
+		return systemd.ServiceInfo(name)
+	case InitSystemUpstart:
+		return upstart.ServiceInfo(name)
+	}
+	infos, err := ListServicesWithInfo()
+	if err != nil {
+		return common.ServiceInfo{}, errors.Trace(err)
+	}
+	for _, info := range infos {
+		if info.Name == name {
+			return info, nil
+		}
+	}
+	return common.ServiceInfo{}, errors.NotFoundf("service %q", name)
 }
 
 // ListServicesScript returns the commands that should be run to get
-// a list of service names on a host.
+// a list of service names on a host. It is intended for remote hosts,
+// such as in cloud-init; use ListServicesWithInfo on the running
+// system.
 func ListServicesScript() string {
 	commands := []string{
 		"init_system=$(" + DiscoverInitSystemScript() + ")",
@@ -193,6 +341,8 @@ func listServicesCommand(initSystem string) (string, bool) {
 		return upstart.ListCommand(), true
 	case InitSystemSystemd:
 		return systemd.ListCommand(), true
+	case InitSystemOpenRC:
+		return openrc.ListCommand(), true
 	default:
 		return "", false
 	}
@@ -69,12 +69,51 @@ func (s *serviceSuite) TestNewServiceUnknown(c *gc.C) {
 	c.Check(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *serviceSuite) TestNewUserServiceSystemd(c *gc.C) {
+	s.PatchEnvironment("XDG_DATA_HOME", "/home/user/.local/share")
+	svc, err := service.NewUserService(s.Name, s.Conf, "vivid")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(svc, gc.FitsTypeOf, &systemd.Service{})
+	c.Check(svc.(*systemd.Service).User, jc.IsTrue)
+	c.Check(svc.(*systemd.Service).Dirname, gc.Equals, "/home/user/.local/share/juju/init/"+s.Name)
+	c.Check(svc.Name(), gc.Equals, s.Name)
+}
+
+func (s *serviceSuite) TestNewUserServiceWindows(c *gc.C) {
+	svc, err := service.NewUserService(s.Name, s.Conf, "win2012")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(svc, gc.FitsTypeOf, &windows.Service{})
+	c.Check(svc.Name(), gc.Equals, s.Name)
+}
+
+func (s *serviceSuite) TestNewUserServiceNotSupported(c *gc.C) {
+	_, err := service.NewUserService(s.Name, s.Conf, "trusty")
+
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *serviceSuite) TestListServices(c *gc.C) {
 	_, err := service.ListServices()
 
 	c.Check(err, jc.ErrorIsNil)
 }
 
+func (s *serviceSuite) TestListServicesWithInfoNoInitSystem(c *gc.C) {
+	s.PatchLocalDiscoveryDisable()
+
+	_, err := service.ListServicesWithInfo()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *serviceSuite) TestLocalServiceInfoNoInitSystem(c *gc.C) {
+	s.PatchLocalDiscoveryDisable()
+
+	_, err := service.LocalServiceInfo("lxd")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (*serviceSuite) TestListServicesScript(c *gc.C) {
 	script := service.ListServicesScript()
 
@@ -90,6 +129,9 @@ func (*serviceSuite) TestListServicesScript(c *gc.C) {
 		`upstart)`,
 		`    sudo initctl list | awk '{print $1}' | sort | uniq`,
 		`    ;;`,
+		`openrc)`,
+		`    rc-service --list | sort | uniq`,
+		`    ;;`,
 		`*)`,
 		`    exit 1`,
 		`    ;;`,
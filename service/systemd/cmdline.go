@@ -6,11 +6,14 @@ package systemd
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/exec"
 	"github.com/juju/utils/shell"
+
+	"github.com/juju/juju/service/common"
 )
 
 const executable = "/bin/systemctl"
@@ -18,6 +21,10 @@ const executable = "/bin/systemctl"
 type commands struct {
 	shell.BashRenderer
 	binary string
+
+	// user is true if the commands address the calling user's
+	// instance of systemd rather than the system's.
+	user bool
 }
 
 func (c commands) resolve(args string) string {
@@ -25,6 +32,9 @@ func (c commands) resolve(args string) string {
 	if binary == "" {
 		binary = executable
 	}
+	if c.user {
+		binary += " --user"
+	}
 	return binary + " " + args
 }
 
@@ -40,6 +50,25 @@ func (c commands) listAll() string {
 	return c.resolve(args)
 }
 
+func (c commands) listUnitFiles() string {
+	args := `list-unit-files --no-legend --no-page -t service`
+	return c.resolve(args)
+}
+
+func (c commands) showInfo(unit string) string {
+	args := "show --property=Id,LoadState,ActiveState,MainPID,UnitFileState " + c.Quote(unit)
+	return c.resolve(args)
+}
+
+func (c commands) showState(units []string) string {
+	quoted := make([]string, len(units))
+	for i, unit := range units {
+		quoted[i] = c.Quote(unit)
+	}
+	args := "show --property=Id,LoadState,ActiveState,MainPID " + strings.Join(quoted, " ")
+	return c.resolve(args)
+}
+
 func (c commands) start(name string) string {
 	args := fmt.Sprintf("start %s.service", name)
 	return c.resolve(args)
@@ -119,6 +148,90 @@ func (cl Cmdline) ListAll() ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// ListAllWithInfo returns the state of all systemd services. It runs
+// systemctl twice: once to list the unit files, with their enablement
+// state, and once to fetch the runtime state of all of them.
+func (cl Cmdline) ListAllWithInfo() ([]common.ServiceInfo, error) {
+	out, err := cl.runCommand(cl.commands.listUnitFiles(), "List")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var infos []common.ServiceInfo
+	var units []string
+	index := make(map[string]int)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasSuffix(fields[0], ".service") {
+			continue
+		}
+		name := strings.TrimSuffix(fields[0], ".service")
+		if strings.HasSuffix(name, "@") {
+			// Templates have no state of their own.
+			continue
+		}
+		index[fields[0]] = len(infos)
+		units = append(units, fields[0])
+		infos = append(infos, common.ServiceInfo{
+			Name:       name,
+			Enabled:    fields[1] == "enabled" || fields[1] == "enabled-runtime",
+			InitSystem: "systemd",
+		})
+	}
+	if len(units) == 0 {
+		return nil, nil
+	}
+
+	out, err = cl.runCommand(cl.commands.showState(units), "Show")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, block := range strings.Split(out, "\n\n") {
+		props := make(map[string]string)
+		for _, line := range strings.Split(strings.TrimSpace(block), "\n") {
+			if parts := strings.SplitN(line, "=", 2); len(parts) == 2 {
+				props[parts[0]] = parts[1]
+			}
+		}
+		i, ok := index[props["Id"]]
+		if !ok {
+			continue
+		}
+		if props["LoadState"] == "loaded" && props["ActiveState"] == "active" {
+			infos[i].Running = true
+			infos[i].Pid, _ = strconv.Atoi(props["MainPID"])
+		}
+	}
+	return infos, nil
+}
+
+// Info returns the state of the named systemd service, or a NotFound
+// error if there is no such service.
+func (cl Cmdline) Info(name string) (common.ServiceInfo, error) {
+	out, err := cl.runCommand(cl.commands.showInfo(name+".service"), "Show")
+	if err != nil {
+		return common.ServiceInfo{}, errors.Trace(err)
+	}
+	props := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if parts := strings.SplitN(line, "=", 2); len(parts) == 2 {
+			props[parts[0]] = parts[1]
+		}
+	}
+	if props["LoadState"] == "not-found" {
+		return common.ServiceInfo{}, errors.NotFoundf("service %q", name)
+	}
+	info := common.ServiceInfo{
+		Name:       name,
+		Enabled:    props["UnitFileState"] == "enabled" || props["UnitFileState"] == "enabled-runtime",
+		InitSystem: "systemd",
+	}
+	if props["LoadState"] == "loaded" && props["ActiveState"] == "active" {
+		info.Running = true
+		info.Pid, _ = strconv.Atoi(props["MainPID"])
+	}
+	return info, nil
+}
+
 func (cl Cmdline) conf(name, dirname string) ([]byte, error) {
 	cmd := cl.commands.conf(name, dirname)
 
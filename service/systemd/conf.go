@@ -96,6 +96,16 @@ func normalize(name string, conf common.Conf, scriptPath string, renderer confRe
 		conf.Limit = nil
 	}
 
+	if len(conf.Before) == 0 {
+		conf.Before = nil
+	}
+	if len(conf.After) == 0 {
+		conf.After = nil
+	}
+	if len(conf.Requires) == 0 {
+		conf.Requires = nil
+	}
+
 	if conf.Transient {
 		// TODO(ericsnow) Handle Transient via systemd-run command?
 		conf.ExecStopPost = commands{}.disable(name)
@@ -136,9 +146,23 @@ func validate(name string, conf common.Conf, renderer shell.Renderer) error {
 	return nil
 }
 
+// The targets that juju's services are wanted by. A user's instance of
+// systemd has no multi-user.target, so user services are started with
+// the user's default.target instead.
+const (
+	systemTarget = "multi-user.target"
+	userTarget   = "default.target"
+)
+
 // serialize returns the data that should be written to disk for the
 // provided Conf, rendered in the systemd unit file format.
 func serialize(name string, conf common.Conf, renderer shell.Renderer) ([]byte, error) {
+	return serializeForTarget(name, conf, renderer, systemTarget)
+}
+
+// serializeForTarget is like serialize, but the rendered unit is
+// wanted by the given target.
+func serializeForTarget(name string, conf common.Conf, renderer shell.Renderer, target string) ([]byte, error) {
 	if err := validate(name, conf, renderer); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -146,7 +170,7 @@ func serialize(name string, conf common.Conf, renderer shell.Renderer) ([]byte,
 	var unitOptions []*unit.UnitOption
 	unitOptions = append(unitOptions, serializeUnit(conf)...)
 	unitOptions = append(unitOptions, serializeService(conf)...)
-	unitOptions = append(unitOptions, serializeInstall(conf)...)
+	unitOptions = append(unitOptions, serializeInstall(target)...)
 	// Don't use unit.Serialize because it has map ordering issues.
 	// Serialize copied locally, and outputs sections in alphabetical order.
 	data, err := ioutil.ReadAll(UnitSerialize(unitOptions))
@@ -164,12 +188,7 @@ func serializeUnit(conf common.Conf) []*unit.UnitOption {
 		})
 	}
 
-	after := []string{
-		"syslog.target",
-		"network.target",
-		"systemd-user-sessions.service",
-	}
-	for _, name := range after {
+	for _, name := range defaultAfter {
 		unitOptions = append(unitOptions, &unit.UnitOption{
 			Section: "Unit",
 			Name:    "After",
@@ -185,9 +204,50 @@ func serializeUnit(conf common.Conf) []*unit.UnitOption {
 		})
 	}
 
+	// Requires does not imply any ordering in systemd, but it does in
+	// common.Conf, so required units are also started before this one.
+	for _, dep := range []struct {
+		directive string
+		names     []string
+	}{
+		{"Before", conf.Before},
+		{"After", conf.After},
+		{"After", conf.Requires},
+		{"Requires", conf.Requires},
+	} {
+		for _, name := range dep.names {
+			unitOptions = append(unitOptions, &unit.UnitOption{
+				Section: "Unit",
+				Name:    dep.directive,
+				Value:   unitName(name),
+			})
+		}
+	}
+
 	return unitOptions
 }
 
+// defaultAfter holds the units that every juju service starts after.
+var defaultAfter = []string{
+	"syslog.target",
+	"network.target",
+	"systemd-user-sessions.service",
+}
+
+// unitName returns the name of the systemd unit for the named service.
+// Names without a unit type suffix are taken to be those of services.
+func unitName(name string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	return name + ".service"
+}
+
+// serviceName reverses unitName.
+func serviceName(unitName string) string {
+	return strings.TrimSuffix(unitName, ".service")
+}
+
 func serializeService(conf common.Conf) []*unit.UnitOption {
 	var unitOptions []*unit.UnitOption
 
@@ -210,6 +270,14 @@ func serializeService(conf common.Conf) []*unit.UnitOption {
 		})
 	}
 
+	if conf.WorkingDirectory != "" {
+		unitOptions = append(unitOptions, &unit.UnitOption{
+			Section: "Service",
+			Name:    "WorkingDirectory",
+			Value:   conf.WorkingDirectory,
+		})
+	}
+
 	if conf.ExecStart != "" {
 		unitOptions = append(unitOptions, &unit.UnitOption{
 			Section: "Service",
@@ -246,13 +314,13 @@ func serializeService(conf common.Conf) []*unit.UnitOption {
 	return unitOptions
 }
 
-func serializeInstall(conf common.Conf) []*unit.UnitOption {
+func serializeInstall(target string) []*unit.UnitOption {
 	var unitOptions []*unit.UnitOption
 
 	unitOptions = append(unitOptions, &unit.UnitOption{
 		Section: "Install",
 		Name:    "WantedBy",
-		Value:   "multi-user.target",
+		Value:   target,
 	})
 
 	return unitOptions
@@ -271,14 +339,26 @@ func deserialize(data []byte, renderer shell.Renderer) (common.Conf, error) {
 func deserializeOptions(opts []*unit.UnitOption, renderer shell.Renderer) (common.Conf, error) {
 	var conf common.Conf
 
+	// Required units are also listed as After, but only appear in
+	// conf.Requires; see serializeUnit.
+	var after []string
+	required := make(map[string]bool)
 	for _, uo := range opts {
 		switch uo.Section {
 		case "Unit":
 			switch uo.Name {
 			case "Description":
 				conf.Desc = uo.Value
+			case "Before":
+				conf.Before = append(conf.Before, serviceName(uo.Value))
 			case "After":
-				// Do nothing until we support it in common.Conf.
+				if !isDefaultAfter(uo.Value) {
+					after = append(after, serviceName(uo.Value))
+				}
+			case "Requires":
+				name := serviceName(uo.Value)
+				conf.Requires = append(conf.Requires, name)
+				required[name] = true
 			default:
 				return conf, errors.NotSupportedf("Unit directive %q", uo.Name)
 			}
@@ -313,6 +393,8 @@ func deserializeOptions(opts []*unit.UnitOption, renderer shell.Renderer) (commo
 						break
 					}
 				}
+			case uo.Name == "WorkingDirectory":
+				conf.WorkingDirectory = uo.Value
 			case uo.Name == "TimeoutSec":
 				timeout, err := strconv.Atoi(uo.Value)
 				if err != nil {
@@ -331,7 +413,7 @@ func deserializeOptions(opts []*unit.UnitOption, renderer shell.Renderer) (commo
 		case "Install":
 			switch uo.Name {
 			case "WantedBy":
-				if uo.Value != "multi-user.target" {
+				if uo.Value != systemTarget && uo.Value != userTarget {
 					return conf, errors.NotValidf("unit target %q", uo.Value)
 				}
 			default:
@@ -341,11 +423,25 @@ func deserializeOptions(opts []*unit.UnitOption, renderer shell.Renderer) (commo
 			return conf, errors.NotSupportedf("section %q", uo.Name)
 		}
 	}
+	for _, name := range after {
+		if !required[name] {
+			conf.After = append(conf.After, name)
+		}
+	}
 
 	err := validate("<>", conf, renderer)
 	return conf, errors.Trace(err)
 }
 
+func isDefaultAfter(name string) bool {
+	for _, defaultName := range defaultAfter {
+		if name == defaultName {
+			return true
+		}
+	}
+	return false
+}
+
 // CleanShutdownService is added to machines to ensure DHCP-assigned
 // IP addresses are released on shutdown, reboot, or halt. See bug
 // http://pad.lv/1348663 for more info.
@@ -0,0 +1,99 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// The locations inspected when detecting systemd. They are variables
+// so that they may be patched in tests.
+var (
+	// runDir only exists when the host was booted with systemd;
+	// see sd_booted(3).
+	runDir = "/run/systemd/system"
+
+	// privateSocket is systemd's private D-Bus socket, used by
+	// systemctl when the system bus is not available.
+	privateSocket = "/run/systemd/private"
+
+	// initCommPath holds the name of PID 1.
+	initCommPath = "/proc/1/comm"
+)
+
+// IsRunning returns whether or not systemd is the local init system.
+//
+// The canonical check is for the existence of /run/systemd/system, but
+// that directory may be missing in some containers and chroots even
+// though systemd is PID 1. In that case we fall back to checking for
+// systemd's private D-Bus socket and the name of PID 1. Membership of
+// a systemd cgroup is not enough, since hosts running another init
+// system may mount systemd's cgroup hierarchy.
+func IsRunning() (bool, error) {
+	checks := []struct {
+		name  string
+		check func() (bool, error)
+	}{
+		{"run directory", isDir(runDir)},
+		{"private socket", isSocket(privateSocket)},
+		{"init process name", initIsSystemd},
+	}
+	for _, c := range checks {
+		ok, err := c.check()
+		if err != nil {
+			return false, errors.Annotatef(err, "checking systemd %s", c.name)
+		}
+		if ok {
+			logger.Tracef("systemd detected by %s", c.name)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isDir(path string) func() (bool, error) {
+	return func() (bool, error) {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return false, nil
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		return info.IsDir(), nil
+	}
+}
+
+func isSocket(path string) func() (bool, error) {
+	return func() (bool, error) {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return false, nil
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		return info.Mode()&os.ModeSocket != 0, nil
+	}
+}
+
+// readProcFile reads the named file, treating a missing or unreadable
+// file as empty; /proc may not be mounted, or may be restricted.
+func readProcFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) || os.IsPermission(err) {
+		return nil, nil
+	}
+	return data, errors.Trace(err)
+}
+
+func initIsSystemd() (bool, error) {
+	data, err := readProcFile(initCommPath)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return strings.TrimSpace(string(data)) == "systemd", nil
+}
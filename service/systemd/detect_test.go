@@ -0,0 +1,89 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemd_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/service/systemd"
+)
+
+type detectSuite struct {
+	testing.IsolationSuite
+	dir string
+}
+
+var _ = gc.Suite(&detectSuite{})
+
+func (s *detectSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	if runtime.GOOS == "windows" {
+		c.Skip("systemd detection is not relevant on windows")
+	}
+	s.dir = c.MkDir()
+	systemd.PatchDetectionPaths(s, s.dir)
+}
+
+func (s *detectSuite) writeFile(c *gc.C, name, content string) {
+	path := filepath.Join(s.dir, name)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *detectSuite) assertIsRunning(c *gc.C, expected bool) {
+	running, err := systemd.IsRunning()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, gc.Equals, expected)
+}
+
+func (s *detectSuite) TestNotRunning(c *gc.C) {
+	s.writeFile(c, "proc/1/comm", "init\n")
+	s.writeFile(c, "proc/1/cgroup", "4:cpu,cpuacct:/\n1:cpuset:/\n")
+	s.assertIsRunning(c, false)
+}
+
+func (s *detectSuite) TestNoProc(c *gc.C) {
+	s.assertIsRunning(c, false)
+}
+
+func (s *detectSuite) TestRunDirectory(c *gc.C) {
+	err := os.MkdirAll(filepath.Join(s.dir, "run", "systemd", "system"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertIsRunning(c, true)
+}
+
+func (s *detectSuite) TestPrivateSocket(c *gc.C) {
+	err := os.MkdirAll(filepath.Join(s.dir, "run", "systemd"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	l, err := net.Listen("unix", filepath.Join(s.dir, "run", "systemd", "private"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer l.Close()
+	s.assertIsRunning(c, true)
+}
+
+func (s *detectSuite) TestPrivateSocketNotSocket(c *gc.C) {
+	s.writeFile(c, "run/systemd/private", "")
+	s.assertIsRunning(c, false)
+}
+
+func (s *detectSuite) TestInitName(c *gc.C) {
+	s.writeFile(c, "proc/1/comm", "systemd\n")
+	s.assertIsRunning(c, true)
+}
+
+func (s *detectSuite) TestSystemdCgroupOnly(c *gc.C) {
+	s.writeFile(c, "proc/1/comm", "init\n")
+	s.writeFile(c, "proc/1/cgroup", "4:cpu,cpuacct:/\n1:name=systemd:/\n")
+	s.assertIsRunning(c, false)
+}
@@ -4,6 +4,8 @@
 package systemd
 
 import (
+	"path/filepath"
+
 	"github.com/juju/testing"
 )
 
@@ -28,6 +30,12 @@ func PatchNewConn(patcher patcher, stub *testing.Stub) *StubDbusAPI {
 	return conn
 }
 
+func PatchNewUserConn(patcher patcher, stub *testing.Stub) *StubDbusAPI {
+	conn := &StubDbusAPI{Stub: stub}
+	patcher.PatchValue(&newUserConn, func() (dbusAPI, error) { return conn, nil })
+	return conn
+}
+
 func PatchFileOps(patcher patcher, stub *testing.Stub) *StubFileOps {
 	fops := &StubFileOps{Stub: stub}
 	patcher.PatchValue(&removeAll, fops.RemoveAll)
@@ -41,3 +49,11 @@ func PatchExec(patcher patcher, stub *testing.Stub) *StubExec {
 	patcher.PatchValue(&runCommands, exec.RunCommand)
 	return exec
 }
+
+// PatchDetectionPaths replaces the paths inspected by IsRunning with
+// paths under the given directory.
+func PatchDetectionPaths(patcher patcher, dir string) {
+	patcher.PatchValue(&runDir, filepath.Join(dir, "run", "systemd", "system"))
+	patcher.PatchValue(&privateSocket, filepath.Join(dir, "run", "systemd", "private"))
+	patcher.PatchValue(&initCommPath, filepath.Join(dir, "proc", "1", "comm"))
+}
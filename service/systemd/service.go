@@ -4,6 +4,7 @@
 package systemd
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"path"
@@ -22,20 +23,9 @@ var (
 	logger = loggo.GetLogger("juju.service.systemd")
 
 	renderer = shell.BashRenderer{}
-	cmds     = commands{renderer, executable}
+	cmds     = commands{BashRenderer: renderer, binary: executable}
 )
 
-// IsRunning returns whether or not systemd is the local init system.
-func IsRunning() (bool, error) {
-	if _, err := os.Stat("/run/systemd/system"); err == nil {
-		return true, nil
-	} else if os.IsNotExist(err) {
-		return false, nil
-	} else {
-		return false, errors.Trace(err)
-	}
-}
-
 // ListServices returns the list of installed service names.
 func ListServices() ([]string, error) {
 	// TODO(ericsnow) conn.ListUnits misses some inactive units, so we
@@ -50,6 +40,26 @@ func ListServices() ([]string, error) {
 	return names, nil
 }
 
+// ListServicesWithInfo returns the state of all the services on the
+// local host.
+func ListServicesWithInfo() ([]common.ServiceInfo, error) {
+	infos, err := Cmdline{}.ListAllWithInfo()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return infos, nil
+}
+
+// ServiceInfo returns the state of the named service on the local
+// host, or a NotFound error if there is no such service.
+func ServiceInfo(name string) (common.ServiceInfo, error) {
+	info, err := Cmdline{}.Info(name)
+	if err != nil {
+		return common.ServiceInfo{}, errors.Trace(err)
+	}
+	return info, nil
+}
+
 // ListCommand returns a command that will list the services on a host.
 func ListCommand() string {
 	return cmds.listAll()
@@ -63,10 +73,30 @@ type Service struct {
 	UnitName string
 	Dirname  string
 	Script   []byte
+
+	// User is true if the service is managed by the calling user's
+	// instance of systemd (systemctl --user) rather than the system's,
+	// so that it may be managed without root privileges.
+	User bool
 }
 
 // NewService returns a new value that implements Service for systemd.
 func NewService(name string, conf common.Conf, dataDir string) (*Service, error) {
+	return newService(name, conf, dataDir, false)
+}
+
+// NewUserService returns a new value that implements Service for the
+// calling user's instance of systemd. The service's files are kept in
+// the given data directory, which must be writable by the user, and
+// the unit is linked into the user's unit directory when installed.
+//
+// A user's services only run while the user is logged in, unless
+// lingering is enabled for the user (see loginctl(1)).
+func NewUserService(name string, conf common.Conf, dataDir string) (*Service, error) {
+	return newService(name, conf, dataDir, true)
+}
+
+func newService(name string, conf common.Conf, dataDir string, user bool) (*Service, error) {
 	confName := name + ".service"
 	var volName string
 	if conf.ExecStart != "" {
@@ -82,6 +112,7 @@ func NewService(name string, conf common.Conf, dataDir string) (*Service, error)
 		ConfName: confName,
 		UnitName: confName,
 		Dirname:  dirname,
+		User:     user,
 	}
 
 	if err := service.setConf(conf); err != nil {
@@ -109,6 +140,10 @@ var newConn = func() (dbusAPI, error) {
 	return dbus.New()
 }
 
+var newUserConn = func() (dbusAPI, error) {
+	return dbus.NewUserConnection()
+}
+
 var newChan = func() chan string {
 	return make(chan string)
 }
@@ -137,8 +172,20 @@ func (s Service) Conf() common.Conf {
 	return s.Service.Conf
 }
 
+// commands returns the commands that manage the service in the
+// instance of systemd it belongs to.
+func (s *Service) commands() commands {
+	c := cmds
+	c.user = s.User
+	return c
+}
+
 func (s *Service) serialize() ([]byte, error) {
-	data, err := serialize(s.UnitName, s.Service.Conf, renderer)
+	target := systemTarget
+	if s.User {
+		target = userTarget
+	}
+	data, err := serializeForTarget(s.UnitName, s.Service.Conf, renderer, target)
 	if err != nil {
 		return nil, s.errorf(err, "failed to serialize conf")
 	}
@@ -183,7 +230,7 @@ func (s *Service) setConf(conf common.Conf) error {
 
 // Installed implements Service.
 func (s *Service) Installed() (bool, error) {
-	names, err := ListServices()
+	names, err := Cmdline{commands: s.commands()}.ListAll()
 	if err != nil {
 		return false, s.errorf(err, "failed to list services")
 	}
@@ -232,8 +279,59 @@ func (s *Service) readConf() (common.Conf, error) {
 	return conf, nil
 }
 
+// Verify implements service.VerifiableService.
+func (s *Service) Verify() ([]string, error) {
+	if s.NoConf() {
+		return nil, s.errorf(nil, "no conf expected")
+	}
+	installed, err := s.Installed()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !installed {
+		return []string{"service is not installed"}, nil
+	}
+
+	expected, err := s.serialize()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	actual, err := Cmdline{}.conf(s.Service.Name, s.Dirname)
+	if err != nil {
+		return nil, s.errorf(err, "failed to read conf from systemd")
+	}
+	return common.Drift(bytes.TrimSpace(expected), actual), nil
+}
+
+// Repair implements service.VerifiableService. The unit file is
+// rewritten and systemd is told to reload it; the running service is
+// left alone until it is next restarted.
+func (s *Service) Repair() error {
+	if s.NoConf() {
+		return s.errorf(nil, "missing conf")
+	}
+	if _, err := s.writeConf(); err != nil {
+		return errors.Trace(err)
+	}
+
+	conn, err := s.newConn()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer conn.Close()
+
+	if err := conn.Reload(); err != nil {
+		return s.errorf(err, "dbus post-repair daemon reload request failed")
+	}
+	return nil
+}
+
 func (s Service) newConn() (dbusAPI, error) {
-	conn, err := newConn()
+	connect := newConn
+	if s.User {
+		connect = newUserConn
+	}
+	conn, err := connect()
 	if err != nil {
 		logger.Errorf("failed to connect to dbus for application %q: %v", s.Service.Name, err)
 	}
@@ -528,6 +626,7 @@ func (s *Service) InstallCommands() ([]string, error) {
 
 	name := s.Name()
 	dirname := s.Dirname
+	cmds := s.commands()
 
 	data, err := s.serialize()
 	if err != nil {
@@ -558,7 +657,7 @@ func (s *Service) InstallCommands() ([]string, error) {
 func (s *Service) StartCommands() ([]string, error) {
 	name := s.Name()
 	cmdList := []string{
-		cmds.start(name),
+		s.commands().start(name),
 	}
 	return cmdList, nil
 }
@@ -219,6 +219,86 @@ func (s *initSystemSuite) TestListServicesEmpty(c *gc.C) {
 	s.stub.CheckCallNames(c, "RunCommand")
 }
 
+func (s *initSystemSuite) TestListServicesWithInfo(c *gc.C) {
+	s.exec.Responses = append(s.exec.Responses, exec.ExecResponse{
+		Stdout: []byte(`
+jujud-machine-0.service  enabled
+getty@.service           enabled
+another.service          disabled
+`[1:]),
+	}, exec.ExecResponse{
+		Stdout: []byte(`
+Id=jujud-machine-0.service
+LoadState=loaded
+ActiveState=active
+MainPID=1234
+
+Id=another.service
+LoadState=loaded
+ActiveState=inactive
+MainPID=0
+`[1:]),
+	})
+
+	infos, err := systemd.ListServicesWithInfo()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(infos, jc.DeepEquals, []common.ServiceInfo{{
+		Name:       "jujud-machine-0",
+		Running:    true,
+		Enabled:    true,
+		Pid:        1234,
+		InitSystem: "systemd",
+	}, {
+		Name:       "another",
+		InitSystem: "systemd",
+	}})
+	s.stub.CheckCallNames(c, "RunCommand", "RunCommand")
+	args := s.stub.Calls()[1].Args[0].(exec.RunParams)
+	c.Check(args.Commands, gc.Equals, "/bin/systemctl show --property=Id,LoadState,ActiveState,MainPID 'jujud-machine-0.service' 'another.service'")
+}
+
+func (s *initSystemSuite) TestServiceInfo(c *gc.C) {
+	s.exec.Responses = append(s.exec.Responses, exec.ExecResponse{
+		Stdout: []byte(`
+Id=jujud-machine-0.service
+LoadState=loaded
+ActiveState=active
+MainPID=1234
+UnitFileState=enabled
+`[1:]),
+	})
+
+	info, err := systemd.ServiceInfo("jujud-machine-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(info, jc.DeepEquals, common.ServiceInfo{
+		Name:       "jujud-machine-0",
+		Running:    true,
+		Enabled:    true,
+		Pid:        1234,
+		InitSystem: "systemd",
+	})
+	s.stub.CheckCallNames(c, "RunCommand")
+	args := s.stub.Calls()[0].Args[0].(exec.RunParams)
+	c.Check(args.Commands, gc.Equals, "/bin/systemctl show --property=Id,LoadState,ActiveState,MainPID,UnitFileState 'jujud-machine-0.service'")
+}
+
+func (s *initSystemSuite) TestServiceInfoNotFound(c *gc.C) {
+	s.exec.Responses = append(s.exec.Responses, exec.ExecResponse{
+		Stdout: []byte(`
+Id=missing.service
+LoadState=not-found
+ActiveState=inactive
+MainPID=0
+UnitFileState=
+`[1:]),
+	})
+
+	_, err := systemd.ServiceInfo("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *initSystemSuite) TestNewService(c *gc.C) {
 	service := s.newService(c)
 	c.Check(service, jc.DeepEquals, &systemd.Service{
@@ -407,6 +487,76 @@ func (s *initSystemSuite) TestExistsTrue(c *gc.C) {
 	s.stub.CheckCallNames(c, "RunCommand")
 }
 
+func (s *initSystemSuite) TestExistsWorkingDirectory(c *gc.C) {
+	s.conf.WorkingDirectory = "/var/lib/juju"
+	s.service = s.newService(c)
+	s.setConf(c, s.conf)
+
+	exists, err := s.service.Exists()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(exists, jc.IsTrue)
+}
+
+func (s *initSystemSuite) TestInstallCommandsWorkingDirectory(c *gc.C) {
+	name := "jujud-machine-0"
+	s.conf.WorkingDirectory = "/var/lib/juju"
+	s.service = s.newService(c)
+	commands, err := s.service.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+
+	test := systemdtesting.WriteConfTest{
+		Service: name,
+		DataDir: s.dataDir,
+		Expected: strings.Replace(
+			s.newConfStr(name),
+			"ExecStart=",
+			"WorkingDirectory=/var/lib/juju\nExecStart=",
+			1,
+		),
+	}
+	test.CheckCommands(c, commands)
+}
+
+func (s *initSystemSuite) TestExistsDependencies(c *gc.C) {
+	s.conf.Before = []string{"juju-shutdown-job"}
+	s.conf.After = []string{"cloud-init.target"}
+	s.conf.Requires = []string{"juju-db"}
+	s.service = s.newService(c)
+	s.setConf(c, s.conf)
+
+	exists, err := s.service.Exists()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(exists, jc.IsTrue)
+}
+
+func (s *initSystemSuite) TestInstallCommandsDependencies(c *gc.C) {
+	name := "jujud-machine-0"
+	s.conf.Before = []string{"juju-shutdown-job"}
+	s.conf.After = []string{"cloud-init.target"}
+	s.conf.Requires = []string{"juju-db"}
+	s.service = s.newService(c)
+	commands, err := s.service.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+
+	test := systemdtesting.WriteConfTest{
+		Service: name,
+		DataDir: s.dataDir,
+		Expected: strings.Replace(
+			s.newConfStr(name),
+			"After=systemd-user-sessions.service\n",
+			"After=systemd-user-sessions.service\n"+
+				"Before=juju-shutdown-job.service\n"+
+				"After=cloud-init.target\n"+
+				"After=juju-db.service\n"+
+				"Requires=juju-db.service\n",
+			1,
+		),
+	}
+	test.CheckCommands(c, commands)
+}
+
 func (s *initSystemSuite) TestExistsFalse(c *gc.C) {
 	// We force the systemd API to return a slightly different conf.
 	// In this case we simply set Conf.Env, which s.conf does not set.
@@ -444,6 +594,54 @@ func (s *initSystemSuite) TestExistsEmptyConf(c *gc.C) {
 	s.stub.CheckCalls(c, nil)
 }
 
+func (s *initSystemSuite) TestVerifyInSync(c *gc.C) {
+	s.addService("jujud-machine-0", "active")
+	s.addListResponse()
+	s.setConf(c, s.conf)
+
+	drift, err := s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(drift, gc.HasLen, 0)
+	s.stub.CheckCallNames(c, "RunCommand", "RunCommand")
+}
+
+func (s *initSystemSuite) TestVerifyDrift(c *gc.C) {
+	s.addService("jujud-machine-0", "active")
+	s.addListResponse()
+	s.setConf(c, common.Conf{
+		Desc:      s.conf.Desc,
+		ExecStart: s.conf.ExecStart,
+		Env:       map[string]string{"a": "b"},
+	})
+
+	drift, err := s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(drift, gc.Not(gc.HasLen), 0)
+	c.Check(strings.Join(drift, "\n"), jc.Contains, "+Environment=\"a=b\"")
+	s.stub.CheckCallNames(c, "RunCommand", "RunCommand")
+}
+
+func (s *initSystemSuite) TestVerifyNotInstalled(c *gc.C) {
+	s.addService("something-else", "active")
+	s.addListResponse()
+
+	drift, err := s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(drift, jc.DeepEquals, []string{"service is not installed"})
+	s.stub.CheckCallNames(c, "RunCommand")
+}
+
+func (s *initSystemSuite) TestRepair(c *gc.C) {
+	err := s.service.Repair()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "MkdirAll", "CreateFile", "Reload", "Close")
+	s.checkCreateFileCall(c, 1, s.name, "", 0644)
+}
+
 func (s *initSystemSuite) TestRunningTrue(c *gc.C) {
 	s.addService("jujud-machine-0", "active")
 	s.addService("something-else", "error")
@@ -858,3 +1056,66 @@ func (s *initSystemSuite) TestStartCommands(c *gc.C) {
 		"/bin/systemctl start jujud-machine-0.service",
 	})
 }
+
+func (s *initSystemSuite) newUserService(c *gc.C) *systemd.Service {
+	svc, err := systemd.NewUserService(s.name, s.conf, "/home/user/.local/share/juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.User, jc.IsTrue)
+	return svc
+}
+
+func (s *initSystemSuite) TestUserServiceInstalled(c *gc.C) {
+	s.addService("jujud-machine-0", "active")
+	s.addListResponse()
+
+	installed, err := s.newUserService(c).Installed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(installed, jc.IsTrue)
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		FuncName: "RunCommand",
+		Args: []interface{}{exec.RunParams{
+			Commands: `/bin/systemctl --user list-unit-files --no-legend --no-page -t service | grep -o -P '^\w[\S]*(?=\.service)'`,
+		}},
+	}})
+}
+
+func (s *initSystemSuite) TestUserServiceRunning(c *gc.C) {
+	userStub := &testing.Stub{}
+	userConn := systemd.PatchNewUserConn(s, userStub)
+	userConn.AddService("jujud-machine-0", "juju agent for machine-0", "active")
+
+	running, err := s.newUserService(c).Running()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(running, jc.IsTrue)
+	userStub.CheckCallNames(c, "ListUnits", "Close")
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *initSystemSuite) TestUserServiceInstallCommands(c *gc.C) {
+	name := "jujud-machine-0"
+	commands, err := s.newUserService(c).InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+
+	test := systemdtesting.WriteConfTest{
+		Service: name,
+		DataDir: "/home/user/.local/share/juju",
+		Expected: strings.Replace(
+			s.newConfStr(name),
+			"WantedBy=multi-user.target",
+			"WantedBy=default.target",
+			-1),
+		User: true,
+	}
+	test.CheckCommands(c, commands)
+}
+
+func (s *initSystemSuite) TestUserServiceStartCommands(c *gc.C) {
+	commands, err := s.newUserService(c).StartCommands()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(commands, jc.DeepEquals, []string{
+		"/bin/systemctl --user start jujud-machine-0.service",
+	})
+}
@@ -21,6 +21,10 @@ type WriteConfTest struct {
 	DataDir  string
 	Expected string
 	Script   string
+
+	// User is true if the commands are expected to manage a user
+	// service.
+	User bool
 }
 
 func (wct WriteConfTest) dirname() string {
@@ -64,10 +68,14 @@ func (wct WriteConfTest) checkWriteConf(c *gc.C, commands []string) {
 	testing.CheckWriteFileCommand(c, commands[0], wct.filename(), wct.Expected, parse)
 
 	// Check the remaining commands.
+	systemctl := "/bin/systemctl"
+	if wct.User {
+		systemctl += " --user"
+	}
 	c.Check(commands[1:], jc.DeepEquals, []string{
-		"/bin/systemctl link " + wct.filename(),
-		"/bin/systemctl daemon-reload",
-		"/bin/systemctl enable " + wct.filename(),
+		systemctl + " link " + wct.filename(),
+		systemctl + " daemon-reload",
+		systemctl + " enable " + wct.filename(),
 	})
 }
 
@@ -39,6 +39,14 @@ func (s *Stub) DiscoverService(name string) (Service, error) {
 	return s.Service, s.NextErr()
 }
 
+// DiscoverServiceWithConf stubs out service.DiscoverService when
+// installing from a manifest.
+func (s *Stub) DiscoverServiceWithConf(name string, conf common.Conf) (Service, error) {
+	s.AddCall("DiscoverService", name, conf)
+
+	return s.Service, s.NextErr()
+}
+
 // BaseSuite is the base test suite for the application package.
 type BaseSuite struct {
 	testing.IsolationSuite
@@ -68,6 +76,7 @@ func (s *BaseSuite) SetUpTest(c *gc.C) {
 	s.Stub = &s.Service.Stub
 	s.Patched = &Stub{Stub: s.Stub}
 	s.PatchValue(&discoverService, s.Patched.DiscoverService)
+	s.PatchValue(&newManifestService, s.Patched.DiscoverServiceWithConf)
 }
 
 func (s *BaseSuite) PatchAttempts(retries int) {
@@ -102,6 +111,7 @@ func (s *BaseSuite) PatchLocalDiscoveryNoMatch(expected string) {
 	names := []string{
 		InitSystemUpstart,
 		InitSystemSystemd,
+		InitSystemOpenRC,
 		InitSystemWindows,
 	}
 	var checks []discoveryCheck
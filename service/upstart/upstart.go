@@ -12,6 +12,8 @@ import (
 	"path"
 	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/juju/errors"
@@ -94,6 +96,94 @@ func ListServices() ([]string, error) {
 	return services, nil
 }
 
+// jobStateRE matches a job's line of "initctl list" output, such as
+// "ssh start/running, process 123". Instances of multi-instance jobs,
+// whose names are followed by the instance in parentheses, do not
+// match.
+var jobStateRE = regexp.MustCompile(`^(\S+) (\w+)/(\w+)(?:, process (\d+))?`)
+
+// ListServicesWithInfo returns the state of all the services on the
+// local host. Upstart has no notion of disabling a job other than a
+// "manual" stanza in its override file, so a job is reported as
+// enabled unless it has one.
+func ListServicesWithInfo() ([]common.ServiceInfo, error) {
+	names, err := ListServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	out, err := exec.Command(initctlPath, "--system", "list").CombinedOutput()
+	if err != nil {
+		return nil, errors.Annotatef(err, "exec %q failed", initctlPath)
+	}
+	type jobState struct {
+		running bool
+		pid     int
+	}
+	states := make(map[string]jobState)
+	for _, line := range strings.Split(string(out), "\n") {
+		groups := jobStateRE.FindStringSubmatch(line)
+		if groups == nil {
+			continue
+		}
+		pid, _ := strconv.Atoi(groups[4])
+		states[groups[1]] = jobState{
+			running: groups[2] == "start" && groups[3] == "running",
+			pid:     pid,
+		}
+	}
+	infos := make([]common.ServiceInfo, len(names))
+	for i, name := range names {
+		state := states[name]
+		infos[i] = common.ServiceInfo{
+			Name:       name,
+			Running:    state.running,
+			Enabled:    !isManual(name),
+			Pid:        state.pid,
+			InitSystem: "upstart",
+		}
+	}
+	return infos, nil
+}
+
+// ServiceInfo returns the state of the named job on the local host, or
+// a NotFound error if there is no such job in InitDir.
+func ServiceInfo(name string) (common.ServiceInfo, error) {
+	if _, err := os.Stat(path.Join(InitDir, name+".conf")); os.IsNotExist(err) {
+		return common.ServiceInfo{}, errors.NotFoundf("service %q", name)
+	} else if err != nil {
+		return common.ServiceInfo{}, errors.Trace(err)
+	}
+	out, err := exec.Command(initctlPath, "--system", "status", name).CombinedOutput()
+	if err != nil {
+		return common.ServiceInfo{}, errors.Annotatef(err, "exec %q failed", initctlPath)
+	}
+	info := common.ServiceInfo{
+		Name:       name,
+		Enabled:    !isManual(name),
+		InitSystem: "upstart",
+	}
+	if groups := jobStateRE.FindStringSubmatch(string(out)); groups != nil {
+		info.Running = groups[2] == "start" && groups[3] == "running"
+		info.Pid, _ = strconv.Atoi(groups[4])
+	}
+	return info, nil
+}
+
+// isManual reports whether the named job's override file stops it
+// being started automatically.
+func isManual(name string) bool {
+	data, err := ioutil.ReadFile(path.Join(InitDir, name+".override"))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "manual" {
+			return true
+		}
+	}
+	return false
+}
+
 // ListCommand returns a command that will list the services on a host.
 func ListCommand() string {
 	// TODO(ericsnow) Do "ls /etc/init/*.conf" instead?
@@ -144,12 +234,24 @@ func (s *Service) Validate() error {
 		if len(s.Service.Conf.Limit) > 0 {
 			return errors.NotSupportedf("Conf.Limit (when transient)")
 		}
+		if s.Service.Conf.WorkingDirectory != "" {
+			return errors.NotSupportedf("Conf.WorkingDirectory (when transient)")
+		}
 		if s.Service.Conf.Logfile != "" {
 			return errors.NotSupportedf("Conf.Logfile (when transient)")
 		}
 		if s.Service.Conf.ExtraScript != "" {
 			return errors.NotSupportedf("Conf.ExtraScript (when transient)")
 		}
+		if len(s.Service.Conf.Before) > 0 {
+			return errors.NotSupportedf("Conf.Before (when transient)")
+		}
+		if len(s.Service.Conf.After) > 0 {
+			return errors.NotSupportedf("Conf.After (when transient)")
+		}
+		if len(s.Service.Conf.Requires) > 0 {
+			return errors.NotSupportedf("Conf.Requires (when transient)")
+		}
 	} else {
 		if s.Service.Conf.AfterStopped != "" {
 			return errors.NotSupportedf("Conf.AfterStopped (when not transient)")
@@ -315,6 +417,31 @@ func (s *Service) Install() error {
 	return nil
 }
 
+// Verify implements service.VerifiableService.
+func (s *Service) Verify() ([]string, error) {
+	expected, err := s.render()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	installed, err := ioutil.ReadFile(s.confPath())
+	if os.IsNotExist(err) {
+		return []string{fmt.Sprintf("%s is missing", s.confPath())}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return common.Drift(expected, installed), nil
+}
+
+// Repair implements service.VerifiableService. Upstart notices the
+// change itself, so the job only needs to be rewritten.
+func (s *Service) Repair() error {
+	conf, err := s.render()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(ioutil.WriteFile(s.confPath(), conf, 0644))
+}
+
 // InstallCommands returns shell commands to install the service.
 func (s *Service) InstallCommands() ([]string, error) {
 	conf, err := s.render()
@@ -356,15 +483,16 @@ func Serialize(name string, conf common.Conf) ([]byte, error) {
 var confT = template.Must(template.New("").Parse(`
 description "{{.Desc}}"
 author "Juju Team <juju@lists.ubuntu.com>"
-start on runlevel [2345]
-stop on runlevel [!2345]
+start on runlevel [2345]{{range .After}} and started {{.}}{{end}}{{range .Requires}} and started {{.}}{{end}}{{range .Before}} and starting {{.}}{{end}}
+stop on runlevel [!2345]{{range .Requires}} or stopping {{.}}{{end}}
 respawn
 normal exit 0
 {{range $k, $v := .Env}}env {{$k}}={{$v|printf "%q"}}
 {{end}}
 {{range $k, $v := .Limit}}limit {{$k}} {{$v}} {{$v}}
 {{end}}
-script
+{{if .WorkingDirectory}}chdir {{.WorkingDirectory}}
+{{end}}script
 {{if .ExtraScript}}{{.ExtraScript}}{{end}}
 {{if .Logfile}}
   # Ensure log files are properly protected
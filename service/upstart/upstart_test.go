@@ -11,6 +11,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"github.com/juju/utils/symlink"
@@ -88,6 +89,69 @@ func (s *UpstartSuite) goodInstall(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *UpstartSuite) TestListServicesWithInfo(c *gc.C) {
+	for _, name := range []string{"ssh", "cron", "some-application"} {
+		err := ioutil.WriteFile(filepath.Join(s.initDir, name+".conf"), nil, 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := ioutil.WriteFile(filepath.Join(s.initDir, "cron.override"), []byte("manual\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	initctl := filepath.Join(s.testPath, "initctl")
+	s.PatchValue(upstart.InitctlPath, initctl)
+	err = ioutil.WriteFile(initctl, []byte(`#!/bin/bash --norc
+echo "ssh start/running, process 123"
+echo "cron stop/waiting"
+echo "network-interface (eth0) start/running"
+echo "some-application start/running"
+`), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	infos, err := upstart.ListServicesWithInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(infos, jc.SameContents, []common.ServiceInfo{{
+		Name:       "ssh",
+		Running:    true,
+		Enabled:    true,
+		Pid:        123,
+		InitSystem: "upstart",
+	}, {
+		Name:       "cron",
+		InitSystem: "upstart",
+	}, {
+		Name:       "some-application",
+		Running:    true,
+		Enabled:    true,
+		InitSystem: "upstart",
+	}})
+}
+
+func (s *UpstartSuite) TestServiceInfo(c *gc.C) {
+	err := ioutil.WriteFile(filepath.Join(s.initDir, "ssh.conf"), nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	initctl := filepath.Join(s.testPath, "initctl")
+	s.PatchValue(upstart.InitctlPath, initctl)
+	err = ioutil.WriteFile(initctl, []byte(`#!/bin/bash --norc
+[ "$*" = "--system status ssh" ] || exit 1
+echo "ssh start/running, process 123"
+`), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := upstart.ServiceInfo("ssh")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info, jc.DeepEquals, common.ServiceInfo{
+		Name:       "ssh",
+		Running:    true,
+		Enabled:    true,
+		Pid:        123,
+		InitSystem: "upstart",
+	})
+}
+
+func (s *UpstartSuite) TestServiceInfoNotFound(c *gc.C) {
+	_, err := upstart.ServiceInfo("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *UpstartSuite) TestInstalled(c *gc.C) {
 	installed, err := s.service.Installed()
 	c.Assert(err, jc.ErrorIsNil)
@@ -120,6 +184,35 @@ func (s *UpstartSuite) TestExistsNonEmpty(c *gc.C) {
 	c.Check(exists, jc.IsFalse)
 }
 
+func (s *UpstartSuite) TestVerifyMissing(c *gc.C) {
+	drift, err := s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, jc.DeepEquals, []string{
+		filepath.Join(s.initDir, "some-application.conf") + " is missing",
+	})
+}
+
+func (s *UpstartSuite) TestVerifyAndRepair(c *gc.C) {
+	s.goodInstall(c)
+	drift, err := s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, gc.HasLen, 0)
+
+	confPath := filepath.Join(s.initDir, "some-application.conf")
+	err = ioutil.WriteFile(confPath, []byte("description \"edited\"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	drift, err = s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, gc.Not(gc.HasLen), 0)
+	c.Check(drift, jc.Contains, `line 1: +description "edited"`)
+
+	err = s.service.Repair()
+	c.Assert(err, jc.ErrorIsNil)
+	drift, err = s.service.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, gc.HasLen, 0)
+}
+
 func (s *UpstartSuite) TestRunning(c *gc.C) {
 	s.MakeTool(c, "status", "exit 1")
 	running, err := s.service.Running()
@@ -338,6 +431,48 @@ end script
 `)
 }
 
+func (s *UpstartSuite) TestInstallWorkingDirectory(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.WorkingDirectory = "/var/lib/some-application"
+	s.assertInstall(c, conf, `
+
+chdir /var/lib/some-application
+script
+
+
+  exec /path/to/some-command x y z
+end script
+`)
+}
+
+func (s *UpstartSuite) TestInstallDependencies(c *gc.C) {
+	conf := s.dummyConf(c)
+	conf.Before = []string{"later-application"}
+	conf.After = []string{"network-application"}
+	conf.Requires = []string{"juju-db"}
+	s.service.Service.Conf = conf
+	cmds, err := s.service.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmds, gc.HasLen, 1)
+	c.Check(cmds[0], jc.Contains, `
+start on runlevel [2345] and started network-application and started juju-db and starting later-application
+stop on runlevel [!2345] or stopping juju-db
+respawn
+`)
+}
+
+func (s *UpstartSuite) TestInstallTransientDependencies(c *gc.C) {
+	s.service.Service.Conf = common.Conf{
+		Desc:         "this is an upstart service",
+		Transient:    true,
+		AfterStopped: "other-application",
+		ExecStart:    "/path/to/some-command x y z",
+		Requires:     []string{"juju-db"},
+	}
+	_, err := s.service.InstallCommands()
+	c.Check(err, gc.ErrorMatches, `Conf.Requires \(when transient\) not supported`)
+}
+
 func (s *UpstartSuite) TestInstallAlreadyRunning(c *gc.C) {
 	pathTo := func(name string) string {
 		return filepath.Join(s.testPath, name)
@@ -12,6 +12,7 @@ var (
 	JujudUser                    = jujudUser
 	ERROR_SERVICE_DOES_NOT_EXIST = c_ERROR_SERVICE_DOES_NOT_EXIST
 	ERROR_SERVICE_EXISTS         = c_ERROR_SERVICE_EXISTS
+	RestartPolicy                = restartPolicy
 )
 
 type patcher interface {
@@ -21,6 +22,15 @@ type patcher interface {
 func PatchServiceManager(patcher patcher, stub *testing.Stub) *StubSvcManager {
 	manager := &StubSvcManager{Stub: stub}
 	patcher.PatchValue(&NewServiceManager, func() (ServiceManager, error) { return manager, nil })
+	patcher.PatchValue(&NewUserServiceManager, func() (ServiceManager, error) { return manager, nil })
 	patcher.PatchValue(&listServices, manager.ListServices)
 	return manager
 }
+
+// PatchUserServiceInstance makes the instances of per-user services
+// those of a logon session with the given id.
+func PatchUserServiceInstance(patcher patcher, logonId string) {
+	patcher.PatchValue(&userServiceInstance, func(name string) (string, error) {
+		return name + "_" + logonId, nil
+	})
+}
@@ -18,6 +18,8 @@ var (
 func PatchMgrConnect(patcher patcher, stub *testing.Stub) *StubMgr {
 	conn := &StubMgr{Stub: stub}
 	patcher.PatchValue(&newManager, func() (windowsManager, error) { return conn, nil })
+	patcher.PatchValue(&setEnvironment, conn.SetEnvironment)
+	patcher.PatchValue(&getEnvironment, conn.GetEnvironment)
 	return conn
 }
 
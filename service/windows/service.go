@@ -5,15 +5,20 @@
 package windows
 
 import (
+	"bytes"
 	"fmt"
+	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/shell"
 
+	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/service/common"
 )
 
@@ -40,8 +45,225 @@ const (
 	// SeAssignPrimaryTokenPrivilege
 	// SeServiceLogonRight
 	jujudUser = ".\\jujud"
+
+	// defaultRestartDelay is how long the service control manager waits
+	// before restarting a failed service whose conf does not say.
+	defaultRestartDelay = 5 * time.Second
+
+	// defaultRestartResetPeriod is how long a service must run without
+	// failing before the service control manager resets its failure
+	// count, when the service's conf does not say.
+	defaultRestartResetPeriod = 5 * time.Second
 )
 
+// restartPolicy returns how long the service control manager should wait
+// before restarting the service described by conf after it fails, and how
+// long the service must then run without failing before its failure count
+// is reset.
+func restartPolicy(conf common.Conf) (delay, resetPeriod time.Duration) {
+	delay, resetPeriod = defaultRestartDelay, defaultRestartResetPeriod
+	if conf.RestartSec > 0 {
+		delay = time.Duration(conf.RestartSec) * time.Second
+	}
+	if conf.RestartResetSec > 0 {
+		resetPeriod = time.Duration(conf.RestartResetSec) * time.Second
+	}
+	return delay, resetPeriod
+}
+
+// wmiServiceName is the name of the WMI service, which every juju
+// service depends on. WMI is needed for almost all installers to work
+// properly, and for all of the advanced windows instrumentation bits
+// (powershell included), so agents must start after it to ensure hooks
+// run properly.
+const wmiServiceName = "Winmgmt"
+
+// validServiceName matches the names of the services that a service
+// may depend on; they are written unquoted into install commands.
+var validServiceName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// serviceDependencies returns the names of the services that the
+// service described by conf depends on. The service control manager
+// starts a service's dependencies before it, and will not start it if
+// they fail, so services the conf only needs to start after are also
+// included.
+func serviceDependencies(conf common.Conf) []string {
+	deps := []string{wmiServiceName}
+	seen := map[string]bool{wmiServiceName: true}
+	for _, names := range [][]string{conf.After, conf.Requires} {
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				deps = append(deps, name)
+			}
+		}
+	}
+	return deps
+}
+
+// serviceCommand returns the executable and arguments of the service
+// described by conf. ServiceBinary and ServiceArgs are used if set, and
+// otherwise ExecStart is split into words by splitCommandLine.
+func serviceCommand(conf common.Conf) (string, []string, error) {
+	if conf.ServiceBinary != "" {
+		return conf.ServiceBinary, conf.ServiceArgs, nil
+	}
+	words, err := splitCommandLine(conf.ExecStart)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	if len(words) == 0 {
+		return "", nil, errors.New("missing ExecStart")
+	}
+	return words[0], words[1:], nil
+}
+
+// splitCommandLine splits cmd into words. Double quotes and the
+// backslashes before them are interpreted as the Windows C runtime
+// does, and words quoted by the PowerShell renderer, in single quotes
+// with embedded single quotes doubled, are also understood.
+func splitCommandLine(cmd string) ([]string, error) {
+	var words []string
+	var word bytes.Buffer
+	inWord, singleQuoted, doubleQuoted := false, false, false
+	slashes := 0
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		if singleQuoted {
+			if c != '\'' {
+				word.WriteByte(c)
+			} else if i+1 < len(cmd) && cmd[i+1] == '\'' {
+				word.WriteByte(c)
+				i++
+			} else {
+				singleQuoted = false
+			}
+			continue
+		}
+		switch c {
+		case '\\':
+			slashes++
+			inWord = true
+			continue
+		case '"':
+			// Each pair of backslashes before a quote is one
+			// backslash; an odd one out makes the quote literal.
+			word.WriteString(strings.Repeat(`\`, slashes/2))
+			if slashes%2 == 1 {
+				word.WriteByte(c)
+			} else {
+				doubleQuoted = !doubleQuoted
+			}
+			slashes = 0
+			inWord = true
+			continue
+		}
+		word.WriteString(strings.Repeat(`\`, slashes))
+		slashes = 0
+		switch {
+		case c == '\'' && !doubleQuoted:
+			singleQuoted = true
+			inWord = true
+		case (c == ' ' || c == '\t') && !doubleQuoted:
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if singleQuoted || doubleQuoted {
+		return nil, errors.NotValidf("command %q with unterminated quote", cmd)
+	}
+	word.WriteString(strings.Repeat(`\`, slashes))
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// escapeArg quotes arg, if necessary, as syscall.EscapeArg does on
+// Windows, so that the service control manager passes it to the
+// service unchanged.
+func escapeArg(arg string) string {
+	if arg == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(arg, " \t\"") {
+		return arg
+	}
+	var buf bytes.Buffer
+	quote := strings.ContainsAny(arg, " \t")
+	if quote {
+		buf.WriteByte('"')
+	}
+	slashes := 0
+	for i := 0; i < len(arg); i++ {
+		c := arg[i]
+		switch c {
+		case '\\':
+			slashes++
+		case '"':
+			// The backslashes before a quote, and the quote
+			// itself, are escaped.
+			buf.WriteString(strings.Repeat(`\`, slashes+1))
+			slashes = 0
+		default:
+			slashes = 0
+		}
+		buf.WriteByte(c)
+	}
+	if quote {
+		buf.WriteString(strings.Repeat(`\`, slashes))
+		buf.WriteByte('"')
+	}
+	return buf.String()
+}
+
+// commandLine returns the command line that runs exe with args, as
+// mgr.CreateService composes it.
+func commandLine(exe string, args []string) string {
+	words := make([]string, len(args)+1)
+	words[0] = escapeArg(exe)
+	for i, arg := range args {
+		words[i+1] = escapeArg(arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// serviceEnvironment returns the environment of the service described
+// by conf, as the sorted "NAME=value" entries of the Environment value
+// of the service's registry key, which the service control manager
+// adds to the service's environment. The service control manager
+// cannot start a service in a working directory, so the directory is
+// passed in the environment for the jujud service handler to change to.
+func serviceEnvironment(conf common.Conf) []string {
+	var env []string
+	for name, value := range conf.Env {
+		env = append(env, name+"="+value)
+	}
+	if conf.WorkingDirectory != "" {
+		env = append(env, osenv.JujuServiceWorkingDirEnvKey+"="+conf.WorkingDirectory)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// isJujud reports whether exe is the path of the jujud executable.
+func isJujud(exe string) bool {
+	name := strings.ToLower(exe[strings.LastIndexAny(exe, `\/`)+1:])
+	return name == "jujud" || name == "jujud.exe"
+}
+
+// serviceKey is the path of the registry key, under HKEY_LOCAL_MACHINE,
+// holding the configuration of the named service.
+func serviceKey(name string) string {
+	return `SYSTEM\CurrentControlSet\Services\` + name
+}
+
 // IsRunning returns whether or not windows is the local init system.
 func IsRunning() (bool, error) {
 	return runtime.GOOS == "windows", nil
@@ -53,6 +275,33 @@ func ListServices() ([]string, error) {
 	return listServices()
 }
 
+// ListServicesWithInfo returns the state of all the installed services
+// on the local host. The service manager interface does not expose
+// start types or process ids, so Enabled and Pid are not reported.
+func ListServicesWithInfo() ([]common.ServiceInfo, error) {
+	names, err := listServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	mgr, err := NewServiceManager()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	infos := make([]common.ServiceInfo, len(names))
+	for i, name := range names {
+		running, err := mgr.Running(name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting state of service %q", name)
+		}
+		infos[i] = common.ServiceInfo{
+			Name:       name,
+			Running:    running,
+			InitSystem: "windows",
+		}
+	}
+	return infos, nil
+}
+
 // ListCommand returns a command that will list the services on a host.
 func ListCommand() string {
 	return `(Get-Service).Name`
@@ -82,6 +331,12 @@ type ServiceManager interface {
 type Service struct {
 	common.Service
 	manager ServiceManager
+
+	// user is true if the service is a per-user service, which the
+	// service control manager runs, as the user, for each user that
+	// logs on. The installed service is a template, and each running
+	// instance of it is a service of its own.
+	user bool
 }
 
 func newService(name string, conf common.Conf, manager ServiceManager) *Service {
@@ -103,6 +358,38 @@ func NewService(name string, conf common.Conf) (*Service, error) {
 	return newService(name, conf, m), nil
 }
 
+// NewUserService returns a new Service type for a per-user service.
+// Once installed, the service is run as each user that logs on, rather
+// than as the jujud user, and it is the instance run for the calling
+// user's logon session that is started, stopped and reported on.
+//
+// The service control manager only lets administrators create, change
+// or delete services, so installing or removing a per-user service
+// still requires administrator rights.
+func NewUserService(name string, conf common.Conf) (*Service, error) {
+	m, err := NewUserServiceManager()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	svc := newService(name, conf, m)
+	svc.user = true
+	return svc, nil
+}
+
+// instanceName returns the name of the service that runs the service's
+// executable: for a per-user service, that of the instance for the
+// calling user's logon session.
+func (s *Service) instanceName() (string, error) {
+	if !s.user {
+		return s.Name(), nil
+	}
+	name, err := userServiceInstance(s.Name())
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot determine instance of user service %q", s.Name())
+	}
+	return name, nil
+}
+
 // Name implements service.Service.
 func (s *Service) Name() string {
 	return s.Service.Name
@@ -127,26 +414,73 @@ func (s *Service) Validate() error {
 		return errors.NotSupportedf("Conf.AfterStopped")
 	}
 
+	// A service cannot make other services depend on it.
+	if len(s.Service.Conf.Before) > 0 {
+		return errors.NotSupportedf("Conf.Before")
+	}
+
+	for _, name := range serviceDependencies(s.Service.Conf) {
+		if !validServiceName.MatchString(name) {
+			return errors.NotValidf("service dependency %q", name)
+		}
+	}
+
+	exe, _, err := serviceCommand(s.Service.Conf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Only jujud changes to the working directory passed to it in
+	// the service's environment; other commands would ignore it.
+	if s.Service.Conf.WorkingDirectory != "" && !isJujud(exe) {
+		return errors.NotSupportedf("Conf.WorkingDirectory for %q", exe)
+	}
+
+	for name := range s.Service.Conf.Env {
+		if name == "" || strings.Contains(name, "=") {
+			return errors.NotValidf("Conf.Env name %q", name)
+		}
+	}
+
 	return nil
 }
 
+// Running returns whether the service is running. A per-user service
+// is running if its instance for the calling user's logon session is.
 func (s *Service) Running() (bool, error) {
 	if ok, err := s.Installed(); err != nil {
 		return false, errors.Trace(err)
 	} else if !ok {
 		return false, nil
 	}
-	return s.manager.Running(s.Name())
+	name, err := s.instanceName()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if s.user {
+		// The instance is only created when the user logs on.
+		if ok, err := isListed(name); err != nil {
+			return false, errors.Trace(err)
+		} else if !ok {
+			return false, nil
+		}
+	}
+	return s.manager.Running(name)
 }
 
 // Installed returns whether the service is installed
 func (s *Service) Installed() (bool, error) {
+	return isListed(s.Name())
+}
+
+// isListed returns whether the named service is installed.
+func isListed(name string) (bool, error) {
 	services, err := ListServices()
 	if err != nil {
 		return false, errors.Trace(err)
 	}
 	for _, val := range services {
-		if s.Name() == val {
+		if name == val {
 			return true, nil
 		}
 	}
@@ -159,6 +493,33 @@ func (s *Service) Exists() (bool, error) {
 	return s.manager.Exists(s.Name(), s.Conf())
 }
 
+// Verify implements service.VerifiableService. The service manager
+// only reports whether the installed configuration matches, so any
+// drift is described as a whole.
+func (s *Service) Verify() ([]string, error) {
+	installed, err := s.Installed()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !installed {
+		return []string{"service is not installed"}, nil
+	}
+	same, err := s.Exists()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !same {
+		return []string{"installed service configuration differs from conf"}, nil
+	}
+	return nil, nil
+}
+
+// Repair implements service.VerifiableService. Windows services cannot
+// be reconfigured in place, so repairing them is not supported.
+func (s *Service) Repair() error {
+	return errors.NotSupportedf("repairing windows service %q", s.Name())
+}
+
 // Start starts the service.
 func (s *Service) Start() error {
 	logger.Infof("Starting service %q", s.Service.Name)
@@ -170,7 +531,19 @@ func (s *Service) Start() error {
 		logger.Infof("Service %q already running", s.Service.Name)
 		return nil
 	}
-	err = s.manager.Start(s.Name())
+	name, err := s.instanceName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if s.user {
+		// The instance is only created when the user logs on.
+		if ok, err := isListed(name); err != nil {
+			return errors.Trace(err)
+		} else if !ok {
+			return errors.NotFoundf("instance %q of user service %q", name, s.Name())
+		}
+	}
+	err = s.manager.Start(name)
 	return err
 }
 
@@ -183,7 +556,11 @@ func (s *Service) Stop() error {
 	if !running {
 		return nil
 	}
-	err = s.manager.Stop(s.Name())
+	name, err := s.instanceName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = s.manager.Stop(name)
 	return err
 }
 
@@ -229,30 +606,77 @@ func (s *Service) Install() error {
 
 // InstallCommands returns shell commands to install the service.
 func (s *Service) InstallCommands() ([]string, error) {
-	cmd := fmt.Sprintf(serviceCreateCommandTemplate[1:],
-		renderer.Quote(s.Service.Name),
-		renderer.Quote(s.Service.Conf.Desc),
-		renderer.Quote(s.Service.Conf.ExecStart),
-		renderer.Quote(s.Service.Name),
-		renderer.Quote(s.Service.Conf.Desc),
-		renderer.Quote(s.Service.Conf.ExecStart),
-		renderer.Quote(s.Service.Name),
-		renderer.Quote(s.Service.Name),
-	)
-	return strings.Split(cmd, "\n"), nil
-}
-
-// StartCommands returns shell commands to start the service.
+	exe, args, err := serviceCommand(s.Service.Conf)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	binPath := commandLine(exe, args)
+	// The dependencies' names are validated, so they need no quoting.
+	deps := serviceDependencies(s.Service.Conf)
+	var cmd string
+	if s.user {
+		cmd = fmt.Sprintf(userServiceCreateCommandTemplate[1:],
+			renderer.Quote(s.Service.Name),
+			renderer.Quote(binPath),
+			renderer.Quote(s.Service.Conf.Desc),
+			strings.Join(deps, "/"),
+			renderer.Quote(s.Service.Name),
+			renderer.Quote(s.Service.Name),
+		)
+	} else {
+		cmd = fmt.Sprintf(serviceCreateCommandTemplate[1:],
+			renderer.Quote(s.Service.Name),
+			strings.Join(deps, ","),
+			renderer.Quote(s.Service.Conf.Desc),
+			renderer.Quote(binPath),
+			renderer.Quote(s.Service.Name),
+			strings.Join(deps, ","),
+			renderer.Quote(s.Service.Conf.Desc),
+			renderer.Quote(binPath),
+			renderer.Quote(s.Service.Name),
+			renderer.Quote(s.Service.Name),
+		)
+	}
+	cmds := strings.Split(cmd, "\n")
+	if env := serviceEnvironment(s.Service.Conf); len(env) > 0 {
+		values := make([]string, len(env))
+		for i, entry := range env {
+			values[i] = renderer.Quote(entry)
+		}
+		cmds = append(cmds, fmt.Sprintf(serviceEnvironmentCommandTemplate,
+			renderer.Quote(`HKLM:\`+serviceKey(s.Service.Name)),
+			strings.Join(values, ","),
+		))
+	}
+	return cmds, nil
+}
+
+// StartCommands returns shell commands to start the service. The
+// commands for a per-user service start all of its instances.
 func (s *Service) StartCommands() ([]string, error) {
+	if s.user {
+		cmd := fmt.Sprintf(`Get-Service -Name %s | Start-Service`, renderer.Quote(s.Service.Name+"_*"))
+		return []string{cmd}, nil
+	}
 	cmd := fmt.Sprintf(`Start-Service %s`, renderer.Quote(s.Service.Name))
 	return []string{cmd}, nil
 }
 
 const serviceCreateCommandTemplate = `
 if ($jujuCreds) {
-  New-Service -Credential $jujuCreds -Name %s -DependsOn Winmgmt -DisplayName %s %s
+  New-Service -Credential $jujuCreds -Name %s -DependsOn %s -DisplayName %s %s
 } else {
-  New-Service -Name %s -DependsOn Winmgmt -DisplayName %s %s
+  New-Service -Name %s -DependsOn %s -DisplayName %s %s
 }
 sc.exe failure %s reset=5 actions=restart/1000
 sc.exe failureflag %s 1`
+
+// serviceEnvironmentCommandTemplate sets the Environment value of a
+// service's registry key.
+const serviceEnvironmentCommandTemplate = `New-ItemProperty -Path %s -Name Environment -PropertyType MultiString -Value @(%s) -Force | Out-Null`
+
+// New-Service cannot create per-user services, so sc.exe is used.
+const userServiceCreateCommandTemplate = `
+sc.exe create %s binPath= %s DisplayName= %s type= userown start= auto depend= %s
+sc.exe failure %s reset=5 actions=restart/1000
+sc.exe failureflag %s 1`
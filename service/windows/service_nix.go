@@ -7,6 +7,8 @@
 package windows
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/service/common"
 )
 
@@ -54,6 +56,14 @@ var listServices = func() ([]string, error) {
 	return []string{}, nil
 }
 
+var userServiceInstance = func(name string) (string, error) {
+	return "", errors.NotSupportedf("per-user services")
+}
+
 var NewServiceManager = func() (ServiceManager, error) {
 	return &SvcManager{}, nil
 }
+
+var NewUserServiceManager = func() (ServiceManager, error) {
+	return &SvcManager{}, nil
+}
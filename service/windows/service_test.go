@@ -5,6 +5,8 @@
 package windows_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -56,6 +58,34 @@ func (s *serviceSuite) SetUpTest(c *gc.C) {
 	s.stub.ResetCalls()
 }
 
+func (s *serviceSuite) TestRestartPolicyDefaults(c *gc.C) {
+	delay, resetPeriod := windows.RestartPolicy(s.conf)
+	c.Check(delay, gc.Equals, 5*time.Second)
+	c.Check(resetPeriod, gc.Equals, 5*time.Second)
+}
+
+func (s *serviceSuite) TestRestartPolicyFromConf(c *gc.C) {
+	s.conf.RestartSec = 10
+	s.conf.RestartResetSec = 300
+	delay, resetPeriod := windows.RestartPolicy(s.conf)
+	c.Check(delay, gc.Equals, 10*time.Second)
+	c.Check(resetPeriod, gc.Equals, 300*time.Second)
+}
+
+func (s *serviceSuite) TestListServicesWithInfo(c *gc.C) {
+	err := s.mgr.Install()
+	c.Assert(err, gc.IsNil)
+	s.stub.ResetCalls()
+
+	infos, err := windows.ListServicesWithInfo()
+	c.Assert(err, gc.IsNil)
+	c.Check(infos, jc.DeepEquals, []common.ServiceInfo{{
+		Name:       s.name,
+		InitSystem: "windows",
+	}})
+	s.stub.CheckCallNames(c, "listServices", "Running")
+}
+
 func (s *serviceSuite) TestInstall(c *gc.C) {
 	err := s.mgr.Install()
 	c.Assert(err, gc.IsNil)
@@ -134,6 +164,21 @@ func (s *serviceSuite) TestStopStart(c *gc.C) {
 	c.Assert(running, jc.IsTrue)
 }
 
+func (s *serviceSuite) TestVerify(c *gc.C) {
+	drift, err := s.mgr.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, jc.DeepEquals, []string{"service is not installed"})
+
+	err = s.mgr.Install()
+	c.Assert(err, jc.ErrorIsNil)
+	drift, err = s.mgr.Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(drift, gc.HasLen, 0)
+
+	err = s.mgr.Repair()
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *serviceSuite) TestRemove(c *gc.C) {
 	err := s.mgr.Install()
 	c.Assert(err, gc.IsNil)
@@ -206,3 +251,122 @@ func (s *serviceSuite) TestInstalledListError(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, listErr.Error())
 	c.Assert(exists, jc.IsFalse)
 }
+
+func (s *serviceSuite) TestUserService(c *gc.C) {
+	windows.PatchUserServiceInstance(s, "3c4d8")
+	svc, err := windows.NewUserService(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Install()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// There is no instance to run until the user logs on.
+	running, err := svc.Running()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsFalse)
+	err = svc.Start()
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.stubMgr.Create(s.name+"_3c4d8", s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.ResetCalls()
+	err = svc.Start()
+	c.Assert(err, jc.ErrorIsNil)
+	running, err = svc.Running()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsTrue)
+	s.stub.CheckCall(c, 4, "Start", s.name+"_3c4d8")
+
+	err = svc.Stop()
+	c.Assert(err, jc.ErrorIsNil)
+	running, err = svc.Running()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(running, jc.IsFalse)
+}
+
+func (s *serviceSuite) TestUserServiceCommands(c *gc.C) {
+	svc, err := windows.NewUserService(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+
+	commands, err := svc.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(commands, jc.DeepEquals, []string{
+		`sc.exe create 'machine-1' binPath= 'C:\juju\bin\jujud.exe machine-1' DisplayName= 'service for machine-1' type= userown start= auto depend= Winmgmt`,
+		`sc.exe failure 'machine-1' reset=5 actions=restart/1000`,
+		`sc.exe failureflag 'machine-1' 1`,
+	})
+
+	commands, err = svc.StartCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(commands, jc.DeepEquals, []string{
+		`Get-Service -Name 'machine-1_*' | Start-Service`,
+	})
+}
+
+func (s *serviceSuite) TestInstallCommandsEnvironment(c *gc.C) {
+	s.conf.ExecStart = s.execPath + ` machine-1 --log-dir 'C:\Juju\log dir'`
+	s.conf.Env = map[string]string{"JUJU_DEV": "it's on"}
+	s.conf.WorkingDirectory = `C:\Juju\lib\juju`
+	svc, err := windows.NewUserService(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+
+	commands, err := svc.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(commands, jc.DeepEquals, []string{
+		`sc.exe create 'machine-1' binPath= 'C:\juju\bin\jujud.exe machine-1 --log-dir "C:\Juju\log dir"' DisplayName= 'service for machine-1' type= userown start= auto depend= Winmgmt`,
+		`sc.exe failure 'machine-1' reset=5 actions=restart/1000`,
+		`sc.exe failureflag 'machine-1' 1`,
+		`New-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Services\machine-1' -Name Environment -PropertyType MultiString -Value @('JUJU_DEV=it''s on','JUJU_SERVICE_WORKING_DIR=C:\Juju\lib\juju') -Force | Out-Null`,
+	})
+}
+
+func (s *serviceSuite) TestValidateUnterminatedQuote(c *gc.C) {
+	s.conf.ExecStart = s.execPath + ` 'machine-1`
+	svc, err := windows.NewService(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = svc.Validate()
+	c.Assert(err, gc.ErrorMatches, `command .* with unterminated quote not valid`)
+}
+
+func (s *serviceSuite) TestValidateWorkingDirectory(c *gc.C) {
+	s.conf.WorkingDirectory = `C:\Juju\lib\juju`
+	svc, err := windows.NewService(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Validate()
+	c.Check(err, jc.ErrorIsNil)
+
+	s.conf.ExecStart = `C:\Windows\System32\notepad.exe`
+	svc, err = windows.NewService(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Validate()
+	c.Check(err, gc.ErrorMatches, `Conf.WorkingDirectory for ".*notepad.exe" not supported`)
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *serviceSuite) TestInstallCommandsDependencies(c *gc.C) {
+	s.conf.After = []string{"juju-db"}
+	s.conf.Requires = []string{"juju-db", "Dnscache"}
+	svc, err := windows.NewUserService(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+
+	commands, err := svc.InstallCommands()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(commands[0], gc.Equals,
+		`sc.exe create 'machine-1' binPath= 'C:\juju\bin\jujud.exe machine-1' DisplayName= 'service for machine-1' type= userown start= auto depend= Winmgmt/juju-db/Dnscache`,
+	)
+}
+
+func (s *serviceSuite) TestValidateDependencies(c *gc.C) {
+	s.conf.Before = []string{"juju-db"}
+	svc, err := windows.NewService(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Validate()
+	c.Check(err, gc.ErrorMatches, `Conf.Before not supported`)
+
+	s.conf.Before = nil
+	s.conf.After = []string{"juju-db;calc.exe"}
+	svc, err = windows.NewService(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Validate()
+	c.Check(err, gc.ErrorMatches, `service dependency "juju-db;calc.exe" not valid`)
+}
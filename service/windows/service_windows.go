@@ -5,14 +5,17 @@
 package windows
 
 import (
+	"fmt"
 	"reflect"
 	"syscall"
+	"time"
 	"unsafe"
 
 	// https://bugs.launchpad.net/juju-core/+bug/1470820
 	"github.com/juju/errors"
 	"github.com/juju/utils/series"
 	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
 	"golang.org/x/sys/windows/svc"
 	"golang.org/x/sys/windows/svc/mgr"
 
@@ -28,6 +31,13 @@ const (
 	SERVICE_CONFIG_FAILURE_ACTIONS_FLAG              = 4
 )
 
+// Per-user service types; see
+// https://docs.microsoft.com/en-us/windows/application-management/per-user-services-in-windows
+const (
+	serviceUserService    = 0x40
+	serviceUserOwnProcess = serviceUserService | windows.SERVICE_WIN32_OWN_PROCESS
+)
+
 //sys enumServicesStatus(h windows.Handle, InfoLevel SC_ENUM_TYPE, dwServiceType uint32, dwServiceState uint32, lpServices uintptr, cbBufSize uint32, pcbBytesNeeded *uint32, lpServicesReturned *uint32, lpResumeHandle *uint32, pszGroupName *uint32) (err error) [failretval==0] = advapi32.EnumServicesStatusExW
 
 const (
@@ -195,7 +205,7 @@ var listServices = func() (services []string, err error) {
 
 	for {
 		var buf [512]enumService
-		err := enumServicesStatus(sc, SC_ENUM_PROCESS_INFO, windows.SERVICE_WIN32,
+		err := enumServicesStatus(sc, SC_ENUM_PROCESS_INFO, windows.SERVICE_WIN32|serviceUserService,
 			windows.SERVICE_STATE_ALL, uintptr(unsafe.Pointer(&buf[0])), uint32(unsafe.Sizeof(buf)), &needed, &returned, &resume, nil)
 		if err != nil {
 			if err == windows.ERROR_MORE_DATA {
@@ -215,11 +225,90 @@ var listServices = func() (services []string, err error) {
 	return services, nil
 }
 
+// setEnvironment sets the Environment value of the named service's
+// registry key. It is defined as a variable to allow us to mock it out
+// for testing.
+var setEnvironment = func(name string, env []string) error {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, serviceKey(name), registry.SET_VALUE)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer key.Close()
+	return errors.Trace(key.SetStringsValue("Environment", env))
+}
+
+// getEnvironment returns the Environment value of the named service's
+// registry key, which is empty if the value is not set. It is defined
+// as a variable to allow us to mock it out for testing.
+var getEnvironment = func(name string) ([]string, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, serviceKey(name), registry.QUERY_VALUE)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer key.Close()
+	env, _, err := key.GetStringsValue("Environment")
+	if err == registry.ErrNotExist {
+		return nil, nil
+	}
+	return env, errors.Trace(err)
+}
+
+// luid is a locally unique identifier.
+// https://msdn.microsoft.com/en-us/library/windows/desktop/aa379261(v=vs.85).aspx
+type luid struct {
+	lowPart  uint32
+	highPart int32
+}
+
+// tokenStatistics is the TOKEN_STATISTICS structure.
+// https://msdn.microsoft.com/en-us/library/windows/desktop/aa379632(v=vs.85).aspx
+type tokenStatistics struct {
+	tokenId            luid
+	authenticationId   luid
+	expirationTime     int64
+	tokenType          uint32
+	impersonationLevel uint32
+	dynamicCharged     uint32
+	dynamicAvailable   uint32
+	groupCount         uint32
+	privilegeCount     uint32
+	modifiedId         luid
+}
+
+// tokenStatisticsClass is the TokenStatistics TOKEN_INFORMATION_CLASS.
+const tokenStatisticsClass = 10
+
+// userServiceInstance returns the name of the instance of the named
+// per-user service for the calling process's logon session. The service
+// control manager names each instance after the template, suffixed with
+// the session's logon id. It is defined as a variable to allow us to
+// mock it out for testing.
+var userServiceInstance = func(name string) (string, error) {
+	token, err := windows.OpenCurrentProcessToken()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer token.Close()
+	var stats tokenStatistics
+	var needed uint32
+	err = windows.GetTokenInformation(
+		token, tokenStatisticsClass,
+		(*byte)(unsafe.Pointer(&stats)), uint32(unsafe.Sizeof(stats)), &needed,
+	)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("%s_%x", name, stats.authenticationId.lowPart), nil
+}
+
 // SvcManager implements ServiceManager interface
 type SvcManager struct {
 	svc         windowsService
 	mgr         windowsManager
 	serviceConf common.Conf
+
+	// user is true if the manager creates per-user services.
+	user bool
 }
 
 func (s *SvcManager) getService(name string) (windowsService, error) {
@@ -286,14 +375,20 @@ func (s *SvcManager) escapeExecPath(exePath string, args []string) string {
 // Exists checks whether the config of the installed service matches the
 // config supplied to this function
 func (s *SvcManager) Exists(name string, conf common.Conf) (bool, error) {
+	exe, args, err := serviceCommand(conf)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
 	// We escape and compose BinaryPathName the same way mgr.CreateService does.
-	execStart := s.escapeExecPath(conf.ServiceBinary, conf.ServiceArgs)
+	execStart := s.escapeExecPath(exe, args)
+	if s.user {
+		if same, err := s.userExists(name, conf, execStart); err != nil || !same {
+			return false, err
+		}
+		return s.environmentExists(name, conf)
+	}
 	cfg := mgr.Config{
-		// make this service dependent on WMI service. WMI is needed for almost
-		// all installers to work properly, and is needed for all of the advanced windows
-		// instrumentation bits (powershell included). Juju agents must start after this
-		// service to ensure hooks run properly.
-		Dependencies:     []string{"Winmgmt"},
+		Dependencies:     serviceDependencies(conf),
 		StartType:        mgr.StartAutomatic,
 		DisplayName:      conf.Desc,
 		ServiceStartName: jujudUser,
@@ -304,10 +399,41 @@ func (s *SvcManager) Exists(name string, conf common.Conf) (bool, error) {
 		return false, err
 	}
 
-	if reflect.DeepEqual(cfg, currentConfig) {
+	if !reflect.DeepEqual(cfg, currentConfig) {
+		return false, nil
+	}
+	return s.environmentExists(name, conf)
+}
+
+// environmentExists checks whether the environment of the installed
+// service matches the one Create would give it.
+func (s *SvcManager) environmentExists(name string, conf common.Conf) (bool, error) {
+	current, err := getEnvironment(name)
+	if err != nil {
+		return false, errors.Annotatef(err, "cannot read environment of service %q", name)
+	}
+	env := serviceEnvironment(conf)
+	if len(env) == 0 && len(current) == 0 {
 		return true, nil
 	}
-	return false, nil
+	return reflect.DeepEqual(env, current), nil
+}
+
+// userExists checks whether the config of the installed per-user
+// service matches the one Create would give it.
+func (s *SvcManager) userExists(name string, conf common.Conf, execStart string) (bool, error) {
+	currentConfig, err := s.Config(name)
+	if err != nil {
+		return false, err
+	}
+	// Per-user services run as the user that logs on, so the account
+	// the template is configured with is not compared.
+	same := currentConfig.ServiceType == serviceUserOwnProcess &&
+		currentConfig.StartType == mgr.StartAutomatic &&
+		currentConfig.DisplayName == conf.Desc &&
+		currentConfig.BinaryPathName == execStart &&
+		reflect.DeepEqual(currentConfig.Dependencies, serviceDependencies(conf))
+	return same, nil
 }
 
 // Stop stops a service.
@@ -356,6 +482,9 @@ func (s *SvcManager) Delete(name string) error {
 
 // Create creates a service with the given config.
 func (s *SvcManager) Create(name string, conf common.Conf) error {
+	if s.user {
+		return s.createUser(name, conf)
+	}
 	serviceStartName := "LocalSystem"
 	var passwd string
 	hostSeries, err := series.HostSeries()
@@ -371,22 +500,68 @@ func (s *SvcManager) Create(name string, conf common.Conf) error {
 		serviceStartName = jujudUser
 	}
 	cfg := mgr.Config{
-		Dependencies:     []string{"Winmgmt"},
+		Dependencies:     serviceDependencies(conf),
 		ErrorControl:     mgr.ErrorSevere,
 		StartType:        mgr.StartAutomatic,
 		DisplayName:      conf.Desc,
 		ServiceStartName: serviceStartName,
 		Password:         passwd,
 	}
+	exe, args, err := serviceCommand(conf)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	// mgr.CreateService actually does correct argument escaping itself. There is no
 	// need for quoted strings of any kind passed to this function. It takes in
 	// a binary name, and an array or arguments.
-	service, err := s.mgr.CreateService(name, conf.ServiceBinary, cfg, conf.ServiceArgs...)
+	service, err := s.mgr.CreateService(name, exe, cfg, args...)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer service.Close()
-	err = s.ensureRestartOnFailure(name)
+	if err := s.ensureEnvironment(name, conf); err != nil {
+		return errors.Trace(err)
+	}
+	err = s.ensureRestartOnFailure(name, conf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// createUser creates a per-user service with the given config. No
+// account is given, because the service control manager runs an
+// instance of the service as each user that logs on.
+func (s *SvcManager) createUser(name string, conf common.Conf) error {
+	cfg := mgr.Config{
+		Dependencies: serviceDependencies(conf),
+		ErrorControl: mgr.ErrorNormal,
+		StartType:    mgr.StartAutomatic,
+		DisplayName:  conf.Desc,
+	}
+	exe, args, err := serviceCommand(conf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	service, err := s.mgr.CreateService(name, exe, cfg, args...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer service.Close()
+	if err := s.ensureEnvironment(name, conf); err != nil {
+		return errors.Trace(err)
+	}
+	// mgr.CreateService always creates a service that runs in its own
+	// process, so the service is made a per-user service afterwards.
+	cfg, err = service.Config()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg.ServiceType = serviceUserOwnProcess
+	if err := service.UpdateConfig(cfg); err != nil {
+		return errors.Annotatef(err, "cannot make %q a per-user service", name)
+	}
+	err = s.ensureRestartOnFailure(name, conf)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -424,7 +599,20 @@ func (s *SvcManager) Config(name string) (mgr.Config, error) {
 	return service.Config()
 }
 
-func (s *SvcManager) ensureRestartOnFailure(name string) (err error) {
+// ensureEnvironment sets the environment of the named service to that
+// described by conf, if there is any.
+func (s *SvcManager) ensureEnvironment(name string, conf common.Conf) error {
+	env := serviceEnvironment(conf)
+	if len(env) == 0 {
+		return nil
+	}
+	if err := setEnvironment(name, env); err != nil {
+		return errors.Annotatef(err, "cannot set environment of service %q", name)
+	}
+	return nil
+}
+
+func (s *SvcManager) ensureRestartOnFailure(name string, conf common.Conf) (err error) {
 	handle, err := s.mgr.GetHandle(name)
 	if err != nil {
 		return errors.Trace(err)
@@ -440,12 +628,13 @@ func (s *SvcManager) ensureRestartOnFailure(name string) (err error) {
 			}
 		}
 	}()
+	delay, resetPeriod := restartPolicy(conf)
 	action := serviceAction{
 		actionType: SC_ACTION_RESTART,
-		delay:      5000,
+		delay:      uint32(delay / time.Millisecond),
 	}
 	failActions := serviceFailureActions{
-		dwResetPeriod: 5,
+		dwResetPeriod: uint32(resetPeriod / time.Second),
 		lpRebootMsg:   nil,
 		lpCommand:     nil,
 		cActions:      1,
@@ -506,3 +695,16 @@ var NewServiceManager = func() (ServiceManager, error) {
 		mgr: m,
 	}, nil
 }
+
+// NewUserServiceManager returns a ServiceManager that creates per-user
+// services.
+var NewUserServiceManager = func() (ServiceManager, error) {
+	m, err := newManager()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &SvcManager{
+		mgr:  m,
+		user: true,
+	}, nil
+}
@@ -203,6 +203,24 @@ func (s *serviceManagerSuite) TestStop(c *gc.C) {
 	c.Assert(running, jc.IsFalse)
 }
 
+func (s *serviceManagerSuite) TestCreateEnvironment(c *gc.C) {
+	s.getPasswd.SetPasswd("fake")
+	s.conf.ExecStart = `'C:\Program Files\juju\jujud.exe' machine --data-dir 'C:\Juju\lib\juju'`
+	s.conf.Env = map[string]string{"JUJU_FOO": "bar", "JUJU_BAZ": "qux quux"}
+	s.conf.WorkingDirectory = `C:\Juju\lib\juju`
+	err := s.mgr.Create(s.name, s.conf)
+	c.Assert(err, jc.ErrorIsNil)
+
+	calls := s.stub.Calls()
+	c.Assert(calls[0].FuncName, gc.Equals, "CreateService")
+	c.Check(calls[0].Args[1], gc.Equals, `C:\Program Files\juju\jujud.exe`)
+	c.Check(windows.Environments[s.name], jc.DeepEquals, []string{
+		"JUJU_BAZ=qux quux",
+		"JUJU_FOO=bar",
+		`JUJU_SERVICE_WORKING_DIR=C:\Juju\lib\juju`,
+	})
+}
+
 func (s *serviceManagerSuite) TestEnsureRestartOnFailure(c *gc.C) {
 	windows.WinChangeServiceConfig2 = func(win.Handle, uint32, *byte) error {
 		return errors.New("ChangeServiceConfig2: zoinks")
@@ -17,6 +17,9 @@ import (
 // is attached to the service itself
 var Services map[string]*StubService
 
+// Environments holds the Environment registry values of the services.
+var Environments map[string][]string
+
 type StubService struct {
 	*testing.Stub
 
@@ -79,6 +82,7 @@ func (s *StubService) Delete() error {
 
 	if _, ok := Services[s.Name]; ok {
 		delete(Services, s.Name)
+		delete(Environments, s.Name)
 		return s.NextErr()
 	}
 	return c_ERROR_SERVICE_DOES_NOT_EXIST
@@ -144,6 +148,23 @@ func (m *StubMgr) CloseHandle(handle windows.Handle) (err error) {
 	return m.NextErr()
 }
 
+func (m *StubMgr) SetEnvironment(name string, env []string) error {
+	m.Stub.AddCall("SetEnvironment", name, env)
+	if _, ok := Services[name]; !ok {
+		return c_ERROR_SERVICE_DOES_NOT_EXIST
+	}
+	Environments[name] = env
+	return m.NextErr()
+}
+
+func (m *StubMgr) GetEnvironment(name string) ([]string, error) {
+	m.Stub.AddCall("GetEnvironment", name)
+	if _, ok := Services[name]; !ok {
+		return nil, c_ERROR_SERVICE_DOES_NOT_EXIST
+	}
+	return Environments[name], m.NextErr()
+}
+
 func (m *StubMgr) Exists(name string) bool {
 	if _, ok := Services[name]; ok {
 		return true
@@ -161,4 +182,5 @@ func (m *StubMgr) List() []string {
 
 func (m *StubMgr) Clear() {
 	Services = map[string]*StubService{}
+	Environments = map[string][]string{}
 }
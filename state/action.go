@@ -5,6 +5,7 @@ package state
 
 import (
 	"time"
+	"unicode/utf8"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -104,6 +105,22 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// ProgressMessage is the most recent progress message reported
+	// by the running action.
+	ProgressMessage string `bson:"progress-message,omitempty"`
+
+	// ProgressPercent is the most recent completion percentage
+	// reported by the running action.
+	ProgressPercent int `bson:"progress-percent,omitempty"`
+
+	// Logs holds the messages logged by the action while it was
+	// running, in the order they were logged.
+	Logs []ActionMessage `bson:"logs,omitempty"`
+
+	// Operation is the id of the operation the action is a task of,
+	// if any.
+	Operation string `bson:"operation,omitempty"`
 }
 
 // action represents an instruction to do some "action" and is expected
@@ -162,6 +179,24 @@ func (a *action) Results() (map[string]interface{}, string) {
 	return a.doc.Results, a.doc.Message
 }
 
+// Progress returns the most recent progress message and completion
+// percentage reported by the running action.
+func (a *action) Progress() (string, int) {
+	return a.doc.ProgressMessage, a.doc.ProgressPercent
+}
+
+// Messages returns the messages logged by the action while it was
+// running, in the order they were logged.
+func (a *action) Messages() []ActionMessage {
+	return a.doc.Logs
+}
+
+// OperationID returns the id of the operation the action is a task of,
+// or "" if it was not enqueued as part of an operation.
+func (a *action) OperationID() string {
+	return a.doc.Operation
+}
+
 // Tag implements the Entity interface and returns a names.Tag that
 // is a names.ActionTag.
 func (a *action) Tag() names.Tag {
@@ -174,6 +209,12 @@ func (a *action) ActionTag() names.ActionTag {
 	return names.NewActionTag(a.Id())
 }
 
+// ActionMessage is a timestamped message logged by a running action.
+type ActionMessage struct {
+	Timestamp time.Time `bson:"timestamp"`
+	Message   string    `bson:"message"`
+}
+
 // ActionResults is a data transfer object that holds the key Action
 // output and results information.
 type ActionResults struct {
@@ -201,6 +242,86 @@ func (a *action) Begin() (Action, error) {
 	return a.st.Action(a.Id())
 }
 
+// SetProgress records an intermediate progress message and completion
+// percentage for the action. It asserts that the action is running.
+func (a *action) SetProgress(message string, percent int) error {
+	if percent < 0 || percent > 100 {
+		return errors.NotValidf("progress percentage %d", percent)
+	}
+	err := a.st.db().RunTransaction([]txn.Op{
+		{
+			C:      actionsC,
+			Id:     a.doc.DocId,
+			Assert: bson.D{{"status", ActionRunning}},
+			Update: bson.D{{"$set", bson.D{
+				{"progress-message", message},
+				{"progress-percent", percent},
+			}}},
+		}})
+	if err == txn.ErrAborted {
+		return errors.Errorf("cannot set progress of action %q: action is not running", a.Id())
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	a.doc.ProgressMessage = message
+	a.doc.ProgressPercent = percent
+	return nil
+}
+
+// maxActionLogEntries is the number of the most recent messages
+// kept in an action's logs, which are stored in the action document
+// and so must not grow without bound.
+var maxActionLogEntries = 1000
+
+// maxActionMessageLength is the length in bytes beyond which logged
+// messages are truncated.
+const maxActionMessageLength = 1024
+
+// Log appends a timestamped message to the action's logs, keeping only
+// the most recent maxActionLogEntries messages. It asserts that the
+// action is running.
+func (a *action) Log(message string) error {
+	entry := ActionMessage{
+		Timestamp: a.st.clock().Now().UTC(),
+		Message:   truncateActionMessage(message),
+	}
+	err := a.st.db().RunTransaction([]txn.Op{
+		{
+			C:      actionsC,
+			Id:     a.doc.DocId,
+			Assert: bson.D{{"status", ActionRunning}},
+			Update: bson.D{{"$push", bson.D{{"logs", bson.D{
+				{"$each", []ActionMessage{entry}},
+				{"$slice", -maxActionLogEntries},
+			}}}}},
+		}})
+	if err == txn.ErrAborted {
+		return errors.Errorf("cannot log message to action %q: action is not running", a.Id())
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	a.doc.Logs = append(a.doc.Logs, entry)
+	if len(a.doc.Logs) > maxActionLogEntries {
+		a.doc.Logs = a.doc.Logs[len(a.doc.Logs)-maxActionLogEntries:]
+	}
+	return nil
+}
+
+// truncateActionMessage returns the message, truncated to at most
+// maxActionMessageLength bytes without splitting a character.
+func truncateActionMessage(message string) string {
+	if len(message) <= maxActionMessageLength {
+		return message
+	}
+	n := maxActionMessageLength
+	for n > 0 && !utf8.RuneStart(message[n]) {
+		n--
+	}
+	return message[:n]
+}
+
 // Finish removes action from the pending queue and captures the output
 // and end state of the action.
 func (a *action) Finish(results ActionResults) (Action, error) {
@@ -356,6 +477,19 @@ func (st *State) FindActionsByName(name string) ([]Action, error) {
 
 // EnqueueAction
 func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}) (Action, error) {
+	return st.enqueueAction("", receiver, actionName, payload)
+}
+
+// EnqueueTask enqueues an action as a task of the operation with the
+// given id.
+func (st *State) EnqueueTask(operationID string, receiver names.Tag, actionName string, payload map[string]interface{}) (Action, error) {
+	if operationID == "" {
+		return nil, errors.New("operation id required")
+	}
+	return st.enqueueAction(operationID, receiver, actionName, payload)
+}
+
+func (st *State) enqueueAction(operationID string, receiver names.Tag, actionName string, payload map[string]interface{}) (Action, error) {
 	if len(actionName) == 0 {
 		return nil, errors.New("action name required")
 	}
@@ -369,6 +503,7 @@ func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload ma
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	doc.Operation = operationID
 
 	ops := []txn.Op{{
 		C:      receiverCollectionName,
@@ -385,6 +520,13 @@ func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload ma
 		Assert: txn.DocMissing,
 		Insert: ndoc,
 	}}
+	if operationID != "" {
+		ops = append(ops, txn.Op{
+			C:      operationsC,
+			Id:     st.docID(operationID),
+			Assert: txn.DocExists,
+		})
+	}
 
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if notDead, err := isNotDead(st, receiverCollectionName, receiverId); err != nil {
@@ -392,6 +534,11 @@ func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload ma
 		} else if !notDead {
 			return nil, ErrDead
 		} else if attempt != 0 {
+			if operationID != "" {
+				if _, err := st.Operation(operationID); err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
 			return nil, errors.Errorf("unexpected attempt number '%d'", attempt)
 		}
 		return ops, nil
@@ -468,8 +615,11 @@ func (st *State) matchingActionsByReceiverAndStatus(tag names.Tag, statusConditi
 // PruneActions removes action entries until
 // only logs newer than <maxLogTime> remain and also ensures
 // that the collection is smaller than <maxLogsMB> after the
-// deletion.
+// deletion. Operations left without any tasks are removed too.
 func PruneActions(st *State, maxHistoryTime time.Duration, maxHistoryMB int) error {
 	err := pruneCollection(st, maxHistoryTime, maxHistoryMB, actionsC, "completed", GoTime)
-	return errors.Trace(err)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(pruneOperations(st))
 }
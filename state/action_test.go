@@ -467,6 +467,148 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(actions), gc.Equals, 0)
 }
 
+func (s *ActionSuite) TestSetProgress(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = action.SetProgress("copying files", 40)
+	c.Assert(err, jc.ErrorIsNil)
+	message, percent := action.Progress()
+	c.Check(message, gc.Equals, "copying files")
+	c.Check(percent, gc.Equals, 40)
+
+	action, err = s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	message, percent = action.Progress()
+	c.Check(message, gc.Equals, "copying files")
+	c.Check(percent, gc.Equals, 40)
+}
+
+func (s *ActionSuite) TestSetProgressNotRunning(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	action, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = action.SetProgress("copying files", 40)
+	c.Assert(err, gc.ErrorMatches, `cannot set progress of action ".*": action is not running`)
+}
+
+func (s *ActionSuite) TestSetProgressInvalidPercent(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = action.SetProgress("too far", 101)
+	c.Assert(err, gc.ErrorMatches, "progress percentage 101 not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ActionSuite) TestLog(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = action.Log("copying files")
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.Log("restarting")
+	c.Assert(err, jc.ErrorIsNil)
+	check := func(messages []state.ActionMessage) {
+		c.Assert(messages, gc.HasLen, 2)
+		c.Check(messages[0].Message, gc.Equals, "copying files")
+		c.Check(messages[1].Message, gc.Equals, "restarting")
+		c.Check(messages[0].Timestamp.IsZero(), jc.IsFalse)
+	}
+	check(action.Messages())
+
+	action, err = s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	check(action.Messages())
+
+	// Logged messages are kept once the action has finished.
+	action, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	check(action.Messages())
+}
+
+func (s *ActionSuite) TestLogKeepsMostRecent(c *gc.C) {
+	s.PatchValue(state.MaxActionLogEntries, 2)
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, message := range []string{"one", "two", "three"} {
+		err = action.Log(message)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	check := func(messages []state.ActionMessage) {
+		c.Assert(messages, gc.HasLen, 2)
+		c.Check(messages[0].Message, gc.Equals, "two")
+		c.Check(messages[1].Message, gc.Equals, "three")
+	}
+	check(action.Messages())
+
+	action, err = s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	check(action.Messages())
+}
+
+func (s *ActionSuite) TestLogTruncatesLongMessages(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = action.Log(strings.Repeat("é", 1000))
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err = s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	messages := action.Messages()
+	c.Assert(messages, gc.HasLen, 1)
+	c.Check(messages[0].Message, gc.Equals, strings.Repeat("é", 512))
+}
+
+func (s *ActionSuite) TestLogNotRunning(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	action, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = action.Log("copying files")
+	c.Assert(err, gc.ErrorMatches, `cannot log message to action ".*": action is not running`)
+	c.Check(action.Messages(), gc.HasLen, 0)
+}
+
 func (s *ActionSuite) TestFindActionTagsByPrefix(c *gc.C) {
 	prefix := "feedbeef"
 	uuidMock := uuidMockHelper{}
@@ -894,6 +1036,9 @@ var _ state.ActionReceiver = (*mockAR)(nil)
 func (r mockAR) AddAction(name string, payload map[string]interface{}) (state.Action, error) {
 	return nil, nil
 }
+func (r mockAR) AddTask(operationID, name string, payload map[string]interface{}) (state.Action, error) {
+	return nil, nil
+}
 func (r mockAR) CancelAction(state.Action) (state.Action, error) { return nil, nil }
 func (r mockAR) WatchActionNotifications() state.StringsWatcher  { return nil }
 func (r mockAR) Actions() ([]state.Action, error)                { return nil, nil }
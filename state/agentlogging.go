@@ -0,0 +1,86 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// agentLoggingDoc records the loggo configuration applied by a single
+// agent on top of the model's logging-config.
+//
+// Note that the document id hasn't been included because we don't
+// need to read it or (directly) write it.
+type agentLoggingDoc struct {
+	Config string `bson:"config"`
+}
+
+// AgentLoggingOverride returns the loggo configuration that the agent
+// with the given tag applies on top of the model's logging-config, or
+// the empty string if there is none.
+func (st *State) AgentLoggingOverride(tag names.Tag) (string, error) {
+	coll, closer := st.db().GetCollection(agentLoggingC)
+	defer closer()
+
+	var doc agentLoggingDoc
+	err := coll.FindId(tag.String()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Annotatef(err, "cannot get logging override for %s", names.ReadableString(tag))
+	}
+	return doc.Config, nil
+}
+
+// SetAgentLoggingOverride sets the loggo configuration that the agent
+// with the given tag applies on top of the model's logging-config.
+// Setting an empty configuration removes any override.
+func (st *State) SetAgentLoggingOverride(tag names.Tag, config string) error {
+	if _, err := loggo.ParseConfigString(config); err != nil {
+		return errors.NewNotValid(err, "logging config")
+	}
+	id := tag.String()
+	var ops []txn.Op
+	if config == "" {
+		ops = []txn.Op{{
+			C:      agentLoggingC,
+			Id:     id,
+			Remove: true,
+		}}
+	} else {
+		doc := agentLoggingDoc{Config: config}
+		ops = []txn.Op{{
+			C:      agentLoggingC,
+			Id:     id,
+			Insert: doc,
+		}, {
+			C:      agentLoggingC,
+			Id:     id,
+			Update: bson.M{"$set": doc},
+		}}
+	}
+	err := st.db().RunTransaction(ops)
+	return errors.Annotatef(err, "cannot set logging override for %s", names.ReadableString(tag))
+}
+
+// removeAgentLoggingOverrideOp returns the operation needed to remove
+// the logging override, if any, of the agent with the given tag.
+func removeAgentLoggingOverrideOp(tag names.Tag) txn.Op {
+	return txn.Op{
+		C:      agentLoggingC,
+		Id:     tag.String(),
+		Remove: true,
+	}
+}
+
+// WatchAgentLoggingOverride returns a NotifyWatcher that notifies when
+// the logging override for the agent with the given tag changes.
+func (st *State) WatchAgentLoggingOverride(tag names.Tag) NotifyWatcher {
+	return newEntityWatcher(st, agentLoggingC, st.docID(tag.String()))
+}
@@ -0,0 +1,113 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type AgentLoggingSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AgentLoggingSuite{})
+
+func (s *AgentLoggingSuite) TestNoOverride(c *gc.C) {
+	config, err := s.State.AgentLoggingOverride(names.NewMachineTag("0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.Equals, "")
+}
+
+func (s *AgentLoggingSuite) TestSetAndReplace(c *gc.C) {
+	tag := names.NewUnitTag("mysql/0")
+	err := s.State.SetAgentLoggingOverride(tag, "juju.worker.uniter=DEBUG")
+	c.Assert(err, jc.ErrorIsNil)
+	config, err := s.State.AgentLoggingOverride(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.Equals, "juju.worker.uniter=DEBUG")
+
+	err = s.State.SetAgentLoggingOverride(tag, "unit=TRACE")
+	c.Assert(err, jc.ErrorIsNil)
+	config, err = s.State.AgentLoggingOverride(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.Equals, "unit=TRACE")
+
+	// Other agents are unaffected.
+	config, err = s.State.AgentLoggingOverride(names.NewUnitTag("mysql/1"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.Equals, "")
+}
+
+func (s *AgentLoggingSuite) TestSetEmptyRemoves(c *gc.C) {
+	tag := names.NewMachineTag("0")
+	err := s.State.SetAgentLoggingOverride(tag, "juju=DEBUG")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetAgentLoggingOverride(tag, "")
+	c.Assert(err, jc.ErrorIsNil)
+	config, err := s.State.AgentLoggingOverride(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.Equals, "")
+
+	// Removing again is not an error.
+	err = s.State.SetAgentLoggingOverride(tag, "")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AgentLoggingSuite) TestRemovedWithMachine(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	err := s.State.SetAgentLoggingOverride(machine.Tag(), "juju=DEBUG")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	config, err := s.State.AgentLoggingOverride(machine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.Equals, "")
+}
+
+func (s *AgentLoggingSuite) TestRemovedWithUnit(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	err := s.State.SetAgentLoggingOverride(unit.Tag(), "unit=TRACE")
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	config, err := s.State.AgentLoggingOverride(unit.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, gc.Equals, "")
+}
+
+func (s *AgentLoggingSuite) TestSetInvalid(c *gc.C) {
+	err := s.State.SetAgentLoggingOverride(names.NewMachineTag("0"), "juju=LOUD")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *AgentLoggingSuite) TestWatch(c *gc.C) {
+	tag := names.NewMachineTag("0")
+	w := s.State.WatchAgentLoggingOverride(tag)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.State.SetAgentLoggingOverride(tag, "juju=DEBUG")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.SetAgentLoggingOverride(names.NewMachineTag("1"), "juju=DEBUG")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = s.State.SetAgentLoggingOverride(tag, "")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
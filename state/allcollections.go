@@ -92,6 +92,10 @@ func allCollections() collectionSchema {
 		// upgrades and schema migrations.
 		upgradeInfoC: {global: true},
 
+		// This collection records the upgrade steps each agent has
+		// completed, so that a retried upgrade can skip them.
+		upgradeStepsC: {global: true},
+
 		// This collection holds a convenient representation of the content of
 		// the simplestreams data source pointing to binaries required by juju.
 		//
@@ -173,6 +177,14 @@ func allCollections() collectionSchema {
 		// are inherited and then forked by new models.
 		globalSettingsC: {global: true},
 
+		// This collection holds named debug-log filter presets shared
+		// by the users of a controller.
+		debugLogPresetsC: {global: true},
+
+		// This collection holds the CIDRs allowed to reach the
+		// controller-level services, such as SSH and the API.
+		firewallRulesC: {global: true},
+
 		// This collection holds workload metrics reported by certain charms
 		// for passing onward to other tools.
 		metricsC: {global: true},
@@ -266,6 +278,13 @@ func allCollections() collectionSchema {
 
 		// meterStatusC is the collection used to store meter status information.
 		meterStatusC: {},
+
+		// unitSecretsC holds the secrets stored by units' charms.
+		unitSecretsC: {},
+
+		// generationsC holds the model's branches, against which
+		// charm config changes are staged.
+		generationsC: {},
 		refcountsC:   {},
 		relationsC: {
 			indexes: []mgo.Index{{
@@ -289,6 +308,10 @@ func allCollections() collectionSchema {
 		rebootC:        {},
 		sshHostKeysC:   {},
 
+		// This collection holds the logging configuration applied by
+		// individual agents on top of the model's logging-config.
+		agentLoggingC: {},
+
 		// This collection contains information from removed machines
 		// that needs to be cleaned up in the provider.
 		machineRemovalsC: {},
@@ -309,6 +332,7 @@ func allCollections() collectionSchema {
 			}},
 		},
 		filesystemAttachmentsC: {},
+		filesystemUsageC:       {},
 		storageInstancesC: {
 			indexes: []mgo.Index{{
 				Key: []string{"model-uuid", "owner"},
@@ -351,6 +375,10 @@ func allCollections() collectionSchema {
 		},
 		actionNotificationsC: {},
 
+		// operationsC holds the operations that group actions, such
+		// as those enqueued by a single juju run.
+		operationsC: {},
+
 		// -----
 
 		// This collection holds information associated with charm payloads.
@@ -457,6 +485,8 @@ const (
 	actionNotificationsC     = "actionnotifications"
 	actionresultsC           = "actionresults"
 	actionsC                 = "actions"
+	operationsC              = "operations"
+	agentLoggingC            = "agentLogging"
 	annotationsC             = "annotations"
 	autocertCacheC           = "autocertCache"
 	assignUnitC              = "assignUnits"
@@ -473,8 +503,11 @@ const (
 	containerRefsC           = "containerRefs"
 	controllersC             = "controllers"
 	controllerUsersC         = "controllerusers"
+	debugLogPresetsC         = "debugLogPresets"
 	filesystemAttachmentsC   = "filesystemAttachments"
 	filesystemsC             = "filesystems"
+	filesystemUsageC         = "filesystemUsage"
+	firewallRulesC           = "firewallRules"
 	globalSettingsC          = "globalSettings"
 	guimetadataC             = "guimetadata"
 	guisettingsC             = "guisettings"
@@ -522,7 +555,10 @@ const (
 	txnLogC                  = "txns.log"
 	txnsC                    = "txns"
 	unitsC                   = "units"
+	unitSecretsC             = "unitSecrets"
+	generationsC             = "generations"
 	upgradeInfoC             = "upgradeInfo"
+	upgradeStepsC            = "upgradeSteps"
 	userLastLoginC           = "userLastLogin"
 	usermodelnameC           = "usermodelname"
 	usersC                   = "users"
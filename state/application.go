@@ -49,9 +49,15 @@ type applicationDoc struct {
 	UnitCount            int        `bson:"unitcount"`
 	RelationCount        int        `bson:"relationcount"`
 	Exposed              bool       `bson:"exposed"`
+	Trusted              bool       `bson:"trusted,omitempty"`
 	MinUnits             int        `bson:"minunits"`
 	TxnRevno             int64      `bson:"txn-revno"`
 	MetricCredentials    []byte     `bson:"metric-credentials"`
+
+	// CompletedHooks records the application hooks that have
+	// completed, keyed on escaped hook name, with the URL of the
+	// charm each last completed for.
+	CompletedHooks map[string]string `bson:"completed-hooks,omitempty"`
 }
 
 func newApplication(st *State, doc *applicationDoc) *Application {
@@ -96,6 +102,12 @@ func (a *Application) globalKey() string {
 	return applicationGlobalKey(a.doc.Name)
 }
 
+// applicationWorkloadVersionKey returns the global database key for
+// the status document holding the application's workload version.
+func applicationWorkloadVersionKey(appName string) string {
+	return applicationGlobalKey(appName) + "#sat#workload-version"
+}
+
 func applicationSettingsKey(appName string, curl *charm.URL) string {
 	return fmt.Sprintf("a#%s#%s", appName, curl)
 }
@@ -295,6 +307,7 @@ func (a *Application) removeOps(asserts bson.D) ([]txn.Op, error) {
 		annotationRemoveOp(a.st, globalKey),
 		removeLeadershipSettingsOp(name),
 		removeStatusOp(a.st, globalKey),
+		removeStatusOp(a.st, applicationWorkloadVersionKey(name)),
 		removeModelApplicationRefOp(a.st, name),
 	)
 	return ops, nil
@@ -333,6 +346,30 @@ func (a *Application) setExposed(exposed bool) (err error) {
 	return nil
 }
 
+// IsTrusted returns whether the application has been trusted with the
+// model's cloud credential. Unlike charm config, which any user with
+// write access to the model may change, only model admins may trust
+// an application. See SetTrusted.
+func (a *Application) IsTrusted() bool {
+	return a.doc.Trusted
+}
+
+// SetTrusted sets whether the application is trusted with the model's
+// cloud credential. See IsTrusted.
+func (a *Application) SetTrusted(trusted bool) error {
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"trusted", trusted}}}},
+	}}
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		return errors.Errorf("cannot set trusted flag for application %q to %v: %v", a, trusted, onAbort(err, errNotAlive))
+	}
+	a.doc.Trusted = trusted
+	return nil
+}
+
 // Charm returns the application's charm and whether units should upgrade to that
 // charm even if they are in an error state.
 func (a *Application) Charm() (ch *Charm, force bool, err error) {
@@ -1346,6 +1383,11 @@ func (a *Application) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		return nil, errors.Trace(err)
 	}
 	ops = append(ops, resOps...)
+	branchOps, err := removeUnitBranchOps(a.st.db(), u.doc.Application, u.doc.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, branchOps...)
 
 	observedFieldsMatch := bson.D{
 		{"charmurl", u.doc.CharmURL},
@@ -1359,9 +1401,11 @@ func (a *Application) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 			Remove: true,
 		},
 		removeMeterStatusOp(a.st, u.globalMeterStatusKey()),
+		removeUnitSecretsOp(u.doc.Name),
 		removeStatusOp(a.st, u.globalAgentKey()),
 		removeStatusOp(a.st, u.globalKey()),
 		removeConstraintsOp(u.globalAgentKey()),
+		removeAgentLoggingOverrideOp(u.Tag()),
 		annotationRemoveOp(a.st, u.globalKey()),
 		newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	)
@@ -1505,6 +1549,73 @@ func (a *Application) UpdateConfigSettings(changes charm.Settings) error {
 	return err
 }
 
+// configSettingsUpdateOps returns the txn ops required to apply the
+// supplied charm config changes to the application's settings, asserting
+// that the application is alive and still using the same charm.
+func (a *Application) configSettingsUpdateOps(changes charm.Settings) ([]txn.Op, error) {
+	if a.doc.Life != Alive {
+		return nil, errors.Annotatef(errNotAlive, "application %q", a.doc.Name)
+	}
+	ch, _, err := a.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	changes, err = ch.Config().ValidateSettings(changes)
+	if err != nil {
+		return nil, errors.Annotatef(err, "application %q", a.doc.Name)
+	}
+	node, err := readSettings(a.st.db(), settingsC, a.settingsKey())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name, value := range changes {
+		if value == nil {
+			node.Delete(name)
+		} else {
+			node.Set(name, value)
+		}
+	}
+	_, settingsOps := node.settingsUpdateOps()
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: bson.D{{"life", Alive}, {"charmurl", a.doc.CharmURL}},
+	}}
+	return append(ops, settingsOps...), nil
+}
+
+// UpdateApplicationsConfigSettings changes the charm config settings of
+// several applications, keyed by application name, in a single
+// transaction: either all of the changes are made or none of them are.
+// As with Application.UpdateConfigSettings, values set to nil are
+// deleted, and unknown or invalid values cause an error.
+func (st *State) UpdateApplicationsConfigSettings(changes map[string]charm.Settings) error {
+	appNames := make([]string, 0, len(changes))
+	for name := range changes {
+		appNames = append(appNames, name)
+	}
+	sort.Strings(appNames)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var ops []txn.Op
+		for _, name := range appNames {
+			app, err := st.Application(name)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			appOps, err := app.configSettingsUpdateOps(changes[name])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, appOps...)
+		}
+		return ops, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return errors.Annotate(err, "cannot update application config settings")
+	}
+	return nil
+}
+
 // LeaderSettings returns a application's leader settings. If nothing has been set
 // yet, it will return an empty map; this is not an error.
 func (a *Application) LeaderSettings() (map[string]string, error) {
@@ -1593,6 +1704,54 @@ func (a *Application) UpdateLeaderSettings(token leadership.Token, updates map[s
 	return a.st.db().Run(buildTxnWithLeadership(buildTxn, token))
 }
 
+// CompletedApplicationHooks returns the application hooks that have
+// completed, keyed on hook name, with the URL of the charm each last
+// completed for. Unlike the leader settings, these are not visible to
+// the charm.
+func (a *Application) CompletedApplicationHooks() (map[string]string, error) {
+	applications, closer := a.st.db().GetCollection(applicationsC)
+	defer closer()
+
+	var doc applicationDoc
+	err := applications.FindId(a.doc.DocID).Select(bson.D{{"completed-hooks", 1}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("application %q", a.doc.Name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get completed hooks of application %q", a.doc.Name)
+	}
+	result := make(map[string]string)
+	for escapedName, charmURL := range doc.CompletedHooks {
+		result[unescapeReplacer.Replace(escapedName)] = charmURL
+	}
+	return result, nil
+}
+
+// SetApplicationHookCompleted records that the named application hook
+// has completed for the charm with the given URL, but will fail (with a
+// suitable error) if the supplied Token loses validity; so that a unit
+// that is no longer the leader cannot record a hook as completed.
+func (a *Application) SetApplicationHookCompleted(token leadership.Token, name, charmURL string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot record completion of application hook %q", name)
+	field := "completed-hooks." + escapeReplacer.Replace(name)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		return []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{field, charmURL}}}},
+		}}, nil
+	}
+	return a.st.db().Run(buildTxnWithLeadership(buildTxn, token))
+}
+
 var ErrSubordinateConstraints = stderrors.New("constraints do not apply to subordinate applications")
 
 // Constraints returns the current application constraints.
@@ -1757,6 +1916,61 @@ func (a *Application) SetStatus(statusInfo status.StatusInfo) error {
 	})
 }
 
+// WorkloadVersion returns the version of the running workload recorded
+// for the application as a whole by its leader unit, or the empty
+// string if none has been recorded. The versions reported by individual
+// units are available from the units themselves.
+func (a *Application) WorkloadVersion() (string, error) {
+	info, err := getStatus(a.st.db(), applicationWorkloadVersionKey(a.doc.Name), "workload version")
+	if errors.IsNotFound(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return info.Message, nil
+}
+
+// SetWorkloadVersion records the version of the workload that the
+// application is running, conditional on the given token remaining
+// valid; it is expected to be checking the leadership of the unit
+// that reported the version.
+func (a *Application) SetWorkloadVersion(token leadership.Token, version string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set workload version for application %q", a.doc.Name)
+	key := applicationWorkloadVersionKey(a.doc.Name)
+	doc := statusDoc{
+		Status:     status.Active,
+		StatusInfo: version,
+		Updated:    a.st.clock().Now().UnixNano(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if a.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		ops := []txn.Op{{
+			C:      applicationsC,
+			Id:     a.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		setOps, err := statusSetOps(a.st.db(), doc, key)
+		if errors.Cause(err) == mgo.ErrNotFound {
+			return append(ops, createStatusOp(a.st, key, doc)), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, setOps...), nil
+	}
+	if err := a.st.db().Run(buildTxnWithLeadership(buildTxn, token)); err != nil {
+		return errors.Trace(err)
+	}
+	probablyUpdateStatusHistory(a.st.db(), key, doc)
+	return nil
+}
+
 // StatusHistory returns a slice of at most filter.Size StatusInfo items
 // or items as old as filter.Date or items newer than now - filter.Delta time
 // representing past statuses for this application.
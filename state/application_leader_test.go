@@ -195,6 +195,79 @@ func (s *ServiceLeaderSuite) TestWatchCoalesceChanges(c *gc.C) {
 	wc.AssertOneChange()
 }
 
+func (s *ServiceLeaderSuite) TestWorkloadVersion(c *gc.C) {
+	version, err := s.service.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(version, gc.Equals, "")
+
+	err = s.service.SetWorkloadVersion(&fakeToken{}, "mysql 5.7.21")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetWorkloadVersion(&fakeToken{}, "mysql 5.7.22")
+	c.Assert(err, jc.ErrorIsNil)
+	version, err = s.service.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(version, gc.Equals, "mysql 5.7.22")
+}
+
+func (s *ServiceLeaderSuite) TestSetWorkloadVersionTokenError(c *gc.C) {
+	err := s.service.SetWorkloadVersion(&failToken{}, "mysql 5.7.21")
+	c.Check(err, gc.ErrorMatches, `cannot set workload version for application ".*": prerequisites failed: something bad happened`)
+	version, err := s.service.WorkloadVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(version, gc.Equals, "")
+}
+
+func (s *ServiceLeaderSuite) TestSetWorkloadVersionDying(c *gc.C) {
+	s.preventRemove(c)
+	s.destroyService(c)
+	err := s.service.SetWorkloadVersion(&fakeToken{}, "mysql 5.7.21")
+	c.Check(err, gc.ErrorMatches, `cannot set workload version for application ".*": not found or not alive`)
+}
+
+func (s *ServiceLeaderSuite) TestApplicationHookCompleted(c *gc.C) {
+	hooks, err := s.service.CompletedApplicationHooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(hooks, gc.HasLen, 0)
+
+	err = s.service.SetApplicationHookCompleted(&fakeToken{}, "migrate", "cs:quantal/mysql-1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetApplicationHookCompleted(&fakeToken{}, "schema.v2", "cs:quantal/mysql-1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetApplicationHookCompleted(&fakeToken{}, "migrate", "cs:quantal/mysql-2")
+	c.Assert(err, jc.ErrorIsNil)
+
+	hooks, err = s.service.CompletedApplicationHooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(hooks, jc.DeepEquals, map[string]string{
+		"migrate":   "cs:quantal/mysql-2",
+		"schema.v2": "cs:quantal/mysql-1",
+	})
+
+	// The completed hooks are not visible to the charm.
+	s.checkSettings(c, map[string]string{})
+}
+
+func (s *ServiceLeaderSuite) TestSetApplicationHookCompletedTokenError(c *gc.C) {
+	err := s.service.SetApplicationHookCompleted(&failToken{}, "migrate", "cs:quantal/mysql-1")
+	c.Check(err, gc.ErrorMatches, `cannot record completion of application hook "migrate": prerequisites failed: something bad happened`)
+	hooks, err := s.service.CompletedApplicationHooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(hooks, gc.HasLen, 0)
+}
+
+func (s *ServiceLeaderSuite) TestSetApplicationHookCompletedDying(c *gc.C) {
+	s.preventRemove(c)
+	s.destroyService(c)
+	err := s.service.SetApplicationHookCompleted(&fakeToken{}, "migrate", "cs:quantal/mysql-1")
+	c.Check(err, gc.ErrorMatches, `cannot record completion of application hook "migrate": not found or not alive`)
+}
+
+func (s *ServiceLeaderSuite) TestCompletedApplicationHooksRemoved(c *gc.C) {
+	s.destroyService(c)
+	_, err := s.service.CompletedApplicationHooks()
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *ServiceLeaderSuite) writeSettings(c *gc.C, update map[string]string) {
 	err := s.service.UpdateLeaderSettings(&fakeToken{}, update)
 	c.Check(err, jc.ErrorIsNil)
@@ -921,6 +921,58 @@ func (s *ApplicationSuite) TestUpdateConfigSettings(c *gc.C) {
 	}
 }
 
+func (s *ApplicationSuite) TestUpdateApplicationsConfigSettings(c *gc.C) {
+	sch := s.AddTestingCharm(c, "dummy")
+	app1 := s.AddTestingApplication(c, "dummy1", sch)
+	app2 := s.AddTestingApplication(c, "dummy2", sch)
+	err := app2.UpdateConfigSettings(charm.Settings{"title": "sir"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.UpdateApplicationsConfigSettings(map[string]charm.Settings{
+		"dummy1": {"outlook": "positive"},
+		"dummy2": {"title": nil, "skill-level": 303},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := app1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"outlook": "positive"})
+	settings, err = app2.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{"skill-level": int64(303)})
+}
+
+func (s *ApplicationSuite) TestUpdateApplicationsConfigSettingsInvalidChangesNothing(c *gc.C) {
+	sch := s.AddTestingCharm(c, "dummy")
+	app1 := s.AddTestingApplication(c, "dummy1", sch)
+	s.AddTestingApplication(c, "dummy2", sch)
+
+	err := s.State.UpdateApplicationsConfigSettings(map[string]charm.Settings{
+		"dummy1": {"outlook": "positive"},
+		"dummy2": {"skill-level": "high"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot update application config settings: application "dummy2": option "skill-level" expected int, got "high"`)
+
+	settings, err := app1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{})
+}
+
+func (s *ApplicationSuite) TestUpdateApplicationsConfigSettingsMissingApplication(c *gc.C) {
+	sch := s.AddTestingCharm(c, "dummy")
+	app1 := s.AddTestingApplication(c, "dummy1", sch)
+
+	err := s.State.UpdateApplicationsConfigSettings(map[string]charm.Settings{
+		"dummy1":  {"outlook": "positive"},
+		"missing": {"outlook": "negative"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	settings, err := app1.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{})
+}
+
 func assertNoSettingsRef(c *gc.C, st *state.State, svcName string, sch *state.Charm) {
 	_, err := state.ServiceSettingsRefCount(st, svcName, sch.URL())
 	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
@@ -1393,6 +1445,28 @@ func (s *ApplicationSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ApplicationSuite) TestSetTrusted(c *gc.C) {
+	c.Assert(s.mysql.IsTrusted(), jc.IsFalse)
+
+	err := s.mysql.SetTrusted(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsTrusted(), jc.IsTrue)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsTrusted(), jc.IsTrue)
+
+	err = s.mysql.SetTrusted(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsTrusted(), jc.IsFalse)
+
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetTrusted(true)
+	c.Assert(err, gc.ErrorMatches, notAliveErr)
+}
+
 func (s *ApplicationSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit(state.AddUnitParams{})
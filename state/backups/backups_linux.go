@@ -157,7 +157,7 @@ func (b *backups) Restore(backupId string, dbInfo *DBInfo, args RestoreArgs) (na
 		// TODO(perrito666) renderer should have a RendererForSeries, for the moment
 		// restore only works on linuxes.
 		renderer, _ := shell.NewRenderer("bash")
-		serviceAgentConf := service.AgentConf(aInfo, renderer)
+		serviceAgentConf := service.ControllerAgentConf(aInfo, renderer, mongo.ServiceName)
 		svc, err := service.NewService(serviceName, serviceAgentConf, args.NewInstSeries)
 		if err != nil {
 			return nil, errors.Annotate(err, "cannot generate service for the restored agent.")
@@ -10,21 +10,27 @@ import (
 
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state/binarystorage"
+	"github.com/juju/juju/state/storage"
 )
 
-var binarystorageNew = binarystorage.New
+var (
+	binarystorageNew              = binarystorage.New
+	binarystorageNewWithBlobStore = binarystorage.NewWithBlobStore
+)
 
 // ToolsStorage returns a new binarystorage.StorageCloser that stores tools
-// metadata in the "juju" database "toolsmetadata" collection.
+// metadata in the "juju" database "toolsmetadata" collection. The tools
+// themselves are kept in the controller's blob store, so that tools
+// added to many models are stored once.
 func (st *State) ToolsStorage() (binarystorage.StorageCloser, error) {
-	modelStorage := newBinaryStorageCloser(st.database, toolsmetadataC, st.ModelUUID())
+	modelStorage := newBinaryStorageCloser(st.database, toolsmetadataC, st.ModelUUID(), newToolsBinaryStorage)
 	if st.IsController() {
 		return modelStorage, nil
 	}
 	// This is a hosted model. Hosted models have their own tools
 	// catalogue, which we combine with the controller's.
 	controllerStorage := newBinaryStorageCloser(
-		st.database, toolsmetadataC, st.ControllerModelUUID(),
+		st.database, toolsmetadataC, st.ControllerModelUUID(), newToolsBinaryStorage,
 	)
 	storage, err := binarystorage.NewLayeredStorage(modelStorage, controllerStorage)
 	if err != nil {
@@ -41,10 +47,12 @@ func (st *State) ToolsStorage() (binarystorage.StorageCloser, error) {
 // GUIStorage returns a new binarystorage.StorageCloser that stores GUI archive
 // metadata in the "juju" database "guimetadata" collection.
 func (st *State) GUIStorage() (binarystorage.StorageCloser, error) {
-	return newBinaryStorageCloser(st.database, guimetadataC, st.ControllerModelUUID()), nil
+	return newBinaryStorageCloser(st.database, guimetadataC, st.ControllerModelUUID(), newBinaryStorage), nil
 }
 
-func newBinaryStorageCloser(db Database, collectionName, uuid string) binarystorage.StorageCloser {
+type binaryStorageFunc func(uuid string, metadataCollection mongo.Collection, txnRunner jujutxn.Runner) binarystorage.Storage
+
+func newBinaryStorageCloser(db Database, collectionName, uuid string, newStorage binaryStorageFunc) binarystorage.StorageCloser {
 	db, closer1 := db.CopyForModel(uuid)
 	metadataCollection, closer2 := db.GetCollection(collectionName)
 	txnRunner, closer3 := db.TransactionRunner()
@@ -53,7 +61,7 @@ func newBinaryStorageCloser(db Database, collectionName, uuid string) binarystor
 		closer2()
 		closer1()
 	}
-	storage := newBinaryStorage(uuid, metadataCollection, txnRunner)
+	storage := newStorage(uuid, metadataCollection, txnRunner)
 	return &storageCloser{storage, closer}
 }
 
@@ -64,6 +72,14 @@ func newBinaryStorage(uuid string, metadataCollection mongo.Collection, txnRunne
 	return binarystorageNew(uuid, managedStorage, metadataCollection, txnRunner)
 }
 
+func newToolsBinaryStorage(uuid string, metadataCollection mongo.Collection, txnRunner jujutxn.Runner) binarystorage.Storage {
+	db := metadataCollection.Writeable().Underlying().Database
+	rs := blobstore.NewGridFS(blobstoreDB, blobstoreDB, db.Session)
+	managedStorage := blobstore.NewManagedStorage(db, rs)
+	blobStorage := storage.NewStorage(uuid, db.Session)
+	return binarystorageNewWithBlobStore(uuid, managedStorage, blobStorage, metadataCollection, txnRunner)
+}
+
 type storageCloser struct {
 	binarystorage.Storage
 	closer func()
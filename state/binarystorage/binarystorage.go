@@ -16,6 +16,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state/storage"
 )
 
 var logger = loggo.GetLogger("juju.state.binarystorage")
@@ -23,6 +24,7 @@ var logger = loggo.GetLogger("juju.state.binarystorage")
 type binaryStorage struct {
 	modelUUID          string
 	managedStorage     blobstore.ManagedStorage
+	blobStorage        storage.Storage
 	metadataCollection mongo.Collection
 	txnRunner          jujutxn.Runner
 }
@@ -46,18 +48,42 @@ func New(
 	}
 }
 
+// NewWithBlobStore constructs a new Storage like New, except that
+// binary files with a SHA256 hash are stored in the controller's blob
+// store through the provided model storage, so that a file added to
+// many models is stored once. Files previously stored in the
+// ManagedStorage remain readable.
+func NewWithBlobStore(
+	modelUUID string,
+	managedStorage blobstore.ManagedStorage,
+	blobStorage storage.Storage,
+	metadataCollection mongo.Collection,
+	runner jujutxn.Runner,
+) Storage {
+	return &binaryStorage{
+		modelUUID:          modelUUID,
+		managedStorage:     managedStorage,
+		blobStorage:        blobStorage,
+		metadataCollection: metadataCollection,
+		txnRunner:          runner,
+	}
+}
+
 // Add implements Storage.Add.
 func (s *binaryStorage) Add(r io.Reader, metadata Metadata) (resultErr error) {
 	// Add the binary file to storage.
 	path := fmt.Sprintf("tools/%s-%s", metadata.Version, metadata.SHA256)
-	if err := s.managedStorage.PutForBucket(s.modelUUID, path, r, metadata.Size); err != nil {
+	if s.blobStorage != nil && metadata.SHA256 != "" {
+		path = storage.BlobPath(storage.SHA256BlobID(metadata.SHA256), path)
+	}
+	if err := s.put(path, r, metadata.Size); err != nil {
 		return errors.Annotate(err, "cannot store binary file")
 	}
 	defer func() {
 		if resultErr == nil {
 			return
 		}
-		err := s.managedStorage.RemoveForBucket(s.modelUUID, path)
+		err := s.remove(path)
 		if err != nil {
 			logger.Errorf("failed to remove binary blob: %v", err)
 		}
@@ -113,7 +139,7 @@ func (s *binaryStorage) Add(r io.Reader, metadata Metadata) (resultErr error) {
 
 	if oldPath != "" && oldPath != path {
 		// Attempt to remove the old path. Failure is non-fatal.
-		err := s.managedStorage.RemoveForBucket(s.modelUUID, oldPath)
+		err := s.remove(oldPath)
 		if err != nil {
 			logger.Errorf("failed to remove old binary blob: %v", err)
 		} else {
@@ -128,7 +154,7 @@ func (s *binaryStorage) Open(version string) (Metadata, io.ReadCloser, error) {
 	if err != nil {
 		return Metadata{}, nil, err
 	}
-	r, _, err := s.managedStorage.GetForBucket(s.modelUUID, metadataDoc.Path)
+	r, err := s.get(metadataDoc.Path)
 	if err != nil {
 		return Metadata{}, nil, err
 	}
@@ -140,6 +166,33 @@ func (s *binaryStorage) Open(version string) (Metadata, io.ReadCloser, error) {
 	return metadata, r, nil
 }
 
+// put stores a binary file at the given path, in the blob store if the
+// path is a blob path.
+func (s *binaryStorage) put(path string, r io.Reader, length int64) error {
+	if _, _, ok := storage.ParseBlobPath(path); ok {
+		return s.blobStorage.Put(path, r, length)
+	}
+	return s.managedStorage.PutForBucket(s.modelUUID, path, r, length)
+}
+
+// get returns the binary file stored at the given path.
+func (s *binaryStorage) get(path string) (io.ReadCloser, error) {
+	if _, _, ok := storage.ParseBlobPath(path); ok && s.blobStorage != nil {
+		r, _, err := s.blobStorage.Get(path)
+		return r, err
+	}
+	r, _, err := s.managedStorage.GetForBucket(s.modelUUID, path)
+	return r, err
+}
+
+// remove removes the binary file stored at the given path.
+func (s *binaryStorage) remove(path string) error {
+	if _, _, ok := storage.ParseBlobPath(path); ok && s.blobStorage != nil {
+		return s.blobStorage.Remove(path)
+	}
+	return s.managedStorage.RemoveForBucket(s.modelUUID, path)
+}
+
 func (s *binaryStorage) Metadata(version string) (Metadata, error) {
 	metadataDoc, err := s.findMetadata(version)
 	if err != nil {
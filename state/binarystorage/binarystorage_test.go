@@ -5,6 +5,7 @@ package binarystorage_test
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"io/ioutil"
 	"strings"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state/binarystorage"
+	"github.com/juju/juju/state/storage"
 	"github.com/juju/juju/testing"
 )
 
@@ -278,6 +280,29 @@ func (s *binaryStorageSuite) TestAddSame(c *gc.C) {
 	}
 }
 
+func (s *binaryStorageSuite) TestAddBlobStore(c *gc.C) {
+	newStorage := func(uuid string) binarystorage.Storage {
+		return binarystorage.NewWithBlobStore(
+			uuid, s.managedStorage, storage.NewStorage(uuid, s.session),
+			s.metadataCollection, s.txnRunner,
+		)
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte("abc")))
+	metadata := binarystorage.Metadata{Version: current, Size: 3, SHA256: hash}
+	err := newStorage("my-uuid").Add(strings.NewReader("abc"), metadata)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The content is kept in the blob store, not the model's bucket.
+	r, _, err := storage.NewBlobStore(s.session).Get(storage.SHA256BlobID(hash))
+	c.Assert(err, jc.ErrorIsNil)
+	r.Close()
+	_, _, err = s.managedStorage.GetForBucket("my-uuid", fmt.Sprintf("tools/%s-%s", current, hash))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	s.storage = newStorage("my-uuid")
+	s.assertMetadataAndContent(c, metadata, "abc")
+}
+
 func (s *binaryStorageSuite) TestAddConcurrent(c *gc.C) {
 	metadata0 := binarystorage.Metadata{Version: current, Size: 1, SHA256: "0"}
 	metadata1 := binarystorage.Metadata{Version: current, Size: 1, SHA256: "1"}
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/binarystorage"
+	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/tools"
@@ -153,6 +154,20 @@ func (s *binaryStorageSuite) testStorageParams(c *gc.C, collName string, uuids [
 		c.Assert(runner, gc.NotNil)
 		return nil
 	})
+	s.PatchValue(state.BinarystorageNewWithBlobStore, func(
+		modelUUID string,
+		managedStorage blobstore.ManagedStorage,
+		blobStorage statestorage.Storage,
+		metadataCollection mongo.Collection,
+		runner jujutxn.Runner,
+	) binarystorage.Storage {
+		uuidArgs = append(uuidArgs, modelUUID)
+		c.Assert(managedStorage, gc.NotNil)
+		c.Assert(blobStorage, gc.NotNil)
+		c.Assert(metadataCollection.Name(), gc.Equals, collName)
+		c.Assert(runner, gc.NotNil)
+		return nil
+	})
 
 	storage, err := openStorage()
 	c.Assert(err, jc.ErrorIsNil)
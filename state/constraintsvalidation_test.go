@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/cloudimagemetadata"
 )
 
 type applicationConstraintsSuite struct {
@@ -267,6 +268,58 @@ func (s *constraintsValidationSuite) TestServiceConstraints(c *gc.C) {
 	}
 }
 
+func (s *constraintsValidationSuite) TestImageIdConstraintValidatedAgainstCloudImages(c *gc.C) {
+	err := s.State.CloudImageMetadataStorage.SaveMetadata([]cloudimagemetadata.Metadata{{
+		MetadataAttributes: cloudimagemetadata.MetadataAttributes{
+			Stream:  "released",
+			Region:  "dummy-region",
+			Version: "16.04",
+			Series:  "xenial",
+			Arch:    "amd64",
+			Source:  "custom",
+		},
+		ImageId: "ami-golden",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	charm := s.AddTestingCharm(c, "wordpress")
+	_, err = s.State.AddApplication(state.AddApplicationArgs{
+		Name:        "wordpress",
+		Charm:       charm,
+		Constraints: constraints.MustParse("image-id=ami-golden"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddApplication(state.AddApplicationArgs{
+		Name:        "wordpress2",
+		Charm:       charm,
+		Constraints: constraints.MustParse("image-id=ami-unknown"),
+	})
+	c.Assert(err, gc.ErrorMatches, `(?s)invalid constraint value: image-id=ami-unknown\nvalid values are: \[ami-golden\]`)
+}
+
+func (s *constraintsValidationSuite) TestImageIdConstraintNotValidatedWithCustomImageMetadataURL(c *gc.C) {
+	err := s.State.CloudImageMetadataStorage.SaveMetadata([]cloudimagemetadata.Metadata{{
+		MetadataAttributes: cloudimagemetadata.MetadataAttributes{
+			Stream:  "released",
+			Region:  "dummy-region",
+			Version: "16.04",
+			Series:  "xenial",
+			Arch:    "amd64",
+			Source:  "custom",
+		},
+		ImageId: "ami-golden",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateModelConfig(map[string]interface{}{
+		"image-metadata-url": "https://images.example.com",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetModelConstraints(constraints.MustParse("image-id=ami-published"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *applicationConstraintsSuite) SetUpTest(c *gc.C) {
 	s.ConnSuite.SetUpTest(c)
 	s.policy.GetConstraintsValidator = func() (constraints.Validator, error) {
@@ -87,3 +87,33 @@ func (st *State) ControllerConfig() (jujucontroller.Config, error) {
 	}
 	return settings.Map(), nil
 }
+
+// UpdateControllerConfig changes the controller config, adding or
+// updating the attributes in updateAttrs and removing those in
+// removeAttrs. Only the attributes in
+// controller.AllowedUpdateConfigAttributes may be changed.
+func (st *State) UpdateControllerConfig(updateAttrs map[string]interface{}, removeAttrs []string) error {
+	for name := range updateAttrs {
+		if !jujucontroller.AllowedUpdateConfigAttributes.Contains(name) {
+			return errors.Errorf("can't change %q after bootstrap", name)
+		}
+	}
+	for _, name := range removeAttrs {
+		if !jujucontroller.AllowedUpdateConfigAttributes.Contains(name) {
+			return errors.Errorf("can't remove %q after bootstrap", name)
+		}
+	}
+	settings, err := readSettings(st.db(), controllersC, controllerSettingsGlobalKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range removeAttrs {
+		settings.Delete(name)
+	}
+	settings.Update(updateAttrs)
+	if err := jujucontroller.Validate(settings.Map()); err != nil {
+		return errors.Trace(err)
+	}
+	_, err = settings.Write()
+	return errors.Trace(err)
+}
@@ -25,11 +25,15 @@ func (s *ControllerSuite) TestControllerAndModelConfigInitialisation(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	optional := map[string]bool{
+		controller.AgentAPIPort:        true,
 		controller.IdentityURL:         true,
 		controller.IdentityPublicKey:   true,
 		controller.AutocertURLKey:      true,
 		controller.AutocertDNSNameKey:  true,
+		controller.APISRVNameKey:       true,
 		controller.AllowModelAccessKey: true,
+		controller.CORSAllowedOrigins:  true,
+		controller.Features:            true,
 		controller.MongoMemoryProfile:  true,
 	}
 	for _, controllerAttr := range controller.ControllerOnlyConfigAttributes {
@@ -55,6 +59,31 @@ func (s *ControllerSuite) TestControllerConfig(c *gc.C) {
 	c.Assert(cfg["controller-uuid"], gc.Equals, s.State.ControllerUUID())
 }
 
+func (s *ControllerSuite) TestUpdateControllerConfig(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.Features: "new-schema",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Features().Values(), jc.DeepEquals, []string{"new-schema"})
+
+	err = s.State.UpdateControllerConfig(nil, []string{controller.Features})
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Features().Values(), gc.HasLen, 0)
+}
+
+func (s *ControllerSuite) TestUpdateControllerConfigRejectsBootstrapOnlyAttributes(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.APIPort: 1234,
+	}, nil)
+	c.Assert(err, gc.ErrorMatches, `can't change "api-port" after bootstrap`)
+	err = s.State.UpdateControllerConfig(nil, []string{controller.AuditingEnabled})
+	c.Assert(err, gc.ErrorMatches, `can't remove "auditing-enabled" after bootstrap`)
+}
+
 func (s *ControllerSuite) TestPing(c *gc.C) {
 	c.Assert(s.Controller.Ping(), gc.IsNil)
 	gitjujutesting.MgoServer.Restart()
@@ -0,0 +1,141 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// DebugLogPreset is a named set of debug-log filters, stored on the
+// controller so that they may be shared between users.
+type DebugLogPreset struct {
+	// Name uniquely identifies the preset within the controller.
+	Name string
+
+	IncludeEntity []string
+	ExcludeEntity []string
+	IncludeModule []string
+	ExcludeModule []string
+
+	// Level is the minimum log level to show; empty means all levels.
+	Level string
+
+	// Format is the output format to use; empty means the default.
+	Format string
+}
+
+// debugLogPresetDoc is the persistent representation of a
+// DebugLogPreset.
+type debugLogPresetDoc struct {
+	Name          string   `bson:"_id"`
+	IncludeEntity []string `bson:"include-entity,omitempty"`
+	ExcludeEntity []string `bson:"exclude-entity,omitempty"`
+	IncludeModule []string `bson:"include-module,omitempty"`
+	ExcludeModule []string `bson:"exclude-module,omitempty"`
+	Level         string   `bson:"level,omitempty"`
+	Format        string   `bson:"format,omitempty"`
+}
+
+func (d debugLogPresetDoc) toPreset() DebugLogPreset {
+	return DebugLogPreset{
+		Name:          d.Name,
+		IncludeEntity: d.IncludeEntity,
+		ExcludeEntity: d.ExcludeEntity,
+		IncludeModule: d.IncludeModule,
+		ExcludeModule: d.ExcludeModule,
+		Level:         d.Level,
+		Format:        d.Format,
+	}
+}
+
+// DebugLogPresets returns all of the debug-log presets stored on the
+// controller, ordered by name.
+func (st *State) DebugLogPresets() ([]DebugLogPreset, error) {
+	coll, closer := st.db().GetCollection(debugLogPresetsC)
+	defer closer()
+
+	var docs []debugLogPresetDoc
+	if err := coll.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "getting debug-log presets")
+	}
+	presets := make([]DebugLogPreset, len(docs))
+	for i, doc := range docs {
+		presets[i] = doc.toPreset()
+	}
+	return presets, nil
+}
+
+// DebugLogPreset returns the debug-log preset with the given name.
+func (st *State) DebugLogPreset(name string) (DebugLogPreset, error) {
+	coll, closer := st.db().GetCollection(debugLogPresetsC)
+	defer closer()
+
+	var doc debugLogPresetDoc
+	err := coll.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return DebugLogPreset{}, errors.NotFoundf("debug-log preset %q", name)
+	}
+	if err != nil {
+		return DebugLogPreset{}, errors.Annotatef(err, "cannot get debug-log preset %q", name)
+	}
+	return doc.toPreset(), nil
+}
+
+// SetDebugLogPreset creates the given debug-log preset, or replaces
+// the existing preset with the same name.
+func (st *State) SetDebugLogPreset(preset DebugLogPreset) error {
+	if preset.Name == "" {
+		return errors.NotValidf("empty debug-log preset name")
+	}
+	doc := debugLogPresetDoc{
+		Name:          preset.Name,
+		IncludeEntity: preset.IncludeEntity,
+		ExcludeEntity: preset.ExcludeEntity,
+		IncludeModule: preset.IncludeModule,
+		ExcludeModule: preset.ExcludeModule,
+		Level:         preset.Level,
+		Format:        preset.Format,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		_, err := st.DebugLogPreset(preset.Name)
+		if errors.IsNotFound(err) {
+			return []txn.Op{{
+				C:      debugLogPresetsC,
+				Id:     preset.Name,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      debugLogPresetsC,
+			Id:     preset.Name,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"include-entity", doc.IncludeEntity},
+				{"exclude-entity", doc.ExcludeEntity},
+				{"include-module", doc.IncludeModule},
+				{"exclude-module", doc.ExcludeModule},
+				{"level", doc.Level},
+				{"format", doc.Format},
+			}}},
+		}}, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot set debug-log preset %q", preset.Name)
+}
+
+// RemoveDebugLogPreset removes the debug-log preset with the given
+// name. It is not an error to remove a preset that does not exist.
+func (st *State) RemoveDebugLogPreset(name string) error {
+	ops := []txn.Op{{
+		C:      debugLogPresetsC,
+		Id:     name,
+		Remove: true,
+	}}
+	return errors.Annotatef(st.db().RunTransaction(ops), "cannot remove debug-log preset %q", name)
+}
@@ -0,0 +1,80 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type DebugLogPresetsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&DebugLogPresetsSuite{})
+
+func (s *DebugLogPresetsSuite) TestSetAndGet(c *gc.C) {
+	preset := state.DebugLogPreset{
+		Name:          "noisy-neutron",
+		IncludeEntity: []string{"unit-neutron-*"},
+		ExcludeModule: []string{"juju.worker.uniter.remotestate"},
+		Level:         "WARNING",
+		Format:        "json",
+	}
+	err := s.State.SetDebugLogPreset(preset)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.State.DebugLogPreset("noisy-neutron")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, preset)
+}
+
+func (s *DebugLogPresetsSuite) TestSetReplaces(c *gc.C) {
+	err := s.State.SetDebugLogPreset(state.DebugLogPreset{
+		Name:          "mine",
+		IncludeEntity: []string{"machine-0"},
+		Level:         "DEBUG",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	replacement := state.DebugLogPreset{
+		Name:          "mine",
+		IncludeModule: []string{"juju.apiserver"},
+	}
+	err = s.State.SetDebugLogPreset(replacement)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.State.DebugLogPreset("mine")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, replacement)
+}
+
+func (s *DebugLogPresetsSuite) TestSetEmptyName(c *gc.C) {
+	err := s.State.SetDebugLogPreset(state.DebugLogPreset{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *DebugLogPresetsSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.State.DebugLogPreset("missing")
+	c.Assert(err, gc.ErrorMatches, `debug-log preset "missing" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DebugLogPresetsSuite) TestListAndRemove(c *gc.C) {
+	for _, name := range []string{"b", "a", "c"} {
+		err := s.State.SetDebugLogPreset(state.DebugLogPreset{Name: name})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.State.RemoveDebugLogPreset("b")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveDebugLogPreset("b")
+	c.Assert(err, jc.ErrorIsNil)
+
+	presets, err := s.State.DebugLogPresets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(presets, jc.DeepEquals, []state.DebugLogPreset{{Name: "a"}, {Name: "c"}})
+}
@@ -50,6 +50,7 @@ const (
 
 var (
 	BinarystorageNew                     = &binarystorageNew
+	BinarystorageNewWithBlobStore        = &binarystorageNewWithBlobStore
 	ImageStorageNewStorage               = &imageStorageNewStorage
 	MachineIdLessThan                    = machineIdLessThan
 	ControllerAvailable                  = &controllerAvailable
@@ -61,6 +62,7 @@ var (
 	ModelGlobalKey                       = modelGlobalKey
 	MergeBindings                        = mergeBindings
 	UpgradeInProgressError               = errUpgradeInProgress
+	MaxActionLogEntries                  = &maxActionLogEntries
 )
 
 type (
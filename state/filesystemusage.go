@@ -0,0 +1,137 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// FilesystemUsage describes the space used on a mounted filesystem, as
+// last reported by the machine it is attached to.
+type FilesystemUsage struct {
+	// UsedBytes is the number of bytes used on the filesystem.
+	UsedBytes uint64 `bson:"used-bytes"`
+
+	// AvailableBytes is the number of bytes available to
+	// unprivileged users of the filesystem.
+	AvailableBytes uint64 `bson:"available-bytes"`
+
+	// Updated is the time at which the usage was recorded.
+	Updated time.Time `bson:"updated"`
+}
+
+// filesystemUsageDoc records the usage of the filesystems attached to
+// a machine, keyed on filesystem ID.
+type filesystemUsageDoc struct {
+	DocID       string                     `bson:"_id"`
+	ModelUUID   string                     `bson:"model-uuid"`
+	Machine     string                     `bson:"machineid"`
+	Filesystems map[string]FilesystemUsage `bson:"filesystems"`
+}
+
+// FilesystemAttachmentUsage returns the most recently recorded usage
+// of the specified filesystem attachment, or a NotFound error if no
+// usage has been recorded.
+func (im *IAASModel) FilesystemAttachmentUsage(
+	machine names.MachineTag,
+	filesystem names.FilesystemTag,
+) (FilesystemUsage, error) {
+	doc, err := getMachineFilesystemUsage(im.mb.db(), machine.Id())
+	if err != nil {
+		return FilesystemUsage{}, errors.Trace(err)
+	}
+	usage, ok := doc.Filesystems[filesystem.Id()]
+	if !ok {
+		return FilesystemUsage{}, errors.NotFoundf(
+			"usage of filesystem %q on machine %q", filesystem.Id(), machine.Id(),
+		)
+	}
+	return usage, nil
+}
+
+// SetMachineFilesystemUsage records the usage of the filesystems
+// attached to the specified machine, keyed on filesystem tag. Usage
+// previously recorded for filesystems not in the map is removed.
+func (im *IAASModel) SetMachineFilesystemUsage(
+	machine names.MachineTag,
+	usage map[names.FilesystemTag]FilesystemUsage,
+) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set filesystem usage for machine %q", machine.Id())
+	now := im.st.clock().Now()
+	filesystems := make(map[string]FilesystemUsage, len(usage))
+	for tag, u := range usage {
+		u.Updated = now
+		filesystems[tag.Id()] = u
+	}
+	db := im.mb.db()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			m, err := im.st.Machine(machine.Id())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if m.Life() == Dead {
+				return nil, errors.New("machine is dead")
+			}
+		}
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     machine.Id(),
+			Assert: notDeadDoc,
+		}}
+		_, err := getMachineFilesystemUsage(db, machine.Id())
+		if errors.IsNotFound(err) {
+			// Usage is reported some time after a machine is
+			// created, so the document is created on demand.
+			ops = append(ops, txn.Op{
+				C:      filesystemUsageC,
+				Id:     machine.Id(),
+				Assert: txn.DocMissing,
+				Insert: &filesystemUsageDoc{
+					Machine:     machine.Id(),
+					Filesystems: filesystems,
+				},
+			})
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		} else {
+			ops = append(ops, txn.Op{
+				C:      filesystemUsageC,
+				Id:     machine.Id(),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"filesystems", filesystems}}}},
+			})
+		}
+		return ops, nil
+	}
+	return db.Run(buildTxn)
+}
+
+func getMachineFilesystemUsage(db Database, machineId string) (*filesystemUsageDoc, error) {
+	coll, cleanup := db.GetCollection(filesystemUsageC)
+	defer cleanup()
+
+	var doc filesystemUsageDoc
+	err := coll.FindId(machineId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("filesystem usage for machine %q", machineId)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get filesystem usage")
+	}
+	return &doc, nil
+}
+
+func removeMachineFilesystemUsageOp(machineId string) txn.Op {
+	return txn.Op{
+		C:      filesystemUsageC,
+		Id:     machineId,
+		Remove: true,
+	}
+}
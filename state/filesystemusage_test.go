@@ -0,0 +1,101 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/state"
+)
+
+type FilesystemUsageSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&FilesystemUsageSuite{})
+
+func (s *FilesystemUsageSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *FilesystemUsageSuite) TestFilesystemAttachmentUsageNotReported(c *gc.C) {
+	_, err := s.IAASModel.FilesystemAttachmentUsage(s.machine.MachineTag(), names.NewFilesystemTag("0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *FilesystemUsageSuite) TestSetMachineFilesystemUsage(c *gc.C) {
+	fs0 := names.NewFilesystemTag("0")
+	fs1 := names.NewFilesystemTag(s.machine.Id() + "/1")
+	err := s.IAASModel.SetMachineFilesystemUsage(s.machine.MachineTag(), map[names.FilesystemTag]state.FilesystemUsage{
+		fs0: {UsedBytes: 1024, AvailableBytes: 2048},
+		fs1: {UsedBytes: 1, AvailableBytes: 2},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := s.IAASModel.FilesystemAttachmentUsage(s.machine.MachineTag(), fs0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage.UsedBytes, gc.Equals, uint64(1024))
+	c.Assert(usage.AvailableBytes, gc.Equals, uint64(2048))
+	c.Assert(usage.Updated.Equal(s.Clock.Now()), jc.IsTrue)
+
+	usage, err = s.IAASModel.FilesystemAttachmentUsage(s.machine.MachineTag(), fs1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage.UsedBytes, gc.Equals, uint64(1))
+	c.Assert(usage.AvailableBytes, gc.Equals, uint64(2))
+}
+
+func (s *FilesystemUsageSuite) TestSetMachineFilesystemUsageReplaces(c *gc.C) {
+	fs0 := names.NewFilesystemTag("0")
+	fs1 := names.NewFilesystemTag("1")
+	err := s.IAASModel.SetMachineFilesystemUsage(s.machine.MachineTag(), map[names.FilesystemTag]state.FilesystemUsage{
+		fs0: {UsedBytes: 1024, AvailableBytes: 2048},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Clock.Advance(time.Minute)
+	err = s.IAASModel.SetMachineFilesystemUsage(s.machine.MachineTag(), map[names.FilesystemTag]state.FilesystemUsage{
+		fs1: {UsedBytes: 4096, AvailableBytes: 0},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.IAASModel.FilesystemAttachmentUsage(s.machine.MachineTag(), fs0)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	usage, err := s.IAASModel.FilesystemAttachmentUsage(s.machine.MachineTag(), fs1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage.UsedBytes, gc.Equals, uint64(4096))
+	c.Assert(usage.Updated.Equal(s.Clock.Now()), jc.IsTrue)
+}
+
+func (s *FilesystemUsageSuite) TestSetMachineFilesystemUsageMachineDead(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.IAASModel.SetMachineFilesystemUsage(s.machine.MachineTag(), map[names.FilesystemTag]state.FilesystemUsage{
+		names.NewFilesystemTag("0"): {UsedBytes: 1024},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot set filesystem usage for machine "0": machine is dead`)
+}
+
+func (s *FilesystemUsageSuite) TestFilesystemUsageMachineRemove(c *gc.C) {
+	err := s.IAASModel.SetMachineFilesystemUsage(s.machine.MachineTag(), map[names.FilesystemTag]state.FilesystemUsage{
+		names.NewFilesystemTag("0"): {UsedBytes: 1024},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.IAASModel.FilesystemAttachmentUsage(s.machine.MachineTag(), names.NewFilesystemTag("0"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
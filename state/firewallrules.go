@@ -0,0 +1,150 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// WellKnownServiceType identifies a controller-level service whose
+// ingress may be restricted by a firewall rule.
+type WellKnownServiceType string
+
+const (
+	// SSHRule applies to SSH access to machines.
+	SSHRule WellKnownServiceType = "ssh"
+
+	// JujuControllerRule applies to the controller's API port.
+	JujuControllerRule WellKnownServiceType = "juju-controller"
+
+	// JujuApplicationOfferRule applies to connections made from other
+	// models consuming offers hosted on the controller.
+	JujuApplicationOfferRule WellKnownServiceType = "juju-application-offer"
+)
+
+// Validate returns an error if the service type is not one of the
+// well known services.
+func (s WellKnownServiceType) Validate() error {
+	switch s {
+	case SSHRule, JujuControllerRule, JujuApplicationOfferRule:
+		return nil
+	}
+	return errors.NotValidf("well known service type %q", s)
+}
+
+// FirewallRule holds the addresses allowed to reach a well known
+// service. The rules are stored on the controller, and are applied to
+// every model by providers that support security groups.
+type FirewallRule struct {
+	// WellKnownService is the service to which the rule applies.
+	WellKnownService WellKnownServiceType
+
+	// WhitelistCIDRs holds the source CIDRs allowed to connect to
+	// the service.
+	WhitelistCIDRs []string
+}
+
+// Validate returns an error if the rule is not valid.
+func (r FirewallRule) Validate() error {
+	if err := r.WellKnownService.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	for _, cidr := range r.WhitelistCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("CIDR %q", cidr)
+		}
+	}
+	return nil
+}
+
+// firewallRuleDoc is the persistent representation of a FirewallRule.
+type firewallRuleDoc struct {
+	WellKnownService string   `bson:"_id"`
+	WhitelistCIDRs   []string `bson:"whitelist-cidrs"`
+}
+
+func (d firewallRuleDoc) toRule() FirewallRule {
+	return FirewallRule{
+		WellKnownService: WellKnownServiceType(d.WellKnownService),
+		WhitelistCIDRs:   d.WhitelistCIDRs,
+	}
+}
+
+// FirewallRules returns all of the firewall rules stored on the
+// controller, ordered by service.
+func (st *State) FirewallRules() ([]FirewallRule, error) {
+	coll, closer := st.db().GetCollection(firewallRulesC)
+	defer closer()
+
+	var docs []firewallRuleDoc
+	if err := coll.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "getting firewall rules")
+	}
+	rules := make([]FirewallRule, len(docs))
+	for i, doc := range docs {
+		rules[i] = doc.toRule()
+	}
+	return rules, nil
+}
+
+// FirewallRule returns the firewall rule for the given service.
+func (st *State) FirewallRule(service WellKnownServiceType) (FirewallRule, error) {
+	coll, closer := st.db().GetCollection(firewallRulesC)
+	defer closer()
+
+	var doc firewallRuleDoc
+	err := coll.FindId(string(service)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return FirewallRule{}, errors.NotFoundf("firewall rule for %q", service)
+	}
+	if err != nil {
+		return FirewallRule{}, errors.Annotatef(err, "cannot get firewall rule for %q", service)
+	}
+	return doc.toRule(), nil
+}
+
+// SaveFirewallRule creates the given firewall rule, or replaces the
+// existing rule for the same service.
+func (st *State) SaveFirewallRule(rule FirewallRule) error {
+	if err := rule.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	doc := firewallRuleDoc{
+		WellKnownService: string(rule.WellKnownService),
+		WhitelistCIDRs:   rule.WhitelistCIDRs,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		_, err := st.FirewallRule(rule.WellKnownService)
+		if errors.IsNotFound(err) {
+			return []txn.Op{{
+				C:      firewallRulesC,
+				Id:     doc.WellKnownService,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      firewallRulesC,
+			Id:     doc.WellKnownService,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"whitelist-cidrs", doc.WhitelistCIDRs},
+			}}},
+		}}, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot save firewall rule for %q", rule.WellKnownService)
+}
+
+// WatchFirewallRules returns a NotifyWatcher that triggers whenever
+// any firewall rule is saved.
+func (st *State) WatchFirewallRules() NotifyWatcher {
+	return newNotifyCollWatcher(st, firewallRulesC, nil)
+}
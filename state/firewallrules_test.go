@@ -0,0 +1,115 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type FirewallRulesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&FirewallRulesSuite{})
+
+func (s *FirewallRulesSuite) TestSaveAndGet(c *gc.C) {
+	rule := state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.1.0/24", "10.0.0.1/32"},
+	}
+	err := s.State.SaveFirewallRule(rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.State.FirewallRule(state.SSHRule)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, rule)
+}
+
+func (s *FirewallRulesSuite) TestSaveReplaces(c *gc.C) {
+	err := s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: state.JujuControllerRule,
+		WhitelistCIDRs:   []string{"192.168.1.0/24"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	replacement := state.FirewallRule{
+		WellKnownService: state.JujuControllerRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	}
+	err = s.State.SaveFirewallRule(replacement)
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.State.FirewallRule(state.JujuControllerRule)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, replacement)
+}
+
+func (s *FirewallRulesSuite) TestSaveInvalid(c *gc.C) {
+	err := s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: "telnet",
+	})
+	c.Assert(err, gc.ErrorMatches, `well known service type "telnet" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	err = s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.1.1"},
+	})
+	c.Assert(err, gc.ErrorMatches, `CIDR "192.168.1.1" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *FirewallRulesSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.State.FirewallRule(state.SSHRule)
+	c.Assert(err, gc.ErrorMatches, `firewall rule for "ssh" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *FirewallRulesSuite) TestList(c *gc.C) {
+	for _, service := range []state.WellKnownServiceType{state.SSHRule, state.JujuApplicationOfferRule} {
+		err := s.State.SaveFirewallRule(state.FirewallRule{
+			WellKnownService: service,
+			WhitelistCIDRs:   []string{"10.0.0.0/8"},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	rules, err := s.State.FirewallRules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []state.FirewallRule{{
+		WellKnownService: state.JujuApplicationOfferRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	}, {
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	}})
+}
+
+func (s *FirewallRulesSuite) TestWatch(c *gc.C) {
+	w := s.State.WatchFirewallRules()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.State.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"10.0.0.0/8"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Rules are shared by all models on the controller.
+	otherState := s.Factory.MakeModel(c, nil)
+	defer otherState.Close()
+	err = otherState.SaveFirewallRule(state.FirewallRule{
+		WellKnownService: state.SSHRule,
+		WhitelistCIDRs:   []string{"192.168.0.0/16"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
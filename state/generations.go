@@ -0,0 +1,435 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// generationDoc represents a branch of the model: a set of charm config
+// changes staged against the units tracking the branch, which form the
+// next generation of the model until the branch is committed.
+type generationDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	Name      string `bson:"name"`
+
+	// AssignedUnits holds the names of the units tracking the
+	// branch, keyed on application name.
+	AssignedUnits map[string][]string `bson:"assigned-units"`
+
+	// Config holds the staged config changes, keyed on application
+	// name. A nil value resets the setting to the charm default.
+	Config map[string]settingsMap `bson:"config"`
+
+	Created   int64  `bson:"created"`
+	CreatedBy string `bson:"created-by"`
+
+	// Completed is zero until the branch is committed or aborted.
+	Completed   int64  `bson:"completed"`
+	CompletedBy string `bson:"completed-by"`
+	Committed   bool   `bson:"committed"`
+
+	TxnRevno int64 `bson:"txn-revno"`
+}
+
+// Generation is a branch of the model, against which charm config
+// changes are staged. Units tracking the branch see the staged config;
+// all others see the config of the current generation. Committing the
+// branch applies the staged changes to every unit at once.
+type Generation struct {
+	st  *State
+	doc generationDoc
+}
+
+// validBranchName matches valid branch names.
+var validBranchName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// AddBranch creates a new, empty branch with the given name. The name
+// of a branch that has been committed or aborted may be reused.
+func (st *State) AddBranch(name, createdBy string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add branch %q", name)
+	if !validBranchName.MatchString(name) {
+		return errors.NotValidf("branch name %q", name)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.Branch(name); err == nil {
+			return nil, errors.AlreadyExistsf("branch")
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		// Completed branches are kept, so branches are identified
+		// by sequence rather than by name.
+		seq, err := sequence(st, "branch")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      generationsC,
+			Id:     strconv.Itoa(seq),
+			Assert: txn.DocMissing,
+			Insert: &generationDoc{
+				Name:          name,
+				AssignedUnits: map[string][]string{},
+				Config:        map[string]settingsMap{},
+				Created:       st.clock().Now().Unix(),
+				CreatedBy:     createdBy,
+			},
+		}}, nil
+	}
+	return st.db().Run(buildTxn)
+}
+
+// Branch returns the branch with the given name that has been neither
+// committed nor aborted.
+func (st *State) Branch(name string) (*Generation, error) {
+	doc, err := getBranch(st.db(), name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Generation{st: st, doc: *doc}, nil
+}
+
+// Branches returns the branches of the model that have been neither
+// committed nor aborted, ordered by name.
+func (st *State) Branches() ([]*Generation, error) {
+	coll, closer := st.db().GetCollection(generationsC)
+	defer closer()
+
+	var docs []generationDoc
+	if err := coll.Find(bson.D{{"completed", 0}}).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get branches")
+	}
+	result := make([]*Generation, len(docs))
+	for i, doc := range docs {
+		result[i] = &Generation{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// HasBranches returns whether the model has any branches that have been
+// neither committed nor aborted.
+func (st *State) HasBranches() (bool, error) {
+	coll, closer := st.db().GetCollection(generationsC)
+	defer closer()
+	count, err := coll.Find(bson.D{{"completed", 0}}).Count()
+	if err != nil {
+		return false, errors.Annotate(err, "cannot count branches")
+	}
+	return count > 0, nil
+}
+
+// Name returns the name of the branch.
+func (g *Generation) Name() string {
+	return g.doc.Name
+}
+
+// CreatedBy returns the name of the user that created the branch.
+func (g *Generation) CreatedBy() string {
+	return g.doc.CreatedBy
+}
+
+// AssignedUnits returns the names of the units tracking the branch,
+// keyed on application name.
+func (g *Generation) AssignedUnits() map[string][]string {
+	return g.doc.AssignedUnits
+}
+
+// Config returns the config changes staged on the branch, keyed on
+// application name.
+func (g *Generation) Config() map[string]charm.Settings {
+	result := make(map[string]charm.Settings, len(g.doc.Config))
+	for app, settings := range g.doc.Config {
+		result[app] = charm.Settings(settings)
+	}
+	return result
+}
+
+// IsCompleted reports whether the branch has been committed or aborted.
+func (g *Generation) IsCompleted() bool {
+	return g.doc.Completed > 0
+}
+
+// IsCommitted reports whether the branch has been committed.
+func (g *Generation) IsCommitted() bool {
+	return g.doc.Committed
+}
+
+// Refresh refreshes the contents of the branch from the underlying
+// state.
+func (g *Generation) Refresh() error {
+	doc, err := getGeneration(g.st.db(), g.doc.DocID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	g.doc = *doc
+	return nil
+}
+
+// AssignUnit makes the named unit track the branch. A unit may track
+// only one branch at a time.
+func (g *Generation) AssignUnit(unitName string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot assign unit %q to branch %q", unitName, g.doc.Name)
+	if !names.IsValidUnit(unitName) {
+		return errors.NotValidf("unit name %q", unitName)
+	}
+	appName, err := names.UnitApplication(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := g.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if g.IsCompleted() {
+			return nil, errors.New("branch is completed")
+		}
+		for _, name := range g.doc.AssignedUnits[appName] {
+			if name == unitName {
+				return nil, jujutxn.ErrNoOperations
+			}
+		}
+		unit, err := g.st.Unit(unitName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if unit.Life() != Alive {
+			return nil, errors.New("unit is not alive")
+		}
+		other, err := unitBranch(g.st.db(), appName, unitName)
+		if err == nil {
+			return nil, errors.Errorf("unit is tracking branch %q", other.Name)
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: isAliveDoc,
+		}, {
+			C:      generationsC,
+			Id:     g.doc.DocID,
+			Assert: bson.D{{"txn-revno", g.doc.TxnRevno}},
+			Update: bson.D{{"$addToSet", bson.D{{"assigned-units." + appName, unitName}}}},
+		}}, nil
+	}
+	return g.st.db().Run(buildTxn)
+}
+
+// AssignAllUnits makes all units of the named application track the
+// branch, including any that are already tracking it.
+func (g *Generation) AssignAllUnits(appName string) error {
+	app, err := g.st.Application(appName)
+	if err != nil {
+		return errors.Annotatef(err, "cannot assign units to branch %q", g.doc.Name)
+	}
+	units, err := app.AllUnits()
+	if err != nil {
+		return errors.Annotatef(err, "cannot assign units to branch %q", g.doc.Name)
+	}
+	for _, unit := range units {
+		if err := g.AssignUnit(unit.Name()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// UpdateCharmConfig stages the given changes to the named
+// application's charm config on the branch, in addition to any already
+// staged. As with Application.UpdateConfigSettings, values set to nil
+// reset the setting to the charm default when the branch is committed.
+func (g *Generation) UpdateCharmConfig(appName string, changes charm.Settings) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot update config of %q on branch %q", appName, g.doc.Name)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := g.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if g.IsCompleted() {
+			return nil, errors.New("branch is completed")
+		}
+		app, err := g.st.Application(appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ch, _, err := app.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		validated, err := ch.Config().ValidateSettings(changes)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(validated) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		var set bson.D
+		for name, value := range validated {
+			key := "config." + appName + "." + escapeReplacer.Replace(name)
+			set = append(set, bson.DocElem{key, value})
+		}
+		return []txn.Op{{
+			C:      applicationsC,
+			Id:     app.doc.DocID,
+			Assert: bson.D{{"life", Alive}, {"charmurl", app.doc.CharmURL}},
+		}, {
+			C:      generationsC,
+			Id:     g.doc.DocID,
+			Assert: bson.D{{"txn-revno", g.doc.TxnRevno}},
+			Update: bson.D{{"$set", set}},
+		}}, nil
+	}
+	return g.st.db().Run(buildTxn)
+}
+
+// Commit applies the config changes staged on the branch to the
+// current generation of the model, so that all units see them, and
+// completes the branch. Either all of the changes are made or none of
+// them are.
+func (g *Generation) Commit(userName string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot commit branch %q", g.doc.Name)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := g.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if g.IsCompleted() {
+			return nil, errors.New("branch is completed")
+		}
+		appNames := make([]string, 0, len(g.doc.Config))
+		for name := range g.doc.Config {
+			appNames = append(appNames, name)
+		}
+		sort.Strings(appNames)
+		ops := []txn.Op{g.completeOp(userName, true)}
+		for _, name := range appNames {
+			app, err := g.st.Application(name)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			appOps, err := app.configSettingsUpdateOps(charm.Settings(g.doc.Config[name]))
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, appOps...)
+		}
+		return ops, nil
+	}
+	return g.st.db().Run(buildTxn)
+}
+
+// Abort completes the branch without applying the config changes staged
+// on it. Units tracking the branch revert to the config of the current
+// generation.
+func (g *Generation) Abort(userName string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot abort branch %q", g.doc.Name)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := g.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if g.IsCompleted() {
+			return nil, errors.New("branch is completed")
+		}
+		return []txn.Op{g.completeOp(userName, false)}, nil
+	}
+	return g.st.db().Run(buildTxn)
+}
+
+func (g *Generation) completeOp(userName string, committed bool) txn.Op {
+	return txn.Op{
+		C:      generationsC,
+		Id:     g.doc.DocID,
+		Assert: bson.D{{"txn-revno", g.doc.TxnRevno}},
+		Update: bson.D{{"$set", bson.D{
+			{"completed", g.st.clock().Now().Unix()},
+			{"completed-by", userName},
+			{"committed", committed},
+		}}},
+	}
+}
+
+// getBranch returns the uncompleted branch with the given name.
+func getBranch(db Database, name string) (*generationDoc, error) {
+	coll, closer := db.GetCollection(generationsC)
+	defer closer()
+
+	var doc generationDoc
+	err := coll.Find(bson.D{{"name", name}, {"completed", 0}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("branch %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get branch %q", name)
+	}
+	return &doc, nil
+}
+
+// getGeneration returns the branch with the given document id, whether
+// or not it has been completed.
+func getGeneration(db Database, docID string) (*generationDoc, error) {
+	coll, closer := db.GetCollection(generationsC)
+	defer closer()
+
+	var doc generationDoc
+	err := coll.FindId(docID).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("branch")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get branch")
+	}
+	return &doc, nil
+}
+
+// unitBranch returns the uncompleted branch tracked by the named unit
+// of the named application, or a NotFound error if it tracks none.
+func unitBranch(db Database, appName, unitName string) (*generationDoc, error) {
+	coll, closer := db.GetCollection(generationsC)
+	defer closer()
+
+	var doc generationDoc
+	err := coll.Find(bson.D{
+		{"completed", 0},
+		{"assigned-units." + appName, unitName},
+	}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("branch tracked by unit %q", unitName)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get branch tracked by unit %q", unitName)
+	}
+	return &doc, nil
+}
+
+// removeUnitBranchOps returns the operations that stop the named unit
+// of the named application tracking its branch, if any, for use when
+// the unit is removed.
+func removeUnitBranchOps(db Database, appName, unitName string) ([]txn.Op, error) {
+	branch, err := unitBranch(db, appName, unitName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []txn.Op{{
+		C:      generationsC,
+		Id:     branch.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$pull", bson.D{{"assigned-units." + appName, unitName}}}},
+	}}, nil
+}
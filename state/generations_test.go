@@ -0,0 +1,297 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type GenerationSuite struct {
+	ConnSuite
+	app   *state.Application
+	units []*state.Unit
+}
+
+var _ = gc.Suite(&GenerationSuite{})
+
+func (s *GenerationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	ch := s.AddTestingCharm(c, "wordpress")
+	s.app = s.AddTestingApplication(c, "wordpress", ch)
+	s.units = nil
+	for i := 0; i < 2; i++ {
+		unit, err := s.app.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(ch.URL())
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *GenerationSuite) addBranch(c *gc.C, name string) *state.Generation {
+	err := s.State.AddBranch(name, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	branch, err := s.State.Branch(name)
+	c.Assert(err, jc.ErrorIsNil)
+	return branch
+}
+
+func (s *GenerationSuite) assertBlogTitle(c *gc.C, unit *state.Unit, expect string) {
+	settings, err := unit.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["blog-title"], gc.Equals, expect)
+}
+
+func (s *GenerationSuite) TestAddBranch(c *gc.C) {
+	branch := s.addBranch(c, "canary")
+	c.Assert(branch.Name(), gc.Equals, "canary")
+	c.Assert(branch.CreatedBy(), gc.Equals, "admin")
+	c.Assert(branch.AssignedUnits(), gc.HasLen, 0)
+	c.Assert(branch.Config(), gc.HasLen, 0)
+	c.Assert(branch.IsCompleted(), jc.IsFalse)
+}
+
+func (s *GenerationSuite) TestAddBranchAlreadyExists(c *gc.C) {
+	s.addBranch(c, "canary")
+	err := s.State.AddBranch("canary", "admin")
+	c.Assert(err, gc.ErrorMatches, `cannot add branch "canary": branch already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *GenerationSuite) TestAddBranchReusesCompletedName(c *gc.C) {
+	aborted := s.addBranch(c, "canary")
+	err := aborted.Abort("admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	branch := s.addBranch(c, "canary")
+	c.Assert(branch.IsCompleted(), jc.IsFalse)
+	err = branch.AssignUnit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The completed branch is unaffected.
+	err = aborted.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(aborted.IsCompleted(), jc.IsTrue)
+	c.Assert(aborted.AssignedUnits(), gc.HasLen, 0)
+}
+
+func (s *GenerationSuite) TestAddBranchInvalidName(c *gc.C) {
+	err := s.State.AddBranch("Canary!", "admin")
+	c.Assert(err, gc.ErrorMatches, `cannot add branch "Canary!": branch name "Canary!" not valid`)
+}
+
+func (s *GenerationSuite) TestBranchNotFound(c *gc.C) {
+	_, err := s.State.Branch("canary")
+	c.Assert(err, gc.ErrorMatches, `branch "canary" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *GenerationSuite) TestBranchesExcludesCompleted(c *gc.C) {
+	s.addBranch(c, "canary")
+	aborted := s.addBranch(c, "aborted")
+	s.addBranch(c, "beta")
+	err := aborted.Abort("admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	branches, err := s.State.Branches()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, branch := range branches {
+		names = append(names, branch.Name())
+	}
+	c.Assert(names, jc.DeepEquals, []string{"beta", "canary"})
+}
+
+func (s *GenerationSuite) TestHasBranches(c *gc.C) {
+	hasBranches, err := s.State.HasBranches()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasBranches, jc.IsFalse)
+
+	branch := s.addBranch(c, "canary")
+	hasBranches, err = s.State.HasBranches()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasBranches, jc.IsTrue)
+
+	err = branch.Abort("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	hasBranches, err = s.State.HasBranches()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasBranches, jc.IsFalse)
+}
+
+func (s *GenerationSuite) TestStagedConfigSeenByTrackingUnits(c *gc.C) {
+	branch := s.addBranch(c, "canary")
+	err := branch.AssignUnit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = branch.UpdateCharmConfig("wordpress", charm.Settings{"blog-title": "canary"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = branch.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(branch.AssignedUnits(), jc.DeepEquals, map[string][]string{
+		"wordpress": {"wordpress/0"},
+	})
+	c.Assert(branch.Config(), jc.DeepEquals, map[string]charm.Settings{
+		"wordpress": {"blog-title": "canary"},
+	})
+	s.assertBlogTitle(c, s.units[0], "canary")
+	s.assertBlogTitle(c, s.units[1], "My Title")
+}
+
+func (s *GenerationSuite) TestStagedConfigReset(c *gc.C) {
+	err := s.app.UpdateConfigSettings(charm.Settings{"blog-title": "current"})
+	c.Assert(err, jc.ErrorIsNil)
+	branch := s.addBranch(c, "canary")
+	err = branch.AssignUnit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = branch.UpdateCharmConfig("wordpress", charm.Settings{"blog-title": nil})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertBlogTitle(c, s.units[0], "My Title")
+	s.assertBlogTitle(c, s.units[1], "current")
+}
+
+func (s *GenerationSuite) TestUpdateCharmConfigInvalid(c *gc.C) {
+	branch := s.addBranch(c, "canary")
+	err := branch.UpdateCharmConfig("wordpress", charm.Settings{"no-such-option": "x"})
+	c.Assert(err, gc.ErrorMatches, `cannot update config of "wordpress" on branch "canary": unknown option "no-such-option"`)
+}
+
+func (s *GenerationSuite) TestAssignUnitTrackingOtherBranch(c *gc.C) {
+	canary := s.addBranch(c, "canary")
+	err := canary.AssignUnit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	beta := s.addBranch(c, "beta")
+	err = beta.AssignUnit("wordpress/0")
+	c.Assert(err, gc.ErrorMatches, `cannot assign unit "wordpress/0" to branch "beta": unit is tracking branch "canary"`)
+
+	// Assigning a unit again is a no-op.
+	err = canary.AssignUnit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *GenerationSuite) TestAssignAllUnits(c *gc.C) {
+	branch := s.addBranch(c, "canary")
+	err := branch.AssignUnit("wordpress/1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = branch.AssignAllUnits("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = branch.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(branch.AssignedUnits()["wordpress"], jc.SameContents, []string{"wordpress/0", "wordpress/1"})
+}
+
+func (s *GenerationSuite) TestRemovedUnitStopsTrackingBranch(c *gc.C) {
+	branch := s.addBranch(c, "canary")
+	err := branch.AssignAllUnits("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.units[0].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = branch.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(branch.AssignedUnits(), jc.DeepEquals, map[string][]string{
+		"wordpress": {"wordpress/1"},
+	})
+}
+
+func (s *GenerationSuite) TestCommit(c *gc.C) {
+	branch := s.addBranch(c, "canary")
+	err := branch.AssignUnit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = branch.UpdateCharmConfig("wordpress", charm.Settings{"blog-title": "canary"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = branch.Commit("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = branch.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(branch.IsCompleted(), jc.IsTrue)
+	c.Assert(branch.IsCommitted(), jc.IsTrue)
+
+	settings, err := s.app.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{"blog-title": "canary"})
+	s.assertBlogTitle(c, s.units[0], "canary")
+	s.assertBlogTitle(c, s.units[1], "canary")
+
+	err = branch.Commit("admin")
+	c.Assert(err, gc.ErrorMatches, `cannot commit branch "canary": branch is completed`)
+}
+
+func (s *GenerationSuite) TestAbort(c *gc.C) {
+	branch := s.addBranch(c, "canary")
+	err := branch.AssignUnit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = branch.UpdateCharmConfig("wordpress", charm.Settings{"blog-title": "canary"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = branch.Abort("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = branch.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(branch.IsCompleted(), jc.IsTrue)
+	c.Assert(branch.IsCommitted(), jc.IsFalse)
+	s.assertBlogTitle(c, s.units[0], "My Title")
+
+	err = branch.UpdateCharmConfig("wordpress", charm.Settings{"blog-title": "again"})
+	c.Assert(err, gc.ErrorMatches, `cannot update config of "wordpress" on branch "canary": branch is completed`)
+}
+
+func (s *GenerationSuite) TestWatchConfigSettingsTrackingUnitOnly(c *gc.C) {
+	branch := s.addBranch(c, "canary")
+	err := branch.AssignUnit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var watchers []testing.NotifyWatcherC
+	for _, unit := range s.units {
+		w, err := unit.WatchConfigSettings()
+		c.Assert(err, jc.ErrorIsNil)
+		defer testing.AssertStop(c, w)
+		wc := testing.NewNotifyWatcherC(c, s.State, w)
+		wc.AssertOneChange()
+		watchers = append(watchers, wc)
+	}
+
+	// Only the tracking unit sees changes staged on the branch.
+	err = branch.UpdateCharmConfig("wordpress", charm.Settings{"blog-title": "canary"})
+	c.Assert(err, jc.ErrorIsNil)
+	watchers[0].AssertOneChange()
+	watchers[1].AssertNoChange()
+
+	// Once committed, the other units see them too.
+	err = branch.Commit("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	watchers[0].AssertNoChange()
+	watchers[1].AssertOneChange()
+}
+
+func (s *GenerationSuite) TestWatchConfigSettingsIgnoresUntrackedBranches(c *gc.C) {
+	w, err := s.units[0].WatchConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Branches not tracked by units of the application do not
+	// affect the unit.
+	branch := s.addBranch(c, "canary")
+	err = branch.UpdateCharmConfig("wordpress", charm.Settings{"blog-title": "canary"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = branch.AssignUnit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -143,6 +143,10 @@ type ActionReceiver interface {
 	// ActionReceiver.
 	AddAction(name string, payload map[string]interface{}) (Action, error)
 
+	// AddTask queues an action with the given name and payload for
+	// this ActionReceiver, as a task of the operation with the given id.
+	AddTask(operationID, name string, payload map[string]interface{}) (Action, error)
+
 	// CancelAction removes a pending Action from the queue for this
 	// ActionReceiver and marks it as cancelled.
 	CancelAction(action Action) (Action, error)
@@ -210,6 +214,18 @@ type Action interface {
 	// Results returns the structured output of the action and any error.
 	Results() (map[string]interface{}, string)
 
+	// Progress returns the most recent progress message and completion
+	// percentage reported by the running action.
+	Progress() (string, int)
+
+	// Messages returns the messages logged by the action while it
+	// was running, in the order they were logged.
+	Messages() []ActionMessage
+
+	// OperationID returns the id of the operation the action is a
+	// task of, or "" if it was not enqueued as part of an operation.
+	OperationID() string
+
 	// ActionTag returns an ActionTag constructed from this action's
 	// Prefix and Sequence.
 	ActionTag() names.ActionTag
@@ -218,6 +234,15 @@ type Action interface {
 	// It asserts that the action is currently pending.
 	Begin() (Action, error)
 
+	// SetProgress records an intermediate progress message and
+	// completion percentage for the action. It asserts that the
+	// action is currently running.
+	SetProgress(message string, percent int) error
+
+	// Log appends a timestamped message to the action's logs. It
+	// asserts that the action is currently running.
+	Log(message string) error
+
 	// Finish removes action from the pending queue and captures the output
 	// and end state of the action.
 	Finish(results ActionResults) (Action, error)
@@ -4,8 +4,12 @@
 package audit
 
 import (
+	"sort"
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/version"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/audit"
 	"github.com/juju/juju/mongo/utils"
@@ -66,7 +70,9 @@ func PutAuditEntryFn(
 
 func auditEntryDocFromAuditEntry(auditEntry audit.AuditEntry) (auditEntryDoc, error) {
 
-	timeAsBlob, err := auditEntry.Timestamp.MarshalText()
+	// Entries are written in UTC so that their timestamps sort
+	// chronologically. See GetAuditEntriesFn.
+	timeAsBlob, err := auditEntry.Timestamp.UTC().MarshalText()
 	if err != nil {
 		return auditEntryDoc{}, errors.Trace(err)
 	}
@@ -82,3 +88,82 @@ func auditEntryDocFromAuditEntry(auditEntry audit.AuditEntry) (auditEntryDoc, er
 		Data:              utils.EscapeKeys(auditEntry.Data),
 	}, nil
 }
+
+// FindDocsFunc finds the documents in the named collection that match
+// the query, sorted by the given fields and, if limit is positive, at
+// most limit of them, and stores them in docs.
+type FindDocsFunc func(collectionName string, query bson.D, sort []string, limit int, docs interface{}) error
+
+// maxZoneOffset is the greatest offset of any time zone from UTC.
+const maxZoneOffset = 14 * time.Hour
+
+// textTimeLayout formats times as the text written by
+// time.Time.MarshalText, without the fractional seconds and zone, so
+// that it sorts before the text of any time in the same second.
+const textTimeLayout = "2006-01-02T15:04:05"
+
+// GetAuditEntriesFn creates a closure which when passed a model UUID,
+// a time, a regular expression and a limit will return the entries in
+// the audit collection for that model written at or after that time
+// whose operations match the expression, if it is not empty, oldest
+// first. If limit is positive, only the most recent limit entries are
+// returned.
+//
+// The timestamps are stored as text, which the database compares
+// chronologically only when written in the same time zone, as
+// entries are written in UTC. The database's time filter is widened
+// by the greatest time zone offset so that entries written in other
+// zones are not missed, and the exact filter is applied here.
+func GetAuditEntriesFn(
+	collectionName string,
+	findDocs FindDocsFunc,
+) func(string, time.Time, string, int) ([]audit.AuditEntry, error) {
+	return func(modelUUID string, since time.Time, operationPattern string, limit int) ([]audit.AuditEntry, error) {
+		var docs []auditEntryDoc
+		query := bson.D{
+			{"model-uuid", modelUUID},
+			{"timestamp", bson.D{{"$gte", since.UTC().Add(-maxZoneOffset).Format(textTimeLayout)}}},
+		}
+		if operationPattern != "" {
+			query = append(query, bson.DocElem{"operation", bson.RegEx{Pattern: operationPattern}})
+		}
+		if err := findDocs(collectionName, query, []string{"-timestamp"}, limit, &docs); err != nil {
+			return nil, errors.Trace(err)
+		}
+		var entries []audit.AuditEntry
+		for _, doc := range docs {
+			auditEntry, err := auditEntryFromAuditEntryDoc(doc)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if auditEntry.Timestamp.Before(since) {
+				continue
+			}
+			entries = append(entries, auditEntry)
+		}
+		// The text of times within the same second doesn't
+		// sort chronologically, so we sort the entries here.
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		})
+		return entries, nil
+	}
+}
+
+func auditEntryFromAuditEntryDoc(doc auditEntryDoc) (audit.AuditEntry, error) {
+	var timestamp time.Time
+	if err := timestamp.UnmarshalText([]byte(doc.Timestamp)); err != nil {
+		return audit.AuditEntry{}, errors.Annotatef(err, "cannot parse audit entry timestamp %q", doc.Timestamp)
+	}
+
+	return audit.AuditEntry{
+		JujuServerVersion: doc.JujuServerVersion,
+		ModelUUID:         doc.ModelUUID,
+		Timestamp:         timestamp.UTC(),
+		RemoteAddress:     doc.RemoteAddress,
+		OriginType:        doc.OriginType,
+		OriginName:        doc.OriginName,
+		Operation:         doc.Operation,
+		Data:              utils.UnescapeKeys(doc.Data),
+	}, nil
+}
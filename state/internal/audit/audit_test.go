@@ -4,6 +4,8 @@
 package audit_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -111,3 +113,71 @@ func (*AuditSuite) TestPutAuditEntry_ValidateAuditEntry(c *gc.C) {
 	err := putAuditEntry(auditEntry)
 	c.Check(err, gc.ErrorMatches, validationErr.Error())
 }
+
+func (*AuditSuite) TestGetAuditEntries(c *gc.C) {
+	modelUUID := utils.MustNewUUID().String()
+	base := coretesting.NonZeroTime().UTC()
+	entry := func(offset time.Duration, operation string) audit.AuditEntry {
+		return audit.AuditEntry{
+			JujuServerVersion: version.MustParse("1.0.0"),
+			ModelUUID:         modelUUID,
+			Timestamp:         base.Add(offset),
+			RemoteAddress:     "8.8.8.8",
+			OriginType:        "API request",
+			OriginName:        "user-bob",
+			Operation:         operation,
+			Data:              map[string]interface{}{"$a.b": "c"},
+		}
+	}
+
+	var stored []interface{}
+	insertDocs := func(_ string, docs ...interface{}) error {
+		stored = append(stored, docs...)
+		return nil
+	}
+	putAuditEntry := stateaudit.PutAuditEntryFn("audit.log", insertDocs)
+	for _, e := range []audit.AuditEntry{
+		entry(2*time.Minute, "Application:v4 - Deploy"),
+		entry(0, "Client:v1 - FullStatus"),
+		entry(time.Minute+500*time.Millisecond, "ModelConfig:v1 - ModelSet"),
+	} {
+		err := putAuditEntry(e)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	since := base.Add(time.Second)
+	findDocs := func(collectionName string, query bson.D, sort []string, limit int, result interface{}) error {
+		c.Check(collectionName, gc.Equals, "audit.log")
+		c.Check(query, jc.DeepEquals, bson.D{
+			{"model-uuid", modelUUID},
+			{"timestamp", bson.D{{"$gte", since.Add(-14 * time.Hour).Format("2006-01-02T15:04:05")}}},
+			{"operation", bson.RegEx{Pattern: "^(Application|ModelConfig):"}},
+		})
+		c.Check(sort, jc.DeepEquals, []string{"-timestamp"})
+		c.Check(limit, gc.Equals, 10)
+		data, err := bson.Marshal(bson.M{"docs": stored})
+		c.Assert(err, jc.ErrorIsNil)
+		var wrapper struct {
+			Docs bson.Raw `bson:"docs"`
+		}
+		err = bson.Unmarshal(data, &wrapper)
+		c.Assert(err, jc.ErrorIsNil)
+		return wrapper.Docs.Unmarshal(result)
+	}
+	getAuditEntries := stateaudit.GetAuditEntriesFn("audit.log", findDocs)
+	entries, err := getAuditEntries(modelUUID, since, "^(Application|ModelConfig):", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, jc.DeepEquals, []audit.AuditEntry{
+		entry(time.Minute+500*time.Millisecond, "ModelConfig:v1 - ModelSet"),
+		entry(2*time.Minute, "Application:v4 - Deploy"),
+	})
+}
+
+func (*AuditSuite) TestGetAuditEntries_PropagatesReadError(c *gc.C) {
+	findDocs := func(string, bson.D, []string, int, interface{}) error {
+		return errors.New("my error")
+	}
+	getAuditEntries := stateaudit.GetAuditEntriesFn("audit.log", findDocs)
+	_, err := getAuditEntries(utils.MustNewUUID().String(), time.Time{}, "", 0)
+	c.Check(err, gc.ErrorMatches, "my error")
+}
@@ -27,7 +27,7 @@ import (
 // to use.
 // Clients do not need to be cleaned up themselves, but they will not function
 // past the lifetime of their configured Mongo.
-func NewClient(config ClientConfig) (lease.Client, error) {
+func NewClient(config ClientConfig) (lease.Store, error) {
 	if err := config.validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -46,7 +46,7 @@ func NewClient(config ClientConfig) (lease.Client, error) {
 	return client, nil
 }
 
-// client implements the lease.Client interface.
+// client implements the lease.Store interface.
 type client struct {
 
 	// config holds resources and configuration necessary to store leases.
@@ -62,7 +62,7 @@ type client struct {
 	skews map[string]Skew
 }
 
-// Leases is part of the lease.Client interface.
+// Leases is part of the lease.Store interface.
 func (client *client) Leases() map[string]lease.Info {
 	leases := make(map[string]lease.Info)
 	for name, entry := range client.entries {
@@ -76,12 +76,12 @@ func (client *client) Leases() map[string]lease.Info {
 	return leases
 }
 
-// ClaimLease is part of the lease.Client interface.
+// ClaimLease is part of the lease.Store interface.
 func (client *client) ClaimLease(name string, request lease.Request) error {
 	return client.request(name, request, client.claimLeaseOps, "claiming")
 }
 
-// ExtendLease is part of the lease.Client interface.
+// ExtendLease is part of the lease.Store interface.
 func (client *client) ExtendLease(name string, request lease.Request) error {
 	return client.request(name, request, client.extendLeaseOps, "extending")
 }
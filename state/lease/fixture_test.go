@@ -47,7 +47,7 @@ type FixtureParams struct {
 
 // Fixture collects together a running client and a bunch of useful data.
 type Fixture struct {
-	Client corelease.Client
+	Client corelease.Store
 	Config lease.ClientConfig
 	Runner jujutxn.Runner
 	Clock  *Clock
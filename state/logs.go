@@ -9,6 +9,7 @@ package state
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -314,6 +315,7 @@ type LogRecord struct {
 type LogTailerParams struct {
 	StartID       int64
 	StartTime     time.Time
+	EndTime       time.Time
 	MinLevel      loggo.Level
 	InitialLines  int
 	NoTail        bool
@@ -321,7 +323,13 @@ type LogTailerParams struct {
 	ExcludeEntity []string
 	IncludeModule []string
 	ExcludeModule []string
-	Oplog         *mgo.Collection // For testing only
+
+	// ModelUUIDs, if set, lists the models whose logs are tailed in
+	// place of those of the tailer's own model. Only a controller
+	// model's tailer may tail the logs of other models.
+	ModelUUIDs []string
+
+	Oplog *mgo.Collection // For testing only
 }
 
 // oplogOverlap is used to decide on the initial oplog timestamp to
@@ -357,11 +365,21 @@ type LogTailerState interface {
 // NewLogTailer returns a LogTailer which filters according to the
 // parameters given.
 func NewLogTailer(st LogTailerState, params LogTailerParams) (LogTailer, error) {
+	modelUUIDs := params.ModelUUIDs
+	if len(modelUUIDs) == 0 {
+		modelUUIDs = []string{st.ModelUUID()}
+	} else if !st.IsController() {
+		return nil, errors.New("tailing the logs of other models requires a controller state")
+	}
 	session := st.MongoSession().Copy()
+	logsColls := make([]*mgo.Collection, len(modelUUIDs))
+	for i, modelUUID := range modelUUIDs {
+		logsColls[i] = session.DB(logsDB).C(logCollectionName(modelUUID)).With(session)
+	}
 	t := &logTailer{
-		modelUUID:       st.ModelUUID(),
+		modelUUIDs:      modelUUIDs,
 		session:         session,
-		logsColl:        session.DB(logsDB).C(logCollectionName(st.ModelUUID())).With(session),
+		logsColls:       logsColls,
 		params:          params,
 		logCh:           make(chan *LogRecord),
 		recentIds:       newRecentIdTracker(maxRecentLogIds),
@@ -379,9 +397,9 @@ func NewLogTailer(st LogTailerState, params LogTailerParams) (LogTailer, error)
 
 type logTailer struct {
 	tomb            tomb.Tomb
-	modelUUID       string
+	modelUUIDs      []string
 	session         *mgo.Session
-	logsColl        *mgo.Collection
+	logsColls       []*mgo.Collection
 	params          LogTailerParams
 	logCh           chan *LogRecord
 	lastID          int64
@@ -420,14 +438,20 @@ func (t *logTailer) loop() error {
 		return err
 	}
 
-	if t.params.NoTail {
+	if t.params.NoTail || t.pastEndTime() {
 		return nil
 	}
 
 	return t.tailOplog()
 }
 
-func (t *logTailer) processReversed(query *mgo.Query) error {
+// pastEndTime reports whether the tailer's end time, if any, has
+// passed, in which case no further logs will match.
+func (t *logTailer) pastEndTime() bool {
+	return !t.params.EndTime.IsZero() && time.Now().After(t.params.EndTime)
+}
+
+func (t *logTailer) processReversed(sel bson.D) error {
 	// We must sort by exactly the fields in the index and exactly reversed
 	// so that Mongo will use the index and not try to sort in memory.
 	// Note (jam): 2017-04-19 if this is truly too much memory load we should
@@ -441,32 +465,33 @@ func (t *logTailer) processReversed(query *mgo.Query) error {
 		return errors.Errorf("too many lines requested (%d) maximum is %d",
 			t.params.InitialLines, maxInitialLines)
 	}
-	query.Sort("-t", "-_id")
-	query.Limit(t.params.InitialLines)
-	iter := query.Iter()
-	queue := make([]logDoc, t.params.InitialLines)
-	cur := t.params.InitialLines
-	var doc logDoc
-	for iter.Next(&doc) {
-		select {
-		case <-t.tomb.Dying():
-			return errors.Trace(tomb.ErrDying)
-		default:
+	var queue []modelLogDoc
+	for i, logsColl := range t.logsColls {
+		query := logsColl.Find(sel).Sort("-t", "-_id").Limit(t.params.InitialLines)
+		iter := query.Iter()
+		var doc logDoc
+		for iter.Next(&doc) {
+			select {
+			case <-t.tomb.Dying():
+				iter.Close()
+				return errors.Trace(tomb.ErrDying)
+			default:
+			}
+			queue = append(queue, modelLogDoc{modelUUID: t.modelUUIDs[i], doc: doc})
 		}
-		cur--
-		queue[cur] = doc
-		if cur == 0 {
-			break
+		if err := iter.Close(); err != nil {
+			return errors.Trace(err)
 		}
 	}
-	if err := iter.Close(); err != nil {
-		return errors.Trace(err)
+	// Each model's lines were loaded in reverse order; put them all
+	// in the correct order, and keep only the most recent lines.
+	sort.Sort(modelLogDocsByTime(queue))
+	if len(queue) > t.params.InitialLines {
+		queue = queue[len(queue)-t.params.InitialLines:]
 	}
-	// We loaded the queue in reverse order, truncate it to just the actual
-	// contents, and then return them in the correct order.
-	queue = queue[cur:]
-	for _, doc := range queue {
-		rec, err := logDocToRecord(t.modelUUID, &doc)
+	for _, mdoc := range queue {
+		doc := mdoc.doc
+		rec, err := logDocToRecord(mdoc.modelUUID, &doc)
 		if err != nil {
 			return errors.Annotate(err, "deserialization failed (possible DB corruption)")
 		}
@@ -485,11 +510,9 @@ func (t *logTailer) processReversed(query *mgo.Query) error {
 func (t *logTailer) processCollection() error {
 	// Create a selector from the params.
 	sel := t.paramsToSelector(t.params, "")
-	query := t.logsColl.Find(sel)
 
-	var doc logDoc
 	if t.params.InitialLines > 0 {
-		return t.processReversed(query)
+		return t.processReversed(sel)
 	}
 	// In tests, sorting by time can leave the result ordering
 	// underconstrained. Since object ids are (timestamp, machine id,
@@ -506,9 +529,14 @@ func (t *logTailer) processCollection() error {
 	// but don't write out any additional errors until we either hit
 	// a good value, or end the method.
 	deserialisationFailures := 0
-	iter := query.Sort("t", "_id").Iter()
-	for iter.Next(&doc) {
-		rec, err := logDocToRecord(t.modelUUID, &doc)
+	iter := newMergedLogIter(t.modelUUIDs, t.logsColls, sel)
+	var doc logDoc
+	for {
+		modelUUID, ok := iter.Next(&doc)
+		if !ok {
+			break
+		}
+		rec, err := logDocToRecord(modelUUID, &doc)
 		if err != nil {
 			if deserialisationFailures == 0 {
 				logger.Warningf("log deserialization failed (possible DB corruption), %v", err)
@@ -526,6 +554,7 @@ func (t *logTailer) processCollection() error {
 		}
 		select {
 		case <-t.tomb.Dying():
+			iter.Close()
 			return tomb.ErrDying
 		case t.logCh <- rec:
 			t.lastID = rec.ID
@@ -545,8 +574,15 @@ func (t *logTailer) tailOplog() error {
 
 	newParams := t.params
 	newParams.StartID = t.lastID // (t.lastID + 1) once Id is a sequential int.
+	// Records after the end time are checked for below, so that
+	// tailing stops once they are seen.
+	newParams.EndTime = time.Time{}
+	namespaces := make([]string, len(t.modelUUIDs))
+	for i, modelUUID := range t.modelUUIDs {
+		namespaces[i] = logsNamespace(modelUUID)
+	}
 	oplogSel := append(t.paramsToSelector(newParams, "o."),
-		bson.DocElem{"ns", logsDB + "." + logCollectionName(t.modelUUID)},
+		bson.DocElem{"ns", bson.M{"$in": namespaces}},
 	)
 
 	oplog := t.params.Oplog
@@ -593,7 +629,8 @@ func (t *logTailer) tailOplog() error {
 				}
 				continue
 			}
-			rec, err := logDocToRecord(t.modelUUID, doc)
+			modelUUID := strings.TrimPrefix(oplogDoc.Namespace, logsNamespace(""))
+			rec, err := logDocToRecord(modelUUID, doc)
 			if err != nil {
 				if deserialisationFailures == 0 {
 					logger.Warningf("log deserialization failed (possible DB corruption), %v", err)
@@ -606,6 +643,11 @@ func (t *logTailer) tailOplog() error {
 				}
 				deserialisationFailures = 0
 			}
+			if !t.params.EndTime.IsZero() && rec.Time.After(t.params.EndTime) {
+				// Logs are written in approximately time order, so
+				// once they pass the end time we are done.
+				return nil
+			}
 			select {
 			case <-t.tomb.Dying():
 				return tomb.ErrDying
@@ -617,8 +659,15 @@ func (t *logTailer) tailOplog() error {
 
 func (t *logTailer) paramsToSelector(params LogTailerParams, prefix string) bson.D {
 	sel := bson.D{}
+	timeRange := bson.M{}
 	if !params.StartTime.IsZero() {
-		sel = append(sel, bson.DocElem{"t", bson.M{"$gte": params.StartTime.UnixNano()}})
+		timeRange["$gte"] = params.StartTime.UnixNano()
+	}
+	if !params.EndTime.IsZero() {
+		timeRange["$lte"] = params.EndTime.UnixNano()
+	}
+	if len(timeRange) > 0 {
+		sel = append(sel, bson.DocElem{"t", timeRange})
 	}
 	if params.MinLevel > loggo.UNSPECIFIED {
 		sel = append(sel, bson.DocElem{"v", bson.M{"$gte": int(params.MinLevel)}})
@@ -647,6 +696,96 @@ func (t *logTailer) paramsToSelector(params LogTailerParams, prefix string) bson
 	return sel
 }
 
+// logsNamespace returns the namespace of the given model's logs
+// collection, as recorded in the oplog.
+func logsNamespace(modelUUID string) string {
+	return logsDB + "." + logCollectionName(modelUUID)
+}
+
+// modelLogDoc is a log document read from a model's logs collection.
+type modelLogDoc struct {
+	modelUUID string
+	doc       logDoc
+}
+
+// modelLogDocsByTime sorts log documents in the order in which they
+// are reported by a LogTailer.
+type modelLogDocsByTime []modelLogDoc
+
+func (d modelLogDocsByTime) Len() int      { return len(d) }
+func (d modelLogDocsByTime) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d modelLogDocsByTime) Less(i, j int) bool {
+	return logDocBefore(&d[i].doc, &d[j].doc)
+}
+
+// logDocBefore reports whether a is reported before b.
+func logDocBefore(a, b *logDoc) bool {
+	if a.Time != b.Time {
+		return a.Time < b.Time
+	}
+	return a.Id < b.Id
+}
+
+// mergedLogIter iterates over the log documents of several models'
+// logs collections, in the order in which they are reported by a
+// LogTailer.
+type mergedLogIter struct {
+	modelUUIDs []string
+	iters      []*mgo.Iter
+	heads      []*logDoc
+}
+
+func newMergedLogIter(modelUUIDs []string, logsColls []*mgo.Collection, sel bson.D) *mergedLogIter {
+	it := &mergedLogIter{
+		modelUUIDs: modelUUIDs,
+		iters:      make([]*mgo.Iter, len(logsColls)),
+		heads:      make([]*logDoc, len(logsColls)),
+	}
+	for i, logsColl := range logsColls {
+		it.iters[i] = logsColl.Find(sel).Sort("t", "_id").Iter()
+		it.advance(i)
+	}
+	return it
+}
+
+// Next sets doc to the next log document, and returns the UUID of its
+// model. It returns false when there are no more documents.
+func (it *mergedLogIter) Next(doc *logDoc) (string, bool) {
+	next := -1
+	for i, head := range it.heads {
+		if head != nil && (next == -1 || logDocBefore(head, it.heads[next])) {
+			next = i
+		}
+	}
+	if next == -1 {
+		return "", false
+	}
+	*doc = *it.heads[next]
+	it.advance(next)
+	return it.modelUUIDs[next], true
+}
+
+func (it *mergedLogIter) advance(i int) {
+	var doc logDoc
+	if it.iters[i].Next(&doc) {
+		it.heads[i] = &doc
+	} else {
+		it.heads[i] = nil
+	}
+}
+
+// Close closes the underlying iterators, returning the first error
+// encountered by any of them.
+func (it *mergedLogIter) Close() error {
+	var result error
+	for _, iter := range it.iters {
+		if err := iter.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
 func makeEntityPattern(entities []string) string {
 	var patterns []string
 	for _, entity := range entities {
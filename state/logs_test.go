@@ -386,6 +386,43 @@ func (s *LogTailerSuite) TestTimeFiltering(c *gc.C) {
 
 }
 
+func (s *LogTailerSuite) TestTimeRangeFiltering(c *gc.C) {
+	startT := coretesting.NonZeroTime()
+	endT := startT.Add(5 * time.Second)
+	s.writeLogsT(c,
+		s.otherUUID,
+		startT.Add(-5*time.Second), startT.Add(-time.Millisecond), 5,
+		logTemplate{Message: "too early"},
+	)
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, s.otherUUID, startT, endT, 5, want)
+	s.writeLogsT(c,
+		s.otherUUID,
+		endT.Add(time.Millisecond), endT.Add(5*time.Second), 5,
+		logTemplate{Message: "too late"},
+	)
+
+	tailer, err := state.NewLogTailer(s.otherState, state.LogTailerParams{
+		StartTime: startT,
+		EndTime:   endT,
+		Oplog:     s.oplogColl,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 5, want)
+
+	// The end time has passed, so the tailer stops once the
+	// logs collection has been read.
+	select {
+	case _, ok := <-tailer.Logs():
+		if ok {
+			c.Fatal("shouldn't be any further logs")
+		}
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for logs channel to close")
+	}
+}
+
 func (s *LogTailerSuite) TestOplogTransition(c *gc.C) {
 	// Ensure that logs aren't repeated as the log tailer moves from
 	// reading from the logs collection to tailing the oplog.
@@ -518,6 +555,68 @@ func (s *LogTailerSuite) TestTailingLogsOnlyForOneModel(c *gc.C) {
 	s.checkLogTailerFiltering(c, s.State, state.LogTailerParams{}, writeLogs, assert)
 }
 
+func (s *LogTailerSuite) TestTailingLogsForSeveralModels(c *gc.C) {
+	t0 := coretesting.ZeroTime()
+	writeLogs := func() {
+		s.writeLogsT(c, s.otherUUID, t0, t0, 1, logTemplate{Message: "first"})
+		s.writeLogsT(c, s.modelUUID, t0.Add(time.Second), t0.Add(time.Second), 1, logTemplate{Message: "second"})
+		s.writeLogsT(c, s.otherUUID, t0.Add(2*time.Second), t0.Add(2*time.Second), 1, logTemplate{Message: "third"})
+	}
+	assert := func(tailer state.LogTailer) {
+		for _, expected := range []struct {
+			modelUUID string
+			message   string
+		}{
+			{s.otherUUID, "first"},
+			{s.modelUUID, "second"},
+			{s.otherUUID, "third"},
+		} {
+			select {
+			case log, ok := <-tailer.Logs():
+				c.Assert(ok, jc.IsTrue)
+				c.Check(log.ModelUUID, gc.Equals, expected.modelUUID)
+				c.Check(log.Message, gc.Equals, expected.message)
+			case <-time.After(coretesting.LongWait):
+				c.Fatalf("timed out waiting for %q", expected.message)
+			}
+		}
+	}
+	params := state.LogTailerParams{
+		ModelUUIDs: []string{s.modelUUID, s.otherUUID},
+	}
+	s.checkLogTailerFiltering(c, s.State, params, writeLogs, assert)
+}
+
+func (s *LogTailerSuite) TestInitialLinesForSeveralModels(c *gc.C) {
+	t0 := coretesting.ZeroTime()
+	for i := 0; i < 6; i++ {
+		t := t0.Add(time.Duration(i) * time.Second)
+		modelUUID := s.modelUUID
+		if i%2 == 0 {
+			modelUUID = s.otherUUID
+		}
+		s.writeLogsT(c, modelUUID, t, t, 1, logTemplate{Message: strconv.Itoa(i)})
+	}
+
+	tailer, err := state.NewLogTailer(s.State, state.LogTailerParams{
+		InitialLines: 3,
+		ModelUUIDs:   []string{s.modelUUID, s.otherUUID},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+
+	s.assertTailer(c, tailer, 1, logTemplate{Message: "3"})
+	s.assertTailer(c, tailer, 1, logTemplate{Message: "4"})
+	s.assertTailer(c, tailer, 1, logTemplate{Message: "5"})
+}
+
+func (s *LogTailerSuite) TestTailingOtherModelsRequiresController(c *gc.C) {
+	_, err := state.NewLogTailer(s.otherState, state.LogTailerParams{
+		ModelUUIDs: []string{s.modelUUID},
+	})
+	c.Assert(err, gc.ErrorMatches, "tailing the logs of other models requires a controller state")
+}
+
 func (s *LogTailerSuite) TestLevelFiltering(c *gc.C) {
 	info := logTemplate{Level: loggo.INFO}
 	error := logTemplate{Level: loggo.ERROR}
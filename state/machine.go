@@ -838,8 +838,10 @@ func (m *Machine) removeOps() ([]txn.Op, error) {
 		annotationRemoveOp(m.st, m.globalKey()),
 		removeRebootDocOp(m.st, m.globalKey()),
 		removeMachineBlockDevicesOp(m.Id()),
+		removeMachineFilesystemUsageOp(m.Id()),
 		removeModelMachineRefOp(m.st, m.Id()),
 		removeSSHHostKeyOp(m.globalKey()),
+		removeAgentLoggingOverrideOp(m.Tag()),
 	}
 	linkLayerDevicesOps, err := m.removeAllLinkLayerDevicesOps()
 	if err != nil {
@@ -1786,6 +1788,25 @@ func (m *Machine) VolumeAttachments() ([]VolumeAttachment, error) {
 
 // AddAction is part of the ActionReceiver interface.
 func (m *Machine) AddAction(name string, payload map[string]interface{}) (Action, error) {
+	payloadWithDefaults, err := m.actionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	return m.st.EnqueueAction(m.Tag(), name, payloadWithDefaults)
+}
+
+// AddTask is part of the ActionReceiver interface.
+func (m *Machine) AddTask(operationID, name string, payload map[string]interface{}) (Action, error) {
+	payloadWithDefaults, err := m.actionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	return m.st.EnqueueTask(operationID, m.Tag(), name, payloadWithDefaults)
+}
+
+// actionPayload validates the payload of the named action, which must
+// be predefined, and returns it with the action's defaults inserted.
+func (m *Machine) actionPayload(name string, payload map[string]interface{}) (map[string]interface{}, error) {
 	spec, ok := actions.PredefinedActionsSpec[name]
 	if !ok {
 		return nil, errors.Errorf("cannot add action %q to a machine; only predefined actions allowed", name)
@@ -1796,11 +1817,7 @@ func (m *Machine) AddAction(name string, payload map[string]interface{}) (Action
 	if err != nil {
 		return nil, err
 	}
-	payloadWithDefaults, err := spec.InsertDefaults(payload)
-	if err != nil {
-		return nil, err
-	}
-	return m.st.EnqueueAction(m.Tag(), name, payloadWithDefaults)
+	return spec.InsertDefaults(payload)
 }
 
 // CancelAction is part of the ActionReceiver interface.
@@ -16,6 +16,7 @@ import (
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2/bson"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/payload"
 	"github.com/juju/juju/resource"
@@ -1457,9 +1458,28 @@ func (e *exporter) constraintsArgs(globalKey string) (description.ConstraintsArg
 		Tags:         optionalStringSlice("tags"),
 		VirtType:     optionalString("virttype"),
 	}
+	// The model description cannot represent an image id, devices to
+	// pass through, a root disk source or a resource pool, so rather
+	// than lose them in migration, the model is not exported.
+	var unsupported string
+	switch {
+	case optionalString("imageid") != "":
+		unsupported = constraints.ImageId
+	case optionalInt("gpus") != 0:
+		unsupported = constraints.Gpu
+	case len(optionalStringSlice("usbdevices")) != 0:
+		unsupported = constraints.UsbDevice
+	case optionalString("rootdisksource") != "":
+		unsupported = constraints.RootDiskSource
+	case optionalString("resourcepool") != "":
+		unsupported = constraints.ResourcePool
+	}
 	if optionalErr != nil {
 		return description.ConstraintsArgs{}, errors.Trace(optionalErr)
 	}
+	if unsupported != "" {
+		return description.ConstraintsArgs{}, errors.NotSupportedf("migrating the %q constraint", unsupported)
+	}
 	return result, nil
 }
 
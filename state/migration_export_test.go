@@ -280,6 +280,38 @@ func (s *MigrationExportSuite) TestMachinesWithVirtConstraint(c *gc.C) {
 	s.assertMachinesMigrated(c, constraints.MustParse("arch=amd64 mem=8G virt-type=kvm"))
 }
 
+func (s *MigrationExportSuite) TestMachineImageIdConstraintNotExported(c *gc.C) {
+	s.Factory.MakeMachine(c, &factory.MachineParams{
+		Constraints: constraints.MustParse("image-id=ubuntu-bf2"),
+	})
+	_, err := s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `.*migrating the "image-id" constraint not supported`)
+}
+
+func (s *MigrationExportSuite) TestMachineGpuConstraintNotExported(c *gc.C) {
+	s.Factory.MakeMachine(c, &factory.MachineParams{
+		Constraints: constraints.MustParse("gpu=1"),
+	})
+	_, err := s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `.*migrating the "gpu" constraint not supported`)
+}
+
+func (s *MigrationExportSuite) TestMachineRootDiskSourceConstraintNotExported(c *gc.C) {
+	s.Factory.MakeMachine(c, &factory.MachineParams{
+		Constraints: constraints.MustParse("root-disk-source=datastore1"),
+	})
+	_, err := s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `.*migrating the "root-disk-source" constraint not supported`)
+}
+
+func (s *MigrationExportSuite) TestMachineUsbDeviceConstraintNotExported(c *gc.C) {
+	s.Factory.MakeMachine(c, &factory.MachineParams{
+		Constraints: constraints.MustParse("usb-device=10c4:ea60"),
+	})
+	_, err := s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `.*migrating the "usb-device" constraint not supported`)
+}
+
 func (s *MigrationExportSuite) assertMachinesMigrated(c *gc.C, cons constraints.Value) {
 	// Add a machine with an LXC container.
 	machine1 := s.Factory.MakeMachine(c, &factory.MachineParams{
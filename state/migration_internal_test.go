@@ -95,6 +95,13 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		guimetadataC,
 		// This is controller global, not migrated.
 		guisettingsC,
+		// Debug-log presets are shared across the controller.
+		debugLogPresetsC,
+		// Firewall rules are controller global, not migrated.
+		firewallRulesC,
+		// Filesystem usage is reported periodically by machine
+		// agents, and will be reported again after migration.
+		filesystemUsageC,
 		// Users aren't migrated.
 		usersC,
 		userLastLoginC,
@@ -113,6 +120,8 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// upgradeInfoC is used to coordinate upgrades and schema migrations,
 		// and aren't needed for model migrations.
 		upgradeInfoC,
+		// Completed upgrade steps belong to the controller's agents.
+		upgradeStepsC,
 		// Not exported, but the tools will possibly need to be either bundled
 		// with the representation or sent separately.
 		toolsmetadataC,
@@ -179,6 +188,10 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Metrics manager maintains controller specific state relating to
 		// the store and forward of charm metrics. Nothing to migrate here.
 		metricsManagerC,
+
+		// Agent logging overrides are a temporary debugging aid, and
+		// are not migrated.
+		agentLoggingC,
 	)
 
 	// THIS SET WILL BE REMOVED WHEN MIGRATIONS ARE COMPLETE
@@ -191,6 +204,19 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		remoteEntitiesC,
 		externalControllersC,
 		relationIngressC,
+
+		// Unit secrets are not part of the model description;
+		// models with unit secrets fail the migration prechecks.
+		unitSecretsC,
+
+		// Nor are branches of the model; models with branches in
+		// progress fail the migration prechecks, and completed
+		// branches are already reflected in the model's config.
+		generationsC,
+
+		// Nor are the operations that group actions; models with
+		// operations fail the migration prechecks.
+		operationsC,
 	)
 
 	envCollections := set.NewStrings()
@@ -353,6 +379,14 @@ func (s *MigrationSuite) TestApplicationDocFields(c *gc.C) {
 		// RelationCount is handled by the number of times the application name
 		// appears in relation endpoints.
 		"RelationCount",
+		// Trusted is not yet supported by the model description, so
+		// applications arrive untrusted and must be trusted again by
+		// a model admin.
+		"Trusted",
+		// CompletedHooks is not yet supported by the model
+		// description, so application hooks run again after
+		// migration.
+		"CompletedHooks",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -382,6 +416,9 @@ func (s *MigrationSuite) TestUnitDocFields(c *gc.C) {
 		"Series",
 		"CharmURL",
 		"TxnRevno",
+		// Suspended is an operational pause and is not carried across
+		// a migration; units arrive in the target model resumed.
+		"Suspended",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -529,6 +566,14 @@ func (s *MigrationSuite) TestConstraintsDocFields(c *gc.C) {
 		"Tags",
 		"Spaces",
 		"VirtType",
+		// ImageId, Gpus, UsbDevices, RootDiskSource and
+		// ResourcePool cannot be represented in the model
+		// description, so models that use them are not exported.
+		"ImageId",
+		"Gpus",
+		"UsbDevices",
+		"RootDiskSource",
+		"ResourcePool",
 	)
 	s.AssertExportedFields(c, constraintsDoc{}, fields)
 }
@@ -661,6 +706,15 @@ func (s *MigrationSuite) TestSSHHostKeyDocFields(c *gc.C) {
 func (s *MigrationSuite) TestActionDocFields(c *gc.C) {
 	ignored := set.NewStrings(
 		"ModelUUID",
+		// Progress is only reported while an action is running,
+		// and is not meaningful in the target controller.
+		"ProgressMessage",
+		"ProgressPercent",
+		// Logged messages are not yet part of the model description.
+		"Logs",
+		// Operations are not part of the model description;
+		// models with operations fail the migration prechecks.
+		"Operation",
 	)
 	migrated := set.NewStrings(
 		"DocId",
@@ -7,6 +7,7 @@ import (
 	"container/list"
 	stderrors "errors"
 	"reflect"
+	"sync/atomic"
 
 	"github.com/juju/errors"
 	"gopkg.in/juju/worker.v1"
@@ -27,6 +28,10 @@ type Multiwatcher struct {
 	// goroutine.
 	revno   int64
 	stopped bool
+
+	// err holds the reason the watcher was stopped by the
+	// storeManager, if it was not stopped by its owner.
+	err error
 }
 
 // NewMultiwatcher creates a new watcher that can observe
@@ -59,6 +64,28 @@ func (w *Multiwatcher) Stop() error {
 
 var ErrStopped = stderrors.New("watcher was stopped")
 
+// ErrResyncRequired is returned by Multiwatcher.Next when the watcher
+// fell so far behind the changes in the store that it was dropped.
+// The client should discard its view of the model and start a new
+// watcher, which will begin with the complete current state.
+var ErrResyncRequired = stderrors.New("watcher fell behind and must be restarted")
+
+// defaultMaxPinnedRemovals is the default number of removed entities
+// a single Multiwatcher may keep alive in the store, because it has
+// yet to be told of their removal, before it is dropped.
+const defaultMaxPinnedRemovals = 10000
+
+// multiwatcherResyncs counts the watchers dropped because they fell
+// too far behind, across all store managers.
+var multiwatcherResyncs int64
+
+// MultiwatcherResyncCount returns the number of Multiwatchers that have
+// been dropped, requiring their clients to resync, since the process
+// started.
+func MultiwatcherResyncCount() int64 {
+	return atomic.LoadInt64(&multiwatcherResyncs)
+}
+
 // Next retrieves all changes that have happened since the last
 // time it was called, blocking until there are some changes available.
 //
@@ -93,6 +120,9 @@ func (w *Multiwatcher) Next() ([]multiwatcher.Delta, error) {
 		return nil, errors.Errorf("shared state watcher was stopped")
 	case ok := <-req.reply:
 		if !ok {
+			if req.err != nil {
+				return nil, errors.Trace(req.err)
+			}
 			return nil, errors.Trace(ErrStopped)
 		}
 	case <-req.noChanges:
@@ -119,6 +149,14 @@ type storeManager struct {
 	// Each entry in the waiting map holds a linked list of Next requests
 	// outstanding for the associated Multiwatcher.
 	waiting map[*Multiwatcher]*request
+
+	// active holds the Multiwatchers that have been sent changes, and
+	// so hold references to entities in the store.
+	active map[*Multiwatcher]bool
+
+	// maxPinnedRemovals holds the number of removed entities a single
+	// Multiwatcher may keep alive before it is dropped.
+	maxPinnedRemovals int
 }
 
 // Backing is the interface required by the storeManager to access the
@@ -167,6 +205,10 @@ type request struct {
 	// the last replied-to Next request.
 	changes []multiwatcher.Delta
 
+	// err holds the reason the watcher was stopped, if a false
+	// reply is sent because the storeManager dropped the watcher.
+	err error
+
 	// next points to the next request in the list of outstanding
 	// requests on a given watcher.  It is used only by the central
 	// storeManager goroutine.
@@ -177,10 +219,12 @@ type request struct {
 // but does not start its run loop.
 func newStoreManagerNoRun(backing Backing) *storeManager {
 	return &storeManager{
-		backing: backing,
-		request: make(chan *request),
-		all:     newStore(),
-		waiting: make(map[*Multiwatcher]*request),
+		backing:           backing,
+		request:           make(chan *request),
+		all:               newStore(),
+		waiting:           make(map[*Multiwatcher]*request),
+		active:            make(map[*Multiwatcher]bool),
+		maxPinnedRemovals: defaultMaxPinnedRemovals,
 	}
 }
 
@@ -239,6 +283,7 @@ func (sm *storeManager) loop() error {
 			if err := sm.backing.Changed(sm.all, change); err != nil {
 				return errors.Trace(err)
 			}
+			sm.dropLaggingWatchers()
 		case req := <-sm.request:
 			sm.handle(req)
 		}
@@ -266,6 +311,7 @@ func (sm *storeManager) handle(req *request) {
 	if req.w.stopped {
 		// The watcher has previously been stopped.
 		if req.reply != nil {
+			req.err = req.w.err
 			select {
 			case req.reply <- false:
 			case <-sm.tomb.Dying():
@@ -284,6 +330,7 @@ func (sm *storeManager) handle(req *request) {
 		delete(sm.waiting, req.w)
 		req.w.stopped = true
 		sm.leave(req.w)
+		delete(sm.active, req.w)
 		return
 	}
 	// Add request to head of list.
@@ -309,7 +356,59 @@ func (sm *storeManager) respond() {
 		req.reply <- true
 		sm.removeWaitingReq(w, req)
 		sm.seen(revno)
+		sm.active[w] = true
+	}
+}
+
+// dropLaggingWatchers stops any Multiwatcher that is keeping too many
+// removed entities alive in the store because its client is not
+// calling Next often enough. Such watchers return ErrResyncRequired
+// from their next call to Next.
+func (sm *storeManager) dropLaggingWatchers() {
+	for w := range sm.active {
+		if _, ok := sm.waiting[w]; ok {
+			// The watcher is waiting for changes, so it
+			// will be up to date as soon as we respond.
+			continue
+		}
+		if sm.all.latestRevno-w.revno <= int64(sm.maxPinnedRemovals) {
+			// Each removal increments the revno, so the watcher
+			// cannot be holding more removals than this.
+			continue
+		}
+		if sm.pinnedRemovals(w) <= sm.maxPinnedRemovals {
+			continue
+		}
+		logger.Warningf(
+			"dropping multiwatcher: more than %d removals not yet delivered; client must resync",
+			sm.maxPinnedRemovals,
+		)
+		w.stopped = true
+		w.err = ErrResyncRequired
+		sm.leave(w)
+		delete(sm.active, w)
+		atomic.AddInt64(&multiwatcherResyncs, 1)
+	}
+}
+
+// pinnedRemovals returns the number of removed entities that are
+// kept in the store only until the given watcher is told of their
+// removal. It stops counting once the limit has been exceeded.
+func (sm *storeManager) pinnedRemovals(w *Multiwatcher) int {
+	count := 0
+	for e := sm.all.list.Front(); e != nil; e = e.Next() {
+		entry := e.Value.(*entityEntry)
+		if entry.revno <= w.revno {
+			break
+		}
+		if entry.removed && entry.creationRevno <= w.revno {
+			count++
+			if count > sm.maxPinnedRemovals {
+				break
+			}
+		}
 	}
+	return count
 }
 
 func (sm *storeManager) removeWaitingReq(w *Multiwatcher, req *request) {
@@ -486,6 +486,59 @@ var (
 	respondTestFinalRevno = int64(len(respondTestChanges))
 )
 
+func (s *storeManagerSuite) TestDropLaggingWatchers(c *gc.C) {
+	sm := newStoreManagerNoRun(newTestBacking(nil))
+	sm.maxPinnedRemovals = 1
+	m0 := &multiwatcher.MachineInfo{Id: "0"}
+	m1 := &multiwatcher.MachineInfo{Id: "1"}
+	sm.all.Update(m0)
+	sm.all.Update(m1)
+
+	// Both watchers see the initial state.
+	slow := &Multiwatcher{all: sm}
+	fast := &Multiwatcher{all: sm}
+	for _, w := range []*Multiwatcher{slow, fast} {
+		req := &request{w: w, reply: make(chan bool, 1)}
+		sm.handle(req)
+		sm.respond()
+		assertReplied(c, true, req)
+	}
+
+	// The fast watcher is always waiting for changes, so it is
+	// never dropped; the slow one is dropped once it holds on to
+	// more removals than allowed.
+	waitFast := func() *request {
+		req := &request{w: fast, reply: make(chan bool, 1)}
+		sm.handle(req)
+		return req
+	}
+	req := waitFast()
+	sm.all.Remove(m0.EntityId())
+	sm.dropLaggingWatchers()
+	sm.respond()
+	assertReplied(c, true, req)
+	c.Assert(slow.stopped, jc.IsFalse)
+	c.Assert(sm.all.list.Len(), gc.Equals, 2)
+
+	req = waitFast()
+	sm.all.Remove(m1.EntityId())
+	sm.dropLaggingWatchers()
+	sm.respond()
+	assertReplied(c, true, req)
+	c.Assert(slow.stopped, jc.IsTrue)
+	c.Assert(fast.stopped, jc.IsFalse)
+	c.Assert(sm.active, jc.DeepEquals, map[*Multiwatcher]bool{fast: true})
+
+	// The removed entries are no longer kept for the slow watcher.
+	c.Assert(sm.all.list.Len(), gc.Equals, 0)
+
+	// The slow watcher's client is told to resync.
+	req = &request{w: slow, reply: make(chan bool, 1)}
+	sm.handle(req)
+	assertReplied(c, false, req)
+	c.Assert(req.err, gc.Equals, ErrResyncRequired)
+}
+
 func (s *storeManagerSuite) TestRespondResults(c *gc.C) {
 	// We test the response results for a pair of watchers by
 	// interleaving notional Next requests in all possible
@@ -0,0 +1,305 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// operationDoc records an operation: a group of actions, its tasks,
+// that were enqueued together, such as by a single juju run.
+type operationDoc struct {
+	DocId     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+
+	// Summary describes the operation.
+	Summary string `bson:"summary"`
+
+	// Enqueued is the time the operation was added.
+	Enqueued time.Time `bson:"enqueued"`
+}
+
+// Operation is a group of actions, its tasks, that were enqueued
+// together. Operations have sequential ids, which, unlike the ids of
+// their tasks, are easy to refer to.
+type Operation struct {
+	st  *State
+	doc operationDoc
+}
+
+// Id returns the operation's id.
+func (op *Operation) Id() string {
+	return op.st.localID(op.doc.DocId)
+}
+
+// Summary returns the description of the operation.
+func (op *Operation) Summary() string {
+	return op.doc.Summary
+}
+
+// Enqueued returns the time the operation was added.
+func (op *Operation) Enqueued() time.Time {
+	return op.doc.Enqueued
+}
+
+// Tasks returns the operation's tasks.
+func (op *Operation) Tasks() ([]Action, error) {
+	return op.st.findActions(bson.D{{"operation", op.Id()}})
+}
+
+// Cancel cancels those of the operation's tasks that are pending, and
+// returns them. Tasks that are already running are left to complete.
+func (op *Operation) Cancel() ([]Action, error) {
+	pending, err := op.st.findActions(bson.D{
+		{"operation", op.Id()},
+		{"status", ActionPending},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cancelled := make([]Action, 0, len(pending))
+	for _, task := range pending {
+		result, err := task.Finish(ActionResults{
+			Status:  ActionCancelled,
+			Message: "operation cancelled",
+		})
+		if err == txn.ErrAborted {
+			// The task has completed, or been cancelled, since
+			// it was read.
+			continue
+		} else if err != nil {
+			return cancelled, errors.Annotatef(err, "cannot cancel task %q of operation %q", task.Id(), op.Id())
+		}
+		cancelled = append(cancelled, result)
+	}
+	return cancelled, nil
+}
+
+// OperationStatus returns the status of an operation with the given
+// tasks. An operation is pending until any of its tasks starts, and is
+// running until all of them have finished; it then has failed if any
+// of them failed, has been cancelled if all of them were, and has
+// completed otherwise.
+func OperationStatus(tasks []Action) ActionStatus {
+	if len(tasks) == 0 {
+		return ActionPending
+	}
+	var pending, running, failed, cancelled int
+	for _, task := range tasks {
+		switch task.Status() {
+		case ActionPending:
+			pending++
+		case ActionRunning:
+			running++
+		case ActionFailed:
+			failed++
+		case ActionCancelled:
+			cancelled++
+		}
+	}
+	switch {
+	case pending == len(tasks):
+		return ActionPending
+	case pending > 0 || running > 0:
+		return ActionRunning
+	case failed > 0:
+		return ActionFailed
+	case cancelled == len(tasks):
+		return ActionCancelled
+	}
+	return ActionCompleted
+}
+
+// EnqueueOperation adds an operation, with the given summary, to which
+// tasks may be added with EnqueueTask, and returns its id.
+func (st *State) EnqueueOperation(summary string) (string, error) {
+	seq, err := sequence(st, "operation")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	id := strconv.Itoa(seq)
+	doc := operationDoc{
+		DocId:     st.docID(id),
+		ModelUUID: st.ModelUUID(),
+		Summary:   summary,
+		Enqueued:  st.nowToTheSecond(),
+	}
+	err = st.db().RunTransaction([]txn.Op{{
+		C:      operationsC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}})
+	if err != nil {
+		return "", errors.Annotate(err, "cannot add operation")
+	}
+	return id, nil
+}
+
+// Operation returns the operation with the given id.
+func (st *State) Operation(id string) (*Operation, error) {
+	operations, closer := st.db().GetCollection(operationsC)
+	defer closer()
+
+	var doc operationDoc
+	err := operations.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("operation %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get operation %q", id)
+	}
+	return &Operation{st: st, doc: doc}, nil
+}
+
+// HasOperations returns whether the model has any operations.
+func (st *State) HasOperations() (bool, error) {
+	operations, closer := st.db().GetCollection(operationsC)
+	defer closer()
+	count, err := operations.Count()
+	if err != nil {
+		return false, errors.Annotate(err, "cannot count operations")
+	}
+	return count > 0, nil
+}
+
+// emptyOperationAge is how long an operation may have no tasks before
+// it is pruned; tasks are added just after their operation is.
+const emptyOperationAge = time.Minute
+
+// pruneOperations removes the operations that no longer have any tasks,
+// their tasks having been pruned.
+func pruneOperations(st *State) error {
+	operations, closer := st.db().GetCollection(operationsC)
+	defer closer()
+	actions, closer := st.db().GetCollection(actionsC)
+	defer closer()
+
+	var docs []operationDoc
+	enqueuedBefore := st.clock().Now().Add(-emptyOperationAge)
+	err := operations.Find(bson.D{{"enqueued", bson.D{{"$lt", enqueuedBefore}}}}).Select(bson.D{{"_id", 1}}).All(&docs)
+	if err != nil {
+		return errors.Annotate(err, "cannot get operations")
+	}
+	var ops []txn.Op
+	for _, doc := range docs {
+		count, err := actions.Find(bson.D{{"operation", st.localID(doc.DocId)}}).Count()
+		if err != nil {
+			return errors.Annotate(err, "cannot count tasks")
+		}
+		if count > 0 {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      operationsC,
+			Id:     doc.DocId,
+			Remove: true,
+		})
+	}
+	for len(ops) > 0 {
+		batch := ops
+		if len(batch) > historyPruneBatchSize {
+			batch = batch[:historyPruneBatchSize]
+		}
+		if err := st.db().RunTransaction(batch); err != nil {
+			return errors.Annotate(err, "cannot remove operations")
+		}
+		ops = ops[len(batch):]
+	}
+	return nil
+}
+
+// OperationQuery selects tasks of operations. A task matches if it
+// matches each of the non-empty fields of the query.
+type OperationQuery struct {
+	// Receivers holds the names of the units and machines whose tasks
+	// are selected.
+	Receivers []string
+
+	// ActionNames holds the names of the actions selected.
+	ActionNames []string
+
+	// Status holds the statuses of the tasks selected.
+	Status []ActionStatus
+}
+
+// OperationInfo holds an operation and those of its tasks selected by
+// an OperationQuery.
+type OperationInfo struct {
+	Operation *Operation
+	Tasks     []Action
+}
+
+// ListOperations returns the operations with tasks that match the
+// query, with the matching tasks, ordered by operation id.
+func (st *State) ListOperations(query OperationQuery) ([]OperationInfo, error) {
+	sel := bson.D{{"operation", bson.D{{"$exists", true}, {"$ne", ""}}}}
+	if len(query.Receivers) > 0 {
+		sel = append(sel, bson.D{{"receiver", bson.D{{"$in", query.Receivers}}}}...)
+	}
+	if len(query.ActionNames) > 0 {
+		sel = append(sel, bson.D{{"name", bson.D{{"$in", query.ActionNames}}}}...)
+	}
+	if len(query.Status) > 0 {
+		sel = append(sel, bson.D{{"status", bson.D{{"$in", query.Status}}}}...)
+	}
+	tasks, err := st.findActions(sel)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	byOperation := make(map[string][]Action)
+	for _, task := range tasks {
+		id := task.OperationID()
+		byOperation[id] = append(byOperation[id], task)
+	}
+	ids := make([]string, 0, len(byOperation))
+	for id := range byOperation {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+
+	infos := make([]OperationInfo, 0, len(ids))
+	for _, id := range ids {
+		op, err := st.Operation(id)
+		if errors.IsNotFound(err) {
+			// The operation's record has been pruned.
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		infos = append(infos, OperationInfo{
+			Operation: op,
+			Tasks:     byOperation[id],
+		})
+	}
+	return infos, nil
+}
+
+// findActions returns the actions matching the given selector.
+func (st *State) findActions(sel bson.D) ([]Action, error) {
+	actions, closer := st.db().GetCollection(actionsC)
+	defer closer()
+
+	var docs []actionDoc
+	if err := actions.Find(sel).Sort("enqueued", "_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get actions")
+	}
+	results := make([]Action, len(docs))
+	for i, doc := range docs {
+		results[i] = newAction(st, doc)
+	}
+	return results, nil
+}
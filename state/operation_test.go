@@ -0,0 +1,182 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type OperationSuite struct {
+	ConnSuite
+	unit    *state.Unit
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&OperationSuite{})
+
+func (s *OperationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	ch := s.AddTestingCharm(c, "dummy")
+	app := s.AddTestingApplication(c, "dummy", ch)
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetCharmURL(ch.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	s.unit = unit
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *OperationSuite) addRunOperation(c *gc.C) (string, []state.Action) {
+	id, err := s.State.EnqueueOperation("run hostname")
+	c.Assert(err, jc.ErrorIsNil)
+	payload := map[string]interface{}{"command": "hostname"}
+	unitTask, err := s.unit.AddTask(id, "juju-run", payload)
+	c.Assert(err, jc.ErrorIsNil)
+	machineTask, err := s.machine.AddTask(id, "juju-run", payload)
+	c.Assert(err, jc.ErrorIsNil)
+	return id, []state.Action{unitTask, machineTask}
+}
+
+func (s *OperationSuite) TestEnqueueOperation(c *gc.C) {
+	id0, tasks := s.addRunOperation(c)
+	id1, err := s.State.EnqueueOperation("another")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id1, gc.Not(gc.Equals), id0)
+
+	op, err := s.State.Operation(id0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Id(), gc.Equals, id0)
+	c.Assert(op.Summary(), gc.Equals, "run hostname")
+	c.Assert(tasks[0].OperationID(), gc.Equals, id0)
+
+	opTasks, err := op.Tasks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(opTasks, gc.HasLen, 2)
+	c.Assert(state.OperationStatus(opTasks), gc.Equals, state.ActionPending)
+}
+
+func (s *OperationSuite) TestOperationNotFound(c *gc.C) {
+	_, err := s.State.Operation("42")
+	c.Assert(err, gc.ErrorMatches, `operation "42" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.unit.AddTask("42", "juju-run", map[string]interface{}{"command": "hostname"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *OperationSuite) TestAddTaskValidatesPayload(c *gc.C) {
+	id, err := s.State.EnqueueOperation("bad")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine.AddTask(id, "snapshot", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add action "snapshot" to a machine; only predefined actions allowed`)
+}
+
+func (s *OperationSuite) TestOperationStatus(c *gc.C) {
+	_, tasks := s.addRunOperation(c)
+	running, err := tasks[0].Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(state.OperationStatus([]state.Action{running, tasks[1]}), gc.Equals, state.ActionRunning)
+
+	completed, err := running.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	failed, err := tasks[1].Finish(state.ActionResults{Status: state.ActionFailed})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(state.OperationStatus([]state.Action{completed}), gc.Equals, state.ActionCompleted)
+	c.Assert(state.OperationStatus([]state.Action{completed, failed}), gc.Equals, state.ActionFailed)
+}
+
+func (s *OperationSuite) TestListOperations(c *gc.C) {
+	id0, tasks := s.addRunOperation(c)
+	id1, _ := s.addRunOperation(c)
+	_, err := tasks[0].Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Actions not enqueued as part of an operation are not listed.
+	_, err = s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	infos, err := s.State.ListOperations(state.OperationQuery{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 2)
+	c.Assert(infos[0].Operation.Id(), gc.Equals, id0)
+	c.Assert(infos[0].Tasks, gc.HasLen, 2)
+	c.Assert(infos[1].Operation.Id(), gc.Equals, id1)
+
+	infos, err = s.State.ListOperations(state.OperationQuery{
+		Receivers: []string{s.unit.Name()},
+		Status:    []state.ActionStatus{state.ActionRunning},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 1)
+	c.Assert(infos[0].Operation.Id(), gc.Equals, id0)
+	c.Assert(infos[0].Tasks, gc.HasLen, 1)
+	c.Assert(infos[0].Tasks[0].Id(), gc.Equals, tasks[0].Id())
+}
+
+func (s *OperationSuite) TestCancel(c *gc.C) {
+	id, tasks := s.addRunOperation(c)
+	_, err := tasks[0].Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	op, err := s.State.Operation(id)
+	c.Assert(err, jc.ErrorIsNil)
+	cancelled, err := op.Cancel()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cancelled, gc.HasLen, 1)
+	c.Assert(cancelled[0].Id(), gc.Equals, tasks[1].Id())
+	c.Assert(cancelled[0].Status(), gc.Equals, state.ActionCancelled)
+
+	// The running task is left to complete.
+	running, err := s.State.Action(tasks[0].Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running.Status(), gc.Equals, state.ActionRunning)
+}
+
+func (s *OperationSuite) TestPruneActionsRemovesEmptyOperations(c *gc.C) {
+	clock := testing.NewClock(coretesting.NonZeroTime())
+	err := s.State.SetClockForTesting(clock)
+	c.Assert(err, jc.ErrorIsNil)
+	finish := func(tasks []state.Action) {
+		for _, task := range tasks {
+			_, err := task.Finish(state.ActionResults{Status: state.ActionCompleted})
+			c.Assert(err, jc.ErrorIsNil)
+		}
+	}
+	oldID, oldTasks := s.addRunOperation(c)
+	finish(oldTasks)
+	clock.Advance(2 * time.Hour)
+	newID, newTasks := s.addRunOperation(c)
+	finish(newTasks)
+
+	err = state.PruneActions(s.State, time.Hour, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Operation(oldID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	op, err := s.State.Operation(newID)
+	c.Assert(err, jc.ErrorIsNil)
+	tasks, err := op.Tasks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tasks, gc.HasLen, 2)
+}
+
+func (s *OperationSuite) TestHasOperations(c *gc.C) {
+	hasOperations, err := s.State.HasOperations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasOperations, jc.IsFalse)
+
+	s.addRunOperation(c)
+	hasOperations, err = s.State.HasOperations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasOperations, jc.IsTrue)
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// OrphanSweep describes the documents of a per-model collection that
+// belong to parent entities in other collections, so that the
+// documents whose parents no longer exist may be found and removed by
+// SweepOrphans.
+type OrphanSweep struct {
+	// Description describes the swept documents, for logging.
+	Description string
+
+	// Collection is the name of the collection to sweep.
+	Collection string
+
+	// Parents returns the parents of the document with the given
+	// model-local ID and contents. The document is orphaned if any of
+	// its parents does not exist; documents with no parents are never
+	// removed.
+	Parents func(localID string, doc bson.M) ([]OrphanParent, error)
+}
+
+// OrphanParent identifies the parent entity of a swept document,
+// within the document's model.
+type OrphanParent struct {
+	// Collection is the name of the parent's collection.
+	Collection string
+
+	// ID is the model-local document ID of the parent. If it is empty,
+	// Selector selects the parent instead.
+	ID string
+
+	// Selector selects the parent's document.
+	Selector bson.D
+}
+
+// orphanSweepBatchSize is the maximum number of orphaned documents
+// that SweepOrphans removes in one transaction.
+var orphanSweepBatchSize = 1000
+
+// SweepOrphans finds the documents described by the sweep, in all
+// models, whose parents no longer exist, and removes them in batches.
+// If dryRun is true, the documents are counted but not removed. It
+// returns the number of orphaned documents found.
+func SweepOrphans(st *State, sweep OrphanSweep, dryRun bool) (int, error) {
+	coll, closer := st.db().GetRawCollection(sweep.Collection)
+	defer closer()
+
+	parentExists := make(map[string]bool)
+	var orphans []interface{}
+	iter := coll.Find(nil).Iter()
+	for {
+		doc := bson.M{}
+		if !iter.Next(&doc) {
+			break
+		}
+		docID, _ := doc["_id"].(string)
+		modelUUID, _ := doc["model-uuid"].(string)
+		localID := strings.TrimPrefix(docID, modelUUID+":")
+		parents, err := sweep.Parents(localID, doc)
+		if err != nil {
+			iter.Close()
+			return 0, errors.Annotatef(err, "cannot find parents of %s document %q", sweep.Collection, docID)
+		}
+		orphaned := false
+		for _, parent := range parents {
+			exists, err := orphanParentExists(st, modelUUID, parent, parentExists)
+			if err != nil {
+				iter.Close()
+				return 0, errors.Trace(err)
+			}
+			if !exists {
+				orphaned = true
+				break
+			}
+		}
+		if orphaned {
+			orphans = append(orphans, doc["_id"])
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return 0, errors.Annotatef(err, "cannot read %s", sweep.Collection)
+	}
+	if dryRun || len(orphans) == 0 {
+		return len(orphans), nil
+	}
+
+	for remaining := orphans; len(remaining) > 0; {
+		batch := remaining
+		if len(batch) > orphanSweepBatchSize {
+			batch = batch[:orphanSweepBatchSize]
+		}
+		remaining = remaining[len(batch):]
+		ops := make([]txn.Op, len(batch))
+		for i, id := range batch {
+			ops[i] = txn.Op{
+				C:      sweep.Collection,
+				Id:     id,
+				Assert: txn.DocExists,
+				Remove: true,
+			}
+		}
+		if err := st.runRawTransaction(ops); err != nil {
+			return 0, errors.Annotatef(err, "cannot remove orphaned %s", sweep.Description)
+		}
+	}
+	return len(orphans), nil
+}
+
+// orphanParentExists reports whether the given parent exists in the
+// model with the given UUID, caching the answer in known.
+func orphanParentExists(st *State, modelUUID string, parent OrphanParent, known map[string]bool) (bool, error) {
+	sel := bson.D{{"model-uuid", modelUUID}}
+	if parent.ID != "" {
+		sel = append(sel, bson.DocElem{"_id", ensureModelUUID(modelUUID, parent.ID)})
+	} else {
+		sel = append(sel, parent.Selector...)
+	}
+	key := fmt.Sprintf("%s %v", parent.Collection, sel)
+	if exists, ok := known[key]; ok {
+		return exists, nil
+	}
+	coll, closer := st.db().GetRawCollection(parent.Collection)
+	defer closer()
+	n, err := coll.Find(sel).Count()
+	if err != nil {
+		return false, errors.Annotatef(err, "cannot read %s", parent.Collection)
+	}
+	known[key] = n > 0
+	return n > 0, nil
+}
+
+var (
+	// OrphanedRelationSettings sweeps the settings of units in
+	// relations that no longer exist.
+	OrphanedRelationSettings = OrphanSweep{
+		Description: "relation settings",
+		Collection:  settingsC,
+		Parents: func(localID string, _ bson.M) ([]OrphanParent, error) {
+			// Relation unit settings keys look like:
+			// r#<relation id>#[<principal unit>#]<role>#<unit>
+			parts := strings.Split(localID, "#")
+			if len(parts) < 4 || parts[0] != "r" {
+				return nil, nil
+			}
+			relationID, err := strconv.Atoi(parts[1])
+			if err != nil {
+				return nil, errors.NotValidf("relation settings key %q", localID)
+			}
+			return []OrphanParent{{
+				Collection: relationsC,
+				Selector:   bson.D{{"id", relationID}},
+			}}, nil
+		},
+	}
+
+	// OrphanedOpenedPorts sweeps the opened ports of machines that no
+	// longer exist.
+	OrphanedOpenedPorts = OrphanSweep{
+		Description: "opened ports",
+		Collection:  openedPortsC,
+		Parents: func(_ string, doc bson.M) ([]OrphanParent, error) {
+			machineID, _ := doc["machine-id"].(string)
+			if machineID == "" {
+				return nil, nil
+			}
+			return []OrphanParent{{Collection: machinesC, ID: machineID}}, nil
+		},
+	}
+
+	// OrphanedStorageAttachments sweeps the attachments of storage
+	// instances that no longer exist. Attachments of units that no
+	// longer exist are not swept, since the attachment counts of their
+	// storage instances would also need to be corrected.
+	OrphanedStorageAttachments = OrphanSweep{
+		Description: "storage attachments",
+		Collection:  storageAttachmentsC,
+		Parents: func(_ string, doc bson.M) ([]OrphanParent, error) {
+			storageID, _ := doc["storageid"].(string)
+			if storageID == "" {
+				return nil, nil
+			}
+			return []OrphanParent{{Collection: storageInstancesC, ID: storageID}}, nil
+		},
+	}
+)
@@ -0,0 +1,134 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type orphanSweepSuite struct {
+	internalStateSuite
+}
+
+var _ = gc.Suite(&orphanSweepSuite{})
+
+func (s *orphanSweepSuite) insert(c *gc.C, collName string, docs ...bson.M) {
+	coll, closer := s.state.db().GetRawCollection(collName)
+	defer closer()
+	uuid := s.state.ModelUUID()
+	for _, doc := range docs {
+		doc["_id"] = uuid + ":" + doc["_id"].(string)
+		doc["model-uuid"] = uuid
+		err := coll.Insert(doc)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *orphanSweepSuite) docIDs(c *gc.C, collName string, sel bson.M) []string {
+	coll, closer := s.state.db().GetRawCollection(collName)
+	defer closer()
+	var docs []struct {
+		DocID string `bson:"_id"`
+	}
+	err := coll.Find(sel).Sort("_id").All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = s.state.localID(doc.DocID)
+	}
+	return ids
+}
+
+func (s *orphanSweepSuite) TestRelationSettings(c *gc.C) {
+	s.insert(c, relationsC, bson.M{
+		"_id": "ntp:ntp-peers",
+		"key": "ntp:ntp-peers",
+		"id":  3,
+	})
+	s.insert(c, settingsC,
+		bson.M{"_id": "r#3#peer#ntp/0"},
+		bson.M{"_id": "r#4#requirer#nrpe/0"},
+		bson.M{"_id": "r#4#min/0#requirer#nrpe/1"},
+		bson.M{"_id": "a#ntp#cs:ntp-1"},
+	)
+	relationSettings := bson.M{"_id": bson.RegEx{Pattern: ":r#"}}
+
+	n, err := SweepOrphans(s.state, OrphanedRelationSettings, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(n, gc.Equals, 2)
+	c.Check(s.docIDs(c, settingsC, relationSettings), jc.DeepEquals, []string{
+		"r#3#peer#ntp/0", "r#4#min/0#requirer#nrpe/1", "r#4#requirer#nrpe/0",
+	})
+
+	n, err = SweepOrphans(s.state, OrphanedRelationSettings, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(n, gc.Equals, 2)
+	c.Check(s.docIDs(c, settingsC, relationSettings), jc.DeepEquals, []string{"r#3#peer#ntp/0"})
+	c.Check(s.docIDs(c, settingsC, bson.M{"_id": bson.RegEx{Pattern: ":a#ntp#"}}), jc.DeepEquals, []string{"a#ntp#cs:ntp-1"})
+
+	n, err = SweepOrphans(s.state, OrphanedRelationSettings, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(n, gc.Equals, 0)
+}
+
+func (s *orphanSweepSuite) TestRelationSettingsBadKey(c *gc.C) {
+	s.insert(c, settingsC, bson.M{"_id": "r#x#peer#ntp/0"})
+
+	_, err := SweepOrphans(s.state, OrphanedRelationSettings, true)
+	c.Assert(err, gc.ErrorMatches, `cannot find parents of settings document ".*:r#x#peer#ntp/0": relation settings key "r#x#peer#ntp/0" not valid`)
+}
+
+func (s *orphanSweepSuite) TestOpenedPortsInBatches(c *gc.C) {
+	s.PatchValue(&orphanSweepBatchSize, 2)
+	s.insert(c, machinesC, bson.M{"_id": "0", "machineid": "0"})
+	s.insert(c, openedPortsC, bson.M{"_id": "m#0#", "machine-id": "0"})
+	for i := 1; i <= 5; i++ {
+		s.insert(c, openedPortsC, bson.M{
+			"_id":        fmt.Sprintf("m#%d#", i),
+			"machine-id": fmt.Sprint(i),
+		})
+	}
+
+	n, err := SweepOrphans(s.state, OrphanedOpenedPorts, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(n, gc.Equals, 5)
+	c.Check(s.docIDs(c, openedPortsC, nil), jc.DeepEquals, []string{"m#0#"})
+}
+
+func (s *orphanSweepSuite) TestStorageAttachments(c *gc.C) {
+	s.insert(c, storageInstancesC, bson.M{"_id": "data/0", "id": "data/0"})
+	s.insert(c, storageAttachmentsC,
+		bson.M{"_id": "u#mysql/0#charm#data/0", "unitid": "mysql/0", "storageid": "data/0"},
+		bson.M{"_id": "u#mysql/0#charm#data/1", "unitid": "mysql/0", "storageid": "data/1"},
+		bson.M{"_id": "u#gone/0#charm#data/0", "unitid": "gone/0", "storageid": "data/0"},
+	)
+
+	n, err := SweepOrphans(s.state, OrphanedStorageAttachments, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(n, gc.Equals, 1)
+	c.Check(s.docIDs(c, storageAttachmentsC, nil), jc.DeepEquals, []string{
+		"u#gone/0#charm#data/0", "u#mysql/0#charm#data/0",
+	})
+}
+
+func (s *orphanSweepSuite) TestParentsInOtherModelIgnored(c *gc.C) {
+	other := s.newState(c)
+	coll, closer := s.state.db().GetRawCollection(machinesC)
+	defer closer()
+	err := coll.Insert(bson.M{
+		"_id":        other.ModelUUID() + ":1",
+		"model-uuid": other.ModelUUID(),
+		"machineid":  "1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.insert(c, openedPortsC, bson.M{"_id": "m#1#", "machine-id": "1"})
+
+	n, err := SweepOrphans(s.state, OrphanedOpenedPorts, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(n, gc.Equals, 1)
+}
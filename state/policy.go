@@ -5,6 +5,7 @@ package state
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
@@ -117,10 +118,43 @@ func (st *State) constraintsValidator() (constraints.Validator, error) {
 		if len(arches) != 0 {
 			validator.UpdateVocabulary(constraints.Arch, arches)
 		}
+
+		// Likewise, restrict image ids to those of the known cloud
+		// images, unless the model has a custom image metadata source
+		// whose images are only discovered at provisioning time.
+		if _, ok := cfg.ImageMetadataURL(); !ok {
+			imageIds, err := st.cloudImageIds(region)
+			if err != nil {
+				return nil, errors.Annotate(err, "querying cloud image ids")
+			}
+			if len(imageIds) != 0 {
+				validator.UpdateVocabulary(constraints.ImageId, imageIds)
+			}
+		}
 	}
 	return validator, nil
 }
 
+// cloudImageIds returns the ids of all cloud images recorded for the
+// given region.
+func (st *State) cloudImageIds(region string) ([]string, error) {
+	found, err := st.CloudImageMetadataStorage.FindMetadata(
+		cloudimagemetadata.MetadataFilter{Region: region},
+	)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	imageIds := set.NewStrings()
+	for _, metadata := range found {
+		for _, m := range metadata {
+			imageIds.Add(m.ImageId)
+		}
+	}
+	return imageIds.SortedValues(), nil
+}
+
 // resolveConstraints combines the given constraints with the environ constraints to get
 // a constraints which will be used to create a new instance.
 func (st *State) resolveConstraints(cons constraints.Value) (constraints.Value, error) {
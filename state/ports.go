@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 	statetxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -310,6 +311,141 @@ func (p *Ports) ClosePorts(portRange PortRange) (err error) {
 	return nil
 }
 
+// OpenClosePorts closes and then opens the specified port ranges in a
+// single transaction: if any of the changes cannot be made, none of
+// them are. Opening a range that is already open, or closing one that
+// is not, is ignored.
+func (p *Ports) OpenClosePorts(openRanges, closeRanges []PortRange) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot open and close ports on %s", p)
+
+	for _, portRanges := range [][]PortRange{openRanges, closeRanges} {
+		for _, portRange := range portRanges {
+			if err := portRange.Validate(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	var (
+		newPorts []PortRange
+		changed  bool
+	)
+	ports := Ports{st: p.st, doc: p.doc, areNew: p.areNew}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := checkModelActive(p.st); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err := p.verifySubnetAliveWhenSet(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err := ports.Refresh(); errors.IsNotFound(err) {
+				ports.areNew = true
+				ports.doc.Ports = nil
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			} else {
+				ports.areNew = false
+			}
+		}
+		var err error
+		newPorts, changed, err = changePortRanges(ports.doc.Ports, openRanges, closeRanges)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !changed {
+			return nil, statetxn.ErrNoOperations
+		}
+
+		ops := []txn.Op{
+			assertModelActiveOp(p.st.ModelUUID()),
+		}
+		for _, unitName := range portRangeUnits(openRanges) {
+			ops = append(ops, txn.Op{
+				C:      unitsC,
+				Id:     p.st.docID(unitName),
+				Assert: notDeadDoc,
+			})
+		}
+		switch {
+		case ports.areNew:
+			ops = append(ops, addPortsDocOps(p.st, &ports.doc, txn.DocMissing, newPorts...)...)
+		case len(newPorts) == 0:
+			// All ports closed, so remove the ports doc instead.
+			ops = append(ops, ports.removeOps()...)
+		default:
+			assert := bson.D{{"txn-revno", ports.doc.TxnRevno}}
+			ops = append(ops, setPortsDocOps(p.st, ports.doc, assert, newPorts...)...)
+		}
+		return ops, nil
+	}
+	if err = p.st.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+	if !changed {
+		return nil
+	}
+	if len(newPorts) > 0 {
+		// Mark object as created.
+		p.areNew = false
+	}
+	p.doc.Ports = newPorts
+	return nil
+}
+
+// changePortRanges returns the port ranges that result from closing
+// and then opening the given ranges in existing, and whether they
+// differ from existing.
+func changePortRanges(existing, openRanges, closeRanges []PortRange) ([]PortRange, bool, error) {
+	result := append([]PortRange(nil), existing...)
+	changed := false
+	for _, closeRange := range closeRanges {
+		found := false
+		kept := result[:0]
+		for _, existingRange := range result {
+			if existingRange == closeRange {
+				found = true
+				continue
+			}
+			if existingRange.UnitName == closeRange.UnitName {
+				if err := existingRange.CheckConflicts(closeRange); err != nil {
+					return nil, false, errors.Annotatef(err, "cannot close ports %s", closeRange)
+				}
+			}
+			kept = append(kept, existingRange)
+		}
+		result = kept
+		changed = changed || found
+	}
+	for _, openRange := range openRanges {
+		found := false
+		for _, existingRange := range result {
+			if existingRange == openRange {
+				found = true
+				break
+			}
+			if err := existingRange.CheckConflicts(openRange); err != nil {
+				return nil, false, errors.Annotatef(err, "cannot open ports %s", openRange)
+			}
+		}
+		if !found {
+			result = append(result, openRange)
+			changed = true
+		}
+	}
+	return result, changed, nil
+}
+
+// portRangeUnits returns the sorted names of the units of the given
+// port ranges.
+func portRangeUnits(portRanges []PortRange) []string {
+	unitNames := set.NewStrings()
+	for _, portRange := range portRanges {
+		unitNames.Add(portRange.UnitName)
+	}
+	return unitNames.SortedValues()
+}
+
 // PortsForUnit returns the ports associated with specified unitName that are
 // maintained on this document (i.e. are open on this unit's assigned machine).
 func (p *Ports) PortsForUnit(unitName string) []PortRange {
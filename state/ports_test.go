@@ -331,6 +331,65 @@ func (s *PortsDocSuite) TestCloseInvalidRange(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot close ports 150-200/tcp \("wordpress/0"\): port ranges 100-200/tcp \("wordpress/0"\) and 150-200/tcp \("wordpress/0"\) conflict`)
 }
 
+func (s *PortsDocSuite) TestOpenClosePorts(c *gc.C) {
+	openRange := func(unit *state.Unit, from, to int, protocol string) state.PortRange {
+		return state.PortRange{FromPort: from, ToPort: to, UnitName: unit.Name(), Protocol: protocol}
+	}
+	err := s.portsWithoutSubnet.OpenPorts(openRange(s.unit1, 100, 200, "tcp"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The closed range may be reused by the opened ones.
+	err = s.portsWithoutSubnet.OpenClosePorts(
+		[]state.PortRange{
+			openRange(s.unit1, 150, 250, "tcp"),
+			openRange(s.unit2, 10, 20, "udp"),
+		},
+		[]state.PortRange{
+			openRange(s.unit1, 100, 200, "tcp"),
+			openRange(s.unit1, 8080, 8080, "tcp"), // not open; ignored
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ports, err := state.GetPorts(s.State, s.machine.Id(), "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports.AllPortRanges(), jc.DeepEquals, map[network.PortRange]string{
+		{150, 250, "tcp"}: s.unit1.Name(),
+		{10, 20, "udp"}:   s.unit2.Name(),
+	})
+
+	// Closing all of the ranges removes the document.
+	err = ports.OpenClosePorts(nil, []state.PortRange{
+		openRange(s.unit1, 150, 250, "tcp"),
+		openRange(s.unit2, 10, 20, "udp"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = state.GetPorts(s.State, s.machine.Id(), "")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *PortsDocSuite) TestOpenClosePortsAllOrNothing(c *gc.C) {
+	err := s.unit1.OpenPorts("tcp", 100, 200)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit2.OpenClosePorts(
+		[]network.PortRange{{300, 400, "tcp"}, {150, 160, "tcp"}},
+		nil,
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot open and close ports for unit "wordpress/1" on subnet "": .*: `+
+		`cannot open ports 150-160/tcp \("wordpress/1"\): port ranges 100-200/tcp \("wordpress/0"\) and 150-160/tcp \("wordpress/1"\) conflict`)
+
+	unitRanges, err := s.unit2.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unitRanges, gc.HasLen, 0)
+
+	err = s.unit2.OpenClosePorts([]network.PortRange{{300, 400, "tcp"}}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	unitRanges, err = s.unit2.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unitRanges, jc.DeepEquals, []network.PortRange{{300, 400, "tcp"}})
+}
+
 func (s *PortsDocSuite) TestRemovePortsDoc(c *gc.C) {
 	portRange := state.PortRange{
 		FromPort: 100,
@@ -34,7 +34,8 @@ type Resources interface {
 	// GetPendingResource returns the identified resource.
 	GetPendingResource(applicationID, name, pendingID string) (resource.Resource, error)
 
-	// SetResource adds the resource to blob storage and updates the metadata.
+	// SetResource adds the resource to blob storage and updates the
+	// metadata. A pinned resource is not replaced.
 	SetResource(applicationID, userID string, res charmresource.Resource, r io.Reader) (resource.Resource, error)
 
 	// SetUnitResource sets the resource metadata for a specific unit.
@@ -43,6 +44,15 @@ type Resources interface {
 	// UpdatePendingResource adds the resource to blob storage and updates the metadata.
 	UpdatePendingResource(applicationID, pendingID, userID string, res charmresource.Resource, r io.Reader) (resource.Resource, error)
 
+	// UpdatePinnedPendingResource adds the resource to blob storage and
+	// updates the metadata, pinning the resource to the given
+	// fingerprint, which the content must have.
+	UpdatePinnedPendingResource(applicationID, pendingID, userID string, res charmresource.Resource, pin charmresource.Fingerprint, r io.Reader) (resource.Resource, error)
+
+	// SetUnpinnedResource is like SetResource, but replaces the
+	// resource even if it is pinned, leaving it unpinned.
+	SetUnpinnedResource(applicationID, userID string, res charmresource.Resource, r io.Reader) (resource.Resource, error)
+
 	// OpenResource returns the metadata for a resource and a reader for the resource.
 	OpenResource(applicationID, name string) (resource.Resource, io.ReadCloser, error)
 
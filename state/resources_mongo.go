@@ -320,6 +320,8 @@ type resourceDoc struct {
 	Username  string    `bson:"username"`
 	Timestamp time.Time `bson:"timestamp-when-added"`
 
+	PinnedFingerprint []byte `bson:"pinned-fingerprint,omitempty"`
+
 	StoragePath string `bson:"storage-path"`
 
 	DownloadProgress *int64 `bson:"download-progress,omitempty"`
@@ -351,7 +353,7 @@ func resource2doc(id string, stored storedResource) *resourceDoc {
 	res := stored.Resource
 	// TODO(ericsnow) We may need to limit the resolution of timestamps
 	// in order to avoid some conversion problems from Mongo.
-	return &resourceDoc{
+	doc := &resourceDoc{
 		DocID:     id,
 		ID:        res.ID,
 		PendingID: res.PendingID,
@@ -373,6 +375,10 @@ func resource2doc(id string, stored storedResource) *resourceDoc {
 
 		StoragePath: stored.storagePath,
 	}
+	if res.IsPinned() {
+		doc.PinnedFingerprint = res.PinnedFingerprint.Bytes()
+	}
+	return doc
 }
 
 // doc2resource returns the resource info represented by the doc.
@@ -408,6 +414,11 @@ func doc2basicResource(doc resourceDoc) (resource.Resource, error) {
 		return res, errors.Annotate(err, "got invalid data from DB")
 	}
 
+	pinned, err := resource.DeserializeFingerprint(doc.PinnedFingerprint)
+	if err != nil {
+		return res, errors.Annotate(err, "got invalid data from DB")
+	}
+
 	res = resource.Resource{
 		Resource: charmresource.Resource{
 			Meta: charmresource.Meta{
@@ -426,6 +437,8 @@ func doc2basicResource(doc resourceDoc) (resource.Resource, error) {
 		ApplicationID: doc.ApplicationID,
 		Username:      doc.Username,
 		Timestamp:     doc.Timestamp,
+
+		PinnedFingerprint: pinned,
 	}
 	if err := res.Validate(); err != nil {
 		return res, errors.Annotate(err, "got invalid data from DB")
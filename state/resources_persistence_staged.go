@@ -5,8 +5,10 @@ package state
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 )
 
@@ -63,8 +65,19 @@ func (staged StagedResource) Unstage() error {
 	return nil
 }
 
-// Activate makes the staged resource the active resource.
+// Activate makes the staged resource the active resource. A pinned
+// active resource is not replaced.
 func (staged StagedResource) Activate() error {
+	return staged.activate(false)
+}
+
+// ActivateUnpinned is like Activate, but replaces the active resource
+// even if it is pinned.
+func (staged StagedResource) ActivateUnpinned() error {
+	return staged.activate(true)
+}
+
+func (staged StagedResource) activate(unpin bool) error {
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		// This is an "upsert".
 		var ops []txn.Op
@@ -73,6 +86,11 @@ func (staged StagedResource) Activate() error {
 			ops = newInsertResourceOps(staged.stored)
 		case 1:
 			ops = newUpdateResourceOps(staged.stored)
+			if staged.stored.PendingID == "" && !unpin {
+				// The resource may have been pinned since it
+				// was checked below.
+				ops[0].Assert = notPinnedDoc
+			}
 		default:
 			return nil, errors.New("setting the resource failed")
 		}
@@ -87,12 +105,17 @@ func (staged StagedResource) Activate() error {
 		// CharmModifiedVersion on the service, since resources are integral to
 		// the high level "version" of the charm.
 		if staged.stored.PendingID == "" {
-			hasNewBytes, err := staged.hasNewBytes()
+			current, err := staged.current()
 			if err != nil {
 				logger.Errorf("can't read existing resource during activate: %v", errors.Details(err))
 				return nil, errors.Trace(err)
 			}
-			if hasNewBytes {
+			if current != nil && len(current.PinnedFingerprint) > 0 && !unpin {
+				return nil, errors.NewNotValid(nil, fmt.Sprintf(
+					"resource %q is pinned, and must be unpinned to be replaced", staged.stored.Name,
+				))
+			}
+			if staged.hasNewBytes(current) {
 				incOps := staged.base.IncCharmModifiedVersionOps(staged.stored.ApplicationID)
 				ops = append(ops, incOps...)
 			}
@@ -105,18 +128,27 @@ func (staged StagedResource) Activate() error {
 	return nil
 }
 
-func (staged StagedResource) hasNewBytes() (bool, error) {
+// notPinnedDoc asserts that a resource is not pinned.
+var notPinnedDoc = bson.D{{"pinned-fingerprint", bson.D{{"$exists", false}}}}
+
+// current returns the active resource's document, or nil if there is
+// no active resource.
+func (staged StagedResource) current() (*resourceDoc, error) {
 	var current resourceDoc
 	err := staged.base.One(resourcesC, staged.stored.ID, &current)
-	switch {
-	case errors.IsNotFound(err):
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Annotate(err, "couldn't read existing resource")
+	}
+	return &current, nil
+}
+
+func (staged StagedResource) hasNewBytes(current *resourceDoc) bool {
+	if current == nil {
 		// if there's no current resource stored, then any non-zero bytes will
 		// be new.
-		return !staged.stored.Fingerprint.IsZero(), nil
-	case err != nil:
-		return false, errors.Annotate(err, "couldn't read existing resource")
-	default:
-		diff := !bytes.Equal(staged.stored.Fingerprint.Bytes(), current.Fingerprint)
-		return diff, nil
+		return !staged.stored.Fingerprint.IsZero()
 	}
+	return !bytes.Equal(staged.stored.Fingerprint.Bytes(), current.Fingerprint)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/state/statetest"
@@ -167,6 +168,53 @@ func (s *StagedResourceSuite) TestActivateExists(c *gc.C) {
 	}})
 	s.stub.CheckCall(c, 7, "IncCharmModifiedVersionOps", "a-application")
 	s.stub.CheckCall(c, 8, "RunTransaction", []txn.Op{{
+		C:      "resources",
+		Id:     "resource#a-application/spam",
+		Assert: bson.D{{"pinned-fingerprint", bson.D{{"$exists", false}}}},
+		Remove: true,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-application/spam",
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}, {
+		C:      "application",
+		Id:     "a-application",
+		Assert: txn.DocExists,
+	}, {
+		C:      "resources",
+		Id:     "resource#a-application/spam#staged",
+		Remove: true,
+	}})
+}
+
+func (s *StagedResourceSuite) TestActivatePinned(c *gc.C) {
+	staged, doc := s.newStagedResource(c, "a-application", "spam")
+	doc.PinnedFingerprint = doc.Fingerprint
+	s.base.ReturnOne = doc
+	ignoredErr := errors.New("<never reached>")
+	s.stub.SetErrors(nil, nil, nil, ignoredErr)
+
+	err := staged.Activate()
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `resource "spam" is pinned, and must be unpinned to be replaced`)
+
+	s.stub.CheckCallNames(c, "Run", "ApplicationExistsOps", "One")
+}
+
+func (s *StagedResourceSuite) TestActivateUnpinned(c *gc.C) {
+	staged, doc := s.newStagedResource(c, "a-application", "spam")
+	current := doc
+	current.PinnedFingerprint = doc.Fingerprint
+	s.base.ReturnOne = current
+	ignoredErr := errors.New("<never reached>")
+	s.stub.SetErrors(nil, nil, nil, txn.ErrAborted, nil, nil, nil, ignoredErr)
+
+	err := staged.ActivateUnpinned()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "Run", "ApplicationExistsOps", "One", "RunTransaction", "ApplicationExistsOps", "One", "RunTransaction")
+	s.stub.CheckCall(c, 6, "RunTransaction", []txn.Op{{
 		C:      "resources",
 		Id:     "resource#a-application/spam",
 		Assert: txn.DocExists,
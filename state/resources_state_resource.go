@@ -4,6 +4,7 @@
 package state
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"path"
@@ -17,6 +18,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/resource"
+	"github.com/juju/juju/state/storage"
 )
 
 type resourcePersistence interface {
@@ -153,11 +155,27 @@ func (st resourceState) GetPendingResource(applicationID, name, pendingID string
 
 // TODO(ericsnow) Separate setting the metadata from storing the blob?
 
-// SetResource stores the resource in the Juju model.
+// SetResource stores the resource in the Juju model. A pinned resource
+// is not replaced.
 func (st resourceState) SetResource(applicationID, userID string, chRes charmresource.Resource, r io.Reader) (resource.Resource, error) {
 	logger.Tracef("adding resource %q for application %q", chRes.Name, applicationID)
 	pendingID := ""
-	res, err := st.setResource(pendingID, applicationID, userID, chRes, r)
+	res, err := st.setResource(pendingID, applicationID, userID, chRes, r, charmresource.Fingerprint{}, false)
+	if err != nil {
+		return res, errors.Trace(err)
+	}
+	return res, nil
+}
+
+// SetUnpinnedResource stores the resource in the Juju model, replacing
+// it even if it is pinned, and leaving it unpinned.
+func (st resourceState) SetUnpinnedResource(applicationID, userID string, chRes charmresource.Resource, r io.Reader) (resource.Resource, error) {
+	logger.Tracef("adding unpinned resource %q for application %q", chRes.Name, applicationID)
+	if r == nil {
+		return resource.Resource{}, errors.NotValidf("unpinning resource %q without content", chRes.Name)
+	}
+	pendingID := ""
+	res, err := st.setResource(pendingID, applicationID, userID, chRes, r, charmresource.Fingerprint{}, true)
 	if err != nil {
 		return res, errors.Trace(err)
 	}
@@ -200,7 +218,7 @@ func (st resourceState) AddPendingResource(applicationID, userID string, chRes c
 	}
 	logger.Debugf("adding pending resource %q for application %q (ID: %s)", chRes.Name, applicationID, pendingID)
 
-	if _, err := st.setResource(pendingID, applicationID, userID, chRes, r); err != nil {
+	if _, err := st.setResource(pendingID, applicationID, userID, chRes, r, charmresource.Fingerprint{}, false); err != nil {
 		return "", errors.Trace(err)
 	}
 
@@ -210,7 +228,28 @@ func (st resourceState) AddPendingResource(applicationID, userID string, chRes c
 // UpdatePendingResource stores the resource in the Juju model.
 func (st resourceState) UpdatePendingResource(applicationID, pendingID, userID string, chRes charmresource.Resource, r io.Reader) (resource.Resource, error) {
 	logger.Tracef("updating pending resource %q (%s) for application %q", chRes.Name, pendingID, applicationID)
-	res, err := st.setResource(pendingID, applicationID, userID, chRes, r)
+	res, err := st.setResource(pendingID, applicationID, userID, chRes, r, charmresource.Fingerprint{}, false)
+	if err != nil {
+		return res, errors.Trace(err)
+	}
+	return res, nil
+}
+
+// UpdatePinnedPendingResource stores the resource in the Juju model,
+// pinned to the given fingerprint. The stored content is checked
+// against the fingerprint as it is written.
+func (st resourceState) UpdatePinnedPendingResource(applicationID, pendingID, userID string, chRes charmresource.Resource, pin charmresource.Fingerprint, r io.Reader) (resource.Resource, error) {
+	logger.Tracef("updating pinned pending resource %q (%s) for application %q", chRes.Name, pendingID, applicationID)
+	if r == nil {
+		return resource.Resource{}, errors.NotValidf("pinning resource %q without content", chRes.Name)
+	}
+	if pin.IsZero() {
+		return resource.Resource{}, errors.NotValidf("pinning resource %q without fingerprint", chRes.Name)
+	}
+	if pendingID == "" {
+		return resource.Resource{}, errors.NotValidf("pinning non-pending resource %q", chRes.Name)
+	}
+	res, err := st.setResource(pendingID, applicationID, userID, chRes, r, pin, false)
 	if err != nil {
 		return res, errors.Trace(err)
 	}
@@ -219,7 +258,7 @@ func (st resourceState) UpdatePendingResource(applicationID, pendingID, userID s
 
 // TODO(ericsnow) Add ResolvePendingResource().
 
-func (st resourceState) setResource(pendingID, applicationID, userID string, chRes charmresource.Resource, r io.Reader) (resource.Resource, error) {
+func (st resourceState) setResource(pendingID, applicationID, userID string, chRes charmresource.Resource, r io.Reader, pin charmresource.Fingerprint, unpin bool) (resource.Resource, error) {
 	id := newResourceID(applicationID, chRes.Name)
 
 	res := resource.Resource{
@@ -228,6 +267,17 @@ func (st resourceState) setResource(pendingID, applicationID, userID string, chR
 		PendingID:     pendingID,
 		ApplicationID: applicationID,
 	}
+	if !pin.IsZero() {
+		// The content is stored under, and checked against, its
+		// claimed fingerprint, so the pin must be that fingerprint.
+		if !bytes.Equal(pin.Bytes(), chRes.Fingerprint.Bytes()) {
+			return res, errors.NotValidf(
+				"resource %q fingerprint %s with pinned fingerprint %s",
+				chRes.Name, chRes.Fingerprint, pin,
+			)
+		}
+		res.PinnedFingerprint = pin
+	}
 	if r != nil {
 		// TODO(ericsnow) Validate the user ID (or use a tag).
 		res.Username = userID
@@ -239,11 +289,21 @@ func (st resourceState) setResource(pendingID, applicationID, userID string, chR
 	}
 
 	if r == nil {
+		if pendingID == "" && !unpin {
+			current, _, err := st.persist.GetResource(id)
+			if err != nil && !errors.IsNotFound(err) {
+				return res, errors.Trace(err)
+			} else if err == nil && current.IsPinned() {
+				return res, errors.NewNotValid(nil, fmt.Sprintf(
+					"resource %q is pinned, and must be unpinned to be replaced", chRes.Name,
+				))
+			}
+		}
 		if err := st.persist.SetResource(res); err != nil {
 			return res, errors.Trace(err)
 		}
 	} else {
-		if err := st.storeResource(res, r); err != nil {
+		if err := st.storeResource(res, r, unpin); err != nil {
 			return res, errors.Trace(err)
 		}
 	}
@@ -251,19 +311,24 @@ func (st resourceState) setResource(pendingID, applicationID, userID string, chR
 	return res, nil
 }
 
-func (st resourceState) storeResource(res resource.Resource, r io.Reader) error {
+func (st resourceState) storeResource(res resource.Resource, r io.Reader, unpin bool) error {
 	// We use a staging approach for adding the resource metadata
 	// to the model. This is necessary because the resource data
 	// is stored separately and adding to both should be an atomic
 	// operation.
-
-	storagePath := storagePath(res.Name, res.ApplicationID, res.PendingID)
+	//
+	// The resource data is stored in the controller's blob store, so
+	// that a resource used in many models is stored once.
+	hash := res.Fingerprint.String()
+	storagePath := storage.BlobPath(
+		storage.SHA384BlobID(hash),
+		storagePath(res.Name, res.ApplicationID, res.PendingID),
+	)
 	staged, err := st.persist.StageResource(res, storagePath)
 	if err != nil {
 		return errors.Trace(err)
 	}
 
-	hash := res.Fingerprint.String()
 	if err := st.storage.PutAndCheckHash(storagePath, r, res.Size, hash); err != nil {
 		if err := staged.Unstage(); err != nil {
 			logger.Errorf("could not unstage resource %q (application %q): %v", res.Name, res.ApplicationID, err)
@@ -271,7 +336,11 @@ func (st resourceState) storeResource(res resource.Resource, r io.Reader) error
 		return errors.Trace(err)
 	}
 
-	if err := staged.Activate(); err != nil {
+	activate := staged.Activate
+	if unpin {
+		activate = staged.ActivateUnpinned
+	}
+	if err := activate(); err != nil {
 		if err := st.storage.Remove(storagePath); err != nil {
 			logger.Errorf("could not remove resource %q (application %q) from storage: %v", res.Name, res.ApplicationID, err)
 		}
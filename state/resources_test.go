@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"time" // Only using time func.
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	charmresource "gopkg.in/juju/charm.v6-unstable/resource"
@@ -64,6 +65,51 @@ func (s *ResourcesSuite) TestFunctional(c *gc.C) {
 	// TODO(ericsnow) Add more as state.Resources grows more functionality.
 }
 
+func (s *ResourcesSuite) TestUpdatePinnedPendingResource(c *gc.C) {
+	ch := s.ConnSuite.AddTestingCharm(c, "wordpress")
+	s.ConnSuite.AddTestingApplication(c, "a-application", ch)
+
+	st, err := s.State.Resources()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := "spamspamspam"
+	res := newResource(c, "spam", data)
+	placeholder := res.Resource
+	placeholder.Fingerprint = charmresource.Fingerprint{}
+	placeholder.Size = 0
+	pendingID, err := st.AddPendingResource("a-application", "", placeholder, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stored, err := st.UpdatePinnedPendingResource("a-application", pendingID, res.Username, res.Resource, res.Fingerprint, bytes.NewBufferString(data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stored.PinnedFingerprint, jc.DeepEquals, res.Fingerprint)
+
+	pending, err := st.GetPendingResource("a-application", "spam", pendingID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(pending.IsPinned(), jc.IsTrue)
+	c.Check(pending.PinnedFingerprint, jc.DeepEquals, res.Fingerprint)
+	c.Check(pending.CheckPinned(), jc.ErrorIsNil)
+}
+
+func (s *ResourcesSuite) TestUpdatePinnedPendingResourceMismatch(c *gc.C) {
+	ch := s.ConnSuite.AddTestingCharm(c, "wordpress")
+	s.ConnSuite.AddTestingApplication(c, "a-application", ch)
+
+	st, err := s.State.Resources()
+	c.Assert(err, jc.ErrorIsNil)
+
+	res := newResource(c, "spam", "spamspamspam")
+	other := newResource(c, "spam", "eggs")
+	placeholder := res.Resource
+	placeholder.Fingerprint = charmresource.Fingerprint{}
+	placeholder.Size = 0
+	pendingID, err := st.AddPendingResource("a-application", "", placeholder, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = st.UpdatePinnedPendingResource("a-application", pendingID, res.Username, res.Resource, other.Fingerprint, bytes.NewBufferString("spamspamspam"))
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
 func newResource(c *gc.C, name, data string) resource.Resource {
 	opened := resourcetesting.NewResource(c, nil, name, "a-application", data)
 	res := opened.Resource
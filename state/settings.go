@@ -229,6 +229,32 @@ func (s *Settings) Write() ([]ItemChange, error) {
 	return changes, nil
 }
 
+// WriteAllSettings writes the changes made to each of the given
+// settings in a single transaction, so that either all of the changes
+// are written or none of them are. As with Settings.Write, changes are
+// applied as deltas on top of the latest version of each node.
+func (st *State) WriteAllSettings(settings ...*Settings) error {
+	var ops []txn.Op
+	for _, s := range settings {
+		_, settingsOps := s.settingsUpdateOps()
+		ops = append(ops, settingsOps...)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("settings")
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write settings: %v", err)
+	}
+	for _, s := range settings {
+		s.disk = copyMap(s.core, nil)
+	}
+	return nil
+}
+
 func newSettings(db Database, collection, key string) *Settings {
 	return &Settings{
 		db:         db,
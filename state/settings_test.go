@@ -469,6 +469,49 @@ func (s *SettingsSuite) TestWriteTwice(c *gc.C) {
 	c.Assert(nodeOne.core, gc.DeepEquals, nodeTwo.core)
 }
 
+func (s *SettingsSuite) TestWriteAllSettings(c *gc.C) {
+	nodeOne, err := s.createSettings("one", map[string]interface{}{"a": "foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	nodeTwo, err := s.createSettings("two", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	nodeOne.Delete("a")
+	nodeTwo.Set("b", "bar")
+
+	err = s.state.WriteAllSettings(nodeOne, nodeTwo)
+	c.Assert(err, jc.ErrorIsNil)
+
+	readOne, err := readSettings(s.state.db(), s.collection, "one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readOne.Map(), gc.DeepEquals, map[string]interface{}{})
+	readTwo, err := readSettings(s.state.db(), s.collection, "two")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readTwo.Map(), gc.DeepEquals, map[string]interface{}{"b": "bar"})
+
+	// Nothing is left to write.
+	changes, err := nodeTwo.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(changes, gc.DeepEquals, []ItemChange{})
+}
+
+func (s *SettingsSuite) TestWriteAllSettingsAtomic(c *gc.C) {
+	nodeOne, err := s.createSettings("one", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	nodeTwo, err := s.createSettings("two", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = removeSettings(s.state.db(), s.collection, "two")
+	c.Assert(err, jc.ErrorIsNil)
+	nodeOne.Set("a", "foo")
+	nodeTwo.Set("b", "bar")
+
+	err = s.state.WriteAllSettings(nodeOne, nodeTwo)
+	c.Assert(err, gc.ErrorMatches, "settings not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	readOne, err := readSettings(s.state.db(), s.collection, "one")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readOne.Map(), gc.DeepEquals, map[string]interface{}{})
+}
+
 func (s *SettingsSuite) TestList(c *gc.C) {
 	_, err := s.createSettings("key#1", map[string]interface{}{"foo1": "bar1"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -12,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -63,11 +64,11 @@ const (
 	// blobstoreDB is the name of the blobstore GridFS database.
 	blobstoreDB = "blobstore"
 
-	// applicationLeadershipNamespace is the name of the lease.Client namespace
+	// applicationLeadershipNamespace is the name of the lease.Store namespace
 	// used by the leadership manager.
 	applicationLeadershipNamespace = "application-leadership"
 
-	// singularControllerNamespace is the name of the lease.Client namespace
+	// singularControllerNamespace is the name of the lease.Store namespace
 	// used by the singular manager
 	singularControllerNamespace = "singular-controller"
 )
@@ -441,7 +442,7 @@ func (st *State) ApplicationLeaders() (map[string]string, error) {
 	return result, nil
 }
 
-func (st *State) getLeadershipLeaseClient() (lease.Client, error) {
+func (st *State) getLeadershipLeaseClient() (lease.Store, error) {
 	client, err := statelease.NewClient(statelease.ClientConfig{
 		Id:           st.leaseClientId,
 		Namespace:    applicationLeadershipNamespace,
@@ -456,7 +457,7 @@ func (st *State) getLeadershipLeaseClient() (lease.Client, error) {
 	return client, nil
 }
 
-func (st *State) getSingularLeaseClient() (lease.Client, error) {
+func (st *State) getSingularLeaseClient() (lease.Store, error) {
 	client, err := statelease.NewClient(statelease.ClientConfig{
 		Id:           st.leaseClientId,
 		Namespace:    singularControllerNamespace,
@@ -2233,6 +2234,25 @@ func (st *State) PutAuditEntryFn() func(audit.AuditEntry) error {
 	return stateaudit.PutAuditEntryFn(auditingC, insert)
 }
 
+// AuditEntries returns the audit entries for the model written at or
+// after the given time whose operations, of the form "Facade:vN -
+// Method", match the given regular expression, if it is not empty,
+// oldest first. If limit is positive, only the most recent limit
+// entries are returned.
+func (st *State) AuditEntries(since time.Time, operationPattern string, limit int) ([]audit.AuditEntry, error) {
+	find := func(collectionName string, query bson.D, sort []string, limit int, docs interface{}) error {
+		collection, closeCollection := st.db().GetCollection(collectionName)
+		defer closeCollection()
+
+		q := collection.Find(query).Sort(sort...)
+		if limit > 0 {
+			q = q.Limit(limit)
+		}
+		return errors.Trace(q.All(docs))
+	}
+	return stateaudit.GetAuditEntriesFn(auditingC, find)(st.ModelUUID(), since, operationPattern, limit)
+}
+
 // SetSLA sets the SLA on the current connected model.
 func (st *State) SetSLA(level, owner string, credentials []byte) error {
 	model, err := st.Model()
@@ -28,6 +28,7 @@ import (
 	mgotxn "gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/audit"
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/core/life"
@@ -4811,3 +4812,67 @@ func (s *StateSuite) testOpenParams() state.OpenParams {
 		MongoDialOpts:      mongotest.DialOpts(),
 	}
 }
+
+func (s *StateSuite) TestAuditEntries(c *gc.C) {
+	now := testing.NonZeroTime().UTC()
+	entry := func(modelUUID string, offset time.Duration, operation string) audit.AuditEntry {
+		return audit.AuditEntry{
+			JujuServerVersion: jujuversion.Current,
+			ModelUUID:         modelUUID,
+			Timestamp:         now.Add(offset),
+			RemoteAddress:     "10.0.0.1",
+			OriginType:        "API request",
+			OriginName:        "user-bob",
+			Operation:         operation,
+		}
+	}
+	otherState := s.Factory.MakeModel(c, nil)
+	defer otherState.Close()
+
+	put := s.State.PutAuditEntryFn()
+	for _, e := range []audit.AuditEntry{
+		entry(s.State.ModelUUID(), time.Minute, "Application:v4 - Deploy"),
+		entry(s.State.ModelUUID(), -time.Minute, "Client:v1 - FullStatus"),
+		entry(otherState.ModelUUID(), time.Minute, "Application:v4 - Deploy"),
+		entry(s.State.ModelUUID(), 0, "ModelConfig:v1 - ModelSet"),
+	} {
+		err := put(e)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	entries, err := s.State.AuditEntries(now, "", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 2)
+	c.Check(entries[0].Operation, gc.Equals, "ModelConfig:v1 - ModelSet")
+	c.Check(entries[1].Operation, gc.Equals, "Application:v4 - Deploy")
+	c.Check(entries[1].Timestamp, gc.Equals, now.Add(time.Minute))
+}
+
+func (s *StateSuite) TestAuditEntriesFilterAndLimit(c *gc.C) {
+	now := testing.NonZeroTime().UTC()
+	put := s.State.PutAuditEntryFn()
+	for i, operation := range []string{
+		"Application:v4 - Deploy",
+		"Client:v1 - FullStatus",
+		"Application:v4 - SetCharm",
+		"Application:v4 - Deploy",
+	} {
+		err := put(audit.AuditEntry{
+			JujuServerVersion: jujuversion.Current,
+			ModelUUID:         s.State.ModelUUID(),
+			Timestamp:         now.Add(time.Duration(i) * time.Minute),
+			RemoteAddress:     "10.0.0.1",
+			OriginType:        "API request",
+			OriginName:        "user-bob",
+			Operation:         operation,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	entries, err := s.State.AuditEntries(now, `^Application:v[0-9]+ - (Deploy|SetCharm)$`, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 2)
+	c.Check(entries[0].Operation, gc.Equals, "Application:v4 - SetCharm")
+	c.Check(entries[1].Operation, gc.Equals, "Application:v4 - Deploy")
+	c.Check(entries[1].Timestamp, gc.Equals, now.Add(3*time.Minute))
+}
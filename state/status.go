@@ -4,6 +4,7 @@
 package state
 
 import (
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -286,6 +287,49 @@ func statusHistory(args *statusHistoryArgs) ([]status.StatusInfo, error) {
 	return results, nil
 }
 
+// UnitStatusInfo holds a status recorded for a unit.
+type UnitStatusInfo struct {
+	UnitName string
+	status.StatusInfo
+}
+
+// UnitAgentStatusHistory returns the statuses with the given value
+// recorded for any of the model's unit agents after the given time,
+// most recent first. If limit is positive, at most limit statuses are
+// returned.
+func (st *State) UnitAgentStatusHistory(value status.Status, since time.Time, limit int) ([]UnitStatusInfo, error) {
+	statusHistory, closer := st.db().GetCollection(statusesHistoryC)
+	defer closer()
+
+	// Unit agent keys are "u#<unit name>", and unit workload keys
+	// are "u#<unit name>#charm".
+	query := statusHistory.Find(bson.D{
+		{globalKeyField, bson.RegEx{Pattern: "^u#[^#]+$"}},
+		{"status", value},
+		{"updated", bson.D{{"$gt", since.UnixNano()}}},
+	}).Sort("-updated")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var docs []historicalStatusDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get unit agent status history")
+	}
+	results := make([]UnitStatusInfo, len(docs))
+	for i, doc := range docs {
+		results[i] = UnitStatusInfo{
+			UnitName: strings.TrimPrefix(doc.GlobalKey, "u#"),
+			StatusInfo: status.StatusInfo{
+				Status:  doc.Status,
+				Message: doc.StatusInfo,
+				Data:    utils.UnescapeKeys(doc.StatusData),
+				Since:   unixNanoToTime(doc.Updated),
+			},
+		}
+	}
+	return results, nil
+}
+
 func PruneStatusHistory(st *State, maxHistoryTime time.Duration, maxHistoryMB int) error {
 	err := pruneCollection(st, maxHistoryTime, maxHistoryMB, statusesHistoryC, "updated", NanoSeconds)
 	return errors.Trace(err)
@@ -294,3 +294,84 @@ func (s *StatusHistorySuite) TestStatusHistoryFiltersByDateAndDelta(c *gc.C) {
 	c.Assert(history[1].Message, gc.Equals, "waiting for machine")
 	c.Assert(history[2].Message, gc.Equals, "2 days ago")
 }
+
+func (s *StatusHistorySuite) TestStatusAnnotations(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	err := unit.AddStatusAnnotation("bob", "disk replaced")
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AddStatusAnnotation("alice", "see ticket OPS-123")
+	c.Assert(err, jc.ErrorIsNil)
+
+	annotations, err := unit.StatusAnnotations(status.StatusHistoryFilter{Size: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 2)
+	c.Assert(annotations[0].Message, gc.Equals, "see ticket OPS-123")
+	c.Assert(annotations[0].Data, jc.DeepEquals, map[string]interface{}{"author": "alice"})
+	c.Assert(annotations[0].Since, gc.NotNil)
+	c.Assert(annotations[1].Message, gc.Equals, "disk replaced")
+	c.Assert(annotations[1].Data, jc.DeepEquals, map[string]interface{}{"author": "bob"})
+
+	// Annotations are not statuses.
+	history, err := unit.StatusHistory(status.StatusHistoryFilter{Size: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	for _, h := range history {
+		c.Assert(h.Message, gc.Not(gc.Equals), "disk replaced")
+	}
+}
+
+func (s *StatusHistorySuite) TestStatusAnnotationsMachine(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	err := machine.AddStatusAnnotation("bob", "rebooted for kernel update")
+	c.Assert(err, jc.ErrorIsNil)
+
+	annotations, err := machine.StatusAnnotations(status.StatusHistoryFilter{Size: 10})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, gc.HasLen, 1)
+	c.Assert(annotations[0].Message, gc.Equals, "rebooted for kernel update")
+}
+
+func (s *StatusHistorySuite) TestStatusAnnotationEmptyMessage(c *gc.C) {
+	machine := s.Factory.MakeMachine(c, nil)
+	err := machine.AddStatusAnnotation("bob", "")
+	c.Assert(err, gc.ErrorMatches, `machine "0": empty status annotation not valid`)
+}
+
+func (s *StatusHistorySuite) TestUnitAgentStatusHistory(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit0 := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	unit1 := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+
+	now := time.Now()
+	setStatus := func(setter status.StatusSetter, value status.Status, message string, offset time.Duration) {
+		since := now.Add(offset)
+		err := setter.SetStatus(status.StatusInfo{
+			Status:  value,
+			Message: message,
+			Since:   &since,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	setStatus(unit0.Agent(), status.Error, "too old", -2*time.Hour)
+	setStatus(unit0.Agent(), status.Error, "install failed", -time.Minute)
+	setStatus(unit0, status.Error, "workload failed", time.Minute)
+	setStatus(unit1.Agent(), status.Error, "start failed", 2*time.Minute)
+	setStatus(unit1.Agent(), status.Error, "start failed again", 3*time.Minute)
+
+	history, err := s.State.UnitAgentStatusHistory(status.Error, now.Add(-time.Hour), 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+	c.Check(history[0].UnitName, gc.Equals, unit1.Name())
+	c.Check(history[0].Message, gc.Equals, "start failed again")
+	c.Check(history[1].UnitName, gc.Equals, unit1.Name())
+	c.Check(history[1].Message, gc.Equals, "start failed")
+	c.Check(history[2].UnitName, gc.Equals, unit0.Name())
+	c.Check(history[2].Message, gc.Equals, "install failed")
+	c.Check(history[2].Status, gc.Equals, status.Error)
+
+	history, err = s.State.UnitAgentStatusHistory(status.Error, now.Add(-time.Hour), 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Check(history[0].Message, gc.Equals, "start failed again")
+}
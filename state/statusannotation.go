@@ -0,0 +1,80 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/status"
+)
+
+// statusAnnotationKey returns the key under which annotations made by
+// operators are recorded in the status history of the entity with the
+// given global key. Annotations are kept apart from the entity's own
+// statuses, so that they neither count as status changes nor take
+// part in squashing repeated statuses; but they are pruned along with
+// the rest of the status history.
+func statusAnnotationKey(globalKey string) string {
+	return globalKey + "#status-annotation"
+}
+
+func addStatusAnnotation(mb modelBackend, globalKey, author, message string) error {
+	if message == "" {
+		return errors.NotValidf("empty status annotation")
+	}
+	doc := &historicalStatusDoc{
+		GlobalKey:  statusAnnotationKey(globalKey),
+		StatusInfo: message,
+		StatusData: map[string]interface{}{status.AnnotationAuthorKey: author},
+		Updated:    mb.clock().Now().UnixNano(),
+	}
+	history, closer := mb.db().GetCollection(statusesHistoryC)
+	defer closer()
+	if err := history.Writeable().Insert(doc); err != nil {
+		return errors.Annotate(err, "cannot add status annotation")
+	}
+	return nil
+}
+
+// AddStatusAnnotation records a note made by the named user, such as a
+// reference to a ticket or the maintenance performed, in the unit's
+// status history.
+func (u *Unit) AddStatusAnnotation(author, message string) error {
+	return errors.Annotatef(
+		addStatusAnnotation(u.st, u.globalKey(), author, message),
+		"unit %q", u.Name(),
+	)
+}
+
+// StatusAnnotations returns the notes that users have added to the
+// unit's status history, selected by filter in the same way as
+// StatusHistory selects statuses.
+func (u *Unit) StatusAnnotations(filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	return statusHistory(&statusHistoryArgs{
+		db:        u.st.db(),
+		globalKey: statusAnnotationKey(u.globalKey()),
+		filter:    filter,
+	})
+}
+
+// AddStatusAnnotation records a note made by the named user, such as a
+// reference to a ticket or the maintenance performed, in the machine's
+// status history.
+func (m *Machine) AddStatusAnnotation(author, message string) error {
+	return errors.Annotatef(
+		addStatusAnnotation(m.st, m.globalKey(), author, message),
+		"machine %q", m.Id(),
+	)
+}
+
+// StatusAnnotations returns the notes that users have added to the
+// machine's status history, selected by filter in the same way as
+// StatusHistory selects statuses.
+func (m *Machine) StatusAnnotations(filter status.StatusHistoryFilter) ([]status.StatusInfo, error) {
+	return statusHistory(&statusHistoryArgs{
+		db:        m.st.db(),
+		globalKey: statusAnnotationKey(m.globalKey()),
+		filter:    filter,
+	})
+}
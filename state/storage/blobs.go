@@ -0,0 +1,303 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"gopkg.in/juju/blobstore.v2"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var logger = loggo.GetLogger("juju.state.storage")
+
+const (
+	// blobsBucket is the blobstore bucket holding the content of the
+	// controller's blobs. Blobs are shared by all models.
+	blobsBucket = "blobs"
+
+	// blobRefsC is the name of the collection that records, for each
+	// blob, where its content is stored and the references to it.
+	blobRefsC = "blobrefs"
+
+	// blobPathPrefix prefixes the id of a blob and a reference to it
+	// to make the path by which a Storage reads it.
+	blobPathPrefix = "blobs/"
+
+	// maxBlobPutAttempts is the number of times Put will try to add a
+	// reference to a blob that other clients are concurrently adding
+	// or releasing.
+	maxBlobPutAttempts = 3
+)
+
+// SHA256BlobID returns the id of the blob whose content has the given
+// hex-encoded SHA256 hash.
+func SHA256BlobID(hash string) string {
+	return "sha256/" + hash
+}
+
+// SHA384BlobID returns the id of the blob whose content has the given
+// hex-encoded SHA384 hash.
+func SHA384BlobID(hash string) string {
+	return "sha384/" + hash
+}
+
+// BlobStore stores data keyed by a hash of its content, so that data
+// added many times, such as the archive of a charm deployed in many
+// models, is stored once. Each blob is held by a set of named
+// references, and its data is removed once every reference has been
+// released. A reference is held by at most one blob at a time, just
+// as a path in a Storage holds at most one piece of data.
+type BlobStore interface {
+	// Put adds the named reference to the blob with the given id,
+	// storing the data read from r if the blob is not already
+	// stored, and releasing the reference from any other blob. If
+	// the data is stored, it must match the id. Adding a reference
+	// that the blob already holds has no effect.
+	Put(id, ref string, r io.Reader, length int64) error
+
+	// Get returns an io.ReadCloser for the data of the blob with the
+	// given id, and its length.
+	Get(id string) (r io.ReadCloser, length int64, err error)
+
+	// Release removes the named reference from the blob holding it,
+	// removing the blob if no references remain. Releasing a
+	// reference that no blob holds has no effect, so that an
+	// interrupted release may safely be retried.
+	Release(ref string) error
+}
+
+// BlobPath returns the path by which a Storage reads the blob with the
+// given id, through the given reference to it. Writing the path to a
+// Storage adds the reference, and removing it releases the reference.
+func BlobPath(id, ref string) string {
+	return blobPathPrefix + id + "/" + ref
+}
+
+// ParseBlobPath returns the blob id and reference that make up a path
+// returned by BlobPath, and whether the path refers to a blob at all.
+func ParseBlobPath(path string) (id, ref string, ok bool) {
+	if !strings.HasPrefix(path, blobPathPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(path, blobPathPrefix), "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[0] + "/" + parts[1], parts[2], true
+}
+
+// newBlobHasher returns a hash.Hash for checking the content of the
+// blob with the given id, and the hex-encoded hash that the content
+// must have.
+func newBlobHasher(id string) (hash.Hash, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, "", errors.NotValidf("blob id %q", id)
+	}
+	switch parts[0] {
+	case "sha256":
+		return sha256.New(), parts[1], nil
+	case "sha384":
+		return sha512.New384(), parts[1], nil
+	}
+	return nil, "", errors.NotValidf("blob id %q", id)
+}
+
+// blobRefDoc records where the content of a blob is stored, and the
+// references to it. The document is removed once the last reference
+// is released.
+type blobRefDoc struct {
+	Id     string   `bson:"_id"`
+	Path   string   `bson:"path"`
+	Length int64    `bson:"length"`
+	Refs   []string `bson:"refs"`
+}
+
+// NewBlobStore returns a BlobStore for the controller whose database
+// is accessed through the given session.
+func NewBlobStore(session *mgo.Session) BlobStore {
+	return blobStore{session}
+}
+
+type blobStore struct {
+	session *mgo.Session
+}
+
+func (s blobStore) open() (*mgo.Session, *mgo.Collection, blobstore.ManagedStorage) {
+	session, ms := stateStorage{session: s.session}.blobstore()
+	return session, session.DB(metadataDB).C(blobRefsC), ms
+}
+
+// referenced selects the blob with the given id if it holds any
+// references. No reference can be added to a blob once it has none.
+func referenced(id string) bson.D {
+	return bson.D{{"_id", id}, {"refs.0", bson.D{{"$exists", true}}}}
+}
+
+// unreferenced selects the blob with the given id if it holds no
+// references.
+func unreferenced(id string) bson.D {
+	return bson.D{{"_id", id}, {"refs", bson.D{{"$size", 0}}}}
+}
+
+// Put is part of the BlobStore interface.
+func (s blobStore) Put(id, ref string, r io.Reader, length int64) error {
+	session, refs, ms := s.open()
+	defer session.Close()
+
+	// The content of each Put is stored at a unique path, so that
+	// a Put racing with the release of the last reference to the
+	// same blob cannot have its content removed from under it.
+	var contentPath string
+	defer func() {
+		if contentPath == "" {
+			return
+		}
+		if err := ms.RemoveForBucket(blobsBucket, contentPath); err != nil {
+			logger.Errorf("cannot remove unused content of blob %q: %v", id, err)
+		}
+	}()
+	for attempt := 0; attempt < maxBlobPutAttempts; attempt++ {
+		err := refs.Update(referenced(id), bson.D{{"$addToSet", bson.D{{"refs", ref}}}})
+		if err == nil {
+			// The blob is already stored.
+			return errors.Trace(s.release(refs, ms, ref, id))
+		} else if err != mgo.ErrNotFound {
+			return errors.Annotatef(err, "cannot add reference to blob %q", id)
+		}
+		if contentPath == "" {
+			if contentPath, err = s.putContent(ms, id, r, length); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		err = refs.Insert(&blobRefDoc{
+			Id:     id,
+			Path:   contentPath,
+			Length: length,
+			Refs:   []string{ref},
+		})
+		if err == nil {
+			contentPath = ""
+			return errors.Trace(s.release(refs, ms, ref, id))
+		} else if !mgo.IsDup(err) {
+			return errors.Annotatef(err, "cannot add blob %q", id)
+		}
+		// Another client has added the same blob concurrently, or
+		// is removing it, or was interrupted while removing it;
+		// finish any removal and try again.
+		if err := s.removeUnreferenced(refs, ms, id); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Errorf("cannot add blob %q: state changing too quickly; try again soon", id)
+}
+
+// putContent stores the content of a blob at a new path, which it
+// returns, having checked that the content matches the id.
+func (s blobStore) putContent(ms blobstore.ManagedStorage, id string, r io.Reader, length int64) (string, error) {
+	hasher, expect, err := newBlobHasher(id)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	path := fmt.Sprintf("%s/%s", id, uuid)
+	if err := ms.PutForBucket(blobsBucket, path, io.TeeReader(r, hasher), length); err != nil {
+		return "", errors.Annotatef(err, "cannot store blob %q", id)
+	}
+	if actual := fmt.Sprintf("%x", hasher.Sum(nil)); actual != expect {
+		if err := ms.RemoveForBucket(blobsBucket, path); err != nil {
+			logger.Errorf("cannot remove content of invalid blob %q: %v", id, err)
+		}
+		return "", errors.Errorf("cannot store blob %q: content has hash %q", id, actual)
+	}
+	return path, nil
+}
+
+// Get is part of the BlobStore interface.
+func (s blobStore) Get(id string) (io.ReadCloser, int64, error) {
+	session, refs, ms := s.open()
+	var doc blobRefDoc
+	err := refs.Find(referenced(id)).One(&doc)
+	if err == mgo.ErrNotFound {
+		session.Close()
+		return nil, -1, errors.NotFoundf("blob %q", id)
+	} else if err != nil {
+		session.Close()
+		return nil, -1, errors.Annotatef(err, "cannot get blob %q", id)
+	}
+	r, length, err := ms.GetForBucket(blobsBucket, doc.Path)
+	if err != nil {
+		session.Close()
+		return nil, -1, errors.Annotatef(err, "cannot get blob %q", id)
+	}
+	return &stateStorageReadCloser{r, session}, length, nil
+}
+
+// Release is part of the BlobStore interface.
+func (s blobStore) Release(ref string) error {
+	session, refs, ms := s.open()
+	defer session.Close()
+	return errors.Trace(s.release(refs, ms, ref, ""))
+}
+
+// release removes the named reference from every blob other than the
+// one with the given id, removing the blobs left unreferenced.
+func (s blobStore) release(refs *mgo.Collection, ms blobstore.ManagedStorage, ref, keepId string) error {
+	sel := bson.D{{"refs", ref}}
+	if keepId != "" {
+		sel = append(sel, bson.DocElem{"_id", bson.D{{"$ne", keepId}}})
+	}
+	var docs []blobRefDoc
+	if err := refs.Find(sel).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return errors.Annotatef(err, "cannot find blobs referenced by %q", ref)
+	}
+	for _, doc := range docs {
+		err := refs.UpdateId(doc.Id, bson.D{{"$pull", bson.D{{"refs", ref}}}})
+		if err == mgo.ErrNotFound {
+			continue
+		} else if err != nil {
+			return errors.Annotatef(err, "cannot release blob %q", doc.Id)
+		}
+		if err := s.removeUnreferenced(refs, ms, doc.Id); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// removeUnreferenced removes the blob with the given id if it holds no
+// references. The content is removed before the document recording
+// it, so that removal can be completed by a later call if it is
+// interrupted.
+func (s blobStore) removeUnreferenced(refs *mgo.Collection, ms blobstore.ManagedStorage, id string) error {
+	var doc blobRefDoc
+	err := refs.Find(unreferenced(id)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot get blob %q", id)
+	}
+	err = ms.RemoveForBucket(blobsBucket, doc.Path)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Annotatef(err, "cannot remove content of blob %q", id)
+	}
+	err = refs.Remove(unreferenced(id))
+	if err != nil && err != mgo.ErrNotFound {
+		return errors.Annotatef(err, "cannot remove blob %q", id)
+	}
+	return nil
+}
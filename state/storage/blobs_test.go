@@ -0,0 +1,214 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/storage"
+	"github.com/juju/juju/testing"
+)
+
+type BlobStoreSuite struct {
+	gitjujutesting.MgoSuite
+	testing.BaseSuite
+	blobs storage.BlobStore
+}
+
+var _ = gc.Suite(&BlobStoreSuite{})
+
+func (s *BlobStoreSuite) SetUpSuite(c *gc.C) {
+	s.BaseSuite.SetUpSuite(c)
+	s.MgoSuite.SetUpSuite(c)
+}
+
+func (s *BlobStoreSuite) TearDownSuite(c *gc.C) {
+	s.MgoSuite.TearDownSuite(c)
+	s.BaseSuite.TearDownSuite(c)
+}
+
+func (s *BlobStoreSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.MgoSuite.SetUpTest(c)
+	s.blobs = storage.NewBlobStore(s.Session)
+}
+
+func (s *BlobStoreSuite) TearDownTest(c *gc.C) {
+	s.MgoSuite.TearDownTest(c)
+	s.BaseSuite.TearDownTest(c)
+}
+
+func sha256Of(data string) string {
+	return storage.SHA256BlobID(fmt.Sprintf("%x", sha256.Sum256([]byte(data))))
+}
+
+func (s *BlobStoreSuite) assertBlob(c *gc.C, id, expect string) {
+	r, length, err := s.blobs.Get(id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	c.Assert(length, gc.Equals, int64(len(expect)))
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, expect)
+}
+
+func (s *BlobStoreSuite) TestPutGet(c *gc.C) {
+	id := sha256Of("abc")
+	err := s.blobs.Put(id, "ref", strings.NewReader("abc"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertBlob(c, id, "abc")
+}
+
+func (s *BlobStoreSuite) TestPutSHA384(c *gc.C) {
+	id := storage.SHA384BlobID(fmt.Sprintf("%x", sha512.Sum384([]byte("abc"))))
+	err := s.blobs.Put(id, "ref", strings.NewReader("abc"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertBlob(c, id, "abc")
+}
+
+func (s *BlobStoreSuite) TestPutWrongHash(c *gc.C) {
+	id := sha256Of("abc")
+	err := s.blobs.Put(id, "ref", strings.NewReader("def"), 3)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(`cannot store blob %q: content has hash ".*"`, id))
+	_, _, err = s.blobs.Get(id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *BlobStoreSuite) TestPutInvalidId(c *gc.C) {
+	err := s.blobs.Put("md5/abc", "ref", strings.NewReader("abc"), 3)
+	c.Assert(err, gc.ErrorMatches, `blob id "md5/abc" not valid`)
+}
+
+func (s *BlobStoreSuite) TestPutDeduplicates(c *gc.C) {
+	id := sha256Of("abc")
+	err := s.blobs.Put(id, "ref1", strings.NewReader("abc"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Data for a blob that is already stored is not read.
+	err = s.blobs.Put(id, "ref2", errorReader{}, 3)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertBlob(c, id, "abc")
+}
+
+func (s *BlobStoreSuite) TestPutMovesReference(c *gc.C) {
+	abc, def := sha256Of("abc"), sha256Of("def")
+	err := s.blobs.Put(abc, "ref", strings.NewReader("abc"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.blobs.Put(def, "ref", strings.NewReader("def"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The reference is held only by the blob it was last added to.
+	_, _, err = s.blobs.Get(abc)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertBlob(c, def, "def")
+}
+
+func (s *BlobStoreSuite) TestReleaseRemovesLastReference(c *gc.C) {
+	id := sha256Of("abc")
+	for _, ref := range []string{"ref1", "ref2"} {
+		err := s.blobs.Put(id, ref, strings.NewReader("abc"), 3)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err := s.blobs.Release("ref1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertBlob(c, id, "abc")
+
+	err = s.blobs.Release("ref2")
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.blobs.Get(id)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf("blob %q not found", id))
+
+	// The blob may be stored again.
+	err = s.blobs.Put(id, "ref1", strings.NewReader("abc"), 3)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertBlob(c, id, "abc")
+}
+
+func (s *BlobStoreSuite) TestReleaseIdempotent(c *gc.C) {
+	id := sha256Of("abc")
+	for _, ref := range []string{"ref1", "ref2"} {
+		err := s.blobs.Put(id, ref, strings.NewReader("abc"), 3)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// Retrying a release does not release any other reference.
+	for i := 0; i < 2; i++ {
+		err := s.blobs.Release("ref1")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.assertBlob(c, id, "abc")
+}
+
+func (s *BlobStoreSuite) TestStorageBlobPath(c *gc.C) {
+	id := sha256Of("abc")
+	otherUUID := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+
+	// Blobs are shared by the storage of all models, each of which
+	// holds its own references.
+	path := storage.BlobPath(id, "charms/abc")
+	for _, uuid := range []string{testUUID, otherUUID} {
+		stor := storage.NewStorage(uuid, s.Session)
+		err := stor.Put(path, strings.NewReader("abc"), 3)
+		c.Assert(err, jc.ErrorIsNil)
+		r, length, err := stor.Get(path)
+		c.Assert(err, jc.ErrorIsNil)
+		data, err := ioutil.ReadAll(r)
+		r.Close()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(length, gc.Equals, int64(3))
+		c.Assert(string(data), gc.Equals, "abc")
+	}
+
+	stor := storage.NewStorage(testUUID, s.Session)
+	for i := 0; i < 2; i++ {
+		err := stor.Remove(path)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.assertBlob(c, id, "abc")
+
+	err := storage.NewStorage(otherUUID, s.Session).Remove(path)
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = stor.Get(path)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *BlobStoreSuite) TestStoragePutAndCheckHashBlobPath(c *gc.C) {
+	hash := fmt.Sprintf("%x", sha512.Sum384([]byte("abc")))
+	path := storage.BlobPath(storage.SHA384BlobID(hash), "resources/abc")
+	stor := storage.NewStorage(testUUID, s.Session)
+	err := stor.PutAndCheckHash(path, strings.NewReader("abc"), 3, hash)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertBlob(c, storage.SHA384BlobID(hash), "abc")
+
+	err = stor.PutAndCheckHash(path, strings.NewReader("abc"), 3, "0123")
+	c.Assert(err, gc.ErrorMatches, `blob path ".*" does not match hash "0123"`)
+}
+
+func (s *BlobStoreSuite) TestParseBlobPath(c *gc.C) {
+	id, ref, ok := storage.ParseBlobPath(storage.BlobPath("sha256/abc", "charms/foo-1"))
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(id, gc.Equals, "sha256/abc")
+	c.Assert(ref, gc.Equals, "charms/foo-1")
+
+	for _, path := range []string{"charms/foo-1", "blobs/sha256/abc", "blobs/sha256//ref"} {
+		_, _, ok := storage.ParseBlobPath(path)
+		c.Check(ok, jc.IsFalse, gc.Commentf("%s", path))
+	}
+}
+
+type errorReader struct{}
+
+func (errorReader) Read([]byte) (int, error) {
+	return 0, errors.New("should not be read")
+}
@@ -6,6 +6,7 @@ package storage
 import (
 	"io"
 
+	"github.com/juju/errors"
 	"gopkg.in/juju/blobstore.v2"
 	"gopkg.in/mgo.v2"
 )
@@ -20,6 +21,9 @@ const (
 
 // Storage is an interface providing methods for storing and retrieving
 // data by path.
+//
+// Paths returned by BlobPath refer to data in the controller's
+// BlobStore, through a reference held by the model.
 type Storage interface {
 	// Get returns an io.ReadCloser for data at path, namespaced to the
 	// model.
@@ -58,7 +62,17 @@ func (s stateStorage) blobstore() (*mgo.Session, blobstore.ManagedStorage) {
 	return session, blobstore.NewManagedStorage(db, rs)
 }
 
+// blobRef returns the reference to a blob named by a blob path, which
+// is qualified by the model so that it is distinct from the references
+// held by other models.
+func (s stateStorage) blobRef(ref string) string {
+	return s.modelUUID + ":" + ref
+}
+
 func (s stateStorage) Get(path string) (r io.ReadCloser, length int64, err error) {
+	if id, _, ok := ParseBlobPath(path); ok {
+		return NewBlobStore(s.session).Get(id)
+	}
 	session, ms := s.blobstore()
 	r, length, err = ms.GetForBucket(s.modelUUID, path)
 	if err != nil {
@@ -69,18 +83,32 @@ func (s stateStorage) Get(path string) (r io.ReadCloser, length int64, err error
 }
 
 func (s stateStorage) Put(path string, r io.Reader, length int64) error {
+	if id, ref, ok := ParseBlobPath(path); ok {
+		return NewBlobStore(s.session).Put(id, s.blobRef(ref), r, length)
+	}
 	session, ms := s.blobstore()
 	defer session.Close()
 	return ms.PutForBucket(s.modelUUID, path, r, length)
 }
 
 func (s stateStorage) PutAndCheckHash(path string, r io.Reader, length int64, hash string) error {
+	if id, ref, ok := ParseBlobPath(path); ok {
+		// The hash is the SHA384 hash of the data, which the blob
+		// store checks if the id is made from it.
+		if id != SHA384BlobID(hash) {
+			return errors.Errorf("blob path %q does not match hash %q", path, hash)
+		}
+		return NewBlobStore(s.session).Put(id, s.blobRef(ref), r, length)
+	}
 	session, ms := s.blobstore()
 	defer session.Close()
 	return ms.PutForBucketAndCheckHash(s.modelUUID, path, r, length, hash)
 }
 
 func (s stateStorage) Remove(path string) error {
+	if _, ref, ok := ParseBlobPath(path); ok {
+		return NewBlobStore(s.session).Release(s.blobRef(ref))
+	}
 	session, ms := s.blobstore()
 	defer session.Close()
 	return ms.RemoveForBucket(s.modelUUID, path)
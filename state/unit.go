@@ -87,6 +87,7 @@ type unitDoc struct {
 	StorageAttachmentCount int `bson:"storageattachmentcount"`
 	MachineId              string
 	Resolved               ResolvedMode
+	Suspended              bool         `bson:"suspended,omitempty"`
 	Tools                  *tools.Tools `bson:",omitempty"`
 	Life                   Life
 	TxnRevno               int64 `bson:"txn-revno"`
@@ -115,7 +116,8 @@ func (u *Unit) Application() (*Application, error) {
 // ConfigSettings returns the complete set of service charm config settings
 // available to the unit. Unset values will be replaced with the default
 // value for the associated option, and may thus be nil when no default is
-// specified.
+// specified. A unit tracking a branch of the model sees the config
+// changes staged on the branch.
 func (u *Unit) ConfigSettings() (charm.Settings, error) {
 	if u.doc.CharmURL == nil {
 		return nil, fmt.Errorf("unit charm not set")
@@ -132,6 +134,20 @@ func (u *Unit) ConfigSettings() (charm.Settings, error) {
 	for name, value := range settings.Map() {
 		result[name] = value
 	}
+	// A unit tracking a branch sees the config staged on it.
+	branch, err := unitBranch(u.st.db(), u.doc.Application, u.doc.Name)
+	if errors.IsNotFound(err) {
+		return result, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defaults := chrm.Config().DefaultSettings()
+	for name, value := range branch.Config[u.doc.Application] {
+		if value == nil {
+			value = defaults[name]
+		}
+		result[name] = value
+	}
 	return result, nil
 }
 
@@ -348,6 +364,9 @@ func (u *Unit) eraseHistory() error {
 	if err := eraseStatusHistory(u.st, u.globalWorkloadVersionKey()); err != nil {
 		return errors.Annotate(err, "version")
 	}
+	if err := eraseStatusHistory(u.st, statusAnnotationKey(u.globalKey())); err != nil {
+		return errors.Annotate(err, "annotations")
+	}
 	return nil
 }
 
@@ -668,6 +687,13 @@ func (u *Unit) Resolved() ResolvedMode {
 	return u.doc.Resolved
 }
 
+// Suspended returns whether the unit has been suspended by an operator.
+// The agent of a suspended unit does not run any hooks until the unit is
+// resumed.
+func (u *Unit) Suspended() bool {
+	return u.doc.Suspended
+}
+
 // IsPrincipal returns whether the unit is deployed in its own container,
 // and can therefore have subordinate services deployed alongside it.
 func (u *Unit) IsPrincipal() bool {
@@ -971,6 +997,57 @@ func (u *Unit) ClosePortsOnSubnet(subnetID, protocol string, fromPort, toPort in
 	return machinePorts.ClosePorts(ports)
 }
 
+// OpenClosePortsOnSubnet closes and then opens the given port ranges for
+// the unit on the given subnet, which can be empty, in a single
+// transaction: if any of the changes cannot be made, none of them are.
+// When non-empty, subnetID must refer to an existing, alive subnet,
+// otherwise an error is returned.
+func (u *Unit) OpenClosePortsOnSubnet(subnetID string, openRanges, closeRanges []network.PortRange) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot open and close ports for unit %q on subnet %q", u, subnetID)
+
+	unitRanges := func(portRanges []network.PortRange) ([]PortRange, error) {
+		result := make([]PortRange, len(portRanges))
+		for i, pr := range portRanges {
+			ports, err := NewPortRange(u.Name(), pr.FromPort, pr.ToPort, pr.Protocol)
+			if err != nil {
+				return nil, errors.Annotatef(err, "invalid port range %v", pr)
+			}
+			result[i] = ports
+		}
+		return result, nil
+	}
+	openPorts, err := unitRanges(openRanges)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	closePorts, err := unitRanges(closeRanges)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	machineID, err := u.AssignedMachineId()
+	if err != nil {
+		return errors.Annotatef(err, "unit %q has no assigned machine", u)
+	}
+
+	if err := u.checkSubnetAliveWhenSet(subnetID); err != nil {
+		return errors.Trace(err)
+	}
+
+	machinePorts, err := getOrCreatePorts(u.st, machineID, subnetID)
+	if err != nil {
+		return errors.Annotate(err, "cannot get or create ports")
+	}
+
+	return machinePorts.OpenClosePorts(openPorts, closePorts)
+}
+
+// OpenClosePorts closes and then opens the given port ranges for the
+// unit, on no particular subnet, in a single transaction.
+func (u *Unit) OpenClosePorts(openRanges, closeRanges []network.PortRange) error {
+	return u.OpenClosePortsOnSubnet("", openRanges, closeRanges)
+}
+
 // OpenPorts opens the given port range and protocol for the unit, if it does
 // not conflict with another already opened range on the unit's assigned
 // machine.
@@ -2330,6 +2407,26 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (Action, error) {
+	payloadWithDefaults, err := u.actionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+}
+
+// AddTask is like AddAction, but adds the action as a task of the
+// operation with the given id.
+func (u *Unit) AddTask(operationID, name string, payload map[string]interface{}) (Action, error) {
+	payloadWithDefaults, err := u.actionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	return u.st.EnqueueTask(operationID, u.Tag(), name, payloadWithDefaults)
+}
+
+// actionPayload validates the payload of the named action against the
+// action's spec, and returns it with the spec's defaults inserted.
+func (u *Unit) actionPayload(name string, payload map[string]interface{}) (map[string]interface{}, error) {
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
@@ -2351,11 +2448,7 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (Action, e
 	if err != nil {
 		return nil, err
 	}
-	payloadWithDefaults, err := spec.InsertDefaults(payload)
-	if err != nil {
-		return nil, err
-	}
-	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+	return spec.InsertDefaults(payload)
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
@@ -2479,6 +2572,30 @@ func (u *Unit) ClearResolved() error {
 	return nil
 }
 
+// SetSuspended suspends or resumes the unit. Suspending a unit that is
+// already suspended, or resuming one that is not, has no effect.
+func (u *Unit) SetSuspended(suspended bool) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set suspended for unit %q", u)
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"suspended", suspended}}}},
+	}}
+	if err := u.st.db().RunTransaction(ops); err == nil {
+		u.doc.Suspended = suspended
+		return nil
+	} else if err != txn.ErrAborted {
+		return err
+	}
+	if ok, err := isNotDead(u.st, unitsC, u.doc.DocID); err != nil {
+		return err
+	} else if !ok {
+		return ErrDead
+	}
+	return errors.NotFoundf("unit")
+}
+
 // StorageConstraints returns the unit's storage constraints.
 func (u *Unit) StorageConstraints() (map[string]StorageConstraints, error) {
 	if u.doc.CharmURL == nil {
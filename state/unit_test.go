@@ -1101,6 +1101,33 @@ func (s *UnitSuite) TestGetSetClearResolved(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot set resolved mode for unit "wordpress/0": invalid error resolution mode: "foo"`)
 }
 
+func (s *UnitSuite) TestSetSuspended(c *gc.C) {
+	c.Assert(s.unit.Suspended(), jc.IsFalse)
+
+	err := s.unit.SetSuspended(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.Suspended(), jc.IsTrue)
+	err = s.unit.SetSuspended(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Suspended(), jc.IsTrue)
+
+	err = s.unit.SetSuspended(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Suspended(), jc.IsFalse)
+}
+
+func (s *UnitSuite) TestSetSuspendedDead(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetSuspended(true)
+	c.Assert(err, gc.ErrorMatches, `cannot set suspended for unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestOpenedPortsOnInvalidSubnet(c *gc.C) {
 	s.testOpenedPorts(c, "bad CIDR", `invalid subnet ID "bad CIDR"`)
 }
@@ -68,7 +68,7 @@ func (u *UnitAgent) SetStatus(unitAgentStatus status.StatusInfo) (err error) {
 	isPrincipal := unit.doc.Principal == ""
 
 	switch unitAgentStatus.Status {
-	case status.Idle, status.Executing, status.Rebooting, status.Failed:
+	case status.Idle, status.Executing, status.Rebooting, status.Failed, status.Suspended:
 		if !isAssigned && isPrincipal {
 			return errors.Errorf("cannot set status %q until unit is assigned", unitAgentStatus.Status)
 		}
@@ -0,0 +1,143 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// unitSecretsDoc holds the secrets stored by a unit's charm, keyed on
+// secret name. Secrets are kept apart from relation settings and
+// application config, so that they are only ever revealed to the unit
+// that stored them.
+type unitSecretsDoc struct {
+	DocID     string            `bson:"_id"`
+	ModelUUID string            `bson:"model-uuid"`
+	Unit      string            `bson:"unit"`
+	Secrets   map[string]string `bson:"secrets"`
+}
+
+// validSecretName matches valid secret names. Names are used as field
+// names in the secrets document, so must not contain "." or "$".
+var validSecretName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// Secrets returns the secrets stored by the unit's charm.
+func (u *Unit) Secrets() (map[string]string, error) {
+	doc, err := getUnitSecrets(u.st.db(), u.doc.Name)
+	if errors.IsNotFound(err) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if doc.Secrets == nil {
+		return map[string]string{}, nil
+	}
+	return doc.Secrets, nil
+}
+
+// UpdateSecrets stores the supplied secrets on behalf of the unit's
+// charm, leaving any other secrets it has stored untouched. Secrets
+// with empty values are removed.
+func (u *Unit) UpdateSecrets(secrets map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot update secrets for unit %q", u.doc.Name)
+	for name := range secrets {
+		if !validSecretName.MatchString(name) {
+			return errors.NotValidf("secret name %q", name)
+		}
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+	db := u.st.db()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); errors.IsNotFound(err) {
+				return nil, errors.New("unit not found")
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if u.Life() != Alive {
+				return nil, errors.New("unit is not alive")
+			}
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: isAliveDoc,
+		}}
+		_, err := getUnitSecrets(db, u.doc.Name)
+		if errors.IsNotFound(err) {
+			// Most charms never store secrets, so the document
+			// is created on demand.
+			values := make(map[string]string)
+			for name, value := range secrets {
+				if value != "" {
+					values[name] = value
+				}
+			}
+			ops = append(ops, txn.Op{
+				C:      unitSecretsC,
+				Id:     u.doc.Name,
+				Assert: txn.DocMissing,
+				Insert: &unitSecretsDoc{
+					Unit:    u.doc.Name,
+					Secrets: values,
+				},
+			})
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		} else {
+			var set, unset bson.D
+			for name, value := range secrets {
+				if value == "" {
+					unset = append(unset, bson.DocElem{"secrets." + name, 1})
+				} else {
+					set = append(set, bson.DocElem{"secrets." + name, value})
+				}
+			}
+			var update bson.D
+			if len(set) > 0 {
+				update = append(update, bson.DocElem{"$set", set})
+			}
+			if len(unset) > 0 {
+				update = append(update, bson.DocElem{"$unset", unset})
+			}
+			ops = append(ops, txn.Op{
+				C:      unitSecretsC,
+				Id:     u.doc.Name,
+				Assert: txn.DocExists,
+				Update: update,
+			})
+		}
+		return ops, nil
+	}
+	return db.Run(buildTxn)
+}
+
+func getUnitSecrets(db Database, unitName string) (*unitSecretsDoc, error) {
+	coll, cleanup := db.GetCollection(unitSecretsC)
+	defer cleanup()
+
+	var doc unitSecretsDoc
+	err := coll.FindId(unitName).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("secrets for unit %q", unitName)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get unit secrets")
+	}
+	return &doc, nil
+}
+
+func removeUnitSecretsOp(unitName string) txn.Op {
+	return txn.Op{
+		C:      unitSecretsC,
+		Id:     unitName,
+		Remove: true,
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type UnitSecretsSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&UnitSecretsSuite{})
+
+func (s *UnitSecretsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.unit = s.Factory.MakeUnit(c, nil)
+}
+
+func (s *UnitSecretsSuite) TestSecretsNoneStored(c *gc.C) {
+	secrets, err := s.unit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 0)
+}
+
+func (s *UnitSecretsSuite) TestUpdateSecrets(c *gc.C) {
+	err := s.unit.UpdateSecrets(map[string]string{
+		"api-key":  "s3kr1t",
+		"password": "hunter2",
+		"unused":   "",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	secrets, err := s.unit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, jc.DeepEquals, map[string]string{
+		"api-key":  "s3kr1t",
+		"password": "hunter2",
+	})
+
+	err = s.unit.UpdateSecrets(map[string]string{
+		"api-key":  "",
+		"password": "correct horse",
+		"token":    "abc",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	secrets, err = s.unit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, jc.DeepEquals, map[string]string{
+		"password": "correct horse",
+		"token":    "abc",
+	})
+}
+
+func (s *UnitSecretsSuite) TestUpdateSecretsInvalidName(c *gc.C) {
+	err := s.unit.UpdateSecrets(map[string]string{"a.b": "c"})
+	c.Assert(err, gc.ErrorMatches, `cannot update secrets for unit "mysql/0": secret name "a.b" not valid`)
+}
+
+func (s *UnitSecretsSuite) TestUpdateSecretsUnitNotAlive(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.UpdateSecrets(map[string]string{"password": "hunter2"})
+	c.Assert(err, gc.ErrorMatches, `cannot update secrets for unit "mysql/0": unit is not alive`)
+}
+
+func (s *UnitSecretsSuite) TestSecretsRemovedWithUnit(c *gc.C) {
+	err := s.unit.UpdateSecrets(map[string]string{"password": "hunter2"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	secrets, err := s.unit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 0)
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/version"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// upgradeStepDoc records that an agent completed an upgrade step. Its
+// presence lets the upgrade be retried without repeating the step.
+type upgradeStepDoc struct {
+	DocID       string    `bson:"_id"`
+	AgentTag    string    `bson:"agent-tag"`
+	Version     string    `bson:"version"`
+	Description string    `bson:"description"`
+	Completed   time.Time `bson:"completed"`
+}
+
+func upgradeStepDocID(agentTag string, vers version.Number, description string) string {
+	return fmt.Sprintf("%s#%s#%s", agentTag, vers, description)
+}
+
+// UpgradeStepCompleted reports whether the agent with the given tag has
+// recorded the described upgrade step, belonging to the upgrade to the
+// given version, as completed.
+func (st *State) UpgradeStepCompleted(agentTag string, vers version.Number, description string) (bool, error) {
+	coll, closer := st.db().GetCollection(upgradeStepsC)
+	defer closer()
+
+	n, err := coll.FindId(upgradeStepDocID(agentTag, vers, description)).Count()
+	if err != nil {
+		return false, errors.Annotatef(err, "cannot read upgrade step %q", description)
+	}
+	return n > 0, nil
+}
+
+// SetUpgradeStepCompleted records that the agent with the given tag has
+// completed the described upgrade step, belonging to the upgrade to the
+// given version. Recording a step more than once is not an error.
+func (st *State) SetUpgradeStepCompleted(agentTag string, vers version.Number, description string) error {
+	doc := upgradeStepDoc{
+		DocID:       upgradeStepDocID(agentTag, vers, description),
+		AgentTag:    agentTag,
+		Version:     vers.String(),
+		Description: description,
+		Completed:   st.clock().Now(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		done, err := st.UpgradeStepCompleted(agentTag, vers, description)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if done {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      upgradeStepsC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot record upgrade step %q", description)
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type UpgradeStepsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UpgradeStepsSuite{})
+
+func (s *UpgradeStepsSuite) TestNotCompleted(c *gc.C) {
+	done, err := s.State.UpgradeStepCompleted("machine-0", version.MustParse("2.2.0"), "add foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsFalse)
+}
+
+func (s *UpgradeStepsSuite) TestSetCompleted(c *gc.C) {
+	vers := version.MustParse("2.2.0")
+	err := s.State.SetUpgradeStepCompleted("machine-0", vers, "add foo")
+	c.Assert(err, jc.ErrorIsNil)
+
+	done, err := s.State.UpgradeStepCompleted("machine-0", vers, "add foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(done, jc.IsTrue)
+
+	// Completion is recorded per agent, version and step.
+	for _, other := range []struct {
+		tag         string
+		vers        version.Number
+		description string
+	}{
+		{"machine-1", vers, "add foo"},
+		{"machine-0", version.MustParse("2.2.1"), "add foo"},
+		{"machine-0", vers, "add bar"},
+	} {
+		done, err := s.State.UpgradeStepCompleted(other.tag, other.vers, other.description)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(done, jc.IsFalse)
+	}
+}
+
+func (s *UpgradeStepsSuite) TestSetCompletedIdempotent(c *gc.C) {
+	vers := version.MustParse("2.2.0")
+	err := s.State.SetUpgradeStepCompleted("machine-0", vers, "add foo")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetUpgradeStepCompleted("machine-0", vers, "add foo")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -1604,14 +1604,135 @@ func (im *IAASModel) WatchFilesystemAttachment(m names.MachineTag, f names.Files
 // unit's service configuration settings. The unit must have a charm URL
 // set before this method is called, and the returned watcher will be
 // valid only while the unit's charm URL is not changed.
-// TODO(fwereade): this could be much smarter; if it were, uniter.Filter
-// could be somewhat simpler.
+//
+// Changes staged on a branch of the model are seen only by the units
+// tracking the branch, so that config-changed runs only on those units
+// until the branch is committed.
 func (u *Unit) WatchConfigSettings() (NotifyWatcher, error) {
 	if u.doc.CharmURL == nil {
 		return nil, fmt.Errorf("unit charm not set")
 	}
-	settingsKey := applicationSettingsKey(u.doc.Application, u.doc.CharmURL)
-	return newEntityWatcher(u.st, settingsC, u.st.docID(settingsKey)), nil
+	return newUnitConfigWatcher(u), nil
+}
+
+// unitConfigWatcher notifies of changes to the config settings seen by
+// a unit, which change with the application's settings and with the
+// branches of the model.
+type unitConfigWatcher struct {
+	commonWatcher
+	unit *Unit
+	out  chan struct{}
+}
+
+func newUnitConfigWatcher(u *Unit) NotifyWatcher {
+	unit := *u
+	w := &unitConfigWatcher{
+		commonWatcher: newCommonWatcher(u.st),
+		unit:          &unit,
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for the unitConfigWatcher.
+func (w *unitConfigWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *unitConfigWatcher) loop() error {
+	in := make(chan watcher.Change)
+	settingsKey := w.unit.st.docID(applicationSettingsKey(w.unit.doc.Application, w.unit.doc.CharmURL))
+	settings, closer := w.db.GetCollection(settingsC)
+	txnRevno, err := getTxnRevno(settings, settingsKey)
+	closer()
+	if err != nil {
+		return err
+	}
+	w.watcher.Watch(settingsC, settingsKey, txnRevno, in)
+	defer w.watcher.Unwatch(settingsC, settingsKey, in)
+	branchCh := make(chan watcher.Change)
+	w.watcher.WatchCollectionWithFilter(generationsC, branchCh, isLocalID(w.backend))
+	defer w.watcher.UnwatchCollection(generationsC, branchCh)
+
+	last, err := w.settings()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.watcher.Dead():
+			return stateWatcherDeadError(w.watcher.Err())
+		case ch := <-in:
+			if _, ok := collect(ch, in, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+		case ch := <-branchCh:
+			ids, ok := collect(ch, branchCh, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			// Only branches tracked by units of this unit's
+			// application can change what it sees.
+			if affected, err := w.branchesAffectApplication(ids); err != nil {
+				return errors.Trace(err)
+			} else if !affected {
+				continue
+			}
+		case out <- struct{}{}:
+			out = nil
+			continue
+		}
+		// Branch changes may not affect this unit, so only report
+		// those that change what it sees.
+		current, err := w.settings()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !reflect.DeepEqual(current, last) {
+			last = current
+			out = w.out
+		}
+	}
+}
+
+// branchesAffectApplication reports whether any of the branches with
+// the given document ids is tracked by units of the watched unit's
+// application.
+func (w *unitConfigWatcher) branchesAffectApplication(ids map[interface{}]bool) (bool, error) {
+	docIDs := make([]string, 0, len(ids))
+	for id := range ids {
+		if docID, ok := id.(string); ok {
+			docIDs = append(docIDs, docID)
+		}
+	}
+	coll, closer := w.db.GetCollection(generationsC)
+	defer closer()
+	count, err := coll.Find(bson.D{
+		{"_id", bson.D{{"$in", docIDs}}},
+		{"assigned-units." + w.unit.doc.Application + ".0", bson.D{{"$exists", true}}},
+	}).Count()
+	if err != nil {
+		return false, errors.Annotate(err, "cannot read branches")
+	}
+	return count > 0, nil
+}
+
+// settings returns the config settings seen by the unit, or nil if the
+// watched settings have been removed.
+func (w *unitConfigWatcher) settings() (charm.Settings, error) {
+	settings, err := w.unit.ConfigSettings()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return settings, err
 }
 
 // WatchMeterStatus returns a watcher observing changes that affect the meter status
@@ -62,7 +62,7 @@ func newWorkers(st *State) (*workers, error) {
 		}
 		manager, err := lease.NewManager(lease.ManagerConfig{
 			Secretary: leadershipSecretary{},
-			Client:    client,
+			Store:     client,
 			Clock:     ws.state.clock(),
 			MaxSleep:  time.Minute,
 		})
@@ -78,7 +78,7 @@ func newWorkers(st *State) (*workers, error) {
 		}
 		manager, err := lease.NewManager(lease.ManagerConfig{
 			Secretary: singularSecretary{st.ModelUUID()},
-			Client:    client,
+			Store:     client,
 			Clock:     st.clock(),
 			MaxSleep:  time.Minute,
 		})
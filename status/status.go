@@ -101,6 +101,11 @@ const (
 	// error (e.g it loses contact with the Juju server) moves it to a different state.
 	Idle Status = "idle"
 
+	// Suspended is set when:
+	// An operator has suspended the unit. The agent finishes whatever it is
+	// doing and then runs no more hooks or actions until the unit is resumed.
+	Suspended Status = "suspended"
+
 	// Failed is set when:
 	// The unit agent has failed in some way,eg the agent ought to be signalling
 	// activity, but it cannot be detected. It might also be that the unit agent
@@ -231,7 +236,8 @@ func (status Status) KnownAgentStatus() bool {
 		Failed,
 		Rebooting,
 		Executing,
-		Idle:
+		Idle,
+		Suspended:
 		return true
 	}
 	return false
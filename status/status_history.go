@@ -170,6 +170,16 @@ const (
 	KindContainer HistoryKind = "juju-container"
 )
 
+// KindAnnotation marks a note that a user has added to an entity's
+// status history. It is not a kind of history that can be requested
+// on its own: annotations are included in the history of every kind
+// reported for an entity.
+const KindAnnotation HistoryKind = "annotation"
+
+// AnnotationAuthorKey is the key in the Data of a status annotation
+// that holds the name of the user who added it.
+const AnnotationAuthorKey = "author"
+
 // String returns a string representation of the HistoryKind.
 func (k HistoryKind) String() string {
 	return string(k)
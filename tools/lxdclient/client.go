@@ -106,6 +106,7 @@ type Client struct {
 	*imageClient
 	*networkClient
 	*storageClient
+	*resourcesClient
 	baseURL                  string
 	defaultProfileBridgeName string
 }
@@ -134,6 +135,8 @@ func Connect(cfg Config, verifyBridgeConfig bool) (*Client, error) {
 
 	networkAPISupported := false
 	storageAPISupported := false
+	proxySupported := false
+	resourcesSupported := false
 	var defaultProfile *api.Profile
 	if cfg.Remote.Protocol != SimplestreamsProtocol {
 		status, err := raw.ServerStatus()
@@ -149,6 +152,15 @@ func Connect(cfg Config, verifyBridgeConfig bool) (*Client, error) {
 			storageAPISupported = true
 		}
 
+		if lxdshared.StringInSlice("container_proxy", status.APIExtensions) {
+			proxySupported = true
+		}
+
+		if lxdshared.StringInSlice("resources_gpu", status.APIExtensions) &&
+			lxdshared.StringInSlice("resources_usb_pci", status.APIExtensions) {
+			resourcesSupported = true
+		}
+
 		defaultProfile, err = raw.ProfileConfig("default")
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -174,14 +186,19 @@ func Connect(cfg Config, verifyBridgeConfig bool) (*Client, error) {
 		}
 	}
 
+	// The devices of the host can be read from sysfs only if LXD is
+	// running on this machine.
+	isLocal := remoteID == remoteIDForLocal && cfg.Remote.Host == ""
+
 	conn := &Client{
 		configClient:             &configClient{raw},
 		certClient:               &certClient{raw},
 		profileClient:            &profileClient{raw},
-		instanceClient:           &instanceClient{raw, remoteID},
+		instanceClient:           &instanceClient{raw, remoteID, proxySupported},
 		imageClient:              &imageClient{raw, connectToRaw},
 		networkClient:            &networkClient{raw, networkAPISupported},
 		storageClient:            &storageClient{raw, storageAPISupported},
+		resourcesClient:          &resourcesClient{lxdResourcesClient{raw}, isLocal, resourcesSupported},
 		baseURL:                  raw.BaseURL,
 		defaultProfileBridgeName: bridgeName,
 	}
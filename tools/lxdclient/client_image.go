@@ -7,7 +7,9 @@ package lxdclient
 
 import (
 	"fmt"
+	"net/http"
 	"path"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -23,6 +25,10 @@ import (
 type rawImageClient interface {
 	GetAlias(string) string
 	GetImageInfo(string) (*api.Image, error)
+	ListImages() ([]api.Image, error)
+	DeleteImage(string) error
+	PostAlias(alias, desc, target string) error
+	DeleteAlias(string) error
 }
 
 type remoteClient interface {
@@ -30,7 +36,7 @@ type remoteClient interface {
 	GetAlias(name string) string
 	// This is like lxd.Client.CopyImage() but simplified and allows us to
 	// inject a testing double.
-	CopyImage(imageTarget string, dest rawImageClient, aliases []string, callback func(string)) error
+	CopyImage(imageTarget string, dest rawImageClient, aliases []string, autoUpdate bool, callback func(string)) error
 }
 
 type imageClient struct {
@@ -46,7 +52,7 @@ func (r rawWrapper) URL() string {
 	return r.Client.BaseURL
 }
 
-func (r rawWrapper) CopyImage(imageTarget string, dest rawImageClient, aliases []string, callback func(string)) error {
+func (r rawWrapper) CopyImage(imageTarget string, dest rawImageClient, aliases []string, autoUpdate bool, callback func(string)) error {
 	rawDest, ok := dest.(*lxd.Client)
 	if !ok {
 		return errors.Errorf("can only copy images to a real lxd.Client instance")
@@ -57,7 +63,7 @@ func (r rawWrapper) CopyImage(imageTarget string, dest rawImageClient, aliases [
 		false,   // copy_aliases
 		aliases, // create these aliases
 		false,   // make the image public
-		true,    // autoUpdate,
+		autoUpdate,
 		callback,
 	)
 }
@@ -179,10 +185,118 @@ func (i *imageClient) ensureImage(
 		context: fmt.Sprintf("copying image for %s from %s: %%s", imageName, source.URL()),
 		forward: forwarder.Forward,
 	}
-	err := source.CopyImage(alias, i.raw, []string{imageName}, adapter.copyProgress)
+	err := source.CopyImage(alias, i.raw, []string{imageName}, true, adapter.copyProgress)
 	return errors.Annotatef(err, "unable to get LXD image for %s", imageName)
 }
 
+// EnsureImage makes sure the local image cache holds the image with the
+// given fingerprint, downloading it from source if it does not, and
+// that alias refers to it. Unlike EnsureImageExists, the image is
+// pinned: it is never auto-updated, and an image cached under the alias
+// with a different fingerprint is not used. The fingerprint may be
+// abbreviated, as with the lxc command.
+func (i *imageClient) EnsureImage(
+	alias, fingerprint string,
+	source Remote,
+	copyProgressHandler func(string),
+) error {
+	if fingerprint == "" {
+		return errors.NotValidf("empty fingerprint for image %q", alias)
+	}
+	info, err := i.imageInfo(fingerprint)
+	if errors.IsNotFound(err) {
+		if info, err = i.copyImage(fingerprint, source, copyProgressHandler); err != nil {
+			return errors.Annotatef(err, "unable to get LXD image %q", alias)
+		}
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if !strings.HasPrefix(info.Fingerprint, fingerprint) {
+		return errors.Errorf("LXD image %q has fingerprint %q, expected %q", alias, info.Fingerprint, fingerprint)
+	}
+	return errors.Trace(i.setAlias(alias, info.Fingerprint))
+}
+
+// copyImage copies the image with the given fingerprint from source to
+// the local image cache, and returns the cached image's details.
+func (i *imageClient) copyImage(fingerprint string, source Remote, copyProgressHandler func(string)) (*api.Image, error) {
+	remote, err := i.connectToSource(source)
+	if err != nil {
+		return nil, errors.Annotatef(err, "connecting to %q", source.Host)
+	}
+	forwarder := stringforwarder.New(copyProgressHandler)
+	defer func() {
+		dropCount := forwarder.Stop()
+		logger.Debugf("dropped %d progress messages", dropCount)
+	}()
+	adapter := &progressContext{
+		logger:  logger,
+		level:   loggo.INFO,
+		context: fmt.Sprintf("copying image %s from %s: %%s", fingerprint, remote.URL()),
+		forward: forwarder.Forward,
+	}
+	if err := remote.CopyImage(fingerprint, i.raw, nil, false, adapter.copyProgress); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// LXD checks the fingerprint of the image it downloads, but make
+	// sure the image we asked for is the one that is now cached.
+	info, err := i.imageInfo(fingerprint)
+	if errors.IsNotFound(err) {
+		return nil, errors.Errorf("image copied from %s does not have fingerprint %q", remote.URL(), fingerprint)
+	}
+	return info, errors.Trace(err)
+}
+
+// setAlias makes alias refer to the image with the given fingerprint,
+// replacing any existing alias with the same name.
+func (i *imageClient) setAlias(alias, fingerprint string) error {
+	target := i.raw.GetAlias(alias)
+	if target == fingerprint {
+		return nil
+	}
+	if target != "" {
+		logger.Infof("moving LXD image alias %q from %q to %q", alias, target, fingerprint)
+		if err := i.raw.DeleteAlias(alias); err != nil {
+			return errors.Annotatef(err, "removing alias %q", alias)
+		}
+	}
+	err := i.raw.PostAlias(alias, "juju pinned image", fingerprint)
+	return errors.Annotatef(err, "adding alias %q", alias)
+}
+
+func (i *imageClient) imageInfo(fingerprint string) (*api.Image, error) {
+	info, err := i.raw.GetImageInfo(fingerprint)
+	if err != nil {
+		if err == lxd.LXDErrors[http.StatusNotFound] {
+			return nil, errors.NotFoundf("image %q", fingerprint)
+		}
+		return nil, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// ListImages returns the images in the local image cache.
+func (i *imageClient) ListImages() ([]api.Image, error) {
+	images, err := i.raw.ListImages()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return images, nil
+}
+
+// DeleteImage removes the image with the given fingerprint, and any
+// aliases referring to it, from the local image cache. If there is no
+// such image, an error satisfying errors.IsNotFound is returned.
+func (i *imageClient) DeleteImage(fingerprint string) error {
+	if err := i.raw.DeleteImage(fingerprint); err != nil {
+		if err == lxd.LXDErrors[http.StatusNotFound] {
+			return errors.NotFoundf("image %q", fingerprint)
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 // seriesLocalAlias returns the alias to assign to images for the
 // specified series. The alias is juju-specific, to support the
 // user supplying a customised image (e.g. CentOS with cloud-init).
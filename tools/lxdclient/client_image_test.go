@@ -7,11 +7,15 @@ package lxdclient
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/lxc/lxd"
+	"github.com/lxc/lxd/shared/api"
 	gc "gopkg.in/check.v1"
 
 	coretesting "github.com/juju/juju/testing"
@@ -47,6 +51,7 @@ type stubRemoteClient struct {
 	stub    *testing.Stub
 	url     string
 	aliases map[string]string
+	images  []api.Image
 }
 
 var _ remoteClient = (*stubRemoteClient)(nil)
@@ -67,10 +72,10 @@ func (s *stubRemoteClient) GetAlias(alias string) string {
 	return s.aliases[alias]
 }
 
-func (s *stubRemoteClient) CopyImage(imageTarget string, dest rawImageClient, aliases []string, callback func(string)) error {
+func (s *stubRemoteClient) CopyImage(imageTarget string, dest rawImageClient, aliases []string, autoUpdate bool, callback func(string)) error {
 	// We don't include the destination or the callback because they aren't
 	// objects we can easily assert against.
-	s.stub.AddCall("CopyImage", imageTarget, aliases)
+	s.stub.AddCall("CopyImage", imageTarget, aliases, autoUpdate)
 	if err := s.stub.NextErr(); err != nil {
 		return err
 	}
@@ -91,6 +96,11 @@ func (s *stubRemoteClient) CopyImage(imageTarget string, dest rawImageClient, al
 		for _, alias := range aliases {
 			stubDest.Aliases[alias] = imageTarget
 		}
+		for _, image := range s.images {
+			if strings.HasPrefix(image.Fingerprint, imageTarget) {
+				stubDest.Images = append(stubDest.Images, image)
+			}
+		}
 	}
 	return nil
 }
@@ -185,7 +195,7 @@ func (s *imageSuite) TestEnsureImageExistsFirstRemote(c *gc.C) {
 		},
 		{ // So Copy the Image
 			FuncName: "CopyImage",
-			Args:     []interface{}{"trusty/amd64", []string{"juju/trusty/amd64"}},
+			Args:     []interface{}{"trusty/amd64", []string{"juju/trusty/amd64"}, true},
 		},
 	})
 	// We've updated the aliases
@@ -234,7 +244,7 @@ func (s *imageSuite) TestEnsureImageExistsUnableToConnect(c *gc.C) {
 		},
 		{ // So Copy the Image
 			FuncName: "CopyImage",
-			Args:     []interface{}{"trusty/amd64", []string{"juju/trusty/amd64"}},
+			Args:     []interface{}{"trusty/amd64", []string{"juju/trusty/amd64"}, true},
 		},
 	})
 	// We've updated the aliases
@@ -282,7 +292,7 @@ func (s *imageSuite) TestEnsureImageExistsNotPresentInFirstRemote(c *gc.C) {
 		},
 		{ // So Copy the Image
 			FuncName: "CopyImage",
-			Args:     []interface{}{"trusty/amd64", []string{"juju/trusty/amd64"}},
+			Args:     []interface{}{"trusty/amd64", []string{"juju/trusty/amd64"}, true},
 		},
 	})
 	// We've updated the aliases
@@ -321,3 +331,100 @@ func (s *imageSuite) TestEnsureImageExistsCallbackIncludesSourceURL(c *gc.C) {
 		c.Fatalf("no messages received")
 	}
 }
+
+const pinnedFingerprint = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func (s *imageSuite) TestEnsureImageCached(c *gc.C) {
+	connector := MakeConnector(s.Stub, s.remoteWithTrusty)
+	raw := &stubClient{
+		stub:   s.Stub,
+		Images: []api.Image{{Fingerprint: pinnedFingerprint}},
+	}
+	client := &imageClient{
+		raw:             raw,
+		connectToSource: connector.connectToSource,
+	}
+	err := client.EnsureImage("juju/xenial/amd64", "0123456789ab", s.remoteWithTrusty.AsRemote(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{FuncName: "GetImageInfo", Args: []interface{}{"0123456789ab"}},
+		{FuncName: "GetAlias", Args: []interface{}{"juju/xenial/amd64"}},
+		{FuncName: "PostAlias", Args: []interface{}{"juju/xenial/amd64", "juju pinned image", pinnedFingerprint}},
+	})
+}
+
+func (s *imageSuite) TestEnsureImageMovesAlias(c *gc.C) {
+	connector := MakeConnector(s.Stub, s.remoteWithTrusty)
+	raw := &stubClient{
+		stub:    s.Stub,
+		Aliases: map[string]string{"juju/xenial/amd64": "fedcba"},
+		Images:  []api.Image{{Fingerprint: pinnedFingerprint}},
+	}
+	client := &imageClient{
+		raw:             raw,
+		connectToSource: connector.connectToSource,
+	}
+	err := client.EnsureImage("juju/xenial/amd64", pinnedFingerprint, s.remoteWithTrusty.AsRemote(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.CheckCallNames(c, "GetImageInfo", "GetAlias", "DeleteAlias", "PostAlias")
+	c.Assert(raw.Aliases, jc.DeepEquals, map[string]string{"juju/xenial/amd64": pinnedFingerprint})
+}
+
+func (s *imageSuite) TestEnsureImageDownloads(c *gc.C) {
+	s.remoteWithTrusty.images = []api.Image{{Fingerprint: pinnedFingerprint}}
+	connector := MakeConnector(s.Stub, s.remoteWithTrusty)
+	raw := &stubClient{stub: s.Stub}
+	client := &imageClient{
+		raw:             raw,
+		connectToSource: connector.connectToSource,
+	}
+	err := client.EnsureImage("juju/xenial/amd64", pinnedFingerprint, s.remoteWithTrusty.AsRemote(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{FuncName: "GetImageInfo", Args: []interface{}{pinnedFingerprint}},
+		{FuncName: "connectToSource", Args: []interface{}{"https://match"}},
+		// The image is copied by fingerprint, and not auto-updated.
+		{FuncName: "CopyImage", Args: []interface{}{pinnedFingerprint, []string(nil), false}},
+		{FuncName: "GetImageInfo", Args: []interface{}{pinnedFingerprint}},
+		{FuncName: "GetAlias", Args: []interface{}{"juju/xenial/amd64"}},
+		{FuncName: "PostAlias", Args: []interface{}{"juju/xenial/amd64", "juju pinned image", pinnedFingerprint}},
+	})
+}
+
+func (s *imageSuite) TestEnsureImageNotCopied(c *gc.C) {
+	connector := MakeConnector(s.Stub, s.remoteWithTrusty)
+	raw := &stubClient{stub: s.Stub}
+	client := &imageClient{
+		raw:             raw,
+		connectToSource: connector.connectToSource,
+	}
+	err := client.EnsureImage("juju/xenial/amd64", pinnedFingerprint, s.remoteWithTrusty.AsRemote(), nil)
+	c.Assert(err, gc.ErrorMatches, `unable to get LXD image "juju/xenial/amd64": image copied from https://match does not have fingerprint "0123.*"`)
+	c.Assert(raw.Aliases, gc.HasLen, 0)
+}
+
+func (s *imageSuite) TestEnsureImageEmptyFingerprint(c *gc.C) {
+	client := &imageClient{raw: &stubClient{stub: s.Stub}}
+	err := client.EnsureImage("juju/xenial/amd64", "", s.remoteWithTrusty.AsRemote(), nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	s.Stub.CheckNoCalls(c)
+}
+
+func (s *imageSuite) TestListImages(c *gc.C) {
+	images := []api.Image{{Fingerprint: "abc"}, {Fingerprint: "def"}}
+	client := &imageClient{raw: &stubClient{stub: s.Stub, Images: images}}
+	result, err := client.ListImages()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, images)
+}
+
+func (s *imageSuite) TestDeleteImage(c *gc.C) {
+	client := &imageClient{raw: &stubClient{stub: s.Stub}}
+	err := client.DeleteImage("abc")
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.CheckCall(c, 0, "DeleteImage", "abc")
+
+	s.Stub.SetErrors(lxd.LXDErrors[http.StatusNotFound])
+	err = client.DeleteImage("abc")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
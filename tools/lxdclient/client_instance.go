@@ -6,8 +6,13 @@
 package lxdclient
 
 import (
+	"bufio"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/lxc/lxd/shared"
@@ -27,6 +32,70 @@ type DiskDevice struct {
 	ReadOnly bool
 }
 
+// ProxyDevice describes an LXD proxy device, which forwards connections
+// from an address on the host to an address in the container. Addresses
+// are of the form "<protocol>:<address>:<port>", e.g. "tcp:0.0.0.0:80".
+type ProxyDevice struct {
+	Listen  string
+	Connect string
+}
+
+// Snapshot describes a point-in-time snapshot of an instance.
+type Snapshot struct {
+	// Name is the name of the snapshot, without the instance prefix.
+	Name string
+
+	// Created is the time at which the snapshot was taken.
+	Created time.Time
+
+	// Stateful indicates whether the snapshot includes the runtime
+	// state of the instance as well as its filesystem.
+	Stateful bool
+}
+
+// startLogNames are the names of the logs, written by LXD for every
+// container, that record how starting the container went: forkstart.log
+// holds the errors of the start itself, and lxc.log the output of
+// liblxc as it set up the container. LXD writes a console log only if
+// the container is configured to have one.
+var startLogNames = []string{"forkstart.log", "lxc.log"}
+
+// startErrorLogLines is the number of lines of each start log recorded
+// in a StartError.
+const startErrorLogLines = 50
+
+// StartError is returned by AddInstance when an instance was created
+// but could not be started. The instance is removed, so StartError
+// records the tails of its start logs, captured beforehand, to show
+// how far it got.
+type StartError struct {
+	// Name is the name of the instance that failed to start.
+	Name string
+
+	// Log holds the last lines of each of the instance's start logs,
+	// each headed by the log's name; it is empty if none of them
+	// could be read.
+	Log string
+
+	// Err is the error returned by LXD when starting the instance.
+	Err error
+}
+
+// Error is part of the error interface.
+func (e *StartError) Error() string {
+	return e.Err.Error()
+}
+
+// StatusData returns the start log tails, keyed by "start-log", for
+// recording with the status of the machine the instance was intended
+// for.
+func (e *StartError) StatusData() map[string]interface{} {
+	if e.Log == "" {
+		return nil
+	}
+	return map[string]interface{}{"start-log": e.Log}
+}
+
 // TODO(ericsnow) We probably need to address some of the things that
 // get handled in container/lxc/clonetemplate.go.
 
@@ -42,11 +111,16 @@ type rawInstanceClient interface {
 	ContainerDeviceAdd(container, devname, devtype string, props []string) (*api.Response, error)
 	ContainerDeviceDelete(container, devname string) (*api.Response, error)
 	PushFile(container, path string, gid int, uid int, mode string, buf io.ReadSeeker) error
+	Snapshot(container string, snapshotName string, stateful bool) (*api.Response, error)
+	RestoreSnapshot(container string, snapshotName string, stateful bool) (*api.Response, error)
+	ListSnapshots(container string) ([]api.ContainerSnapshot, error)
+	GetLog(container string, log string) (io.Reader, error)
 }
 
 type instanceClient struct {
-	raw    rawInstanceClient
-	remote string
+	raw            rawInstanceClient
+	remote         string
+	proxySupported bool
 }
 
 func (client *instanceClient) addInstance(spec InstanceSpec) error {
@@ -117,10 +191,15 @@ func (client *instanceClient) AddInstance(spec InstanceSpec) (*Instance, error)
 	}
 
 	if err := client.startInstance(spec); err != nil {
+		startErr := &StartError{
+			Name: spec.Name,
+			Log:  client.startLogTails(spec.Name),
+			Err:  err,
+		}
 		if err := client.removeInstance(spec.Name); err != nil {
 			logger.Errorf("could not remove container %q after starting it failed", spec.Name)
 		}
-		return nil, errors.Trace(err)
+		return nil, errors.Trace(startErr)
 	}
 
 	inst, err := client.Instance(spec.Name)
@@ -306,25 +385,124 @@ func (client *instanceClient) Addresses(name string) ([]network.Address, error)
 	return addrs, nil
 }
 
-// AttachDisk attaches a disk to an instance.
-func (client *instanceClient) AttachDisk(instanceName, deviceName string, disk DiskDevice) error {
-	props := []string{"path=" + disk.Path, "source=" + disk.Source}
-	if disk.Pool != "" {
-		props = append(props, "pool="+disk.Pool)
+// InstanceDevice is a device that may be attached to, and detached
+// from, an instance while it is running. It is one of DiskDevice or
+// NICDevice.
+type InstanceDevice interface {
+	ProfileDevice
+
+	// validate returns an error if the device cannot be attached.
+	validate() error
+}
+
+func (d DiskDevice) validate() error {
+	if d.Source == "" {
+		return errors.NotValidf("disk device with empty source")
+	}
+	if d.Path == "" || d.Path == "/" {
+		return errors.NotValidf("disk device with path %q", d.Path)
+	}
+	return nil
+}
+
+func (d NICDevice) validate() error {
+	if d.NICType == "" {
+		return errors.NotValidf("nic device with empty nictype")
+	}
+	if d.Parent == "" && d.NICType != "p2p" {
+		return errors.NotValidf("%s nic device with empty parent", d.NICType)
 	}
-	if disk.ReadOnly {
-		props = append(props, "readonly=true")
+	return nil
+}
+
+// AttachDevice attaches a disk or nic device to an instance. If the
+// instance is running, LXD hot-plugs the device, so the instance need
+// not be restarted. Attaching a device identical to one the instance
+// already has does nothing.
+func (client *instanceClient) AttachDevice(instanceName, deviceName string, device InstanceDevice) error {
+	if err := device.validate(); err != nil {
+		return errors.Annotatef(err, "attaching device %q to instance %q", deviceName, instanceName)
 	}
-	resp, err := client.raw.ContainerDeviceAdd(instanceName, deviceName, "disk", props)
+	info, err := client.raw.ContainerInfo(instanceName)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	props := device.deviceProperties()
+	if existing, ok := info.Devices[deviceName]; ok {
+		if sameDeviceProperties(existing, props) {
+			return nil
+		}
+		return errors.AlreadyExistsf("device %q of instance %q", deviceName, instanceName)
+	}
+
+	var args []string
+	for k, v := range props {
+		if k != "type" {
+			args = append(args, k+"="+v)
+		}
+	}
+	sort.Strings(args)
+	resp, err := client.raw.ContainerDeviceAdd(instanceName, deviceName, props["type"], args)
+	if err != nil {
+		return errors.Annotatef(err, "attaching device %q to instance %q", deviceName, instanceName)
+	}
 	if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+		return errors.Annotatef(err, "attaching device %q to instance %q", deviceName, instanceName)
+	}
+	return nil
+}
+
+// DetachDevice detaches a disk or nic device, previously attached with
+// AttachDevice, from an instance. If the instance is running, LXD
+// hot-unplugs the device. Detaching a device the instance does not
+// have does nothing, but devices inherited from the instance's
+// profiles, and the root disk, cannot be detached.
+func (client *instanceClient) DetachDevice(instanceName, deviceName string) error {
+	info, err := client.raw.ContainerInfo(instanceName)
+	if err != nil {
 		return errors.Trace(err)
 	}
+	device, ok := info.Devices[deviceName]
+	if !ok {
+		if _, ok := info.ExpandedDevices[deviceName]; ok {
+			return errors.NotSupportedf("detaching profile device %q", deviceName)
+		}
+		return nil
+	}
+	switch device["type"] {
+	case "disk":
+		if device["path"] == "/" {
+			return errors.NotSupportedf("detaching root disk device %q", deviceName)
+		}
+	case "nic":
+	default:
+		return errors.NotSupportedf("detaching %s device %q", device["type"], deviceName)
+	}
+
+	resp, err := client.raw.ContainerDeviceDelete(instanceName, deviceName)
+	if err != nil {
+		return errors.Annotatef(err, "detaching device %q from instance %q", deviceName, instanceName)
+	}
+	if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+		return errors.Annotatef(err, "detaching device %q from instance %q", deviceName, instanceName)
+	}
 	return nil
 }
 
+// sameDeviceProperties reports whether two devices have the same
+// properties.
+func sameDeviceProperties(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
 // RemoveDevice removes a device from an instance.
 func (client *instanceClient) RemoveDevice(instanceName, deviceName string) error {
 	resp, err := client.raw.ContainerDeviceDelete(instanceName, deviceName)
@@ -336,3 +514,171 @@ func (client *instanceClient) RemoveDevice(instanceName, deviceName string) erro
 	}
 	return nil
 }
+
+// ProxySupported reports whether or not the LXD remote supports proxy
+// devices.
+func (client *instanceClient) ProxySupported() bool {
+	return client.proxySupported
+}
+
+// AddProxyDevice adds a proxy device to an instance, forwarding
+// connections from the host to the instance.
+func (client *instanceClient) AddProxyDevice(instanceName, deviceName string, proxy ProxyDevice) error {
+	if !client.proxySupported {
+		return errors.NotSupportedf("proxy devices on this remote")
+	}
+	props := []string{"listen=" + proxy.Listen, "connect=" + proxy.Connect}
+	resp, err := client.raw.ContainerDeviceAdd(instanceName, deviceName, "proxy", props)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// ProxyDevices returns the proxy devices attached to an instance.
+func (client *instanceClient) ProxyDevices(instanceName string) (map[string]ProxyDevice, error) {
+	info, err := client.raw.ContainerInfo(instanceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return proxyDevices(info.Devices), nil
+}
+
+// Snapshot takes a snapshot of an instance with the given name, so that
+// the instance can later be rolled back to it with RestoreSnapshot. If
+// stateful is true the running state of the instance is also captured.
+func (client *instanceClient) Snapshot(instanceName, snapshotName string, stateful bool) error {
+	resp, err := client.raw.Snapshot(instanceName, snapshotName, stateful)
+	if err != nil {
+		return errors.Annotatef(err, "snapshotting instance %q", instanceName)
+	}
+	if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+		return errors.Annotatef(err, "snapshotting instance %q", instanceName)
+	}
+	return nil
+}
+
+// RestoreSnapshot rolls an instance back to the named snapshot.
+func (client *instanceClient) RestoreSnapshot(instanceName, snapshotName string, stateful bool) error {
+	resp, err := client.raw.RestoreSnapshot(instanceName, snapshotName, stateful)
+	if err != nil {
+		return errors.Annotatef(err, "restoring instance %q to snapshot %q", instanceName, snapshotName)
+	}
+	if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+		return errors.Annotatef(err, "restoring instance %q to snapshot %q", instanceName, snapshotName)
+	}
+	return nil
+}
+
+// ListSnapshots returns the snapshots of an instance.
+func (client *instanceClient) ListSnapshots(instanceName string) ([]Snapshot, error) {
+	infos, err := client.raw.ListSnapshots(instanceName)
+	if err != nil {
+		return nil, errors.Annotatef(err, "listing snapshots of instance %q", instanceName)
+	}
+	snapshots := make([]Snapshot, len(infos))
+	for i, info := range infos {
+		// LXD reports snapshot names qualified with the name
+		// of the container; strip that off.
+		name := info.Name
+		if pos := strings.LastIndex(name, shared.SnapshotDelimiter); pos >= 0 {
+			name = name[pos+len(shared.SnapshotDelimiter):]
+		}
+		snapshots[i] = Snapshot{
+			Name:     name,
+			Created:  info.CreationDate,
+			Stateful: info.Stateful,
+		}
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshot removes the named snapshot of an instance.
+func (client *instanceClient) DeleteSnapshot(instanceName, snapshotName string) error {
+	resp, err := client.raw.Delete(instanceName + shared.SnapshotDelimiter + snapshotName)
+	if err != nil {
+		return errors.Annotatef(err, "deleting snapshot %q of instance %q", snapshotName, instanceName)
+	}
+	if err := client.raw.WaitForSuccess(resp.Operation); err != nil {
+		return errors.Annotatef(err, "deleting snapshot %q of instance %q", snapshotName, instanceName)
+	}
+	return nil
+}
+
+// Log returns a reader streaming the named log, such as "lxc.log", of
+// the named instance, as kept by LXD. The caller must close it.
+func (client *instanceClient) Log(instanceName, logName string) (io.ReadCloser, error) {
+	r, err := client.raw.GetLog(instanceName, logName)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading %s of instance %q", logName, instanceName)
+	}
+	if rc, ok := r.(io.ReadCloser); ok {
+		return rc, nil
+	}
+	return ioutil.NopCloser(r), nil
+}
+
+// LogTail returns at most the last n lines of the named log of the
+// named instance.
+func (client *instanceClient) LogTail(instanceName, logName string, n int) (string, error) {
+	r, err := client.Log(instanceName, logName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer r.Close()
+	tail, err := tailLines(r, n)
+	if err != nil {
+		return "", errors.Annotatef(err, "reading %s of instance %q", logName, instanceName)
+	}
+	return tail, nil
+}
+
+// startLogTails returns the tails of the start logs of the named
+// instance, each headed by the log's name. Logs that cannot be read
+// are skipped.
+func (client *instanceClient) startLogTails(instanceName string) string {
+	var tails []string
+	for _, logName := range startLogNames {
+		tail, err := client.LogTail(instanceName, logName, startErrorLogLines)
+		if err != nil {
+			logger.Warningf("could not read start log of container %q: %v", instanceName, err)
+			continue
+		}
+		if tail == "" {
+			continue
+		}
+		if !strings.HasSuffix(tail, "\n") {
+			tail += "\n"
+		}
+		tails = append(tails, fmt.Sprintf("==> %s <==\n%s", logName, tail))
+	}
+	return strings.Join(tails, "")
+}
+
+// tailLines returns at most the last n lines read from r, without
+// holding more than n lines in memory.
+func tailLines(r io.Reader, n int) (string, error) {
+	if n <= 0 {
+		return "", nil
+	}
+	lines := make([]string, 0, n)
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if len(lines) == n {
+				lines = append(lines[:0], lines[1:]...)
+			}
+			lines = append(lines, line)
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	return strings.Join(lines, ""), nil
+}
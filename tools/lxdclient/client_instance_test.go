@@ -7,7 +7,10 @@ package lxdclient_test
 
 import (
 	"errors"
+	"io/ioutil"
+	"time"
 
+	jujuerrors "github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	lxdapi "github.com/lxc/lxd/shared/api"
@@ -187,9 +190,9 @@ type devicesSuite struct {
 
 var _ = gc.Suite(&devicesSuite{})
 
-func (s *devicesSuite) TestAttachDisk(c *gc.C) {
+func (s *devicesSuite) TestAttachDeviceDisk(c *gc.C) {
 	client := lxdclient.NewInstanceClient(s.Client)
-	err := client.AttachDisk("instance", "device", lxdclient.DiskDevice{
+	err := client.AttachDevice("instance", "device", lxdclient.DiskDevice{
 		Source: "source-value",
 		Path:   "path-value",
 		Pool:   "pool-value",
@@ -197,39 +200,156 @@ func (s *devicesSuite) TestAttachDisk(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"ContainerInfo", []interface{}{"instance"}},
 		{"ContainerDeviceAdd", []interface{}{"instance", "device", "disk", []string{
-			"path=path-value", "source=source-value", "pool=pool-value",
+			"path=path-value", "pool=pool-value", "source=source-value",
 		}}},
 		{"WaitForSuccess", []interface{}{""}},
 	})
 }
 
-func (s *devicesSuite) TestAttachDiskReadOnly(c *gc.C) {
+func (s *devicesSuite) TestAttachDeviceDiskReadOnly(c *gc.C) {
 	client := lxdclient.NewInstanceClient(s.Client)
-	err := client.AttachDisk("instance", "device", lxdclient.DiskDevice{
+	err := client.AttachDevice("instance", "device", lxdclient.DiskDevice{
 		Source:   "source-value",
 		Path:     "path-value",
 		ReadOnly: true,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
-	s.Stub.CheckCall(c, 0, "ContainerDeviceAdd", "instance", "device", "disk", []string{
-		"path=path-value", "source=source-value", "readonly=true",
+	s.Stub.CheckCall(c, 1, "ContainerDeviceAdd", "instance", "device", "disk", []string{
+		"path=path-value", "readonly=true", "source=source-value",
 	})
 }
 
-func (s *devicesSuite) TestAttachDiskSyncError(c *gc.C) {
-	s.Stub.SetErrors(errors.New("sync error"))
+func (s *devicesSuite) TestAttachDeviceNIC(c *gc.C) {
 	client := lxdclient.NewInstanceClient(s.Client)
-	err := client.AttachDisk("instance", "device", lxdclient.DiskDevice{})
-	c.Assert(err, gc.ErrorMatches, "sync error")
+	err := client.AttachDevice("instance", "eth1", lxdclient.NICDevice{
+		NICType: "bridged",
+		Parent:  "br-eth1",
+		Name:    "eth1",
+		MTU:     9000,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCall(c, 1, "ContainerDeviceAdd", "instance", "eth1", "nic", []string{
+		"mtu=9000", "name=eth1", "nictype=bridged", "parent=br-eth1",
+	})
 }
 
-func (s *devicesSuite) TestAttachDiskAsyncError(c *gc.C) {
-	s.Stub.SetErrors(nil, errors.New("async error"))
+func (s *devicesSuite) TestAttachDeviceAlreadyAttached(c *gc.C) {
+	s.Client.Container = &lxdapi.Container{}
+	s.Client.Container.Devices = map[string]map[string]string{
+		"device": {"type": "disk", "path": "/srv", "source": "vol"},
+	}
 	client := lxdclient.NewInstanceClient(s.Client)
-	err := client.AttachDisk("instance", "device", lxdclient.DiskDevice{})
-	c.Assert(err, gc.ErrorMatches, "async error")
+	err := client.AttachDevice("instance", "device", lxdclient.DiskDevice{
+		Source: "vol",
+		Path:   "/srv",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.CheckCallNames(c, "ContainerInfo")
+
+	err = client.AttachDevice("instance", "device", lxdclient.DiskDevice{
+		Source: "vol",
+		Path:   "/var/lib",
+	})
+	c.Assert(err, jc.Satisfies, jujuerrors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `device "device" of instance "instance" already exists`)
+	s.Stub.CheckCallNames(c, "ContainerInfo", "ContainerInfo")
+}
+
+func (s *devicesSuite) TestAttachDeviceInvalid(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	for _, test := range []struct {
+		device lxdclient.InstanceDevice
+		err    string
+	}{{
+		device: lxdclient.DiskDevice{Path: "/srv"},
+		err:    "disk device with empty source not valid",
+	}, {
+		device: lxdclient.DiskDevice{Source: "vol", Path: "/"},
+		err:    `disk device with path "/" not valid`,
+	}, {
+		device: lxdclient.NICDevice{Parent: "lxdbr0"},
+		err:    "nic device with empty nictype not valid",
+	}, {
+		device: lxdclient.NICDevice{NICType: "macvlan"},
+		err:    "macvlan nic device with empty parent not valid",
+	}} {
+		err := client.AttachDevice("instance", "device", test.device)
+		c.Check(err, gc.ErrorMatches, `attaching device "device" to instance "instance": `+test.err)
+	}
+	s.Stub.CheckNoCalls(c)
+}
+
+func (s *devicesSuite) TestAttachDeviceSyncError(c *gc.C) {
+	s.Stub.SetErrors(nil, errors.New("sync error"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.AttachDevice("instance", "device", lxdclient.DiskDevice{Source: "vol", Path: "/srv"})
+	c.Assert(err, gc.ErrorMatches, `attaching device "device" to instance "instance": sync error`)
+}
+
+func (s *devicesSuite) TestAttachDeviceAsyncError(c *gc.C) {
+	s.Stub.SetErrors(nil, nil, errors.New("async error"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.AttachDevice("instance", "device", lxdclient.DiskDevice{Source: "vol", Path: "/srv"})
+	c.Assert(err, gc.ErrorMatches, `attaching device "device" to instance "instance": async error`)
+}
+
+func (s *devicesSuite) TestDetachDevice(c *gc.C) {
+	s.Client.Container = &lxdapi.Container{}
+	s.Client.Container.Devices = map[string]map[string]string{
+		"eth1": {"type": "nic", "nictype": "bridged", "parent": "br-eth1"},
+	}
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.DetachDevice("instance", "eth1")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"ContainerInfo", []interface{}{"instance"}},
+		{"ContainerDeviceDelete", []interface{}{"instance", "eth1"}},
+		{"WaitForSuccess", []interface{}{""}},
+	})
+}
+
+func (s *devicesSuite) TestDetachDeviceNotAttached(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.DetachDevice("instance", "device")
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.CheckCallNames(c, "ContainerInfo")
+}
+
+func (s *devicesSuite) TestDetachDeviceNotSupported(c *gc.C) {
+	s.Client.Container = &lxdapi.Container{}
+	s.Client.Container.Devices = map[string]map[string]string{
+		"root":  {"type": "disk", "path": "/", "pool": "default"},
+		"proxy": {"type": "proxy", "listen": "tcp:0.0.0.0:80", "connect": "tcp:127.0.0.1:80"},
+	}
+	s.Client.Container.ExpandedDevices = map[string]map[string]string{
+		"eth0": {"type": "nic", "nictype": "bridged", "parent": "lxdbr0"},
+	}
+	client := lxdclient.NewInstanceClient(s.Client)
+	for name, expect := range map[string]string{
+		"root":  `detaching root disk device "root" not supported`,
+		"proxy": `detaching proxy device "proxy" not supported`,
+		"eth0":  `detaching profile device "eth0" not supported`,
+	} {
+		err := client.DetachDevice("instance", name)
+		c.Check(err, gc.ErrorMatches, expect)
+	}
+	s.Stub.CheckCallNames(c, "ContainerInfo", "ContainerInfo", "ContainerInfo")
+}
+
+func (s *devicesSuite) TestDetachDeviceAsyncError(c *gc.C) {
+	s.Client.Container = &lxdapi.Container{}
+	s.Client.Container.Devices = map[string]map[string]string{
+		"device": {"type": "disk", "path": "/srv", "source": "vol"},
+	}
+	s.Stub.SetErrors(nil, nil, errors.New("async error"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.DetachDevice("instance", "device")
+	c.Assert(err, gc.ErrorMatches, `detaching device "device" from instance "instance": async error`)
 }
 
 func (s *devicesSuite) TestRemoveDevice(c *gc.C) {
@@ -256,3 +376,200 @@ func (s *devicesSuite) TestRemoveDeviceAsyncError(c *gc.C) {
 	err := client.RemoveDevice("instance", "device")
 	c.Assert(err, gc.ErrorMatches, "async error")
 }
+
+func (s *devicesSuite) TestAddProxyDevice(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.AddProxyDevice("instance", "device", lxdclient.ProxyDevice{
+		Listen:  "tcp:0.0.0.0:80",
+		Connect: "tcp:127.0.0.1:8080",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"ContainerDeviceAdd", []interface{}{"instance", "device", "proxy", []string{
+			"listen=tcp:0.0.0.0:80", "connect=tcp:127.0.0.1:8080",
+		}}},
+		{"WaitForSuccess", []interface{}{""}},
+	})
+}
+
+func (s *devicesSuite) TestAddProxyDeviceSyncError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("sync error"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.AddProxyDevice("instance", "device", lxdclient.ProxyDevice{})
+	c.Assert(err, gc.ErrorMatches, "sync error")
+}
+
+func (s *devicesSuite) TestAddProxyDeviceAsyncError(c *gc.C) {
+	s.Stub.SetErrors(nil, errors.New("async error"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.AddProxyDevice("instance", "device", lxdclient.ProxyDevice{})
+	c.Assert(err, gc.ErrorMatches, "async error")
+}
+
+func (s *devicesSuite) TestAddProxyDeviceNotSupported(c *gc.C) {
+	client := lxdclient.NewInstanceClientWithoutProxy(s.Client)
+	c.Assert(client.ProxySupported(), jc.IsFalse)
+	err := client.AddProxyDevice("instance", "device", lxdclient.ProxyDevice{})
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotSupported)
+	s.Stub.CheckNoCalls(c)
+}
+
+func (s *devicesSuite) TestProxyDevices(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	proxies, err := client.ProxyDevices("instance")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(proxies, gc.HasLen, 0)
+	s.Stub.CheckCall(c, 0, "ContainerInfo", "instance")
+}
+
+func (s *devicesSuite) TestProxyDevicesError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("boom"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	_, err := client.ProxyDevices("instance")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type snapshotsSuite struct {
+	lxdclient.BaseSuite
+}
+
+var _ = gc.Suite(&snapshotsSuite{})
+
+func (s *snapshotsSuite) TestSnapshot(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.Snapshot("instance", "snap0", true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"Snapshot", []interface{}{"instance", "snap0", true}},
+		{"WaitForSuccess", []interface{}{""}},
+	})
+}
+
+func (s *snapshotsSuite) TestSnapshotAsyncError(c *gc.C) {
+	s.Stub.SetErrors(nil, errors.New("async error"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.Snapshot("instance", "snap0", false)
+	c.Assert(err, gc.ErrorMatches, `snapshotting instance "instance": async error`)
+}
+
+func (s *snapshotsSuite) TestRestoreSnapshot(c *gc.C) {
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.RestoreSnapshot("instance", "snap0", false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"RestoreSnapshot", []interface{}{"instance", "snap0", false}},
+		{"WaitForSuccess", []interface{}{""}},
+	})
+}
+
+func (s *snapshotsSuite) TestRestoreSnapshotSyncError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("sync error"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.RestoreSnapshot("instance", "snap0", false)
+	c.Assert(err, gc.ErrorMatches, `restoring instance "instance" to snapshot "snap0": sync error`)
+}
+
+func (s *snapshotsSuite) TestListSnapshots(c *gc.C) {
+	created := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.Client.Snapshots = []lxdapi.ContainerSnapshot{{
+		Name:         "instance/snap0",
+		CreationDate: created,
+		Stateful:     true,
+	}}
+	client := lxdclient.NewInstanceClient(s.Client)
+	snapshots, err := client.ListSnapshots("instance")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshots, jc.DeepEquals, []lxdclient.Snapshot{{
+		Name:     "snap0",
+		Created:  created,
+		Stateful: true,
+	}})
+	s.Stub.CheckCall(c, 0, "ListSnapshots", "instance")
+}
+
+func (s *snapshotsSuite) TestDeleteSnapshot(c *gc.C) {
+	s.Client.Response = &lxdapi.Response{}
+	client := lxdclient.NewInstanceClient(s.Client)
+	err := client.DeleteSnapshot("instance", "snap0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"Delete", []interface{}{"instance/snap0"}},
+		{"WaitForSuccess", []interface{}{""}},
+	})
+}
+
+type logSuite struct {
+	lxdclient.BaseSuite
+}
+
+var _ = gc.Suite(&logSuite{})
+
+func (s *logSuite) TestLog(c *gc.C) {
+	s.Client.Logs = map[string]string{"lxc.log": "booting\nstarted\n"}
+	client := lxdclient.NewInstanceClient(s.Client)
+	r, err := client.Log("instance", "lxc.log")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "booting\nstarted\n")
+	s.Stub.CheckCall(c, 0, "GetLog", "instance", "lxc.log")
+}
+
+func (s *logSuite) TestLogError(c *gc.C) {
+	s.Stub.SetErrors(errors.New("boom"))
+	client := lxdclient.NewInstanceClient(s.Client)
+	_, err := client.Log("instance", "lxc.log")
+	c.Assert(err, gc.ErrorMatches, `reading lxc.log of instance "instance": boom`)
+}
+
+func (s *logSuite) TestLogTail(c *gc.C) {
+	s.Client.Logs = map[string]string{"lxc.log": "one\ntwo\nthree\nfour"}
+	client := lxdclient.NewInstanceClient(s.Client)
+	tail, err := client.LogTail("instance", "lxc.log", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(tail, gc.Equals, "three\nfour")
+
+	tail, err = client.LogTail("instance", "lxc.log", 10)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(tail, gc.Equals, "one\ntwo\nthree\nfour")
+}
+
+func (s *logSuite) TestAddInstanceStartErrorCapturesStartLogs(c *gc.C) {
+	s.Client.Response = &lxdapi.Response{}
+	s.Client.Container = &lxdapi.Container{StatusCode: lxdapi.Stopped}
+	s.Client.Logs = map[string]string{
+		"forkstart.log": "lxc_start - start.c: failed to spawn",
+		"lxc.log":       "lxc_conf - conf.c: mount failed\n",
+	}
+	s.Stub.SetErrors(
+		nil,                           // Init
+		nil,                           // WaitForSuccess
+		nil,                           // Action
+		errors.New("failed to start"), // WaitForSuccess
+	)
+	client := lxdclient.NewInstanceClient(s.Client)
+	_, err := client.AddInstance(lxdclient.InstanceSpec{Name: "instance"})
+	c.Assert(err, gc.ErrorMatches, "failed to start")
+
+	startErr, ok := jujuerrors.Cause(err).(*lxdclient.StartError)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(startErr.Name, gc.Equals, "instance")
+	c.Check(startErr.StatusData(), jc.DeepEquals, map[string]interface{}{
+		"start-log": "==> forkstart.log <==\nlxc_start - start.c: failed to spawn\n" +
+			"==> lxc.log <==\nlxc_conf - conf.c: mount failed\n",
+	})
+	s.Stub.CheckCallNames(c, "Init", "WaitForSuccess", "Action", "WaitForSuccess", "GetLog", "GetLog", "ContainerInfo", "Delete", "WaitForSuccess")
+	s.Stub.CheckCall(c, 4, "GetLog", "instance", "forkstart.log")
+	s.Stub.CheckCall(c, 5, "GetLog", "instance", "lxc.log")
+}
+
+func (s *logSuite) TestStartErrorWithoutLog(c *gc.C) {
+	startErr := &lxdclient.StartError{Name: "instance", Err: errors.New("boom")}
+	c.Check(startErr.Error(), gc.Equals, "boom")
+	c.Check(startErr.StatusData(), gc.IsNil)
+}
@@ -6,7 +6,11 @@
 package lxdclient
 
 import (
+	"net/http"
+	"strconv"
+
 	"github.com/juju/errors"
+	"github.com/lxc/lxd"
 	"github.com/lxc/lxd/shared/api"
 )
 
@@ -18,21 +22,281 @@ type rawProfileClient interface {
 	ProfileDelete(profile string) error
 	ProfileDeviceAdd(profile, devname, devtype string, props []string) (*api.Response, error)
 	ProfileConfig(profile string) (*api.Profile, error)
+	PutProfile(name string, profile api.ProfilePut) error
 }
 
 type profileClient struct {
 	raw rawProfileClient
 }
 
-// ProfileDelete deletes an existing profile. No check is made to
+// Profile describes an LXD profile: config and devices that are
+// applied to the containers the profile is assigned to.
+type Profile struct {
+	Name        string
+	Description string
+	Config      map[string]string
+	Devices     map[string]ProfileDevice
+}
+
+// ProfileDevice is a device of an LXD profile. It is one of NICDevice,
+// DiskDevice, UnixCharDevice or, for types Juju does not manage,
+// OtherDevice.
+type ProfileDevice interface {
+	// deviceProperties returns the LXD representation of the device,
+	// including its type.
+	deviceProperties() map[string]string
+}
+
+// NICDevice describes an LXD network interface device.
+type NICDevice struct {
+	// NICType is the kind of interface, e.g. "bridged" or "macvlan".
+	NICType string
+
+	// Parent is the name of the host device, e.g. "lxdbr0".
+	Parent string
+
+	// Name is the name of the interface inside the container.
+	Name string
+
+	// HWAddr is the MAC address of the interface, if fixed.
+	HWAddr string
+
+	// MTU is the MTU of the interface, if not the default.
+	MTU int
+}
+
+func (d NICDevice) deviceProperties() map[string]string {
+	props := map[string]string{
+		"type":    "nic",
+		"nictype": d.NICType,
+		"parent":  d.Parent,
+	}
+	setIfNotEmpty(props, "name", d.Name)
+	setIfNotEmpty(props, "hwaddr", d.HWAddr)
+	if d.MTU != 0 {
+		props["mtu"] = strconv.Itoa(d.MTU)
+	}
+	return props
+}
+
+func (d DiskDevice) deviceProperties() map[string]string {
+	props := map[string]string{
+		"type": "disk",
+		"path": d.Path,
+	}
+	setIfNotEmpty(props, "source", d.Source)
+	setIfNotEmpty(props, "pool", d.Pool)
+	if d.ReadOnly {
+		props["readonly"] = "true"
+	}
+	return props
+}
+
+// UnixCharDevice describes an LXD unix character device, such as
+// /dev/kvm, made available inside containers.
+type UnixCharDevice struct {
+	// Path is the path of the device inside the container.
+	Path string
+
+	// Major and Minor are the device's numbers. If zero, those of
+	// the device at Path on the host are used.
+	Major int
+	Minor int
+
+	// UID and GID own the device inside the container.
+	UID int
+	GID int
+
+	// Mode is the octal file mode of the device, e.g. "0660". If
+	// empty, LXD's default is used.
+	Mode string
+}
+
+func (d UnixCharDevice) deviceProperties() map[string]string {
+	props := map[string]string{
+		"type": "unix-char",
+		"path": d.Path,
+	}
+	if d.Major != 0 || d.Minor != 0 {
+		props["major"] = strconv.Itoa(d.Major)
+		props["minor"] = strconv.Itoa(d.Minor)
+	}
+	if d.UID != 0 {
+		props["uid"] = strconv.Itoa(d.UID)
+	}
+	if d.GID != 0 {
+		props["gid"] = strconv.Itoa(d.GID)
+	}
+	setIfNotEmpty(props, "mode", d.Mode)
+	return props
+}
+
+// OtherDevice holds the properties of a profile device of a type Juju
+// does not manage, so that the device survives profiles being read
+// and updated.
+type OtherDevice struct {
+	Type       string
+	Properties map[string]string
+}
+
+func (d OtherDevice) deviceProperties() map[string]string {
+	props := make(map[string]string, len(d.Properties)+1)
+	for k, v := range d.Properties {
+		props[k] = v
+	}
+	props["type"] = d.Type
+	return props
+}
+
+func setIfNotEmpty(props map[string]string, key, value string) {
+	if value != "" {
+		props[key] = value
+	}
+}
+
+// newDevice returns the typed device for the given LXD device
+// properties.
+func newDevice(props map[string]string) (ProfileDevice, error) {
+	atoi := func(key string) (int, error) {
+		if props[key] == "" {
+			return 0, nil
+		}
+		v, err := strconv.Atoi(props[key])
+		if err != nil {
+			return 0, errors.Annotatef(err, "parsing %s", key)
+		}
+		return v, nil
+	}
+	switch props["type"] {
+	case "nic":
+		mtu, err := atoi("mtu")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return NICDevice{
+			NICType: props["nictype"],
+			Parent:  props["parent"],
+			Name:    props["name"],
+			HWAddr:  props["hwaddr"],
+			MTU:     mtu,
+		}, nil
+	case "disk":
+		return DiskDevice{
+			Path:     props["path"],
+			Source:   props["source"],
+			Pool:     props["pool"],
+			ReadOnly: props["readonly"] == "true",
+		}, nil
+	case "unix-char":
+		d := UnixCharDevice{
+			Path: props["path"],
+			Mode: props["mode"],
+		}
+		var err error
+		for key, field := range map[string]*int{
+			"major": &d.Major,
+			"minor": &d.Minor,
+			"uid":   &d.UID,
+			"gid":   &d.GID,
+		} {
+			if *field, err = atoi(key); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		return d, nil
+	}
+	other := OtherDevice{
+		Type:       props["type"],
+		Properties: make(map[string]string),
+	}
+	for k, v := range props {
+		if k != "type" {
+			other.Properties[k] = v
+		}
+	}
+	return other, nil
+}
+
+func newProfile(raw api.Profile) (Profile, error) {
+	profile := Profile{
+		Name:        raw.Name,
+		Description: raw.Description,
+		Config:      raw.Config,
+		Devices:     make(map[string]ProfileDevice, len(raw.Devices)),
+	}
+	for name, props := range raw.Devices {
+		device, err := newDevice(props)
+		if err != nil {
+			return Profile{}, errors.Annotatef(err, "device %q of profile %q", name, raw.Name)
+		}
+		profile.Devices[name] = device
+	}
+	return profile, nil
+}
+
+func (p Profile) put() api.ProfilePut {
+	put := api.ProfilePut{
+		Description: p.Description,
+		Config:      p.Config,
+		Devices:     make(map[string]map[string]string, len(p.Devices)),
+	}
+	if put.Config == nil {
+		put.Config = make(map[string]string)
+	}
+	for name, device := range p.Devices {
+		put.Devices[name] = device.deviceProperties()
+	}
+	return put
+}
+
+// CreateProfile creates the given profile.
+func (p profileClient) CreateProfile(profile Profile) error {
+	if err := p.raw.ProfileCreate(profile.Name); err != nil {
+		//TODO(wwitzel3) use HasProfile to generate a more useful AlreadyExists error
+		return errors.Annotatef(err, "creating profile %q", profile.Name)
+	}
+	if err := p.raw.PutProfile(profile.Name, profile.put()); err != nil {
+		return errors.Annotatef(err, "creating profile %q", profile.Name)
+	}
+	return nil
+}
+
+// UpdateProfile replaces the description, config and devices of the
+// existing profile with the given name with those given.
+func (p profileClient) UpdateProfile(profile Profile) error {
+	if err := p.raw.PutProfile(profile.Name, profile.put()); err != nil {
+		if err == lxd.LXDErrors[http.StatusNotFound] {
+			return errors.NotFoundf("profile %q", profile.Name)
+		}
+		return errors.Annotatef(err, "updating profile %q", profile.Name)
+	}
+	return nil
+}
+
+// DeleteProfile deletes an existing profile. No check is made to
 // verify the profile exists.
-func (p profileClient) ProfileDelete(profile string) error {
-	if err := p.raw.ProfileDelete(profile); err != nil {
-		return errors.Trace(err)
+func (p profileClient) DeleteProfile(name string) error {
+	if err := p.raw.ProfileDelete(name); err != nil {
+		return errors.Annotatef(err, "deleting profile %q", name)
 	}
 	return nil
 }
 
+// ListProfiles returns all of the profiles on the LXD remote.
+func (p profileClient) ListProfiles() ([]Profile, error) {
+	raw, err := p.raw.ListProfiles()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing profiles")
+	}
+	profiles := make([]Profile, len(raw))
+	for i, r := range raw {
+		if profiles[i], err = newProfile(r); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return profiles, nil
+}
+
 // ProfileDeviceAdd adds a profile device, such as a disk or a nic, to
 // the specified profile. No check is made to verify the profile
 // exists.
@@ -44,21 +308,6 @@ func (p profileClient) ProfileDeviceAdd(profile, devname, devtype string, props
 	return resp, err
 }
 
-// CreateProfile attempts to create a new lxc profile and set the given config.
-func (p profileClient) CreateProfile(name string, config map[string]string) error {
-	if err := p.raw.ProfileCreate(name); err != nil {
-		//TODO(wwitzel3) use HasProfile to generate a more useful AlreadyExists error
-		return errors.Trace(err)
-	}
-
-	for k, v := range config {
-		if err := p.raw.SetProfileConfigItem(name, k, v); err != nil {
-			return errors.Trace(err)
-		}
-	}
-	return nil
-}
-
 // HasProfile returns true/false if the profile exists.
 func (p profileClient) HasProfile(name string) (bool, error) {
 	profiles, err := p.raw.ListProfiles()
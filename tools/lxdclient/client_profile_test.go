@@ -0,0 +1,188 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build go1.3
+
+package lxdclient_test
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/lxc/lxd"
+	"github.com/lxc/lxd/shared/api"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/tools/lxdclient"
+)
+
+type ProfileClientSuite struct {
+	testing.IsolationSuite
+
+	raw *mockRawProfileClient
+}
+
+var _ = gc.Suite(&ProfileClientSuite{})
+
+func (s *ProfileClientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.raw = &mockRawProfileClient{}
+}
+
+var kvmProfile = lxdclient.Profile{
+	Name:        "juju-kvm",
+	Description: "KVM for juju",
+	Config:      map[string]string{"security.nesting": "true"},
+	Devices: map[string]lxdclient.ProfileDevice{
+		"eth1": lxdclient.NICDevice{
+			NICType: "bridged",
+			Parent:  "br0",
+			MTU:     9000,
+		},
+		"data": lxdclient.DiskDevice{
+			Path:     "/srv",
+			Source:   "/var/lib/data",
+			ReadOnly: true,
+		},
+		"kvm": lxdclient.UnixCharDevice{
+			Path: "/dev/kvm",
+			GID:  108,
+			Mode: "0660",
+		},
+	},
+}
+
+var kvmProfilePut = api.ProfilePut{
+	Description: "KVM for juju",
+	Config:      map[string]string{"security.nesting": "true"},
+	Devices: map[string]map[string]string{
+		"eth1": {
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  "br0",
+			"mtu":     "9000",
+		},
+		"data": {
+			"type":     "disk",
+			"path":     "/srv",
+			"source":   "/var/lib/data",
+			"readonly": "true",
+		},
+		"kvm": {
+			"type": "unix-char",
+			"path": "/dev/kvm",
+			"gid":  "108",
+			"mode": "0660",
+		},
+	},
+}
+
+func (s *ProfileClientSuite) TestCreateProfile(c *gc.C) {
+	client := lxdclient.NewProfileClient(s.raw)
+	err := client.CreateProfile(kvmProfile)
+	c.Assert(err, jc.ErrorIsNil)
+	s.raw.CheckCallNames(c, "ProfileCreate", "PutProfile")
+	s.raw.CheckCall(c, 0, "ProfileCreate", "juju-kvm")
+	s.raw.CheckCall(c, 1, "PutProfile", "juju-kvm", kvmProfilePut)
+}
+
+func (s *ProfileClientSuite) TestCreateProfileError(c *gc.C) {
+	s.raw.SetErrors(errors.New("boom"))
+	client := lxdclient.NewProfileClient(s.raw)
+	err := client.CreateProfile(kvmProfile)
+	c.Assert(err, gc.ErrorMatches, `creating profile "juju-kvm": boom`)
+	s.raw.CheckCallNames(c, "ProfileCreate")
+}
+
+func (s *ProfileClientSuite) TestUpdateProfile(c *gc.C) {
+	client := lxdclient.NewProfileClient(s.raw)
+	err := client.UpdateProfile(lxdclient.Profile{Name: "juju-default"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.raw.CheckCall(c, 0, "PutProfile", "juju-default", api.ProfilePut{
+		Config:  map[string]string{},
+		Devices: map[string]map[string]string{},
+	})
+}
+
+func (s *ProfileClientSuite) TestUpdateProfileNotFound(c *gc.C) {
+	s.raw.SetErrors(lxd.LXDErrors[http.StatusNotFound])
+	client := lxdclient.NewProfileClient(s.raw)
+	err := client.UpdateProfile(kvmProfile)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `profile "juju-kvm" not found`)
+}
+
+func (s *ProfileClientSuite) TestDeleteProfile(c *gc.C) {
+	client := lxdclient.NewProfileClient(s.raw)
+	err := client.DeleteProfile("juju-kvm")
+	c.Assert(err, jc.ErrorIsNil)
+	s.raw.CheckCall(c, 0, "ProfileDelete", "juju-kvm")
+}
+
+func (s *ProfileClientSuite) TestListProfiles(c *gc.C) {
+	s.raw.profiles = []api.Profile{{
+		Name:       "juju-kvm",
+		ProfilePut: kvmProfilePut,
+	}, {
+		Name: "gpu",
+		ProfilePut: api.ProfilePut{
+			Devices: map[string]map[string]string{
+				"gpu": {"type": "gpu", "id": "0"},
+			},
+		},
+	}}
+	client := lxdclient.NewProfileClient(s.raw)
+	profiles, err := client.ListProfiles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(profiles, jc.DeepEquals, []lxdclient.Profile{kvmProfile, {
+		Name: "gpu",
+		Devices: map[string]lxdclient.ProfileDevice{
+			"gpu": lxdclient.OtherDevice{
+				Type:       "gpu",
+				Properties: map[string]string{"id": "0"},
+			},
+		},
+	}})
+}
+
+func (s *ProfileClientSuite) TestListProfilesInvalidDevice(c *gc.C) {
+	s.raw.profiles = []api.Profile{{
+		Name: "broken",
+		ProfilePut: api.ProfilePut{
+			Devices: map[string]map[string]string{
+				"eth0": {"type": "nic", "mtu": "big"},
+			},
+		},
+	}}
+	client := lxdclient.NewProfileClient(s.raw)
+	_, err := client.ListProfiles()
+	c.Assert(err, gc.ErrorMatches, `device "eth0" of profile "broken": parsing mtu: .*`)
+}
+
+type mockRawProfileClient struct {
+	lxdclient.RawProfileClient
+	testing.Stub
+	profiles []api.Profile
+}
+
+func (c *mockRawProfileClient) ProfileCreate(name string) error {
+	c.MethodCall(c, "ProfileCreate", name)
+	return c.NextErr()
+}
+
+func (c *mockRawProfileClient) PutProfile(name string, profile api.ProfilePut) error {
+	c.MethodCall(c, "PutProfile", name, profile)
+	return c.NextErr()
+}
+
+func (c *mockRawProfileClient) ProfileDelete(name string) error {
+	c.MethodCall(c, "ProfileDelete", name)
+	return c.NextErr()
+}
+
+func (c *mockRawProfileClient) ListProfiles() ([]api.Profile, error) {
+	c.MethodCall(c, "ListProfiles")
+	return c.profiles, c.NextErr()
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
 	"github.com/lxc/lxd"
 	"github.com/lxc/lxd/shared/api"
 )
@@ -16,6 +17,7 @@ import (
 type rawStorageClient interface {
 	StoragePoolCreate(name string, driver string, config map[string]string) error
 	StoragePoolGet(name string) (api.StoragePool, error)
+	StoragePoolDelete(name string) error
 	ListStoragePools() ([]api.StoragePool, error)
 
 	StoragePoolVolumeTypeCreate(pool string, volume string, volumeType string, config map[string]string) error
@@ -25,6 +27,10 @@ type rawStorageClient interface {
 	StoragePoolVolumesList(pool string) ([]api.StorageVolume, error)
 }
 
+// storagePoolDrivers holds the LXD storage drivers with which Juju
+// may create storage pools.
+var storagePoolDrivers = set.NewStrings("dir", "zfs", "btrfs", "lvm", "ceph")
+
 type storageClient struct {
 	raw       rawStorageClient
 	supported bool
@@ -127,11 +133,30 @@ func (c *storageClient) StoragePools() ([]api.StoragePool, error) {
 }
 
 // CreateStoragePool creates a LXD storage pool with the given name, driver,
-// and configuration attributes.
+// and configuration attributes. The driver must be one of "dir", "zfs",
+// "btrfs", "lvm" or "ceph".
 func (c *storageClient) CreateStoragePool(name, driver string, attrs map[string]string) error {
 	if !c.supported {
 		return errors.NotSupportedf("storage API on this remote")
 	}
+	if !storagePoolDrivers.Contains(driver) {
+		return errors.NotValidf("storage pool driver %q", driver)
+	}
 	err := c.raw.StoragePoolCreate(name, driver, attrs)
 	return errors.Annotatef(err, "creating storage pool %q", name)
 }
+
+// DeleteStoragePool deletes the LXD storage pool with the given name.
+// The pool must not have any volumes.
+func (c *storageClient) DeleteStoragePool(name string) error {
+	if !c.supported {
+		return errors.NotSupportedf("storage API on this remote")
+	}
+	if err := c.raw.StoragePoolDelete(name); err != nil {
+		if err == lxd.LXDErrors[http.StatusNotFound] {
+			return errors.NotFoundf("storage pool %q", name)
+		}
+		return errors.Annotatef(err, "deleting storage pool %q", name)
+	}
+	return nil
+}
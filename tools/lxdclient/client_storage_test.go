@@ -195,21 +195,61 @@ func (s *StorageClientSuite) TestCreateStoragePool(c *gc.C) {
 		"a": "b",
 		"c": "d",
 	}
-	err := client.CreateStoragePool("name", "driver", attrs)
+	err := client.CreateStoragePool("name", "zfs", attrs)
 	c.Assert(err, jc.ErrorIsNil)
 	s.raw.CheckCalls(c, []testing.StubCall{{
 		"StoragePoolCreate",
-		[]interface{}{"name", "driver", attrs},
+		[]interface{}{"name", "zfs", attrs},
 	}})
 }
 
+func (s *StorageClientSuite) TestCreateStoragePoolCeph(c *gc.C) {
+	client := lxdclient.NewStorageClient(s.raw, true)
+	err := client.CreateStoragePool("name", "ceph", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.raw.CheckCalls(c, []testing.StubCall{{
+		"StoragePoolCreate",
+		[]interface{}{"name", "ceph", map[string]string(nil)},
+	}})
+}
+
+func (s *StorageClientSuite) TestCreateStoragePoolInvalidDriver(c *gc.C) {
+	client := lxdclient.NewStorageClient(s.raw, true)
+	err := client.CreateStoragePool("name", "driver", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `storage pool driver "driver" not valid`)
+	s.raw.CheckNoCalls(c)
+}
+
 func (s *StorageClientSuite) TestCreateStoragePoolError(c *gc.C) {
 	s.raw.SetErrors(errors.New("burp"))
 	client := lxdclient.NewStorageClient(s.raw, true)
-	err := client.CreateStoragePool("name", "driver", nil)
+	err := client.CreateStoragePool("name", "dir", nil)
 	c.Assert(err, gc.ErrorMatches, `creating storage pool "name": burp`)
 }
 
+func (s *StorageClientSuite) TestDeleteStoragePool(c *gc.C) {
+	client := lxdclient.NewStorageClient(s.raw, true)
+	err := client.DeleteStoragePool("name")
+	c.Assert(err, jc.ErrorIsNil)
+	s.raw.CheckCalls(c, []testing.StubCall{{"StoragePoolDelete", []interface{}{"name"}}})
+}
+
+func (s *StorageClientSuite) TestDeleteStoragePoolError(c *gc.C) {
+	s.raw.SetErrors(errors.New("burp"))
+	client := lxdclient.NewStorageClient(s.raw, true)
+	err := client.DeleteStoragePool("name")
+	c.Assert(err, gc.ErrorMatches, `deleting storage pool "name": burp`)
+}
+
+func (s *StorageClientSuite) TestDeleteStoragePoolNotFound(c *gc.C) {
+	s.raw.SetErrors(lxd.LXDErrors[http.StatusNotFound])
+	client := lxdclient.NewStorageClient(s.raw, true)
+	err := client.DeleteStoragePool("name")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `storage pool "name" not found`)
+}
+
 type mockRawStorageClient struct {
 	testing.Stub
 	volumes []api.StorageVolume
@@ -257,6 +297,11 @@ func (c *mockRawStorageClient) StoragePoolGet(name string) (api.StoragePool, err
 	return api.StoragePool{}, c.NextErr()
 }
 
+func (c *mockRawStorageClient) StoragePoolDelete(name string) error {
+	c.MethodCall(c, "StoragePoolDelete", name)
+	return c.NextErr()
+}
+
 func (c *mockRawStorageClient) StoragePoolCreate(name, driver string, attrs map[string]string) error {
 	c.MethodCall(c, "StoragePoolCreate", name, driver, attrs)
 	return c.NextErr()
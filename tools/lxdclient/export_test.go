@@ -9,20 +9,38 @@ import (
 	"github.com/juju/testing"
 )
 
-var NewInstanceSummary = newInstanceSummary
+var (
+	NewInstanceSummary = newInstanceSummary
+	InterfaceAddrs     = &interfaceAddrs
+	SysfsRoot          = &sysfsRoot
+)
 
 type (
-	RawInstanceClient rawInstanceClient
-	RawStorageClient  rawStorageClient
+	RawInstanceClient  rawInstanceClient
+	RawProfileClient   rawProfileClient
+	RawStorageClient   rawStorageClient
+	RawResourcesClient rawResourcesClient
 )
 
 func NewInstanceClient(raw RawInstanceClient) *instanceClient {
+	return &instanceClient{
+		raw:            rawInstanceClient(raw),
+		remote:         "",
+		proxySupported: true,
+	}
+}
+
+func NewInstanceClientWithoutProxy(raw RawInstanceClient) *instanceClient {
 	return &instanceClient{
 		raw:    rawInstanceClient(raw),
 		remote: "",
 	}
 }
 
+func NewProfileClient(raw RawProfileClient) *profileClient {
+	return &profileClient{raw: raw}
+}
+
 func NewStorageClient(raw RawStorageClient, supported bool) *storageClient {
 	return &storageClient{
 		raw:       raw,
@@ -30,6 +48,14 @@ func NewStorageClient(raw RawStorageClient, supported bool) *storageClient {
 	}
 }
 
+func NewResourcesClient(raw RawResourcesClient, local, supported bool) *resourcesClient {
+	return &resourcesClient{
+		raw:       raw,
+		local:     local,
+		supported: supported,
+	}
+}
+
 func PatchGenerateCertificate(s *testing.CleanupSuite, cert, key string) {
 	s.PatchValue(&generateCertificate, func() ([]byte, []byte, error) {
 		return []byte(cert), []byte(key), nil
@@ -284,6 +284,25 @@ func (i *Instance) Disks() map[string]DiskDevice {
 	return disks
 }
 
+// ProxyDevices returns the proxy devices attached to the instance.
+func (i *Instance) ProxyDevices() map[string]ProxyDevice {
+	return proxyDevices(i.InstanceSummary.Devices)
+}
+
+func proxyDevices(devices map[string]map[string]string) map[string]ProxyDevice {
+	proxies := make(map[string]ProxyDevice)
+	for name, device := range devices {
+		if device["type"] != "proxy" {
+			continue
+		}
+		proxies[name] = ProxyDevice{
+			Listen:  device["listen"],
+			Connect: device["connect"],
+		}
+	}
+	return proxies
+}
+
 func resolveMetadata(metadata map[string]string) map[string]string {
 	config := make(map[string]string)
 
@@ -50,6 +50,11 @@ var templateContainerInfo = lxdapi.Container{
 				"type": "unix-char",
 				"path": "/dev/mem",
 			},
+			"http": {
+				"type":    "proxy",
+				"listen":  "tcp:0.0.0.0:80",
+				"connect": "tcp:127.0.0.1:80",
+			},
 		},
 		Ephemeral: false,
 		Profiles:  []string{""},
@@ -156,3 +161,16 @@ func (*instanceSuite) TestDisks(c *gc.C) {
 		},
 	})
 }
+
+func (*instanceSuite) TestProxyDevices(c *gc.C) {
+	summary := lxdclient.NewInstanceSummary(&templateContainerInfo)
+	inst := lxdclient.NewInstance(summary, nil)
+
+	proxies := inst.ProxyDevices()
+	c.Assert(proxies, jc.DeepEquals, map[string]lxdclient.ProxyDevice{
+		"http": {
+			Listen:  "tcp:0.0.0.0:80",
+			Connect: "tcp:127.0.0.1:80",
+		},
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build go1.3
+
+package lxdclient
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/lxc/lxd"
+)
+
+// sysfsRoot is where the host's sysfs is mounted.
+var sysfsRoot = "/sys"
+
+// USBDeviceID identifies a model of USB device by the hexadecimal
+// vendor and product ids it reports.
+type USBDeviceID struct {
+	VendorID  string
+	ProductID string
+}
+
+// HostResources describes the devices on an LXD host that may be
+// passed through to containers.
+type HostResources struct {
+	// GPUs holds the DRM card ids of the host's GPUs, in ascending
+	// order.
+	GPUs []string
+
+	// USBDevices holds the ids of the USB devices attached to the
+	// host. A model of device appears once for every attached device.
+	USBDevices []USBDeviceID
+}
+
+// HasUSBDevice reports whether a device with the given id is attached
+// to the host.
+func (r HostResources) HasUSBDevice(id USBDeviceID) bool {
+	for _, dev := range r.USBDevices {
+		if dev == id {
+			return true
+		}
+	}
+	return false
+}
+
+type rawResourcesClient interface {
+	// Resources returns the metadata of the LXD server's response
+	// to GET /1.0/resources.
+	Resources() (json.RawMessage, error)
+}
+
+type resourcesClient struct {
+	raw rawResourcesClient
+
+	// local is true if the LXD server runs on this machine.
+	local bool
+
+	// supported is true if the LXD server reports its GPUs and USB
+	// devices through the resources API.
+	supported bool
+}
+
+// serverResources holds the parts of the LXD resources document that
+// describe the devices that may be passed through to containers.
+type serverResources struct {
+	GPU struct {
+		Cards []struct {
+			DRM *struct {
+				ID int `json:"id"`
+			} `json:"drm"`
+		} `json:"cards"`
+	} `json:"gpu"`
+	USB struct {
+		Devices []struct {
+			VendorID  string `json:"vendor_id"`
+			ProductID string `json:"product_id"`
+		} `json:"devices"`
+	} `json:"usb"`
+}
+
+// HostResources returns the devices on the LXD host that may be passed
+// through to containers. They are read from the LXD API if the server
+// reports them there, or otherwise from sysfs if the server runs on
+// this machine. The devices of other hosts cannot be read.
+func (c *resourcesClient) HostResources() (HostResources, error) {
+	if !c.supported {
+		if c.local {
+			return LocalHostResources()
+		}
+		return HostResources{}, errors.NotSupportedf("reading the devices of a remote LXD host without the resources API")
+	}
+	data, err := c.raw.Resources()
+	if err != nil {
+		return HostResources{}, errors.Trace(err)
+	}
+	var resources serverResources
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return HostResources{}, errors.Annotate(err, "decoding LXD resources")
+	}
+	var result HostResources
+	var ids []int
+	for _, card := range resources.GPU.Cards {
+		// Cards without a DRM driver cannot be passed through.
+		if card.DRM != nil {
+			ids = append(ids, card.DRM.ID)
+		}
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		result.GPUs = append(result.GPUs, strconv.Itoa(id))
+	}
+	for _, device := range resources.USB.Devices {
+		result.USBDevices = append(result.USBDevices, USBDeviceID{
+			VendorID:  strings.ToLower(device.VendorID),
+			ProductID: strings.ToLower(device.ProductID),
+		})
+	}
+	return result, nil
+}
+
+// lxdResourcesClient implements rawResourcesClient, which the LXD
+// client does not.
+type lxdResourcesClient struct {
+	raw *lxd.Client
+}
+
+// Resources is part of the rawResourcesClient interface.
+func (c lxdResourcesClient) Resources() (json.RawMessage, error) {
+	resp, err := c.raw.Http.Get(c.raw.BaseURL + "/1.0/resources")
+	if err != nil {
+		return nil, errors.Annotate(err, "reading LXD resources")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		Type     string          `json:"type"`
+		Error    string          `json:"error"`
+		Metadata json.RawMessage `json:"metadata"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.Annotate(err, "decoding LXD response")
+	}
+	if response.Type == "error" {
+		return nil, errors.Errorf("reading LXD resources: %s", response.Error)
+	}
+	return response.Metadata, nil
+}
+
+var drmCard = regexp.MustCompile(`^card([0-9]+)$`)
+
+// LocalHostResources returns the devices on the local machine that may
+// be passed through to containers, as read from sysfs.
+func LocalHostResources() (HostResources, error) {
+	var result HostResources
+
+	cards, err := ioutil.ReadDir(filepath.Join(sysfsRoot, "class", "drm"))
+	if err != nil && !os.IsNotExist(err) {
+		return HostResources{}, errors.Annotate(err, "listing GPUs")
+	}
+	var ids []int
+	for _, card := range cards {
+		// Connectors such as card0-HDMI-A-1 are listed alongside the
+		// cards themselves, and are skipped by the pattern.
+		if m := drmCard.FindStringSubmatch(card.Name()); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		result.GPUs = append(result.GPUs, strconv.Itoa(id))
+	}
+
+	usbDir := filepath.Join(sysfsRoot, "bus", "usb", "devices")
+	devices, err := ioutil.ReadDir(usbDir)
+	if err != nil && !os.IsNotExist(err) {
+		return HostResources{}, errors.Annotate(err, "listing USB devices")
+	}
+	for _, device := range devices {
+		// Interfaces of a device are listed alongside it but have no
+		// ids of their own, so they are skipped here.
+		vendor, err := readSysfsAttr(filepath.Join(usbDir, device.Name(), "idVendor"))
+		if err != nil {
+			continue
+		}
+		product, err := readSysfsAttr(filepath.Join(usbDir, device.Name(), "idProduct"))
+		if err != nil {
+			continue
+		}
+		result.USBDevices = append(result.USBDevices, USBDeviceID{
+			VendorID:  vendor,
+			ProductID: product,
+		})
+	}
+	return result, nil
+}
+
+func readSysfsAttr(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(strings.TrimSpace(string(data))), nil
+}
+
+// GPUDevice returns an LXD device that passes the host GPU with the
+// given DRM card id through to a container.
+func GPUDevice(id string) Device {
+	return Device{
+		"type": "gpu",
+		"id":   id,
+	}
+}
+
+// USBDevice returns an LXD device that passes USB devices with the
+// given id through to a container.
+func USBDevice(id USBDeviceID) Device {
+	return Device{
+		"type":      "usb",
+		"vendorid":  id.VendorID,
+		"productid": id.ProductID,
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build go1.3
+
+package lxdclient_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/tools/lxdclient"
+)
+
+type resourcesSuite struct {
+	lxdclient.BaseSuite
+	root string
+}
+
+var _ = gc.Suite(&resourcesSuite{})
+
+func (s *resourcesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.root = c.MkDir()
+	s.PatchValue(lxdclient.SysfsRoot, s.root)
+}
+
+func (s *resourcesSuite) mkdir(c *gc.C, path ...string) string {
+	dir := filepath.Join(append([]string{s.root}, path...)...)
+	err := os.MkdirAll(dir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	return dir
+}
+
+func (s *resourcesSuite) addUSBDevice(c *gc.C, name, vendor, product string) {
+	dir := s.mkdir(c, "bus", "usb", "devices", name)
+	err := ioutil.WriteFile(filepath.Join(dir, "idVendor"), []byte(vendor+"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(dir, "idProduct"), []byte(product+"\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *resourcesSuite) TestLocalHostResources(c *gc.C) {
+	s.mkdir(c, "class", "drm", "card10")
+	s.mkdir(c, "class", "drm", "card1")
+	s.mkdir(c, "class", "drm", "card1-HDMI-A-1")
+	s.mkdir(c, "class", "drm", "renderD128")
+	s.addUSBDevice(c, "1-1", "046d", "C52B")
+	s.addUSBDevice(c, "1-2", "10c4", "ea60")
+	s.mkdir(c, "bus", "usb", "devices", "1-1:1.0")
+
+	resources, err := lxdclient.LocalHostResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, jc.DeepEquals, lxdclient.HostResources{
+		GPUs: []string{"1", "10"},
+		USBDevices: []lxdclient.USBDeviceID{
+			{VendorID: "046d", ProductID: "c52b"},
+			{VendorID: "10c4", ProductID: "ea60"},
+		},
+	})
+	c.Assert(resources.HasUSBDevice(lxdclient.USBDeviceID{VendorID: "10c4", ProductID: "ea60"}), jc.IsTrue)
+	c.Assert(resources.HasUSBDevice(lxdclient.USBDeviceID{VendorID: "10c4", ProductID: "ea61"}), jc.IsFalse)
+}
+
+func (s *resourcesSuite) TestLocalHostResourcesNoDevices(c *gc.C) {
+	resources, err := lxdclient.LocalHostResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, jc.DeepEquals, lxdclient.HostResources{})
+}
+
+func (s *resourcesSuite) TestDevices(c *gc.C) {
+	c.Assert(lxdclient.GPUDevice("1"), jc.DeepEquals, lxdclient.Device{
+		"type": "gpu",
+		"id":   "1",
+	})
+	c.Assert(lxdclient.USBDevice(lxdclient.USBDeviceID{VendorID: "10c4", ProductID: "ea60"}), jc.DeepEquals, lxdclient.Device{
+		"type":      "usb",
+		"vendorid":  "10c4",
+		"productid": "ea60",
+	})
+}
+
+func (s *resourcesSuite) TestHostResourcesFromAPI(c *gc.C) {
+	raw := &stubResourcesClient{
+		Stub: s.Stub,
+		resources: `{
+			"gpu": {"cards": [
+				{"drm": {"id": 10}},
+				{"vendor": "no driver"},
+				{"drm": {"id": 1}}
+			]},
+			"usb": {"devices": [
+				{"vendor_id": "046d", "product_id": "C52B"},
+				{"vendor_id": "10c4", "product_id": "ea60"}
+			]}
+		}`,
+	}
+	// The API is preferred even when LXD is running locally.
+	client := lxdclient.NewResourcesClient(raw, true, true)
+
+	resources, err := client.HostResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, jc.DeepEquals, lxdclient.HostResources{
+		GPUs: []string{"1", "10"},
+		USBDevices: []lxdclient.USBDeviceID{
+			{VendorID: "046d", ProductID: "c52b"},
+			{VendorID: "10c4", ProductID: "ea60"},
+		},
+	})
+	s.Stub.CheckCallNames(c, "Resources")
+}
+
+func (s *resourcesSuite) TestHostResourcesFromAPIError(c *gc.C) {
+	raw := &stubResourcesClient{Stub: s.Stub}
+	s.Stub.SetErrors(errors.New("boom"))
+	client := lxdclient.NewResourcesClient(raw, false, true)
+
+	_, err := client.HostResources()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *resourcesSuite) TestHostResourcesLocalWithoutAPI(c *gc.C) {
+	s.mkdir(c, "class", "drm", "card0")
+	raw := &stubResourcesClient{Stub: s.Stub}
+	client := lxdclient.NewResourcesClient(raw, true, false)
+
+	resources, err := client.HostResources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, jc.DeepEquals, lxdclient.HostResources{GPUs: []string{"0"}})
+	s.Stub.CheckNoCalls(c)
+}
+
+func (s *resourcesSuite) TestHostResourcesRemoteWithoutAPI(c *gc.C) {
+	// The devices of this machine must not be mistaken for those
+	// of the remote host.
+	s.mkdir(c, "class", "drm", "card0")
+	raw := &stubResourcesClient{Stub: s.Stub}
+	client := lxdclient.NewResourcesClient(raw, false, false)
+
+	_, err := client.HostResources()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	s.Stub.CheckNoCalls(c)
+}
+
+type stubResourcesClient struct {
+	*testing.Stub
+	resources string
+}
+
+func (s *stubResourcesClient) Resources() (json.RawMessage, error) {
+	s.AddCall("Resources")
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(s.resources), nil
+}
@@ -8,10 +8,13 @@ package lxdclient
 import (
 	"crypto/x509"
 	"io"
+	"net/http"
 	"runtime"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
+	"github.com/lxc/lxd"
 	"github.com/lxc/lxd/shared"
 	"github.com/lxc/lxd/shared/api"
 	gc "gopkg.in/check.v1"
@@ -48,10 +51,14 @@ type stubClient struct {
 	stub *testing.Stub
 
 	Instance   *api.ContainerState
+	Container  *api.Container
 	Instances  []api.Container
 	ReturnCode int
 	Response   *api.Response
 	Aliases    map[string]string
+	Images     []api.Image
+	Snapshots  []api.ContainerSnapshot
+	Logs       map[string]string
 }
 
 func (s *stubClient) WaitForSuccess(waitURL string) error {
@@ -148,7 +155,47 @@ func (s *stubClient) GetImageInfo(imageTarget string) (*api.Image, error) {
 	if err := s.stub.NextErr(); err != nil {
 		return nil, err
 	}
-	return &api.Image{}, nil
+	for _, image := range s.Images {
+		if strings.HasPrefix(image.Fingerprint, imageTarget) {
+			image := image
+			return &image, nil
+		}
+	}
+	return nil, lxd.LXDErrors[http.StatusNotFound]
+}
+
+func (s *stubClient) ListImages() ([]api.Image, error) {
+	s.stub.AddCall("ListImages")
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.Images, nil
+}
+
+func (s *stubClient) DeleteImage(image string) error {
+	s.stub.AddCall("DeleteImage", image)
+	return s.stub.NextErr()
+}
+
+func (s *stubClient) PostAlias(alias, desc, target string) error {
+	s.stub.AddCall("PostAlias", alias, desc, target)
+	if err := s.stub.NextErr(); err != nil {
+		return err
+	}
+	if s.Aliases == nil {
+		s.Aliases = make(map[string]string)
+	}
+	s.Aliases[alias] = target
+	return nil
+}
+
+func (s *stubClient) DeleteAlias(alias string) error {
+	s.stub.AddCall("DeleteAlias", alias)
+	if err := s.stub.NextErr(); err != nil {
+		return err
+	}
+	delete(s.Aliases, alias)
+	return nil
 }
 
 func (s *stubClient) ContainerDeviceAdd(container, devname, devtype string, props []string) (*api.Response, error) {
@@ -172,6 +219,9 @@ func (s *stubClient) ContainerInfo(name string) (*api.Container, error) {
 	if err := s.stub.NextErr(); err != nil {
 		return nil, err
 	}
+	if s.Container != nil {
+		return s.Container, nil
+	}
 	return &api.Container{}, nil
 }
 
@@ -182,3 +232,35 @@ func (s *stubClient) PushFile(container, path string, gid int, uid int, mode str
 	}
 	return nil
 }
+
+func (s *stubClient) Snapshot(container string, snapshotName string, stateful bool) (*api.Response, error) {
+	s.stub.AddCall("Snapshot", container, snapshotName, stateful)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return &api.Response{}, nil
+}
+
+func (s *stubClient) RestoreSnapshot(container string, snapshotName string, stateful bool) (*api.Response, error) {
+	s.stub.AddCall("RestoreSnapshot", container, snapshotName, stateful)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return &api.Response{}, nil
+}
+
+func (s *stubClient) ListSnapshots(container string) ([]api.ContainerSnapshot, error) {
+	s.stub.AddCall("ListSnapshots", container)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.Snapshots, nil
+}
+
+func (s *stubClient) GetLog(container string, log string) (io.Reader, error) {
+	s.stub.AddCall("GetLog", container, log)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return strings.NewReader(s.Logs[log]), nil
+}
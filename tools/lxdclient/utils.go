@@ -6,91 +6,187 @@
 package lxdclient
 
 import (
+	"net"
 	"strings"
 
 	"github.com/juju/errors"
-	"github.com/juju/utils/series"
 	"github.com/lxc/lxd/shared/api"
 
 	"github.com/juju/juju/service"
-	"github.com/juju/juju/service/common"
 )
 
 // IsInstalledLocally returns true if LXD is installed locally.
 func IsInstalledLocally() (bool, error) {
-	names, err := service.ListServices()
-	if err != nil {
+	_, err := service.LocalServiceInfo("lxd")
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
 		return false, errors.Trace(err)
 	}
-	for _, name := range names {
-		if name == "lxd" {
-			return true, nil
-		}
-	}
-	return false, nil
+	return true, nil
 }
 
 // IsRunningLocally returns true if LXD is running locally.
 func IsRunningLocally() (bool, error) {
-	installed, err := IsInstalledLocally()
-	if err != nil {
-		return installed, errors.Trace(err)
-	}
-	if !installed {
+	info, err := service.LocalServiceInfo("lxd")
+	if errors.IsNotFound(err) {
 		return false, nil
-	}
-
-	hostSeries, err := series.HostSeries()
-	if err != nil {
+	} else if err != nil {
 		return false, errors.Trace(err)
 	}
-	svc, err := service.NewService("lxd", common.Conf{}, hostSeries)
-	if err != nil {
-		return false, errors.Trace(err)
-	}
-
-	running, err := svc.Running()
-	if err != nil {
-		return running, errors.Trace(err)
-	}
-
-	return running, nil
+	return info.Running, nil
 }
 
 // errIPV6NotSupported is the error returned by glibc for attempts at unsupported
 // protocols.
 const errIPV6NotSupported = `socket: address family not supported by protocol`
 
-// EnableHTTPSListener configures LXD to listen for HTTPS requests,
-// rather than only via the Unix socket.
-func EnableHTTPSListener(client interface {
+// serverConfigClient is the part of the LXD client needed to
+// configure its HTTPS listener.
+type serverConfigClient interface {
 	ServerStatus() (*api.Server, error)
 	SetServerConfig(k, v string) error
-}) error {
+}
+
+// EnableHTTPSListener configures LXD to listen for HTTPS requests,
+// rather than only via the Unix socket. LXD listens on all addresses;
+// use EnableHTTPSListenerOn to restrict it.
+func EnableHTTPSListener(client serverConfigClient) error {
 	// First check that the server is not already listening for HTTPS.
-	state, err := client.ServerStatus()
-	if err != nil {
+	listening, err := isListeningHTTPS(client)
+	if err != nil || listening {
 		return errors.Trace(err)
 	}
-	if _, ok := state.Config["core.https_address"]; ok {
-		return nil
-	}
 
 	// Make sure the LXD service is configured to listen to local https
 	// requests, rather than only via the Unix socket.
-	// TODO: jam 2016-02-25 This tells LXD to listen on all addresses,
-	//      which does expose the LXD to outside requests. It would
-	//      probably be better to only tell LXD to listen for requests on
-	//      the loopback and LXC bridges that we are using.
 	if err := client.SetServerConfig("core.https_address", "[::]"); err != nil {
 		// if the error hints that the problem might be a protocol unsupported
 		// such as what happens when IPV6 is disabled in kernel, we try IPV4
 		// as a fallback.
-		cause := errors.Cause(err)
-		if strings.HasSuffix(cause.Error(), errIPV6NotSupported) {
+		if isIPV6NotSupported(err) {
 			return errors.Trace(client.SetServerConfig("core.https_address", "0.0.0.0"))
 		}
 		return errors.Trace(err)
 	}
 	return nil
 }
+
+// EnableHTTPSListenerOn configures LXD to listen for HTTPS requests on
+// one of the given addresses, rather than on every interface. Each
+// entry may be an IP address, or a CIDR matching the addresses of the
+// local network interfaces (e.g. the subnet of the LXD bridge).
+//
+// LXD binds a single address, so the addresses are tried in the order
+// given. Loopback addresses are passed over when any other address is
+// given, since clients on the host can connect through any local
+// address. As with EnableHTTPSListener, IPv6 addresses are passed over
+// if IPv6 is not supported by the kernel.
+func EnableHTTPSListenerOn(client serverConfigClient, addrs []string) error {
+	listening, err := isListeningHTTPS(client)
+	if err != nil || listening {
+		return errors.Trace(err)
+	}
+
+	ips, err := listenerAddresses(addrs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, ip := range ips {
+		address := ip.String()
+		if ip.To4() == nil {
+			address = "[" + address + "]"
+		}
+		err = client.SetServerConfig("core.https_address", address)
+		if err == nil {
+			return nil
+		}
+		if ip.To4() == nil && isIPV6NotSupported(err) {
+			logger.Debugf("cannot listen on %s: %v", address, err)
+			continue
+		}
+		return errors.Trace(err)
+	}
+	return errors.Annotatef(err, "cannot listen for HTTPS on any of %v", addrs)
+}
+
+// isListeningHTTPS reports whether the server is already configured
+// to listen for HTTPS requests.
+func isListeningHTTPS(client serverConfigClient) (bool, error) {
+	state, err := client.ServerStatus()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	_, ok := state.Config["core.https_address"]
+	return ok, nil
+}
+
+func isIPV6NotSupported(err error) bool {
+	return strings.HasSuffix(errors.Cause(err).Error(), errIPV6NotSupported)
+}
+
+// interfaceAddrs is patched out in tests.
+var interfaceAddrs = net.InterfaceAddrs
+
+// listenerAddresses returns the IP addresses described by the given
+// addresses and CIDRs, in order and without duplicates.
+func listenerAddresses(addrs []string) ([]net.IP, error) {
+	var local []net.IP
+	var result []net.IP
+	add := func(ip net.IP) {
+		for _, existing := range result {
+			if existing.Equal(ip) {
+				return
+			}
+		}
+		result = append(result, ip)
+	}
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip != nil {
+			add(ip)
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(addr)
+		if err != nil {
+			return nil, errors.NotValidf("listen address %q", addr)
+		}
+		if local == nil {
+			if local, err = localAddresses(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		for _, ip := range local {
+			if ipNet.Contains(ip) {
+				add(ip)
+			}
+		}
+	}
+
+	var nonLoopback []net.IP
+	for _, ip := range result {
+		if !ip.IsLoopback() {
+			nonLoopback = append(nonLoopback, ip)
+		}
+	}
+	if len(nonLoopback) > 0 {
+		result = nonLoopback
+	}
+	if len(result) == 0 {
+		return nil, errors.NotFoundf("local address matching %v", addrs)
+	}
+	return result, nil
+}
+
+func localAddresses() ([]net.IP, error) {
+	addrs, err := interfaceAddrs()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting local addresses")
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok {
+			ips = append(ips, ipNet.IP)
+		}
+	}
+	return ips, nil
+}
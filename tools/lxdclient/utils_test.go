@@ -7,6 +7,7 @@ package lxdclient_test
 
 import (
 	"errors"
+	"net"
 
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -57,6 +58,67 @@ func (s *utilsSuite) TestEnableHTTPSListenerIPV4Fallback(c *gc.C) {
 	client.CheckCall(c, 2, "SetServerConfig", "core.https_address", "0.0.0.0")
 }
 
+func (s *utilsSuite) patchInterfaceAddrs(c *gc.C, cidrs ...string) {
+	var addrs []net.Addr
+	for _, cidr := range cidrs {
+		ip, ipNet, err := net.ParseCIDR(cidr)
+		c.Assert(err, jc.ErrorIsNil)
+		ipNet.IP = ip
+		addrs = append(addrs, ipNet)
+	}
+	s.PatchValue(lxdclient.InterfaceAddrs, func() ([]net.Addr, error) {
+		return addrs, nil
+	})
+}
+
+func (s *utilsSuite) TestEnableHTTPSListenerOn(c *gc.C) {
+	s.patchInterfaceAddrs(c, "127.0.0.1/8", "10.0.8.1/24", "192.168.1.10/24")
+	client := newMockConfigSetter()
+	err := lxdclient.EnableHTTPSListenerOn(client, []string{"127.0.0.1", "10.0.8.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	client.CheckCall(c, 0, "ServerStatus")
+	client.CheckCall(c, 1, "SetServerConfig", "core.https_address", "10.0.8.1")
+}
+
+func (s *utilsSuite) TestEnableHTTPSListenerOnLoopbackOnly(c *gc.C) {
+	client := newMockConfigSetter()
+	err := lxdclient.EnableHTTPSListenerOn(client, []string{"::1"})
+	c.Assert(err, jc.ErrorIsNil)
+	client.CheckCall(c, 1, "SetServerConfig", "core.https_address", "[::1]")
+}
+
+func (s *utilsSuite) TestEnableHTTPSListenerOnAlreadyEnabled(c *gc.C) {
+	client := newMockConfigSetter()
+	client.ServerState.Config["core.https_address"] = "foo"
+	err := lxdclient.EnableHTTPSListenerOn(client, []string{"10.0.8.1"})
+	c.Assert(err, jc.ErrorIsNil)
+	client.CheckCallNames(c, "ServerStatus")
+}
+
+func (s *utilsSuite) TestEnableHTTPSListenerOnIPV4Fallback(c *gc.C) {
+	s.patchInterfaceAddrs(c, "10.0.8.1/24", "fd42:1::1/64")
+	client := newMockConfigSetter()
+	client.SetErrors(nil, errors.New("any error string added by lxd: socket: address family not supported by protocol"))
+	err := lxdclient.EnableHTTPSListenerOn(client, []string{"fd42:1::/64", "10.0.8.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	client.CheckCall(c, 1, "SetServerConfig", "core.https_address", "[fd42:1::1]")
+	client.CheckCall(c, 2, "SetServerConfig", "core.https_address", "10.0.8.1")
+}
+
+func (s *utilsSuite) TestEnableHTTPSListenerOnNoMatch(c *gc.C) {
+	s.patchInterfaceAddrs(c, "192.168.1.10/24")
+	client := newMockConfigSetter()
+	err := lxdclient.EnableHTTPSListenerOn(client, []string{"10.0.8.0/24"})
+	c.Assert(err, gc.ErrorMatches, `local address matching \[10.0.8.0/24\] not found`)
+	client.CheckCallNames(c, "ServerStatus")
+}
+
+func (s *utilsSuite) TestEnableHTTPSListenerOnInvalid(c *gc.C) {
+	client := newMockConfigSetter()
+	err := lxdclient.EnableHTTPSListenerOn(client, []string{"lxdbr0"})
+	c.Assert(err, gc.ErrorMatches, `listen address "lxdbr0" not valid`)
+}
+
 type mockConfigSetter struct {
 	testing.Stub
 	ServerState *api.Server
@@ -5,8 +5,10 @@ package upgrades
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/version"
 
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
@@ -17,6 +19,7 @@ import (
 type StateBackend interface {
 	AllModels() ([]Model, error)
 	ControllerUUID() string
+	ControllerConfig() (controller.Config, error)
 
 	StripLocalUserDomain() error
 	RenameAddModelPermission() error
@@ -34,6 +37,14 @@ type StateBackend interface {
 	AddUpdateStatusHookSettings() error
 	CorrectRelationUnitCounts() error
 	AddModelEnvironVersion() error
+
+	// SweepOrphans finds, and unless dryRun is true removes, the
+	// documents described by the sweep whose parents no longer exist.
+	// It returns the number of orphaned documents found.
+	SweepOrphans(sweep state.OrphanSweep, dryRun bool) (int, error)
+
+	UpgradeStepCompleted(agentTag string, vers version.Number, description string) (bool, error)
+	SetUpgradeStepCompleted(agentTag string, vers version.Number, description string) error
 }
 
 // Model is an interface providing access to the details of a model within the
@@ -78,6 +89,10 @@ func (s stateBackend) ControllerUUID() string {
 	return s.st.ControllerUUID()
 }
 
+func (s stateBackend) ControllerConfig() (controller.Config, error) {
+	return s.st.ControllerConfig()
+}
+
 func (s stateBackend) StripLocalUserDomain() error {
 	return state.StripLocalUserDomain(s.st)
 }
@@ -142,6 +157,18 @@ func (s stateBackend) AddModelEnvironVersion() error {
 	return state.AddModelEnvironVersion(s.st)
 }
 
+func (s stateBackend) SweepOrphans(sweep state.OrphanSweep, dryRun bool) (int, error) {
+	return state.SweepOrphans(s.st, sweep, dryRun)
+}
+
+func (s stateBackend) UpgradeStepCompleted(agentTag string, vers version.Number, description string) (bool, error) {
+	return s.st.UpgradeStepCompleted(agentTag, vers, description)
+}
+
+func (s stateBackend) SetUpgradeStepCompleted(agentTag string, vers version.Number, description string) error {
+	return s.st.SetUpgradeStepCompleted(agentTag, vers, description)
+}
+
 type modelShim struct {
 	st *state.State
 	m  *state.Model
@@ -3,13 +3,32 @@
 
 package upgrades
 
-import "github.com/juju/juju/environs"
+import (
+	"github.com/juju/version"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/environs"
+)
 
 var (
 	UpgradeOperations      = &upgradeOperations
 	StateUpgradeOperations = &stateUpgradeOperations
+	StepRetryPolicy        = &stepRetryPolicy
+	FeatureSteps           = featureSteps
+	NewAPICompletedSteps   = &newAPICompletedSteps
 )
 
+// CompletedStepsFunc returns a function, to patch over
+// NewAPICompletedSteps, that records API upgrade steps with steps.
+func CompletedStepsFunc(steps interface {
+	UpgradeStepCompleted(agentTag string, vers version.Number, description string) (bool, error)
+	SetUpgradeStepCompleted(agentTag string, vers version.Number, description string) error
+}) func(api.Connection) completedSteps {
+	return func(api.Connection) completedSteps {
+		return steps
+	}
+}
+
 type ModelConfigUpdater environConfigUpdater
 type ModelConfigReader environConfigReader
 
@@ -0,0 +1,114 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+)
+
+// FeatureGated may be implemented by a Step that is only run when a
+// named feature is enabled in the controller config. Such steps allow
+// experimental schema migrations to ship without being run until the
+// feature is enabled.
+//
+// The controller config is only available on controller machines, so
+// a feature-gated step is never run on other machines.
+type FeatureGated interface {
+	// Feature returns the name of the feature that must be enabled
+	// for the step to be run.
+	Feature() string
+}
+
+// featureSteps returns the given steps, each to be run only when the
+// named feature is enabled in the controller config.
+func featureSteps(feature string, steps ...Step) []Step {
+	gated := make([]Step, len(steps))
+	for i, step := range steps {
+		gated[i] = &featureStep{Step: step, feature: feature}
+	}
+	return gated
+}
+
+// featureStep wraps a Step so that it is only run when a feature is
+// enabled.
+type featureStep struct {
+	Step
+	feature string
+}
+
+var _ FeatureGated = (*featureStep)(nil)
+var _ PreconditionChecker = (*featureStep)(nil)
+
+// Feature is defined on the FeatureGated interface.
+func (step *featureStep) Feature() string {
+	return step.feature
+}
+
+// CheckPreconditions is defined on the PreconditionChecker interface.
+func (step *featureStep) CheckPreconditions(context Context) error {
+	if checker, ok := step.Step.(PreconditionChecker); ok {
+		return checker.CheckPreconditions(context)
+	}
+	return nil
+}
+
+// stepFeature returns the name of the feature that must be enabled for
+// the step to be run, or "" if the step is always run.
+func stepFeature(step Step) string {
+	if gated, ok := step.(FeatureGated); ok {
+		return gated.Feature()
+	}
+	return ""
+}
+
+// controllerFeatures returns the features enabled in the controller
+// config of the given state.
+func controllerFeatures(st StateBackend) (set.Strings, error) {
+	cfg, err := st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cfg.Features(), nil
+}
+
+// featureChecker reports whether features are enabled in the controller
+// config, which it reads at most once, when it is first asked about a
+// feature. The config is not read at all if no step is feature-gated.
+type featureChecker struct {
+	st      StateBackend
+	enabled set.Strings
+}
+
+func (f *featureChecker) isEnabled(feature string) (bool, error) {
+	if f.st == nil {
+		// There is no controller config to enable the feature.
+		return false, nil
+	}
+	if f.enabled == nil {
+		enabled, err := controllerFeatures(f.st)
+		if err != nil {
+			return false, errors.Annotate(err, "cannot read controller features")
+		}
+		f.enabled = enabled
+	}
+	return f.enabled.Contains(feature), nil
+}
+
+// stepEnabled reports whether the step should be run, given the
+// enabled features.
+func (f *featureChecker) stepEnabled(step Step) (bool, error) {
+	feature := stepFeature(step)
+	if feature == "" {
+		return true, nil
+	}
+	enabled, err := f.isEnabled(feature)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if !enabled {
+		logger.Debugf("skipping upgrade step %q: feature %q not enabled", step.Description(), feature)
+	}
+	return enabled, nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type featuresSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&featuresSuite{})
+
+func (s *featuresSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(upgrades.StepRetryPolicy, fastRetryPolicy)
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: append(
+					[]upgrades.Step{newUpgradeStep("state step", upgrades.Controller)},
+					upgrades.FeatureSteps("new-schema",
+						newUpgradeStep("new schema step", upgrades.Controller),
+					)...,
+				),
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: append(
+					upgrades.FeatureSteps("new-agent-config",
+						newUpgradeStep("new agent config step", upgrades.AllMachines),
+					),
+					newUpgradeStep("api step", upgrades.AllMachines),
+				),
+			},
+		}
+	})
+}
+
+func (s *featuresSuite) performUpgrade(c *gc.C, state *mockStateBackend, target upgrades.Target) ([]string, error) {
+	ctx := &mockContext{
+		agentConfig: &mockAgentConfig{tag: names.NewMachineTag("0")},
+		state:       state,
+	}
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(target), ctx)
+	return ctx.messages, err
+}
+
+func (s *featuresSuite) TestPerformUpgradeSkipsDisabledFeatures(c *gc.C) {
+	state := &mockStateBackend{}
+	messages, err := s.performUpgrade(c, state, upgrades.Controller)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(messages, jc.DeepEquals, []string{"state step", "api step"})
+	state.CheckCallNames(c,
+		"UpgradeStepCompleted", "SetUpgradeStepCompleted",
+		"ControllerConfig",
+		"UpgradeStepCompleted", "SetUpgradeStepCompleted",
+	)
+}
+
+func (s *featuresSuite) TestPerformUpgradeRunsEnabledFeatures(c *gc.C) {
+	state := &mockStateBackend{features: "new-schema, new-agent-config"}
+	messages, err := s.performUpgrade(c, state, upgrades.Controller)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(messages, jc.DeepEquals, []string{
+		"state step", "new schema step", "new agent config step", "api step",
+	})
+
+	// The controller config is read only once.
+	var configReads int
+	for _, call := range state.Calls() {
+		if call.FuncName == "ControllerConfig" {
+			configReads++
+		}
+	}
+	c.Check(configReads, gc.Equals, 1)
+}
+
+func (s *featuresSuite) TestPerformUpgradeRunsSkippedFeaturesLater(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.20.0"),
+				steps: append(
+					[]upgrades.Step{newUpgradeStep("old state step", upgrades.Controller)},
+					upgrades.FeatureSteps("new-schema",
+						newUpgradeStep("old schema step", upgrades.Controller),
+					)...,
+				),
+			},
+		}
+	})
+	state := &mockStateBackend{}
+	messages, err := s.performUpgrade(c, state, upgrades.Controller)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(messages, jc.DeepEquals, []string{"api step"})
+
+	// Once the feature is enabled, the next upgrade runs the step
+	// skipped by the earlier one, but not the step's ungated
+	// neighbour, and records it as completed.
+	state.features = "new-schema, new-agent-config"
+	messages, err = s.performUpgrade(c, state, upgrades.Controller)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(messages, jc.DeepEquals, []string{"old schema step", "new agent config step", "api step"})
+
+	messages, err = s.performUpgrade(c, state, upgrades.Controller)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(messages, jc.DeepEquals, []string{"api step"})
+}
+
+func (s *featuresSuite) TestPerformUpgradeFeaturesNeedController(c *gc.C) {
+	state := &mockStateBackend{features: "new-agent-config"}
+	messages, err := s.performUpgrade(c, state, upgrades.HostMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(messages, jc.DeepEquals, []string{"api step"})
+	state.CheckNoCalls(c)
+}
+
+func (s *featuresSuite) TestPerformUpgradeCannotReadFeatures(c *gc.C) {
+	state := &mockStateBackend{}
+	// The first state step is recorded as completed before the
+	// controller config is read.
+	state.SetErrors(nil, nil, errors.New("no config"))
+	messages, err := s.performUpgrade(c, state, upgrades.Controller)
+	c.Assert(err, gc.ErrorMatches, "cannot read controller features: no config")
+	c.Check(messages, jc.DeepEquals, []string{"state step"})
+}
+
+func (s *featuresSuite) TestPlanHonoursFeatures(c *gc.C) {
+	from, to := version.MustParse("1.20.0"), version.MustParse("1.21.0")
+	describe := func(plan []upgrades.PlannedStep) []string {
+		var descriptions []string
+		for _, step := range plan {
+			descriptions = append(descriptions, step.Description)
+		}
+		return descriptions
+	}
+
+	plan := upgrades.Plan(from, to, targets(upgrades.Controller), nil)
+	c.Check(describe(plan), jc.DeepEquals, []string{"state step", "api step"})
+
+	plan = upgrades.Plan(from, to, targets(upgrades.Controller), set.NewStrings("new-schema"))
+	c.Check(describe(plan), jc.DeepEquals, []string{"state step", "new schema step", "api step"})
+	c.Check(plan[0].Feature, gc.Equals, "")
+	c.Check(plan[1].Feature, gc.Equals, "new-schema")
+
+	// Features are only enabled on controllers.
+	plan = upgrades.Plan(from, to, targets(upgrades.HostMachine), set.NewStrings("new-agent-config"))
+	c.Check(describe(plan), jc.DeepEquals, []string{"api step"})
+
+	plan = upgrades.PlanStateSteps(from, to, set.NewStrings("new-schema", "new-agent-config"))
+	c.Check(describe(plan), jc.DeepEquals, []string{"state step", "new schema step"})
+}
+
+func (s *featuresSuite) TestDryRunReadsFeatures(c *gc.C) {
+	state := &mockStateBackend{features: "new-schema"}
+	ctx := &mockContext{state: state}
+	checks, err := upgrades.DryRun(
+		version.MustParse("1.20.0"), version.MustParse("1.21.0"),
+		targets(upgrades.Controller), ctx,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	var descriptions []string
+	for _, check := range checks {
+		descriptions = append(descriptions, check.Description)
+	}
+	c.Check(descriptions, jc.DeepEquals, []string{"state step", "new schema step", "api step"})
+}
+
+func (s *featuresSuite) TestFeatureStepsKeepPreconditions(c *gc.C) {
+	step := &checkedStep{
+		mockUpgradeStep: *newUpgradeStep("checked", upgrades.Controller),
+		err:             errors.New("no way"),
+	}
+	gated := upgrades.FeatureSteps("new-schema", step)
+	c.Assert(gated, gc.HasLen, 1)
+	c.Check(gated[0].Description(), gc.Equals, "checked")
+	c.Check(gated[0].(upgrades.FeatureGated).Feature(), gc.Equals, "new-schema")
+	err := gated[0].(upgrades.PreconditionChecker).CheckPreconditions(&mockContext{})
+	c.Check(err, gc.ErrorMatches, "no way")
+}
@@ -45,6 +45,10 @@ type opsIterator struct {
 	to      version.Number
 	allOps  []Operation
 	current int
+
+	// earlierGated holds whether the operations for versions at or
+	// before from are also returned, for their feature-gated steps.
+	earlierGated bool
 }
 
 func newStateUpgradeOpsIterator(from version.Number) *opsIterator {
@@ -88,7 +92,7 @@ func (it *opsIterator) Next() bool {
 		targetVersion := it.allOps[it.current].TargetVersion()
 
 		// Do not run steps for versions of Juju earlier or same as we are upgrading from.
-		if targetVersion.Compare(it.from) <= 0 {
+		if targetVersion.Compare(it.from) <= 0 && !it.earlierGated {
 			continue
 		}
 		// Do not run steps for versions of Juju later than we are upgrading to.
@@ -102,3 +106,19 @@ func (it *opsIterator) Next() bool {
 func (it *opsIterator) Get() Operation {
 	return it.allOps[it.current]
 }
+
+// Earlier reports whether the current operation is for a version of
+// Juju earlier or same as we are upgrading from, in which case only
+// its feature-gated steps may be run.
+func (it *opsIterator) Earlier() bool {
+	return it.Get().TargetVersion().Compare(it.from) <= 0
+}
+
+// includeEarlierGated makes the iterator also return the operations
+// for versions earlier or same as we are upgrading from, so that
+// feature-gated steps skipped by earlier upgrades, while their
+// features were disabled, are considered again.
+func (it *opsIterator) includeEarlierGated() *opsIterator {
+	it.earlierGated = true
+	return it
+}
@@ -0,0 +1,179 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
+)
+
+// PreconditionChecker may be implemented by a Step that is able to
+// check, without making any changes, that it can be run.
+type PreconditionChecker interface {
+	// CheckPreconditions returns an error if the step cannot be run
+	// in the given context.
+	CheckPreconditions(Context) error
+}
+
+// PlannedStep describes an upgrade step that would be run by
+// PerformUpgrade.
+type PlannedStep struct {
+	// TargetVersion is the version of the operation that the step
+	// belongs to.
+	TargetVersion version.Number
+
+	// Description is the step's human readable description.
+	Description string
+
+	// Targets holds the machine types for which the step is applicable.
+	Targets []Target
+
+	// State is true if the step is run against the StateBackend,
+	// and false if it is run against the API.
+	State bool
+
+	// Feature holds the name of the feature that enabled the step,
+	// or "" if the step is not feature-gated.
+	Feature string
+
+	step Step
+}
+
+// Plan returns the steps that would be run, in order, by
+// PerformUpgrade when upgrading from one version of Juju to another
+// on a machine with the given targets, with the given features
+// enabled in the controller config. No steps are run.
+//
+// The plan includes the enabled feature-gated steps for versions
+// earlier or same as "from", which PerformUpgrade runs unless they
+// were completed by an earlier upgrade.
+func Plan(from, to version.Number, targets []Target, features set.Strings) []PlannedStep {
+	var plan []PlannedStep
+	if hasStateTarget(targets) {
+		ops := newOpsIterator(from, to, stateUpgradeOperations()).includeEarlierGated()
+		plan = append(plan, planUpgradeSteps(ops, targets, true, features)...)
+	} else {
+		// As in PerformUpgrade, features are only enabled on
+		// controllers.
+		features = nil
+	}
+	ops := newOpsIterator(from, to, upgradeOperations()).includeEarlierGated()
+	return append(plan, planUpgradeSteps(ops, targets, false, features)...)
+}
+
+// PlanStateSteps returns the StateBackend steps that would be run, in
+// order, by the database master when upgrading the controller from
+// one version of Juju to another, with the given features enabled.
+func PlanStateSteps(from, to version.Number, features set.Strings) []PlannedStep {
+	ops := newOpsIterator(from, to, stateUpgradeOperations()).includeEarlierGated()
+	return planUpgradeSteps(ops, []Target{Controller, DatabaseMaster}, true, features)
+}
+
+func planUpgradeSteps(ops *opsIterator, targets []Target, state bool, features set.Strings) []PlannedStep {
+	var plan []PlannedStep
+	for ops.Next() {
+		op := ops.Get()
+		for _, step := range op.Steps() {
+			if !targetsMatch(targets, step.Targets()) {
+				continue
+			}
+			feature := stepFeature(step)
+			if feature != "" && !features.Contains(feature) {
+				continue
+			}
+			if feature == "" && ops.Earlier() {
+				continue
+			}
+			plan = append(plan, PlannedStep{
+				TargetVersion: op.TargetVersion(),
+				Description:   step.Description(),
+				Targets:       step.Targets(),
+				State:         state,
+				Feature:       feature,
+				step:          step,
+			})
+		}
+	}
+	return plan
+}
+
+// StepCheck records the result of checking the preconditions of a
+// planned upgrade step.
+type StepCheck struct {
+	PlannedStep
+
+	// Err holds the reason the step could not be run, or nil if its
+	// preconditions were met or it has none.
+	Err error
+}
+
+// DryRun checks the preconditions of each step that PerformUpgrade
+// would run when upgrading from one version of Juju to another, in the
+// order they would be run, without running any of them. Unlike
+// PerformUpgrade, checking continues after a step's preconditions are
+// not met, so that all problems are reported together.
+//
+// An error is returned only if the context could not be used at all;
+// problems with individual steps are recorded in the results.
+func DryRun(from, to version.Number, targets []Target, context Context) ([]StepCheck, error) {
+	var features set.Strings
+	if hasStateTarget(targets) {
+		var err error
+		features, err = controllerFeatures(context.StateContext().State())
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot access state")
+		}
+	}
+	plan := Plan(from, to, targets, features)
+	for _, planned := range plan {
+		if planned.State {
+			// Every state step requires access to the database;
+			// reading the models is enough to show that we have it.
+			if _, err := context.StateContext().State().AllModels(); err != nil {
+				return nil, errors.Annotate(err, "cannot access state")
+			}
+			break
+		}
+	}
+	return checkSteps(plan, context), nil
+}
+
+// DryRunStateSteps checks the preconditions of each StateBackend step
+// that the database master would run when upgrading the controller
+// from one version of Juju to another, as DryRun does. Only a
+// StateContext is required, so the controller can check the steps on
+// an operator's behalf before the upgrade is started.
+func DryRunStateSteps(from, to version.Number, context Context) ([]StepCheck, error) {
+	features, err := controllerFeatures(context.StateContext().State())
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot access state")
+	}
+	return checkSteps(PlanStateSteps(from, to, features), context), nil
+}
+
+func checkSteps(plan []PlannedStep, context Context) []StepCheck {
+	checks := make([]StepCheck, len(plan))
+	for i, planned := range plan {
+		checks[i].PlannedStep = planned
+		checker, ok := planned.step.(PreconditionChecker)
+		if !ok {
+			continue
+		}
+		var stepContext Context
+		if planned.State {
+			stepContext = context.StateContext()
+		} else {
+			stepContext = context.APIContext()
+		}
+		if err := checker.CheckPreconditions(stepContext); err != nil {
+			logger.Warningf("upgrade step %q cannot be run: %v", planned.Description, err)
+			checks[i].Err = &upgradeError{
+				description: planned.Description,
+				err:         err,
+			}
+		}
+	}
+	return checks
+}
@@ -0,0 +1,202 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+type planSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&planSuite{})
+
+func (s *planSuite) TestPlanMatchesPerformUpgrade(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, stateUpgradeOperations)
+	s.PatchValue(upgrades.UpgradeOperations, upgradeOperations)
+	for i, test := range upgradeTests {
+		if test.err != "" {
+			// PerformUpgrade stops at the failing step.
+			continue
+		}
+		c.Logf("%d: %s", i, test.about)
+		fromVersion := version.Zero
+		if test.fromVersion != "" {
+			fromVersion = version.MustParse(test.fromVersion)
+		}
+		toVersion := version.MustParse("1.18.0")
+		if test.toVersion != "" {
+			toVersion = version.MustParse(test.toVersion)
+		}
+		descriptions := []string{}
+		for _, step := range upgrades.Plan(fromVersion, toVersion, test.targets, nil) {
+			descriptions = append(descriptions, step.Description)
+		}
+		c.Check(descriptions, jc.DeepEquals, test.expectedSteps)
+	}
+}
+
+func (s *planSuite) TestPlanDetails(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, stateUpgradeOperations)
+	s.PatchValue(upgrades.UpgradeOperations, upgradeOperations)
+	plan := upgrades.Plan(version.MustParse("1.20.0"), version.MustParse("1.21.0"), targets(upgrades.DatabaseMaster), nil)
+	c.Assert(plan, gc.HasLen, 2)
+	c.Check(plan[0].TargetVersion, gc.Equals, version.MustParse("1.21.0"))
+	c.Check(plan[0].Description, gc.Equals, "state step 1 - 1.21.0")
+	c.Check(plan[0].Targets, jc.DeepEquals, targets(upgrades.DatabaseMaster))
+	c.Check(plan[0].State, jc.IsTrue)
+	c.Check(plan[1].Description, gc.Equals, "step 1 - 1.21.0")
+	c.Check(plan[1].State, jc.IsFalse)
+}
+
+func (s *planSuite) TestPlanStateSteps(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, stateUpgradeOperations)
+	s.PatchValue(upgrades.UpgradeOperations, upgradeOperations)
+	var descriptions []string
+	for _, step := range upgrades.PlanStateSteps(version.MustParse("1.20.0"), version.MustParse("1.22.0"), nil) {
+		c.Check(step.State, jc.IsTrue)
+		descriptions = append(descriptions, step.Description)
+	}
+	c.Check(descriptions, jc.DeepEquals, []string{
+		"state step 1 - 1.21.0", "state step 2 - 1.21.0",
+		"state step 1 - 1.22.0", "state step 2 - 1.22.0",
+	})
+}
+
+type checkedStep struct {
+	mockUpgradeStep
+	err error
+}
+
+func (s *checkedStep) Run(upgrades.Context) error {
+	panic("dry run must not run steps")
+}
+
+func (s *checkedStep) CheckPreconditions(upgrades.Context) error {
+	return s.err
+}
+
+func (s *planSuite) patchCheckedSteps() {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					&checkedStep{mockUpgradeStep: *newUpgradeStep("state ok", upgrades.DatabaseMaster)},
+					&checkedStep{
+						mockUpgradeStep: *newUpgradeStep("state bad", upgrades.DatabaseMaster),
+						err:             errors.New("no way"),
+					},
+				},
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps: []upgrades.Step{
+					&checkedStep{
+						mockUpgradeStep: *newUpgradeStep("api bad", upgrades.AllMachines),
+						err:             errors.New("nope"),
+					},
+					&checkedStep{mockUpgradeStep: *newUpgradeStep("api ok", upgrades.AllMachines)},
+				},
+			},
+		}
+	})
+}
+
+func (s *planSuite) TestDryRun(c *gc.C) {
+	s.patchCheckedSteps()
+	state := &mockStateBackend{}
+	ctx := &mockContext{state: state}
+
+	checks, err := upgrades.DryRun(
+		version.MustParse("1.20.0"), version.MustParse("1.21.0"),
+		targets(upgrades.DatabaseMaster), ctx,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 4)
+	var results []string
+	for _, check := range checks {
+		result := check.Description
+		if check.Err != nil {
+			result += ": " + check.Err.Error()
+		}
+		results = append(results, result)
+	}
+	c.Check(results, jc.DeepEquals, []string{
+		"state ok",
+		"state bad: state bad: no way",
+		"api bad: api bad: nope",
+		"api ok",
+	})
+	state.CheckCallNames(c, "ControllerConfig", "AllModels")
+}
+
+func (s *planSuite) TestDryRunNoStateAccess(c *gc.C) {
+	s.patchCheckedSteps()
+	state := &mockStateBackend{}
+	state.SetErrors(errors.New("no database"))
+	ctx := &mockContext{state: state}
+
+	_, err := upgrades.DryRun(
+		version.MustParse("1.20.0"), version.MustParse("1.21.0"),
+		targets(upgrades.DatabaseMaster), ctx,
+	)
+	c.Assert(err, gc.ErrorMatches, "cannot access state: no database")
+}
+
+func (s *planSuite) TestDryRunAPIStepsOnly(c *gc.C) {
+	s.patchCheckedSteps()
+	state := &mockStateBackend{}
+	ctx := &mockContext{state: state}
+
+	checks, err := upgrades.DryRun(
+		version.MustParse("1.20.0"), version.MustParse("1.21.0"),
+		targets(upgrades.HostMachine), ctx,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 2)
+	c.Check(checks[0].Err, gc.ErrorMatches, "api bad: nope")
+	c.Check(checks[1].Err, jc.ErrorIsNil)
+	state.CheckNoCalls(c)
+}
+
+func (s *planSuite) TestDryRunStateSteps(c *gc.C) {
+	s.patchCheckedSteps()
+	state := &mockStateBackend{}
+	ctx := &mockContext{state: state}
+
+	checks, err := upgrades.DryRunStateSteps(
+		version.MustParse("1.20.0"), version.MustParse("1.21.0"), ctx,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(checks, gc.HasLen, 2)
+	c.Check(checks[0].Description, gc.Equals, "state ok")
+	c.Check(checks[0].Err, jc.ErrorIsNil)
+	c.Check(checks[1].Description, gc.Equals, "state bad")
+	c.Check(checks[1].Err, gc.ErrorMatches, "state bad: no way")
+	state.CheckCallNames(c, "ControllerConfig")
+}
+
+func (s *planSuite) TestDryRunStateStepsNoStateAccess(c *gc.C) {
+	s.patchCheckedSteps()
+	state := &mockStateBackend{}
+	state.SetErrors(errors.New("no database"))
+	ctx := &mockContext{state: state}
+
+	_, err := upgrades.DryRunStateSteps(
+		version.MustParse("1.20.0"), version.MustParse("1.21.0"), ctx,
+	)
+	c.Assert(err, gc.ErrorMatches, "cannot access state: no database")
+}
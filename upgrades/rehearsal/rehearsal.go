@@ -0,0 +1,155 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package rehearsal runs a controller's upgrade steps against a copy
+// of its data, restored from a backup archive into a sandbox mongod,
+// so that operators can rehearse an upgrade with their own data
+// before performing it. No controller is required.
+package rehearsal
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/upgrades"
+	jujuversion "github.com/juju/juju/version"
+)
+
+var logger = loggo.GetLogger("juju.upgrades.rehearsal")
+
+// Config holds the configuration for a rehearsal.
+type Config struct {
+	// Archive supplies the backup archive whose data the upgrade
+	// steps are run against.
+	Archive io.Reader
+
+	// FromVersion is the version of Juju being upgraded from. If it
+	// is zero, the version recorded in the archive is used.
+	FromVersion version.Number
+
+	// ToVersion is the version of Juju being upgraded to. If it is
+	// zero, the current version is used. Only the steps known to
+	// this version of Juju can be rehearsed, so it must not be newer
+	// than the current version.
+	ToVersion version.Number
+
+	// WorkDir is the directory in which the sandbox database and the
+	// agent data directory seen by the upgrade steps are kept. If it
+	// is empty, a temporary directory is used and removed once the
+	// rehearsal is complete.
+	WorkDir string
+
+	// Clock is used to time the upgrade steps. If it is nil, the
+	// wall clock is used.
+	Clock clock.Clock
+}
+
+// Validate returns an error if the config cannot be used for a
+// rehearsal.
+func (config Config) Validate() error {
+	if config.Archive == nil {
+		return errors.NotValidf("nil Archive")
+	}
+	if config.ToVersion.Compare(jujuversion.Current) > 0 {
+		return errors.NotValidf("ToVersion %s newer than current version %s", config.ToVersion, jujuversion.Current)
+	}
+	return nil
+}
+
+// Run restores the data in the configured backup archive into a
+// sandbox database, runs the upgrade steps that the controller's
+// database master would run against it, and reports the outcome of
+// each step.
+//
+// An error is returned only if the rehearsal could not be set up;
+// failing upgrade steps are recorded in the report.
+func Run(config Config) (*Report, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+
+	workDir := config.WorkDir
+	if workDir == "" {
+		dir, err := ioutil.TempDir("", "juju-upgrade-rehearsal-")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer os.RemoveAll(dir)
+		workDir = dir
+	}
+
+	ws, err := backups.NewArchiveWorkspaceReader(config.Archive)
+	if ws != nil {
+		defer ws.Close()
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot unpack backup archive")
+	}
+	meta, err := ws.Metadata()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read backup metadata")
+	}
+
+	report := &Report{
+		ControllerModelUUID: meta.Origin.Model,
+		FromVersion:         config.FromVersion,
+		ToVersion:           config.ToVersion,
+	}
+	if report.FromVersion == version.Zero {
+		report.FromVersion = meta.Origin.Version
+	}
+	if report.ToVersion == version.Zero {
+		report.ToVersion = jujuversion.Current
+	}
+
+	sb, err := newSandbox(filepath.Join(workDir, "sandbox"))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot start sandbox database")
+	}
+	defer func() {
+		if err := sb.Close(); err != nil {
+			logger.Errorf("cannot stop sandbox database: %v", err)
+		}
+	}()
+	logger.Infof("restoring backup %s into sandbox database", meta.ID())
+	if err := sb.Restore(ws.DBDumpDir); err != nil {
+		return nil, errors.Annotate(err, "cannot restore backup into sandbox database")
+	}
+	context, err := sb.Context(contextParams{
+		ControllerModelUUID: meta.Origin.Model,
+		Machine:             meta.Origin.Machine,
+		FromVersion:         report.FromVersion,
+		DataDir:             filepath.Join(workDir, "data"),
+		Clock:               clk,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot open restored state")
+	}
+	report.ControllerUUID = context.ControllerUUID
+
+	start := clk.Now()
+	report.Steps = upgrades.Rehearse(report.FromVersion, report.ToVersion, context.Features, context.Context, clk)
+	report.Duration = clk.Now().Sub(start)
+	for _, planned := range upgrades.Plan(
+		report.FromVersion, report.ToVersion,
+		[]upgrades.Target{upgrades.Controller, upgrades.DatabaseMaster},
+		context.Features,
+	) {
+		if !planned.State {
+			report.NotRehearsed = append(report.NotRehearsed, planned)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rehearsal
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	bt "github.com/juju/juju/state/backups/testing"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type rehearsalSuite struct {
+	testing.IsolationSuite
+	stub    testing.Stub
+	workDir string
+}
+
+var _ = gc.Suite(&rehearsalSuite{})
+
+const controllerUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+func (s *rehearsalSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub.ResetCalls()
+	s.workDir = c.MkDir()
+	s.PatchValue(&newSandbox, func(dir string) (sandbox, error) {
+		s.stub.AddCall("newSandbox", dir)
+		if err := s.stub.NextErr(); err != nil {
+			return nil, err
+		}
+		return &fakeSandbox{&s.stub}, nil
+	})
+}
+
+type fakeSandbox struct {
+	stub *testing.Stub
+}
+
+func (sb *fakeSandbox) Restore(dumpDir string) error {
+	sb.stub.AddCall("Restore", dumpDir)
+	if _, err := os.Stat(filepath.Join(dumpDir, "juju", "machines.bson")); err != nil {
+		return errors.Annotate(err, "dump not unpacked")
+	}
+	return sb.stub.NextErr()
+}
+
+func (sb *fakeSandbox) Context(p contextParams) (*restoredContext, error) {
+	sb.stub.AddCall("Context", p.ControllerModelUUID, p.Machine, p.FromVersion)
+	if err := sb.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return &restoredContext{ControllerUUID: controllerUUID}, nil
+}
+
+func (sb *fakeSandbox) Close() error {
+	sb.stub.AddCall("Close")
+	return sb.stub.NextErr()
+}
+
+func (s *rehearsalSuite) archive(c *gc.C) Config {
+	meta := bt.NewMetadata()
+	archive, err := bt.NewArchiveBasic(meta)
+	c.Assert(err, jc.ErrorIsNil)
+	return Config{
+		Archive: archive,
+		WorkDir: s.workDir,
+	}
+}
+
+func (s *rehearsalSuite) TestRun(c *gc.C) {
+	report, err := Run(s.archive(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.ControllerUUID, gc.Equals, controllerUUID)
+	c.Assert(report.ControllerModelUUID, gc.Equals, bt.NewMetadata().Origin.Model)
+	c.Assert(report.FromVersion, gc.Equals, jujuversion.Current)
+	c.Assert(report.ToVersion, gc.Equals, jujuversion.Current)
+	c.Assert(report.Steps, gc.HasLen, 0)
+	c.Assert(report.Failed(), jc.IsFalse)
+
+	s.stub.CheckCallNames(c, "newSandbox", "Restore", "Context", "Close")
+	s.stub.CheckCall(c, 0, "newSandbox", filepath.Join(s.workDir, "sandbox"))
+	s.stub.CheckCall(c, 2, "Context", bt.NewMetadata().Origin.Model, "0", jujuversion.Current)
+}
+
+func (s *rehearsalSuite) TestRunRestoreFails(c *gc.C) {
+	s.stub.SetErrors(nil, errors.New("mongorestore failed"))
+	_, err := Run(s.archive(c))
+	c.Assert(err, gc.ErrorMatches, "cannot restore backup into sandbox database: mongorestore failed")
+	s.stub.CheckCallNames(c, "newSandbox", "Restore", "Close")
+}
+
+func (s *rehearsalSuite) TestRunSandboxFails(c *gc.C) {
+	s.stub.SetErrors(errors.New("no mongod"))
+	_, err := Run(s.archive(c))
+	c.Assert(err, gc.ErrorMatches, "cannot start sandbox database: no mongod")
+}
+
+func (s *rehearsalSuite) TestRunBadArchive(c *gc.C) {
+	config := s.archive(c)
+	config.Archive = errorReader{}
+	_, err := Run(config)
+	c.Assert(err, gc.ErrorMatches, "cannot unpack backup archive: .*")
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *rehearsalSuite) TestValidate(c *gc.C) {
+	config := s.archive(c)
+	config.Archive = nil
+	c.Check(config.Validate(), gc.ErrorMatches, "nil Archive not valid")
+
+	config = s.archive(c)
+	newer := jujuversion.Current
+	newer.Major++
+	config.ToVersion = newer
+	c.Check(config.Validate(), gc.ErrorMatches, `ToVersion .* newer than current version .* not valid`)
+
+	config.ToVersion = version.Zero
+	c.Check(config.Validate(), jc.ErrorIsNil)
+}
+
+type errorReader struct{}
+
+func (errorReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
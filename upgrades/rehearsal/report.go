@@ -0,0 +1,92 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rehearsal
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/upgrades"
+)
+
+// Report describes the outcome of a rehearsal.
+type Report struct {
+	// ControllerUUID is the UUID of the controller whose data was
+	// used.
+	ControllerUUID string
+
+	// ControllerModelUUID is the UUID of the controller's model.
+	ControllerModelUUID string
+
+	// FromVersion and ToVersion are the versions of Juju between
+	// which the upgrade was rehearsed.
+	FromVersion version.Number
+	ToVersion   version.Number
+
+	// Steps holds the outcome of each of the database master's
+	// upgrade steps, in the order they were run.
+	Steps []upgrades.StepResult
+
+	// NotRehearsed holds the upgrade steps that each controller
+	// machine runs through the API. These need a running controller,
+	// and so cannot be rehearsed.
+	NotRehearsed []upgrades.PlannedStep
+
+	// Duration holds how long the upgrade steps took to run.
+	Duration time.Duration
+}
+
+// Failed returns true if any upgrade step failed.
+func (r *Report) Failed() bool {
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes a human readable form of the report to w.
+func (r *Report) Write(w io.Writer) error {
+	fmt.Fprintf(w, "Upgrade rehearsal for controller %s (model %s): %s -> %s\n\n",
+		r.ControllerUUID, r.ControllerModelUUID, r.FromVersion, r.ToVersion,
+	)
+	if len(r.Steps) == 0 {
+		fmt.Fprintln(w, "No database upgrade steps to run.")
+	} else {
+		tw := tabwriter.NewWriter(w, 0, 1, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tSTEP\tDURATION\tRESULT")
+		for _, step := range r.Steps {
+			duration, result := "-", "not run"
+			if step.Run {
+				duration = step.Duration.String()
+				result = "ok"
+				if step.Err != nil {
+					result = "failed: " + step.Err.Error()
+				}
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", step.TargetVersion, step.Description, duration, result)
+		}
+		if err := tw.Flush(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(r.NotRehearsed) > 0 {
+		fmt.Fprintln(w, "\nSteps run through the API, which were not rehearsed:")
+		for _, step := range r.NotRehearsed {
+			fmt.Fprintf(w, "  %s  %s\n", step.TargetVersion, step.Description)
+		}
+	}
+	outcome := "succeeded"
+	if r.Failed() {
+		outcome = "FAILED"
+	}
+	_, err := fmt.Fprintf(w, "\nRehearsal %s after %s.\n", outcome, r.Duration)
+	return errors.Trace(err)
+}
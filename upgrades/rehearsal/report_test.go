@@ -0,0 +1,100 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rehearsal_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/upgrades"
+	"github.com/juju/juju/upgrades/rehearsal"
+)
+
+type reportSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&reportSuite{})
+
+func plannedStep(ver, description string) upgrades.PlannedStep {
+	return upgrades.PlannedStep{
+		TargetVersion: version.MustParse(ver),
+		Description:   description,
+		State:         true,
+	}
+}
+
+func (s *reportSuite) report() *rehearsal.Report {
+	return &rehearsal.Report{
+		ControllerUUID:      "controller-uuid",
+		ControllerModelUUID: "model-uuid",
+		FromVersion:         version.MustParse("2.1.0"),
+		ToVersion:           version.MustParse("2.2.0"),
+		Steps: []upgrades.StepResult{{
+			PlannedStep: plannedStep("2.2.0", "first step"),
+			Run:         true,
+			Duration:    3 * time.Second,
+		}, {
+			PlannedStep: plannedStep("2.2.0", "second step"),
+			Run:         true,
+			Duration:    time.Second,
+			Err:         errors.New("second step: boom"),
+		}, {
+			PlannedStep: plannedStep("2.2.0", "third step"),
+		}},
+		NotRehearsed: []upgrades.PlannedStep{plannedStep("2.2.0", "api step")},
+		Duration:     4 * time.Second,
+	}
+}
+
+func (s *reportSuite) TestFailed(c *gc.C) {
+	report := s.report()
+	c.Assert(report.Failed(), jc.IsTrue)
+	report.Steps = report.Steps[:1]
+	c.Assert(report.Failed(), jc.IsFalse)
+}
+
+func (s *reportSuite) TestWrite(c *gc.C) {
+	var buf bytes.Buffer
+	err := s.report().Write(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `
+Upgrade rehearsal for controller controller-uuid (model model-uuid): 2.1.0 -> 2.2.0
+
+VERSION  STEP         DURATION  RESULT
+2.2.0    first step   3s        ok
+2.2.0    second step  1s        failed: second step: boom
+2.2.0    third step   -         not run
+
+Steps run through the API, which were not rehearsed:
+  2.2.0  api step
+
+Rehearsal FAILED after 4s.
+`[1:])
+}
+
+func (s *reportSuite) TestWriteNoSteps(c *gc.C) {
+	report := &rehearsal.Report{
+		ControllerUUID:      "controller-uuid",
+		ControllerModelUUID: "model-uuid",
+		FromVersion:         version.MustParse("2.2.0"),
+		ToVersion:           version.MustParse("2.2.0"),
+	}
+	var buf bytes.Buffer
+	err := report.Write(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, `
+Upgrade rehearsal for controller controller-uuid (model model-uuid): 2.2.0 -> 2.2.0
+
+No database upgrade steps to run.
+
+Rehearsal succeeded after 0s.
+`[1:])
+}
@@ -0,0 +1,243 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rehearsal
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
+	"gopkg.in/juju/names.v2"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/upgrades"
+)
+
+// sandboxDialTimeout is how long to wait for the sandbox mongod to
+// start accepting connections.
+const sandboxDialTimeout = 2 * time.Minute
+
+// sandbox is a disposable database into which a backup is restored.
+type sandbox interface {
+	// Restore restores the database dump in the given directory.
+	Restore(dumpDir string) error
+
+	// Context returns an upgrade context giving access to the
+	// restored data.
+	Context(contextParams) (*restoredContext, error)
+
+	// Close stops the database and releases its resources.
+	Close() error
+}
+
+// contextParams holds the details needed to open a restored
+// controller's state.
+type contextParams struct {
+	ControllerModelUUID string
+	Machine             string
+	FromVersion         version.Number
+	DataDir             string
+	Clock               clock.Clock
+}
+
+// restoredContext is an upgrade context giving access to a restored
+// controller's state.
+type restoredContext struct {
+	upgrades.Context
+	ControllerUUID string
+
+	// Features holds the features enabled in the restored
+	// controller's config.
+	Features set.Strings
+}
+
+// newSandbox starts a sandbox database, keeping its files in the
+// given directory. It is a variable so that it can be replaced in
+// tests.
+var newSandbox = newMongoSandbox
+
+// mongoSandbox is a sandbox running a private, unauthenticated
+// mongod listening only on the loopback interface.
+type mongoSandbox struct {
+	binDir  string
+	addr    string
+	cmd     *exec.Cmd
+	session *mgo.Session
+	st      *state.State
+	pool    *state.StatePool
+}
+
+func newMongoSandbox(dir string) (sandbox, error) {
+	mongod, err := mongo.Path(mongo.InstalledVersion())
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot find mongod")
+	}
+	dbDir := filepath.Join(dir, "db")
+	if err := os.MkdirAll(dbDir, 0700); err != nil {
+		return nil, errors.Trace(err)
+	}
+	port, err := unusedPort()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cmd := exec.Command(mongod,
+		"--dbpath", dbDir,
+		"--logpath", filepath.Join(dir, "mongod.log"),
+		"--bind_ip", "127.0.0.1",
+		"--port", strconv.Itoa(port),
+		"--nounixsocket",
+	)
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Annotate(err, "cannot start mongod")
+	}
+	sb := &mongoSandbox{
+		binDir: filepath.Dir(mongod),
+		addr:   net.JoinHostPort("127.0.0.1", strconv.Itoa(port)),
+		cmd:    cmd,
+	}
+	sb.session, err = mgo.DialWithTimeout(sb.addr, sandboxDialTimeout)
+	if err != nil {
+		sb.Close()
+		return nil, errors.Annotatef(err, "cannot connect to mongod at %s", sb.addr)
+	}
+	return sb, nil
+}
+
+// unusedPort returns a TCP port on the loopback interface that is not
+// currently in use.
+func unusedPort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// Restore is part of the sandbox interface.
+func (sb *mongoSandbox) Restore(dumpDir string) error {
+	mongorestore := filepath.Join(sb.binDir, "mongorestore")
+	if _, err := os.Stat(mongorestore); err != nil {
+		mongorestore, err = exec.LookPath("mongorestore")
+		if err != nil {
+			return errors.Annotate(err, "cannot find mongorestore")
+		}
+	}
+	args := []string{
+		"--host", sb.addr,
+		"--drop",
+		// See state/backups for why a small batch size is used.
+		"--batchSize", "10",
+	}
+	if _, err := os.Stat(filepath.Join(dumpDir, "oplog.bson")); err == nil {
+		args = append(args, "--oplogReplay")
+	}
+	args = append(args, dumpDir)
+	logger.Debugf("running %s %v", mongorestore, args)
+	if out, err := exec.Command(mongorestore, args...).CombinedOutput(); err != nil {
+		return errors.Annotatef(err, "mongorestore failed: %s", out)
+	}
+	return nil
+}
+
+// Context is part of the sandbox interface.
+func (sb *mongoSandbox) Context(p contextParams) (*restoredContext, error) {
+	var doc struct {
+		Settings map[string]interface{} `bson:"settings"`
+	}
+	controllers := sb.session.DB("juju").C("controllers")
+	if err := controllers.FindId("controllerSettings").One(&doc); err != nil {
+		return nil, errors.Annotate(err, "cannot read controller settings")
+	}
+	controllerUUID, _ := doc.Settings[controller.ControllerUUIDKey].(string)
+	caCert, _ := doc.Settings[controller.CACertKey].(string)
+	if !names.IsValidController(controllerUUID) {
+		return nil, errors.NotValidf("controller UUID %q", controllerUUID)
+	}
+	if !names.IsValidModel(p.ControllerModelUUID) {
+		return nil, errors.NotValidf("controller model UUID %q", p.ControllerModelUUID)
+	}
+
+	st, err := state.Open(state.OpenParams{
+		Clock:              p.Clock,
+		ControllerTag:      names.NewControllerTag(controllerUUID),
+		ControllerModelTag: names.NewModelTag(p.ControllerModelUUID),
+		MongoInfo: &mongo.MongoInfo{
+			Info: mongo.Info{
+				Addrs:      []string{sb.addr},
+				DisableTLS: true,
+			},
+		},
+		MongoDialOpts: mongo.DefaultDialOpts(),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sb.st = st
+	sb.pool = state.NewStatePool(st)
+
+	machine := p.Machine
+	if !names.IsValidMachine(machine) {
+		machine = "0"
+	}
+	agentConfig, err := agent.NewAgentConfig(agent.AgentConfigParams{
+		Paths: agent.Paths{
+			DataDir: p.DataDir,
+			LogDir:  filepath.Join(p.DataDir, "log"),
+		},
+		Jobs:              []multiwatcher.MachineJob{multiwatcher.JobManageModel},
+		UpgradedToVersion: p.FromVersion,
+		Tag:               names.NewMachineTag(machine),
+		Password:          "rehearsal",
+		Controller:        names.NewControllerTag(controllerUUID),
+		Model:             names.NewModelTag(p.ControllerModelUUID),
+		StateAddresses:    []string{sb.addr},
+		CACert:            caCert,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create agent configuration")
+	}
+	return &restoredContext{
+		Context:        upgrades.NewContext(agentConfig, nil, upgrades.NewStateBackend(st, sb.pool)),
+		ControllerUUID: controllerUUID,
+		Features:       controller.Config(doc.Settings).Features(),
+	}, nil
+}
+
+// Close is part of the sandbox interface.
+func (sb *mongoSandbox) Close() error {
+	var errs []error
+	if sb.pool != nil {
+		errs = append(errs, sb.pool.Close())
+	}
+	if sb.st != nil {
+		errs = append(errs, sb.st.Close())
+	}
+	if sb.session != nil {
+		sb.session.Close()
+	}
+	if err := sb.cmd.Process.Kill(); err != nil {
+		errs = append(errs, err)
+	}
+	// The process was killed, so its exit status is of no interest.
+	sb.cmd.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"time"
+
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"github.com/juju/version"
+)
+
+// StepResult records the outcome of running a planned upgrade step
+// during a rehearsal.
+type StepResult struct {
+	PlannedStep
+
+	// Run is true if the step was run. Steps following a failed
+	// step are not run.
+	Run bool
+
+	// Duration holds how long the step took to run.
+	Duration time.Duration
+
+	// Err holds the error returned by the step, if any.
+	Err error
+}
+
+// Rehearse runs the StateBackend steps that the database master would
+// run when upgrading the controller from one version of Juju to
+// another with the given features enabled, recording the outcome and
+// duration of each. The context
+// should give access to a disposable copy of the controller's data,
+// since the steps make changes to it.
+//
+// As with PerformUpgrade, no further steps are run once a step fails,
+// since subsequent steps may require the successful completion of
+// earlier ones; the steps that were not run are included in the
+// results.
+func Rehearse(from, to version.Number, features set.Strings, context Context, clock clock.Clock) []StepResult {
+	plan := PlanStateSteps(from, to, features)
+	results := make([]StepResult, len(plan))
+	failed := false
+	for i, planned := range plan {
+		results[i].PlannedStep = planned
+		if failed {
+			continue
+		}
+		logger.Infof("rehearsing upgrade step: %v", planned.Description)
+		start := clock.Now()
+		err := planned.step.Run(context.StateContext())
+		results[i].Run = true
+		results[i].Duration = clock.Now().Sub(start)
+		if err != nil {
+			logger.Errorf("upgrade step %q failed: %v", planned.Description, err)
+			results[i].Err = &upgradeError{
+				description: planned.Description,
+				err:         err,
+			}
+			failed = true
+		}
+	}
+	return results
+}
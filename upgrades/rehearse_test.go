@@ -0,0 +1,80 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+type rehearseSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&rehearseSuite{})
+
+func (s *rehearseSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(upgrades.StateUpgradeOperations, stateUpgradeOperations)
+	s.PatchValue(upgrades.UpgradeOperations, upgradeOperations)
+}
+
+// tickingClock is a clock.Clock that advances by a second every
+// time it is read.
+type tickingClock struct {
+	clock.Clock
+	now time.Time
+}
+
+func (c *tickingClock) Now() time.Time {
+	c.now = c.now.Add(time.Second)
+	return c.now
+}
+
+func (s *rehearseSuite) TestRehearse(c *gc.C) {
+	ctx := &mockContext{}
+	results := upgrades.Rehearse(
+		version.MustParse("1.20.0"), version.MustParse("1.22.0"),
+		nil, ctx, &tickingClock{},
+	)
+	var descriptions []string
+	for _, result := range results {
+		c.Check(result.Run, jc.IsTrue)
+		c.Check(result.Duration, gc.Equals, time.Second)
+		c.Check(result.Err, jc.ErrorIsNil)
+		descriptions = append(descriptions, result.Description)
+	}
+	expected := []string{
+		"state step 1 - 1.21.0", "state step 2 - 1.21.0",
+		"state step 1 - 1.22.0", "state step 2 - 1.22.0",
+	}
+	c.Check(descriptions, jc.DeepEquals, expected)
+	c.Check(ctx.messages, jc.DeepEquals, expected)
+}
+
+func (s *rehearseSuite) TestRehearseStopsAtFailure(c *gc.C) {
+	ctx := &mockContext{}
+	results := upgrades.Rehearse(
+		version.MustParse("1.10.0"), version.MustParse("1.21.0"),
+		nil, ctx, &tickingClock{},
+	)
+	c.Assert(results, gc.HasLen, 5)
+	c.Check(results[0].Run, jc.IsTrue)
+	c.Check(results[0].Err, jc.ErrorIsNil)
+	c.Check(results[1].Run, jc.IsTrue)
+	c.Check(results[1].Err, gc.ErrorMatches, "state step 2 error: upgrade error occurred")
+	for _, result := range results[2:] {
+		c.Check(result.Run, jc.IsFalse)
+		c.Check(result.Duration, gc.Equals, time.Duration(0))
+	}
+	c.Check(results[4].Description, gc.Equals, "state step 2 - 1.21.0")
+	c.Check(ctx.messages, jc.DeepEquals, []string{"state step 1 - 1.11.0"})
+}
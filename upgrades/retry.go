@@ -0,0 +1,96 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"io"
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+)
+
+// RetryPolicy describes how an upgrade step that fails with a
+// transient error is retried before the upgrade is abandoned.
+type RetryPolicy struct {
+	// Attempts is the maximum number of times a step is run.
+	Attempts int
+
+	// Delay is how long to wait after a step's first failure. The
+	// delay doubles after each further failure, up to MaxDelay.
+	Delay    time.Duration
+	MaxDelay time.Duration
+
+	// Clock is used to wait between attempts.
+	Clock clock.Clock
+
+	// IsTransient reports whether a step that failed with the given
+	// error may succeed if it is run again.
+	IsTransient func(error) bool
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used by PerformUpgrade.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts:    5,
+		Delay:       time.Second,
+		MaxDelay:    30 * time.Second,
+		Clock:       clock.WallClock,
+		IsTransient: IsTransientError,
+	}
+}
+
+// stepRetryPolicy returns the RetryPolicy used by PerformUpgrade; it
+// is a variable so that tests can avoid waiting between attempts.
+var stepRetryPolicy = DefaultRetryPolicy
+
+// Run calls f, calling it again while it fails with a transient error
+// and attempts remain. It returns the last error returned by f.
+func (p RetryPolicy) Run(description string, f func() error) error {
+	err := retry.Call(retry.CallArgs{
+		Func: f,
+		IsFatalError: func(err error) bool {
+			return p.IsTransient == nil || !p.IsTransient(err)
+		},
+		NotifyFunc: func(err error, attempt int) {
+			logger.Warningf("upgrade step %q failed (attempt %d of %d): %v",
+				description, attempt, p.Attempts, err)
+		},
+		Attempts:    p.Attempts,
+		Delay:       p.Delay,
+		MaxDelay:    p.MaxDelay,
+		BackoffFunc: retry.DoubleDelay,
+		Clock:       p.Clock,
+	})
+	if retry.IsAttemptsExceeded(err) {
+		return retry.LastError(err)
+	}
+	return err
+}
+
+// IsTransientError returns true if the error is one that commonly
+// occurs while controllers restart or elect a new Mongo primary during
+// an upgrade, and so is worth retrying.
+func IsTransientError(err error) bool {
+	cause := errors.Cause(err)
+	switch cause {
+	case io.EOF, io.ErrUnexpectedEOF, rpc.ErrShutdown, jujutxn.ErrExcessiveContention:
+		return true
+	}
+	if _, ok := cause.(net.Error); ok {
+		return true
+	}
+	if params.IsCodeTryAgain(cause) {
+		return true
+	}
+	// The mgo driver reports a lost replica set primary with an
+	// unexported error value.
+	return cause.Error() == "no reachable servers"
+}
@@ -0,0 +1,80 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"io"
+	"net"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	jujutxn "github.com/juju/txn"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/upgrades"
+)
+
+type retrySuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&retrySuite{})
+
+func (s *retrySuite) TestRunSucceedsAfterTransientErrors(c *gc.C) {
+	calls := 0
+	err := fastRetryPolicy().Run("step", func() error {
+		calls++
+		if calls < 3 {
+			return io.EOF
+		}
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 3)
+}
+
+func (s *retrySuite) TestRunReturnsLastErrorWhenAttemptsExhausted(c *gc.C) {
+	policy := fastRetryPolicy()
+	policy.Attempts = 3
+	calls := 0
+	err := policy.Run("step", func() error {
+		calls++
+		return errors.Annotatef(io.EOF, "attempt %d", calls)
+	})
+	c.Assert(err, gc.ErrorMatches, "attempt 3: EOF")
+	c.Assert(calls, gc.Equals, 3)
+}
+
+func (s *retrySuite) TestRunStopsOnOtherErrors(c *gc.C) {
+	calls := 0
+	err := fastRetryPolicy().Run("step", func() error {
+		calls++
+		return errors.New("bad data")
+	})
+	c.Assert(err, gc.ErrorMatches, "bad data")
+	c.Assert(calls, gc.Equals, 1)
+}
+
+func (s *retrySuite) TestIsTransientError(c *gc.C) {
+	for i, test := range []struct {
+		err       error
+		transient bool
+	}{
+		{io.EOF, true},
+		{errors.Trace(io.ErrUnexpectedEOF), true},
+		{rpc.ErrShutdown, true},
+		{jujutxn.ErrExcessiveContention, true},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{&params.Error{Code: params.CodeTryAgain}, true},
+		{errors.New("no reachable servers"), true},
+		{errors.New("bad data"), false},
+		{errors.NotFoundf("model"), false},
+	} {
+		c.Logf("test %d: %v", i, test.err)
+		c.Check(upgrades.IsTransientError(test.err), gc.Equals, test.transient)
+	}
+}
@@ -6,6 +6,8 @@ package upgrades
 import (
 	"os"
 	"path/filepath"
+
+	"github.com/juju/errors"
 )
 
 // stateStepsFor22 returns upgrade steps for Juju 2.2 that manipulate state directly.
@@ -31,6 +33,7 @@ func stateStepsFor22() []Step {
 			run: func(context Context) error {
 				return context.State().AddControllerLogCollectionsSizeSettings()
 			},
+			precondition: checkControllerConfig,
 		},
 		&upgradeStep{
 			description: "add status history pruning config settings",
@@ -38,6 +41,7 @@ func stateStepsFor22() []Step {
 			run: func(context Context) error {
 				return context.State().AddStatusHistoryPruneSettings()
 			},
+			precondition: checkModelConfigs,
 		},
 		&upgradeStep{
 			description: "add storage constraints to storage instance docs",
@@ -60,9 +64,10 @@ func stateStepsFor22() []Step {
 func stepsFor22() []Step {
 	return []Step{
 		&upgradeStep{
-			description: "remove meter status file",
-			targets:     []Target{AllMachines},
-			run:         removeMeterStatusFile,
+			description:  "remove meter status file",
+			targets:      []Target{AllMachines},
+			run:          removeMeterStatusFile,
+			precondition: checkDataDir,
 		},
 	}
 }
@@ -73,3 +78,40 @@ func removeMeterStatusFile(context Context) error {
 	meterStatusFile := filepath.Join(dataDir, "meter-status.yaml")
 	return os.RemoveAll(meterStatusFile)
 }
+
+// checkControllerConfig returns an error if the controller config
+// cannot be read.
+func checkControllerConfig(context Context) error {
+	_, err := context.State().ControllerConfig()
+	return errors.Trace(err)
+}
+
+// checkModelConfigs returns an error if the config of any model cannot
+// be read, since the steps that add model config settings rewrite the
+// config of every model.
+func checkModelConfigs(context Context) error {
+	models, err := context.State().AllModels()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, model := range models {
+		if _, err := model.Config(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// checkDataDir returns an error if the agent data directory does not
+// exist.
+func checkDataDir(context Context) error {
+	dataDir := context.AgentConfig().DataDir()
+	info, err := os.Stat(dataDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !info.IsDir() {
+		return errors.Errorf("%q is not a directory", dataDir)
+	}
+	return nil
+}
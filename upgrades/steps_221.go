@@ -12,6 +12,7 @@ func stateStepsFor221() []Step {
 			run: func(context Context) error {
 				return context.State().AddUpdateStatusHookSettings()
 			},
+			precondition: checkModelConfigs,
 		},
 		&upgradeStep{
 			description: "correct relation unit counts for subordinates",
@@ -12,6 +12,7 @@ func stateStepsFor223() []Step {
 			run: func(context Context) error {
 				return context.State().AddActionPruneSettings()
 			},
+			precondition: checkModelConfigs,
 		},
 	}
 }
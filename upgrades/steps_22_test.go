@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
@@ -66,6 +67,22 @@ func (s *steps22Suite) TestAddStatusHistoryPruneSettings(c *gc.C) {
 	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
 }
 
+func (s *steps22Suite) TestAddStatusHistoryPruneSettingsPreconditions(c *gc.C) {
+	step := findStateStep(c, v220, "add status history pruning config settings")
+	checker, ok := step.(upgrades.PreconditionChecker)
+	c.Assert(ok, jc.IsTrue)
+
+	model := &mockModel{}
+	state := &mockStateBackend{models: []upgrades.Model{model}}
+	context := &mockContext{state: state}
+	err := checker.CheckPreconditions(context)
+	c.Assert(err, jc.ErrorIsNil)
+
+	model.SetErrors(errors.New("bad config"))
+	err = checker.CheckPreconditions(context)
+	c.Assert(err, gc.ErrorMatches, "bad config")
+}
+
 func (s *steps22Suite) TestAddStorageInstanceConstraints(c *gc.C) {
 	step := findStateStep(c, v220, "add storage constraints to storage instance docs")
 	// Logic for step itself is tested in state package.
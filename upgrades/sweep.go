@@ -0,0 +1,35 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+)
+
+// SweepOrphans finds, for each of the given sweeps in turn, the
+// documents whose parent entities no longer exist, and removes them
+// unless dryRun is true. Upgrade steps use it to clean up documents
+// left behind by earlier versions. It returns the total number of
+// orphaned documents found.
+func SweepOrphans(backend StateBackend, dryRun bool, sweeps ...state.OrphanSweep) (int, error) {
+	total := 0
+	for _, sweep := range sweeps {
+		n, err := backend.SweepOrphans(sweep, dryRun)
+		if err != nil {
+			return total, errors.Annotatef(err, "sweeping orphaned %s", sweep.Description)
+		}
+		total += n
+		switch {
+		case n == 0:
+			logger.Debugf("no orphaned %s found", sweep.Description)
+		case dryRun:
+			logger.Infof("found %d orphaned %s", n, sweep.Description)
+		default:
+			logger.Infof("removed %d orphaned %s", n, sweep.Description)
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+)
+
+type sweepSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&sweepSuite{})
+
+func (s *sweepSuite) TestSweepOrphans(c *gc.C) {
+	backend := &mockStateBackend{orphans: map[string]int{
+		"relation settings": 3,
+		"opened ports":      2,
+	}}
+
+	n, err := upgrades.SweepOrphans(backend, false,
+		state.OrphanedRelationSettings,
+		state.OrphanedOpenedPorts,
+		state.OrphanedStorageAttachments,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(n, gc.Equals, 5)
+	backend.CheckCalls(c, []testing.StubCall{
+		{"SweepOrphans", []interface{}{"relation settings", false}},
+		{"SweepOrphans", []interface{}{"opened ports", false}},
+		{"SweepOrphans", []interface{}{"storage attachments", false}},
+	})
+}
+
+func (s *sweepSuite) TestSweepOrphansDryRun(c *gc.C) {
+	backend := &mockStateBackend{orphans: map[string]int{"opened ports": 2}}
+
+	n, err := upgrades.SweepOrphans(backend, true, state.OrphanedOpenedPorts)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(n, gc.Equals, 2)
+	backend.CheckCalls(c, []testing.StubCall{
+		{"SweepOrphans", []interface{}{"opened ports", true}},
+	})
+}
+
+func (s *sweepSuite) TestSweepOrphansError(c *gc.C) {
+	backend := &mockStateBackend{}
+	backend.SetErrors(nil, errors.New("boom"))
+
+	_, err := upgrades.SweepOrphans(backend, false,
+		state.OrphanedRelationSettings,
+		state.OrphanedOpenedPorts,
+		state.OrphanedStorageAttachments,
+	)
+	c.Assert(err, gc.ErrorMatches, "sweeping orphaned opened ports: boom")
+	backend.CheckCallNames(c, "SweepOrphans", "SweepOrphans")
+}
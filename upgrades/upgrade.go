@@ -6,8 +6,12 @@ package upgrades
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/version"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/upgradesteps"
 )
 
 var logger = loggo.GetLogger("juju.upgrade")
@@ -91,15 +95,31 @@ func (e *upgradeError) Error() string {
 
 // PerformUpgrade runs the business logic needed to upgrade the current "from" version to this
 // version of Juju on the "target" type of machine.
+//
+// Feature-gated steps are run only if their feature is enabled in the
+// controller config, and so only when one of the targets is a
+// controller. Their completion is always recorded in state, and those
+// for versions earlier or same as "from" that have not been completed,
+// because their feature was disabled when they were last considered,
+// are run too.
+//
+// Completed steps are recorded in state directly by steps targeting
+// controllers, and through the UpgradeSteps facade by the others, so
+// that a retried upgrade does not run them again.
 func PerformUpgrade(from version.Number, targets []Target, context Context) error {
+	features := &featureChecker{}
 	if hasStateTarget(targets) {
-		ops := newStateUpgradeOpsIterator(from)
-		if err := runUpgradeSteps(ops, targets, context.StateContext()); err != nil {
+		ops := newStateUpgradeOpsIterator(from).includeEarlierGated()
+		stateContext := context.StateContext()
+		features.st = stateContext.State()
+		if err := runUpgradeSteps(ops, targets, stateContext, stateContext.State(), features); err != nil {
 			return err
 		}
 	}
-	ops := newUpgradeOpsIterator(from)
-	if err := runUpgradeSteps(ops, targets, context.APIContext()); err != nil {
+	ops := newUpgradeOpsIterator(from).includeEarlierGated()
+	apiContext := context.APIContext()
+	completed := newAPICompletedSteps(apiContext.APIState())
+	if err := runUpgradeSteps(ops, targets, apiContext, completed, features); err != nil {
 		return err
 	}
 	logger.Infof("All upgrade steps completed successfully")
@@ -127,21 +147,46 @@ func hasDatabaseMasterTarget(targets []Target) bool {
 // runUpgradeSteps finds all the upgrade operations relevant to
 // the targets given and runs the associated upgrade steps.
 //
-// As soon as any error is encountered, the operation is aborted since
-// subsequent steps may required successful completion of earlier
-// ones. The steps must be idempotent so that the entire upgrade
-// operation can be retried.
-func runUpgradeSteps(ops *opsIterator, targets []Target, context Context) error {
+// Steps failing with a transient error are retried according to the
+// step retry policy. As soon as any other error is encountered, or the
+// retries are exhausted, the operation is aborted since subsequent
+// steps may required successful completion of earlier ones. The steps
+// must be idempotent so that the entire upgrade operation can be
+// retried.
+//
+// If completed is not nil, it records each step that completes, and
+// steps already recorded as completed by this agent are skipped.
+// Feature-gated steps are skipped unless features reports their
+// feature enabled, and are recorded as completed in the controller's
+// state whatever completed is. Skipped steps are not recorded, so that
+// they are considered again by later upgrades.
+func runUpgradeSteps(ops *opsIterator, targets []Target, context Context, completed completedSteps, features *featureChecker) error {
+	policy := stepRetryPolicy()
 	for ops.Next() {
+		vers := ops.Get().TargetVersion()
 		for _, step := range ops.Get().Steps() {
-			if targetsMatch(targets, step.Targets()) {
-				logger.Infof("running upgrade step: %v", step.Description())
-				if err := step.Run(context); err != nil {
-					logger.Errorf("upgrade step %q failed: %v", step.Description(), err)
-					return &upgradeError{
-						description: step.Description(),
-						err:         err,
-					}
+			if !targetsMatch(targets, step.Targets()) {
+				continue
+			}
+			stepCompleted := completed
+			if stepFeature(step) != "" {
+				if enabled, err := features.stepEnabled(step); err != nil {
+					return errors.Trace(err)
+				} else if !enabled {
+					continue
+				}
+				// Feature-gated steps are only enabled on
+				// controllers, which can record them.
+				stepCompleted = features.st
+			} else if ops.Earlier() {
+				continue
+			}
+			err := runUpgradeStep(step, vers, policy, context, stepCompleted)
+			if err != nil {
+				logger.Errorf("upgrade step %q failed: %v", step.Description(), err)
+				return &upgradeError{
+					description: step.Description(),
+					err:         err,
 				}
 			}
 		}
@@ -149,6 +194,54 @@ func runUpgradeSteps(ops *opsIterator, targets []Target, context Context) error
 	return nil
 }
 
+// completedSteps records the upgrade steps that agents have completed.
+type completedSteps interface {
+	UpgradeStepCompleted(agentTag string, vers version.Number, description string) (bool, error)
+	SetUpgradeStepCompleted(agentTag string, vers version.Number, description string) error
+}
+
+// newAPICompletedSteps returns the completedSteps used to record the
+// steps run against the API, or nil if the controller does not
+// support recording them.
+var newAPICompletedSteps = func(conn api.Connection) completedSteps {
+	if conn == nil || conn.BestFacadeVersion("UpgradeSteps") < 1 {
+		return nil
+	}
+	return upgradesteps.NewFacade(conn)
+}
+
+func runUpgradeStep(step Step, vers version.Number, policy RetryPolicy, context Context, completed completedSteps) error {
+	description := step.Description()
+	var agentTag string
+	if completed != nil {
+		agentTag = context.AgentConfig().Tag().String()
+		var done bool
+		err := policy.Run(description, func() (err error) {
+			done, err = completed.UpgradeStepCompleted(agentTag, vers, description)
+			return err
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if done {
+			logger.Infof("skipping completed upgrade step: %v", description)
+			return nil
+		}
+	}
+	logger.Infof("running upgrade step: %v", description)
+	if err := policy.Run(description, func() error {
+		return step.Run(context)
+	}); err != nil {
+		return err
+	}
+	if completed == nil {
+		return nil
+	}
+	return policy.Run(description, func() error {
+		return completed.SetUpgradeStepCompleted(agentTag, vers, description)
+	})
+}
+
 // targetsMatch returns true if any machineTargets match any of
 // stepTargets.
 func targetsMatch(machineTargets []Target, stepTargets []Target) bool {
@@ -164,12 +257,14 @@ func targetsMatch(machineTargets []Target, stepTargets []Target) bool {
 
 // upgradeStep is a default Step implementation.
 type upgradeStep struct {
-	description string
-	targets     []Target
-	run         func(Context) error
+	description  string
+	targets      []Target
+	run          func(Context) error
+	precondition func(Context) error
 }
 
 var _ Step = (*upgradeStep)(nil)
+var _ PreconditionChecker = (*upgradeStep)(nil)
 
 // Description is defined on the Step interface.
 func (step *upgradeStep) Description() string {
@@ -185,3 +280,11 @@ func (step *upgradeStep) Targets() []Target {
 func (step *upgradeStep) Run(context Context) error {
 	return step.run(context)
 }
+
+// CheckPreconditions is defined on the PreconditionChecker interface.
+func (step *upgradeStep) CheckPreconditions(context Context) error {
+	if step.precondition == nil {
+		return nil
+	}
+	return step.precondition(context)
+}
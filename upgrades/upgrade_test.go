@@ -5,9 +5,11 @@ package upgrades_test
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -19,9 +21,11 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/upgrades"
@@ -66,6 +70,18 @@ type upgradeSuite struct {
 
 var _ = gc.Suite(&upgradeSuite{})
 
+func (s *upgradeSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(upgrades.StepRetryPolicy, fastRetryPolicy)
+}
+
+func fastRetryPolicy() upgrades.RetryPolicy {
+	policy := upgrades.DefaultRetryPolicy()
+	policy.Delay = time.Millisecond
+	policy.MaxDelay = time.Millisecond
+	return policy
+}
+
 type mockUpgradeOperation struct {
 	targetVersion version.Number
 	steps         []upgrades.Step
@@ -215,7 +231,30 @@ func (mock *mockAgentConfig) Model() names.ModelTag {
 type mockStateBackend struct {
 	upgrades.StateBackend
 	testing.Stub
-	models []upgrades.Model
+	models    []upgrades.Model
+	completed []string
+	features  string
+	orphans   map[string]int
+}
+
+func (mock *mockStateBackend) UpgradeStepCompleted(agentTag string, vers version.Number, description string) (bool, error) {
+	mock.MethodCall(mock, "UpgradeStepCompleted", agentTag, vers, description)
+	key := fmt.Sprintf("%s %s %s", agentTag, vers, description)
+	for _, done := range mock.completed {
+		if done == key {
+			return true, mock.NextErr()
+		}
+	}
+	return false, mock.NextErr()
+}
+
+func (mock *mockStateBackend) SetUpgradeStepCompleted(agentTag string, vers version.Number, description string) error {
+	mock.MethodCall(mock, "SetUpgradeStepCompleted", agentTag, vers, description)
+	if err := mock.NextErr(); err != nil {
+		return err
+	}
+	mock.completed = append(mock.completed, fmt.Sprintf("%s %s %s", agentTag, vers, description))
+	return nil
 }
 
 func (mock *mockStateBackend) AllModels() ([]upgrades.Model, error) {
@@ -228,6 +267,16 @@ func (mock *mockStateBackend) ControllerUUID() string {
 	return "a-b-c-d"
 }
 
+func (mock *mockStateBackend) ControllerConfig() (controller.Config, error) {
+	mock.MethodCall(mock, "ControllerConfig")
+	return controller.Config{controller.Features: mock.features}, mock.NextErr()
+}
+
+func (mock *mockStateBackend) SweepOrphans(sweep state.OrphanSweep, dryRun bool) (int, error) {
+	mock.MethodCall(mock, "SweepOrphans", sweep.Description, dryRun)
+	return mock.orphans[sweep.Description], mock.NextErr()
+}
+
 type mockModel struct {
 	testing.Stub
 	config    *config.Config
@@ -506,8 +555,9 @@ func (s *upgradeSuite) TestPerformUpgrade(c *gc.C) {
 		c.Logf("%d: %s", i, test.about)
 		var messages []string
 		ctx := &mockContext{
-			messages: messages,
-			state:    &mockStateBackend{},
+			messages:    messages,
+			agentConfig: &mockAgentConfig{tag: names.NewMachineTag("0")},
+			state:       &mockStateBackend{},
 		}
 		fromVersion := version.Zero
 		if test.fromVersion != "" {
@@ -583,8 +633,8 @@ func (s *upgradeSuite) TestAPIStepsGetRestrictedContext(c *gc.C) {
 
 func (s *upgradeSuite) checkContextRestriction(c *gc.C, expectedPanic string) {
 	fromVersion := version.MustParse("1.20.0")
-	type fakeAgentConfigSetter struct{ agent.ConfigSetter }
-	ctx := upgrades.NewContext(fakeAgentConfigSetter{}, nil, &mockStateBackend{})
+	agentConfig := &mockAgentConfig{tag: names.NewMachineTag("0")}
+	ctx := upgrades.NewContext(agentConfig, nil, &mockStateBackend{})
 	c.Assert(
 		func() { upgrades.PerformUpgrade(fromVersion, targets(upgrades.Controller), ctx) },
 		gc.PanicMatches, expectedPanic,
@@ -626,6 +676,144 @@ func (s *upgradeSuite) TestStateStepsNotAttemptedWhenNoStateTarget(c *gc.C) {
 	check(upgrades.HostMachine, 0, nil)
 }
 
+type flakyStep struct {
+	failures []error
+	runs     int
+	targets  []upgrades.Target
+}
+
+func (s *flakyStep) Description() string {
+	return "flaky"
+}
+
+func (s *flakyStep) Targets() []upgrades.Target {
+	if s.targets != nil {
+		return s.targets
+	}
+	return []upgrades.Target{upgrades.Controller}
+}
+
+func (s *flakyStep) Run(upgrades.Context) error {
+	s.runs++
+	if len(s.failures) == 0 {
+		return nil
+	}
+	err := s.failures[0]
+	s.failures = s.failures[1:]
+	return err
+}
+
+func (s *upgradeSuite) patchFlakyStateStep(step *flakyStep) {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps:         []upgrades.Step{step},
+			},
+		}
+	})
+	s.PatchValue(upgrades.UpgradeOperations,
+		func() []upgrades.Operation { return nil })
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+}
+
+func (s *upgradeSuite) TestPerformUpgradeRetriesTransientErrors(c *gc.C) {
+	step := &flakyStep{failures: []error{io.EOF, errors.Trace(io.ErrUnexpectedEOF)}}
+	s.patchFlakyStateStep(step)
+	ctx := &mockContext{
+		agentConfig: &mockAgentConfig{tag: names.NewMachineTag("0")},
+		state:       &mockStateBackend{},
+	}
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(upgrades.Controller), ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(step.runs, gc.Equals, 3)
+}
+
+func (s *upgradeSuite) TestPerformUpgradeGivesUpAfterRetries(c *gc.C) {
+	policy := fastRetryPolicy()
+	policy.Attempts = 2
+	s.PatchValue(upgrades.StepRetryPolicy, func() upgrades.RetryPolicy { return policy })
+	step := &flakyStep{failures: []error{io.EOF, io.EOF, io.EOF}}
+	s.patchFlakyStateStep(step)
+	ctx := &mockContext{
+		agentConfig: &mockAgentConfig{tag: names.NewMachineTag("0")},
+		state:       &mockStateBackend{},
+	}
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(upgrades.Controller), ctx)
+	c.Assert(err, gc.ErrorMatches, "flaky: EOF")
+	c.Assert(step.runs, gc.Equals, 2)
+}
+
+func (s *upgradeSuite) TestPerformUpgradeDoesNotRetryOtherErrors(c *gc.C) {
+	step := &flakyStep{failures: []error{errors.New("bad data")}}
+	s.patchFlakyStateStep(step)
+	ctx := &mockContext{
+		agentConfig: &mockAgentConfig{tag: names.NewMachineTag("0")},
+		state:       &mockStateBackend{},
+	}
+	err := upgrades.PerformUpgrade(version.MustParse("1.20.0"), targets(upgrades.Controller), ctx)
+	c.Assert(err, gc.ErrorMatches, "flaky: bad data")
+	c.Assert(step.runs, gc.Equals, 1)
+}
+
+func (s *upgradeSuite) TestPerformUpgradeRecordsCompletedStateSteps(c *gc.C) {
+	step := &flakyStep{}
+	s.patchFlakyStateStep(step)
+	state := &mockStateBackend{}
+	ctx := &mockContext{
+		agentConfig: &mockAgentConfig{tag: names.NewMachineTag("0")},
+		state:       state,
+	}
+	from := version.MustParse("1.20.0")
+	err := upgrades.PerformUpgrade(from, targets(upgrades.Controller), ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	vers := version.MustParse("1.21.0")
+	state.CheckCalls(c, []testing.StubCall{
+		{"UpgradeStepCompleted", []interface{}{"machine-0", vers, "flaky"}},
+		{"SetUpgradeStepCompleted", []interface{}{"machine-0", vers, "flaky"}},
+	})
+
+	// Running the upgrade again skips the completed step.
+	state.ResetCalls()
+	err = upgrades.PerformUpgrade(from, targets(upgrades.Controller), ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(step.runs, gc.Equals, 1)
+	state.CheckCallNames(c, "UpgradeStepCompleted")
+}
+
+func (s *upgradeSuite) TestPerformUpgradeRecordsCompletedAPISteps(c *gc.C) {
+	step := &flakyStep{targets: []upgrades.Target{upgrades.AllMachines}}
+	s.PatchValue(upgrades.UpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps:         []upgrades.Step{step},
+			},
+		}
+	})
+	s.PatchValue(&jujuversion.Current, version.MustParse("1.21.0"))
+	completed := &mockStateBackend{}
+	s.PatchValue(upgrades.NewAPICompletedSteps, upgrades.CompletedStepsFunc(completed))
+	ctx := &mockContext{
+		agentConfig: &mockAgentConfig{tag: names.NewMachineTag("1")},
+	}
+	from := version.MustParse("1.20.0")
+	err := upgrades.PerformUpgrade(from, targets(upgrades.HostMachine), ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	vers := version.MustParse("1.21.0")
+	completed.CheckCalls(c, []testing.StubCall{
+		{"UpgradeStepCompleted", []interface{}{"machine-1", vers, "flaky"}},
+		{"SetUpgradeStepCompleted", []interface{}{"machine-1", vers, "flaky"}},
+	})
+
+	// Running the upgrade again skips the completed step.
+	completed.ResetCalls()
+	err = upgrades.PerformUpgrade(from, targets(upgrades.HostMachine), ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(step.runs, gc.Equals, 1)
+	completed.CheckCallNames(c, "UpgradeStepCompleted")
+}
+
 func (s *upgradeSuite) TestUpgradeOperationsOrdered(c *gc.C) {
 	var previous version.Number
 	for i, utv := range (*upgrades.UpgradeOperations)() {
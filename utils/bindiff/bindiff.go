@@ -0,0 +1,426 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package bindiff computes deltas that describe how to make a target
+// file from a base file, and applies them. A delta holds the parts of
+// the target that are not found in the base, so when the two files are
+// similar, as successive builds of a binary are, the delta is much
+// smaller than the target.
+package bindiff
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// magic starts every delta.
+	magic = "bindiff1"
+
+	// blockSize is the size of the base blocks that are searched for
+	// in the target.
+	blockSize = 512
+
+	// maxCandidates is the maximum number of base blocks with the
+	// same weak hash that are compared with the target.
+	maxCandidates = 8
+
+	// lookahead is how much of the target is compared with each
+	// candidate block when choosing between them.
+	lookahead = 64 * 1024
+
+	// chunkSize is how much of the target and base are read at a
+	// time.
+	chunkSize = 32 * 1024
+
+	// maxInsert is the most target data held in memory waiting to be
+	// inserted; longer runs of data not found in the base are written
+	// as several inserts.
+	maxInsert = 1024 * 1024
+
+	// opCopy is followed by the offset and length of data to copy
+	// from the base.
+	opCopy = 'c'
+
+	// opInsert is followed by the length of data to insert, and the
+	// data itself.
+	opInsert = 'i'
+
+	// opEnd is followed by the SHA256 hash of the target, and ends
+	// the delta.
+	opEnd = 'e'
+)
+
+// Diff writes to w a delta that makes target from base, which holds
+// baseSize bytes. The target is read as the delta is written, so only
+// an index of the base's blocks and a window of the target are held
+// in memory.
+func Diff(w io.Writer, base io.ReaderAt, baseSize int64, target io.Reader) error {
+	index, err := indexBlocks(base, baseSize)
+	if err != nil {
+		return errors.Annotate(err, "indexing base")
+	}
+	hash := sha256.New()
+	d := &differ{
+		e:        &encoder{w: bufio.NewWriter(w)},
+		base:     base,
+		baseSize: baseSize,
+		index:    index,
+		target:   io.TeeReader(target, hash),
+		scratch:  make([]byte, lookahead),
+	}
+	d.e.w.WriteString(magic)
+	if err := d.run(); err != nil {
+		return errors.Trace(err)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], hash.Sum(nil))
+	d.e.end(sum)
+	return errors.Trace(d.e.err)
+}
+
+// indexBlocks returns the offsets of the blocks of base, keyed by
+// their weak hashes.
+func indexBlocks(base io.ReaderAt, baseSize int64) (map[uint32][]int64, error) {
+	index := make(map[uint32][]int64)
+	block := make([]byte, blockSize)
+	for offset := int64(0); offset+blockSize <= baseSize; offset += blockSize {
+		if err := readBase(base, block, offset); err != nil {
+			return nil, errors.Trace(err)
+		}
+		var h rollingHash
+		h.reset(block)
+		index[h.sum()] = append(index[h.sum()], offset)
+	}
+	return index, nil
+}
+
+// readBase reads len(p) bytes from base at offset into p.
+func readBase(base io.ReaderAt, p []byte, offset int64) error {
+	n, err := base.ReadAt(p, offset)
+	if n == len(p) {
+		return nil
+	}
+	return errors.Annotate(noEOF(err), "reading base")
+}
+
+// differ holds the state of a Diff.
+type differ struct {
+	e        *encoder
+	base     io.ReaderAt
+	baseSize int64
+	index    map[uint32][]int64
+	target   io.Reader
+	eof      bool
+
+	// buf holds the target data that has been read but not yet
+	// written to the delta.
+	buf []byte
+
+	// scratch holds data read from the base.
+	scratch []byte
+}
+
+// run writes the operations that make the target.
+func (d *differ) run() error {
+	// The data in buf before i is to be inserted; i is where the
+	// next block of the base is looked for.
+	var i int
+	var h rollingHash
+	reset := true
+	for {
+		if err := d.fill(i + blockSize + 1); err != nil {
+			return errors.Trace(err)
+		}
+		if len(d.buf) < i+blockSize {
+			break
+		}
+		if reset {
+			h.reset(d.buf[i : i+blockSize])
+			reset = false
+		}
+		start, err := d.findMatch(i, h.sum())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if start < 0 {
+			if i+blockSize < len(d.buf) {
+				h.roll(d.buf[i], d.buf[i+blockSize])
+			}
+			i++
+			if i == maxInsert {
+				d.e.insert(d.buf[:i])
+				d.buf = d.buf[i:]
+				i = 0
+			}
+			continue
+		}
+		// The match may extend back into the pending insert.
+		back, err := d.matchBack(start, i)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		start -= int64(back)
+		i -= back
+		d.e.insert(d.buf[:i])
+		d.buf = d.buf[i:]
+		i = 0
+		length, err := d.extend(start)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		d.e.copy(start, length)
+		reset = true
+	}
+	d.e.insert(d.buf)
+	d.buf = nil
+	return nil
+}
+
+// fill reads the target until buf holds at least n bytes or all of
+// the target has been read.
+func (d *differ) fill(n int) error {
+	for len(d.buf) < n && !d.eof {
+		if cap(d.buf) < n {
+			buf := make([]byte, len(d.buf), n+chunkSize)
+			copy(buf, d.buf)
+			d.buf = buf
+		}
+		m, err := d.target.Read(d.buf[len(d.buf):cap(d.buf)])
+		d.buf = d.buf[:len(d.buf)+m]
+		if err == io.EOF {
+			d.eof = true
+		} else if err != nil {
+			return errors.Annotate(err, "reading target")
+		}
+	}
+	return nil
+}
+
+// findMatch returns the offset of the base block with weak hash sum
+// that matches the target at buf[i:] for longest, looking no further
+// than lookahead bytes. It returns -1 if no block matches.
+func (d *differ) findMatch(i int, sum uint32) (int64, error) {
+	candidates := d.index[sum]
+	if len(candidates) == 0 {
+		return -1, nil
+	}
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+	if err := d.fill(i + lookahead); err != nil {
+		return -1, errors.Trace(err)
+	}
+	target := d.buf[i:]
+	if len(target) > lookahead {
+		target = target[:lookahead]
+	}
+	bestStart, bestLength := int64(-1), 0
+	for _, start := range candidates {
+		n := len(target)
+		if remaining := d.baseSize - start; remaining < int64(n) {
+			n = int(remaining)
+		}
+		base := d.scratch[:n]
+		if err := readBase(d.base, base, start); err != nil {
+			return -1, errors.Trace(err)
+		}
+		if !bytes.Equal(base[:blockSize], target[:blockSize]) {
+			continue
+		}
+		length := blockSize
+		for length < n && base[length] == target[length] {
+			length++
+		}
+		if length > bestLength {
+			bestStart, bestLength = start, length
+		}
+	}
+	return bestStart, nil
+}
+
+// matchBack returns how many of the bytes in buf before i match those
+// in the base before start.
+func (d *differ) matchBack(start int64, i int) (int, error) {
+	n := i
+	if n > chunkSize {
+		n = chunkSize
+	}
+	if int64(n) > start {
+		n = int(start)
+	}
+	base := d.scratch[:n]
+	if err := readBase(d.base, base, start-int64(n)); err != nil {
+		return 0, errors.Trace(err)
+	}
+	var back int
+	for back < n && base[n-back-1] == d.buf[i-back-1] {
+		back++
+	}
+	return back, nil
+}
+
+// extend returns the length of the match between the base from start
+// and the start of buf, removing the matched data from buf.
+func (d *differ) extend(start int64) (int64, error) {
+	var length int64
+	for {
+		if err := d.fill(chunkSize); err != nil {
+			return 0, errors.Trace(err)
+		}
+		n := len(d.buf)
+		if n > chunkSize {
+			n = chunkSize
+		}
+		if remaining := d.baseSize - start - length; remaining < int64(n) {
+			n = int(remaining)
+		}
+		if n == 0 {
+			return length, nil
+		}
+		base := d.scratch[:n]
+		if err := readBase(d.base, base, start+length); err != nil {
+			return 0, errors.Trace(err)
+		}
+		var k int
+		for k < n && base[k] == d.buf[k] {
+			k++
+		}
+		length += int64(k)
+		d.buf = d.buf[k:]
+		if k < n {
+			return length, nil
+		}
+	}
+}
+
+// rollingHash is a weak checksum of a block of data that can be moved
+// along the data one byte at a time, as used by rsync.
+type rollingHash struct {
+	a, b uint32
+}
+
+func (h *rollingHash) reset(block []byte) {
+	h.a, h.b = 0, 0
+	for i, c := range block {
+		h.a += uint32(c)
+		h.b += uint32(len(block)-i) * uint32(c)
+	}
+}
+
+// roll moves the hashed block along by one byte, removing out from the
+// start and adding in at the end.
+func (h *rollingHash) roll(out, in byte) {
+	h.a += uint32(in) - uint32(out)
+	h.b += h.a - blockSize*uint32(out)
+}
+
+func (h *rollingHash) sum() uint32 {
+	return h.a&0xffff | h.b<<16
+}
+
+// encoder writes delta operations, remembering the first error.
+type encoder struct {
+	w   *bufio.Writer
+	err error
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (e *encoder) uvarint(v int64) {
+	n := binary.PutUvarint(e.buf[:], uint64(v))
+	e.w.Write(e.buf[:n])
+}
+
+func (e *encoder) insert(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	e.w.WriteByte(opInsert)
+	e.uvarint(int64(len(data)))
+	e.w.Write(data)
+}
+
+func (e *encoder) copy(offset, length int64) {
+	e.w.WriteByte(opCopy)
+	e.uvarint(offset)
+	e.uvarint(length)
+}
+
+func (e *encoder) end(hash [sha256.Size]byte) {
+	e.w.WriteByte(opEnd)
+	e.w.Write(hash[:])
+	// bufio.Writer remembers the first write error, and returns it
+	// from Flush.
+	e.err = e.w.Flush()
+}
+
+// Patch applies delta to base, writing the target to w. The target is
+// checked against the hash recorded in the delta, but only after it
+// has all been written; if Patch returns an error, the data written to
+// w must be discarded.
+func Patch(w io.Writer, base io.ReaderAt, delta io.Reader) error {
+	r := bufio.NewReader(delta)
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, header); err != nil || string(header) != magic {
+		return errors.New("invalid delta header")
+	}
+	hash := sha256.New()
+	out := io.MultiWriter(w, hash)
+	for {
+		op, err := r.ReadByte()
+		if err != nil {
+			return errors.Annotate(noEOF(err), "reading delta")
+		}
+		switch op {
+		case opCopy:
+			offset, err := binary.ReadUvarint(r)
+			if err != nil {
+				return errors.Annotate(noEOF(err), "reading delta")
+			}
+			length, err := binary.ReadUvarint(r)
+			if err != nil {
+				return errors.Annotate(noEOF(err), "reading delta")
+			}
+			n, err := io.Copy(out, io.NewSectionReader(base, int64(offset), int64(length)))
+			if err != nil {
+				return errors.Annotate(err, "copying from base")
+			}
+			if uint64(n) != length {
+				return errors.Errorf("base too short: cannot copy %d bytes at offset %d", length, offset)
+			}
+		case opInsert:
+			length, err := binary.ReadUvarint(r)
+			if err != nil {
+				return errors.Annotate(noEOF(err), "reading delta")
+			}
+			if _, err := io.CopyN(out, r, int64(length)); err != nil {
+				return errors.Annotate(noEOF(err), "reading delta")
+			}
+		case opEnd:
+			var expect [sha256.Size]byte
+			if _, err := io.ReadFull(r, expect[:]); err != nil {
+				return errors.Annotate(noEOF(err), "reading delta")
+			}
+			if actual := hash.Sum(nil); !bytes.Equal(actual, expect[:]) {
+				return errors.Errorf("target SHA256 mismatch, expected %x, got %x", expect, actual)
+			}
+			return nil
+		default:
+			return errors.Errorf("invalid delta operation %q", op)
+		}
+	}
+}
+
+// noEOF returns io.ErrUnexpectedEOF in place of io.EOF, since a
+// delta must not end before its end operation.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bindiff_test
+
+import (
+	"bytes"
+	"math/rand"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/bindiff"
+)
+
+type bindiffSuite struct{}
+
+var _ = gc.Suite(&bindiffSuite{})
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	data := make([]byte, n)
+	r.Read(data)
+	return data
+}
+
+func diff(c *gc.C, base, target []byte) []byte {
+	var delta bytes.Buffer
+	err := bindiff.Diff(&delta, bytes.NewReader(base), int64(len(base)), bytes.NewReader(target))
+	c.Assert(err, jc.ErrorIsNil)
+	return delta.Bytes()
+}
+
+func (*bindiffSuite) TestRoundTrip(c *gc.C) {
+	r := rand.New(rand.NewSource(1))
+	base := randomBytes(r, 1<<20)
+	var target []byte
+	target = append(target, base[:300000]...)
+	target = append(target, "inserted"...)
+	target = append(target, base[300100:700000]...)
+	target = append(target, randomBytes(r, 5000)...)
+	target = append(target, base[10:5000]...)
+
+	delta := diff(c, base, target)
+	// The delta holds little more than the data not in the base.
+	c.Assert(len(delta) < 6000, jc.IsTrue, gc.Commentf("delta is %d bytes", len(delta)))
+
+	var result bytes.Buffer
+	err := bindiff.Patch(&result, bytes.NewReader(base), bytes.NewReader(delta))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bytes.Equal(result.Bytes(), target), jc.IsTrue)
+}
+
+func (*bindiffSuite) TestSmallTargets(c *gc.C) {
+	base := randomBytes(rand.New(rand.NewSource(2)), 4096)
+	for _, target := range [][]byte{nil, []byte("short"), base} {
+		delta := diff(c, base, target)
+		var result bytes.Buffer
+		err := bindiff.Patch(&result, bytes.NewReader(base), bytes.NewReader(delta))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(bytes.Equal(result.Bytes(), target), jc.IsTrue)
+	}
+}
+
+func (*bindiffSuite) TestLongInsert(c *gc.C) {
+	// Data not found in the base is inserted in pieces, so that
+	// the target need not all be held in memory.
+	r := rand.New(rand.NewSource(4))
+	base := randomBytes(r, 8192)
+	target := append(randomBytes(r, 3<<20), base...)
+	delta := diff(c, base, target)
+	c.Assert(len(delta) < len(target), jc.IsTrue)
+
+	var result bytes.Buffer
+	err := bindiff.Patch(&result, bytes.NewReader(base), bytes.NewReader(delta))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bytes.Equal(result.Bytes(), target), jc.IsTrue)
+}
+
+func (*bindiffSuite) TestPatchWrongBase(c *gc.C) {
+	r := rand.New(rand.NewSource(3))
+	base := randomBytes(r, 8192)
+	target := append(append([]byte{}, base...), "more"...)
+	delta := diff(c, base, target)
+
+	err := bindiff.Patch(&bytes.Buffer{}, bytes.NewReader(base[:4096]), bytes.NewReader(delta))
+	c.Assert(err, gc.ErrorMatches, "base too short: cannot copy .*")
+
+	otherBase := append([]byte{}, base...)
+	otherBase[0]++
+	err = bindiff.Patch(&bytes.Buffer{}, bytes.NewReader(otherBase), bytes.NewReader(delta))
+	c.Assert(err, gc.ErrorMatches, "target SHA256 mismatch, expected [0-9a-f]+, got [0-9a-f]+")
+}
+
+func (*bindiffSuite) TestPatchInvalidDelta(c *gc.C) {
+	delta := diff(c, []byte("base"), []byte("target"))
+	err := bindiff.Patch(&bytes.Buffer{}, bytes.NewReader(nil), bytes.NewReader(delta[1:]))
+	c.Assert(err, gc.ErrorMatches, "invalid delta header")
+	err = bindiff.Patch(&bytes.Buffer{}, bytes.NewReader(nil), bytes.NewReader(delta[:len(delta)-1]))
+	c.Assert(err, gc.ErrorMatches, "reading delta: unexpected EOF")
+}
@@ -33,3 +33,7 @@ func NewTestSimpleContext(agentConfig agent.Config, logDir string, data *svctest
 		},
 	}
 }
+
+func NewTestNestedContext(agentConfig agent.Config, logDir string, data *svctesting.FakeServiceData, newUnitWorker NewUnitWorkerFunc) (*NestedContext, error) {
+	return newNestedContext(NewTestSimpleContext(agentConfig, logDir, data), newUnitWorker)
+}
@@ -21,7 +21,7 @@ import (
 type ManifoldConfig struct {
 	AgentName        string
 	APICallerName    string
-	NewDeployContext func(st *apideployer.State, agentConfig agent.Config) Context
+	NewDeployContext func(st *apideployer.State, agentConfig agent.Config) (Context, error)
 }
 
 // Manifold returns a dependency manifold that runs a deployer worker,
@@ -68,9 +68,15 @@ func (config ManifoldConfig) newWorker(a agent.Agent, apiCaller base.APICaller)
 	}
 
 	deployerFacade := apideployer.NewState(apiCaller)
-	context := config.NewDeployContext(deployerFacade, cfg)
+	context, err := config.NewDeployContext(deployerFacade, cfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create unit agent deploy context")
+	}
 	w, err := NewDeployer(deployerFacade, context)
 	if err != nil {
+		if contextWorker, ok := context.(worker.Worker); ok {
+			worker.Stop(contextWorker)
+		}
 		return nil, errors.Annotate(err, "cannot start unit agent deployer worker")
 	}
 	return w, nil
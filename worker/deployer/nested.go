@@ -0,0 +1,218 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	jworker "github.com/juju/juju/worker"
+)
+
+// NewUnitWorkerFunc returns a worker that runs the agent for the named
+// unit, whose configuration has already been written to the machine
+// agent's data directory.
+type NewUnitWorkerFunc func(unitName string) (worker.Worker, error)
+
+// NestedContext is a Context that runs unit agents inside the machine
+// agent process, rather than installing an init service for each one.
+//
+// Units that were deployed with a SimpleContext before the machine
+// agent switched over continue to run under their init services, and
+// are recalled through them.
+type NestedContext struct {
+	simple        *SimpleContext
+	newUnitWorker NewUnitWorkerFunc
+	runner        *worker.Runner
+
+	// workers holds the most recently started worker for each unit
+	// deployed by the context; the value is nil until the runner
+	// first starts it.
+	mu      sync.Mutex
+	workers map[string]worker.Worker
+}
+
+var _ Context = (*NestedContext)(nil)
+
+// NewNestedContext returns a new NestedContext, acting on behalf of the
+// machine agent with the supplied config, and starts the agents of any
+// units it has previously deployed.
+func NewNestedContext(agentConfig agent.Config, api APICalls, newUnitWorker NewUnitWorkerFunc) (*NestedContext, error) {
+	return newNestedContext(NewSimpleContext(agentConfig, api), newUnitWorker)
+}
+
+func newNestedContext(simple *SimpleContext, newUnitWorker NewUnitWorkerFunc) (*NestedContext, error) {
+	ctx := &NestedContext{
+		simple:        simple,
+		newUnitWorker: newUnitWorker,
+		runner: worker.NewRunner(worker.RunnerParams{
+			// A failing unit agent must never take down the
+			// others, so errors are never fatal to the runner.
+			IsFatal:      func(error) bool { return false },
+			RestartDelay: jworker.RestartDelay,
+		}),
+		workers: make(map[string]worker.Worker),
+	}
+	units, err := ctx.nestedUnits()
+	if err != nil {
+		worker.Stop(ctx.runner)
+		return nil, errors.Trace(err)
+	}
+	for _, unitName := range units {
+		if err := ctx.startUnit(unitName); err != nil {
+			worker.Stop(ctx.runner)
+			return nil, errors.Trace(err)
+		}
+	}
+	return ctx, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (ctx *NestedContext) Kill() {
+	ctx.runner.Kill()
+}
+
+// Wait is part of the worker.Worker interface.
+func (ctx *NestedContext) Wait() error {
+	return ctx.runner.Wait()
+}
+
+// AgentConfig is part of the Context interface.
+func (ctx *NestedContext) AgentConfig() agent.Config {
+	return ctx.simple.agentConfig
+}
+
+// DeployUnit is part of the Context interface.
+func (ctx *NestedContext) DeployUnit(unitName, initialPassword string) (err error) {
+	deployed, err := ctx.DeployedUnits()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range deployed {
+		if name == unitName {
+			return fmt.Errorf("unit %q is already deployed", unitName)
+		}
+	}
+
+	agentConfig := ctx.simple.agentConfig
+	if err := writeUnitAgent(agentConfig, ctx.simple.api, unitName, initialPassword); err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			if err := removeUnitAgent(agentConfig.DataDir(), unitName); err != nil {
+				logger.Errorf("cannot remove agent for unit %q: %v", unitName, err)
+			}
+		}
+	}()
+	return errors.Trace(ctx.startUnit(unitName))
+}
+
+// RecallUnit is part of the Context interface.
+func (ctx *NestedContext) RecallUnit(unitName string) error {
+	jobs, err := ctx.simple.deployedUnitsInitSystemJobs()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := jobs[unitName]; ok {
+		return ctx.simple.RecallUnit(unitName)
+	}
+
+	ctx.mu.Lock()
+	w, ok := ctx.workers[unitName]
+	delete(ctx.workers, unitName)
+	ctx.mu.Unlock()
+	if !ok {
+		return errors.Errorf("unit %q is not deployed", unitName)
+	}
+	if err := ctx.runner.StopWorker(unitName); err != nil {
+		return errors.Trace(err)
+	}
+	// The runner does not wait for the worker to stop, but the unit's
+	// workers must be finished with its data before we remove it.
+	if w != nil {
+		if err := w.Wait(); err != nil {
+			logger.Debugf("agent for unit %q stopped: %v", unitName, err)
+		}
+	}
+	return removeUnitAgent(ctx.simple.agentConfig.DataDir(), unitName)
+}
+
+// DeployedUnits is part of the Context interface.
+func (ctx *NestedContext) DeployedUnits() ([]string, error) {
+	installed, err := ctx.simple.DeployedUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nested, err := ctx.nestedUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(installed, nested...), nil
+}
+
+// nestedUnits returns the names of the units with agent configuration
+// in the data directory, but no init service.
+func (ctx *NestedContext) nestedUnits() ([]string, error) {
+	jobs, err := ctx.simple.deployedUnitsInitSystemJobs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	fis, err := ioutil.ReadDir(agent.BaseDir(ctx.simple.agentConfig.DataDir()))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var units []string
+	for _, fi := range fis {
+		if !fi.IsDir() {
+			continue
+		}
+		tag, err := names.ParseUnitTag(fi.Name())
+		if err != nil {
+			continue
+		}
+		if _, ok := jobs[tag.Id()]; ok {
+			continue
+		}
+		units = append(units, tag.Id())
+	}
+	return units, nil
+}
+
+// startUnit starts the named unit's agent in the runner, recording each
+// worker started so that RecallUnit may wait for it to finish.
+func (ctx *NestedContext) startUnit(unitName string) error {
+	logger.Infof("starting agent for unit %q", unitName)
+	ctx.mu.Lock()
+	ctx.workers[unitName] = nil
+	ctx.mu.Unlock()
+	err := ctx.runner.StartWorker(unitName, func() (worker.Worker, error) {
+		w, err := ctx.newUnitWorker(unitName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ctx.mu.Lock()
+		if _, ok := ctx.workers[unitName]; ok {
+			ctx.workers[unitName] = w
+		}
+		ctx.mu.Unlock()
+		return w, nil
+	})
+	if err != nil {
+		ctx.mu.Lock()
+		delete(ctx.workers, unitName)
+		ctx.mu.Unlock()
+		return errors.Trace(err)
+	}
+	return nil
+}
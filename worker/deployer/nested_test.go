@@ -0,0 +1,141 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package deployer_test
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/deployer"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type NestedContextSuite struct {
+	testing.BaseSuite
+	SimpleToolsFixture
+
+	mu      sync.Mutex
+	workers map[string]worker.Worker
+	started chan string
+}
+
+var _ = gc.Suite(&NestedContextSuite{})
+
+func (s *NestedContextSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.SimpleToolsFixture.SetUp(c, c.MkDir())
+	s.workers = make(map[string]worker.Worker)
+	s.started = make(chan string, 10)
+}
+
+func (s *NestedContextSuite) TearDownTest(c *gc.C) {
+	s.SimpleToolsFixture.TearDown(c)
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *NestedContextSuite) newUnitWorker(unitName string) (worker.Worker, error) {
+	w := workertest.NewErrorWorker(nil)
+	s.mu.Lock()
+	s.workers[unitName] = w
+	s.mu.Unlock()
+	s.started <- unitName
+	return w, nil
+}
+
+func (s *NestedContextSuite) getContext(c *gc.C) *deployer.NestedContext {
+	config := agentConfig(names.NewMachineTag("99"), s.dataDir, s.logDir)
+	ctx, err := deployer.NewTestNestedContext(config, s.logDir, s.data, s.newUnitWorker)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, ctx) })
+	return ctx
+}
+
+func (s *NestedContextSuite) assertStarted(c *gc.C, unitName string) {
+	select {
+	case name := <-s.started:
+		c.Assert(name, gc.Equals, unitName)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("agent for unit %q not started", unitName)
+	}
+}
+
+func (s *NestedContextSuite) TestDeployRecall(c *gc.C) {
+	ctx := s.getContext(c)
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+
+	err = ctx.DeployUnit("foo/123", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStarted(c, "foo/123")
+	s.assertUpstartCount(c, 0)
+
+	conf, err := agent.ReadConfig(agent.ConfigPath(s.dataDir, names.NewUnitTag("foo/123")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conf.Tag(), gc.Equals, names.NewUnitTag("foo/123"))
+
+	units, err = ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.DeepEquals, []string{"foo/123"})
+
+	err = ctx.DeployUnit("foo/123", "some-password")
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is already deployed`)
+
+	err = ctx.RecallUnit("foo/123")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mu.Lock()
+	w := s.workers["foo/123"]
+	s.mu.Unlock()
+	workertest.CheckKilled(c, w)
+	units, err = ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+	s.checkUnitRemoved(c, "foo/123")
+
+	err = ctx.RecallUnit("foo/123")
+	c.Assert(err, gc.ErrorMatches, `unit "foo/123" is not deployed`)
+}
+
+func (s *NestedContextSuite) TestRestartsDeployedUnits(c *gc.C) {
+	ctx := s.getContext(c)
+	err := ctx.DeployUnit("foo/123", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStarted(c, "foo/123")
+	workertest.CleanKill(c, ctx)
+
+	ctx = s.getContext(c)
+	s.assertStarted(c, "foo/123")
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.DeepEquals, []string{"foo/123"})
+}
+
+func (s *NestedContextSuite) TestServiceUnitsRecalledThroughService(c *gc.C) {
+	s.injectUnit(c, "jujud-unit-mysql-0", "unit-mysql-0")
+	ctx := s.getContext(c)
+
+	err := ctx.DeployUnit("foo/123", "some-password")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStarted(c, "foo/123")
+
+	units, err := ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	sort.Strings(units)
+	c.Assert(units, gc.DeepEquals, []string{"foo/123", "mysql/0"})
+
+	err = ctx.RecallUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpstartCount(c, 0)
+	units, err = ctx.DeployedUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.DeepEquals, []string{"foo/123"})
+}
@@ -80,7 +80,15 @@ func NewSimpleContext(agentConfig agent.Config, api APICalls) *SimpleContext {
 			return service.DiscoverService(name, conf)
 		},
 		listServices: func() ([]string, error) {
-			return service.ListServices()
+			infos, err := service.ListServicesWithInfo()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			names := make([]string, len(infos))
+			for i, info := range infos {
+				names[i] = info.Name
+			}
+			return names, nil
 		},
 	}
 }
@@ -107,63 +115,13 @@ func (ctx *SimpleContext) DeployUnit(unitName, initialPassword string) (err erro
 		return fmt.Errorf("unit %q is already deployed", unitName)
 	}
 
-	// Link the current tools for use by the new agent.
-	tag := names.NewUnitTag(unitName)
-	dataDir := ctx.agentConfig.DataDir()
-	logDir := ctx.agentConfig.LogDir()
-	hostSeries, err := series.HostSeries()
-	if err != nil {
-		return errors.Trace(err)
-	}
-	current := version.Binary{
-		Number: jujuversion.Current,
-		Arch:   arch.HostArch(),
-		Series: hostSeries,
-	}
-	toolsDir := tools.ToolsDir(dataDir, tag.String())
-	defer removeOnErr(&err, toolsDir)
-	_, err = tools.ChangeAgentTools(dataDir, tag.String(), current)
-	if err != nil {
-		return errors.Trace(err)
-	}
-
-	result, err := ctx.api.ConnectionInfo()
-	if err != nil {
+	if err := writeUnitAgent(ctx.agentConfig, ctx.api, unitName, initialPassword); err != nil {
 		return errors.Trace(err)
 	}
-	logger.Debugf("state addresses: %q", result.StateAddresses)
-	logger.Debugf("API addresses: %q", result.APIAddresses)
-	containerType := ctx.agentConfig.Value(agent.ContainerType)
-	namespace := ctx.agentConfig.Value(agent.Namespace)
-	conf, err := agent.NewAgentConfig(
-		agent.AgentConfigParams{
-			Paths: agent.Paths{
-				DataDir:         dataDir,
-				LogDir:          logDir,
-				MetricsSpoolDir: agent.DefaultPaths.MetricsSpoolDir,
-			},
-			UpgradedToVersion: jujuversion.Current,
-			Tag:               tag,
-			Password:          initialPassword,
-			Nonce:             "unused",
-			Controller:        ctx.agentConfig.Controller(),
-			Model:             ctx.agentConfig.Model(),
-			// TODO: remove the state addresses here and test when api only.
-			StateAddresses: result.StateAddresses,
-			APIAddresses:   result.APIAddresses,
-			CACert:         ctx.agentConfig.CACert(),
-			Values: map[string]string{
-				agent.ContainerType: containerType,
-				agent.Namespace:     namespace,
-			},
-		})
-	if err != nil {
-		return errors.Trace(err)
-	}
-	if err := conf.Write(); err != nil {
-		return err
-	}
-	defer removeOnErr(&err, conf.Dir())
+	tag := names.NewUnitTag(unitName)
+	dataDir := ctx.agentConfig.DataDir()
+	defer removeOnErr(&err, tools.ToolsDir(dataDir, tag.String()))
+	defer removeOnErr(&err, agent.Dir(dataDir, tag))
 
 	// Install an init service that runs the unit agent.
 	if err := service.InstallAndStart(svc); err != nil {
@@ -213,21 +171,7 @@ func (ctx *SimpleContext) RecallUnit(unitName string) error {
 	if err := svc.Remove(); err != nil {
 		return err
 	}
-	tag := names.NewUnitTag(unitName)
-	dataDir := ctx.agentConfig.DataDir()
-	agentDir := agent.Dir(dataDir, tag)
-	// Recursivley change mode to 777 on windows to avoid
-	// Operation not permitted errors when deleting the agentDir
-	err = recursiveChmod(agentDir, os.FileMode(0777))
-	if err != nil {
-		return err
-	}
-	if err := os.RemoveAll(agentDir); err != nil {
-		return err
-	}
-	// TODO(dfc) should take a Tag
-	toolsDir := tools.ToolsDir(dataDir, tag.String())
-	return os.Remove(toolsDir)
+	return removeUnitAgent(ctx.agentConfig.DataDir(), unitName)
 }
 
 var deployedRe = regexp.MustCompile("^(jujud-.*unit-([a-z0-9-]+)-([0-9]+))$")
@@ -288,6 +232,84 @@ func (ctx *SimpleContext) service(unitName string, renderer shell.Renderer) (dep
 	return ctx.discoverService(svcName, conf)
 }
 
+// writeUnitAgent links the current tools for use by the named unit's
+// agent, and writes the agent's configuration to the data directory of
+// the supplied machine agent config.
+func writeUnitAgent(agentConfig agent.Config, api APICalls, unitName, initialPassword string) (err error) {
+	tag := names.NewUnitTag(unitName)
+	dataDir := agentConfig.DataDir()
+	logDir := agentConfig.LogDir()
+	hostSeries, err := series.HostSeries()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current := version.Binary{
+		Number: jujuversion.Current,
+		Arch:   arch.HostArch(),
+		Series: hostSeries,
+	}
+	toolsDir := tools.ToolsDir(dataDir, tag.String())
+	defer removeOnErr(&err, toolsDir)
+	_, err = tools.ChangeAgentTools(dataDir, tag.String(), current)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	result, err := api.ConnectionInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("state addresses: %q", result.StateAddresses)
+	logger.Debugf("API addresses: %q", result.APIAddresses)
+	containerType := agentConfig.Value(agent.ContainerType)
+	namespace := agentConfig.Value(agent.Namespace)
+	conf, err := agent.NewAgentConfig(
+		agent.AgentConfigParams{
+			Paths: agent.Paths{
+				DataDir:         dataDir,
+				LogDir:          logDir,
+				MetricsSpoolDir: agent.DefaultPaths.MetricsSpoolDir,
+			},
+			UpgradedToVersion: jujuversion.Current,
+			Tag:               tag,
+			Password:          initialPassword,
+			Nonce:             "unused",
+			Controller:        agentConfig.Controller(),
+			Model:             agentConfig.Model(),
+			// TODO: remove the state addresses here and test when api only.
+			StateAddresses: result.StateAddresses,
+			APIAddresses:   result.APIAddresses,
+			CACert:         agentConfig.CACert(),
+			Values: map[string]string{
+				agent.ContainerType: containerType,
+				agent.Namespace:     namespace,
+			},
+		})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return conf.Write()
+}
+
+// removeUnitAgent removes the named unit agent's configuration and
+// tools from the data directory.
+func removeUnitAgent(dataDir, unitName string) error {
+	tag := names.NewUnitTag(unitName)
+	agentDir := agent.Dir(dataDir, tag)
+	// Recursivley change mode to 777 on windows to avoid
+	// Operation not permitted errors when deleting the agentDir
+	err := recursiveChmod(agentDir, os.FileMode(0777))
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(agentDir); err != nil {
+		return err
+	}
+	// TODO(dfc) should take a Tag
+	toolsDir := tools.ToolsDir(dataDir, tag.String())
+	return os.Remove(toolsDir)
+}
+
 func removeOnErr(err *error, path string) {
 	if *err != nil {
 		if err := os.RemoveAll(path); err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/catacomb"
@@ -15,12 +16,12 @@ import (
 
 var logger = loggo.GetLogger("juju.worker.environ")
 
-// ConfigObserver exposes a model configuration and a watch constructor
-// that allows clients to be informed of changes to the configuration.
+// ConfigObserver exposes a model configuration and cloud spec, and watch
+// constructors that allow clients to be informed of changes to them.
 type ConfigObserver interface {
 	environs.EnvironConfigGetter
 	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
-	WatchCredential(tag names.CloudCredentialTag) (watcher.NotifyWatcher, error)
+	WatchCloudSpecChanges(tag names.ModelTag) (watcher.NotifyWatcher, error)
 }
 
 // Config describes the dependencies of a Tracker.
@@ -44,7 +45,9 @@ func (config Config) Validate() error {
 }
 
 // Tracker loads an environment, makes it available to clients, and updates
-// the environment in response to config changes until it is killed.
+// the environment in response to config changes, and credential changes
+// if the environment implements environs.CloudSpecSetter, until it is
+// killed.
 type Tracker struct {
 	config   Config
 	catacomb catacomb.Catacomb
@@ -94,6 +97,24 @@ func (t *Tracker) loop() error {
 	if err := t.catacomb.Add(environWatcher); err != nil {
 		return errors.Trace(err)
 	}
+	var modelTag names.ModelTag
+	var cloudSpecChanges watcher.NotifyChannel
+	cloudSpecSetter, _ := t.environ.(environs.CloudSpecSetter)
+	if cloudSpecSetter != nil {
+		modelTag = names.NewModelTag(t.environ.Config().UUID())
+		cloudSpecWatcher, err := t.config.Observer.WatchCloudSpecChanges(modelTag)
+		switch {
+		case params.IsCodeNotFound(err) || errors.IsNotSupported(err):
+			logger.Debugf("not watching cloud spec: %v", err)
+		case err != nil:
+			return errors.Annotate(err, "cannot watch cloud spec")
+		default:
+			if err := t.catacomb.Add(cloudSpecWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			cloudSpecChanges = cloudSpecWatcher.Changes()
+		}
+	}
 	for {
 		logger.Debugf("waiting for environ watch notification")
 		select {
@@ -103,14 +124,26 @@ func (t *Tracker) loop() error {
 			if !ok {
 				return errors.New("environ config watch closed")
 			}
-		}
-		logger.Debugf("reloading environ config")
-		modelConfig, err := t.config.Observer.ModelConfig()
-		if err != nil {
-			return errors.Annotate(err, "cannot read environ config")
-		}
-		if err = t.environ.SetConfig(modelConfig); err != nil {
-			return errors.Annotate(err, "cannot update environ config")
+			logger.Debugf("reloading environ config")
+			modelConfig, err := t.config.Observer.ModelConfig()
+			if err != nil {
+				return errors.Annotate(err, "cannot read environ config")
+			}
+			if err = t.environ.SetConfig(modelConfig); err != nil {
+				return errors.Annotate(err, "cannot update environ config")
+			}
+		case _, ok := <-cloudSpecChanges:
+			if !ok {
+				return errors.New("cloud spec watch closed")
+			}
+			logger.Debugf("reloading cloud spec")
+			cloudSpec, err := t.config.Observer.CloudSpec(modelTag)
+			if err != nil {
+				return errors.Annotate(err, "cannot read cloud spec")
+			}
+			if err = cloudSpecSetter.SetCloudSpec(cloudSpec); err != nil {
+				return errors.Annotate(err, "cannot update environ cloud spec")
+			}
 		}
 	}
 }
@@ -7,9 +7,12 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/environ"
@@ -241,3 +244,95 @@ func (s *TrackerSuite) TestWatchedModelConfigUpdates(c *gc.C) {
 		context.CheckCallNames(c, "ModelConfig", "CloudSpec", "WatchForModelConfigChanges", "ModelConfig")
 	})
 }
+
+func (s *TrackerSuite) TestWatchedCloudSpecUpdates(c *gc.C) {
+	fix := &fixture{
+		cloud: environs.CloudSpec{
+			Name: "foo",
+			Type: "bar",
+		},
+	}
+	fix.Run(c, func(context *runContext) {
+		tracker, err := environ.NewTracker(environ.Config{
+			Observer:       context,
+			NewEnvironFunc: newMockCloudSpecEnviron,
+		})
+		c.Check(err, jc.ErrorIsNil)
+		defer workertest.CleanKill(c, tracker)
+
+		updated := environs.CloudSpec{
+			Name:   "foo",
+			Type:   "bar",
+			Region: "updated",
+		}
+		context.SetCloudSpec(updated)
+		gotEnviron := tracker.Environ().(*mockCloudSpecEnviron)
+
+		timeout := time.After(coretesting.LongWait)
+		attempt := time.After(0)
+		context.SendCloudSpecNotify()
+		for {
+			select {
+			case <-attempt:
+				spec := gotEnviron.CloudSpec()
+				if spec.Region == "" {
+					attempt = time.After(coretesting.ShortWait)
+					continue
+				}
+				c.Check(spec, jc.DeepEquals, updated)
+			case <-timeout:
+				c.Fatalf("timed out waiting for environ to be updated")
+			}
+			break
+		}
+		modelTag := names.NewModelTag(gotEnviron.Config().UUID())
+		context.stub.CheckCalls(c, []testing.StubCall{
+			{"ModelConfig", nil},
+			{"CloudSpec", []interface{}{modelTag}},
+			{"WatchForModelConfigChanges", nil},
+			{"WatchCloudSpecChanges", []interface{}{modelTag}},
+			{"CloudSpec", []interface{}{modelTag}},
+		})
+	})
+}
+
+func (s *TrackerSuite) TestWatchedCloudSpecIncompatible(c *gc.C) {
+	fix := &fixture{}
+	fix.Run(c, func(context *runContext) {
+		tracker, err := environ.NewTracker(environ.Config{
+			Observer: context,
+			NewEnvironFunc: func(args environs.OpenParams) (environs.Environ, error) {
+				env, err := newMockCloudSpecEnviron(args)
+				env.(*mockCloudSpecEnviron).SetErrors(errors.New("SetCloudSpec is broken"))
+				return env, err
+			},
+		})
+		c.Check(err, jc.ErrorIsNil)
+		defer workertest.DirtyKill(c, tracker)
+
+		context.SendCloudSpecNotify()
+		err = workertest.CheckKilled(c, tracker)
+		c.Check(err, gc.ErrorMatches, "cannot update environ cloud spec: SetCloudSpec is broken")
+		context.CheckCallNames(c, "ModelConfig", "CloudSpec", "WatchForModelConfigChanges", "WatchCloudSpecChanges", "CloudSpec")
+	})
+}
+
+func (s *TrackerSuite) TestCloudSpecNotWatchedWithoutCredential(c *gc.C) {
+	fix := &fixture{
+		observerErrs: []error{
+			nil, nil, nil, &params.Error{Code: params.CodeNotFound, Message: "credential not found"},
+		},
+	}
+	fix.Run(c, func(context *runContext) {
+		tracker, err := environ.NewTracker(environ.Config{
+			Observer:       context,
+			NewEnvironFunc: newMockCloudSpecEnviron,
+		})
+		c.Check(err, jc.ErrorIsNil)
+		defer workertest.CleanKill(c, tracker)
+
+		context.SendCloudSpecNotify()
+		workertest.CheckAlive(c, tracker)
+		context.CheckCallNames(c, "ModelConfig", "CloudSpec", "WatchForModelConfigChanges", "WatchCloudSpecChanges")
+	})
+}
@@ -28,10 +28,13 @@ type fixture struct {
 func (fix *fixture) Run(c *gc.C, test func(*runContext)) {
 	watcher := newNotifyWatcher(fix.watcherErr)
 	defer workertest.DirtyKill(c, watcher)
+	credWatcher := newNotifyWatcher(fix.watcherErr)
+	defer workertest.DirtyKill(c, credWatcher)
 	context := &runContext{
-		cloud:   fix.cloud,
-		config:  newModelConfig(c, fix.initialConfig),
-		watcher: watcher,
+		cloud:       fix.cloud,
+		config:      newModelConfig(c, fix.initialConfig),
+		watcher:     watcher,
+		credWatcher: credWatcher,
 	}
 	context.stub.SetErrors(fix.observerErrs...)
 	test(context)
@@ -103,32 +106,39 @@ func (context *runContext) WatchForModelConfigChanges() (watcher.NotifyWatcher,
 	return context.watcher, nil
 }
 
-// KillCredentialNotify kills the watcher returned from WatchCredentialChanges with
+// SetCloudSpec updates the cloud spec returned by CloudSpec.
+func (context *runContext) SetCloudSpec(spec environs.CloudSpec) {
+	context.mu.Lock()
+	defer context.mu.Unlock()
+	context.cloud = spec
+}
+
+// KillCloudSpecNotify kills the watcher returned from WatchCloudSpecChanges with
 // the error configured in the enclosing fixture.
-func (context *runContext) KillCredentialNotify() {
+func (context *runContext) KillCloudSpecNotify() {
 	context.credWatcher.Kill()
 }
 
-// SendCredentialNotify sends a value on the channel used by WatchCredentialChanges
+// SendCloudSpecNotify sends a value on the channel used by WatchCloudSpecChanges
 // results.
-func (context *runContext) SendCredentialNotify() {
+func (context *runContext) SendCloudSpecNotify() {
 	context.credWatcher.changes <- struct{}{}
 }
 
-// CloseCredentialNotify closes the channel used by WatchCredentialChanges results.
-func (context *runContext) CloseCredentialNotify() {
+// CloseCloudSpecNotify closes the channel used by WatchCloudSpecChanges results.
+func (context *runContext) CloseCloudSpecNotify() {
 	close(context.credWatcher.changes)
 }
 
-// WatchCredential is part of the environ.ConfigObserver interface.
-func (context *runContext) WatchCredential(cred names.CloudCredentialTag) (watcher.NotifyWatcher, error) {
+// WatchCloudSpecChanges is part of the environ.ConfigObserver interface.
+func (context *runContext) WatchCloudSpecChanges(tag names.ModelTag) (watcher.NotifyWatcher, error) {
 	context.mu.Lock()
 	defer context.mu.Unlock()
-	context.stub.AddCall("WatchCredential")
+	context.stub.AddCall("WatchCloudSpecChanges", tag)
 	if err := context.stub.NextErr(); err != nil {
 		return nil, err
 	}
-	return context.watcher, nil
+	return context.credWatcher, nil
 }
 
 func (context *runContext) CheckCallNames(c *gc.C, names ...string) {
@@ -191,3 +201,32 @@ func (e *mockEnviron) SetConfig(cfg *config.Config) error {
 func newMockEnviron(args environs.OpenParams) (environs.Environ, error) {
 	return &mockEnviron{cfg: args.Config}, nil
 }
+
+type mockCloudSpecEnviron struct {
+	mockEnviron
+	spec environs.CloudSpec
+}
+
+func (e *mockCloudSpecEnviron) CloudSpec() environs.CloudSpec {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.spec
+}
+
+func (e *mockCloudSpecEnviron) SetCloudSpec(spec environs.CloudSpec) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.MethodCall(e, "SetCloudSpec", spec)
+	if err := e.NextErr(); err != nil {
+		return err
+	}
+	e.spec = spec
+	return nil
+}
+
+func newMockCloudSpecEnviron(args environs.OpenParams) (environs.Environ, error) {
+	return &mockCloudSpecEnviron{
+		mockEnviron: mockEnviron{cfg: args.Config},
+		spec:        args.Cloud,
+	}, nil
+}
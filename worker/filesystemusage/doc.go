@@ -0,0 +1,9 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package filesystemusage defines a worker that periodically reports
+// the space used on the filesystems attached to the machine it runs
+// on, so that operators and charms can act before a filesystem fills
+// up. This worker will be run on all Juju-managed machines (one per
+// machine agent).
+package filesystemusage
@@ -0,0 +1,84 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package filesystemusage
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	jworker "github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+)
+
+var logger = loggo.GetLogger("juju.worker.filesystemusage")
+
+// reportPeriod is the time period between filesystem usage reports.
+const reportPeriod = 5 * time.Minute
+
+// Facade is an interface that is supplied to NewWorker for finding
+// the filesystems attached to the local host, and recording their
+// usage.
+type Facade interface {
+	MachineFilesystemAttachments() ([]params.FilesystemAttachment, error)
+	SetMachineFilesystemUsage([]params.FilesystemAttachmentUsage) error
+}
+
+// StatFunc is the type of a function that is supplied to NewWorker
+// for finding the number of bytes used and available on the
+// filesystem containing the given path.
+type StatFunc func(path string) (used, available uint64, err error)
+
+// DefaultStat is the default function for finding filesystem usage
+// for the operating system of the local host.
+var DefaultStat StatFunc = statFilesystem
+
+// NewWorker returns a worker that periodically records the usage of
+// the filesystems attached to the machine in state.
+var NewWorker = func(facade Facade, stat StatFunc) worker.Worker {
+	f := func(stop <-chan struct{}) error {
+		return doWork(facade, stat)
+	}
+	return jworker.NewPeriodicWorker(f, reportPeriod, jworker.NewTimer, jworker.Jitter(0.2))
+}
+
+func doWork(facade Facade, stat StatFunc) error {
+	attachments, err := facade.MachineFilesystemAttachments()
+	if errors.IsNotSupported(err) {
+		logger.Infof("controller does not support filesystem usage reporting")
+		return dependency.ErrUninstall
+	} else if err != nil {
+		return errors.Annotate(err, "cannot get filesystem attachments")
+	}
+	usage := make([]params.FilesystemAttachmentUsage, 0, len(attachments))
+	for _, attachment := range attachments {
+		mountPoint := attachment.Info.MountPoint
+		if mountPoint == "" {
+			continue
+		}
+		used, available, err := stat(mountPoint)
+		if err != nil {
+			// The filesystem may not be mounted yet, or may
+			// have just been detached; we'll report it next
+			// time if it's still there.
+			logger.Debugf("cannot get usage of %s at %q: %v", attachment.FilesystemTag, mountPoint, err)
+			continue
+		}
+		usage = append(usage, params.FilesystemAttachmentUsage{
+			FilesystemTag: attachment.FilesystemTag,
+			Usage: params.FilesystemUsage{
+				UsedBytes:      used,
+				AvailableBytes: available,
+			},
+		})
+	}
+	logger.Tracef("filesystem usage: %v", usage)
+	if err := facade.SetMachineFilesystemUsage(usage); err != nil {
+		return errors.Annotate(err, "cannot set filesystem usage")
+	}
+	return nil
+}
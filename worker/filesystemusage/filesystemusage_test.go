@@ -0,0 +1,115 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package filesystemusage_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/filesystemusage"
+)
+
+var _ = gc.Suite(&FilesystemUsageWorkerSuite{})
+
+type FilesystemUsageWorkerSuite struct {
+	coretesting.BaseSuite
+}
+
+func (s *FilesystemUsageWorkerSuite) TestWorker(c *gc.C) {
+	done := make(chan struct{})
+	facade := &mockFacade{
+		attachments: []params.FilesystemAttachment{{
+			FilesystemTag: "filesystem-0",
+			Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv"},
+		}},
+		set: func() { close(done) },
+	}
+	stat := func(string) (uint64, uint64, error) {
+		return 1, 2, nil
+	}
+
+	w := filesystemusage.NewWorker(facade, stat)
+	defer w.Wait()
+	defer w.Kill()
+
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for filesystem usage to be reported")
+	}
+}
+
+func (s *FilesystemUsageWorkerSuite) TestDoWork(c *gc.C) {
+	facade := &mockFacade{
+		attachments: []params.FilesystemAttachment{{
+			FilesystemTag: "filesystem-0",
+			Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv/0"},
+		}, {
+			FilesystemTag: "filesystem-1",
+			Info:          params.FilesystemAttachmentInfo{MountPoint: "/srv/1"},
+		}, {
+			// Not yet mounted.
+			FilesystemTag: "filesystem-2",
+		}},
+	}
+	var statted []string
+	stat := func(path string) (uint64, uint64, error) {
+		statted = append(statted, path)
+		if path == "/srv/1" {
+			return 0, 0, errors.New("no such file or directory")
+		}
+		return 1024, 2048, nil
+	}
+
+	err := filesystemusage.DoWork(facade, stat)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statted, jc.DeepEquals, []string{"/srv/0", "/srv/1"})
+	facade.CheckCallNames(c, "MachineFilesystemAttachments", "SetMachineFilesystemUsage")
+	facade.CheckCall(c, 1, "SetMachineFilesystemUsage", []params.FilesystemAttachmentUsage{{
+		FilesystemTag: "filesystem-0",
+		Usage:         params.FilesystemUsage{UsedBytes: 1024, AvailableBytes: 2048},
+	}})
+}
+
+func (s *FilesystemUsageWorkerSuite) TestDoWorkNotSupported(c *gc.C) {
+	facade := &mockFacade{}
+	facade.SetErrors(errors.NotSupportedf("filesystem usage reporting"))
+	err := filesystemusage.DoWork(facade, nil)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+	facade.CheckCallNames(c, "MachineFilesystemAttachments")
+}
+
+func (s *FilesystemUsageWorkerSuite) TestDoWorkSetError(c *gc.C) {
+	facade := &mockFacade{}
+	facade.SetErrors(nil, errors.New("boom"))
+	err := filesystemusage.DoWork(facade, nil)
+	c.Assert(err, gc.ErrorMatches, "cannot set filesystem usage: boom")
+}
+
+type mockFacade struct {
+	testing.Stub
+	attachments []params.FilesystemAttachment
+	set         func()
+}
+
+func (f *mockFacade) MachineFilesystemAttachments() ([]params.FilesystemAttachment, error) {
+	f.MethodCall(f, "MachineFilesystemAttachments")
+	return f.attachments, f.NextErr()
+}
+
+func (f *mockFacade) SetMachineFilesystemUsage(usage []params.FilesystemAttachmentUsage) error {
+	f.MethodCall(f, "SetMachineFilesystemUsage", usage)
+	if f.set != nil {
+		f.set()
+		f.set = nil
+	}
+	return f.NextErr()
+}
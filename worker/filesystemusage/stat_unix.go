@@ -0,0 +1,25 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package filesystemusage
+
+import (
+	"syscall"
+
+	"github.com/juju/errors"
+)
+
+// statFilesystem returns the number of bytes used on, and available
+// to unprivileged users of, the filesystem containing path.
+func statFilesystem(path string) (used, available uint64, err error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	blockSize := uint64(fs.Bsize)
+	used = (uint64(fs.Blocks) - uint64(fs.Bfree)) * blockSize
+	available = uint64(fs.Bavail) * blockSize
+	return used, available, nil
+}
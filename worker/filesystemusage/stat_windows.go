@@ -0,0 +1,14 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package filesystemusage
+
+import (
+	"github.com/juju/errors"
+)
+
+// statFilesystem is not implemented on Windows, where Juju does not
+// support filesystem storage.
+func statFilesystem(path string) (used, available uint64, err error) {
+	return 0, 0, errors.NotSupportedf("filesystem usage on windows")
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/juju/juju/api/firewaller"
 	"github.com/juju/juju/api/remoterelations"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
@@ -41,6 +42,9 @@ type FirewallerAPI interface {
 	WatchIngressAddressesForRelation(tag names.RelationTag) (watcher.StringsWatcher, error)
 	ControllerAPIInfoForModel(modelUUID string) (*api.Info, error)
 	MacaroonForRelation(relationKey string) (*macaroon.Macaroon, error)
+	ControllerConfig() (controller.Config, error)
+	WatchFirewallRules() (watcher.NotifyWatcher, error)
+	FirewallRules() ([]params.FirewallRule, error)
 }
 
 // CrossModelFirewallerFacade exposes firewaller functionality on the
@@ -137,6 +141,10 @@ type Firewaller struct {
 	localRelationsChange        chan *remoteRelationNetworkChange
 	relationIngress             map[names.RelationTag]*remoteRelationData
 	pollClock                   clock.Clock
+
+	serviceFirewaller    environs.ServiceFirewaller
+	firewallRulesChanges watcher.NotifyChannel
+	serviceIngressRules  []network.IngressRule
 }
 
 // NewFirewaller returns a new Firewaller.
@@ -229,6 +237,21 @@ func (fw *Firewaller) setUp() error {
 		fw.remoteRelationsWatcher = &stubWatcher{changes: make(watcher.StringsChannel)}
 	}
 
+	if serviceFirewaller, ok := fw.environFirewaller.(environs.ServiceFirewaller); ok {
+		firewallRulesWatcher, err := fw.firewallerApi.WatchFirewallRules()
+		if errors.IsNotImplemented(err) {
+			logger.Debugf("not applying firewall rules: %v", err)
+		} else if err != nil {
+			return errors.Annotatef(err, "failed to start firewall rules watcher")
+		} else {
+			if err := fw.catacomb.Add(firewallRulesWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			fw.serviceFirewaller = serviceFirewaller
+			fw.firewallRulesChanges = firewallRulesWatcher.Changes()
+		}
+	}
+
 	logger.Debugf("started watching opened port ranges for the model")
 	return nil
 }
@@ -252,6 +275,11 @@ func (fw *Firewaller) loop() error {
 					return err
 				}
 			}
+			// New machines may have caused the provider to create
+			// the groups to which the service rules apply.
+			if err := fw.setServiceIngressRules(); err != nil {
+				return errors.Trace(err)
+			}
 			if !reconciled {
 				reconciled = true
 				var err error
@@ -277,6 +305,13 @@ func (fw *Firewaller) loop() error {
 					return errors.Trace(err)
 				}
 			}
+		case _, ok := <-fw.firewallRulesChanges:
+			if !ok {
+				return errors.New("firewall rules watcher closed")
+			}
+			if err := fw.firewallRulesChanged(); err != nil {
+				return errors.Trace(err)
+			}
 		case change, ok := <-fw.remoteRelationsWatcher.Changes():
 			if !ok {
 				return errors.New("remote relations watcher closed")
@@ -316,6 +351,80 @@ func (fw *Firewaller) loop() error {
 	}
 }
 
+// firewallRulesChanged fetches the controller's firewall rules and
+// applies them to the environment.
+func (fw *Firewaller) firewallRulesChanged() error {
+	rules, err := fw.firewallerApi.FirewallRules()
+	if err != nil {
+		return errors.Annotate(err, "cannot get firewall rules")
+	}
+	controllerConfig, err := fw.firewallerApi.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fw.serviceIngressRules, err = serviceIngressRules(rules, controllerConfig.APIPort())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return fw.setServiceIngressRules()
+}
+
+// setServiceIngressRules applies the most recently fetched firewall
+// rules to the environment.
+func (fw *Firewaller) setServiceIngressRules() error {
+	if fw.serviceFirewaller == nil || len(fw.serviceIngressRules) == 0 {
+		return nil
+	}
+	if err := fw.serviceFirewaller.SetServiceIngressRules(fw.serviceIngressRules); err != nil {
+		return errors.Annotate(err, "cannot set service ingress rules")
+	}
+	return nil
+}
+
+// serviceIngressRules returns the ingress rules which restrict access
+// to the well known services to the whitelisted CIDRs of the given
+// firewall rules. An empty whitelist allows access from anywhere, and
+// services without a rule are left alone.
+//
+// Application offers are consumed through the API port, so when access
+// to the controller is restricted, the CIDRs whitelisted for offers may
+// also reach the API port.
+func serviceIngressRules(rules []params.FirewallRule, apiPort int) ([]network.IngressRule, error) {
+	var result []network.IngressRule
+	var controllerRule, offerRule *params.FirewallRule
+	for i, rule := range rules {
+		switch rule.KnownService {
+		case "ssh":
+			ingressRule, err := network.NewIngressRule("tcp", 22, 22, rule.WhitelistCIDRS...)
+			if err != nil {
+				return nil, errors.Annotatef(err, "firewall rule for %q", rule.KnownService)
+			}
+			result = append(result, ingressRule)
+		case "juju-controller":
+			controllerRule = &rules[i]
+		case "juju-application-offer":
+			offerRule = &rules[i]
+		default:
+			logger.Warningf("ignoring firewall rule for unknown service %q", rule.KnownService)
+		}
+	}
+	if controllerRule != nil {
+		cidrs := set.NewStrings(controllerRule.WhitelistCIDRS...)
+		if offerRule != nil && !cidrs.IsEmpty() {
+			if len(offerRule.WhitelistCIDRS) == 0 {
+				cidrs = set.NewStrings()
+			}
+			cidrs = cidrs.Union(set.NewStrings(offerRule.WhitelistCIDRS...))
+		}
+		ingressRule, err := network.NewIngressRule("tcp", apiPort, apiPort, cidrs.SortedValues()...)
+		if err != nil {
+			return nil, errors.Annotatef(err, "firewall rule for %q", controllerRule.KnownService)
+		}
+		result = append(result, ingressRule)
+	}
+	return result, nil
+}
+
 func (fw *Firewaller) publishNetworkChanged(change *remoteRelationNetworkChange) error {
 	logger.Debugf("process remote relation egress change for %v", change.relationTag)
 	relData, ok := fw.relationIngress[change.relationTag]
@@ -91,6 +91,19 @@ func (cfg ManifoldConfig) start(context dependency.Context) (worker.Worker, erro
 		logger.Infof("stopping firewaller (not required)")
 		return nil, dependency.ErrUninstall
 	}
+	// A provider without firewall support is not an error worth
+	// restarting for: the feature is simply off.
+	caps, err := environ.Capabilities()
+	if errors.IsNotSupported(err) {
+		logger.Warningf("stopping firewaller (environ capabilities not supported)")
+		return nil, dependency.ErrUninstall
+	} else if err != nil {
+		return nil, errors.Annotate(err, "getting environ capabilities")
+	}
+	if !caps.SupportsFirewallMode(mode) {
+		logger.Warningf("stopping firewaller (firewall mode %q not supported)", mode)
+		return nil, dependency.ErrUninstall
+	}
 
 	firewallerAPI, err := cfg.NewFirewallerFacade(apiConn)
 	if err != nil {
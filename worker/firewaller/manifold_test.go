@@ -48,6 +48,54 @@ func (s *ManifoldSuite) TestManifoldFirewallModeNone(c *gc.C) {
 	c.Assert(err, gc.Equals, dependency.ErrUninstall)
 }
 
+func (s *ManifoldSuite) TestManifoldFirewallModeNotSupported(c *gc.C) {
+	ctx := &mockDependencyContext{
+		env: &mockEnviron{
+			config: coretesting.CustomModelConfig(c, coretesting.Attrs{
+				"firewall-mode": config.FwGlobal,
+			}),
+			caps: environs.Capabilities{
+				FirewallModes: []string{config.FwInstance, config.FwNone},
+			},
+		},
+	}
+
+	manifold := firewaller.Manifold(firewaller.ManifoldConfig{
+		AgentName:                "agent",
+		APICallerName:            "api-caller",
+		EnvironName:              "environ",
+		NewControllerConnection:  func(*api.Info) (api.Connection, error) { return nil, nil },
+		NewFirewallerFacade:      func(base.APICaller) (firewaller.FirewallerAPI, error) { return nil, nil },
+		NewFirewallerWorker:      func(firewaller.Config) (worker.Worker, error) { return nil, nil },
+		NewRemoteRelationsFacade: func(base.APICaller) (*remoterelations.Client, error) { return nil, nil },
+	})
+	_, err := manifold.Start(ctx)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+}
+
+func (s *ManifoldSuite) TestManifoldCapabilitiesNotSupported(c *gc.C) {
+	ctx := &mockDependencyContext{
+		env: &mockEnviron{
+			config: coretesting.CustomModelConfig(c, coretesting.Attrs{
+				"firewall-mode": config.FwInstance,
+			}),
+			capsErr: errors.NotSupportedf("capabilities"),
+		},
+	}
+
+	manifold := firewaller.Manifold(firewaller.ManifoldConfig{
+		AgentName:                "agent",
+		APICallerName:            "api-caller",
+		EnvironName:              "environ",
+		NewControllerConnection:  func(*api.Info) (api.Connection, error) { return nil, nil },
+		NewFirewallerFacade:      func(base.APICaller) (firewaller.FirewallerAPI, error) { return nil, nil },
+		NewFirewallerWorker:      func(firewaller.Config) (worker.Worker, error) { return nil, nil },
+		NewRemoteRelationsFacade: func(base.APICaller) (*remoterelations.Client, error) { return nil, nil },
+	})
+	_, err := manifold.Start(ctx)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+}
+
 type mockDependencyContext struct {
 	dependency.Context
 	env *mockEnviron
@@ -62,13 +110,19 @@ func (m *mockDependencyContext) Get(name string, out interface{}) error {
 
 type mockEnviron struct {
 	environs.Environ
-	config *config.Config
+	config  *config.Config
+	caps    environs.Capabilities
+	capsErr error
 }
 
 func (e *mockEnviron) Config() *config.Config {
 	return e.config
 }
 
+func (e *mockEnviron) Capabilities() (environs.Capabilities, error) {
+	return e.caps, e.capsErr
+}
+
 type ManifoldConfigSuite struct {
 	testing.IsolationSuite
 	config firewaller.ManifoldConfig
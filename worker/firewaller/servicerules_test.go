@@ -0,0 +1,77 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+type ServiceRulesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ServiceRulesSuite{})
+
+func (s *ServiceRulesSuite) TestSSH(c *gc.C) {
+	rules, err := serviceIngressRules([]params.FirewallRule{{
+		KnownService:   "ssh",
+		WhitelistCIDRS: []string{"10.0.0.0/8", "192.168.1.0/24"},
+	}}, 17070)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 22, 22, "10.0.0.0/8", "192.168.1.0/24"),
+	})
+}
+
+func (s *ServiceRulesSuite) TestEmptyWhitelistAllowsAnywhere(c *gc.C) {
+	rules, err := serviceIngressRules([]params.FirewallRule{{
+		KnownService: "ssh",
+	}, {
+		KnownService: "juju-controller",
+	}}, 17070)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 22, 22),
+		network.MustNewIngressRule("tcp", 17070, 17070),
+	})
+}
+
+func (s *ServiceRulesSuite) TestControllerIncludesOffers(c *gc.C) {
+	rules, err := serviceIngressRules([]params.FirewallRule{{
+		KnownService:   "juju-application-offer",
+		WhitelistCIDRS: []string{"192.168.1.0/24"},
+	}, {
+		KnownService:   "juju-controller",
+		WhitelistCIDRS: []string{"10.0.0.0/8"},
+	}}, 17070)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule("tcp", 17070, 17070, "10.0.0.0/8", "192.168.1.0/24"),
+	})
+}
+
+func (s *ServiceRulesSuite) TestOffersWithoutControllerRule(c *gc.C) {
+	rules, err := serviceIngressRules([]params.FirewallRule{{
+		KnownService:   "juju-application-offer",
+		WhitelistCIDRS: []string{"192.168.1.0/24"},
+	}, {
+		KnownService:   "telnet",
+		WhitelistCIDRS: []string{"10.0.0.0/8"},
+	}}, 17070)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rules, gc.HasLen, 0)
+}
+
+func (s *ServiceRulesSuite) TestInvalidCIDR(c *gc.C) {
+	_, err := serviceIngressRules([]params.FirewallRule{{
+		KnownService:   "ssh",
+		WhitelistCIDRS: []string{"10.0.0.1"},
+	}}, 17070)
+	c.Assert(err, gc.ErrorMatches, `firewall rule for "ssh": invalid CIDR address: 10.0.0.1`)
+}
@@ -18,6 +18,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/status"
@@ -193,6 +194,42 @@ func (s *machineSuite) TestLongPollIntervalWhenHasAllInstanceInfo(c *gc.C) {
 	clock.CheckCall(c, 0, "After", LongPoll)
 }
 
+func (s *machineSuite) TestLongPollIntervalInsideMaintenanceWindow(c *gc.C) {
+	// The test clock starts at midnight on a Monday.
+	s.testLongPollWithMaintenanceWindows(c, "Tue 02:00-03:00, 00:00-01:00", LongPoll)
+}
+
+func (s *machineSuite) TestLongPollIntervalOutsideMaintenanceWindow(c *gc.C) {
+	s.testLongPollWithMaintenanceWindows(c, "Tue 02:00-03:00", OffPeakLongPoll)
+}
+
+func (s *machineSuite) testLongPollWithMaintenanceWindows(c *gc.C, value string, expectInterval time.Duration) {
+	windows, err := config.ParseMaintenanceWindows(value)
+	c.Assert(err, jc.ErrorIsNil)
+	context := &testMachineContext{
+		getInstanceInfo: instanceInfoGetter(c, "i1234", testAddrs, "running", nil),
+		windows:         windows,
+		dyingc:          make(chan struct{}),
+	}
+	m := &testMachine{
+		tag:        names.NewMachineTag("99"),
+		instanceId: "i1234",
+		refresh:    func() error { return nil },
+		addresses:  testAddrs,
+		life:       params.Alive,
+		status:     status.Started,
+	}
+	died := make(chan machine)
+
+	clock := newTestClock()
+	go runMachine(context, m, nil, died, clock)
+	c.Assert(clock.WaitAdvance(expectInterval, 0, 1), jc.ErrorIsNil)
+
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killErr, gc.Equals, nil)
+	clock.CheckCall(c, 0, "After", expectInterval)
+}
+
 func testRunMachine(
 	c *gc.C,
 	addrs []network.Address,
@@ -360,6 +397,7 @@ func instanceInfoGetter(
 type testMachineContext struct {
 	killErr         error
 	getInstanceInfo func(instance.Id) (instanceInfo, error)
+	windows         config.MaintenanceWindows
 	dyingc          chan struct{}
 }
 
@@ -374,6 +412,10 @@ func (context *testMachineContext) instanceInfo(id instance.Id) (instanceInfo, e
 	return context.getInstanceInfo(id)
 }
 
+func (context *testMachineContext) maintenanceWindows() config.MaintenanceWindows {
+	return context.windows
+}
+
 func (context *testMachineContext) dying() <-chan struct{} {
 	return context.dyingc
 }
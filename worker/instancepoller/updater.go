@@ -12,6 +12,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/status"
@@ -26,11 +27,14 @@ var logger = loggo.GetLogger("juju.worker.instancepoller")
 // with an exponent of ShortPollBackoff until a maximum(ish) of LongPoll.
 //
 // When a machine has an address and is started LongPoll will be used to
-// check that the instance address or status has not changed.
+// check that the instance address or status has not changed, or
+// OffPeakLongPoll if the model has maintenance windows configured and
+// none of them is open.
 var (
 	ShortPoll        = 1 * time.Second
 	ShortPollBackoff = 2.0
 	LongPoll         = 15 * time.Minute
+	OffPeakLongPoll  = 1 * time.Hour
 )
 
 type machine interface {
@@ -65,6 +69,7 @@ type lifetimeContext interface {
 type machineContext interface {
 	lifetimeContext
 	instanceInfo(id instance.Id) (instanceInfo, error)
+	maintenanceWindows() config.MaintenanceWindows
 }
 
 type updaterContext interface {
@@ -233,7 +238,7 @@ func machineLoop(context machineContext, m machine, lifeChanged <-chan struct{},
 		select {
 		case <-context.dying():
 			return context.errDying()
-		case <-clock.After(pollInterval):
+		case <-clock.After(longPollInterval(context, pollInterval, clock)):
 			shouldPollInstance = true
 		case <-lifeChanged:
 			if err := m.Refresh(); err != nil {
@@ -246,6 +251,19 @@ func machineLoop(context machineContext, m machine, lifeChanged <-chan struct{},
 	}
 }
 
+// longPollInterval returns the interval to wait before polling the
+// instance again, relaxing LongPoll outside the model's maintenance
+// windows.
+func longPollInterval(context machineContext, pollInterval time.Duration, clock clock.Clock) time.Duration {
+	if pollInterval != LongPoll {
+		return pollInterval
+	}
+	if windows := context.maintenanceWindows(); len(windows) > 0 && !windows.Allows(clock.Now()) {
+		return OffPeakLongPoll
+	}
+	return pollInterval
+}
+
 // pollInstanceInfo checks the current provider addresses and status
 // for the given machine's instance, and sets them on the machine if they've changed.
 func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo, err error) {
@@ -4,6 +4,7 @@
 package instancepoller
 
 import (
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -12,7 +13,9 @@ import (
 	worker "gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/api/instancepoller"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/catacomb"
 )
 
@@ -42,6 +45,7 @@ func (config Config) Validate() error {
 type updaterWorker struct {
 	config     Config
 	aggregator *aggregator
+	windows    *maintenanceWindowsHandler
 	catacomb   catacomb.Catacomb
 }
 
@@ -89,14 +93,24 @@ func (u *updaterWorker) loop() (err error) {
 	if err := u.catacomb.Add(u.aggregator); err != nil {
 		return errors.Trace(err)
 	}
-	watcher, err := u.config.Facade.WatchModelMachines()
+	u.windows = &maintenanceWindowsHandler{facade: u.config.Facade}
+	windowsWorker, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: u.windows,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := u.catacomb.Add(windowsWorker); err != nil {
+		return errors.Trace(err)
+	}
+	machinesWatcher, err := u.config.Facade.WatchModelMachines()
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if err := u.catacomb.Add(watcher); err != nil {
+	if err := u.catacomb.Add(machinesWatcher); err != nil {
 		return errors.Trace(err)
 	}
-	return watchMachinesLoop(u, watcher)
+	return watchMachinesLoop(u, machinesWatcher)
 }
 
 // newMachineContext is part of the updaterContext interface.
@@ -114,6 +128,11 @@ func (u *updaterWorker) instanceInfo(id instance.Id) (instanceInfo, error) {
 	return u.aggregator.instanceInfo(id)
 }
 
+// maintenanceWindows is part of the machineContext interface.
+func (u *updaterWorker) maintenanceWindows() config.MaintenanceWindows {
+	return u.windows.get()
+}
+
 // kill is part of the lifetimeContext interface.
 func (u *updaterWorker) kill(err error) {
 	u.catacomb.Kill(err)
@@ -128,3 +147,41 @@ func (u *updaterWorker) dying() <-chan struct{} {
 func (u *updaterWorker) errDying() error {
 	return u.catacomb.ErrDying()
 }
+
+// maintenanceWindowsHandler implements watcher.NotifyHandler, reading
+// the model's maintenance windows again whenever its config changes.
+type maintenanceWindowsHandler struct {
+	facade *instancepoller.API
+
+	mu      sync.Mutex
+	windows config.MaintenanceWindows
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (h *maintenanceWindowsHandler) SetUp() (watcher.NotifyWatcher, error) {
+	return h.facade.WatchForModelConfigChanges()
+}
+
+// Handle is part of the watcher.NotifyHandler interface.
+func (h *maintenanceWindowsHandler) Handle(_ <-chan struct{}) error {
+	windows, err := h.facade.MaintenanceWindows()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.windows = windows
+	return nil
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (h *maintenanceWindowsHandler) TearDown() error {
+	return nil
+}
+
+// get returns the maintenance windows last read.
+func (h *maintenanceWindowsHandler) get() config.MaintenanceWindows {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.windows
+}
@@ -4,6 +4,7 @@
 package leadership
 
 import (
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -33,6 +34,11 @@ type Tracker struct {
 	waitMinionTickets chan chan bool
 	waitingLeader     []chan bool
 	waitingMinion     []chan bool
+
+	// mu guards leaseExpiry, which is written by the loop goroutine
+	// and read by clients of LeaseExpiry.
+	mu          sync.Mutex
+	leaseExpiry time.Time
 }
 
 // NewTracker returns a *Tracker that attempts to claim and retain service
@@ -107,6 +113,13 @@ func (t *Tracker) ClaimDuration() time.Duration {
 	return t.duration
 }
 
+// LeaseExpiry is part of the leadership.Tracker interface.
+func (t *Tracker) LeaseExpiry() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.leaseExpiry
+}
+
 // ClaimLeader is part of the leadership.Tracker interface.
 func (t *Tracker) ClaimLeader() leadership.Ticket {
 	return t.submit(t.claimTickets)
@@ -188,6 +201,7 @@ func (t *Tracker) setLeader(untilTime time.Time) error {
 	renewTime := untilTime.Add(-t.duration)
 	logger.Tracef("%s will renew %s leadership at %s", t.unitName, t.applicationName, renewTime)
 	t.isMinion = false
+	t.setLeaseExpiry(untilTime)
 	t.claimLease = nil
 	t.renewLease = t.clock.After(renewTime.Sub(t.clock.Now()))
 
@@ -207,6 +221,7 @@ func (t *Tracker) setLeader(untilTime time.Time) error {
 func (t *Tracker) setMinion() error {
 	logger.Infof("%s leadership for %s denied", t.applicationName, t.unitName)
 	t.isMinion = true
+	t.setLeaseExpiry(time.Time{})
 	t.renewLease = nil
 	if t.claimLease == nil {
 		t.claimLease = make(chan struct{})
@@ -238,6 +253,12 @@ func (t *Tracker) setMinion() error {
 	return nil
 }
 
+func (t *Tracker) setLeaseExpiry(expiry time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.leaseExpiry = expiry
+}
+
 // isLeader returns true if leadership is guaranteed for the Tracker's duration.
 func (t *Tracker) isLeader() (bool, error) {
 	if !t.isMinion {
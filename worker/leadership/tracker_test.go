@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v2"
 	worker "gopkg.in/juju/worker.v1"
@@ -114,6 +115,38 @@ func (s *TrackerSuite) TestOnLeaderSuccess(c *gc.C) {
 	}})
 }
 
+func (s *TrackerSuite) TestLeaseExpiryWhenLeader(c *gc.C) {
+	tracker := s.newTracker()
+	assertClaimLeader(c, tracker, true)
+
+	expiry := s.clock.Now().Add(leaseDuration)
+	c.Assert(tracker.LeaseExpiry(), gc.Equals, expiry)
+}
+
+func (s *TrackerSuite) TestLeaseExpiryWhenMinion(c *gc.C) {
+	s.claimer.Stub.SetErrors(coreleadership.ErrClaimDenied, nil)
+	tracker := s.newTracker()
+	assertClaimLeader(c, tracker, false)
+	c.Assert(tracker.LeaseExpiry().IsZero(), jc.IsTrue)
+
+	workertest.CleanKill(c, tracker)
+	s.unblockRelease(c)
+}
+
+func (s *TrackerSuite) TestLeaseExpiryClearedOnLoss(c *gc.C) {
+	s.claimer.Stub.SetErrors(nil, coreleadership.ErrClaimDenied, nil)
+	tracker := s.newTracker()
+	assertClaimLeader(c, tracker, true)
+	c.Assert(tracker.LeaseExpiry().IsZero(), jc.IsFalse)
+
+	s.refreshes(1)
+	assertClaimLeader(c, tracker, false)
+	c.Assert(tracker.LeaseExpiry().IsZero(), jc.IsTrue)
+
+	workertest.CleanKill(c, tracker)
+	s.unblockRelease(c)
+}
+
 func (s *TrackerSuite) TestOnLeaderFailure(c *gc.C) {
 	s.claimer.Stub.SetErrors(coreleadership.ErrClaimDenied, nil)
 	tracker := s.newTracker()
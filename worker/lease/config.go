@@ -33,14 +33,14 @@ type ManagerConfig struct {
 	// Secretary is responsible for validating lease names and holder names.
 	Secretary Secretary
 
-	// Client is responsible for recording, retrieving, and expiring leases.
-	Client lease.Client
+	// Store is responsible for recording, retrieving, and expiring leases.
+	Store lease.Store
 
 	// Clock is reponsible for reporting the passage of time.
 	Clock clock.Clock
 
 	// MaxSleep is the longest time the Manager should sleep before
-	// refreshing its client's leases and checking for expiries.
+	// refreshing its store's leases and checking for expiries.
 	MaxSleep time.Duration
 }
 
@@ -50,8 +50,8 @@ func (config ManagerConfig) Validate() error {
 	if config.Secretary == nil {
 		return errors.NotValidf("nil Secretary")
 	}
-	if config.Client == nil {
-		return errors.NotValidf("nil Client")
+	if config.Store == nil {
+		return errors.NotValidf("nil Store")
 	}
 	if config.Clock == nil {
 		return errors.NotValidf("nil Clock")
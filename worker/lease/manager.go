@@ -101,7 +101,7 @@ func (manager *Manager) loop() error {
 			return errors.Trace(err)
 		}
 
-		leases := manager.config.Client.Leases()
+		leases := manager.config.Store.Leases()
 		for leaseName := range blocks {
 			if _, found := leases[leaseName]; !found {
 				blocks.unblock(leaseName)
@@ -151,7 +151,7 @@ func (manager *Manager) Claim(leaseName, holderName string, duration time.Durati
 // unrecoverable errors; mere failure to claim just indicates a bad request, and
 // is communicated back to the claim's originator.
 func (manager *Manager) handleClaim(claim claim) error {
-	client := manager.config.Client
+	store := manager.config.Store
 	request := lease.Request{claim.holderName, claim.duration}
 	err := lease.ErrInvalid
 	for err == lease.ErrInvalid {
@@ -159,12 +159,12 @@ func (manager *Manager) handleClaim(claim claim) error {
 		case <-manager.catacomb.Dying():
 			return manager.catacomb.ErrDying()
 		default:
-			info, found := client.Leases()[claim.leaseName]
+			info, found := store.Leases()[claim.leaseName]
 			switch {
 			case !found:
-				err = client.ClaimLease(claim.leaseName, request)
+				err = store.ClaimLease(claim.leaseName, request)
 			case info.Holder == claim.holderName:
-				err = client.ExtendLease(claim.leaseName, request)
+				err = store.ExtendLease(claim.leaseName, request)
 			default:
 				claim.respond(false)
 				return nil
@@ -193,13 +193,13 @@ func (manager *Manager) Token(leaseName, holderName string) lease.Token {
 // unrecoverable errors; mere untruth of the assertion just indicates a bad
 // request, and is communicated back to the check's originator.
 func (manager *Manager) handleCheck(check check) error {
-	client := manager.config.Client
-	info, found := client.Leases()[check.leaseName]
+	store := manager.config.Store
+	info, found := store.Leases()[check.leaseName]
 	if !found || info.Holder != check.holderName {
-		if err := client.Refresh(); err != nil {
+		if err := store.Refresh(); err != nil {
 			return errors.Trace(err)
 		}
-		info, found = client.Leases()[check.leaseName]
+		info, found = store.Leases()[check.leaseName]
 	}
 
 	var response error
@@ -231,7 +231,7 @@ func (manager *Manager) WaitUntilExpired(leaseName string) error {
 func (manager *Manager) nextTick() <-chan time.Time {
 	now := manager.config.Clock.Now()
 	nextTick := now.Add(manager.config.MaxSleep)
-	for _, info := range manager.config.Client.Leases() {
+	for _, info := range manager.config.Store.Leases() {
 		if info.Expiry.After(nextTick) {
 			continue
 		}
@@ -244,18 +244,18 @@ func (manager *Manager) nextTick() <-chan time.Time {
 // tick snapshots recent leases and expires any that it can. There
 // might be none that need attention; or those that do might already
 // have been extended or expired by someone else; so ErrInvalid is
-// expected, and ignored, comfortable that the client will have been
+// expected, and ignored, comfortable that the store will have been
 // updated in the background; and that we'll see fresh info when we
 // subsequently check nextWake().
 //
 // It will return only unrecoverable errors.
 func (manager *Manager) tick() error {
 	logger.Tracef("refreshing leases...")
-	client := manager.config.Client
-	if err := client.Refresh(); err != nil {
+	store := manager.config.Store
+	if err := store.Refresh(); err != nil {
 		return errors.Trace(err)
 	}
-	leases := client.Leases()
+	leases := store.Leases()
 
 	// Sort lease names so we expire in a predictable order for the tests.
 	names := make([]string, 0, len(leases))
@@ -270,7 +270,7 @@ func (manager *Manager) tick() error {
 		if leases[name].Expiry.After(now) {
 			continue
 		}
-		switch err := client.ExpireLease(name); err {
+		switch err := store.ExpireLease(name); err {
 		case nil, lease.ErrInvalid:
 		default:
 			return errors.Trace(err)
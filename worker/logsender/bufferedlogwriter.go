@@ -76,11 +76,16 @@ func UninstallBufferedLogWriter() error {
 // returned by the Logs method.
 //
 // Up to maxLen log messages will be buffered. If this limit is
-// exceeded, the oldest records will be automatically discarded.
+// exceeded, the oldest records will be automatically discarded,
+// unless a disk buffer has been enabled, in which case messages
+// beyond the disk buffer's smaller in-memory limit are buffered on
+// disk until there is room for them.
 type BufferedLogWriter struct {
 	maxLen int
 	in     LogRecordCh
 	out    LogRecordCh
+	disk   chan enableDisk
+	done   chan struct{}
 
 	mu    sync.Mutex
 	stats LogStats
@@ -93,16 +98,85 @@ func NewBufferedLogWriter(maxLen int) *BufferedLogWriter {
 		maxLen: maxLen,
 		in:     make(LogRecordCh),
 		out:    make(LogRecordCh),
+		disk:   make(chan enableDisk),
+		done:   make(chan struct{}),
 	}
 	go w.loop()
 	return w
 }
 
+// EnableDiskBuffer makes the writer buffer messages in the given
+// directory, up to maxBytes in size, once memLen messages (or the
+// writer's maxLen, if smaller) are waiting in memory, so that messages
+// logged while the controller is unreachable are not lost if the agent
+// restarts. If the disk buffer is full, the oldest messages in it are
+// discarded. Messages left in the directory by an earlier writer are
+// sent once those in memory have been.
+//
+// If the disk buffer cannot be written or read, the writer reverts to
+// buffering up to maxLen messages in memory only.
+func (w *BufferedLogWriter) EnableDiskBuffer(dir string, maxBytes int64, memLen int) error {
+	if memLen <= 0 {
+		return errors.NotValidf("log disk buffer memory length %d", memLen)
+	}
+	disk, err := openDiskBuffer(dir, maxBytes)
+	if err != nil {
+		return errors.Annotate(err, "cannot open log disk buffer")
+	}
+	select {
+	case w.disk <- enableDisk{disk, memLen}:
+		return nil
+	case <-w.done:
+		disk.close()
+		return errors.New("buffered log writer closed")
+	}
+}
+
+// enableDisk holds a request to buffer records on disk once memLen
+// records are held in memory.
+type enableDisk struct {
+	disk   *diskBuffer
+	memLen int
+}
+
 func (w *BufferedLogWriter) loop() {
+	defer close(w.done)
 	buffer := deque.New()
 	var outCh LogRecordCh // Output channel - set when there's something to send.
 	var outRec *LogRecord // Next LogRecord to send to the output channel.
 
+	// disk, if not nil, holds the records received while the buffer
+	// holds memLen records or more. They are moved to the buffer as
+	// it is drained.
+	var disk *diskBuffer
+	var diskMemLen int
+	defer func() {
+		if disk != nil {
+			disk.close()
+		}
+	}()
+	// memLen returns the number of records held in memory before
+	// further records are buffered on disk.
+	memLen := func() int {
+		if diskMemLen < w.maxLen {
+			return diskMemLen
+		}
+		return w.maxLen
+	}
+	refill := func() {
+		for disk != nil && buffer.Len() < memLen() {
+			rec, err := disk.pop()
+			if err != nil {
+				disk.close()
+				disk = nil
+			} else if rec == nil {
+				return
+			} else {
+				buffer.PushBack(rec)
+			}
+		}
+	}
+
 	for {
 		// If there's something in the buffer and there's nothing
 		// queued up to send, set up the next LogRecord to send.
@@ -121,6 +195,29 @@ func (w *BufferedLogWriter) loop() {
 				return
 			}
 
+			if disk != nil && (disk.len() > 0 || buffer.Len() >= memLen()) {
+				dropped, err := disk.push(inRec)
+				if err == nil {
+					// The dropped records were the oldest on
+					// disk, which follow the newest in memory.
+					if item, ok := buffer.PopBack(); ok {
+						item.(*LogRecord).DroppedAfter += dropped
+						buffer.PushBack(item)
+					} else if outCh != nil {
+						outRec.DroppedAfter += dropped
+					}
+					w.mu.Lock()
+					w.stats.Enqueued++
+					w.stats.Dropped += uint64(dropped)
+					w.mu.Unlock()
+					continue
+				}
+				// Records that were written remain on disk,
+				// and will be sent by a later writer.
+				disk.close()
+				disk = nil
+			}
+
 			buffer.PushBack(inRec)
 
 			w.mu.Lock()
@@ -140,6 +237,14 @@ func (w *BufferedLogWriter) loop() {
 			w.mu.Lock()
 			w.stats.Sent++
 			w.mu.Unlock()
+			refill()
+
+		case enable := <-w.disk:
+			if disk != nil {
+				disk.close()
+			}
+			disk, diskMemLen = enable.disk, enable.memLen
+			refill()
 		}
 	}
 
@@ -128,6 +128,111 @@ func (s *bufferedLogWriterSuite) TestLimiting(c *gc.C) {
 	})
 }
 
+func (s *bufferedLogWriterSuite) writeMessages(from, to int) {
+	for i := from; i < to; i++ {
+		s.writer.Write(
+			loggo.Entry{
+				Level:     loggo.INFO,
+				Module:    "module",
+				Filename:  "filename",
+				Line:      42,
+				Timestamp: time.Now(),
+				Message:   fmt.Sprintf("log%d", i),
+			})
+	}
+}
+
+func (s *bufferedLogWriterSuite) TestDiskBuffer(c *gc.C) {
+	err := s.writer.EnableDiskBuffer(c.MkDir(), 1<<20, maxLen)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Logs that do not fit in memory are buffered on disk, and none
+	// are dropped.
+	const numMessages = maxLen * 3
+	s.writeMessages(0, numMessages)
+	for i := 0; i < numMessages; i++ {
+		rec := s.receiveOne(c)
+		c.Assert(rec.Message, gc.Equals, fmt.Sprintf("log%d", i))
+		c.Assert(rec.DroppedAfter, gc.Equals, 0)
+	}
+	logsendertest.ExpectLogStats(c, s.writer, logsender.LogStats{
+		Enqueued: numMessages,
+		Sent:     numMessages,
+	})
+}
+
+func (s *bufferedLogWriterSuite) TestDiskBufferSurvivesRestart(c *gc.C) {
+	dir := c.MkDir()
+	err := s.writer.EnableDiskBuffer(dir, 1<<20, maxLen)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The first log is waiting to be sent, and the next maxLen are
+	// buffered in memory; the rest are buffered on disk.
+	s.writeMessages(0, maxLen+5)
+	s.writer.Close()
+
+	s.writer = logsender.NewBufferedLogWriter(maxLen)
+	err = s.writer.EnableDiskBuffer(dir, 1<<20, maxLen)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := maxLen + 1; i < maxLen+5; i++ {
+		rec := s.receiveOne(c)
+		c.Assert(rec.Message, gc.Equals, fmt.Sprintf("log%d", i))
+	}
+}
+
+func (s *bufferedLogWriterSuite) TestDiskBufferSpillsBeforeMemoryFull(c *gc.C) {
+	s.writer.Close()
+	s.writer = logsender.NewBufferedLogWriter(1000)
+	dir := c.MkDir()
+	err := s.writer.EnableDiskBuffer(dir, 1<<20, 2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The first log is waiting to be sent, and the next two are
+	// buffered in memory; the rest are buffered on disk, although
+	// the writer has room for them in memory.
+	s.writeMessages(0, 10)
+	s.writer.Close()
+
+	s.writer = logsender.NewBufferedLogWriter(1000)
+	err = s.writer.EnableDiskBuffer(dir, 1<<20, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 3; i < 10; i++ {
+		rec := s.receiveOne(c)
+		c.Assert(rec.Message, gc.Equals, fmt.Sprintf("log%d", i))
+	}
+}
+
+func (s *bufferedLogWriterSuite) TestDiskBufferDropsOldest(c *gc.C) {
+	// The disk buffer only has room for a few logs.
+	err := s.writer.EnableDiskBuffer(c.MkDir(), 1000, maxLen)
+	c.Assert(err, jc.ErrorIsNil)
+
+	const numMessages = maxLen + 1 + 20
+	s.writeMessages(0, numMessages)
+	for i := 0; i < maxLen; i++ {
+		rec := s.receiveOne(c)
+		c.Assert(rec.Message, gc.Equals, fmt.Sprintf("log%d", i))
+		c.Assert(rec.DroppedAfter, gc.Equals, 0)
+	}
+
+	// The oldest logs on disk were dropped after the newest in
+	// memory.
+	rec := s.receiveOne(c)
+	c.Assert(rec.Message, gc.Equals, fmt.Sprintf("log%d", maxLen))
+	dropped := rec.DroppedAfter
+	c.Assert(dropped > 0, jc.IsTrue)
+	c.Assert(dropped < 20, jc.IsTrue)
+	for i := maxLen + 1 + dropped; i < numMessages; i++ {
+		rec := s.receiveOne(c)
+		c.Assert(rec.Message, gc.Equals, fmt.Sprintf("log%d", i))
+	}
+	logsendertest.ExpectLogStats(c, s.writer, logsender.LogStats{
+		Enqueued: numMessages,
+		Sent:     uint64(numMessages - dropped),
+		Dropped:  uint64(dropped),
+	})
+}
+
 func (s *bufferedLogWriterSuite) TestClose(c *gc.C) {
 	s.writer.Close()
 	s.shouldClose = false // Prevent the usual teardown (calling Close twice will panic)
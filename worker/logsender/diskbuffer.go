@@ -0,0 +1,283 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logsender
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// diskSegmentsPerBuffer is the number of segments a disk buffer
+	// is divided into. The buffer discards a segment at a time, so
+	// the more segments, the fewer records are discarded at once.
+	diskSegmentsPerBuffer = 8
+
+	diskSegmentSuffix = ".log"
+)
+
+// diskBuffer is a queue of log records kept in files in a directory,
+// so that records survive the agent restarting. The queue is divided
+// into segments, each a file holding JSON-encoded records one per
+// line; when the total size of the segments exceeds the buffer's
+// maximum, the oldest segment is discarded.
+//
+// Records read from the buffer are not removed from disk until their
+// segment has been read entirely, so records may be read again after
+// a restart.
+type diskBuffer struct {
+	dir          string
+	maxBytes     int64
+	segmentBytes int64
+
+	// segments holds the buffer's segments, oldest first.
+	segments []*diskSegment
+	size     int64
+
+	// writer appends to the newest segment.
+	writer *os.File
+
+	// reader and decoder read from the oldest segment.
+	reader  *os.File
+	decoder *json.Decoder
+}
+
+// diskSegment describes one file of a diskBuffer.
+type diskSegment struct {
+	seq     uint64
+	size    int64
+	records int
+	read    int
+}
+
+// openDiskBuffer opens the disk buffer in the given directory, creating
+// it if necessary. Records already in the buffer are read first.
+func openDiskBuffer(dir string, maxBytes int64) (*diskBuffer, error) {
+	if maxBytes <= 0 {
+		return nil, errors.NotValidf("disk buffer size %d", maxBytes)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Trace(err)
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	segmentBytes := maxBytes / diskSegmentsPerBuffer
+	if segmentBytes == 0 {
+		segmentBytes = 1
+	}
+	b := &diskBuffer{
+		dir:          dir,
+		maxBytes:     maxBytes,
+		segmentBytes: segmentBytes,
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, diskSegmentSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, diskSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		seg := &diskSegment{seq: seq, size: info.Size()}
+		if seg.records, err = countRecords(b.path(seg)); err != nil {
+			return nil, errors.Trace(err)
+		}
+		b.segments = append(b.segments, seg)
+		b.size += seg.size
+	}
+	sort.Slice(b.segments, func(i, j int) bool {
+		return b.segments[i].seq < b.segments[j].seq
+	})
+	for b.size > b.maxBytes && len(b.segments) > 1 {
+		if _, err := b.dropOldest(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return b, nil
+}
+
+// countRecords returns the number of records in the segment file at
+// the given path.
+func countRecords(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var count int
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A partial last line was being written when the
+			// agent stopped; it is skipped when read.
+			return count, nil
+		} else if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if len(line) > 1 {
+			count++
+		}
+	}
+}
+
+func (b *diskBuffer) path(seg *diskSegment) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%016d%s", seg.seq, diskSegmentSuffix))
+}
+
+// len returns the number of unread records in the buffer.
+func (b *diskBuffer) len() int {
+	var n int
+	for _, seg := range b.segments {
+		n += seg.records - seg.read
+	}
+	return n
+}
+
+// push adds a record to the end of the buffer, and returns the number
+// of unread records discarded to make room for it.
+func (b *diskBuffer) push(rec *LogRecord) (int, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	data = append(data, '\n')
+	// Records are never appended to segments written before the
+	// buffer was opened, which may end with a partial record.
+	last := b.newest()
+	if b.writer == nil || last.size > 0 && last.size+int64(len(data)) > b.segmentBytes {
+		if last, err = b.addSegment(); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	n, err := b.writer.Write(data)
+	last.size += int64(n)
+	b.size += int64(n)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	last.records++
+
+	var dropped int
+	for b.size > b.maxBytes && len(b.segments) > 1 {
+		n, err := b.dropOldest()
+		dropped += n
+		if err != nil {
+			return dropped, errors.Trace(err)
+		}
+	}
+	return dropped, nil
+}
+
+// pop removes the record at the front of the buffer and returns it, or
+// nil if there are no unread records.
+func (b *diskBuffer) pop() (*LogRecord, error) {
+	for len(b.segments) > 0 {
+		seg := b.segments[0]
+		if seg.read == seg.records {
+			if len(b.segments) == 1 {
+				// Keep the newest segment for appending.
+				return nil, nil
+			}
+			if _, err := b.dropOldest(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			continue
+		}
+		if b.decoder == nil {
+			f, err := os.Open(b.path(seg))
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			b.reader = f
+			b.decoder = json.NewDecoder(f)
+			for i := 0; i < seg.read; i++ {
+				var skip LogRecord
+				if err := b.decoder.Decode(&skip); err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+		}
+		var rec LogRecord
+		if err := b.decoder.Decode(&rec); err != nil {
+			return nil, errors.Trace(err)
+		}
+		seg.read++
+		return &rec, nil
+	}
+	return nil, nil
+}
+
+// close closes the buffer's files. Unread records remain on disk.
+func (b *diskBuffer) close() error {
+	b.closeReader()
+	if b.writer != nil {
+		err := b.writer.Close()
+		b.writer = nil
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+func (b *diskBuffer) newest() *diskSegment {
+	if len(b.segments) == 0 {
+		return nil
+	}
+	return b.segments[len(b.segments)-1]
+}
+
+// addSegment starts a new, empty segment to append records to.
+func (b *diskBuffer) addSegment() (*diskSegment, error) {
+	seg := &diskSegment{seq: 1}
+	if last := b.newest(); last != nil {
+		seg.seq = last.seq + 1
+	}
+	if b.writer != nil {
+		if err := b.writer.Close(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		b.writer = nil
+	}
+	f, err := os.OpenFile(b.path(seg), os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	b.writer = f
+	b.segments = append(b.segments, seg)
+	return seg, nil
+}
+
+// dropOldest removes the oldest segment, and returns the number of
+// unread records it held.
+func (b *diskBuffer) dropOldest() (int, error) {
+	seg := b.segments[0]
+	b.closeReader()
+	b.segments = b.segments[1:]
+	b.size -= seg.size
+	if err := os.Remove(b.path(seg)); err != nil && !os.IsNotExist(err) {
+		return seg.records - seg.read, errors.Trace(err)
+	}
+	return seg.records - seg.read, nil
+}
+
+func (b *diskBuffer) closeReader() {
+	if b.reader != nil {
+		b.reader.Close()
+		b.reader = nil
+		b.decoder = nil
+	}
+}
@@ -54,7 +54,8 @@ func New(logs LogRecordCh, logSenderAPI *logsender.API) worker.Worker {
 					// happen if API connectivity is lost for extended
 					// periods. The maximum in-memory log buffer is
 					// quite large (see the InstallBufferedLogWriter
-					// call in jujuDMain).
+					// call in jujuDMain), and agents buffer further
+					// logs on disk.
 					err := logWriter.WriteLog(&params.LogRecord{
 						Time:    rec.Time,
 						Module:  loggerName,
@@ -4,6 +4,7 @@
 package meterstatus
 
 import (
+	stdcontext "context"
 	"fmt"
 	"math/rand"
 	"time"
@@ -65,6 +66,16 @@ func (ctx *limitedContext) UnitName() string {
 // SetProcess implements runner.Context.
 func (ctx *limitedContext) SetProcess(process context.HookProcess) {}
 
+// CancelContext implements runner.Context.
+func (ctx *limitedContext) CancelContext() stdcontext.Context {
+	return stdcontext.Background()
+}
+
+// KillHook implements runner.Context.
+func (ctx *limitedContext) KillHook(reason error) error {
+	return context.ErrNoProcess
+}
+
 // ActionData implements runner.Context.
 func (ctx *limitedContext) ActionData() (*context.ActionData, error) {
 	return nil, jujuc.ErrRestrictedContext
@@ -4,6 +4,7 @@
 package collect
 
 import (
+	stdcontext "context"
 	"fmt"
 	"math/rand"
 	"time"
@@ -72,6 +73,16 @@ func (ctx *hookContext) addJujuUnitsMetric() error {
 // SetProcess implements runner.Context.
 func (ctx *hookContext) SetProcess(process context.HookProcess) {}
 
+// CancelContext implements runner.Context.
+func (ctx *hookContext) CancelContext() stdcontext.Context {
+	return stdcontext.Background()
+}
+
+// KillHook implements runner.Context.
+func (ctx *hookContext) KillHook(reason error) error {
+	return context.ErrNoProcess
+}
+
 // ActionData implements runner.Context.
 func (ctx *hookContext) ActionData() (*context.ActionData, error) {
 	return nil, jujuc.ErrRestrictedContext
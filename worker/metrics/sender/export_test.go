@@ -3,8 +3,31 @@
 
 package sender
 
+import (
+	"time"
+
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+)
+
 var (
 	NewSender            = newSender
 	NewMetricAdderClient = newMetricAdderClient
 	SocketName           = &socketName
 )
+
+// Flush flushes the given sender's spool, returning the number of
+// batches found in it.
+func Flush(s *sender) (int, error) {
+	return s.flush(nil)
+}
+
+type FlushPolicy flushPolicy
+
+func (p FlushPolicy) Delay(pending, failures int) time.Duration {
+	return flushPolicy(p).delay(pending, failures, nil)
+}
+
+func NewFlushWorkerForTest(flush func(<-chan struct{}) (int, error), policy FlushPolicy, clock clock.Clock) worker.Worker {
+	return newFlushWorker(flush, flushPolicy(policy), clock, func() {})
+}
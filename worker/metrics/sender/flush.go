@@ -0,0 +1,144 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sender
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/tomb.v1"
+)
+
+const (
+	// DefaultMinFlushPeriod is the interval between flushes while the
+	// spool is under pressure, and the initial retry delay after a
+	// failed flush.
+	DefaultMinFlushPeriod = 30 * time.Second
+
+	// DefaultMaxFlushBackoff caps the delay between retries after
+	// consecutive failed flushes.
+	DefaultMaxFlushBackoff = 30 * time.Minute
+
+	// DefaultPressureThreshold is the number of spooled batches at
+	// which the spool is considered to be under pressure.
+	DefaultPressureThreshold = 50
+)
+
+// flushPolicy determines how long the sender waits between successive
+// flushes of the metric spool.
+type flushPolicy struct {
+	// Period is the interval between flushes when the spool is empty.
+	Period time.Duration
+
+	// MinPeriod is the interval between flushes when the spool holds
+	// PressureThreshold or more batches. Intervals for spools holding
+	// fewer batches are scaled between Period and MinPeriod.
+	MinPeriod time.Duration
+
+	// PressureThreshold is the number of spooled batches at which
+	// MinPeriod is used.
+	PressureThreshold int
+
+	// MaxBackoff caps the exponential backoff, starting at MinPeriod,
+	// applied after consecutive failed flushes.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction (between 0 and 1) by which each delay
+	// is randomly adjusted, so that agents do not flush in lockstep.
+	Jitter float64
+}
+
+// delay returns the time to wait before the next flush, given the
+// number of batches found in the spool by the last flush and the
+// number of consecutive failed flushes.
+func (p flushPolicy) delay(pending, failures int, r *rand.Rand) time.Duration {
+	var d time.Duration
+	switch {
+	case failures > 0:
+		d = p.MaxBackoff
+		if failures < 32 {
+			if backoff := p.MinPeriod << uint(failures-1); backoff > 0 && backoff < d {
+				d = backoff
+			}
+		}
+	case pending >= p.PressureThreshold:
+		d = p.MinPeriod
+	default:
+		d = p.Period - (p.Period-p.MinPeriod)*time.Duration(pending)/time.Duration(p.PressureThreshold)
+	}
+	if p.Jitter > 0 && r != nil {
+		window := int64(2 * p.Jitter * float64(d))
+		if window > 0 {
+			d = time.Duration((1-p.Jitter)*float64(d)) + time.Duration(r.Int63n(window))
+		}
+	}
+	return d
+}
+
+// flushFunc sends the spooled metrics, returning the number of batches
+// that were found in the spool.
+type flushFunc func(stop <-chan struct{}) (int, error)
+
+// flushWorker periodically flushes the metric spool, adapting the
+// interval between flushes to spool pressure and backing off on
+// failures.
+type flushWorker struct {
+	tomb   tomb.Tomb
+	flush  flushFunc
+	policy flushPolicy
+	clock  clock.Clock
+	rand   *rand.Rand
+	stop   func()
+}
+
+// newFlushWorker returns a worker that calls flush according to the
+// given policy. The stop function is called when the worker is killed.
+func newFlushWorker(flush flushFunc, policy flushPolicy, clock clock.Clock, stop func()) worker.Worker {
+	w := &flushWorker{
+		flush:  flush,
+		policy: policy,
+		clock:  clock,
+		rand:   rand.New(rand.NewSource(clock.Now().UnixNano())),
+		stop:   stop,
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+func (w *flushWorker) loop() error {
+	var delay time.Duration
+	var failures int
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.clock.After(delay):
+		}
+		pending, err := w.flush(w.tomb.Dying())
+		if err != nil {
+			failures++
+			logger.Warningf("failed to send metrics (attempt %d): %v", failures, err)
+		} else {
+			failures = 0
+		}
+		delay = w.policy.delay(pending, failures, w.rand)
+		logger.Tracef("next metrics flush in %v (%d batches spooled)", delay, pending)
+	}
+}
+
+// Kill implements worker.Worker.
+func (w *flushWorker) Kill() {
+	w.stop()
+	w.tomb.Kill(nil)
+}
+
+// Wait implements worker.Worker.
+func (w *flushWorker) Wait() error {
+	return w.tomb.Wait()
+}
@@ -0,0 +1,89 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sender_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/metrics/sender"
+)
+
+type flushSuite struct {
+	testing.IsolationSuite
+	policy sender.FlushPolicy
+}
+
+var _ = gc.Suite(&flushSuite{})
+
+func (s *flushSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.policy = sender.FlushPolicy{
+		Period:            5 * time.Minute,
+		MinPeriod:         30 * time.Second,
+		PressureThreshold: 10,
+		MaxBackoff:        4 * time.Minute,
+	}
+}
+
+func (s *flushSuite) TestDelayScalesWithPressure(c *gc.C) {
+	c.Assert(s.policy.Delay(0, 0), gc.Equals, 5*time.Minute)
+	c.Assert(s.policy.Delay(5, 0), gc.Equals, 165*time.Second)
+	c.Assert(s.policy.Delay(10, 0), gc.Equals, 30*time.Second)
+	c.Assert(s.policy.Delay(1000, 0), gc.Equals, 30*time.Second)
+}
+
+func (s *flushSuite) TestDelayBacksOffOnFailure(c *gc.C) {
+	c.Assert(s.policy.Delay(0, 1), gc.Equals, 30*time.Second)
+	c.Assert(s.policy.Delay(0, 2), gc.Equals, time.Minute)
+	c.Assert(s.policy.Delay(0, 3), gc.Equals, 2*time.Minute)
+	c.Assert(s.policy.Delay(0, 4), gc.Equals, 4*time.Minute)
+	c.Assert(s.policy.Delay(0, 5), gc.Equals, 4*time.Minute)
+	c.Assert(s.policy.Delay(0, 100), gc.Equals, 4*time.Minute)
+}
+
+func (s *flushSuite) TestWorkerRetriesWithBackoff(c *gc.C) {
+	clock := testing.NewClock(time.Now())
+	flushed := make(chan struct{}, 10)
+	results := []error{errors.New("boom"), errors.New("boom"), nil}
+	flush := func(<-chan struct{}) (int, error) {
+		var err error
+		err, results = results[0], results[1:]
+		flushed <- struct{}{}
+		return 0, err
+	}
+	w := sender.NewFlushWorkerForTest(flush, s.policy, clock)
+	defer func() {
+		w.Kill()
+		c.Assert(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	waitFlush := func() {
+		select {
+		case <-flushed:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for flush")
+		}
+	}
+	// The first flush happens immediately.
+	c.Assert(clock.WaitAdvance(0, coretesting.LongWait, 1), jc.ErrorIsNil)
+	waitFlush()
+	// Failed flushes are retried with an increasing delay.
+	c.Assert(clock.WaitAdvance(30*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	waitFlush()
+	c.Assert(clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	waitFlush()
+	// After a successful flush the normal period applies.
+	c.Assert(clock.WaitAdvance(5*time.Minute-time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	select {
+	case <-flushed:
+		c.Fatalf("unexpected flush")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
@@ -8,13 +8,13 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/juju/worker.v1"
 
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/metricsadder"
-	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/metrics/spool"
 	"github.com/juju/juju/worker/uniter"
@@ -72,7 +72,14 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
-			return spool.NewPeriodicWorker(s.Do, period, jworker.NewTimer, s.stop), nil
+			policy := flushPolicy{
+				Period:            period,
+				MinPeriod:         DefaultMinFlushPeriod,
+				PressureThreshold: DefaultPressureThreshold,
+				MaxBackoff:        DefaultMaxFlushBackoff,
+				Jitter:            0.2,
+			}
+			return newFlushWorker(s.flush, policy, clock.WallClock, s.stop), nil
 		},
 	}
 }
@@ -36,26 +36,39 @@ type sender struct {
 // Do sends metrics from the metric spool to the
 // controller via an api call.
 func (s *sender) Do(stop <-chan struct{}) error {
+	_, err := s.flush(stop)
+	return err
+}
+
+// flush sends metrics from the metric spool to the controller,
+// returning the number of batches that were found in the spool.
+func (s *sender) flush(stop <-chan struct{}) (int, error) {
 	reader, err := s.factory.Reader()
 	if err != nil {
-		return errors.Trace(err)
+		return 0, errors.Trace(err)
 	}
 	defer reader.Close()
 	return s.sendMetrics(reader)
 }
 
-func (s *sender) sendMetrics(reader spool.MetricReader) error {
+// sendMetrics sends all batches in the unit's spool to the controller
+// in a single API call. It returns the number of batches in the spool
+// before any were sent, which is the measure of spool pressure used to
+// schedule the next flush; once the spool has been drained it is
+// always empty.
+func (s *sender) sendMetrics(reader spool.MetricReader) (int, error) {
 	batches, err := reader.Read()
 	if err != nil {
-		return errors.Annotate(err, "failed to open the metric reader")
+		return 0, errors.Annotate(err, "failed to open the metric reader")
 	}
+	pending := len(batches)
 	var sendBatches []params.MetricBatchParam
 	for _, batch := range batches {
 		sendBatches = append(sendBatches, spool.APIMetricBatch(batch))
 	}
 	results, err := s.client.AddMetricBatches(sendBatches)
 	if err != nil {
-		return errors.Annotate(err, "could not send metrics")
+		return pending, errors.Annotate(err, "could not send metrics")
 	}
 	for batchUUID, resultErr := range results {
 		// if we fail to send any metric batch we log a warning with the assumption that
@@ -70,7 +83,7 @@ func (s *sender) sendMetrics(reader spool.MetricReader) error {
 			logger.Errorf("failed to send batch %q: %v", batchUUID, resultErr)
 		}
 	}
-	return nil
+	return pending, nil
 }
 
 // Handle sends metrics from the spool directory to the
@@ -88,12 +101,8 @@ func (s *sender) Handle(c net.Conn, _ <-chan struct{}) (err error) {
 	if err := c.SetDeadline(time.Now().Add(spool.DefaultTimeout)); err != nil {
 		return errors.Annotate(err, "failed to set the deadline")
 	}
-	reader, err := s.factory.Reader()
-	if err != nil {
-		return errors.Trace(err)
-	}
-	defer reader.Close()
-	return s.sendMetrics(reader)
+	_, err = s.flush(nil)
+	return errors.Trace(err)
 }
 
 func (s *sender) stop() {
@@ -116,6 +116,56 @@ func (s *senderSuite) TestMetricSendingSuccess(c *gc.C) {
 	c.Assert(batches, gc.HasLen, 0)
 }
 
+func (s *senderSuite) TestMetricSendingSingleCall(c *gc.C) {
+	declaredMetrics := map[string]corecharm.Metric{
+		"pings": corecharm.Metric{Description: "test pings", Type: corecharm.MetricTypeAbsolute},
+	}
+	for _, unitTag := range []string{"testcharm/1", "testcharm/2", "testcharm/3", "testcharm/4"} {
+		recorder, err := s.metricfactory.Recorder(declaredMetrics, "local:trusty/testcharm", unitTag)
+		c.Assert(err, jc.ErrorIsNil)
+		err = recorder.AddMetric("pings", "50", time.Now())
+		c.Assert(err, jc.ErrorIsNil)
+		err = recorder.Close()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	apiSender := newTestAPIMetricSender()
+	metricSender, err := sender.NewSender(apiSender, s.metricfactory, s.socketDir, "test-unit-0")
+	c.Assert(err, jc.ErrorIsNil)
+	stopCh := make(chan struct{})
+	err = metricSender.Do(stopCh)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// All five spooled batches are sent in one call.
+	c.Assert(apiSender.calls, gc.Equals, 1)
+	c.Assert(apiSender.batches, gc.HasLen, 5)
+
+	reader, err := spool.NewJSONMetricReader(s.spoolDir)
+	c.Assert(err, jc.ErrorIsNil)
+	batches, err := reader.Read()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(batches, gc.HasLen, 0)
+}
+
+func (s *senderSuite) TestFlushReportsPendingBeforeDrain(c *gc.C) {
+	apiSender := newTestAPIMetricSender()
+	metricSender, err := sender.NewSender(apiSender, s.metricfactory, s.socketDir, "test-unit-0")
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err := sender.Flush(metricSender)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.Equals, 1)
+
+	reader, err := spool.NewJSONMetricReader(s.spoolDir)
+	c.Assert(err, jc.ErrorIsNil)
+	batches, err := reader.Read()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(batches, gc.HasLen, 0)
+
+	pending, err = sender.Flush(metricSender)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.Equals, 0)
+}
+
 func (s *senderSuite) TestSendingGetDuplicate(c *gc.C) {
 	apiSender := newTestAPIMetricSender()
 
@@ -205,12 +255,14 @@ func newTestAPIMetricSender() *testAPIMetricSender {
 }
 
 type testAPIMetricSender struct {
+	calls     int
 	batches   []params.MetricBatchParam
 	errors    chan error
 	sendError chan error
 }
 
 func (t *testAPIMetricSender) AddMetricBatches(batches []params.MetricBatchParam) (map[string]error, error) {
+	t.calls++
 	t.batches = batches
 
 	var err error
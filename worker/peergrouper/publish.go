@@ -4,9 +4,12 @@
 package peergrouper
 
 import (
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils"
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -19,6 +22,12 @@ type apiHostPortsSetter interface {
 type publisher struct {
 	st apiHostPortsSetter
 
+	// srvName, if non-empty, holds the DNS name under which the
+	// API servers are published as SRV records; the zone file
+	// fragment holding them is written to zoneDir.
+	srvName string
+	zoneDir string
+
 	mu             sync.Mutex
 	lastAPIServers [][]network.HostPort
 }
@@ -49,10 +58,32 @@ func (pub *publisher) publishAPIServers(apiServers [][]network.HostPort, instanc
 	if err != nil {
 		return err
 	}
+	if err := pub.writeSRVZone(sortedAPIServers); err != nil {
+		return errors.Annotate(err, "cannot publish API server SRV records")
+	}
 	pub.lastAPIServers = sortedAPIServers
 	return nil
 }
 
+// writeSRVZone writes the SRV records for the given API servers to
+// <zoneDir>/<srvName>.zone, replacing any previous content, so that it
+// can be included by the authoritative DNS server for srvName.
+func (pub *publisher) writeSRVZone(apiServers [][]network.HostPort) error {
+	if pub.srvName == "" {
+		return nil
+	}
+	if err := os.MkdirAll(pub.zoneDir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	zone := network.APISRVZone(pub.srvName, apiServers)
+	path := filepath.Join(pub.zoneDir, pub.srvName+".zone")
+	if err := utils.AtomicWriteFile(path, []byte(zone), 0644); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("published API server SRV records to %s", path)
+	return nil
+}
+
 func apiServersEqual(a, b [][]network.HostPort) bool {
 	if len(a) != len(b) {
 		return false
@@ -4,6 +4,9 @@
 package peergrouper
 
 import (
+	"io/ioutil"
+	"path/filepath"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -79,3 +82,18 @@ func (s *publishSuite) TestPublisherRejectsNoServers(c *gc.C) {
 	err := statePublish.PublishAPIServers(nil, nil)
 	c.Assert(err, gc.ErrorMatches, "no api servers specified")
 }
+
+func (s *publishSuite) TestPublisherWritesSRVZone(c *gc.C) {
+	var mock mockAPIHostPortsSetter
+	statePublish := newPublisher(&mock)
+	statePublish.srvName = "juju.example.com"
+	statePublish.zoneDir = filepath.Join(c.MkDir(), "dns")
+
+	apiServers := [][]network.HostPort{network.NewHostPorts(17070, "10.0.0.1")}
+	err := statePublish.publishAPIServers(apiServers, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(filepath.Join(statePublish.zoneDir, "juju.example.com.zone"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, network.APISRVZone("juju.example.com", apiServers))
+}
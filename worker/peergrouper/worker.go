@@ -118,8 +118,10 @@ type pgWorker struct {
 }
 
 // New returns a new worker that maintains the mongo replica set
-// with respect to the given state.
-func New(st *state.State, clock clock.Clock, supportsSpaces bool, hub Hub) (worker.Worker, error) {
+// with respect to the given state. If the controller is configured
+// with an api-srv-name, SRV records for the API servers are
+// published to a zone file fragment in zoneDir.
+func New(st *state.State, clock clock.Clock, supportsSpaces bool, hub Hub, zoneDir string) (worker.Worker, error) {
 	cfg, err := st.ControllerConfig()
 	if err != nil {
 		return nil, err
@@ -129,7 +131,10 @@ func New(st *state.State, clock clock.Clock, supportsSpaces bool, hub Hub) (work
 		mongoPort: cfg.StatePort(),
 		apiPort:   cfg.APIPort(),
 	}
-	return newWorker(shim, clock, newPublisher(st), supportsSpaces, hub)
+	pub := newPublisher(st)
+	pub.srvName = cfg.APISRVName()
+	pub.zoneDir = zoneDir
+	return newWorker(shim, clock, pub, supportsSpaces, hub)
 }
 
 func newWorker(st stateInterface, clock clock.Clock, pub publisherInterface, supportsSpaces bool, hub Hub) (worker.Worker, error) {
@@ -13,6 +13,7 @@ import (
 	"gopkg.in/juju/names.v2"
 	worker "gopkg.in/juju/worker.v1"
 
+	"github.com/juju/juju/agent"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/params"
@@ -526,6 +527,14 @@ func (task *provisionerTask) constructInstanceConfig(
 		return nil, errors.Trace(err)
 	}
 
+	if srvName := controller.Config(pInfo.ControllerConfig).APISRVName(); srvName != "" {
+		if instanceConfig.AgentEnvironment == nil {
+			instanceConfig.AgentEnvironment = make(map[string]string)
+		}
+		instanceConfig.AgentEnvironment[agent.APISRVName] = srvName
+	}
+
+	instanceConfig.ControllerAPIPorts = controller.Config(pInfo.ControllerConfig).APIPorts()
 	instanceConfig.Tags = pInfo.Tags
 	if len(pInfo.Jobs) > 0 {
 		instanceConfig.Jobs = pInfo.Jobs
@@ -733,9 +742,20 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 	return nil
 }
 
+// statusDataError is implemented by errors that carry diagnostic data,
+// such as the start logs of a container that failed to start, to
+// be recorded with a machine's provisioning error status.
+type statusDataError interface {
+	StatusData() map[string]interface{}
+}
+
 func (task *provisionerTask) setErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
 	logger.Errorf(message, machine, err)
-	if err := machine.SetInstanceStatus(status.ProvisioningError, err.Error(), nil); err != nil {
+	var data map[string]interface{}
+	if dataErr, ok := errors.Cause(err).(statusDataError); ok {
+		data = dataErr.StatusData()
+	}
+	if err := machine.SetInstanceStatus(status.ProvisioningError, err.Error(), data); err != nil {
 		// Something is wrong with this machine, better report it back.
 		return errors.Annotatef(err, "cannot set error status for machine %q", machine)
 	}
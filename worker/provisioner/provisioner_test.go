@@ -597,6 +597,46 @@ func (s *ProvisionerSuite) TestProvisionerFailedStartInstanceWithInjectedCreatio
 	c.Check(instanceStatus.Message, gc.Equals, destroyError.Error())
 }
 
+// statusDataError is an error carrying diagnostic status data, as
+// returned when an LXD container fails to start.
+type statusDataError struct {
+	error
+	data map[string]interface{}
+}
+
+func (e statusDataError) StatusData() map[string]interface{} {
+	return e.data
+}
+
+func (s *ProvisionerSuite) TestProvisionerFailedStartInstanceRecordsStatusData(c *gc.C) {
+	s.PatchValue(provisioner.RetryStrategyDelay, 0*time.Second)
+	s.PatchValue(provisioner.RetryStrategyCount, 0)
+
+	errorInjectionChannel := make(chan error, 1)
+
+	p := s.newEnvironProvisioner(c)
+	defer stop(c, p)
+
+	cleanup := dummy.PatchTransientErrorInjectionChannel(errorInjectionChannel)
+	defer cleanup()
+
+	errorInjectionChannel <- statusDataError{
+		error: errors.New("container failed to start"),
+		data:  map[string]interface{}{"start-log": "==> lxc.log <==\nmount failed\n"},
+	}
+
+	m, err := s.addMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkNoOperations(c)
+
+	_, instanceStatus := s.waitUntilMachineNotPending(c, m)
+	c.Check(instanceStatus.Status, gc.Equals, status.ProvisioningError)
+	c.Check(instanceStatus.Message, gc.Equals, "container failed to start")
+	c.Check(instanceStatus.Data, jc.DeepEquals, map[string]interface{}{
+		"start-log": "==> lxc.log <==\nmount failed\n",
+	})
+}
+
 func (s *ProvisionerSuite) TestProvisionerSucceedStartInstanceWithInjectedRetryableCreationError(c *gc.C) {
 	// Set the retry delay to 0, and retry count to 2 to keep tests short
 	s.PatchValue(provisioner.RetryStrategyDelay, 0*time.Second)
@@ -0,0 +1,169 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package applicationhooks runs the hooks that a charm declares, in its
+// application-hooks directory, to be run once for the whole application
+// rather than once by every unit; for example, to migrate a database
+// schema shared by the units.
+//
+// Application hooks are run by the application's leader, in name order,
+// once the unit has started, and again after each change of charm.
+// The completion of each hook is recorded on the controller, where it
+// is not visible to the charm, and can only be written while holding
+// the leadership lease. If leadership changes while a hook is running,
+// the hook is killed and its completion is not recorded, and the new
+// leader runs it instead.
+package applicationhooks
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	jujuos "github.com/juju/utils/os"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/remotestate"
+	"github.com/juju/juju/worker/uniter/resolver"
+)
+
+var logger = loggo.GetLogger("juju.worker.uniter.applicationhooks")
+
+// Config holds the configuration of an application hooks resolver.
+type Config struct {
+	// Hooks returns the names of the application hooks of the
+	// deployed charm, in the order in which they are to be run.
+	Hooks func() ([]string, error)
+
+	// CompletedHooks returns the application hooks recorded on the
+	// controller as completed, keyed on hook name, with the URL of
+	// the charm each last completed for.
+	CompletedHooks func() (map[string]string, error)
+}
+
+type applicationHooksResolver struct {
+	config Config
+
+	// hooks caches the application hooks of the charm identified by
+	// hooksCharm, so that the charm directory is only read again
+	// when the charm changes.
+	hooks      []string
+	hooksCharm *charmVersion
+
+	// completed caches the completed hooks recorded on the
+	// controller. Only the leader records completions, so they are
+	// read once each time the unit becomes the leader.
+	completed map[string]string
+}
+
+// charmVersion identifies the deployed charm's content.
+type charmVersion struct {
+	url             string
+	modifiedVersion int
+}
+
+// NewResolver returns a new application hooks resolver.
+func NewResolver(config Config) resolver.Resolver {
+	return &applicationHooksResolver{config: config}
+}
+
+// NextOp is defined on the Resolver interface.
+func (r *applicationHooksResolver) NextOp(
+	localState resolver.LocalState,
+	remoteState remotestate.Snapshot,
+	opFactory operation.Factory,
+) (operation.Operation, error) {
+	if !localState.Leader {
+		r.completed = nil
+		return nil, resolver.ErrNoOperation
+	}
+	if !localState.Started || localState.Kind != operation.Continue {
+		return nil, resolver.ErrNoOperation
+	}
+	if remoteState.Life != params.Alive || localState.CharmURL == nil {
+		return nil, resolver.ErrNoOperation
+	}
+	charmURL := localState.CharmURL.String()
+	names, err := r.charmHooks(charmVersion{charmURL, localState.CharmModifiedVersion})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list application hooks")
+	}
+	if len(names) == 0 {
+		return nil, resolver.ErrNoOperation
+	}
+	if r.completed == nil {
+		completed, err := r.config.CompletedHooks()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot read completed application hooks")
+		}
+		if completed == nil {
+			completed = make(map[string]string)
+		}
+		r.completed = completed
+	}
+	for _, name := range names {
+		if r.completed[name] == charmURL {
+			continue
+		}
+		// The controller's record, as read, does not include
+		// the hooks that this unit has run since.
+		if localState.CompletedApplicationHooks[name] == charmURL {
+			continue
+		}
+		logger.Debugf("application hook %q has not run for %s", name, charmURL)
+		return opFactory.NewRunHook(hook.Info{
+			Kind:                hook.ApplicationHook,
+			ApplicationHookName: name,
+			CharmURL:            charmURL,
+		})
+	}
+	return nil, resolver.ErrNoOperation
+}
+
+// charmHooks returns the application hooks of the given charm, reading
+// them only if the charm has changed since they were last read.
+func (r *applicationHooksResolver) charmHooks(charm charmVersion) ([]string, error) {
+	if r.hooksCharm != nil && *r.hooksCharm == charm {
+		return r.hooks, nil
+	}
+	hooks, err := r.config.Hooks()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	r.hooks = hooks
+	r.hooksCharm = &charm
+	return hooks, nil
+}
+
+// CharmHooks returns the names of the application hooks of the charm
+// deployed in charmDir, sorted. Elsewhere than on Windows, only the
+// executable files of the application-hooks directory are hooks; on
+// Windows, every file is, named without its extension.
+func CharmHooks(charmDir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(charmDir, hook.ApplicationHooksDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	names := set.NewStrings()
+	for _, info := range infos {
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		name := info.Name()
+		if jujuos.HostOS() == jujuos.Windows {
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+		} else if info.Mode()&0111 == 0 {
+			continue
+		}
+		names.Add(name)
+	}
+	return names.SortedValues(), nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package applicationhooks_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	jujuos "github.com/juju/utils/os"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/uniter/applicationhooks"
+	"github.com/juju/juju/worker/uniter/operation"
+	"github.com/juju/juju/worker/uniter/remotestate"
+	"github.com/juju/juju/worker/uniter/resolver"
+)
+
+type resolverSuite struct {
+	testing.IsolationSuite
+
+	stub        testing.Stub
+	hooks       []string
+	completed   map[string]string
+	localState  resolver.LocalState
+	remoteState remotestate.Snapshot
+	opFactory   operation.Factory
+	resolver    resolver.Resolver
+}
+
+var _ = gc.Suite(&resolverSuite{})
+
+func (s *resolverSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = testing.Stub{}
+	s.hooks = []string{"create-schema", "migrate"}
+	s.completed = map[string]string{
+		"create-schema": "cs:wordpress-1",
+	}
+	s.localState = resolver.LocalState{
+		CharmURL: charm.MustParseURL("cs:wordpress-1"),
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+			Leader:    true,
+		},
+	}
+	s.remoteState = remotestate.Snapshot{
+		Life:   params.Alive,
+		Leader: true,
+	}
+	s.opFactory = operation.NewFactory(operation.FactoryParams{})
+	s.resolver = applicationhooks.NewResolver(applicationhooks.Config{
+		Hooks: func() ([]string, error) {
+			s.stub.AddCall("Hooks")
+			return s.hooks, s.stub.NextErr()
+		},
+		CompletedHooks: func() (map[string]string, error) {
+			s.stub.AddCall("CompletedHooks")
+			completed := make(map[string]string)
+			for k, v := range s.completed {
+				completed[k] = v
+			}
+			return completed, s.stub.NextErr()
+		},
+	})
+}
+
+func (s *resolverSuite) TestRunsPendingHook(c *gc.C) {
+	op, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run application-hook (migrate) hook")
+	s.stub.CheckCallNames(c, "Hooks", "CompletedHooks")
+}
+
+func (s *resolverSuite) TestRunsHooksAgainForNewCharm(c *gc.C) {
+	s.localState.CharmURL = charm.MustParseURL("cs:wordpress-2")
+	op, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run application-hook (create-schema) hook")
+}
+
+func (s *resolverSuite) TestLocallyCompletedHook(c *gc.C) {
+	s.localState.CompletedApplicationHooks = map[string]string{
+		"migrate": "cs:wordpress-1",
+	}
+	_, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *resolverSuite) TestHooksReadOnCharmChange(c *gc.C) {
+	_, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCallNames(c, "Hooks", "CompletedHooks")
+
+	s.localState.CharmModifiedVersion = 1
+	_, err = s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	s.localState.CharmURL = charm.MustParseURL("cs:wordpress-2")
+	_, err = s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCallNames(c, "Hooks", "CompletedHooks", "Hooks", "Hooks")
+}
+
+func (s *resolverSuite) TestCompletedHooksReadOnBecomingLeader(c *gc.C) {
+	_, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	s.completed["migrate"] = "cs:wordpress-1"
+
+	// The completed hooks are only read again once the unit has
+	// lost and regained leadership.
+	op, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run application-hook (migrate) hook")
+	s.stub.CheckCallNames(c, "Hooks", "CompletedHooks")
+
+	s.localState.Leader = false
+	_, err = s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.localState.Leader = true
+	_, err = s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckCallNames(c, "Hooks", "CompletedHooks", "CompletedHooks")
+}
+
+func (s *resolverSuite) TestNotLeader(c *gc.C) {
+	s.localState.Leader = false
+	_, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *resolverSuite) TestNotStarted(c *gc.C) {
+	s.localState.Started = false
+	_, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *resolverSuite) TestDying(c *gc.C) {
+	s.remoteState.Life = params.Dying
+	_, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *resolverSuite) TestNoHooks(c *gc.C) {
+	s.hooks = nil
+	_, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	s.stub.CheckCallNames(c, "Hooks")
+}
+
+func (s *resolverSuite) TestHooksError(c *gc.C) {
+	s.stub.SetErrors(errors.New("boom"))
+	_, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.ErrorMatches, "cannot list application hooks: boom")
+}
+
+func (s *resolverSuite) TestCompletedHooksError(c *gc.C) {
+	s.stub.SetErrors(nil, errors.New("boom"))
+	_, err := s.resolver.NextOp(s.localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.ErrorMatches, "cannot read completed application hooks: boom")
+}
+
+type charmHooksSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&charmHooksSuite{})
+
+func (s *charmHooksSuite) TestCharmHooks(c *gc.C) {
+	if jujuos.HostOS() == jujuos.Windows {
+		c.Skip("hooks need not be executable on Windows")
+	}
+	charmDir := c.MkDir()
+	hooksDir := filepath.Join(charmDir, "application-hooks")
+	err := os.MkdirAll(filepath.Join(hooksDir, "lib"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	for name, mode := range map[string]os.FileMode{
+		"migrate":       0755,
+		"create-schema": 0755,
+		"README":        0644,
+	} {
+		err := ioutil.WriteFile(filepath.Join(hooksDir, name), nil, mode)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	names, err := applicationhooks.CharmHooks(charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"create-schema", "migrate"})
+}
+
+func (s *charmHooksSuite) TestCharmHooksNoDirectory(c *gc.C) {
+	names, err := applicationhooks.CharmHooks(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+}
@@ -68,6 +68,17 @@ type Deployer interface {
 	Deploy() error
 }
 
+// Verifier is implemented by Deployers that can check whether the files
+// of the deployed charm are still intact.
+type Verifier interface {
+
+	// Verify returns the slash-separated paths, relative to the charm
+	// directory, of the files belonging to the deployed charm that are
+	// missing or have changed since it was deployed. It returns no paths
+	// if no charm has been deployed.
+	Verify() ([]string, error)
+}
+
 // ErrConflict indicates that an upgrade failed and cannot be resolved
 // without human intervention.
 var ErrConflict = errors.New("charm upgrade has conflicts")
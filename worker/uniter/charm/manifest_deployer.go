@@ -6,7 +6,9 @@ package charm
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/juju/utils"
 	"github.com/juju/utils/set"
@@ -21,8 +23,46 @@ const (
 	// manifestsDataPath holds the path in the data dir where the manifest
 	// deployer stores the manifests for its charms.
 	manifestsDataPath = "manifests"
+
+	// hashesDataPath holds the path in the data dir where the manifest
+	// deployer stores the SHA-256 hashes of the files it has deployed.
+	hashesDataPath = "hashes"
 )
 
+// charmWritablePatterns match the names of files and directories that
+// charms commonly write into their own directory, such as compiled
+// Python modules and the charm helpers' unit state. Changes to them are
+// not damage, so they are never verified.
+var charmWritablePatterns = []string{
+	"*.pyc",
+	"*.pyo",
+	"__pycache__",
+	".unit-state.db",
+}
+
+// charmWritable reports whether the supplied slash-separated path,
+// relative to the charm directory, is or is within a path matched by
+// charmWritablePatterns.
+func charmWritable(p string) bool {
+	for _, name := range strings.Split(p, "/") {
+		for _, pattern := range charmWritablePatterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deployedFile records what a regular file deployed from a charm looked
+// like, so that it can be verified later. The size and modification time
+// allow intact files to be recognised without being hashed.
+type deployedFile struct {
+	SHA256  string `yaml:"sha256"`
+	Size    int64  `yaml:"size"`
+	ModTime int64  `yaml:"mtime"`
+}
+
 // NewManifestDeployer returns a Deployer that installs bundles from the
 // supplied BundleReader into charmPath, and which reads and writes its
 // persistent data into dataPath.
@@ -104,6 +144,11 @@ func (d *manifestDeployer) Deploy() (err error) {
 		return err
 	}
 
+	// Record what we deployed, so that it can be verified later.
+	if err := d.storeHashes(d.staged.url, d.staged.manifest); err != nil {
+		return err
+	}
+
 	// Move the deploying file over the charm URL file, and we're done.
 	return d.finishDeploy()
 }
@@ -191,6 +236,106 @@ func (d *manifestDeployer) loadManifest(urlFilePath string) (*charm.URL, set.Str
 	return url, set.NewStrings(manifest...), err
 }
 
+// Verify is part of the Verifier interface. Files whose size and
+// modification time are unchanged since deployment are not hashed, and
+// files that charms commonly write themselves are not verified.
+func (d *manifestDeployer) Verify() ([]string, error) {
+	url, manifest, err := d.loadManifest(CharmURLPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	files := map[string]deployedFile{}
+	err = utils.ReadYaml(d.hashesPath(url), &files)
+	haveHashes := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var damaged []string
+	for _, path := range manifest.SortedValues() {
+		if charmWritable(path) {
+			continue
+		}
+		fullPath := filepath.Join(d.charmPath, filepath.FromSlash(path))
+		info, err := os.Lstat(fullPath)
+		if os.IsNotExist(err) {
+			damaged = append(damaged, path)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		expected, ok := files[path]
+		if !ok {
+			continue
+		}
+		if !info.Mode().IsRegular() || info.Size() != expected.Size {
+			damaged = append(damaged, path)
+			continue
+		}
+		if info.ModTime().UnixNano() == expected.ModTime {
+			continue
+		}
+		actual, _, err := utils.ReadFileSHA256(fullPath)
+		if err != nil {
+			return nil, err
+		}
+		if actual != expected.SHA256 {
+			damaged = append(damaged, path)
+		}
+	}
+	if !haveHashes && len(damaged) == 0 {
+		// The charm was deployed before hashes were recorded; record
+		// them now, so that later changes can be detected.
+		logger.Infof("recording hashes of files from charm %q", url)
+		if err := d.storeHashes(url, manifest); err != nil {
+			return nil, err
+		}
+	}
+	return damaged, nil
+}
+
+// storeHashes stores, into dataPath, the hashes, sizes and modification
+// times of the regular files in the charm directory that are referenced
+// by the supplied manifest and are not charm-writable.
+func (d *manifestDeployer) storeHashes(url *charm.URL, manifest set.Strings) error {
+	files := make(map[string]deployedFile)
+	for _, path := range manifest.Values() {
+		if charmWritable(path) {
+			continue
+		}
+		fullPath := filepath.Join(d.charmPath, filepath.FromSlash(path))
+		info, err := os.Lstat(fullPath)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		hash, _, err := utils.ReadFileSHA256(fullPath)
+		if err != nil {
+			return err
+		}
+		files[path] = deployedFile{
+			SHA256:  hash,
+			Size:    info.Size(),
+			ModTime: info.ModTime().UnixNano(),
+		}
+	}
+	if err := os.MkdirAll(d.DataPath(hashesDataPath), 0755); err != nil {
+		return err
+	}
+	return utils.WriteYaml(d.hashesPath(url), files)
+}
+
+// hashesPath returns the path in dataPath of the file holding the hashes
+// of the files deployed for the supplied charm.
+func (d *manifestDeployer) hashesPath(url *charm.URL) string {
+	return filepath.Join(d.DataPath(hashesDataPath), charm.Quote(url.String()))
+}
+
 // CharmPath returns the supplied path joined to the ManifestDeployer's charm directory.
 func (d *manifestDeployer) CharmPath(path string) string {
 	return filepath.Join(d.charmPath, path)
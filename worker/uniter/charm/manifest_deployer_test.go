@@ -5,8 +5,11 @@ package charm_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	ft "github.com/juju/testing/filetesting"
@@ -257,3 +260,90 @@ func (s *ManifestDeployerSuite) TestUpgradeConflictRevertRetryDifferentCharm(c *
 	ft.Removed{"old-file"}.Check(c, s.targetPath)
 	ft.Removed{"bad-file"}.Check(c, s.targetPath)
 }
+
+func (s *ManifestDeployerSuite) TestVerifyNotDeployed(c *gc.C) {
+	damaged, err := s.deployer.(charm.Verifier).Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(damaged, gc.HasLen, 0)
+}
+
+func (s *ManifestDeployerSuite) TestVerifyIntact(c *gc.C) {
+	s.deployCharm(c, 1,
+		ft.File{"some-file", "hello", 0644},
+		ft.Dir{"some-dir", 0755},
+		ft.File{"some-dir/another-file", "world", 0644},
+	)
+	ft.File{"user-file", "user", 0644}.Create(c, s.targetPath)
+
+	damaged, err := s.deployer.(charm.Verifier).Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(damaged, gc.HasLen, 0)
+}
+
+func (s *ManifestDeployerSuite) TestVerifyDamaged(c *gc.C) {
+	s.deployCharm(c, 1,
+		ft.File{"some-file", "hello", 0644},
+		ft.File{"changed-file", "hello", 0644},
+		ft.Dir{"some-dir", 0755},
+		ft.File{"some-dir/another-file", "world", 0644},
+	)
+	ft.Removed{"some-dir"}.Create(c, s.targetPath)
+	ft.File{"changed-file", "goodbye", 0644}.Create(c, s.targetPath)
+
+	damaged, err := s.deployer.(charm.Verifier).Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(damaged, jc.DeepEquals, []string{"changed-file", "some-dir", "some-dir/another-file"})
+}
+
+func (s *ManifestDeployerSuite) TestVerifyIgnoresCharmWritableFiles(c *gc.C) {
+	s.deployCharm(c, 1,
+		ft.File{"some-file", "hello", 0644},
+		ft.File{"module.pyc", "compiled", 0644},
+		ft.Dir{"lib", 0755},
+		ft.Dir{"lib/__pycache__", 0755},
+		ft.File{"lib/__pycache__/module.pyc", "compiled", 0644},
+	)
+	ft.File{"module.pyc", "recompiled", 0644}.Create(c, s.targetPath)
+	ft.Removed{"lib/__pycache__"}.Create(c, s.targetPath)
+
+	damaged, err := s.deployer.(charm.Verifier).Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(damaged, gc.HasLen, 0)
+}
+
+func (s *ManifestDeployerSuite) TestVerifyChangedContentSameSize(c *gc.C) {
+	s.deployCharm(c, 1,
+		ft.File{"some-file", "hello", 0644},
+	)
+	path := filepath.Join(s.targetPath, "some-file")
+	err := ioutil.WriteFile(path, []byte("jello"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	later := time.Now().Add(time.Minute)
+	err = os.Chtimes(path, later, later)
+	c.Assert(err, jc.ErrorIsNil)
+
+	damaged, err := s.deployer.(charm.Verifier).Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(damaged, jc.DeepEquals, []string{"some-file"})
+}
+
+func (s *ManifestDeployerSuite) TestRedeployRepairsDamage(c *gc.C) {
+	content := ft.Entries{
+		ft.File{"some-file", "hello", 0644},
+		ft.Dir{"some-dir", 0755},
+		ft.File{"some-dir/another-file", "world", 0644},
+	}
+	info := s.deployCharm(c, 1, content...)
+	ft.Removed{"some-dir/another-file"}.Create(c, s.targetPath)
+	ft.File{"some-file", "goodbye", 0644}.Create(c, s.targetPath)
+
+	err := s.deployer.Stage(info, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.deployer.Deploy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCharm(c, 1, content...)
+
+	damaged, err := s.deployer.(charm.Verifier).Verify()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(damaged, gc.HasLen, 0)
+}
@@ -16,8 +16,17 @@ const (
 	LeaderElected         hooks.Kind = "leader-elected"
 	LeaderDeposed         hooks.Kind = "leader-deposed"
 	LeaderSettingsChanged hooks.Kind = "leader-settings-changed"
+
+	// ApplicationHook identifies a hook, found in the charm's
+	// application-hooks directory, that is run once for the whole
+	// application, by its leader, rather than once by every unit.
+	ApplicationHook hooks.Kind = "application-hook"
 )
 
+// ApplicationHooksDir is the directory of a charm that holds its
+// application hooks.
+const ApplicationHooksDir = "application-hooks"
+
 // Info holds details required to execute a hook. Not all fields are
 // relevant to all Kind values.
 type Info struct {
@@ -35,8 +44,21 @@ type Info struct {
 	// associated with RemoteUnit. It is only set when RemoteUnit is set.
 	ChangeVersion int64 `yaml:"change-version,omitempty"`
 
+	// DepartingUnit is the name of the unit that is leaving the relation.
+	// It is only set when Kind is relation-departed, and is either the
+	// local unit or RemoteUnit.
+	DepartingUnit string `yaml:"departing-unit,omitempty"`
+
 	// StorageId is the ID of the storage instance relevant to the hook.
 	StorageId string `yaml:"storage-id,omitempty"`
+
+	// ApplicationHookName is the name of the application hook to run.
+	// It is only set when Kind is application-hook.
+	ApplicationHookName string `yaml:"application-hook-name,omitempty"`
+
+	// CharmURL is the URL of the charm for which the application hook
+	// is run. It is only set when Kind is application-hook.
+	CharmURL string `yaml:"charm-url,omitempty"`
 }
 
 // Validate returns an error if the info is not valid.
@@ -60,6 +82,14 @@ func (hi Info) Validate() error {
 	// TODO(fwereade): define these in charm/hooks...
 	case LeaderElected, LeaderDeposed, LeaderSettingsChanged:
 		return nil
+	case ApplicationHook:
+		if hi.ApplicationHookName == "" {
+			return fmt.Errorf("%q hook requires a hook name", hi.Kind)
+		}
+		if hi.CharmURL == "" {
+			return fmt.Errorf("%q hook requires a charm URL", hi.Kind)
+		}
+		return nil
 	}
 	return fmt.Errorf("unknown hook kind %q", hi.Kind)
 }
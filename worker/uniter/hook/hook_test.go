@@ -50,6 +50,9 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.StorageAttached}, `invalid storage ID ""`},
 	{hook.Info{Kind: hooks.StorageAttached, StorageId: "data/0"}, ""},
 	{hook.Info{Kind: hooks.StorageDetaching, StorageId: "data/0"}, ""},
+	{hook.Info{Kind: hook.ApplicationHook, CharmURL: "cs:wordpress-1"}, `"application-hook" hook requires a hook name`},
+	{hook.Info{Kind: hook.ApplicationHook, ApplicationHookName: "migrate"}, `"application-hook" hook requires a charm URL`},
+	{hook.Info{Kind: hook.ApplicationHook, ApplicationHookName: "migrate", CharmURL: "cs:wordpress-1"}, ""},
 }
 
 func (s *InfoSuite) TestValidate(c *gc.C) {
@@ -4,8 +4,11 @@
 package uniter
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/juju/names.v2"
 	worker "gopkg.in/juju/worker.v1"
 
@@ -18,6 +21,17 @@ import (
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/resolver"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+const (
+	// DefaultRelationCacheMaxEntries is the number of units whose
+	// relation settings a unit agent caches for each relation.
+	DefaultRelationCacheMaxEntries = 1000
+
+	// DefaultRelationCacheTTL is the time for which a unit agent
+	// caches relation settings.
+	DefaultRelationCacheTTL = time.Hour
 )
 
 // ManifoldConfig defines the names of the manifolds on which a
@@ -31,11 +45,29 @@ type ManifoldConfig struct {
 	CharmDirName          string
 	HookRetryStrategyName string
 	TranslateResolverErr  func(error) error
+
+	// RelationCacheMaxEntries and RelationCacheTTL bound the caches
+	// of relation settings used by hook contexts; zero values leave
+	// them unbounded.
+	RelationCacheMaxEntries int
+	RelationCacheTTL        time.Duration
+
+	// PrometheusRegisterer, if non-nil, is used to register the
+	// relation cache and context factory metrics.
+	PrometheusRegisterer prometheus.Registerer
 }
 
 // Manifold returns a dependency manifold that runs a uniter worker,
 // using the resource names defined in the supplied config.
 func Manifold(config ManifoldConfig) dependency.Manifold {
+	// The metrics outlive any one uniter, so that the counters are
+	// not reset when the uniter restarts.
+	relationCache := context.RelationCacheConfig{
+		MaxEntries: config.RelationCacheMaxEntries,
+		TTL:        config.RelationCacheTTL,
+		Metrics:    &context.RelationCacheMetrics{},
+	}
+	contextFactoryMetrics := context.NewContextFactoryMetrics()
 	return dependency.Manifold{
 		Inputs: []string{
 			config.AgentName,
@@ -78,6 +110,17 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				return nil, err
 			}
 
+			if config.PrometheusRegisterer != nil {
+				config.PrometheusRegisterer.Unregister(relationCache.Metrics)
+				if err := config.PrometheusRegisterer.Register(relationCache.Metrics); err != nil {
+					return nil, errors.Annotate(err, "registering relation cache metrics")
+				}
+				config.PrometheusRegisterer.Unregister(contextFactoryMetrics)
+				if err := config.PrometheusRegisterer.Register(contextFactoryMetrics); err != nil {
+					return nil, errors.Annotate(err, "registering context factory metrics")
+				}
+			}
+
 			downloader := api.NewCharmDownloader(apiConn.Client())
 
 			manifoldConfig := config
@@ -102,6 +145,9 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				NewOperationExecutor: operation.NewExecutor,
 				TranslateResolverErr: config.TranslateResolverErr,
 				Clock:                manifoldConfig.Clock,
+				RelationCache:        relationCache,
+
+				ContextFactoryMetrics: contextFactoryMetrics,
 			})
 			if err != nil {
 				return nil, errors.Trace(err)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/series"
 	corecharm "gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 	"gopkg.in/juju/names.v2"
@@ -15,6 +16,7 @@ import (
 	"github.com/juju/juju/status"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
+	"github.com/juju/juju/worker/uniter/packages"
 	"github.com/juju/juju/worker/uniter/runner"
 )
 
@@ -45,6 +47,8 @@ func (opc *operationCallbacks) PrepareHook(hi hook.Info) (string, error) {
 		name = fmt.Sprintf("%s-%s", storageName, hi.Kind)
 		// TODO(axw) if the agent is not installed yet,
 		// set the status to "preparing storage".
+	case hi.Kind == hook.ApplicationHook:
+		name = hi.ApplicationHookName
 	case hi.Kind == hooks.ConfigChanged:
 		// TODO(axw)
 		//opc.u.f.DiscardConfigEvent()
@@ -79,6 +83,7 @@ func notifyHook(hook string, ctx runner.Context, method func(string)) {
 
 // NotifyHookCompleted is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) NotifyHookCompleted(hook string, ctx runner.Context) {
+	opc.u.hookKillReason = nil
 	if opc.u.observer != nil {
 		notifyHook(hook, ctx, opc.u.observer.HookCompleted)
 	}
@@ -86,11 +91,18 @@ func (opc *operationCallbacks) NotifyHookCompleted(hook string, ctx runner.Conte
 
 // NotifyHookFailed is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) NotifyHookFailed(hook string, ctx runner.Context) {
+	opc.u.hookKillReason = nil
 	if opc.u.observer != nil {
 		notifyHook(hook, ctx, opc.u.observer.HookFailed)
 	}
 }
 
+// NotifyHookKilled is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) NotifyHookKilled(hook string, ctx runner.Context, reason error) {
+	opc.NotifyHookFailed(hook, ctx)
+	opc.u.hookKillReason = reason
+}
+
 // FailAction is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) FailAction(actionId, message string) error {
 	if !names.IsValidAction(actionId) {
@@ -122,3 +134,23 @@ func (opc *operationCallbacks) SetCurrentCharm(charmURL *corecharm.URL) error {
 func (opc *operationCallbacks) SetExecutingStatus(message string) error {
 	return setAgentStatus(opc.u, status.Executing, message, nil)
 }
+
+// InstallSystemPackages is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) InstallSystemPackages() error {
+	pkgs, err := packages.ReadSystemPackages(opc.u.paths.State.CharmDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(pkgs) == 0 {
+		return nil
+	}
+	modelConfig, err := opc.u.st.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	hostSeries, err := series.HostSeries()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return packages.Install(hostSeries, modelConfig.ProxySettings(), pkgs)
+}
@@ -72,6 +72,19 @@ func (f *factory) NewResolvedUpgrade(charmURL *corecharm.URL) (Operation, error)
 	return f.newDeploy(Upgrade, charmURL, false, true)
 }
 
+// NewRepairCharm is part of the Factory interface.
+func (f *factory) NewRepairCharm(charmURL *corecharm.URL) (Operation, error) {
+	if charmURL == nil {
+		return nil, errors.New("charm url required")
+	}
+	return &repairCharm{
+		charmURL:  charmURL,
+		callbacks: f.config.Callbacks,
+		deployer:  f.config.Deployer,
+		abort:     f.config.Abort,
+	}, nil
+}
+
 // NewRunHook is part of the Factory interface.
 func (f *factory) NewRunHook(hookInfo hook.Info) (Operation, error) {
 	if err := hookInfo.Validate(); err != nil {
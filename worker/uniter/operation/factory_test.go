@@ -81,6 +81,14 @@ func (s *FactorySuite) TestNewResolvedUpgradeString(c *gc.C) {
 	)
 }
 
+func (s *FactorySuite) TestNewRepairCharmError(c *gc.C) {
+	s.testNewDeployError(c, (operation.Factory).NewRepairCharm)
+}
+
+func (s *FactorySuite) TestNewRepairCharmString(c *gc.C) {
+	s.testNewDeployString(c, (operation.Factory).NewRepairCharm, "repair charm")
+}
+
 func (s *FactorySuite) TestNewActionError(c *gc.C) {
 	op, err := s.factory.NewAction("lol-something")
 	c.Check(op, gc.IsNil)
@@ -199,6 +207,16 @@ func (s *FactorySuite) TestNewHookString_Skip(c *gc.C) {
 	c.Check(op.String(), gc.Equals, "skip run relation-joined (123; foo/22) hook")
 }
 
+func (s *FactorySuite) TestNewHookString_ApplicationHook(c *gc.C) {
+	op, err := s.factory.NewRunHook(hook.Info{
+		Kind:                hook.ApplicationHook,
+		ApplicationHookName: "migrate",
+		CharmURL:            "cs:quantal/wordpress-1",
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(op.String(), gc.Equals, "run application-hook (migrate) hook")
+}
+
 func (s *FactorySuite) TestNewAcceptLeadershipString(c *gc.C) {
 	op, err := s.factory.NewAcceptLeadership()
 	c.Assert(err, jc.ErrorIsNil)
@@ -80,6 +80,11 @@ type Factory interface {
 	// non-overlapping remnants of a previously failed upgrade to the same charm.
 	NewResolvedUpgrade(charmURL *corecharm.URL) (Operation, error)
 
+	// NewRepairCharm creates an operation to redeploy the supplied charm,
+	// which must be the one currently deployed, restoring any of its files
+	// that have gone missing or been changed.
+	NewRepairCharm(charmURL *corecharm.URL) (Operation, error)
+
 	// NewRunHook creates an operation to execute the supplied hook.
 	NewRunHook(hookInfo hook.Info) (Operation, error)
 
@@ -141,6 +146,16 @@ type Callbacks interface {
 	NotifyHookCompleted(string, runner.Context)
 	NotifyHookFailed(string, runner.Context)
 
+	// NotifyHookKilled is called in place of NotifyHookFailed when the
+	// hook failed because it was killed, for the given reason, before
+	// it completed; the reason is reported in the unit's agent status.
+	NotifyHookKilled(string, runner.Context, error)
+
+	// InstallSystemPackages installs the operating system packages declared
+	// by the current charm. It's only used by RunHook operations, before the
+	// install hook runs.
+	InstallSystemPackages() error
+
 	// The following methods exist primarily to allow us to test operation code
 	// without using a live api connection.
 
@@ -0,0 +1,61 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	corecharm "gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/worker/uniter/charm"
+)
+
+// repairCharm redeploys the current charm over a charm directory whose
+// files have gone missing or been changed, without otherwise affecting
+// the uniter's state.
+type repairCharm struct {
+	DoesNotRequireMachineLock
+
+	charmURL *corecharm.URL
+
+	callbacks Callbacks
+	deployer  charm.Deployer
+	abort     <-chan struct{}
+}
+
+// String is part of the Operation interface.
+func (rc *repairCharm) String() string {
+	return fmt.Sprintf("repair charm %s", rc.charmURL)
+}
+
+// Prepare stages the current charm for redeployment.
+// Prepare is part of the Operation interface.
+func (rc *repairCharm) Prepare(state State) (*State, error) {
+	if err := rc.callbacks.SetExecutingStatus("repairing charm directory"); err != nil {
+		return nil, errors.Trace(err)
+	}
+	info, err := rc.callbacks.GetArchiveInfo(rc.charmURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := rc.deployer.Stage(info, rc.abort); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return nil, nil
+}
+
+// Execute redeploys the staged charm.
+// Execute is part of the Operation interface.
+func (rc *repairCharm) Execute(state State) (*State, error) {
+	if err := rc.deployer.Deploy(); err != nil {
+		return nil, errors.Annotate(err, "cannot repair charm directory")
+	}
+	return nil, nil
+}
+
+// Commit is part of the Operation interface.
+func (rc *repairCharm) Commit(state State) (*State, error) {
+	return nil, nil
+}
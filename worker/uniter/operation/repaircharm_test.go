@@ -0,0 +1,98 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/operation"
+)
+
+type RepairCharmSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&RepairCharmSuite{})
+
+type RepairCharmCallbacks struct {
+	*DeployCallbacks
+	executingMessage string
+}
+
+func (cb *RepairCharmCallbacks) SetExecutingStatus(message string) error {
+	cb.executingMessage = message
+	return nil
+}
+
+func (s *RepairCharmSuite) newOp(c *gc.C, callbacks operation.Callbacks, deployer *MockDeployer) operation.Operation {
+	factory := operation.NewFactory(operation.FactoryParams{
+		Deployer:  deployer,
+		Callbacks: callbacks,
+	})
+	op, err := factory.NewRepairCharm(curl("cs:quantal/hive-23"))
+	c.Assert(err, jc.ErrorIsNil)
+	return op
+}
+
+func (s *RepairCharmSuite) TestPrepareArchiveInfoError(c *gc.C) {
+	callbacks := &RepairCharmCallbacks{DeployCallbacks: &DeployCallbacks{
+		MockGetArchiveInfo: &MockGetArchiveInfo{err: errors.New("pew")},
+	}}
+	op := s.newOp(c, callbacks, &MockDeployer{})
+
+	newState, err := op.Prepare(operation.State{})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "pew")
+	c.Check(callbacks.MockGetArchiveInfo.gotCharmURL, gc.DeepEquals, curl("cs:quantal/hive-23"))
+}
+
+func (s *RepairCharmSuite) TestPrepareStageError(c *gc.C) {
+	callbacks := &RepairCharmCallbacks{DeployCallbacks: NewDeployCallbacks()}
+	deployer := &MockDeployer{MockStage: &MockStage{err: errors.New("squish")}}
+	op := s.newOp(c, callbacks, deployer)
+
+	newState, err := op.Prepare(operation.State{})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "squish")
+	c.Check(*deployer.MockStage.gotInfo, gc.Equals, callbacks.MockGetArchiveInfo.info)
+}
+
+func (s *RepairCharmSuite) TestRunSuccess(c *gc.C) {
+	callbacks := &RepairCharmCallbacks{DeployCallbacks: NewDeployCallbacks()}
+	deployer := &MockDeployer{
+		MockStage:  &MockStage{},
+		MockDeploy: &MockNoArgs{},
+	}
+	op := s.newOp(c, callbacks, deployer)
+	state := operation.State{Kind: operation.Continue, Step: operation.Pending}
+
+	newState, err := op.Prepare(state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(newState, gc.IsNil)
+	c.Check(callbacks.executingMessage, gc.Equals, "repairing charm directory")
+	c.Check(*deployer.MockStage.gotInfo, gc.Equals, callbacks.MockGetArchiveInfo.info)
+	// The current charm is not changed.
+	c.Check(callbacks.MockSetCurrentCharm.gotCharmURL, gc.IsNil)
+
+	newState, err = op.Execute(state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(newState, gc.IsNil)
+	c.Check(deployer.MockDeploy.called, jc.IsTrue)
+
+	newState, err = op.Commit(state)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(newState, gc.IsNil)
+}
+
+func (s *RepairCharmSuite) TestExecuteError(c *gc.C) {
+	deployer := &MockDeployer{MockDeploy: &MockNoArgs{err: errors.New("rasp")}}
+	op := s.newOp(c, &RepairCharmCallbacks{DeployCallbacks: NewDeployCallbacks()}, deployer)
+
+	newState, err := op.Execute(operation.State{})
+	c.Check(newState, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "cannot repair charm directory: rasp")
+}
@@ -40,6 +40,8 @@ func (rh *runHook) String() string {
 		}
 	case rh.info.Kind.IsStorage():
 		suffix = fmt.Sprintf(" (%s)", rh.info.StorageId)
+	case rh.info.Kind == hook.ApplicationHook:
+		suffix = fmt.Sprintf(" (%s)", rh.info.ApplicationHookName)
 	}
 	return fmt.Sprintf("run %s%s hook", rh.info.Kind, suffix)
 }
@@ -81,6 +83,16 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	if err := rh.beforeHook(state); err != nil {
 		return nil, err
 	}
+	if rh.info.Kind == hooks.Install {
+		// Packages declared by the charm must be in place before its
+		// install hook runs; failing to install them fails the hook so
+		// that it can be retried once the problem is resolved.
+		if err := rh.callbacks.InstallSystemPackages(); err != nil {
+			logger.Errorf("cannot install system packages for %q hook: %v", rh.name, err)
+			rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
+			return nil, ErrHookFailed
+		}
+	}
 	if err := rh.callbacks.SetExecutingStatus(message); err != nil {
 		return nil, err
 	}
@@ -103,6 +115,14 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	case cause == context.ErrReboot:
 		err = ErrNeedsReboot
 	case err == nil:
+	case context.IsHookKilledError(cause):
+		// The hook ran for longer than the model's hook timeout
+		// allows, or lost the leadership it ran under. It has
+		// failed, and is retried like any other failed hook, but
+		// the reason it was killed is reported in its status.
+		logger.Errorf("hook %q killed: %v", rh.name, err)
+		rh.callbacks.NotifyHookKilled(rh.name, rh.runner.Context(), context.HookKilledReason(cause))
+		return nil, ErrHookFailed
 	default:
 		logger.Errorf("hook %q failed: %v", rh.name, err)
 		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
@@ -181,9 +181,11 @@ func (s *RunHookSuite) TestPrepareSuccess_Preserve(c *gc.C) {
 func (s *RunHookSuite) getExecuteRunnerTest(c *gc.C, newHook newHook, kind hooks.Kind, runErr error) (operation.Operation, *ExecuteHookCallbacks, *MockRunnerFactory) {
 	runnerFactory := NewRunHookRunnerFactory(runErr)
 	callbacks := &ExecuteHookCallbacks{
-		PrepareHookCallbacks:    NewPrepareHookCallbacks(),
-		MockNotifyHookCompleted: &MockNotify{},
-		MockNotifyHookFailed:    &MockNotify{},
+		PrepareHookCallbacks:      NewPrepareHookCallbacks(),
+		MockNotifyHookCompleted:   &MockNotify{},
+		MockNotifyHookFailed:      &MockNotify{},
+		MockNotifyHookKilled:      &MockNotify{},
+		MockInstallSystemPackages: &MockNoArgs{},
 	}
 	factory := operation.NewFactory(operation.FactoryParams{
 		RunnerFactory: runnerFactory,
@@ -272,6 +274,22 @@ func (s *RunHookSuite) TestExecuteOtherError(c *gc.C) {
 	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
 }
 
+func (s *RunHookSuite) TestExecuteHookKilledError(c *gc.C) {
+	runErr := context.NewHookKilledError(errors.New("timed out after 1m0s"))
+	op, callbacks, runnerFactory := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.ConfigChanged, runErr)
+	_, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(operation.State{})
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, gc.IsNil)
+	c.Assert(*callbacks.MockNotifyHookKilled.gotName, gc.Equals, "some-hook-name")
+	c.Assert(*callbacks.MockNotifyHookKilled.gotContext, gc.Equals, runnerFactory.MockNewHookRunner.runner.context)
+	c.Assert(callbacks.gotKillReason, gc.ErrorMatches, "timed out after 1m0s")
+	c.Assert(callbacks.MockNotifyHookFailed.gotName, gc.IsNil)
+	c.Assert(callbacks.MockNotifyHookCompleted.gotName, gc.IsNil)
+}
+
 func (s *RunHookSuite) TestInstallHookPreservesStatus(c *gc.C) {
 	op, callbacks, f := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.Install, nil)
 	err := f.MockNewHookRunner.runner.Context().SetUnitStatus(jujuc.StatusInfo{Status: "blocked", Info: "no database"})
@@ -310,6 +328,40 @@ func (s *RunHookSuite) TestInstallHookWHenNoStatusSet(c *gc.C) {
 	c.Assert(status.Info, gc.Equals, "installing charm software")
 }
 
+func (s *RunHookSuite) TestInstallHookInstallsSystemPackages(c *gc.C) {
+	op, callbacks, f := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.Install, nil)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = op.Execute(*midState)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callbacks.MockInstallSystemPackages.called, jc.IsTrue)
+	c.Assert(*f.MockNewHookRunner.runner.MockRunHook.gotName, gc.Equals, "some-hook-name")
+}
+
+func (s *RunHookSuite) TestInstallHookSystemPackagesError(c *gc.C) {
+	op, callbacks, f := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.Install, nil)
+	callbacks.MockInstallSystemPackages.err = errors.New("no network")
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newState, err := op.Execute(*midState)
+	c.Assert(err, gc.Equals, operation.ErrHookFailed)
+	c.Assert(newState, gc.IsNil)
+	c.Assert(*callbacks.MockNotifyHookFailed.gotName, gc.Equals, "some-hook-name")
+	c.Assert(f.MockNewHookRunner.runner.MockRunHook.gotName, gc.IsNil)
+}
+
+func (s *RunHookSuite) TestOtherHooksDoNotInstallSystemPackages(c *gc.C) {
+	op, callbacks, _ := s.getExecuteRunnerTest(c, (operation.Factory).NewRunHook, hooks.Start, nil)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = op.Execute(*midState)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callbacks.MockInstallSystemPackages.called, jc.IsFalse)
+}
+
 func (s *RunHookSuite) testExecuteSuccess(
 	c *gc.C, before, after operation.State, setStatusCalled bool,
 ) {
@@ -175,8 +175,15 @@ func (mock *MockNotify) Call(hookName string, ctx runner.Context) {
 
 type ExecuteHookCallbacks struct {
 	*PrepareHookCallbacks
-	MockNotifyHookCompleted *MockNotify
-	MockNotifyHookFailed    *MockNotify
+	MockNotifyHookCompleted   *MockNotify
+	MockNotifyHookFailed      *MockNotify
+	MockNotifyHookKilled      *MockNotify
+	MockInstallSystemPackages *MockNoArgs
+	gotKillReason             error
+}
+
+func (cb *ExecuteHookCallbacks) InstallSystemPackages() error {
+	return cb.MockInstallSystemPackages.Call()
 }
 
 func (cb *ExecuteHookCallbacks) NotifyHookCompleted(hookName string, ctx runner.Context) {
@@ -187,6 +194,11 @@ func (cb *ExecuteHookCallbacks) NotifyHookFailed(hookName string, ctx runner.Con
 	cb.MockNotifyHookFailed.Call(hookName, ctx)
 }
 
+func (cb *ExecuteHookCallbacks) NotifyHookKilled(hookName string, ctx runner.Context, reason error) {
+	cb.MockNotifyHookKilled.Call(hookName, ctx)
+	cb.gotKillReason = reason
+}
+
 type MockCommitHook struct {
 	gotHook *hook.Info
 	err     error
@@ -0,0 +1,16 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package packages
+
+import (
+	"github.com/juju/utils/packaging/manager"
+)
+
+func PatchNewPackageManager(patcher interface {
+	PatchValue(interface{}, interface{})
+}, pacman manager.PackageManager, err error) {
+	patcher.PatchValue(&newPackageManager, func(string) (manager.PackageManager, error) {
+		return pacman, err
+	})
+}
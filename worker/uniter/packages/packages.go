@@ -0,0 +1,78 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package packages installs the operating system packages that a charm
+// declares in the system-packages section of its metadata, so that charms
+// do not need to install them from their own install hooks.
+package packages
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/packaging/manager"
+	"github.com/juju/utils/proxy"
+	goyaml "gopkg.in/yaml.v2"
+)
+
+var logger = loggo.GetLogger("juju.worker.uniter.packages")
+
+// metadata holds the parts of a charm's metadata.yaml that the charm
+// package does not know about.
+type metadata struct {
+	SystemPackages []string `yaml:"system-packages"`
+}
+
+// ReadSystemPackages returns the system packages declared by the charm
+// deployed in the supplied directory. A charm without a system-packages
+// section requires no packages.
+func ReadSystemPackages(charmDir string) ([]string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(charmDir, "metadata.yaml"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var meta metadata
+	if err := goyaml.Unmarshal(data, &meta); err != nil {
+		return nil, errors.Annotate(err, "cannot parse charm metadata")
+	}
+	for _, pkg := range meta.SystemPackages {
+		if pkg == "" {
+			return nil, errors.NotValidf("empty system package name")
+		}
+	}
+	return meta.SystemPackages, nil
+}
+
+// Install installs the supplied packages with the native package manager
+// for the given series (apt, yum or zypper), fetching them through the
+// supplied proxies.
+func Install(series string, proxies proxy.Settings, pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	pacman, err := newPackageManager(series)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get package manager for series %q", series)
+	}
+	return errors.Trace(install(pacman, proxies, pkgs))
+}
+
+var newPackageManager = manager.NewPackageManager
+
+func install(pacman manager.PackageManager, proxies proxy.Settings, pkgs []string) error {
+	if proxies != (proxy.Settings{}) {
+		if err := pacman.SetProxy(proxies); err != nil {
+			return errors.Annotate(err, "cannot set package manager proxy")
+		}
+	}
+	logger.Infof("installing system packages %v", pkgs)
+	if err := pacman.Install(pkgs...); err != nil {
+		return errors.Annotate(err, "cannot install system packages")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package packages_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/packaging/manager"
+	"github.com/juju/utils/proxy"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/packages"
+)
+
+type PackagesSuite struct {
+	testing.IsolationSuite
+	pacman *mockPackageManager
+}
+
+var _ = gc.Suite(&PackagesSuite{})
+
+func (s *PackagesSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.pacman = &mockPackageManager{}
+	packages.PatchNewPackageManager(s, s.pacman, nil)
+}
+
+func (s *PackagesSuite) writeMetadata(c *gc.C, content string) string {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "metadata.yaml"), []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return dir
+}
+
+func (s *PackagesSuite) TestReadSystemPackages(c *gc.C) {
+	dir := s.writeMetadata(c, `
+name: wordpress
+summary: blog
+system-packages:
+  - php-fpm
+  - nginx
+`)
+	pkgs, err := packages.ReadSystemPackages(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pkgs, jc.DeepEquals, []string{"php-fpm", "nginx"})
+}
+
+func (s *PackagesSuite) TestReadSystemPackagesNoneDeclared(c *gc.C) {
+	dir := s.writeMetadata(c, "name: wordpress\nsummary: blog\n")
+	pkgs, err := packages.ReadSystemPackages(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pkgs, gc.HasLen, 0)
+}
+
+func (s *PackagesSuite) TestReadSystemPackagesNoMetadata(c *gc.C) {
+	pkgs, err := packages.ReadSystemPackages(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pkgs, gc.HasLen, 0)
+}
+
+func (s *PackagesSuite) TestReadSystemPackagesEmptyName(c *gc.C) {
+	dir := s.writeMetadata(c, "name: wordpress\nsystem-packages: [nginx, '']\n")
+	_, err := packages.ReadSystemPackages(dir)
+	c.Assert(err, gc.ErrorMatches, "empty system package name not valid")
+}
+
+func (s *PackagesSuite) TestInstall(c *gc.C) {
+	err := packages.Install("xenial", proxy.Settings{}, []string{"nginx", "php-fpm"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.pacman.CheckCalls(c, []testing.StubCall{
+		{"Install", []interface{}{[]string{"nginx", "php-fpm"}}},
+	})
+}
+
+func (s *PackagesSuite) TestInstallWithProxy(c *gc.C) {
+	proxies := proxy.Settings{Http: "http://squid.internal:3128"}
+	err := packages.Install("centos7", proxies, []string{"httpd"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.pacman.CheckCalls(c, []testing.StubCall{
+		{"SetProxy", []interface{}{proxies}},
+		{"Install", []interface{}{[]string{"httpd"}}},
+	})
+}
+
+func (s *PackagesSuite) TestInstallNothing(c *gc.C) {
+	err := packages.Install("xenial", proxy.Settings{}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.pacman.CheckNoCalls(c)
+}
+
+func (s *PackagesSuite) TestInstallError(c *gc.C) {
+	s.pacman.SetErrors(errors.New("E: Unable to locate package nginx"))
+	err := packages.Install("xenial", proxy.Settings{}, []string{"nginx"})
+	c.Assert(err, gc.ErrorMatches, "cannot install system packages: E: Unable to locate package nginx")
+}
+
+func (s *PackagesSuite) TestInstallUnknownSeries(c *gc.C) {
+	packages.PatchNewPackageManager(s, nil, errors.New("unknown series"))
+	err := packages.Install("beos", proxy.Settings{}, []string{"nginx"})
+	c.Assert(err, gc.ErrorMatches, `cannot get package manager for series "beos": unknown series`)
+}
+
+type mockPackageManager struct {
+	manager.PackageManager
+	testing.Stub
+}
+
+func (m *mockPackageManager) SetProxy(settings proxy.Settings) error {
+	m.MethodCall(m, "SetProxy", settings)
+	return m.NextErr()
+}
+
+func (m *mockPackageManager) Install(pkgs ...string) error {
+	m.MethodCall(m, "Install", pkgs)
+	return m.NextErr()
+}
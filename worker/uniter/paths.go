@@ -103,6 +103,10 @@ type StatePaths struct {
 	// MetricsSpoolDir acts as temporary storage for metrics being sent from
 	// the uniter to state.
 	MetricsSpoolDir string
+
+	// HookToolAuditFile records every hook tool invocation made by the
+	// unit's charm.
+	HookToolAuditFile string
 }
 
 // NewPaths returns the set of filesystem paths that the supplied unit should
@@ -145,14 +149,15 @@ func NewWorkerPaths(dataDir string, unitTag names.UnitTag, worker string) Paths
 			JujucServerSocket: socket("agent", true),
 		},
 		State: StatePaths{
-			BaseDir:         baseDir,
-			CharmDir:        join(baseDir, "charm"),
-			OperationsFile:  join(stateDir, "uniter"),
-			RelationsDir:    join(stateDir, "relations"),
-			BundlesDir:      join(stateDir, "bundles"),
-			DeployerDir:     join(stateDir, "deployer"),
-			StorageDir:      join(stateDir, "storage"),
-			MetricsSpoolDir: join(stateDir, "spool", "metrics"),
+			BaseDir:           baseDir,
+			CharmDir:          join(baseDir, "charm"),
+			OperationsFile:    join(stateDir, "uniter"),
+			RelationsDir:      join(stateDir, "relations"),
+			BundlesDir:        join(stateDir, "bundles"),
+			DeployerDir:       join(stateDir, "deployer"),
+			StorageDir:        join(stateDir, "storage"),
+			MetricsSpoolDir:   join(stateDir, "spool", "metrics"),
+			HookToolAuditFile: join(stateDir, "hook-tool-audit.log"),
 		},
 	}
 }
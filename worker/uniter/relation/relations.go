@@ -202,6 +202,11 @@ func (r *relations) NextHook(
 			continue
 		}
 		var remoteBroken bool
+		var departingUnit string
+		if remoteState.Life == params.Dying {
+			// The local unit is leaving all of its relations.
+			departingUnit = r.unit.Name()
+		}
 		if remoteState.Life == params.Dying || relationSnapshot.Life == params.Dying {
 			relationSnapshot = remotestate.RelationSnapshot{}
 			remoteBroken = true
@@ -209,7 +214,7 @@ func (r *relations) NextHook(
 		}
 		// If either the unit or the relation are Dying,
 		// then the relation should be broken.
-		hook, err := nextRelationHook(relationer.dir.State(), relationSnapshot, remoteBroken)
+		hook, err := nextRelationHook(relationer.dir.State(), relationSnapshot, remoteBroken, departingUnit)
 		if err == resolver.ErrNoOperation {
 			continue
 		}
@@ -221,11 +226,14 @@ func (r *relations) NextHook(
 // nextRelationHook returns the next hook op that should be executed in the
 // relation characterised by the supplied local and remote state; or an error
 // if the states do not refer to the same relation; or ErrRelationUpToDate if
-// no hooks need to be executed.
+// no hooks need to be executed. If the local unit is leaving the relation,
+// localDeparting holds its name; otherwise departed hooks report the remote
+// unit as departing.
 func nextRelationHook(
 	local *State,
 	remote remotestate.RelationSnapshot,
 	remoteBroken bool,
+	localDeparting string,
 ) (hook.Info, error) {
 
 	// If there's a guaranteed next hook, return that.
@@ -259,11 +267,16 @@ func nextRelationHook(
 			continue
 		}
 		if _, found := remote.Members[unitName]; !found {
+			departingUnit := localDeparting
+			if departingUnit == "" {
+				departingUnit = unitName
+			}
 			return hook.Info{
 				Kind:          hooks.RelationDeparted,
 				RelationId:    relationId,
 				RemoteUnit:    unitName,
 				ChangeVersion: changeVersion,
+				DepartingUnit: departingUnit,
 			}, nil
 		}
 	}
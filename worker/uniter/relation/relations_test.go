@@ -378,6 +378,7 @@ func (s *relationsSuite) assertHookRelationDeparted(c *gc.C, numCalls *int32, ap
 	c.Assert(err, jc.ErrorIsNil)
 	assertNumCalls(c, numCalls, numCallsBefore)
 	c.Assert(op.String(), gc.Equals, "run hook relation-departed on unit with relation 1")
+	c.Assert(op.(*mockOperation).hookInfo.DepartingUnit, gc.Equals, "wordpress")
 
 	// Commit the operation so we save local state for any next operation.
 	_, err = r.PrepareHook(op.(*mockOperation).hookInfo)
@@ -394,6 +395,40 @@ func (s *relationsSuite) TestHookRelationDeparted(c *gc.C) {
 	s.assertHookRelationDeparted(c, &numCalls, apiCalls...)
 }
 
+func (s *relationsSuite) TestHookRelationDepartedUnitDying(c *gc.C) {
+	var numCalls int32
+	apiCalls := relationJoinedAPICalls()
+	r := s.assertHookRelationJoined(c, &numCalls, apiCalls...)
+	s.assertHookRelationChanged(c, r, remotestate.RelationSnapshot{
+		Life: params.Alive,
+	}, &numCalls)
+	numCallsBefore := numCalls
+
+	localState := resolver.LocalState{
+		State: operation.State{
+			Kind: operation.Continue,
+		},
+	}
+	remoteState := remotestate.Snapshot{
+		Life: params.Dying,
+		Relations: map[int]remotestate.RelationSnapshot{
+			1: remotestate.RelationSnapshot{
+				Life: params.Alive,
+				Members: map[string]int64{
+					"wordpress": 1,
+				},
+			},
+		},
+	}
+	relationsResolver := relation.NewRelationsResolver(r)
+	op, err := relationsResolver.NextOp(localState, remoteState, &mockOperations{})
+	c.Assert(err, jc.ErrorIsNil)
+	assertNumCalls(c, &numCalls, numCallsBefore)
+	c.Assert(op.String(), gc.Equals, "run hook relation-departed on unit with relation 1")
+	c.Assert(op.(*mockOperation).hookInfo.RemoteUnit, gc.Equals, "wordpress")
+	c.Assert(op.(*mockOperation).hookInfo.DepartingUnit, gc.Equals, "wordpress/0")
+}
+
 func (s *relationsSuite) TestHookRelationBroken(c *gc.C) {
 	var numCalls int32
 	apiCalls := relationJoinedAPICalls()
@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/uniter/remotestate"
 )
@@ -103,6 +104,8 @@ type mockState struct {
 	storageAttachment         map[params.StorageAttachmentId]params.StorageAttachment
 	relationUnitsWatchers     map[names.RelationTag]*mockRelationUnitsWatcher
 	storageAttachmentWatchers map[names.StorageTag]*mockNotifyWatcher
+	maintenanceWindows        config.MaintenanceWindows
+	modelConfigWatcher        *mockNotifyWatcher
 }
 
 func (st *mockState) Relation(tag names.RelationTag) (remotestate.Relation, error) {
@@ -186,10 +189,19 @@ func (st *mockState) UpdateStatusHookInterval() (time.Duration, error) {
 	return 5 * time.Minute, nil
 }
 
+func (st *mockState) MaintenanceWindows() (config.MaintenanceWindows, error) {
+	return st.maintenanceWindows, nil
+}
+
+func (st *mockState) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	return st.modelConfigWatcher, nil
+}
+
 type mockUnit struct {
 	tag                   names.UnitTag
 	life                  params.Life
 	resolved              params.ResolvedMode
+	suspended             bool
 	service               mockService
 	unitWatcher           *mockNotifyWatcher
 	addressesWatcher      *mockNotifyWatcher
@@ -211,6 +223,10 @@ func (u *mockUnit) Resolved() (params.ResolvedMode, error) {
 	return u.resolved, nil
 }
 
+func (u *mockUnit) Suspended() (bool, error) {
+	return u.suspended, nil
+}
+
 func (u *mockUnit) Application() (remotestate.Application, error) {
 	return &u.service, nil
 }
@@ -40,6 +40,10 @@ type Snapshot struct {
 	// hook execution errors.
 	ResolvedMode params.ResolvedMode
 
+	// Suspended reports whether an operator has suspended
+	// the unit, in which case no hooks should be run.
+	Suspended bool
+
 	// RetryHookVersion increments each time a failed
 	// hook is meant to be retried if ResolvedMode is
 	// set to ResolvedNone.
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/watcher"
 )
 
@@ -28,12 +29,15 @@ type State interface {
 	WatchRelationUnits(names.RelationTag, names.UnitTag) (watcher.RelationUnitsWatcher, error)
 	WatchStorageAttachment(names.StorageTag, names.UnitTag) (watcher.NotifyWatcher, error)
 	UpdateStatusHookInterval() (time.Duration, error)
+	MaintenanceWindows() (config.MaintenanceWindows, error)
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
 }
 
 type Unit interface {
 	Life() params.Life
 	Refresh() error
 	Resolved() (params.ResolvedMode, error)
+	Suspended() (bool, error)
 	Application() (Application, error)
 	Tag() names.UnitTag
 	Watch() (watcher.NotifyWatcher, error)
@@ -5,9 +5,11 @@ package remotestate
 
 import (
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/juju/worker.v1"
 
@@ -20,6 +22,10 @@ import (
 
 var logger = loggo.GetLogger("juju.worker.uniter.remotestate")
 
+// offPeakUpdateStatusInterval is how often the update-status hook is run
+// outside the model's maintenance windows, if any are configured.
+const offPeakUpdateStatusInterval = 60 * time.Minute
+
 // RemoteStateWatcher collects unit, service, and service config information
 // from separate state watchers, and updates a Snapshot which is sent on a
 // channel upon change.
@@ -35,6 +41,7 @@ type RemoteStateWatcher struct {
 	updateStatusChannel       UpdateStatusTimerFunc
 	commandChannel            <-chan string
 	retryHookChannel          <-chan struct{}
+	clock                     clock.Clock
 
 	catacomb catacomb.Catacomb
 
@@ -52,6 +59,7 @@ type WatcherConfig struct {
 	CommandChannel      <-chan string
 	RetryHookChannel    <-chan struct{}
 	UnitTag             names.UnitTag
+	Clock               clock.Clock
 }
 
 // NewWatcher returns a RemoteStateWatcher that handles state changes pertaining to the
@@ -67,6 +75,7 @@ func NewWatcher(config WatcherConfig) (*RemoteStateWatcher, error) {
 		updateStatusChannel:       config.UpdateStatusChannel,
 		commandChannel:            config.CommandChannel,
 		retryHookChannel:          config.RetryHookChannel,
+		clock:                     config.Clock,
 		// Note: it is important that the out channel be buffered!
 		// The remote state watcher will perform a non-blocking send
 		// on the channel to wake up the observer. It is non-blocking
@@ -304,8 +313,27 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// The maintenance windows are read again whenever the model
+	// config changes.
+	modelConfigw, err := w.st.WatchForModelConfigChanges()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := w.catacomb.Add(modelConfigw); err != nil {
+		return errors.Trace(err)
+	}
+	maintenanceWindows, err := w.st.MaintenanceWindows()
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	for {
+		// Outside the maintenance windows, update-status is run
+		// as infrequently as it may be configured to run.
+		interval := updateStatusInterval
+		if len(maintenanceWindows) > 0 && !maintenanceWindows.Allows(w.clock.Now()) {
+			interval = offPeakUpdateStatusInterval
+		}
 		select {
 		case <-w.catacomb.Dying():
 			return w.catacomb.ErrDying()
@@ -350,6 +378,16 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 			}
 			observedEvent(&seenAddressesChange)
 
+		case _, ok := <-modelConfigw.Changes():
+			logger.Debugf("got model config change: ok=%t", ok)
+			if !ok {
+				return errors.New("model config watcher closed")
+			}
+			maintenanceWindows, err = w.st.MaintenanceWindows()
+			if err != nil {
+				return errors.Trace(err)
+			}
+
 		case _, ok := <-leaderSettingsw.Changes():
 			logger.Debugf("got leader settings change: ok=%t", ok)
 			if !ok {
@@ -418,7 +456,7 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 				return errors.Trace(err)
 			}
 
-		case <-w.updateStatusChannel(updateStatusInterval).After():
+		case <-w.updateStatusChannel(interval).After():
 			logger.Debugf("update status timer triggered")
 			if err := w.updateStatusChanged(); err != nil {
 				return errors.Trace(err)
@@ -481,10 +519,15 @@ func (w *RemoteStateWatcher) unitChanged() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	suspended, err := w.unit.Suspended()
+	if err != nil {
+		return errors.Trace(err)
+	}
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.current.Life = w.unit.Life()
 	w.current.ResolvedMode = resolved
+	w.current.Suspended = suspended
 	return nil
 }
 
@@ -4,6 +4,7 @@
 package remotestate_test
 
 import (
+	"sync"
 	"time"
 
 	"github.com/juju/testing"
@@ -13,6 +14,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/uniter/remotestate"
@@ -25,6 +27,9 @@ type WatcherSuite struct {
 	leadership *mockLeadershipTracker
 	watcher    *remotestate.RemoteStateWatcher
 	clock      *testing.Clock
+
+	mu         sync.Mutex
+	statusWait time.Duration
 }
 
 // Duration is arbitrary, we'll trigger the ticker
@@ -58,6 +63,7 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 		storageAttachment:         make(map[params.StorageAttachmentId]params.StorageAttachment),
 		relationUnitsWatchers:     make(map[names.RelationTag]*mockRelationUnitsWatcher),
 		storageAttachmentWatchers: make(map[names.StorageTag]*mockNotifyWatcher),
+		modelConfigWatcher:        newMockNotifyWatcher(),
 	}
 
 	s.leadership = &mockLeadershipTracker{
@@ -67,7 +73,14 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 	}
 
 	s.clock = testing.NewClock(time.Now())
+	s.startWatcher(c)
+}
+
+func (s *WatcherSuite) startWatcher(c *gc.C) {
 	statusTicker := func(wait time.Duration) remotestate.Waiter {
+		s.mu.Lock()
+		s.statusWait = wait
+		s.mu.Unlock()
 		return dummyWaiter{s.clock.After(statusTickDuration)}
 	}
 
@@ -76,11 +89,18 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 		LeadershipTracker:   s.leadership,
 		UnitTag:             s.st.unit.tag,
 		UpdateStatusChannel: statusTicker,
+		Clock:               s.clock,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.watcher = w
 }
 
+func (s *WatcherSuite) lastStatusWait() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statusWait
+}
+
 type dummyWaiter struct {
 	c <-chan time.Time
 }
@@ -168,6 +188,11 @@ func (s *WatcherSuite) TestRemoteStateChanged(c *gc.C) {
 	assertOneChange()
 	c.Assert(s.watcher.Snapshot().Life, gc.Equals, params.Dying)
 
+	s.st.unit.suspended = true
+	s.st.unit.unitWatcher.changes <- struct{}{}
+	assertOneChange()
+	c.Assert(s.watcher.Snapshot().Suspended, jc.IsTrue)
+
 	s.st.unit.addressesWatcher.changes <- struct{}{}
 	assertOneChange()
 	c.Assert(s.watcher.Snapshot().ConfigVersion, gc.Equals, initial.ConfigVersion+1)
@@ -556,6 +581,50 @@ func (s *WatcherSuite) TestUpdateStatusTicker(c *gc.C) {
 	c.Assert(s.watcher.Snapshot().UpdateStatusVersion, gc.Equals, initial.UpdateStatusVersion+2)
 }
 
+func (s *WatcherSuite) restartWatcherWithMaintenanceWindows(c *gc.C, value string) {
+	s.watcher.Kill()
+	c.Assert(s.watcher.Wait(), jc.ErrorIsNil)
+
+	windows, err := config.ParseMaintenanceWindows(value)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.maintenanceWindows = windows
+	// 2017-10-02 is a Monday.
+	s.clock = testing.NewClock(time.Date(2017, 10, 2, 12, 0, 0, 0, time.UTC))
+	s.startWatcher(c)
+}
+
+func (s *WatcherSuite) TestUpdateStatusInsideMaintenanceWindow(c *gc.C) {
+	s.restartWatcherWithMaintenanceWindows(c, "Sat 02:00-04:00, 11:30-12:30")
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.lastStatusWait(), gc.Equals, 5*time.Minute)
+}
+
+func (s *WatcherSuite) TestUpdateStatusOutsideMaintenanceWindow(c *gc.C) {
+	s.restartWatcherWithMaintenanceWindows(c, "Sat 02:00-04:00")
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.lastStatusWait(), gc.Equals, 60*time.Minute)
+}
+
+func (s *WatcherSuite) TestUpdateStatusMaintenanceWindowsChanged(c *gc.C) {
+	s.restartWatcherWithMaintenanceWindows(c, "Sat 02:00-04:00")
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.lastStatusWait(), gc.Equals, 60*time.Minute)
+
+	windows, err := config.ParseMaintenanceWindows("11:30-12:30")
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.maintenanceWindows = windows
+	s.st.modelConfigWatcher.changes <- struct{}{}
+	for attempt := coretesting.LongAttempt.Start(); attempt.Next(); {
+		if s.lastStatusWait() == 5*time.Minute {
+			return
+		}
+	}
+	c.Fatalf("update-status interval not changed")
+}
+
 // waitAlarmsStable is used to wait until the remote watcher's loop has
 // stopped churning (at least for testing.ShortWait), so that we can
 // then Advance the clock with some confidence that the SUT really is
@@ -18,6 +18,7 @@ import (
 type ResolverConfig struct {
 	ClearResolved       func() error
 	ReportHookError     func(hook.Info) error
+	ReportSuspended     func() error
 	ShouldRetryHooks    bool
 	StartRetryHookTimer func()
 	StopRetryHookTimer  func()
@@ -26,11 +27,23 @@ type ResolverConfig struct {
 	Relations           resolver.Resolver
 	Storage             resolver.Resolver
 	Commands            resolver.Resolver
+	ApplicationHooks    resolver.Resolver
+
+	// VerifyCharmDir, if set, is called before running the first
+	// update-status hook after the uniter starts, and returns the paths
+	// of any files of the deployed charm that are missing or have
+	// changed. If there are any, the charm is redeployed before running
+	// the hook.
+	VerifyCharmDir func() ([]string, error)
 }
 
 type uniterResolver struct {
 	config                ResolverConfig
 	retryHookTimerStarted bool
+
+	// charmDirVerified records whether the charm directory has been
+	// verified since the uniter started.
+	charmDirVerified bool
 }
 
 // NewUniterResolver returns a new resolver.Resolver for the uniter.
@@ -50,6 +63,18 @@ func (s *uniterResolver) NextOp(
 		return nil, resolver.ErrTerminate
 	}
 
+	if remoteState.Suspended && remoteState.Life == params.Alive {
+		// The operator has suspended the unit. Any operation
+		// that was running has completed by now, so report that
+		// we're suspended and wait to be resumed; remote state
+		// changes accumulate in the snapshot until then. A dying
+		// unit ignores suspension so that it can be removed.
+		if err := s.config.ReportSuspended(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, resolver.ErrWaiting
+	}
+
 	if localState.Kind == operation.Upgrade {
 		if localState.Conflicted {
 			return s.nextOpConflicted(localState, remoteState, opFactory)
@@ -95,6 +120,12 @@ func (s *uniterResolver) NextOp(
 
 	switch localState.Kind {
 	case operation.RunHook:
+		if localState.Hook.Kind == hook.ApplicationHook && !localState.Leader && localState.Step != operation.Done {
+			// Leadership changed before the application hook
+			// completed; the new leader will run it.
+			logger.Infof("skipping %q application hook; no longer the leader", localState.Hook.ApplicationHookName)
+			return opFactory.NewSkipHook(*localState.Hook)
+		}
 		switch localState.Step {
 		case operation.Pending:
 			logger.Infof("awaiting error resolution for %q hook", localState.Hook.Kind)
@@ -264,15 +295,43 @@ func (s *uniterResolver) nextOp(
 		return opFactory.NewRunHook(hook.Info{Kind: hooks.ConfigChanged})
 	}
 
-	op, err := s.config.Relations.NextOp(localState, remoteState, opFactory)
+	op, err := s.config.ApplicationHooks.NextOp(localState, remoteState, opFactory)
+	if errors.Cause(err) != resolver.ErrNoOperation {
+		return op, err
+	}
+
+	op, err = s.config.Relations.NextOp(localState, remoteState, opFactory)
 	if errors.Cause(err) != resolver.ErrNoOperation {
 		return op, err
 	}
 
 	// UpdateStatus hook runs if nothing else needs to.
 	if localState.UpdateStatusVersion != remoteState.UpdateStatusVersion {
+		if s.charmDirDamaged() {
+			return opFactory.NewRepairCharm(localState.CharmURL)
+		}
 		return opFactory.NewRunHook(hook.Info{Kind: hooks.UpdateStatus})
 	}
 
 	return nil, resolver.ErrNoOperation
 }
+
+// charmDirDamaged reports whether the charm directory needs to be
+// repaired. Verifying means reading the whole charm, so it is done only
+// once after the uniter starts.
+func (s *uniterResolver) charmDirDamaged() bool {
+	if s.config.VerifyCharmDir == nil || s.charmDirVerified {
+		return false
+	}
+	s.charmDirVerified = true
+	damaged, err := s.config.VerifyCharmDir()
+	if err != nil {
+		logger.Warningf("cannot verify charm directory: %v", err)
+		return false
+	}
+	if len(damaged) == 0 {
+		return false
+	}
+	logger.Warningf("charm directory is damaged, redeploying charm; missing or changed: %v", damaged)
+	return true
+}
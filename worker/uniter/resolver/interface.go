@@ -90,6 +90,12 @@ type LocalState struct {
 	// been committed.
 	LeaderSettingsVersion int
 
+	// CompletedApplicationHooks records the application hooks committed
+	// by this unit, keyed on hook name, with the URL of the charm each
+	// was run for. It covers the time until their completion is
+	// observed in the leader settings.
+	CompletedApplicationHooks map[string]string
+
 	// CompletedActions is the set of actions that have been completed.
 	// This is used to prevent us re running actions requested by the
 	// controller.
@@ -37,6 +37,16 @@ func (s *resolverOpFactory) NewRunHook(info hook.Info) (operation.Operation, err
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if info.Kind == hook.ApplicationHook {
+		// Skipped application hooks are not recorded: they
+		// are left for the leader to run.
+		op = onCommitWrapper{op, func() {
+			if s.LocalState.CompletedApplicationHooks == nil {
+				s.LocalState.CompletedApplicationHooks = make(map[string]string)
+			}
+			s.LocalState.CompletedApplicationHooks[info.ApplicationHookName] = info.CharmURL
+		}}
+	}
 	return s.wrapHookOp(op, info), nil
 }
 
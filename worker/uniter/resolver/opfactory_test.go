@@ -126,6 +126,29 @@ func (s *ResolverOpFactorySuite) testLeaderSettingsChanged(
 	c.Assert(f.LocalState.UpdateStatusVersion, gc.Equals, 3)
 }
 
+func (s *ResolverOpFactorySuite) TestApplicationHookCompleted(c *gc.C) {
+	f := resolver.NewResolverOpFactory(s.opFactory)
+	info := hook.Info{
+		Kind:                hook.ApplicationHook,
+		ApplicationHookName: "migrate",
+		CharmURL:            "cs:wordpress-1",
+	}
+
+	op, err := f.NewSkipHook(info)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = op.Commit(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.LocalState.CompletedApplicationHooks, gc.HasLen, 0)
+
+	op, err = f.NewRunHook(info)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = op.Commit(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.LocalState.CompletedApplicationHooks, jc.DeepEquals, map[string]string{
+		"migrate": "cs:wordpress-1",
+	})
+}
+
 func (s *ResolverOpFactorySuite) TestUpgrade(c *gc.C) {
 	s.testUpgrade(c, resolver.ResolverOpFactory.NewUpgrade)
 	s.testUpgrade(c, resolver.ResolverOpFactory.NewRevertUpgrade)
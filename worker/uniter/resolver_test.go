@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter"
 	uniteractions "github.com/juju/juju/worker/uniter/actions"
+	"github.com/juju/juju/worker/uniter/applicationhooks"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/leadership"
 	"github.com/juju/juju/worker/uniter/operation"
@@ -35,6 +36,7 @@ type resolverSuite struct {
 
 	clearResolved   func() error
 	reportHookError func(hook.Info) error
+	reportSuspended func() error
 }
 
 var _ = gc.Suite(&resolverSuite{})
@@ -59,9 +61,14 @@ func (s *resolverSuite) SetUpTest(c *gc.C) {
 		return errors.New("unexpected report hook error")
 	}
 
+	s.reportSuspended = func() error {
+		return errors.New("unexpected report suspended")
+	}
+
 	s.resolverConfig = uniter.ResolverConfig{
 		ClearResolved:       func() error { return s.clearResolved() },
 		ReportHookError:     func(info hook.Info) error { return s.reportHookError(info) },
+		ReportSuspended:     func() error { return s.reportSuspended() },
 		StartRetryHookTimer: func() { s.stub.AddCall("StartRetryHookTimer") },
 		StopRetryHookTimer:  func() { s.stub.AddCall("StopRetryHookTimer") },
 		ShouldRetryHooks:    true,
@@ -70,6 +77,7 @@ func (s *resolverSuite) SetUpTest(c *gc.C) {
 		Relations:           relation.NewRelationsResolver(&dummyRelations{}),
 		Storage:             storage.NewResolver(attachments),
 		Commands:            nopResolver{},
+		ApplicationHooks:    nopResolver{},
 	}
 
 	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
@@ -109,6 +117,45 @@ func (s *resolverSuite) TestNotStartedNotInstalled(c *gc.C) {
 	c.Assert(op.String(), gc.Equals, "run install hook")
 }
 
+func (s *resolverSuite) TestSuspended(c *gc.C) {
+	s.reportSuspended = func() error {
+		s.stub.AddCall("ReportSuspended")
+		return nil
+	}
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind: operation.Continue,
+		},
+	}
+	s.remoteState.Life = params.Alive
+	s.remoteState.Suspended = true
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrWaiting)
+	s.stub.CheckCallNames(c, "ReportSuspended")
+
+	// Once resumed, the queued install hook runs.
+	s.remoteState.Suspended = false
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run install hook")
+}
+
+func (s *resolverSuite) TestSuspendedDyingUnitNotSuspended(c *gc.C) {
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind: operation.Continue,
+		},
+	}
+	s.remoteState.Life = params.Dying
+	s.remoteState.Suspended = true
+	_, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, gc.Equals, resolver.ErrTerminate)
+}
+
 func (s *resolverSuite) TestHookErrorDoesNotStartRetryTimerIfShouldRetryFalse(c *gc.C) {
 	s.resolverConfig.ShouldRetryHooks = false
 	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
@@ -252,3 +299,118 @@ func (s *resolverSuite) TestRunHookStopRetryTimer(c *gc.C) {
 	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
 	s.stub.CheckCallNames(c, "StartRetryHookTimer", "StopRetryHookTimer")
 }
+
+func (s *resolverSuite) TestUpdateStatusRepairsDamagedCharmDir(c *gc.C) {
+	damaged := []string{"hooks/install"}
+	s.resolverConfig.VerifyCharmDir = func() ([]string, error) {
+		s.stub.AddCall("VerifyCharmDir")
+		return damaged, s.stub.NextErr()
+	}
+	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+		},
+	}
+	s.remoteState.UpdateStatusVersion = 1
+
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "repair charm cs:precise/mysql-2")
+
+	// The charm directory is only verified once after starting.
+	op, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run update-status hook")
+	s.remoteState.UpdateStatusVersion = 2
+	op, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run update-status hook")
+	s.stub.CheckCallNames(c, "VerifyCharmDir")
+}
+
+func (s *resolverSuite) TestUpdateStatusVerifyCharmDirError(c *gc.C) {
+	s.resolverConfig.VerifyCharmDir = func() ([]string, error) {
+		return nil, errors.New("boom")
+	}
+	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+		},
+	}
+	s.remoteState.UpdateStatusVersion = 1
+
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run update-status hook")
+}
+
+func (s *resolverSuite) TestApplicationHookSkippedWhenNotLeader(c *gc.C) {
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind:      operation.RunHook,
+			Step:      operation.Queued,
+			Installed: true,
+			Started:   true,
+			Leader:    true,
+			Hook: &hook.Info{
+				Kind:                hook.ApplicationHook,
+				ApplicationHookName: "migrate",
+				CharmURL:            s.charmURL.String(),
+			},
+		},
+	}
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run application-hook (migrate) hook")
+
+	// Once leadership has changed, queued and failed application
+	// hooks are left for the new leader to run.
+	localState.Leader = false
+	for _, step := range []operation.Step{operation.Queued, operation.Pending} {
+		localState.Step = step
+		op, err = s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(op.String(), gc.Equals, "skip run application-hook (migrate) hook")
+	}
+}
+
+func (s *resolverSuite) TestApplicationHooksRunBeforeRelations(c *gc.C) {
+	s.resolverConfig.ApplicationHooks = applicationhooks.NewResolver(applicationhooks.Config{
+		Hooks: func() ([]string, error) {
+			return []string{"migrate"}, nil
+		},
+		CompletedHooks: func() (map[string]string, error) {
+			return nil, nil
+		},
+	})
+	s.resolver = uniter.NewUniterResolver(s.resolverConfig)
+	localState := resolver.LocalState{
+		CharmModifiedVersion: s.charmModifiedVersion,
+		CharmURL:             s.charmURL,
+		State: operation.State{
+			Kind:      operation.Continue,
+			Installed: true,
+			Started:   true,
+			Leader:    true,
+		},
+	}
+	s.remoteState.Life = params.Alive
+	s.remoteState.Leader = true
+	s.remoteState.UpdateStatusVersion = 1
+
+	op, err := s.resolver.NextOp(localState, s.remoteState, s.opFactory)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run application-hook (migrate) hook")
+}
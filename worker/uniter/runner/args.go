@@ -59,6 +59,20 @@ func searchHook(charmDir, hook string) (string, error) {
 	return "", context.NewMissingHookError(hook)
 }
 
+// searchDispatchOrHook returns the charm's dispatch executable if it
+// has one, and otherwise searches for the hook as searchHook does. It
+// also reports whether dispatch was found.
+func searchDispatchOrHook(charmDir, hook string) (string, bool, error) {
+	dispatch, err := searchHook(charmDir, dispatchName)
+	if err == nil {
+		return dispatch, true, nil
+	} else if !context.IsMissingHookError(err) {
+		return "", false, err
+	}
+	hookFile, err := searchHook(charmDir, hook)
+	return hookFile, false, err
+}
+
 // hookCommand constructs an appropriate command to be passed to
 // exec.Command(). The exec package uses cmd.exe as default on windows.
 // cmd.exe does not know how to execute ps1 files by default, and
@@ -0,0 +1,134 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// AuditRecord describes a single hook tool invocation made by a charm.
+type AuditRecord struct {
+	// Time is the time at which the invocation completed.
+	Time time.Time `json:"time"`
+
+	// Unit is the name of the unit whose charm invoked the tool.
+	Unit string `json:"unit"`
+
+	// Tool is the name of the hook tool.
+	Tool string `json:"tool"`
+
+	// Args holds the arguments passed to the hook tool, with the
+	// values of any secrets redacted.
+	Args []string `json:"args,omitempty"`
+
+	// RelationId is the id of the relation of the running hook,
+	// or -1 if the hook is not a relation hook.
+	RelationId int `json:"relation-id"`
+
+	// Code is the hook tool's exit code.
+	Code int `json:"code"`
+
+	// Duration is the time taken to run the hook tool.
+	Duration time.Duration `json:"duration"`
+}
+
+// Auditor records hook tool invocations.
+type Auditor interface {
+	// Audit records the supplied hook tool invocation.
+	Audit(AuditRecord) error
+}
+
+// NewFileAuditor returns a FileAuditor that appends records, one JSON
+// document per line, to the file at the given path, rotating it as it
+// grows. The file is created if necessary, and is readable only by its
+// owner since hook tool arguments may contain secrets.
+func NewFileAuditor(path string) (*FileAuditor, error) {
+	// Rotated files keep the mode of the file they replace.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot open hook tool audit log")
+	}
+	if err := f.Close(); err != nil {
+		return nil, errors.Annotate(err, "cannot open hook tool audit log")
+	}
+	return &FileAuditor{
+		logger: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    20, // megabytes
+			MaxBackups: 2,
+			Compress:   true,
+		},
+	}, nil
+}
+
+// FileAuditor is an Auditor that writes records to a log file.
+type FileAuditor struct {
+	logger *lumberjack.Logger
+}
+
+// Audit is part of the Auditor interface.
+func (a *FileAuditor) Audit(record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := a.logger.Write(append(data, '\n')); err != nil {
+		return errors.Annotate(err, "cannot write hook tool audit log")
+	}
+	return nil
+}
+
+// Close closes the log file.
+func (a *FileAuditor) Close() error {
+	return errors.Trace(a.logger.Close())
+}
+
+// NewLogAuditor returns an Auditor that writes records, as JSON, to the
+// supplied logger. When the logger's messages are forwarded to the
+// controller, as the unit agent's are, the records become visible to
+// operators via debug-log. Hook tool arguments, which may hold
+// passwords set with relation-set or leader-set, are left out.
+func NewLogAuditor(logger loggo.Logger) Auditor {
+	return logAuditor{logger}
+}
+
+type logAuditor struct {
+	logger loggo.Logger
+}
+
+// Audit is part of the Auditor interface.
+func (a logAuditor) Audit(record AuditRecord) error {
+	record.Args = nil
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	a.logger.Infof("%s", data)
+	return nil
+}
+
+// NewMultiAuditor returns an Auditor that passes each record to all of
+// the supplied auditors, returning the first error encountered.
+func NewMultiAuditor(auditors ...Auditor) Auditor {
+	return multiAuditor(auditors)
+}
+
+type multiAuditor []Auditor
+
+// Audit is part of the Auditor interface.
+func (a multiAuditor) Audit(record AuditRecord) error {
+	var firstErr error
+	for _, auditor := range a {
+		if err := auditor.Audit(record); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner"
+)
+
+type AuditSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+var auditRecord = runner.AuditRecord{
+	Time:       time.Date(2017, 10, 1, 12, 0, 0, 0, time.UTC),
+	Unit:       "u/0",
+	Tool:       "relation-set",
+	Args:       []string{"foo=bar"},
+	RelationId: 1,
+	Code:       0,
+	Duration:   time.Second,
+}
+
+func (s *AuditSuite) TestFileAuditor(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "audit.log")
+	auditor, err := runner.NewFileAuditor(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer auditor.Close()
+
+	second := auditRecord
+	second.Tool = "status-set"
+	second.Args = nil
+	second.RelationId = -1
+	second.Code = 1
+	c.Assert(auditor.Audit(auditRecord), jc.ErrorIsNil)
+	c.Assert(auditor.Audit(second), jc.ErrorIsNil)
+
+	info, err := os.Stat(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	f, err := os.Open(path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	var records []runner.AuditRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record runner.AuditRecord
+		c.Assert(json.Unmarshal(scanner.Bytes(), &record), jc.ErrorIsNil)
+		records = append(records, record)
+	}
+	c.Assert(scanner.Err(), jc.ErrorIsNil)
+	c.Assert(records, jc.DeepEquals, []runner.AuditRecord{auditRecord, second})
+}
+
+func (s *AuditSuite) TestFileAuditorError(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "missing", "audit.log")
+	_, err := runner.NewFileAuditor(path)
+	c.Assert(err, gc.ErrorMatches, "cannot open hook tool audit log: .*")
+}
+
+func (s *AuditSuite) TestFileAuditorKeepsMode(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "audit.log")
+	err := ioutil.WriteFile(path, []byte("existing\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	auditor, err := runner.NewFileAuditor(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(auditor.Audit(auditRecord), jc.ErrorIsNil)
+	c.Assert(auditor.Close(), jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.HasPrefix(string(data), "existing\n{"), jc.IsTrue)
+	info, err := os.Stat(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+}
+
+func (s *AuditSuite) TestLogAuditor(c *gc.C) {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("audit-test", &tw), jc.ErrorIsNil)
+	defer loggo.RemoveWriter("audit-test")
+	logger := loggo.GetLogger("test.audit")
+	defer logger.SetLogLevel(logger.LogLevel())
+	logger.SetLogLevel(loggo.INFO)
+
+	err := runner.NewLogAuditor(logger).Audit(auditRecord)
+	c.Assert(err, jc.ErrorIsNil)
+
+	log := tw.Log()
+	c.Assert(log, gc.HasLen, 1)
+	var record runner.AuditRecord
+	c.Assert(json.Unmarshal([]byte(log[0].Message), &record), jc.ErrorIsNil)
+	// Arguments are not forwarded, since they may hold passwords.
+	expect := auditRecord
+	expect.Args = nil
+	c.Assert(record, jc.DeepEquals, expect)
+}
+
+func (s *AuditSuite) TestMultiAuditor(c *gc.C) {
+	first := &recordingAuditor{err: errors.New("first")}
+	second := &recordingAuditor{err: errors.New("second")}
+	err := runner.NewMultiAuditor(first, second).Audit(auditRecord)
+	c.Assert(err, gc.ErrorMatches, "first")
+	c.Assert(first.records, jc.DeepEquals, []runner.AuditRecord{auditRecord})
+	c.Assert(second.records, jc.DeepEquals, []runner.AuditRecord{auditRecord})
+}
+
+type recordingAuditor struct {
+	records []runner.AuditRecord
+	err     error
+}
+
+func (a *recordingAuditor) Audit(record runner.AuditRecord) error {
+	a.records = append(a.records, record)
+	return a.err
+}
@@ -9,12 +9,14 @@ import (
 
 // ActionData contains the tag, parameters, and results of an Action.
 type ActionData struct {
-	Name           string
-	Tag            names.ActionTag
-	Params         map[string]interface{}
-	Failed         bool
-	ResultsMessage string
-	ResultsMap     map[string]interface{}
+	Name            string
+	Tag             names.ActionTag
+	Params          map[string]interface{}
+	Failed          bool
+	ResultsMessage  string
+	ResultsMap      map[string]interface{}
+	ProgressMessage string
+	ProgressPercent int
 }
 
 // NewActionData builds a suitable ActionData struct with no nil members.
@@ -4,7 +4,11 @@
 package context
 
 import (
+	"container/list"
 	"sort"
+	"time"
+
+	"github.com/juju/utils/clock"
 
 	"github.com/juju/juju/apiserver/params"
 )
@@ -15,25 +19,70 @@ type SettingsFunc func(unitName string) (params.Settings, error)
 // SettingsMap is a map from unit name to relation settings.
 type SettingsMap map[string]params.Settings
 
+// RelationCacheConfig holds the bounds of a RelationCache, and where
+// it records its activity.
+type RelationCacheConfig struct {
+	// MaxEntries, if non-zero, is the maximum number of units whose
+	// settings are cached. When it is exceeded, the least recently
+	// used settings are evicted.
+	MaxEntries int
+
+	// TTL, if non-zero, is the maximum time for which settings are
+	// cached; older settings are read again when next used.
+	TTL time.Duration
+
+	// Clock is used to expire settings. If nil, the wall clock is
+	// used.
+	Clock clock.Clock
+
+	// Metrics, if non-nil, records the cache's hits, misses and
+	// evictions.
+	Metrics *RelationCacheMetrics
+}
+
 // RelationCache stores a relation's remote unit membership and settings.
 // Member settings are stored until invalidated or removed by name; settings
-// of non-member units are stored only until the cache is pruned.
+// of non-member units are stored only until the cache is pruned. Settings
+// of either kind may be evicted earlier to keep within the cache's bounds.
 type RelationCache struct {
 	// readSettings is used to get settings data if when not already present.
 	readSettings SettingsFunc
-	// members' keys define the relation's membership; non-nil values hold
-	// cached settings.
-	members SettingsMap
-	// others is a short-term cache for non-member settings.
-	others SettingsMap
+	config       RelationCacheConfig
+	// members' keys define the relation's membership.
+	members map[string]bool
+	// entries holds the cached settings of members and non-members,
+	// keyed on unit name.
+	entries map[string]*list.Element
+	// lru orders the cached settings from most to least recently
+	// used.
+	lru *list.List
+}
+
+// cacheEntry holds the settings of a unit in a RelationCache.
+type cacheEntry struct {
+	unitName string
+	settings params.Settings
+	read     time.Time
 }
 
 // NewRelationCache creates a new RelationCache that will use the supplied
 // SettingsFunc to populate itself on demand. Initial membership is determined
-// by memberNames.
+// by memberNames. The cache is unbounded.
 func NewRelationCache(readSettings SettingsFunc, memberNames []string) *RelationCache {
+	return NewBoundedRelationCache(readSettings, memberNames, RelationCacheConfig{})
+}
+
+// NewBoundedRelationCache creates a new RelationCache, like
+// NewRelationCache, that keeps within the bounds of the supplied config.
+func NewBoundedRelationCache(readSettings SettingsFunc, memberNames []string, config RelationCacheConfig) *RelationCache {
+	if config.Clock == nil {
+		config.Clock = clock.WallClock
+	}
 	cache := &RelationCache{
 		readSettings: readSettings,
+		config:       config,
+		entries:      make(map[string]*list.Element),
+		lru:          list.New(),
 	}
 	cache.Prune(memberNames)
 	return cache
@@ -42,12 +91,16 @@ func NewRelationCache(readSettings SettingsFunc, memberNames []string) *Relation
 // Prune resets the membership to the supplied list, and discards the settings
 // of all non-member units.
 func (cache *RelationCache) Prune(memberNames []string) {
-	newMembers := SettingsMap{}
+	newMembers := make(map[string]bool)
 	for _, memberName := range memberNames {
-		newMembers[memberName] = cache.members[memberName]
+		newMembers[memberName] = true
 	}
 	cache.members = newMembers
-	cache.others = SettingsMap{}
+	for unitName := range cache.entries {
+		if !newMembers[unitName] {
+			cache.remove(unitName)
+		}
+	}
 }
 
 // MemberNames returns the names of the remote units present in the relation.
@@ -62,23 +115,31 @@ func (cache *RelationCache) MemberNames() (memberNames []string) {
 // Settings returns the settings of the named remote unit. It's valid to get
 // the settings of any unit that has ever been in the relation.
 func (cache *RelationCache) Settings(unitName string) (params.Settings, error) {
-	settings, isMember := cache.members[unitName]
-	if settings == nil {
-		if !isMember {
-			settings = cache.others[unitName]
-		}
-		if settings == nil {
-			var err error
-			settings, err = cache.readSettings(unitName)
-			if err != nil {
-				return nil, err
-			}
+	now := cache.config.Clock.Now()
+	if elem, ok := cache.entries[unitName]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if cache.config.TTL == 0 || now.Sub(entry.read) < cache.config.TTL {
+			cache.config.Metrics.hit()
+			cache.lru.MoveToFront(elem)
+			return entry.settings, nil
 		}
+		cache.config.Metrics.evicted()
+		cache.remove(unitName)
 	}
-	if isMember {
-		cache.members[unitName] = settings
-	} else {
-		cache.others[unitName] = settings
+	cache.config.Metrics.miss()
+	settings, err := cache.readSettings(unitName)
+	if err != nil {
+		return nil, err
+	}
+	cache.entries[unitName] = cache.lru.PushFront(&cacheEntry{
+		unitName: unitName,
+		settings: settings,
+		read:     now,
+	})
+	for cache.config.MaxEntries > 0 && cache.lru.Len() > cache.config.MaxEntries {
+		oldest := cache.lru.Back().Value.(*cacheEntry)
+		cache.config.Metrics.evicted()
+		cache.remove(oldest.unitName)
 	}
 	return settings, nil
 }
@@ -87,11 +148,21 @@ func (cache *RelationCache) Settings(unitName string) (params.Settings, error) {
 // member of the relation, and that the next attempt to read its settings will
 // use fresh data.
 func (cache *RelationCache) InvalidateMember(memberName string) {
-	cache.members[memberName] = nil
+	cache.members[memberName] = true
+	cache.remove(memberName)
 }
 
 // RemoveMember ensures that the named remote unit will not be considered a
 // member of the relation,
 func (cache *RelationCache) RemoveMember(memberName string) {
 	delete(cache.members, memberName)
+	cache.remove(memberName)
+}
+
+// remove discards the cached settings of the named unit, if any.
+func (cache *RelationCache) remove(unitName string) {
+	if elem, ok := cache.entries[unitName]; ok {
+		cache.lru.Remove(elem)
+		delete(cache.entries, unitName)
+	}
 }
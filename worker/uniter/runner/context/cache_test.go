@@ -4,6 +4,8 @@
 package context_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -213,3 +215,70 @@ func (s *RelationCacheSuite) TestPruneUncachesOtherSettings(c *gc.C) {
 	c.Assert(settings, jc.DeepEquals, params.Settings{"baz": "qux"})
 	c.Assert(s.calls, jc.DeepEquals, []string{"x/2", "x/2"})
 }
+
+func (s *RelationCacheSuite) TestMaxEntriesEvictsLeastRecentlyUsed(c *gc.C) {
+	s.results = []settingsResult{
+		{params.Settings{"n": "1"}, nil},
+		{params.Settings{"n": "2"}, nil},
+		{params.Settings{"n": "3"}, nil},
+		{params.Settings{"n": "1 again"}, nil},
+	}
+	metrics := &context.RelationCacheMetrics{}
+	cache := context.NewBoundedRelationCache(s.ReadSettings, []string{"u/1", "u/2", "u/3"}, context.RelationCacheConfig{
+		MaxEntries: 2,
+		Metrics:    metrics,
+	})
+
+	for _, unitName := range []string{"u/1", "u/2", "u/2", "u/3"} {
+		_, err := cache.Settings(unitName)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(s.calls, jc.DeepEquals, []string{"u/1", "u/2", "u/3"})
+
+	// Evicted settings are read again; membership is unaffected.
+	settings, err := cache.Settings("u/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"n": "1 again"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"u/1", "u/2", "u/3", "u/1"})
+	c.Assert(cache.MemberNames(), jc.DeepEquals, []string{"u/1", "u/2", "u/3"})
+	c.Assert(metrics.Stats(), jc.DeepEquals, context.RelationCacheStats{
+		Hits:      1,
+		Misses:    4,
+		Evictions: 2,
+	})
+}
+
+func (s *RelationCacheSuite) TestTTLExpiresSettings(c *gc.C) {
+	s.results = []settingsResult{
+		{params.Settings{"foo": "bar"}, nil},
+		{params.Settings{"baz": "qux"}, nil},
+	}
+	clock := testing.NewClock(time.Time{})
+	metrics := &context.RelationCacheMetrics{}
+	cache := context.NewBoundedRelationCache(s.ReadSettings, []string{"x/2"}, context.RelationCacheConfig{
+		TTL:     time.Minute,
+		Clock:   clock,
+		Metrics: metrics,
+	})
+
+	settings, err := cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
+
+	clock.Advance(59 * time.Second)
+	settings, err = cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"foo": "bar"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/2"})
+
+	clock.Advance(time.Second)
+	settings, err = cache.Settings("x/2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, params.Settings{"baz": "qux"})
+	c.Assert(s.calls, jc.DeepEquals, []string{"x/2", "x/2"})
+	c.Assert(metrics.Stats(), jc.DeepEquals, context.RelationCacheStats{
+		Hits:      1,
+		Misses:    2,
+		Evictions: 1,
+	})
+}
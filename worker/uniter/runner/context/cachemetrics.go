@@ -0,0 +1,111 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	jujuRelationCacheHitsTotalDesc = prometheus.NewDesc(
+		"juju_uniter_relation_cache_hits_total",
+		"Total number of relation settings read from the cache.",
+		[]string{},
+		prometheus.Labels{},
+	)
+	jujuRelationCacheMissesTotalDesc = prometheus.NewDesc(
+		"juju_uniter_relation_cache_misses_total",
+		"Total number of relation settings not found in the cache.",
+		[]string{},
+		prometheus.Labels{},
+	)
+	jujuRelationCacheEvictionsTotalDesc = prometheus.NewDesc(
+		"juju_uniter_relation_cache_evictions_total",
+		"Total number of relation settings evicted from the cache.",
+		[]string{},
+		prometheus.Labels{},
+	)
+)
+
+// RelationCacheStats holds the counters of a RelationCacheMetrics.
+type RelationCacheStats struct {
+	// Hits is the number of times settings were found in a cache.
+	Hits uint64
+
+	// Misses is the number of times settings were read because they
+	// were not found in a cache, or had expired.
+	Misses uint64
+
+	// Evictions is the number of times settings were discarded to
+	// keep a cache within its bounds.
+	Evictions uint64
+}
+
+// RelationCacheMetrics counts the activity of the RelationCaches that
+// share it, so that the memory used by the settings of large relations
+// may be diagnosed. It is a prometheus.Collector.
+type RelationCacheMetrics struct {
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// Stats returns the current counters.
+func (m *RelationCacheMetrics) Stats() RelationCacheStats {
+	return RelationCacheStats{
+		Hits:      atomic.LoadUint64(&m.hits),
+		Misses:    atomic.LoadUint64(&m.misses),
+		Evictions: atomic.LoadUint64(&m.evictions),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (m *RelationCacheMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- jujuRelationCacheHitsTotalDesc
+	ch <- jujuRelationCacheMissesTotalDesc
+	ch <- jujuRelationCacheEvictionsTotalDesc
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (m *RelationCacheMetrics) Collect(ch chan<- prometheus.Metric) {
+	stats := m.Stats()
+	ch <- prometheus.MustNewConstMetric(
+		jujuRelationCacheHitsTotalDesc,
+		prometheus.CounterValue,
+		float64(stats.Hits),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		jujuRelationCacheMissesTotalDesc,
+		prometheus.CounterValue,
+		float64(stats.Misses),
+	)
+	ch <- prometheus.MustNewConstMetric(
+		jujuRelationCacheEvictionsTotalDesc,
+		prometheus.CounterValue,
+		float64(stats.Evictions),
+	)
+}
+
+// The counting methods below may be called on a nil
+// RelationCacheMetrics, in which case they do nothing.
+
+func (m *RelationCacheMetrics) hit() {
+	if m != nil {
+		atomic.AddUint64(&m.hits, 1)
+	}
+}
+
+func (m *RelationCacheMetrics) miss() {
+	if m != nil {
+		atomic.AddUint64(&m.misses, 1)
+	}
+}
+
+func (m *RelationCacheMetrics) evicted() {
+	if m != nil {
+		atomic.AddUint64(&m.evictions, 1)
+	}
+}
@@ -6,7 +6,9 @@
 package context
 
 import (
+	stdcontext "context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +16,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/clock"
+	"github.com/juju/utils/keyvalues"
 	"github.com/juju/utils/proxy"
 	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
@@ -120,6 +123,14 @@ type HookContext struct {
 	// availabilityzone is the cached value of the unit's availability zone name.
 	availabilityzone string
 
+	// cloudSpec holds the cloud the model is running in, once it
+	// has been read from the controller.
+	cloudSpec *params.CloudSpec
+
+	// secrets holds the secrets stored by the unit's charm, once
+	// they have been read from the controller.
+	secrets map[string]string
+
 	// configSettings holds the service configuration.
 	configSettings charm.Settings
 
@@ -152,10 +163,28 @@ type HookContext struct {
 	// or if it is running a relation-broken hook.
 	remoteUnitName string
 
+	// departingUnitName identifies the unit leaving the relation in a
+	// relation-departed hook. It is either the local unit or the remote
+	// unit, and is empty for any other hook.
+	departingUnitName string
+
 	// relations contains the context for every relation the unit is a member
 	// of, keyed on relation id.
 	relations map[int]*ContextRelation
 
+	// configVersion is the controller's version of the config settings
+	// seen by the executing config-changed hook; it changes when, and
+	// only when, the settings do. It is empty if the context is not
+	// running a config-changed hook, or the controller reports no
+	// version.
+	configVersion string
+
+	// applicationHook is the name of the executing application hook,
+	// and applicationHookCharmURL the URL of the charm it is run for.
+	// They are empty if the context is not running an application hook.
+	applicationHook         string
+	applicationHookCharmURL string
+
 	// apiAddrs contains the API server addresses.
 	apiAddrs []string
 
@@ -182,6 +211,19 @@ type HookContext struct {
 	// like a juju-run command or a hook
 	process HookProcess
 
+	// cancelContext is done when the hook being run should be
+	// abandoned; cancel releases its resources. Both are nil if the
+	// hook may run for as long as it likes.
+	cancelContext stdcontext.Context
+	cancel        stdcontext.CancelFunc
+
+	// timeout is how long the hook may run for before its context is
+	// cancelled, or zero if it may run for as long as it likes.
+	timeout time.Duration
+
+	// killReason records why the running hook was killed, if it was.
+	killReason error
+
 	// rebootPriority tells us when the hook wants to reboot. If rebootPriority is jujuc.RebootNow
 	// the hook will be killed and requeued
 	rebootPriority jujuc.RebootPriority
@@ -208,6 +250,10 @@ type HookContext struct {
 	// clock is used for any time operations.
 	clock clock.Clock
 
+	// paths holds the paths used to build the environment of hooks
+	// run in the context.
+	paths Paths
+
 	componentDir   func(string) string
 	componentFuncs map[string]ComponentFunc
 
@@ -280,6 +326,32 @@ func (ctx *HookContext) SetProcess(process HookProcess) {
 	ctx.process = process
 }
 
+// CancelContext returns a context.Context that is done when the hook
+// being run in the context should be abandoned, because its deadline
+// has passed.
+func (ctx *HookContext) CancelContext() stdcontext.Context {
+	if ctx.cancelContext == nil {
+		return stdcontext.Background()
+	}
+	return ctx.cancelContext
+}
+
+// KillHook kills the hook being run in the context, recording the
+// reason it was killed; the hook is reported as failed for that reason
+// when the context is flushed.
+func (ctx *HookContext) KillHook(reason error) error {
+	mutex.Lock()
+	ctx.killReason = reason
+	mutex.Unlock()
+	return ctx.killCharmHook()
+}
+
+func (ctx *HookContext) getKillReason() error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return ctx.killReason
+}
+
 func (ctx *HookContext) Id() string {
 	return ctx.id
 }
@@ -305,6 +377,27 @@ func (ctx *HookContext) UnitStatus() (*jujuc.StatusInfo, error) {
 	return ctx.status, nil
 }
 
+// UnitStatusHistory returns the latest n entries of the unit's workload
+// and agent status history, oldest first. It is not cached, as it changes
+// whenever the unit's status does.
+func (ctx *HookContext) UnitStatusHistory(n int) ([]jujuc.StatusHistoryEntry, error) {
+	history, err := ctx.unit.UnitStatusHistory(n)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	entries := make([]jujuc.StatusHistoryEntry, len(history))
+	for i, st := range history {
+		entries[i] = jujuc.StatusHistoryEntry{
+			Kind:   st.Kind,
+			Status: st.Status,
+			Info:   st.Info,
+			Data:   st.Data,
+			Since:  st.Since,
+		}
+	}
+	return entries, nil
+}
+
 // ApplicationStatus returns the status for the application and all the units on
 // the service to which this context unit belongs, only if this unit is
 // the leader.
@@ -423,6 +516,21 @@ func (ctx *HookContext) Storage(tag names.StorageTag) (jujuc.ContextStorageAttac
 	return ctx.storage.Storage(tag)
 }
 
+// StorageUsage returns the most recently reported usage of the
+// filesystem storage attachment with the supplied tag. The usage is
+// fetched from the controller each time, as it changes independently
+// of the storage attachment.
+func (ctx *HookContext) StorageUsage(tag names.StorageTag) (params.FilesystemUsage, error) {
+	attachment, err := ctx.state.StorageAttachment(tag, ctx.unit.Tag())
+	if err != nil {
+		return params.FilesystemUsage{}, errors.Trace(err)
+	}
+	if attachment.Usage == nil {
+		return params.FilesystemUsage{}, errors.NotFoundf("usage of storage %q", tag.Id())
+	}
+	return *attachment.Usage, nil
+}
+
 func (ctx *HookContext) AddUnitStorage(cons map[string]params.StorageConstraints) error {
 	// All storage constraints are accumulated before context is flushed.
 	if ctx.storageAddConstraints == nil {
@@ -481,6 +589,64 @@ func (ctx *HookContext) ConfigSettings() (charm.Settings, error) {
 	return result, nil
 }
 
+// GoalState returns the units and relations that the model intends to
+// exist for the unit's application. It is not cached, as it may change
+// while the hook is running.
+func (ctx *HookContext) GoalState() (*params.GoalState, error) {
+	goalState, err := ctx.unit.GoalState()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &goalState, nil
+}
+
+// CloudSpec returns the cloud that the model is running in. The cloud
+// credential is only included if the unit's application is trusted.
+func (ctx *HookContext) CloudSpec() (*params.CloudSpec, error) {
+	if ctx.cloudSpec == nil {
+		spec, err := ctx.state.CloudSpec()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot get cloud spec")
+		}
+		ctx.cloudSpec = spec
+	}
+	return ctx.cloudSpec, nil
+}
+
+// SecretGet returns the secrets stored by the unit's charm.
+func (ctx *HookContext) SecretGet() (map[string]string, error) {
+	if ctx.secrets == nil {
+		secrets, err := ctx.unit.Secrets()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ctx.secrets = secrets
+	}
+	result := make(map[string]string, len(ctx.secrets))
+	for name, value := range ctx.secrets {
+		result[name] = value
+	}
+	return result, nil
+}
+
+// SecretSet immediately stores the supplied secrets on behalf of the
+// unit's charm. Secrets with empty values are removed.
+func (ctx *HookContext) SecretSet(secrets map[string]string) error {
+	if err := ctx.unit.SetSecrets(secrets); err != nil {
+		return errors.Trace(err)
+	}
+	if ctx.secrets != nil {
+		for name, value := range secrets {
+			if value == "" {
+				delete(ctx.secrets, name)
+			} else {
+				ctx.secrets[name] = value
+			}
+		}
+	}
+	return nil
+}
+
 // ActionName returns the name of the action.
 func (ctx *HookContext) ActionName() (string, error) {
 	if ctx.actionData == nil {
@@ -506,6 +672,34 @@ func (ctx *HookContext) SetActionMessage(message string) error {
 	return nil
 }
 
+// SetActionProgress reports intermediate progress of the running Action
+// to the controller, so that it may be observed before the Action
+// completes.
+func (ctx *HookContext) SetActionProgress(message string, percent int) error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	if percent < 0 || percent > 100 {
+		return errors.NotValidf("progress percentage %d", percent)
+	}
+	if err := ctx.state.ActionProgress(ctx.actionData.Tag, message, percent); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.actionData.ProgressMessage = message
+	ctx.actionData.ProgressPercent = percent
+	return nil
+}
+
+// ActionLog appends a timestamped message to the running Action's logs
+// on the controller, so that it may be observed before the Action
+// completes.
+func (ctx *HookContext) ActionLog(message string) error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	return errors.Trace(ctx.state.ActionLog(ctx.actionData.Tag, message))
+}
+
 // SetActionFailed sets the fail state of the action.
 func (ctx *HookContext) SetActionFailed() error {
 	if ctx.actionData == nil {
@@ -538,6 +732,16 @@ func (ctx *HookContext) RemoteUnitName() (string, error) {
 	return ctx.remoteUnitName, nil
 }
 
+// DepartingUnit returns the name of the unit leaving the relation in a
+// relation-departed hook. This is the local unit if it is the one
+// departing, and the remote unit otherwise.
+func (ctx *HookContext) DepartingUnit() (string, error) {
+	if ctx.departingUnitName == "" {
+		return "", errors.NotFoundf("departing unit")
+	}
+	return ctx.departingUnitName, nil
+}
+
 func (ctx *HookContext) Relation(id int) (jujuc.ContextRelation, error) {
 	r, found := ctx.relations[id]
 	if !found {
@@ -599,6 +803,9 @@ func (context *HookContext) HookVars(paths Paths) ([]string, error) {
 	} else if !errors.IsNotFound(err) {
 		return nil, errors.Trace(err)
 	}
+	if context.configVersion != "" {
+		vars = append(vars, "JUJU_CONFIG_VERSION="+context.configVersion)
+	}
 	if context.actionData != nil {
 		vars = append(vars,
 			"JUJU_ACTION_NAME="+context.actionData.Name,
@@ -609,6 +816,25 @@ func (context *HookContext) HookVars(paths Paths) ([]string, error) {
 	return append(vars, OSDependentEnvVars(paths)...), nil
 }
 
+// HookEnvironment returns the environment variables given to hooks run
+// in the context, as returned by HookVars, keyed by name. On Windows,
+// hooks also inherit the unit agent's environment, which is not
+// included.
+func (context *HookContext) HookEnvironment() (map[string]string, error) {
+	if context.paths == nil {
+		return nil, errors.NotSupportedf("hook environment without paths")
+	}
+	vars, err := context.HookVars(context.paths)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	env, err := keyvalues.Parse(vars, true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return env, nil
+}
+
 func (ctx *HookContext) handleReboot(err *error) {
 	logger.Tracef("checking for reboot request")
 	rebootPriority := ctx.GetRebootPriority()
@@ -647,6 +873,16 @@ func (ctx *HookContext) Prepare() error {
 
 // Flush implements the Context interface.
 func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
+	if ctx.cancel != nil {
+		ctx.cancel()
+	}
+	if reason := ctx.getKillReason(); reason != nil {
+		if reason == stdcontext.DeadlineExceeded && ctx.timeout > 0 {
+			reason = errors.Errorf("timed out after %v", ctx.timeout)
+		}
+		logger.Errorf("%q was killed: %v", process, reason)
+		ctxErr = NewHookKilledError(reason)
+	}
 	writeChanges := ctxErr == nil
 
 	// In the case of Actions, handle any errors using finalizeAction.
@@ -665,46 +901,24 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 		defer ctx.handleReboot(&err)
 	}
 
-	for id, rctx := range ctx.relations {
-		if writeChanges {
-			if e := rctx.WriteSettings(); e != nil {
-				e = errors.Errorf(
-					"could not write settings from %q to relation %d: %v",
-					process, id, e,
-				)
-				logger.Errorf("%v", e)
-				if ctxErr == nil {
-					ctxErr = e
-				}
+	if writeChanges {
+		if e := ctx.writeRelationSettings(); e != nil {
+			e = errors.Errorf(
+				"could not write relation settings from %q: %v",
+				process, e,
+			)
+			logger.Errorf("%v", e)
+			if ctxErr == nil {
+				ctxErr = e
 			}
 		}
 	}
 
-	for rangeKey, rangeInfo := range ctx.pendingPorts {
-		if writeChanges {
-			var e error
-			var op string
-			if rangeInfo.ShouldOpen {
-				e = ctx.unit.OpenPorts(
-					rangeKey.Ports.Protocol,
-					rangeKey.Ports.FromPort,
-					rangeKey.Ports.ToPort,
-				)
-				op = "open"
-			} else {
-				e = ctx.unit.ClosePorts(
-					rangeKey.Ports.Protocol,
-					rangeKey.Ports.FromPort,
-					rangeKey.Ports.ToPort,
-				)
-				op = "close"
-			}
-			if e != nil {
-				e = errors.Annotatef(e, "cannot %s %v", op, rangeKey.Ports)
-				logger.Errorf("%v", e)
-				if ctxErr == nil {
-					ctxErr = e
-				}
+	if len(ctx.pendingPorts) > 0 && writeChanges {
+		if e := ctx.writePorts(); e != nil {
+			logger.Errorf("%v", e)
+			if ctxErr == nil {
+				ctxErr = e
 			}
 		}
 	}
@@ -721,6 +935,10 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 		}
 	}
 
+	if ctx.applicationHook != "" && writeChanges && ctxErr == nil {
+		ctxErr = ctx.recordApplicationHook()
+	}
+
 	// TODO (tasdomas) 2014 09 03: context finalization needs to modified to apply all
 	//                             changes in one api call to minimize the risk
 	//                             of partial failures.
@@ -732,6 +950,86 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 	return ctxErr
 }
 
+// writeRelationSettings writes the changes made to the unit's settings
+// in all of its relations in a single API call.
+func (ctx *HookContext) writeRelationSettings() error {
+	var ids []int
+	for id := range ctx.relations {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	var (
+		pending []*uniter.Settings
+		written []*ContextRelation
+	)
+	for _, id := range ids {
+		rctx := ctx.relations[id]
+		if settings := rctx.pendingSettings(); settings != nil {
+			pending = append(pending, settings)
+			written = append(written, rctx)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := ctx.state.WriteSettings(pending...); err != nil {
+		return errors.Trace(err)
+	}
+	for _, rctx := range written {
+		rctx.settingsWritten()
+	}
+	return nil
+}
+
+// writePorts opens and closes the pending port ranges together in a
+// single API call, so that either all of them change or none do. If the
+// controller does not support that, each range is opened or closed in
+// turn.
+func (ctx *HookContext) writePorts() error {
+	var openRanges, closeRanges []network.PortRange
+	for rangeKey, rangeInfo := range ctx.pendingPorts {
+		if rangeInfo.ShouldOpen {
+			openRanges = append(openRanges, rangeKey.Ports)
+		} else {
+			closeRanges = append(closeRanges, rangeKey.Ports)
+		}
+	}
+	err := ctx.unit.OpenClosePortRanges(openRanges, closeRanges)
+	if err == nil {
+		return nil
+	} else if !errors.IsNotSupported(err) {
+		return errors.Annotate(err, "cannot open and close ports")
+	}
+	var firstErr error
+	change := func(op string, r network.PortRange, f func(string, int, int) error) {
+		if err := f(r.Protocol, r.FromPort, r.ToPort); err != nil {
+			err = errors.Annotatef(err, "cannot %s %v", op, r)
+			if firstErr == nil {
+				firstErr = err
+			} else {
+				logger.Errorf("%v", err)
+			}
+		}
+	}
+	for _, r := range openRanges {
+		change("open", r, ctx.unit.OpenPorts)
+	}
+	for _, r := range closeRanges {
+		change("close", r, ctx.unit.ClosePorts)
+	}
+	return firstErr
+}
+
+// recordApplicationHook records on the controller that the executing
+// application hook has completed for its charm, so that it is not run
+// again. The record can only be written while the unit holds the
+// leadership lease: if leadership was lost while the hook ran, the
+// write fails and the new leader will run the hook instead.
+func (ctx *HookContext) recordApplicationHook() error {
+	err := ctx.unit.SetApplicationHookCompleted(ctx.applicationHook, ctx.applicationHookCharmURL)
+	return errors.Annotatef(err, "cannot record completion of application hook %q", ctx.applicationHook)
+}
+
 // finalizeAction passes back the final status of an Action hook to state.
 // It wraps any errors which occurred in normal behavior of the Action run;
 // only errors passed in unhandledErr will be returned.
@@ -837,6 +1135,26 @@ func (ctx *HookContext) SetUnitWorkloadVersion(version string) error {
 	return result.OneError()
 }
 
+// SetApplicationWorkloadVersion sets the workload version of the
+// current unit's application to the specified value. It will fail
+// unless the unit is the application's leader.
+func (ctx *HookContext) SetApplicationWorkloadVersion(version string) error {
+	if ctx.state.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("setting application workload version")
+	}
+	var result params.ErrorResults
+	args := params.EntityWorkloadVersions{
+		Entities: []params.EntityWorkloadVersion{
+			{Tag: ctx.unit.Tag().String(), WorkloadVersion: version},
+		},
+	}
+	err := ctx.state.Facade().FacadeCall("SetApplicationWorkloadVersion", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // NetworkInfo returns the network info for the given bindingNames.
 func (ctx *HookContext) NetworkInfo(bindingNames []string) (map[string]params.NetworkInfoResult, error) {
 	return ctx.unit.NetworkInfo(bindingNames)
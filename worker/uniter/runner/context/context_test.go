@@ -46,6 +46,13 @@ func (s *InterfaceSuite) TestRemoteUnitName(c *gc.C) {
 	c.Assert(name, gc.Equals, "")
 }
 
+func (s *InterfaceSuite) TestDepartingUnit(c *gc.C) {
+	ctx := s.GetContext(c, 1, "u/123")
+	name, err := ctx.DepartingUnit()
+	c.Assert(err, gc.ErrorMatches, "departing unit not found")
+	c.Assert(name, gc.Equals, "")
+}
+
 func (s *InterfaceSuite) TestRelationIds(c *gc.C) {
 	ctx := s.GetContext(c, -1, "")
 	relIds, err := ctx.RelationIds()
@@ -227,6 +234,27 @@ func (s *InterfaceSuite) TestConfigCaching(c *gc.C) {
 	c.Assert(settings, gc.DeepEquals, charm.Settings{"blog-title": "My Title"})
 }
 
+func (s *InterfaceSuite) TestSecrets(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	secrets, err := ctx.SecretGet()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, gc.HasLen, 0)
+
+	err = ctx.SecretSet(map[string]string{"password": "hunter2", "token": "abc"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.SecretSet(map[string]string{"token": ""})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Secrets are written through immediately, and the local view
+	// is kept up to date.
+	secrets, err = ctx.SecretGet()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secrets, jc.DeepEquals, map[string]string{"password": "hunter2"})
+	stored, err := s.unit.Secrets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stored, jc.DeepEquals, secrets)
+}
+
 // TestNonActionCallsToActionMethodsFail does exactly what its name says:
 // it simply makes sure that Action-related calls to HookContexts with a nil
 // actionData member error out correctly.
@@ -238,6 +266,10 @@ func (s *InterfaceSuite) TestNonActionCallsToActionMethodsFail(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "not running an action")
 	err = ctx.SetActionMessage("foo")
 	c.Check(err, gc.ErrorMatches, "not running an action")
+	err = ctx.SetActionProgress("foo", 50)
+	c.Check(err, gc.ErrorMatches, "not running an action")
+	err = ctx.ActionLog("foo")
+	c.Check(err, gc.ErrorMatches, "not running an action")
 	err = ctx.UpdateActionResults([]string{"1", "2", "3"}, "value")
 	c.Check(err, gc.ErrorMatches, "not running an action")
 }
@@ -312,6 +344,64 @@ func (s *InterfaceSuite) TestSetActionMessage(c *gc.C) {
 	c.Check(actionData.ResultsMessage, gc.Equals, "because reasons")
 }
 
+// TestSetActionProgress ensures SetActionProgress records progress
+// against the running action.
+func (s *InterfaceSuite) TestSetActionProgress(c *gc.C) {
+	action, err := s.State.EnqueueAction(s.unit.Tag(), "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	hctx := s.getHookContext(c, s.State.ModelUUID(), -1, "", noProxies)
+	tag := action.ActionTag()
+	context.SetActionData(hctx, context.NewActionData("snapshot", &tag, nil))
+	err = hctx.SetActionProgress("copying files", 30)
+	c.Assert(err, jc.ErrorIsNil)
+
+	actionData, err := hctx.ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(actionData.ProgressMessage, gc.Equals, "copying files")
+	c.Check(actionData.ProgressPercent, gc.Equals, 30)
+
+	action, err = s.State.ActionByTag(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	message, percent := action.Progress()
+	c.Check(message, gc.Equals, "copying files")
+	c.Check(percent, gc.Equals, 30)
+}
+
+// TestActionLog ensures ActionLog appends messages to the running
+// action's logs.
+func (s *InterfaceSuite) TestActionLog(c *gc.C) {
+	action, err := s.State.EnqueueAction(s.unit.Tag(), "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	hctx := s.getHookContext(c, s.State.ModelUUID(), -1, "", noProxies)
+	tag := action.ActionTag()
+	context.SetActionData(hctx, context.NewActionData("snapshot", &tag, nil))
+	err = hctx.ActionLog("copying files")
+	c.Assert(err, jc.ErrorIsNil)
+	err = hctx.ActionLog("restarting")
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err = s.State.ActionByTag(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	messages := action.Messages()
+	c.Assert(messages, gc.HasLen, 2)
+	c.Check(messages[0].Message, gc.Equals, "copying files")
+	c.Check(messages[1].Message, gc.Equals, "restarting")
+}
+
+// TestSetActionProgressInvalidPercent ensures SetActionProgress rejects
+// percentages outside 0-100.
+func (s *InterfaceSuite) TestSetActionProgressInvalidPercent(c *gc.C) {
+	hctx := context.GetStubActionContext(nil)
+	err := hctx.SetActionProgress("too far", 101)
+	c.Assert(err, gc.ErrorMatches, "progress percentage 101 not valid")
+}
+
 func (s *InterfaceSuite) TestRequestRebootAfterHook(c *gc.C) {
 	var killed bool
 	p := &mockProcess{func() error {
@@ -10,12 +10,14 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/clock"
+	"github.com/juju/utils/proxy"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -76,9 +78,20 @@ type contextFactory struct {
 	zone       string
 	principal  string
 
+	// Callback to get the unit's meter status.
+	getMeterStatus func() (code, info string, err error)
+
+	// Cache of the model's config, if one was supplied.
+	modelConfig *ModelConfigCache
+
 	// Callback to get relation state snapshot.
 	getRelationInfos RelationsFunc
 	relationCaches   map[int]*RelationCache
+	relationCache    RelationCacheConfig
+
+	// Where the factory records the contexts it creates, and the
+	// time it spends creating them; may be nil.
+	metrics *ContextFactoryMetrics
 
 	// For generating "unique" context ids.
 	rand *rand.Rand
@@ -94,6 +107,25 @@ type FactoryConfig struct {
 	Storage          StorageContextAccessor
 	Paths            Paths
 	Clock            clock.Clock
+
+	// MeterStatus, if non-nil, supplies the unit's meter status to
+	// new contexts. Otherwise, the status is fetched from the API for
+	// every context.
+	MeterStatus *MeterStatusCache
+
+	// ModelConfig, if non-nil, supplies the model's proxy settings and
+	// hook timeouts to new contexts. Otherwise, the model config is
+	// fetched from the API for every context.
+	ModelConfig *ModelConfigCache
+
+	// RelationCache holds the bounds of the caches of each
+	// relation's settings, and where they record their activity. If
+	// its Clock is nil, Clock is used.
+	RelationCache RelationCacheConfig
+
+	// Metrics, if non-nil, records the contexts created by the
+	// factory and the time spent setting them up.
+	Metrics *ContextFactoryMetrics
 }
 
 // NewContextFactory returns a ContextFactory capable of creating execution contexts backed
@@ -124,6 +156,11 @@ func NewContextFactory(config FactoryConfig) (ContextFactory, error) {
 		principal = ""
 	}
 
+	relationCache := config.RelationCache
+	if relationCache.Clock == nil {
+		relationCache.Clock = config.Clock
+	}
+
 	f := &contextFactory{
 		unit:             unit,
 		state:            config.State,
@@ -134,11 +171,18 @@ func NewContextFactory(config FactoryConfig) (ContextFactory, error) {
 		machineTag:       machineTag,
 		getRelationInfos: config.GetRelationInfos,
 		relationCaches:   map[int]*RelationCache{},
+		relationCache:    relationCache,
+		metrics:          config.Metrics,
 		storage:          config.Storage,
 		rand:             rand.New(rand.NewSource(time.Now().Unix())),
 		clock:            config.Clock,
 		zone:             zone,
 		principal:        principal,
+		modelConfig:      config.ModelConfig,
+	}
+	f.getMeterStatus = unit.MeterStatus
+	if config.MeterStatus != nil {
+		f.getMeterStatus = config.MeterStatus.MeterStatus
 	}
 	return f, nil
 }
@@ -151,9 +195,11 @@ func (f *contextFactory) newId(name string) string {
 
 // coreContext creates a new context with all unspecialised fields filled in.
 func (f *contextFactory) coreContext() (*HookContext, error) {
+	start := f.clock.Now()
 	leadershipContext := newLeadershipContext(
 		f.state.LeadershipSettings,
 		f.tracker,
+		f.clock,
 	)
 	ctx := &HookContext{
 		unit:               f.unit,
@@ -168,6 +214,7 @@ func (f *contextFactory) coreContext() (*HookContext, error) {
 		pendingPorts:       make(map[PortRange]PortRangeInfo),
 		storage:            f.storage,
 		clock:              f.clock,
+		paths:              f.paths,
 		componentDir:       f.paths.ComponentDir,
 		componentFuncs:     registeredComponentFuncs,
 		availabilityzone:   f.zone,
@@ -176,6 +223,7 @@ func (f *contextFactory) coreContext() (*HookContext, error) {
 	if err := f.updateContext(ctx); err != nil {
 		return nil, err
 	}
+	f.metrics.coreContextCreated(f.clock.Now().Sub(start))
 	return ctx, nil
 }
 
@@ -190,19 +238,34 @@ func (f *contextFactory) ActionContext(actionData *ActionData) (*HookContext, er
 	}
 	ctx.actionData = actionData
 	ctx.id = f.newId(actionData.Name)
+	f.metrics.contextCreated(actionContextKind)
 	return ctx, nil
 }
 
 // HookContext is part of the ContextFactory interface.
 func (f *contextFactory) HookContext(hookInfo hook.Info) (*HookContext, error) {
+	if hookInfo.Kind == hooks.ConfigChanged && f.modelConfig != nil {
+		// The change that triggered the hook may not have reached
+		// the cache's watcher yet; make sure the hook sees it.
+		f.modelConfig.Invalidate()
+	}
 	ctx, err := f.coreContext()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	hookName := string(hookInfo.Kind)
+	if hookInfo.Kind == hooks.ConfigChanged {
+		// Fetch the settings the hook will see along with their
+		// version, so that the two agree.
+		ctx.configSettings, ctx.configVersion, err = ctx.unit.ConfigSettingsWithVersion()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	if hookInfo.Kind.IsRelation() {
 		ctx.relationId = hookInfo.RelationId
 		ctx.remoteUnitName = hookInfo.RemoteUnit
+		ctx.departingUnitName = hookInfo.DepartingUnit
 		relation, found := ctx.relations[hookInfo.RelationId]
 		if !found {
 			return nil, errors.Errorf("unknown relation id: %v", hookInfo.RelationId)
@@ -215,6 +278,11 @@ func (f *contextFactory) HookContext(hookInfo hook.Info) (*HookContext, error) {
 		}
 		hookName = fmt.Sprintf("%s-%s", relation.Name(), hookInfo.Kind)
 	}
+	if hookInfo.Kind == hook.ApplicationHook {
+		ctx.applicationHook = hookInfo.ApplicationHookName
+		ctx.applicationHookCharmURL = hookInfo.CharmURL
+		hookName = hookInfo.ApplicationHookName
+	}
 	if hookInfo.Kind.IsStorage() {
 		ctx.storageTag = names.NewStorageTag(hookInfo.StorageId)
 		if _, err := ctx.storage.Storage(ctx.storageTag); err != nil {
@@ -227,9 +295,37 @@ func (f *contextFactory) HookContext(hookInfo hook.Info) (*HookContext, error) {
 		hookName = fmt.Sprintf("%s-%s", storageName, hookName)
 	}
 	ctx.id = f.newId(hookName)
+	timeout, err := f.hookTimeout(hookInfo.Kind)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if timeout > 0 {
+		ctx.timeout = timeout
+		ctx.cancelContext, ctx.cancel = newDeadlineContext(f.clock, timeout)
+	}
+	if hookInfo.Kind == hook.ApplicationHook {
+		ctx.cancelContext, ctx.cancel = newLeaderContext(ctx.CancelContext(), ctx.cancel, f.tracker)
+	}
+	f.metrics.contextCreated(hookContextKind)
 	return ctx, nil
 }
 
+// hookTimeout returns how long a hook of the given kind may run for,
+// according to the model's config; zero means there is no limit.
+func (f *contextFactory) hookTimeout(kind hooks.Kind) (time.Duration, error) {
+	var modelConfig *config.Config
+	var err error
+	if f.modelConfig != nil {
+		modelConfig, err = f.modelConfig.ModelConfig()
+	} else {
+		modelConfig, err = f.state.ModelConfig()
+	}
+	if err != nil {
+		return 0, errors.Annotate(err, "cannot get hook timeout")
+	}
+	return modelConfig.HookTimeout(string(kind)), nil
+}
+
 // CommandContext is part of the ContextFactory interface.
 func (f *contextFactory) CommandContext(commandInfo CommandInfo) (*HookContext, error) {
 	ctx, err := f.coreContext()
@@ -243,6 +339,7 @@ func (f *contextFactory) CommandContext(commandInfo CommandInfo) (*HookContext,
 	ctx.relationId = relationId
 	ctx.remoteUnitName = remoteUnitName
 	ctx.id = f.newId("run-commands")
+	f.metrics.contextCreated(commandContextKind)
 	return ctx, nil
 }
 
@@ -259,7 +356,7 @@ func (f *contextFactory) getContextRelations() map[int]*ContextRelation {
 		if found {
 			cache.Prune(memberNames)
 		} else {
-			cache = NewRelationCache(relationUnit.ReadSettings, memberNames)
+			cache = NewBoundedRelationCache(relationUnit.ReadSettings, memberNames, f.relationCache)
 		}
 		relationCaches[id] = cache
 		contextRelations[id] = NewContextRelation(relationUnit, cache)
@@ -278,16 +375,22 @@ func (f *contextFactory) getContextRelations() map[int]*ContextRelation {
 func (f *contextFactory) updateContext(ctx *HookContext) (err error) {
 	defer errors.Trace(err)
 
+	start := f.clock.Now()
 	ctx.apiAddrs, err = f.state.APIAddresses()
+	f.apiCallCompleted("api-addresses", start)
 	if err != nil {
 		return err
 	}
+	start = f.clock.Now()
 	ctx.machinePorts, err = f.state.AllMachinePorts(f.machineTag)
+	f.apiCallCompleted("machine-ports", start)
 	if err != nil {
 		return errors.Trace(err)
 	}
 
-	statusCode, statusInfo, err := f.unit.MeterStatus()
+	start = f.clock.Now()
+	statusCode, statusInfo, err := f.getMeterStatus()
+	f.apiCallCompleted("meter-status", start)
 	if err != nil {
 		return errors.Annotate(err, "could not retrieve meter status for unit")
 	}
@@ -296,34 +399,60 @@ func (f *contextFactory) updateContext(ctx *HookContext) (err error) {
 		info: statusInfo,
 	}
 
+	start = f.clock.Now()
 	sla, err := f.state.SLALevel()
+	f.apiCallCompleted("sla-level", start)
 	if err != nil {
 		return errors.Annotate(err, "could not retrieve the SLA level")
 	}
 	ctx.slaLevel = sla
 
-	// TODO(fwereade) 23-10-2014 bug 1384572
-	// Nothing here should ever be getting the environ config directly.
-	modelConfig, err := f.state.ModelConfig()
+	start = f.clock.Now()
+	ctx.proxySettings, err = f.proxySettings()
+	f.apiCallCompleted("proxy-settings", start)
 	if err != nil {
 		return err
 	}
-	ctx.proxySettings = modelConfig.ProxySettings()
 
 	// Calling these last, because there's a potential race: they're not guaranteed
 	// to be set in time to be needed for a hook. If they're not, we just leave them
 	// unset as we always have; this isn't great but it's about behaviour preservation.
+	start = f.clock.Now()
 	ctx.publicAddress, err = f.unit.PublicAddress()
+	f.apiCallCompleted("public-address", start)
 	if err != nil && !params.IsCodeNoAddressSet(err) {
 		return err
 	}
+	start = f.clock.Now()
 	ctx.privateAddress, err = f.unit.PrivateAddress()
+	f.apiCallCompleted("private-address", start)
 	if err != nil && !params.IsCodeNoAddressSet(err) {
 		return err
 	}
 	return nil
 }
 
+// apiCallCompleted records the duration of an API call, made by
+// updateContext, that started at the given time.
+func (f *contextFactory) apiCallCompleted(call string, start time.Time) {
+	f.metrics.apiCallCompleted(call, f.clock.Now().Sub(start))
+}
+
+// proxySettings returns the model's proxy settings, from the factory's
+// cache if it has one.
+func (f *contextFactory) proxySettings() (proxy.Settings, error) {
+	if f.modelConfig != nil {
+		return f.modelConfig.ProxySettings()
+	}
+	// TODO(fwereade) 23-10-2014 bug 1384572
+	// Nothing here should ever be getting the environ config directly.
+	modelConfig, err := f.state.ModelConfig()
+	if err != nil {
+		return proxy.Settings{}, err
+	}
+	return modelConfig.ProxySettings(), nil
+}
+
 func inferRemoteUnit(rctxs map[int]*ContextRelation, info CommandInfo) (int, string, error) {
 	relationId := info.RelationId
 	hasRelation := relationId != -1
@@ -4,15 +4,21 @@
 package context_test
 
 import (
+	stdcontext "context"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/fs"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 	"gopkg.in/juju/names.v2"
 
@@ -21,6 +27,7 @@ import (
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	runnertesting "github.com/juju/juju/worker/uniter/runner/testing"
@@ -92,7 +99,7 @@ func (s *ContextFactorySuite) testLeadershipContextWiring(c *gc.C, createContext
 	var stub testing.Stub
 	stub.SetErrors(errors.New("bam"))
 	restore := context.PatchNewLeadershipContext(
-		func(accessor context.LeadershipSettingsAccessor, tracker leadership.Tracker) context.LeadershipContext {
+		func(accessor context.LeadershipSettingsAccessor, tracker leadership.Tracker, _ clock.Clock) context.LeadershipContext {
 			stub.AddCall("NewLeadershipContext", accessor, tracker)
 			return &StubLeadershipContext{Stub: &stub}
 		},
@@ -122,6 +129,16 @@ func (s *ContextFactorySuite) TestNewHookContextRetrievesSLALevel(c *gc.C) {
 	c.Assert(ctx.SLALevel(), gc.Equals, "essential")
 }
 
+func (s *ContextFactorySuite) TestNewHookContextRetrievesCloudSpec(c *gc.C) {
+	ctx, err := s.factory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	spec, err := ctx.CloudSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec.Type, gc.Equals, "dummy")
+	c.Assert(spec.Region, gc.Equals, "dummy-region")
+	c.Assert(spec.Credential, gc.IsNil)
+}
+
 func (s *ContextFactorySuite) TestNewHookContextLeadershipContext(c *gc.C) {
 	s.testLeadershipContextWiring(c, func() *context.HookContext {
 		ctx, err := s.factory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
@@ -235,6 +252,119 @@ func (s *ContextFactorySuite) TestNewHookContextWithStorage(c *gc.C) {
 	s.AssertNotRelationContext(c, ctx)
 }
 
+func (s *ContextFactorySuite) configVersion(c *gc.C, kind hooks.Kind) string {
+	ctx, err := s.factory.HookContext(hook.Info{Kind: kind})
+	c.Assert(err, jc.ErrorIsNil)
+	vars, err := ctx.HookVars(s.paths)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, v := range vars {
+		if strings.HasPrefix(v, "JUJU_CONFIG_VERSION=") {
+			return strings.TrimPrefix(v, "JUJU_CONFIG_VERSION=")
+		}
+	}
+	return ""
+}
+
+func (s *ContextFactorySuite) TestHookContextConfigVersion(c *gc.C) {
+	c.Assert(s.configVersion(c, hooks.Install), gc.Equals, "")
+
+	version := s.configVersion(c, hooks.ConfigChanged)
+	c.Assert(version, gc.Not(gc.Equals), "")
+	c.Assert(s.configVersion(c, hooks.ConfigChanged), gc.Equals, version)
+
+	err := s.service.UpdateConfigSettings(charm.Settings{"blog-title": "changed"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.configVersion(c, hooks.ConfigChanged), gc.Not(gc.Equals), version)
+}
+
+func (s *ContextFactorySuite) TestHookContextTimeout(c *gc.C) {
+	err := s.State.UpdateModelConfig(map[string]interface{}{
+		"hook-timeout":           "1h",
+		"hook-timeout-overrides": "install=1m stop=0s",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	clock := testing.NewClock(time.Time{})
+	contextFactory, err := context.NewContextFactory(context.FactoryConfig{
+		State:            s.uniter,
+		UnitTag:          s.unit.Tag().(names.UnitTag),
+		Tracker:          runnertesting.FakeTracker{},
+		GetRelationInfos: s.getRelationInfos,
+		Storage:          s.storage,
+		Paths:            s.paths,
+		Clock:            clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := contextFactory.HookContext(hook.Info{Kind: hooks.Stop})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := ctx.CancelContext().Deadline()
+	c.Assert(ok, jc.IsFalse)
+
+	ctx, err = contextFactory.HookContext(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	deadline, ok := ctx.CancelContext().Deadline()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(deadline, gc.Equals, clock.Now().Add(time.Hour))
+	c.Assert(ctx.Flush("config-changed", nil), jc.ErrorIsNil)
+	c.Assert(ctx.CancelContext().Err(), gc.Equals, stdcontext.Canceled)
+
+	ctx, err = contextFactory.HookContext(hook.Info{Kind: hooks.Install})
+	c.Assert(err, jc.ErrorIsNil)
+	cancel := ctx.CancelContext()
+	deadline, ok = cancel.Deadline()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(deadline, gc.Equals, clock.Now().Add(time.Minute))
+	c.Assert(cancel.Err(), jc.ErrorIsNil)
+
+	clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	select {
+	case <-cancel.Done():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for hook deadline")
+	}
+	c.Assert(cancel.Err(), gc.Equals, stdcontext.DeadlineExceeded)
+
+	// A hook killed at its deadline is reported with its timeout.
+	ctx.SetProcess(&mockProcess{func() error {
+		return errors.New("process is already dead")
+	}})
+	c.Assert(ctx.KillHook(cancel.Err()), jc.ErrorIsNil)
+	err = ctx.Flush("install", errors.New("signal: killed"))
+	c.Assert(err, gc.ErrorMatches, "hook killed: timed out after 1m0s")
+	c.Assert(context.HookKilledReason(err), gc.ErrorMatches, "timed out after 1m0s")
+}
+
+func (s *ContextFactorySuite) TestApplicationHookContextKilledWhenDeposed(c *gc.C) {
+	deposed := make(minionTicket)
+	contextFactory, err := context.NewContextFactory(context.FactoryConfig{
+		State:            s.uniter,
+		UnitTag:          s.unit.Tag().(names.UnitTag),
+		Tracker:          minionTracker{ticket: deposed},
+		GetRelationInfos: s.getRelationInfos,
+		Storage:          s.storage,
+		Paths:            s.paths,
+		Clock:            testing.NewClock(time.Time{}),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := contextFactory.HookContext(hook.Info{
+		Kind:                hook.ApplicationHook,
+		ApplicationHookName: "migrate",
+		CharmURL:            "cs:quantal/wordpress-3",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	cancel := ctx.CancelContext()
+	c.Assert(cancel.Err(), jc.ErrorIsNil)
+
+	close(deposed)
+	select {
+	case <-cancel.Done():
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for leadership loss")
+	}
+	c.Assert(cancel.Err(), gc.Equals, context.ErrLeadershipLost)
+}
+
 func (s *ContextFactorySuite) TestActionContext(c *gc.C) {
 	s.SetCharm(c, "dummy")
 	action, err := s.State.EnqueueAction(s.unit.Tag(), "snapshot", nil)
@@ -266,6 +396,62 @@ func (s *ContextFactorySuite) TestCommandContext(c *gc.C) {
 	s.AssertNotStorageContext(c, ctx)
 }
 
+func (s *ContextFactorySuite) TestMetrics(c *gc.C) {
+	metrics := context.NewContextFactoryMetrics()
+	contextFactory, err := context.NewContextFactory(context.FactoryConfig{
+		State:            s.uniter,
+		UnitTag:          s.unit.Tag().(names.UnitTag),
+		Tracker:          runnertesting.FakeTracker{},
+		GetRelationInfos: s.getRelationInfos,
+		Storage:          s.storage,
+		Paths:            s.paths,
+		Clock:            testing.NewClock(time.Time{}),
+		Metrics:          metrics,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = contextFactory.HookContext(hook.Info{Kind: hooks.Install})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = contextFactory.HookContext(hook.Info{Kind: hooks.Start})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = contextFactory.CommandContext(context.CommandInfo{RelationId: -1})
+	c.Assert(err, jc.ErrorIsNil)
+
+	registry := prometheus.NewPedanticRegistry()
+	err = registry.Register(metrics)
+	c.Assert(err, jc.ErrorIsNil)
+	metricFamilies, err := registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	families := make(map[string]*dto.MetricFamily)
+	for _, family := range metricFamilies {
+		families[family.GetName()] = family
+	}
+
+	created := make(map[string]float64)
+	for _, metric := range families["juju_uniter_contexts_created_total"].GetMetric() {
+		created[metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+	}
+	c.Check(created, jc.DeepEquals, map[string]float64{"hook": 2, "command": 1})
+
+	coreContext := families["juju_uniter_core_context_duration_seconds"].GetMetric()
+	c.Assert(coreContext, gc.HasLen, 1)
+	c.Check(coreContext[0].GetHistogram().GetSampleCount(), gc.Equals, uint64(3))
+
+	apiCalls := make(map[string]uint64)
+	for _, metric := range families["juju_uniter_context_api_call_duration_seconds"].GetMetric() {
+		apiCalls[metric.GetLabel()[0].GetValue()] = metric.GetHistogram().GetSampleCount()
+	}
+	c.Check(apiCalls, jc.DeepEquals, map[string]uint64{
+		"api-addresses":   3,
+		"machine-ports":   3,
+		"meter-status":    3,
+		"sla-level":       3,
+		"proxy-settings":  3,
+		"public-address":  3,
+		"private-address": 3,
+	})
+}
+
 func (s *ContextFactorySuite) TestCommandContextNoRelation(c *gc.C) {
 	ctx, err := s.factory.CommandContext(context.CommandInfo{RelationId: -1})
 	c.Assert(err, jc.ErrorIsNil)
@@ -419,6 +605,22 @@ func (s *ContextFactorySuite) TestNewHookContextRelationDepartedUpdatesRelationC
 	c.Assert(member, jc.IsTrue)
 }
 
+func (s *ContextFactorySuite) TestNewHookContextRelationDepartedDepartingUnit(c *gc.C) {
+	s.setUpCacheMethods(c)
+	s.membership[1] = []string{"r/0"}
+
+	ctx, err := s.factory.HookContext(hook.Info{
+		Kind:          hooks.RelationDeparted,
+		RelationId:    1,
+		RemoteUnit:    "r/0",
+		DepartingUnit: "u/0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	departing, err := ctx.DepartingUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(departing, gc.Equals, "u/0")
+}
+
 func (s *ContextFactorySuite) TestNewHookContextRelationBrokenRetainsCaches(c *gc.C) {
 	// Note that this is bizarre and unrealistic, because we would never usually
 	// run relation-broken on a non-empty relation. But verfying that the settings
@@ -455,3 +657,27 @@ func (stub *StubLeadershipContext) IsLeader() (bool, error) {
 	stub.MethodCall(stub, "IsLeader")
 	return false, stub.NextErr()
 }
+
+// minionTracker is a leadership.Tracker whose WaitMinion ticket is
+// ready when the unit is deposed.
+type minionTracker struct {
+	runnertesting.FakeTracker
+	ticket minionTicket
+}
+
+func (t minionTracker) WaitMinion() leadership.Ticket {
+	return t.ticket
+}
+
+// minionTicket is a leadership.Ticket that is ready, reporting that the
+// unit is no longer the leader, once it is closed.
+type minionTicket chan struct{}
+
+func (t minionTicket) Ready() <-chan struct{} {
+	return t
+}
+
+func (t minionTicket) Wait() bool {
+	<-t
+	return true
+}
@@ -0,0 +1,153 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	stdcontext "context"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/core/leadership"
+)
+
+// deadlineContext is a context.Context whose deadline is measured by a
+// clock.Clock rather than the wall clock, so that hook timeouts can be
+// exercised in tests.
+type deadlineContext struct {
+	stdcontext.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// newDeadlineContext returns a context that is done once the given
+// timeout has elapsed on the supplied clock, or when the returned
+// cancel func is called, whichever happens first.
+func newDeadlineContext(clk clock.Clock, timeout time.Duration) (stdcontext.Context, stdcontext.CancelFunc) {
+	ctx := &deadlineContext{
+		Context:  stdcontext.Background(),
+		deadline: clk.Now().Add(timeout),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-clk.After(timeout):
+			ctx.finish(stdcontext.DeadlineExceeded)
+		case <-ctx.done:
+		}
+	}()
+	return ctx, func() { ctx.finish(stdcontext.Canceled) }
+}
+
+func (ctx *deadlineContext) finish(err error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.err != nil {
+		return
+	}
+	ctx.err = err
+	close(ctx.done)
+}
+
+// Deadline is part of the context.Context interface.
+func (ctx *deadlineContext) Deadline() (time.Time, bool) {
+	return ctx.deadline, true
+}
+
+// Done is part of the context.Context interface.
+func (ctx *deadlineContext) Done() <-chan struct{} {
+	return ctx.done
+}
+
+// Err is part of the context.Context interface.
+func (ctx *deadlineContext) Err() error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.err
+}
+
+// errLeadershipLost is the reason an application hook is killed when
+// its unit can no longer be sure that it is the leader.
+var errLeadershipLost = errors.New("leadership lost")
+
+// leaderContext is a context.Context that is done when its parent is,
+// or as soon as the unit's leadership can no longer be guaranteed; so
+// that a deposed leader stops running an application hook that the new
+// leader will run instead.
+type leaderContext struct {
+	stdcontext.Context
+	done chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// newLeaderContext returns a context that is done when the parent is,
+// when the tracker's leadership can no longer be guaranteed, or when
+// the returned cancel func is called, whichever happens first. The
+// cancel func also calls parentCancel, if it is not nil.
+func newLeaderContext(
+	parent stdcontext.Context,
+	parentCancel stdcontext.CancelFunc,
+	tracker leadership.Tracker,
+) (stdcontext.Context, stdcontext.CancelFunc) {
+	ctx := &leaderContext{
+		Context: parent,
+		done:    make(chan struct{}),
+	}
+	minion := tracker.WaitMinion()
+	go func() {
+		ready := minion.Ready()
+		for {
+			select {
+			case <-parent.Done():
+				ctx.finish(parent.Err())
+				return
+			case <-ready:
+				if minion.Wait() {
+					ctx.finish(errLeadershipLost)
+					return
+				}
+				// The tracker stopped without deposing us;
+				// wait for the parent instead.
+				ready = nil
+			case <-ctx.done:
+				return
+			}
+		}
+	}()
+	return ctx, func() {
+		ctx.finish(stdcontext.Canceled)
+		if parentCancel != nil {
+			parentCancel()
+		}
+	}
+}
+
+func (ctx *leaderContext) finish(err error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.err != nil {
+		return
+	}
+	ctx.err = err
+	close(ctx.done)
+}
+
+// Done is part of the context.Context interface.
+func (ctx *leaderContext) Done() <-chan struct{} {
+	return ctx.done
+}
+
+// Err is part of the context.Context interface.
+func (ctx *leaderContext) Err() error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	return ctx.err
+}
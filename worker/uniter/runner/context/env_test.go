@@ -97,6 +97,52 @@ func (s *EnvSuite) setRelation(ctx *context.HookContext) (expectVars []string) {
 	}
 }
 
+func (s *EnvSuite) TestEnvConfigVersion(c *gc.C) {
+	s.PatchValue(&jujuos.HostOS, func() jujuos.OSType { return jujuos.Ubuntu })
+	os.Setenv("PATH", "foo:bar")
+	ubuntuVars := []string{
+		"PATH=path-to-tools:foo:bar",
+		"APT_LISTCHANGES_FRONTEND=none",
+		"DEBIAN_FRONTEND=noninteractive",
+	}
+
+	ctx, contextVars := s.getContext()
+	paths, pathsVars := s.getPaths()
+	context.SetEnvironmentHookContextConfigVersion(ctx, "0f1e2d")
+	actualVars, err := ctx.HookVars(paths)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVars(c, actualVars, contextVars, pathsVars, ubuntuVars, []string{"JUJU_CONFIG_VERSION=0f1e2d"})
+}
+
+func (s *EnvSuite) TestHookEnvironment(c *gc.C) {
+	s.PatchValue(&jujuos.HostOS, func() jujuos.OSType { return jujuos.Ubuntu })
+	os.Setenv("PATH", "foo:bar")
+
+	ctx, contextVars := s.getContext()
+	paths, pathsVars := s.getPaths()
+	context.SetEnvironmentHookContextPaths(ctx, paths)
+	relationVars := s.setRelation(ctx)
+	env, err := ctx.HookEnvironment()
+	c.Assert(err, jc.ErrorIsNil)
+
+	var expectVars []string
+	for _, vars := range [][]string{contextVars, pathsVars, relationVars} {
+		expectVars = append(expectVars, vars...)
+	}
+	expect, err := keyvalues.Parse(expectVars, true)
+	c.Assert(err, jc.ErrorIsNil)
+	expect["PATH"] = "path-to-tools:foo:bar"
+	expect["APT_LISTCHANGES_FRONTEND"] = "none"
+	expect["DEBIAN_FRONTEND"] = "noninteractive"
+	c.Assert(env, jc.DeepEquals, expect)
+}
+
+func (s *EnvSuite) TestHookEnvironmentNoPaths(c *gc.C) {
+	ctx, _ := s.getContext()
+	_, err := ctx.HookEnvironment()
+	c.Assert(err, gc.ErrorMatches, "hook environment without paths not supported")
+}
+
 func (s *EnvSuite) TestEnvSetsPath(c *gc.C) {
 	paths := context.OSDependentEnvVars(MockEnvPaths{})
 	c.Assert(paths, gc.Not(gc.HasLen), 0)
@@ -4,6 +4,8 @@
 package context
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 )
 
@@ -27,3 +29,36 @@ func IsMissingHookError(err error) bool {
 func NewMissingHookError(hookName string) error {
 	return &missingHookError{hookName}
 }
+
+// hookKilledError is returned from Flush when the context's hook was
+// killed before it completed.
+type hookKilledError struct {
+	reason error
+}
+
+func (e *hookKilledError) Error() string {
+	return fmt.Sprintf("hook killed: %v", e.reason)
+}
+
+// IsHookKilledError returns true if the error, or its cause, indicates
+// that a hook was killed before it completed.
+func IsHookKilledError(err error) bool {
+	_, ok := errors.Cause(err).(*hookKilledError)
+	return ok
+}
+
+// HookKilledReason returns the reason the hook was killed, if the
+// error, or its cause, indicates that a hook was killed before it
+// completed; otherwise it returns nil.
+func HookKilledReason(err error) error {
+	if e, ok := errors.Cause(err).(*hookKilledError); ok {
+		return e.reason
+	}
+	return nil
+}
+
+// NewHookKilledError returns an error indicating that a hook was
+// killed, for the given reason, before it completed.
+func NewHookKilledError(reason error) error {
+	return &hookKilledError{reason}
+}
@@ -56,6 +56,7 @@ func NewHookContext(
 		pendingPorts:       make(map[PortRange]PortRangeInfo),
 		assignedMachineTag: assignedMachineTag,
 		clock:              clock,
+		paths:              paths,
 	}
 	// Get and cache the addresses.
 	var err error
@@ -103,6 +104,28 @@ func SetEnvironmentHookContextRelation(
 	}
 }
 
+// SetEnvironmentHookContextPaths exists purely to set the paths used
+// by HookEnvironment.
+func SetEnvironmentHookContextPaths(context *HookContext, paths Paths) {
+	context.paths = paths
+}
+
+// SetEnvironmentHookContextConfigVersion exists purely to set the fields used in hookVars.
+func SetEnvironmentHookContextConfigVersion(context *HookContext, configVersion string) {
+	context.configVersion = configVersion
+}
+
+// SetApplicationHook makes the context run the named application hook
+// for the given charm.
+func SetApplicationHook(context *HookContext, name, charmURL string) {
+	context.applicationHook = name
+	context.applicationHookCharmURL = charmURL
+}
+
+// ErrLeadershipLost is the reason an application hook is killed when
+// its unit is deposed.
+var ErrLeadershipLost = errLeadershipLost
+
 func PatchCachedStatus(ctx jujuc.Context, status, info string, data map[string]interface{}) func() {
 	hctx := ctx.(*HookContext)
 	oldStatus := hctx.status
@@ -124,7 +147,12 @@ func GetStubActionContext(in map[string]interface{}) *HookContext {
 	}
 }
 
-type LeadershipContextFunc func(LeadershipSettingsAccessor, leadership.Tracker) LeadershipContext
+// SetActionData sets the action data of the given context.
+func SetActionData(ctx *HookContext, data *ActionData) {
+	ctx.actionData = data
+}
+
+type LeadershipContextFunc func(LeadershipSettingsAccessor, leadership.Tracker, clock.Clock) LeadershipContext
 
 func PatchNewLeadershipContext(f LeadershipContextFunc) func() {
 	var old LeadershipContextFunc
@@ -172,19 +200,33 @@ func ContextMachineTag(hctx *HookContext) names.MachineTag {
 
 func UpdateCachedSettings(cf0 ContextFactory, relId int, unitName string, settings params.Settings) {
 	cf := cf0.(*contextFactory)
-	members := cf.relationCaches[relId].members
-	if members[unitName] == nil {
-		members[unitName] = params.Settings{}
+	cache := cf.relationCaches[relId]
+	cache.members[unitName] = true
+	elem, ok := cache.entries[unitName]
+	if !ok {
+		elem = cache.lru.PushFront(&cacheEntry{
+			unitName: unitName,
+			settings: params.Settings{},
+			read:     cache.config.Clock.Now(),
+		})
+		cache.entries[unitName] = elem
 	}
+	entry := elem.Value.(*cacheEntry)
 	for key, value := range settings {
-		members[unitName][key] = value
+		entry.settings[key] = value
 	}
 }
 
+// CachedSettings returns the cached settings of the named unit, if
+// any, and whether it is a member of the relation.
 func CachedSettings(cf0 ContextFactory, relId int, unitName string) (params.Settings, bool) {
 	cf := cf0.(*contextFactory)
-	settings, found := cf.relationCaches[relId].members[unitName]
-	return settings, found
+	cache := cf.relationCaches[relId]
+	var settings params.Settings
+	if elem, ok := cache.entries[unitName]; ok {
+		settings = elem.Value.(*cacheEntry).settings
+	}
+	return settings, cache.members[unitName]
 }
 
 func (ctx *HookContext) SLALevel() string {
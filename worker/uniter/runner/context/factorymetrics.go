@@ -0,0 +1,98 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	kindLabel = "kind"
+	callLabel = "call"
+)
+
+// Kinds of context created by a ContextFactory, as recorded by
+// ContextFactoryMetrics.
+const (
+	hookContextKind    = "hook"
+	actionContextKind  = "action"
+	commandContextKind = "command"
+)
+
+// contextSetupBuckets are the upper bounds, in seconds, of the
+// histograms of the time taken to set up contexts. Setup is dominated
+// by API calls, so the buckets range from a fast round trip to one that
+// would noticeably delay a hook.
+var contextSetupBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// ContextFactoryMetrics records how many contexts a ContextFactory
+// creates, and how long it spends creating them, so that slow hook
+// setup may be diagnosed. It is a prometheus.Collector.
+type ContextFactoryMetrics struct {
+	contextsTotal       *prometheus.CounterVec
+	coreContextDuration prometheus.Histogram
+	apiCallDuration     *prometheus.HistogramVec
+}
+
+// NewContextFactoryMetrics returns a new ContextFactoryMetrics.
+func NewContextFactoryMetrics() *ContextFactoryMetrics {
+	return &ContextFactoryMetrics{
+		contextsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "juju",
+			Name:      "uniter_contexts_created_total",
+			Help:      "Total number of hook, action and command contexts created.",
+		}, []string{kindLabel}),
+
+		coreContextDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "juju",
+			Name:      "uniter_core_context_duration_seconds",
+			Help:      "Time taken to fill in the fields common to all contexts.",
+			Buckets:   contextSetupBuckets,
+		}),
+
+		apiCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "juju",
+			Name:      "uniter_context_api_call_duration_seconds",
+			Help:      "Time taken by each API call made to update a new context.",
+			Buckets:   contextSetupBuckets,
+		}, []string{callLabel}),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (m *ContextFactoryMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.contextsTotal.Describe(ch)
+	m.coreContextDuration.Describe(ch)
+	m.apiCallDuration.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (m *ContextFactoryMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.contextsTotal.Collect(ch)
+	m.coreContextDuration.Collect(ch)
+	m.apiCallDuration.Collect(ch)
+}
+
+// The recording methods below may be called on a nil
+// ContextFactoryMetrics, in which case they do nothing.
+
+func (m *ContextFactoryMetrics) contextCreated(kind string) {
+	if m != nil {
+		m.contextsTotal.With(prometheus.Labels{kindLabel: kind}).Inc()
+	}
+}
+
+func (m *ContextFactoryMetrics) coreContextCreated(duration time.Duration) {
+	if m != nil {
+		m.coreContextDuration.Observe(duration.Seconds())
+	}
+}
+
+func (m *ContextFactoryMetrics) apiCallCompleted(call string, duration time.Duration) {
+	if m != nil {
+		m.apiCallDuration.With(prometheus.Labels{callLabel: call}).Observe(duration.Seconds())
+	}
+}
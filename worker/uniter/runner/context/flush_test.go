@@ -4,6 +4,8 @@
 package context_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -28,6 +30,7 @@ var _ = gc.Suite(&FlushContextSuite{})
 func (s *FlushContextSuite) SetUpTest(c *gc.C) {
 	s.HookContextSuite.SetUpTest(c)
 	s.stub.ResetCalls()
+	s.stub.SetErrors()
 }
 
 func (s *FlushContextSuite) TestRunHookRelationFlushingError(c *gc.C) {
@@ -58,6 +61,31 @@ func (s *FlushContextSuite) TestRunHookRelationFlushingError(c *gc.C) {
 	c.Assert(settings1, gc.DeepEquals, map[string]interface{}{"relation-name": "db1"})
 }
 
+func (s *FlushContextSuite) TestRunHookKilledFlushing(c *gc.C) {
+	ctx := s.context(c)
+	ctx.SetProcess(&mockProcess{func() error {
+		return errors.New("process is already dead")
+	}})
+
+	relCtx0, err := ctx.Relation(0)
+	c.Assert(err, jc.ErrorIsNil)
+	node0, err := relCtx0.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	node0.Set("foo", "1")
+
+	err = ctx.KillHook(errors.New("too slow"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The kill is reported in place of the hook's own failure.
+	err = ctx.Flush("some badge", errors.New("signal: killed"))
+	c.Assert(err, gc.ErrorMatches, "hook killed: too slow")
+	c.Assert(context.IsHookKilledError(err), jc.IsTrue)
+
+	settings0, err := s.relunits[0].ReadSettings("u/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings0, gc.DeepEquals, map[string]interface{}{"relation-name": "db0"})
+}
+
 func (s *FlushContextSuite) TestRunHookRelationFlushingSuccess(c *gc.C) {
 	ctx := s.context(c)
 
@@ -169,6 +197,31 @@ func (s *FlushContextSuite) TestRunHookOpensAndClosesPendingPorts(c *gc.C) {
 	c.Assert(unitRanges, jc.DeepEquals, expectUnitRanges)
 }
 
+func (s *FlushContextSuite) TestRunHookPendingPortsAllOrNothing(c *gc.C) {
+	otherUnit, err := s.service.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = otherUnit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx := s.context(c)
+	err = ctx.OpenPorts("udp", 10, 20)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ctx.OpenPorts("tcp", 300, 400)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Another unit opens a conflicting range while the hook runs.
+	err = otherUnit.OpenPorts("tcp", 350, 360)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ctx.Flush("some badge", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot open and close ports: .*port ranges .* conflict`)
+
+	// Neither range has been opened.
+	unitRanges, err := s.unit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unitRanges, gc.HasLen, 0)
+}
+
 func (s *FlushContextSuite) TestRunHookAddStorageOnFailure(c *gc.C) {
 	ctx := s.context(c)
 	c.Assert(ctx.UnitName(), gc.Equals, "u/0")
@@ -208,6 +261,50 @@ func (s *FlushContextSuite) TestRunHookAddUnitStorageOnSuccess(c *gc.C) {
 	c.Assert(all, gc.HasLen, 0)
 }
 
+func (s *FlushContextSuite) TestRunApplicationHookRecordsCompletion(c *gc.C) {
+	err := s.State.LeadershipClaimer().ClaimLeadership(s.service.Name(), s.unit.Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := s.context(c)
+	context.SetApplicationHook(ctx, "migrate", "cs:quantal/wordpress-3")
+
+	err = ctx.Flush("migrate", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCompletedApplicationHooks(c, map[string]string{"migrate": "cs:quantal/wordpress-3"})
+
+	// The completion is not visible to the charm.
+	settings, err := s.service.LeaderSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
+
+func (s *FlushContextSuite) TestRunApplicationHookFailureNotRecorded(c *gc.C) {
+	err := s.State.LeadershipClaimer().ClaimLeadership(s.service.Name(), s.unit.Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := s.context(c)
+	context.SetApplicationHook(ctx, "migrate", "cs:quantal/wordpress-3")
+
+	err = ctx.Flush("migrate", errors.New("blam pow"))
+	c.Assert(err, gc.ErrorMatches, "blam pow")
+	s.assertCompletedApplicationHooks(c, map[string]string{})
+}
+
+func (s *FlushContextSuite) TestRunApplicationHookNotLeader(c *gc.C) {
+	ctx := s.context(c)
+	context.SetApplicationHook(ctx, "migrate", "cs:quantal/wordpress-3")
+
+	// Only the leader can record the hook's completion; the new
+	// leader runs the hook again.
+	err := ctx.Flush("migrate", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot record completion of application hook "migrate": .*`)
+	s.assertCompletedApplicationHooks(c, map[string]string{})
+}
+
+func (s *FlushContextSuite) assertCompletedApplicationHooks(c *gc.C, expect map[string]string) {
+	hooks, err := s.service.CompletedApplicationHooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hooks, jc.DeepEquals, expect)
+}
+
 func (s *HookContextSuite) context(c *gc.C) *context.HookContext {
 	uuid, err := utils.NewUUID()
 	c.Assert(err, jc.ErrorIsNil)
@@ -4,7 +4,10 @@
 package context
 
 import (
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
 
 	"github.com/juju/juju/core/leadership"
 )
@@ -25,6 +28,7 @@ type LeadershipSettingsAccessor interface {
 // exists separately of HookContext for clarity, and ease of testing.
 type LeadershipContext interface {
 	IsLeader() (bool, error)
+	LeaderLeaseRemaining() (time.Duration, error)
 	LeaderSettings() (map[string]string, error)
 	WriteLeaderSettings(map[string]string) error
 }
@@ -32,16 +36,23 @@ type LeadershipContext interface {
 type leadershipContext struct {
 	accessor        LeadershipSettingsAccessor
 	tracker         leadership.Tracker
+	clock           clock.Clock
 	applicationName string
 
 	isMinion bool
 	settings map[string]string
+
+	// leaseExpiry records when the lease backing the last successful
+	// leadership claim expires; until that is near, further claims are
+	// answered without consulting the tracker.
+	leaseExpiry time.Time
 }
 
-func NewLeadershipContext(accessor LeadershipSettingsAccessor, tracker leadership.Tracker) LeadershipContext {
+func NewLeadershipContext(accessor LeadershipSettingsAccessor, tracker leadership.Tracker, clock clock.Clock) LeadershipContext {
 	return &leadershipContext{
 		accessor:        accessor,
 		tracker:         tracker,
+		clock:           clock,
 		applicationName: tracker.ApplicationName(),
 	}
 }
@@ -65,12 +76,28 @@ func (ctx *leadershipContext) IsLeader() (bool, error) {
 	return false, errors.Trace(err)
 }
 
+// LeaderLeaseRemaining is part of the jujuc.Context interface.
+func (ctx *leadershipContext) LeaderLeaseRemaining() (time.Duration, error) {
+	err := ctx.ensureLeader()
+	switch err {
+	case nil:
+		if remaining := ctx.leaseExpiry.Sub(ctx.clock.Now()); remaining > 0 {
+			return remaining, nil
+		}
+		return 0, nil
+	case errIsMinion:
+		return 0, nil
+	}
+	return 0, errors.Trace(err)
+}
+
 // WriteLeaderSettings is part of the jujuc.Context interface.
 func (ctx *leadershipContext) WriteLeaderSettings(settings map[string]string) error {
 	// This may trigger a lease refresh; it would be desirable to use a less
 	// eager approach here, but we're working around a race described in
 	// `apiserver/leadership.LeadershipSettingsAccessor.Merge`, and as of
 	// 2015-02-19 it's better to stay eager.
+	ctx.leaseExpiry = time.Time{}
 	err := ctx.ensureLeader()
 	if err == nil {
 		// Clear local settings; if we need them again we should use the values
@@ -102,10 +129,19 @@ func (ctx *leadershipContext) ensureLeader() error {
 	if ctx.isMinion {
 		return errIsMinion
 	}
+	// A previous claim can be trusted for as long as the tracker would
+	// guarantee a fresh one; once the lease is closer to expiry than that,
+	// the tracker must be asked again so that a long-running hook does not
+	// keep believing it leads after the lease has lapsed.
+	if ctx.clock.Now().Add(ctx.tracker.ClaimDuration()).Before(ctx.leaseExpiry) {
+		return nil
+	}
 	success := ctx.tracker.ClaimLeader().Wait()
 	if !success {
 		ctx.isMinion = true
+		ctx.leaseExpiry = time.Time{}
 		return errIsMinion
 	}
+	ctx.leaseExpiry = ctx.tracker.LeaseExpiry()
 	return nil
 }
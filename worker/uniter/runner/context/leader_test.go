@@ -4,6 +4,8 @@
 package context_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -18,6 +20,7 @@ type LeaderSuite struct {
 	testing.Stub
 	accessor *StubLeadershipSettingsAccessor
 	tracker  *StubTracker
+	clock    *testing.Clock
 	context  context.LeadershipContext
 }
 
@@ -31,11 +34,13 @@ func (s *LeaderSuite) SetUpTest(c *gc.C) {
 	s.tracker = &StubTracker{
 		Stub:        &s.Stub,
 		serviceName: "led-service",
+		duration:    30 * time.Second,
 	}
+	s.clock = testing.NewClock(time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC))
 	s.CheckCalls(c, []testing.StubCall{{
 		FuncName: "ApplicationName",
 	}}, func() {
-		s.context = context.NewLeadershipContext(s.accessor, s.tracker)
+		s.context = context.NewLeadershipContext(s.accessor, s.tracker, s.clock)
 	})
 }
 
@@ -67,6 +72,62 @@ func (s *LeaderSuite) TestIsLeaderSuccess(c *gc.C) {
 	})
 }
 
+func (s *LeaderSuite) TestIsLeaderCachedUntilLeaseNearExpiry(c *gc.C) {
+	s.tracker.expiry = s.clock.Now().Add(time.Minute)
+	s.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeader",
+	}}, func() {
+		// The first call asks the tracker...
+		s.tracker.results = []StubTicket{true}
+		leader, err := s.context.IsLeader()
+		c.Check(leader, jc.IsTrue)
+		c.Check(err, jc.ErrorIsNil)
+	})
+
+	s.CheckCalls(c, nil, func() {
+		// ...the second trusts the lease...
+		s.clock.Advance(29 * time.Second)
+		leader, err := s.context.IsLeader()
+		c.Check(leader, jc.IsTrue)
+		c.Check(err, jc.ErrorIsNil)
+	})
+
+	s.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeader",
+	}}, func() {
+		// ...but once the lease is too close to expiry, it's checked again.
+		s.clock.Advance(time.Second)
+		s.tracker.results = []StubTicket{false}
+		leader, err := s.context.IsLeader()
+		c.Check(leader, jc.IsFalse)
+		c.Check(err, jc.ErrorIsNil)
+	})
+}
+
+func (s *LeaderSuite) TestLeaderLeaseRemaining(c *gc.C) {
+	s.tracker.expiry = s.clock.Now().Add(time.Minute)
+	s.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeader",
+	}}, func() {
+		s.tracker.results = []StubTicket{true}
+		s.clock.Advance(15 * time.Second)
+		remaining, err := s.context.LeaderLeaseRemaining()
+		c.Check(remaining, gc.Equals, 45*time.Second)
+		c.Check(err, jc.ErrorIsNil)
+	})
+}
+
+func (s *LeaderSuite) TestLeaderLeaseRemainingMinion(c *gc.C) {
+	s.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeader",
+	}}, func() {
+		s.tracker.results = []StubTicket{false}
+		remaining, err := s.context.LeaderLeaseRemaining()
+		c.Check(remaining, gc.Equals, time.Duration(0))
+		c.Check(err, jc.ErrorIsNil)
+	})
+}
+
 func (s *LeaderSuite) TestIsLeaderFailure(c *gc.C) {
 	s.CheckCalls(c, []testing.StubCall{{
 		FuncName: "ClaimLeader",
@@ -304,6 +365,8 @@ type StubTracker struct {
 	leadership.Tracker
 	*testing.Stub
 	serviceName string
+	duration    time.Duration
+	expiry      time.Time
 	results     []StubTicket
 }
 
@@ -312,6 +375,14 @@ func (stub *StubTracker) ApplicationName() string {
 	return stub.serviceName
 }
 
+func (stub *StubTracker) ClaimDuration() time.Duration {
+	return stub.duration
+}
+
+func (stub *StubTracker) LeaseExpiry() time.Time {
+	return stub.expiry
+}
+
 func (stub *StubTracker) ClaimLeader() (result leadership.Ticket) {
 	stub.MethodCall(stub, "ClaimLeader")
 	result, stub.results = stub.results[0], stub.results[1:]
@@ -0,0 +1,85 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/watcher"
+)
+
+// MeterStatusSource provides a unit's meter status, and notifies of
+// changes to it.
+type MeterStatusSource interface {
+	// MeterStatus returns the unit's meter status code and info.
+	MeterStatus() (code, info string, err error)
+
+	// WatchMeterStatus returns a watcher that notifies of changes
+	// to the unit's meter status.
+	WatchMeterStatus() (watcher.NotifyWatcher, error)
+}
+
+// MeterStatusCache stores a unit's meter status, so that contexts may be
+// created without fetching it from the API every time. The status is
+// refetched only after the cache's watcher has reported a change.
+//
+// MeterStatusCache is a worker: killing it stops the underlying watcher.
+type MeterStatusCache struct {
+	source  MeterStatusSource
+	watcher watcher.NotifyWatcher
+
+	mu    sync.Mutex
+	valid bool
+	code  string
+	info  string
+}
+
+// NewMeterStatusCache returns a new MeterStatusCache that watches the
+// supplied source for changes.
+func NewMeterStatusCache(source MeterStatusSource) (*MeterStatusCache, error) {
+	w, err := source.WatchMeterStatus()
+	if err != nil {
+		return nil, errors.Annotate(err, "watching meter status")
+	}
+	return &MeterStatusCache{
+		source:  source,
+		watcher: w,
+	}, nil
+}
+
+// MeterStatus returns the cached meter status, fetching it first if the
+// cache is empty or the status has changed since it was last fetched.
+func (cache *MeterStatusCache) MeterStatus() (code, info string, err error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	select {
+	case _, ok := <-cache.watcher.Changes():
+		if !ok {
+			return "", "", errors.New("meter status watcher stopped")
+		}
+		cache.valid = false
+	default:
+	}
+	if !cache.valid {
+		code, info, err := cache.source.MeterStatus()
+		if err != nil {
+			return "", "", errors.Trace(err)
+		}
+		cache.code, cache.info = code, info
+		cache.valid = true
+	}
+	return cache.code, cache.info, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (cache *MeterStatusCache) Kill() {
+	cache.watcher.Kill()
+}
+
+// Wait is part of the worker.Worker interface.
+func (cache *MeterStatusCache) Wait() error {
+	return cache.watcher.Wait()
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+type MeterStatusCacheSuite struct {
+	testing.IsolationSuite
+	source *mockMeterStatusSource
+}
+
+var _ = gc.Suite(&MeterStatusCacheSuite{})
+
+func (s *MeterStatusCacheSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.source = &mockMeterStatusSource{
+		code:    "GREEN",
+		info:    "all good",
+		changes: make(chan struct{}, 1),
+	}
+}
+
+func (s *MeterStatusCacheSuite) TestWatchError(c *gc.C) {
+	s.source.SetErrors(errors.New("boom"))
+	_, err := context.NewMeterStatusCache(s.source)
+	c.Assert(err, gc.ErrorMatches, "watching meter status: boom")
+}
+
+func (s *MeterStatusCacheSuite) TestMeterStatusCached(c *gc.C) {
+	cache, err := context.NewMeterStatusCache(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 3; i++ {
+		code, info, err := cache.MeterStatus()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(code, gc.Equals, "GREEN")
+		c.Check(info, gc.Equals, "all good")
+	}
+	s.source.CheckCallNames(c, "WatchMeterStatus", "MeterStatus")
+}
+
+func (s *MeterStatusCacheSuite) TestMeterStatusRefetchedAfterChange(c *gc.C) {
+	cache, err := context.NewMeterStatusCache(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = cache.MeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.source.code, s.source.info = "AMBER", "running late"
+	s.source.changes <- struct{}{}
+	code, info, err := cache.MeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(code, gc.Equals, "AMBER")
+	c.Check(info, gc.Equals, "running late")
+
+	_, _, err = cache.MeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	s.source.CheckCallNames(c, "WatchMeterStatus", "MeterStatus", "MeterStatus")
+}
+
+func (s *MeterStatusCacheSuite) TestMeterStatusErrorNotCached(c *gc.C) {
+	s.source.SetErrors(nil, errors.New("boom"))
+	cache, err := context.NewMeterStatusCache(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = cache.MeterStatus()
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	code, _, err := cache.MeterStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(code, gc.Equals, "GREEN")
+	s.source.CheckCallNames(c, "WatchMeterStatus", "MeterStatus", "MeterStatus")
+}
+
+func (s *MeterStatusCacheSuite) TestWatcherStopped(c *gc.C) {
+	cache, err := context.NewMeterStatusCache(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	close(s.source.changes)
+	_, _, err = cache.MeterStatus()
+	c.Assert(err, gc.ErrorMatches, "meter status watcher stopped")
+}
+
+type mockMeterStatusSource struct {
+	testing.Stub
+	code, info string
+	changes    chan struct{}
+}
+
+func (s *mockMeterStatusSource) MeterStatus() (string, string, error) {
+	s.MethodCall(s, "MeterStatus")
+	if err := s.NextErr(); err != nil {
+		return "", "", err
+	}
+	return s.code, s.info, nil
+}
+
+func (s *mockMeterStatusSource) WatchMeterStatus() (watcher.NotifyWatcher, error) {
+	s.MethodCall(s, "WatchMeterStatus")
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return &mockMeterStatusWatcher{s.changes}, nil
+}
+
+type mockMeterStatusWatcher struct {
+	changes chan struct{}
+}
+
+func (w *mockMeterStatusWatcher) Changes() watcher.NotifyChannel {
+	return w.changes
+}
+
+func (w *mockMeterStatusWatcher) Kill() {}
+
+func (w *mockMeterStatusWatcher) Wait() error {
+	return nil
+}
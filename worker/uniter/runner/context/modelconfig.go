@@ -0,0 +1,120 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/proxy"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/watcher"
+)
+
+// ModelConfigSource provides the model's configuration, and notifies
+// of changes to it.
+type ModelConfigSource interface {
+	// ModelConfig returns the model's current configuration.
+	ModelConfig() (*config.Config, error)
+
+	// WatchForModelConfigChanges returns a watcher that notifies of
+	// changes to the model's configuration.
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+}
+
+// ModelConfigCache stores the model's configuration, and the proxy
+// settings derived from it, so that contexts may be created without
+// fetching it from the API every time. The configuration is refetched
+// only after the cache's watcher has reported a change, or the cache
+// has been invalidated.
+//
+// ModelConfigCache is a worker: killing it stops the underlying watcher.
+type ModelConfigCache struct {
+	source  ModelConfigSource
+	watcher watcher.NotifyWatcher
+
+	mu            sync.Mutex
+	config        *config.Config
+	proxySettings proxy.Settings
+}
+
+// NewModelConfigCache returns a new ModelConfigCache that watches the
+// supplied source for changes.
+func NewModelConfigCache(source ModelConfigSource) (*ModelConfigCache, error) {
+	w, err := source.WatchForModelConfigChanges()
+	if err != nil {
+		return nil, errors.Annotate(err, "watching model config")
+	}
+	return &ModelConfigCache{
+		source:  source,
+		watcher: w,
+	}, nil
+}
+
+// ModelConfig returns the cached model configuration, fetching it
+// first if the cache is empty or the configuration has changed since
+// it was last fetched.
+func (cache *ModelConfigCache) ModelConfig() (*config.Config, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err := cache.refresh(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cache.config, nil
+}
+
+// ProxySettings returns the proxy settings of the cached model
+// configuration, fetching it first as for ModelConfig.
+func (cache *ModelConfigCache) ProxySettings() (proxy.Settings, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err := cache.refresh(); err != nil {
+		return proxy.Settings{}, errors.Trace(err)
+	}
+	return cache.proxySettings, nil
+}
+
+// Invalidate discards the cached configuration, so that it will be
+// fetched afresh when next required. It is used before running a hook
+// that must see any change that triggered it, and that may not yet
+// have been reported by the watcher.
+func (cache *ModelConfigCache) Invalidate() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.config = nil
+}
+
+// refresh fetches the model configuration if it is not cached or has
+// changed. It must be called with cache.mu held.
+func (cache *ModelConfigCache) refresh() error {
+	select {
+	case _, ok := <-cache.watcher.Changes():
+		if !ok {
+			return errors.New("model config watcher stopped")
+		}
+		cache.config = nil
+	default:
+	}
+	if cache.config != nil {
+		return nil
+	}
+	cfg, err := cache.source.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cache.config = cfg
+	cache.proxySettings = cfg.ProxySettings()
+	return nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (cache *ModelConfigCache) Kill() {
+	cache.watcher.Kill()
+}
+
+// Wait is part of the worker.Worker interface.
+func (cache *ModelConfigCache) Wait() error {
+	return cache.watcher.Wait()
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package context_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/uniter/runner/context"
+)
+
+type ModelConfigCacheSuite struct {
+	testing.IsolationSuite
+	source *mockModelConfigSource
+}
+
+var _ = gc.Suite(&ModelConfigCacheSuite{})
+
+func (s *ModelConfigCacheSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.source = &mockModelConfigSource{
+		config: coretesting.CustomModelConfig(c, coretesting.Attrs{
+			"http-proxy": "http://proxy.example.com",
+		}),
+		changes: make(chan struct{}, 1),
+	}
+}
+
+func (s *ModelConfigCacheSuite) TestWatchError(c *gc.C) {
+	s.source.SetErrors(errors.New("boom"))
+	_, err := context.NewModelConfigCache(s.source)
+	c.Assert(err, gc.ErrorMatches, "watching model config: boom")
+}
+
+func (s *ModelConfigCacheSuite) TestModelConfigCached(c *gc.C) {
+	cache, err := context.NewModelConfigCache(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 3; i++ {
+		cfg, err := cache.ModelConfig()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(cfg, gc.Equals, s.source.config)
+		proxySettings, err := cache.ProxySettings()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(proxySettings.Http, gc.Equals, "http://proxy.example.com")
+	}
+	s.source.CheckCallNames(c, "WatchForModelConfigChanges", "ModelConfig")
+}
+
+func (s *ModelConfigCacheSuite) TestModelConfigRefetchedAfterChange(c *gc.C) {
+	cache, err := context.NewModelConfigCache(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cache.ProxySettings()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.source.config = coretesting.CustomModelConfig(c, coretesting.Attrs{
+		"http-proxy": "http://other.example.com",
+	})
+	s.source.changes <- struct{}{}
+	proxySettings, err := cache.ProxySettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(proxySettings.Http, gc.Equals, "http://other.example.com")
+
+	_, err = cache.ProxySettings()
+	c.Assert(err, jc.ErrorIsNil)
+	s.source.CheckCallNames(c, "WatchForModelConfigChanges", "ModelConfig", "ModelConfig")
+}
+
+func (s *ModelConfigCacheSuite) TestInvalidate(c *gc.C) {
+	cache, err := context.NewModelConfigCache(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cache.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cache.Invalidate()
+	_, err = cache.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	s.source.CheckCallNames(c, "WatchForModelConfigChanges", "ModelConfig", "ModelConfig")
+}
+
+func (s *ModelConfigCacheSuite) TestModelConfigErrorNotCached(c *gc.C) {
+	s.source.SetErrors(nil, errors.New("boom"))
+	cache, err := context.NewModelConfigCache(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cache.ProxySettings()
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	_, err = cache.ProxySettings()
+	c.Assert(err, jc.ErrorIsNil)
+	s.source.CheckCallNames(c, "WatchForModelConfigChanges", "ModelConfig", "ModelConfig")
+}
+
+func (s *ModelConfigCacheSuite) TestWatcherStopped(c *gc.C) {
+	cache, err := context.NewModelConfigCache(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	close(s.source.changes)
+	_, err = cache.ModelConfig()
+	c.Assert(err, gc.ErrorMatches, "model config watcher stopped")
+}
+
+type mockModelConfigSource struct {
+	testing.Stub
+	config  *config.Config
+	changes chan struct{}
+}
+
+func (s *mockModelConfigSource) ModelConfig() (*config.Config, error) {
+	s.MethodCall(s, "ModelConfig")
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.config, nil
+}
+
+func (s *mockModelConfigSource) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	s.MethodCall(s, "WatchForModelConfigChanges")
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return &mockMeterStatusWatcher{s.changes}, nil
+}
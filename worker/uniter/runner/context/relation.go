@@ -23,7 +23,7 @@ type ContextRelation struct {
 	endpointName string
 
 	// settings allows read and write access to the relation unit settings.
-	settings *uniter.Settings
+	settings *relationSettings
 
 	// cache holds remote unit membership and settings.
 	cache *RelationCache
@@ -66,15 +66,57 @@ func (ctx *ContextRelation) Settings() (jujuc.Settings, error) {
 		if err != nil {
 			return nil, err
 		}
-		ctx.settings = node
+		ctx.settings = &relationSettings{Settings: node}
 	}
 	return ctx.settings, nil
 }
 
-// WriteSettings persists all changes made to the unit's relation settings.
-func (ctx *ContextRelation) WriteSettings() (err error) {
+// pendingSettings returns the unit's relation settings if they have
+// been changed since they were last written, and nil otherwise.
+func (ctx *ContextRelation) pendingSettings() *uniter.Settings {
+	if ctx.settings == nil || !ctx.settings.changed {
+		return nil
+	}
+	return ctx.settings.Settings
+}
+
+// settingsWritten records that the changes returned by pendingSettings
+// have been written.
+func (ctx *ContextRelation) settingsWritten() {
 	if ctx.settings != nil {
-		err = ctx.settings.Write()
+		ctx.settings.changed = false
+	}
+}
+
+// WriteSettings persists all changes made to the unit's relation settings.
+func (ctx *ContextRelation) WriteSettings() error {
+	settings := ctx.pendingSettings()
+	if settings == nil {
+		return nil
 	}
-	return
+	if err := settings.Write(); err != nil {
+		return err
+	}
+	ctx.settingsWritten()
+	return nil
+}
+
+// relationSettings buffers the changes made to the unit's relation
+// settings during a hook, so that they can be written together with
+// the changes made to its other relations when the hook completes.
+type relationSettings struct {
+	*uniter.Settings
+	changed bool
+}
+
+// Set is part of the jujuc.Settings interface.
+func (s *relationSettings) Set(key, value string) {
+	s.Settings.Set(key, value)
+	s.changed = true
+}
+
+// Delete is part of the jujuc.Settings interface.
+func (s *relationSettings) Delete(key string) {
+	s.Settings.Delete(key)
+	s.changed = true
 }
@@ -161,6 +161,29 @@ func (s *ContextRelationSuite) TestLocalSettings(c *gc.C) {
 	c.Assert(settings, gc.DeepEquals, map[string]interface{}{"change": "exciting"})
 }
 
+func (s *ContextRelationSuite) TestWriteUnchangedSettings(c *gc.C) {
+	ctx := context.NewContextRelation(s.apiRelUnit, nil)
+
+	// Read Settings...
+	_, err := ctx.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// ...change them in state...
+	settings, err := s.ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("other", "change")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// ...and check that writing the unchanged settings does not
+	// overwrite the other change.
+	err = ctx.WriteSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.ru.ReadSettings("u/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m["other"], gc.Equals, "change")
+}
+
 func convertSettings(settings params.Settings) map[string]interface{} {
 	result := make(map[string]interface{})
 	for k, v := range settings {
@@ -17,3 +17,16 @@ var (
 func RunnerPaths(rnr Runner) context.Paths {
 	return rnr.(*runner).paths
 }
+
+func RunnerHooksDir(rnr Runner) string {
+	return rnr.(*runner).hooksDir
+}
+
+// NewAttemptTrackingRunners returns a function that creates runners
+// sharing a single record of hook attempts, as a factory's do.
+func NewAttemptTrackingRunners(paths context.Paths) func(Context) Runner {
+	attempts := newHookAttempts()
+	return func(ctx Context) Runner {
+		return newRunner(ctx, paths, nil, attempts)
+	}
+}
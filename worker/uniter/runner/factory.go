@@ -33,11 +33,13 @@ type Factory interface {
 }
 
 // NewFactory returns a Factory capable of creating runners for executing
-// charm hooks, actions and commands.
+// charm hooks, actions and commands. If auditor is not nil, the runners
+// record every hook tool invocation with it.
 func NewFactory(
 	state *uniter.State,
 	paths context.Paths,
 	contextFactory context.ContextFactory,
+	auditor Auditor,
 ) (
 	Factory, error,
 ) {
@@ -45,6 +47,8 @@ func NewFactory(
 		state:          state,
 		paths:          paths,
 		contextFactory: contextFactory,
+		auditor:        auditor,
+		attempts:       newHookAttempts(),
 	}
 
 	return f, nil
@@ -57,7 +61,9 @@ type factory struct {
 	state *uniter.State
 
 	// Fields that shouldn't change in a factory's lifetime.
-	paths context.Paths
+	paths    context.Paths
+	auditor  Auditor
+	attempts *hookAttempts
 }
 
 // NewCommandRunner exists to satisfy the Factory interface.
@@ -66,7 +72,7 @@ func (f *factory) NewCommandRunner(commandInfo context.CommandInfo) (Runner, err
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	runner := NewRunner(ctx, f.paths)
+	runner := NewAuditedRunner(ctx, f.paths, f.auditor)
 	return runner, nil
 }
 
@@ -80,7 +86,10 @@ func (f *factory) NewHookRunner(hookInfo hook.Info) (Runner, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	runner := NewRunner(ctx, f.paths)
+	runner := newRunner(ctx, f.paths, f.auditor, f.attempts)
+	if hookInfo.Kind == hook.ApplicationHook {
+		runner.hooksDir = hook.ApplicationHooksDir
+	}
 	return runner, nil
 }
 
@@ -120,10 +129,16 @@ func (f *factory) NewActionRunner(actionId string) (Runner, error) {
 	if err := spec.ValidateParams(params); err != nil {
 		return nil, &badActionError{name, err.Error()}
 	}
+	// Defaults are normally inserted when the action is enqueued, but
+	// the charm may have been upgraded since then.
+	params, err = spec.InsertDefaults(params)
+	if err != nil {
+		return nil, &badActionError{name, err.Error()}
+	}
 
 	actionData := context.NewActionData(name, &tag, params)
 	ctx, err := f.contextFactory.ActionContext(actionData)
-	runner := NewRunner(ctx, f.paths)
+	runner := NewAuditedRunner(ctx, f.paths, f.auditor)
 	return runner, nil
 }
 
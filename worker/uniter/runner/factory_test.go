@@ -4,7 +4,9 @@
 package runner_test
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -113,6 +115,21 @@ func (s *FactorySuite) TestNewHookRunner(c *gc.C) {
 	s.AssertPaths(c, rnr)
 }
 
+func (s *FactorySuite) TestNewHookRunnerApplicationHook(c *gc.C) {
+	rnr, err := s.factory.NewHookRunner(hook.Info{
+		Kind:                hook.ApplicationHook,
+		ApplicationHookName: "migrate",
+		CharmURL:            "cs:quantal/wordpress-3",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertPaths(c, rnr)
+	c.Assert(runner.RunnerHooksDir(rnr), gc.Equals, "application-hooks")
+
+	rnr, err = s.factory.NewHookRunner(hook.Info{Kind: hooks.ConfigChanged})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(runner.RunnerHooksDir(rnr), gc.Equals, "hooks")
+}
+
 func (s *FactorySuite) TestNewHookRunnerWithBadHook(c *gc.C) {
 	rnr, err := s.factory.NewHookRunner(hook.Info{})
 	c.Assert(rnr, gc.IsNil)
@@ -174,6 +191,7 @@ func (s *FactorySuite) TestNewHookRunnerWithStorage(c *gc.C) {
 		uniter,
 		s.paths,
 		contextFactory,
+		nil,
 	)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -252,6 +270,36 @@ func (s *FactorySuite) TestNewActionRunnerGood(c *gc.C) {
 	}
 }
 
+func (s *FactorySuite) TestNewActionRunnerInsertsDefaults(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.State.EnqueueAction(s.unit.Tag(), "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Upgrade the charm to one whose action has a new parameter with
+	// a default, which was not known when the action was enqueued.
+	err = ioutil.WriteFile(filepath.Join(s.paths.GetCharmDir(), "actions.yaml"), []byte(`
+snapshot:
+  description: Take a snapshot of the database.
+  params:
+    outfile:
+      type: string
+      default: foo.bz2
+    compression:
+      type: string
+      default: bzip2
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rnr, err := s.factory.NewActionRunner(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := rnr.Context().ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Params, jc.DeepEquals, map[string]interface{}{
+		"outfile":     "foo.bz2",
+		"compression": "bzip2",
+	})
+}
+
 func (s *FactorySuite) TestNewActionRunnerBadCharm(c *gc.C) {
 	rnr, err := s.factory.NewActionRunner("irrelevant")
 	c.Assert(rnr, gc.IsNil)
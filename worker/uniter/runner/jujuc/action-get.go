@@ -31,6 +31,10 @@ func (c *ActionGetCommand) Info() *cmd.Info {
 action-get will print the value of the parameter at the given key, serialized
 as YAML.  If multiple keys are passed, action-get will recurse into the param
 map as needed.
+
+Parameters that were not supplied take the default values declared for them
+in actions.yaml.  With no key, the entire validated parameter map is printed;
+use --format json to consume it from a script.
 `
 	return &cmd.Info{
 		Name:    "action-get",
@@ -119,5 +123,37 @@ func (c *ActionGetCommand) Run(ctx *cmd.Context) error {
 		answer, _ = recurseMapOnKeys(c.keys, params)
 	}
 
-	return c.out.Write(ctx, answer)
+	return c.out.Write(ctx, stringKeyed(answer))
+}
+
+// stringKeyed returns the given value with any nested maps keyed by
+// interface{}, as produced by decoding YAML, converted to maps keyed
+// by string, so that it can be serialized as JSON. Maps with keys
+// that are not strings are left as they are.
+func stringKeyed(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			m[k] = stringKeyed(v)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			key, ok := k.(string)
+			if !ok {
+				return value
+			}
+			m[key] = stringKeyed(v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(typed))
+		for i, v := range typed {
+			s[i] = stringKeyed(v)
+		}
+		return s
+	}
+	return value
 }
@@ -241,6 +241,11 @@ func (s *ActionGetSuite) TestActionGet(c *gc.C) {
 		args:         []string{"--format", "json", "outfile.type"},
 		actionParams: actionGetTestMaps[4],
 		out:          `{"1":"raw","2":"gzip","3":"bzip"}` + "\n",
+	}, {
+		summary:      "an entire map with an inner map keyed by interface{}",
+		args:         []string{"--format", "json"},
+		actionParams: actionGetTestMaps[4],
+		out:          `{"outfile":{"type":{"1":"raw","2":"gzip","3":"bzip"}}}` + "\n",
 	}, {
 		summary: "too many arguments",
 		args:    []string{"multiple", "keys"},
@@ -290,6 +295,10 @@ Details:
 action-get will print the value of the parameter at the given key, serialized
 as YAML.  If multiple keys are passed, action-get will recurse into the param
 map as needed.
+
+Parameters that were not supplied take the default values declared for them
+in actions.yaml.  With no key, the entire validated parameter map is printed;
+use --format json to consume it from a script.
 `)
 	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
 }
@@ -0,0 +1,67 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"strconv"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+)
+
+// ActionProgressCommand implements the action-progress command.
+type ActionProgressCommand struct {
+	cmd.CommandBase
+	ctx     Context
+	message string
+	percent int
+}
+
+// NewActionProgressCommand returns a new ActionProgressCommand with the given context.
+func NewActionProgressCommand(ctx Context) (cmd.Command, error) {
+	return &ActionProgressCommand{ctx: ctx}, nil
+}
+
+// Info returns the content for --help.
+func (c *ActionProgressCommand) Info() *cmd.Info {
+	doc := `
+action-progress reports the progress of a running action, as a message and
+a percentage between 0 and 100. The progress is recorded immediately, so that
+long-running actions may be observed before they complete.
+
+Example:
+    action-progress "copying files" 40
+`
+	return &cmd.Info{
+		Name:    "action-progress",
+		Args:    "\"<message>\" <percent>",
+		Purpose: "report progress of a running action",
+		Doc:     doc,
+	}
+}
+
+// SetFlags handles any option flags, but there are none.
+func (c *ActionProgressCommand) SetFlags(f *gnuflag.FlagSet) {
+}
+
+// Init sets the progress message and percentage, and checks for malformed
+// invocations.
+func (c *ActionProgressCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("no progress message and percentage specified")
+	}
+	percent, err := strconv.Atoi(args[1])
+	if err != nil || percent < 0 || percent > 100 {
+		return errors.Errorf("invalid percentage %q: must be an integer between 0 and 100", args[1])
+	}
+	c.message = args[0]
+	c.percent = percent
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Run reports the Action's progress.
+func (c *ActionProgressCommand) Run(ctx *cmd.Context) error {
+	return c.ctx.SetActionProgress(c.message, c.percent)
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type ActionProgressSuite struct {
+	ContextSuite
+}
+
+type actionProgressContext struct {
+	jujuc.Context
+	message string
+	percent int
+}
+
+func (ctx *actionProgressContext) SetActionProgress(message string, percent int) error {
+	ctx.message = message
+	ctx.percent = percent
+	return nil
+}
+
+type nonActionProgressContext struct {
+	jujuc.Context
+}
+
+func (ctx *nonActionProgressContext) SetActionProgress(message string, percent int) error {
+	return fmt.Errorf("not running an action")
+}
+
+var _ = gc.Suite(&ActionProgressSuite{})
+
+func (s *ActionProgressSuite) TestActionProgress(c *gc.C) {
+	var actionProgressTests = []struct {
+		summary string
+		command []string
+		message string
+		percent int
+		errMsg  string
+		code    int
+	}{{
+		summary: "a message and percentage are reported",
+		command: []string{"copying files", "40"},
+		message: "copying files",
+		percent: 40,
+	}, {
+		summary: "no parameters is an error",
+		command: []string{},
+		errMsg:  "ERROR no progress message and percentage specified\n",
+		code:    2,
+	}, {
+		summary: "a missing percentage is an error",
+		command: []string{"copying files"},
+		errMsg:  "ERROR no progress message and percentage specified\n",
+		code:    2,
+	}, {
+		summary: "a non-numeric percentage is an error",
+		command: []string{"copying files", "lots"},
+		errMsg:  "ERROR invalid percentage \"lots\": must be an integer between 0 and 100\n",
+		code:    2,
+	}, {
+		summary: "an out of range percentage is an error",
+		command: []string{"copying files", "101"},
+		errMsg:  "ERROR invalid percentage \"101\": must be an integer between 0 and 100\n",
+		code:    2,
+	}, {
+		summary: "extra arguments are an error",
+		command: []string{"copying files", "40", "something else"},
+		errMsg:  "ERROR unrecognized args: [\"something else\"]\n",
+		code:    2,
+	}}
+
+	for i, t := range actionProgressTests {
+		c.Logf("test %d: %s", i, t.summary)
+		hctx := &actionProgressContext{}
+		com, err := jujuc.NewCommand(hctx, cmdString("action-progress"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, t.command)
+		c.Check(code, gc.Equals, t.code)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, t.errMsg)
+		c.Check(hctx.message, gc.Equals, t.message)
+		c.Check(hctx.percent, gc.Equals, t.percent)
+	}
+}
+
+func (s *ActionProgressSuite) TestNonActionSetActionProgressFails(c *gc.C) {
+	hctx := &nonActionProgressContext{}
+	com, err := jujuc.NewCommand(hctx, cmdString("action-progress"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"copying files", "40"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR not running an action\n")
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+}
+
+func (s *ActionProgressSuite) TestHelp(c *gc.C) {
+	hctx, _ := s.NewHookContext()
+	com, err := jujuc.NewCommand(hctx, cmdString("action-progress"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, `Usage: action-progress "<message>" <percent>
+
+Summary:
+report progress of a running action
+
+Details:
+action-progress reports the progress of a running action, as a message and
+a percentage between 0 and 100. The progress is recorded immediately, so that
+long-running actions may be observed before they complete.
+
+Example:
+    action-progress "copying files" 40
+`)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+}
@@ -30,6 +30,10 @@ other useful identifier, such as a Git hash, that indicates the
 version of the deployed software. (It shouldn't be confused with the
 charm revision.) The version set will be displayed in "juju status"
 output for the application.
+
+When run by the leader unit, the version is also recorded for the
+application as a whole, and is displayed in preference to the versions
+reported by the individual units.
 `
 	return &cmd.Info{
 		Name:    "application-version-set",
@@ -50,5 +54,21 @@ func (c *applicationVersionSetCommand) Init(args []string) error {
 
 // Run is part of the cmd.Command interface.
 func (c *applicationVersionSetCommand) Run(ctx *cmd.Context) error {
-	return c.ctx.SetUnitWorkloadVersion(c.version)
+	if err := c.ctx.SetUnitWorkloadVersion(c.version); err != nil {
+		return errors.Trace(err)
+	}
+	isLeader, err := c.ctx.IsLeader()
+	if err != nil {
+		return errors.Annotate(err, "cannot determine leadership")
+	}
+	if !isLeader {
+		return nil
+	}
+	err = c.ctx.SetApplicationWorkloadVersion(c.version)
+	if errors.IsNotSupported(err) {
+		// The controller cannot record application versions; the
+		// unit's version will be displayed instead.
+		return nil
+	}
+	return errors.Trace(err)
 }
@@ -48,6 +48,29 @@ func (s *ApplicationVersionSetSuite) TestApplicationVersionSetWithArguments(c *g
 	c.Check(hctx.info.Version.WorkloadVersion, gc.Equals, "dia de los muertos")
 }
 
+func (s *ApplicationVersionSetSuite) TestApplicationVersionSetLeader(c *gc.C) {
+	hctx, com := s.createCommand(c, nil)
+	hctx.info.Leadership.IsLeader = true
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"mysql 5.7.21"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.Version.WorkloadVersion, gc.Equals, "mysql 5.7.21")
+	c.Check(hctx.info.Version.ApplicationWorkloadVersion, gc.Equals, "mysql 5.7.21")
+	s.Stub.CheckCallNames(c, "SetUnitWorkloadVersion", "IsLeader", "SetApplicationWorkloadVersion")
+}
+
+func (s *ApplicationVersionSetSuite) TestApplicationVersionSetLeaderNotSupported(c *gc.C) {
+	hctx, com := s.createCommand(c, nil)
+	hctx.info.Leadership.IsLeader = true
+	s.Stub.SetErrors(nil, nil, errors.NotSupportedf("setting application workload version"))
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"mysql 5.7.21"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(hctx.info.Version.WorkloadVersion, gc.Equals, "mysql 5.7.21")
+}
+
 func (s *ApplicationVersionSetSuite) TestApplicationVersionSetError(c *gc.C) {
 	hctx, com := s.createCommand(c, errors.New("uh oh spaghettio"))
 	ctx := cmdtesting.Context(c)
@@ -73,6 +96,10 @@ other useful identifier, such as a Git hash, that indicates the
 version of the deployed software. (It shouldn't be confused with the
 charm revision.) The version set will be displayed in "juju status"
 output for the application.
+
+When run by the leader unit, the version is also recorded for the
+application as a whole, and is displayed in preference to the versions
+reported by the individual units.
 `[1:]
 
 	_, com := s.createCommand(c, nil)
@@ -28,6 +28,10 @@ func (c *ConfigGetCommand) Info() *cmd.Info {
 When no <key> is supplied, all keys with values or defaults are printed. If
 --all is set, all known keys are printed; those without defaults or values are
 reported as null. <key> and --all are mutually exclusive.
+
+The env and env-powershell formats print the configuration as shell export
+statements or PowerShell assignments respectively, naming each variable after
+its key prefixed by CONFIG_, with "-" and "." replaced by "_".
 `
 	return &cmd.Info{
 		Name:    "config-get",
@@ -38,7 +42,7 @@ reported as null. <key> and --all are mutually exclusive.
 }
 
 func (c *ConfigGetCommand) SetFlags(f *gnuflag.FlagSet) {
-	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	c.out.AddFlags(f, "smart", settingsFormatters(configEnvPrefix))
 	f.BoolVar(&c.All, "a", false, "print all keys")
 	f.BoolVar(&c.All, "all", false, "")
 }
@@ -70,6 +74,8 @@ func (c *ConfigGetCommand) Run(ctx *cmd.Context) error {
 			}
 		}
 		value = settings
+	} else if isEnvFormat(c.out.Name()) {
+		value = map[string]interface{}{c.Key: settings[c.Key]}
 	} else {
 		value, _ = settings[c.Key]
 	}
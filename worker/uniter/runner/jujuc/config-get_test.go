@@ -123,6 +123,76 @@ func (s *ConfigGetSuite) TestOutputFormatAll(c *gc.C) {
 	}
 }
 
+var configGetEnvTests = []struct {
+	args []string
+	out  string
+}{
+	{[]string{"--format", "env"}, `
+export CONFIG_monsters='false'
+export CONFIG_spline_reticulation='45'
+export CONFIG_title='Bob'\''s "Title"'
+export CONFIG_username='admin001'
+`},
+	{[]string{"--format", "env", "--all"}, `
+export CONFIG_empty=''
+export CONFIG_monsters='false'
+export CONFIG_spline_reticulation='45'
+export CONFIG_title='Bob'\''s "Title"'
+export CONFIG_username='admin001'
+`},
+	{[]string{"--format", "env", "title"}, `
+export CONFIG_title='Bob'\''s "Title"'
+`},
+	{[]string{"--format", "env", "missing"}, `
+export CONFIG_missing=''
+`},
+	{[]string{"--format", "env-powershell"}, `
+$env:CONFIG_monsters = 'false'
+$env:CONFIG_spline_reticulation = '45'
+$env:CONFIG_title = 'Bob''s "Title"'
+$env:CONFIG_username = 'admin001'
+`},
+}
+
+func (s *ConfigGetSuite) TestOutputFormatEnv(c *gc.C) {
+	for i, t := range configGetEnvTests {
+		c.Logf("test %d: %#v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		hctx.info.ConfigSettings["title"] = `Bob's "Title"`
+		com, err := jujuc.NewCommand(hctx, cmdString("config-get"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out[1:])
+	}
+}
+
+func (s *ConfigGetSuite) TestOutputFormatEnvConflict(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.ConfigSettings["spline_reticulation"] = 46
+	com, err := jujuc.NewCommand(hctx, cmdString("config-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--format", "env"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Matches,
+		`ERROR keys "spline[-_]reticulation" and "spline[-_]reticulation" both map to environment variable "CONFIG_spline_reticulation"\n`)
+}
+
+func (s *ConfigGetSuite) TestOutputFormatEnvInvalidKey(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.ConfigSettings["bad key"] = "value"
+	com, err := jujuc.NewCommand(hctx, cmdString("config-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--format", "env"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals,
+		"ERROR key \"bad key\" cannot be converted to an environment variable name\n")
+}
+
 func (s *ConfigGetSuite) TestHelp(c *gc.C) {
 	hctx := s.GetHookContext(c, -1, "")
 	com, err := jujuc.NewCommand(hctx, cmdString("config-get"))
@@ -139,7 +209,7 @@ Options:
 -a, --all  (= false)
     print all keys
 --format  (= smart)
-    Specify output format (json|smart|yaml)
+    Specify output format (env|env-powershell|json|smart|yaml)
 -o, --output (= "")
     Specify an output file
 
@@ -147,6 +217,10 @@ Details:
 When no <key> is supplied, all keys with values or defaults are printed. If
 --all is set, all known keys are printed; those without defaults or values are
 reported as null. <key> and --all are mutually exclusive.
+
+The env and env-powershell formats print the configuration as shell export
+statements or PowerShell assignments respectively, naming each variable after
+its key prefixed by CONFIG_, with "-" and "." replaced by "_".
 `)
 	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
 }
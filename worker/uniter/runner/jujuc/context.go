@@ -75,6 +75,11 @@ type relationHookContext interface {
 	// is associated with if it was found, and an error if it was not found or is not
 	// available.
 	RemoteUnitName() (string, error)
+
+	// DepartingUnit returns the name of the unit leaving the relation
+	// in a relation-departed hook, which may be the local unit or the
+	// remote unit. It returns an error if no unit is departing.
+	DepartingUnit() (string, error)
 }
 
 // ActionHookContext is the context for an action hook.
@@ -97,6 +102,12 @@ type actionHookContext interface {
 
 	// SetActionFailed sets a failure state for the Action.
 	SetActionFailed() error
+
+	// SetActionProgress reports the progress of the running Action.
+	SetActionProgress(message string, percent int) error
+
+	// ActionLog appends a message to the logs of the running Action.
+	ActionLog(message string) error
 }
 
 // ContextUnit is the part of a hook context related to the unit.
@@ -106,6 +117,28 @@ type ContextUnit interface {
 
 	// Config returns the current service configuration of the executing unit.
 	ConfigSettings() (charm.Settings, error)
+
+	// GoalState returns the units and relations that the model intends
+	// to exist for the executing unit's application.
+	GoalState() (*params.GoalState, error)
+
+	// CloudSpec returns the cloud that the model is running in. The
+	// cloud credential is only included if the executing unit's
+	// application is trusted.
+	CloudSpec() (*params.CloudSpec, error)
+
+	// SecretGet returns the secrets stored by the executing unit's
+	// charm.
+	SecretGet() (map[string]string, error)
+
+	// SecretSet immediately stores the supplied secrets on behalf of
+	// the executing unit's charm. Secrets with empty values are
+	// removed.
+	SecretSet(map[string]string) error
+
+	// HookEnvironment returns the environment variables, keyed by
+	// name, that are given to the executing hook.
+	HookEnvironment() (map[string]string, error)
 }
 
 // ContextStatus is the part of a hook context related to the unit's status.
@@ -116,6 +149,10 @@ type ContextStatus interface {
 	// SetUnitStatus updates the unit's status.
 	SetUnitStatus(StatusInfo) error
 
+	// UnitStatusHistory returns the latest n entries of the unit's
+	// workload and agent status history, oldest first.
+	UnitStatusHistory(n int) ([]StatusHistoryEntry, error)
+
 	// ApplicationStatus returns the executing unit's service status
 	// (including all units).
 	ApplicationStatus() (ApplicationStatusInfo, error)
@@ -170,6 +207,10 @@ type ContextLeadership interface {
 	// least the next 30s.
 	IsLeader() (bool, error)
 
+	// LeaderLeaseRemaining returns how long the lease backing the local
+	// unit's leadership has left to run, or zero if it is not leader.
+	LeaderLeaseRemaining() (time.Duration, error)
+
 	// LeaderSettings returns the current leader settings. Once leader settings
 	// have been read in a given context, they will not be updated other than
 	// via successful calls to WriteLeaderSettings.
@@ -205,6 +246,11 @@ type ContextStorage interface {
 
 	// AddUnitStorage saves storage constraints in the context.
 	AddUnitStorage(map[string]params.StorageConstraints) error
+
+	// StorageUsage returns the most recently reported usage of the
+	// filesystem storage attachment with the supplied tag, or a
+	// NotFound error if no usage has been reported.
+	StorageUsage(names.StorageTag) (params.FilesystemUsage, error)
 }
 
 // ContextComponents exposes modular Juju components as they relate to
@@ -292,6 +338,10 @@ type ContextVersion interface {
 
 	// SetUnitWorkloadVersion updates the workload version for the unit.
 	SetUnitWorkloadVersion(string) error
+
+	// SetApplicationWorkloadVersion updates the workload version for
+	// the unit's application. Only the leader may do so.
+	SetApplicationWorkloadVersion(string) error
 }
 
 // Settings is implemented by types that manipulate unit settings.
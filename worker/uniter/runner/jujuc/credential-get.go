@@ -0,0 +1,95 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// credentialGetCommand implements the credential-get command.
+type credentialGetCommand struct {
+	cmd.CommandBase
+	ctx Context
+	out cmd.Output
+}
+
+// NewCredentialGetCommand returns a new credentialGetCommand with the
+// given context.
+func NewCredentialGetCommand(ctx Context) (cmd.Command, error) {
+	return &credentialGetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *credentialGetCommand) Info() *cmd.Info {
+	doc := `
+credential-get prints the type, name, region and endpoints of the cloud
+that the model is running in.
+
+The cloud credential used by the model is only printed if a model admin
+has trusted the application with "juju trust".
+`
+	return &cmd.Info{
+		Name:    "credential-get",
+		Purpose: "print the model's cloud and, if trusted, its credential",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *credentialGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init is part of the cmd.Command interface.
+func (c *credentialGetCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run is part of the cmd.Command interface.
+func (c *credentialGetCommand) Run(ctx *cmd.Context) error {
+	spec, err := c.ctx.CloudSpec()
+	if err != nil {
+		return errors.Annotate(err, "cannot get cloud spec")
+	}
+	return c.out.Write(ctx, formatCloudSpec(spec))
+}
+
+// formattedCredential is the serialized form of params.CloudCredential.
+type formattedCredential struct {
+	AuthType   string            `json:"auth-type" yaml:"auth-type"`
+	Attributes map[string]string `json:"attrs,omitempty" yaml:"attrs,omitempty"`
+}
+
+// formattedCloudSpec is the serialized form of params.CloudSpec.
+type formattedCloudSpec struct {
+	Type             string               `json:"type" yaml:"type"`
+	Name             string               `json:"name" yaml:"name"`
+	Region           string               `json:"region,omitempty" yaml:"region,omitempty"`
+	Endpoint         string               `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	IdentityEndpoint string               `json:"identity-endpoint,omitempty" yaml:"identity-endpoint,omitempty"`
+	StorageEndpoint  string               `json:"storage-endpoint,omitempty" yaml:"storage-endpoint,omitempty"`
+	Credential       *formattedCredential `json:"credential,omitempty" yaml:"credential,omitempty"`
+}
+
+func formatCloudSpec(spec *params.CloudSpec) formattedCloudSpec {
+	result := formattedCloudSpec{
+		Type:             spec.Type,
+		Name:             spec.Name,
+		Region:           spec.Region,
+		Endpoint:         spec.Endpoint,
+		IdentityEndpoint: spec.IdentityEndpoint,
+		StorageEndpoint:  spec.StorageEndpoint,
+	}
+	if spec.Credential != nil {
+		result.Credential = &formattedCredential{
+			AuthType:   spec.Credential.AuthType,
+			Attributes: spec.Credential.Attributes,
+		}
+	}
+	return result
+}
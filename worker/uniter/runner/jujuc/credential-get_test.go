@@ -0,0 +1,104 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type CredentialGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&CredentialGetSuite{})
+
+func (s *CredentialGetSuite) createCommand(c *gc.C, credential *params.CloudCredential) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.Unit.CloudSpec = params.CloudSpec{
+		Type:       "ec2",
+		Name:       "aws",
+		Region:     "us-east-1",
+		Endpoint:   "https://ec2.us-east-1.amazonaws.com",
+		Credential: credential,
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("credential-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *CredentialGetSuite) TestOutputFormat(c *gc.C) {
+	expect := map[string]interface{}{
+		"type":     "ec2",
+		"name":     "aws",
+		"region":   "us-east-1",
+		"endpoint": "https://ec2.us-east-1.amazonaws.com",
+		"credential": map[string]interface{}{
+			"auth-type": "access-key",
+			"attrs": map[string]interface{}{
+				"access-key": "key",
+				"secret-key": "secret",
+			},
+		},
+	}
+	for i, t := range []struct {
+		args    []string
+		checker gc.Checker
+	}{
+		{nil, jc.YAMLEquals},
+		{[]string{"--format", "yaml"}, jc.YAMLEquals},
+		{[]string{"--format", "json"}, jc.JSONEquals},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c, &params.CloudCredential{
+			AuthType: "access-key",
+			Attributes: map[string]string{
+				"access-key": "key",
+				"secret-key": "secret",
+			},
+		})
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), t.checker, expect)
+	}
+}
+
+func (s *CredentialGetSuite) TestUntrustedOmitsCredential(c *gc.C) {
+	com := s.createCommand(c, nil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), jc.YAMLEquals, map[string]interface{}{
+		"type":     "ec2",
+		"name":     "aws",
+		"region":   "us-east-1",
+		"endpoint": "https://ec2.us-east-1.amazonaws.com",
+	})
+}
+
+func (s *CredentialGetSuite) TestCloudSpecError(c *gc.C) {
+	com := s.createCommand(c, nil)
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "ERROR cannot get cloud spec: boom\n")
+}
+
+func (s *CredentialGetSuite) TestUnexpectedArgs(c *gc.C) {
+	com := s.createCommand(c, nil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"blah"})
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, `ERROR unrecognized args: ["blah"]`+"\n")
+}
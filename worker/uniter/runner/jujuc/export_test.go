@@ -11,3 +11,7 @@ import (
 func HandleSettingsFile(c *RelationSetCommand, ctx *cmd.Context) error {
 	return c.handleSettingsFile(ctx)
 }
+
+func RedactArgs(commandName string, args []string) []string {
+	return redactArgs(commandName, args)
+}
@@ -0,0 +1,166 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+)
+
+const (
+	// formatEnv is the name of the format that renders settings as
+	// POSIX shell export statements.
+	formatEnv = "env"
+
+	// formatEnvPowerShell is the name of the format that renders
+	// settings as PowerShell environment variable assignments.
+	formatEnvPowerShell = "env-powershell"
+)
+
+const (
+	// configEnvPrefix prefixes the names of the environment variables
+	// holding application configuration.
+	configEnvPrefix = "CONFIG_"
+
+	// relationEnvPrefix prefixes the names of the environment
+	// variables holding relation settings.
+	relationEnvPrefix = "RELATION_"
+)
+
+// settingsFormatters returns the formatters available to commands that
+// print settings. In addition to the default formatters, settings may
+// be rendered as environment variable assignments, so that a charm can
+// load them into a shell with, for example:
+//
+//	eval "$(config-get --format env)"
+//	config-get --format env-powershell | Invoke-Expression
+//
+// Each variable is named after its setting's key, with the given
+// prefix, so that settings cannot replace variables that the hook
+// relies on, such as PATH or JUJU_UNIT_NAME.
+func settingsFormatters(prefix string) map[string]cmd.Formatter {
+	return map[string]cmd.Formatter{
+		"json":  cmd.FormatJson,
+		"smart": cmd.FormatSmart,
+		"yaml":  cmd.FormatYaml,
+		formatEnv: func(writer io.Writer, value interface{}) error {
+			return formatShellEnv(writer, prefix, value)
+		},
+		formatEnvPowerShell: func(writer io.Writer, value interface{}) error {
+			return formatPowerShellEnv(writer, prefix, value)
+		},
+	}
+}
+
+// isEnvFormat reports whether the named format renders settings as
+// environment variable assignments.
+func isEnvFormat(name string) bool {
+	return name == formatEnv || name == formatEnvPowerShell
+}
+
+// formatShellEnv writes the settings in value as POSIX shell export
+// statements, with each value single-quoted.
+func formatShellEnv(writer io.Writer, prefix string, value interface{}) error {
+	return writeEnv(writer, prefix, value, func(name, value string) string {
+		quoted := strings.Replace(value, `'`, `'\''`, -1)
+		return fmt.Sprintf("export %s='%s'\n", name, quoted)
+	})
+}
+
+// formatPowerShellEnv writes the settings in value as PowerShell
+// environment variable assignments, with each value single-quoted.
+func formatPowerShellEnv(writer io.Writer, prefix string, value interface{}) error {
+	return writeEnv(writer, prefix, value, func(name, value string) string {
+		// PowerShell treats typographic single quotes as quotes too.
+		quoted := value
+		for _, quote := range []string{`'`, "‘", "’", "‚", "‛"} {
+			quoted = strings.Replace(quoted, quote, quote+quote, -1)
+		}
+		return fmt.Sprintf("$env:%s = '%s'\n", name, quoted)
+	})
+}
+
+// writeEnv writes an assignment, made by assign, for each of the
+// settings in value, which must be a map with string keys, in order of
+// variable name. Variable names are made from the setting keys by
+// envVarName.
+func writeEnv(writer io.Writer, prefix string, value interface{}, assign func(name, value string) string) error {
+	settings := reflect.ValueOf(value)
+	if settings.Kind() != reflect.Map || settings.Type().Key().Kind() != reflect.String {
+		return errors.Errorf("cannot format %T as environment variables", value)
+	}
+	keys := make(map[string]string)
+	values := make(map[string]string)
+	for _, key := range settings.MapKeys() {
+		name, err := envVarName(prefix, key.String())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if other, ok := keys[name]; ok {
+			return errors.Errorf("keys %q and %q both map to environment variable %q", other, key.String(), name)
+		}
+		v, err := envValue(settings.MapIndex(key).Interface())
+		if err != nil {
+			return errors.Annotatef(err, "key %q", key.String())
+		}
+		keys[name] = key.String()
+		values[name] = v
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := io.WriteString(writer, assign(name, values[name])); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// envVarName returns the environment variable name for the given key:
+// the key, with "-" and "." replaced by "_", following the given
+// prefix. Keys containing any other character that is not valid in a
+// variable name are rejected, as are keys that would make a name
+// starting with a digit.
+func envVarName(prefix, key string) (string, error) {
+	if key == "" {
+		return "", errors.NotValidf("empty key")
+	}
+	name := []rune(prefix + key)
+	if name[0] >= '0' && name[0] <= '9' {
+		return "", errors.Errorf("key %q cannot be converted to an environment variable name", key)
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+		case r == '-' || r == '.':
+			name[i] = '_'
+		default:
+			return "", errors.Errorf("key %q cannot be converted to an environment variable name", key)
+		}
+	}
+	return string(name), nil
+}
+
+// envValue returns the string form of a setting's value. Only scalar
+// values are supported; a nil value is rendered as an empty string.
+func envValue(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case bool, int, int64, float64:
+		return fmt.Sprint(value), nil
+	}
+	return "", errors.Errorf("cannot format %T as an environment variable", value)
+}
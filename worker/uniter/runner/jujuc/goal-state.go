@@ -0,0 +1,104 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// goalStateCommand implements the goal-state command.
+type goalStateCommand struct {
+	cmd.CommandBase
+	ctx Context
+	out cmd.Output
+}
+
+// NewGoalStateCommand returns a new goalStateCommand with the given context.
+func NewGoalStateCommand(ctx Context) (cmd.Command, error) {
+	return &goalStateCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *goalStateCommand) Info() *cmd.Info {
+	doc := `
+goal-state prints the units and relations that the model intends to exist
+for this unit's application, along with their current status.
+
+The "units" section lists the units of the application, including this one.
+The "relations" section lists, for each relation endpoint, the applications
+and units at the other end of its relations.
+
+Units that are being removed are reported with a status of "dying" or "dead".
+`
+	return &cmd.Info{
+		Name:    "goal-state",
+		Purpose: "print the status of the charm's peers and related units",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *goalStateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init is part of the cmd.Command interface.
+func (c *goalStateCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run is part of the cmd.Command interface.
+func (c *goalStateCommand) Run(ctx *cmd.Context) error {
+	goalState, err := c.ctx.GoalState()
+	if err != nil {
+		return errors.Annotate(err, "cannot get goal state")
+	}
+	return c.out.Write(ctx, formatGoalState(goalState))
+}
+
+// goalStateStatus is the serialized form of params.GoalStateStatus.
+type goalStateStatus struct {
+	Status string `json:"status" yaml:"status"`
+	Since  string `json:"since,omitempty" yaml:"since,omitempty"`
+}
+
+type unitsGoalState map[string]goalStateStatus
+
+// formattedGoalState is the serialized form of params.GoalState.
+type formattedGoalState struct {
+	Units     unitsGoalState            `json:"units" yaml:"units"`
+	Relations map[string]unitsGoalState `json:"relations" yaml:"relations"`
+}
+
+func formatGoalState(goalState *params.GoalState) formattedGoalState {
+	result := formattedGoalState{
+		Units:     formatUnitsGoalState(goalState.Units),
+		Relations: make(map[string]unitsGoalState, len(goalState.Relations)),
+	}
+	for endpoint, units := range goalState.Relations {
+		result.Relations[endpoint] = formatUnitsGoalState(units)
+	}
+	return result
+}
+
+func formatUnitsGoalState(units params.UnitsGoalState) unitsGoalState {
+	result := make(unitsGoalState, len(units))
+	for name, unitStatus := range units {
+		var since string
+		if unitStatus.Since != nil {
+			since = unitStatus.Since.UTC().Format(time.RFC3339)
+		}
+		result[name] = goalStateStatus{
+			Status: unitStatus.Status,
+			Since:  since,
+		}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type GoalStateSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&GoalStateSuite{})
+
+func (s *GoalStateSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	since := time.Date(2017, 10, 1, 12, 30, 0, 0, time.UTC)
+	hctx.info.Unit.GoalState = params.GoalState{
+		Units: params.UnitsGoalState{
+			"u/0": {Status: "active", Since: &since},
+			"u/1": {Status: "waiting"},
+		},
+		Relations: map[string]params.UnitsGoalState{
+			"db": {
+				"mysql":   {Status: "joined"},
+				"mysql/0": {Status: "dying", Since: &since},
+			},
+		},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("goal-state"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *GoalStateSuite) TestOutputFormat(c *gc.C) {
+	expect := map[string]interface{}{
+		"units": map[string]interface{}{
+			"u/0": map[string]interface{}{"status": "active", "since": "2017-10-01T12:30:00Z"},
+			"u/1": map[string]interface{}{"status": "waiting"},
+		},
+		"relations": map[string]interface{}{
+			"db": map[string]interface{}{
+				"mysql":   map[string]interface{}{"status": "joined"},
+				"mysql/0": map[string]interface{}{"status": "dying", "since": "2017-10-01T12:30:00Z"},
+			},
+		},
+	}
+	for i, t := range []struct {
+		args    []string
+		checker gc.Checker
+	}{
+		{nil, jc.YAMLEquals},
+		{[]string{"--format", "yaml"}, jc.YAMLEquals},
+		{[]string{"--format", "json"}, jc.JSONEquals},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), t.checker, expect)
+	}
+}
+
+func (s *GoalStateSuite) TestGoalStateError(c *gc.C) {
+	com := s.createCommand(c)
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "ERROR cannot get goal state: boom\n")
+}
+
+func (s *GoalStateSuite) TestUnexpectedArgs(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"blah"})
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, `ERROR unrecognized args: ["blah"]`+"\n")
+}
@@ -0,0 +1,80 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+)
+
+// hookEnvCommand implements the hook-env command.
+type hookEnvCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	name string
+	out  cmd.Output
+}
+
+// NewHookEnvCommand returns a new hookEnvCommand with the given context.
+func NewHookEnvCommand(ctx Context) (cmd.Command, error) {
+	return &hookEnvCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *hookEnvCommand) Info() *cmd.Info {
+	doc := `
+hook-env prints the environment variables that Juju gives to the executing
+hook: the JUJU_* variables describing the context, the proxy settings and
+the paths. If a variable name is given, only that variable is printed.
+
+By default the variables are printed as shell export statements, so that a
+juju debug-hooks session can recreate the hook's environment with:
+
+    eval "$(hook-env)"
+
+On Windows, hooks also inherit the environment of the unit agent, which is
+not printed.
+`
+	return &cmd.Info{
+		Name:    "hook-env",
+		Args:    "[<name>]",
+		Purpose: "print the hook's environment",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *hookEnvCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, formatEnv, settingsFormatters(""))
+}
+
+// Init is part of the cmd.Command interface.
+func (c *hookEnvCommand) Init(args []string) error {
+	c.name = ""
+	if len(args) == 0 {
+		return nil
+	}
+	c.name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run is part of the cmd.Command interface.
+func (c *hookEnvCommand) Run(ctx *cmd.Context) error {
+	env, err := c.ctx.HookEnvironment()
+	if err != nil {
+		return errors.Annotate(err, "cannot read hook environment")
+	}
+	if c.name == "" {
+		return c.out.Write(ctx, env)
+	}
+	value, ok := env[c.name]
+	if !ok {
+		return c.out.Write(ctx, nil)
+	}
+	if isEnvFormat(c.out.Name()) {
+		return c.out.Write(ctx, map[string]string{c.name: value})
+	}
+	return c.out.Write(ctx, value)
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type HookEnvSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&HookEnvSuite{})
+
+func (s *HookEnvSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.Unit.Environment = map[string]string{
+		"JUJU_UNIT_NAME": "u/0",
+		"JUJU_CHARM_DIR": "/var/lib/juju/agents/unit-u-0/charm",
+		"http_proxy":     "http://proxy.invalid:3128",
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("hook-env"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *HookEnvSuite) TestInitError(c *gc.C) {
+	com := s.createCommand(c)
+	err := cmdtesting.InitCommand(com, []string{"JUJU_UNIT_NAME", "extra"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *HookEnvSuite) TestPrintAll(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, ""+
+		"export JUJU_CHARM_DIR='/var/lib/juju/agents/unit-u-0/charm'\n"+
+		"export JUJU_UNIT_NAME='u/0'\n"+
+		"export http_proxy='http://proxy.invalid:3128'\n",
+	)
+}
+
+func (s *HookEnvSuite) TestPrintAllYAML(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--format", "yaml"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stdout), jc.YAMLEquals, map[string]interface{}{
+		"JUJU_UNIT_NAME": "u/0",
+		"JUJU_CHARM_DIR": "/var/lib/juju/agents/unit-u-0/charm",
+		"http_proxy":     "http://proxy.invalid:3128",
+	})
+}
+
+func (s *HookEnvSuite) TestPrintName(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		output string
+	}{{
+		args:   []string{"JUJU_UNIT_NAME"},
+		output: "export JUJU_UNIT_NAME='u/0'\n",
+	}, {
+		args:   []string{"JUJU_UNIT_NAME", "--format", "smart"},
+		output: "u/0\n",
+	}, {
+		args:   []string{"JUJU_RELATION", "--format", "smart"},
+		output: "",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		com := s.createCommand(c)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, test.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), gc.Equals, test.output)
+	}
+}
+
+func (s *HookEnvSuite) TestHookEnvironmentError(c *gc.C) {
+	com := s.createCommand(c)
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "ERROR cannot read hook environment: boom\n")
+}
@@ -4,6 +4,8 @@
 package jujuc
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
@@ -12,8 +14,9 @@ import (
 // isLeaderCommand implements the is-leader command.
 type isLeaderCommand struct {
 	cmd.CommandBase
-	ctx Context
-	out cmd.Output
+	ctx       Context
+	out       cmd.Output
+	remaining bool
 }
 
 // NewIsLeaderCommand returns a new isLeaderCommand with the given context.
@@ -27,6 +30,9 @@ func (c *isLeaderCommand) Info() *cmd.Info {
 is-leader prints a boolean indicating whether the local unit is guaranteed to
 be application leader for at least 30 seconds. If it fails, you should assume that
 there is no such guarantee.
+
+With --remaining, is-leader instead prints the number of whole seconds left on
+the lease backing the local unit's leadership, or 0 if it is not leader.
 `
 	return &cmd.Info{
 		Name:    "is-leader",
@@ -38,10 +44,18 @@ there is no such guarantee.
 // SetFlags is part of the cmd.Command interface.
 func (c *isLeaderCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.remaining, "remaining", false, "print the seconds remaining on the leadership lease")
 }
 
 // Run is part of the cmd.Command interface.
 func (c *isLeaderCommand) Run(ctx *cmd.Context) error {
+	if c.remaining {
+		remaining, err := c.ctx.LeaderLeaseRemaining()
+		if err != nil {
+			return errors.Annotatef(err, "leadership status unknown")
+		}
+		return c.out.Write(ctx, int(remaining/time.Second))
+	}
 	success, err := c.ctx.IsLeader()
 	if err != nil {
 		return errors.Annotatef(err, "leadership status unknown")
@@ -4,6 +4,8 @@
 package jujuc_test
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
@@ -88,6 +90,28 @@ func (s *isLeaderSuite) TestFormatJsonNo(c *gc.C) {
 	s.testParseOutput(c, false, []string{"--format", "json"}, jc.JSONEquals)
 }
 
+func (s *isLeaderSuite) TestRemaining(c *gc.C) {
+	jujucContext := &isLeaderContext{remaining: 42500 * time.Millisecond}
+	command, err := jujuc.NewIsLeaderCommand(jujucContext)
+	c.Assert(err, jc.ErrorIsNil)
+	runContext := cmdtesting.Context(c)
+	code := cmd.Main(command, runContext, []string{"--remaining"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(jujucContext.called, jc.IsFalse)
+	c.Check(bufferString(runContext.Stdout), gc.Equals, "42\n")
+	c.Check(bufferString(runContext.Stderr), gc.Equals, "")
+}
+
+func (s *isLeaderSuite) TestRemainingError(c *gc.C) {
+	jujucContext := &isLeaderContext{err: errors.New("pow")}
+	command, err := jujuc.NewIsLeaderCommand(jujucContext)
+	c.Assert(err, jc.ErrorIsNil)
+	runContext := cmdtesting.Context(c)
+	code := cmd.Main(command, runContext, []string{"--remaining"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(runContext.Stderr), gc.Equals, "ERROR leadership status unknown: pow\n")
+}
+
 func (s *isLeaderSuite) testOutput(c *gc.C, leader bool, args []string, expect string) {
 	jujucContext := &isLeaderContext{leader: leader}
 	command, err := jujuc.NewIsLeaderCommand(jujucContext)
@@ -114,12 +138,17 @@ func (s *isLeaderSuite) testParseOutput(c *gc.C, leader bool, args []string, che
 
 type isLeaderContext struct {
 	jujuc.Context
-	called bool
-	leader bool
-	err    error
+	called    bool
+	leader    bool
+	remaining time.Duration
+	err       error
 }
 
 func (ctx *isLeaderContext) IsLeader() (bool, error) {
 	ctx.called = true
 	return ctx.leader, ctx.err
 }
+
+func (ctx *isLeaderContext) LeaderLeaseRemaining() (time.Duration, error) {
+	return ctx.remaining, ctx.err
+}
@@ -20,6 +20,7 @@ type JujuLogCommand struct {
 	Message    string
 	Debug      bool
 	Level      string
+	Action     bool
 	formatFlag string // deprecated
 }
 
@@ -39,6 +40,7 @@ func (c *JujuLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Debug, "debug", false, "log at debug level")
 	f.StringVar(&c.Level, "l", "INFO", "Send log message at the given level")
 	f.StringVar(&c.Level, "log-level", "INFO", "")
+	f.BoolVar(&c.Action, "action", false, "also append the message to the running action's log")
 	f.StringVar(&c.formatFlag, "format", "", "deprecated format flag")
 }
 
@@ -76,5 +78,8 @@ func (c *JujuLogCommand) Run(ctx *cmd.Context) error {
 	}
 
 	logger.Logf(logLevel, "%s%s", prefix, c.Message)
+	if c.Action {
+		return errors.Trace(c.ctx.ActionLog(c.Message))
+	}
 	return nil
 }
@@ -53,3 +53,19 @@ func (s *JujuLogSuite) TestLogDeprecation(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "--format flag deprecated for command \"juju-log\"")
 }
+
+func (s *JujuLogSuite) TestLogAction(c *gc.C) {
+	ctx, info := s.newHookContext(-1, "")
+	info.ActionParams = map[string]interface{}{}
+	com, err := jujuc.NewCommand(ctx, cmdString("juju-log"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cmdtesting.RunCommand(c, com, "--action", "copying", "files")
+	c.Assert(err, jc.ErrorIsNil)
+	s.Stub.CheckCall(c, len(s.Stub.Calls())-1, "ActionLog", "copying files")
+}
+
+func (s *JujuLogSuite) TestLogActionNotRunning(c *gc.C) {
+	com := s.newJujuLogCommand(c)
+	_, err := cmdtesting.RunCommand(c, com, "--action", "msg")
+	c.Assert(err, gc.ErrorMatches, "not running an action")
+}
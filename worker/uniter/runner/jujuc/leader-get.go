@@ -5,6 +5,7 @@ package jujuc
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -14,9 +15,10 @@ import (
 // leaderGetCommand implements the leader-get command.
 type leaderGetCommand struct {
 	cmd.CommandBase
-	ctx Context
-	key string
-	out cmd.Output
+	ctx       Context
+	key       string
+	out       cmd.Output
+	leaseInfo bool
 }
 
 // NewLeaderGetCommand returns a new leaderGetCommand with the given context.
@@ -29,6 +31,10 @@ func (c *leaderGetCommand) Info() *cmd.Info {
 	doc := `
 leader-get prints the value of a leadership setting specified by key. If no key
 is given, or if the key is "-", all keys and values will be printed.
+
+With --lease-info, leader-get instead prints whether the local unit is leader,
+and the number of whole seconds left on the lease backing its leadership, so
+that charms can avoid starting long operations just before the lease lapses.
 `
 	return &cmd.Info{
 		Name:    "leader-get",
@@ -41,11 +47,15 @@ is given, or if the key is "-", all keys and values will be printed.
 // SetFlags is part of the cmd.Command interface.
 func (c *leaderGetCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.leaseInfo, "lease-info", false, "print leadership lease information instead of settings")
 }
 
 // Init is part of the cmd.Command interface.
 func (c *leaderGetCommand) Init(args []string) error {
 	c.key = ""
+	if c.leaseInfo {
+		return cmd.CheckEmpty(args)
+	}
 	if len(args) == 0 {
 		return nil
 	}
@@ -61,6 +71,9 @@ func (c *leaderGetCommand) Init(args []string) error {
 
 // Run is part of the cmd.Command interface.
 func (c *leaderGetCommand) Run(ctx *cmd.Context) error {
+	if c.leaseInfo {
+		return c.writeLeaseInfo(ctx)
+	}
 	settings, err := c.ctx.LeaderSettings()
 	if err != nil {
 		return errors.Annotatef(err, "cannot read leadership settings")
@@ -73,3 +86,18 @@ func (c *leaderGetCommand) Run(ctx *cmd.Context) error {
 	}
 	return c.out.Write(ctx, nil)
 }
+
+func (c *leaderGetCommand) writeLeaseInfo(ctx *cmd.Context) error {
+	isLeader, err := c.ctx.IsLeader()
+	if err != nil {
+		return errors.Annotatef(err, "leadership status unknown")
+	}
+	remaining, err := c.ctx.LeaderLeaseRemaining()
+	if err != nil {
+		return errors.Annotatef(err, "leadership status unknown")
+	}
+	return c.out.Write(ctx, map[string]interface{}{
+		"leader":          isLeader,
+		"lease-remaining": int(remaining / time.Second),
+	})
+}
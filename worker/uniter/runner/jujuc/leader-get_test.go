@@ -4,6 +4,8 @@
 package jujuc_test
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
@@ -132,6 +134,52 @@ func (s *leaderGetSuite) TestSettingsFormatYAMLEmpty(c *gc.C) {
 	s.testParseOutput(c, []string{"--format", "yaml"}, jc.YAMLEquals, leaderGetSettings())
 }
 
+func (s *leaderGetSuite) TestLeaseInfoWithKey(c *gc.C) {
+	runContext := cmdtesting.Context(c)
+	code := cmd.Main(s.command, runContext, []string{"--lease-info", "key"})
+	c.Check(code, gc.Equals, 2)
+	c.Check(bufferString(runContext.Stderr), gc.Equals, `ERROR unrecognized args: ["key"]`+"\n")
+}
+
+func (s *leaderGetSuite) TestLeaseInfoLeader(c *gc.C) {
+	jujucContext := &leaderGetContext{leader: true, remaining: 42500 * time.Millisecond}
+	s.testLeaseInfo(c, jujucContext, []string{"--lease-info"}, jc.YAMLEquals, map[string]interface{}{
+		"leader":          true,
+		"lease-remaining": 42,
+	})
+}
+
+func (s *leaderGetSuite) TestLeaseInfoMinionJSON(c *gc.C) {
+	jujucContext := &leaderGetContext{}
+	s.testLeaseInfo(c, jujucContext, []string{"--lease-info", "--format", "json"}, jc.JSONEquals, map[string]interface{}{
+		"leader":          false,
+		"lease-remaining": 0,
+	})
+}
+
+func (s *leaderGetSuite) TestLeaseInfoError(c *gc.C) {
+	jujucContext := &leaderGetContext{err: errors.New("pow")}
+	command, err := jujuc.NewLeaderGetCommand(jujucContext)
+	c.Assert(err, jc.ErrorIsNil)
+	runContext := cmdtesting.Context(c)
+	code := cmd.Main(command, runContext, []string{"--lease-info"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(jujucContext.called, jc.IsFalse)
+	c.Check(bufferString(runContext.Stdout), gc.Equals, "")
+	c.Check(bufferString(runContext.Stderr), gc.Equals, "ERROR leadership status unknown: pow\n")
+}
+
+func (s *leaderGetSuite) testLeaseInfo(c *gc.C, jujucContext *leaderGetContext, args []string, checker gc.Checker, expect interface{}) {
+	command, err := jujuc.NewLeaderGetCommand(jujucContext)
+	c.Assert(err, jc.ErrorIsNil)
+	runContext := cmdtesting.Context(c)
+	code := cmd.Main(command, runContext, args)
+	c.Check(code, gc.Equals, 0)
+	c.Check(jujucContext.called, jc.IsFalse)
+	c.Check(bufferString(runContext.Stdout), checker, expect)
+	c.Check(bufferString(runContext.Stderr), gc.Equals, "")
+}
+
 func (s *leaderGetSuite) testOutput(c *gc.C, args []string, expect string) {
 	s.testParseOutput(c, args, gc.Equals, expect)
 }
@@ -164,12 +212,22 @@ func newLeaderGetContext(err error) *leaderGetContext {
 
 type leaderGetContext struct {
 	jujuc.Context
-	called   bool
-	settings map[string]string
-	err      error
+	called    bool
+	settings  map[string]string
+	leader    bool
+	remaining time.Duration
+	err       error
 }
 
 func (c *leaderGetContext) LeaderSettings() (map[string]string, error) {
 	c.called = true
 	return c.settings, c.err
 }
+
+func (c *leaderGetContext) IsLeader() (bool, error) {
+	return c.leader, c.err
+}
+
+func (c *leaderGetContext) LeaderLeaseRemaining() (time.Duration, error) {
+	return c.remaining, c.err
+}
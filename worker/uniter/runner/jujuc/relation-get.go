@@ -21,9 +21,10 @@ type RelationGetCommand struct {
 	RelationId      int
 	relationIdProxy gnuflag.Value
 
-	Key      string
-	UnitName string
-	out      cmd.Output
+	Key       string
+	UnitName  string
+	Departing bool
+	out       cmd.Output
 }
 
 func NewRelationGetCommand(ctx Context) (cmd.Command, error) {
@@ -43,6 +44,12 @@ func (c *RelationGetCommand) Info() *cmd.Info {
 	doc := `
 relation-get prints the value of a unit's relation setting, specified by key.
 If no key is given, or if the key is "-", all keys and values will be printed.
+In a relation-departed hook, --departing prints the name of the unit that is
+leaving the relation instead; this is the local unit if it is the one leaving.
+The env and env-powershell formats print the settings as shell export
+statements or PowerShell assignments respectively, naming each variable after
+its key prefixed by RELATION_, with "-" and "." replaced by "_". With
+--departing, the departing unit is printed as RELATION_departing_unit.
 `
 	// There's nothing we can really do about the error here.
 	if name, err := c.ctx.RemoteUnitName(); err == nil {
@@ -61,13 +68,17 @@ If no key is given, or if the key is "-", all keys and values will be printed.
 
 // SetFlags is part of the cmd.Command interface.
 func (c *RelationGetCommand) SetFlags(f *gnuflag.FlagSet) {
-	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	c.out.AddFlags(f, "smart", settingsFormatters(relationEnvPrefix))
 	f.Var(c.relationIdProxy, "r", "specify a relation by id")
 	f.Var(c.relationIdProxy, "relation", "")
+	f.BoolVar(&c.Departing, "departing", false, "print the name of the departing unit")
 }
 
 // Init is part of the cmd.Command interface.
 func (c *RelationGetCommand) Init(args []string) error {
+	if c.Departing {
+		return cmd.CheckEmpty(args)
+	}
 	if c.RelationId == -1 {
 		return fmt.Errorf("no relation id specified")
 	}
@@ -95,6 +106,16 @@ func (c *RelationGetCommand) Init(args []string) error {
 }
 
 func (c *RelationGetCommand) Run(ctx *cmd.Context) error {
+	if c.Departing {
+		name, err := c.ctx.DepartingUnit()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if isEnvFormat(c.out.Name()) {
+			return c.out.Write(ctx, params.Settings{"departing-unit": name})
+		}
+		return c.out.Write(ctx, name)
+	}
 	r, err := c.ctx.Relation(c.RelationId)
 	if err != nil {
 		return errors.Trace(err)
@@ -116,6 +137,9 @@ func (c *RelationGetCommand) Run(ctx *cmd.Context) error {
 	if c.Key == "" {
 		return c.out.Write(ctx, settings)
 	}
+	if isEnvFormat(c.out.Name()) {
+		return c.out.Write(ctx, params.Settings{c.Key: settings[c.Key]})
+	}
 	if value, ok := settings[c.Key]; ok {
 		return c.out.Write(ctx, value)
 	}
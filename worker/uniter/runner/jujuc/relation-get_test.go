@@ -137,6 +137,27 @@ var relationGetTests = []struct {
 		relid:   1,
 		args:    []string{"missing", "u/1", "--format", "smart"},
 		out:     "",
+	}, {
+		summary: "env formatting all keys",
+		relid:   0,
+		args:    []string{"-", "u/0", "--format", "env"},
+		out:     "export RELATION_private_address='foo: bar\n'",
+	}, {
+		summary: "env formatting specific key",
+		relid:   1,
+		unit:    "m/0",
+		args:    []string{"pew", "--format", "env"},
+		out:     "export RELATION_pew='pew\npew\n'",
+	}, {
+		summary: "env formatting missing key",
+		relid:   1,
+		args:    []string{"missing", "u/1", "--format", "env"},
+		out:     "export RELATION_missing=''",
+	}, {
+		summary: "env-powershell formatting",
+		relid:   1,
+		args:    []string{"-", "u/1", "--format", "env-powershell"},
+		out:     "$env:RELATION_value = '12345'",
 	},
 }
 
@@ -222,8 +243,10 @@ Summary:
 get relation settings
 
 Options:
+--departing  (= false)
+    print the name of the departing unit
 --format  (= smart)
-    Specify output format (json|smart|yaml)
+    Specify output format (env|env-powershell|json|smart|yaml)
 -o, --output (= "")
     Specify an output file
 -r, --relation  (= %s)
@@ -232,6 +255,12 @@ Options:
 Details:
 relation-get prints the value of a unit's relation setting, specified by key.
 If no key is given, or if the key is "-", all keys and values will be printed.
+In a relation-departed hook, --departing prints the name of the unit that is
+leaving the relation instead; this is the local unit if it is the one leaving.
+The env and env-powershell formats print the settings as shell export
+statements or PowerShell assignments respectively, naming each variable after
+its key prefixed by RELATION_, with "-" and "." replaced by "_". With
+--departing, the departing unit is printed as RELATION_departing_unit.
 %s`[1:]
 
 var relationGetHelpTests = []struct {
@@ -278,6 +307,50 @@ func (s *RelationGetSuite) TestHelp(c *gc.C) {
 	}
 }
 
+func (s *RelationGetSuite) TestDeparting(c *gc.C) {
+	hctx, info := s.newHookContext(1, "m/0")
+	info.DepartingUnit = "u/0"
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--departing"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "u/0\n")
+}
+
+func (s *RelationGetSuite) TestDepartingFormatEnv(c *gc.C) {
+	hctx, info := s.newHookContext(1, "m/0")
+	info.DepartingUnit = "u/0"
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--departing", "--format", "env"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "export RELATION_departing_unit='u/0'\n")
+}
+
+func (s *RelationGetSuite) TestDepartingNotDeparted(c *gc.C) {
+	hctx, _ := s.newHookContext(1, "m/0")
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--departing"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "ERROR departing unit not found\n")
+}
+
+func (s *RelationGetSuite) TestDepartingWithArgs(c *gc.C) {
+	hctx, _ := s.newHookContext(1, "m/0")
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--departing", "pew"})
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(bufferString(ctx.Stderr), gc.Matches, `(.|\n)*ERROR unrecognized args: \["pew"\]\n`)
+}
+
 func (s *RelationGetSuite) TestOutputPath(c *gc.C) {
 	hctx, _ := s.newHookContext(1, "m/0")
 	com, err := jujuc.NewCommand(hctx, cmdString("relation-get"))
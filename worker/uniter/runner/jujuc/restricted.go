@@ -25,12 +25,34 @@ type RestrictedContext struct{}
 // ConfigSettings implements jujuc.Context.
 func (*RestrictedContext) ConfigSettings() (charm.Settings, error) { return nil, ErrRestrictedContext }
 
+// GoalState implements jujuc.Context.
+func (*RestrictedContext) GoalState() (*params.GoalState, error) { return nil, ErrRestrictedContext }
+
+// CloudSpec implements jujuc.Context.
+func (*RestrictedContext) CloudSpec() (*params.CloudSpec, error) { return nil, ErrRestrictedContext }
+
+// SecretGet implements jujuc.Context.
+func (*RestrictedContext) SecretGet() (map[string]string, error) { return nil, ErrRestrictedContext }
+
+// SecretSet implements jujuc.Context.
+func (*RestrictedContext) SecretSet(map[string]string) error { return ErrRestrictedContext }
+
+// HookEnvironment implements jujuc.Context.
+func (*RestrictedContext) HookEnvironment() (map[string]string, error) {
+	return nil, ErrRestrictedContext
+}
+
 // UnitStatus implements jujuc.Context.
 func (*RestrictedContext) UnitStatus() (*StatusInfo, error) { return nil, ErrRestrictedContext }
 
 // SetUnitStatus implements jujuc.Context.
 func (*RestrictedContext) SetUnitStatus(StatusInfo) error { return ErrRestrictedContext }
 
+// UnitStatusHistory implements jujuc.Context.
+func (*RestrictedContext) UnitStatusHistory(int) ([]StatusHistoryEntry, error) {
+	return nil, ErrRestrictedContext
+}
+
 // ApplicationStatus implements jujuc.Context.
 func (*RestrictedContext) ApplicationStatus() (ApplicationStatusInfo, error) {
 	return ApplicationStatusInfo{}, ErrRestrictedContext
@@ -77,6 +99,11 @@ func (*RestrictedContext) NetworkInfo(bindingNames []string) (map[string]params.
 // IsLeader implements jujuc.Context.
 func (*RestrictedContext) IsLeader() (bool, error) { return false, ErrRestrictedContext }
 
+// LeaderLeaseRemaining implements jujuc.Context.
+func (*RestrictedContext) LeaderLeaseRemaining() (time.Duration, error) {
+	return 0, ErrRestrictedContext
+}
+
 // LeaderSettings implements jujuc.Context.
 func (*RestrictedContext) LeaderSettings() (map[string]string, error) {
 	return nil, ErrRestrictedContext
@@ -106,6 +133,11 @@ func (*RestrictedContext) AddUnitStorage(map[string]params.StorageConstraints) e
 	return ErrRestrictedContext
 }
 
+// StorageUsage implements jujuc.Context.
+func (*RestrictedContext) StorageUsage(names.StorageTag) (params.FilesystemUsage, error) {
+	return params.FilesystemUsage{}, ErrRestrictedContext
+}
+
 // Relation implements jujuc.Context.
 func (*RestrictedContext) Relation(id int) (ContextRelation, error) {
 	return nil, ErrRestrictedContext
@@ -122,6 +154,9 @@ func (*RestrictedContext) HookRelation() (ContextRelation, error) {
 // RemoteUnitName implements jujuc.Context.
 func (*RestrictedContext) RemoteUnitName() (string, error) { return "", ErrRestrictedContext }
 
+// DepartingUnit implements jujuc.Context.
+func (*RestrictedContext) DepartingUnit() (string, error) { return "", ErrRestrictedContext }
+
 // ActionParams implements jujuc.Context.
 func (*RestrictedContext) ActionParams() (map[string]interface{}, error) {
 	return nil, ErrRestrictedContext
@@ -138,6 +173,12 @@ func (*RestrictedContext) SetActionMessage(string) error { return ErrRestrictedC
 // SetActionFailed implements jujuc.Context.
 func (*RestrictedContext) SetActionFailed() error { return ErrRestrictedContext }
 
+// SetActionProgress implements jujuc.Context.
+func (*RestrictedContext) SetActionProgress(string, int) error { return ErrRestrictedContext }
+
+// ActionLog implements jujuc.Context.
+func (*RestrictedContext) ActionLog(string) error { return ErrRestrictedContext }
+
 // Component implements jujc.Context.
 func (*RestrictedContext) Component(string) (ContextComponent, error) {
 	return nil, ErrRestrictedContext
@@ -152,3 +193,8 @@ func (*RestrictedContext) UnitWorkloadVersion() (string, error) {
 func (*RestrictedContext) SetUnitWorkloadVersion(string) error {
 	return ErrRestrictedContext
 }
+
+// SetApplicationWorkloadVersion implements jujuc.Context.
+func (*RestrictedContext) SetApplicationWorkloadVersion(string) error {
+	return ErrRestrictedContext
+}
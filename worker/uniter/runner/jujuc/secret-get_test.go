@@ -0,0 +1,80 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type SecretGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&SecretGetSuite{})
+
+func (s *SecretGetSuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetHookContext(c, -1, "")
+	hctx.info.Unit.Secrets = map[string]string{
+		"api-key":  "s3kr1t",
+		"password": "hunter2",
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("secret-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *SecretGetSuite) TestInitError(c *gc.C) {
+	com := s.createCommand(c)
+	err := cmdtesting.InitCommand(com, []string{"x=x"})
+	c.Assert(err, gc.ErrorMatches, `invalid key "x=x"`)
+}
+
+func (s *SecretGetSuite) TestGetAll(c *gc.C) {
+	for i, args := range [][]string{nil, {"-"}} {
+		c.Logf("test %d: %v", i, args)
+		com := s.createCommand(c)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, append(args, "--format", "yaml"))
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), jc.YAMLEquals, map[string]interface{}{
+			"api-key":  "s3kr1t",
+			"password": "hunter2",
+		})
+	}
+}
+
+func (s *SecretGetSuite) TestGetKey(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"password"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "hunter2\n")
+}
+
+func (s *SecretGetSuite) TestGetMissingKey(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"token"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "")
+}
+
+func (s *SecretGetSuite) TestSecretGetError(c *gc.C) {
+	com := s.createCommand(c)
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "ERROR cannot read secrets: boom\n")
+}
@@ -0,0 +1,56 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+)
+
+// secretSetCommand implements the secret-set command.
+type secretSetCommand struct {
+	cmd.CommandBase
+	ctx     Context
+	secrets map[string]string
+}
+
+// NewSecretSetCommand returns a new secretSetCommand with the given context.
+func NewSecretSetCommand(ctx Context) (cmd.Command, error) {
+	return &secretSetCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *secretSetCommand) Info() *cmd.Info {
+	doc := `
+secret-set immediately stores the supplied key/value pairs with the controller,
+on behalf of this unit. Setting a key to an empty value removes it. Secrets can
+later be read back with secret-get, but are never revealed to other units, or
+written to relation settings or application config.
+
+Keys must start with a letter or digit, and contain only letters, digits,
+"-" and "_".
+`
+	return &cmd.Info{
+		Name:    "secret-set",
+		Args:    "<key>=<value> [...]",
+		Purpose: "store unit secrets",
+		Doc:     doc,
+	}
+}
+
+// Init is part of the cmd.Command interface.
+func (c *secretSetCommand) Init(args []string) (err error) {
+	if len(args) == 0 {
+		return errors.New("no secrets specified")
+	}
+	c.secrets, err = keyvalues.Parse(args, true)
+	return
+}
+
+// Run is part of the cmd.Command interface.
+func (c *secretSetCommand) Run(_ *cmd.Context) error {
+	err := c.ctx.SecretSet(c.secrets)
+	return errors.Annotatef(err, "cannot store secrets")
+}
@@ -15,7 +15,9 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -46,13 +48,19 @@ func RegisterCommand(name string, f creator) {
 var baseCommands = map[string]creator{
 	"close-port" + cmdSuffix:              NewClosePortCommand,
 	"config-get" + cmdSuffix:              NewConfigGetCommand,
+	"credential-get" + cmdSuffix:          NewCredentialGetCommand,
+	"goal-state" + cmdSuffix:              NewGoalStateCommand,
+	"hook-env" + cmdSuffix:                NewHookEnvCommand,
 	"juju-log" + cmdSuffix:                NewJujuLogCommand,
 	"open-port" + cmdSuffix:               NewOpenPortCommand,
 	"opened-ports" + cmdSuffix:            NewOpenedPortsCommand,
 	"relation-get" + cmdSuffix:            NewRelationGetCommand,
+	"secret-get" + cmdSuffix:              NewSecretGetCommand,
+	"secret-set" + cmdSuffix:              NewSecretSetCommand,
 	"action-get" + cmdSuffix:              NewActionGetCommand,
 	"action-set" + cmdSuffix:              NewActionSetCommand,
 	"action-fail" + cmdSuffix:             NewActionFailCommand,
+	"action-progress" + cmdSuffix:         NewActionProgressCommand,
 	"relation-ids" + cmdSuffix:            NewRelationIdsCommand,
 	"relation-list" + cmdSuffix:           NewRelationListCommand,
 	"relation-set" + cmdSuffix:            NewRelationSetCommand,
@@ -60,6 +68,7 @@ var baseCommands = map[string]creator{
 	"add-metric" + cmdSuffix:              NewAddMetricCommand,
 	"juju-reboot" + cmdSuffix:             NewJujuRebootCommand,
 	"status-get" + cmdSuffix:              NewStatusGetCommand,
+	"status-history" + cmdSuffix:          NewStatusHistoryCommand,
 	"status-set" + cmdSuffix:              NewStatusSetCommand,
 	"network-get" + cmdSuffix:             NewNetworkGetCommand,
 	"application-version-set" + cmdSuffix: NewApplicationVersionSetCommand,
@@ -131,10 +140,24 @@ type Request struct {
 // CmdGetter looks up a Command implementation connected to a particular Context.
 type CmdGetter func(contextId, cmdName string) (cmd.Command, error)
 
+// Invocation describes a completed hook tool invocation. The values
+// of any secrets passed in Args are redacted.
+type Invocation struct {
+	ContextId   string
+	CommandName string
+	Args        []string
+	Code        int
+	Duration    time.Duration
+}
+
+// InvocationObserver is called after each hook tool invocation completes.
+type InvocationObserver func(Invocation)
+
 // Jujuc implements the jujuc command in the form required by net/rpc.
 type Jujuc struct {
-	mu     sync.Mutex
-	getCmd CmdGetter
+	mu      sync.Mutex
+	getCmd  CmdGetter
+	observe InvocationObserver
 }
 
 // badReqErrorf returns an error indicating a bad Request.
@@ -174,14 +197,25 @@ func (j *Jujuc) Main(req Request, resp *exec.ExecResponse) error {
 	defer j.mu.Unlock()
 	// Beware, reducing the log level of the following line will lead
 	// to passwords leaking if passed as args.
-	logger.Tracef("running hook tool %q %q", req.CommandName, req.Args)
+	args := redactArgs(req.CommandName, req.Args)
+	logger.Tracef("running hook tool %q %q", req.CommandName, args)
 	logger.Debugf("running hook tool %q", req.CommandName)
 	logger.Tracef("hook context id %q; dir %q", req.ContextId, req.Dir)
 	wrapper := &cmdWrapper{c, nil}
+	start := time.Now()
 	resp.Code = cmd.Main(wrapper, ctx, req.Args)
 	if errors.Cause(wrapper.err) == ErrNoStdin {
 		return ErrNoStdin
 	}
+	if j.observe != nil {
+		j.observe(Invocation{
+			ContextId:   req.ContextId,
+			CommandName: req.CommandName,
+			Args:        args,
+			Code:        resp.Code,
+			Duration:    time.Since(start),
+		})
+	}
 	resp.Stdout = stdout.Bytes()
 	resp.Stderr = stderr.Bytes()
 	return nil
@@ -202,8 +236,15 @@ type Server struct {
 // remote command invocations against an appropriate Context. It will not
 // actually do so until Run is called.
 func NewServer(getCmd CmdGetter, socketPath string) (*Server, error) {
+	return NewObservedServer(getCmd, socketPath, nil)
+}
+
+// NewObservedServer creates an RPC server like NewServer, which will
+// additionally report every completed command invocation to the supplied
+// observer, if it is not nil.
+func NewObservedServer(getCmd CmdGetter, socketPath string, observe InvocationObserver) (*Server, error) {
 	server := rpc.NewServer()
-	if err := server.Register(&Jujuc{getCmd: getCmd}); err != nil {
+	if err := server.Register(&Jujuc{getCmd: getCmd, observe: observe}); err != nil {
 		return nil, err
 	}
 	listener, err := sockets.Listen(socketPath)
@@ -281,3 +322,23 @@ func (c *cmdWrapper) Run(ctx *cmd.Context) error {
 	c.err = c.Command.Run(ctx)
 	return c.err
 }
+
+// redactedValue replaces the values of secrets passed to hook tools.
+const redactedValue = "<redacted>"
+
+// redactArgs returns the arguments of the named hook tool with the
+// values of any secrets replaced, so that they can be logged and
+// audited. Secret names are kept.
+func redactArgs(commandName string, args []string) []string {
+	if commandName != "secret-set" {
+		return args
+	}
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if j := strings.Index(arg, "="); j >= 0 {
+			arg = arg[:j+1] + redactedValue
+		}
+		redacted[i] = arg
+	}
+	return redacted
+}
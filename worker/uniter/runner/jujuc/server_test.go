@@ -145,6 +145,49 @@ func (s *ServerSuite) TestHappyPath(c *gc.C) {
 	c.Assert(string(content), gc.Equals, "something")
 }
 
+func (s *ServerSuite) TestObserver(c *gc.C) {
+	s.server.Close()
+	c.Assert(<-s.err, gc.IsNil)
+
+	invocations := make(chan jujuc.Invocation, 1)
+	srv, err := jujuc.NewObservedServer(factory, s.sockPath, func(inv jujuc.Invocation) {
+		invocations <- inv
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.server = srv
+	go func() { s.err <- s.server.Run() }()
+
+	resp, err := s.Call(c, jujuc.Request{
+		ContextId:   "validCtx",
+		Dir:         c.MkDir(),
+		CommandName: "remote",
+		Args:        []string{"--value", "error"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Code, gc.Equals, 1)
+
+	select {
+	case inv := <-invocations:
+		c.Check(inv.ContextId, gc.Equals, "validCtx")
+		c.Check(inv.CommandName, gc.Equals, "remote")
+		c.Check(inv.Args, jc.DeepEquals, []string{"--value", "error"})
+		c.Check(inv.Code, gc.Equals, 1)
+		c.Check(inv.Duration >= 0, jc.IsTrue)
+	default:
+		c.Fatalf("invocation not observed")
+	}
+}
+
+func (s *ServerSuite) TestRedactArgs(c *gc.C) {
+	args := []string{"password=hunter2", "token=a=b", "empty="}
+	c.Check(jujuc.RedactArgs("secret-set", args), jc.DeepEquals, []string{
+		"password=<redacted>", "token=<redacted>", "empty=<redacted>",
+	})
+	c.Check(args[0], gc.Equals, "password=hunter2")
+	c.Check(jujuc.RedactArgs("secret-get", []string{"password"}), jc.DeepEquals, []string{"password"})
+	c.Check(jujuc.RedactArgs("relation-set", []string{"foo=bar"}), jc.DeepEquals, []string{"foo=bar"})
+}
+
 func (s *ServerSuite) TestNoStdin(c *gc.C) {
 	dir := c.MkDir()
 	_, err := s.Call(c, jujuc.Request{
@@ -253,6 +296,9 @@ var newCommandTests = []struct {
 }{
 	{"close-port", ""},
 	{"config-get", ""},
+	{"credential-get", ""},
+	{"goal-state", ""},
+	{"hook-env", ""},
 	{"juju-log", ""},
 	{"open-port", ""},
 	{"opened-ports", ""},
@@ -260,10 +306,13 @@ var newCommandTests = []struct {
 	{"relation-ids", ""},
 	{"relation-list", ""},
 	{"relation-set", ""},
+	{"secret-get", ""},
+	{"secret-set", ""},
 	{"unit-get", ""},
 	{"storage-add", ""},
 	{"storage-get", ""},
 	{"status-get", ""},
+	{"status-history", ""},
 	{"status-set", ""},
 	// The error message contains .exe on Windows
 	{"random", "unknown command: random(.exe)?"},
@@ -0,0 +1,112 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+)
+
+// defaultStatusHistoryCount is the number of entries printed by
+// status-history when no count is given.
+const defaultStatusHistoryCount = 10
+
+// StatusHistoryEntry is a record of a past status of a unit's workload
+// or agent.
+type StatusHistoryEntry struct {
+	// Kind is "workload" or "juju-unit", for a workload or an agent
+	// status respectively.
+	Kind   string
+	Status string
+	Info   string
+	Data   map[string]interface{}
+	Since  *time.Time
+}
+
+// statusHistoryCommand implements the status-history command.
+type statusHistoryCommand struct {
+	cmd.CommandBase
+	ctx   Context
+	count int
+	out   cmd.Output
+}
+
+// NewStatusHistoryCommand returns a new statusHistoryCommand with the
+// given context.
+func NewStatusHistoryCommand(ctx Context) (cmd.Command, error) {
+	return &statusHistoryCommand{ctx: ctx}, nil
+}
+
+// Info is part of the cmd.Command interface.
+func (c *statusHistoryCommand) Info() *cmd.Info {
+	doc := `
+status-history prints the most recent changes to this unit's workload and
+agent status, oldest first. Workload statuses, as set by status-set, have
+a kind of "workload"; agent statuses have a kind of "juju-unit".
+
+Charms may use the history to react to recent transitions, for example to
+avoid restarting a service that keeps flapping between states.
+`
+	return &cmd.Info{
+		Name:    "status-history",
+		Args:    "[-n <count>]",
+		Purpose: "print recent status changes of this unit",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *statusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+	f.IntVar(&c.count, "n", defaultStatusHistoryCount, "number of entries to print")
+	f.IntVar(&c.count, "count", defaultStatusHistoryCount, "")
+}
+
+// Init is part of the cmd.Command interface.
+func (c *statusHistoryCommand) Init(args []string) error {
+	if c.count < 1 {
+		return errors.Errorf("count must be positive, got %d", c.count)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run is part of the cmd.Command interface.
+func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
+	history, err := c.ctx.UnitStatusHistory(c.count)
+	if err != nil {
+		return errors.Annotate(err, "cannot get status history")
+	}
+	return c.out.Write(ctx, formatStatusHistory(history))
+}
+
+// formattedStatusHistoryEntry is the serialized form of a
+// StatusHistoryEntry.
+type formattedStatusHistoryEntry struct {
+	Kind    string                 `json:"kind" yaml:"kind"`
+	Status  string                 `json:"status" yaml:"status"`
+	Message string                 `json:"message,omitempty" yaml:"message,omitempty"`
+	Data    map[string]interface{} `json:"data,omitempty" yaml:"data,omitempty"`
+	Since   string                 `json:"since,omitempty" yaml:"since,omitempty"`
+}
+
+func formatStatusHistory(history []StatusHistoryEntry) []formattedStatusHistoryEntry {
+	result := make([]formattedStatusHistoryEntry, len(history))
+	for i, entry := range history {
+		var since string
+		if entry.Since != nil {
+			since = entry.Since.UTC().Format(time.RFC3339)
+		}
+		result[i] = formattedStatusHistoryEntry{
+			Kind:    entry.Kind,
+			Status:  entry.Status,
+			Message: entry.Info,
+			Data:    entry.Data,
+			Since:   since,
+		}
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type StatusHistorySuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+func (s *StatusHistorySuite) createCommand(c *gc.C) cmd.Command {
+	hctx := s.GetStatusHookContext(c)
+	since := time.Date(2017, 10, 1, 12, 30, 0, 0, time.UTC)
+	later := since.Add(time.Minute)
+	hctx.info.Status.UnitStatusHistory = []jujuc.StatusHistoryEntry{
+		{Kind: "juju-unit", Status: "executing", Info: "running config-changed hook", Since: &since},
+		{Kind: "workload", Status: "blocked", Info: "need db", Data: map[string]interface{}{"retries": 3}, Since: &later},
+		{Kind: "juju-unit", Status: "idle", Since: &later},
+	}
+	com, err := jujuc.NewCommand(hctx, cmdString("status-history"))
+	c.Assert(err, jc.ErrorIsNil)
+	return com
+}
+
+func (s *StatusHistorySuite) TestOutputFormat(c *gc.C) {
+	expect := []interface{}{
+		map[string]interface{}{
+			"kind":    "juju-unit",
+			"status":  "executing",
+			"message": "running config-changed hook",
+			"since":   "2017-10-01T12:30:00Z",
+		},
+		map[string]interface{}{
+			"kind":    "workload",
+			"status":  "blocked",
+			"message": "need db",
+			"data":    map[string]interface{}{"retries": 3},
+			"since":   "2017-10-01T12:31:00Z",
+		},
+		map[string]interface{}{
+			"kind":   "juju-unit",
+			"status": "idle",
+			"since":  "2017-10-01T12:31:00Z",
+		},
+	}
+	for i, t := range []struct {
+		args    []string
+		checker gc.Checker
+	}{
+		{nil, jc.YAMLEquals},
+		{[]string{"--format", "yaml"}, jc.YAMLEquals},
+		{[]string{"--format", "json"}, jc.JSONEquals},
+	} {
+		c.Logf("test %d: %v", i, t.args)
+		com := s.createCommand(c)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Assert(bufferString(ctx.Stdout), t.checker, expect)
+	}
+	s.Stub.CheckCall(c, 0, "UnitStatusHistory", 10)
+}
+
+func (s *StatusHistorySuite) TestCount(c *gc.C) {
+	for i, args := range [][]string{{"-n", "1"}, {"--count", "1"}} {
+		c.Logf("test %d: %v", i, args)
+		s.Stub.ResetCalls()
+		com := s.createCommand(c)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(com, ctx, append(args, "--format", "json"))
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stdout), jc.JSONEquals, []interface{}{
+			map[string]interface{}{
+				"kind":   "juju-unit",
+				"status": "idle",
+				"since":  "2017-10-01T12:31:00Z",
+			},
+		})
+		s.Stub.CheckCall(c, 0, "UnitStatusHistory", 1)
+	}
+}
+
+func (s *StatusHistorySuite) TestInvalidCount(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"-n", "0"})
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "ERROR count must be positive, got 0\n")
+}
+
+func (s *StatusHistorySuite) TestStatusHistoryError(c *gc.C) {
+	com := s.createCommand(c)
+	s.Stub.SetErrors(errors.New("boom"))
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, nil)
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "ERROR cannot get status history: boom\n")
+}
+
+func (s *StatusHistorySuite) TestUnexpectedArgs(c *gc.C) {
+	com := s.createCommand(c)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"blah"})
+	c.Assert(code, gc.Equals, 2)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, `ERROR unrecognized args: ["blah"]`+"\n")
+}
@@ -7,9 +7,16 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/names.v2"
+
+	jujustorage "github.com/juju/juju/storage"
 )
 
+// usageKeys holds the storage-get keys whose values are taken from
+// the reported usage of a filesystem.
+var usageKeys = set.NewStrings("used-bytes", "available-bytes")
+
 // StorageGetCommand implements the storage-get command.
 type StorageGetCommand struct {
 	cmd.CommandBase
@@ -33,6 +40,10 @@ func NewStorageGetCommand(ctx Context) (cmd.Command, error) {
 func (c *StorageGetCommand) Info() *cmd.Info {
 	doc := `
 When no <key> is supplied, all keys values are printed.
+
+For filesystem storage, the "used-bytes" and "available-bytes" keys
+hold the usage most recently reported by the unit's machine, once
+it has been reported.
 `
 	return &cmd.Info{
 		Name:    "storage-get",
@@ -68,6 +79,17 @@ func (c *StorageGetCommand) Run(ctx *cmd.Context) error {
 		"kind":     storage.Kind().String(),
 		"location": storage.Location(),
 	}
+	if storage.Kind() == jujustorage.StorageKindFilesystem && (c.key == "" || usageKeys.Contains(c.key)) {
+		usage, err := c.ctx.StorageUsage(c.storageTag)
+		if err == nil {
+			values["used-bytes"] = usage.UsedBytes
+			values["available-bytes"] = usage.AvailableBytes
+		} else if !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		} else if c.key != "" {
+			return errors.Errorf("%s of %s not yet reported", c.key, names.ReadableString(c.storageTag))
+		}
+	}
 	if c.key == "" {
 		return c.out.Write(ctx, values)
 	}
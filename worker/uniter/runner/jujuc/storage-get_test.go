@@ -14,7 +14,9 @@ import (
 	gc "gopkg.in/check.v1"
 	goyaml "gopkg.in/yaml.v2"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	jujuctesting "github.com/juju/juju/worker/uniter/runner/jujuc/testing"
 )
 
 type storageGetSuite struct {
@@ -83,6 +85,10 @@ Options:
 
 Details:
 When no <key> is supplied, all keys values are printed.
+
+For filesystem storage, the "used-bytes" and "available-bytes" keys
+hold the usage most recently reported by the unit's machine, once
+it has been reported.
 `)
 	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
 }
@@ -103,3 +109,72 @@ func (s *storageGetSuite) TestOutputPath(c *gc.C) {
 	c.Assert(goyaml.Unmarshal(content, &out), gc.IsNil)
 	c.Assert(out, gc.DeepEquals, storageAttributes)
 }
+
+func (s *storageGetSuite) newFilesystemHookContext(usage *params.FilesystemUsage) *jujuctesting.Context {
+	hctx, info := s.NewHookContext()
+	info.SetFilesystemStorage(s.storageName, "/srv/data", s.Stub)
+	info.SetStorageTag(s.storageName)
+	if usage != nil {
+		info.SetStorageUsage(s.storageName, *usage)
+	}
+	return hctx
+}
+
+func (s *storageGetSuite) TestFilesystemUsage(c *gc.C) {
+	hctx := s.newFilesystemHookContext(&params.FilesystemUsage{
+		UsedBytes:      1024,
+		AvailableBytes: 2048,
+	})
+	com, err := jujuc.NewCommand(hctx, cmdString("storage-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--format", "yaml"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+
+	var out map[string]interface{}
+	c.Assert(goyaml.Unmarshal(bufferBytes(ctx.Stdout), &out), gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{
+		"kind":            "filesystem",
+		"location":        "/srv/data",
+		"used-bytes":      1024,
+		"available-bytes": 2048,
+	})
+}
+
+func (s *storageGetSuite) TestFilesystemUsageKey(c *gc.C) {
+	hctx := s.newFilesystemHookContext(&params.FilesystemUsage{
+		UsedBytes:      1024,
+		AvailableBytes: 2048,
+	})
+	com, err := jujuc.NewCommand(hctx, cmdString("storage-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"available-bytes"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Assert(bufferString(ctx.Stdout), gc.Equals, "2048\n")
+}
+
+func (s *storageGetSuite) TestFilesystemUsageNotReported(c *gc.C) {
+	hctx := s.newFilesystemHookContext(nil)
+	com, err := jujuc.NewCommand(hctx, cmdString("storage-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(com, ctx, []string{"--format", "yaml"})
+	c.Assert(code, gc.Equals, 0)
+
+	var out map[string]interface{}
+	c.Assert(goyaml.Unmarshal(bufferBytes(ctx.Stdout), &out), gc.IsNil)
+	c.Assert(out, gc.DeepEquals, map[string]interface{}{
+		"kind":     "filesystem",
+		"location": "/srv/data",
+	})
+
+	com, err = jujuc.NewCommand(hctx, cmdString("storage-get"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx = cmdtesting.Context(c)
+	code = cmd.Main(com, ctx, []string{"used-bytes"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "ERROR used-bytes of storage data/0 not yet reported\n")
+}
@@ -69,3 +69,29 @@ func (c *ContextActionHook) SetActionFailed() error {
 	}
 	return nil
 }
+
+// SetActionProgress implements jujuc.ActionHookContext.
+func (c *ContextActionHook) SetActionProgress(message string, percent int) error {
+	c.stub.AddCall("SetActionProgress", message, percent)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.info.ActionParams == nil {
+		return errors.Errorf("not running an action")
+	}
+	return nil
+}
+
+// ActionLog implements jujuc.ActionHookContext.
+func (c *ContextActionHook) ActionLog(message string) error {
+	c.stub.AddCall("ActionLog", message)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.info.ActionParams == nil {
+		return errors.Errorf("not running an action")
+	}
+	return nil
+}
@@ -4,12 +4,15 @@
 package testing
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 )
 
 // Leadership holds the values for the hook context.
 type Leadership struct {
 	IsLeader       bool
+	LeaseRemaining time.Duration
 	LeaderSettings map[string]string
 }
 
@@ -29,6 +32,16 @@ func (c *ContextLeader) IsLeader() (bool, error) {
 	return c.info.IsLeader, nil
 }
 
+// LeaderLeaseRemaining implements jujuc.ContextLeader.
+func (c *ContextLeader) LeaderLeaseRemaining() (time.Duration, error) {
+	c.stub.AddCall("LeaderLeaseRemaining")
+	if err := c.stub.NextErr(); err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	return c.info.LeaseRemaining, nil
+}
+
 // LeaderSettings implements jujuc.ContextLeader.
 func (c *ContextLeader) LeaderSettings() (map[string]string, error) {
 	c.stub.AddCall("LeaderSettings")
@@ -13,12 +13,14 @@ import (
 type RelationHook struct {
 	HookRelation   jujuc.ContextRelation
 	RemoteUnitName string
+	DepartingUnit  string
 }
 
 // Reset clears the RelationHook's data.
 func (rh *RelationHook) Reset() {
 	rh.HookRelation = nil
 	rh.RemoteUnitName = ""
+	rh.DepartingUnit = ""
 }
 
 // ContextRelationHook is a test double for jujuc.RelationHookContext.
@@ -49,3 +51,15 @@ func (c *ContextRelationHook) RemoteUnitName() (string, error) {
 
 	return c.info.RemoteUnitName, err
 }
+
+// DepartingUnit implements jujuc.RelationHookContext.
+func (c *ContextRelationHook) DepartingUnit() (string, error) {
+	c.stub.AddCall("DepartingUnit")
+	c.stub.NextErr()
+	var err error
+	if c.info.DepartingUnit == "" {
+		err = errors.NotFoundf("departing unit")
+	}
+
+	return c.info.DepartingUnit, err
+}
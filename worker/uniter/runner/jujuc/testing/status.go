@@ -12,6 +12,7 @@ import (
 // Status  holds the values for the hook context.
 type Status struct {
 	UnitStatus        jujuc.StatusInfo
+	UnitStatusHistory []jujuc.StatusHistoryEntry
 	ApplicationStatus jujuc.ApplicationStatusInfo
 }
 
@@ -50,6 +51,20 @@ func (c *ContextStatus) SetUnitStatus(status jujuc.StatusInfo) error {
 	return nil
 }
 
+// UnitStatusHistory implements jujuc.ContextStatus.
+func (c *ContextStatus) UnitStatusHistory(n int) ([]jujuc.StatusHistoryEntry, error) {
+	c.stub.AddCall("UnitStatusHistory", n)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	history := c.info.UnitStatusHistory
+	if len(history) > n {
+		history = history[len(history)-n:]
+	}
+	return history, nil
+}
+
 // ApplicationStatus implements jujuc.ContextStatus.
 func (c *ContextStatus) ApplicationStatus() (jujuc.ApplicationStatusInfo, error) {
 	c.stub.AddCall("ApplicationStatus")
@@ -21,6 +21,7 @@ type Storage struct {
 	Storage    map[names.StorageTag]jujuc.ContextStorageAttachment
 	StorageTag names.StorageTag
 	Added      map[string]params.StorageConstraints
+	Usage      map[names.StorageTag]params.FilesystemUsage
 }
 
 // SetAttachment adds the attachment to the storage.
@@ -49,6 +50,19 @@ func (s *Storage) SetBlockStorage(name, location string, stub *testing.Stub) {
 	s.SetNewAttachment(name, location, storage.StorageKindBlock, stub)
 }
 
+// SetFilesystemStorage adds the attachment to the storage.
+func (s *Storage) SetFilesystemStorage(name, location string, stub *testing.Stub) {
+	s.SetNewAttachment(name, location, storage.StorageKindFilesystem, stub)
+}
+
+// SetStorageUsage records the usage of the identified storage.
+func (s *Storage) SetStorageUsage(id string, usage params.FilesystemUsage) {
+	if s.Usage == nil {
+		s.Usage = make(map[names.StorageTag]params.FilesystemUsage)
+	}
+	s.Usage[names.NewStorageTag(id)] = usage
+}
+
 // SetStorageTag sets the storage tag to the given ID.
 func (s *Storage) SetStorageTag(id string) {
 	tag := names.NewStorageTag(id)
@@ -117,6 +131,20 @@ func (c *ContextStorage) HookStorage() (jujuc.ContextStorageAttachment, error) {
 	return c.Storage(c.info.StorageTag)
 }
 
+// StorageUsage implements jujuc.ContextStorage.
+func (c *ContextStorage) StorageUsage(tag names.StorageTag) (params.FilesystemUsage, error) {
+	c.stub.AddCall("StorageUsage", tag)
+	if err := c.stub.NextErr(); err != nil {
+		return params.FilesystemUsage{}, err
+	}
+
+	usage, ok := c.info.Usage[tag]
+	if !ok {
+		return params.FilesystemUsage{}, errors.NotFoundf("usage")
+	}
+	return usage, nil
+}
+
 // AddUnitStorage implements jujuc.ContextStorage.
 func (c *ContextStorage) AddUnitStorage(all map[string]params.StorageConstraints) error {
 	c.stub.AddCall("AddUnitStorage", all)
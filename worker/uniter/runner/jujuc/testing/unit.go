@@ -6,12 +6,18 @@ package testing
 import (
 	"github.com/juju/errors"
 	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/params"
 )
 
 // Unit holds the values for the hook context.
 type Unit struct {
 	Name           string
 	ConfigSettings charm.Settings
+	GoalState      params.GoalState
+	CloudSpec      params.CloudSpec
+	Secrets        map[string]string
+	Environment    map[string]string
 }
 
 // ContextUnit is a test double for jujuc.ContextUnit.
@@ -37,3 +43,63 @@ func (c *ContextUnit) ConfigSettings() (charm.Settings, error) {
 
 	return c.info.ConfigSettings, nil
 }
+
+// GoalState implements jujuc.ContextUnit.
+func (c *ContextUnit) GoalState() (*params.GoalState, error) {
+	c.stub.AddCall("GoalState")
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &c.info.GoalState, nil
+}
+
+// CloudSpec implements jujuc.ContextUnit.
+func (c *ContextUnit) CloudSpec() (*params.CloudSpec, error) {
+	c.stub.AddCall("CloudSpec")
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &c.info.CloudSpec, nil
+}
+
+// SecretGet implements jujuc.ContextUnit.
+func (c *ContextUnit) SecretGet() (map[string]string, error) {
+	c.stub.AddCall("SecretGet")
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return c.info.Secrets, nil
+}
+
+// SecretSet implements jujuc.ContextUnit.
+func (c *ContextUnit) SecretSet(secrets map[string]string) error {
+	c.stub.AddCall("SecretSet", secrets)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.info.Secrets == nil {
+		c.info.Secrets = make(map[string]string)
+	}
+	for name, value := range secrets {
+		if value == "" {
+			delete(c.info.Secrets, name)
+		} else {
+			c.info.Secrets[name] = value
+		}
+	}
+	return nil
+}
+
+// HookEnvironment implements jujuc.ContextUnit.
+func (c *ContextUnit) HookEnvironment() (map[string]string, error) {
+	c.stub.AddCall("HookEnvironment")
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return c.info.Environment, nil
+}
@@ -9,7 +9,8 @@ import (
 
 // Version holds values for the hook context.
 type Version struct {
-	WorkloadVersion string
+	WorkloadVersion            string
+	ApplicationWorkloadVersion string
 }
 
 // ContextVersion is a test double for jujuc.ContextVersion.
@@ -36,3 +37,13 @@ func (c *ContextVersion) SetUnitWorkloadVersion(version string) error {
 	c.info.WorkloadVersion = version
 	return nil
 }
+
+// SetApplicationWorkloadVersion implements jujuc.ContextVersion.
+func (c *ContextVersion) SetApplicationWorkloadVersion(version string) error {
+	c.stub.AddCall("SetApplicationWorkloadVersion", version)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	c.info.ApplicationWorkloadVersion = version
+	return nil
+}
@@ -18,6 +18,7 @@ type hookLogger struct {
 	mu      sync.Mutex
 	stopped bool
 	logger  loggo.Logger
+	prefix  string
 }
 
 func (l *hookLogger) run() {
@@ -37,7 +38,7 @@ func (l *hookLogger) run() {
 			l.mu.Unlock()
 			return
 		}
-		l.logger.Debugf("%s", line)
+		l.logger.Debugf("%s%s", l.prefix, line)
 		l.mu.Unlock()
 	}
 }
@@ -4,11 +4,14 @@
 package runner
 
 import (
+	stdcontext "context"
 	"encoding/base64"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"sync"
 	"time"
 	"unicode/utf8"
 
@@ -27,6 +30,17 @@ import (
 
 var logger = loggo.GetLogger("juju.worker.uniter.runner")
 
+const (
+	// dispatchName is the name of the executable, at the root of a
+	// charm's directory, that is run in place of the charm's hook and
+	// action files if it exists. It learns which hook or action it is
+	// being run for from JUJU_DISPATCH_PATH.
+	dispatchName = "dispatch"
+
+	// actionsDir is the directory of a charm that holds its actions.
+	actionsDir = "actions"
+)
+
 // Runner is responsible for invoking commands in a context.
 type Runner interface {
 
@@ -50,6 +64,8 @@ type Context interface {
 	HookVars(paths context.Paths) ([]string, error)
 	ActionData() (*context.ActionData, error)
 	SetProcess(process context.HookProcess)
+	CancelContext() stdcontext.Context
+	KillHook(reason error) error
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
 
@@ -59,13 +75,75 @@ type Context interface {
 
 // NewRunner returns a Runner backed by the supplied context and paths.
 func NewRunner(context Context, paths context.Paths) Runner {
-	return &runner{context, paths}
+	return NewAuditedRunner(context, paths, nil)
+}
+
+// NewAuditedRunner returns a Runner backed by the supplied context and
+// paths, which records every hook tool invocation with the supplied
+// auditor, if it is not nil.
+func NewAuditedRunner(context Context, paths context.Paths, auditor Auditor) Runner {
+	return newRunner(context, paths, auditor, nil)
+}
+
+func newRunner(context Context, paths context.Paths, auditor Auditor, attempts *hookAttempts) *runner {
+	return &runner{
+		context:  context,
+		paths:    paths,
+		auditor:  auditor,
+		attempts: attempts,
+		hooksDir: "hooks",
+	}
 }
 
 // runner implements Runner.
 type runner struct {
 	context Context
 	paths   context.Paths
+	auditor Auditor
+
+	// attempts, if not nil, counts the runs of each hook so that
+	// retries of a failing hook can be told apart in its logs.
+	attempts *hookAttempts
+
+	// hooksDir is the directory of the charm that RunHook runs hooks
+	// from.
+	hooksDir string
+}
+
+// hookAttempts tracks how many times in a row each hook has failed.
+// It is shared by all the runners created by a factory.
+type hookAttempts struct {
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+func newHookAttempts() *hookAttempts {
+	return &hookAttempts{failures: make(map[string]int)}
+}
+
+// start returns the attempt number of a run of the named hook that is
+// about to start: 1, unless earlier runs of the hook failed.
+func (a *hookAttempts) start(hookName string) int {
+	if a == nil {
+		return 1
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.failures[hookName] + 1
+}
+
+// finish records the outcome of a run of the named hook.
+func (a *hookAttempts) finish(hookName string, err error) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err == nil || context.IsMissingHookError(err) {
+		delete(a.failures, hookName)
+	} else {
+		a.failures[hookName]++
+	}
 }
 
 func (runner *runner) Context() Context {
@@ -195,15 +273,18 @@ func (runner *runner) RunAction(actionName string) error {
 	if actionName == actions.JujuRunActionName {
 		return runner.runJujuRunAction()
 	}
-	return runner.runCharmHookWithLocation(actionName, "actions")
+	return runner.runCharmHookWithLocation(actionName, actionsDir, 1)
 }
 
 // RunHook exists to satisfy the Runner interface.
 func (runner *runner) RunHook(hookName string) error {
-	return runner.runCharmHookWithLocation(hookName, "hooks")
+	attempt := runner.attempts.start(hookName)
+	err := runner.runCharmHookWithLocation(hookName, runner.hooksDir, attempt)
+	runner.attempts.finish(hookName, err)
+	return err
 }
 
-func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) error {
+func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string, attempt int) error {
 	srv, err := runner.startJujucServer()
 	if err != nil {
 		return err
@@ -214,6 +295,7 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) e
 	if err != nil {
 		return errors.Trace(err)
 	}
+	env = append(env, dispatchVars(hookName, charmLocation)...)
 	if jujuos.HostOS() == jujuos.Windows {
 		// TODO(fwereade): somehow consolidate with utils/exec?
 		// We don't do this on the other code path, which uses exec.RunCommands,
@@ -226,45 +308,119 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string) e
 		logger.Infof("executing %s via debug-hooks", hookName)
 		err = session.RunHook(hookName, runner.paths.GetCharmDir(), env)
 	} else {
-		err = runner.runCharmHook(hookName, env, charmLocation)
+		err = runner.runCharmHook(hookName, env, charmLocation, attempt)
 	}
 	return runner.context.Flush(hookName, err)
 }
 
-func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string) error {
+// dispatchVars returns the environment variables that tell a charm's
+// dispatch executable which hook or action it is being run for.
+func dispatchVars(hookName, charmLocation string) []string {
+	vars := []string{"JUJU_DISPATCH_PATH=" + path.Join(charmLocation, hookName)}
+	if charmLocation != actionsDir {
+		vars = append(vars, "JUJU_HOOK_NAME="+hookName)
+	}
+	return vars
+}
+
+func (runner *runner) runCharmHook(hookName string, env []string, charmLocation string, attempt int) error {
 	charmDir := runner.paths.GetCharmDir()
-	hook, err := searchHook(charmDir, filepath.Join(charmLocation, hookName))
+	hookPath := filepath.Join(charmLocation, hookName)
+	hook, dispatched, err := searchDispatchOrHook(charmDir, hookPath)
 	if err != nil {
 		return err
 	}
+	description := hookPath
+	if dispatched {
+		description += " via " + dispatchName
+	}
 	hookCmd := hookCommand(hook)
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
 	ps.Env = env
 	ps.Dir = charmDir
-	outReader, outWriter, err := os.Pipe()
+
+	// Juju's messages about the hook are logged under the hook's
+	// module, such as "unit.mysql/0.install". The hook's stdout and
+	// stderr are logged under fixed modules for the unit, such as
+	// "unit.mysql/0.stdout", so that all of a unit's output on
+	// either stream can be selected by module. Each line of output
+	// names the hook and the attempt, so that retries can be told
+	// apart.
+	hookLog := runner.getLogger(hookName)
+	prefix := fmt.Sprintf("%s (attempt %d): ", hookName, attempt)
+	stdoutLogger, stdoutWriter, err := runner.startHookLogger("stdout", prefix)
 	if err != nil {
-		return errors.Errorf("cannot make logging pipe: %v", err)
+		return errors.Trace(err)
 	}
-	ps.Stdout = outWriter
-	ps.Stderr = outWriter
-	hookLogger := &hookLogger{
-		r:      outReader,
-		done:   make(chan struct{}),
-		logger: runner.getLogger(hookName),
+	stderrLogger, stderrWriter, err := runner.startHookLogger("stderr", prefix)
+	if err != nil {
+		stdoutWriter.Close()
+		stdoutLogger.stop()
+		return errors.Trace(err)
 	}
-	go hookLogger.run()
+	ps.Stdout = stdoutWriter
+	ps.Stderr = stderrWriter
+
+	hookLog.Infof("running %s (attempt %d)", description, attempt)
 	err = ps.Start()
-	outWriter.Close()
+	stdoutWriter.Close()
+	stderrWriter.Close()
 	if err == nil {
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(hookProcess{ps.Process})
 		// Block until execution finishes
-		err = ps.Wait()
+		err = runner.waitForHook(hookName, ps)
+	}
+	stdoutLogger.stop()
+	stderrLogger.stop()
+	if err != nil {
+		hookLog.Infof("%s (attempt %d) failed: %v", description, attempt, err)
+	} else {
+		hookLog.Infof("%s (attempt %d) completed", description, attempt)
 	}
-	hookLogger.stop()
 	return errors.Trace(err)
 }
 
+// startHookLogger starts logging the lines written to the returned
+// file, each with the given prefix, under the unit's logger module for
+// the named file descriptor.
+func (runner *runner) startHookLogger(fd, prefix string) (*hookLogger, *os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, nil, errors.Errorf("cannot make %s logging pipe: %v", fd, err)
+	}
+	l := &hookLogger{
+		r:      r,
+		done:   make(chan struct{}),
+		logger: loggo.GetLogger(fmt.Sprintf("unit.%s.%s", runner.context.UnitName(), fd)),
+		prefix: prefix,
+	}
+	go l.run()
+	return l, w, nil
+}
+
+// waitForHook blocks until the hook process exits. If the context is
+// cancelled first, because the hook has run for too long, the process
+// is killed.
+func (runner *runner) waitForHook(hookName string, ps *exec.Cmd) error {
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- ps.Wait()
+	}()
+	cancel := runner.context.CancelContext()
+	select {
+	case err := <-waitErr:
+		return err
+	case <-cancel.Done():
+	}
+	reason := cancel.Err()
+	logger.Warningf("killing %s hook: %v", hookName, reason)
+	if err := runner.context.KillHook(reason); err != nil {
+		return errors.Annotatef(err, "cannot kill %s hook", hookName)
+	}
+	return <-waitErr
+}
+
 func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 	// Prepare server.
 	getCmd := func(ctxId, cmdName string) (cmd.Command, error) {
@@ -273,7 +429,11 @@ func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 		}
 		return jujuc.NewCommand(runner.context, cmdName)
 	}
-	srv, err := jujuc.NewServer(getCmd, runner.paths.GetJujucSocket())
+	var observe jujuc.InvocationObserver
+	if runner.auditor != nil {
+		observe = runner.audit
+	}
+	srv, err := jujuc.NewObservedServer(getCmd, runner.paths.GetJujucSocket(), observe)
 	if err != nil {
 		return nil, errors.Annotate(err, "starting jujuc server")
 	}
@@ -281,6 +441,30 @@ func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 	return srv, nil
 }
 
+// audit records a hook tool invocation with the runner's auditor.
+// Failure to record an invocation is logged, but does not affect
+// the running hook.
+func (runner *runner) audit(inv jujuc.Invocation) {
+	relationId := -1
+	if relation, err := runner.context.HookRelation(); err == nil {
+		relationId = relation.Id()
+	}
+	record := AuditRecord{
+		Time:       time.Now(),
+		Unit:       runner.context.UnitName(),
+		Tool:       inv.CommandName,
+		Args:       inv.Args,
+		RelationId: relationId,
+		Code:       inv.Code,
+		Duration:   inv.Duration,
+	}
+	if err := runner.auditor.Audit(record); err != nil {
+		logger.Warningf("cannot audit hook tool %q: %v", inv.CommandName, err)
+	}
+}
+
+// getLogger returns the logger for juju's messages about the named
+// hook.
 func (runner *runner) getLogger(hookName string) loggo.Logger {
 	return loggo.GetLogger(fmt.Sprintf("unit.%s.%s", runner.context.UnitName(), hookName))
 }
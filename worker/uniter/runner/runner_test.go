@@ -4,6 +4,7 @@
 package runner_test
 
 import (
+	stdcontext "context"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	envtesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/exec"
@@ -155,6 +157,9 @@ type MockContext struct {
 	flushBadge      string
 	flushFailure    error
 	flushResult     error
+	cancelContext   stdcontext.Context
+	process         context.HookProcess
+	killReason      error
 }
 
 func (ctx *MockContext) UnitName() string {
@@ -174,6 +179,19 @@ func (ctx *MockContext) ActionData() (*context.ActionData, error) {
 
 func (ctx *MockContext) SetProcess(process context.HookProcess) {
 	ctx.expectPid = process.Pid()
+	ctx.process = process
+}
+
+func (ctx *MockContext) CancelContext() stdcontext.Context {
+	if ctx.cancelContext == nil {
+		return stdcontext.Background()
+	}
+	return ctx.cancelContext
+}
+
+func (ctx *MockContext) KillHook(reason error) error {
+	ctx.killReason = reason
+	return ctx.process.Kill()
 }
 
 func (ctx *MockContext) Prepare() error {
@@ -252,6 +270,170 @@ func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunHookLogsOutputStreamsSeparately(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hook writes to stderr with a bash script")
+	}
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("hook-output-tester", &tw), jc.ErrorIsNil)
+	defer loggo.RemoveWriter("hook-output-tester")
+	hookLogger := loggo.GetLogger("unit.some-unit/999")
+	defer hookLogger.SetLogLevel(hookLogger.LogLevel())
+	hookLogger.SetLogLevel(loggo.DEBUG)
+
+	makeCharm(c, hookSpec{
+		dir:    "hooks",
+		name:   hookName,
+		perm:   0700,
+		stdout: "to stdout",
+		stderr: "to stderr",
+	}, s.paths.GetCharmDir())
+	err := runner.NewRunner(&MockContext{}, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+
+	logs := make(map[string][]string)
+	for _, entry := range tw.Log() {
+		logs[entry.Module] = append(logs[entry.Module], entry.Message)
+	}
+	c.Check(logs, jc.DeepEquals, map[string][]string{
+		"unit.some-unit/999.something-happened": {
+			"running hooks/something-happened (attempt 1)",
+			"hooks/something-happened (attempt 1) completed",
+		},
+		"unit.some-unit/999.stdout": {"something-happened (attempt 1): to stdout"},
+		"unit.some-unit/999.stderr": {"something-happened (attempt 1): to stderr"},
+	})
+}
+
+func (s *RunMockContextSuite) TestRunHookCountsAttempts(c *gc.C) {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("hook-attempt-tester", &tw), jc.ErrorIsNil)
+	defer loggo.RemoveWriter("hook-attempt-tester")
+	hookLogger := loggo.GetLogger("unit.some-unit/999")
+	defer hookLogger.SetLogLevel(hookLogger.LogLevel())
+	hookLogger.SetLogLevel(loggo.DEBUG)
+
+	makeCharm(c, hookSpec{
+		dir:    "hooks",
+		name:   hookName,
+		perm:   0700,
+		stdout: "output",
+		code:   1,
+	}, s.paths.GetCharmDir())
+	newRunner := runner.NewAttemptTrackingRunners(s.paths)
+	for i := 0; i < 2; i++ {
+		// Like a real context, report the hook's failure when flushed.
+		ctx := &MockContext{flushResult: errors.New("exit status 1")}
+		err := newRunner(ctx).RunHook("something-happened")
+		c.Assert(err, gc.ErrorMatches, "exit status 1")
+	}
+	c.Check(tw.Log(), jc.LogMatches, []jc.SimpleMessage{
+		{loggo.INFO, `running hooks/something-happened \(attempt 1\)`},
+		{loggo.DEBUG, `something-happened \(attempt 1\): output`},
+		{loggo.INFO, `hooks/something-happened \(attempt 1\) failed: exit status 1`},
+		{loggo.INFO, `running hooks/something-happened \(attempt 2\)`},
+		{loggo.DEBUG, `something-happened \(attempt 2\): output`},
+		{loggo.INFO, `hooks/something-happened \(attempt 2\) failed: exit status 1`},
+	})
+	var modules []string
+	for _, entry := range tw.Log() {
+		modules = append(modules, entry.Module)
+	}
+	c.Check(modules, jc.DeepEquals, []string{
+		"unit.some-unit/999.something-happened",
+		"unit.some-unit/999.stdout",
+		"unit.some-unit/999.something-happened",
+		"unit.some-unit/999.something-happened",
+		"unit.some-unit/999.stdout",
+		"unit.some-unit/999.something-happened",
+	})
+}
+
+func (s *RunMockContextSuite) TestRunHookKilledWhenCancelled(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hook sleeps with a bash script")
+	}
+	cancelContext, cancel := stdcontext.WithCancel(stdcontext.Background())
+	cancel()
+	ctx := &MockContext{
+		cancelContext: cancelContext,
+	}
+	makeCharm(c, hookSpec{
+		dir:   "hooks",
+		name:  hookName,
+		perm:  0700,
+		sleep: 10,
+	}, s.paths.GetCharmDir())
+	t0 := time.Now()
+	err := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	if time.Now().Sub(t0) > 5*time.Second {
+		c.Errorf("hook was not killed")
+	}
+	c.Assert(ctx.killReason, gc.Equals, stdcontext.Canceled)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "signal: killed")
+}
+
+func (s *RunMockContextSuite) captureDispatchOutput(c *gc.C) *loggo.TestWriter {
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("dispatch-tester", &tw), jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { loggo.RemoveWriter("dispatch-tester") })
+	hookLogger := loggo.GetLogger("unit.some-unit/999")
+	level := hookLogger.LogLevel()
+	s.AddCleanup(func(*gc.C) { hookLogger.SetLogLevel(level) })
+	hookLogger.SetLogLevel(loggo.DEBUG)
+
+	makeCharm(c, hookSpec{
+		name:   "dispatch",
+		perm:   0700,
+		stdout: "$JUJU_DISPATCH_PATH ${JUJU_HOOK_NAME:-no-hook}",
+	}, s.paths.GetCharmDir())
+	return &tw
+}
+
+func (s *RunMockContextSuite) TestRunHookPrefersDispatch(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("dispatch reads its environment with a bash script")
+	}
+	tw := s.captureDispatchOutput(c)
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+		code: 1,
+	}, s.paths.GetCharmDir())
+
+	ctx := &MockContext{}
+	err := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.IsNil)
+	c.Check(tw.Log(), jc.LogMatches, []jc.SimpleMessage{
+		{loggo.INFO, `running hooks/something-happened via dispatch \(attempt 1\)`},
+		{loggo.DEBUG, `something-happened \(attempt 1\): hooks/something-happened something-happened`},
+		{loggo.INFO, `hooks/something-happened via dispatch \(attempt 1\) completed`},
+	})
+}
+
+func (s *RunMockContextSuite) TestRunActionViaDispatch(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("dispatch reads its environment with a bash script")
+	}
+	tw := s.captureDispatchOutput(c)
+
+	ctx := &MockContext{actionData: &context.ActionData{}}
+	err := runner.NewRunner(ctx, s.paths).RunAction("snapshot")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushBadge, gc.Equals, "snapshot")
+	c.Assert(ctx.flushFailure, gc.IsNil)
+	c.Check(tw.Log(), jc.LogMatches, []jc.SimpleMessage{
+		{loggo.INFO, `running actions/snapshot via dispatch \(attempt 1\)`},
+		{loggo.DEBUG, `snapshot \(attempt 1\): actions/snapshot no-hook`},
+		{loggo.INFO, `actions/snapshot via dispatch \(attempt 1\) completed`},
+	})
+}
+
 func (s *RunMockContextSuite) TestRunActionFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
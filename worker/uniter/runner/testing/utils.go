@@ -124,3 +124,19 @@ type FakeTracker struct {
 func (FakeTracker) ApplicationName() string {
 	return "application-name"
 }
+
+// WaitMinion returns a ticket that is never ready, as the FakeTracker
+// never loses leadership.
+func (FakeTracker) WaitMinion() leadership.Ticket {
+	return fakeTicket{}
+}
+
+type fakeTicket struct{}
+
+func (fakeTicket) Ready() <-chan struct{} {
+	return nil
+}
+
+func (fakeTicket) Wait() bool {
+	return false
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/utils"
 	"github.com/juju/utils/clock"
 	"github.com/juju/utils/exec"
+	"github.com/juju/utils/featureflag"
 	corecharm "gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/juju/worker.v1"
@@ -22,11 +23,13 @@ import (
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/status"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/catacomb"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/uniter/actions"
+	"github.com/juju/juju/worker/uniter/applicationhooks"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/hook"
 	uniterleadership "github.com/juju/juju/worker/uniter/leadership"
@@ -43,6 +46,10 @@ import (
 
 var logger = loggo.GetLogger("juju.worker.uniter")
 
+// auditLogger receives hook tool audit records when they are forwarded
+// to the controller.
+var auditLogger = loggo.GetLogger("juju.worker.uniter.audit")
+
 // A UniterExecutionObserver gets the appropriate methods called when a hook
 // is executed and either succeeds or fails.  Missing hooks don't get reported
 // in this way.
@@ -101,6 +108,25 @@ type Uniter struct {
 	// downloader is the downloader that should be used to get the charm
 	// archive.
 	downloader charm.Downloader
+
+	// verifyCharmDir, if set, checks the deployed charm directory for
+	// missing or changed files.
+	verifyCharmDir func() ([]string, error)
+
+	// relationCache holds the bounds of the caches of relation
+	// settings used by hook contexts.
+	relationCache context.RelationCacheConfig
+
+	// contextFactoryMetrics records the creation of hook contexts.
+	contextFactoryMetrics *context.ContextFactoryMetrics
+
+	// hookToolAuditor records hook tool invocations in the unit's
+	// hook tool audit log.
+	hookToolAuditor *runner.FileAuditor
+
+	// hookKillReason records why the last hook to fail was killed, if
+	// it was, so that the reason can be reported in its status.
+	hookKillReason error
 }
 
 // UniterParams hold all the necessary parameters for a new Uniter.
@@ -117,6 +143,14 @@ type UniterParams struct {
 	NewOperationExecutor NewExecutorFunc
 	TranslateResolverErr func(error) error
 	Clock                clock.Clock
+	// RelationCache holds the bounds of the caches of relation
+	// settings used by hook contexts, and where they record their
+	// activity.
+	RelationCache context.RelationCacheConfig
+	// ContextFactoryMetrics, if non-nil, records the contexts created
+	// for hooks, actions and commands, and the time spent setting
+	// them up.
+	ContextFactoryMetrics *context.ContextFactoryMetrics
 	// TODO (mattyw, wallyworld, fwereade) Having the observer here make this approach a bit more legitimate, but it isn't.
 	// the observer is only a stop gap to be used in tests. A better approach would be to have the uniter tests start hooks
 	// that write to files, and have the tests watch the output to know that hooks have finished.
@@ -147,6 +181,9 @@ func NewUniter(uniterParams *UniterParams) (*Uniter, error) {
 		observer:             uniterParams.Observer,
 		clock:                uniterParams.Clock,
 		downloader:           uniterParams.Downloader,
+		relationCache:        uniterParams.RelationCache,
+
+		contextFactoryMetrics: uniterParams.ContextFactoryMetrics,
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &u.catacomb,
@@ -158,6 +195,13 @@ func NewUniter(uniterParams *UniterParams) (*Uniter, error) {
 }
 
 func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
+	defer func() {
+		if u.hookToolAuditor != nil {
+			if err := u.hookToolAuditor.Close(); err != nil {
+				logger.Warningf("closing hook tool audit log: %v", err)
+			}
+		}
+	}()
 	if err := u.init(unitTag); err != nil {
 		if err == jworker.ErrTerminateAgent {
 			return err
@@ -245,6 +289,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 				UpdateStatusChannel: u.updateStatusAt,
 				CommandChannel:      u.commandChannel,
 				RetryHookChannel:    retryHookChan,
+				Clock:               u.clock,
 			})
 		if err != nil {
 			return errors.Trace(err)
@@ -284,6 +329,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 		uniterResolver := NewUniterResolver(ResolverConfig{
 			ClearResolved:       clearResolved,
 			ReportHookError:     u.reportHookError,
+			ReportSuspended:     u.reportSuspended,
 			ShouldRetryHooks:    u.hookRetryStrategy.ShouldRetry,
 			StartRetryHookTimer: retryHookTimer.Start,
 			StopRetryHookTimer:  retryHookTimer.Reset,
@@ -294,6 +340,13 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 			Commands: runcommands.NewCommandsResolver(
 				u.commands, watcher.CommandCompleted,
 			),
+			ApplicationHooks: applicationhooks.NewResolver(applicationhooks.Config{
+				Hooks: func() ([]string, error) {
+					return applicationhooks.CharmHooks(u.paths.State.CharmDir)
+				},
+				CompletedHooks: u.unit.CompletedApplicationHooks,
+			}),
+			VerifyCharmDir: u.verifyCharmDir,
 		})
 
 		// We should not do anything until there has been a change
@@ -458,6 +511,23 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 	if err != nil {
 		return errors.Annotatef(err, "cannot create deployer")
 	}
+	if verifier, ok := deployer.(charm.Verifier); ok {
+		u.verifyCharmDir = verifier.Verify
+	}
+	meterStatus, err := context.NewMeterStatusCache(u.unit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := u.catacomb.Add(meterStatus); err != nil {
+		return errors.Trace(err)
+	}
+	modelConfig, err := context.NewModelConfigCache(u.st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := u.catacomb.Add(modelConfig); err != nil {
+		return errors.Trace(err)
+	}
 	contextFactory, err := context.NewContextFactory(context.FactoryConfig{
 		State:            u.st,
 		UnitTag:          unitTag,
@@ -466,12 +536,24 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		Storage:          u.storage,
 		Paths:            u.paths,
 		Clock:            u.clock,
+		MeterStatus:      meterStatus,
+		ModelConfig:      modelConfig,
+		RelationCache:    u.relationCache,
+		Metrics:          u.contextFactoryMetrics,
 	})
 	if err != nil {
 		return err
 	}
+	u.hookToolAuditor, err = runner.NewFileAuditor(u.paths.State.HookToolAuditFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var auditor runner.Auditor = u.hookToolAuditor
+	if featureflag.Enabled(feature.ForwardHookToolAudit) {
+		auditor = runner.NewMultiAuditor(auditor, runner.NewLogAuditor(auditLogger))
+	}
 	runnerFactory, err := runner.NewFactory(
-		u.st, u.paths, contextFactory,
+		u.st, u.paths, contextFactory, auditor,
 	)
 	if err != nil {
 		return errors.Trace(err)
@@ -559,6 +641,10 @@ func (u *Uniter) acquireExecutionLock() (mutex.Releaser, error) {
 	return releaser, nil
 }
 
+func (u *Uniter) reportSuspended() error {
+	return setAgentStatus(u, status.Suspended, "suspended by operator", nil)
+}
+
 func (u *Uniter) reportHookError(hookInfo hook.Info) error {
 	// Set the agent status to "error". We must do this here in case the
 	// hook is interrupted (e.g. unit agent crashes), rather than immediately
@@ -576,7 +662,14 @@ func (u *Uniter) reportHookError(hookInfo hook.Info) error {
 		}
 		hookName = fmt.Sprintf("%s-%s", relationName, hookInfo.Kind)
 	}
+	if hookInfo.Kind == hook.ApplicationHook {
+		hookName = hookInfo.ApplicationHookName
+	}
 	statusData["hook"] = hookName
 	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
+	if u.hookKillReason != nil {
+		statusData["killed"] = u.hookKillReason.Error()
+		statusMessage = fmt.Sprintf("hook killed: %q: %v", hookName, u.hookKillReason)
+	}
 	return setAgentStatus(u, status.Error, statusMessage, statusData)
 }
@@ -1548,6 +1548,11 @@ func (mock *mockLeaderTracker) ClaimDuration() time.Duration {
 	return 30 * time.Second
 }
 
+func (mock *mockLeaderTracker) LeaseExpiry() time.Time {
+	// The mock never reports a lease, so hook contexts always consult it.
+	return time.Time{}
+}
+
 func (mock *mockLeaderTracker) ClaimLeader() leadership.Ticket {
 	mock.mu.Lock()
 	defer mock.mu.Unlock()
@@ -5,5 +5,7 @@ package upgrader
 
 var (
 	RetryAfter           = &retryAfter
+	RecheckAfter         = &recheckAfter
 	AllowedTargetVersion = allowedTargetVersion
+	ToolsDeltaURL        = toolsDeltaURL
 )
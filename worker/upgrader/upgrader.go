@@ -6,6 +6,8 @@ package upgrader
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"os"
 	"time"
 
 	"github.com/juju/errors"
@@ -18,6 +20,7 @@ import (
 
 	"github.com/juju/juju/agent"
 	agenttools "github.com/juju/juju/agent/tools"
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/upgrader"
 	coretools "github.com/juju/juju/tools"
 	jujuversion "github.com/juju/juju/version"
@@ -32,6 +35,14 @@ var retryAfter = func() <-chan time.Time {
 	return time.After(5 * time.Second)
 }
 
+// recheckAfter returns a channel that receives a value when the
+// desired version should be checked again without a model config
+// change, because the controller is holding this agent back until a
+// maintenance window opens or upgrade canaries have soaked.
+var recheckAfter = func() <-chan time.Time {
+	return time.After(5 * time.Minute)
+}
+
 var logger = loggo.GetLogger("juju.worker.upgrader")
 
 // Upgrader represents a worker that watches the state for upgrade
@@ -40,6 +51,7 @@ type Upgrader struct {
 	catacomb                    catacomb.Catacomb
 	st                          *upgrader.State
 	dataDir                     string
+	caCert                      string
 	tag                         names.Tag
 	origAgentVersion            version.Number
 	upgradeStepsWaiter          gate.Waiter
@@ -62,6 +74,7 @@ func NewAgentUpgrader(
 	u := &Upgrader{
 		st:                          st,
 		dataDir:                     agentConfig.DataDir(),
+		caCert:                      agentConfig.CACert(),
 		tag:                         agentConfig.Tag(),
 		origAgentVersion:            origAgentVersion,
 		upgradeStepsWaiter:          upgradeStepsWaiter,
@@ -151,12 +164,13 @@ func (u *Upgrader) loop() error {
 		}
 	}
 
-	var retry <-chan time.Time
+	var retry, recheck <-chan time.Time
 	for {
 		select {
-		// NOTE: retry and dying both start out nil, so they can't be chosen
-		// first time round the loop. However...
+		// NOTE: retry, recheck and dying all start out nil, so they can't
+		// be chosen first time round the loop. However...
 		case <-retry:
+		case <-recheck:
 		case <-dying:
 			return u.catacomb.ErrDying()
 		// ...*every* other case *must* allowDying(), before doing anything
@@ -171,12 +185,20 @@ func (u *Upgrader) loop() error {
 			}
 		}
 
-		wantVersion, err := u.st.DesiredVersion(u.tag.String())
+		wantVersion, held, err := u.st.DesiredVersionHeld(u.tag.String())
 		if err != nil {
 			return err
 		}
 		logger.Infof("desired tool version: %v", wantVersion)
 
+		// Only poll for the desired version while the controller is
+		// holding this agent back; otherwise the model config
+		// watcher reports any requested upgrade.
+		recheck = nil
+		if held {
+			logger.Infof("upgrade held by the controller, checking again later")
+			recheck = recheckAfter()
+		}
 		if wantVersion == jujuversion.Current {
 			u.initialUpgradeCheckComplete.Unlock()
 			continue
@@ -223,6 +245,7 @@ func (u *Upgrader) loop() error {
 			logger.Errorf("failed to fetch agent binaries from %q: %v", wantTools.URL, err)
 		}
 		retry = retryAfter()
+		recheck = nil
 	}
 }
 
@@ -250,10 +273,37 @@ func (u *Upgrader) newUpgradeReadyError(newVersion version.Binary) *UpgradeReady
 }
 
 func (u *Upgrader) ensureTools(agentTools *coretools.Tools) error {
-	logger.Infof("fetching agent binaries from %q", agentTools.URL)
-	// The reader MUST verify the tools' hash, so there is no
-	// need to validate the peer. We cannot anyway: see http://pad.lv/1261780.
-	resp, err := utils.GetNonValidatingHTTPClient().Get(agentTools.URL)
+	// If we have kept the archive of the current tools, ask for a
+	// delta from it, which is much smaller than the new tools.
+	// Controllers that cannot provide the delta send the full tools
+	// instead.
+	currentTools := toBinaryVersion(jujuversion.Current)
+	if _, err := os.Stat(agenttools.SharedToolsArchive(u.dataDir, currentTools)); err == nil {
+		deltaURL, err := toolsDeltaURL(agentTools.URL, currentTools)
+		if err == nil {
+			err = u.fetchTools(agentTools, deltaURL, &currentTools)
+		}
+		if err == nil {
+			return nil
+		}
+		logger.Warningf("cannot fetch agent binaries delta, fetching in full: %v", err)
+	}
+	return u.fetchTools(agentTools, agentTools.URL, nil)
+}
+
+// fetchTools fetches the given tools from toolsURL and unpacks them.
+// If base is not nil, the response may be a delta from the tools with
+// that version.
+func (u *Upgrader) fetchTools(agentTools *coretools.Tools, toolsURL string, base *version.Binary) error {
+	logger.Infof("fetching agent binaries from %q", toolsURL)
+	// The tools are served by the controller, so we accept only its
+	// certificate. The tools' hash is checked when they are unpacked
+	// too, whether they are sent in full or as a delta.
+	client, err := toolsHTTPClient(u.caCert)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := client.Get(toolsURL)
 	if err != nil {
 		return err
 	}
@@ -261,10 +311,40 @@ func (u *Upgrader) ensureTools(agentTools *coretools.Tools) error {
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("bad HTTP response: %v", resp.Status)
 	}
-	err = agenttools.UnpackTools(u.dataDir, agentTools, resp.Body)
+	if base != nil && resp.Header.Get("Content-Type") == agenttools.DeltaContentType {
+		err = agenttools.UnpackToolsDelta(u.dataDir, *base, agentTools, resp.Body)
+	} else {
+		err = agenttools.UnpackTools(u.dataDir, agentTools, resp.Body)
+	}
 	if err != nil {
 		return fmt.Errorf("cannot unpack agent binaries: %v", err)
 	}
 	logger.Infof("unpacked agent binaries %s to %s", agentTools.Version, u.dataDir)
 	return nil
 }
+
+// toolsHTTPClient returns an HTTP client that trusts the controller
+// with the given CA certificate.
+func toolsHTTPClient(caCert string) (*http.Client, error) {
+	pool, err := api.CreateCertPool(caCert)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create certificate pool")
+	}
+	tlsConfig := utils.SecureTLSConfig()
+	tlsConfig.RootCAs = pool
+	tlsConfig.ServerName = "juju-apiserver"
+	return &http.Client{Transport: utils.NewHttpTLSTransport(tlsConfig)}, nil
+}
+
+// toolsDeltaURL returns the URL from which to fetch a delta from the
+// tools with version base to the tools at toolsURL.
+func toolsDeltaURL(toolsURL string, base version.Binary) (string, error) {
+	u, err := url.Parse(toolsURL)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	query := u.Query()
+	query.Set("delta-from", base.String())
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
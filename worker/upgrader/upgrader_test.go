@@ -91,6 +91,10 @@ func (mock *mockConfig) DataDir() string {
 	return mock.datadir
 }
 
+func (mock *mockConfig) CACert() string {
+	return coretesting.CACert
+}
+
 func agentConfig(tag names.Tag, datadir string) agent.Config {
 	return &mockConfig{
 		tag:     tag,
@@ -184,6 +188,31 @@ func (s *UpgraderSuite) TestUpgraderUpgradesImmediately(c *gc.C) {
 	envtesting.CheckTools(c, foundTools, newTools)
 }
 
+func (s *UpgraderSuite) TestUpgraderDoesNotRecheckUnlessHeld(c *gc.C) {
+	vers := version.MustParseBinary("5.4.3-precise-amd64")
+	err := statetesting.SetAgentVersion(s.State, vers.Number)
+	c.Assert(err, jc.ErrorIsNil)
+	s.patchVersion(vers)
+
+	rechecks := make(chan struct{}, 1)
+	s.PatchValue(upgrader.RecheckAfter, func() <-chan time.Time {
+		select {
+		case rechecks <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	u := s.makeUpgrader(c)
+	defer statetesting.AssertStop(c, u)
+
+	s.expectInitialUpgradeCheckDone(c)
+	select {
+	case <-rechecks:
+		c.Fatalf("upgrader scheduled a recheck although its upgrade is not held")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
 func (s *UpgraderSuite) TestUpgraderRetryAndChanged(c *gc.C) {
 	stor := s.DefaultToolsStorage
 	oldTools := envtesting.PrimeTools(c, stor, s.DataDir(), s.Environ.Config().AgentStream(), version.MustParseBinary("5.4.3-precise-amd64"))
@@ -414,3 +443,12 @@ func (s *AllowedTargetVersionSuite) TestAllowedTargetVersionSuite(c *gc.C) {
 		c.Check(result, gc.Equals, test.allowed)
 	}
 }
+
+func (s *AllowedTargetVersionSuite) TestToolsDeltaURL(c *gc.C) {
+	deltaURL, err := upgrader.ToolsDeltaURL(
+		"https://10.0.0.1:17070/model/deadbeef/tools/2.2.1-xenial-amd64",
+		version.MustParseBinary("2.2.0-xenial-amd64"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deltaURL, gc.Equals, "https://10.0.0.1:17070/model/deadbeef/tools/2.2.1-xenial-amd64?delta-from=2.2.0-xenial-amd64")
+}